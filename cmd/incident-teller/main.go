@@ -3,30 +3,75 @@ package main
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"google.golang.org/grpc"
+
+	"incident-teller/internal/adapters/nagios"
 	"incident-teller/internal/adapters/netdata"
+	"incident-teller/internal/adapters/openai"
 	"incident-teller/internal/adapters/repository"
+	"incident-teller/internal/adapters/repository/mongodb"
+	redisrepo "incident-teller/internal/adapters/repository/redis"
+	"incident-teller/internal/adapters/zabbix"
 	"incident-teller/internal/ai"
 	"incident-teller/internal/api"
+	"incident-teller/internal/calendar"
+	"incident-teller/internal/classify"
 	"incident-teller/internal/config"
 	"incident-teller/internal/database"
+	"incident-teller/internal/domain"
+	"incident-teller/internal/enrichment"
+	"incident-teller/internal/environment"
+	"incident-teller/internal/exporter"
+	"incident-teller/internal/faults"
+	"incident-teller/internal/idgen"
+	"incident-teller/internal/labels"
+	"incident-teller/internal/notify"
 	"incident-teller/internal/observability"
+	"incident-teller/internal/oncall"
+	"incident-teller/internal/playbook"
 	"incident-teller/internal/ports"
+	"incident-teller/internal/redact"
+	"incident-teller/internal/replay"
+	"incident-teller/internal/report"
 	"incident-teller/internal/services"
+	"incident-teller/internal/severity"
+	"incident-teller/internal/snapshot"
+	"incident-teller/internal/statuspage"
+	"incident-teller/internal/templates"
+	"incident-teller/internal/ticketing"
+	"incident-teller/internal/topology"
 )
 
 func main() {
 	// Parse command-line flags
 	configPath := flag.String("config", "", "Path to configuration file")
 	version := flag.Bool("version", false, "Show version information")
+	readOnly := flag.Bool("read-only", false, "Serve existing data read-only: no polling, no writes (snapshot mode)")
+	migrateIDs := flag.Bool("migrate-ids", false, "Rewrite legacy alert/incident IDs to the configured ID format and exit")
+	poller := flag.Bool("poller", true, "Poll the alert sources (overrides ingestion.poller)")
+	backfill := flag.Bool("backfill", true, "Correlate stored alerts into incidents on startup (overrides ingestion.backfill)")
+	enableAI := flag.Bool("ai", true, "Enable AI analysis (overrides ai.enabled)")
+	enableMetrics := flag.Bool("metrics", true, "Serve metrics on the metrics port (overrides observability.enable_metrics)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [migrate up|down [N]|status | replay export FILE | replay run [-speed N] [-batch D] [-truth FILE] [-report FILE] DUMP | snapshot export [-ids ID,...] [-sanitize] FILE | snapshot import FILE | report weekly [-format markdown|pdf] [-to DATE] [-output FILE] | validate [-config FILE] [-offline] [-timeout D]]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
 	if *version {
@@ -34,16 +79,52 @@ func main() {
 		os.Exit(0)
 	}
 
+	// validate loads the configuration itself, reporting errors instead of exiting on them
+	if flag.Arg(0) == "validate" {
+		os.Exit(runValidate(*configPath, flag.Args()[1:]))
+	}
+
 	// Load configuration
 	cfg, err := config.Load(*configPath)
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	if *readOnly {
+		cfg.Database.ReadOnly = true
+	}
+	// Toggles override the configuration only when given explicitly
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "poller":
+			cfg.Ingestion.Poller = *poller
+		case "backfill":
+			cfg.Ingestion.Backfill = *backfill
+		case "ai":
+			cfg.AI.Enabled = *enableAI
+		case "metrics":
+			cfg.Observability.EnableMetrics = *enableMetrics
+		}
+	})
+	// Subsystems turned off through server.features stay off whatever their own settings say
+	if !cfg.Server.FeatureEnabled(config.FeatureAI) {
+		cfg.AI.Enabled = false
+	}
+	if !cfg.Server.FeatureEnabled(config.FeatureNotifications) {
+		cfg.Notifications.Enabled = false
+	}
 
 	// Initialize observability
 	logger := observability.NewLogger(cfg.Observability)
 	metrics := observability.NewMetrics(cfg.Observability)
-	healthChecker := observability.NewHealthChecker("1.0.0")
+	healthChecker := observability.NewHealthCheckerFromConfig("1.0.0", cfg.Observability)
+
+	// Configure ID generation
+	idGenerator, err := idgen.NewGenerator(cfg.Incident.IDFormat)
+	if err != nil {
+		log.Fatalf("Failed to configure ID generator: %v", err)
+	}
+	idgen.SetDefault(idGenerator)
+	domain.SetRiskPolicy(riskPolicy(cfg.Risk))
 
 	logger.Info("Starting IncidentTeller",
 		observability.String("version", "1.0.0"),
@@ -57,19 +138,57 @@ func main() {
 
 	// Initialize database
 	var db *sql.DB
+	var dialect database.Dialect
 	var repo api.Repository
 
 	switch cfg.Database.Type {
-	case "postgres", "postgresql":
-		db, err = sql.Open("postgres", cfg.Database.GetDSN())
-	case "mysql":
-		db, err = sql.Open("mysql", cfg.Database.GetDSN())
-	case "sqlite":
-		db, err = sql.Open("sqlite3", cfg.Database.GetDSN())
+	case "postgres", "postgresql", "mysql", "sqlite":
+		dialect, err = database.ParseDialect(cfg.Database.Type)
+		if err == nil {
+			db, err = sql.Open(dialect.DriverName(), cfg.Database.GetDSN())
+		}
+	case "redis":
+		redisRepo := redisrepo.NewRepository(redisrepo.Options{
+			Addr:      cfg.Database.RedisAddr,
+			Password:  cfg.Database.RedisPassword,
+			DB:        cfg.Database.RedisDB,
+			KeyPrefix: cfg.Database.RedisKeyPrefix,
+			TTL:       cfg.Database.RedisTTL,
+		})
+		defer redisRepo.Close()
+
+		pingCtx, pingCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = redisRepo.PingContext(pingCtx)
+		pingCancel()
+		repo = redisRepo
+		logger.Info("Using Redis repository",
+			observability.String("addr", cfg.Database.RedisAddr),
+			observability.String("ttl", cfg.Database.RedisTTL.String()))
+	case "mongodb":
+		var mongoRepo *mongodb.Repository
+		mongoRepo, err = mongodb.NewRepository(mongodb.Options{
+			URI:      cfg.Database.MongoURI,
+			Database: cfg.Database.Database,
+		})
+		if err == nil {
+			defer mongoRepo.Close(context.Background())
+
+			initCtx, initCancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if err = mongoRepo.PingContext(initCtx); err == nil && !cfg.Database.ReadOnly {
+				err = mongoRepo.Init(initCtx)
+			}
+			initCancel()
+			repo = mongoRepo
+			logger.Info("Using MongoDB repository",
+				observability.String("database", cfg.Database.Database))
+		}
 	case "memory":
 		memoryRepo := repository.NewInMemoryRepository()
+		memoryRepo.SetLimits(cfg.Database.MemoryMaxAlerts, cfg.Incident.MaxIncidents, cfg.Database.MemoryRetention)
 		repo = memoryRepo
-		logger.Info("Using in-memory repository")
+		logger.Info("Using in-memory repository",
+			observability.Int("max_alerts", cfg.Database.MemoryMaxAlerts),
+			observability.Int("max_incidents", cfg.Incident.MaxIncidents))
 	default:
 		logger.Fatal("Unsupported database type", observability.String("type", cfg.Database.Type))
 	}
@@ -87,12 +206,50 @@ func main() {
 		db.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
 
 		// Initialize SQL repository
-		sqlRepo := database.NewSQLRepository(db)
+		sqlRepo := database.NewSQLRepositoryWithDialect(db, dialect)
+		sqlRepo.SetBatchSize(cfg.Database.BatchSize)
+		sqlRepo.EnableCache(cfg.Database.CacheTTL, cfg.Database.CacheMaxEntries, metrics)
 		initCtx, initCancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer initCancel()
 
-		if err := sqlRepo.Init(initCtx); err != nil {
-			logger.Fatal("Failed to initialize database", observability.Error(err))
+		// SQLite pins its own pool; a read-only file keeps whatever journal mode it has
+		sqliteOpts := database.SQLiteOptions{
+			JournalMode:  cfg.Database.SQLiteJournalMode,
+			BusyTimeout:  cfg.Database.SQLiteBusyTimeout,
+			Synchronous:  cfg.Database.SQLiteSynchronous,
+			MaxOpenConns: cfg.Database.SQLiteMaxOpenConns,
+		}
+		if cfg.Database.ReadOnly {
+			sqliteOpts.JournalMode = ""
+		}
+		if err := sqlRepo.TuneSQLite(initCtx, sqliteOpts); err != nil {
+			logger.Fatal("Failed to tune SQLite", observability.Error(err))
+		}
+
+		if flag.Arg(0) == "migrate" {
+			if err := runMigrate(initCtx, sqlRepo, flag.Args()[1:]); err != nil {
+				log.Fatalf("Migration failed: %v", err)
+			}
+			os.Exit(0)
+		}
+
+		// A read-only snapshot is served as-is; schema changes would be a write
+		if !cfg.Database.ReadOnly && cfg.Database.AutoMigrate {
+			if err := sqlRepo.Init(initCtx); err != nil {
+				logger.Fatal("Failed to initialize database", observability.Error(err))
+			}
+		}
+
+		if *migrateIDs {
+			alertsMigrated, incidentsMigrated, err := sqlRepo.MigrateIDs(initCtx, idGenerator)
+			if err != nil {
+				logger.Fatal("Failed to migrate IDs", observability.Error(err))
+			}
+			logger.Info("ID migration completed",
+				observability.String("format", idGenerator.Name()),
+				observability.Int("alerts", alertsMigrated),
+				observability.Int("incidents", incidentsMigrated))
+			os.Exit(0)
 		}
 
 		repo = sqlRepo
@@ -100,15 +257,56 @@ func main() {
 			observability.String("type", cfg.Database.Type))
 	}
 
+	if flag.Arg(0) == "migrate" {
+		log.Fatalf("migrate requires a SQL database, not %q", cfg.Database.Type)
+	}
+
+	if flag.Arg(0) == "replay" {
+		if err := runReplay(context.Background(), cfg, repo, flag.Args()[1:]); err != nil {
+			log.Fatalf("Replay failed: %v", err)
+		}
+		os.Exit(0)
+	}
+
+	if flag.Arg(0) == "snapshot" {
+		if err := runSnapshot(context.Background(), repo, cfg.Database.ReadOnly, flag.Args()[1:]); err != nil {
+			log.Fatalf("Snapshot failed: %v", err)
+		}
+		os.Exit(0)
+	}
+
+	if flag.Arg(0) == "report" {
+		if err := runReport(context.Background(), repo, flag.Args()[1:]); err != nil {
+			log.Fatalf("Report failed: %v", err)
+		}
+		os.Exit(0)
+	}
+
+	// Faults injected through /api/admin/faults to test how the pipeline degrades
+	var faultInjector *faults.Injector
+	databaseCheck := observability.DatabaseHealthCheck(repo)
+	if cfg.Server.FaultInjection {
+		faultInjector = faults.NewInjector()
+		databaseCheck = faultInjector.HealthCheck(databaseCheck)
+		logger.Warn("Fault injection enabled on /api/admin/faults")
+	}
+
 	// Register health checks
-	healthChecker.RegisterCheck("database", observability.DatabaseHealthCheck(repo))
-	healthChecker.RegisterCheck("netdata", observability.NetdataHealthCheck(cfg.Netdata.BaseURL))
+	healthChecker.RegisterReadinessCheck("database", databaseCheck)
+	if cfg.Netdata.Enabled {
+		healthChecker.RegisterCheck("netdata", observability.NetdataHealthCheck(cfg.Netdata.BaseURL))
+	}
 	healthChecker.RegisterCheck("memory", observability.MemoryHealthCheck(80.0))
 
 	// Initialize Netdata client (supports both local and cloud)
 	var netdataClient ports.AlertSource
+	var netdataStream ports.AlertStream
+
+	switch {
+	case !cfg.Netdata.Enabled:
+		logger.Info("Netdata alert source disabled")
 
-	if cfg.Netdata.CloudEnabled {
+	case cfg.Netdata.CloudEnabled:
 		logger.Info("Using Netdata Cloud API",
 			observability.String("space", cfg.Netdata.CloudSpace))
 
@@ -117,147 +315,954 @@ func main() {
 			cfg.Netdata.CloudSpace,
 			cfg.Netdata.CloudRooms...,
 		)
-	} else {
+
+	default:
 		logger.Info("Using Local Netdata API",
 			observability.String("url", cfg.Netdata.BaseURL))
 
-		netdataClient = netdata.NewClient(
+		localClient := netdata.NewClient(
 			cfg.Netdata.BaseURL,
 			cfg.Netdata.Hostname,
 		)
+		localClient.SetTimeout(cfg.Netdata.Timeout)
+		localClient.SetRetry(cfg.Netdata.RetryCount, cfg.Netdata.RetryDelay)
+		localClient.SetFaults(faultInjector)
+		netdataClient = localClient
+
+		if cfg.Netdata.Mode == "stream" {
+			netdataStream = netdata.NewStreamClient(localClient, cfg.Netdata.StreamInterval)
+			logger.Info("Netdata streaming mode enabled",
+				observability.String("interval", cfg.Netdata.StreamInterval.String()))
+		}
+	}
+
+	if cfg.Netdata.Enabled && cfg.Netdata.CloudEnabled && cfg.Netdata.Mode == "stream" {
+		logger.Warn("Streaming mode is not supported for Netdata Cloud, falling back to polling")
+	}
+
+	// Zabbix and Nagios/Icinga run alongside Netdata when enabled
+	var zabbixClient *zabbix.Client
+	if cfg.Zabbix.Enabled {
+		logger.Info("Using Zabbix API",
+			observability.String("url", cfg.Zabbix.URL))
+
+		zabbixClient = zabbix.NewClient(
+			cfg.Zabbix.URL,
+			cfg.Zabbix.APIToken,
+			cfg.Zabbix.Username,
+			cfg.Zabbix.Password,
+			cfg.Zabbix.Timeout,
+		)
+	}
+
+	var nagiosReceiver *nagios.Receiver
+	if cfg.Nagios.Enabled {
+		logger.Info("Using Nagios/Icinga check result webhook",
+			observability.String("path", "/api/webhooks/nagios"))
+
+		nagiosReceiver = nagios.NewReceiver(cfg.Nagios.Token, cfg.Nagios.Hostname, cfg.Nagios.BufferSize)
+	}
+
+	// Business hours and peak traffic windows adjust risk levels
+	businessCalendar, err := calendar.FromConfig(cfg.Calendar)
+	if err != nil {
+		log.Fatalf("Invalid business calendar: %v", err)
 	}
 
-	// Initialize AI model
+	// Initialize AI model; every version in the model path is loaded, the configured one
+	// answers requests and the others are scored in the shadow
 	var aiModel ai.AIModel
+	var activeModel *ai.LocalAIModel
+	var modelVersions []*ai.LocalAIModel
 	if cfg.AI.Enabled {
-		aiModel = ai.NewLocalAIModel()
+		versions, err := ai.LoadModelVersions(cfg.AI.ModelPath)
+		if err != nil {
+			logger.Fatal("Failed to load model versions", observability.Error(err))
+		}
+		for _, weights := range versions {
+			model := ai.NewLocalAIModel()
+			model.SetCalendar(businessCalendar)
+			model.SetWeights(weights)
+			if weights.Version == cfg.AI.ModelVersion {
+				activeModel = model
+			}
+			modelVersions = append(modelVersions, model)
+		}
+		if activeModel == nil {
+			logger.Fatal("Unknown AI model version", observability.String("version", cfg.AI.ModelVersion))
+		}
+		aiModel = activeModel
+		if cfg.AI.ModelType == "remote" {
+			// Blast radius scores come from an external model server, the rest stays local
+			aiModel = ai.NewRemoteModel(activeModel, cfg.AI.APIEndpoint, cfg.AI.APIToken,
+				cfg.AI.PredictionTimeout, cfg.AI.RemoteRetries)
+		}
 		logger.Info("AI model enabled",
 			observability.String("type", cfg.AI.ModelType),
+			observability.String("version", activeModel.Version()),
+			observability.Int("versions", len(modelVersions)),
 			observability.Float64("confidence_threshold", cfg.AI.ConfidenceThreshold))
 	} else {
 		logger.Info("AI model disabled")
 	}
+	if aiModel != nil && faultInjector != nil {
+		aiModel = faults.WrapModel(aiModel, faultInjector)
+	}
 
 	// Initialize analyzers
 	incidentAnalyzer := services.NewIncidentAnalyzer()
 
-	// Initialize enhanced poller
-	poller := services.NewRealTimePoller(
-		netdataClient,
-		repo,
-		incidentAnalyzer,
-		cfg.Netdata.PollInterval,
+	// Initialize alert correlation
+	serviceTopology, err := topology.FromConfig(cfg.Topology)
+	if err != nil {
+		log.Fatalf("Invalid topology: %v", err)
+	}
+	correlation, err := services.NewCorrelationStrategy(
+		cfg.Incident.CorrelationStrategy,
+		cfg.Incident.CorrelationLabels,
+		cfg.Incident.GroupBy,
+		serviceTopology,
 	)
+	if err != nil {
+		log.Fatalf("Failed to configure correlation: %v", err)
+	}
+	// Incidents never span environments
+	environments, err := environment.FromConfig(cfg.Environments)
+	if err != nil {
+		log.Fatalf("Invalid environments: %v", err)
+	}
+	if environments != nil {
+		correlation = services.ScopeByEnvironment(correlation, environments.Of)
+		logger.Info("Incidents scoped by environment", observability.String("label", environments.Label()))
+	}
+	groupKey, err := labels.ParseGroupKey(cfg.Incident.GroupBy)
+	if err != nil {
+		log.Fatalf("Failed to configure correlation: %v", err)
+	}
+	// Learn how issues propagate between resources from alert history
+	var learner *services.PropagationLearner
+	if cfg.AI.EnableLearning {
+		learner = services.NewPropagationLearner(
+			cfg.AI.LearningWindow,
+			cfg.AI.LearningMinObservations,
+			cfg.AI.LearningLookback,
+		)
+		learner.SetTopology(serviceTopology)
+		incidentAnalyzer.SetPropagationLearner(learner)
+		for _, model := range modelVersions {
+			model.SetPropagationModel(learner)
+		}
+	}
+
+	// Organization playbooks take precedence over the built-in fix steps
+	playbooks := playbook.NewStore()
+	if cfg.Playbooks.Dir != "" {
+		if playbooks, err = playbook.Load(cfg.Playbooks.Dir); err != nil {
+			logger.Fatal("Failed to load playbooks", observability.Error(err))
+		}
+		logger.Info("Playbooks loaded",
+			observability.String("dir", cfg.Playbooks.Dir), observability.Int("count", len(playbooks.List())))
+	}
+
+	// Detect alert bursts and never-before-seen alerts, seeding baselines from history
+	var anomalyDetector *services.AnomalyDetector
+	if cfg.Anomaly.Enabled {
+		anomalyDetector = services.NewAnomalyDetector(
+			cfg.Anomaly.BucketSize,
+			cfg.Anomaly.BaselineWindow,
+			cfg.Anomaly.Threshold,
+			cfg.Anomaly.MinBurstSize,
+		)
+		if history, err := repo.GetAlerts(context.Background()); err != nil {
+			logger.Warn("Failed to load alert history for anomaly baselines", observability.Error(err))
+		} else {
+			anomalyDetector.Observe(history)
+		}
+		for _, model := range modelVersions {
+			model.SetAnomalySource(anomalyDetector)
+		}
+		logger.Info("Anomaly detection enabled",
+			observability.String("bucket", cfg.Anomaly.BucketSize.String()),
+			observability.String("baseline", cfg.Anomaly.BaselineWindow.String()))
+	}
+
+	incidentBuilder := services.NewIncidentBuilder(cfg.Incident.CorrelationWindow)
+	incidentBuilder.SetStrategy(correlation)
+	logger.Info("Alert correlation configured",
+		observability.String("strategy", correlation.Name()),
+		observability.String("window", cfg.Incident.CorrelationWindow.String()))
+
+	// Known failure modes pre-populate the incidents they match
+	incidentTemplates, err := templates.FromConfig(cfg.Incident.Templates)
+	if err != nil {
+		log.Fatalf("Invalid incident templates: %v", err)
+	}
+	incidentBuilder.SetTemplates(incidentTemplates)
+	if incidentTemplates.Len() > 0 {
+		logger.Info("Incident templates loaded", observability.Int("count", incidentTemplates.Len()))
+	}
+
+	// Alert storms become a single incident and throttle the notifications of the others
+	var stormDetector *services.StormDetector
+	if cfg.Incident.StormThreshold > 0 {
+		stormDetector = services.NewStormDetector(cfg.Incident.StormThreshold, cfg.Incident.StormWindow, cfg.Incident.StormCooldown)
+		incidentBuilder.SetStormDetector(stormDetector)
+		logger.Info("Alert storm detection enabled",
+			observability.Int("threshold", cfg.Incident.StormThreshold),
+			observability.String("window", cfg.Incident.StormWindow.String()))
+	}
+
+	// Poll every enabled alert source concurrently
+	sources := services.NewSourceManager(repo, incidentAnalyzer)
+	if netdataClient != nil {
+		netdataPoller := sources.Add("netdata", netdataClient, cfg.Netdata.PollInterval)
+		if netdataStream != nil {
+			netdataPoller.SetStream(netdataStream)
+		}
+		if cfg.Netdata.CircuitFailures > 0 {
+			netdataPoller.SetCircuitBreaker(services.NewCircuitBreaker(
+				"netdata", cfg.Netdata.CircuitFailures, cfg.Netdata.CircuitCooldown))
+		}
+	}
+	if zabbixClient != nil {
+		sources.Add("zabbix", zabbixClient, cfg.Zabbix.PollInterval)
+	}
+	if nagiosReceiver != nil {
+		sources.Add("nagios", nagiosReceiver, cfg.Nagios.PollInterval)
+	}
+
+	enricher, err := enrichment.FromConfig(cfg.Enrichment)
+	if err != nil {
+		log.Fatalf("Invalid enrichment config: %v", err)
+	}
+	if environments != nil {
+		// After the other enrichers, so an environment label they set wins
+		enricher = enricher.With(environments)
+	}
+	sources.SetEnrichment(enricher)
+
+	classifier, err := classify.FromConfig(cfg.Resources)
+	if err != nil {
+		log.Fatalf("Invalid resource rules: %v", err)
+	}
+	sources.SetClassifier(classifier)
+	sources.SetFaults(faultInjector)
+
+	severityMapper, err := severity.FromConfig(cfg.Severity)
+	if err != nil {
+		log.Fatalf("Invalid severity rules: %v", err)
+	}
+	sources.SetSeverityMapper(severityMapper)
+	sources.SetRawPayloadLimit(cfg.Ingestion.RawPayloadMaxBytes)
+	var flapDetector *services.FlapDetector
+	if cfg.Flapping.Enabled {
+		flapDetector = services.NewFlapDetector(cfg.Flapping.Window, cfg.Flapping.Threshold)
+		sources.SetFlapDetector(flapDetector)
+	}
+	if cfg.Suppression.Enabled {
+		sources.SetDependencySuppressor(services.NewDependencySuppressor(cfg.Suppression.RootAlerts))
+	}
+	if anomalyDetector != nil {
+		sources.SetAnomalyDetector(anomalyDetector)
+	}
+	sources.SetMetrics(metrics)
+	for _, name := range sources.Sources() {
+		healthChecker.RegisterCheck("source_"+name, sources.HealthCheck(name))
+	}
+
+	// Initialize on-call rotation
+	var onCall *oncall.Manager
+	if cfg.OnCall.Enabled {
+		onCall, err = oncall.NewManager(onCallSchedule(cfg.OnCall))
+		if err != nil {
+			log.Fatalf("Invalid on-call schedule: %v", err)
+		}
+		logger.Info("On-call rotation enabled",
+			observability.Int("members", len(cfg.OnCall.Members)),
+			observability.String("shift_length", cfg.OnCall.ShiftLength.String()))
+	}
+
+	// Hostnames, IPs and sensitive labels are masked in what leaves for OpenAI and chat channels
+	redactor, err := redact.FromConfig(cfg.Redaction)
+	if err != nil {
+		log.Fatalf("Invalid redaction config: %v", err)
+	}
+	var notifyRedactor *redact.Redactor
+	if cfg.Redaction.Notifications {
+		notifyRedactor = redactor
+	}
+	if redactor != nil {
+		logger.Info("Redaction enabled", observability.Bool("notifications", cfg.Redaction.Notifications))
+	}
+
+	// Change events recorded through the API, also correlated in notification analysis
+	changeTracker := services.NewChangeTracker(1000)
+
+	// Initialize notifications
+	var incidentNotifier *services.IncidentNotifier
+	var dispatcher *notify.Dispatcher
+	if cfg.Notifications.Enabled {
+		channels, err := notificationChannels(cfg.Notifications, notifyRedactor)
+		if err != nil {
+			log.Fatalf("Failed to configure notifications: %v", err)
+		}
+		dispatcher = notify.NewDispatcher(channels...)
+		incidentNotifier = services.NewIncidentNotifier(qualityGate(cfg.Notifications), dispatcher)
+		if onCall != nil {
+			incidentNotifier.SetOnCall(onCall)
+		}
+		if learner != nil {
+			incidentNotifier.SetPropagationLearner(learner)
+		}
+		incidentNotifier.SetChangeTracker(changeTracker)
+		incidentNotifier.SetPlaybooks(playbooks)
+		incidentNotifier.SetTemplates(incidentTemplates)
+		incidentNotifier.SetStormDetector(stormDetector)
+		logger.Info("Notifications enabled", observability.Int("channels", dispatcher.Len()))
+	}
+
+	// Raise an "ingestion stalled" incident when the alert sources go silent
+	var watchdog *services.IngestionWatchdog
+	if cfg.Ingestion.Poller && !cfg.Database.ReadOnly && cfg.Ingestion.StallThreshold > 0 {
+		watchdog = services.NewIngestionWatchdog(sources, cfg.Ingestion.StallThreshold)
+		watchdog.SetIncidentStore(repo)
+		watchdog.SetDispatcher(dispatcher)
+		watchdog.SetMetrics(metrics)
+		healthChecker.RegisterCheck("ingestion", watchdog.HealthCheck())
+	}
+
+	// Stored alert batches wait in a bounded queue for the workers correlating them
+	alertQueue := services.NewAlertQueue(cfg.Ingestion.Workers, cfg.Ingestion.QueueSize,
+		services.Backpressure(cfg.Ingestion.Backpressure))
+	alertQueue.SetMetrics(metrics)
+	sources.SetAlertQueue(alertQueue)
+	if cfg.Ingestion.Poller && !cfg.Database.ReadOnly {
+		healthChecker.RegisterCheck("alert_queue", alertQueue.HealthCheck())
+	}
 
 	// Create context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Start metrics server if enabled
+	var metricsServer *http.Server
 	if cfg.Observability.EnableMetrics {
+		startedAt := time.Now()
+		metricsMux := http.NewServeMux()
+		metricsMux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprintf(w, "# IncidentTeller Metrics\n")
+			fmt.Fprintf(w, "incident_teller_uptime_seconds %f\n", time.Since(startedAt).Seconds())
+			fmt.Fprintf(w, "incident_teller_build_info{version=\"1.0.0\"} 1\n")
+			if watchdog != nil {
+				status := watchdog.Status(time.Now())
+				if status.LastSuccess != nil {
+					fmt.Fprintf(w, "incident_teller_ingestion_last_success_timestamp_seconds %d\n", status.LastSuccess.Unix())
+				}
+				fmt.Fprintf(w, "incident_teller_ingestion_silence_seconds %f\n", status.Silence.Seconds())
+				stalled := 0
+				if status.Stalled {
+					stalled = 1
+				}
+				fmt.Fprintf(w, "incident_teller_ingestion_stalled %d\n", stalled)
+			}
+			queue := alertQueue.Stats()
+			fmt.Fprintf(w, "incident_teller_alert_queue_depth %d\n", queue.Depth)
+			fmt.Fprintf(w, "incident_teller_alert_queue_capacity %d\n", queue.Capacity)
+			fmt.Fprintf(w, "incident_teller_alert_queue_busy_workers %d\n", queue.Busy)
+			fmt.Fprintf(w, "incident_teller_alert_queue_processed_batches_total %d\n", queue.Processed)
+			fmt.Fprintf(w, "incident_teller_alert_queue_dropped_batches_total %d\n", queue.Dropped)
+			fmt.Fprintf(w, "incident_teller_alert_queue_dropped_alerts_total %d\n", queue.DroppedAlerts)
+		})
+		metricsServer = &http.Server{
+			Addr:        fmt.Sprintf(":%d", cfg.Observability.MetricsPort),
+			Handler:     metricsMux,
+			ReadTimeout: cfg.Server.ReadTimeout,
+		}
+
 		go func() {
-			metricsAddr := fmt.Sprintf(":%d", cfg.Observability.MetricsPort)
-			logger.Info("Starting metrics server", observability.String("addr", metricsAddr))
-
-			// Simple metrics endpoint
-			http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
-				w.Header().Set("Content-Type", "text/plain")
-				fmt.Fprintf(w, "# IncidentTeller Metrics\n")
-				fmt.Fprintf(w, "incident_teller_uptime_seconds %f\n", time.Since(time.Now()).Seconds())
-				fmt.Fprintf(w, "incident_teller_build_info{version=\"1.0.0\"} 1\n")
-			})
-
-			if err := http.ListenAndServe(metricsAddr, nil); err != nil {
+			logger.Info("Starting metrics server", observability.String("addr", metricsServer.Addr))
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 				logger.Error("Metrics server failed", observability.Error(err))
 			}
 		}()
 	}
 
+	// Background work done by one replica only: the leader, or this one without election
+	var leaderTasks []func(ctx context.Context)
+
+	// Export finalized incident timelines as OTLP logs if enabled
+	if cfg.Observability.EnableOTLPLogs && !cfg.Database.ReadOnly {
+		cursors, ok := repo.(ports.ExportCursorStore)
+		if !ok {
+			logger.Fatal("OTLP timeline export is not supported by this database", observability.String("type", cfg.Database.Type))
+		}
+		otlpExporter := observability.NewOTLPLogExporter(
+			cfg.Observability.OTLPEndpoint,
+			cfg.Observability.ServiceName,
+			cfg.Observability.OTLPHeaders,
+		)
+		timelineExporter := services.NewTimelineLogExporter(otlpExporter, cursors)
+		leaderTasks = append(leaderTasks, func(ctx context.Context) {
+			timelineExporter.Run(ctx, repo, 30*time.Second)
+		})
+
+		logger.Info("OTLP timeline export enabled",
+			observability.String("endpoint", cfg.Observability.OTLPEndpoint))
+	}
+
+	// Restore persisted propagation patterns and keep relearning them
+	if learner != nil {
+		store, _ := repo.(ports.PropagationPatternStore)
+		if store != nil {
+			if err := learner.LoadFrom(ctx, store); err != nil {
+				logger.Warn("Failed to load propagation patterns", observability.Error(err))
+			}
+		}
+		if cfg.Database.ReadOnly {
+			store = nil
+		}
+		go learner.Run(ctx, repo, store, cfg.AI.LearningInterval)
+
+		logger.Info("Propagation learning enabled",
+			observability.String("interval", cfg.AI.LearningInterval.String()),
+			observability.String("window", cfg.AI.LearningWindow.String()))
+	}
+
+	// Predict incidents from open warnings and send pre-incident notifications
+	var predictor *services.PredictionService
+	if cfg.Prediction.Enabled {
+		predictor = services.NewPredictionService(repo, cfg.Prediction.Horizon, cfg.Prediction.MinConfidence, cfg.Prediction.Lookback)
+		predictor.SetAIModel(aiModel)
+		if learner != nil {
+			predictor.SetPropagationLearner(learner)
+		}
+		if dispatcher != nil && !cfg.Database.ReadOnly {
+			predictor.SetDispatcher(dispatcher)
+		}
+		go predictor.Run(ctx, cfg.Prediction.Interval)
+
+		logger.Info("Incident prediction enabled",
+			observability.String("interval", cfg.Prediction.Interval.String()),
+			observability.String("horizon", cfg.Prediction.Horizon.String()))
+	}
+
+	// Email a digest of recent incidents on schedule
+	if cfg.Digest.Enabled && !cfg.Database.ReadOnly {
+		weekday, at, location, err := cfg.Digest.ParseSchedule()
+		if err != nil {
+			logger.Fatal("Invalid digest schedule", observability.Error(err))
+		}
+		schedule := services.DigestSchedule{Period: cfg.Digest.Period, Weekday: weekday, At: at, Location: location}
+
+		digestBuilder := services.NewDigestBuilder()
+		digestBuilder.SetPropagationLearner(learner)
+		mailer := notify.NewSMTPMailer(cfg.Digest.SMTPHost, cfg.Digest.SMTPPort,
+			cfg.Digest.SMTPUsername, cfg.Digest.SMTPPassword, cfg.Digest.From, cfg.Digest.Recipients)
+		loadIncidents := func(ctx context.Context, from, to time.Time) ([]domain.Incident, error) {
+			if rangeRepo, ok := repo.(api.IncidentRangeRepository); ok {
+				return rangeRepo.GetIncidentsByTimeRange(ctx, from, to)
+			}
+			return repo.GetIncidents(ctx)
+		}
+		go digestBuilder.RunDigest(ctx, schedule, loadIncidents, mailer)
+
+		logger.Info("Incident digest enabled",
+			observability.String("next", schedule.Next(time.Now()).Format(time.RFC3339)),
+			observability.Int("recipients", len(cfg.Digest.Recipients)))
+	}
+
+	// Apply reloaded settings on SIGHUP, file change or POST /api/admin/reload
+	reloader := config.NewReloader(*configPath, cfg)
+	reloader.OnReload("log_level", func(newCfg *config.Config) error {
+		if leveled, ok := logger.(interface{ SetLevel(observability.LogLevel) }); ok {
+			leveled.SetLevel(observability.ParseLogLevel(newCfg.Observability.LogLevel))
+		}
+		return nil
+	})
+	reloader.OnReload("correlation_window", func(newCfg *config.Config) error {
+		incidentBuilder.SetWindow(newCfg.Incident.CorrelationWindow)
+		return nil
+	})
+	reloader.OnReload("risk", func(newCfg *config.Config) error {
+		domain.SetRiskPolicy(riskPolicy(newCfg.Risk))
+		return nil
+	})
+	reloader.OnReload("poll_intervals", func(newCfg *config.Config) error {
+		intervals := map[string]time.Duration{
+			"netdata": newCfg.Netdata.PollInterval,
+			"zabbix":  newCfg.Zabbix.PollInterval,
+			"nagios":  newCfg.Nagios.PollInterval,
+		}
+		for _, name := range sources.Sources() {
+			if err := sources.SetPollInterval(name, intervals[name]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	reloader.OnReload("notifications", func(newCfg *config.Config) error {
+		if incidentNotifier == nil {
+			if newCfg.Notifications.Enabled {
+				return fmt.Errorf("enabling notifications requires a restart")
+			}
+			return nil
+		}
+		if newCfg.Notifications.Enabled {
+			channels, err := notificationChannels(newCfg.Notifications, notifyRedactor)
+			if err != nil {
+				return err
+			}
+			dispatcher.Replace(channels...)
+		} else {
+			dispatcher.Replace()
+		}
+		incidentNotifier.SetQualityGate(qualityGate(newCfg.Notifications))
+		return nil
+	})
+
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reloadChan:
+				result, err := reloader.Reload()
+				if err != nil {
+					logger.Error("Configuration reload failed", observability.Error(err))
+					continue
+				}
+				logger.Info("Configuration reloaded",
+					observability.String("components", strings.Join(result.Applied, ",")))
+			}
+		}
+	}()
+	if cfg.Server.ConfigWatchInterval > 0 && *configPath != "" {
+		go reloader.Watch(ctx, cfg.Server.ConfigWatchInterval)
+	}
+
 	// Initialize API handlers
 	apiHandler := api.NewHandler(repo, aiModel, logger, healthChecker, metrics)
+	apiHandler.SetReadOnly(cfg.Database.ReadOnly || cfg.Server.ReadOnly)
+	apiHandler.SetFeatures(cfg.Server.Features)
+	apiHandler.SetRedactor(redactor)
+	apiHandler.SetDashboard(cfg.Server.Dashboard)
+	apiHandler.SetGraphQL(cfg.Server.GraphQL)
+	apiHandler.SetConfigReloader(reloader, cfg.Server.AdminToken)
+	if err := apiHandler.SetShareLinks(cfg.Server.ShareSecret, cfg.Server.ShareMaxTTL); err != nil {
+		log.Fatalf("Failed to configure share links: %v", err)
+	}
+	apiHandler.SetFaults(faultInjector)
+	apiHandler.SetCORSPolicy(api.CORSPolicy{
+		AllowedOrigins:   cfg.Server.CORSAllowedOrigins,
+		AllowedMethods:   cfg.Server.CORSAllowedMethods,
+		AllowedHeaders:   cfg.Server.CORSAllowedHeaders,
+		AllowCredentials: cfg.Server.CORSAllowCredentials,
+		MaxAge:           cfg.Server.CORSMaxAge,
+	})
+	apiHandler.SetMaxBodyBytes(cfg.Server.MaxBodyBytes)
+	apiHandler.SetAccessLog(cfg.Server.AccessLog)
+	if cfg.Server.RateLimit > 0 {
+		apiHandler.SetRateLimit(api.NewRateLimiter(cfg.Server.RateLimit, cfg.Server.RateLimitBurst, cfg.Server.TrustProxyHeaders))
+	}
+	apiHandler.SetIncidentBuilder(incidentBuilder)
+	apiHandler.SetPropagationLearner(learner)
+	apiHandler.SetPlaybooks(playbooks)
+	apiHandler.SetTemplates(incidentTemplates)
+	apiHandler.SetCalendar(businessCalendar)
+	apiHandler.SetChangeTracker(changeTracker)
+	apiHandler.SetTopology(serviceTopology)
+	apiHandler.SetEnvironments(environments)
+	apiHandler.SetGroupKey(groupKey)
+	if flapDetector != nil {
+		apiHandler.SetFlapDetector(flapDetector)
+	}
+	apiHandler.SetStormDetector(stormDetector)
+	apiHandler.SetAnomalyDetector(anomalyDetector, cfg.Anomaly.Window)
+	if watchdog != nil {
+		apiHandler.SetIngestionWatchdog(watchdog)
+	}
+	apiHandler.SetPredictionService(predictor)
 
-	// Start API server
-	go func() {
-		apiAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
-		logger.Info("Starting API server", observability.String("addr", apiAddr))
+	// Analyze created and updated incidents in the background
+	var analysisQueue *services.AnalysisQueue
+	if cfg.AI.Enabled {
+		analysisQueue = services.NewAnalysisQueue(apiHandler.AnalyzeIncident,
+			cfg.AI.AnalysisWorkers, cfg.AI.AnalysisQueueSize, cfg.AI.AnalysisTimeout)
+		apiHandler.SetAnalysisQueue(analysisQueue)
+		go analysisQueue.Run(ctx)
+	}
+	if cfg.StatusPage.Enabled {
+		apiHandler.SetStatusPage(statuspage.NewGenerator(cfg.StatusPage.Title, serviceTopology, cfg.StatusPage.HistoryDays))
+	}
+	if cfg.ChatOps.Enabled {
+		apiHandler.SetSlackCommands(cfg.ChatOps.SlackSigningSecret)
+	}
+	if nagiosReceiver != nil {
+		apiHandler.SetNagiosWebhook(nagiosReceiver)
+	}
+	if onCall != nil {
+		apiHandler.SetOnCall(onCall)
+	}
+	apiHandler.SetClassifier(classifier)
+	if hostInfoSource, ok := netdataClient.(api.HostInfoSource); ok {
+		apiHandler.SetHostInfoSource(hostInfoSource)
+	}
 
-		mux := apiHandler.SetupRoutes()
+	// Persist timelines with their causality links as alerts are attached to incidents
+	var timelineRecorder *services.TimelineRecorder
+	if store, ok := repo.(ports.TimelineStore); ok && !cfg.Database.ReadOnly {
+		timelineRecorder = services.NewTimelineRecorder(incidentAnalyzer, store)
+		apiHandler.SetTimelineRecorder(timelineRecorder)
+	}
 
-		if err := http.ListenAndServe(apiAddr, mux); err != nil {
-			logger.Error("API server failed", observability.Error(err))
+	// Link incidents repeating resolved ones as occurrences of the same problem
+	var problemLinker *services.ProblemLinker
+	if store, ok := repo.(ports.ProblemStore); ok && cfg.Incident.RecurrenceThreshold > 0 && !cfg.Database.ReadOnly {
+		problemLinker = services.NewProblemLinker(repo, store, cfg.Incident.RecurrenceThreshold)
+	}
+
+	// Attach the recent values of their charts to the alerts of incidents as they form
+	var alertSampler *services.AlertSampler
+	if metricSource, ok := netdataClient.(ports.MetricSource); ok && cfg.Netdata.SamplePoints > 0 && !cfg.Database.ReadOnly {
+		store, _ := repo.(ports.AlertSampleStore)
+		alertSampler = services.NewAlertSampler(store, cfg.Netdata.SampleWindow, cfg.Netdata.SamplePoints)
+		alertSampler.AddSource("netdata", metricSource)
+	}
+
+	// Store each model version's root causes, score them against feedback and calibrate
+	// the confidences from the outcomes so far
+	if store, ok := repo.(ports.RootCauseStore); ok && activeModel != nil {
+		evaluator := services.NewModelEvaluator(store, activeModel, modelVersions,
+			cfg.AI.CalibrationBins, cfg.AI.CalibrationMinSamples)
+		if err := evaluator.Calibrate(ctx); err != nil {
+			logger.Warn("Failed to calibrate AI models", observability.Error(err))
+		}
+		apiHandler.SetModelEvaluator(evaluator)
+	}
+
+	// File Jira/GitHub tickets for incidents and close them on resolution
+	var ticketManager *services.TicketManager
+	if cfg.Ticketing.Tracker != "" {
+		store, ok := repo.(ports.TicketStore)
+		if !ok {
+			logger.Fatal("Ticketing is not supported by this database", observability.String("type", cfg.Database.Type))
+		}
+		ticketManager = services.NewTicketManager(ticketTracker(cfg.Ticketing), store)
+		if cfg.Ticketing.AutoCreate {
+			ticketManager.SetAutoCreate(cfg.Ticketing.MinSeverity)
+		}
+		if learner != nil {
+			ticketManager.SetPropagationLearner(learner)
+		}
+		ticketManager.SetPlaybooks(playbooks)
+		apiHandler.SetTicketManager(ticketManager)
+		logger.Info("Ticketing enabled",
+			observability.String("tracker", cfg.Ticketing.Tracker),
+			observability.Bool("auto_create", cfg.Ticketing.AutoCreate))
+	}
+
+	// Elect one replica to poll the alert sources, escalate, send reminders and export
+	// timelines when several share the database
+	var elector *services.LeaderElector
+	if cfg.Ingestion.LeaderElection && !cfg.Database.ReadOnly {
+		store, ok := repo.(ports.LeaseStore)
+		if !ok {
+			logger.Fatal("Leader election is not supported by this database", observability.String("type", cfg.Database.Type))
+		}
+		replica := cfg.Ingestion.ReplicaID
+		if replica == "" {
+			hostname, _ := os.Hostname()
+			replica = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+		}
+		elector = services.NewLeaderElector(store, services.IngestionLease, replica, cfg.Ingestion.LeaseTTL)
+		apiHandler.SetLeaderElector(elector)
+		logger.Info("Leader election enabled",
+			observability.String("replica", replica),
+			observability.String("lease_ttl", cfg.Ingestion.LeaseTTL.String()))
+	}
+
+	// Page the next escalation level while incidents stay unacknowledged
+	if cfg.Escalation.Enabled && !cfg.Database.ReadOnly {
+		store, ok := repo.(ports.EscalationStore)
+		if !ok {
+			logger.Fatal("Escalation is not supported by this database", observability.String("type", cfg.Database.Type))
+		}
+		policies, err := escalationPolicies(cfg.Escalation, notifyRedactor)
+		if err != nil {
+			logger.Fatal("Invalid escalation policies", observability.Error(err))
+		}
+		escalator := services.NewEscalator(policies, store, apiHandler.Acknowledgements(), dispatcher)
+		if onCall != nil {
+			escalator.SetOnCall(onCall)
+		}
+		if acks, ok := repo.(ports.AcknowledgementStore); ok {
+			escalator.SetAcknowledgementStore(acks)
+		}
+		leaderTasks = append(leaderTasks, func(ctx context.Context) {
+			escalator.Run(ctx, repo, cfg.Escalation.CheckInterval)
+		})
+		logger.Info("Escalation enabled",
+			observability.Int("policies", len(policies)),
+			observability.String("check_interval", cfg.Escalation.CheckInterval.String()))
+	}
+
+	// Report incident action items once they are past their due date
+	if store, ok := repo.(ports.ActionItemStore); ok && dispatcher != nil &&
+		cfg.Notifications.OverdueActionItemsInterval > 0 && !cfg.Database.ReadOnly {
+		reminder := services.NewActionItemReminder(store, dispatcher)
+		leaderTasks = append(leaderTasks, func(ctx context.Context) {
+			reminder.Run(ctx, repo, cfg.Notifications.OverdueActionItemsInterval)
+		})
+		logger.Info("Overdue action item reminders enabled",
+			observability.String("interval", cfg.Notifications.OverdueActionItemsInterval.String()))
+	}
+
+	// Deliver incident events to the webhooks managed through /api/webhooks
+	var webhooks *exporter.WebhookPublisher
+	if w := cfg.Exporters.Webhooks; w.Enabled {
+		store, ok := repo.(ports.WebhookStore)
+		if !ok {
+			logger.Fatal("Webhooks are enabled but the repository can't store them")
+		}
+		webhooks = exporter.NewWebhookPublisher(store, exporter.WebhookOptions{
+			MaxAttempts:    w.MaxAttempts,
+			InitialBackoff: w.InitialBackoff,
+			MaxBackoff:     w.MaxBackoff,
+			Timeout:        w.Timeout,
+		})
+		apiHandler.SetWebhooks(webhooks)
+	}
+
+	// Publish incident events to Kafka/NATS for downstream data platforms
+	eventExporter, err := newEventExporter(ctx, cfg.Exporters, repo, webhooks)
+	if err != nil {
+		logger.Fatal("Failed to create event exporter", observability.Error(err))
+	}
+	if eventExporter != nil {
+		apiHandler.SetExporter(eventExporter)
+		logger.Info("Event exporter enabled",
+			observability.String("encoding", cfg.Exporters.Encoding),
+			observability.Bool("kafka", cfg.Exporters.Kafka.Enabled),
+			observability.Bool("nats", cfg.Exporters.NATS.Enabled),
+			observability.Bool("webhooks", webhooks != nil))
+	}
+
+	// Start API server
+	server := &http.Server{
+		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
+		Handler:      apiHandler.SetupRoutes(),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+	go func() {
+		logger.Info("Starting API server", observability.String("addr", server.Addr))
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("API server failed", observability.Error(err))
 		}
 	}()
 
+	// Start gRPC server alongside the REST API
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPCPort > 0 {
+		addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.GRPCPort)
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			logger.Fatal("Failed to listen for gRPC", observability.Error(err))
+		}
+		grpcServer = apiHandler.NewGRPCServer()
+		go func() {
+			logger.Info("Starting gRPC server", observability.String("addr", addr))
+			if err := grpcServer.Serve(listener); err != nil {
+				logger.Fatal("gRPC server failed", observability.Error(err))
+			}
+		}()
+	}
+
 	// Handle graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
-	// Start poller in background
-	go func() {
-		logger.Info("Starting alert poller",
-			observability.String("interval", cfg.Netdata.PollInterval.String()))
+	// Start backfill and pollers in background (not in read-only snapshot mode)
+	switch {
+	case cfg.Database.ReadOnly:
+		logger.Info("Read-only snapshot mode: backfill, poller and mutations disabled")
+	case !cfg.Ingestion.Backfill && !cfg.Ingestion.Poller:
+		logger.Info("Ingestion disabled: serving stored data only")
+	}
+	if cfg.Ingestion.Backfill && !cfg.Database.ReadOnly {
+		go backfillIncidents(ctx, repo, logger, incidentBuilder)
+	}
+	if cfg.Ingestion.Poller && !cfg.Database.ReadOnly {
+		ingest := func(ctx context.Context) {
+			// On the first run, fill the store with the recent past before polling
+			if cfg.Ingestion.HistoryWindow > 0 {
+				since := time.Now().Add(-cfg.Ingestion.HistoryWindow)
+				if history := sources.BackfillHistory(ctx, since, cfg.Ingestion.HistoryChunk); len(history) > 0 {
+					backfillIncidents(ctx, repo, logger, incidentBuilder)
+				}
+			}
+
+			logger.Info("Starting alert sources",
+				observability.String("sources", strings.Join(sources.Sources(), ",")))
+			if watchdog != nil {
+				go watchdog.Run(ctx)
+			}
 
-		if err := poller.Start(ctx); err != nil && err != context.Canceled {
-			logger.Error("Poller error", observability.Error(err))
+			if err := sources.Start(ctx); err != nil && err != context.Canceled {
+				logger.Error("Poller error", observability.Error(err))
+			}
 		}
-	}()
+		// Replicas poll only while they are the leader, so followers are ready without polling
+		if elector == nil {
+			healthChecker.RegisterReadinessCheck("poller", sources.PollingCheck())
+		}
+		leaderTasks = append(leaderTasks, ingest)
+	}
+	if elector != nil {
+		go elector.Run(ctx, func(ctx context.Context) { runAll(ctx, leaderTasks) })
+	} else {
+		for _, task := range leaderTasks {
+			go task(ctx)
+		}
+	}
 
-	// Monitor events and perform AI analysis
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
+	// Correlate, persist and notify stored alert batches with the worker pool
+	processAlerts := func(ctx context.Context, alerts []domain.Alert) {
+		logger.Info("Received alerts for analysis",
+			observability.Int("count", len(alerts)))
+
+		metrics.RecordDuration("alerts_received_duration", time.Since(time.Now()), nil)
+
+		// Perform comprehensive analysis
+		timeline := incidentAnalyzer.AnalyzeIncident(alerts)
+
+		// Correlate the batch into incidents and persist them; flapping
+		// streams are only reported by /api/alerts/noisy
+		if flapDetector != nil && cfg.Flapping.ExcludeFromIncidents {
+			alerts = services.WithoutFlapping(alerts)
+		}
+		incidents := incidentBuilder.Build(alerts)
+		for _, incident := range incidents {
+			// Injected repository latency only fails on shutdown
+			if err := faultInjector.Delay(ctx); err != nil {
 				return
-			case alerts := <-poller.Events():
-				logger.Info("Received alerts for analysis",
-					observability.Int("count", len(alerts)))
-
-				metrics.RecordDuration("alerts_received_duration", time.Since(time.Now()), nil)
-
-				// Perform comprehensive analysis
-				timeline := incidentAnalyzer.AnalyzeIncident(alerts)
-
-				// Generate AI-powered insights if enabled
-				if cfg.AI.Enabled && aiModel != nil {
-					aiCtx, aiCancel := context.WithTimeout(ctx, cfg.AI.PredictionTimeout)
-					defer aiCancel()
-
-					rootCause, err := aiModel.PredictRootCause(aiCtx, alerts)
-					if err != nil {
-						logger.Warn("AI prediction failed", observability.Error(err))
-					} else {
-						logger.Info("AI root cause prediction",
-							observability.Float64("confidence", rootCause.Confidence),
-							observability.String("pattern_type", rootCause.PatternType))
-
-						metrics.RecordHistogram("ai_predictions_total", 1, map[string]string{
-							"type": "root_cause",
-						})
-					}
-
-					blastRadius, err := aiModel.PredictBlastRadius(aiCtx, alerts)
-					if err != nil {
-						logger.Warn("AI blast radius prediction failed", observability.Error(err))
-					} else {
-						logger.Info("AI blast radius prediction",
-							observability.Float64("impact_score", blastRadius.ImpactScore),
-							observability.String("risk_level", blastRadius.RiskLevel))
-
-						metrics.RecordHistogram("ai_predictions_total", 1, map[string]string{
-							"type": "blast_radius",
-						})
-					}
+			}
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				logger.Error("Failed to save incident",
+					observability.String("incident_id", incident.ID),
+					observability.Error(err))
+				continue
+			}
+			if alertSampler != nil {
+				sampled, err := alertSampler.Sample(ctx, incident)
+				if err != nil {
+					logger.Warn("Failed to sample incident alerts",
+						observability.String("incident_id", incident.ID),
+						observability.Error(err))
+				}
+				incident = sampled
+			}
+			if timelineRecorder != nil {
+				if _, err := timelineRecorder.Record(ctx, incident); err != nil {
+					logger.Warn("Failed to record incident timeline",
+						observability.String("incident_id", incident.ID),
+						observability.Error(err))
+				}
+			}
+			if problemLinker != nil {
+				link, err := problemLinker.Link(ctx, incident)
+				if err != nil {
+					logger.Warn("Failed to link incident to problem",
+						observability.String("incident_id", incident.ID),
+						observability.Error(err))
+				} else if link != nil {
+					logger.Info("Incident linked to recurring problem",
+						observability.String("incident_id", incident.ID),
+						observability.String("problem_id", link.ProblemID),
+						observability.Float64("similarity", link.Similarity))
+				}
+			}
+			if ticketManager != nil {
+				if err := ticketManager.Sync(ctx, incident); err != nil {
+					logger.Warn("Failed to sync incident ticket",
+						observability.String("incident_id", incident.ID),
+						observability.Error(err))
+				}
+			}
+			if eventExporter != nil {
+				if err := eventExporter.IncidentSaved(ctx, incident); err != nil {
+					logger.Warn("Failed to export incident event",
+						observability.String("incident_id", incident.ID),
+						observability.Error(err))
+				}
+			}
+			if analysisQueue != nil {
+				if err := analysisQueue.Enqueue(incident); err != nil {
+					logger.Warn("Failed to queue incident analysis",
+						observability.String("incident_id", incident.ID),
+						observability.Error(err))
 				}
+			}
+		}
+
+		// Generate AI-powered insights if enabled
+		if cfg.AI.Enabled && aiModel != nil {
+			aiCtx, aiCancel := context.WithTimeout(ctx, cfg.AI.PredictionTimeout)
+
+			rootCause, err := aiModel.PredictRootCause(aiCtx, alerts)
+			if err != nil {
+				logger.Warn("AI prediction failed", observability.Error(err))
+			} else {
+				logger.Info("AI root cause prediction",
+					observability.Float64("confidence", rootCause.Confidence),
+					observability.String("pattern_type", rootCause.PatternType))
 
-				// Generate summary
-				summary := incidentAnalyzer.GenerateIncidentSummary(timeline)
-				logger.Info("Incident analysis completed",
-					observability.String("summary", summary))
+				metrics.RecordHistogram("ai_predictions_total", 1, map[string]string{
+					"type": "root_cause",
+				})
+			}
+
+			blastRadius, err := aiModel.PredictBlastRadius(aiCtx, alerts)
+			if err != nil {
+				logger.Warn("AI blast radius prediction failed", observability.Error(err))
+			} else {
+				logger.Info("AI blast radius prediction",
+					observability.Float64("impact_score", blastRadius.ImpactScore),
+					observability.String("risk_level", blastRadius.RiskLevel))
 
-				metrics.RecordHistogram("incidents_analyzed_total", 1, nil)
+				metrics.RecordHistogram("ai_predictions_total", 1, map[string]string{
+					"type": "blast_radius",
+				})
+			}
+			aiCancel()
+		}
+
+		// Notify about active incidents in this batch
+		if incidentNotifier != nil {
+			for _, incident := range incidents {
+				if incident.ResolvedAt != nil {
+					continue
+				}
+				if err := incidentNotifier.Notify(ctx, incident); err != nil {
+					logger.Warn("Failed to send incident notification",
+						observability.String("incident_id", incident.ID),
+						observability.Error(err))
+				}
 			}
 		}
-	}()
+
+		// Generate summary
+		summary := incidentAnalyzer.GenerateIncidentSummary(timeline)
+		logger.Info("Incident analysis completed",
+			observability.String("summary", summary))
+
+		metrics.RecordHistogram("incidents_analyzed_total", 1, nil)
+	}
+	go alertQueue.Run(ctx, processAlerts)
 
 	logger.Info("IncidentTeller started successfully",
 		observability.String("mode", func() string {
@@ -271,9 +1276,27 @@ func main() {
 	<-sigChan
 	logger.Info("Shutdown signal received")
 
-	// Cancel context and wait for graceful shutdown
+	// Stop ingestion and let in-flight requests finish
 	cancel()
-	time.Sleep(2 * time.Second)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("API server forced to shutdown", observability.Error(err))
+	}
+	if metricsServer != nil {
+		metricsServer.Shutdown(shutdownCtx)
+	}
+	if grpcServer != nil {
+		// Streams only end when their clients leave, so they are cut off after a grace period
+		timer := time.AfterFunc(5*time.Second, grpcServer.Stop)
+		grpcServer.GracefulStop()
+		timer.Stop()
+	}
+	if eventExporter != nil {
+		if err := eventExporter.Close(); err != nil {
+			logger.Warn("Failed to close event exporter", observability.Error(err))
+		}
+	}
 
 	// Print final statistics
 	if sqlRepo, ok := repo.(*database.SQLRepository); ok {
@@ -287,3 +1310,595 @@ func main() {
 
 	logger.Info("IncidentTeller stopped")
 }
+
+// notificationChannels creates a notifier for every configured webhook; route webhooks
+// only receive incidents matching the route's labels and matchers
+func notificationChannels(cfg config.NotificationsConfig, redactor *redact.Redactor) ([]notify.Notifier, error) {
+	var channels []notify.Notifier
+	if cfg.SlackWebhookURL != "" {
+		channels = append(channels, notify.Redacted(notify.NewSlackNotifier(cfg.SlackWebhookURL), redactor))
+	}
+	if cfg.TeamsWebhookURL != "" {
+		channels = append(channels, notify.Redacted(notify.NewTeamsNotifier(cfg.TeamsWebhookURL), redactor))
+	}
+	if cfg.DiscordWebhookURL != "" {
+		channels = append(channels, notify.Redacted(notify.NewDiscordNotifier(cfg.DiscordWebhookURL), redactor))
+	}
+	for _, route := range cfg.Routes {
+		matchers, err := labels.ParseMatcherList(route.Matchers)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notification route: %w", err)
+		}
+		matchers = append(labels.Equal(route.Labels), matchers...)
+		if route.SlackWebhookURL != "" {
+			channels = append(channels, notify.Routed(notify.Redacted(notify.NewSlackNotifier(route.SlackWebhookURL), redactor), matchers))
+		}
+		if route.TeamsWebhookURL != "" {
+			channels = append(channels, notify.Routed(notify.Redacted(notify.NewTeamsNotifier(route.TeamsWebhookURL), redactor), matchers))
+		}
+		if route.DiscordWebhookURL != "" {
+			channels = append(channels, notify.Routed(notify.Redacted(notify.NewDiscordNotifier(route.DiscordWebhookURL), redactor), matchers))
+		}
+	}
+	return channels, nil
+}
+
+// escalationPolicies converts the escalation config into policies; levels with webhooks
+// page their own channels, the others the default notification channels
+func escalationPolicies(cfg config.EscalationConfig, redactor *redact.Redactor) ([]services.EscalationPolicy, error) {
+	policies := make([]services.EscalationPolicy, len(cfg.Policies))
+	for i, policy := range cfg.Policies {
+		priority, err := domain.ParsePriority(policy.Priority)
+		if err != nil {
+			return nil, err
+		}
+		policies[i] = services.EscalationPolicy{Priority: priority, Levels: make([]services.EscalationLevel, len(policy.Levels))}
+		for j, level := range policy.Levels {
+			channels, err := notificationChannels(config.NotificationsConfig{
+				SlackWebhookURL:   level.SlackWebhookURL,
+				TeamsWebhookURL:   level.TeamsWebhookURL,
+				DiscordWebhookURL: level.DiscordWebhookURL,
+			}, redactor)
+			if err != nil {
+				return nil, err
+			}
+			policies[i].Levels[j] = services.EscalationLevel{After: level.After, OnCallSecondary: level.OnCallSecondary}
+			if len(channels) > 0 {
+				policies[i].Levels[j].Channels = notify.NewDispatcher(channels...)
+			}
+		}
+	}
+	return policies, nil
+}
+
+func qualityGate(cfg config.NotificationsConfig) *services.QualityGate {
+	return services.NewQualityGate(cfg.MinConfidence, cfg.MinSummaryLength, cfg.MaxSummaryLength)
+}
+
+// onCallSchedule converts the on-call config into a rotation schedule. Without an explicit
+// rotation start the rotation is anchored at the Unix epoch, so it is stable across restarts.
+func onCallSchedule(cfg config.OnCallConfig) oncall.Schedule {
+	start := time.Unix(0, 0).UTC()
+	if cfg.RotationStart != "" {
+		// Already validated by config.Load
+		start, _ = time.Parse(time.RFC3339, cfg.RotationStart)
+	}
+
+	members := make([]oncall.Member, len(cfg.Members))
+	for i, m := range cfg.Members {
+		members[i] = oncall.Member{Name: m.Name, Email: m.Email, SlackID: m.SlackID}
+	}
+
+	return oncall.Schedule{
+		Members:     members,
+		Start:       start,
+		ShiftLength: cfg.ShiftLength,
+	}
+}
+
+// riskPolicy converts the risk config into the policy incidents are classified with
+func riskPolicy(cfg config.RiskConfig) domain.RiskPolicy {
+	thresholds := func(t config.RiskThresholdsConfig) domain.RiskThresholds {
+		return domain.RiskThresholds{CriticalAlerts: t.CriticalAlerts, Hosts: t.Hosts, ResourceTypes: t.ResourceTypes}
+	}
+	policy := domain.RiskPolicy{
+		Medium:    thresholds(cfg.Medium),
+		High:      thresholds(cfg.High),
+		Critical:  thresholds(cfg.Critical),
+		Resources: make(map[domain.ResourceType]domain.ResourceRisk, len(cfg.Resources)),
+	}
+	for name, resource := range cfg.Resources {
+		policy.Resources[domain.ResourceType(name)] = domain.ResourceRisk{Weight: resource.Weight, HighValue: resource.HighValue}
+	}
+	return policy
+}
+
+// ticketTracker creates the configured issue tracker
+func ticketTracker(cfg config.TicketingConfig) ticketing.Tracker {
+	if cfg.Tracker == "github" {
+		return ticketing.NewGitHubTracker(cfg.GitHub.APIURL, cfg.GitHub.Repo, cfg.GitHub.Token)
+	}
+	return ticketing.NewJiraTracker(ticketing.JiraOptions{
+		BaseURL:    cfg.Jira.BaseURL,
+		Email:      cfg.Jira.Email,
+		APIToken:   cfg.Jira.APIToken,
+		Project:    cfg.Jira.Project,
+		IssueType:  cfg.Jira.IssueType,
+		DoneStatus: cfg.Jira.DoneStatus,
+	})
+}
+
+// newEventExporter creates the exporter for the enabled brokers and webhooks, or nil if
+// none is enabled. Stored incidents are seeded so they aren't exported as new on restart.
+func newEventExporter(ctx context.Context, cfg config.ExportersConfig, repo api.Repository, webhooks *exporter.WebhookPublisher) (*exporter.Exporter, error) {
+	if !cfg.Kafka.Enabled && !cfg.NATS.Enabled && webhooks == nil {
+		return nil, nil
+	}
+
+	encoder, err := exporter.NewEncoder(cfg.Encoding, cfg.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	var publishers []exporter.Publisher
+	if cfg.Kafka.Enabled {
+		publishers = append(publishers, exporter.NewKafkaPublisher(cfg.Kafka.Brokers, cfg.Kafka.Topic))
+	}
+	if cfg.NATS.Enabled {
+		publisher, err := exporter.NewNATSPublisher(cfg.NATS.URL, cfg.NATS.SubjectPrefix)
+		if err != nil {
+			return nil, err
+		}
+		publishers = append(publishers, publisher)
+	}
+	if webhooks != nil {
+		publishers = append(publishers, webhooks)
+	}
+
+	e := exporter.New(encoder, publishers...)
+	incidents, err := repo.GetIncidents(ctx)
+	if err != nil {
+		e.Close()
+		return nil, fmt.Errorf("failed to load incidents: %w", err)
+	}
+	e.Seed(incidents)
+	return e, nil
+}
+
+// runAll runs the tasks concurrently and returns once all of them have returned
+func runAll(ctx context.Context, tasks []func(ctx context.Context)) {
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		wg.Add(1)
+		go func(task func(ctx context.Context)) {
+			defer wg.Done()
+			task(ctx)
+		}(task)
+	}
+	wg.Wait()
+}
+
+// backfillIncidents correlates the stored alerts into incidents, so incidents exist for
+// alerts ingested before incidents were persisted
+func backfillIncidents(ctx context.Context, repo api.Repository, logger observability.Logger, builder *services.IncidentBuilder) {
+	logger.Info("Checking for alerts to backfill...")
+	alerts, err := repo.GetAlerts(ctx)
+	if err != nil {
+		logger.Error("Backfill failed to get alerts", observability.Error(err))
+		return
+	}
+
+	if len(alerts) == 0 {
+		return
+	}
+
+	incidents := builder.Build(alerts)
+	for _, incident := range incidents {
+		if err := repo.SaveIncident(ctx, incident); err != nil {
+			logger.Error("Failed to backfill incident",
+				observability.String("incident_id", incident.ID),
+				observability.Error(err))
+		}
+	}
+	logger.Info("Backfill complete", observability.Int("incidents", len(incidents)))
+}
+
+// runMigrate implements the "migrate up|down [N]|status" subcommand
+func runMigrate(ctx context.Context, repo *database.SQLRepository, args []string) error {
+	migrator, err := repo.Migrator()
+	if err != nil {
+		return err
+	}
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		fmt.Printf("Applied %d migration(s)\n", applied)
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		fmt.Printf("Rolled back %d migration(s)\n", rolledBack)
+		return err
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range statuses {
+			state := "pending"
+			if st.Applied {
+				state = "applied " + st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d  %-30s %s\n", st.Version, st.Name, state)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown migrate command %q (expected up, down or status)", command)
+	}
+}
+
+// runReplay implements the "replay export FILE" and "replay run [flags] DUMP" subcommands.
+// export writes the stored alerts as a dump; run feeds a dump through the configured
+// pipeline in memory, leaving the database untouched.
+func runReplay(ctx context.Context, cfg *config.Config, repo api.Repository, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected replay export FILE or replay run DUMP")
+	}
+
+	switch args[0] {
+	case "export":
+		if len(args) != 2 {
+			return fmt.Errorf("expected replay export FILE")
+		}
+		alerts, err := repo.GetAlerts(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get alerts: %w", err)
+		}
+		out := os.Stdout
+		if args[1] != "-" {
+			if out, err = os.Create(args[1]); err != nil {
+				return err
+			}
+			defer out.Close()
+		}
+		if err := replay.WriteDump(out, alerts); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d alerts\n", len(alerts))
+		return nil
+
+	case "run":
+		flags := flag.NewFlagSet("replay run", flag.ContinueOnError)
+		speed := flags.Float64("speed", 0, "Replay speedup: 1 is real time, 60 a minute per second, 0 as fast as possible")
+		batch := flags.Duration("batch", cfg.Netdata.PollInterval, "Recorded time one batch of alerts spans")
+		truthPath := flags.String("truth", "", "Ground truth JSON file to compare the replay with")
+		reportPath := flags.String("report", "", "Write the replay result and comparison as JSON to this file")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return fmt.Errorf("expected replay run [flags] DUMP")
+		}
+
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		alerts, err := replay.ReadDump(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read dump: %w", err)
+		}
+
+		var truth *replay.GroundTruth
+		if *truthPath != "" {
+			if truth, err = replay.LoadGroundTruth(*truthPath); err != nil {
+				return err
+			}
+		}
+
+		engine, err := replay.FromConfig(cfg)
+		if err != nil {
+			return err
+		}
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		result, err := engine.Run(ctx, alerts, replay.Options{Speed: *speed, BatchInterval: *batch})
+		if err != nil {
+			return err
+		}
+
+		var report *replay.Report
+		if truth != nil {
+			compared := replay.Compare(result, alerts, truth)
+			report = &compared
+		}
+		if err := replay.WriteText(os.Stdout, result, report); err != nil {
+			return err
+		}
+		if *reportPath != "" {
+			data, err := json.MarshalIndent(struct {
+				Result *replay.Result `json:"result"`
+				Report *replay.Report `json:"report,omitempty"`
+			}{result, report}, "", "  ")
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(*reportPath, append(data, '\n'), 0o644)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown replay command %q (expected export or run)", args[0])
+	}
+}
+
+// runSnapshot implements the "snapshot export [flags] FILE" and "snapshot import FILE"
+// subcommands, e.g. to move incidents to another database backend
+func runSnapshot(ctx context.Context, repo api.Repository, readOnly bool, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected snapshot export FILE or snapshot import FILE")
+	}
+
+	switch args[0] {
+	case "export":
+		flags := flag.NewFlagSet("snapshot export", flag.ContinueOnError)
+		ids := flags.String("ids", "", "Comma-separated incident IDs to export; all incidents if empty")
+		sanitize := flags.Bool("sanitize", false, "Replace hostnames and remove people, for sharing the snapshot")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return fmt.Errorf("expected snapshot export [flags] FILE")
+		}
+
+		var selected []string
+		if *ids != "" {
+			selected = strings.Split(*ids, ",")
+		}
+		exported, err := snapshot.Export(ctx, repo, selected)
+		if err != nil {
+			return err
+		}
+		if *sanitize {
+			exported = snapshot.Sanitize(exported)
+		}
+
+		out := os.Stdout
+		if flags.Arg(0) != "-" {
+			if out, err = os.Create(flags.Arg(0)); err != nil {
+				return err
+			}
+			defer out.Close()
+		}
+		if err := snapshot.Write(out, exported); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d incidents\n", len(exported.Incidents))
+		return nil
+
+	case "import":
+		if len(args) != 2 {
+			return fmt.Errorf("expected snapshot import FILE")
+		}
+		if readOnly {
+			return fmt.Errorf("the database is read-only")
+		}
+		in := os.Stdin
+		if args[1] != "-" {
+			file, err := os.Open(args[1])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			in = file
+		}
+		imported, err := snapshot.Read(in)
+		if err != nil {
+			return err
+		}
+		result, err := snapshot.Import(ctx, repo, imported)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Imported %d incidents, %d alerts, %d root causes, %d timeline entries and %d priority changes\n",
+			result.Incidents, result.Alerts, result.RootCauses, result.TimelineEntries, result.PriorityChanges)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown snapshot command %q (expected export or import)", args[0])
+	}
+}
+
+// runReport implements the "report weekly [flags]" subcommand, writing the weekly
+// reliability report of the week ending at -to as Markdown or PDF
+func runReport(ctx context.Context, repo api.Repository, args []string) error {
+	if len(args) == 0 || args[0] != "weekly" {
+		return fmt.Errorf("expected report weekly [-format markdown|pdf] [-to DATE] [-output FILE]")
+	}
+
+	flags := flag.NewFlagSet("report weekly", flag.ContinueOnError)
+	format := flags.String("format", "markdown", "Output format: markdown or pdf")
+	toFlag := flags.String("to", "", "End of the reported week as RFC3339 or YYYY-MM-DD (inclusive); default now")
+	output := flags.String("output", "-", "File to write the report to; - writes to stdout")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	renderer, err := report.NewRenderer(*format)
+	if err != nil {
+		return err
+	}
+	if renderer.Format() != report.FormatMarkdown && renderer.Format() != report.FormatPDF {
+		return fmt.Errorf("unsupported report format %q (expected markdown or pdf)", *format)
+	}
+
+	to := time.Now().UTC()
+	if *toFlag != "" {
+		if to, err = time.Parse(time.RFC3339, *toFlag); err != nil {
+			date, dateErr := time.Parse("2006-01-02", *toFlag)
+			if dateErr != nil {
+				return fmt.Errorf("invalid -to %q: must be RFC3339 or YYYY-MM-DD", *toFlag)
+			}
+			to = date.Add(24 * time.Hour)
+		}
+	}
+
+	incidents, err := repo.GetIncidents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get incidents: %w", err)
+	}
+	tickets, _ := repo.(ports.TicketStore)
+	actions, _ := repo.(ports.ActionItemStore)
+	actionItems, err := services.OpenActionItems(ctx, tickets, actions, incidents)
+	if err != nil {
+		return err
+	}
+	weekly := services.NewDigestBuilder().BuildWeekly(incidents, actionItems, to)
+	rendered := renderer.Render(services.WeeklyReportDocument(weekly))
+
+	if *output == "-" {
+		_, err = os.Stdout.WriteString(rendered)
+		return err
+	}
+	if err := os.WriteFile(*output, []byte(rendered), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote weekly report covering %d incidents to %s\n", weekly.Incidents, *output)
+	return nil
+}
+
+// runValidate implements the "validate [flags]" subcommand: it loads and validates the
+// configuration, then probes the database and the enabled alert sources and AI services
+// without starting anything. It returns the exit code: 1 if any check failed.
+func runValidate(configPath string, args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	path := flags.String("config", configPath, "Path to configuration file")
+	offline := flags.Bool("offline", false, "Only validate the configuration, without connectivity probes")
+	timeout := flags.Duration("timeout", 10*time.Second, "Timeout of each connectivity probe")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Load(*path)
+	if err != nil {
+		fmt.Printf("❌ configuration: %v\n", err)
+		return 1
+	}
+	fmt.Println("✅ configuration: valid")
+	if *offline {
+		return 0
+	}
+
+	type probe struct {
+		name  string
+		check func(ctx context.Context) error
+	}
+	probes := []probe{{"database (" + cfg.Database.Type + ")", func(ctx context.Context) error {
+		return probeDatabase(ctx, cfg.Database)
+	}}}
+	if cfg.Netdata.Enabled && cfg.Netdata.CloudEnabled {
+		probes = append(probes, probe{"netdata cloud", func(ctx context.Context) error {
+			_, err := netdata.NewCloudClient(cfg.Netdata.CloudToken, cfg.Netdata.CloudSpace, cfg.Netdata.CloudRooms...).FetchLatest(ctx, 0)
+			return err
+		}})
+	} else if cfg.Netdata.Enabled {
+		probes = append(probes, probe{"netdata " + cfg.Netdata.BaseURL, func(ctx context.Context) error {
+			if result := observability.NetdataHealthCheck(cfg.Netdata.BaseURL)(ctx); result.Status != "healthy" {
+				return fmt.Errorf("%s", result.Message)
+			}
+			return nil
+		}})
+	}
+	if cfg.Zabbix.Enabled {
+		probes = append(probes, probe{"zabbix " + cfg.Zabbix.URL, func(ctx context.Context) error {
+			client := zabbix.NewClient(cfg.Zabbix.URL, cfg.Zabbix.APIToken, cfg.Zabbix.Username, cfg.Zabbix.Password, cfg.Zabbix.Timeout)
+			_, err := client.FetchLatest(ctx, 0)
+			return err
+		}})
+	}
+	if cfg.AI.Enabled && cfg.AI.OpenAI.Enabled && (cfg.AI.ModelType == "openai" || cfg.AI.ModelType == "hybrid") {
+		probes = append(probes, probe{"openai", func(ctx context.Context) error {
+			client, err := openai.NewClient(cfg.AI.OpenAI)
+			if err != nil {
+				return err
+			}
+			return client.Ping(ctx)
+		}})
+	}
+
+	code := 0
+	for _, p := range probes {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		err := p.check(ctx)
+		cancel()
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", p.name, err)
+			code = 1
+			continue
+		}
+		fmt.Printf("✅ %s: ok\n", p.name)
+	}
+	return code
+}
+
+// probeDatabase connects to the configured database and closes the connection again
+func probeDatabase(ctx context.Context, cfg config.DatabaseConfig) error {
+	// Opening a missing SQLite file would create it; its directory is enough
+	if cfg.Type == "sqlite" {
+		if _, err := os.Stat(cfg.SQLitePath); errors.Is(err, os.ErrNotExist) {
+			_, err := os.Stat(filepath.Dir(cfg.SQLitePath))
+			return err
+		}
+	}
+
+	switch cfg.Type {
+	case "postgres", "postgresql", "mysql", "sqlite":
+		dialect, err := database.ParseDialect(cfg.Type)
+		if err != nil {
+			return err
+		}
+		db, err := sql.Open(dialect.DriverName(), cfg.GetDSN())
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		return db.PingContext(ctx)
+	case "redis":
+		redisRepo := redisrepo.NewRepository(redisrepo.Options{
+			Addr:      cfg.RedisAddr,
+			Password:  cfg.RedisPassword,
+			DB:        cfg.RedisDB,
+			KeyPrefix: cfg.RedisKeyPrefix,
+			TTL:       cfg.RedisTTL,
+		})
+		defer redisRepo.Close()
+		return redisRepo.PingContext(ctx)
+	case "mongodb":
+		mongoRepo, err := mongodb.NewRepository(mongodb.Options{URI: cfg.MongoURI, Database: cfg.Database})
+		if err != nil {
+			return err
+		}
+		defer mongoRepo.Close(context.Background())
+		return mongoRepo.PingContext(ctx)
+	case "memory":
+		return nil
+	default:
+		return fmt.Errorf("unsupported database type %q", cfg.Type)
+	}
+}
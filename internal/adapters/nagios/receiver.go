@@ -0,0 +1,327 @@
+package nagios
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
+)
+
+// Check results are small; anything larger is rejected
+const maxResultBody = 64 << 10
+
+// Receiver implements the AlertSource interface for Nagios and Icinga. Check results are
+// pushed to its webhook (e.g. from an OCSP/OCHP command or an Icinga event command) and
+// buffered until the poller fetches them.
+//
+// Passive checks repeat the current state on every run, so only state changes become
+// alerts, like the entries of a Netdata alarm log.
+type Receiver struct {
+	token    string
+	hostname string // Default hostname if a result has none
+	capacity int
+
+	mu      sync.Mutex
+	pending []domain.Alert
+	states  map[string]domain.AlertStatus // host/service -> last known status
+	lastID  uint64
+}
+
+// NewReceiver creates a check result receiver. Requests must carry the token as a Bearer
+// Authorization header or ?token= query parameter. At most capacity results are buffered
+// between polls; the oldest are dropped first.
+func NewReceiver(token, hostname string, capacity int) *Receiver {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &Receiver{
+		token:    token,
+		hostname: hostname,
+		capacity: capacity,
+		states:   make(map[string]domain.AlertStatus),
+	}
+}
+
+// CheckResult is a passive check result. Field names of the Nagios macros and of the
+// Icinga 2 process-check-result API are both accepted.
+type CheckResult struct {
+	Host               string `json:"host"`
+	HostName           string `json:"host_name"`
+	Service            string `json:"service"`
+	ServiceDescription string `json:"service_description"`
+	ReturnCode         *int   `json:"return_code"`
+	ExitStatus         *int   `json:"exit_status"`
+	State              string `json:"state"` // OK, WARNING, CRITICAL, UNKNOWN, UP, DOWN, UNREACHABLE
+	Output             string `json:"output"`
+	PluginOutput       string `json:"plugin_output"`
+	PerformanceData    string `json:"performance_data"`
+	Timestamp          int64  `json:"timestamp"` // Unix seconds, defaults to the receive time
+
+	raw json.RawMessage // The result as posted to the webhook, stored with its alert
+}
+
+// FetchLatest returns the buffered state changes with an ID greater than lastID. Changes
+// up to lastID have been processed and are dropped; later ones stay buffered until then,
+// so a batch that fails to save is returned again.
+func (r *Receiver) FetchLatest(ctx context.Context, lastID uint64) ([]domain.Alert, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Keep new IDs above the last processed one after a restart
+	if lastID > r.lastID {
+		r.lastID = lastID
+	}
+
+	remaining := r.pending[:0]
+	for _, alert := range r.pending {
+		if alert.ExternalID > lastID {
+			remaining = append(remaining, alert)
+		}
+	}
+	r.pending = remaining
+
+	alerts := make([]domain.Alert, len(r.pending))
+	copy(alerts, r.pending)
+	return alerts, nil
+}
+
+// ServeHTTP accepts one check result or a JSON array of results
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !r.authorized(req) {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	results, err := decodeResults(http.MaxBytesReader(w, req.Body, maxResultBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	accepted := 0
+	for _, result := range results {
+		ok, err := r.Receive(result, time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if ok {
+			accepted++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintf(w, `{"received":%d,"state_changes":%d}`+"\n", len(results), accepted)
+}
+
+func (r *Receiver) authorized(req *http.Request) bool {
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = req.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(r.token)) == 1
+}
+
+func decodeResults(body io.Reader) ([]CheckResult, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if trimmed := strings.TrimSpace(string(raw)); strings.HasPrefix(trimmed, "[") {
+		var entries []json.RawMessage
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("invalid check results: %w", err)
+		}
+		results := make([]CheckResult, 0, len(entries))
+		for _, entry := range entries {
+			var result CheckResult
+			if err := json.Unmarshal(entry, &result); err != nil {
+				return nil, fmt.Errorf("invalid check results: %w", err)
+			}
+			result.raw = entry
+			results = append(results, result)
+		}
+		return results, nil
+	}
+
+	var result CheckResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("invalid check result: %w", err)
+	}
+	result.raw = raw
+	return []CheckResult{result}, nil
+}
+
+// Receive records a check result received at now. It returns whether the result changed
+// the check's state and was queued as an alert.
+func (r *Receiver) Receive(result CheckResult, now time.Time) (bool, error) {
+	host := firstNonEmpty(result.HostName, result.Host, r.hostname)
+	service := firstNonEmpty(result.ServiceDescription, result.Service)
+	status, state, err := resultStatus(result, service == "")
+	if err != nil {
+		return false, err
+	}
+
+	occurredAt := now.UTC()
+	if result.Timestamp > 0 {
+		occurredAt = time.Unix(result.Timestamp, 0).UTC()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := host + "/" + service
+	oldStatus, known := r.states[key]
+	r.states[key] = status
+	if !known {
+		oldStatus = domain.StatusClear
+	}
+	if oldStatus == status {
+		return false, nil
+	}
+
+	// IDs are microsecond timestamps so they keep increasing across restarts
+	id := uint64(now.UnixMicro())
+	if id <= r.lastID {
+		id = r.lastID + 1
+	}
+	r.lastID = id
+
+	name := service
+	chart := "nagios.service"
+	if service == "" {
+		name = "host_check"
+		chart = "nagios.host"
+	}
+	output := firstNonEmpty(result.PluginOutput, result.Output)
+
+	labels := map[string]string{"source": "nagios", "state": state}
+	if result.PerformanceData != "" {
+		labels["perfdata"] = result.PerformanceData
+	}
+	rawPayload := []byte(result.raw)
+	if rawPayload == nil {
+		rawPayload, _ = json.Marshal(result)
+	}
+
+	r.pending = append(r.pending, domain.Alert{
+		ID:           idgen.Derive(occurredAt, fmt.Sprintf("nagios-%s-%d", key, id)),
+		ExternalID:   id,
+		Host:         host,
+		Chart:        chart,
+		Family:       strings.ToLower(service),
+		Name:         name,
+		Status:       status,
+		OldStatus:    oldStatus,
+		Value:        firstPerfValue(result.PerformanceData),
+		OccurredAt:   occurredAt,
+		Description:  output,
+		ResourceType: classifyResourceType(service, output),
+		Labels:       labels,
+		RawPayload:   string(rawPayload),
+	})
+	if len(r.pending) > r.capacity {
+		r.pending = r.pending[len(r.pending)-r.capacity:]
+	}
+	return true, nil
+}
+
+// resultStatus maps a return code or state name to an alert status. Hosts report
+// UP/DOWN/UNREACHABLE, services OK/WARNING/CRITICAL/UNKNOWN.
+func resultStatus(result CheckResult, hostCheck bool) (domain.AlertStatus, string, error) {
+	code := result.ReturnCode
+	if code == nil {
+		code = result.ExitStatus
+	}
+
+	state := strings.ToUpper(result.State)
+	if state == "" && code != nil {
+		names := []string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+		if hostCheck {
+			names = []string{"UP", "DOWN", "UNREACHABLE", "UNREACHABLE"}
+		}
+		if *code < 0 || *code >= len(names) {
+			return "", "", fmt.Errorf("invalid return code %d", *code)
+		}
+		state = names[*code]
+	}
+
+	switch state {
+	case "OK", "UP":
+		return domain.StatusClear, state, nil
+	case "WARNING", "UNKNOWN":
+		return domain.StatusWarning, state, nil
+	case "CRITICAL", "DOWN", "UNREACHABLE":
+		return domain.StatusCritical, state, nil
+	case "":
+		return "", "", fmt.Errorf("check result needs a return_code, exit_status or state")
+	default:
+		return "", "", fmt.Errorf("unknown state %q", result.State)
+	}
+}
+
+// classifyResourceType determines the resource type from the service name and output,
+// e.g. "check_disk" or "Memory usage"
+func classifyResourceType(service, output string) domain.ResourceType {
+	if service == "" {
+		return domain.ResourceNetwork // Host checks are reachability (ping) checks
+	}
+
+	text := strings.ToLower(service + " " + output)
+	switch {
+	case strings.Contains(text, "cpu") || strings.Contains(text, "load"):
+		return domain.ResourceCPU
+	case strings.Contains(text, "mem") || strings.Contains(text, "swap"):
+		return domain.ResourceMemory
+	case strings.Contains(text, "disk") || strings.Contains(text, "filesystem") || strings.Contains(text, "inode"):
+		return domain.ResourceDisk
+	case strings.Contains(text, "ping") || strings.Contains(text, "network") || strings.Contains(text, "interface"):
+		return domain.ResourceNetwork
+	case strings.Contains(text, "proc") || strings.Contains(text, "http") || strings.Contains(text, "service"):
+		return domain.ResourceProcess
+	}
+	return domain.ResourceUnknown
+}
+
+// firstPerfValue returns the value of the first performance data metric, e.g. 91 for
+// "used=91%;80;90"
+func firstPerfValue(perfData string) float64 {
+	fields := strings.Fields(perfData)
+	if len(fields) == 0 {
+		return 0
+	}
+	_, metric, ok := strings.Cut(fields[0], "=")
+	if !ok {
+		return 0
+	}
+	value, _, _ := strings.Cut(metric, ";")
+	value = strings.TrimRightFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	parsed, _ := strconv.ParseFloat(value, 64)
+	return parsed
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
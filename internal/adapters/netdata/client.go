@@ -3,13 +3,19 @@ package netdata
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
+	"sort"
 	"time"
 
 	"incident-teller/internal/domain"
+	"incident-teller/internal/faults"
+	"incident-teller/internal/idgen"
 )
 
 // Client implements the AlertSource interface for Netdata
@@ -17,6 +23,9 @@ type Client struct {
 	baseURL    string
 	httpClient *http.Client
 	hostname   string // Default hostname if not in response
+	retries    int
+	retryDelay time.Duration
+	faults     *faults.Injector
 }
 
 // NewClient creates a new Netdata API client
@@ -30,8 +39,125 @@ func NewClient(baseURL, hostname string) *Client {
 	}
 }
 
+// SetRetry retries failed alarm log fetches up to retries times, waiting an exponentially
+// growing, jittered delay starting at delay. Only network errors, 5xx and 429 responses
+// are retried.
+func (c *Client) SetRetry(retries int, delay time.Duration) {
+	c.retries = retries
+	c.retryDelay = delay
+}
+
+// SetTimeout limits each request to the Netdata API
+func (c *Client) SetTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// SetFaults makes alarm log fetches fail as timed out while Netdata timeouts are injected
+func (c *Client) SetFaults(injector *faults.Injector) {
+	c.faults = injector
+}
+
 // FetchLatest retrieves alarm logs from Netdata API since the given unique ID
 func (c *Client) FetchLatest(ctx context.Context, lastID uint64) ([]domain.Alert, error) {
+	return c.fetchWithRetry(ctx, lastID)
+}
+
+// fetchWithRetry fetches the alarm log, retrying temporary failures with backoff
+func (c *Client) fetchWithRetry(ctx context.Context, lastID uint64) ([]domain.Alert, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff(c.retryDelay, attempt)):
+			}
+		}
+
+		alerts, err := c.fetchAlarmLog(ctx, lastID, nil)
+		if err == nil {
+			return alerts, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil || !temporary(err) {
+			break
+		}
+	}
+	if c.retries > 0 && temporary(lastErr) {
+		return nil, fmt.Errorf("%w (after %d retries)", lastErr, c.retries)
+	}
+	return nil, lastErr
+}
+
+// backoff returns the delay before a retry: delay doubled per attempt, of which a random
+// half is waited so that clients don't retry in lockstep
+func backoff(delay time.Duration, attempt int) time.Duration {
+	d := delay << (attempt - 1)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// statusError is an unexpected HTTP status from the Netdata API
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.code, e.body)
+}
+
+// temporary reports whether a fetch error may go away on retry
+func temporary(err error) bool {
+	var status *statusError
+	if errors.As(err, &status) {
+		return status.code >= 500 || status.code == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// FetchHistory retrieves the alarm log entries that occurred since the given time, oldest
+// first. The alarm_log endpoint only pages by unique ID, so the full log the agent keeps
+// is fetched and cut to the period here.
+func (c *Client) FetchHistory(ctx context.Context, since time.Time) ([]domain.Alert, error) {
+	alerts, err := c.fetchWithRetry(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]domain.Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		if !alert.OccurredAt.Before(since) {
+			history = append(history, alert)
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		if !history[i].OccurredAt.Equal(history[j].OccurredAt) {
+			return history[i].OccurredAt.Before(history[j].OccurredAt)
+		}
+		return history[i].ExternalID < history[j].ExternalID
+	})
+	return history, nil
+}
+
+// cacheValidators holds HTTP validators for conditional alarm log requests
+type cacheValidators struct {
+	etag         string
+	lastModified string
+}
+
+// fetchAlarmLog retrieves alarm logs, sending conditional headers when validators are given.
+// A 304 Not Modified response yields no alerts and no error.
+func (c *Client) fetchAlarmLog(ctx context.Context, lastID uint64, validators *cacheValidators) ([]domain.Alert, error) {
+	if err := c.faults.NetdataError(); err != nil {
+		return nil, fmt.Errorf("failed to fetch alarms: %w", err)
+	}
+
 	// Build URL with query parameters
 	apiURL, err := url.Parse(c.baseURL + "/api/v1/alarm_log")
 	if err != nil {
@@ -50,6 +176,15 @@ func (c *Client) FetchLatest(ctx context.Context, lastID uint64) ([]domain.Alert
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	if validators != nil {
+		if validators.etag != "" {
+			req.Header.Set("If-None-Match", validators.etag)
+		}
+		if validators.lastModified != "" {
+			req.Header.Set("If-Modified-Since", validators.lastModified)
+		}
+	}
+
 	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -57,9 +192,17 @@ func (c *Client) FetchLatest(ctx context.Context, lastID uint64) ([]domain.Alert
 	}
 	defer resp.Body.Close()
 
+	if validators != nil {
+		if resp.StatusCode == http.StatusNotModified {
+			return nil, nil
+		}
+		validators.etag = resp.Header.Get("ETag")
+		validators.lastModified = resp.Header.Get("Last-Modified")
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		return nil, &statusError{code: resp.StatusCode, body: string(body)}
 	}
 
 	// Read response body
@@ -68,21 +211,29 @@ func (c *Client) FetchLatest(ctx context.Context, lastID uint64) ([]domain.Alert
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	// Try to parse as array first (common format)
-	var logs []domain.NetdataAlarmLog
-	if err := json.Unmarshal(body, &logs); err != nil {
+	// Try to parse as array first (common format). The entries are kept as sent, to be
+	// stored with the alerts.
+	var entries []json.RawMessage
+	if err := json.Unmarshal(body, &entries); err != nil {
 		// If that fails, try wrapped response
-		var wrappedResp domain.NetdataAlarmLogResponse
+		var wrappedResp struct {
+			Alarms []json.RawMessage `json:"alarms"`
+		}
 		if err := json.Unmarshal(body, &wrappedResp); err != nil {
 			return nil, fmt.Errorf("failed to parse response: %w", err)
 		}
-		logs = wrappedResp.Alarms
+		entries = wrappedResp.Alarms
 	}
 
 	// Normalize to domain alerts
-	alerts := make([]domain.Alert, 0, len(logs))
-	for _, log := range logs {
+	alerts := make([]domain.Alert, 0, len(entries))
+	for _, entry := range entries {
+		var log domain.NetdataAlarmLog
+		if err := json.Unmarshal(entry, &log); err != nil {
+			return nil, fmt.Errorf("failed to parse alarm log entry: %w", err)
+		}
 		alert := c.normalizeAlert(log)
+		alert.RawPayload = string(entry)
 		alerts = append(alerts, alert)
 	}
 
@@ -98,7 +249,7 @@ func (c *Client) normalizeAlert(log domain.NetdataAlarmLog) domain.Alert {
 	}
 
 	// Parse timestamp
-	occurredAt := time.Unix(int64(log.When), 0)
+	occurredAt := time.Unix(int64(log.When), 0).UTC()
 
 	// Map status strings
 	status := mapStatus(log.Status)
@@ -107,8 +258,23 @@ func (c *Client) normalizeAlert(log domain.NetdataAlarmLog) domain.Alert {
 	// Classify resource type
 	resourceType := classifyResourceType(log.Chart, log.Family)
 
-	// Generate unique ID
-	alertID := fmt.Sprintf("%s-%d", hostname, log.UniqueID)
+	// Generate a stable, time-ordered ID; the Netdata unique_id stays in ExternalID
+	alertID := idgen.Derive(occurredAt, fmt.Sprintf("%s-%d", hostname, log.UniqueID))
+
+	labels := map[string]string{
+		"source":    log.Source,
+		"units":     log.Units,
+		"exec":      log.Exec,
+		"recipient": log.Recipient,
+		"alarm_id":  fmt.Sprintf("%d", log.AlarmID),
+		"event_id":  fmt.Sprintf("%d", log.EventID),
+	}
+	// Chart labels carry the container identity of cgroup charts
+	for k, v := range log.ChartLabels {
+		if _, exists := labels[k]; !exists {
+			labels[k] = v
+		}
+	}
 
 	return domain.Alert{
 		ID:           alertID,
@@ -123,14 +289,7 @@ func (c *Client) normalizeAlert(log domain.NetdataAlarmLog) domain.Alert {
 		OccurredAt:   occurredAt,
 		Description:  log.Info,
 		ResourceType: resourceType,
-		Labels: map[string]string{
-			"source":    log.Source,
-			"units":     log.Units,
-			"exec":      log.Exec,
-			"recipient": log.Recipient,
-			"alarm_id":  fmt.Sprintf("%d", log.AlarmID),
-			"event_id":  fmt.Sprintf("%d", log.EventID),
-		},
+		Labels:       labels,
 	}
 }
 
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
 )
 
 // CloudClient implements Netdata Cloud API
@@ -61,7 +62,7 @@ type CloudGraphQLResponse struct {
 		Space struct {
 			Alarms struct {
 				Edges []struct {
-					Node CloudAlarm `json:"node"`
+					Node json.RawMessage `json:"node"` // A CloudAlarm, kept as sent
 				} `json:"edges"`
 				pageInfo struct {
 					EndCursor   string `json:"endCursor"`
@@ -156,7 +157,13 @@ func (c *CloudClient) FetchLatest(ctx context.Context, lastID uint64) ([]domain.
 	// Convert to domain alerts
 	alerts := make([]domain.Alert, 0, len(cloudResp.Data.Space.Alarms.Edges))
 	for _, edge := range cloudResp.Data.Space.Alarms.Edges {
-		alerts = append(alerts, c.normalizeCloudAlarm(edge.Node))
+		var alarm CloudAlarm
+		if err := json.Unmarshal(edge.Node, &alarm); err != nil {
+			return nil, fmt.Errorf("failed to decode alarm: %w", err)
+		}
+		alert := c.normalizeCloudAlarm(alarm)
+		alert.RawPayload = string(edge.Node)
+		alerts = append(alerts, alert)
 	}
 
 	return alerts, nil
@@ -169,7 +176,7 @@ func (c *CloudClient) normalizeCloudAlarm(alarm CloudAlarm) domain.Alert {
 	resourceType := classifyResourceType(alarm.Chart, alarm.Component)
 
 	return domain.Alert{
-		ID:           alarm.ID,
+		ID:           idgen.Derive(time.Unix(alarm.Timestamp, 0), alarm.ID),
 		ExternalID:   uint64(alarm.Timestamp), // Use timestamp as unique ID
 		Host:         alarm.Node,
 		Chart:        alarm.Chart,
@@ -178,7 +185,7 @@ func (c *CloudClient) normalizeCloudAlarm(alarm CloudAlarm) domain.Alert {
 		Status:       status,
 		OldStatus:    oldStatus,
 		Value:        alarm.Value,
-		OccurredAt:   time.Unix(alarm.Timestamp, 0),
+		OccurredAt:   time.Unix(alarm.Timestamp, 0).UTC(),
 		Description:  alarm.Info,
 		ResourceType: resourceType,
 		Labels: map[string]string{
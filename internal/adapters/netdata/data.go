@@ -0,0 +1,92 @@
+package netdata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// chartData is Netdata's /api/v1/data response in the json format: one row per point,
+// the timestamp followed by a value per dimension
+type chartData struct {
+	Labels []string     `json:"labels"`
+	Data   [][]*float64 `json:"data"`
+}
+
+// FetchSamples retrieves the values of an alert's chart over the window ending when it
+// occurred from the agent's /api/v1/data endpoint. A point's value is the sum of the
+// chart's dimensions, averaged over the point. Alerts of hosts streaming into this
+// parent are sampled through the parent's /host/<hostname> API.
+func (c *Client) FetchSamples(ctx context.Context, alert domain.Alert, window time.Duration, points int) ([]domain.MetricSample, error) {
+	if alert.Chart == "" || window <= 0 || points <= 0 {
+		return nil, nil
+	}
+
+	base := c.baseURL
+	if alert.Host != "" && alert.Host != c.hostname {
+		base += "/host/" + url.PathEscape(alert.Host)
+	}
+	apiURL, err := url.Parse(base + "/api/v1/data")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base URL: %w", err)
+	}
+
+	query := apiURL.Query()
+	query.Set("chart", alert.Chart)
+	query.Set("after", strconv.FormatInt(alert.OccurredAt.Add(-window).Unix(), 10))
+	query.Set("before", strconv.FormatInt(alert.OccurredAt.Unix(), 10))
+	query.Set("points", strconv.Itoa(points))
+	query.Set("group", "average")
+	query.Set("format", "json")
+	query.Set("options", "seconds|flip")
+	apiURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chart data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var data chartData
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse chart data: %w", err)
+	}
+
+	samples := make([]domain.MetricSample, 0, len(data.Data))
+	for _, row := range data.Data {
+		if len(row) < 2 || row[0] == nil {
+			continue
+		}
+		var sum float64
+		var found bool
+		for _, value := range row[1:] {
+			if value != nil {
+				sum += *value
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
+		samples = append(samples, domain.MetricSample{At: time.Unix(int64(*row[0]), 0).UTC(), Value: sum})
+	}
+	if len(samples) > points {
+		samples = samples[len(samples)-points:]
+	}
+	return samples, nil
+}
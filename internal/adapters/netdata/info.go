@@ -0,0 +1,69 @@
+package netdata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"incident-teller/internal/domain"
+)
+
+// agentInfo is the subset of Netdata's /api/v1/info response used for host inventory
+type agentInfo struct {
+	Version       string            `json:"version"`
+	MirroredHosts []string          `json:"mirrored_hosts"`
+	OSName        string            `json:"os_name"`
+	OSVersion     string            `json:"os_version"`
+	KernelName    string            `json:"kernel_name"`
+	KernelVersion string            `json:"kernel_version"`
+	Architecture  string            `json:"architecture"`
+	CoresTotal    string            `json:"cores_total"`
+	HostLabels    map[string]string `json:"host_labels"`
+}
+
+// FetchHostInfo retrieves node metadata from the Netdata agent's /api/v1/info endpoint
+func (c *Client) FetchHostInfo(ctx context.Context) ([]domain.HostInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/info", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch agent info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var info agentInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to parse agent info: %w", err)
+	}
+
+	var cores int
+	fmt.Sscanf(info.CoresTotal, "%d", &cores)
+
+	hosts := []domain.HostInfo{{
+		Hostname:      c.hostname,
+		OS:            info.OSName + " " + info.OSVersion,
+		Kernel:        info.KernelName + " " + info.KernelVersion,
+		Architecture:  info.Architecture,
+		Cores:         cores,
+		AgentVersion:  info.Version,
+		Labels:        info.HostLabels,
+		MirroredHosts: info.MirroredHosts,
+	}}
+
+	// Children streaming into this parent are known by name only
+	for _, mirrored := range info.MirroredHosts {
+		if mirrored != c.hostname {
+			hosts = append(hosts, domain.HostInfo{Hostname: mirrored, AgentVersion: info.Version})
+		}
+	}
+
+	return hosts, nil
+}
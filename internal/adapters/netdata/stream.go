@@ -0,0 +1,77 @@
+package netdata
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// StreamClient pushes Netdata alerts into the pipeline as they happen.
+//
+// The Netdata v1 agent API has no push channel for the alarm log, so the stream is built
+// from aggressive short-polling with conditional requests (If-None-Match/If-Modified-Since):
+// unchanged logs cost a 304 with no body, keeping sub-second intervals cheap.
+type StreamClient struct {
+	client   *Client
+	interval time.Duration
+}
+
+// NewStreamClient creates a streaming alert source on top of a Netdata client
+func NewStreamClient(client *Client, interval time.Duration) *StreamClient {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &StreamClient{
+		client:   client,
+		interval: interval,
+	}
+}
+
+// FetchLatest retrieves alarm logs since the given unique ID (one-shot, for AlertSource compatibility)
+func (s *StreamClient) FetchLatest(ctx context.Context, lastID uint64) ([]domain.Alert, error) {
+	return s.client.FetchLatest(ctx, lastID)
+}
+
+// FetchHistory retrieves the alarm log entries since the given time, oldest first
+func (s *StreamClient) FetchHistory(ctx context.Context, since time.Time) ([]domain.Alert, error) {
+	return s.client.FetchHistory(ctx, since)
+}
+
+// Stream emits batches of new alerts on out until ctx is cancelled
+func (s *StreamClient) Stream(ctx context.Context, lastID uint64, out chan<- []domain.Alert) error {
+	validators := &cacheValidators{}
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		alerts, err := s.client.fetchAlarmLog(ctx, lastID, validators)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Printf("⚠️  Netdata stream fetch failed: %v", err)
+		}
+
+		if len(alerts) > 0 {
+			for _, alert := range alerts {
+				if alert.ExternalID > lastID {
+					lastID = alert.ExternalID
+				}
+			}
+
+			select {
+			case out <- alerts:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
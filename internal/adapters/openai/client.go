@@ -2,19 +2,27 @@ package openai
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	openai "github.com/sashabaranov/go-openai"
 	"incident-teller/internal/config"
 	"incident-teller/internal/domain"
+	"incident-teller/internal/redact"
 )
 
 // Client provides OpenAI API integration
 type Client struct {
 	apiClient *openai.Client
 	config    config.OpenAIConfig
+	redactor  *redact.Redactor
+
+	// jsonModeUnsupported is set once the model rejects JSON mode, so later analyses go
+	// straight to one call per section
+	jsonModeUnsupported atomic.Bool
 }
 
 // NewClient creates a new OpenAI client
@@ -35,14 +43,34 @@ func NewClient(cfg config.OpenAIConfig) (*Client, error) {
 	}, nil
 }
 
-// AnalyzeIncident generates a summary and insights about an incident
+// SetRedactor masks hostnames, IP addresses and sensitive labels in the alert context sent
+// to OpenAI; the tokens in its answers are replaced with the real values again
+func (c *Client) SetRedactor(redactor *redact.Redactor) {
+	c.redactor = redactor
+}
+
+// AnalyzeIncident generates a summary and insights about an incident. In the combined mode
+// all sections come from a single JSON mode call; if the model doesn't support JSON mode or
+// the answer can't be parsed, they are generated one call at a time.
 func (c *Client) AnalyzeIncident(ctx context.Context, alerts []domain.Alert) (IncidentAnalysis, error) {
 	if len(alerts) == 0 {
 		return IncidentAnalysis{}, fmt.Errorf("no alerts to analyze")
 	}
 
 	// Prepare context from alerts
-	context := c.prepareIncidentContext(alerts)
+	context := c.prepareIncidentContext(c.redactor.Alerts(alerts))
+
+	if c.config.Mode != "multi" && !c.jsonModeUnsupported.Load() {
+		combined, err := c.generateCombinedAnalysis(ctx, context)
+		switch {
+		case err == nil:
+			return c.analysis(alerts, combined.Summary, combined.RootCause, combined.recommendations(), combined.Impact), nil
+		case errors.Is(err, errJSONModeUnsupported):
+			c.jsonModeUnsupported.Store(true)
+		case !errors.Is(err, errInvalidAnalysis):
+			return IncidentAnalysis{}, fmt.Errorf("failed to generate analysis: %w", err)
+		}
+	}
 
 	// Generate summary
 	summary, err := c.generateIncidentSummary(ctx, context)
@@ -68,15 +96,20 @@ func (c *Client) AnalyzeIncident(ctx context.Context, alerts []domain.Alert) (In
 		return IncidentAnalysis{}, fmt.Errorf("failed to generate impact assessment: %w", err)
 	}
 
+	return c.analysis(alerts, summary, rootCause, recommendations, impact), nil
+}
+
+// analysis assembles the analysis of the alerts, restoring redacted values in the sections
+func (c *Client) analysis(alerts []domain.Alert, summary, rootCause string, recommendations Recommendations, impact string) IncidentAnalysis {
 	return IncidentAnalysis{
-		Summary:         summary,
-		RootCause:       rootCause,
-		Recommendations: recommendations,
-		Impact:          impact,
+		Summary:         c.redactor.Restore(strings.TrimSpace(summary)),
+		RootCause:       c.redactor.Restore(strings.TrimSpace(rootCause)),
+		Recommendations: c.restoreRecommendations(recommendations),
+		Impact:          c.redactor.Restore(strings.TrimSpace(impact)),
 		GeneratedAt:     time.Now(),
 		AlertCount:      len(alerts),
 		TimeSpan:        c.calculateTimeSpan(alerts),
-	}, nil
+	}
 }
 
 // generateIncidentSummary creates a concise summary of the incident
@@ -172,6 +205,11 @@ Impact Assessment:`, context)
 
 // callOpenAI makes a request to the OpenAI API
 func (c *Client) callOpenAI(ctx context.Context, prompt string, system string) (string, error) {
+	return c.complete(ctx, prompt, system, false)
+}
+
+// complete makes a chat completion request, asking for a JSON object if jsonMode is set
+func (c *Client) complete(ctx context.Context, prompt string, system string, jsonMode bool) (string, error) {
 	// Create a timeout context if one doesn't exist
 	if _, ok := ctx.Deadline(); !ok {
 		var cancel context.CancelFunc
@@ -195,9 +233,15 @@ func (c *Client) callOpenAI(ctx context.Context, prompt string, system string) (
 		Temperature: float32(c.config.Temperature),
 		TopP:        float32(c.config.TopP),
 	}
+	if jsonMode {
+		req.ResponseFormat = &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject}
+	}
 
 	resp, err := c.apiClient.CreateChatCompletion(ctx, req)
 	if err != nil {
+		if jsonMode && isJSONModeRejected(err) {
+			return "", fmt.Errorf("%w: %v", errJSONModeUnsupported, err)
+		}
 		return "", fmt.Errorf("OpenAI API error: %w", err)
 	}
 
@@ -208,6 +252,14 @@ func (c *Client) callOpenAI(ctx context.Context, prompt string, system string) (
 	return resp.Choices[0].Message.Content, nil
 }
 
+// Ping checks that the API key is accepted by listing the available models
+func (c *Client) Ping(ctx context.Context) error {
+	if _, err := c.apiClient.ListModels(ctx); err != nil {
+		return fmt.Errorf("OpenAI API error: %w", err)
+	}
+	return nil
+}
+
 // prepareIncidentContext formats alerts into a readable context
 func (c *Client) prepareIncidentContext(alerts []domain.Alert) string {
 	var sb strings.Builder
@@ -217,7 +269,7 @@ func (c *Client) prepareIncidentContext(alerts []domain.Alert) string {
 		sb.WriteString(fmt.Sprintf(
 			"%d. [%s] %s on %s - Status: %s -> %s (Value: %.2f)\n",
 			i+1,
-			alert.OccurredAt.Format("15:04:05"),
+			alert.OccurredAt.UTC().Format("15:04:05 -07:00"),
 			alert.Name,
 			alert.Host,
 			alert.OldStatus,
@@ -227,6 +279,14 @@ func (c *Client) prepareIncidentContext(alerts []domain.Alert) string {
 		if alert.Description != "" {
 			sb.WriteString(fmt.Sprintf("   Description: %s\n", alert.Description))
 		}
+		if len(alert.Samples) > 1 {
+			values := make([]string, len(alert.Samples))
+			for j, sample := range alert.Samples {
+				values[j] = fmt.Sprintf("%.2f", sample.Value)
+			}
+			span := alert.Samples[len(alert.Samples)-1].At.Sub(alert.Samples[0].At)
+			sb.WriteString(fmt.Sprintf("   Chart values over the %s before it, oldest first: %s\n", span, strings.Join(values, ", ")))
+		}
 	}
 
 	sb.WriteString("\nAffected Resources:\n")
@@ -241,6 +301,22 @@ func (c *Client) prepareIncidentContext(alerts []domain.Alert) string {
 	return sb.String()
 }
 
+// restoreRecommendations replaces redaction tokens in the recommendations with the real values
+func (c *Client) restoreRecommendations(r Recommendations) Recommendations {
+	restore := func(actions []string) []string {
+		restored := make([]string, len(actions))
+		for i, action := range actions {
+			restored[i] = c.redactor.Restore(action)
+		}
+		return restored
+	}
+	return Recommendations{
+		Immediate: restore(r.Immediate),
+		ShortTerm: restore(r.ShortTerm),
+		LongTerm:  restore(r.LongTerm),
+	}
+}
+
 // parseRecommendations parses the recommendations response
 func (c *Client) parseRecommendations(response string) Recommendations {
 	lines := strings.Split(response, "\n")
@@ -0,0 +1,91 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+var (
+	// errJSONModeUnsupported is returned when the model rejects JSON mode requests
+	errJSONModeUnsupported = errors.New("model does not support JSON mode")
+	// errInvalidAnalysis is returned when the combined answer isn't the expected JSON object
+	errInvalidAnalysis = errors.New("invalid combined analysis")
+)
+
+// combinedAnalysis is the JSON object the combined prompt asks for
+type combinedAnalysis struct {
+	Summary         string `json:"summary"`
+	RootCause       string `json:"root_cause"`
+	Recommendations struct {
+		Immediate []string `json:"immediate"`
+		ShortTerm []string `json:"short_term"`
+		LongTerm  []string `json:"long_term"`
+	} `json:"recommendations"`
+	Impact string `json:"impact"`
+}
+
+// generateCombinedAnalysis asks for the summary, root cause, recommendations and impact in
+// a single JSON mode call
+func (c *Client) generateCombinedAnalysis(ctx context.Context, context string) (combinedAnalysis, error) {
+	prompt := fmt.Sprintf(`You are an expert SRE analyzing a system incident. Based on the following incident data, analyze what happened.
+
+Incident Data:
+%s
+
+Respond with a JSON object with these fields:
+- "summary": a concise summary (2-3 sentences) of what happened
+- "root_cause": the most likely root cause with the primary cause, contributing factors and why it occurred. Consider cascading failures, resource exhaustion, and system interactions.
+- "recommendations": an object with actionable recommendations as arrays of strings: "immediate" (within 5 minutes), "short_term" (within 8 hours) and "long_term" (prevention, ongoing)
+- "impact": a brief assessment (1-2 sentences) of the business impact, considering services affected, severity (low/medium/high/critical), user impact and data integrity concerns`, context)
+
+	response, err := c.complete(ctx, prompt, "Analyze the incident and respond in JSON.", true)
+	if err != nil {
+		return combinedAnalysis{}, err
+	}
+
+	var analysis combinedAnalysis
+	if err := json.Unmarshal([]byte(response), &analysis); err != nil {
+		return combinedAnalysis{}, fmt.Errorf("%w: %v", errInvalidAnalysis, err)
+	}
+	if analysis.Summary == "" || analysis.RootCause == "" {
+		return combinedAnalysis{}, fmt.Errorf("%w: missing the summary or root cause", errInvalidAnalysis)
+	}
+	return analysis, nil
+}
+
+// recommendations converts the recommendations of the combined analysis, dropping empty actions
+func (a combinedAnalysis) recommendations() Recommendations {
+	clean := func(actions []string) []string {
+		cleaned := []string{}
+		for _, action := range actions {
+			if action = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(action), "-")); action != "" {
+				cleaned = append(cleaned, action)
+			}
+		}
+		return cleaned
+	}
+	return Recommendations{
+		Immediate: clean(a.Recommendations.Immediate),
+		ShortTerm: clean(a.Recommendations.ShortTerm),
+		LongTerm:  clean(a.Recommendations.LongTerm),
+	}
+}
+
+// isJSONModeRejected reports whether the API refused a request because of its response format
+func isJSONModeRejected(err error) bool {
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusBadRequest {
+		return false
+	}
+	param := ""
+	if apiErr.Param != nil {
+		param = *apiErr.Param
+	}
+	return param == "response_format" || strings.Contains(apiErr.Message, "response_format")
+}
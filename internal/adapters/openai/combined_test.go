@@ -0,0 +1,138 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+)
+
+// fakeChat serves chat completions, answering JSON mode requests with jsonAnswer or, if it
+// is empty, rejecting them like a model without JSON mode
+type fakeChat struct {
+	jsonAnswer string
+
+	mu        sync.Mutex
+	calls     int
+	jsonCalls int
+}
+
+func (f *fakeChat) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req openai.ChatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.calls++
+	jsonMode := req.ResponseFormat != nil && req.ResponseFormat.Type == openai.ChatCompletionResponseFormatTypeJSONObject
+	if jsonMode {
+		f.jsonCalls++
+	}
+	f.mu.Unlock()
+
+	answer := "IMMEDIATE (within 5 minutes):\n- Restart web-01\n"
+	if jsonMode {
+		if f.jsonAnswer == "" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error": {"message": "Invalid parameter: 'response_format' of type 'json_object' is not supported with this model.", "type": "invalid_request_error", "param": "response_format"}}`)
+			return
+		}
+		answer = f.jsonAnswer
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Role: "assistant", Content: answer}}},
+	})
+}
+
+func newTestClient(t *testing.T, handler http.Handler, mode string) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	apiConfig := openai.DefaultConfig("test")
+	apiConfig.BaseURL = server.URL + "/v1"
+	return &Client{
+		apiClient: openai.NewClientWithConfig(apiConfig),
+		config:    config.OpenAIConfig{Enabled: true, Model: "gpt-4", Timeout: 5 * time.Second, MaxTokens: 512, Mode: mode},
+	}
+}
+
+var testAlerts = []domain.Alert{
+	{ID: "a1", Host: "web-01", Name: "cpu_usage", Status: domain.StatusCritical, OccurredAt: time.Now()},
+}
+
+func TestAnalyzeIncident_Combined(t *testing.T) {
+	chat := &fakeChat{jsonAnswer: `{"summary": "CPU saturated on web-01.", "root_cause": "A runaway batch job.",
+		"recommendations": {"immediate": ["- Kill the job", ""], "short_term": ["Add a CPU limit"]}, "impact": "Slow checkout."}`}
+	client := newTestClient(t, chat, "combined")
+
+	analysis, err := client.AnalyzeIncident(context.Background(), testAlerts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chat.calls != 1 {
+		t.Errorf("expected a single call, got %d", chat.calls)
+	}
+	if analysis.Summary != "CPU saturated on web-01." || analysis.Impact != "Slow checkout." {
+		t.Errorf("unexpected analysis %+v", analysis)
+	}
+	if len(analysis.Recommendations.Immediate) != 1 || analysis.Recommendations.Immediate[0] != "Kill the job" ||
+		len(analysis.Recommendations.ShortTerm) != 1 || analysis.Recommendations.LongTerm == nil {
+		t.Errorf("unexpected recommendations %+v", analysis.Recommendations)
+	}
+}
+
+func TestAnalyzeIncident_FallsBackWithoutJSONMode(t *testing.T) {
+	chat := &fakeChat{}
+	client := newTestClient(t, chat, "combined")
+
+	for i := 0; i < 2; i++ {
+		analysis, err := client.AnalyzeIncident(context.Background(), testAlerts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(analysis.Recommendations.Immediate) != 1 {
+			t.Errorf("unexpected recommendations %+v", analysis.Recommendations)
+		}
+	}
+	// One rejected JSON mode call, then four calls per analysis
+	if chat.jsonCalls != 1 || chat.calls != 9 {
+		t.Errorf("expected JSON mode to be tried once and 9 calls, got %d and %d", chat.jsonCalls, chat.calls)
+	}
+}
+
+func TestAnalyzeIncident_FallsBackOnInvalidJSON(t *testing.T) {
+	chat := &fakeChat{jsonAnswer: `{"summary": ""}`}
+	client := newTestClient(t, chat, "combined")
+
+	if _, err := client.AnalyzeIncident(context.Background(), testAlerts); err != nil {
+		t.Fatal(err)
+	}
+	if chat.calls != 5 {
+		t.Errorf("expected the combined call and four section calls, got %d", chat.calls)
+	}
+}
+
+func TestAnalyzeIncident_Multi(t *testing.T) {
+	chat := &fakeChat{jsonAnswer: `{}`}
+	client := newTestClient(t, chat, "multi")
+
+	if _, err := client.AnalyzeIncident(context.Background(), testAlerts); err != nil {
+		t.Fatal(err)
+	}
+	if chat.jsonCalls != 0 || chat.calls != 4 {
+		t.Errorf("expected four calls without JSON mode, got %d calls, %d in JSON mode", chat.calls, chat.jsonCalls)
+	}
+}
@@ -1,48 +1,185 @@
 package repository
 
 import (
+	"container/list"
 	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"incident-teller/internal/domain"
 )
 
+// maxRecords bounds the append-only audit log, dead letters and root cause predictions,
+// which keep their newest entries
+const maxRecords = 10000
+
 // InMemoryRepository provides a simple in-memory storage for testing and development
 type InMemoryRepository struct {
 	mu              sync.RWMutex
 	alerts          map[string]domain.Alert // alertID -> Alert
+	fingerprints    map[string]string       // fingerprint -> alertID
+	alertAcks       map[string]domain.AlertAck
 	incidents       []domain.Incident
 	lastProcessedID uint64
+	sourceCursors   map[string]uint64    // source -> last processed ID
+	exportCursors   map[string]time.Time // exporter -> last exported resolution time
+	patterns        []domain.PropagationPattern
+	acknowledged    map[string]time.Time // incidentID -> first acknowledgement
+	timelines       map[string][]domain.TimelineEntry
+	tickets         map[string]domain.Ticket // incidentID -> ticket
+	problemLinks    []domain.ProblemLink     // Oldest first
+	escalations     map[string][]domain.Escalation
+	rootCauses      []domain.RootCauseRecord
+	leases          map[string]domain.Lease
+	priorities      map[string]domain.Priority // incidentID -> manual priority
+	metadata        map[string]domain.IncidentMetadata
+	overrides       map[string]domain.RootCauseOverride // incidentID -> pinned root cause
+	priorityChanges map[string][]domain.PriorityChange
+	transitions     map[string][]domain.StateTransition // incidentID -> lifecycle, oldest first
+	actionItems     []domain.ActionItem                 // Oldest first
+	auditLog        []domain.AuditEntry
+	webhooks        []domain.Webhook
+	deadLetters     []domain.FailedDelivery
+	samples         map[string][]domain.MetricSample // alertID -> sampled chart values
+
+	// Bounds set by SetLimits; 0 is unbounded. The LRU lists hold the IDs of alerts and
+	// incidents, least recently saved first.
+	maxAlerts        int
+	maxIncidents     int
+	retention        time.Duration
+	alertLRU         *list.List
+	alertElems       map[string]*list.Element
+	incidentLRU      *list.List
+	incidentElems    map[string]*list.Element
+	lastSweep        time.Time
+	evictedAlerts    uint64
+	evictedIncidents uint64
 }
 
 // NewInMemoryRepository creates a new in-memory repository
 func NewInMemoryRepository() *InMemoryRepository {
 	return &InMemoryRepository{
 		alerts:          make(map[string]domain.Alert),
+		fingerprints:    make(map[string]string),
+		alertAcks:       make(map[string]domain.AlertAck),
 		incidents:       make([]domain.Incident, 0),
 		lastProcessedID: 0,
+		sourceCursors:   make(map[string]uint64),
+		exportCursors:   make(map[string]time.Time),
+		acknowledged:    make(map[string]time.Time),
+		timelines:       make(map[string][]domain.TimelineEntry),
+		tickets:         make(map[string]domain.Ticket),
+		escalations:     make(map[string][]domain.Escalation),
+		leases:          make(map[string]domain.Lease),
+		priorities:      make(map[string]domain.Priority),
+		metadata:        make(map[string]domain.IncidentMetadata),
+		overrides:       make(map[string]domain.RootCauseOverride),
+		priorityChanges: make(map[string][]domain.PriorityChange),
+		transitions:     make(map[string][]domain.StateTransition),
+		samples:         make(map[string][]domain.MetricSample),
+		alertLRU:        list.New(),
+		alertElems:      make(map[string]*list.Element),
+		incidentLRU:     list.New(),
+		incidentElems:   make(map[string]*list.Element),
 	}
 }
 
+// SetLimits bounds the repository to at most maxAlerts alerts and maxIncidents incidents,
+// evicting the least recently saved ones, resolved incidents first. With a retention,
+// alerts that occurred and incidents that resolved longer ago are evicted too. Zero
+// values are unbounded.
+func (r *InMemoryRepository) SetLimits(maxAlerts, maxIncidents int, retention time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.maxAlerts = maxAlerts
+	r.maxIncidents = maxIncidents
+	r.retention = retention
+	r.lastSweep = time.Time{}
+	r.evict(time.Now())
+}
+
 // SaveAlert stores an alert in memory
 func (r *InMemoryRepository) SaveAlert(ctx context.Context, alert domain.Alert) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.alerts[alert.ID] = alert
+	r.saveAlert(alert)
+	r.evict(time.Now())
+	return nil
+}
+
+// SaveAlerts stores a batch of alerts in memory
+func (r *InMemoryRepository) SaveAlerts(ctx context.Context, alerts []domain.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, alert := range alerts {
+		r.saveAlert(alert)
+	}
+	r.evict(time.Now())
 	return nil
 }
 
+// saveAlert replaces the stored alert with the same fingerprint, keeping its ID
+func (r *InMemoryRepository) saveAlert(alert domain.Alert) {
+	if fingerprint := alert.Fingerprint(); fingerprint != "" {
+		if id, ok := r.fingerprints[fingerprint]; ok {
+			alert.ID = id
+		}
+		r.fingerprints[fingerprint] = alert.ID
+	}
+	r.alerts[alert.ID] = alert
+	touch(r.alertLRU, r.alertElems, alert.ID)
+}
+
 // GetIncidents returns all stored incidents
 func (r *InMemoryRepository) GetIncidents(ctx context.Context) ([]domain.Incident, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	// Return copy to prevent external modification
+	return r.incidentsWithPriorities(), nil
+}
+
+// QueryIncidents returns the incidents matching the query's search text, in the requested order
+func (r *InMemoryRepository) QueryIncidents(ctx context.Context, q domain.IncidentQuery) ([]domain.Incident, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return domain.ApplyIncidentQuery(r.incidentsWithPriorities(), q, time.Now()), nil
+}
+
+// incidentsWithPriorities returns a copy of the incidents with their manual priorities,
+// metadata, pinned root causes, lifecycle states and alert samples
+func (r *InMemoryRepository) incidentsWithPriorities() []domain.Incident {
 	incidents := make([]domain.Incident, len(r.incidents))
 	copy(incidents, r.incidents)
-	return incidents, nil
+	for i := range incidents {
+		incidents[i].PriorityOverride = r.priorities[incidents[i].ID]
+		incidents[i].SetMetadata(r.metadata[incidents[i].ID])
+		if override, ok := r.overrides[incidents[i].ID]; ok {
+			incidents[i].RootCauseOverride = &override
+		}
+		if transitions := r.transitions[incidents[i].ID]; len(transitions) > 0 {
+			last := transitions[len(transitions)-1]
+			incidents[i].State = last.To
+			incidents[i].StateChangedAt = &last.ChangedAt
+		}
+		if len(r.samples) > 0 {
+			events := make([]domain.Alert, len(incidents[i].Events))
+			for j, event := range incidents[i].Events {
+				if samples, ok := r.samples[event.ID]; ok {
+					event.Samples = samples
+				}
+				events[j] = event
+			}
+			incidents[i].Events = events
+		}
+	}
+	return incidents
 }
 
 // SaveIncident stores an incident
@@ -50,6 +187,9 @@ func (r *InMemoryRepository) SaveIncident(ctx context.Context, incident domain.I
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	touch(r.incidentLRU, r.incidentElems, incident.ID)
+	defer r.evict(time.Now())
+
 	// Check if incident already exists
 	for i, existing := range r.incidents {
 		if existing.ID == incident.ID {
@@ -63,6 +203,129 @@ func (r *InMemoryRepository) SaveIncident(ctx context.Context, incident domain.I
 	return nil
 }
 
+// touch marks an ID as the most recently saved in an LRU list
+func touch(lru *list.List, elems map[string]*list.Element, id string) {
+	if elem, ok := elems[id]; ok {
+		lru.MoveToBack(elem)
+		return
+	}
+	elems[id] = lru.PushBack(id)
+}
+
+// evict removes the alerts and incidents beyond the limits or past the retention. The
+// retention is swept at most once a minute. Callers hold r.mu.
+func (r *InMemoryRepository) evict(now time.Time) {
+	if r.retention > 0 && now.Sub(r.lastSweep) >= time.Minute {
+		r.lastSweep = now
+		cutoff := now.Add(-r.retention)
+		for id, alert := range r.alerts {
+			if alert.OccurredAt.Before(cutoff) {
+				r.evictAlert(id)
+			}
+		}
+		var expired []string
+		for _, incident := range r.incidents {
+			if incident.ResolvedAt != nil && incident.ResolvedAt.Before(cutoff) {
+				expired = append(expired, incident.ID)
+			}
+		}
+		for _, id := range expired {
+			r.evictIncident(id)
+		}
+	}
+
+	for r.maxAlerts > 0 && len(r.alerts) > r.maxAlerts {
+		r.evictAlert(r.alertLRU.Front().Value.(string))
+	}
+	for r.maxIncidents > 0 && len(r.incidents) > r.maxIncidents {
+		r.evictIncident(r.evictableIncident())
+	}
+}
+
+// evictableIncident returns the least recently saved resolved incident, or the least
+// recently saved one if none is resolved
+func (r *InMemoryRepository) evictableIncident() string {
+	resolved := make(map[string]bool, len(r.incidents))
+	for _, incident := range r.incidents {
+		resolved[incident.ID] = incident.ResolvedAt != nil
+	}
+	for elem := r.incidentLRU.Front(); elem != nil; elem = elem.Next() {
+		if resolved[elem.Value.(string)] {
+			return elem.Value.(string)
+		}
+	}
+	return r.incidentLRU.Front().Value.(string)
+}
+
+// evictAlert removes an alert with its acknowledgement and samples
+func (r *InMemoryRepository) evictAlert(id string) {
+	alert, ok := r.alerts[id]
+	if !ok {
+		return
+	}
+	if fingerprint := alert.Fingerprint(); fingerprint != "" && r.fingerprints[fingerprint] == id {
+		delete(r.fingerprints, fingerprint)
+	}
+	delete(r.alerts, id)
+	delete(r.alertAcks, id)
+	delete(r.samples, id)
+	if elem, ok := r.alertElems[id]; ok {
+		r.alertLRU.Remove(elem)
+		delete(r.alertElems, id)
+	}
+	r.evictedAlerts++
+}
+
+// evictIncident removes an incident with its timeline, acknowledgement, ticket,
+// escalations, priority, metadata, pinned root cause, lifecycle, problem link, root
+// cause predictions and action items
+func (r *InMemoryRepository) evictIncident(id string) {
+	for i, incident := range r.incidents {
+		if incident.ID == id {
+			r.incidents = append(r.incidents[:i], r.incidents[i+1:]...)
+			r.evictedIncidents++
+			break
+		}
+	}
+	delete(r.acknowledged, id)
+	delete(r.timelines, id)
+	delete(r.tickets, id)
+	delete(r.escalations, id)
+	delete(r.priorities, id)
+	delete(r.metadata, id)
+	delete(r.overrides, id)
+	delete(r.priorityChanges, id)
+	delete(r.transitions, id)
+	r.problemLinks = removeIncident(r.problemLinks, id, func(link domain.ProblemLink) string { return link.IncidentID })
+	r.rootCauses = removeIncident(r.rootCauses, id, func(record domain.RootCauseRecord) string { return record.IncidentID })
+	r.actionItems = removeIncident(r.actionItems, id, func(item domain.ActionItem) string { return item.IncidentID })
+	if elem, ok := r.incidentElems[id]; ok {
+		r.incidentLRU.Remove(elem)
+		delete(r.incidentElems, id)
+	}
+}
+
+// removeIncident drops the records of an incident, keeping the order of the rest
+func removeIncident[T any](records []T, id string, incidentID func(T) string) []T {
+	kept := records[:0]
+	for _, record := range records {
+		if incidentID(record) != id {
+			kept = append(kept, record)
+		}
+	}
+	clear(records[len(kept):])
+	return kept
+}
+
+// appendBounded appends a record, dropping the oldest beyond maxRecords
+func appendBounded[T any](records []T, record T) []T {
+	records = append(records, record)
+	if len(records) > maxRecords {
+		records = records[len(records)-maxRecords:]
+	}
+	return records
+}
+
 // GetLastProcessedID returns the last processed alert ID
 func (r *InMemoryRepository) GetLastProcessedID(ctx context.Context) (uint64, error) {
 	r.mu.RLock()
@@ -78,6 +341,41 @@ func (r *InMemoryRepository) SetLastProcessedID(ctx context.Context, id uint64)
 	return nil
 }
 
+// GetSourceCursor returns the last processed alert ID of an alert source
+func (r *InMemoryRepository) GetSourceCursor(ctx context.Context, source string) (uint64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.sourceCursors[source], nil
+}
+
+// SetSourceCursor updates the last processed alert ID of an alert source
+func (r *InMemoryRepository) SetSourceCursor(ctx context.Context, source string, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sourceCursors[source] = id
+	return nil
+}
+
+// SavePropagationPatterns replaces the stored propagation patterns
+func (r *InMemoryRepository) SavePropagationPatterns(ctx context.Context, patterns []domain.PropagationPattern) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.patterns = make([]domain.PropagationPattern, len(patterns))
+	copy(r.patterns, patterns)
+	return nil
+}
+
+// GetPropagationPatterns returns the stored propagation patterns
+func (r *InMemoryRepository) GetPropagationPatterns(ctx context.Context) ([]domain.PropagationPattern, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	patterns := make([]domain.PropagationPattern, len(r.patterns))
+	copy(patterns, r.patterns)
+	return patterns, nil
+}
+
 // GetAlerts returns all stored alerts (useful for analysis)
 func (r *InMemoryRepository) GetAlerts(ctx context.Context) ([]domain.Alert, error) {
 	r.mu.RLock()
@@ -102,14 +400,103 @@ func (r *InMemoryRepository) GetAlertByID(ctx context.Context, id string) (domai
 	return alert, nil
 }
 
+// GetAlert returns an alert with its raw payload and acknowledgement, or nil if it
+// doesn't exist
+func (r *InMemoryRepository) GetAlert(ctx context.Context, id string) (*domain.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	alert, exists := r.alerts[id]
+	if !exists {
+		return nil, nil
+	}
+	alert = r.withAlertAck(alert)
+	return &alert, nil
+}
+
+// QueryAlerts returns the alerts matching the query, newest first, and how many match
+func (r *InMemoryRepository) QueryAlerts(ctx context.Context, q domain.AlertQuery) ([]domain.Alert, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := []domain.Alert{}
+	for _, alert := range r.alerts {
+		if alert = r.withAlertAck(alert); q.Matches(alert) {
+			matched = append(matched, alert)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].OccurredAt.Equal(matched[j].OccurredAt) {
+			return matched[i].OccurredAt.After(matched[j].OccurredAt)
+		}
+		return matched[i].ID > matched[j].ID
+	})
+
+	total := len(matched)
+	if q.Offset >= total {
+		return []domain.Alert{}, total, nil
+	}
+	matched = matched[q.Offset:]
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[:q.Limit]
+	}
+	return matched, total, nil
+}
+
+// AcknowledgeAlert records the acknowledgement of an alert, replacing an earlier one
+func (r *InMemoryRepository) AcknowledgeAlert(ctx context.Context, ack domain.AlertAck) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.alerts[ack.AlertID]; !exists {
+		return fmt.Errorf("alert not found: %s", ack.AlertID)
+	}
+	r.alertAcks[ack.AlertID] = ack
+	return nil
+}
+
+// withAlertAck sets the acknowledgement of an alert. Callers hold r.mu.
+func (r *InMemoryRepository) withAlertAck(alert domain.Alert) domain.Alert {
+	if ack, ok := r.alertAcks[alert.ID]; ok {
+		alert.Ack = &ack
+	}
+	return alert
+}
+
+// GetExportCursor returns the resolution time up to which an exporter has exported
+func (r *InMemoryRepository) GetExportCursor(ctx context.Context, exporter string) (time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.exportCursors[exporter], nil
+}
+
+// SetExportCursor records an exporter's progress
+func (r *InMemoryRepository) SetExportCursor(ctx context.Context, exporter string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exportCursors[exporter] = at
+	return nil
+}
+
 // Clear removes all data (useful for testing)
 func (r *InMemoryRepository) Clear() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	r.alerts = make(map[string]domain.Alert)
+	r.fingerprints = make(map[string]string)
+	r.alertAcks = make(map[string]domain.AlertAck)
 	r.incidents = make([]domain.Incident, 0)
 	r.lastProcessedID = 0
+	r.sourceCursors = make(map[string]uint64)
+	r.exportCursors = make(map[string]time.Time)
+	r.patterns = nil
+	r.alertLRU.Init()
+	r.alertElems = make(map[string]*list.Element)
+	r.incidentLRU.Init()
+	r.incidentElems = make(map[string]*list.Element)
+	r.evictedAlerts = 0
+	r.evictedIncidents = 0
 }
 
 // Stats returns repository statistics
@@ -121,6 +508,10 @@ func (r *InMemoryRepository) Stats(ctx context.Context) (map[string]interface{},
 		"total_alerts":      len(r.alerts),
 		"total_incidents":   len(r.incidents),
 		"last_processed_id": r.lastProcessedID,
+		"max_alerts":        r.maxAlerts,
+		"max_incidents":     r.maxIncidents,
+		"evicted_alerts":    r.evictedAlerts,
+		"evicted_incidents": r.evictedIncidents,
 	}, nil
 }
 
@@ -128,3 +519,657 @@ func (r *InMemoryRepository) Stats(ctx context.Context) (map[string]interface{},
 func (r *InMemoryRepository) PingContext(ctx context.Context) error {
 	return nil // In-memory repo is always available
 }
+
+// IncidentStatsByLabel aggregates incident counts and MTTR per value of a label key since the
+// given time, for the incidents passing the filter
+func (r *InMemoryRepository) IncidentStatsByLabel(ctx context.Context, key string, since time.Time, filter domain.LabelFilter) ([]domain.LabelGroupStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	groups := make(map[string]*domain.LabelGroupStats)
+	totals := make(map[string]time.Duration)
+
+	for _, incident := range r.incidents {
+		if incident.StartedAt.Before(since) || !filter.Matches(incident) {
+			continue
+		}
+
+		value, ok := incident.Labels()[key]
+		if !ok {
+			continue
+		}
+
+		group, exists := groups[value]
+		if !exists {
+			group = &domain.LabelGroupStats{Key: key, Value: value}
+			groups[value] = group
+		}
+
+		group.Incidents++
+		if incident.ResolvedAt != nil {
+			group.Resolved++
+			totals[value] += incident.ResolvedAt.Sub(incident.StartedAt)
+		}
+	}
+
+	stats := make([]domain.LabelGroupStats, 0, len(groups))
+	for value, group := range groups {
+		if group.Resolved > 0 {
+			group.MTTR = totals[value] / time.Duration(group.Resolved)
+		}
+		stats = append(stats, *group)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Incidents != stats[j].Incidents {
+			return stats[i].Incidents > stats[j].Incidents
+		}
+		return stats[i].Value < stats[j].Value
+	})
+
+	return stats, nil
+}
+
+// SaveAcknowledgement records when an incident was first acknowledged
+func (r *InMemoryRepository) SaveAcknowledgement(ctx context.Context, incidentID, by string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.acknowledged[incidentID]; !exists {
+		r.acknowledged[incidentID] = at
+	}
+	return nil
+}
+
+// IsAcknowledged reports whether an incident has been acknowledged
+func (r *InMemoryRepository) IsAcknowledged(ctx context.Context, incidentID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.acknowledged[incidentID]
+	return ok, nil
+}
+
+// GetTimelineEntries returns the stored timeline entries of an incident
+func (r *InMemoryRepository) GetTimelineEntries(ctx context.Context, incidentID string) ([]domain.TimelineEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]domain.TimelineEntry{}, r.timelines[incidentID]...), nil
+}
+
+// AppendTimelineEntries adds entries after the incident's stored timeline entries
+func (r *InMemoryRepository) AppendTimelineEntries(ctx context.Context, incidentID string, entries []domain.TimelineEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.timelines[incidentID] = append(r.timelines[incidentID], entries...)
+	return nil
+}
+
+// ReplaceTimelineEntries replaces the stored timeline of an incident
+func (r *InMemoryRepository) ReplaceTimelineEntries(ctx context.Context, incidentID string, entries []domain.TimelineEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.timelines[incidentID] = append([]domain.TimelineEntry{}, entries...)
+	return nil
+}
+
+// GetTicket returns the tracker ticket filed for an incident, or nil if there is none
+func (r *InMemoryRepository) GetTicket(ctx context.Context, incidentID string) (*domain.Ticket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ticket, ok := r.tickets[incidentID]
+	if !ok {
+		return nil, nil
+	}
+	return &ticket, nil
+}
+
+// SaveTicket stores the ticket of an incident, replacing any earlier one
+func (r *InMemoryRepository) SaveTicket(ctx context.Context, ticket domain.Ticket) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tickets[ticket.IncidentID] = ticket
+	return nil
+}
+
+// LinkIncident records the incident as an occurrence of a problem, replacing an earlier
+// link
+func (r *InMemoryRepository) LinkIncident(ctx context.Context, link domain.ProblemLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.problemLinks {
+		if existing.IncidentID == link.IncidentID {
+			r.problemLinks = append(r.problemLinks[:i], r.problemLinks[i+1:]...)
+			break
+		}
+	}
+	r.problemLinks = append(r.problemLinks, link)
+	return nil
+}
+
+// GetProblemLinks returns the links of a problem, or of every problem if problemID is
+// empty, oldest first
+func (r *InMemoryRepository) GetProblemLinks(ctx context.Context, problemID string) ([]domain.ProblemLink, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	links := []domain.ProblemLink{}
+	for _, link := range r.problemLinks {
+		if problemID == "" || link.ProblemID == problemID {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+// GetEscalations returns the escalations of an incident in level order
+func (r *InMemoryRepository) GetEscalations(ctx context.Context, incidentID string) ([]domain.Escalation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]domain.Escalation(nil), r.escalations[incidentID]...), nil
+}
+
+// SaveEscalation records an escalation. Each level of an incident is kept once.
+func (r *InMemoryRepository) SaveEscalation(ctx context.Context, escalation domain.Escalation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	escalations := r.escalations[escalation.IncidentID]
+	for _, existing := range escalations {
+		if existing.Level == escalation.Level {
+			return nil
+		}
+	}
+	escalations = append(escalations, escalation)
+	sort.SliceStable(escalations, func(i, j int) bool { return escalations[i].Level < escalations[j].Level })
+	r.escalations[escalation.IncidentID] = escalations
+	return nil
+}
+
+// SaveRootCause stores a model version's root cause prediction for an incident. Feedback
+// on an earlier prediction is kept if the predicted alert is the same.
+func (r *InMemoryRepository) SaveRootCause(ctx context.Context, record domain.RootCauseRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.rootCauses {
+		if existing.IncidentID == record.IncidentID && existing.ModelVersion == record.ModelVersion {
+			if existing.AlertID == record.AlertID && existing.Correct != nil {
+				record.Correct, record.FeedbackAt = existing.Correct, existing.FeedbackAt
+			}
+			r.rootCauses[i] = record
+			return nil
+		}
+	}
+	r.rootCauses = appendBounded(r.rootCauses, record)
+	return nil
+}
+
+// GetRootCauses returns the stored root cause predictions, of one incident if incidentID
+// is set, oldest first
+func (r *InMemoryRepository) GetRootCauses(ctx context.Context, incidentID string) ([]domain.RootCauseRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := []domain.RootCauseRecord{}
+	for _, record := range r.rootCauses {
+		if incidentID == "" || record.IncidentID == incidentID {
+			records = append(records, record)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].PredictedAt.Before(records[j].PredictedAt)
+	})
+	return records, nil
+}
+
+// SetRootCauseFeedback records whether a model version's prediction for an incident was
+// correct
+func (r *InMemoryRepository) SetRootCauseFeedback(ctx context.Context, incidentID, modelVersion string, correct bool, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, record := range r.rootCauses {
+		if record.IncidentID == incidentID && record.ModelVersion == modelVersion {
+			r.rootCauses[i].Correct = &correct
+			r.rootCauses[i].FeedbackAt = &at
+		}
+	}
+	return nil
+}
+
+// SetRootCauseOverride pins the root cause of an incident
+func (r *InMemoryRepository) SetRootCauseOverride(ctx context.Context, override domain.RootCauseOverride) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.overrides[override.IncidentID] = override
+	return nil
+}
+
+// DeleteRootCauseOverride unpins the root cause of an incident
+func (r *InMemoryRepository) DeleteRootCauseOverride(ctx context.Context, incidentID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.overrides, incidentID)
+	return nil
+}
+
+// GetRootCauseOverrides returns the pinned root causes, of one incident if incidentID is set
+func (r *InMemoryRepository) GetRootCauseOverrides(ctx context.Context, incidentID string) ([]domain.RootCauseOverride, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	overrides := []domain.RootCauseOverride{}
+	for _, override := range r.overrides {
+		if incidentID == "" || override.IncidentID == incidentID {
+			overrides = append(overrides, override)
+		}
+	}
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].SetAt.Before(overrides[j].SetAt) })
+	return overrides, nil
+}
+
+// SetPriority records a priority change and sets or, for an empty priority, removes
+// the incident's manual priority
+func (r *InMemoryRepository) SetPriority(ctx context.Context, change domain.PriorityChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if change.Priority == "" {
+		delete(r.priorities, change.IncidentID)
+	} else {
+		r.priorities[change.IncidentID] = change.Priority
+	}
+	r.priorityChanges[change.IncidentID] = append(r.priorityChanges[change.IncidentID], change)
+	return nil
+}
+
+// GetPriorityChanges returns the priority changes of an incident, oldest first
+func (r *InMemoryRepository) GetPriorityChanges(ctx context.Context, incidentID string) ([]domain.PriorityChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]domain.PriorityChange{}, r.priorityChanges[incidentID]...), nil
+}
+
+// SetIncidentState records a state transition and makes its state the incident's state,
+// provided the incident is still in the transition's From state
+func (r *InMemoryRepository) SetIncidentState(ctx context.Context, transition domain.StateTransition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if transitions := r.transitions[transition.IncidentID]; len(transitions) > 0 {
+		if transitions[len(transitions)-1].To != transition.From {
+			return domain.ErrStateConflict
+		}
+	} else {
+		for _, incident := range r.incidents {
+			if incident.ID == transition.IncidentID && incident.CurrentState() != transition.From {
+				return domain.ErrStateConflict
+			}
+		}
+	}
+	r.transitions[transition.IncidentID] = append(r.transitions[transition.IncidentID], transition)
+	return nil
+}
+
+// GetStateTransitions returns the state transitions of an incident, oldest first
+func (r *InMemoryRepository) GetStateTransitions(ctx context.Context, incidentID string) ([]domain.StateTransition, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]domain.StateTransition{}, r.transitions[incidentID]...), nil
+}
+
+// SaveActionItem stores an action item, replacing the one with the same ID
+func (r *InMemoryRepository) SaveActionItem(ctx context.Context, item domain.ActionItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.actionItems {
+		if existing.ID == item.ID {
+			r.actionItems[i] = item
+			return nil
+		}
+	}
+	r.actionItems = append(r.actionItems, item)
+	return nil
+}
+
+// DeleteActionItem removes an action item, reporting whether there was one
+func (r *InMemoryRepository) DeleteActionItem(ctx context.Context, id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.actionItems {
+		if existing.ID == id {
+			r.actionItems = append(r.actionItems[:i], r.actionItems[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetActionItems returns the action items of an incident, or of every incident if
+// incidentID is empty, oldest first
+func (r *InMemoryRepository) GetActionItems(ctx context.Context, incidentID string) ([]domain.ActionItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	items := []domain.ActionItem{}
+	for _, item := range r.actionItems {
+		if incidentID == "" || item.IncidentID == incidentID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// SetIncidentMetadata replaces the severity, tags and custom fields of an incident
+func (r *InMemoryRepository) SetIncidentMetadata(ctx context.Context, metadata domain.IncidentMetadata) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if metadata.IsEmpty() {
+		delete(r.metadata, metadata.IncidentID)
+	} else {
+		r.metadata[metadata.IncidentID] = metadata
+	}
+	return nil
+}
+
+// SaveAuditEntry appends an entry to the audit log
+func (r *InMemoryRepository) SaveAuditEntry(ctx context.Context, entry domain.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.auditLog = appendBounded(r.auditLog, entry)
+	return nil
+}
+
+// GetAuditEntries returns the audit log entries matching the query, newest first
+func (r *InMemoryRepository) GetAuditEntries(ctx context.Context, q domain.AuditQuery) ([]domain.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []domain.AuditEntry{}
+	for i := len(r.auditLog) - 1; i >= 0; i-- {
+		if q.Limit > 0 && len(entries) == q.Limit {
+			break
+		}
+		if q.Matches(r.auditLog[i]) {
+			entries = append(entries, r.auditLog[i])
+		}
+	}
+	return entries, nil
+}
+
+// GetWebhooks returns every outgoing webhook, oldest first
+func (r *InMemoryRepository) GetWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]domain.Webhook{}, r.webhooks...), nil
+}
+
+// GetWebhook returns a webhook, or nil if it doesn't exist
+func (r *InMemoryRepository) GetWebhook(ctx context.Context, id string) (*domain.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, webhook := range r.webhooks {
+		if webhook.ID == id {
+			return &webhook, nil
+		}
+	}
+	return nil, nil
+}
+
+// SaveWebhook stores or replaces a webhook
+func (r *InMemoryRepository) SaveWebhook(ctx context.Context, webhook domain.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.webhooks {
+		if existing.ID == webhook.ID {
+			webhook.CreatedAt = existing.CreatedAt
+			r.webhooks[i] = webhook
+			return nil
+		}
+	}
+	r.webhooks = append(r.webhooks, webhook)
+	return nil
+}
+
+// DeleteWebhook removes a webhook with its failed deliveries
+func (r *InMemoryRepository) DeleteWebhook(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhooks := r.webhooks[:0]
+	for _, webhook := range r.webhooks {
+		if webhook.ID != id {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	r.webhooks = webhooks
+
+	deadLetters := r.deadLetters[:0]
+	for _, delivery := range r.deadLetters {
+		if delivery.WebhookID != id {
+			deadLetters = append(deadLetters, delivery)
+		}
+	}
+	r.deadLetters = deadLetters
+	return nil
+}
+
+// SaveFailedDelivery adds a delivery to the dead letters
+func (r *InMemoryRepository) SaveFailedDelivery(ctx context.Context, delivery domain.FailedDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deadLetters = appendBounded(r.deadLetters, delivery)
+	return nil
+}
+
+// GetFailedDelivery returns a dead letter, or nil if it doesn't exist
+func (r *InMemoryRepository) GetFailedDelivery(ctx context.Context, id string) (*domain.FailedDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, delivery := range r.deadLetters {
+		if delivery.ID == id {
+			return &delivery, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetFailedDeliveries returns the dead letters, of one webhook if webhookID is set, newest
+// first. limit 0 returns all.
+func (r *InMemoryRepository) GetFailedDeliveries(ctx context.Context, webhookID string, limit int) ([]domain.FailedDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := []domain.FailedDelivery{}
+	for i := len(r.deadLetters) - 1; i >= 0; i-- {
+		if limit > 0 && len(deliveries) == limit {
+			break
+		}
+		if webhookID == "" || r.deadLetters[i].WebhookID == webhookID {
+			deliveries = append(deliveries, r.deadLetters[i])
+		}
+	}
+	return deliveries, nil
+}
+
+// DeleteFailedDelivery removes a dead letter
+func (r *InMemoryRepository) DeleteFailedDelivery(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, delivery := range r.deadLetters {
+		if delivery.ID == id {
+			r.deadLetters = append(r.deadLetters[:i], r.deadLetters[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// AcquireLease takes or renews a lease for holder until ttl from now if it is free,
+// expired or already held by holder, and returns the current lease
+func (r *InMemoryRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (domain.Lease, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	lease, exists := r.leases[name]
+	if exists && lease.Holder != holder && lease.ExpiresAt.After(now) {
+		return lease, false, nil
+	}
+	if !exists || lease.Holder != holder {
+		lease = domain.Lease{Name: name, Holder: holder, AcquiredAt: now}
+	}
+	lease.ExpiresAt = now.Add(ttl)
+	r.leases[name] = lease
+	return lease, true, nil
+}
+
+// ReleaseLease expires a lease held by holder
+func (r *InMemoryRepository) ReleaseLease(ctx context.Context, name, holder string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if lease, exists := r.leases[name]; exists && lease.Holder == holder {
+		lease.ExpiresAt = time.Now().UTC()
+		r.leases[name] = lease
+	}
+	return nil
+}
+
+// ReliabilityStats aggregates MTTR, MTTA, incident frequency and recurring incidents for
+// the incidents started within [from, to) that pass the filter
+func (r *InMemoryRepository) ReliabilityStats(ctx context.Context, from, to time.Time, filter domain.LabelFilter) (domain.ReliabilityStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := domain.ReliabilityStats{From: from, To: to}
+	var resolveTotal, ackTotal time.Duration
+	hosts := make(map[string]int)
+	resources := make(map[string]int)
+	states := make(map[domain.IncidentState]int)
+	titles := make(map[string]*domain.RecurringIncident)
+	titleResolveTotals := make(map[string]time.Duration)
+	titleResolved := make(map[string]int)
+
+	for _, incident := range r.incidents {
+		if incident.StartedAt.Before(from) || !incident.StartedAt.Before(to) || !filter.Matches(incident) {
+			continue
+		}
+
+		stats.Incidents++
+		stats.ByWeekday[incident.StartedAt.UTC().Weekday()]++
+		if transitions := r.transitions[incident.ID]; len(transitions) > 0 {
+			incident.State = transitions[len(transitions)-1].To
+		}
+		states[incident.CurrentState()]++
+		if incident.ResolvedAt != nil {
+			stats.Resolved++
+			resolveTotal += incident.ResolvedAt.Sub(incident.StartedAt)
+			titleResolved[incident.Title]++
+			titleResolveTotals[incident.Title] += incident.ResolvedAt.Sub(incident.StartedAt)
+		}
+		if at, ok := r.acknowledged[incident.ID]; ok {
+			stats.Acknowledged++
+			ackTotal += at.Sub(incident.StartedAt)
+		}
+
+		seenHosts := make(map[string]bool)
+		seenResources := make(map[string]bool)
+		for _, event := range incident.Events {
+			seenHosts[event.Host] = true
+			seenResources[string(event.ResourceType)] = true
+		}
+		for host := range seenHosts {
+			hosts[host]++
+		}
+		for resource := range seenResources {
+			resources[resource]++
+		}
+
+		if titles[incident.Title] == nil {
+			titles[incident.Title] = &domain.RecurringIncident{Title: incident.Title}
+		}
+		titles[incident.Title].Incidents++
+	}
+
+	if stats.Resolved > 0 {
+		stats.MTTR = resolveTotal / time.Duration(stats.Resolved)
+	}
+	if stats.Acknowledged > 0 {
+		stats.MTTA = ackTotal / time.Duration(stats.Acknowledged)
+	}
+	stats.ByHost = topFrequencies(hosts, 10)
+	stats.ByResourceType = topFrequencies(resources, 10)
+	stats.ByState = domain.StateFrequencies(states)
+
+	stats.Recurring = []domain.RecurringIncident{}
+	for title, recurring := range titles {
+		if recurring.Incidents < 2 {
+			continue
+		}
+		if titleResolved[title] > 0 {
+			recurring.MTTR = titleResolveTotals[title] / time.Duration(titleResolved[title])
+		}
+		stats.Recurring = append(stats.Recurring, *recurring)
+	}
+	sort.Slice(stats.Recurring, func(i, j int) bool {
+		if stats.Recurring[i].Incidents != stats.Recurring[j].Incidents {
+			return stats.Recurring[i].Incidents > stats.Recurring[j].Incidents
+		}
+		return stats.Recurring[i].Title < stats.Recurring[j].Title
+	})
+	if len(stats.Recurring) > 10 {
+		stats.Recurring = stats.Recurring[:10]
+	}
+
+	return stats, nil
+}
+
+// topFrequencies returns the n largest counts, ties broken by key
+func topFrequencies(counts map[string]int, n int) []domain.FrequencyCount {
+	result := make([]domain.FrequencyCount, 0, len(counts))
+	for key, count := range counts {
+		result = append(result, domain.FrequencyCount{Key: key, Incidents: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Incidents != result[j].Incidents {
+			return result[i].Incidents > result[j].Incidents
+		}
+		return result[i].Key < result[j].Key
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+// SaveAlertSamples replaces the chart values sampled for an alert
+func (r *InMemoryRepository) SaveAlertSamples(ctx context.Context, alertID string, samples []domain.MetricSample) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.samples[alertID] = samples
+	return nil
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func TestInMemoryRepository_Limits(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	repo := NewInMemoryRepository()
+	repo.SetLimits(3, 2, 0)
+
+	for i := 0; i < 5; i++ {
+		alert := domain.Alert{ID: fmt.Sprintf("a%d", i), Host: "h", OccurredAt: now}
+		if err := repo.SaveAlert(ctx, alert); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Saving a2 again makes a3 the least recently saved
+	if err := repo.SaveAlert(ctx, domain.Alert{ID: "a2", Host: "h", OccurredAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SaveAlert(ctx, domain.Alert{ID: "a5", Host: "h", OccurredAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	for id, want := range map[string]bool{"a0": false, "a1": false, "a2": true, "a3": false, "a4": true, "a5": true} {
+		if _, err := repo.GetAlertByID(ctx, id); (err == nil) != want {
+			t.Errorf("alert %s kept = %v, want %v", id, err == nil, want)
+		}
+	}
+
+	// Resolved incidents go first, even if saved more recently
+	resolved := now.Add(-time.Minute)
+	for _, incident := range []domain.Incident{
+		{ID: "open-1", StartedAt: now},
+		{ID: "resolved", StartedAt: now, ResolvedAt: &resolved},
+		{ID: "open-2", StartedAt: now},
+	} {
+		if err := repo.SaveIncident(ctx, incident); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.AppendTimelineEntries(ctx, "open-1", []domain.TimelineEntry{{}}); err != nil {
+		t.Fatal(err)
+	}
+	incidents, _ := repo.GetIncidents(ctx)
+	if len(incidents) != 2 || incidents[0].ID != "open-1" || incidents[1].ID != "open-2" {
+		t.Fatalf("expected the resolved incident to be evicted, got %v", incidents)
+	}
+
+	// Without resolved ones, the least recently saved incident goes
+	if err := repo.SaveIncident(ctx, domain.Incident{ID: "open-3", StartedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	incidents, _ = repo.GetIncidents(ctx)
+	if len(incidents) != 2 || incidents[0].ID != "open-2" || incidents[1].ID != "open-3" {
+		t.Fatalf("expected open-1 to be evicted, got %v", incidents)
+	}
+	if entries, _ := repo.GetTimelineEntries(ctx, "open-1"); len(entries) != 0 {
+		t.Errorf("expected the timeline of an evicted incident to be dropped, got %v", entries)
+	}
+
+	// Records of an evicted incident go with it, those of kept incidents stay
+	for _, id := range []string{"open-2", "open-3"} {
+		_ = repo.LinkIncident(ctx, domain.ProblemLink{IncidentID: id, ProblemID: "p"})
+		_ = repo.SaveRootCause(ctx, domain.RootCauseRecord{IncidentID: id, ModelVersion: "v1"})
+		_ = repo.SaveActionItem(ctx, domain.ActionItem{ID: "action-" + id, IncidentID: id})
+	}
+	if err := repo.SaveIncident(ctx, domain.Incident{ID: "open-4", StartedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if links, _ := repo.GetProblemLinks(ctx, ""); len(links) != 1 || links[0].IncidentID != "open-3" {
+		t.Errorf("expected only the problem link of open-3 to be kept, got %v", links)
+	}
+	if records, _ := repo.GetRootCauses(ctx, ""); len(records) != 1 || records[0].IncidentID != "open-3" {
+		t.Errorf("expected only the root cause of open-3 to be kept, got %v", records)
+	}
+	if items, _ := repo.GetActionItems(ctx, ""); len(items) != 1 || items[0].IncidentID != "open-3" {
+		t.Errorf("expected only the action item of open-3 to be kept, got %v", items)
+	}
+
+	stats, _ := repo.Stats(ctx)
+	if stats["evicted_alerts"] != uint64(3) || stats["evicted_incidents"] != uint64(3) {
+		t.Errorf("unexpected eviction counters %v", stats)
+	}
+}
+
+func TestInMemoryRepository_Retention(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+	repo := NewInMemoryRepository()
+
+	_ = repo.SaveAlerts(ctx, []domain.Alert{
+		{ID: "old", Host: "h", OccurredAt: old},
+		{ID: "new", Host: "h", OccurredAt: now},
+	})
+	_ = repo.SaveIncident(ctx, domain.Incident{ID: "resolved", StartedAt: old, ResolvedAt: &old})
+	_ = repo.SaveIncident(ctx, domain.Incident{ID: "open", StartedAt: old})
+
+	repo.SetLimits(0, 0, time.Hour)
+
+	if _, err := repo.GetAlertByID(ctx, "old"); err == nil {
+		t.Error("expected the alert past the retention to be evicted")
+	}
+	if _, err := repo.GetAlertByID(ctx, "new"); err != nil {
+		t.Error("expected the recent alert to be kept")
+	}
+	incidents, _ := repo.GetIncidents(ctx)
+	if len(incidents) != 1 || incidents[0].ID != "open" {
+		t.Errorf("expected only the open incident to be kept, got %v", incidents)
+	}
+}
+
+func TestInMemoryRepository_BoundedRecords(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryRepository()
+
+	for i := 0; i < maxRecords+5; i++ {
+		_ = repo.SaveAuditEntry(ctx, domain.AuditEntry{Actor: fmt.Sprintf("u%d", i)})
+		_ = repo.SaveFailedDelivery(ctx, domain.FailedDelivery{ID: fmt.Sprintf("d%d", i)})
+		_ = repo.SaveRootCause(ctx, domain.RootCauseRecord{IncidentID: fmt.Sprintf("i%d", i)})
+	}
+
+	entries, _ := repo.GetAuditEntries(ctx, domain.AuditQuery{})
+	if len(entries) != maxRecords || entries[0].Actor != fmt.Sprintf("u%d", maxRecords+4) || entries[len(entries)-1].Actor != "u5" {
+		t.Errorf("expected the newest %d audit entries, got %d from %s to %s", maxRecords, len(entries), entries[0].Actor, entries[len(entries)-1].Actor)
+	}
+	deliveries, _ := repo.GetFailedDeliveries(ctx, "", 0)
+	if len(deliveries) != maxRecords {
+		t.Errorf("expected %d dead letters, got %d", maxRecords, len(deliveries))
+	}
+	if delivery, _ := repo.GetFailedDelivery(ctx, "d4"); delivery != nil {
+		t.Error("expected the oldest dead letters to be dropped")
+	}
+	records, _ := repo.GetRootCauses(ctx, "")
+	if len(records) != maxRecords || records[0].IncidentID != "i5" {
+		t.Errorf("expected the newest %d root causes, got %d", maxRecords, len(records))
+	}
+}
+
+func TestInMemoryRepository_SetIncidentStateConflict(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	repo := NewInMemoryRepository()
+	_ = repo.SaveIncident(ctx, domain.Incident{ID: "inc", StartedAt: now})
+
+	triage := domain.StateTransition{IncidentID: "inc", From: domain.StateDetected, To: domain.StateTriaged, ChangedAt: now}
+	if err := repo.SetIncidentState(ctx, triage); err != nil {
+		t.Fatal(err)
+	}
+	// A second responder moving it on from detected lost the race
+	late := domain.StateTransition{IncidentID: "inc", From: domain.StateDetected, To: domain.StateMitigating, ChangedAt: now}
+	if err := repo.SetIncidentState(ctx, late); !errors.Is(err, domain.ErrStateConflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+	// Before the first transition, a resolved incident is resolved
+	resolved := now
+	_ = repo.SaveIncident(ctx, domain.Incident{ID: "done", StartedAt: now, ResolvedAt: &resolved})
+	if err := repo.SetIncidentState(ctx, domain.StateTransition{IncidentID: "done", From: domain.StateDetected, To: domain.StateTriaged}); !errors.Is(err, domain.ErrStateConflict) {
+		t.Fatalf("expected a conflict for a resolved incident, got %v", err)
+	}
+
+	if transitions, _ := repo.GetStateTransitions(ctx, "inc"); len(transitions) != 1 || transitions[0].To != domain.StateTriaged {
+		t.Errorf("expected only the winning transition, got %v", transitions)
+	}
+}
@@ -0,0 +1,339 @@
+// Package redis provides a Redis-backed repository for ephemeral, high-throughput
+// deployments. Alerts and incidents are stored as hashes that expire after a TTL
+// and are indexed by time in sorted sets.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"incident-teller/internal/domain"
+)
+
+// Options configures the Redis repository
+type Options struct {
+	Addr      string
+	Password  string
+	DB        int
+	KeyPrefix string        // Prepended to every key, e.g. "incident-teller:"
+	TTL       time.Duration // How long alerts and incidents are kept; 0 keeps them forever
+}
+
+// Repository stores alerts and incidents in Redis
+//
+// Keys (relative to the prefix):
+//
+//	alert:<id>            hash with the alert JSON and a few indexed fields
+//	incident:<id>         hash with the incident JSON (events embedded)
+//	alerts                sorted set of alert IDs scored by occurred_at (ms)
+//	incidents             sorted set of incident IDs scored by started_at (ms)
+//	last_processed_id     string
+//	propagation_patterns  string with the learned patterns as JSON
+type Repository struct {
+	client *goredis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRepository creates a Redis repository. The connection is established lazily;
+// use PingContext to verify it.
+func NewRepository(opts Options) *Repository {
+	return &Repository{
+		client: goredis.NewClient(&goredis.Options{
+			Addr:     opts.Addr,
+			Password: opts.Password,
+			DB:       opts.DB,
+		}),
+		prefix: opts.KeyPrefix,
+		ttl:    opts.TTL,
+	}
+}
+
+func (r *Repository) key(parts ...string) string {
+	key := r.prefix
+	for i, part := range parts {
+		if i > 0 {
+			key += ":"
+		}
+		key += part
+	}
+	return key
+}
+
+func score(t time.Time) float64 {
+	return float64(t.UnixMilli())
+}
+
+// SaveAlert stores an alert and indexes it by occurrence time
+func (r *Repository) SaveAlert(ctx context.Context, alert domain.Alert) error {
+	return r.SaveAlerts(ctx, []domain.Alert{alert})
+}
+
+// SaveAlerts stores a batch of alerts in a single MULTI/EXEC round trip
+func (r *Repository) SaveAlerts(ctx context.Context, alerts []domain.Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	pipe := r.client.TxPipeline()
+	members := make([]goredis.Z, 0, len(alerts))
+	for _, alert := range alerts {
+		data, err := json.Marshal(alert)
+		if err != nil {
+			return fmt.Errorf("failed to marshal alert: %w", err)
+		}
+
+		key := r.key("alert", alert.ID)
+		pipe.HSet(ctx, key,
+			"data", data,
+			"host", alert.Host,
+			"status", string(alert.Status),
+			"occurred_at", alert.OccurredAt.Format(time.RFC3339Nano),
+		)
+		if r.ttl > 0 {
+			pipe.Expire(ctx, key, r.ttl)
+		}
+		members = append(members, goredis.Z{Score: score(alert.OccurredAt), Member: alert.ID})
+	}
+	pipe.ZAdd(ctx, r.key("alerts"), members...)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save alerts: %w", err)
+	}
+	return nil
+}
+
+// GetAlerts returns all stored alerts, newest first
+func (r *Repository) GetAlerts(ctx context.Context) ([]domain.Alert, error) {
+	ids, err := r.client.ZRevRange(ctx, r.key("alerts"), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+
+	alerts := make([]domain.Alert, 0, len(ids))
+	err = r.load(ctx, "alerts", "alert", ids, func(data []byte) error {
+		var alert domain.Alert
+		if err := json.Unmarshal(data, &alert); err != nil {
+			return fmt.Errorf("failed to unmarshal alert: %w", err)
+		}
+		alerts = append(alerts, alert)
+		return nil
+	})
+	return alerts, err
+}
+
+// SaveIncident stores an incident with its events embedded and indexes it by start time
+func (r *Repository) SaveIncident(ctx context.Context, incident domain.Incident) error {
+	data, err := json.Marshal(incident)
+	if err != nil {
+		return fmt.Errorf("failed to marshal incident: %w", err)
+	}
+
+	key := r.key("incident", incident.ID)
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, key,
+		"data", data,
+		"status", string(incident.Status),
+		"started_at", incident.StartedAt.Format(time.RFC3339Nano),
+	)
+	if r.ttl > 0 {
+		pipe.Expire(ctx, key, r.ttl)
+	}
+	pipe.ZAdd(ctx, r.key("incidents"), goredis.Z{Score: score(incident.StartedAt), Member: incident.ID})
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save incident: %w", err)
+	}
+	return nil
+}
+
+// GetIncidents returns all stored incidents, most recent first
+func (r *Repository) GetIncidents(ctx context.Context) ([]domain.Incident, error) {
+	ids, err := r.client.ZRevRange(ctx, r.key("incidents"), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incidents: %w", err)
+	}
+	return r.loadIncidents(ctx, ids)
+}
+
+// GetIncidentsByTimeRange returns incidents started within [start, end], most recent first
+func (r *Repository) GetIncidentsByTimeRange(ctx context.Context, start, end time.Time) ([]domain.Incident, error) {
+	ids, err := r.client.ZRevRangeByScore(ctx, r.key("incidents"), &goredis.ZRangeBy{
+		Min: strconv.FormatFloat(score(start), 'f', 0, 64),
+		Max: strconv.FormatFloat(score(end), 'f', 0, 64),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incidents by time range: %w", err)
+	}
+	return r.loadIncidents(ctx, ids)
+}
+
+// QueryIncidents returns the incidents matching the query's search text, in the requested order.
+// Redis has no secondary indexes for text, so filtering happens client-side.
+func (r *Repository) QueryIncidents(ctx context.Context, q domain.IncidentQuery) ([]domain.Incident, error) {
+	incidents, err := r.GetIncidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return domain.ApplyIncidentQuery(incidents, q, time.Now()), nil
+}
+
+func (r *Repository) loadIncidents(ctx context.Context, ids []string) ([]domain.Incident, error) {
+	incidents := make([]domain.Incident, 0, len(ids))
+	err := r.load(ctx, "incidents", "incident", ids, func(data []byte) error {
+		var incident domain.Incident
+		if err := json.Unmarshal(data, &incident); err != nil {
+			return fmt.Errorf("failed to unmarshal incident: %w", err)
+		}
+		incidents = append(incidents, incident)
+		return nil
+	})
+	return incidents, err
+}
+
+// load fetches the JSON payloads for the given IDs in one round trip. IDs whose hash
+// has expired are removed from the index.
+func (r *Repository) load(ctx context.Context, index, kind string, ids []string, decode func([]byte) error) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	pipe := r.client.Pipeline()
+	cmds := make([]*goredis.StringCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HGet(ctx, r.key(kind, id), "data")
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, goredis.Nil) {
+		return fmt.Errorf("failed to load %s: %w", index, err)
+	}
+
+	var expired []interface{}
+	for i, cmd := range cmds {
+		data, err := cmd.Bytes()
+		if errors.Is(err, goredis.Nil) {
+			expired = append(expired, ids[i])
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load %s %s: %w", kind, ids[i], err)
+		}
+		if err := decode(data); err != nil {
+			return err
+		}
+	}
+
+	if len(expired) > 0 {
+		if err := r.client.ZRem(ctx, r.key(index), expired...).Err(); err != nil {
+			return fmt.Errorf("failed to prune expired %s: %w", index, err)
+		}
+	}
+	return nil
+}
+
+// GetLastProcessedID returns the last processed alert ID
+func (r *Repository) GetLastProcessedID(ctx context.Context) (uint64, error) {
+	value, err := r.client.Get(ctx, r.key("last_processed_id")).Result()
+	if errors.Is(err, goredis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last processed ID: %w", err)
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// SetLastProcessedID updates the last processed alert ID. It never expires.
+func (r *Repository) SetLastProcessedID(ctx context.Context, id uint64) error {
+	if err := r.client.Set(ctx, r.key("last_processed_id"), strconv.FormatUint(id, 10), 0).Err(); err != nil {
+		return fmt.Errorf("failed to set last processed ID: %w", err)
+	}
+	return nil
+}
+
+// GetSourceCursor returns the last processed alert ID of an alert source
+func (r *Repository) GetSourceCursor(ctx context.Context, source string) (uint64, error) {
+	value, err := r.client.Get(ctx, r.key("last_processed_id:"+source)).Result()
+	if errors.Is(err, goredis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s cursor: %w", source, err)
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// SetSourceCursor updates the last processed alert ID of an alert source. It never expires.
+func (r *Repository) SetSourceCursor(ctx context.Context, source string, id uint64) error {
+	if err := r.client.Set(ctx, r.key("last_processed_id:"+source), strconv.FormatUint(id, 10), 0).Err(); err != nil {
+		return fmt.Errorf("failed to set %s cursor: %w", source, err)
+	}
+	return nil
+}
+
+// SavePropagationPatterns replaces the stored propagation patterns. They never expire.
+func (r *Repository) SavePropagationPatterns(ctx context.Context, patterns []domain.PropagationPattern) error {
+	data, err := json.Marshal(patterns)
+	if err != nil {
+		return fmt.Errorf("failed to marshal propagation patterns: %w", err)
+	}
+	if err := r.client.Set(ctx, r.key("propagation_patterns"), data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to save propagation patterns: %w", err)
+	}
+	return nil
+}
+
+// GetPropagationPatterns returns the stored propagation patterns
+func (r *Repository) GetPropagationPatterns(ctx context.Context) ([]domain.PropagationPattern, error) {
+	data, err := r.client.Get(ctx, r.key("propagation_patterns")).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return []domain.PropagationPattern{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get propagation patterns: %w", err)
+	}
+
+	var patterns []domain.PropagationPattern
+	if err := json.Unmarshal(data, &patterns); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal propagation patterns: %w", err)
+	}
+	return patterns, nil
+}
+
+// Stats returns repository statistics. Counts may include entries whose TTL has
+// expired but that have not been pruned from the index yet.
+func (r *Repository) Stats(ctx context.Context) (map[string]interface{}, error) {
+	pipe := r.client.Pipeline()
+	alerts := pipe.ZCard(ctx, r.key("alerts"))
+	incidents := pipe.ZCard(ctx, r.key("incidents"))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	lastID, err := r.GetLastProcessedID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"total_alerts":      alerts.Val(),
+		"total_incidents":   incidents.Val(),
+		"last_processed_id": lastID,
+		"ttl":               r.ttl.String(),
+	}, nil
+}
+
+// PingContext verifies the connection to Redis
+func (r *Repository) PingContext(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// Close closes the Redis client
+func (r *Repository) Close() error {
+	return r.client.Close()
+}
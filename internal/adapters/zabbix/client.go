@@ -0,0 +1,344 @@
+package zabbix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
+)
+
+// Events fetched per API call; the poller catches up over several polls
+const defaultBatchSize = 500
+
+// Client implements the AlertSource interface for Zabbix. It polls trigger events
+// (problems and their recoveries) through the JSON-RPC API, using the Zabbix event ID
+// as the alert's external ID. Requires Zabbix 6.4 or later, which accepts API tokens and
+// sessions as Bearer authorization.
+type Client struct {
+	url        string
+	token      string
+	username   string
+	password   string
+	httpClient *http.Client
+	batchSize  int
+
+	mu       sync.Mutex
+	session  string                        // Session from user.login when no API token is set
+	triggers map[string]domain.AlertStatus // triggerID -> last known status
+}
+
+// NewClient creates a Zabbix API client. url is the frontend's api_jsonrpc.php endpoint.
+// Authenticate with an API token, or with a username and password when token is empty.
+func NewClient(url, token, username, password string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &Client{
+		url:        url,
+		token:      token,
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: timeout},
+		batchSize:  defaultBatchSize,
+		triggers:   make(map[string]domain.AlertStatus),
+	}
+}
+
+// event is a Zabbix trigger event as returned by event.get
+type event struct {
+	EventID  string `json:"eventid"`
+	ObjectID string `json:"objectid"` // Trigger ID
+	Clock    string `json:"clock"`
+	NS       string `json:"ns"`
+	Value    string `json:"value"` // "1" problem, "0" recovery
+	Severity string `json:"severity"`
+	Name     string `json:"name"`
+	OpData   string `json:"opdata"`
+	Hosts    []struct {
+		Host string `json:"host"`
+		Name string `json:"name"`
+	} `json:"hosts"`
+	Tags []struct {
+		Tag   string `json:"tag"`
+		Value string `json:"value"`
+	} `json:"tags"`
+}
+
+// FetchLatest retrieves trigger events with an ID greater than lastID, oldest first
+func (c *Client) FetchLatest(ctx context.Context, lastID uint64) ([]domain.Alert, error) {
+	params := map[string]interface{}{
+		"output":      "extend",
+		"source":      0, // Triggers
+		"object":      0,
+		"selectHosts": []string{"host", "name"},
+		"selectTags":  "extend",
+		"sortfield":   []string{"eventid"},
+		"sortorder":   "ASC",
+		"limit":       c.batchSize,
+	}
+	if lastID > 0 {
+		params["eventid_from"] = strconv.FormatUint(lastID+1, 10)
+	}
+
+	// The events are kept as sent, to be stored with the alerts
+	var events []json.RawMessage
+	if err := c.call(ctx, "event.get", params, &events); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	alerts := make([]domain.Alert, 0, len(events))
+	for _, raw := range events {
+		var e event
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return nil, fmt.Errorf("failed to decode Zabbix event: %w", err)
+		}
+		alert, err := c.normalizeEvent(e)
+		if err != nil {
+			return nil, err
+		}
+		alert.RawPayload = string(raw)
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+// normalizeEvent converts a Zabbix event to a domain Alert. Callers hold c.mu.
+func (c *Client) normalizeEvent(e event) (domain.Alert, error) {
+	eventID, err := strconv.ParseUint(e.EventID, 10, 64)
+	if err != nil {
+		return domain.Alert{}, fmt.Errorf("invalid Zabbix event ID %q: %w", e.EventID, err)
+	}
+	seconds, _ := strconv.ParseInt(e.Clock, 10, 64)
+	nanos, _ := strconv.ParseInt(e.NS, 10, 64)
+	occurredAt := time.Unix(seconds, nanos).UTC()
+
+	host := "unknown"
+	if len(e.Hosts) > 0 {
+		host = e.Hosts[0].Host
+	}
+
+	labels := map[string]string{
+		"source":     "zabbix",
+		"trigger_id": e.ObjectID,
+		"severity":   severityName(e.Severity),
+	}
+	if e.OpData != "" {
+		labels["opdata"] = e.OpData
+	}
+	component := ""
+	for _, tag := range e.Tags {
+		if tag.Tag == "" || tag.Value == "" {
+			continue
+		}
+		if _, exists := labels[tag.Tag]; !exists {
+			labels[tag.Tag] = tag.Value
+		}
+		if tag.Tag == "component" && component == "" {
+			component = tag.Value
+		}
+	}
+
+	status := mapSeverity(e.Severity)
+	if e.Value == "0" {
+		status = domain.StatusClear
+	}
+	oldStatus, known := c.triggers[e.ObjectID]
+	if !known {
+		oldStatus = domain.StatusClear
+		if status == domain.StatusClear {
+			oldStatus = domain.StatusWarning
+		}
+	}
+	c.triggers[e.ObjectID] = status
+
+	return domain.Alert{
+		ID:           idgen.Derive(occurredAt, "zabbix-"+e.EventID),
+		ExternalID:   eventID,
+		Host:         host,
+		Chart:        "zabbix.trigger." + e.ObjectID,
+		Family:       component,
+		Name:         e.Name,
+		Status:       status,
+		OldStatus:    oldStatus,
+		OccurredAt:   occurredAt,
+		Description:  e.Name,
+		ResourceType: classifyResourceType(component, e.Name),
+		Labels:       labels,
+	}, nil
+}
+
+// mapSeverity converts a Zabbix trigger severity (0 not classified .. 5 disaster)
+func mapSeverity(severity string) domain.AlertStatus {
+	switch severity {
+	case "4", "5":
+		return domain.StatusCritical
+	default:
+		return domain.StatusWarning
+	}
+}
+
+func severityName(severity string) string {
+	switch severity {
+	case "1":
+		return "information"
+	case "2":
+		return "warning"
+	case "3":
+		return "average"
+	case "4":
+		return "high"
+	case "5":
+		return "disaster"
+	default:
+		return "not_classified"
+	}
+}
+
+// classifyResourceType determines the resource type from the "component" tag used by
+// the official templates, falling back to keywords in the trigger name
+func classifyResourceType(component, name string) domain.ResourceType {
+	switch strings.ToLower(component) {
+	case "cpu":
+		return domain.ResourceCPU
+	case "memory", "swap":
+		return domain.ResourceMemory
+	case "storage", "disk", "filesystem":
+		return domain.ResourceDisk
+	case "network", "interface":
+		return domain.ResourceNetwork
+	case "application", "process", "service":
+		return domain.ResourceProcess
+	}
+
+	lower := strings.ToLower(name)
+	switch {
+	case strings.Contains(lower, "cpu") || strings.Contains(lower, "load average"):
+		return domain.ResourceCPU
+	case strings.Contains(lower, "memory") || strings.Contains(lower, "swap"):
+		return domain.ResourceMemory
+	case strings.Contains(lower, "disk") || strings.Contains(lower, "filesystem") || strings.Contains(lower, "space"):
+		return domain.ResourceDisk
+	case strings.Contains(lower, "interface") || strings.Contains(lower, "network") || strings.Contains(lower, "icmp"):
+		return domain.ResourceNetwork
+	case strings.Contains(lower, "process") || strings.Contains(lower, "service"):
+		return domain.ResourceProcess
+	}
+
+	return domain.ResourceUnknown
+}
+
+// rpcRequest and rpcResponse are JSON-RPC 2.0 envelopes
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+	ID      int         `json:"id"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// call invokes an API method, logging in first when using username/password
+// authentication. An expired session is renewed once.
+func (c *Client) call(ctx context.Context, method string, params, result interface{}) error {
+	auth, err := c.authToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = c.do(ctx, method, params, auth, result)
+	if err != nil && c.token == "" && strings.Contains(strings.ToLower(err.Error()), "session") {
+		c.mu.Lock()
+		c.session = ""
+		c.mu.Unlock()
+		if auth, err = c.authToken(ctx); err != nil {
+			return err
+		}
+		err = c.do(ctx, method, params, auth, result)
+	}
+	return err
+}
+
+// authToken returns the API token, logging in to obtain a session when none is configured
+func (c *Client) authToken(ctx context.Context) (string, error) {
+	if c.token != "" {
+		return c.token, nil
+	}
+
+	c.mu.Lock()
+	session := c.session
+	c.mu.Unlock()
+	if session != "" {
+		return session, nil
+	}
+
+	params := map[string]string{"username": c.username, "password": c.password}
+	if err := c.do(ctx, "user.login", params, "", &session); err != nil {
+		return "", fmt.Errorf("failed to log in to Zabbix: %w", err)
+	}
+
+	c.mu.Lock()
+	c.session = session
+	c.mu.Unlock()
+	return session, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, params interface{}, auth string, result interface{}) error {
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", Method: method, Params: params, ID: 1})
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json-rpc")
+	if auth != "" {
+		req.Header.Set("Authorization", "Bearer "+auth)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var rpcResp rpcResponse
+	if err := json.Unmarshal(respBody, &rpcResp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("zabbix %s failed: %s %s", method, rpcResp.Error.Message, rpcResp.Error.Data)
+	}
+	if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+		return fmt.Errorf("failed to parse %s result: %w", method, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package ai
+
+import (
+	"math"
+)
+
+// CalibrationSample is a raw root cause score with whether the prediction was correct
+type CalibrationSample struct {
+	Score   float64
+	Correct bool
+}
+
+// CalibrationBin is one bucket of the calibration curve: predictions with a raw score in
+// [Lower, Upper) and how often they were correct
+type CalibrationBin struct {
+	Lower     float64 `json:"lower"`
+	Upper     float64 `json:"upper"`
+	Count     int     `json:"count"`
+	Correct   int     `json:"correct"`
+	MeanScore float64 `json:"mean_score"`
+	Accuracy  float64 `json:"accuracy"`
+}
+
+// Calibration maps raw root cause scores to the observed accuracy of predictions with
+// similar scores (histogram binning). Bins with fewer than MinSamples predictions keep
+// the raw score.
+type Calibration struct {
+	Bins       []CalibrationBin `json:"bins"`
+	MinSamples int              `json:"min_samples"`
+	Samples    int              `json:"samples"`
+	Brier      float64          `json:"brier_score"`                // Mean squared error of the raw scores
+	ECE        float64          `json:"expected_calibration_error"` // Count-weighted gap between mean score and accuracy
+}
+
+// FitCalibration bins samples by raw score into bins equal-width bins over [0, 1]
+func FitCalibration(samples []CalibrationSample, bins, minSamples int) *Calibration {
+	if bins < 1 {
+		bins = 1
+	}
+	c := &Calibration{Bins: make([]CalibrationBin, bins), MinSamples: minSamples, Samples: len(samples)}
+	for i := range c.Bins {
+		c.Bins[i].Lower = float64(i) / float64(bins)
+		c.Bins[i].Upper = float64(i+1) / float64(bins)
+	}
+
+	for _, sample := range samples {
+		bin := &c.Bins[c.bin(sample.Score)]
+		bin.Count++
+		bin.MeanScore += sample.Score
+		outcome := 0.0
+		if sample.Correct {
+			bin.Correct++
+			outcome = 1
+		}
+		c.Brier += (sample.Score - outcome) * (sample.Score - outcome)
+	}
+
+	for i := range c.Bins {
+		bin := &c.Bins[i]
+		if bin.Count == 0 {
+			continue
+		}
+		bin.MeanScore /= float64(bin.Count)
+		bin.Accuracy = float64(bin.Correct) / float64(bin.Count)
+		c.ECE += float64(bin.Count) * math.Abs(bin.MeanScore-bin.Accuracy)
+	}
+	if len(samples) > 0 {
+		c.Brier /= float64(len(samples))
+		c.ECE /= float64(len(samples))
+	}
+	return c
+}
+
+// Apply returns the calibrated confidence of a raw score
+func (c *Calibration) Apply(score float64) float64 {
+	if c == nil || len(c.Bins) == 0 {
+		return score
+	}
+	bin := c.Bins[c.bin(score)]
+	if bin.Count == 0 || bin.Count < c.MinSamples {
+		return score
+	}
+	return bin.Accuracy
+}
+
+func (c *Calibration) bin(score float64) int {
+	i := int(math.Max(score, 0) * float64(len(c.Bins)))
+	return min(i, len(c.Bins)-1)
+}
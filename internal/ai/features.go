@@ -0,0 +1,198 @@
+package ai
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// Features extracted from a set of alerts
+const (
+	FeatureDurationSeconds   = "duration_seconds"
+	FeatureAlertCount        = "alert_count"
+	FeatureCriticalCount     = "critical_count"
+	FeatureWarningCount      = "warning_count"
+	FeatureHostCount         = "host_count"
+	FeatureResourceTypeCount = "resource_type_count"
+	FeatureHasCritical       = "has_critical"
+	FeatureHasWarning        = "has_warning"
+	FeatureHourOfDay         = "hour_of_day"
+	FeatureDayOfWeek         = "day_of_week"
+	FeatureAlerts1Min        = "alerts_1_min"
+	FeatureAlerts5Min        = "alerts_5_min"
+	FeatureAlerts15Min       = "alerts_15_min"
+	FeatureMaxValue          = "max_value"
+	FeatureMinValue          = "min_value"
+)
+
+// ResourceCountFeature is the feature counting the alerts on a resource type
+func ResourceCountFeature(resource domain.ResourceType) string {
+	return fmt.Sprintf("resource_%s_count", resource)
+}
+
+// FeatureVector holds the numeric features of a set of alerts by name. Missing features
+// are 0; flags are 1 when set.
+type FeatureVector map[string]float64
+
+// Get returns the value of a feature
+func (v FeatureVector) Get(name string) float64 {
+	return v[name]
+}
+
+// Names returns the names of the features, sorted
+func (v FeatureVector) Names() []string {
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FeatureImportance is how much a feature moved a prediction
+type FeatureImportance struct {
+	Feature      string
+	Value        float64
+	Contribution float64 // Change of the prediction caused by the feature's value
+}
+
+// featureBaselines are the feature values that add nothing to a prediction; it is 0 for
+// the features not listed
+var featureBaselines = FeatureVector{
+	FeatureHostCount:         1,
+	FeatureResourceTypeCount: 1,
+}
+
+// Importance attributes a prediction to the features by occlusion: the contribution of a
+// feature is how much the prediction changes when the feature is reset to its baseline.
+// Predictions are capped, so contributions need not add up to the prediction. Features
+// without a contribution are left out; the rest are sorted by the size of their
+// contribution.
+func Importance(predict func(FeatureVector) float64, features FeatureVector) []FeatureImportance {
+	prediction := predict(features)
+
+	importance := []FeatureImportance{}
+	occluded := make(FeatureVector, len(features))
+	for name, value := range features {
+		occluded[name] = value
+	}
+	for _, name := range features.Names() {
+		value := features[name]
+		occluded[name] = featureBaselines[name]
+		contribution := prediction - predict(occluded)
+		occluded[name] = value
+
+		if math.Abs(contribution) > 1e-9 {
+			importance = append(importance, FeatureImportance{Feature: name, Value: value, Contribution: contribution})
+		}
+	}
+	sort.SliceStable(importance, func(i, j int) bool {
+		return math.Abs(importance[i].Contribution) > math.Abs(importance[j].Contribution)
+	})
+	return importance
+}
+
+// FeatureExtractor converts alerts to ML features
+type FeatureExtractor struct{}
+
+// NewFeatureExtractor creates a new feature extractor
+func NewFeatureExtractor() *FeatureExtractor {
+	return &FeatureExtractor{}
+}
+
+// ExtractFeatures converts alerts, ordered by time, to a feature vector for ML
+func (fe *FeatureExtractor) ExtractFeatures(alerts []domain.Alert) FeatureVector {
+	features := FeatureVector{}
+
+	if len(alerts) == 0 {
+		return features
+	}
+
+	// Time-based features
+	duration := alerts[len(alerts)-1].OccurredAt.Sub(alerts[0].OccurredAt)
+	features[FeatureDurationSeconds] = duration.Seconds()
+
+	// Alert count features
+	features[FeatureAlertCount] = float64(len(alerts))
+
+	criticalCount := 0
+	warningCount := 0
+	resourceTypes := make(map[domain.ResourceType]int)
+	hosts := make(map[string]int)
+
+	for _, alert := range alerts {
+		if alert.Status == domain.StatusCritical {
+			criticalCount++
+		} else if alert.Status == domain.StatusWarning {
+			warningCount++
+		}
+		resourceTypes[alert.ResourceType]++
+		hosts[alert.Host]++
+	}
+
+	features[FeatureCriticalCount] = float64(criticalCount)
+	features[FeatureWarningCount] = float64(warningCount)
+	features[FeatureHostCount] = float64(len(hosts))
+	features[FeatureResourceTypeCount] = float64(len(resourceTypes))
+
+	// Resource type features
+	for rt, count := range resourceTypes {
+		features[ResourceCountFeature(rt)] = float64(count)
+	}
+
+	// Severity features
+	if criticalCount > 0 {
+		features[FeatureHasCritical] = 1
+	}
+	if warningCount > 0 {
+		features[FeatureHasWarning] = 1
+	}
+
+	// Temporal features
+	features[FeatureHourOfDay] = float64(alerts[0].OccurredAt.Hour())
+	features[FeatureDayOfWeek] = float64(alerts[0].OccurredAt.Weekday())
+
+	// Pattern features
+	if len(alerts) > 1 {
+		features[FeatureAlerts1Min] = float64(fe.countAlertsInWindow(alerts, time.Minute))
+		features[FeatureAlerts5Min] = float64(fe.countAlertsInWindow(alerts, 5*time.Minute))
+		features[FeatureAlerts15Min] = float64(fe.countAlertsInWindow(alerts, 15*time.Minute))
+	}
+
+	// Value-based features
+	maxValue := 0.0
+	minValue := math.MaxFloat64
+	for _, alert := range alerts {
+		if alert.Value > maxValue {
+			maxValue = alert.Value
+		}
+		if alert.Value < minValue {
+			minValue = alert.Value
+		}
+	}
+	features[FeatureMaxValue] = maxValue
+	features[FeatureMinValue] = minValue
+
+	return features
+}
+
+func (fe *FeatureExtractor) countAlertsInWindow(alerts []domain.Alert, window time.Duration) int {
+	if len(alerts) == 0 {
+		return 0
+	}
+
+	count := 0
+	start := alerts[0].OccurredAt
+	end := start.Add(window)
+
+	for _, alert := range alerts {
+		if alert.OccurredAt.After(start) && alert.OccurredAt.Before(end) {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,96 @@
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func TestFeatureExtractor_ExtractFeatures(t *testing.T) {
+	start := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC) // Monday
+	alerts := []domain.Alert{
+		{Host: "db-01", ResourceType: domain.ResourceMemory, Status: domain.StatusWarning, Value: 85, OccurredAt: start},
+		{Host: "db-01", ResourceType: domain.ResourceMemory, Status: domain.StatusCritical, Value: 97.5, OccurredAt: start.Add(30 * time.Second)},
+		{Host: "web-01", ResourceType: domain.ResourceCPU, Status: domain.StatusCritical, Value: 92, OccurredAt: start.Add(3 * time.Minute)},
+	}
+
+	features := NewFeatureExtractor().ExtractFeatures(alerts)
+	expected := FeatureVector{
+		FeatureDurationSeconds:                      180,
+		FeatureAlertCount:                           3,
+		FeatureCriticalCount:                        2,
+		FeatureWarningCount:                         1,
+		FeatureHostCount:                            2,
+		FeatureResourceTypeCount:                    2,
+		ResourceCountFeature(domain.ResourceMemory): 2,
+		ResourceCountFeature(domain.ResourceCPU):    1,
+		FeatureHasCritical:                          1,
+		FeatureHasWarning:                           1,
+		FeatureHourOfDay:                            10,
+		FeatureDayOfWeek:                            1,
+		FeatureAlerts1Min:                           1, // The window excludes the first alert
+		FeatureAlerts5Min:                           2,
+		FeatureAlerts15Min:                          2,
+		FeatureMaxValue:                             97.5,
+		FeatureMinValue:                             85,
+	}
+	if len(features) != len(expected) {
+		t.Errorf("expected features %v, got %v", expected.Names(), features.Names())
+	}
+	for name, value := range expected {
+		if features.Get(name) != value {
+			t.Errorf("expected %s = %v, got %v", name, value, features.Get(name))
+		}
+	}
+
+	if pattern := NewPatternMatcher().IdentifyPattern(features); pattern != "burst" {
+		t.Errorf("expected a burst at 60 alerts an hour, got %s", pattern)
+	}
+	if pattern := NewPatternMatcher().IdentifyPattern(NewFeatureExtractor().ExtractFeatures(alerts[:1])); pattern != "single" {
+		t.Errorf("expected a single alert pattern, got %s", pattern)
+	}
+}
+
+func TestImportance(t *testing.T) {
+	classifier := NewIncidentClassifier()
+	features := FeatureVector{
+		FeatureAlertCount:        2,
+		FeatureCriticalCount:     1,
+		FeatureHostCount:         2,
+		FeatureResourceTypeCount: 1,
+		FeatureHourOfDay:         10,
+	}
+
+	// 0.2 for the alerts, 0.2 for the critical one and 0.15 for the second host
+	importance := Importance(classifier.PredictImpact, features)
+	if len(importance) != 3 {
+		t.Fatalf("expected 3 contributing features, got %+v", importance)
+	}
+	expected := []struct {
+		feature      string
+		contribution float64
+	}{
+		{FeatureAlertCount, 0.2},
+		{FeatureCriticalCount, 0.2},
+		{FeatureHostCount, 0.15},
+	}
+	for i, e := range expected {
+		if importance[i].Feature != e.feature || importance[i].Value != features[e.feature] ||
+			!approx(importance[i].Contribution, e.contribution) {
+			t.Errorf("expected %s to contribute %.2f at position %d, got %+v", e.feature, e.contribution, i, importance)
+		}
+	}
+
+	// Capped predictions only credit what the feature adds above the cap
+	features[FeatureCriticalCount] = 6
+	for _, factor := range Importance(classifier.PredictImpact, features) {
+		if factor.Feature == FeatureAlertCount {
+			t.Errorf("expected no contribution from the alert count beyond the cap, got %+v", factor)
+		}
+	}
+}
+
+func approx(a, b float64) bool {
+	return a-b < 1e-9 && b-a < 1e-9
+}
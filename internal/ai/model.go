@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"sync"
 	"time"
 
+	"incident-teller/internal/calendar"
 	"incident-teller/internal/domain"
 )
 
@@ -20,11 +22,12 @@ type AIModel interface {
 // RootCausePrediction uses ML to predict root cause with confidence
 type RootCausePrediction struct {
 	PrimaryCause      *domain.Alert
-	Confidence        float64 // 0.0-1.0
+	Confidence        float64 // 0.0-1.0, calibrated when the model has a calibration
+	RawConfidence     float64 // Score of the primary cause before calibration
 	AlternativeCauses []*domain.Alert
 	Reasoning         string
 	PatternType       string // "cascade", "spike", "gradual", "sudden"
-	MLFeatures        []string
+	MLFeatures        FeatureVector
 	ModelVersion      string
 }
 
@@ -35,7 +38,9 @@ type BlastRadiusPrediction struct {
 	CascadeProbability float64
 	DurationPredicted  time.Duration
 	BusinessImpact     string
-	RiskLevel          string // "low", "medium", "high", "critical"
+	RiskLevel          string              // "low", "medium", "high", "critical"
+	ImpactFactors      []FeatureImportance // Features driving the impact score
+	CascadeFactors     []FeatureImportance // Features driving the feature-based cascade probability
 }
 
 // PatternAnalysis identifies temporal and correlation patterns
@@ -49,11 +54,31 @@ type PatternAnalysis struct {
 	PredictedNext     time.Time
 }
 
+// PropagationModel supplies propagation patterns learned from alert history
+type PropagationModel interface {
+	// PatternsFrom returns the learned patterns for an issue on the resource spreading
+	// to other resources of the alert's host
+	PatternsFrom(alert domain.Alert, from domain.ResourceType) []domain.PropagationPattern
+}
+
+// AnomalySource scores how anomalous a set of alerts is against observed baselines
+type AnomalySource interface {
+	// AnomalyScore returns 0.0-1.0; 0 when none of the alerts is part of an anomaly
+	AnomalyScore(alerts []domain.Alert) float64
+}
+
 // LocalAIModel implements AI with ML algorithms
 type LocalAIModel struct {
 	featureExtractor *FeatureExtractor
 	patternMatcher   *PatternMatcher
 	classifier       *IncidentClassifier
+	propagation      PropagationModel
+	anomalies        AnomalySource
+	calendar         *calendar.Calendar
+	weights          ModelWeights
+
+	calibrationMu sync.RWMutex
+	calibration   *Calibration
 }
 
 // NewLocalAIModel creates a new AI model instance
@@ -62,9 +87,49 @@ func NewLocalAIModel() *LocalAIModel {
 		featureExtractor: NewFeatureExtractor(),
 		patternMatcher:   NewPatternMatcher(),
 		classifier:       NewIncidentClassifier(),
+		weights:          DefaultWeights(),
 	}
 }
 
+// SetWeights replaces the built-in root cause weights with those of another model version
+func (ai *LocalAIModel) SetWeights(weights ModelWeights) {
+	ai.weights = weights
+}
+
+// Version returns the version of the root cause weights in use
+func (ai *LocalAIModel) Version() string {
+	return ai.weights.Version
+}
+
+// Weights returns the root cause weights in use
+func (ai *LocalAIModel) Weights() ModelWeights {
+	return ai.weights
+}
+
+// SetCalibration maps raw root cause scores to calibrated confidences; nil removes the
+// calibration. It is safe to call while predictions run.
+func (ai *LocalAIModel) SetCalibration(calibration *Calibration) {
+	ai.calibrationMu.Lock()
+	defer ai.calibrationMu.Unlock()
+	ai.calibration = calibration
+}
+
+// SetPropagationModel blends learned propagation patterns into cascade probability predictions
+func (ai *LocalAIModel) SetPropagationModel(model PropagationModel) {
+	ai.propagation = model
+}
+
+// SetAnomalySource boosts the pattern anomaly score with detected rate bursts and novel alerts
+func (ai *LocalAIModel) SetAnomalySource(source AnomalySource) {
+	ai.anomalies = source
+}
+
+// SetCalendar makes blast radius risk levels and business impact aware of business
+// hours and peak traffic windows
+func (ai *LocalAIModel) SetCalendar(cal *calendar.Calendar) {
+	ai.calendar = cal
+}
+
 // PredictRootCause uses ML algorithms to predict root cause
 func (ai *LocalAIModel) PredictRootCause(ctx context.Context, alerts []domain.Alert) (RootCausePrediction, error) {
 	if len(alerts) == 0 {
@@ -88,26 +153,32 @@ func (ai *LocalAIModel) PredictRootCause(ctx context.Context, alerts []domain.Al
 			Confidence:        0.0,
 			AlternativeCauses: []*domain.Alert{},
 			Reasoning:         "All alerts are resolved - no active root cause detected",
-			PatternType:       ai.patternMatcher.IdentifyPattern(alerts, features),
+			PatternType:       ai.patternMatcher.IdentifyPattern(features),
 			MLFeatures:        features,
-			ModelVersion:      "1.0.0",
+			ModelVersion:      ai.weights.Version,
 		}, nil
 	}
 
+	rawConfidence := confidence
+	ai.calibrationMu.RLock()
+	confidence = ai.calibration.Apply(rawConfidence)
+	ai.calibrationMu.RUnlock()
+
 	// Generate reasoning
 	reasoning := ai.generateReasoning(bestCandidate, features, confidence)
 
 	// Identify pattern type
-	patternType := ai.patternMatcher.IdentifyPattern(alerts, features)
+	patternType := ai.patternMatcher.IdentifyPattern(features)
 
 	return RootCausePrediction{
 		PrimaryCause:      bestCandidate,
 		Confidence:        confidence,
+		RawConfidence:     rawConfidence,
 		AlternativeCauses: ai.getAlternativeCauses(candidates, scores, bestCandidate),
 		Reasoning:         reasoning,
 		PatternType:       patternType,
 		MLFeatures:        features,
-		ModelVersion:      "1.0.0",
+		ModelVersion:      ai.weights.Version,
 	}, nil
 }
 
@@ -118,21 +189,26 @@ func (ai *LocalAIModel) PredictBlastRadius(ctx context.Context, alerts []domain.
 	}
 
 	features := ai.featureExtractor.ExtractFeatures(alerts)
+	prediction := ai.blastRadius(alerts, features, ai.classifier.Predict(features))
+	prediction.ImpactFactors = Importance(ai.classifier.PredictImpact, features)
+	prediction.CascadeFactors = Importance(ai.classifier.PredictCascadeProbability, features)
+	return prediction, nil
+}
 
-	// Predict impact using trained model
-	impactScore := ai.classifier.PredictImpact(features)
-
-	// Predict cascade probability
-	cascadeProb := ai.classifier.PredictCascadeProbability(features)
-
-	// Estimate duration
-	duration := ai.classifier.PredictDuration(features)
-
-	// Determine business impact
-	businessImpact := ai.classifyBusinessImpact(impactScore, cascadeProb)
+// blastRadius completes predicted scores of alerts into a blast radius prediction
+func (ai *LocalAIModel) blastRadius(alerts []domain.Alert, features FeatureVector, scores BlastRadiusScores) BlastRadiusPrediction {
+	impactScore := scores.ImpactScore
+	cascadeProb := scores.CascadeProbability
+	if learned, ok := ai.learnedCascadeProbability(alerts); ok {
+		// Observed history is weighted above the feature heuristics
+		cascadeProb = cascadeProb*0.4 + learned*0.6
+	}
+	duration := scores.Duration
 
-	// Determine risk level
-	riskLevel := ai.determineRiskLevel(impactScore, cascadeProb, duration)
+	// Determine business impact and risk level at the time the incident started
+	startedAt := earliestAlert(alerts)
+	businessImpact := ai.calendar.DescribeImpact(ai.classifyBusinessImpact(impactScore, cascadeProb), startedAt)
+	riskLevel := ai.calendar.AdjustRisk(ai.determineRiskLevel(impactScore, cascadeProb, duration), startedAt)
 
 	// Identify affected services
 	affectedServices := ai.identifyAffectedServices(alerts, features)
@@ -144,7 +220,7 @@ func (ai *LocalAIModel) PredictBlastRadius(ctx context.Context, alerts []domain.
 		DurationPredicted:  duration,
 		BusinessImpact:     businessImpact,
 		RiskLevel:          riskLevel,
-	}, nil
+	}
 }
 
 // AnalyzePatterns identifies temporal patterns and correlations
@@ -156,7 +232,7 @@ func (ai *LocalAIModel) AnalyzePatterns(ctx context.Context, alerts []domain.Ale
 	features := ai.featureExtractor.ExtractFeatures(alerts)
 
 	// Identify pattern type
-	patternType := ai.patternMatcher.IdentifyPattern(alerts, features)
+	patternType := ai.patternMatcher.IdentifyPattern(features)
 
 	// Calculate confidence
 	confidence := ai.calculatePatternConfidence(alerts, patternType)
@@ -169,6 +245,9 @@ func (ai *LocalAIModel) AnalyzePatterns(ctx context.Context, alerts []domain.Ale
 
 	// Calculate anomaly score
 	anomalyScore := ai.calculateAnomalyScore(features)
+	if ai.anomalies != nil {
+		anomalyScore = math.Min(anomalyScore+ai.anomalies.AnomalyScore(alerts), 1.0)
+	}
 
 	// Build correlation matrix
 	correlationMatrix := ai.buildCorrelationMatrix(alerts)
@@ -187,94 +266,6 @@ func (ai *LocalAIModel) AnalyzePatterns(ctx context.Context, alerts []domain.Ale
 	}, nil
 }
 
-// FeatureExtractor converts alerts to ML features
-type FeatureExtractor struct{}
-
-// NewFeatureExtractor creates a new feature extractor
-func NewFeatureExtractor() *FeatureExtractor {
-	return &FeatureExtractor{}
-}
-
-// ExtractFeatures converts alerts to feature vector for ML
-func (fe *FeatureExtractor) ExtractFeatures(alerts []domain.Alert) []string {
-	features := []string{}
-
-	if len(alerts) == 0 {
-		return features
-	}
-
-	// Time-based features
-	duration := alerts[len(alerts)-1].OccurredAt.Sub(alerts[0].OccurredAt)
-	features = append(features, fmt.Sprintf("duration_seconds:%.0f", duration.Seconds()))
-
-	// Alert count features
-	features = append(features, fmt.Sprintf("alert_count:%d", len(alerts)))
-
-	criticalCount := 0
-	warningCount := 0
-	resourceTypes := make(map[domain.ResourceType]int)
-	hosts := make(map[string]int)
-
-	for _, alert := range alerts {
-		if alert.Status == domain.StatusCritical {
-			criticalCount++
-		} else if alert.Status == domain.StatusWarning {
-			warningCount++
-		}
-		resourceTypes[alert.ResourceType]++
-		hosts[alert.Host]++
-	}
-
-	features = append(features, fmt.Sprintf("critical_count:%d", criticalCount))
-	features = append(features, fmt.Sprintf("warning_count:%d", warningCount))
-	features = append(features, fmt.Sprintf("host_count:%d", len(hosts)))
-	features = append(features, fmt.Sprintf("resource_type_count:%d", len(resourceTypes)))
-
-	// Resource type features
-	for rt, count := range resourceTypes {
-		features = append(features, fmt.Sprintf("resource_%s_count:%d", rt, count))
-	}
-
-	// Severity features
-	if criticalCount > 0 {
-		features = append(features, "has_critical:true")
-	}
-	if warningCount > 0 {
-		features = append(features, "has_warning:true")
-	}
-
-	// Temporal features
-	hourOfDay := alerts[0].OccurredAt.Hour()
-	features = append(features, fmt.Sprintf("hour_of_day:%d", hourOfDay))
-	dayOfWeek := int(alerts[0].OccurredAt.Weekday())
-	features = append(features, fmt.Sprintf("day_of_week:%d", dayOfWeek))
-
-	// Pattern features
-	if len(alerts) > 1 {
-		timeWindows := []time.Duration{1 * time.Minute, 5 * time.Minute, 15 * time.Minute}
-		for _, window := range timeWindows {
-			count := fe.countAlertsInWindow(alerts, window)
-			features = append(features, fmt.Sprintf("alerts_%.0f_min:%d", window.Minutes(), count))
-		}
-	}
-
-	// Value-based features
-	maxValue := 0.0
-	minValue := math.MaxFloat64
-	for _, alert := range alerts {
-		if alert.Value > maxValue {
-			maxValue = alert.Value
-		}
-		if alert.Value < minValue {
-			minValue = alert.Value
-		}
-	}
-	features = append(features, fmt.Sprintf("max_value:%.2f", maxValue))
-	features = append(features, fmt.Sprintf("min_value:%.2f", minValue))
-
-	return features
-}
-
 // PatternMatcher identifies incident patterns
 type PatternMatcher struct{}
 
@@ -284,14 +275,15 @@ func NewPatternMatcher() *PatternMatcher {
 }
 
 // IdentifyPattern classifies the incident pattern
-func (pm *PatternMatcher) IdentifyPattern(alerts []domain.Alert, features []string) string {
-	if len(alerts) < 2 {
+func (pm *PatternMatcher) IdentifyPattern(features FeatureVector) string {
+	alertCount := features[FeatureAlertCount]
+	if alertCount < 2 {
 		return "single"
 	}
 
 	// Analyze timing patterns
-	timeDiff := alerts[len(alerts)-1].OccurredAt.Sub(alerts[0].OccurredAt)
-	alertRate := float64(len(alerts)) / timeDiff.Hours()
+	timeDiff := time.Duration(features[FeatureDurationSeconds] * float64(time.Second))
+	alertRate := alertCount / timeDiff.Hours()
 
 	// Classify based on alert rate and spread
 	if alertRate > 10 {
@@ -300,7 +292,7 @@ func (pm *PatternMatcher) IdentifyPattern(alerts []domain.Alert, features []stri
 		return "cascade"
 	} else if timeDiff > 30*time.Minute {
 		return "gradual"
-	} else if timeDiff < 5*time.Minute && len(alerts) > 3 {
+	} else if timeDiff < 5*time.Minute && alertCount > 3 {
 		return "spike"
 	}
 
@@ -315,76 +307,92 @@ func NewIncidentClassifier() *IncidentClassifier {
 	return &IncidentClassifier{}
 }
 
+// BlastRadiusScores are the predictions a blast radius is derived from
+type BlastRadiusScores struct {
+	ImpactScore        float64 // 0.0-1.0
+	CascadeProbability float64 // 0.0-1.0
+	Duration           time.Duration
+}
+
+// Predict predicts the impact, cascade probability and duration of an incident
+func (ic *IncidentClassifier) Predict(features FeatureVector) BlastRadiusScores {
+	return BlastRadiusScores{
+		ImpactScore:        ic.PredictImpact(features),
+		CascadeProbability: ic.PredictCascadeProbability(features),
+		Duration:           ic.PredictDuration(features),
+	}
+}
+
 // PredictImpact predicts incident impact score (0.0-1.0)
-func (ic *IncidentClassifier) PredictImpact(features []string) float64 {
+func (ic *IncidentClassifier) PredictImpact(features FeatureVector) float64 {
 	score := 0.0
 
 	// Base score from alert count
-	alertCount := ic.extractFeatureValue(features, "alert_count:")
-	score += math.Min(float64(alertCount)/10.0, 0.3)
+	alertCount := features[FeatureAlertCount]
+	score += math.Min(alertCount/10.0, 0.3)
 
 	// Critical alerts increase impact
-	criticalCount := ic.extractFeatureValue(features, "critical_count:")
-	score += float64(criticalCount) * 0.2
+	criticalCount := features[FeatureCriticalCount]
+	score += criticalCount * 0.2
 
 	// Multiple hosts increase impact
-	hostCount := ic.extractFeatureValue(features, "host_count:")
-	score += math.Min(float64(hostCount-1)*0.15, 0.3)
+	hostCount := features[FeatureHostCount]
+	score += math.Min((hostCount-1)*0.15, 0.3)
 
 	// Multiple resource types increase impact
-	resourceCount := ic.extractFeatureValue(features, "resource_type_count:")
-	score += math.Min(float64(resourceCount-1)*0.1, 0.2)
+	resourceCount := features[FeatureResourceTypeCount]
+	score += math.Min((resourceCount-1)*0.1, 0.2)
 
 	// Cap at 1.0
 	return math.Min(score, 1.0)
 }
 
 // PredictCascadeProbability predicts likelihood of cascade
-func (ic *IncidentClassifier) PredictCascadeProbability(features []string) float64 {
+func (ic *IncidentClassifier) PredictCascadeProbability(features FeatureVector) float64 {
 	prob := 0.0
 
 	// Multiple resource types increase cascade probability
-	resourceCount := ic.extractFeatureValue(features, "resource_type_count:")
+	resourceCount := features[FeatureResourceTypeCount]
 	if resourceCount > 1 {
-		prob += 0.3 * math.Min(float64(resourceCount-1)/3.0, 1.0)
+		prob += 0.3 * math.Min((resourceCount-1)/3.0, 1.0)
 	}
 
 	// High alert rate indicates cascade
-	alerts5Min := ic.extractFeatureValue(features, "alerts_5_min:")
+	alerts5Min := features[FeatureAlerts5Min]
 	if alerts5Min > 5 {
 		prob += 0.3
 	}
 
 	// Critical alerts increase cascade probability
-	criticalCount := ic.extractFeatureValue(features, "critical_count:")
+	criticalCount := features[FeatureCriticalCount]
 	if criticalCount > 0 {
-		prob += 0.2 * math.Min(float64(criticalCount)/3.0, 1.0)
+		prob += 0.2 * math.Min(criticalCount/3.0, 1.0)
 	}
 
 	// Multiple hosts increase cascade probability
-	hostCount := ic.extractFeatureValue(features, "host_count:")
+	hostCount := features[FeatureHostCount]
 	if hostCount > 1 {
-		prob += 0.2 * math.Min(float64(hostCount-1)/2.0, 1.0)
+		prob += 0.2 * math.Min((hostCount-1)/2.0, 1.0)
 	}
 
 	return math.Min(prob, 1.0)
 }
 
 // PredictDuration estimates incident duration
-func (ic *IncidentClassifier) PredictDuration(features []string) time.Duration {
+func (ic *IncidentClassifier) PredictDuration(features FeatureVector) time.Duration {
 	baseDuration := 10 * time.Minute
 
 	// Adjust based on impact factors
-	criticalCount := ic.extractFeatureValue(features, "critical_count:")
+	criticalCount := features[FeatureCriticalCount]
 	baseDuration += time.Duration(criticalCount) * 15 * time.Minute
 
-	hostCount := ic.extractFeatureValue(features, "host_count:")
+	hostCount := features[FeatureHostCount]
 	baseDuration += time.Duration(hostCount-1) * 10 * time.Minute
 
-	resourceCount := ic.extractFeatureValue(features, "resource_type_count:")
+	resourceCount := features[FeatureResourceTypeCount]
 	baseDuration += time.Duration(resourceCount-1) * 5 * time.Minute
 
-	maxValue := ic.extractFeatureValueFloat(features, "max_value:")
+	maxValue := features[FeatureMaxValue]
 	if maxValue > 95 {
 		baseDuration += 20 * time.Minute
 	}
@@ -394,7 +402,47 @@ func (ic *IncidentClassifier) PredictDuration(features []string) time.Duration {
 
 // Helper methods
 
-func (ai *LocalAIModel) identifyRootCauseCandidates(alerts []domain.Alert, features []string) []*domain.Alert {
+// learnedCascadeProbability estimates the chance that the active issues spread to another
+// resource of their host, from learned propagation patterns. ok is false when no pattern
+// applies.
+func (ai *LocalAIModel) learnedCascadeProbability(alerts []domain.Alert) (float64, bool) {
+	if ai.propagation == nil {
+		return 0, false
+	}
+
+	// Latest state of every host/resource pair
+	type hostResource struct {
+		host     string
+		resource domain.ResourceType
+	}
+	latest := make(map[hostResource]domain.Alert)
+	for _, alert := range alerts {
+		key := hostResource{alert.Host, alert.ResourceType}
+		if current, ok := latest[key]; !ok || alert.OccurredAt.After(current.OccurredAt) {
+			latest[key] = alert
+		}
+	}
+
+	noCascade := 1.0
+	applied := false
+	for key, alert := range latest {
+		if alert.Status == domain.StatusClear {
+			continue
+		}
+		for _, pattern := range ai.propagation.PatternsFrom(alert, key.resource) {
+			// Resources already affected on the host are not a further cascade
+			if _, affected := latest[hostResource{key.host, pattern.To}]; affected {
+				continue
+			}
+			noCascade *= 1 - pattern.Probability
+			applied = true
+		}
+	}
+
+	return 1 - noCascade, applied
+}
+
+func (ai *LocalAIModel) identifyRootCauseCandidates(alerts []domain.Alert, features FeatureVector) []*domain.Alert {
 	candidates := []*domain.Alert{}
 
 	for i := range alerts {
@@ -407,25 +455,11 @@ func (ai *LocalAIModel) identifyRootCauseCandidates(alerts []domain.Alert, featu
 	return candidates
 }
 
-func (ai *LocalAIModel) scoreWithML(candidates []*domain.Alert, features []string) map[*domain.Alert]float64 {
+func (ai *LocalAIModel) scoreWithML(candidates []*domain.Alert, features FeatureVector) map[*domain.Alert]float64 {
 	scores := make(map[*domain.Alert]float64)
 
 	for _, candidate := range candidates {
-		score := 0.0
-
-		// Position-based scoring (earlier alerts get higher scores)
-		// This would normally use a trained model
-		if candidate.ResourceType == domain.ResourceMemory {
-			score += 0.3 // Memory issues often root causes
-		}
-		if candidate.Status == domain.StatusCritical {
-			score += 0.2
-		}
-		if candidate.Value > 90 {
-			score += 0.15
-		}
-
-		scores[candidate] = score
+		scores[candidate] = ai.weights.Score(*candidate)
 	}
 
 	return scores
@@ -478,7 +512,7 @@ func (ai *LocalAIModel) getAlternativeCauses(candidates []*domain.Alert, scores
 	return alternatives
 }
 
-func (ai *LocalAIModel) generateReasoning(alert *domain.Alert, features []string, confidence float64) string {
+func (ai *LocalAIModel) generateReasoning(alert *domain.Alert, features FeatureVector, confidence float64) string {
 	reasoning := fmt.Sprintf("ML analysis identifies %s alert on %s as root cause",
 		alert.ResourceType, alert.Chart)
 
@@ -493,50 +527,6 @@ func (ai *LocalAIModel) generateReasoning(alert *domain.Alert, features []string
 	return reasoning
 }
 
-func (ic *IncidentClassifier) extractFeatureValue(features []string, prefix string) int {
-	for _, feature := range features {
-		if len(feature) > len(prefix) && feature[:len(prefix)] == prefix {
-			var value int
-			_, err := fmt.Sscanf(feature[len(prefix):], "%d", &value)
-			if err == nil {
-				return value
-			}
-		}
-	}
-	return 0
-}
-
-func (ic *IncidentClassifier) extractFeatureValueFloat(features []string, prefix string) float64 {
-	for _, feature := range features {
-		if len(feature) > len(prefix) && feature[:len(prefix)] == prefix {
-			var value float64
-			_, err := fmt.Sscanf(feature[len(prefix):], "%f", &value)
-			if err == nil {
-				return value
-			}
-		}
-	}
-	return 0.0
-}
-
-func (fe *FeatureExtractor) countAlertsInWindow(alerts []domain.Alert, window time.Duration) int {
-	if len(alerts) == 0 {
-		return 0
-	}
-
-	count := 0
-	start := alerts[0].OccurredAt
-	end := start.Add(window)
-
-	for _, alert := range alerts {
-		if alert.OccurredAt.After(start) && alert.OccurredAt.Before(end) {
-			count++
-		}
-	}
-
-	return count
-}
-
 func (ai *LocalAIModel) calculatePatternConfidence(alerts []domain.Alert, patternType string) float64 {
 	// Simple confidence calculation based on pattern strength
 	switch patternType {
@@ -580,6 +570,9 @@ func (ai *LocalAIModel) detectSeasonality(alerts []domain.Alert) bool {
 }
 
 func (ai *LocalAIModel) determineTrend(alerts []domain.Alert) string {
+	if trend, ok := sampledTrend(alerts); ok {
+		return trend
+	}
 	if len(alerts) < 3 {
 		return "stable"
 	}
@@ -608,21 +601,68 @@ func (ai *LocalAIModel) determineTrend(alerts []domain.Alert) string {
 	return "stable"
 }
 
-func (ai *LocalAIModel) calculateAnomalyScore(features []string) float64 {
+// sampledTrend is the direction the sampled charts of the alerts moved in: the change of
+// each series' least-squares line over its span relative to the series' mean, averaged
+// over the alerts. ok is false when no alert has three samples.
+func sampledTrend(alerts []domain.Alert) (trend string, ok bool) {
+	var total float64
+	var series int
+	for _, alert := range alerts {
+		samples := alert.Samples
+		if len(samples) < 3 {
+			continue
+		}
+
+		n := float64(len(samples))
+		start := samples[0].At
+		var sumX, sumY, sumXY, sumXX float64
+		for _, sample := range samples {
+			x := sample.At.Sub(start).Seconds()
+			sumX += x
+			sumY += sample.Value
+			sumXY += x * sample.Value
+			sumXX += x * x
+		}
+		denominator := n*sumXX - sumX*sumX
+		mean := math.Abs(sumY / n)
+		if denominator == 0 || mean == 0 {
+			continue
+		}
+
+		slope := (n*sumXY - sumX*sumY) / denominator
+		span := samples[len(samples)-1].At.Sub(start).Seconds()
+		total += slope * span / mean
+		series++
+	}
+	if series == 0 {
+		return "", false
+	}
+
+	switch change := total / float64(series); {
+	case change > 0.1:
+		return "increasing", true
+	case change < -0.1:
+		return "decreasing", true
+	default:
+		return "stable", true
+	}
+}
+
+func (ai *LocalAIModel) calculateAnomalyScore(features FeatureVector) float64 {
 	// Simple anomaly detection based on feature deviation
 	score := 0.0
 
-	alertCount := ai.extractFeatureValue(features, "alert_count:")
+	alertCount := features[FeatureAlertCount]
 	if alertCount > 10 {
 		score += 0.3
 	}
 
-	criticalCount := ai.extractFeatureValue(features, "critical_count:")
+	criticalCount := features[FeatureCriticalCount]
 	if criticalCount > 3 {
 		score += 0.3
 	}
 
-	alerts5Min := ai.extractFeatureValue(features, "alerts_5_min:")
+	alerts5Min := features[FeatureAlerts5Min]
 	if alerts5Min > 5 {
 		score += 0.4
 	}
@@ -745,19 +785,6 @@ func (ai *LocalAIModel) determineRiskLevel(impactScore, cascadeProb float64, dur
 	}
 }
 
-func (ai *LocalAIModel) extractFeatureValue(features []string, prefix string) int {
-	for _, feature := range features {
-		if len(feature) > len(prefix) && feature[:len(prefix)] == prefix {
-			var value int
-			_, err := fmt.Sscanf(feature[len(prefix):], "%d", &value)
-			if err == nil {
-				return value
-			}
-		}
-	}
-	return 0
-}
-
 func (ai *LocalAIModel) classifyBusinessImpact(impactScore, cascadeProb float64) string {
 	combinedScore := (impactScore * 0.6) + (cascadeProb * 0.4)
 
@@ -772,7 +799,18 @@ func (ai *LocalAIModel) classifyBusinessImpact(impactScore, cascadeProb float64)
 	}
 }
 
-func (ai *LocalAIModel) identifyAffectedServices(alerts []domain.Alert, features []string) []string {
+// earliestAlert returns when the first of the alerts occurred
+func earliestAlert(alerts []domain.Alert) time.Time {
+	earliest := alerts[0].OccurredAt
+	for _, alert := range alerts[1:] {
+		if alert.OccurredAt.Before(earliest) {
+			earliest = alert.OccurredAt
+		}
+	}
+	return earliest
+}
+
+func (ai *LocalAIModel) identifyAffectedServices(alerts []domain.Alert, features FeatureVector) []string {
 	services := make(map[string]bool)
 
 	// Extract service information from alerts
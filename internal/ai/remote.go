@@ -0,0 +1,157 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// RemoteModel predicts the impact, cascade probability and duration of incidents with an
+// external scoring service, e.g. a model server running an ONNX model, which is sent the
+// feature vector of the alerts. Root causes and patterns come from the local model, which
+// also answers when the service fails.
+//
+// The service receives {"features": {"alert_count": 3, ...}} and answers
+// {"impact_score": 0.7, "cascade_probability": 0.4, "duration_seconds": 1800}.
+type RemoteModel struct {
+	local    *LocalAIModel
+	endpoint string
+	token    string
+	client   *http.Client
+	timeout  time.Duration // Per attempt
+	retries  int
+	backoff  time.Duration
+}
+
+// NewRemoteModel creates a model scoring blast radii at endpoint, with a bearer token if
+// one is set. Each attempt is limited to timeout; failed attempts are retried up to
+// retries times before falling back to local.
+func NewRemoteModel(local *LocalAIModel, endpoint, token string, timeout time.Duration, retries int) *RemoteModel {
+	return &RemoteModel{
+		local:    local,
+		endpoint: endpoint,
+		token:    token,
+		client:   &http.Client{},
+		timeout:  timeout,
+		retries:  retries,
+		backoff:  200 * time.Millisecond,
+	}
+}
+
+type remoteScoreRequest struct {
+	Features FeatureVector `json:"features"`
+}
+
+type remoteScoreResponse struct {
+	ImpactScore        *float64 `json:"impact_score"`
+	CascadeProbability *float64 `json:"cascade_probability"`
+	DurationSeconds    *float64 `json:"duration_seconds"`
+}
+
+// PredictRootCause predicts the root cause with the local model
+func (m *RemoteModel) PredictRootCause(ctx context.Context, alerts []domain.Alert) (RootCausePrediction, error) {
+	return m.local.PredictRootCause(ctx, alerts)
+}
+
+// AnalyzePatterns analyzes patterns with the local model
+func (m *RemoteModel) AnalyzePatterns(ctx context.Context, alerts []domain.Alert) (PatternAnalysis, error) {
+	return m.local.AnalyzePatterns(ctx, alerts)
+}
+
+// PredictBlastRadius scores the alerts with the scoring service, or with the local model
+// if the service can't be reached
+func (m *RemoteModel) PredictBlastRadius(ctx context.Context, alerts []domain.Alert) (BlastRadiusPrediction, error) {
+	if len(alerts) == 0 {
+		return BlastRadiusPrediction{}, fmt.Errorf("no alerts to analyze")
+	}
+
+	features := m.local.featureExtractor.ExtractFeatures(alerts)
+	scores, err := m.score(ctx, features)
+	if err != nil {
+		log.Printf("⚠️  Remote scoring failed, using the local model: %v", err)
+		return m.local.PredictBlastRadius(ctx, alerts)
+	}
+	return m.local.blastRadius(alerts, features, scores), nil
+}
+
+// score sends the features to the scoring service, retrying failed attempts
+func (m *RemoteModel) score(ctx context.Context, features FeatureVector) (BlastRadiusScores, error) {
+	body, err := json.Marshal(remoteScoreRequest{Features: features})
+	if err != nil {
+		return BlastRadiusScores{}, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= m.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return BlastRadiusScores{}, ctx.Err()
+			case <-time.After(m.backoff << (attempt - 1)):
+			}
+		}
+
+		scores, retry, err := m.scoreOnce(ctx, body)
+		if err == nil {
+			return scores, nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return BlastRadiusScores{}, lastErr
+}
+
+// scoreOnce makes one scoring request; retry tells whether a failure may be temporary
+func (m *RemoteModel) scoreOnce(ctx context.Context, body []byte) (BlastRadiusScores, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return BlastRadiusScores{}, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.token != "" {
+		req.Header.Set("Authorization", "Bearer "+m.token)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return BlastRadiusScores{}, true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return BlastRadiusScores{}, retry, fmt.Errorf("scoring service returned %d: %s", resp.StatusCode, bytes.TrimSpace(message))
+	}
+
+	var result remoteScoreResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return BlastRadiusScores{}, false, fmt.Errorf("invalid scoring response: %w", err)
+	}
+	if result.ImpactScore == nil || result.CascadeProbability == nil || result.DurationSeconds == nil {
+		return BlastRadiusScores{}, false, errors.New("scoring response lacks impact_score, cascade_probability or duration_seconds")
+	}
+	return BlastRadiusScores{
+		ImpactScore:        clamp01(*result.ImpactScore),
+		CascadeProbability: clamp01(*result.CascadeProbability),
+		Duration:           time.Duration(math.Max(*result.DurationSeconds, 0) * float64(time.Second)),
+	}, false, nil
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(v, 1))
+}
@@ -0,0 +1,76 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func remoteAlerts() []domain.Alert {
+	start := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	return []domain.Alert{
+		{ID: "a1", Host: "db-01", Chart: "mysql.queries", ResourceType: domain.ResourceMemory, Status: domain.StatusCritical, OccurredAt: start},
+		{ID: "a2", Host: "db-01", Chart: "disk.io", ResourceType: domain.ResourceDisk, Status: domain.StatusWarning, OccurredAt: start.Add(time.Minute)},
+	}
+}
+
+func TestRemoteModel_PredictBlastRadius(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first attempt fails and is retried
+		if requests.Add(1) == 1 {
+			http.Error(w, "warming up", http.StatusServiceUnavailable)
+			return
+		}
+		var req remoteScoreRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Features[FeatureAlertCount] != 2 ||
+			r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"impact_score": 0.9, "cascade_probability": 1.4, "duration_seconds": 5400}`))
+	}))
+	defer server.Close()
+
+	model := NewRemoteModel(NewLocalAIModel(), server.URL, "secret", time.Second, 2)
+	model.backoff = time.Millisecond
+	prediction, err := model.PredictBlastRadius(context.Background(), remoteAlerts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests.Load() != 2 || prediction.ImpactScore != 0.9 || prediction.CascadeProbability != 1 ||
+		prediction.DurationPredicted != 90*time.Minute || prediction.RiskLevel == "" || len(prediction.AffectedServices) == 0 {
+		t.Errorf("expected the remote scores after a retry, got %d requests and %+v", requests.Load(), prediction)
+	}
+}
+
+func TestRemoteModel_FallsBackToLocal(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "unknown model", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	local := NewLocalAIModel()
+	model := NewRemoteModel(local, server.URL, "", time.Second, 3)
+	model.backoff = time.Millisecond
+
+	prediction, err := model.PredictBlastRadius(context.Background(), remoteAlerts())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := local.PredictBlastRadius(context.Background(), remoteAlerts())
+	if requests.Load() != 1 {
+		t.Errorf("expected client errors not to be retried, got %d requests", requests.Load())
+	}
+	if prediction.ImpactScore != expected.ImpactScore || prediction.DurationPredicted != expected.DurationPredicted {
+		t.Errorf("expected the local prediction %+v, got %+v", expected, prediction)
+	}
+}
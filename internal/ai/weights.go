@@ -0,0 +1,104 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"incident-teller/internal/domain"
+)
+
+// DefaultModelVersion is the version of the built-in root cause weights
+const DefaultModelVersion = "1.0.0"
+
+// ModelWeights are the scores a version of the local model gives root cause candidates.
+// A candidate scores the sum of the weights it matches.
+type ModelWeights struct {
+	Version            string                          `yaml:"version" json:"version"`
+	ResourceScores     map[domain.ResourceType]float64 `yaml:"resource_scores" json:"resource_scores"`
+	CriticalScore      float64                         `yaml:"critical_score" json:"critical_score"`
+	HighValueScore     float64                         `yaml:"high_value_score" json:"high_value_score"`
+	HighValueThreshold float64                         `yaml:"high_value_threshold" json:"high_value_threshold"`
+}
+
+// DefaultWeights returns the built-in weights
+func DefaultWeights() ModelWeights {
+	return ModelWeights{
+		Version:            DefaultModelVersion,
+		ResourceScores:     map[domain.ResourceType]float64{domain.ResourceMemory: 0.3}, // Memory issues often root causes
+		CriticalScore:      0.2,
+		HighValueScore:     0.15,
+		HighValueThreshold: 90,
+	}
+}
+
+// Score returns the raw root cause score of an alert
+func (w ModelWeights) Score(alert domain.Alert) float64 {
+	score := w.ResourceScores[alert.ResourceType]
+	if alert.Status == domain.StatusCritical {
+		score += w.CriticalScore
+	}
+	if alert.Value > w.HighValueThreshold {
+		score += w.HighValueScore
+	}
+	return score
+}
+
+// LoadModelVersions reads the model versions in the YAML and JSON files of dir, one
+// version per file. The built-in weights are always included unless a file overrides
+// their version; a missing dir only yields the built-in weights. Versions are sorted.
+func LoadModelVersions(dir string) ([]ModelWeights, error) {
+	versions := map[string]ModelWeights{DefaultModelVersion: DefaultWeights()}
+	files := map[string]string{}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read model dir: %w", err)
+	}
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read model %s: %w", path, err)
+		}
+		// JSON is valid YAML
+		var weights ModelWeights
+		if err := yaml.Unmarshal(data, &weights); err != nil {
+			return nil, fmt.Errorf("failed to parse model %s: %w", path, err)
+		}
+		if weights.Version == "" {
+			return nil, fmt.Errorf("model %s has no version", path)
+		}
+		if other, ok := files[weights.Version]; ok {
+			return nil, fmt.Errorf("models %s and %s have the same version %s", other, path, weights.Version)
+		}
+		files[weights.Version] = path
+		versions[weights.Version] = weights
+	}
+
+	result := make([]ModelWeights, 0, len(versions))
+	for _, weights := range versions {
+		result = append(result, weights)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// FindModelVersion returns the weights of a version
+func FindModelVersion(versions []ModelWeights, version string) (ModelWeights, bool) {
+	for _, weights := range versions {
+		if weights.Version == version {
+			return weights, true
+		}
+	}
+	return ModelWeights{}, false
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"incident-teller/internal/observability"
+)
+
+// quietRoutes are polled by probes, scrapers and the dashboard's log tail; their access
+// logs are debug level
+var quietRoutes = map[string]bool{
+	"/healthz":            true,
+	"/readyz":             true,
+	"/api/health":         true,
+	"/api/logs":           true,
+	"/api/metrics/export": true,
+}
+
+// SetAccessLog enables or disables the access log; request metrics are always recorded
+func (h *Handler) SetAccessLog(enabled bool) {
+	h.accessLog = enabled
+}
+
+// accessRecorder remembers the status code and the bytes written by a handler
+type accessRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (a *accessRecorder) WriteHeader(code int) {
+	if a.status == 0 {
+		a.status = code
+	}
+	a.ResponseWriter.WriteHeader(code)
+}
+
+func (a *accessRecorder) Write(b []byte) (int, error) {
+	if a.status == 0 {
+		a.status = http.StatusOK
+	}
+	n, err := a.ResponseWriter.Write(b)
+	a.bytes += int64(n)
+	return n, err
+}
+
+// Flush keeps server-sent events streaming through the recorder
+func (a *accessRecorder) Flush() {
+	http.NewResponseController(a.ResponseWriter).Flush()
+}
+
+func (a *accessRecorder) Unwrap() http.ResponseWriter {
+	return a.ResponseWriter
+}
+
+// countingBody counts the bytes of the request body read by a handler
+type countingBody struct {
+	io.ReadCloser
+	bytes int64
+}
+
+func (c *countingBody) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.bytes += int64(n)
+	return n, err
+}
+
+// withAccessLog is a middleware that records the count, latency, status code and payload
+// sizes of requests per route pattern, e.g. /api/incidents/{id}, and logs each request.
+// It wraps every other middleware, so rate-limited and rejected requests are counted too.
+func (h *Handler) withAccessLog(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		_, route := mux.Handler(r)
+		if route == "" {
+			route = "unmatched"
+		}
+
+		body := &countingBody{ReadCloser: r.Body}
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = body
+		}
+		recorder := &accessRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		duration := time.Since(start)
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		labels := map[string]string{"route": route, "method": r.Method}
+		h.metrics.RecordDuration("http_request_duration_seconds", duration, labels)
+		h.metrics.RecordHistogram("http_request_size_bytes", float64(body.bytes), labels)
+		h.metrics.RecordHistogram("http_response_size_bytes", float64(recorder.bytes), labels)
+		h.metrics.IncCounter("http_requests_total", map[string]string{
+			"route": route, "method": r.Method, "status": strconv.Itoa(status),
+		})
+
+		if !h.accessLog {
+			return
+		}
+		log := h.logger.Info
+		if quietRoutes[route] && status < http.StatusBadRequest {
+			log = h.logger.Debug
+		}
+		log("HTTP request",
+			observability.String("method", r.Method),
+			observability.String("path", r.URL.Path),
+			observability.String("route", route),
+			observability.Int("status", status),
+			observability.Duration("duration", duration),
+			observability.Int64("request_bytes", body.bytes),
+			observability.Int64("response_bytes", recorder.bytes),
+			observability.String("remote_addr", h.clientAddr(r)))
+	})
+}
@@ -0,0 +1,286 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/ports"
+)
+
+// IncidentActionItemRequest creates or updates an action item. Creating one needs a
+// description; updating changes only the given fields.
+type IncidentActionItemRequest struct {
+	Description *string `json:"description,omitempty"`
+	Owner       *string `json:"owner,omitempty"`
+	DueAt       *string `json:"due_at,omitempty"` // RFC3339, or YYYY-MM-DD for the end of that day; "" clears it
+	Status      *string `json:"status,omitempty"` // open (default), in_progress, done or cancelled
+}
+
+// IncidentActionItemResponse is a follow-up of an incident
+type IncidentActionItemResponse struct {
+	ID                string     `json:"id"`
+	IncidentID        string     `json:"incident_id"`
+	Description       string     `json:"description"`
+	Owner             string     `json:"owner,omitempty"`
+	DueAt             *time.Time `json:"due_at,omitempty"`
+	Status            string     `json:"status"`
+	Overdue           bool       `json:"overdue"`
+	OverdueNotifiedAt *time.Time `json:"overdue_notified_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// IncidentActionItemListResponse lists the action items of an incident, oldest first
+type IncidentActionItemListResponse struct {
+	ActionItems []IncidentActionItemResponse `json:"action_items"`
+	Open        int                          `json:"open"`
+	Overdue     int                          `json:"overdue"`
+}
+
+// handleIncidentActionItems lists (GET) or creates (POST) the action items of an incident
+func (h *Handler) handleIncidentActionItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	store, incident, ok := h.actionItemIncident(w, r)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+
+	if r.Method == http.MethodGet {
+		items, err := store.GetActionItems(ctx, incident.ID)
+		if err != nil {
+			h.logger.Error("Failed to get action items",
+				observability.String("incident_id", incident.ID), observability.Error(err))
+			h.writeError(w, http.StatusInternalServerError, "Failed to get action items")
+			return
+		}
+		now := time.Now()
+		response := IncidentActionItemListResponse{ActionItems: make([]IncidentActionItemResponse, 0, len(items))}
+		for _, item := range items {
+			response.ActionItems = append(response.ActionItems, actionItemResponse(item, now))
+			if item.Status.IsOpen() {
+				response.Open++
+			}
+			if item.Overdue(now) {
+				response.Overdue++
+			}
+		}
+		h.writeJSON(w, http.StatusOK, response)
+		return
+	}
+
+	var req IncidentActionItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Description == nil || strings.TrimSpace(*req.Description) == "" {
+		h.writeError(w, http.StatusBadRequest, "description is required")
+		return
+	}
+	now := time.Now().UTC()
+	item := domain.ActionItem{
+		ID:         idgen.New(now),
+		IncidentID: incident.ID,
+		Status:     domain.ActionItemOpen,
+		CreatedAt:  now,
+	}
+	if msg := applyActionItemRequest(r, &item, req); msg != "" {
+		h.writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	item.UpdatedAt = now
+
+	if err := store.SaveActionItem(ctx, item); err != nil {
+		h.logger.Error("Failed to save action item",
+			observability.String("incident_id", incident.ID), observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to save action item")
+		return
+	}
+	auditChange(r, nil, actionItemFields(item))
+	h.writeJSON(w, http.StatusCreated, actionItemResponse(item, now))
+}
+
+// handleIncidentActionItem returns (GET), updates (PATCH) or deletes (DELETE) an action
+// item of an incident
+func (h *Handler) handleIncidentActionItem(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodPatch, http.MethodDelete:
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	store, incident, ok := h.actionItemIncident(w, r)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+
+	items, err := store.GetActionItems(ctx, incident.ID)
+	if err != nil {
+		h.logger.Error("Failed to get action items",
+			observability.String("incident_id", incident.ID), observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get action items")
+		return
+	}
+	var item *domain.ActionItem
+	for i := range items {
+		if items[i].ID == r.PathValue("action") {
+			item = &items[i]
+			break
+		}
+	}
+	if item == nil {
+		h.writeError(w, http.StatusNotFound, "Action item not found")
+		return
+	}
+	before := actionItemFields(*item)
+
+	switch r.Method {
+	case http.MethodGet:
+		h.writeJSON(w, http.StatusOK, actionItemResponse(*item, time.Now()))
+
+	case http.MethodPatch:
+		var req IncidentActionItemRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if msg := applyActionItemRequest(r, item, req); msg != "" {
+			h.writeError(w, http.StatusBadRequest, msg)
+			return
+		}
+		item.UpdatedAt = time.Now().UTC()
+		if err := store.SaveActionItem(ctx, *item); err != nil {
+			h.logger.Error("Failed to save action item",
+				observability.String("incident_id", incident.ID), observability.Error(err))
+			h.writeError(w, http.StatusInternalServerError, "Failed to save action item")
+			return
+		}
+		auditChange(r, before, actionItemFields(*item))
+		h.writeJSON(w, http.StatusOK, actionItemResponse(*item, time.Now()))
+
+	case http.MethodDelete:
+		if _, err := store.DeleteActionItem(ctx, item.ID); err != nil {
+			h.logger.Error("Failed to delete action item",
+				observability.String("incident_id", incident.ID), observability.Error(err))
+			h.writeError(w, http.StatusInternalServerError, "Failed to delete action item")
+			return
+		}
+		auditChange(r, before, nil)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// actionItemIncident returns the action item store and the incident of the request,
+// writing the error response if either is missing
+func (h *Handler) actionItemIncident(w http.ResponseWriter, r *http.Request) (ports.ActionItemStore, *domain.Incident, bool) {
+	store, ok := h.repo.(ports.ActionItemStore)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, "Action items not supported by the repository")
+		return nil, nil, false
+	}
+	incident, err := h.findIncident(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return nil, nil, false
+	}
+	if incident == nil {
+		h.writeError(w, http.StatusNotFound, "Incident not found")
+		return nil, nil, false
+	}
+	return store, incident, true
+}
+
+// applyActionItemRequest sets the given fields of the request on the item. It returns a
+// message for a 400 response if a field is invalid. A bare due date is the end of that day
+// in the request's time zone.
+func applyActionItemRequest(r *http.Request, item *domain.ActionItem, req IncidentActionItemRequest) string {
+	if req.Description != nil {
+		description := strings.TrimSpace(*req.Description)
+		if description == "" {
+			return "description must not be empty"
+		}
+		item.Description = description
+	}
+	if req.Owner != nil {
+		item.Owner = strings.TrimSpace(*req.Owner)
+	}
+	if req.Status != nil {
+		status, err := domain.ParseActionItemStatus(*req.Status)
+		if err != nil {
+			return err.Error()
+		}
+		item.Status = status
+	}
+	if req.DueAt != nil {
+		var dueAt *time.Time
+		if *req.DueAt != "" {
+			parsed, dateOnly, err := parseExportTime(*req.DueAt)
+			if err != nil {
+				return "Invalid due_at: must be RFC3339 or YYYY-MM-DD"
+			}
+			if dateOnly {
+				location, msg := requestTimeZone(r)
+				if msg != "" {
+					return msg
+				}
+				parsed = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 23, 59, 59, 0, location)
+			}
+			parsed = parsed.UTC()
+			dueAt = &parsed
+		}
+		// A new due date is reported again once it passes
+		if !equalTimes(item.DueAt, dueAt) {
+			item.OverdueNotifiedAt = nil
+		}
+		item.DueAt = dueAt
+	}
+	return ""
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// actionItemFields returns the audited fields of an action item
+func actionItemFields(item domain.ActionItem) map[string]any {
+	dueAt := ""
+	if item.DueAt != nil {
+		dueAt = item.DueAt.UTC().Format(time.RFC3339)
+	}
+	return map[string]any{
+		"id":          item.ID,
+		"description": item.Description,
+		"owner":       item.Owner,
+		"due_at":      dueAt,
+		"status":      string(item.Status),
+	}
+}
+
+func actionItemResponse(item domain.ActionItem, now time.Time) IncidentActionItemResponse {
+	return IncidentActionItemResponse{
+		ID:                item.ID,
+		IncidentID:        item.IncidentID,
+		Description:       item.Description,
+		Owner:             item.Owner,
+		DueAt:             item.DueAt,
+		Status:            string(item.Status),
+		Overdue:           item.Overdue(now),
+		OverdueNotifiedAt: item.OverdueNotifiedAt,
+		CreatedAt:         item.CreatedAt,
+		UpdatedAt:         item.UpdatedAt,
+	}
+}
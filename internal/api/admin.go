@@ -0,0 +1,86 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"incident-teller/internal/config"
+	"incident-teller/internal/observability"
+)
+
+// ConfigReloader reloads the configuration of the running server
+type ConfigReloader interface {
+	Reload() (config.ReloadResult, error)
+	Effective() (config.Effective, error)
+}
+
+// SetConfigReloader enables POST /api/admin/reload and GET /api/admin/config. Requests must carry the admin token
+// as a Bearer Authorization header.
+func (h *Handler) SetConfigReloader(reloader ConfigReloader, adminToken string) {
+	h.reloader = reloader
+	h.adminToken = adminToken
+}
+
+// authorizeAdmin checks the admin token, writing the error response if it doesn't match
+func (h *Handler) authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+		h.writeError(w, http.StatusUnauthorized, "Invalid admin token")
+		return false
+	}
+	return true
+}
+
+// handleAdminReload reloads the configuration and reports which components applied it
+func (h *Handler) handleAdminReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.reloader == nil || h.adminToken == "" {
+		h.writeError(w, http.StatusNotFound, "Admin API not enabled")
+		return
+	}
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	result, err := h.reloader.Reload()
+	if err != nil {
+		h.logger.Error("Configuration reload failed", observability.Error(err))
+		if len(result.Applied) == 0 && len(result.Failed) == 0 {
+			h.writeError(w, http.StatusBadRequest, "Invalid configuration: "+err.Error())
+			return
+		}
+		h.writeJSON(w, http.StatusInternalServerError, result)
+		return
+	}
+
+	h.logger.Info("Configuration reloaded", observability.Int("components", len(result.Applied)))
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+// handleAdminConfig returns the effective configuration with secrets masked, and whether
+// each setting came from the file or the environment
+func (h *Handler) handleAdminConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.reloader == nil || h.adminToken == "" {
+		h.writeError(w, http.StatusNotFound, "Admin API not enabled")
+		return
+	}
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	effective, err := h.reloader.Effective()
+	if err != nil {
+		h.logger.Error("Failed to get effective configuration", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get effective configuration")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, effective)
+}
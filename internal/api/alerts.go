@@ -0,0 +1,303 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/ports"
+)
+
+// AlertResponse is a stored alert
+type AlertResponse struct {
+	ID             string            `json:"id"`
+	ExternalID     uint64            `json:"external_id"`
+	Source         string            `json:"source,omitempty"`
+	Host           string            `json:"host"`
+	Chart          string            `json:"chart"`
+	Family         string            `json:"family"`
+	Name           string            `json:"name"`
+	Status         string            `json:"status"`
+	OldStatus      string            `json:"old_status"`
+	Value          float64           `json:"value"`
+	OccurredAt     time.Time         `json:"occurred_at"`
+	Description    string            `json:"description,omitempty"`
+	ResourceType   string            `json:"resource_type"`
+	Labels         map[string]string `json:"labels"`
+	Acknowledged   bool              `json:"acknowledged"`
+	AcknowledgedBy string            `json:"acknowledged_by,omitempty"`
+	AckNote        string            `json:"ack_note,omitempty"`
+	AcknowledgedAt *time.Time        `json:"acknowledged_at,omitempty"`
+}
+
+// AlertListResponse is a page of alerts, newest first
+type AlertListResponse struct {
+	Alerts   []AlertResponse `json:"alerts"`
+	Total    int             `json:"total"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+}
+
+// AlertDetailResponse is an alert with the incidents it belongs to and the payload its
+// source sent
+type AlertDetailResponse struct {
+	AlertResponse
+	IncidentIDs []string              `json:"incident_ids"`
+	Samples     []domain.MetricSample `json:"samples,omitempty"`
+	RawPayload  json.RawMessage       `json:"raw_payload,omitempty"` // As received at ingest; absent for alerts stored before
+
+	// The payload was longer than the ingestion limit; its start is given as a string
+	RawPayloadTruncated bool `json:"raw_payload_truncated,omitempty"`
+}
+
+// AlertAckRequest acknowledges an alert; By is required
+type AlertAckRequest struct {
+	By   string `json:"by"`
+	Note string `json:"note,omitempty"`
+}
+
+// handleAlerts lists stored alerts, newest first, filtered and paginated
+func (h *Handler) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	store, ok := h.alertStore(w)
+	if !ok {
+		return
+	}
+
+	params := r.URL.Query()
+	q := domain.AlertQuery{
+		Search: strings.TrimSpace(params.Get("q")),
+		Host:   params.Get("host"),
+		Source: params.Get("source"),
+	}
+	if v := params.Get("status"); v != "" {
+		for _, status := range strings.Split(v, ",") {
+			status = strings.ToUpper(strings.TrimSpace(status))
+			switch domain.AlertStatus(status) {
+			case domain.StatusClear, domain.StatusWarning, domain.StatusCritical, domain.StatusRemoved, domain.StatusUndefined:
+				q.Statuses = append(q.Statuses, domain.AlertStatus(status))
+			default:
+				h.writeError(w, http.StatusBadRequest, "Invalid status: must be CLEAR, WARNING, CRITICAL, REMOVED or UNDEFINED")
+				return
+			}
+		}
+	}
+	if v := params.Get("from"); v != "" {
+		from, _, err := parseExportTime(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid from: must be RFC3339 or YYYY-MM-DD")
+			return
+		}
+		q.From = from
+	}
+	if v := params.Get("to"); v != "" {
+		to, dateOnly, err := parseExportTime(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid to: must be RFC3339 or YYYY-MM-DD")
+			return
+		}
+		if dateOnly {
+			// A bare date includes the whole day
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+		q.To = to
+	}
+	if v := params.Get("acknowledged"); v != "" {
+		acknowledged, err := strconv.ParseBool(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid acknowledged: must be true or false")
+			return
+		}
+		q.Acknowledged = &acknowledged
+	}
+
+	page, pageSize := 1, 20
+	if v := params.Get("page"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+	if v := params.Get("page_size"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 && parsed <= 100 {
+			pageSize = parsed
+		}
+	}
+	q.Limit, q.Offset = pageSize, (page-1)*pageSize
+
+	alerts, total, err := store.QueryAlerts(r.Context(), q)
+	if err != nil {
+		h.logger.Error("Failed to query alerts", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to query alerts")
+		return
+	}
+
+	response := AlertListResponse{Alerts: make([]AlertResponse, 0, len(alerts)), Total: total, Page: page, PageSize: pageSize}
+	for _, alert := range alerts {
+		response.Alerts = append(response.Alerts, alertResponse(alert))
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// handleAlert returns an alert with the raw payload its source sent
+func (h *Handler) handleAlert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	alert, ok := h.findAlert(w, r)
+	if !ok {
+		return
+	}
+
+	response := AlertDetailResponse{
+		AlertResponse: alertResponse(*alert),
+		IncidentIDs:   []string{},
+		Samples:       alert.Samples,
+	}
+	if alert.RawPayload != "" {
+		if json.Valid([]byte(alert.RawPayload)) {
+			response.RawPayload = json.RawMessage(alert.RawPayload)
+		} else {
+			// Sources send JSON, so only a payload cut to the limit is invalid
+			response.RawPayload, _ = json.Marshal(alert.RawPayload)
+			response.RawPayloadTruncated = true
+		}
+	}
+
+	incidents, err := h.repo.GetIncidents(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+	for _, incident := range incidents {
+		for _, event := range incident.Events {
+			if event.ID == alert.ID {
+				response.IncidentIDs = append(response.IncidentIDs, incident.ID)
+				break
+			}
+		}
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// handleAlertAck acknowledges a single alert, e.g. a noisy one, without resolving or
+// acknowledging its incident
+func (h *Handler) handleAlertAck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req AlertAckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	by := strings.TrimSpace(req.By)
+	if by == "" {
+		h.writeError(w, http.StatusBadRequest, "by is required")
+		return
+	}
+	auditActor(r, by)
+
+	alert, ok := h.findAlert(w, r)
+	if !ok {
+		return
+	}
+	store := h.repo.(ports.AlertStore)
+
+	before := alertAckFields(alert.Ack)
+	ack := domain.AlertAck{
+		AlertID:        alert.ID,
+		By:             by,
+		Note:           strings.TrimSpace(req.Note),
+		AcknowledgedAt: time.Now().UTC(),
+	}
+	if err := store.AcknowledgeAlert(r.Context(), ack); err != nil {
+		h.logger.Error("Failed to acknowledge alert",
+			observability.String("alert_id", alert.ID), observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to acknowledge alert")
+		return
+	}
+	alert.Ack = &ack
+	auditChange(r, before, alertAckFields(alert.Ack))
+	h.logger.Info("Alert acknowledged",
+		observability.String("alert_id", alert.ID),
+		observability.String("by", by))
+
+	h.writeJSON(w, http.StatusOK, alertResponse(*alert))
+}
+
+// alertStore returns the repository as an AlertStore, writing the error if it isn't one
+func (h *Handler) alertStore(w http.ResponseWriter) (ports.AlertStore, bool) {
+	store, ok := h.repo.(ports.AlertStore)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, "Alert details not supported by the repository")
+	}
+	return store, ok
+}
+
+// findAlert looks up the alert of a request, writing the error if there is none
+func (h *Handler) findAlert(w http.ResponseWriter, r *http.Request) (*domain.Alert, bool) {
+	store, ok := h.alertStore(w)
+	if !ok {
+		return nil, false
+	}
+	alert, err := store.GetAlert(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.logger.Error("Failed to get alert", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get alert")
+		return nil, false
+	}
+	if alert == nil {
+		h.writeError(w, http.StatusNotFound, "Alert not found")
+		return nil, false
+	}
+	return alert, true
+}
+
+// alertAckFields returns the fields of an alert acknowledgement, for the audit log
+func alertAckFields(ack *domain.AlertAck) map[string]any {
+	if ack == nil {
+		return map[string]any{"acknowledged_by": "", "ack_note": ""}
+	}
+	return map[string]any{"acknowledged_by": ack.By, "ack_note": ack.Note}
+}
+
+func alertResponse(alert domain.Alert) AlertResponse {
+	labels := alert.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	response := AlertResponse{
+		ID:           alert.ID,
+		ExternalID:   alert.ExternalID,
+		Source:       alert.Source,
+		Host:         alert.Host,
+		Chart:        alert.Chart,
+		Family:       alert.Family,
+		Name:         alert.Name,
+		Status:       string(alert.Status),
+		OldStatus:    string(alert.OldStatus),
+		Value:        alert.Value,
+		OccurredAt:   alert.OccurredAt,
+		Description:  alert.Description,
+		ResourceType: string(alert.ResourceType),
+		Labels:       labels,
+	}
+	if alert.Ack != nil {
+		response.Acknowledged = true
+		response.AcknowledgedBy = alert.Ack.By
+		response.AckNote = alert.Ack.Note
+		response.AcknowledgedAt = &alert.Ack.AcknowledgedAt
+	}
+	return response
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/services"
+)
+
+// IncidentAnalysisResponse is the background analysis of an incident
+type IncidentAnalysisResponse struct {
+	RootCause   *RootCauseResponse   `json:"root_cause,omitempty"`
+	BlastRadius *BlastRadiusResponse `json:"blast_radius,omitempty"`
+	Analysis    *AIAnalysisResponse  `json:"analysis"`
+}
+
+// AnalysisStatusResponse is the state of an incident's analysis job. Current is false
+// while the incident changed since the analysis in Result.
+type AnalysisStatusResponse struct {
+	IncidentID  string                    `json:"incident_id"`
+	Status      string                    `json:"status"`
+	Current     bool                      `json:"current"`
+	EnqueuedAt  time.Time                 `json:"enqueued_at"`
+	StartedAt   *time.Time                `json:"started_at,omitempty"`
+	CompletedAt *time.Time                `json:"completed_at,omitempty"`
+	Error       string                    `json:"error,omitempty"`
+	Result      *IncidentAnalysisResponse `json:"result,omitempty"`
+}
+
+// SetAnalysisQueue enables /api/incidents/{id}/analysis/status and analysis events on
+// /api/events. Incident details and analyses use finished jobs instead of running the
+// AI model inline.
+func (h *Handler) SetAnalysisQueue(queue *services.AnalysisQueue) {
+	h.analyses = queue
+}
+
+// AnalyzeIncident predicts the root cause and blast radius of an incident and tells its
+// story; it is the analysis function of the analysis queue
+func (h *Handler) AnalyzeIncident(ctx context.Context, incident domain.Incident) (any, error) {
+	if len(incident.Events) == 0 {
+		return nil, fmt.Errorf("incident has no alerts")
+	}
+
+	response := &IncidentAnalysisResponse{}
+	if h.aiModel != nil {
+		if rootCause, err := h.aiModel.PredictRootCause(ctx, incident.Events); err == nil {
+			response.RootCause = h.convertRootCauseToResponse(rootCause)
+		}
+		response.RootCause = pinRootCause(incident, response.RootCause)
+		h.recordRootCauses(ctx, incident)
+		if blastRadius, err := h.aiModel.PredictBlastRadius(ctx, incident.Events); err == nil {
+			response.BlastRadius = h.convertBlastRadiusToResponse(blastRadius)
+		}
+	}
+
+	analysis, err := h.analyzeAlerts(ctx, incident.Events)
+	if err != nil {
+		return nil, err
+	}
+	h.applyTemplate(incident, analysis)
+	response.Analysis = analysis
+	h.exportAnalysis(ctx, incident.ID, analysis)
+	return response, nil
+}
+
+// enqueueAnalysis queues an incident saved through the API for analysis
+func (h *Handler) enqueueAnalysis(incident domain.Incident) {
+	if h.analyses == nil {
+		return
+	}
+	if err := h.analyses.Enqueue(incident); err != nil {
+		h.logger.Warn("Failed to queue incident analysis",
+			observability.String("incident_id", incident.ID), observability.Error(err))
+	}
+}
+
+// currentAnalysis returns the finished analysis of an incident as it is, if there is one
+func (h *Handler) currentAnalysis(incident domain.Incident) *IncidentAnalysisResponse {
+	if h.analyses == nil {
+		return nil
+	}
+	job, ok := h.analyses.Job(incident.ID)
+	if !ok || !job.Current(incident) {
+		return nil
+	}
+	analysis, _ := job.Result.(*IncidentAnalysisResponse)
+	return analysis
+}
+
+// handleIncidentAnalysisStatus returns the analysis job of an incident. An incident
+// without a job, e.g. one stored before startup, is queued.
+func (h *Handler) handleIncidentAnalysisStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.analyses == nil {
+		h.writeError(w, http.StatusNotFound, "Analysis queue not enabled")
+		return
+	}
+
+	incident, err := h.findIncident(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+	if incident == nil {
+		h.writeError(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+
+	job, ok := h.analyses.Job(incident.ID)
+	if !ok || (!job.Current(*incident) && job.Status != services.AnalysisPending && job.Status != services.AnalysisRunning) {
+		if err := h.analyses.Enqueue(*incident); err != nil {
+			if errors.Is(err, services.ErrAnalysisQueueFull) {
+				h.writeError(w, http.StatusServiceUnavailable, "Analysis queue is full")
+				return
+			}
+			h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to queue analysis: %v", err))
+			return
+		}
+		job, _ = h.analyses.Job(incident.ID)
+	}
+
+	h.writeJSON(w, http.StatusOK, analysisStatusResponse(job, incident))
+}
+
+// analysisStatusResponse converts a job; incident is nil when the incident isn't at hand
+func analysisStatusResponse(job services.AnalysisJob, incident *domain.Incident) AnalysisStatusResponse {
+	response := AnalysisStatusResponse{
+		IncidentID:  job.IncidentID,
+		Status:      string(job.Status),
+		Current:     job.Result != nil && job.AnalyzedVersion == job.Version,
+		EnqueuedAt:  job.EnqueuedAt,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.CompletedAt,
+		Error:       job.Error,
+	}
+	if incident != nil {
+		response.Current = job.Current(*incident)
+	}
+	response.Result, _ = job.Result.(*IncidentAnalysisResponse)
+	return response
+}
+
+// sendSSEAnalysis sends a finished analysis job as an "analysis" event
+func (h *Handler) sendSSEAnalysis(w http.ResponseWriter, flusher http.Flusher, job services.AnalysisJob) {
+	data, err := json.Marshal(analysisStatusResponse(job, nil))
+	if err != nil {
+		h.logger.Error("Failed to marshal analysis for SSE", observability.Error(err))
+		return
+	}
+
+	fmt.Fprintf(w, "event: analysis\ndata: %s\n\n", data)
+	flusher.Flush()
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+)
+
+// LabelAnalyticsRepository is implemented by repositories that can aggregate incidents by label
+type LabelAnalyticsRepository interface {
+	IncidentStatsByLabel(ctx context.Context, key string, since time.Time, filter domain.LabelFilter) ([]domain.LabelGroupStats, error)
+}
+
+// LabelGroupResponse represents incident statistics for one label value
+type LabelGroupResponse struct {
+	Value       string  `json:"value"`
+	Incidents   int     `json:"incidents"`
+	Resolved    int     `json:"resolved"`
+	Active      int     `json:"active"`
+	MTTRSeconds float64 `json:"mttr_seconds"`
+	MTTR        string  `json:"mttr"`
+}
+
+// GroupedAnalyticsResponse represents incident analytics grouped by a label key
+type GroupedAnalyticsResponse struct {
+	GroupBy string               `json:"group_by"`
+	Since   time.Time            `json:"since"`
+	Groups  []LabelGroupResponse `json:"groups"`
+	Total   int                  `json:"total"`
+}
+
+// handleIncidentAnalytics returns incident counts and MTTR grouped by an arbitrary label key
+// (e.g. ?group_by=env or ?group_by=team&window=168h)
+func (h *Handler) handleIncidentAnalytics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy == "" {
+		groupBy = "host"
+	}
+
+	window := 30 * 24 * time.Hour
+	if ws := r.URL.Query().Get("window"); ws != "" {
+		parsed, err := time.ParseDuration(ws)
+		if err != nil || parsed <= 0 {
+			h.writeError(w, http.StatusBadRequest, "Invalid window duration")
+			return
+		}
+		window = parsed
+	}
+	since := time.Now().Add(-window)
+	envFilter, invalid := h.parseEnvironmentFilter(r)
+	if invalid != "" {
+		h.writeError(w, http.StatusBadRequest, invalid)
+		return
+	}
+
+	labelRepo, ok := h.repo.(LabelAnalyticsRepository)
+	if !ok {
+		h.writeError(w, http.StatusNotImplemented, "Repository does not support label analytics")
+		return
+	}
+
+	stats, err := labelRepo.IncidentStatsByLabel(r.Context(), groupBy, since, envFilter)
+	if err != nil {
+		h.logger.Error("Failed to aggregate incidents by label", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to compute analytics")
+		return
+	}
+
+	response := GroupedAnalyticsResponse{
+		GroupBy: groupBy,
+		Since:   since,
+		Groups:  make([]LabelGroupResponse, len(stats)),
+	}
+	for i, group := range stats {
+		response.Groups[i] = LabelGroupResponse{
+			Value:       group.Value,
+			Incidents:   group.Incidents,
+			Resolved:    group.Resolved,
+			Active:      group.Incidents - group.Resolved,
+			MTTRSeconds: group.MTTR.Seconds(),
+			MTTR:        group.MTTR.Round(time.Second).String(),
+		}
+		response.Total += group.Incidents
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
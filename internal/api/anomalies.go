@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"incident-teller/internal/services"
+)
+
+// AnomalyResponse describes one detected anomaly
+type AnomalyResponse struct {
+	Type         string    `json:"type"`
+	Host         string    `json:"host"`
+	ResourceType string    `json:"resource_type"`
+	AlertName    string    `json:"alert_name"`
+	AlertIDs     []string  `json:"alert_ids"`
+	DetectedAt   time.Time `json:"detected_at"`
+	Count        int       `json:"count"`
+	Baseline     float64   `json:"baseline"`
+	Score        float64   `json:"score"`
+	Message      string    `json:"message"`
+}
+
+// SetAnomalyDetector enables GET /api/anomalies and anomaly events in enhanced timelines.
+// window is how far back the endpoint looks by default.
+func (h *Handler) SetAnomalyDetector(detector *services.AnomalyDetector, window time.Duration) {
+	h.anomalies = detector
+	h.anomalyWindow = window
+}
+
+// handleAnomalies lists the anomalies detected in the current window (?window= overrides it)
+func (h *Handler) handleAnomalies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.anomalies == nil {
+		h.writeError(w, http.StatusNotFound, "Anomaly detection not enabled")
+		return
+	}
+
+	window := h.anomalyWindow
+	if ws := r.URL.Query().Get("window"); ws != "" {
+		parsed, err := time.ParseDuration(ws)
+		if err != nil || parsed <= 0 {
+			h.writeError(w, http.StatusBadRequest, "Invalid window duration")
+			return
+		}
+		window = parsed
+	}
+	since := time.Now().Add(-window)
+
+	anomalies := []AnomalyResponse{}
+	for _, a := range h.anomalies.Recent(since) {
+		anomalies = append(anomalies, AnomalyResponse{
+			Type:         string(a.Type),
+			Host:         a.Host,
+			ResourceType: string(a.ResourceType),
+			AlertName:    a.AlertName,
+			AlertIDs:     a.AlertIDs,
+			DetectedAt:   a.DetectedAt,
+			Count:        a.Count,
+			Baseline:     a.Baseline,
+			Score:        a.Score,
+			Message:      a.Message,
+		})
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"anomalies": anomalies,
+		"count":     len(anomalies),
+		"window":    window.String(),
+		"since":     since,
+	})
+}
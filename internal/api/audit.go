@@ -0,0 +1,372 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/ports"
+)
+
+// auditSkipPaths lists write endpoints left out of the audit log: computations that change
+// nothing, and alert ingestion, which the stored alerts already record
+var auditSkipPaths = map[string]bool{
+	"/api/analyze":         true,
+	"/api/graphql":         true,
+	"/api/webhooks/nagios": true,
+}
+
+// Request bodies are kept in the audit log up to this size
+const maxAuditPayload = 16 << 10
+
+// auditSecretFields are request fields never kept in the audit log. Fields whose name
+// contains token, secret or password are redacted too.
+var auditSecretFields = map[string]bool{
+	"response_url": true, // Slack's reply URL needs no other credential
+	"api_key":      true,
+}
+
+const auditRedacted = "[redacted]"
+
+// AuditEntryResponse is an entry of the audit log
+type AuditEntryResponse struct {
+	ID         string                        `json:"id"`
+	Actor      string                        `json:"actor,omitempty"`
+	APIKey     string                        `json:"api_key,omitempty"` // Fingerprint of the bearer token
+	RemoteAddr string                        `json:"remote_addr"`
+	Method     string                        `json:"method"`
+	Path       string                        `json:"path"`
+	Status     int                           `json:"status"`
+	Payload    string                        `json:"payload,omitempty"`
+	Changes    map[string]domain.FieldChange `json:"changes,omitempty"`
+	At         time.Time                     `json:"at"`
+}
+
+// AuditLogResponse lists audit log entries, newest first
+type AuditLogResponse struct {
+	Entries []AuditEntryResponse `json:"entries"`
+	Count   int                  `json:"count"`
+}
+
+type auditContextKey struct{}
+
+// auditRecord collects what a handler reports about its write
+type auditRecord struct {
+	actor   string
+	changes map[string]domain.FieldChange
+}
+
+// auditActor names the user making a write, for endpoints that take the user from the
+// request rather than the X-User header
+func auditActor(r *http.Request, actor string) {
+	if record, ok := r.Context().Value(auditContextKey{}).(*auditRecord); ok {
+		record.actor = actor
+	}
+}
+
+// auditChange records the fields a write changed. before and after are the resource as
+// returned by the API; nil stands for a resource that didn't exist or was deleted.
+func auditChange(r *http.Request, before, after any) {
+	if record, ok := r.Context().Value(auditContextKey{}).(*auditRecord); ok {
+		record.changes = diffFields(before, after)
+	}
+}
+
+// statusRecorder remembers the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if s.status == 0 {
+		s.status = code
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(b)
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// withAudit is a middleware that records every write to the audit log, when the
+// repository keeps one
+func (h *Handler) withAudit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		store, ok := h.repo.(ports.AuditStore)
+		if !ok || !audited(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var payload []byte
+		if r.Body != nil {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					h.writeError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+				} else {
+					h.writeError(w, http.StatusBadRequest, "Failed to read request body")
+				}
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			payload = body
+		}
+
+		record := &auditRecord{}
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), auditContextKey{}, record)))
+
+		now := time.Now().UTC()
+		entry := domain.AuditEntry{
+			ID:         idgen.New(now),
+			Actor:      strings.TrimSpace(r.Header.Get("X-User")),
+			APIKey:     requestKeyFingerprint(r),
+			RemoteAddr: h.clientAddr(r),
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Status:     recorder.status,
+			Payload:    redactPayload(r.Header.Get("Content-Type"), payload),
+			Changes:    record.changes,
+			At:         now,
+		}
+		if entry.Actor == "" {
+			entry.Actor = record.actor
+		}
+		if entry.Status == 0 {
+			entry.Status = http.StatusOK
+		}
+
+		// The client may be gone already; the write happened regardless
+		if err := store.SaveAuditEntry(context.WithoutCancel(r.Context()), entry); err != nil {
+			h.logger.Error("Failed to save audit entry",
+				observability.String("method", entry.Method),
+				observability.String("path", entry.Path),
+				observability.Error(err))
+		}
+	})
+}
+
+// audited reports whether a request is a write that belongs in the audit log
+func audited(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return !auditSkipPaths[r.URL.Path]
+}
+
+// requestKeyFingerprint identifies the bearer token (or ?token= parameter) of a request
+// without revealing it
+func requestKeyFingerprint(r *http.Request) string {
+	token := r.Header.Get("Authorization")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	return strings.TrimPrefix(tokenKey(token), "token:")
+}
+
+// clientAddr returns the client IP address, from the proxy headers if they are trusted
+func (h *Handler) clientAddr(r *http.Request) string {
+	if h.rateLimiter != nil {
+		return h.rateLimiter.clientIP(r)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// redactPayload returns a JSON or form request body with the secret fields redacted,
+// truncated to maxAuditPayload
+func redactPayload(contentType string, body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	payload := string(body)
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if form, err := url.ParseQuery(payload); err == nil {
+			for key := range form {
+				if secretField(key) {
+					form.Set(key, auditRedacted)
+				}
+			}
+			payload = form.Encode()
+		}
+	} else {
+		var value any
+		if err := json.Unmarshal(body, &value); err == nil {
+			if redacted, err := json.Marshal(redactJSON(value)); err == nil {
+				payload = string(redacted)
+			}
+		}
+	}
+
+	if len(payload) > maxAuditPayload {
+		payload = payload[:maxAuditPayload] + "…"
+	}
+	return payload
+}
+
+func redactJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if secretField(key) {
+				v[key] = auditRedacted
+			} else {
+				v[key] = redactJSON(field)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = redactJSON(v[i])
+		}
+	}
+	return value
+}
+
+func secretField(name string) bool {
+	name = strings.ToLower(name)
+	return auditSecretFields[name] ||
+		strings.Contains(name, "token") || strings.Contains(name, "secret") || strings.Contains(name, "password")
+}
+
+// diffFields compares the top-level JSON fields of two values. A value that isn't a JSON
+// object is compared as a whole under the "value" field.
+func diffFields(before, after any) map[string]domain.FieldChange {
+	from, to := jsonFields(before), jsonFields(after)
+
+	changes := make(map[string]domain.FieldChange)
+	for name, value := range from {
+		if !reflect.DeepEqual(value, to[name]) {
+			changes[name] = domain.FieldChange{From: value, To: to[name]}
+		}
+	}
+	for name, value := range to {
+		if _, ok := from[name]; !ok {
+			changes[name] = domain.FieldChange{To: value}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+func jsonFields(value any) map[string]any {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err == nil {
+		return fields
+	}
+	var whole any
+	if err := json.Unmarshal(data, &whole); err != nil || whole == nil {
+		return nil
+	}
+	return map[string]any{"value": whole}
+}
+
+// handleAuditLog lists the audit log, filtered by actor, api_key, method, path prefix and
+// time range
+func (h *Handler) handleAuditLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	store, ok := h.repo.(ports.AuditStore)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, "Audit log not supported by the repository")
+		return
+	}
+
+	params := r.URL.Query()
+	q := domain.AuditQuery{
+		Actor:      params.Get("actor"),
+		APIKey:     params.Get("api_key"),
+		Method:     strings.ToUpper(params.Get("method")),
+		PathPrefix: params.Get("path"),
+		Limit:      100,
+	}
+	if v := params.Get("from"); v != "" {
+		from, _, err := parseExportTime(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid from: must be RFC3339 or YYYY-MM-DD")
+			return
+		}
+		q.From = from
+	}
+	if v := params.Get("to"); v != "" {
+		to, dateOnly, err := parseExportTime(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid to: must be RFC3339 or YYYY-MM-DD")
+			return
+		}
+		if dateOnly {
+			// A bare date includes the whole day
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+		q.To = to
+	}
+	if v := params.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > 1000 {
+			h.writeError(w, http.StatusBadRequest, "Invalid limit: must be between 1 and 1000")
+			return
+		}
+		q.Limit = limit
+	}
+
+	entries, err := store.GetAuditEntries(r.Context(), q)
+	if err != nil {
+		h.logger.Error("Failed to get audit log", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get audit log")
+		return
+	}
+
+	response := AuditLogResponse{Entries: make([]AuditEntryResponse, len(entries)), Count: len(entries)}
+	for i, entry := range entries {
+		response.Entries[i] = AuditEntryResponse{
+			ID:         entry.ID,
+			Actor:      entry.Actor,
+			APIKey:     entry.APIKey,
+			RemoteAddr: entry.RemoteAddr,
+			Method:     entry.Method,
+			Path:       entry.Path,
+			Status:     entry.Status,
+			Payload:    entry.Payload,
+			Changes:    entry.Changes,
+			At:         entry.At,
+		}
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
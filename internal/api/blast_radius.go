@@ -0,0 +1,164 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"incident-teller/internal/observability"
+	"incident-teller/internal/services"
+)
+
+// BlastRadiusAnalysisResponse is the impact analysis of an incident, with the part of the
+// topology it touches for rendering an impact map
+type BlastRadiusAnalysisResponse struct {
+	IncidentID         string                         `json:"incident_id"`
+	ImpactScore        int                            `json:"impact_score"` // 0-100
+	Summary            string                         `json:"summary"`
+	ImpactDescription  string                         `json:"impact_description"`
+	RecoveryEstimate   string                         `json:"recovery_estimate"`
+	AffectedHosts      []string                       `json:"affected_hosts"`
+	AffectedResources  []string                       `json:"affected_resources"`
+	AffectedCharts     []string                       `json:"affected_charts"`
+	CascadeDepth       int                            `json:"cascade_depth"`
+	TotalAlerts        int                            `json:"total_alerts"`
+	CriticalAlerts     int                            `json:"critical_alerts"`
+	DurationSeconds    float64                        `json:"duration_seconds"`
+	DirectlyAffected   []BlastRadiusComponentResponse `json:"directly_affected"`
+	IndirectlyAffected []BlastRadiusComponentResponse `json:"indirectly_affected"`
+	Unaffected         []BlastRadiusComponentResponse `json:"unaffected"`
+	Topology           BlastRadiusTopologyResponse    `json:"topology"`
+}
+
+// BlastRadiusComponentResponse is a host, resource or chart classified by its impact
+type BlastRadiusComponentResponse struct {
+	Name         string     `json:"name"`
+	Type         string     `json:"type"`   // host, resource or chart
+	Impact       string     `json:"impact"` // direct, indirect or unaffected
+	Evidence     []string   `json:"evidence"`
+	AffectedAt   *time.Time `json:"affected_at,omitempty"`
+	MetricValues []float64  `json:"metric_values,omitempty"`
+}
+
+// BlastRadiusTopologyResponse is the subgraph of services, hosts and resources an
+// incident touches. Services come from the configured topology, with the services they
+// depend on and those depending on them.
+type BlastRadiusTopologyResponse struct {
+	Nodes []BlastRadiusNodeResponse `json:"nodes"`
+	Edges []BlastRadiusEdgeResponse `json:"edges"`
+}
+
+// BlastRadiusNodeResponse is a node of the impact map
+type BlastRadiusNodeResponse struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`   // service, host or resource
+	Impact string `json:"impact"` // direct, indirect or unaffected
+	Alerts int    `json:"alerts"`
+}
+
+// BlastRadiusEdgeResponse connects two nodes of the impact map
+type BlastRadiusEdgeResponse struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"` // depends_on (service to service), runs_on (service to host) or has (host to resource)
+}
+
+// handleIncidentBlastRadius returns the blast radius analysis of an incident with its
+// topology subgraph
+func (h *Handler) handleIncidentBlastRadius(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	incident, err := h.findIncident(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+	if incident == nil {
+		h.writeError(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+
+	// Fixes can't be recommended without a root cause, so an incident without events
+	// skips the comprehensive analysis
+	analysis := services.NewBlastRadiusAnalyzer().AnalyzeBlastRadius(nil, services.RootCauseCandidate{})
+	if len(incident.Events) > 0 {
+		analysis = services.NewComprehensiveIncidentAnalyzer().AnalyzeIncident(*incident).BlastRadius
+	}
+	graph := services.BuildBlastRadiusGraph(incident.Events, analysis, h.topology)
+
+	response := BlastRadiusAnalysisResponse{
+		IncidentID:         incident.ID,
+		ImpactScore:        analysis.ImpactScore,
+		Summary:            analysis.SimpleSummary,
+		ImpactDescription:  analysis.ImpactDescription,
+		RecoveryEstimate:   analysis.RecoveryEstimate,
+		AffectedHosts:      nonNilStrings(analysis.AffectedHosts),
+		AffectedResources:  []string{},
+		AffectedCharts:     nonNilStrings(analysis.AffectedCharts),
+		CascadeDepth:       analysis.CascadeDepth,
+		TotalAlerts:        analysis.TotalAlerts,
+		CriticalAlerts:     analysis.CriticalAlerts,
+		DurationSeconds:    analysis.Duration.Seconds(),
+		DirectlyAffected:   blastRadiusComponents(analysis.DirectlyAffected),
+		IndirectlyAffected: blastRadiusComponents(analysis.IndirectlyAffected),
+		Unaffected:         blastRadiusComponents(analysis.Unaffected),
+		Topology: BlastRadiusTopologyResponse{
+			Nodes: make([]BlastRadiusNodeResponse, 0, len(graph.Nodes)),
+			Edges: make([]BlastRadiusEdgeResponse, 0, len(graph.Edges)),
+		},
+	}
+	for _, resource := range analysis.AffectedResources {
+		response.AffectedResources = append(response.AffectedResources, string(resource))
+	}
+	for _, node := range graph.Nodes {
+		response.Topology.Nodes = append(response.Topology.Nodes, BlastRadiusNodeResponse{
+			ID:     node.ID,
+			Name:   node.Name,
+			Type:   node.Type,
+			Impact: impactName(node.Impact),
+			Alerts: node.Alerts,
+		})
+	}
+	for _, edge := range graph.Edges {
+		response.Topology.Edges = append(response.Topology.Edges, BlastRadiusEdgeResponse(edge))
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+func blastRadiusComponents(components []services.Component) []BlastRadiusComponentResponse {
+	result := make([]BlastRadiusComponentResponse, 0, len(components))
+	for _, component := range components {
+		result = append(result, BlastRadiusComponentResponse{
+			Name:         component.Name,
+			Type:         component.Type,
+			Impact:       impactName(component.Impact),
+			Evidence:     nonNilStrings(component.Evidence),
+			AffectedAt:   component.AffectedAt,
+			MetricValues: component.MetricValues,
+		})
+	}
+	return result
+}
+
+// impactName returns the name of an impact level in responses
+func impactName(impact services.ComponentImpact) string {
+	switch impact {
+	case services.ImpactDirect:
+		return "direct"
+	case services.ImpactIndirect:
+		return "indirect"
+	default:
+		return "unaffected"
+	}
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
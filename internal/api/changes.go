@@ -0,0 +1,223 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/services"
+)
+
+// ChangeEventRequest is the native webhook format for recording a change
+type ChangeEventRequest struct {
+	Type        string            `json:"type"` // deploy, config, feature_flag
+	Host        string            `json:"host"`
+	Service     string            `json:"service"`
+	FleetWide   bool              `json:"fleet_wide"` // Applies to every host
+	Version     string            `json:"version"`
+	Description string            `json:"description"`
+	Source      string            `json:"source"`
+	OccurredAt  *time.Time        `json:"occurred_at,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// ChangeEventResponse represents a recorded change event
+type ChangeEventResponse struct {
+	ID          string            `json:"id"`
+	Type        string            `json:"type"`
+	Host        string            `json:"host,omitempty"`
+	Service     string            `json:"service,omitempty"`
+	FleetWide   bool              `json:"fleet_wide,omitempty"`
+	Version     string            `json:"version,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Source      string            `json:"source,omitempty"`
+	OccurredAt  time.Time         `json:"occurred_at"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// githubDeploymentPayload is the subset of GitHub's deployment/deployment_status webhook we use
+type githubDeploymentPayload struct {
+	Deployment struct {
+		SHA         string                 `json:"sha"`
+		Ref         string                 `json:"ref"`
+		Environment string                 `json:"environment"`
+		Description string                 `json:"description"`
+		CreatedAt   time.Time              `json:"created_at"`
+		Payload     map[string]interface{} `json:"payload"`
+	} `json:"deployment"`
+	DeploymentStatus *struct {
+		State     string    `json:"state"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"deployment_status"`
+	Repository struct {
+		Name string `json:"name"`
+	} `json:"repository"`
+}
+
+// SetChangeTracker replaces the handler's change history, e.g. with one shared with the
+// incident notifier so recorded changes show up in notification analysis
+func (h *Handler) SetChangeTracker(tracker *services.ChangeTracker) {
+	h.changes = tracker
+	if h.topology != nil {
+		tracker.SetTopology(h.topology)
+	}
+}
+
+// handleChangeEvents records (POST) or lists (GET) deployment and config change events
+func (h *Handler) handleChangeEvents(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		limit := 100
+		if l := r.URL.Query().Get("limit"); l != "" {
+			if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+				limit = parsed
+			}
+		}
+
+		changes := h.changes.Recent(limit)
+		response := make([]ChangeEventResponse, len(changes))
+		for i, change := range changes {
+			response[i] = convertChangeToResponse(change)
+		}
+		h.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"changes": response,
+			"count":   len(response),
+		})
+
+	case http.MethodPost:
+		change, ok := h.decodeChangeEvent(w, r)
+		if !ok {
+			return
+		}
+
+		recorded := h.changes.Record(change)
+		h.metrics.IncCounter("change_events_recorded", map[string]string{"type": string(recorded.Type)})
+		h.logger.Info("Recorded change event",
+			observability.String("type", string(recorded.Type)),
+			observability.String("host", recorded.Host),
+			observability.String("service", recorded.Service),
+			observability.String("version", recorded.Version))
+
+		h.writeJSON(w, http.StatusCreated, convertChangeToResponse(recorded))
+
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// decodeChangeEvent parses either the native format or a GitHub deployment webhook
+func (h *Handler) decodeChangeEvent(w http.ResponseWriter, r *http.Request) (domain.ChangeEvent, bool) {
+	switch r.Header.Get("X-GitHub-Event") {
+	case "":
+		// Native format
+	case "deployment", "deployment_status":
+		return h.decodeGitHubDeployment(w, r)
+	case "ping":
+		h.writeJSON(w, http.StatusOK, map[string]string{"status": "pong"})
+		return domain.ChangeEvent{}, false
+	default:
+		h.writeError(w, http.StatusBadRequest, "Unsupported GitHub event type")
+		return domain.ChangeEvent{}, false
+	}
+
+	var req ChangeEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return domain.ChangeEvent{}, false
+	}
+
+	if req.Host == "" && req.Service == "" && !req.FleetWide {
+		h.writeError(w, http.StatusBadRequest, "Either host, service or fleet_wide is required")
+		return domain.ChangeEvent{}, false
+	}
+
+	changeType := domain.ChangeType(req.Type)
+	switch changeType {
+	case "":
+		changeType = domain.ChangeDeploy
+	case domain.ChangeDeploy, domain.ChangeConfig, domain.ChangeFeatureFlag:
+	default:
+		h.writeError(w, http.StatusBadRequest, "Invalid change type: must be deploy, config or feature_flag")
+		return domain.ChangeEvent{}, false
+	}
+
+	change := domain.ChangeEvent{
+		Type:        changeType,
+		Host:        req.Host,
+		Service:     req.Service,
+		FleetWide:   req.FleetWide,
+		Version:     req.Version,
+		Description: req.Description,
+		Source:      req.Source,
+		Labels:      req.Labels,
+	}
+	if req.OccurredAt != nil {
+		change.OccurredAt = *req.OccurredAt
+	}
+
+	return change, true
+}
+
+// decodeGitHubDeployment maps a GitHub deployment webhook to a change event.
+// The target host is taken from the deployment payload ("host") or the ?host= query parameter.
+func (h *Handler) decodeGitHubDeployment(w http.ResponseWriter, r *http.Request) (domain.ChangeEvent, bool) {
+	var payload githubDeploymentPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid GitHub webhook payload")
+		return domain.ChangeEvent{}, false
+	}
+
+	// Only successful rollouts count as changes
+	if payload.DeploymentStatus != nil && payload.DeploymentStatus.State != "success" {
+		h.writeJSON(w, http.StatusAccepted, map[string]string{"status": "ignored"})
+		return domain.ChangeEvent{}, false
+	}
+
+	host := r.URL.Query().Get("host")
+	if v, ok := payload.Deployment.Payload["host"].(string); ok && v != "" {
+		host = v
+	}
+
+	version := payload.Deployment.Ref
+	if version == "" {
+		version = payload.Deployment.SHA
+	}
+
+	occurredAt := payload.Deployment.CreatedAt
+	if payload.DeploymentStatus != nil && !payload.DeploymentStatus.CreatedAt.IsZero() {
+		occurredAt = payload.DeploymentStatus.CreatedAt
+	}
+
+	return domain.ChangeEvent{
+		Type:        domain.ChangeDeploy,
+		Host:        host,
+		Service:     payload.Repository.Name,
+		Version:     version,
+		Description: payload.Deployment.Description,
+		Source:      "github",
+		OccurredAt:  occurredAt,
+		Labels: map[string]string{
+			"environment": payload.Deployment.Environment,
+			"sha":         payload.Deployment.SHA,
+		},
+	}, true
+}
+
+// convertChangeToResponse converts a change event to API response format
+func convertChangeToResponse(change domain.ChangeEvent) ChangeEventResponse {
+	return ChangeEventResponse{
+		ID:          change.ID,
+		Type:        string(change.Type),
+		Host:        change.Host,
+		Service:     change.Service,
+		FleetWide:   change.FleetWide,
+		Version:     change.Version,
+		Description: change.Description,
+		Source:      change.Source,
+		OccurredAt:  change.OccurredAt,
+		Labels:      change.Labels,
+	}
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"incident-teller/internal/services"
+)
+
+func TestChangeEvents_SharedTracker(t *testing.T) {
+	h := newTestHandler(t)
+	tracker := services.NewChangeTracker(10)
+	h.SetChangeTracker(tracker)
+	routes := h.SetupRoutes()
+
+	rec := serve(routes, http.MethodPost, "/api/events/change", `{"type": "deploy", "host": "web-01", "version": "v2"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	if recent := tracker.Recent(10); len(recent) != 1 || recent[0].Host != "web-01" || recent[0].Version != "v2" {
+		t.Fatalf("expected the change in the shared tracker, got %+v", recent)
+	}
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy controls which browser origins may call the API
+type CORSPolicy struct {
+	AllowedOrigins   []string // Exact origins such as https://dashboard.example.com, or "*"
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool // Allow cookies and Authorization headers; requires explicit origins
+	MaxAge           time.Duration
+}
+
+// defaultCORSPolicy allows any origin without credentials
+var defaultCORSPolicy = CORSPolicy{
+	AllowedOrigins: []string{"*"},
+	AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+	AllowedHeaders: []string{"Content-Type", "Authorization"},
+	MaxAge:         24 * time.Hour,
+}
+
+// SetCORSPolicy replaces the default policy, which allows any origin without credentials
+func (h *Handler) SetCORSPolicy(policy CORSPolicy) {
+	h.cors = &policy
+}
+
+// allowsOrigin reports whether a cross-origin request from origin is allowed
+func (p CORSPolicy) allowsOrigin(origin string) bool {
+	for _, allowed := range p.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p CORSPolicy) wildcard() bool {
+	return !p.AllowCredentials && len(p.AllowedOrigins) == 1 && p.AllowedOrigins[0] == "*"
+}
+
+// sameOrigin reports whether the Origin header names the scheme and host the request was
+// sent to, which browsers also send for same-origin POSTs
+func (h *Handler) sameOrigin(r *http.Request, origin string) bool {
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Scheme, h.requestScheme(r)) && strings.EqualFold(u.Host, r.Host)
+}
+
+// requestScheme returns the scheme the client used, from X-Forwarded-Proto if the proxy
+// headers are trusted
+func (h *Handler) requestScheme(r *http.Request) string {
+	if h.rateLimiter != nil && h.rateLimiter.trustProxy {
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+			proto, _, _ = strings.Cut(proto, ",")
+			return strings.ToLower(strings.TrimSpace(proto))
+		}
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// withCORS is a middleware that handles Cross-Origin Resource Sharing. Requests from
+// origins outside the policy are rejected; requests without an Origin header (curl,
+// server-to-server) are not affected.
+func (h *Handler) withCORS(next http.Handler) http.Handler {
+	policy := defaultCORSPolicy
+	if h.cors != nil {
+		policy = *h.cors
+	}
+	methods := strings.Join(policy.AllowedMethods, ", ")
+	headers := strings.Join(policy.AllowedHeaders, ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && !h.sameOrigin(r, origin) {
+			if !policy.allowsOrigin(origin) {
+				h.writeError(w, http.StatusForbidden, "Origin not allowed")
+				return
+			}
+
+			if policy.wildcard() {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Add("Vary", "Origin")
+			}
+			if policy.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if r.Method == http.MethodOptions {
+			if methods != "" {
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+			}
+			if headers != "" {
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+			}
+			if policy.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(policy.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// corsRequest sends a request with an Origin header to http://api.example.com
+func corsRequest(routes http.Handler, method, origin string, prepare ...func(*http.Request)) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "http://api.example.com/api/incidents", nil)
+	if origin != "" {
+		r.Header.Set("Origin", origin)
+	}
+	for _, f := range prepare {
+		f(r)
+	}
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestCORS_ExplicitOrigins(t *testing.T) {
+	h := newTestHandler(t)
+	h.SetCORSPolicy(CORSPolicy{
+		AllowedOrigins: []string{"https://dashboard.example.com/"},
+		AllowedMethods: []string{"GET", "POST"},
+	})
+	routes := h.SetupRoutes()
+
+	rec := corsRequest(routes, http.MethodGet, "https://dashboard.example.com")
+	if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "https://dashboard.example.com" {
+		t.Errorf("expected the allowed origin to be echoed, got %d %q", rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if rec.Header().Get("Vary") != "Origin" {
+		t.Errorf("expected Vary: Origin for an echoed origin, got %q", rec.Header().Get("Vary"))
+	}
+	if rec.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Error("expected no credentials without AllowCredentials")
+	}
+
+	for _, origin := range []string{"https://evil.example.com", "http://dashboard.example.com", "null"} {
+		if rec := corsRequest(routes, http.MethodGet, origin); rec.Code != http.StatusForbidden {
+			t.Errorf("origin %s: expected 403, got %d", origin, rec.Code)
+		}
+	}
+
+	// Requests without an Origin header aren't browsers and pass
+	if rec := corsRequest(routes, http.MethodGet, ""); rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("expected a request without Origin to pass untouched, got %d", rec.Code)
+	}
+}
+
+func TestCORS_SameOriginComparesScheme(t *testing.T) {
+	h := newTestHandler(t)
+	h.SetCORSPolicy(CORSPolicy{AllowedOrigins: []string{"https://dashboard.example.com"}})
+	routes := h.SetupRoutes()
+
+	if rec := corsRequest(routes, http.MethodPost, "http://api.example.com"); rec.Header().Get("Access-Control-Allow-Origin") != "" || rec.Code == http.StatusForbidden {
+		t.Errorf("expected a same-origin request to pass without CORS headers, got %d", rec.Code)
+	}
+	// Same host, other scheme: a different origin outside the policy
+	if rec := corsRequest(routes, http.MethodGet, "https://api.example.com"); rec.Code != http.StatusForbidden {
+		t.Errorf("expected https origin on an http request to be cross-origin, got %d", rec.Code)
+	}
+	withTLS := func(r *http.Request) { r.TLS = &tls.ConnectionState{} }
+	if rec := corsRequest(routes, http.MethodGet, "https://api.example.com", withTLS); rec.Code != http.StatusOK {
+		t.Errorf("expected https origin on a TLS request to be same-origin, got %d", rec.Code)
+	}
+	if rec := corsRequest(routes, http.MethodGet, "http://api.example.com", withTLS); rec.Code != http.StatusForbidden {
+		t.Errorf("expected http origin on a TLS request to be cross-origin, got %d", rec.Code)
+	}
+
+	// X-Forwarded-Proto counts only when the proxy headers are trusted
+	forwarded := func(r *http.Request) { r.Header.Set("X-Forwarded-Proto", "https") }
+	if rec := corsRequest(routes, http.MethodGet, "https://api.example.com", forwarded); rec.Code != http.StatusForbidden {
+		t.Errorf("expected an untrusted X-Forwarded-Proto to be ignored, got %d", rec.Code)
+	}
+	h.SetRateLimit(NewRateLimiter(1000, 1000, true))
+	if rec := corsRequest(routes, http.MethodGet, "https://api.example.com", forwarded); rec.Code != http.StatusOK {
+		t.Errorf("expected a trusted X-Forwarded-Proto to make the request same-origin, got %d", rec.Code)
+	}
+}
+
+func TestCORS_Wildcard(t *testing.T) {
+	routes := newTestHandler(t).SetupRoutes()
+
+	rec := corsRequest(routes, http.MethodGet, "https://anywhere.example.org")
+	if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("expected the default policy to allow any origin with *, got %d %q", rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if rec.Header().Get("Vary") != "" || rec.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Errorf("expected neither Vary nor credentials for *, got %v", rec.Header())
+	}
+}
+
+func TestCORS_Credentials(t *testing.T) {
+	h := newTestHandler(t)
+	h.SetCORSPolicy(CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+	routes := h.SetupRoutes()
+
+	// Credentials never go with *: the origin is echoed and caches vary on it
+	rec := corsRequest(routes, http.MethodGet, "https://dashboard.example.com")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://dashboard.example.com" {
+		t.Errorf("expected the origin to be echoed with credentials, got %q", got)
+	}
+	if rec.Header().Get("Access-Control-Allow-Credentials") != "true" || rec.Header().Get("Vary") != "Origin" {
+		t.Errorf("expected credentials and Vary: Origin, got %v", rec.Header())
+	}
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	h := newTestHandler(t)
+	h.SetCORSPolicy(CORSPolicy{
+		AllowedOrigins: []string{"https://dashboard.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+		MaxAge:         10 * time.Minute,
+	})
+	routes := h.SetupRoutes()
+
+	rec := corsRequest(routes, http.MethodOptions, "https://dashboard.example.com", func(r *http.Request) {
+		r.Header.Set("Access-Control-Request-Method", "POST")
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected preflight 200, got %d", rec.Code)
+	}
+	for header, want := range map[string]string{
+		"Access-Control-Allow-Origin":  "https://dashboard.example.com",
+		"Access-Control-Allow-Methods": "GET, POST",
+		"Access-Control-Allow-Headers": "Content-Type, Authorization",
+		"Access-Control-Max-Age":       "600",
+	} {
+		if got := rec.Header().Get(header); got != want {
+			t.Errorf("%s: expected %q, got %q", header, want, got)
+		}
+	}
+
+	if rec := corsRequest(routes, http.MethodOptions, "https://evil.example.com"); rec.Code != http.StatusForbidden {
+		t.Errorf("expected a preflight from a disallowed origin to get 403, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+
+	"incident-teller/internal/observability"
+)
+
+// dashboardHTML is a self-contained single-page dashboard built on the public API:
+// /api/incidents, /api/timeline-enhanced/{id}, /api/analyze and the /api/events stream
+//
+//go:embed dashboard/index.html
+var dashboardHTML []byte
+
+// SetDashboard enables the embedded web dashboard at GET /
+func (h *Handler) SetDashboard(enabled bool) {
+	h.dashboard = enabled
+}
+
+// handleDashboard serves the embedded dashboard
+func (h *Handler) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !h.dashboard {
+		h.writeError(w, http.StatusNotFound, "Dashboard not enabled")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := w.Write(dashboardHTML); err != nil {
+		h.logger.Error("Failed to write dashboard", observability.Error(err))
+	}
+}
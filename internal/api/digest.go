@@ -0,0 +1,128 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"incident-teller/internal/observability"
+	"incident-teller/internal/services"
+)
+
+// DigestCountResponse is one entry of a digest ranking
+type DigestCountResponse struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// DigestResponse is the JSON form of an incident digest
+type DigestResponse struct {
+	From          time.Time                  `json:"from"`
+	To            time.Time                  `json:"to"`
+	Incidents     int                        `json:"incidents"`
+	Resolved      int                        `json:"resolved"`
+	Active        int                        `json:"active"`
+	Critical      int                        `json:"critical"`
+	MTTRSeconds   float64                    `json:"mttr_seconds"`
+	MTTR          string                     `json:"mttr"`
+	TopRootCauses []DigestCountResponse      `json:"top_root_causes"`
+	NoisiestHosts []DigestCountResponse      `json:"noisiest_hosts"`
+	Longest       []IncidentListItemResponse `json:"longest"`
+}
+
+// handleDigest previews the incident digest for the last period (?period=7d, default 7d)
+// as the HTML email that is sent, or as JSON with ?format=json, in the requested time zone
+func (h *Handler) handleDigest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	period := 7 * 24 * time.Hour
+	if ps := r.URL.Query().Get("period"); ps != "" {
+		parsed, err := parsePeriod(ps)
+		if err != nil || parsed <= 0 {
+			h.writeError(w, http.StatusBadRequest, "Invalid period, use e.g. 1d, 7d or 12h")
+			return
+		}
+		period = parsed
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "html" && format != "json" {
+		h.writeError(w, http.StatusBadRequest, "Invalid format, use html or json")
+		return
+	}
+	location, msg := requestTimeZone(r)
+	if msg != "" {
+		h.writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	to := time.Now().In(location)
+	from := to.Add(-period)
+	incidents, err := h.incidentsInRange(r.Context(), from, to)
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve incidents")
+		return
+	}
+
+	builder := services.NewDigestBuilder()
+	builder.SetPropagationLearner(h.learner)
+	digest := builder.Build(incidents, from, to)
+	digest.Location = location
+
+	if format == "json" {
+		h.writeJSON(w, http.StatusOK, h.convertDigestToResponse(digest))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(services.DigestHTML(services.DigestDocument(digest)))); err != nil {
+		h.logger.Error("Failed to write digest", observability.Error(err))
+	}
+}
+
+func (h *Handler) convertDigestToResponse(digest services.Digest) DigestResponse {
+	counts := func(ranking []services.DigestCount) []DigestCountResponse {
+		result := make([]DigestCountResponse, len(ranking))
+		for i, c := range ranking {
+			result[i] = DigestCountResponse{Name: c.Name, Count: c.Count}
+		}
+		return result
+	}
+
+	longest := make([]IncidentListItemResponse, len(digest.Longest))
+	for i, incident := range digest.Longest {
+		longest[i] = h.convertIncidentToListItem(incident)
+	}
+
+	return DigestResponse{
+		From:          digest.From,
+		To:            digest.To,
+		Incidents:     digest.Incidents,
+		Resolved:      digest.Resolved,
+		Active:        digest.Active,
+		Critical:      digest.Critical,
+		MTTRSeconds:   digest.MTTR.Seconds(),
+		MTTR:          digest.MTTR.Round(time.Second).String(),
+		TopRootCauses: counts(digest.TopRootCauses),
+		NoisiestHosts: counts(digest.NoisiestHosts),
+		Longest:       longest,
+	}
+}
+
+// parsePeriod parses a duration that may also be given in days, e.g. "7d"
+func parsePeriod(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/environment"
+)
+
+// SetEnvironments enables the ?environment= filter of the incident and analytics endpoints
+// and the environment of listed incidents
+func (h *Handler) SetEnvironments(resolver *environment.Resolver) {
+	h.environments = resolver
+}
+
+// parseEnvironmentFilter reads the ?environment= parameter. On invalid input it returns a
+// message suitable for a 400 response.
+func (h *Handler) parseEnvironmentFilter(r *http.Request) (domain.LabelFilter, string) {
+	env := strings.TrimSpace(r.URL.Query().Get("environment"))
+	if env == "" {
+		return domain.LabelFilter{}, ""
+	}
+	if h.environments == nil {
+		return domain.LabelFilter{}, "Invalid environment: environments are not enabled"
+	}
+	return domain.LabelFilter{Key: h.environments.Label(), Value: env}, ""
+}
+
+// incidentEnvironment returns the environment of an incident, empty if environments are
+// disabled or it has none
+func (h *Handler) incidentEnvironment(incident domain.Incident) string {
+	if h.environments == nil {
+		return ""
+	}
+	return incident.Labels()[h.environments.Label()]
+}
+
+// filterIncidentsByEnvironment keeps the incidents passing the environment filter
+func filterIncidentsByEnvironment(incidents []domain.Incident, filter domain.LabelFilter) []domain.Incident {
+	if filter.IsZero() {
+		return incidents
+	}
+	matched := make([]domain.Incident, 0, len(incidents))
+	for _, incident := range incidents {
+		if filter.Matches(incident) {
+			matched = append(matched, incident)
+		}
+	}
+	return matched
+}
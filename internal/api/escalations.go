@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/ports"
+)
+
+// withEscalations adds the escalations of an incident to its timeline, in time order
+func (h *Handler) withEscalations(ctx context.Context, incident *domain.Incident, timeline []TimelineEventResponse) []TimelineEventResponse {
+	store, ok := h.repo.(ports.EscalationStore)
+	if !ok {
+		return timeline
+	}
+	escalations, err := store.GetEscalations(ctx, incident.ID)
+	if err != nil {
+		h.logger.Error("Failed to get escalations",
+			observability.String("incident_id", incident.ID), observability.Error(err))
+		return timeline
+	}
+	if len(escalations) == 0 {
+		return timeline
+	}
+
+	for _, escalation := range escalations {
+		duration := escalation.EscalatedAt.Sub(incident.StartedAt).String()
+		timeline = append(timeline, TimelineEventResponse{
+			Timestamp: escalation.EscalatedAt,
+			Type:      "ESCALATED",
+			Message: fmt.Sprintf("Unacknowledged %s incident escalated to level %d: %s",
+				escalation.Priority, escalation.Level, escalation.Target),
+			Severity:           "warning",
+			DurationSinceStart: &duration,
+		})
+	}
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].Timestamp.Before(timeline[j].Timestamp) })
+	return timeline
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+)
+
+// IncidentRangeRepository is implemented by repositories that can select incidents by start time
+type IncidentRangeRepository interface {
+	GetIncidentsByTimeRange(ctx context.Context, start, end time.Time) ([]domain.Incident, error)
+}
+
+// IncidentExportRecord is one incident in an export file
+type IncidentExportRecord struct {
+	ID              string     `json:"id"`
+	Title           string     `json:"title"`
+	Status          string     `json:"status"`
+	StartedAt       time.Time  `json:"started_at"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	Ongoing         bool       `json:"ongoing"`
+	RiskLevel       string     `json:"risk_level"`
+	RootCause       string     `json:"root_cause"`
+	TotalEvents     int        `json:"total_events"`
+	AffectedHosts   []string   `json:"affected_hosts"`
+	Assignee        string     `json:"assignee,omitempty"`
+}
+
+var incidentExportColumns = []string{
+	"id", "title", "status", "started_at", "resolved_at", "duration_seconds", "ongoing",
+	"risk_level", "root_cause", "total_events", "affected_hosts", "assignee",
+}
+
+// handleIncidentExport streams incidents started in [from, to] as a CSV or JSON download
+func (h *Handler) handleIncidentExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	params := r.URL.Query()
+
+	format := strings.ToLower(params.Get("format"))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		h.writeError(w, http.StatusBadRequest, "Invalid format: must be csv or json")
+		return
+	}
+
+	now := time.Now().UTC()
+	from := time.Time{}
+	to := now
+	if v := params.Get("from"); v != "" {
+		parsed, _, err := parseExportTime(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid from: must be RFC3339 or YYYY-MM-DD")
+			return
+		}
+		from = parsed
+	}
+	if v := params.Get("to"); v != "" {
+		parsed, dateOnly, err := parseExportTime(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid to: must be RFC3339 or YYYY-MM-DD")
+			return
+		}
+		to = parsed
+		if dateOnly {
+			// A bare date includes the whole day
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+	}
+	if to.Before(from) {
+		h.writeError(w, http.StatusBadRequest, "Invalid range: to is before from")
+		return
+	}
+
+	incidents, err := h.incidentsInRange(r.Context(), from, to)
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve incidents")
+		return
+	}
+
+	records := make([]IncidentExportRecord, len(incidents))
+	for i, incident := range incidents {
+		records[i] = h.incidentExportRecord(incident, now)
+	}
+
+	filename := fmt.Sprintf("incidents-%s-%s.%s", exportFilenameDate(from), to.Format("20060102"), format)
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+
+	if format == "json" {
+		h.writeJSON(w, http.StatusOK, records)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	cw.Write(incidentExportColumns)
+	for _, rec := range records {
+		resolvedAt := ""
+		if rec.ResolvedAt != nil {
+			resolvedAt = rec.ResolvedAt.UTC().Format(time.RFC3339)
+		}
+		cw.Write([]string{
+			rec.ID,
+			rec.Title,
+			rec.Status,
+			rec.StartedAt.UTC().Format(time.RFC3339),
+			resolvedAt,
+			strconv.FormatInt(rec.DurationSeconds, 10),
+			strconv.FormatBool(rec.Ongoing),
+			rec.RiskLevel,
+			rec.RootCause,
+			strconv.Itoa(rec.TotalEvents),
+			strings.Join(rec.AffectedHosts, ";"),
+			rec.Assignee,
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		h.logger.Error("Failed to write incident export", observability.Error(err))
+	}
+}
+
+// incidentsInRange returns incidents started within [from, to], oldest first
+func (h *Handler) incidentsInRange(ctx context.Context, from, to time.Time) ([]domain.Incident, error) {
+	var incidents []domain.Incident
+	if repo, ok := h.repo.(IncidentRangeRepository); ok {
+		found, err := repo.GetIncidentsByTimeRange(ctx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		incidents = found
+	} else {
+		all, err := h.repo.GetIncidents(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, incident := range all {
+			if !incident.StartedAt.Before(from) && !incident.StartedAt.After(to) {
+				incidents = append(incidents, incident)
+			}
+		}
+	}
+
+	return domain.ApplyIncidentQuery(incidents, domain.IncidentQuery{Ascending: true}, time.Now()), nil
+}
+
+func (h *Handler) incidentExportRecord(incident domain.Incident, now time.Time) IncidentExportRecord {
+	return IncidentExportRecord{
+		ID:              incident.ID,
+		Title:           incident.Title,
+		Status:          string(incident.Status),
+		StartedAt:       incident.StartedAt,
+		ResolvedAt:      incident.ResolvedAt,
+		DurationSeconds: int64(incident.Duration(now) / time.Second),
+		Ongoing:         incident.ResolvedAt == nil,
+		RiskLevel:       incident.RiskLevel(),
+		RootCause:       h.identifyPrimaryRootCause(incident),
+		TotalEvents:     len(incident.Events),
+		AffectedHosts:   incident.Hosts(),
+		Assignee:        h.incidentAssignee(incident.ID),
+	}
+}
+
+// parseExportTime accepts RFC3339 timestamps and bare YYYY-MM-DD dates (UTC)
+func parseExportTime(s string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	return t, true, err
+}
+
+func exportFilenameDate(t time.Time) string {
+	if t.IsZero() {
+		return "all"
+	}
+	return t.UTC().Format("20060102")
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/exporter"
+	"incident-teller/internal/observability"
+)
+
+// SetExporter publishes incidents saved through the API and incident analyses to the
+// configured event exporters
+func (h *Handler) SetExporter(e *exporter.Exporter) {
+	h.exporter = e
+}
+
+// exportIncident publishes the lifecycle event of a saved incident. Export failures are
+// logged rather than failing the request.
+func (h *Handler) exportIncident(ctx context.Context, incident domain.Incident) {
+	if h.exporter == nil {
+		return
+	}
+	if err := h.exporter.IncidentSaved(ctx, incident); err != nil {
+		h.logger.Warn("Failed to export incident event",
+			observability.String("incident_id", incident.ID), observability.Error(err))
+	}
+}
+
+// exportAnalysis publishes the analysis of an incident
+func (h *Handler) exportAnalysis(ctx context.Context, incidentID string, analysis *AIAnalysisResponse) {
+	if h.exporter == nil {
+		return
+	}
+	if err := h.exporter.AnalysisCompleted(ctx, incidentID, analysis); err != nil {
+		h.logger.Warn("Failed to export analysis event",
+			observability.String("incident_id", incidentID), observability.Error(err))
+	}
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"incident-teller/internal/faults"
+	"incident-teller/internal/observability"
+)
+
+// FaultsRequest sets the faults injected into the pipeline
+type FaultsRequest struct {
+	RepositoryLatency string `json:"repository_latency,omitempty"` // e.g. "2s"
+	NetdataTimeout    bool   `json:"netdata_timeout,omitempty"`
+	AIFailure         bool   `json:"ai_failure,omitempty"`
+	Duration          string `json:"duration,omitempty"` // Faults clear themselves after it, e.g. "15m"; empty keeps them until deleted
+}
+
+// FaultsResponse reports the faults being injected
+type FaultsResponse struct {
+	Active            bool       `json:"active"`
+	RepositoryLatency string     `json:"repository_latency,omitempty"`
+	NetdataTimeout    bool       `json:"netdata_timeout"`
+	AIFailure         bool       `json:"ai_failure"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+}
+
+// SetFaults enables /api/admin/faults, injecting failures into the pipeline to test how
+// IncidentTeller degrades. Requests need the admin token.
+func (h *Handler) SetFaults(injector *faults.Injector) {
+	h.faults = injector
+}
+
+// handleAdminFaults reports (GET), sets (PUT) or clears (DELETE) the injected faults
+func (h *Handler) handleAdminFaults(w http.ResponseWriter, r *http.Request) {
+	if h.faults == nil || h.adminToken == "" {
+		h.writeError(w, http.StatusNotFound, "Fault injection not enabled")
+		return
+	}
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	previous := convertFaultsToResponse(h.faults.Current())
+	switch r.Method {
+	case http.MethodGet:
+		h.writeJSON(w, http.StatusOK, previous)
+
+	case http.MethodPut:
+		var req FaultsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		var injected faults.Faults
+		if req.RepositoryLatency != "" {
+			latency, err := time.ParseDuration(req.RepositoryLatency)
+			if err != nil || latency < 0 {
+				h.writeError(w, http.StatusBadRequest, "Invalid repository_latency")
+				return
+			}
+			injected.RepositoryLatency = latency
+		}
+		if req.Duration != "" {
+			duration, err := time.ParseDuration(req.Duration)
+			if err != nil || duration <= 0 {
+				h.writeError(w, http.StatusBadRequest, "Invalid duration")
+				return
+			}
+			injected.ExpiresAt = time.Now().Add(duration)
+		}
+		injected.NetdataTimeout = req.NetdataTimeout
+		injected.AIFailure = req.AIFailure
+
+		h.faults.Set(injected)
+		updated := convertFaultsToResponse(injected)
+		auditChange(r, previous, updated)
+
+		h.logger.Warn("Fault injection enabled", observability.String("faults", describeFaults(injected)))
+		h.writeJSON(w, http.StatusOK, updated)
+
+	case http.MethodDelete:
+		h.faults.Clear()
+		auditChange(r, previous, FaultsResponse{})
+		h.logger.Info("Fault injection cleared")
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func convertFaultsToResponse(f faults.Faults) FaultsResponse {
+	resp := FaultsResponse{
+		Active:         f.Active(),
+		NetdataTimeout: f.NetdataTimeout,
+		AIFailure:      f.AIFailure,
+	}
+	if f.RepositoryLatency > 0 {
+		resp.RepositoryLatency = f.RepositoryLatency.String()
+	}
+	if !f.ExpiresAt.IsZero() && f.Active() {
+		expiresAt := f.ExpiresAt
+		resp.ExpiresAt = &expiresAt
+	}
+	return resp
+}
+
+// describeFaults lists the injected faults, e.g. "repository latency 2s, Netdata timeouts"
+func describeFaults(f faults.Faults) string {
+	var parts []string
+	if f.RepositoryLatency > 0 {
+		parts = append(parts, fmt.Sprintf("repository latency %s", f.RepositoryLatency))
+	}
+	if f.NetdataTimeout {
+		parts = append(parts, "Netdata timeouts")
+	}
+	if f.AIFailure {
+		parts = append(parts, "AI failures")
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"incident-teller/internal/api/openapi"
+	"incident-teller/internal/config"
+)
+
+// featureRoutes maps the routes of subsystems that can be turned off to their feature
+var featureRoutes = map[string]string{
+	"/api/analyze":                             config.FeatureAI,
+	"/api/ai/models":                           config.FeatureAI,
+	"/api/incidents/{id}/analysis/status":      config.FeatureAI,
+	"/api/incidents/{id}/root-causes":          config.FeatureAI,
+	"/api/incidents/{id}/root-causes/feedback": config.FeatureAI,
+	"/api/events":                              config.FeatureSSE,
+	"/api/incidents/export":                    config.FeatureExports,
+	"/api/metrics/export":                      config.FeatureExports,
+	"/api/snapshots":                           config.FeatureExports,
+	"/api/incidents/{id}/timeline/export":      config.FeatureExports,
+}
+
+// SetFeatures turns subsystems off or on, e.g. {"sse": false}; the routes of those turned
+// off are neither served nor documented. Call it before SetupRoutes.
+func (h *Handler) SetFeatures(features map[string]bool) {
+	h.features = features
+}
+
+// featureEnabled reports whether a subsystem is on; unlisted ones are
+func (h *Handler) featureEnabled(feature string) bool {
+	enabled, ok := h.features[feature]
+	return !ok || enabled
+}
+
+// enabledRoutes leaves out the routes of the subsystems turned off
+func (h *Handler) enabledRoutes(routes []openapi.Route) []openapi.Route {
+	enabled := routes[:0]
+	for _, route := range routes {
+		if feature, ok := featureRoutes[route.Pattern]; ok && !h.featureEnabled(feature) {
+			continue
+		}
+		enabled = append(enabled, route)
+	}
+	return enabled
+}
@@ -0,0 +1,321 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+)
+
+// Grafana JSON data source targets
+const (
+	grafanaTargetIncidents     = "incidents"      // Incidents started per interval
+	grafanaTargetOpenIncidents = "open_incidents" // Incidents open at the end of each interval
+	grafanaTargetMTTR          = "mttr"           // Mean seconds to resolve the incidents resolved per interval
+)
+
+var grafanaTargets = []string{grafanaTargetIncidents, grafanaTargetOpenIncidents, grafanaTargetMTTR}
+
+// maxGrafanaPoints caps the points of a series; larger ranges get longer intervals
+const maxGrafanaPoints = 10000
+
+// GrafanaRange is the time range of a Grafana panel
+type GrafanaRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// GrafanaTarget is one query of a Grafana panel
+type GrafanaTarget struct {
+	Target string `json:"target"`
+	RefID  string `json:"refId,omitempty"`
+	Type   string `json:"type,omitempty"` // timeserie (default) or table
+}
+
+// GrafanaQueryRequest is the body Grafana posts to /query
+type GrafanaQueryRequest struct {
+	Range         GrafanaRange    `json:"range"`
+	IntervalMs    int64           `json:"intervalMs"`
+	MaxDataPoints int             `json:"maxDataPoints"`
+	Targets       []GrafanaTarget `json:"targets"`
+}
+
+// GrafanaTimeSeries is a series of [value, unix milliseconds] points
+type GrafanaTimeSeries struct {
+	Target     string       `json:"target"`
+	RefID      string       `json:"refId,omitempty"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+// GrafanaColumn is a column of a Grafana table
+type GrafanaColumn struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+// GrafanaTable is the incidents in the range as a table
+type GrafanaTable struct {
+	Type    string          `json:"type"`
+	RefID   string          `json:"refId,omitempty"`
+	Columns []GrafanaColumn `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// GrafanaAnnotationRequest is the body Grafana posts to /annotations
+type GrafanaAnnotationRequest struct {
+	Range      GrafanaRange    `json:"range"`
+	Annotation json.RawMessage `json:"annotation"`
+}
+
+// GrafanaAnnotation is an incident shown as a region on Grafana panels
+type GrafanaAnnotation struct {
+	Annotation json.RawMessage `json:"annotation,omitempty"`
+	Time       int64           `json:"time"`
+	TimeEnd    int64           `json:"timeEnd"`
+	IsRegion   bool            `json:"isRegion"`
+	Title      string          `json:"title"`
+	Text       string          `json:"text"`
+	Tags       []string        `json:"tags"`
+}
+
+// handleGrafanaRoot answers Grafana's data source connection test
+func (h *Handler) handleGrafanaRoot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleGrafanaSearch lists the targets a panel can query
+func (h *Handler) handleGrafanaSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, grafanaTargets)
+}
+
+// handleGrafanaQuery returns a series per target, or the incidents as a table for table targets
+func (h *Handler) handleGrafanaQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req GrafanaQueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if !req.Range.To.After(req.Range.From) {
+		h.writeError(w, http.StatusBadRequest, "Invalid range: to must be after from")
+		return
+	}
+	for _, target := range req.Targets {
+		if target.Type != "table" && !isGrafanaTarget(target.Target) {
+			h.writeError(w, http.StatusBadRequest, fmt.Sprintf("Unknown target %q: must be one of %s",
+				target.Target, strings.Join(grafanaTargets, ", ")))
+			return
+		}
+	}
+
+	incidents, err := h.incidentsOverlapping(r, req.Range)
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+
+	interval := grafanaInterval(req)
+	results := make([]interface{}, 0, len(req.Targets))
+	for _, target := range req.Targets {
+		if target.Type == "table" {
+			results = append(results, grafanaIncidentTable(target.RefID, incidents, req.Range))
+			continue
+		}
+		results = append(results, GrafanaTimeSeries{
+			Target:     target.Target,
+			RefID:      target.RefID,
+			Datapoints: grafanaSeries(target.Target, incidents, req.Range, interval),
+		})
+	}
+	h.writeJSON(w, http.StatusOK, results)
+}
+
+// handleGrafanaAnnotations returns the incidents in the range as regions. The annotation's
+// query text, if any, keeps the incidents matching it, e.g. a host.
+func (h *Handler) handleGrafanaAnnotations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req GrafanaAnnotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if !req.Range.To.After(req.Range.From) {
+		h.writeError(w, http.StatusBadRequest, "Invalid range: to must be after from")
+		return
+	}
+	var annotation struct {
+		Query string `json:"query"`
+	}
+	if len(req.Annotation) > 0 {
+		json.Unmarshal(req.Annotation, &annotation)
+	}
+
+	incidents, err := h.incidentsOverlapping(r, req.Range)
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+
+	now := time.Now()
+	annotations := []GrafanaAnnotation{}
+	for _, incident := range incidents {
+		if annotation.Query != "" && !incident.Matches(annotation.Query) {
+			continue
+		}
+		end := now
+		if incident.ResolvedAt != nil {
+			end = *incident.ResolvedAt
+		}
+		tags := append([]string{string(incident.Priority()), incident.RiskLevel()}, incident.Hosts()...)
+		annotations = append(annotations, GrafanaAnnotation{
+			Annotation: req.Annotation,
+			Time:       incident.StartedAt.UnixMilli(),
+			TimeEnd:    end.UnixMilli(),
+			IsRegion:   true,
+			Title:      incident.Title,
+			Text: fmt.Sprintf("Incident %s: %d events, %s", incident.ID, len(incident.Events),
+				h.calculateDuration(incident)),
+			Tags: append(tags, incident.Tags...),
+		})
+	}
+	h.writeJSON(w, http.StatusOK, annotations)
+}
+
+// incidentsOverlapping returns the incidents that were open at some point of the range
+func (h *Handler) incidentsOverlapping(r *http.Request, rng GrafanaRange) ([]domain.Incident, error) {
+	started, err := h.incidentsInRange(r.Context(), time.Time{}, rng.To)
+	if err != nil {
+		return nil, err
+	}
+	incidents := make([]domain.Incident, 0, len(started))
+	for _, incident := range started {
+		if incident.ResolvedAt == nil || !incident.ResolvedAt.Before(rng.From) {
+			incidents = append(incidents, incident)
+		}
+	}
+	return incidents, nil
+}
+
+func isGrafanaTarget(target string) bool {
+	for _, t := range grafanaTargets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+// grafanaInterval returns the panel's interval, lengthened so a series stays within
+// maxDataPoints and maxGrafanaPoints
+func grafanaInterval(req GrafanaQueryRequest) time.Duration {
+	span := req.Range.To.Sub(req.Range.From)
+	interval := time.Duration(req.IntervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	maxPoints := maxGrafanaPoints
+	if req.MaxDataPoints > 0 && req.MaxDataPoints < maxPoints {
+		maxPoints = req.MaxDataPoints
+	}
+	if minInterval := span / time.Duration(maxPoints); interval < minInterval {
+		interval = minInterval
+	}
+	return interval
+}
+
+// grafanaSeries computes a target's points at the start of each interval of the range.
+// Intervals without resolved incidents have no MTTR point.
+func grafanaSeries(target string, incidents []domain.Incident, rng GrafanaRange, interval time.Duration) [][2]float64 {
+	points := [][2]float64{}
+	for start := rng.From.Truncate(interval); start.Before(rng.To); start = start.Add(interval) {
+		end := start.Add(interval)
+		var count int
+		var resolvedFor time.Duration
+		for _, incident := range incidents {
+			switch target {
+			case grafanaTargetIncidents:
+				if !incident.StartedAt.Before(start) && incident.StartedAt.Before(end) {
+					count++
+				}
+			case grafanaTargetOpenIncidents:
+				if incident.StartedAt.Before(end) && (incident.ResolvedAt == nil || !incident.ResolvedAt.Before(end)) {
+					count++
+				}
+			case grafanaTargetMTTR:
+				if incident.ResolvedAt != nil && !incident.ResolvedAt.Before(start) && incident.ResolvedAt.Before(end) {
+					count++
+					resolvedFor += incident.ResolvedAt.Sub(incident.StartedAt)
+				}
+			}
+		}
+
+		value := float64(count)
+		if target == grafanaTargetMTTR {
+			if count == 0 {
+				continue
+			}
+			value = (resolvedFor / time.Duration(count)).Seconds()
+		}
+		points = append(points, [2]float64{value, float64(start.UnixMilli())})
+	}
+	return points
+}
+
+// grafanaIncidentTable lists the incidents that started in the range, newest first
+func grafanaIncidentTable(refID string, incidents []domain.Incident, rng GrafanaRange) GrafanaTable {
+	table := GrafanaTable{
+		Type:  "table",
+		RefID: refID,
+		Columns: []GrafanaColumn{
+			{Text: "Time", Type: "time"},
+			{Text: "ID", Type: "string"},
+			{Text: "Title", Type: "string"},
+			{Text: "Priority", Type: "string"},
+			{Text: "Status", Type: "string"},
+			{Text: "Hosts", Type: "string"},
+			{Text: "Duration (s)", Type: "number"},
+		},
+		Rows: [][]interface{}{},
+	}
+
+	now := time.Now()
+	for _, incident := range incidents {
+		if incident.StartedAt.Before(rng.From) {
+			continue
+		}
+		table.Rows = append(table.Rows, []interface{}{
+			incident.StartedAt.UnixMilli(),
+			incident.ID,
+			incident.Title,
+			string(incident.Priority()),
+			string(incident.Status),
+			strings.Join(incident.Hosts(), ", "),
+			int64(incident.Duration(now).Seconds()),
+		})
+	}
+	sort.SliceStable(table.Rows, func(i, j int) bool { return table.Rows[i][0].(int64) > table.Rows[j][0].(int64) })
+	return table
+}
@@ -0,0 +1,453 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+
+	"incident-teller/internal/domain"
+)
+
+const (
+	graphQLDefaultLimit = 20
+	graphQLMaxLimit     = 500
+)
+
+// GraphQLRequest is a GraphQL query sent to /api/graphql
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operationName,omitempty"`
+}
+
+// SetGraphQL enables the read-only GraphQL endpoint at /api/graphql
+func (h *Handler) SetGraphQL(enabled bool) {
+	if !enabled {
+		h.graphqlSchema = nil
+		return
+	}
+	schema, err := h.newGraphQLSchema()
+	if err != nil {
+		// The schema is static, so this only fails if it is defined incorrectly
+		panic(fmt.Sprintf("invalid GraphQL schema: %v", err))
+	}
+	h.graphqlSchema = &schema
+}
+
+// handleGraphQL executes a GraphQL query given as a JSON body (POST) or as
+// ?query=&variables= parameters (GET)
+func (h *Handler) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.graphqlSchema == nil {
+		h.writeError(w, http.StatusNotFound, "GraphQL not enabled")
+		return
+	}
+
+	var req GraphQLRequest
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid JSON body")
+			return
+		}
+	} else {
+		params := r.URL.Query()
+		req.Query = params.Get("query")
+		req.OperationName = params.Get("operationName")
+		if variables := params.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				h.writeError(w, http.StatusBadRequest, "Invalid variables")
+				return
+			}
+		}
+	}
+	if req.Query == "" {
+		h.writeError(w, http.StatusBadRequest, "Missing query")
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         *h.graphqlSchema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        r.Context(),
+	})
+	// Query errors are reported in the result with a 200, as GraphQL clients expect
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+// gqlField resolves a field from its parent value, which is of type T
+func gqlField[T any](typ graphql.Output, resolve func(T) any) *graphql.Field {
+	return &graphql.Field{Type: typ, Resolve: func(p graphql.ResolveParams) (any, error) {
+		return resolve(p.Source.(T)), nil
+	}}
+}
+
+// gqlLimit reads the limit argument of a list field
+func gqlLimit(p graphql.ResolveParams) int {
+	limit, ok := p.Args["limit"].(int)
+	if !ok || limit <= 0 {
+		return graphQLDefaultLimit
+	}
+	return min(limit, graphQLMaxLimit)
+}
+
+// gqlPairs returns the entries of a map as key/value pairs sorted by key, e.g. for labels
+func gqlPairs(m map[string]string) [][2]string {
+	pairs := make([][2]string, 0, len(m))
+	for key, value := range m {
+		pairs = append(pairs, [2]string{key, value})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i][0] < pairs[j][0] })
+	return pairs
+}
+
+var gqlLimitArg = &graphql.ArgumentConfig{Type: graphql.Int, Description: "Maximum number of items; defaults to 20, at most 500"}
+
+// newGraphQLSchema defines the GraphQL schema. Each nested field is resolved only when
+// it is requested, so e.g. the AI root cause is only computed for queries that select it.
+func (h *Handler) newGraphQLSchema() (graphql.Schema, error) {
+	str := graphql.String
+	nonNullStr := graphql.NewNonNull(graphql.String)
+	strList := graphql.NewList(nonNullStr)
+
+	labelType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Label",
+		Fields: graphql.Fields{
+			"key":   gqlField(nonNullStr, func(l [2]string) any { return l[0] }),
+			"value": gqlField(nonNullStr, func(l [2]string) any { return l[1] }),
+		},
+	})
+
+	sampleType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "MetricSample",
+		Description: "A value of a chart at a point in time",
+		Fields: graphql.Fields{
+			"at":    gqlField(graphql.NewNonNull(graphql.DateTime), func(s domain.MetricSample) any { return s.At }),
+			"value": gqlField(graphql.NewNonNull(graphql.Float), func(s domain.MetricSample) any { return s.Value }),
+		},
+	})
+
+	alertType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Alert",
+		Description: "A state change of a monitored chart",
+		Fields: graphql.Fields{
+			"id":           gqlField(graphql.NewNonNull(graphql.ID), func(a domain.Alert) any { return a.ID }),
+			"host":         gqlField(nonNullStr, func(a domain.Alert) any { return a.Host }),
+			"chart":        gqlField(nonNullStr, func(a domain.Alert) any { return a.Chart }),
+			"family":       gqlField(str, func(a domain.Alert) any { return a.Family }),
+			"name":         gqlField(nonNullStr, func(a domain.Alert) any { return a.Name }),
+			"status":       gqlField(nonNullStr, func(a domain.Alert) any { return string(a.Status) }),
+			"oldStatus":    gqlField(str, func(a domain.Alert) any { return string(a.OldStatus) }),
+			"value":        gqlField(graphql.Float, func(a domain.Alert) any { return a.Value }),
+			"occurredAt":   gqlField(graphql.NewNonNull(graphql.DateTime), func(a domain.Alert) any { return a.OccurredAt }),
+			"description":  gqlField(str, func(a domain.Alert) any { return a.Description }),
+			"resourceType": gqlField(str, func(a domain.Alert) any { return string(a.ResourceType) }),
+			"source":       gqlField(str, func(a domain.Alert) any { return a.Source }),
+			"labels":       gqlField(graphql.NewList(labelType), func(a domain.Alert) any { return gqlPairs(a.Labels) }),
+			"samples":      gqlField(graphql.NewList(sampleType), func(a domain.Alert) any { return a.Samples }),
+		},
+	})
+
+	timelineEventType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TimelineEvent",
+		Fields: graphql.Fields{
+			"timestamp":    gqlField(graphql.NewNonNull(graphql.DateTime), func(e TimelineEventResponse) any { return e.Timestamp }),
+			"type":         gqlField(nonNullStr, func(e TimelineEventResponse) any { return e.Type }),
+			"message":      gqlField(nonNullStr, func(e TimelineEventResponse) any { return e.Message }),
+			"severity":     gqlField(nonNullStr, func(e TimelineEventResponse) any { return e.Severity }),
+			"resourceType": gqlField(str, func(e TimelineEventResponse) any { return e.ResourceType }),
+			"source":       gqlField(str, func(e TimelineEventResponse) any { return e.Source }),
+			"causedBy":     gqlField(strList, func(e TimelineEventResponse) any { return e.CausedBy }),
+			"samples":      gqlField(graphql.NewList(sampleType), func(e TimelineEventResponse) any { return e.Samples }),
+			"durationSinceStart": gqlField(str, func(e TimelineEventResponse) any {
+				if e.DurationSinceStart == nil {
+					return nil
+				}
+				return *e.DurationSinceStart
+			}),
+		},
+	})
+
+	alternativeCauseType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "AlternativeCause",
+		Fields: graphql.Fields{
+			"alertId":      gqlField(str, func(c AlternativeCauseResponse) any { return c.AlertID }),
+			"resourceType": gqlField(str, func(c AlternativeCauseResponse) any { return c.ResourceType }),
+			"chart":        gqlField(str, func(c AlternativeCauseResponse) any { return c.Chart }),
+			"host":         gqlField(str, func(c AlternativeCauseResponse) any { return c.Host }),
+			"confidence":   gqlField(graphql.Float, func(c AlternativeCauseResponse) any { return c.Confidence }),
+		},
+	})
+
+	rootCauseType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "RootCause",
+		Description: "AI root cause prediction, or the root cause pinned by a responder",
+		Fields: graphql.Fields{
+			"alertId":           gqlField(str, func(c *RootCauseResponse) any { return c.AlertID }),
+			"resourceType":      gqlField(str, func(c *RootCauseResponse) any { return c.ResourceType }),
+			"chart":             gqlField(str, func(c *RootCauseResponse) any { return c.Chart }),
+			"host":              gqlField(str, func(c *RootCauseResponse) any { return c.Host }),
+			"confidence":        gqlField(graphql.Float, func(c *RootCauseResponse) any { return c.Confidence }),
+			"patternType":       gqlField(str, func(c *RootCauseResponse) any { return c.PatternType }),
+			"reasoning":         gqlField(str, func(c *RootCauseResponse) any { return c.Reasoning }),
+			"pinned":            gqlField(graphql.Boolean, func(c *RootCauseResponse) any { return c.Pinned }),
+			"alternativeCauses": gqlField(graphql.NewList(alternativeCauseType), func(c *RootCauseResponse) any { return c.AlternativeCauses }),
+		},
+	})
+
+	blastRadiusType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "BlastRadius",
+		Description: "AI impact prediction",
+		Fields: graphql.Fields{
+			"impactScore":        gqlField(graphql.Float, func(b *BlastRadiusResponse) any { return b.ImpactScore }),
+			"affectedServices":   gqlField(strList, func(b *BlastRadiusResponse) any { return b.AffectedServices }),
+			"cascadeProbability": gqlField(graphql.Float, func(b *BlastRadiusResponse) any { return b.CascadeProbability }),
+			"durationPredicted":  gqlField(str, func(b *BlastRadiusResponse) any { return b.DurationPredicted }),
+			"businessImpact":     gqlField(str, func(b *BlastRadiusResponse) any { return b.BusinessImpact }),
+			"riskLevel":          gqlField(str, func(b *BlastRadiusResponse) any { return b.RiskLevel }),
+		},
+	})
+
+	recommendationsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Recommendations",
+		Fields: graphql.Fields{
+			"immediate": gqlField(strList, func(r RecommendationsResponse) any { return r.Immediate }),
+			"shortTerm": gqlField(strList, func(r RecommendationsResponse) any { return r.ShortTerm }),
+			"longTerm":  gqlField(strList, func(r RecommendationsResponse) any { return r.LongTerm }),
+		},
+	})
+
+	analysisType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Analysis",
+		Description: "The story of an incident",
+		Fields: graphql.Fields{
+			"summary":          gqlField(str, func(a *AIAnalysisResponse) any { return a.Summary }),
+			"narrative":        gqlField(str, func(a *AIAnalysisResponse) any { return a.Narrative }),
+			"rootCause":        gqlField(str, func(a *AIAnalysisResponse) any { return a.RootCauseText }),
+			"impactAssessment": gqlField(str, func(a *AIAnalysisResponse) any { return a.ImpactAssessment }),
+			"recommendations":  gqlField(recommendationsType, func(a *AIAnalysisResponse) any { return a.Recommendations }),
+			"generatedAt":      gqlField(graphql.DateTime, func(a *AIAnalysisResponse) any { return a.GeneratedAt }),
+			"alertCount":       gqlField(graphql.Int, func(a *AIAnalysisResponse) any { return a.AlertCount }),
+		},
+	})
+
+	incidentType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Incident",
+		Fields: graphql.Fields{
+			"id":               gqlField(graphql.NewNonNull(graphql.ID), func(i *domain.Incident) any { return i.ID }),
+			"title":            gqlField(nonNullStr, func(i *domain.Incident) any { return i.Title }),
+			"status":           gqlField(nonNullStr, func(i *domain.Incident) any { return string(i.Status) }),
+			"state":            gqlField(nonNullStr, func(i *domain.Incident) any { return string(i.CurrentState()) }),
+			"startedAt":        gqlField(graphql.NewNonNull(graphql.DateTime), func(i *domain.Incident) any { return i.StartedAt }),
+			"resolvedAt":       gqlField(graphql.DateTime, func(i *domain.Incident) any { return i.ResolvedAt }),
+			"active":           gqlField(graphql.NewNonNull(graphql.Boolean), func(i *domain.Incident) any { return i.ResolvedAt == nil }),
+			"duration":         gqlField(nonNullStr, func(i *domain.Incident) any { return h.calculateDuration(*i) }),
+			"riskLevel":        gqlField(nonNullStr, func(i *domain.Incident) any { return h.calculateRiskLevel(*i) }),
+			"priority":         gqlField(nonNullStr, func(i *domain.Incident) any { return string(h.incidentPriority(*i)) }),
+			"totalEvents":      gqlField(graphql.NewNonNull(graphql.Int), func(i *domain.Incident) any { return len(i.Events) }),
+			"primaryRootCause": gqlField(str, func(i *domain.Incident) any { return h.identifyPrimaryRootCause(*i) }),
+			"assignee":         gqlField(str, func(i *domain.Incident) any { return h.incidentAssignee(i.ID) }),
+			"severity":         gqlField(str, func(i *domain.Incident) any { return i.Severity }),
+			"tags":             gqlField(graphql.NewNonNull(strList), func(i *domain.Incident) any { return incidentTags(*i) }),
+			"customFields":     gqlField(graphql.NewNonNull(graphql.NewList(labelType)), func(i *domain.Incident) any { return gqlPairs(i.CustomFields) }),
+			"acknowledgedBy": gqlField(str, func(i *domain.Incident) any {
+				if ack := h.incidentAcknowledgement(i.ID); ack != nil {
+					return ack.By
+				}
+				return nil
+			}),
+			"acknowledgedAt": gqlField(graphql.DateTime, func(i *domain.Incident) any {
+				if ack := h.incidentAcknowledgement(i.ID); ack != nil {
+					return ack.AcknowledgedAt
+				}
+				return nil
+			}),
+			"alerts": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(alertType))),
+				Args: graphql.FieldConfigArgument{"limit": gqlLimitArg},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					events := p.Source.(*domain.Incident).Events
+					return events[:min(len(events), gqlLimit(p))], nil
+				},
+			},
+			"timeline": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(timelineEventType))),
+				Description: "Chronological events, earliest first",
+				Args:        graphql.FieldConfigArgument{"limit": gqlLimitArg},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					timeline := h.incidentTimeline(p.Context, p.Source.(*domain.Incident))
+					return timeline[:min(len(timeline), gqlLimit(p))], nil
+				},
+			},
+			"rootCause": {
+				Type: rootCauseType,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					incident := p.Source.(*domain.Incident)
+					if h.aiModel == nil || len(incident.Events) == 0 {
+						if rootCause := pinRootCause(*incident, nil); rootCause != nil {
+							return rootCause, nil
+						}
+						return nil, nil
+					}
+					rootCause, err := h.aiModel.PredictRootCause(p.Context, incident.Events)
+					if err != nil {
+						return nil, err
+					}
+					return pinRootCause(*incident, h.convertRootCauseToResponse(rootCause)), nil
+				},
+			},
+			"blastRadius": {
+				Type: blastRadiusType,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					incident := p.Source.(*domain.Incident)
+					if h.aiModel == nil || len(incident.Events) == 0 {
+						return nil, nil
+					}
+					blastRadius, err := h.aiModel.PredictBlastRadius(p.Context, incident.Events)
+					if err != nil {
+						return nil, err
+					}
+					return h.convertBlastRadiusToResponse(blastRadius), nil
+				},
+			},
+			"analysis": {
+				Type: analysisType,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					incident := p.Source.(*domain.Incident)
+					if len(incident.Events) == 0 {
+						return nil, nil
+					}
+					analysis, err := h.analyzeAlerts(p.Context, incident.Events)
+					if err != nil {
+						return nil, err
+					}
+					h.applyTemplate(*incident, analysis)
+					return analysis, nil
+				},
+			},
+		},
+	})
+
+	statsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Stats",
+		Fields: graphql.Fields{
+			"activeIncidents":   gqlField(graphql.NewNonNull(graphql.Int), func(s IncidentSummaryResponse) any { return s.ActiveIncidents }),
+			"resolvedIncidents": gqlField(graphql.NewNonNull(graphql.Int), func(s IncidentSummaryResponse) any { return s.ResolvedIncidents }),
+			"averageConfidence": gqlField(graphql.Float, func(s IncidentSummaryResponse) any { return s.AverageConfidence }),
+			"riskLevel":         gqlField(nonNullStr, func(s IncidentSummaryResponse) any { return s.RiskLevel }),
+			"lastIncidentTime":  gqlField(str, func(s IncidentSummaryResponse) any { return s.LastIncidentTime }),
+			"totalAlerts": {
+				Type: graphql.NewNonNull(graphql.Int),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					alerts, err := h.repo.GetAlerts(p.Context)
+					if err != nil {
+						return nil, err
+					}
+					return len(alerts), nil
+				},
+			},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"incidents": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(incidentType))),
+				Description: "Incidents matching a search, newest first unless sorted otherwise",
+				Args: graphql.FieldConfigArgument{
+					"query":  &graphql.ArgumentConfig{Type: str, Description: "Search title, host, chart and alert name"},
+					"sort":   &graphql.ArgumentConfig{Type: str, Description: "started_at, duration, risk, events or priority"},
+					"order":  &graphql.ArgumentConfig{Type: str, Description: "asc or desc"},
+					"active": &graphql.ArgumentConfig{Type: graphql.Boolean, Description: "Only active (true) or resolved (false) incidents"},
+					"limit":  gqlLimitArg,
+					"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					search, _ := p.Args["query"].(string)
+					sortBy, _ := p.Args["sort"].(string)
+					order, _ := p.Args["order"].(string)
+					q, invalid := newIncidentQuery(search, sortBy, order)
+					if invalid != "" {
+						return nil, fmt.Errorf("%s", invalid)
+					}
+					incidents, err := h.queryIncidents(p.Context, q)
+					if err != nil {
+						return nil, err
+					}
+
+					active, filterActive := p.Args["active"].(bool)
+					offset, _ := p.Args["offset"].(int)
+					limit := gqlLimit(p)
+					result := []*domain.Incident{}
+					for i := range incidents {
+						if filterActive && (incidents[i].ResolvedAt == nil) != active {
+							continue
+						}
+						if offset > 0 {
+							offset--
+							continue
+						}
+						if len(result) == limit {
+							break
+						}
+						result = append(result, &incidents[i])
+					}
+					return result, nil
+				},
+			},
+			"incident": {
+				Type: incidentType,
+				Args: graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return h.findIncident(p.Context, p.Args["id"].(string))
+				},
+			},
+			"alerts": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(alertType))),
+				Description: "Stored alerts, newest first",
+				Args: graphql.FieldConfigArgument{
+					"host":   &graphql.ArgumentConfig{Type: str},
+					"status": &graphql.ArgumentConfig{Type: str, Description: "e.g. WARNING or CRITICAL"},
+					"limit":  gqlLimitArg,
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					alerts, err := h.repo.GetAlerts(p.Context)
+					if err != nil {
+						return nil, err
+					}
+					host, _ := p.Args["host"].(string)
+					status, _ := p.Args["status"].(string)
+					limit := gqlLimit(p)
+					result := []domain.Alert{}
+					for i := len(alerts) - 1; i >= 0 && len(result) < limit; i-- {
+						if (host != "" && alerts[i].Host != host) || (status != "" && string(alerts[i].Status) != status) {
+							continue
+						}
+						result = append(result, alerts[i])
+					}
+					return result, nil
+				},
+			},
+			"stats": {
+				Type: graphql.NewNonNull(statsType),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					incidents, err := h.repo.GetIncidents(p.Context)
+					if err != nil {
+						return nil, err
+					}
+					return h.incidentSummary(p.Context, incidents), nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
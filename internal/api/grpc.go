@@ -0,0 +1,319 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"incident-teller/internal/api/pb"
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+)
+
+// incidentStreamInterval is how often StreamIncidents checks for changed incidents
+const incidentStreamInterval = 3 * time.Second
+
+// grpcServer serves the gRPC API from the same handler state as the REST API
+type grpcServer struct {
+	pb.UnimplementedIncidentTellerServer
+	h *Handler
+}
+
+// NewGRPCServer creates a gRPC server exposing the IncidentTeller service. Clients are
+// rate limited like REST clients.
+func (h *Handler) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(h.unaryRateLimit),
+		grpc.ChainStreamInterceptor(h.streamRateLimit),
+	)
+	server := grpc.NewServer(opts...)
+	pb.RegisterIncidentTellerServer(server, &grpcServer{h: h})
+	return server
+}
+
+// ListIncidents returns a page of incidents
+func (s *grpcServer) ListIncidents(ctx context.Context, req *pb.ListIncidentsRequest) (*pb.ListIncidentsResponse, error) {
+	query, invalid := newIncidentQuery(req.GetQuery(), req.GetSort(), req.GetOrder())
+	if invalid != "" {
+		return nil, status.Error(codes.InvalidArgument, invalid)
+	}
+
+	incidents, err := s.h.queryIncidents(ctx, query)
+	if err != nil {
+		s.h.logger.Error("Failed to get incidents", observability.Error(err))
+		return nil, status.Error(codes.Internal, "failed to get incidents")
+	}
+
+	page, pageSize := 1, 20
+	if req.GetPage() > 0 {
+		page = int(req.GetPage())
+	}
+	if req.GetPageSize() > 0 && req.GetPageSize() <= 100 {
+		pageSize = int(req.GetPageSize())
+	}
+	list := s.h.paginateIncidents(incidents, page, pageSize)
+
+	response := &pb.ListIncidentsResponse{
+		Total:    int32(list.Total),
+		Page:     int32(list.Page),
+		PageSize: int32(list.PageSize),
+	}
+	for _, item := range list.Incidents {
+		response.Incidents = append(response.Incidents, incidentToProto(item))
+	}
+	return response, nil
+}
+
+// GetIncident returns an incident with its timeline and AI analysis
+func (s *grpcServer) GetIncident(ctx context.Context, req *pb.GetIncidentRequest) (*pb.IncidentDetail, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "incident ID is required")
+	}
+
+	incident, err := s.h.findIncident(ctx, req.GetId())
+	if err != nil {
+		s.h.logger.Error("Failed to get incidents", observability.Error(err))
+		return nil, status.Error(codes.Internal, "failed to get incidents")
+	}
+	if incident == nil {
+		return nil, status.Error(codes.NotFound, "incident not found")
+	}
+	return incidentDetailToProto(s.h.incidentDetail(ctx, incident)), nil
+}
+
+// AnalyzeIncident tells the story of an incident, or of all stored alerts
+func (s *grpcServer) AnalyzeIncident(ctx context.Context, req *pb.AnalyzeIncidentRequest) (*pb.Analysis, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var alerts []domain.Alert
+	var incident *domain.Incident
+	if req.GetIncidentId() != "" {
+		var err error
+		incident, err = s.h.findIncident(ctx, req.GetIncidentId())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get incidents: %v", err)
+		}
+		if incident == nil {
+			return nil, status.Error(codes.NotFound, "incident not found")
+		}
+		alerts = incident.Events
+	} else {
+		var err error
+		if alerts, err = s.h.repo.GetAlerts(ctx); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get alerts: %v", err)
+		}
+	}
+	if len(alerts) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "no alerts available for analysis")
+	}
+
+	analysis, err := s.h.analyzeAlerts(ctx, alerts)
+	if err != nil {
+		s.h.logger.Error("Failed to generate AI analysis", observability.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to generate analysis: %v", err)
+	}
+	if incident != nil {
+		s.h.applyTemplate(*incident, analysis)
+		s.h.exportAnalysis(ctx, req.GetIncidentId(), analysis)
+	}
+	return &pb.Analysis{
+		Summary:          analysis.Summary,
+		Narrative:        analysis.Narrative,
+		RootCause:        analysis.RootCauseText,
+		ImpactAssessment: analysis.ImpactAssessment,
+		Recommendations: &pb.Recommendations{
+			Immediate: analysis.Recommendations.Immediate,
+			ShortTerm: analysis.Recommendations.ShortTerm,
+			LongTerm:  analysis.Recommendations.LongTerm,
+		},
+		GeneratedAt: timestamppb.New(analysis.GeneratedAt),
+		AlertCount:  int32(analysis.AlertCount),
+	}, nil
+}
+
+// StreamIncidents sends an incident each time it is created, gains alerts or changes status
+func (s *grpcServer) StreamIncidents(req *pb.StreamIncidentsRequest, stream pb.IncidentTeller_StreamIncidentsServer) error {
+	ctx := stream.Context()
+	ticker := time.NewTicker(incidentStreamInterval)
+	defer ticker.Stop()
+
+	// Incidents are sent when their version differs from the one last seen
+	seen := make(map[string]string)
+	first := true
+	for {
+		incidents, err := s.h.repo.GetIncidents(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			s.h.logger.Error("Failed to get incidents for gRPC stream", observability.Error(err))
+		}
+		for _, incident := range incidents {
+			version := incidentVersion(incident)
+			if seen[incident.ID] == version {
+				continue
+			}
+			seen[incident.ID] = version
+			if first && !req.GetIncludeExisting() {
+				continue
+			}
+			if err := stream.Send(incidentToProto(s.h.convertIncidentToListItem(incident))); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// incidentVersion changes whenever a streamed incident should be sent again
+func incidentVersion(incident domain.Incident) string {
+	version := fmt.Sprintf("%s/%d", incident.Status, len(incident.Events))
+	if incident.ResolvedAt != nil {
+		version += "/" + strconv.FormatInt(incident.ResolvedAt.Unix(), 10)
+	}
+	return version
+}
+
+func (h *Handler) unaryRateLimit(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := h.allowGRPC(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (h *Handler) streamRateLimit(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := h.allowGRPC(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// allowGRPC takes a token from the buckets of the calling peer and its bearer token
+func (h *Handler) allowGRPC(ctx context.Context) error {
+	if h.rateLimiter == nil {
+		return nil
+	}
+
+	keys := []string{}
+	if p, ok := peer.FromContext(ctx); ok {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		keys = append(keys, "ip:"+host)
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, auth := range md.Get("authorization") {
+			if key := tokenKey(auth); key != "" {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	if ok, wait := h.rateLimiter.AllowAll(keys); !ok {
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry in %s", wait.Round(time.Second))
+	}
+	return nil
+}
+
+func incidentToProto(item IncidentListItemResponse) *pb.Incident {
+	return &pb.Incident{
+		Id:          item.ID,
+		Title:       item.Title,
+		Status:      item.Status,
+		StartedAt:   timestamppb.New(item.StartedAt),
+		ResolvedAt:  optionalTimestamp(item.ResolvedAt),
+		Duration:    item.Duration,
+		RootCause:   item.RootCause,
+		TotalEvents: int32(item.TotalEvents),
+		RiskLevel:   item.RiskLevel,
+		Assignee:    item.Assignee,
+	}
+}
+
+func incidentDetailToProto(detail IncidentDetailResponse) *pb.IncidentDetail {
+	response := &pb.IncidentDetail{
+		Id:             detail.ID,
+		Title:          detail.Title,
+		Status:         detail.Status,
+		StartedAt:      timestamppb.New(detail.StartedAt),
+		ResolvedAt:     optionalTimestamp(detail.ResolvedAt),
+		Duration:       detail.Duration,
+		RiskLevel:      detail.RiskLevel,
+		TotalEvents:    int32(detail.TotalEvents),
+		Assignee:       detail.Assignee,
+		AcknowledgedBy: detail.AcknowledgedBy,
+		AcknowledgedAt: optionalTimestamp(detail.AcknowledgedAt),
+	}
+
+	if rc := detail.RootCause; rc != nil {
+		response.RootCause = &pb.RootCause{
+			AlertId:      rc.AlertID,
+			ResourceType: rc.ResourceType,
+			Chart:        rc.Chart,
+			Host:         rc.Host,
+			Confidence:   rc.Confidence,
+			PatternType:  rc.PatternType,
+			Reasoning:    rc.Reasoning,
+		}
+		for _, alt := range rc.AlternativeCauses {
+			response.RootCause.AlternativeCauses = append(response.RootCause.AlternativeCauses, &pb.AlternativeCause{
+				AlertId:      alt.AlertID,
+				ResourceType: alt.ResourceType,
+				Chart:        alt.Chart,
+				Host:         alt.Host,
+				Confidence:   alt.Confidence,
+			})
+		}
+	}
+
+	if br := detail.BlastRadius; br != nil {
+		response.BlastRadius = &pb.BlastRadius{
+			ImpactScore:        br.ImpactScore,
+			AffectedServices:   br.AffectedServices,
+			CascadeProbability: br.CascadeProbability,
+			DurationPredicted:  br.DurationPredicted,
+			BusinessImpact:     br.BusinessImpact,
+			RiskLevel:          br.RiskLevel,
+		}
+	}
+
+	for _, event := range detail.EventTimeline {
+		pbEvent := &pb.TimelineEvent{
+			Timestamp:    timestamppb.New(event.Timestamp),
+			Type:         event.Type,
+			Message:      event.Message,
+			Severity:     event.Severity,
+			ResourceType: event.ResourceType,
+			Source:       event.Source,
+		}
+		if event.DurationSinceStart != nil {
+			pbEvent.DurationSinceStart = *event.DurationSinceStart
+		}
+		response.Timeline = append(response.Timeline, pbEvent)
+	}
+	return response
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -6,14 +6,31 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/graphql-go/graphql"
+
 	"incident-teller/internal/ai"
+	"incident-teller/internal/api/openapi"
+	"incident-teller/internal/calendar"
+	"incident-teller/internal/classify"
 	"incident-teller/internal/domain"
+	"incident-teller/internal/environment"
+	"incident-teller/internal/faults"
+	"incident-teller/internal/idgen"
+	"incident-teller/internal/labels"
 	"incident-teller/internal/observability"
+	"incident-teller/internal/exporter"
+	"incident-teller/internal/oncall"
+	"incident-teller/internal/playbook"
+	"incident-teller/internal/redact"
 	"incident-teller/internal/services"
+	"incident-teller/internal/statuspage"
+	"incident-teller/internal/templates"
+	"incident-teller/internal/topology"
 )
 
 // Handler provides HTTP handlers for the IncidentTeller API
@@ -23,11 +40,57 @@ type Handler struct {
 	logger        observability.Logger
 	healthChecker observability.HealthChecker
 	metrics       observability.Metrics
+	changes       *services.ChangeTracker
+	noiseAnalyzer *services.NoiseAnalyzer
+	flaps         *services.FlapDetector
+	storms        *services.StormDetector
+	hostInfo      HostInfoSource
+	onCall        *oncall.Manager
+	builder       *services.IncidentBuilder
+	timelines     *services.TimelineRecorder
+	tickets       *services.TicketManager
+	playbooks     *playbook.Store
+	learner       *services.PropagationLearner
+	anomalies     *services.AnomalyDetector
+	anomalyWindow time.Duration
+	predictions   *services.PredictionService
+	statusPage    *statuspage.Generator
+	acks          *services.AcknowledgementTracker
+	slackSecret   string // Signing secret of the Slack app sending slash commands
+	nagios        http.Handler
+	rateLimiter   *RateLimiter
+	cors          *CORSPolicy
+	reloader      ConfigReloader
+	adminToken    string
+	maxBodyBytes  int64
+	readOnly      bool
+	dashboard     bool
+	accessLog     bool
+	spec          *openapi.Document // Generated from the routes by SetupRoutes
+	graphqlSchema *graphql.Schema   // Set when the GraphQL endpoint is enabled
+	exporter      *exporter.Exporter
+	webhooks      *exporter.WebhookPublisher
+	calendar      *calendar.Calendar
+	analyses      *services.AnalysisQueue
+	evaluator     *services.ModelEvaluator
+	topology      *topology.Topology
+	groupKey      labels.GroupKey
+	elector       *services.LeaderElector
+	templates     *templates.Set
+	watchdog      *services.IngestionWatchdog
+	classifier    *classify.Classifier
+	faults        *faults.Injector
+	features      map[string]bool // Subsystems turned off or on; unlisted ones are on
+	redactor      *redact.Redactor
+	environments  *environment.Resolver
+	shareSecret   []byte        // Signs share links; nil disables them
+	shareMaxTTL   time.Duration // Longest a share link may be valid; 0 = unlimited
 }
 
 // Repository interface for data access
 type Repository interface {
 	SaveAlert(ctx context.Context, alert domain.Alert) error
+	SaveAlerts(ctx context.Context, alerts []domain.Alert) error
 	GetIncidents(ctx context.Context) ([]domain.Incident, error)
 	GetLastProcessedID(ctx context.Context) (uint64, error)
 	SetLastProcessedID(ctx context.Context, id uint64) error
@@ -39,15 +102,39 @@ type Repository interface {
 
 // NewHandler creates a new API handler
 func NewHandler(repo Repository, aiModel ai.AIModel, logger observability.Logger, healthChecker observability.HealthChecker, metrics observability.Metrics) *Handler {
+	if metrics == nil {
+		metrics = &observability.NoOpMetrics{}
+	}
 	return &Handler{
 		repo:          repo,
 		aiModel:       aiModel,
 		logger:        logger,
 		healthChecker: healthChecker,
 		metrics:       metrics,
+		changes:       services.NewChangeTracker(1000),
+		noiseAnalyzer: services.NewNoiseAnalyzer(5 * time.Minute),
+		flaps:         services.NewFlapDetector(30*time.Minute, 4),
+		builder:       services.NewIncidentBuilder(15 * time.Minute),
+		acks:          services.NewAcknowledgementTracker(),
 	}
 }
 
+// SetIncidentBuilder replaces the builder used to correlate alerts into incidents
+func (h *Handler) SetIncidentBuilder(builder *services.IncidentBuilder) {
+	h.builder = builder
+}
+
+// SetCalendar adjusts incident risk levels for business hours and peak traffic windows
+func (h *Handler) SetCalendar(cal *calendar.Calendar) {
+	h.calendar = cal
+}
+
+// SetGroupKey groups the alerts of the alert group and timeline endpoints by key instead
+// of by host
+func (h *Handler) SetGroupKey(key labels.GroupKey) {
+	h.groupKey = key
+}
+
 // ErrorResponse represents an API error response
 type ErrorResponse struct {
 	Error   string `json:"error"`
@@ -62,21 +149,43 @@ type IncidentSummaryResponse struct {
 	AverageConfidence float64 `json:"average_confidence"`
 	RiskLevel         string  `json:"risk_level"`
 	LastIncidentTime  *string `json:"last_incident_time,omitempty"`
+
+	ByState map[string]int `json:"by_state"` // Incidents in each lifecycle state
 }
 
 // IncidentDetailResponse represents a single incident with AI analysis
 type IncidentDetailResponse struct {
-	ID            string                  `json:"id"`
-	Title         string                  `json:"title"`
-	Status        string                  `json:"status"`
-	StartedAt     time.Time               `json:"started_at"`
-	ResolvedAt    *time.Time              `json:"resolved_at,omitempty"`
-	Duration      string                  `json:"duration"`
-	RootCause     *RootCauseResponse      `json:"root_cause,omitempty"`
-	BlastRadius   *BlastRadiusResponse    `json:"blast_radius,omitempty"`
-	RiskLevel     string                  `json:"risk_level"`
-	TotalEvents   int                     `json:"total_events"`
-	EventTimeline []TimelineEventResponse `json:"event_timeline"`
+	ID              string                    `json:"id"`
+	Title           string                    `json:"title"`
+	Status          string                    `json:"status"`
+	StartedAt       time.Time                 `json:"started_at"`
+	ResolvedAt      *time.Time                `json:"resolved_at,omitempty"`
+	Duration        string                    `json:"duration"`
+	RootCause       *RootCauseResponse        `json:"root_cause,omitempty"`
+	BlastRadius     *BlastRadiusResponse      `json:"blast_radius,omitempty"`
+	RiskLevel       string                    `json:"risk_level"`
+	RiskReasons     []string                  `json:"risk_reasons"` // Thresholds the risk level was reached by
+	Priority        string                    `json:"priority"`
+	PriorityManual  bool                      `json:"priority_manual"` // Overridden instead of following the risk level
+	TotalEvents     int                       `json:"total_events"`
+	EventTimeline   []TimelineEventResponse   `json:"event_timeline"`
+	Assignee        string                    `json:"assignee,omitempty"`
+	AcknowledgedBy  string                    `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt  *time.Time                `json:"acknowledged_at,omitempty"`
+	TicketURL       string                    `json:"ticket_url,omitempty"`
+	PriorityHistory []PriorityChangeResponse  `json:"priority_history,omitempty"`
+	Template        *IncidentTemplateResponse `json:"template,omitempty"` // Known failure mode with its remediation
+	Severity        string                    `json:"severity,omitempty"`
+	Tags            []string                  `json:"tags"`
+	CustomFields    map[string]string         `json:"custom_fields"`
+	PinnedRootCause *PinnedRootCauseResponse  `json:"pinned_root_cause,omitempty"` // Set by a responder; RootCause follows it
+
+	State              string                    `json:"state"` // Lifecycle state: detected, triaged, mitigating, monitoring, resolved or postmortem
+	StateChangedAt     *time.Time                `json:"state_changed_at,omitempty"`
+	AllowedTransitions []string                  `json:"allowed_transitions"` // States the incident may move to next
+	StateHistory       []StateTransitionResponse `json:"state_history,omitempty"`
+
+	ProblemID string `json:"problem_id,omitempty"` // Recurring problem the incident is an occurrence of, see /api/problems
 }
 
 // RootCauseResponse represents AI root cause analysis
@@ -86,8 +195,11 @@ type RootCauseResponse struct {
 	Chart             string                     `json:"chart"`
 	Host              string                     `json:"host"`
 	Confidence        float64                    `json:"confidence"`
+	RawConfidence     float64                    `json:"raw_confidence"` // Before calibration against feedback
+	ModelVersion      string                     `json:"model_version"`
 	PatternType       string                     `json:"pattern_type"`
 	Reasoning         string                     `json:"reasoning"`
+	Pinned            bool                       `json:"pinned"` // Set by a responder instead of predicted
 	AlternativeCauses []AlternativeCauseResponse `json:"alternative_causes"`
 }
 
@@ -108,6 +220,16 @@ type BlastRadiusResponse struct {
 	DurationPredicted  string   `json:"duration_predicted"`
 	BusinessImpact     string   `json:"business_impact"`
 	RiskLevel          string   `json:"risk_level"`
+	// Features that drove the impact score and cascade probability, largest first
+	ImpactFactors  []FeatureImportanceResponse `json:"impact_factors,omitempty"`
+	CascadeFactors []FeatureImportanceResponse `json:"cascade_factors,omitempty"`
+}
+
+// FeatureImportanceResponse is how much a feature of the alerts moved a prediction
+type FeatureImportanceResponse struct {
+	Feature      string  `json:"feature"`
+	Value        float64 `json:"value"`
+	Contribution float64 `json:"contribution"`
 }
 
 // AIAnalysisResponse represents AI-generated insights
@@ -119,13 +241,15 @@ type AIAnalysisResponse struct {
 	GeneratedAt     time.Time              `json:"generated_at"`
 	AlertCount      int                    `json:"alert_count"`
 	TimeSpan        string                 `json:"time_span"`
+	Narrative       string                 `json:"narrative,omitempty"` // Story of how the incident unfolded
 }
 
 // RecommendationsResponse contains actionable recommendations
 type RecommendationsResponse struct {
-	Immediate []string `json:"immediate"`
-	ShortTerm []string `json:"short_term"`
-	LongTerm  []string `json:"long_term"`
+	Immediate  []string `json:"immediate"`
+	ShortTerm  []string `json:"short_term"`
+	LongTerm   []string `json:"long_term"`
+	RunbookURL string   `json:"runbook_url,omitempty"` // From the organization playbook matching the root cause
 }
 
 // AlertGroupResponse represents a group of related alerts
@@ -145,12 +269,16 @@ type AlertGroupResponse struct {
 
 // TimelineEventResponse represents a timeline event
 type TimelineEventResponse struct {
-	Timestamp          time.Time `json:"timestamp"`
-	Type               string    `json:"type"`
-	Message            string    `json:"message"`
-	Severity           string    `json:"severity"`
-	DurationSinceStart *string   `json:"duration_since_start,omitempty"`
-	ResourceType       string    `json:"resource_type"`
+	Timestamp          time.Time             `json:"timestamp"`
+	Type               string                `json:"type"`
+	Message            string                `json:"message"`
+	Severity           string                `json:"severity"`
+	DurationSinceStart *string               `json:"duration_since_start,omitempty"`
+	ResourceType       string                `json:"resource_type"`
+	Source             string                `json:"source,omitempty"`
+	CausedBy           []string              `json:"caused_by,omitempty"`     // IDs of alerts that likely caused this event
+	SuppressedBy       string                `json:"suppressed_by,omitempty"` // ID of the alert reporting the host unreachable
+	Samples            []domain.MetricSample `json:"samples,omitempty"`       // Chart values before the alert, oldest first
 }
 
 // TimelineResponse represents a timeline response
@@ -171,77 +299,131 @@ type IncidentListResponse struct {
 
 // IncidentListItemResponse represents a single incident in a list
 type IncidentListItemResponse struct {
-	ID          string     `json:"id"`
-	Title       string     `json:"title"`
-	Status      string     `json:"status"`
-	StartedAt   time.Time  `json:"started_at"`
-	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
-	Duration    string     `json:"duration"`
-	RootCause   string     `json:"root_cause"`
-	TotalEvents int        `json:"total_events"`
-	RiskLevel   string     `json:"risk_level"`
+	ID           string            `json:"id"`
+	Title        string            `json:"title"`
+	Status       string            `json:"status"`
+	State        string            `json:"state"`
+	StartedAt    time.Time         `json:"started_at"`
+	ResolvedAt   *time.Time        `json:"resolved_at,omitempty"`
+	Duration     string            `json:"duration"`
+	RootCause    string            `json:"root_cause"`
+	TotalEvents  int               `json:"total_events"`
+	RiskLevel    string            `json:"risk_level"`
+	Priority     string            `json:"priority"`
+	Assignee     string            `json:"assignee,omitempty"`
+	Severity     string            `json:"severity,omitempty"`
+	Tags         []string          `json:"tags"`
+	CustomFields map[string]string `json:"custom_fields"`
+	Environment  string            `json:"environment,omitempty"`
+}
+
+// ProbeResponse is the answer to the liveness and readiness probes
+type ProbeResponse struct {
+	Status   string            `json:"status"`             // alive, ready or not_ready
+	Checks   map[string]string `json:"checks,omitempty"`   // Readiness check statuses
+	Failures []string          `json:"failures,omitempty"` // Messages of the failed readiness checks
 }
 
 // HealthResponse represents health check response
 type HealthResponse struct {
-	Status    string            `json:"status"`
-	Version   string            `json:"version"`
-	Timestamp time.Time         `json:"timestamp"`
-	Checks    map[string]string `json:"checks,omitempty"`
+	Status    string                   `json:"status"`
+	Version   string                   `json:"version"`
+	Timestamp time.Time                `json:"timestamp"`
+	Checks    map[string]string        `json:"checks,omitempty"`
+	Ingestion *IngestionHealthResponse `json:"ingestion,omitempty"`
 }
 
 // SetupRoutes configures the API routes and applies middleware
 func (h *Handler) SetupRoutes() http.Handler {
 	mux := http.NewServeMux()
 
-	// API routes
-	mux.HandleFunc("/api/incidents/summary", h.handleIncidentsSummary)
-	mux.HandleFunc("/api/incidents", h.handleIncidents)
-	mux.HandleFunc("/api/incidents/", h.handleIncidentDetail)
-	mux.HandleFunc("/api/timeline/", h.handleIncidentTimeline)
-	mux.HandleFunc("/api/timeline-enhanced/", h.handleIncidentTimelineEnhanced)
-	mux.HandleFunc("/api/health", h.handleHealth)
-	mux.HandleFunc("/api/logs", h.handleLogs)
-	mux.HandleFunc("/api/metrics/export", h.handleMetricsExport)
-	mux.HandleFunc("/api/diagnostics", h.handleDiagnostics)
-	mux.HandleFunc("/api/events", h.handleSSE)
-	mux.HandleFunc("/api/test/create-incident", h.handleCreateTestIncident)
-	
-	// AI-powered analysis endpoints
-	mux.HandleFunc("/api/analyze", h.handleAIAnalysis)
-	mux.HandleFunc("/api/alert-groups", h.handleAlertGroups)
-
-	return h.withCORS(mux)
-}
-
-// withCORS is a middleware that handles Cross-Origin Resource Sharing
-func (h *Handler) withCORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
+	routes := h.enabledRoutes(h.routes())
+	h.spec = openapi.Build(apiInfo, ErrorResponse{}, routes)
+	openapi.Register(mux, routes)
 
-		next.ServeHTTP(w, r)
-	})
+	return h.withAccessLog(mux, h.withCORS(h.withRateLimit(h.withReadOnly(h.withAudit(mux)))))
 }
 
-// handleLogs returns the recent buffered logs
+// LogEntryResponse represents a buffered service log record
+type LogEntryResponse struct {
+	Seq       uint64            `json:"seq"`
+	Timestamp time.Time         `json:"timestamp"`
+	Level     string            `json:"level"`
+	Message   string            `json:"message"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	Line      string            `json:"line"`
+}
+
+// LogsResponse represents a page of service logs. Cursor is the seq of the last record, to
+// pass as since for the next page.
+type LogsResponse struct {
+	Logs    []LogEntryResponse `json:"logs"`
+	Count   int                `json:"count"`
+	Cursor  uint64             `json:"cursor"`
+	HasMore bool               `json:"has_more"`
+}
+
+// handleLogs returns the buffered logs: the newest ones, or with ?since= the ones after
+// that cursor, oldest first, so the dashboard can tail them
 func (h *Handler) handleLogs(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	logs := h.logger.GetLogs()
-	h.writeJSON(w, http.StatusOK, map[string]interface{}{
-		"logs":  logs,
-		"count": len(logs),
-	})
+	query := r.URL.Query()
+	filter := observability.LogFilter{MinLevel: observability.DebugLevel, Limit: 100}
+	if v := query.Get("level"); v != "" {
+		switch strings.ToLower(v) {
+		case "debug", "info", "warn", "error":
+			filter.MinLevel = observability.ParseLogLevel(strings.ToLower(v))
+		default:
+			h.writeError(w, http.StatusBadRequest, "level must be debug, info, warn or error")
+			return
+		}
+	}
+	if v := query.Get("since"); v != "" {
+		since, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "since must be a log cursor")
+			return
+		}
+		filter.Since = since
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > 1000 {
+			h.writeError(w, http.StatusBadRequest, "limit must be between 1 and 1000")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	// One more record than asked for tells whether a tail has more to read
+	limit := filter.Limit
+	filter.Limit++
+	entries := h.logger.GetLogs(filter)
+	response := LogsResponse{Logs: []LogEntryResponse{}, Cursor: filter.Since}
+	if len(entries) > limit {
+		if filter.Since > 0 {
+			entries, response.HasMore = entries[:limit], true
+		} else {
+			entries = entries[1:]
+		}
+	}
+	for _, entry := range entries {
+		response.Logs = append(response.Logs, LogEntryResponse{
+			Seq:       entry.Seq,
+			Timestamp: entry.Time,
+			Level:     strings.ToLower(entry.Level.String()),
+			Message:   entry.Message,
+			Fields:    entry.Fields,
+			Line:      entry.Line,
+		})
+		response.Cursor = entry.Seq
+	}
+	response.Count = len(response.Logs)
+	h.writeJSON(w, http.StatusOK, response)
 }
 
 // handleMetricsExport returns metrics in CSV format
@@ -281,11 +463,17 @@ func (h *Handler) handleDiagnostics(w http.ResponseWriter, r *http.Request) {
 	var mem runtime.MemStats
 	runtime.ReadMemStats(&mem)
 
+	records := fmt.Sprintf("Records: %v alerts, %v incidents", repoStats["total_alerts"], repoStats["total_incidents"])
+	if evicted, ok := repoStats["evicted_alerts"]; ok {
+		// The memory database evicts beyond its limits
+		records += fmt.Sprintf("; evicted: %v alerts, %v incidents", evicted, repoStats["evicted_incidents"])
+	}
+
 	diagnostics := []map[string]interface{}{
 		{
 			"check":   "database_connectivity",
 			"status":  health.Checks["database"].Status,
-			"details": fmt.Sprintf("Records: %v alerts, %v incidents", repoStats["total_alerts"], repoStats["total_incidents"]),
+			"details": records,
 		},
 		{
 			"check":   "netdata_api_connectivity",
@@ -304,11 +492,62 @@ func (h *Handler) handleDiagnostics(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+	response := map[string]interface{}{
 		"status":      health.Status,
 		"diagnostics": diagnostics,
 		"timestamp":   time.Now(),
-	})
+	}
+	if h.classifier != nil {
+		unclassified := h.classifier.Unclassified()
+		check := map[string]interface{}{
+			"check":   "resource_classification",
+			"status":  "pass",
+			"details": "Every alert was classified by resource type",
+		}
+		if len(unclassified) > 0 {
+			alerts := 0
+			for _, chart := range unclassified {
+				alerts += chart.Alerts
+			}
+			check["status"] = "warn"
+			check["details"] = fmt.Sprintf("%d alerts on %d charts of unknown resource type; add resources.rules for them", alerts, len(unclassified))
+		}
+		diagnostics = append(diagnostics, check)
+		response["diagnostics"] = diagnostics
+		response["unclassified_charts"] = unclassified
+	}
+	if injected := h.faults.Current(); injected.Active() {
+		diagnostics = append(diagnostics, map[string]interface{}{
+			"check":   "fault_injection",
+			"status":  "warn",
+			"details": "Injecting " + describeFaults(injected) + "; DELETE /api/admin/faults to stop",
+		})
+		response["diagnostics"] = diagnostics
+	}
+	if h.elector != nil {
+		leader := h.elector.Status()
+		details := "No leader: the lease is free or expired"
+		if leader.Leader != "" {
+			details = fmt.Sprintf("Leader: %s (this replica: %s)", leader.Leader, leader.Replica)
+		}
+		response["leader"] = leader
+		response["diagnostics"] = append(diagnostics, map[string]interface{}{
+			"check":   "ingestion_leader",
+			"status":  map[bool]string{true: "pass", false: "warn"}[leader.Leader != ""],
+			"details": details,
+		})
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// SetClassifier reports the charts left of unknown resource type on /api/diagnostics
+func (h *Handler) SetClassifier(classifier *classify.Classifier) {
+	h.classifier = classifier
+}
+
+// SetLeaderElector reports the replica polling the alert sources on /api/diagnostics
+func (h *Handler) SetLeaderElector(elector *services.LeaderElector) {
+	h.elector = elector
 }
 
 // handleSSE provides Server-Sent Events for real-time updates
@@ -332,10 +571,21 @@ func (h *Handler) handleSSE(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The stream outlives the server's write timeout
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
 	ctx := r.Context()
 	ticker := time.NewTicker(3 * time.Second)
 	defer ticker.Stop()
 
+	// Finished incident analyses are sent as "analysis" events
+	var analyses <-chan services.AnalysisJob
+	if h.analyses != nil {
+		var unsubscribe func()
+		analyses, unsubscribe = h.analyses.Subscribe()
+		defer unsubscribe()
+	}
+
 	// Send initial data
 	h.sendSSEUpdate(w, flusher, ctx)
 
@@ -346,6 +596,8 @@ func (h *Handler) handleSSE(w http.ResponseWriter, r *http.Request) {
 			return
 		case <-ticker.C:
 			h.sendSSEUpdate(w, flusher, ctx)
+		case job := <-analyses:
+			h.sendSSEAnalysis(w, flusher, job)
 		}
 	}
 }
@@ -374,7 +626,8 @@ func (h *Handler) sendSSEUpdate(w http.ResponseWriter, flusher http.Flusher, ctx
 	flusher.Flush()
 }
 
-// handleCreateTestIncident creates a test incident for development
+// handleCreateTestIncident creates a test incident for development; POST /api/test/scenarios
+// injects whole scenarios
 func (h *Handler) handleCreateTestIncident(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
@@ -385,7 +638,7 @@ func (h *Handler) handleCreateTestIncident(w http.ResponseWriter, r *http.Reques
 
 	// Create a test critical alert
 	alert := domain.Alert{
-		ID:           fmt.Sprintf("test-%d", time.Now().Unix()),
+		ID:           idgen.New(time.Now()),
 		ExternalID:   uint64(time.Now().Unix()),
 		Host:         "localhost",
 		Chart:        "system.cpu",
@@ -394,7 +647,7 @@ func (h *Handler) handleCreateTestIncident(w http.ResponseWriter, r *http.Reques
 		Status:       domain.StatusCritical,
 		OldStatus:    domain.StatusClear,
 		Value:        95.0,
-		OccurredAt:   time.Now(),
+		OccurredAt:   time.Now().UTC(),
 		Description:  "Test critical CPU alert",
 		ResourceType: domain.ResourceCPU,
 		Labels: map[string]string{
@@ -402,35 +655,13 @@ func (h *Handler) handleCreateTestIncident(w http.ResponseWriter, r *http.Reques
 		},
 	}
 
-	// Save the alert
-	if err := h.repo.SaveAlert(ctx, alert); err != nil {
-		h.logger.Error("Failed to save test alert", observability.Error(err))
-		h.writeError(w, http.StatusInternalServerError, "Failed to save alert")
-		return
-	}
-
-	// Create incident from this alert
-	builder := services.NewIncidentBuilder(15 * time.Minute)
-
-	// Get all alerts and build incidents
-	alerts, err := h.repo.GetAlerts(ctx)
+	incidents, err := h.injectAlerts(ctx, []domain.Alert{alert}, nil)
 	if err != nil {
-		h.logger.Error("Failed to get alerts", observability.Error(err))
-		h.writeError(w, http.StatusInternalServerError, "Failed to get alerts")
+		h.logger.Error("Failed to create test incident", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to create test incident")
 		return
 	}
 
-	incidents := builder.Build(alerts)
-
-	// Save the new incidents
-	for _, incident := range incidents {
-		if err := h.repo.SaveIncident(ctx, incident); err != nil {
-			h.logger.Error("Failed to save incident", observability.Error(err))
-			h.writeError(w, http.StatusInternalServerError, "Failed to save incident")
-			return
-		}
-	}
-
 	if len(incidents) > 0 {
 		h.logger.Info("Test incident created",
 			observability.String("incident_id", incidents[0].ID),
@@ -460,6 +691,11 @@ func (h *Handler) handleIncidentsSummary(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	h.writeJSON(w, http.StatusOK, h.incidentSummary(ctx, incidents))
+}
+
+// incidentSummary computes the summary statistics of a set of incidents
+func (h *Handler) incidentSummary(ctx context.Context, incidents []domain.Incident) IncidentSummaryResponse {
 	activeIncidents := 0
 	resolvedIncidents := 0
 	var totalConfidence float64
@@ -511,14 +747,14 @@ func (h *Handler) handleIncidentsSummary(w http.ResponseWriter, r *http.Request)
 		ResolvedIncidents: resolvedIncidents,
 		AverageConfidence: avgConfidence,
 		RiskLevel:         overallRiskLevel,
+		ByState:           stateCounts(incidents),
 	}
 
 	if lastIncidentTime != nil {
 		formatted := lastIncidentTime.Format(time.RFC3339)
 		response.LastIncidentTime = &formatted
 	}
-
-	h.writeJSON(w, http.StatusOK, response)
+	return response
 }
 
 // handleIncidents returns a list of incidents
@@ -530,12 +766,37 @@ func (h *Handler) handleIncidents(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	incidents, err := h.repo.GetIncidents(ctx)
+	query, invalid := parseIncidentQuery(r)
+	if invalid != "" {
+		h.writeError(w, http.StatusBadRequest, invalid)
+		return
+	}
+	matchers, invalid := parseLabelMatchers(r)
+	if invalid != "" {
+		h.writeError(w, http.StatusBadRequest, invalid)
+		return
+	}
+	envFilter, invalid := h.parseEnvironmentFilter(r)
+	if invalid != "" {
+		h.writeError(w, http.StatusBadRequest, invalid)
+		return
+	}
+	states, invalid := parseIncidentStateFilter(r)
+	if invalid != "" {
+		h.writeError(w, http.StatusBadRequest, invalid)
+		return
+	}
+
+	incidents, err := h.queryIncidents(ctx, query)
 	if err != nil {
 		h.logger.Error("Failed to get incidents", observability.Error(err))
 		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
 		return
 	}
+	incidents = filterIncidentsByLabels(incidents, matchers)
+	incidents = filterIncidentsByEnvironment(incidents, envFilter)
+	incidents = filterIncidentsByMetadata(incidents, parseIncidentMetadataFilter(r))
+	incidents = filterIncidentsByState(incidents, states)
 
 	// Parse query parameters
 	page := 1
@@ -553,25 +814,17 @@ func (h *Handler) handleIncidents(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	response := h.paginateIncidents(incidents, page, pageSize)
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// paginateIncidents converts a page of incidents to the list response
+func (h *Handler) paginateIncidents(incidents []domain.Incident, page, pageSize int) IncidentListResponse {
 	// Convert to response format
 	var incidentItems []IncidentListItemResponse
 	for _, incident := range incidents {
-		rootCause := h.identifyPrimaryRootCause(incident)
-		riskLevel := h.calculateRiskLevel(incident)
-		duration := h.calculateDuration(incident)
-
-		item := IncidentListItemResponse{
-			ID:          incident.ID,
-			Title:       incident.Title,
-			Status:      string(incident.Status),
-			StartedAt:   incident.StartedAt,
-			ResolvedAt:  incident.ResolvedAt,
-			Duration:    duration,
-			RootCause:   rootCause,
-			TotalEvents: len(incident.Events),
-			RiskLevel:   riskLevel,
-		}
-		incidentItems = append(incidentItems, item)
+		incidentItems = append(incidentItems, h.convertIncidentToListItem(incident))
 	}
 
 	// Pagination
@@ -586,18 +839,43 @@ func (h *Handler) handleIncidents(w http.ResponseWriter, r *http.Request) {
 		incidentItems = incidentItems[start:end]
 	}
 
-	response := IncidentListResponse{
+	return IncidentListResponse{
 		Incidents: incidentItems,
 		Total:     total,
 		Page:      page,
 		PageSize:  pageSize,
 	}
+}
 
-	h.writeJSON(w, http.StatusOK, response)
+// convertIncidentToListItem converts an incident to its list item response
+func (h *Handler) convertIncidentToListItem(incident domain.Incident) IncidentListItemResponse {
+	return IncidentListItemResponse{
+		ID:           incident.ID,
+		Title:        incident.Title,
+		Status:       string(incident.Status),
+		State:        string(incident.CurrentState()),
+		StartedAt:    incident.StartedAt,
+		ResolvedAt:   incident.ResolvedAt,
+		Duration:     h.calculateDuration(incident),
+		RootCause:    h.identifyPrimaryRootCause(incident),
+		TotalEvents:  len(incident.Events),
+		RiskLevel:    h.calculateRiskLevel(incident),
+		Priority:     string(h.incidentPriority(incident)),
+		Assignee:     h.incidentAssignee(incident.ID),
+		Severity:     incident.Severity,
+		Tags:         incidentTags(incident),
+		CustomFields: incidentCustomFields(incident),
+		Environment:  h.incidentEnvironment(incident),
+	}
 }
 
-// handleIncidentDetail returns detailed information about a specific incident
+// handleIncidentDetail returns detailed information about a specific incident (GET) or
+// changes its priority (PATCH)
 func (h *Handler) handleIncidentDetail(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPatch {
+		h.handleIncidentPatch(w, r)
+		return
+	}
 	if r.Method != http.MethodGet {
 		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
@@ -610,37 +888,48 @@ func (h *Handler) handleIncidentDetail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx := r.Context()
-
-	incidents, err := h.repo.GetIncidents(ctx)
+	incident, err := h.findIncident(r.Context(), id)
 	if err != nil {
 		h.logger.Error("Failed to get incidents", observability.Error(err))
 		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
 		return
 	}
-
-	// Find the specific incident
-	var incident *domain.Incident
-	for i, inc := range incidents {
-		if inc.ID == id {
-			incident = &incidents[i]
-			break
-		}
-	}
-
 	if incident == nil {
 		h.writeError(w, http.StatusNotFound, "Incident not found")
 		return
 	}
 
-	// Perform AI analysis
+	h.writeJSON(w, http.StatusOK, h.incidentDetail(r.Context(), incident))
+}
+
+// findIncident returns the incident with the given ID, or nil if there is none
+func (h *Handler) findIncident(ctx context.Context, id string) (*domain.Incident, error) {
+	incidents, err := h.repo.GetIncidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range incidents {
+		if incidents[i].ID == id {
+			return &incidents[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// incidentDetail builds the detail of an incident, including its AI analysis
+func (h *Handler) incidentDetail(ctx context.Context, incident *domain.Incident) IncidentDetailResponse {
+	// Use the background analysis when it is up to date, otherwise analyze inline
 	var rootCauseResponse *RootCauseResponse
 	var blastRadiusResponse *BlastRadiusResponse
 
-	if h.aiModel != nil && len(incident.Events) > 0 {
+	if analysis := h.currentAnalysis(*incident); analysis != nil {
+		rootCauseResponse = analysis.RootCause
+		blastRadiusResponse = analysis.BlastRadius
+	} else if h.aiModel != nil && len(incident.Events) > 0 {
 		if rootCause, err := h.aiModel.PredictRootCause(ctx, incident.Events); err == nil {
 			rootCauseResponse = h.convertRootCauseToResponse(rootCause)
 		}
+		rootCauseResponse = pinRootCause(*incident, rootCauseResponse)
 
 		if blastRadius, err := h.aiModel.PredictBlastRadius(ctx, incident.Events); err == nil {
 			blastRadiusResponse = h.convertBlastRadiusToResponse(blastRadius)
@@ -648,20 +937,44 @@ func (h *Handler) handleIncidentDetail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := IncidentDetailResponse{
-		ID:            incident.ID,
-		Title:         incident.Title,
-		Status:        string(incident.Status),
-		StartedAt:     incident.StartedAt,
-		ResolvedAt:    incident.ResolvedAt,
-		Duration:      h.calculateDuration(*incident),
-		RootCause:     rootCauseResponse,
-		BlastRadius:   blastRadiusResponse,
-		RiskLevel:     h.calculateRiskLevel(*incident),
-		TotalEvents:   len(incident.Events),
-		EventTimeline: h.convertTimelineToResponse(incident),
-	}
-
-	h.writeJSON(w, http.StatusOK, response)
+		ID:              incident.ID,
+		Title:           incident.Title,
+		Status:          string(incident.Status),
+		StartedAt:       incident.StartedAt,
+		ResolvedAt:      incident.ResolvedAt,
+		Duration:        h.calculateDuration(*incident),
+		RootCause:       rootCauseResponse,
+		BlastRadius:     blastRadiusResponse,
+		RiskLevel:       h.calculateRiskLevel(*incident),
+		RiskReasons:     h.riskReasons(*incident),
+		Priority:        string(h.incidentPriority(*incident)),
+		PriorityManual:  incident.PriorityOverride != "",
+		TotalEvents:     len(incident.Events),
+		EventTimeline:   h.convertTimelineToResponse(incident),
+		Assignee:        h.incidentAssignee(incident.ID),
+		TicketURL:       h.incidentTicketURL(ctx, incident.ID),
+		PriorityHistory: h.priorityHistory(ctx, incident.ID),
+		Template:        h.incidentTemplate(*incident),
+		Severity:        incident.Severity,
+		Tags:            incidentTags(*incident),
+		CustomFields:    incidentCustomFields(*incident),
+		PinnedRootCause: pinnedRootCauseResponse(incident.RootCauseOverride),
+
+		State:              string(incident.CurrentState()),
+		StateChangedAt:     incident.StateChangedAt,
+		AllowedTransitions: allowedTransitions(*incident),
+		StateHistory:       h.stateHistory(ctx, incident.ID),
+
+		ProblemID: h.incidentProblemID(ctx, incident.ID),
+	}
+	if rootCauseResponse == nil {
+		response.RootCause = pinRootCause(*incident, nil)
+	}
+	if ack := h.incidentAcknowledgement(incident.ID); ack != nil {
+		response.AcknowledgedBy = ack.By
+		response.AcknowledgedAt = &ack.AcknowledgedAt
+	}
+	return response
 }
 
 // handleHealth returns system health information
@@ -684,12 +997,49 @@ func (h *Handler) handleHealth(w http.ResponseWriter, r *http.Request) {
 	for name, check := range health.Checks {
 		response.Checks[name] = check.Status
 	}
+	response.Ingestion = h.ingestionHealth()
 
 	// Always return 200 OK so the frontend can see the health status
-	// This allows the frontend to display health information even if some checks fail
+	// This allows the frontend to display health information even if some checks fail;
+	// orchestrators probe /healthz and /readyz instead
 	h.writeJSON(w, http.StatusOK, response)
 }
 
+// handleLiveness answers the liveness probe: the process is up and serving requests
+func (h *Handler) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, ProbeResponse{Status: "alive"})
+}
+
+// handleReadiness answers the readiness probe with 503 while a readiness check (database
+// reachable, alert sources polled) fails
+func (h *Handler) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	readiness := h.healthChecker.CheckReadiness(r.Context())
+	response := ProbeResponse{Status: "ready", Checks: make(map[string]string)}
+	for name, check := range readiness.Checks {
+		response.Checks[name] = check.Status
+		if check.Status == "unhealthy" {
+			response.Failures = append(response.Failures, name+": "+check.Message)
+		}
+	}
+	sort.Strings(response.Failures)
+
+	status := http.StatusOK
+	if readiness.Status == "unhealthy" {
+		response.Status = "not_ready"
+		status = http.StatusServiceUnavailable
+	}
+	h.writeJSON(w, status, response)
+}
+
 // handleIncidentTimeline returns timeline events for a specific incident
 func (h *Handler) handleIncidentTimeline(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -698,7 +1048,7 @@ func (h *Handler) handleIncidentTimeline(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Extract incident ID from URL
-	id := extractIncidentID(r.URL.Path)
+	id := strings.TrimPrefix(r.URL.Path, "/api/timeline/")
 	if id == "" {
 		h.writeError(w, http.StatusBadRequest, "Invalid incident ID")
 		return
@@ -728,7 +1078,7 @@ func (h *Handler) handleIncidentTimeline(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Convert timeline to response format
-	timelineEvents := h.convertTimelineToResponse(incident)
+	timelineEvents := h.incidentTimeline(ctx, incident)
 
 	// Calculate incident duration
 	duration := h.calculateDuration(*incident)
@@ -784,6 +1134,9 @@ func (h *Handler) identifyPrimaryRootCause(incident domain.Incident) string {
 	if len(incident.Events) == 0 {
 		return "Unknown"
 	}
+	if pinned, ok := incident.PinnedRootCause(); ok {
+		return string(pinned.ResourceType)
+	}
 
 	// Find the first critical or warning alert
 	for _, event := range incident.Events {
@@ -796,32 +1149,18 @@ func (h *Handler) identifyPrimaryRootCause(incident domain.Incident) string {
 }
 
 func (h *Handler) calculateRiskLevel(incident domain.Incident) string {
-	if len(incident.Events) == 0 {
-		return "low"
-	}
-
-	criticalCount := 0
-	hostCount := make(map[string]bool)
-	resourceTypes := make(map[domain.ResourceType]bool)
-
-	for _, event := range incident.Events {
-		if event.Status == domain.StatusCritical {
-			criticalCount++
-		}
-		hostCount[event.Host] = true
-		resourceTypes[event.ResourceType] = true
-	}
+	return h.calendar.AdjustRisk(incident.RiskLevel(), incident.StartedAt)
+}
 
-	// Calculate risk based on severity, scope, and duration
-	if criticalCount >= 3 || len(hostCount) >= 3 || len(resourceTypes) >= 3 {
-		return "critical"
-	} else if criticalCount >= 2 || len(hostCount) >= 2 || len(resourceTypes) >= 2 {
-		return "high"
-	} else if criticalCount >= 1 || len(hostCount) > 1 {
-		return "medium"
+// riskReasons explains the risk level of an incident with the thresholds it reached and
+// any business calendar adjustment
+func (h *Handler) riskReasons(incident domain.Incident) []string {
+	assessment := incident.RiskAssessment()
+	reasons := assessment.Reasons
+	if adjusted := h.calendar.AdjustRisk(assessment.Level, incident.StartedAt); adjusted != assessment.Level {
+		reasons = append(reasons, fmt.Sprintf("the business calendar moves it from %s to %s", assessment.Level, adjusted))
 	}
-
-	return "low"
+	return reasons
 }
 
 func (h *Handler) calculateDuration(incident domain.Incident) string {
@@ -839,6 +1178,7 @@ func (h *Handler) convertRootCauseToResponse(rootCause ai.RootCausePrediction) *
 			Chart:             "",
 			Host:              "",
 			Confidence:        0.0,
+			ModelVersion:      rootCause.ModelVersion,
 			PatternType:       rootCause.PatternType,
 			Reasoning:         rootCause.Reasoning,
 			AlternativeCauses: []AlternativeCauseResponse{},
@@ -862,6 +1202,8 @@ func (h *Handler) convertRootCauseToResponse(rootCause ai.RootCausePrediction) *
 		Chart:             rootCause.PrimaryCause.Chart,
 		Host:              rootCause.PrimaryCause.Host,
 		Confidence:        rootCause.Confidence,
+		RawConfidence:     rootCause.RawConfidence,
+		ModelVersion:      rootCause.ModelVersion,
 		PatternType:       rootCause.PatternType,
 		Reasoning:         rootCause.Reasoning,
 		AlternativeCauses: alternatives,
@@ -876,7 +1218,17 @@ func (h *Handler) convertBlastRadiusToResponse(blastRadius ai.BlastRadiusPredict
 		DurationPredicted:  blastRadius.DurationPredicted.String(),
 		BusinessImpact:     blastRadius.BusinessImpact,
 		RiskLevel:          blastRadius.RiskLevel,
+		ImpactFactors:      convertFeatureImportance(blastRadius.ImpactFactors),
+		CascadeFactors:     convertFeatureImportance(blastRadius.CascadeFactors),
+	}
+}
+
+func convertFeatureImportance(importance []ai.FeatureImportance) []FeatureImportanceResponse {
+	var factors []FeatureImportanceResponse
+	for _, factor := range importance {
+		factors = append(factors, FeatureImportanceResponse(factor))
 	}
+	return factors
 }
 
 func (h *Handler) convertTimelineToResponse(incident *domain.Incident) []TimelineEventResponse {
@@ -905,6 +1257,14 @@ func (h *Handler) convertTimelineToResponse(incident *domain.Incident) []Timelin
 
 		message := h.generateEventMessage(event)
 
+		// Alerts of an unreachable host are grouped under the alert reporting it
+		var causedBy []string
+		suppressedBy := services.SuppressedBy(event)
+		if suppressedBy != "" {
+			eventType, severity = "SUPPRESSED", "info"
+			causedBy = []string{suppressedBy}
+		}
+
 		timeline = append(timeline, TimelineEventResponse{
 			Timestamp:          event.OccurredAt,
 			Type:               eventType,
@@ -912,13 +1272,85 @@ func (h *Handler) convertTimelineToResponse(incident *domain.Incident) []Timelin
 			Severity:           severity,
 			DurationSinceStart: durationSinceStart,
 			ResourceType:       string(event.ResourceType),
+			Source:             event.Source,
+			CausedBy:           causedBy,
+			SuppressedBy:       suppressedBy,
+			Samples:            event.Samples,
 		})
 	}
 
 	return timeline
 }
 
+// SetTimelineRecorder serves timelines from stored entries, extended as alerts arrive,
+// instead of building them from the incident's alerts on every request
+func (h *Handler) SetTimelineRecorder(recorder *services.TimelineRecorder) {
+	h.timelines = recorder
+}
+
+// incidentTimeline returns the timeline of an incident with its escalations
+func (h *Handler) incidentTimeline(ctx context.Context, incident *domain.Incident) []TimelineEventResponse {
+	return h.withEscalations(ctx, incident, h.alertTimeline(ctx, incident))
+}
+
+// alertTimeline returns the timeline of an incident's alerts with causality links when
+// timelines are recorded, falling back to building it from the incident's alerts
+func (h *Handler) alertTimeline(ctx context.Context, incident *domain.Incident) []TimelineEventResponse {
+	if h.timelines == nil {
+		return h.convertTimelineToResponse(incident)
+	}
+
+	entries, err := h.timelines.Record(ctx, *incident)
+	if err != nil {
+		h.logger.Error("Failed to get recorded timeline",
+			observability.String("incident_id", incident.ID), observability.Error(err))
+		return h.convertTimelineToResponse(incident)
+	}
+
+	samples := make(map[string][]domain.MetricSample)
+	for _, event := range incident.Events {
+		if len(event.Samples) > 0 {
+			samples[event.ID] = event.Samples
+		}
+	}
+
+	timeline := make([]TimelineEventResponse, 0, len(entries))
+	for i, entry := range entries {
+		event := TimelineEventResponse{
+			Timestamp:    entry.Timestamp,
+			Type:         entry.Type,
+			Message:      entry.Message,
+			Severity:     entry.Severity,
+			ResourceType: string(entry.ResourceType),
+			CausedBy:     entry.CausedBy,
+		}
+		if entry.Type == "SUPPRESSED" && len(entry.CausedBy) > 0 {
+			event.SuppressedBy = entry.CausedBy[0]
+		}
+		for _, id := range entry.RelatedAlertIDs {
+			if event.Samples = samples[id]; event.Samples != nil {
+				break
+			}
+		}
+		if i > 0 && entry.DurationSinceStart != nil {
+			duration := entry.DurationSinceStart.String()
+			event.DurationSinceStart = &duration
+		}
+		timeline = append(timeline, event)
+	}
+	return timeline
+}
+
 func (h *Handler) generateEventMessage(event domain.Alert) string {
+	message := h.statusMessage(event)
+	if trend := services.DescribeSamples(event); trend != "" {
+		message += "; " + trend
+	}
+	return message
+}
+
+// statusMessage describes the status change of an alert
+func (h *Handler) statusMessage(event domain.Alert) string {
 	switch event.Status {
 	case domain.StatusCritical:
 		return fmt.Sprintf("Critical alert triggered for %s on %s (value: %.2f)",
@@ -945,11 +1377,33 @@ func (h *Handler) handleAIAnalysis(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get all alerts
-	alerts, err := h.repo.GetAlerts(ctx)
-	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get alerts: %v", err))
-		return
+	// Analyze one incident's alerts if requested, otherwise all alerts
+	var alerts []domain.Alert
+	var incident *domain.Incident
+	incidentID := r.URL.Query().Get("incident_id")
+	if incidentID != "" {
+		var err error
+		incident, err = h.findIncident(ctx, incidentID)
+		if err != nil {
+			h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get incidents: %v", err))
+			return
+		}
+		if incident == nil {
+			h.writeError(w, http.StatusNotFound, "Incident not found")
+			return
+		}
+		if analysis := h.currentAnalysis(*incident); analysis != nil {
+			h.writeJSON(w, http.StatusOK, analysis.Analysis)
+			return
+		}
+		alerts = incident.Events
+	} else {
+		var err error
+		alerts, err = h.repo.GetAlerts(ctx)
+		if err != nil {
+			h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get alerts: %v", err))
+			return
+		}
 	}
 
 	if len(alerts) == 0 {
@@ -957,20 +1411,32 @@ func (h *Handler) handleAIAnalysis(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get AI analysis
-	analysisData, err := h.getAIAnalysis(ctx, alerts)
+	response, err := h.analyzeAlerts(ctx, alerts)
 	if err != nil {
 		h.logger.Error("Failed to generate AI analysis", observability.Field{Key: "error", Value: err})
 		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to generate analysis: %v", err))
 		return
 	}
+	if incident != nil {
+		h.applyTemplate(*incident, response)
+		h.exportAnalysis(ctx, incidentID, response)
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// analyzeAlerts tells the story of a non-empty set of alerts
+func (h *Handler) analyzeAlerts(ctx context.Context, alerts []domain.Alert) (*AIAnalysisResponse, error) {
+	// Get AI analysis
+	analysisData, err := h.getAIAnalysis(ctx, alerts)
+	if err != nil {
+		return nil, err
+	}
 
 	// Convert interface{} to map
 	analysisMap, ok := analysisData.(map[string]interface{})
 	if !ok {
-		h.logger.Error("Invalid analysis response format", observability.Field{Key: "type", Value: fmt.Sprintf("%T", analysisData)})
-		h.writeError(w, http.StatusInternalServerError, "Invalid analysis format")
-		return
+		return nil, fmt.Errorf("invalid analysis format %T", analysisData)
 	}
 
 	// Extract recommendation data
@@ -985,20 +1451,25 @@ func (h *Handler) handleAIAnalysis(w http.ResponseWriter, r *http.Request) {
 		if lt, ok := rec["long_term"].([]string); ok {
 			recommendations.LongTerm = lt
 		}
+		if runbook, ok := rec["runbook_url"].(string); ok {
+			recommendations.RunbookURL = runbook
+		}
 	}
 
 	// Build response
-	response := AIAnalysisResponse{
+	response := &AIAnalysisResponse{
 		Summary:          fmt.Sprintf("%v", analysisMap["summary"]),
 		RootCauseText:    fmt.Sprintf("%v", analysisMap["root_cause"]),
 		ImpactAssessment: fmt.Sprintf("%v", analysisMap["impact"]),
 		Recommendations:  recommendations,
-		GeneratedAt:      time.Now(),
+		GeneratedAt:      time.Now().UTC(),
 		AlertCount:       len(alerts),
 		TimeSpan:         "incident analysis",
 	}
-
-	h.writeJSON(w, http.StatusOK, response)
+	if narrative, ok := analysisMap["narrative"].(string); ok {
+		response.Narrative = narrative
+	}
+	return response, nil
 }
 
 // handleAlertGroups returns alerts grouped by host and cascade relationships
@@ -1020,6 +1491,7 @@ func (h *Handler) handleAlertGroups(w http.ResponseWriter, r *http.Request) {
 
 	// Group alerts
 	grouper := services.NewAlertGrouper(15 * time.Minute)
+	grouper.SetGroupKey(h.groupKey)
 	groups := grouper.GroupAlerts(alerts)
 
 	// Convert to response format
@@ -1089,10 +1561,13 @@ func (h *Handler) handleIncidentTimelineEnhanced(w http.ResponseWriter, r *http.
 
 	// Group alerts and build enhanced timeline
 	grouper := services.NewAlertGrouper(15 * time.Minute)
+	grouper.SetGroupKey(h.groupKey)
 	groups := grouper.GroupAlerts(incident.Events)
 
 	timelineBuilder := services.NewEnhancedTimelineBuilder(grouper)
+	timelineBuilder.SetAnomalyDetector(h.anomalies)
 	timeline := timelineBuilder.BuildTimeline(incident.Events, groups)
+	pinTimelineRootCause(*incident, &timeline)
 
 	// Convert to response format
 	eventResponses := make([]map[string]interface{}, len(timeline.Events))
@@ -1108,6 +1583,9 @@ func (h *Handler) handleIncidentTimelineEnhanced(w http.ResponseWriter, r *http.
 			"is_root_cause":         event.IsRootCause,
 			"resources_affected":    event.ResourcesAffected,
 		}
+		if event.SourceAlert != nil && event.SourceAlert.Source != "" {
+			eventResponses[i]["source"] = event.SourceAlert.Source
+		}
 	}
 
 	response := map[string]interface{}{
@@ -1153,17 +1631,18 @@ func (h *Handler) getOpenAIAnalysis(ctx context.Context, openaiClient interface{
 // getLocalAnalysis uses local ML models for analysis
 func (h *Handler) getLocalAnalysis(alerts []domain.Alert) (interface{}, error) {
 	// Use existing incident teller for local analysis
-	teller := services.NewIncidentTeller()
-	story := teller.TellStory(alerts)
+	story := h.incidentTeller().TellStory(alerts)
 
 	return map[string]interface{}{
 		"summary":   story.Summary,
+		"narrative": story.Timeline,
 		"root_cause": story.RootCause,
 		"impact":    story.Impact,
 		"recommendations": map[string]interface{}{
 			"immediate": story.Fix.ImmediateActions,
 			"short_term": story.Fix.ShortTermActions,
 			"long_term": story.Fix.LongTermActions,
+			"runbook_url": story.Fix.RunbookURL,
 		},
 		"generated_at": story.GeneratedAt,
 		"alert_count": len(alerts),
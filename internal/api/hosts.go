@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/services"
+)
+
+// HostInfoSource provides agent-reported metadata about monitored hosts
+type HostInfoSource interface {
+	FetchHostInfo(ctx context.Context) ([]domain.HostInfo, error)
+}
+
+// HostResponse represents a host in the fleet inventory
+type HostResponse struct {
+	Host            string            `json:"host"`
+	Health          string            `json:"health"`
+	TotalIncidents  int               `json:"total_incidents"`
+	ActiveIncidents int               `json:"active_incidents"`
+	ActiveAlerts    int               `json:"active_alerts"`
+	LastAlertAt     *time.Time        `json:"last_alert_at,omitempty"`
+	Discovered      bool              `json:"discovered"` // Reported by the agent, not only seen in alerts
+	OS              string            `json:"os,omitempty"`
+	Kernel          string            `json:"kernel,omitempty"`
+	Architecture    string            `json:"architecture,omitempty"`
+	Cores           int               `json:"cores,omitempty"`
+	AgentVersion    string            `json:"agent_version,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+}
+
+// SetHostInfoSource enables agent-based host discovery for the inventory endpoints
+func (h *Handler) SetHostInfoSource(source HostInfoSource) {
+	h.hostInfo = source
+}
+
+// handleHosts returns the discovered host inventory
+func (h *Handler) handleHosts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	ctx := r.Context()
+
+	var infos []domain.HostInfo
+	if h.hostInfo != nil {
+		fetched, err := h.hostInfo.FetchHostInfo(ctx)
+		if err != nil {
+			// Inventory still works from observed alerts
+			h.logger.Warn("Failed to fetch host info", observability.Error(err))
+		} else {
+			infos = fetched
+		}
+	}
+
+	alerts, err := h.repo.GetAlerts(ctx)
+	if err != nil {
+		h.logger.Error("Failed to get alerts", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve alerts")
+		return
+	}
+
+	incidents, err := h.repo.GetIncidents(ctx)
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve incidents")
+		return
+	}
+
+	summaries := services.BuildHostInventory(infos, alerts, incidents)
+	hosts := make([]HostResponse, len(summaries))
+	for i, s := range summaries {
+		hosts[i] = HostResponse{
+			Host:            s.Host,
+			Health:          s.Health,
+			TotalIncidents:  s.TotalIncidents,
+			ActiveIncidents: s.ActiveIncidents,
+			ActiveAlerts:    s.ActiveAlerts,
+			LastAlertAt:     s.LastAlertAt,
+			Discovered:      s.Info != nil,
+		}
+		if s.Info != nil {
+			hosts[i].OS = s.Info.OS
+			hosts[i].Kernel = s.Info.Kernel
+			hosts[i].Architecture = s.Info.Architecture
+			hosts[i].Cores = s.Info.Cores
+			hosts[i].AgentVersion = s.Info.AgentVersion
+			hosts[i].Labels = s.Info.Labels
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"hosts": hosts,
+		"total": len(hosts),
+	})
+}
+
+// handleHostIncidents returns the incidents that involved a given host
+func (h *Handler) handleHostIncidents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	host := r.PathValue("host")
+	if host == "" {
+		h.writeError(w, http.StatusBadRequest, "Invalid host")
+		return
+	}
+
+	incidents, err := h.repo.GetIncidents(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve incidents")
+		return
+	}
+
+	items := []IncidentListItemResponse{}
+	for _, incident := range incidents {
+		for _, incidentHost := range services.IncidentHosts(incident) {
+			if incidentHost == host {
+				items = append(items, h.convertIncidentToListItem(incident))
+				break
+			}
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"host":      host,
+		"incidents": items,
+		"total":     len(items),
+	})
+}
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/services"
+)
+
+// Verdicts of an incident comparison
+const (
+	VerdictSameProblem = "same_problem" // Same root cause, fixed by the same playbook
+	VerdictRelated     = "related"      // Same root cause, same playbook or mostly the same signals
+	VerdictDifferent   = "different"
+)
+
+// IncidentComparisonResponse is the diff of two incidents, e.g. to decide in a
+// retrospective whether a repeat incident is the same problem again
+type IncidentComparisonResponse struct {
+	A           ComparedIncidentResponse      `json:"a"`
+	B           ComparedIncidentResponse      `json:"b"`
+	Hosts       OverlapResponse               `json:"hosts"`
+	Resources   OverlapResponse               `json:"resources"` // Resource types
+	Charts      OverlapResponse               `json:"charts"`
+	Timeline    TimelineAlignmentResponse     `json:"timeline"`
+	RootCause   RootCauseComparisonResponse   `json:"root_cause"`
+	BlastRadius BlastRadiusComparisonResponse `json:"blast_radius"`
+	Playbook    PlaybookComparisonResponse    `json:"playbook"`
+	Verdict     string                        `json:"verdict"` // same_problem, related or different
+	Reasons     []string                      `json:"reasons"`
+}
+
+// ComparedIncidentResponse identifies a compared incident
+type ComparedIncidentResponse struct {
+	ID         string     `json:"id"`
+	Title      string     `json:"title"`
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	Duration   string     `json:"duration"`
+	Priority   string     `json:"priority"`
+	RiskLevel  string     `json:"risk_level"`
+	Events     int        `json:"events"`
+}
+
+// OverlapResponse splits the values of both incidents into shared and one-sided ones
+type OverlapResponse struct {
+	Common     []string `json:"common"`
+	OnlyA      []string `json:"only_a"`
+	OnlyB      []string `json:"only_b"`
+	Similarity float64  `json:"similarity"` // Shared values over all values, 0-1
+}
+
+// TimelineAlignmentResponse lines up the alert signals (chart/alert name) of both incidents
+// by their offset from each incident's start
+type TimelineAlignmentResponse struct {
+	Aligned   []AlignedSignalResponse `json:"aligned"`
+	OnlyA     []string                `json:"only_a"`
+	OnlyB     []string                `json:"only_b"`
+	SameOrder bool                    `json:"same_order"` // Shared signals first fired in the same order
+	MeanShift string                  `json:"mean_shift"`
+}
+
+// AlignedSignalResponse is a signal of both incidents with when it first fired in each
+type AlignedSignalResponse struct {
+	Signal       string `json:"signal"`
+	ResourceType string `json:"resource_type"`
+	OffsetA      string `json:"offset_a"`
+	OffsetB      string `json:"offset_b"`
+	Shift        string `json:"shift"` // How much later it fired in B, negative if earlier
+}
+
+// RootCauseComparisonResponse compares the predicted root causes
+type RootCauseComparisonResponse struct {
+	A                *RootCauseResponse `json:"a,omitempty"`
+	B                *RootCauseResponse `json:"b,omitempty"`
+	SameResourceType bool               `json:"same_resource_type"`
+	SameChart        bool               `json:"same_chart"`
+	SameHost         bool               `json:"same_host"`
+}
+
+// BlastRadiusComparisonResponse compares the predicted blast radii
+type BlastRadiusComparisonResponse struct {
+	A                *BlastRadiusResponse `json:"a,omitempty"`
+	B                *BlastRadiusResponse `json:"b,omitempty"`
+	ImpactScoreDelta float64              `json:"impact_score_delta"` // B minus A
+	SameRiskLevel    bool                 `json:"same_risk_level"`
+	Services         OverlapResponse      `json:"services"`
+}
+
+// PlaybookComparisonResponse compares the fix playbooks applying to both incidents
+type PlaybookComparisonResponse struct {
+	A    PlaybookRefResponse `json:"a"`
+	B    PlaybookRefResponse `json:"b"`
+	Same bool                `json:"same"`
+}
+
+// PlaybookRefResponse is the fix playbook of an incident: an organization playbook, or
+// the built-in fixes for the root cause's resource type
+type PlaybookRefResponse struct {
+	ID           string `json:"id,omitempty"` // Organization playbook; empty for the built-in fixes
+	ResourceType string `json:"resource_type"`
+	RunbookURL   string `json:"runbook_url,omitempty"`
+}
+
+// handleIncidentCompare compares the incidents given by the a and b query parameters
+func (h *Handler) handleIncidentCompare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	idA, idB := r.URL.Query().Get("a"), r.URL.Query().Get("b")
+	if idA == "" || idB == "" {
+		h.writeError(w, http.StatusBadRequest, "Parameters a and b are required")
+		return
+	}
+	if idA == idB {
+		h.writeError(w, http.StatusBadRequest, "Parameters a and b must be different incidents")
+		return
+	}
+
+	ctx := r.Context()
+	var incidents [2]*domain.Incident
+	for i, id := range []string{idA, idB} {
+		incident, err := h.findIncident(ctx, id)
+		if err != nil {
+			h.logger.Error("Failed to get incidents", observability.Error(err))
+			h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+			return
+		}
+		if incident == nil {
+			h.writeError(w, http.StatusNotFound, fmt.Sprintf("Incident %s not found", id))
+			return
+		}
+		incidents[i] = incident
+	}
+
+	h.writeJSON(w, http.StatusOK, h.compareIncidents(ctx, *incidents[0], *incidents[1]))
+}
+
+// compareIncidents diffs two incidents and judges whether they are the same problem
+func (h *Handler) compareIncidents(ctx context.Context, a, b domain.Incident) IncidentComparisonResponse {
+	detailA, detailB := h.incidentDetail(ctx, &a), h.incidentDetail(ctx, &b)
+	comparison := services.CompareIncidents(a, b)
+
+	response := IncidentComparisonResponse{
+		A:         comparedIncident(detailA),
+		B:         comparedIncident(detailB),
+		Hosts:     overlapResponse(comparison.Hosts),
+		Resources: overlapResponse(comparison.Resources),
+		Charts:    overlapResponse(comparison.Charts),
+		Timeline: TimelineAlignmentResponse{
+			Aligned:   make([]AlignedSignalResponse, len(comparison.Timeline.Aligned)),
+			OnlyA:     comparison.Timeline.OnlyA,
+			OnlyB:     comparison.Timeline.OnlyB,
+			SameOrder: comparison.Timeline.SameOrder,
+			MeanShift: comparison.Timeline.MeanShift.String(),
+		},
+		RootCause: RootCauseComparisonResponse{A: detailA.RootCause, B: detailB.RootCause},
+		BlastRadius: BlastRadiusComparisonResponse{
+			A: detailA.BlastRadius,
+			B: detailB.BlastRadius,
+		},
+		Playbook: PlaybookComparisonResponse{A: h.incidentPlaybook(a), B: h.incidentPlaybook(b)},
+	}
+	for i, signal := range comparison.Timeline.Aligned {
+		response.Timeline.Aligned[i] = AlignedSignalResponse{
+			Signal:       signal.Signal,
+			ResourceType: string(signal.ResourceType),
+			OffsetA:      signal.OffsetA.String(),
+			OffsetB:      signal.OffsetB.String(),
+			Shift:        signal.Shift().String(),
+		}
+	}
+
+	if rcA, rcB := detailA.RootCause, detailB.RootCause; rcA != nil && rcB != nil && rcA.AlertID != "" && rcB.AlertID != "" {
+		response.RootCause.SameResourceType = rcA.ResourceType == rcB.ResourceType
+		response.RootCause.SameChart = rcA.Chart == rcB.Chart
+		response.RootCause.SameHost = rcA.Host == rcB.Host
+	}
+
+	var servicesA, servicesB []string
+	if br := detailA.BlastRadius; br != nil {
+		servicesA = br.AffectedServices
+	}
+	if br := detailB.BlastRadius; br != nil {
+		servicesB = br.AffectedServices
+	}
+	response.BlastRadius.Services = overlapResponse(services.NewOverlap(servicesA, servicesB))
+	if brA, brB := detailA.BlastRadius, detailB.BlastRadius; brA != nil && brB != nil {
+		response.BlastRadius.ImpactScoreDelta = brB.ImpactScore - brA.ImpactScore
+		response.BlastRadius.SameRiskLevel = brA.RiskLevel == brB.RiskLevel
+	}
+
+	playbookA, playbookB := response.Playbook.A, response.Playbook.B
+	response.Playbook.Same = playbookA.ResourceType != "" && playbookA.ID == playbookB.ID &&
+		(playbookA.ID != "" || playbookA.ResourceType == playbookB.ResourceType)
+
+	response.Verdict, response.Reasons = compareVerdict(response)
+	return response
+}
+
+// incidentPlaybook returns the fix playbook that applies to the incident's root cause
+func (h *Handler) incidentPlaybook(incident domain.Incident) PlaybookRefResponse {
+	if len(incident.Events) == 0 {
+		return PlaybookRefResponse{}
+	}
+	analyzer := services.NewComprehensiveIncidentAnalyzer()
+	analyzer.SetPlaybooks(h.playbooks)
+	fix := analyzer.AnalyzeIncident(incident).ActionableFixes
+	return PlaybookRefResponse{ID: fix.Playbook, ResourceType: string(fix.RootCauseType), RunbookURL: fix.RunbookURL}
+}
+
+// compareVerdict judges whether the compared incidents are the same problem, with the reasons
+func compareVerdict(c IncidentComparisonResponse) (string, []string) {
+	var reasons []string
+	sameRootCause := c.RootCause.SameResourceType && c.RootCause.SameChart
+	if sameRootCause {
+		reasons = append(reasons, fmt.Sprintf("Both root causes are %s alerts on %s", c.RootCause.A.ResourceType, c.RootCause.A.Chart))
+	} else if a, b := c.RootCause.A, c.RootCause.B; a != nil && b != nil && a.AlertID != "" && b.AlertID != "" {
+		reasons = append(reasons, fmt.Sprintf("Root causes differ: %s on %s vs %s on %s",
+			a.ResourceType, a.Chart, b.ResourceType, b.Chart))
+	}
+	switch {
+	case c.Playbook.Same && c.Playbook.A.ID != "":
+		reasons = append(reasons, "Both are fixed by playbook "+c.Playbook.A.ID)
+	case c.Playbook.Same:
+		reasons = append(reasons, "Both are fixed by the built-in "+c.Playbook.A.ResourceType+" fixes")
+	default:
+		reasons = append(reasons, "Different fix playbooks apply")
+	}
+	similarSignals := c.Charts.Similarity >= 0.5
+	reasons = append(reasons, fmt.Sprintf("%d of %d charts are shared", len(c.Charts.Common),
+		len(c.Charts.Common)+len(c.Charts.OnlyA)+len(c.Charts.OnlyB)))
+	if len(c.Hosts.Common) == 0 {
+		reasons = append(reasons, "No host is affected by both")
+	}
+
+	switch {
+	case sameRootCause && c.Playbook.Same:
+		return VerdictSameProblem, reasons
+	case sameRootCause || c.Playbook.Same || similarSignals:
+		return VerdictRelated, reasons
+	}
+	return VerdictDifferent, reasons
+}
+
+func comparedIncident(detail IncidentDetailResponse) ComparedIncidentResponse {
+	return ComparedIncidentResponse{
+		ID:         detail.ID,
+		Title:      detail.Title,
+		Status:     detail.Status,
+		StartedAt:  detail.StartedAt,
+		ResolvedAt: detail.ResolvedAt,
+		Duration:   detail.Duration,
+		Priority:   detail.Priority,
+		RiskLevel:  detail.RiskLevel,
+		Events:     detail.TotalEvents,
+	}
+}
+
+func overlapResponse(o services.Overlap) OverlapResponse {
+	return OverlapResponse{Common: o.Common, OnlyA: o.OnlyA, OnlyB: o.OnlyB, Similarity: o.Similarity}
+}
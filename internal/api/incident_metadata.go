@@ -0,0 +1,174 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"incident-teller/internal/domain"
+)
+
+// Limits of the metadata an incident can carry
+const (
+	maxIncidentTags        = 50
+	maxIncidentValueLength = 256
+)
+
+// customFieldParam prefixes the listing parameters filtering by custom field, e.g.
+// ?field.team=payments
+const customFieldParam = "field."
+
+// customFieldName is the pattern of custom field names, e.g. "team" or "product_area"
+var customFieldName = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// patchIncidentMetadata applies the severity, tags and custom fields of a PATCH request to
+// an incident's metadata. On invalid input it returns a message suitable for a 400
+// response.
+func patchIncidentMetadata(metadata domain.IncidentMetadata, req IncidentPatchRequest) (domain.IncidentMetadata, string) {
+	if req.Severity != nil {
+		severity := strings.TrimSpace(*req.Severity)
+		if len(severity) > maxIncidentValueLength {
+			return metadata, fmt.Sprintf("severity must be at most %d characters", maxIncidentValueLength)
+		}
+		metadata.Severity = severity
+	}
+
+	if req.Tags != nil {
+		tags := domain.NormalizeTags(*req.Tags)
+		if len(tags) > maxIncidentTags {
+			return metadata, fmt.Sprintf("at most %d tags are allowed", maxIncidentTags)
+		}
+		for _, tag := range tags {
+			if len(tag) > maxIncidentValueLength || strings.Contains(tag, ",") {
+				return metadata, fmt.Sprintf("invalid tag %q: must be at most %d characters without commas", tag, maxIncidentValueLength)
+			}
+		}
+		metadata.Tags = tags
+	}
+
+	if req.CustomFields != nil {
+		fields := make(map[string]string, len(metadata.CustomFields)+len(req.CustomFields))
+		for name, value := range metadata.CustomFields {
+			fields[name] = value
+		}
+		for name, value := range req.CustomFields {
+			if !customFieldName.MatchString(name) {
+				return metadata, fmt.Sprintf("invalid custom field name %q: use letters, digits, '_', '.' or '-'", name)
+			}
+			if value == nil {
+				delete(fields, name)
+				continue
+			}
+			if len(*value) > maxIncidentValueLength {
+				return metadata, fmt.Sprintf("custom field %s must be at most %d characters", name, maxIncidentValueLength)
+			}
+			fields[name] = *value
+		}
+		if len(fields) == 0 {
+			fields = nil
+		}
+		metadata.CustomFields = fields
+	}
+
+	if len(metadata.Tags) == 0 {
+		metadata.Tags = nil
+	}
+	return metadata, ""
+}
+
+// incidentMetadataFilter selects incidents by severity, tags and custom fields
+type incidentMetadataFilter struct {
+	severities   []string          // Any of them
+	tags         []string          // All of them
+	customFields map[string]string // All of them
+}
+
+// parseIncidentMetadataFilter reads the ?severity=, ?tag= and ?field.<name>= parameters of
+// the incident listing. Severities may be comma-separated; tags may be repeated or
+// comma-separated.
+func parseIncidentMetadataFilter(r *http.Request) incidentMetadataFilter {
+	var filter incidentMetadataFilter
+	for name, values := range r.URL.Query() {
+		switch {
+		case name == "severity":
+			for _, value := range values {
+				filter.severities = append(filter.severities, splitList(value)...)
+			}
+		case name == "tag":
+			for _, value := range values {
+				filter.tags = append(filter.tags, splitList(value)...)
+			}
+		case strings.HasPrefix(name, customFieldParam) && len(name) > len(customFieldParam):
+			if filter.customFields == nil {
+				filter.customFields = make(map[string]string)
+			}
+			filter.customFields[strings.TrimPrefix(name, customFieldParam)] = values[0]
+		}
+	}
+	return filter
+}
+
+// splitList splits a comma-separated parameter, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// matches reports whether an incident satisfies the filter. Severities and tags compare
+// case-insensitively, custom field values exactly.
+func (f incidentMetadataFilter) matches(incident domain.Incident) bool {
+	if len(f.severities) > 0 {
+		found := false
+		for _, severity := range f.severities {
+			found = found || strings.EqualFold(incident.Severity, severity)
+		}
+		if !found {
+			return false
+		}
+	}
+	if !incident.HasTags(f.tags...) {
+		return false
+	}
+	for name, value := range f.customFields {
+		if actual, ok := incident.CustomFields[name]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// filterIncidentsByMetadata keeps the incidents matching the filter
+func filterIncidentsByMetadata(incidents []domain.Incident, filter incidentMetadataFilter) []domain.Incident {
+	if len(filter.severities) == 0 && len(filter.tags) == 0 && len(filter.customFields) == 0 {
+		return incidents
+	}
+	matched := make([]domain.Incident, 0, len(incidents))
+	for _, incident := range incidents {
+		if filter.matches(incident) {
+			matched = append(matched, incident)
+		}
+	}
+	return matched
+}
+
+// incidentTags returns the tags of an incident, never nil
+func incidentTags(incident domain.Incident) []string {
+	if incident.Tags == nil {
+		return []string{}
+	}
+	return incident.Tags
+}
+
+// incidentCustomFields returns the custom fields of an incident, never nil
+func incidentCustomFields(incident domain.Incident) map[string]string {
+	if incident.CustomFields == nil {
+		return map[string]string{}
+	}
+	return incident.CustomFields
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/labels"
+)
+
+// IncidentQueryRepository is implemented by repositories that can search and sort incidents natively
+type IncidentQueryRepository interface {
+	QueryIncidents(ctx context.Context, q domain.IncidentQuery) ([]domain.Incident, error)
+}
+
+// parseIncidentQuery reads the ?q=, ?sort= and ?order= parameters of the incident listing.
+// On invalid input it returns a message suitable for a 400 response.
+func parseIncidentQuery(r *http.Request) (domain.IncidentQuery, string) {
+	params := r.URL.Query()
+	return newIncidentQuery(params.Get("q"), params.Get("sort"), params.Get("order"))
+}
+
+// newIncidentQuery validates a search, sort field and order shared by the REST and gRPC listings
+func newIncidentQuery(search, sortBy, order string) (domain.IncidentQuery, string) {
+	query := domain.IncidentQuery{
+		Search: strings.TrimSpace(search),
+		SortBy: domain.SortByStartedAt,
+	}
+
+	if sortBy != "" {
+		switch s := domain.IncidentSort(sortBy); s {
+		case domain.SortByStartedAt, domain.SortByDuration, domain.SortByRisk, domain.SortByEvents, domain.SortByPriority:
+			query.SortBy = s
+		default:
+			return query, "Invalid sort: must be started_at, duration, risk, events or priority"
+		}
+	}
+
+	switch strings.ToLower(order) {
+	case "", "desc":
+	case "asc":
+		query.Ascending = true
+	default:
+		return query, "Invalid order: must be asc or desc"
+	}
+
+	return query, ""
+}
+
+// queryIncidents searches and sorts incidents in the repository when supported,
+// otherwise in memory
+func (h *Handler) queryIncidents(ctx context.Context, q domain.IncidentQuery) ([]domain.Incident, error) {
+	if repo, ok := h.repo.(IncidentQueryRepository); ok {
+		return repo.QueryIncidents(ctx, q)
+	}
+
+	incidents, err := h.repo.GetIncidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return domain.ApplyIncidentQuery(incidents, q, time.Now()), nil
+}
+
+// parseLabelMatchers reads the ?labels= matchers of the incident listing, e.g.
+// service="checkout",env!~"dev|staging". On invalid input it returns a message suitable
+// for a 400 response.
+func parseLabelMatchers(r *http.Request) (labels.Matchers, string) {
+	matchers, err := labels.ParseMatchers(r.URL.Query().Get("labels"))
+	if err != nil {
+		return nil, "Invalid labels: " + err.Error()
+	}
+	return matchers, ""
+}
+
+// filterIncidentsByLabels keeps the incidents whose labels satisfy every matcher
+func filterIncidentsByLabels(incidents []domain.Incident, matchers labels.Matchers) []domain.Incident {
+	if len(matchers) == 0 {
+		return incidents
+	}
+	matched := make([]domain.Incident, 0, len(incidents))
+	for _, incident := range incidents {
+		if matchers.Matches(incident.Labels()) {
+			matched = append(matched, incident)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/ports"
+)
+
+// IncidentStateRequest moves an incident to another lifecycle state; ChangedBy is required
+type IncidentStateRequest struct {
+	State     string `json:"state"` // triaged, mitigating, monitoring, resolved or postmortem
+	ChangedBy string `json:"changed_by"`
+	Note      string `json:"note,omitempty"`
+}
+
+// StateTransitionResponse is a lifecycle state change of an incident
+type StateTransitionResponse struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	ChangedBy string    `json:"changed_by"`
+	Note      string    `json:"note,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// handleIncidentState moves an incident to another lifecycle state, then returns the
+// updated incident detail. Transitions the state machine doesn't allow are rejected.
+func (h *Handler) handleIncidentState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	store, ok := h.repo.(ports.IncidentStateStore)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, "Incident states not supported by the repository")
+		return
+	}
+
+	var req IncidentStateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	state, err := domain.ParseIncidentState(req.State)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid state: must be detected, triaged, mitigating, monitoring, resolved or postmortem")
+		return
+	}
+	changedBy := strings.TrimSpace(req.ChangedBy)
+	if changedBy == "" {
+		h.writeError(w, http.StatusBadRequest, "changed_by is required")
+		return
+	}
+	auditActor(r, changedBy)
+
+	ctx := r.Context()
+	incident, err := h.findIncident(ctx, r.PathValue("id"))
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+	if incident == nil {
+		h.writeError(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+
+	current := incident.CurrentState()
+	if !current.CanTransitionTo(state) {
+		h.writeError(w, http.StatusConflict, fmt.Sprintf("Cannot move incident from %s to %s", current, state))
+		return
+	}
+
+	transition := domain.StateTransition{
+		IncidentID: incident.ID,
+		From:       current,
+		To:         state,
+		ChangedBy:  changedBy,
+		Note:       strings.TrimSpace(req.Note),
+		ChangedAt:  time.Now().UTC(),
+	}
+	if err := store.SetIncidentState(ctx, transition); err != nil {
+		if errors.Is(err, domain.ErrStateConflict) {
+			h.writeError(w, http.StatusConflict, fmt.Sprintf("Incident is no longer %s: it was moved concurrently", current))
+			return
+		}
+		h.logger.Error("Failed to set incident state",
+			observability.String("incident_id", incident.ID), observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to set incident state")
+		return
+	}
+	incident.State = state
+	incident.StateChangedAt = &transition.ChangedAt
+	auditChange(r, map[string]any{"state": string(current)}, map[string]any{"state": string(state)})
+	h.logger.Info("Incident state changed",
+		observability.String("incident_id", incident.ID),
+		observability.String("from", string(current)),
+		observability.String("to", string(state)),
+		observability.String("changed_by", changedBy))
+
+	h.writeJSON(w, http.StatusOK, h.incidentDetail(ctx, incident))
+}
+
+// stateHistory returns the state transitions of an incident, nil if the repository
+// doesn't keep them
+func (h *Handler) stateHistory(ctx context.Context, incidentID string) []StateTransitionResponse {
+	store, ok := h.repo.(ports.IncidentStateStore)
+	if !ok {
+		return nil
+	}
+	transitions, err := store.GetStateTransitions(ctx, incidentID)
+	if err != nil {
+		h.logger.Warn("Failed to get state transitions",
+			observability.String("incident_id", incidentID), observability.Error(err))
+		return nil
+	}
+
+	var history []StateTransitionResponse
+	for _, transition := range transitions {
+		history = append(history, StateTransitionResponse{
+			From:      string(transition.From),
+			To:        string(transition.To),
+			ChangedBy: transition.ChangedBy,
+			Note:      transition.Note,
+			ChangedAt: transition.ChangedAt,
+		})
+	}
+	return history
+}
+
+// allowedTransitions returns the states an incident may move to next, never nil
+func allowedTransitions(incident domain.Incident) []string {
+	allowed := []string{}
+	for _, state := range incident.CurrentState().Transitions() {
+		allowed = append(allowed, string(state))
+	}
+	return allowed
+}
+
+// parseIncidentStateFilter reads the comma-separated ?state= parameter of the incident
+// listing. On invalid input it returns a message suitable for a 400 response.
+func parseIncidentStateFilter(r *http.Request) ([]domain.IncidentState, string) {
+	var states []domain.IncidentState
+	for _, value := range splitList(r.URL.Query().Get("state")) {
+		state, err := domain.ParseIncidentState(value)
+		if err != nil {
+			return nil, "Invalid state: must be detected, triaged, mitigating, monitoring, resolved or postmortem"
+		}
+		states = append(states, state)
+	}
+	return states, ""
+}
+
+// filterIncidentsByState keeps the incidents currently in any of the states
+func filterIncidentsByState(incidents []domain.Incident, states []domain.IncidentState) []domain.Incident {
+	if len(states) == 0 {
+		return incidents
+	}
+	matched := make([]domain.Incident, 0, len(incidents))
+	for _, incident := range incidents {
+		current := incident.CurrentState()
+		for _, state := range states {
+			if current == state {
+				matched = append(matched, incident)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// stateCounts counts the incidents in each lifecycle state, including empty ones
+func stateCounts(incidents []domain.Incident) map[string]int {
+	counts := make(map[string]int, len(domain.IncidentStates))
+	for _, state := range domain.IncidentStates {
+		counts[string(state)] = 0
+	}
+	for _, incident := range incidents {
+		counts[string(incident.CurrentState())]++
+	}
+	return counts
+}
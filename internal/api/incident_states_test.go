@@ -0,0 +1,64 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func TestIncidentState_ConcurrentTransitions(t *testing.T) {
+	h := newTestHandler(t, domain.Incident{ID: "inc-a", StartedAt: time.Now()})
+	routes := h.SetupRoutes()
+
+	// Responders racing from detected to different states: one wins, the rest conflict
+	var wg sync.WaitGroup
+	codes := make([]int, 8)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			state := []string{"triaged", "mitigating"}[i%2]
+			body := fmt.Sprintf(`{"state": %q, "changed_by": "responder-%d"}`, state, i)
+			codes[i] = serve(routes, http.MethodPost, "/api/incidents/inc-a/state", body).Code
+		}(i)
+	}
+	wg.Wait()
+
+	won := 0
+	for _, code := range codes {
+		switch code {
+		case http.StatusOK:
+			won++
+		case http.StatusConflict:
+		default:
+			t.Fatalf("expected 200 or 409, got %d", code)
+		}
+	}
+	if won != 1 {
+		t.Fatalf("expected one transition to win, got %d (%v)", won, codes)
+	}
+}
+
+func TestIncidentState_RejectsInvalidTransition(t *testing.T) {
+	h := newTestHandler(t, domain.Incident{ID: "inc-a", StartedAt: time.Now()})
+	routes := h.SetupRoutes()
+
+	for _, step := range []struct {
+		body string
+		want int
+	}{
+		{`{"state": "postmortem", "changed_by": "alice"}`, http.StatusConflict},
+		{`{"state": "triaged"}`, http.StatusBadRequest},
+		{`{"state": "fixed", "changed_by": "alice"}`, http.StatusBadRequest},
+		{`{"state": "resolved", "changed_by": "alice"}`, http.StatusOK},
+		{`{"state": "triaged", "changed_by": "alice"}`, http.StatusConflict},
+	} {
+		if rec := serve(routes, http.MethodPost, "/api/incidents/inc-a/state", step.body); rec.Code != step.want {
+			t.Errorf("%s: expected %d, got %d: %s", step.body, step.want, rec.Code, rec.Body.String())
+		}
+	}
+}
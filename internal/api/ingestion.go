@@ -0,0 +1,36 @@
+package api
+
+import (
+	"time"
+
+	"incident-teller/internal/services"
+)
+
+// IngestionHealthResponse is the heartbeat of alert ingestion
+type IngestionHealthResponse struct {
+	LastSuccessfulPoll *time.Time `json:"last_successful_poll,omitempty"`
+	SilenceSeconds     float64    `json:"silence_seconds"` // Since the last successful poll of any source
+	StallThreshold     string     `json:"stall_threshold"`
+	Stalled            bool       `json:"stalled"`
+	StalledSince       *time.Time `json:"stalled_since,omitempty"`
+}
+
+// SetIngestionWatchdog adds the ingestion heartbeat to /api/health
+func (h *Handler) SetIngestionWatchdog(watchdog *services.IngestionWatchdog) {
+	h.watchdog = watchdog
+}
+
+// ingestionHealth returns the ingestion heartbeat, or nil without a watchdog
+func (h *Handler) ingestionHealth() *IngestionHealthResponse {
+	if h.watchdog == nil {
+		return nil
+	}
+	status := h.watchdog.Status(time.Now())
+	return &IngestionHealthResponse{
+		LastSuccessfulPoll: status.LastSuccess,
+		SilenceSeconds:     status.Silence.Seconds(),
+		StallThreshold:     h.watchdog.Threshold().String(),
+		Stalled:            status.Stalled,
+		StalledSince:       status.StalledAt,
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"incident-teller/internal/observability"
+	"incident-teller/internal/oncall"
+)
+
+// OnCallShiftResponse describes who is on call and for how long
+type OnCallShiftResponse struct {
+	Member     oncall.Member `json:"member"`
+	ShiftStart time.Time     `json:"shift_start"`
+	ShiftEnd   time.Time     `json:"shift_end"`
+	Override   bool          `json:"override"`
+}
+
+// OnCallScheduleRequest is the body accepted by PUT /api/oncall/schedule and returned by GET
+type OnCallScheduleRequest struct {
+	Members     []oncall.Member   `json:"members"`
+	Start       time.Time         `json:"start"`
+	ShiftLength string            `json:"shift_length"` // Go duration, e.g. "168h"
+	Overrides   []oncall.Override `json:"overrides,omitempty"`
+}
+
+// SetOnCall enables the on-call endpoints and assignee reporting on incidents
+func (h *Handler) SetOnCall(manager *oncall.Manager) {
+	h.onCall = manager
+}
+
+// handleOnCallCurrent returns the member currently on call
+func (h *Handler) handleOnCallCurrent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.onCall == nil {
+		h.writeError(w, http.StatusNotFound, "On-call schedule not configured")
+		return
+	}
+
+	shift, ok := h.onCall.Current(time.Now())
+	if !ok {
+		h.writeError(w, http.StatusNotFound, "Nobody is on call")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, OnCallShiftResponse{
+		Member:     shift.Member,
+		ShiftStart: shift.Start,
+		ShiftEnd:   shift.End,
+		Override:   shift.Override,
+	})
+}
+
+// handleOnCallSchedule returns (GET) or replaces (PUT) the on-call rotation
+func (h *Handler) handleOnCallSchedule(w http.ResponseWriter, r *http.Request) {
+	if h.onCall == nil {
+		h.writeError(w, http.StatusNotFound, "On-call schedule not configured")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.writeJSON(w, http.StatusOK, convertScheduleToResponse(h.onCall.Schedule()))
+
+	case http.MethodPut:
+		var req OnCallScheduleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		shiftLength, err := time.ParseDuration(req.ShiftLength)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid shift_length")
+			return
+		}
+
+		schedule := oncall.Schedule{
+			Members:     req.Members,
+			Start:       req.Start,
+			ShiftLength: shiftLength,
+			Overrides:   req.Overrides,
+		}
+		previous := convertScheduleToResponse(h.onCall.Schedule())
+		if err := h.onCall.SetSchedule(schedule); err != nil {
+			h.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		updated := convertScheduleToResponse(h.onCall.Schedule())
+		auditChange(r, previous, updated)
+
+		h.logger.Info("On-call schedule updated",
+			observability.Int("members", len(schedule.Members)),
+			observability.String("shift_length", shiftLength.String()))
+
+		h.writeJSON(w, http.StatusOK, updated)
+
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// handleOnCallOverrides adds a temporary override, e.g. for a shift swap
+func (h *Handler) handleOnCallOverrides(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.onCall == nil {
+		h.writeError(w, http.StatusNotFound, "On-call schedule not configured")
+		return
+	}
+
+	var override oncall.Override
+	if err := json.NewDecoder(r.Body).Decode(&override); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.onCall.AddOverride(override); err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, override)
+}
+
+// incidentAssignee returns the name of the on-call member an incident was assigned to
+func (h *Handler) incidentAssignee(incidentID string) string {
+	if h.onCall == nil {
+		return ""
+	}
+	if assignment, ok := h.onCall.Assignment(incidentID); ok {
+		return assignment.Member.Name
+	}
+	return ""
+}
+
+func convertScheduleToResponse(schedule oncall.Schedule) OnCallScheduleRequest {
+	return OnCallScheduleRequest{
+		Members:     schedule.Members,
+		Start:       schedule.Start,
+		ShiftLength: schedule.ShiftLength.String(),
+		Overrides:   schedule.Overrides,
+	}
+}
@@ -0,0 +1,269 @@
+// Package openapi describes HTTP routes as an OpenAPI 3 document. Routes are declared once,
+// with their handler and their request/response types, and both the ServeMux registration
+// and the spec are generated from that declaration, so they cannot drift apart.
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is the OpenAPI version of generated documents
+const Version = "3.0.3"
+
+const jsonContentType = "application/json"
+
+// Route is an HTTP route: a ServeMux pattern, its handler and the operations it serves
+type Route struct {
+	Pattern    string // http.ServeMux pattern the handler is registered under
+	Path       string // Path template in the spec, if it differs from Pattern
+	Handler    http.HandlerFunc
+	Tag        string      // Groups the operations in the spec
+	Operations []Operation // Documented methods; routes without any are left out of the spec
+}
+
+// Operation documents one method of a route
+type Operation struct {
+	Method       string
+	Summary      string
+	Description  string
+	Query        []Param
+	Request      any    // Zero value of the request body type; nil if there is no body
+	RequestType  string // Request content type; defaults to application/json
+	Status       int    // Success status; defaults to 200
+	Response     any    // Zero value of the response body type; nil if there is no body
+	ResponseType string // Response content type; defaults to application/json
+	Auth         bool   // Requires a bearer token
+}
+
+// Param is a query parameter
+type Param struct {
+	Name        string
+	Type        string // string, integer, number or boolean; defaults to string
+	Description string
+	Required    bool
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Document is an OpenAPI 3 document
+type Document struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       Info                                   `json:"info"`
+	Tags       []Tag                                  `json:"tags,omitempty"`
+	Paths      map[string]map[string]*OperationObject `json:"paths"`
+	Components Components                             `json:"components"`
+}
+
+// Tag names a group of operations
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// Components holds the reusable schemas and security schemes
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme is an authentication method
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+}
+
+// OperationObject is an operation in the spec
+type OperationObject struct {
+	Tags        []string                   `json:"tags,omitempty"`
+	Summary     string                     `json:"summary,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	OperationID string                     `json:"operationId"`
+	Parameters  []ParameterObject          `json:"parameters,omitempty"`
+	RequestBody *RequestBody               `json:"requestBody,omitempty"`
+	Responses   map[string]*ResponseObject `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+// ParameterObject is a path or query parameter in the spec
+type ParameterObject struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is a request body in the spec
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// ResponseObject is a response in the spec
+type ResponseObject struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a body in one content type
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}.$]+)(\.\.\.)?\}`)
+
+// Register registers the handler of every route on mux
+func Register(mux *http.ServeMux, routes []Route) {
+	for _, route := range routes {
+		mux.HandleFunc(route.Pattern, route.Handler)
+	}
+}
+
+// Build generates the document for routes. Every operation also documents errorResponse
+// as its error body.
+func Build(info Info, errorResponse any, routes []Route) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]map[string]*OperationObject),
+	}
+	schemas := newSchemaRegistry()
+	errorSchema := schemas.schemaOf(errorResponse)
+
+	tags := make(map[string]bool)
+	secured := false
+	for _, route := range routes {
+		if len(route.Operations) == 0 {
+			continue
+		}
+		path := route.Path
+		if path == "" {
+			path = specPath(route.Pattern)
+		}
+		if route.Tag != "" && !tags[route.Tag] {
+			tags[route.Tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: route.Tag})
+		}
+
+		item := doc.Paths[path]
+		if item == nil {
+			item = make(map[string]*OperationObject)
+			doc.Paths[path] = item
+		}
+		for _, op := range route.Operations {
+			item[strings.ToLower(op.Method)] = buildOperation(path, route.Tag, op, schemas, errorSchema)
+			secured = secured || op.Auth
+		}
+	}
+
+	doc.Components.Schemas = schemas.components
+	if secured {
+		doc.Components.SecuritySchemes = map[string]*SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer"},
+		}
+	}
+	return doc
+}
+
+func buildOperation(path, tag string, op Operation, schemas *schemaRegistry, errorSchema *Schema) *OperationObject {
+	operation := &OperationObject{
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: operationID(op.Method, path),
+		Responses:   make(map[string]*ResponseObject),
+	}
+	if tag != "" {
+		operation.Tags = []string{tag}
+	}
+
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		operation.Parameters = append(operation.Parameters, ParameterObject{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	for _, param := range op.Query {
+		paramType := param.Type
+		if paramType == "" {
+			paramType = "string"
+		}
+		operation.Parameters = append(operation.Parameters, ParameterObject{
+			Name:        param.Name,
+			In:          "query",
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      &Schema{Type: paramType},
+		})
+	}
+
+	if op.Request != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{contentType(op.RequestType): {Schema: schemas.schemaOf(op.Request)}},
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	response := &ResponseObject{Description: http.StatusText(status)}
+	switch {
+	case op.Response != nil:
+		response.Content = map[string]MediaType{contentType(op.ResponseType): {Schema: schemas.schemaOf(op.Response)}}
+	case op.ResponseType != "":
+		response.Content = map[string]MediaType{op.ResponseType: {Schema: &Schema{Type: "string"}}}
+	}
+	operation.Responses[strconv.Itoa(status)] = response
+	operation.Responses["default"] = &ResponseObject{
+		Description: "Error",
+		Content:     map[string]MediaType{jsonContentType: {Schema: errorSchema}},
+	}
+
+	if op.Auth {
+		operation.Security = []map[string][]string{{"bearerAuth": {}}}
+	}
+	return operation
+}
+
+func contentType(t string) string {
+	if t == "" {
+		return jsonContentType
+	}
+	return t
+}
+
+// specPath converts a ServeMux pattern to a path template: "/{$}" becomes "/" and
+// "{rest...}" becomes "{rest}"
+func specPath(pattern string) string {
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		pattern = path
+	}
+	pattern = strings.ReplaceAll(pattern, "{$}", "")
+	pattern = strings.ReplaceAll(pattern, "...}", "}")
+	if pattern == "" {
+		return "/"
+	}
+	return pattern
+}
+
+// operationID derives a stable ID such as getApiIncidentsId from the method and path
+func operationID(method, path string) string {
+	var id strings.Builder
+	id.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-' || r == '.' || r == '_'
+	}) {
+		id.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return id.String()
+}
@@ -0,0 +1,69 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+type testError struct {
+	Message string `json:"message"`
+}
+
+type testNode struct {
+	Name     string     `json:"name"`
+	Started  time.Time  `json:"started_at"`
+	Parent   *testNode  `json:"parent,omitempty"`
+	Children []testNode `json:"children"`
+	Internal string     `json:"-"`
+}
+
+func TestBuild(t *testing.T) {
+	routes := []Route{
+		{Pattern: "/api/nodes/{id}", Handler: func(http.ResponseWriter, *http.Request) {}, Tag: "Nodes", Operations: []Operation{
+			{Method: http.MethodGet, Response: testNode{}, Query: []Param{{Name: "depth", Type: "integer"}}},
+			{Method: http.MethodPut, Request: testNode{}, Response: Object{"updated": true}, Auth: true},
+		}},
+		{Pattern: "/{$}", Handler: func(http.ResponseWriter, *http.Request) {}},
+	}
+	doc := Build(Info{Title: "Test", Version: "1"}, testError{}, routes)
+
+	if len(doc.Paths) != 1 {
+		t.Fatalf("expected only the documented route, got %v", doc.Paths)
+	}
+	get := doc.Paths["/api/nodes/{id}"]["get"]
+	if get == nil || get.OperationID != "getApiNodesId" {
+		t.Fatalf("unexpected get operation: %+v", get)
+	}
+	if len(get.Parameters) != 2 || get.Parameters[0].In != "path" || get.Parameters[1].Schema.Type != "integer" {
+		t.Errorf("unexpected parameters: %+v", get.Parameters)
+	}
+	if ref := get.Responses["200"].Content[jsonContentType].Schema.Ref; ref != "#/components/schemas/testNode" {
+		t.Errorf("response ref: got %q", ref)
+	}
+
+	node := doc.Components.Schemas["testNode"]
+	if node == nil {
+		t.Fatal("testNode component missing")
+	}
+	if _, ok := node.Properties["Internal"]; ok {
+		t.Error(`json:"-" field documented`)
+	}
+	if node.Properties["started_at"].Format != "date-time" {
+		t.Errorf("time.Time: got %+v", node.Properties["started_at"])
+	}
+	if node.Properties["children"].Items.Ref != "#/components/schemas/testNode" {
+		t.Errorf("recursive slice: got %+v", node.Properties["children"].Items)
+	}
+	if len(node.Required) != 3 {
+		t.Errorf("required: got %v, want name, started_at and children", node.Required)
+	}
+
+	put := doc.Paths["/api/nodes/{id}"]["put"]
+	if put.RequestBody == nil || len(put.Security) != 1 || doc.Components.SecuritySchemes["bearerAuth"] == nil {
+		t.Errorf("expected an authenticated request body, got %+v", put)
+	}
+	if updated := put.Responses["200"].Content[jsonContentType].Schema.Properties["updated"]; updated.Type != "boolean" {
+		t.Errorf("Object property: got %+v", updated)
+	}
+}
@@ -0,0 +1,180 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Schema is a JSON schema in the spec
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+}
+
+// Object describes a JSON object built as a map literal: each value is the zero value of
+// the property's type, e.g. Object{"incidents": []Incident{}, "total": 0}
+type Object map[string]any
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	rawType      = reflect.TypeOf(json.RawMessage(nil))
+	objectType   = reflect.TypeOf(Object(nil))
+)
+
+// schemaRegistry derives schemas from Go types the way encoding/json marshals them. Named
+// struct types become components referenced with $ref.
+type schemaRegistry struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// schemaOf returns the schema of v's type, or of each property if v is an Object
+func (r *schemaRegistry) schemaOf(v any) *Schema {
+	if object, ok := v.(Object); ok {
+		schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for name, value := range object {
+			if value == nil {
+				schema.Properties[name] = &Schema{}
+				continue
+			}
+			schema.Properties[name] = r.schemaOf(value)
+		}
+		return schema
+	}
+	return r.schema(reflect.TypeOf(v))
+}
+
+func (r *schemaRegistry) schema(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64", Description: "Duration in nanoseconds"}
+	case rawType, objectType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := r.schema(t.Elem())
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + r.component(t)}
+	default:
+		// Interfaces and anything else encoding/json can't describe statically
+		return &Schema{}
+	}
+}
+
+// component registers a named struct type, returning its component name. The name is
+// registered before the fields are walked, so recursive types terminate.
+func (r *schemaRegistry) component(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := r.components[name]; taken {
+		// Same type name in another package, e.g. api.HostResponse and domain.HostResponse
+		pkg := []rune(path.Base(t.PkgPath()))
+		name = string(unicode.ToUpper(pkg[0])) + string(pkg[1:]) + name
+	}
+	r.names[t] = name
+	r.components[name] = &Schema{}
+	*r.components[name] = *r.structSchema(t)
+	return name
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.addFields(schema, t)
+	return schema
+}
+
+// addFields adds the JSON properties of t's fields, flattening embedded structs
+func (r *schemaRegistry) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				r.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := r.schema(field.Type)
+		if options == "string" || strings.Contains(options, ",string") {
+			property = &Schema{Type: "string"}
+		}
+		schema.Properties[name] = property
+
+		omitempty := strings.Contains(options, "omitempty")
+		if !omitempty && field.Type.Kind() != reflect.Pointer {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package openapi
+
+import (
+	"html"
+	"strings"
+)
+
+// SwaggerUIVersion is the swagger-ui-dist release loaded from the CDN
+const SwaggerUIVersion = "5.17.14"
+
+// SwaggerUI renders a Swagger UI page for the spec served at specURL. The UI assets are
+// loaded from unpkg.com, so browsing the docs needs internet access.
+func SwaggerUI(title, specURL string) string {
+	base := "https://unpkg.com/swagger-ui-dist@" + SwaggerUIVersion
+
+	var out strings.Builder
+	out.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	out.WriteString(`<meta name="viewport" content="width=device-width, initial-scale=1">` + "\n")
+	out.WriteString("<title>" + html.EscapeString(title) + "</title>\n")
+	out.WriteString(`<link rel="stylesheet" href="` + base + `/swagger-ui.css">` + "\n")
+	out.WriteString("</head>\n<body>\n<div id=\"swagger-ui\"></div>\n")
+	out.WriteString(`<script src="` + base + `/swagger-ui-bundle.js" crossorigin></script>` + "\n")
+	out.WriteString("<script>\nwindow.onload = () => {\n")
+	out.WriteString("  window.ui = SwaggerUIBundle({url: " + jsString(specURL) + ", dom_id: \"#swagger-ui\", deepLinking: true});\n")
+	out.WriteString("};\n</script>\n</body>\n</html>\n")
+	return out.String()
+}
+
+// jsString quotes s as a JavaScript string literal that is safe inside a <script> element
+func jsString(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "<", `\u003c`, ">", `\u003e`, "\n", `\n`)
+	return `"` + replacer.Replace(s) + `"`
+}
@@ -0,0 +1,256 @@
+// gRPC interface of IncidentTeller. It exposes the same incidents and analysis as the
+// REST API under /api.
+//
+// After editing, regenerate the Go code from the repository root with:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	  --go-grpc_out=. --go-grpc_opt=paths=source_relative \
+//	  internal/api/pb/incident_teller.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: internal/api/pb/incident_teller.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	IncidentTeller_ListIncidents_FullMethodName   = "/incidentteller.v1.IncidentTeller/ListIncidents"
+	IncidentTeller_GetIncident_FullMethodName     = "/incidentteller.v1.IncidentTeller/GetIncident"
+	IncidentTeller_AnalyzeIncident_FullMethodName = "/incidentteller.v1.IncidentTeller/AnalyzeIncident"
+	IncidentTeller_StreamIncidents_FullMethodName = "/incidentteller.v1.IncidentTeller/StreamIncidents"
+)
+
+// IncidentTellerClient is the client API for IncidentTeller service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type IncidentTellerClient interface {
+	// ListIncidents returns a page of incidents, like GET /api/incidents
+	ListIncidents(ctx context.Context, in *ListIncidentsRequest, opts ...grpc.CallOption) (*ListIncidentsResponse, error)
+	// GetIncident returns an incident with its timeline and AI analysis, like GET /api/incidents/{id}
+	GetIncident(ctx context.Context, in *GetIncidentRequest, opts ...grpc.CallOption) (*IncidentDetail, error)
+	// AnalyzeIncident tells the story of an incident, like POST /api/analyze
+	AnalyzeIncident(ctx context.Context, in *AnalyzeIncidentRequest, opts ...grpc.CallOption) (*Analysis, error)
+	// StreamIncidents sends an incident each time it is created or changes
+	StreamIncidents(ctx context.Context, in *StreamIncidentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Incident], error)
+}
+
+type incidentTellerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIncidentTellerClient(cc grpc.ClientConnInterface) IncidentTellerClient {
+	return &incidentTellerClient{cc}
+}
+
+func (c *incidentTellerClient) ListIncidents(ctx context.Context, in *ListIncidentsRequest, opts ...grpc.CallOption) (*ListIncidentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListIncidentsResponse)
+	err := c.cc.Invoke(ctx, IncidentTeller_ListIncidents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *incidentTellerClient) GetIncident(ctx context.Context, in *GetIncidentRequest, opts ...grpc.CallOption) (*IncidentDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IncidentDetail)
+	err := c.cc.Invoke(ctx, IncidentTeller_GetIncident_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *incidentTellerClient) AnalyzeIncident(ctx context.Context, in *AnalyzeIncidentRequest, opts ...grpc.CallOption) (*Analysis, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Analysis)
+	err := c.cc.Invoke(ctx, IncidentTeller_AnalyzeIncident_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *incidentTellerClient) StreamIncidents(ctx context.Context, in *StreamIncidentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Incident], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &IncidentTeller_ServiceDesc.Streams[0], IncidentTeller_StreamIncidents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamIncidentsRequest, Incident]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IncidentTeller_StreamIncidentsClient = grpc.ServerStreamingClient[Incident]
+
+// IncidentTellerServer is the server API for IncidentTeller service.
+// All implementations must embed UnimplementedIncidentTellerServer
+// for forward compatibility.
+type IncidentTellerServer interface {
+	// ListIncidents returns a page of incidents, like GET /api/incidents
+	ListIncidents(context.Context, *ListIncidentsRequest) (*ListIncidentsResponse, error)
+	// GetIncident returns an incident with its timeline and AI analysis, like GET /api/incidents/{id}
+	GetIncident(context.Context, *GetIncidentRequest) (*IncidentDetail, error)
+	// AnalyzeIncident tells the story of an incident, like POST /api/analyze
+	AnalyzeIncident(context.Context, *AnalyzeIncidentRequest) (*Analysis, error)
+	// StreamIncidents sends an incident each time it is created or changes
+	StreamIncidents(*StreamIncidentsRequest, grpc.ServerStreamingServer[Incident]) error
+	mustEmbedUnimplementedIncidentTellerServer()
+}
+
+// UnimplementedIncidentTellerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedIncidentTellerServer struct{}
+
+func (UnimplementedIncidentTellerServer) ListIncidents(context.Context, *ListIncidentsRequest) (*ListIncidentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIncidents not implemented")
+}
+func (UnimplementedIncidentTellerServer) GetIncident(context.Context, *GetIncidentRequest) (*IncidentDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIncident not implemented")
+}
+func (UnimplementedIncidentTellerServer) AnalyzeIncident(context.Context, *AnalyzeIncidentRequest) (*Analysis, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnalyzeIncident not implemented")
+}
+func (UnimplementedIncidentTellerServer) StreamIncidents(*StreamIncidentsRequest, grpc.ServerStreamingServer[Incident]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamIncidents not implemented")
+}
+func (UnimplementedIncidentTellerServer) mustEmbedUnimplementedIncidentTellerServer() {}
+func (UnimplementedIncidentTellerServer) testEmbeddedByValue()                        {}
+
+// UnsafeIncidentTellerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IncidentTellerServer will
+// result in compilation errors.
+type UnsafeIncidentTellerServer interface {
+	mustEmbedUnimplementedIncidentTellerServer()
+}
+
+func RegisterIncidentTellerServer(s grpc.ServiceRegistrar, srv IncidentTellerServer) {
+	// If the following call pancis, it indicates UnimplementedIncidentTellerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&IncidentTeller_ServiceDesc, srv)
+}
+
+func _IncidentTeller_ListIncidents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIncidentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IncidentTellerServer).ListIncidents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IncidentTeller_ListIncidents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IncidentTellerServer).ListIncidents(ctx, req.(*ListIncidentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IncidentTeller_GetIncident_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIncidentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IncidentTellerServer).GetIncident(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IncidentTeller_GetIncident_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IncidentTellerServer).GetIncident(ctx, req.(*GetIncidentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IncidentTeller_AnalyzeIncident_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzeIncidentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IncidentTellerServer).AnalyzeIncident(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IncidentTeller_AnalyzeIncident_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IncidentTellerServer).AnalyzeIncident(ctx, req.(*AnalyzeIncidentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IncidentTeller_StreamIncidents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamIncidentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IncidentTellerServer).StreamIncidents(m, &grpc.GenericServerStream[StreamIncidentsRequest, Incident]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IncidentTeller_StreamIncidentsServer = grpc.ServerStreamingServer[Incident]
+
+// IncidentTeller_ServiceDesc is the grpc.ServiceDesc for IncidentTeller service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IncidentTeller_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "incidentteller.v1.IncidentTeller",
+	HandlerType: (*IncidentTellerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListIncidents",
+			Handler:    _IncidentTeller_ListIncidents_Handler,
+		},
+		{
+			MethodName: "GetIncident",
+			Handler:    _IncidentTeller_GetIncident_Handler,
+		},
+		{
+			MethodName: "AnalyzeIncident",
+			Handler:    _IncidentTeller_AnalyzeIncident_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamIncidents",
+			Handler:       _IncidentTeller_StreamIncidents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "internal/api/pb/incident_teller.proto",
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"incident-teller/internal/observability"
+	"incident-teller/internal/playbook"
+)
+
+// SetPlaybooks enables /api/playbooks and makes analyses use the organization's playbooks
+func (h *Handler) SetPlaybooks(store *playbook.Store) {
+	h.playbooks = store
+}
+
+// handlePlaybooks lists (GET) or creates (POST) playbooks
+func (h *Handler) handlePlaybooks(w http.ResponseWriter, r *http.Request) {
+	if h.playbooks == nil {
+		h.writeError(w, http.StatusNotFound, "Playbooks not enabled")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.writeJSON(w, http.StatusOK, h.playbooks.List())
+
+	case http.MethodPost:
+		var p playbook.Playbook
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if _, err := h.playbooks.Get(p.ID); err == nil {
+			h.writeError(w, http.StatusConflict, "Playbook already exists")
+			return
+		}
+		h.putPlaybook(w, r, p)
+
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// handlePlaybook returns (GET), replaces (PUT) or deletes (DELETE) a playbook
+func (h *Handler) handlePlaybook(w http.ResponseWriter, r *http.Request) {
+	if h.playbooks == nil {
+		h.writeError(w, http.StatusNotFound, "Playbooks not enabled")
+		return
+	}
+	id := r.PathValue("id")
+
+	switch r.Method {
+	case http.MethodGet:
+		p, err := h.playbooks.Get(id)
+		if err != nil {
+			h.writeError(w, http.StatusNotFound, "Playbook not found")
+			return
+		}
+		h.writeJSON(w, http.StatusOK, p)
+
+	case http.MethodPut:
+		var p playbook.Playbook
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		p.ID = id
+		h.putPlaybook(w, r, p)
+
+	case http.MethodDelete:
+		existing, _ := h.playbooks.Get(id)
+		if err := h.playbooks.Delete(id); err != nil {
+			if errors.Is(err, playbook.ErrNotFound) {
+				h.writeError(w, http.StatusNotFound, "Playbook not found")
+				return
+			}
+			h.logger.Error("Failed to delete playbook", observability.Error(err))
+			h.writeError(w, http.StatusInternalServerError, "Failed to delete playbook")
+			return
+		}
+		auditChange(r, existing, nil)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// putPlaybook stores a playbook and responds with it, 201 if it is new
+func (h *Handler) putPlaybook(w http.ResponseWriter, r *http.Request, p playbook.Playbook) {
+	if err := p.Validate(); err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var previous any
+	if existing, err := h.playbooks.Get(p.ID); err == nil {
+		previous = existing
+	}
+
+	created, err := h.playbooks.Put(p)
+	if err != nil {
+		h.logger.Error("Failed to save playbook", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to save playbook")
+		return
+	}
+
+	auditChange(r, previous, p)
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	h.writeJSON(w, status, p)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"incident-teller/internal/services"
+)
+
+// PredictionResponse describes one predicted incident
+type PredictionResponse struct {
+	Host          string    `json:"host"`
+	Confidence    float64   `json:"confidence"`
+	ExpectedAt    time.Time `json:"expected_at"`
+	WithinMinutes int       `json:"within_minutes"`
+	WarningIDs    []string  `json:"warning_ids"`
+	WarningNames  []string  `json:"warning_names"`
+	Reasons       []string  `json:"reasons"`
+	PredictedAt   time.Time `json:"predicted_at"`
+}
+
+// SetPredictionService enables GET /api/predictions
+func (h *Handler) SetPredictionService(predictions *services.PredictionService) {
+	h.predictions = predictions
+}
+
+// handlePredictions lists the incidents predicted by the last evaluation, most confident first
+func (h *Handler) handlePredictions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.predictions == nil {
+		h.writeError(w, http.StatusNotFound, "Incident prediction not enabled")
+		return
+	}
+
+	now := time.Now()
+	predictions := []PredictionResponse{}
+	for _, p := range h.predictions.Predictions() {
+		response := PredictionResponse{
+			Host:          p.Host,
+			Confidence:    p.Confidence,
+			ExpectedAt:    p.ExpectedAt,
+			WithinMinutes: p.MinutesUntil(now),
+			WarningIDs:    []string{},
+			WarningNames:  []string{},
+			Reasons:       p.Reasons,
+			PredictedAt:   p.PredictedAt,
+		}
+		for _, alert := range p.Warnings {
+			response.WarningIDs = append(response.WarningIDs, alert.ID)
+			response.WarningNames = append(response.WarningNames, alert.Name)
+		}
+		predictions = append(predictions, response)
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"predictions": predictions,
+		"count":       len(predictions),
+	})
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/ports"
+)
+
+// IncidentPatchRequest changes an incident; fields left out are kept. Priority is P1-P4,
+// or "auto" to follow the risk level again; ChangedBy is recorded in the incident's
+// priority history and metadata.
+type IncidentPatchRequest struct {
+	Priority     *string            `json:"priority,omitempty"`
+	Severity     *string            `json:"severity,omitempty"`      // "" clears it
+	Tags         *[]string          `json:"tags,omitempty"`          // Replaces the tags
+	CustomFields map[string]*string `json:"custom_fields,omitempty"` // Sets the given fields; null removes one
+	ChangedBy    string             `json:"changed_by"`
+	Reason       string             `json:"reason,omitempty"`
+}
+
+// PriorityChangeResponse is one entry of an incident's priority history
+type PriorityChangeResponse struct {
+	Priority  string    `json:"priority"` // "auto" when reset to follow the risk level
+	Previous  string    `json:"previous"`
+	ChangedBy string    `json:"changed_by"`
+	Reason    string    `json:"reason,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// incidentPriority returns the manual priority of an incident, else its template's
+// default priority, else the one following from its calendar-adjusted risk level
+func (h *Handler) incidentPriority(incident domain.Incident) domain.Priority {
+	if incident.PriorityOverride != "" {
+		return incident.PriorityOverride
+	}
+	if incident.DefaultPriority != "" {
+		return incident.DefaultPriority
+	}
+	return domain.PriorityForRisk(h.calculateRiskLevel(incident))
+}
+
+// handleIncidentPatch overrides or resets the priority of an incident and changes its
+// severity, tags and custom fields, then returns the updated incident detail
+func (h *Handler) handleIncidentPatch(w http.ResponseWriter, r *http.Request) {
+	var req IncidentPatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	changesMetadata := req.Severity != nil || req.Tags != nil || req.CustomFields != nil
+	if req.Priority == nil && !changesMetadata {
+		h.writeError(w, http.StatusBadRequest, "priority, severity, tags or custom_fields is required")
+		return
+	}
+	changedBy := strings.TrimSpace(req.ChangedBy)
+	if changedBy == "" {
+		h.writeError(w, http.StatusBadRequest, "changed_by is required")
+		return
+	}
+	auditActor(r, changedBy)
+
+	priorityStore, ok := h.repo.(ports.PriorityStore)
+	if req.Priority != nil && !ok {
+		h.writeError(w, http.StatusNotFound, "Priority overrides not supported by the repository")
+		return
+	}
+	metadataStore, ok := h.repo.(ports.IncidentMetadataStore)
+	if changesMetadata && !ok {
+		h.writeError(w, http.StatusNotFound, "Incident metadata not supported by the repository")
+		return
+	}
+
+	var priority domain.Priority
+	if req.Priority != nil && !strings.EqualFold(strings.TrimSpace(*req.Priority), "auto") {
+		parsed, err := domain.ParsePriority(*req.Priority)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		priority = parsed
+	}
+
+	ctx := r.Context()
+	incident, err := h.findIncident(ctx, extractIncidentID(r.URL.Path))
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+	if incident == nil {
+		h.writeError(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+
+	var metadata domain.IncidentMetadata
+	if changesMetadata {
+		var invalid string
+		if metadata, invalid = patchIncidentMetadata(incident.Metadata(), req); invalid != "" {
+			h.writeError(w, http.StatusBadRequest, invalid)
+			return
+		}
+	}
+
+	before := h.incidentPatchFields(*incident)
+	if req.Priority != nil && priority != incident.PriorityOverride {
+		change := domain.PriorityChange{
+			IncidentID: incident.ID,
+			Priority:   priority,
+			Previous:   h.incidentPriority(*incident),
+			ChangedBy:  changedBy,
+			Reason:     strings.TrimSpace(req.Reason),
+			ChangedAt:  time.Now().UTC(),
+		}
+		if err := priorityStore.SetPriority(ctx, change); err != nil {
+			h.logger.Error("Failed to set incident priority",
+				observability.String("incident_id", incident.ID), observability.Error(err))
+			h.writeError(w, http.StatusInternalServerError, "Failed to set incident priority")
+			return
+		}
+		incident.PriorityOverride = priority
+		h.logger.Info("Incident priority changed",
+			observability.String("incident_id", incident.ID),
+			observability.String("priority", string(h.incidentPriority(*incident))),
+			observability.String("changed_by", changedBy))
+	}
+	if changesMetadata {
+		metadata.UpdatedBy = changedBy
+		metadata.UpdatedAt = time.Now().UTC()
+		if err := metadataStore.SetIncidentMetadata(ctx, metadata); err != nil {
+			h.logger.Error("Failed to set incident metadata",
+				observability.String("incident_id", incident.ID), observability.Error(err))
+			h.writeError(w, http.StatusInternalServerError, "Failed to set incident metadata")
+			return
+		}
+		incident.SetMetadata(metadata)
+	}
+	auditChange(r, before, h.incidentPatchFields(*incident))
+
+	h.writeJSON(w, http.StatusOK, h.incidentDetail(ctx, incident))
+}
+
+// incidentPatchFields returns the fields of an incident a PATCH changes, for the audit log
+func (h *Handler) incidentPatchFields(incident domain.Incident) map[string]any {
+	return map[string]any{
+		"priority":      string(h.incidentPriority(incident)),
+		"severity":      incident.Severity,
+		"tags":          incident.Tags,
+		"custom_fields": incident.CustomFields,
+	}
+}
+
+// priorityHistory returns the priority changes of an incident, or nil if the repository
+// doesn't keep them
+func (h *Handler) priorityHistory(ctx context.Context, incidentID string) []PriorityChangeResponse {
+	store, ok := h.repo.(ports.PriorityStore)
+	if !ok {
+		return nil
+	}
+	changes, err := store.GetPriorityChanges(ctx, incidentID)
+	if err != nil {
+		h.logger.Warn("Failed to get priority changes",
+			observability.String("incident_id", incidentID), observability.Error(err))
+		return nil
+	}
+
+	var history []PriorityChangeResponse
+	for _, change := range changes {
+		priority := string(change.Priority)
+		if priority == "" {
+			priority = "auto"
+		}
+		history = append(history, PriorityChangeResponse{
+			Priority:  priority,
+			Previous:  string(change.Previous),
+			ChangedBy: change.ChangedBy,
+			Reason:    change.Reason,
+			ChangedAt: change.ChangedAt,
+		})
+	}
+	return history
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"incident-teller/internal/observability"
+	"incident-teller/internal/ports"
+	"incident-teller/internal/services"
+)
+
+// ProblemResponse is an underlying problem that caused several incidents
+type ProblemResponse struct {
+	ID          string    `json:"id"`    // ID of the first incident
+	Title       string    `json:"title"` // Title of the first incident
+	Occurrences int       `json:"occurrences"`
+	IncidentIDs []string  `json:"incident_ids"` // Oldest first
+	Hosts       []string  `json:"hosts"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	Active      bool      `json:"active"` // The latest occurrence is not resolved
+}
+
+// ProblemListResponse lists the recurring problems, most recently seen first
+type ProblemListResponse struct {
+	Problems []ProblemResponse `json:"problems"`
+	Total    int               `json:"total"`
+}
+
+// handleProblems lists the problems that caused more than one incident
+func (h *Handler) handleProblems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	store, ok := h.repo.(ports.ProblemStore)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, "Recurring problems not supported by the repository")
+		return
+	}
+
+	ctx := r.Context()
+	links, err := store.GetProblemLinks(ctx, "")
+	if err != nil {
+		h.logger.Error("Failed to get problem links", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get problems")
+		return
+	}
+	incidents, err := h.repo.GetIncidents(ctx)
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+
+	problems := services.GroupProblems(links, incidents)
+	response := ProblemListResponse{Problems: make([]ProblemResponse, 0, len(problems)), Total: len(problems)}
+	for _, problem := range problems {
+		response.Problems = append(response.Problems, ProblemResponse{
+			ID:          problem.ID,
+			Title:       problem.Title,
+			Occurrences: problem.Occurrences,
+			IncidentIDs: problem.IncidentIDs,
+			Hosts:       nonNilStrings(problem.Hosts),
+			FirstSeen:   problem.FirstSeen,
+			LastSeen:    problem.LastSeen,
+			Active:      problem.Active,
+		})
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// incidentProblemID returns the problem an incident was linked to as an occurrence, empty
+// if it wasn't
+func (h *Handler) incidentProblemID(ctx context.Context, incidentID string) string {
+	store, ok := h.repo.(ports.ProblemStore)
+	if !ok {
+		return ""
+	}
+	links, err := store.GetProblemLinks(ctx, "")
+	if err != nil {
+		h.logger.Warn("Failed to get problem links",
+			observability.String("incident_id", incidentID), observability.Error(err))
+		return ""
+	}
+	for _, link := range links {
+		if link.IncidentID == incidentID {
+			return link.ProblemID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"incident-teller/internal/services"
+)
+
+// PropagationPatternResponse describes one learned propagation pattern
+type PropagationPatternResponse struct {
+	Host          string    `json:"host,omitempty"`
+	Service       string    `json:"service,omitempty"`
+	From          string    `json:"from"`
+	To            string    `json:"to"`
+	Probability   float64   `json:"probability"`
+	WindowSeconds float64   `json:"window_seconds"`
+	Observations  int       `json:"observations"`
+	Description   string    `json:"description"`
+	LearnedAt     time.Time `json:"learned_at"`
+}
+
+// SetPropagationLearner enables the learned propagation patterns endpoint and their use
+// in local analysis
+func (h *Handler) SetPropagationLearner(learner *services.PropagationLearner) {
+	h.learner = learner
+}
+
+// handlePropagationPatterns lists learned propagation patterns, optionally for one host
+// (?host=) or service (?service=)
+func (h *Handler) handlePropagationPatterns(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.learner == nil {
+		h.writeError(w, http.StatusNotFound, "Propagation learning not enabled")
+		return
+	}
+
+	host := r.URL.Query().Get("host")
+	service := r.URL.Query().Get("service")
+
+	patterns := []PropagationPatternResponse{}
+	for _, p := range h.learner.Patterns() {
+		if (host != "" && p.Host != host) || (service != "" && p.Service != service) {
+			continue
+		}
+		patterns = append(patterns, PropagationPatternResponse{
+			Host:          p.Host,
+			Service:       p.Service,
+			From:          string(p.From),
+			To:            string(p.To),
+			Probability:   p.Probability,
+			WindowSeconds: p.Window.Seconds(),
+			Observations:  p.Observations,
+			Description:   p.String(),
+			LearnedAt:     p.LearnedAt,
+		})
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"patterns": patterns,
+		"count":    len(patterns),
+	})
+}
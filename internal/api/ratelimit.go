@@ -0,0 +1,189 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Buckets idle this long are dropped
+const rateLimitIdleTTL = 10 * time.Minute
+
+// RateLimiter keeps a token bucket per client IP address and per bearer token (or ?token=
+// query parameter). A request needs a token from both buckets, so rotating tokens from one
+// address doesn't get around the limit.
+type RateLimiter struct {
+	rate       float64 // Tokens added per second
+	burst      float64
+	trustProxy bool // Take the client IP from the last X-Forwarded-For entry / X-Real-IP
+	mu         sync.Mutex
+	buckets    map[string]*tokenBucket
+	lastSweep  time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter allows each client requestsPerSecond requests on average and bursts of
+// up to burst requests
+func NewRateLimiter(requestsPerSecond float64, burst int, trustProxy bool) *RateLimiter {
+	if burst < 1 {
+		burst = int(math.Max(1, math.Ceil(requestsPerSecond)))
+	}
+	return &RateLimiter{
+		rate:       requestsPerSecond,
+		burst:      float64(burst),
+		trustProxy: trustProxy,
+		buckets:    make(map[string]*tokenBucket),
+	}
+}
+
+// Allow takes a token from the client's bucket. When the bucket is empty it returns false
+// and how long until the next token is available.
+func (l *RateLimiter) Allow(client string) (bool, time.Duration) {
+	return l.AllowAll([]string{client})
+}
+
+// AllowAll takes a token from each of the clients' buckets if every one of them has one.
+// Otherwise none is charged, and it returns false and how long until all of them have a
+// token again.
+func (l *RateLimiter) AllowAll(clients []string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > rateLimitIdleTTL {
+		for key, bucket := range l.buckets {
+			if now.Sub(bucket.last) > rateLimitIdleTTL {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	allowed := true
+	var wait time.Duration
+	buckets := make([]*tokenBucket, len(clients))
+	fresh := make(map[string]*tokenBucket)
+	for i, client := range clients {
+		bucket, ok := l.buckets[client]
+		if !ok {
+			// Kept only if the request is allowed, so rejected requests carrying made-up
+			// tokens don't grow the map
+			bucket = &tokenBucket{tokens: l.burst, last: now}
+			fresh[client] = bucket
+		}
+		bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+		bucket.last = now
+		buckets[i] = bucket
+
+		if bucket.tokens < 1 {
+			allowed = false
+			wait = max(wait, time.Duration((1-bucket.tokens)/l.rate*float64(time.Second)))
+		}
+	}
+	if !allowed {
+		return false, wait
+	}
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+	for client, bucket := range fresh {
+		l.buckets[client] = bucket
+	}
+	return true, 0
+}
+
+// clientKeys returns the buckets a request draws from. Tokens are hashed so they aren't
+// kept in memory in the clear.
+func (l *RateLimiter) clientKeys(r *http.Request) []string {
+	keys := []string{"ip:" + l.clientIP(r)}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	if key := tokenKey(token); key != "" {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// tokenKey returns the bucket of a bearer token, or "" if there is none
+func tokenKey(token string) string {
+	token = strings.TrimPrefix(token, "Bearer ")
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return "token:" + hex.EncodeToString(sum[:8])
+}
+
+// allowRequest takes a token from every bucket of the request, or from none of them
+func (l *RateLimiter) allowRequest(r *http.Request) (bool, time.Duration) {
+	return l.AllowAll(l.clientKeys(r))
+}
+
+// clientIP returns the address of the request's client. Behind a trusted proxy that is the
+// last X-Forwarded-For entry, the one the proxy appended: earlier entries come from the
+// client and can be anything.
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	if l.trustProxy {
+		if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+			forwarded := values[len(values)-1]
+			if client := strings.TrimSpace(forwarded[strings.LastIndex(forwarded, ",")+1:]); client != "" {
+				return client
+			}
+		}
+		if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+			return realIP
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// SetRateLimit limits requests per client; nil disables rate limiting
+func (h *Handler) SetRateLimit(limiter *RateLimiter) {
+	h.rateLimiter = limiter
+}
+
+// SetMaxBodyBytes rejects request bodies larger than maxBytes; 0 disables the limit
+func (h *Handler) SetMaxBodyBytes(maxBytes int64) {
+	h.maxBodyBytes = maxBytes
+}
+
+// withRateLimit is a middleware that enforces the rate and request body limits
+func (h *Handler) withRateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.rateLimiter != nil && r.Method != http.MethodOptions {
+			if ok, wait := h.rateLimiter.allowRequest(r); !ok {
+				seconds := int(math.Ceil(wait.Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+				h.writeError(w, http.StatusTooManyRequests, "Rate limit exceeded")
+				return
+			}
+		}
+
+		if h.maxBodyBytes > 0 && r.Body != nil {
+			if r.ContentLength > h.maxBodyBytes {
+				h.writeError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, h.maxBodyBytes)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
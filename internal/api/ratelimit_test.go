@@ -0,0 +1,158 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// limitedRequest sends a GET from the client address with an optional bearer token
+func limitedRequest(routes http.Handler, method, addr, token string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/api/incidents", nil)
+	r.RemoteAddr = addr + ":40000"
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestRateLimiter_AllowAllChargesNoneWhenOneIsEmpty(t *testing.T) {
+	limiter := NewRateLimiter(0.001, 1, false)
+	if ok, _ := limiter.Allow("a"); !ok {
+		t.Fatal("expected the first token of a")
+	}
+
+	ok, wait := limiter.AllowAll([]string{"a", "b"})
+	if ok || wait <= 0 {
+		t.Fatalf("expected a to block the request with a wait, got %v %s", ok, wait)
+	}
+	if ok, _ := limiter.Allow("b"); !ok {
+		t.Error("expected b not to be charged for the rejected request")
+	}
+}
+
+func TestRateLimiter_RejectedRequestsKeepNoBuckets(t *testing.T) {
+	limiter := NewRateLimiter(0.001, 1, false)
+	limiter.Allow("ip:10.0.0.1")
+
+	// Random tokens from an address that is out of tokens aren't remembered
+	for i := 0; i < 100; i++ {
+		if ok, _ := limiter.AllowAll([]string{"ip:10.0.0.1", "token:" + strconv.Itoa(i)}); ok {
+			t.Fatalf("request %d: expected the address to be limited", i)
+		}
+	}
+	if n := len(limiter.buckets); n != 1 {
+		t.Errorf("expected only the address's bucket, got %d buckets", n)
+	}
+}
+
+func TestRateLimiter_ClientIPBehindProxy(t *testing.T) {
+	tests := []struct {
+		name       string
+		trustProxy bool
+		forwarded  []string
+		want       string
+	}{
+		{"untrusted", false, []string{"203.0.113.9"}, "10.0.0.1"},
+		{"appended by the proxy", true, []string{"198.51.100.7, 203.0.113.9"}, "203.0.113.9"},
+		{"last header", true, []string{"198.51.100.7", "192.0.2.4, 203.0.113.9"}, "203.0.113.9"},
+		{"no header", true, nil, "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/incidents", nil)
+			r.RemoteAddr = "10.0.0.1:40000"
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if got := NewRateLimiter(1, 1, tt.trustProxy).clientIP(r); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRateLimit_RetryAfter(t *testing.T) {
+	h := newTestHandler(t)
+	h.SetRateLimit(NewRateLimiter(0.5, 2, false))
+	routes := h.SetupRoutes()
+
+	for i := 0; i < 2; i++ {
+		if rec := limitedRequest(routes, http.MethodGet, "10.0.0.1", ""); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200 within the burst, got %d", i, rec.Code)
+		}
+	}
+	rec := limitedRequest(routes, http.MethodGet, "10.0.0.1", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after the burst, got %d", rec.Code)
+	}
+	if retry, err := strconv.Atoi(rec.Header().Get("Retry-After")); err != nil || retry < 1 || retry > 2 {
+		t.Errorf("expected Retry-After of 1-2 seconds at 0.5 requests/s, got %q", rec.Header().Get("Retry-After"))
+	}
+
+	// Other clients and preflights aren't affected
+	if rec := limitedRequest(routes, http.MethodGet, "10.0.0.2", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected another client to pass, got %d", rec.Code)
+	}
+	if rec := limitedRequest(routes, http.MethodOptions, "10.0.0.1", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected preflights to skip the rate limit, got %d", rec.Code)
+	}
+}
+
+func TestRateLimit_BucketsChargedTogether(t *testing.T) {
+	h := newTestHandler(t)
+	h.SetRateLimit(NewRateLimiter(0.001, 2, false))
+	routes := h.SetupRoutes()
+
+	// Rotating tokens from one address doesn't get around the address's bucket
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		if rec := limitedRequest(routes, http.MethodGet, "10.0.0.1", "token-"+strconv.Itoa(i)); rec.Code != want {
+			t.Errorf("rotated token %d: expected %d, got %d", i, want, rec.Code)
+		}
+	}
+
+	// A token used up elsewhere doesn't drain the bucket of the address it's rejected from
+	for i := 0; i < 2; i++ {
+		limitedRequest(routes, http.MethodGet, "10.0.0.2", "shared")
+	}
+	if rec := limitedRequest(routes, http.MethodGet, "10.0.0.3", "shared"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the used up token to be rejected, got %d", rec.Code)
+	}
+	for i := 0; i < 2; i++ {
+		if rec := limitedRequest(routes, http.MethodGet, "10.0.0.3", ""); rec.Code != http.StatusOK {
+			t.Errorf("request %d from 10.0.0.3: expected its full burst, got %d", i, rec.Code)
+		}
+	}
+}
+
+func TestMaxBodyBytes(t *testing.T) {
+	h := newTestHandler(t, domain.Incident{ID: "inc-a", StartedAt: time.Now()})
+	h.SetMaxBodyBytes(64)
+	routes := h.SetupRoutes()
+	large := `{"state": "triaged", "changed_by": "alice", "note": "` + strings.Repeat("x", 100) + `"}`
+
+	// A declared length over the limit is rejected before reading
+	if rec := serve(routes, http.MethodPost, "/api/incidents/inc-a/state", large); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for a declared length over the limit, got %d", rec.Code)
+	}
+
+	// A body without a declared length is cut off while reading
+	r := httptest.NewRequest(http.MethodPost, "/api/incidents/inc-a/state", strings.NewReader(large))
+	r.ContentLength = -1
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, r)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for a streamed body over the limit, got %d", rec.Code)
+	}
+
+	if rec := serve(routes, http.MethodPost, "/api/incidents/inc-a/state", `{"state": "triaged", "changed_by": "alice"}`); rec.Code != http.StatusOK {
+		t.Errorf("expected a body within the limit to pass, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+)
+
+// readOnlySafePaths lists POST endpoints that only compute results and never mutate state
+var readOnlySafePaths = map[string]bool{
+	"/api/analyze":             true,
+	"/api/graphql":             true, // The schema has no mutations
+	"/api/slack/commands":      true, // Only "ack" mutates, and it checks read-only mode itself
+	"/api/admin/reload":        true, // Reloads settings, not data
+	"/api/admin/faults":        true, // Fault injection is in memory only
+	"/api/redaction/restore":   true,
+	"/api/grafana/search":      true, // The Grafana data source only reads
+	"/api/grafana/query":       true,
+	"/api/grafana/annotations": true,
+}
+
+// SetReadOnly enables snapshot mode, rejecting every request that would mutate state
+func (h *Handler) SetReadOnly(readOnly bool) {
+	h.readOnly = readOnly
+}
+
+// withReadOnly is a middleware that blocks mutating requests in read-only mode
+func (h *Handler) withReadOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.readOnly {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("X-Read-Only", "true")
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Share links are signed, not stored, so incidents can be shared from a snapshot too
+		if readOnlySafePaths[r.URL.Path] || strings.HasPrefix(r.URL.Path, "/api/incidents/") && strings.HasSuffix(r.URL.Path, "/share") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h.writeError(w, http.StatusForbidden, "Server is running in read-only mode")
+	})
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"incident-teller/internal/redact"
+)
+
+// RedactionRestoreRequest is the body of POST /api/redaction/restore
+type RedactionRestoreRequest struct {
+	Text string `json:"text"`
+}
+
+// RedactionRestoreResponse is redacted text with the real values put back
+type RedactionRestoreResponse struct {
+	Text     string `json:"text"`
+	Mappings int    `json:"mappings"` // Values the redactor has replaced with tokens so far
+}
+
+// SetRedactor enables POST /api/redaction/restore, which turns the tokens in text sent to
+// OpenAI or posted to notification channels back into hostnames, IPs and label values
+func (h *Handler) SetRedactor(redactor *redact.Redactor) {
+	h.redactor = redactor
+}
+
+// handleRedactionRestore replaces the redaction tokens in text with the values they stand for
+func (h *Handler) handleRedactionRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.redactor == nil {
+		h.writeError(w, http.StatusNotFound, "Redaction not enabled")
+		return
+	}
+
+	var req RedactionRestoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, RedactionRestoreResponse{
+		Text:     h.redactor.Restore(req.Text),
+		Mappings: h.redactor.Mappings(),
+	})
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+)
+
+// ReliabilityRepository is implemented by repositories that can aggregate reliability metrics
+type ReliabilityRepository interface {
+	ReliabilityStats(ctx context.Context, from, to time.Time, filter domain.LabelFilter) (domain.ReliabilityStats, error)
+}
+
+// AcknowledgementRepository is implemented by repositories that persist acknowledgements,
+// which MTTA is computed from
+type AcknowledgementRepository interface {
+	SaveAcknowledgement(ctx context.Context, incidentID, by string, at time.Time) error
+}
+
+// FrequencyResponse is the number of incidents for one value of a dimension
+type FrequencyResponse struct {
+	Key       string `json:"key"`
+	Incidents int    `json:"incidents"`
+}
+
+// RecurringIncidentResponse is an incident title seen repeatedly in the period
+type RecurringIncidentResponse struct {
+	Title       string  `json:"title"`
+	Incidents   int     `json:"incidents"`
+	MTTRSeconds float64 `json:"mttr_seconds"`
+}
+
+// ReliabilityPeriodResponse summarizes one period
+type ReliabilityPeriodResponse struct {
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	Incidents    int       `json:"incidents"`
+	Resolved     int       `json:"resolved"`
+	Acknowledged int       `json:"acknowledged"`
+	MTTRSeconds  float64   `json:"mttr_seconds"`
+	MTTR         string    `json:"mttr"`
+	MTTASeconds  float64   `json:"mtta_seconds"`
+	MTTA         string    `json:"mtta"`
+}
+
+// ReliabilityTrendResponse compares the period with the one before it. Percentages are
+// omitted when the previous value is zero.
+type ReliabilityTrendResponse struct {
+	Incidents        int      `json:"incidents"`
+	IncidentsPercent *float64 `json:"incidents_percent,omitempty"`
+	MTTRSeconds      float64  `json:"mttr_seconds"`
+	MTTRPercent      *float64 `json:"mttr_percent,omitempty"`
+	MTTASeconds      float64  `json:"mtta_seconds"`
+	MTTAPercent      *float64 `json:"mtta_percent,omitempty"`
+}
+
+// ReliabilityAnalyticsResponse is the response of GET /api/analytics
+type ReliabilityAnalyticsResponse struct {
+	ReliabilityPeriodResponse
+	ByHost         []FrequencyResponse         `json:"by_host"`
+	ByResourceType []FrequencyResponse         `json:"by_resource_type"`
+	ByState        []FrequencyResponse         `json:"by_state"` // Every lifecycle state, in lifecycle order
+	ByDayOfWeek    []FrequencyResponse         `json:"by_day_of_week"`
+	Recurring      []RecurringIncidentResponse `json:"recurring"`
+	Previous       ReliabilityPeriodResponse   `json:"previous"`
+	Trend          ReliabilityTrendResponse    `json:"trend"`
+}
+
+// handleReliabilityAnalytics returns MTTR, MTTA, incident frequency, recurring incidents
+// and the change against the previous period (?window=30d, default 30 days)
+func (h *Handler) handleReliabilityAnalytics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	window := 30 * 24 * time.Hour
+	if ws := r.URL.Query().Get("window"); ws != "" {
+		parsed, err := parsePeriod(ws)
+		if err != nil || parsed <= 0 {
+			h.writeError(w, http.StatusBadRequest, "Invalid window, use e.g. 7d or 168h")
+			return
+		}
+		window = parsed
+	}
+	envFilter, invalid := h.parseEnvironmentFilter(r)
+	if invalid != "" {
+		h.writeError(w, http.StatusBadRequest, invalid)
+		return
+	}
+
+	reliabilityRepo, ok := h.repo.(ReliabilityRepository)
+	if !ok {
+		h.writeError(w, http.StatusNotImplemented, "Repository does not support reliability analytics")
+		return
+	}
+
+	to := time.Now()
+	from := to.Add(-window)
+
+	current, err := reliabilityRepo.ReliabilityStats(r.Context(), from, to, envFilter)
+	if err != nil {
+		h.logger.Error("Failed to aggregate reliability stats", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to compute analytics")
+		return
+	}
+	previous, err := reliabilityRepo.ReliabilityStats(r.Context(), from.Add(-window), from, envFilter)
+	if err != nil {
+		h.logger.Error("Failed to aggregate reliability stats", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to compute analytics")
+		return
+	}
+
+	response := ReliabilityAnalyticsResponse{
+		ReliabilityPeriodResponse: convertReliabilityPeriod(current),
+		ByHost:                    convertFrequencies(current.ByHost),
+		ByResourceType:            convertFrequencies(current.ByResourceType),
+		ByState:                   convertFrequencies(current.ByState),
+		ByDayOfWeek:               make([]FrequencyResponse, len(current.ByWeekday)),
+		Recurring:                 make([]RecurringIncidentResponse, len(current.Recurring)),
+		Previous:                  convertReliabilityPeriod(previous),
+		Trend: ReliabilityTrendResponse{
+			Incidents:        current.Incidents - previous.Incidents,
+			IncidentsPercent: percentChange(float64(current.Incidents), float64(previous.Incidents)),
+			MTTRSeconds:      (current.MTTR - previous.MTTR).Seconds(),
+			MTTRPercent:      percentChange(current.MTTR.Seconds(), previous.MTTR.Seconds()),
+			MTTASeconds:      (current.MTTA - previous.MTTA).Seconds(),
+			MTTAPercent:      percentChange(current.MTTA.Seconds(), previous.MTTA.Seconds()),
+		},
+	}
+	for day, count := range current.ByWeekday {
+		response.ByDayOfWeek[day] = FrequencyResponse{Key: time.Weekday(day).String(), Incidents: count}
+	}
+	for i, recurring := range current.Recurring {
+		response.Recurring[i] = RecurringIncidentResponse{
+			Title:       recurring.Title,
+			Incidents:   recurring.Incidents,
+			MTTRSeconds: recurring.MTTR.Seconds(),
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+func convertReliabilityPeriod(stats domain.ReliabilityStats) ReliabilityPeriodResponse {
+	return ReliabilityPeriodResponse{
+		From:         stats.From,
+		To:           stats.To,
+		Incidents:    stats.Incidents,
+		Resolved:     stats.Resolved,
+		Acknowledged: stats.Acknowledged,
+		MTTRSeconds:  stats.MTTR.Seconds(),
+		MTTR:         stats.MTTR.Round(time.Second).String(),
+		MTTASeconds:  stats.MTTA.Seconds(),
+		MTTA:         stats.MTTA.Round(time.Second).String(),
+	}
+}
+
+func convertFrequencies(counts []domain.FrequencyCount) []FrequencyResponse {
+	result := make([]FrequencyResponse, len(counts))
+	for i, count := range counts {
+		result[i] = FrequencyResponse{Key: count.Key, Incidents: count.Incidents}
+	}
+	return result
+}
+
+// percentChange returns the relative change from previous to current in percent, or nil
+// when there is no previous value to compare with
+func percentChange(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := (current - previous) / previous * 100
+	return &change
+}
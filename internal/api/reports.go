@@ -0,0 +1,201 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/services"
+)
+
+// IncidentNoiseResponse represents alert-noise statistics for a resolved incident
+type IncidentNoiseResponse struct {
+	IncidentID  string  `json:"incident_id"`
+	Title       string  `json:"title"`
+	TotalAlerts int     `json:"total_alerts"`
+	Informative int     `json:"informative"`
+	Duplicate   int     `json:"duplicate"`
+	Flapping    int     `json:"flapping"`
+	Churn       int     `json:"churn"`
+	Efficiency  float64 `json:"efficiency"`
+}
+
+// SourceNoiseResponse represents aggregated alert-noise statistics for an alert source
+type SourceNoiseResponse struct {
+	Source      string  `json:"source"`
+	Incidents   int     `json:"incidents"`
+	TotalAlerts int     `json:"total_alerts"`
+	Informative int     `json:"informative"`
+	Duplicate   int     `json:"duplicate"`
+	Flapping    int     `json:"flapping"`
+	Churn       int     `json:"churn"`
+	Efficiency  float64 `json:"efficiency"`
+}
+
+// NoiseReportResponse represents the alerting-noise cost report
+type NoiseReportResponse struct {
+	Incidents         []IncidentNoiseResponse `json:"incidents"`
+	Sources           []SourceNoiseResponse   `json:"sources"`
+	TotalAlerts       int                     `json:"total_alerts"`
+	RedundantAlerts   int                     `json:"redundant_alerts"`
+	OverallEfficiency float64                 `json:"overall_efficiency"`
+}
+
+// handleNoiseReport returns the per-incident and per-source alerting noise report
+func (h *Handler) handleNoiseReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	incidents, err := h.repo.GetIncidents(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve incidents")
+		return
+	}
+
+	report := h.noiseAnalyzer.BuildReport(incidents)
+	h.writeJSON(w, http.StatusOK, convertNoiseReportToResponse(report))
+}
+
+// NoiseGeneratorResponse represents an alert stream ranked by its noise
+type NoiseGeneratorResponse struct {
+	Host              string  `json:"host"`
+	Chart             string  `json:"chart"`
+	Name              string  `json:"name"`
+	Alerts            int     `json:"alerts"`
+	NoiseAlerts       int     `json:"noise_alerts"`
+	FlappingAlerts    int     `json:"flapping_alerts"`
+	Transitions       int     `json:"transitions"`
+	NoiseRatio        float64 `json:"noise_ratio"`
+	CurrentlyFlapping bool    `json:"currently_flapping"`
+}
+
+// WeeklyNoiseResponse represents the noisiest alert streams of one week
+type WeeklyNoiseResponse struct {
+	WeekStart  time.Time                `json:"week_start"`
+	WeekEnd    time.Time                `json:"week_end"`
+	Generators []NoiseGeneratorResponse `json:"generators"`
+}
+
+// SetFlapDetector sets the detector whose settings rank flapping alerts and whose live
+// state reports streams that are flapping now
+func (h *Handler) SetFlapDetector(detector *services.FlapDetector) {
+	h.flaps = detector
+}
+
+// handleNoisyAlerts ranks the top noise generators of each of the last weeks (ISO weeks
+// in UTC, the current one first)
+func (h *Handler) handleNoisyAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	weeks, limit := 1, 10
+	if v := r.URL.Query().Get("weeks"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > 52 {
+			h.writeError(w, http.StatusBadRequest, "weeks must be between 1 and 52")
+			return
+		}
+		weeks = parsed
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > 100 {
+			h.writeError(w, http.StatusBadRequest, "limit must be between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+
+	alerts, err := h.repo.GetAlerts(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to get alerts", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve alerts")
+		return
+	}
+	// Replaying the whole history lets flapping that started last week carry over
+	flapping := h.flaps.Replay(alerts)
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+
+	response := make([]WeeklyNoiseResponse, 0, weeks)
+	for i := 0; i < weeks; i++ {
+		start, end := weekStart.AddDate(0, 0, -7*i), weekStart.AddDate(0, 0, -7*(i-1))
+
+		var weekAlerts []domain.Alert
+		var weekFlapping []bool
+		for j, alert := range alerts {
+			if !alert.OccurredAt.Before(start) && alert.OccurredAt.Before(end) {
+				weekAlerts = append(weekAlerts, alert)
+				weekFlapping = append(weekFlapping, flapping[j])
+			}
+		}
+
+		week := WeeklyNoiseResponse{WeekStart: start, WeekEnd: end, Generators: []NoiseGeneratorResponse{}}
+		for _, g := range h.noiseAnalyzer.RankGenerators(weekAlerts, weekFlapping, limit) {
+			week.Generators = append(week.Generators, NoiseGeneratorResponse{
+				Host:              g.Host,
+				Chart:             g.Chart,
+				Name:              g.Name,
+				Alerts:            g.Alerts,
+				NoiseAlerts:       g.NoiseAlerts,
+				FlappingAlerts:    g.FlappingAlerts,
+				Transitions:       g.Transitions,
+				NoiseRatio:        g.NoiseRatio,
+				CurrentlyFlapping: h.flaps.Flapping(domain.Alert{Host: g.Host, Chart: g.Chart, Name: g.Name}),
+			})
+		}
+		response = append(response, week)
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"weeks": response,
+	})
+}
+
+// convertNoiseReportToResponse converts a noise report to API response format
+func convertNoiseReportToResponse(report services.NoiseReport) NoiseReportResponse {
+	response := NoiseReportResponse{
+		Incidents:         make([]IncidentNoiseResponse, len(report.Incidents)),
+		Sources:           make([]SourceNoiseResponse, len(report.Sources)),
+		TotalAlerts:       report.TotalAlerts,
+		RedundantAlerts:   report.RedundantAlerts,
+		OverallEfficiency: report.OverallEfficiency,
+	}
+
+	for i, inc := range report.Incidents {
+		response.Incidents[i] = IncidentNoiseResponse{
+			IncidentID:  inc.IncidentID,
+			Title:       inc.Title,
+			TotalAlerts: inc.TotalAlerts,
+			Informative: inc.Informative,
+			Duplicate:   inc.Duplicate,
+			Flapping:    inc.Flapping,
+			Churn:       inc.Churn,
+			Efficiency:  inc.Efficiency,
+		}
+	}
+
+	for i, src := range report.Sources {
+		response.Sources[i] = SourceNoiseResponse{
+			Source:      src.Source,
+			Incidents:   src.Incidents,
+			TotalAlerts: src.TotalAlerts,
+			Informative: src.Informative,
+			Duplicate:   src.Duplicate,
+			Flapping:    src.Flapping,
+			Churn:       src.Churn,
+			Efficiency:  src.Efficiency,
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/ports"
+	"incident-teller/internal/services"
+)
+
+// RootCauseOverrideRequest pins an alert of the incident as its root cause. SetBy is
+// recorded with the override.
+type RootCauseOverrideRequest struct {
+	AlertID     string `json:"alert_id"`
+	Explanation string `json:"explanation,omitempty"`
+	SetBy       string `json:"set_by"`
+}
+
+// PinnedRootCauseResponse is the root cause a responder pinned on an incident
+type PinnedRootCauseResponse struct {
+	AlertID     string    `json:"alert_id"`
+	Explanation string    `json:"explanation,omitempty"`
+	SetBy       string    `json:"set_by"`
+	SetAt       time.Time `json:"set_at"`
+}
+
+// handleIncidentRootCauseOverride pins (PUT) or unpins (DELETE) the root cause of an
+// incident, then returns the updated incident detail. A pinned root cause is also given as
+// feedback on the stored model predictions.
+func (h *Handler) handleIncidentRootCauseOverride(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	store, ok := h.repo.(ports.RootCauseOverrideStore)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, "Root cause overrides not supported by the repository")
+		return
+	}
+
+	ctx := r.Context()
+	incident, err := h.findIncident(ctx, r.PathValue("id"))
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+	if incident == nil {
+		h.writeError(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+	before := rootCauseOverrideFields(incident.RootCauseOverride)
+
+	if r.Method == http.MethodDelete {
+		if err := store.DeleteRootCauseOverride(ctx, incident.ID); err != nil {
+			h.logger.Error("Failed to remove root cause override",
+				observability.String("incident_id", incident.ID), observability.Error(err))
+			h.writeError(w, http.StatusInternalServerError, "Failed to remove root cause override")
+			return
+		}
+		incident.RootCauseOverride = nil
+		auditChange(r, before, rootCauseOverrideFields(nil))
+		h.enqueueAnalysis(*incident)
+		h.writeJSON(w, http.StatusOK, h.incidentDetail(ctx, incident))
+		return
+	}
+
+	var req RootCauseOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.AlertID = strings.TrimSpace(req.AlertID)
+	if req.AlertID == "" {
+		h.writeError(w, http.StatusBadRequest, "alert_id is required")
+		return
+	}
+	setBy := strings.TrimSpace(req.SetBy)
+	if setBy == "" {
+		h.writeError(w, http.StatusBadRequest, "set_by is required")
+		return
+	}
+	auditActor(r, setBy)
+	found := false
+	for _, alert := range incident.Events {
+		found = found || alert.ID == req.AlertID
+	}
+	if !found {
+		h.writeError(w, http.StatusBadRequest, "Root cause alert is not part of the incident")
+		return
+	}
+
+	override := domain.RootCauseOverride{
+		IncidentID:  incident.ID,
+		AlertID:     req.AlertID,
+		Explanation: strings.TrimSpace(req.Explanation),
+		SetBy:       setBy,
+		SetAt:       time.Now().UTC(),
+	}
+	if err := store.SetRootCauseOverride(ctx, override); err != nil {
+		h.logger.Error("Failed to set root cause override",
+			observability.String("incident_id", incident.ID), observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to set root cause override")
+		return
+	}
+	incident.RootCauseOverride = &override
+	h.metrics.IncCounter("root_cause_overrides_total", nil)
+	h.logger.Info("Incident root cause pinned",
+		observability.String("incident_id", incident.ID),
+		observability.String("alert_id", override.AlertID),
+		observability.String("set_by", setBy))
+
+	if h.evaluator != nil {
+		_, err := h.evaluator.Feedback(ctx, incident.ID, services.RootCauseFeedback{RootCauseAlertID: override.AlertID})
+		if err != nil && !errors.Is(err, services.ErrNoRootCausePredictions) {
+			h.logger.Warn("Failed to score root cause predictions against override",
+				observability.String("incident_id", incident.ID), observability.Error(err))
+		}
+	}
+	auditChange(r, before, rootCauseOverrideFields(&override))
+	h.enqueueAnalysis(*incident)
+
+	h.writeJSON(w, http.StatusOK, h.incidentDetail(ctx, incident))
+}
+
+// rootCauseOverrideFields returns the fields of an override, for the audit log
+func rootCauseOverrideFields(override *domain.RootCauseOverride) map[string]any {
+	if override == nil {
+		return map[string]any{"root_cause_alert_id": "", "root_cause_explanation": ""}
+	}
+	return map[string]any{
+		"root_cause_alert_id":    override.AlertID,
+		"root_cause_explanation": override.Explanation,
+	}
+}
+
+// pinnedRootCauseResponse converts the override of an incident, nil if it has none
+func pinnedRootCauseResponse(override *domain.RootCauseOverride) *PinnedRootCauseResponse {
+	if override == nil {
+		return nil
+	}
+	return &PinnedRootCauseResponse{
+		AlertID:     override.AlertID,
+		Explanation: override.Explanation,
+		SetBy:       override.SetBy,
+		SetAt:       override.SetAt,
+	}
+}
+
+// pinRootCause returns the root cause of an incident whose root cause was pinned: the
+// pinned alert at full confidence, with the analyzed primary cause as an alternative.
+// Without a pinned root cause the analyzed one is returned as is.
+func pinRootCause(incident domain.Incident, analyzed *RootCauseResponse) *RootCauseResponse {
+	pinned, ok := incident.PinnedRootCause()
+	if !ok {
+		return analyzed
+	}
+
+	reasoning := "Pinned as the root cause by " + incident.RootCauseOverride.SetBy
+	if incident.RootCauseOverride.Explanation != "" {
+		reasoning += ": " + incident.RootCauseOverride.Explanation
+	}
+	response := &RootCauseResponse{
+		AlertID:           pinned.ID,
+		ResourceType:      string(pinned.ResourceType),
+		Chart:             pinned.Chart,
+		Host:              pinned.Host,
+		Confidence:        1,
+		RawConfidence:     1,
+		PatternType:       "manual",
+		Reasoning:         reasoning,
+		Pinned:            true,
+		AlternativeCauses: []AlternativeCauseResponse{},
+	}
+	if analyzed == nil {
+		return response
+	}
+
+	response.ModelVersion = analyzed.ModelVersion
+	if analyzed.AlertID != "" && analyzed.AlertID != pinned.ID {
+		response.AlternativeCauses = append(response.AlternativeCauses, AlternativeCauseResponse{
+			AlertID:      analyzed.AlertID,
+			ResourceType: analyzed.ResourceType,
+			Chart:        analyzed.Chart,
+			Host:         analyzed.Host,
+			Confidence:   analyzed.Confidence,
+		})
+	}
+	for _, alt := range analyzed.AlternativeCauses {
+		if alt.AlertID != pinned.ID {
+			response.AlternativeCauses = append(response.AlternativeCauses, alt)
+		}
+	}
+	return response
+}
+
+// pinTimelineRootCause marks the first event of the pinned root cause alert as the root
+// cause of a timeline, instead of the one the timeline builder guessed
+func pinTimelineRootCause(incident domain.Incident, timeline *services.TimelineWithInsights) {
+	pinned, ok := incident.PinnedRootCause()
+	if !ok {
+		return
+	}
+	for i := range timeline.Events {
+		if event := timeline.Events[i].SourceAlert; event != nil && event.ID == pinned.ID {
+			if timeline.RootCauseEventIndex != nil {
+				timeline.Events[*timeline.RootCauseEventIndex].IsRootCause = false
+			}
+			timeline.Events[i].IsRootCause = true
+			index := i
+			timeline.RootCauseEventIndex = &index
+			return
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"incident-teller/internal/ai"
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/services"
+)
+
+// RootCausePredictionResponse is the root cause a model version predicted for an
+// incident, with the feedback on it
+type RootCausePredictionResponse struct {
+	ModelVersion string     `json:"model_version"`
+	Active       bool       `json:"active"`
+	AlertID      string     `json:"alert_id"`
+	RawScore     float64    `json:"raw_score"`
+	Confidence   float64    `json:"confidence"`
+	PredictedAt  time.Time  `json:"predicted_at"`
+	Correct      *bool      `json:"correct,omitempty"`
+	FeedbackAt   *time.Time `json:"feedback_at,omitempty"`
+}
+
+// RootCausePredictionsResponse lists the root causes predicted for an incident
+type RootCausePredictionsResponse struct {
+	IncidentID  string                        `json:"incident_id"`
+	Predictions []RootCausePredictionResponse `json:"predictions"`
+}
+
+// RootCauseFeedbackRequest is either the actual root cause alert or whether the active
+// model version's prediction was correct
+type RootCauseFeedbackRequest struct {
+	RootCauseAlertID string `json:"root_cause_alert_id,omitempty"`
+	Correct          *bool  `json:"correct,omitempty"`
+}
+
+// ModelVersionResponse is a root cause model version with its accuracy and calibration curve
+type ModelVersionResponse struct {
+	Version     string          `json:"version"`
+	Active      bool            `json:"active"`
+	Weights     ai.ModelWeights `json:"weights"`
+	Predictions int             `json:"predictions"`
+	Feedback    int             `json:"feedback"`
+	Correct     int             `json:"correct"`
+	Overrides   int             `json:"overrides"`
+	Accuracy    float64         `json:"accuracy"`
+	Calibration *ai.Calibration `json:"calibration"`
+}
+
+// SetModelEvaluator enables root cause feedback and model version reports. Analyzed
+// incidents get their root cause predicted by every model version.
+func (h *Handler) SetModelEvaluator(evaluator *services.ModelEvaluator) {
+	h.evaluator = evaluator
+}
+
+// recordRootCauses stores the root cause every model version predicts for an incident
+func (h *Handler) recordRootCauses(ctx context.Context, incident domain.Incident) {
+	if h.evaluator == nil || h.readOnly {
+		return
+	}
+	if err := h.evaluator.Record(ctx, incident); err != nil {
+		h.logger.Warn("Failed to record root cause predictions",
+			observability.String("incident_id", incident.ID), observability.Error(err))
+	}
+}
+
+// handleIncidentRootCauses lists the root causes predicted for an incident by each model
+// version
+func (h *Handler) handleIncidentRootCauses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	incident, ok := h.rootCauseIncident(w, r)
+	if !ok {
+		return
+	}
+
+	records, err := h.evaluator.Predictions(r.Context(), incident.ID)
+	if err != nil {
+		h.logger.Error("Failed to get root cause predictions", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get root cause predictions")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, h.rootCausePredictionsResponse(incident.ID, records))
+}
+
+// handleRootCauseFeedback scores the root causes predicted for an incident and
+// recalibrates the models
+func (h *Handler) handleRootCauseFeedback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	incident, ok := h.rootCauseIncident(w, r)
+	if !ok {
+		return
+	}
+
+	var req RootCauseFeedbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.RootCauseAlertID == "" && req.Correct == nil {
+		h.writeError(w, http.StatusBadRequest, "Either root_cause_alert_id or correct is required")
+		return
+	}
+	if req.RootCauseAlertID != "" {
+		found := false
+		for _, alert := range incident.Events {
+			found = found || alert.ID == req.RootCauseAlertID
+		}
+		if !found {
+			h.writeError(w, http.StatusBadRequest, "Root cause alert is not part of the incident")
+			return
+		}
+	}
+
+	records, err := h.evaluator.Feedback(r.Context(), incident.ID, services.RootCauseFeedback{
+		RootCauseAlertID: req.RootCauseAlertID,
+		Correct:          req.Correct,
+	})
+	if errors.Is(err, services.ErrNoRootCausePredictions) {
+		h.writeError(w, http.StatusNotFound, "No root cause predictions stored for this incident")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to save root cause feedback",
+			observability.String("incident_id", incident.ID), observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to save root cause feedback")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, h.rootCausePredictionsResponse(incident.ID, records))
+}
+
+// handleModelVersions lists the root cause model versions with their accuracy and
+// calibration curves
+func (h *Handler) handleModelVersions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.evaluator == nil {
+		h.writeError(w, http.StatusNotFound, "Model evaluation not enabled")
+		return
+	}
+
+	reports, err := h.evaluator.Report(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to evaluate model versions", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to evaluate model versions")
+		return
+	}
+	models := make([]ModelVersionResponse, 0, len(reports))
+	for _, report := range reports {
+		models = append(models, ModelVersionResponse(report))
+	}
+	h.writeJSON(w, http.StatusOK, models)
+}
+
+// rootCauseIncident finds the incident of a root cause request, writing the error if
+// there is none
+func (h *Handler) rootCauseIncident(w http.ResponseWriter, r *http.Request) (*domain.Incident, bool) {
+	if h.evaluator == nil {
+		h.writeError(w, http.StatusNotFound, "Model evaluation not enabled")
+		return nil, false
+	}
+	incident, err := h.findIncident(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return nil, false
+	}
+	if incident == nil {
+		h.writeError(w, http.StatusNotFound, "Incident not found")
+		return nil, false
+	}
+	return incident, true
+}
+
+func (h *Handler) rootCausePredictionsResponse(incidentID string, records []domain.RootCauseRecord) RootCausePredictionsResponse {
+	response := RootCausePredictionsResponse{IncidentID: incidentID, Predictions: []RootCausePredictionResponse{}}
+	for _, record := range records {
+		response.Predictions = append(response.Predictions, RootCausePredictionResponse{
+			ModelVersion: record.ModelVersion,
+			Active:       record.ModelVersion == h.evaluator.ActiveVersion(),
+			AlertID:      record.AlertID,
+			RawScore:     record.RawScore,
+			Confidence:   record.Confidence,
+			PredictedAt:  record.PredictedAt,
+			Correct:      record.Correct,
+			FeedbackAt:   record.FeedbackAt,
+		})
+	}
+	return response
+}
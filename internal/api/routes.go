@@ -0,0 +1,569 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"incident-teller/internal/api/openapi"
+	"incident-teller/internal/classify"
+	"incident-teller/internal/config"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/oncall"
+	"incident-teller/internal/playbook"
+	"incident-teller/internal/services"
+	"incident-teller/internal/snapshot"
+	"incident-teller/internal/statuspage"
+)
+
+// apiInfo describes the API in the OpenAPI document
+var apiInfo = openapi.Info{
+	Title:       "IncidentTeller API",
+	Version:     "1.0.0",
+	Description: "Incidents correlated from monitoring alerts, with root cause analysis, timelines and incident stories.",
+}
+
+var (
+	windowParam   = openapi.Param{Name: "window", Description: "Lookback window as a Go duration or days, e.g. 15m or 30d"}
+	envParam      = openapi.Param{Name: "environment", Description: "Only incidents of this environment, e.g. prod; needs environments enabled"}
+	tzParam       = openapi.Param{Name: "tz", Description: "Time zone of rendered times, e.g. Europe/Berlin or +05:30; else Accept-Timezone, else UTC"}
+	pageParams    = []openapi.Param{{Name: "page", Type: "integer"}, {Name: "page_size", Type: "integer", Description: "At most 100"}}
+	incidentQuery = []openapi.Param{
+		{Name: "q", Description: "Search title, host, chart and alert name"},
+		{Name: "sort", Description: "started_at, duration, risk, events or priority"},
+		{Name: "order", Description: "asc or desc"},
+		{Name: "labels", Description: `Label matchers, e.g. service="checkout",env!~"dev|staging"`},
+		{Name: "severity", Description: "Comma-separated severities, any of which matches"},
+		{Name: "tag", Description: "Comma-separated or repeated tags, all of which must be set"},
+		{Name: "field.{name}", Description: "Custom field value, e.g. field.team=payments; may be given for several fields"},
+		{Name: "state", Description: "Comma-separated lifecycle states, any of which matches, e.g. triaged,mitigating"},
+		envParam,
+	}
+	servicePeriodParams = []openapi.Param{
+		{Name: "window", Description: "Period before to, e.g. 90d (default)"},
+		{Name: "from", Description: "RFC3339 or YYYY-MM-DD; overrides window"},
+		{Name: "to", Description: "RFC3339 or YYYY-MM-DD; default now"},
+	}
+)
+
+// routes declares every route with its OpenAPI description. SetupRoutes registers exactly
+// these routes and serves the document generated from them, so a handler can't be added
+// without showing up in the spec.
+func (h *Handler) routes() []openapi.Route {
+	return []openapi.Route{
+		// Incidents
+		{Pattern: "/api/incidents/summary", Handler: h.handleIncidentsSummary, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Active/resolved counts and overall risk level", Response: IncidentSummaryResponse{}},
+		}},
+		{Pattern: "/api/incidents/export", Handler: h.handleIncidentExport, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Export incidents started in a range as CSV or JSON", Response: []IncidentExportRecord{},
+				Query: []openapi.Param{
+					{Name: "format", Description: "csv (default) or json"},
+					{Name: "from", Description: "RFC3339 or YYYY-MM-DD"},
+					{Name: "to", Description: "RFC3339 or YYYY-MM-DD"},
+				}},
+		}},
+		{Pattern: "/api/snapshots", Handler: h.handleSnapshots, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Export incidents with their alerts, root causes and timeline notes as a versioned snapshot",
+				Description: "With sanitize=true hostnames are replaced by host-1, host-2, ... and the people who changed priorities are removed, " +
+					"so the snapshot can be shared, e.g. as training data.",
+				Query: []openapi.Param{
+					{Name: "ids", Description: "Comma-separated incident IDs; all incidents if omitted"},
+					{Name: "sanitize", Type: "boolean"},
+				},
+				Response: snapshot.Snapshot{}},
+			{Method: http.MethodPost, Summary: "Import a snapshot, e.g. one exported from another database backend",
+				Description: "Incidents, alerts, root causes and timelines are replaced, so importing a snapshot again changes nothing.",
+				Request:     snapshot.Snapshot{}, Response: snapshot.ImportResult{}},
+		}},
+		{Pattern: "/api/incidents/compare", Handler: h.handleIncidentCompare, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Diff two incidents to tell whether a repeat incident is the same problem again",
+				Description: "Compares the affected hosts, resources and charts, the aligned timelines, root causes, blast radii " +
+					"and fix playbooks. The verdict is same_problem when both root causes are alerts of the same resource type and chart " +
+					"and the same fix playbook applies, related when one of these holds or most charts are shared, and different otherwise.",
+				Query:    []openapi.Param{{Name: "a", Description: "Incident ID", Required: true}, {Name: "b", Description: "Incident ID", Required: true}},
+				Response: IncidentComparisonResponse{}},
+		}},
+		{Pattern: "/api/incidents", Handler: h.handleIncidents, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Paginated list of incidents", Query: append(pageParams, incidentQuery...), Response: IncidentListResponse{}},
+		}},
+		{Pattern: "/api/incidents/", Path: "/api/incidents/{id}", Handler: h.handleIncidentDetail, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Incident details with AI root cause and blast radius", Response: IncidentDetailResponse{}},
+			{Method: http.MethodPatch, Summary: "Change the priority, severity, tags or custom fields of an incident",
+				Description: "The priority is P1-P4, or auto to follow the risk level again; every change is kept in priority_history with changed_by and reason. " +
+					"Tags replace the current ones; custom_fields sets the given fields, null removes one. Severity, tags and custom fields are kept when the incident is correlated again.",
+				Request: IncidentPatchRequest{}, Response: IncidentDetailResponse{}},
+		}},
+		{Pattern: "/api/incidents/{id}/analysis/status", Handler: h.handleIncidentAnalysisStatus, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "State of an incident's background AI analysis, with the result when finished",
+				Description: "Incidents are analyzed in the background when created or updated; an incident without an " +
+					"analysis is queued. Finished analyses are also sent as \"analysis\" events on /api/events.",
+				Response: AnalysisStatusResponse{}},
+		}},
+		{Pattern: "/api/incidents/{id}/root-causes", Handler: h.handleIncidentRootCauses, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Root causes predicted for an incident by each model version, with feedback",
+				Response: RootCausePredictionsResponse{}},
+		}},
+		{Pattern: "/api/incidents/{id}/root-causes/feedback", Handler: h.handleRootCauseFeedback, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Tell whether the predicted root cause was correct, or which alert was the root cause",
+				Description: "With root_cause_alert_id every model version's prediction is scored; with correct, the active " +
+					"version's prediction and those of versions that predicted the same alert. Confidences are recalibrated.",
+				Request: RootCauseFeedbackRequest{}, Response: RootCausePredictionsResponse{}},
+		}},
+		{Pattern: "/api/incidents/{id}/root-cause", Handler: h.handleIncidentRootCauseOverride, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodPut, Summary: "Pin an alert of the incident as its root cause, with an explanation",
+				Description: "Reports, digests, tickets, notifications and stories use the pinned root cause instead of the predicted one; " +
+					"it survives correlation and is given as feedback on the stored model predictions, so overrides count in model accuracy.",
+				Request: RootCauseOverrideRequest{}, Response: IncidentDetailResponse{}},
+			{Method: http.MethodDelete, Summary: "Unpin the root cause, following the prediction again", Response: IncidentDetailResponse{}},
+		}},
+		{Pattern: "/api/problems", Handler: h.handleProblems, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Recurring problems: incidents linked as occurrences of the same underlying problem",
+				Description: "A new incident is linked to the most similar incident resolved before it started when the Jaccard similarity " +
+					"of their host, resource type and alert name combinations reaches incident.recurrence_threshold. " +
+					"Problems are listed with their occurrences, most recently seen first.",
+				Response: ProblemListResponse{}},
+		}},
+		{Pattern: "/api/incidents/{id}/state", Handler: h.handleIncidentState, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Move an incident to another lifecycle state",
+				Description: "Incidents go detected, triaged, mitigating, monitoring, resolved, postmortem; steps may be skipped going forward, " +
+					"and monitoring or resolved incidents may go back to mitigating. Other transitions return 409, as does one that " +
+					"lost to a concurrent transition of the incident. " +
+					"Every transition is kept in state_history with changed_by and note; until the first one the state is " +
+					"detected, or resolved once the incident's alerts cleared.",
+				Request: IncidentStateRequest{}, Response: IncidentDetailResponse{}},
+		}},
+		{Pattern: "/api/incidents/{id}/blast-radius", Handler: h.handleIncidentBlastRadius, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Blast radius of an incident with the topology subgraph it touches, for an impact map",
+				Description: "Components are classified as directly, indirectly or not affected, using the pinned root cause if any. " +
+					"The topology has the incident's hosts and resources and, with a service topology configured, their services " +
+					"and the services depending on them or they depend on; nodes without alerts are unaffected.",
+				Response: BlastRadiusAnalysisResponse{}},
+		}},
+		{Pattern: "/api/incidents/{id}/story", Handler: h.handleIncidentStory, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Narrative of an incident: timeline, root cause, impact and fix",
+				Description: "The tone is calm-engineer (default), executive or terse; the locale en (default), es, de or hi. " +
+					"Fix steps come from the playbooks and are not translated. Unknown tones or locales return 400.",
+				Query: []openapi.Param{
+					{Name: "tone", Description: "calm-engineer (default), executive or terse"},
+					{Name: "locale", Description: "en (default), es, de or hi; regional variants such as es-MX use their language"},
+					tzParam,
+				},
+				Response: StoryResponse{}},
+		}},
+		{Pattern: "/api/incidents/{id}/share", Handler: h.handleIncidentShare, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Create an expiring, read-only link to an incident for people without API access",
+				Description: "The signed token grants GET access to the incident's details, story and timeline under /api/shared/{token} " +
+					"until it expires, by default after 24h and at most after server.share_max_ttl. The body is optional.",
+				Request: ShareLinkRequest{}, Status: http.StatusCreated, Response: ShareLinkResponse{}},
+		}},
+		{Pattern: "/api/shared/{token}", Handler: h.handleSharedIncident, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Details of the incident a share link grants access to; 401 once it expired",
+				Response: IncidentDetailResponse{}},
+		}},
+		{Pattern: "/api/shared/{token}/story", Handler: h.handleSharedIncidentStory, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Story of the incident a share link grants access to",
+				Query: []openapi.Param{
+					{Name: "tone", Description: "calm-engineer (default), executive or terse"},
+					{Name: "locale", Description: "en (default), es, de or hi"},
+					tzParam,
+				},
+				Response: StoryResponse{}},
+		}},
+		{Pattern: "/api/shared/{token}/timeline", Handler: h.handleSharedIncidentTimeline, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Timeline of the incident a share link grants access to", Response: TimelineResponse{}},
+		}},
+		{Pattern: "/api/incidents/{id}/actions", Handler: h.handleIncidentActionItems, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Follow-up action items of an incident, oldest first, with open and overdue counts",
+				Response: IncidentActionItemListResponse{}},
+			{Method: http.MethodPost, Summary: "Record a follow-up action item with an owner and due date",
+				Description: "Open and in-progress items are listed in the weekly report. Once past due they are reported once per due date " +
+					"through the notification channels, routed by the incident's labels and action_owner. A due_at of YYYY-MM-DD is the end of " +
+					"that day in the request's time zone.",
+				Query: []openapi.Param{tzParam}, Request: IncidentActionItemRequest{}, Status: http.StatusCreated, Response: IncidentActionItemResponse{}},
+		}},
+		{Pattern: "/api/incidents/{id}/actions/{action}", Handler: h.handleIncidentActionItem, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "An action item of an incident", Response: IncidentActionItemResponse{}},
+			{Method: http.MethodPatch, Summary: "Update the description, owner, due date or status of an action item",
+				Description: "Only the given fields change; an empty due_at clears it. A new due date is reported again once it passes.",
+				Query:       []openapi.Param{tzParam},
+				Request:     IncidentActionItemRequest{}, Response: IncidentActionItemResponse{}},
+			{Method: http.MethodDelete, Summary: "Delete an action item", Status: http.StatusNoContent},
+		}},
+		{Pattern: "/api/incidents/{id}/timeline/export", Handler: h.handleIncidentTimelineExport, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Download an incident's timeline as CSV or an iCalendar file",
+				Description: "Includes notes, escalations, priority changes and the changes deployed to the incident's hosts from " +
+					"30 minutes before it started. The calendar has one event spanning the incident and one per timeline event.",
+				Query: []openapi.Param{{Name: "format", Description: "csv (default) or ics"}, tzParam}},
+		}},
+		{Pattern: "/api/incidents/{id}/ticket", Handler: h.handleIncidentTicket, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Jira/GitHub ticket filed for an incident", Response: TicketResponse{}},
+			{Method: http.MethodPost, Summary: "File a Jira/GitHub ticket with the summary, technical report and fix playbook",
+				Description: "Returns 201 with the new ticket, or 200 with the ticket filed earlier",
+				Status:      http.StatusCreated, Response: TicketResponse{}},
+		}},
+		{Pattern: "/api/timeline/", Path: "/api/timeline/{id}", Handler: h.handleIncidentTimeline, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Chronological events of an incident", Response: TimelineResponse{}},
+		}},
+		{Pattern: "/api/timeline-enhanced/{id}", Handler: h.handleIncidentTimelineEnhanced, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Incident timeline with cascade and root cause markers", Response: openapi.Object{
+				"incident_id":            "",
+				"events":                 []openapi.Object{},
+				"total_events":           0,
+				"duration":               "",
+				"start_time":             time.Time{},
+				"end_time":               time.Time{},
+				"critical_points":        []int{},
+				"root_cause_event_index": 0,
+				"resolution_event_index": 0,
+			}},
+		}},
+		{Pattern: "/api/events", Handler: h.handleSSE, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Server-sent events with the latest incident every few seconds",
+				Description: "Finished incident analyses are sent as \"analysis\" events", ResponseType: "text/event-stream"},
+		}},
+		{Pattern: "/api/test/create-incident", Handler: h.handleCreateTestIncident, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Create a simulated critical incident for development", Status: http.StatusCreated,
+				Response: openapi.Object{"incident_count": 0, "alert_id": "", "message": ""}},
+		}},
+		{Pattern: "/api/test/scenarios", Handler: h.handleScenarios, Tag: "Incidents", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Built-in synthetic incident scenarios",
+				Response: openapi.Object{"scenarios": []ScenarioInfoResponse{}}},
+			{Method: http.MethodPost, Summary: "Inject a synthetic incident scenario for testing and demos",
+				Description: "Injects a built-in scenario or a custom script of timed alerts. With speed 0 every alert is " +
+					"injected at once, backdated to end now (201); otherwise the scenario plays in the background (202).",
+				Request: ScenarioRequest{}, Status: http.StatusCreated, Response: ScenarioResponse{}},
+		}},
+
+		// System
+		{Pattern: "/api/health", Handler: h.handleHealth, Tag: "System", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Service health",
+				Description: "Always 200 so dashboards can show failing checks. Critical checks (observability.critical_health_checks) failing make the status unhealthy, others degraded.",
+				Response:    HealthResponse{}},
+		}},
+		{Pattern: "/healthz", Handler: h.handleLiveness, Tag: "System", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Liveness probe: 200 while the process is up", Response: ProbeResponse{}},
+		}},
+		{Pattern: "/readyz", Handler: h.handleReadiness, Tag: "System", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Readiness probe: 200 when the database is reachable and the alert sources are polled, 503 otherwise",
+				Description: "Replicas using leader election are ready without polling, as only the leader polls.",
+				Response:    ProbeResponse{}},
+		}},
+		{Pattern: "/api/logs", Handler: h.handleLogs, Tag: "System", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Recent internal service logs",
+				Description: "The newest logs, or with since the logs after that cursor, oldest first, for tailing",
+				Query: []openapi.Param{
+					{Name: "level", Description: "Minimum level: debug, info, warn or error; default debug"},
+					{Name: "since", Type: "integer", Description: "Cursor of a previous response; only newer logs are returned"},
+					{Name: "limit", Type: "integer", Description: "Logs to return, at most 1000; default 100"},
+				}, Response: LogsResponse{}},
+		}},
+		{Pattern: "/api/metrics/export", Handler: h.handleMetricsExport, Tag: "System", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Service metrics as CSV", ResponseType: "text/csv"},
+		}},
+		{Pattern: "/api/diagnostics", Handler: h.handleDiagnostics, Tag: "System", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Component health diagnostics",
+				Description: "With ingestion.leader_election, leader shows the replica polling the alert sources. unclassified_charts lists the charts whose alerts no resources.rules entry or source could classify, the most frequent first.",
+				Response: openapi.Object{"status": "", "diagnostics": []map[string]any{}, "timestamp": time.Time{}, "leader": services.LeaderStatus{},
+					"unclassified_charts": []classify.UnclassifiedChart{}}},
+		}},
+		{Pattern: "/api/events/change", Handler: h.handleChangeEvents, Tag: "Changes", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Recent deploy, config and feature-flag changes",
+				Query:    []openapi.Param{{Name: "limit", Type: "integer"}},
+				Response: openapi.Object{"changes": []ChangeEventResponse{}, "count": 0}},
+			{Method: http.MethodPost, Summary: "Record a change event",
+				Description: "Accepts the native format or a GitHub deployment webhook (X-GitHub-Event: deployment)",
+				Request:     ChangeEventRequest{}, Status: http.StatusCreated, Response: ChangeEventResponse{}},
+		}},
+
+		// AI-powered analysis endpoints
+		{Pattern: "/api/analyze", Handler: h.handleAIAnalysis, Tag: "Analysis", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Analyze all alerts, or one incident's, and tell the incident story",
+				Query: []openapi.Param{{Name: "incident_id"}}, Response: AIAnalysisResponse{}},
+		}},
+		{Pattern: "/api/ai/models", Handler: h.handleModelVersions, Tag: "Analysis", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Root cause model versions with their accuracy and calibration curves",
+				Response: []ModelVersionResponse{}},
+		}},
+		{Pattern: "/api/redaction/restore", Handler: h.handleRedactionRestore, Tag: "Analysis", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Replace the redaction tokens in text sent to OpenAI or notification channels with the real values",
+				Description: "With redaction enabled, hostnames become host-<hash>, IP addresses ip-<hash> and values of denylisted labels <label>-<hash>. " +
+					"The mapping is kept in this process only.",
+				Request: RedactionRestoreRequest{}, Response: RedactionRestoreResponse{}},
+		}},
+		{Pattern: "/api/alert-groups", Handler: h.handleAlertGroups, Tag: "Analysis", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Alerts grouped by host and cascade relationships",
+				Response: openapi.Object{"groups": []AlertGroupResponse{}, "total": 0}},
+		}},
+		{Pattern: "/api/anomalies", Handler: h.handleAnomalies, Tag: "Analysis", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Alert bursts and never-before-seen alerts", Query: []openapi.Param{windowParam},
+				Response: openapi.Object{"anomalies": []AnomalyResponse{}, "count": 0, "window": "", "since": time.Time{}}},
+		}},
+		{Pattern: "/api/predictions", Handler: h.handlePredictions, Tag: "Analysis", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Incidents likely to form soon from open warnings",
+				Response: openapi.Object{"predictions": []PredictionResponse{}, "count": 0}},
+		}},
+
+		{Pattern: "/api/alerts", Handler: h.handleAlerts, Tag: "Alerts", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Paginated list of stored alerts, newest first",
+				Query: append(pageParams,
+					openapi.Param{Name: "q", Description: "Search host, chart and alert name"},
+					openapi.Param{Name: "host"},
+					openapi.Param{Name: "source", Description: "Alert source, e.g. netdata or zabbix"},
+					openapi.Param{Name: "status", Description: "Comma-separated statuses, any of which matches"},
+					openapi.Param{Name: "from", Description: "RFC3339 or YYYY-MM-DD"},
+					openapi.Param{Name: "to", Description: "RFC3339 or YYYY-MM-DD"},
+					openapi.Param{Name: "acknowledged", Type: "boolean"},
+				),
+				Response: AlertListResponse{}},
+		}},
+		{Pattern: "/api/alerts/{id}", Handler: h.handleAlert, Tag: "Alerts", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "An alert with its incidents, chart samples and the raw payload its source sent",
+				Response: AlertDetailResponse{}},
+		}},
+		{Pattern: "/api/alerts/{id}/ack", Handler: h.handleAlertAck, Tag: "Alerts", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Acknowledge a single alert, e.g. a noisy one, leaving its incident open",
+				Request: AlertAckRequest{}, Response: AlertResponse{}},
+		}},
+
+		// Reports and analytics
+		{Pattern: "/api/reports/noise", Handler: h.handleNoiseReport, Tag: "Reports", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Alerting noise per incident and per alert source", Response: NoiseReportResponse{}},
+		}},
+		{Pattern: "/api/alerts/noisy", Handler: h.handleNoisyAlerts, Tag: "Reports", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Top noise generators per week",
+				Description: "Alert streams ranked by duplicate, churning and flapping alerts, for each of the last weeks (UTC, current week first)",
+				Query: []openapi.Param{
+					{Name: "weeks", Type: "integer", Description: "Weeks to rank, at most 52; default 1"},
+					{Name: "limit", Type: "integer", Description: "Generators per week, at most 100; default 10"},
+				}, Response: openapi.Object{"weeks": []WeeklyNoiseResponse{}}},
+		}},
+		{Pattern: "/api/alerts/storms", Handler: h.handleAlertStorms, Tag: "Reports", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Recent alert storms",
+				Description: "Alert storms collapsed into a single incident, the latest first, with the incidents whose notifications they suppressed",
+				Response:    openapi.Object{"enabled": true, "storms": []AlertStormResponse{}}},
+		}},
+		{Pattern: "/api/reports/digest", Handler: h.handleDigest, Tag: "Reports", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Incident digest as the HTML email, or JSON with format=json",
+				Query: []openapi.Param{
+					{Name: "period", Description: "e.g. 1d, 7d or 12h"},
+					{Name: "format", Description: "html (default) or json"},
+					tzParam,
+				}, Response: DigestResponse{}},
+		}},
+		{Pattern: "/api/reports/weekly", Handler: h.handleWeeklyReport, Tag: "Reports", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Weekly reliability report as Markdown, PDF or JSON",
+				Description: "Incidents of the week by severity, the MTTR trend of the last 4 weeks, the top root-cause resource " +
+					"types, the noisiest hosts and the open action items (tracker tickets of incidents still open).",
+				Query: []openapi.Param{
+					{Name: "format", Description: "markdown (default), pdf or json"},
+					{Name: "to", Description: "End of the week, RFC3339 or YYYY-MM-DD (inclusive, in the time zone); default now"},
+					tzParam,
+				}, Response: WeeklyReportResponse{}},
+		}},
+		{Pattern: "/api/analytics", Handler: h.handleReliabilityAnalytics, Tag: "Reports", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "MTTR, MTTA, incident frequencies and trends", Query: []openapi.Param{windowParam, envParam},
+				Response: ReliabilityAnalyticsResponse{}},
+		}},
+		{Pattern: "/api/analytics/incidents", Handler: h.handleIncidentAnalytics, Tag: "Reports", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Incident counts and MTTR grouped by a label",
+				Query:    []openapi.Param{{Name: "group_by", Required: true}, windowParam, envParam},
+				Response: GroupedAnalyticsResponse{}},
+		}},
+		{Pattern: "/api/analytics/propagation-patterns", Handler: h.handlePropagationPatterns, Tag: "Reports", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Learned resource propagation patterns",
+				Query:    []openapi.Param{{Name: "host"}, {Name: "service"}},
+				Response: openapi.Object{"patterns": []PropagationPatternResponse{}, "count": 0}},
+		}},
+
+		// Grafana JSON data source
+		{Pattern: "/api/grafana/{$}", Handler: h.handleGrafanaRoot, Tag: "Reports", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Grafana JSON data source connection test", Response: openapi.Object{"status": "ok"}},
+		}},
+		{Pattern: "/api/grafana/search", Handler: h.handleGrafanaSearch, Tag: "Reports", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Targets a Grafana panel can query: incidents, open_incidents and mttr",
+				Response: []string{}},
+		}},
+		{Pattern: "/api/grafana/query", Handler: h.handleGrafanaQuery, Tag: "Reports", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Incident counts, open incidents and MTTR over time for Grafana panels",
+				Description: "Each series has a point per panel interval: incidents started, incidents open at its end, or the mean " +
+					"seconds to resolve the incidents resolved in it. Targets of type table get the incidents started in the range.",
+				Request: GrafanaQueryRequest{}, Response: []GrafanaTimeSeries{}},
+		}},
+		{Pattern: "/api/grafana/annotations", Handler: h.handleGrafanaAnnotations, Tag: "Reports", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Incidents open in the range as Grafana annotation regions",
+				Description: "The annotation's query, if set, keeps the incidents matching it like the q parameter, e.g. a host.",
+				Request:     GrafanaAnnotationRequest{}, Response: []GrafanaAnnotation{}},
+		}},
+
+		// Fleet inventory
+		{Pattern: "/api/hosts", Handler: h.handleHosts, Tag: "Hosts", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Host inventory with health and incident counts",
+				Response: openapi.Object{"hosts": []HostResponse{}, "total": 0}},
+		}},
+		{Pattern: "/api/hosts/{host}/incidents", Handler: h.handleHostIncidents, Tag: "Hosts", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Incidents that involved a host",
+				Response: openapi.Object{"host": "", "incidents": []IncidentListItemResponse{}, "total": 0}},
+		}},
+		{Pattern: "/api/services", Handler: h.handleServices, Tag: "Hosts", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Topology services with incident counts, MTTR and availability over a period",
+				Description: "Incidents are mapped onto services by their hosts; only critical alerts on a service's hosts count as downtime",
+				Query:       servicePeriodParams, Response: ServiceListResponse{}},
+		}},
+		{Pattern: "/api/services/{name}/incidents", Handler: h.handleServiceIncidents, Tag: "Hosts", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Incidents that touched a service over a period, newest first",
+				Query: servicePeriodParams, Response: ServiceIncidentsResponse{}},
+		}},
+
+		// On-call
+		{Pattern: "/api/oncall/current", Handler: h.handleOnCallCurrent, Tag: "On-call", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Who is on call right now", Response: OnCallShiftResponse{}},
+		}},
+		{Pattern: "/api/oncall/schedule", Handler: h.handleOnCallSchedule, Tag: "On-call", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "On-call rotation", Response: OnCallScheduleRequest{}},
+			{Method: http.MethodPut, Summary: "Replace the on-call rotation", Request: OnCallScheduleRequest{}, Response: OnCallScheduleRequest{}},
+		}},
+		{Pattern: "/api/oncall/overrides", Handler: h.handleOnCallOverrides, Tag: "On-call", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Add a temporary override, e.g. a shift swap",
+				Request: oncall.Override{}, Status: http.StatusCreated, Response: oncall.Override{}},
+		}},
+
+		// Playbooks
+		{Pattern: "/api/playbooks", Handler: h.handlePlaybooks, Tag: "Playbooks", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Organization remediation playbooks", Response: []playbook.Playbook{}},
+			{Method: http.MethodPost, Summary: "Add a playbook matched by resource type, chart pattern or alert name",
+				Request: playbook.Playbook{}, Status: http.StatusCreated, Response: playbook.Playbook{}},
+		}},
+		{Pattern: "/api/playbooks/{id}", Handler: h.handlePlaybook, Tag: "Playbooks", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "A playbook", Response: playbook.Playbook{}},
+			{Method: http.MethodPut, Summary: "Create or replace a playbook", Request: playbook.Playbook{}, Response: playbook.Playbook{}},
+			{Method: http.MethodDelete, Summary: "Delete a playbook", Status: http.StatusNoContent},
+		}},
+
+		// ChatOps
+		{Pattern: "/api/slack/commands", Handler: h.handleSlackCommand, Tag: "Integrations", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Slack slash commands, answered with Block Kit",
+				Description: "Requests must carry a valid X-Slack-Signature",
+				Request:     openapi.Object{"command": "", "text": "", "user_name": ""}, RequestType: "application/x-www-form-urlencoded",
+				Response: openapi.Object{}},
+		}},
+
+		// Alert source webhooks
+		{Pattern: "/api/webhooks/nagios", Handler: h.handleNagiosWebhook, Tag: "Integrations", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Nagios/Icinga passive check results, one or an array",
+				Request: openapi.Object{
+					"host_name": "", "service_description": "", "return_code": 0, "state": "",
+					"plugin_output": "", "performance_data": "", "timestamp": int64(0),
+				}, Status: http.StatusAccepted, Auth: true},
+		}},
+
+		// Outgoing webhooks
+		{Pattern: "/api/webhooks", Handler: h.handleWebhooks, Tag: "Integrations", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Outgoing webhooks receiving incident events", Response: []WebhookResponse{}},
+			{Method: http.MethodPost, Summary: "Add a webhook: URL, HMAC signing secret and event filter",
+				Request: WebhookRequest{}, Status: http.StatusCreated, Response: WebhookResponse{}},
+		}},
+		{Pattern: "/api/webhooks/{id}", Handler: h.handleWebhook, Tag: "Integrations", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "A webhook", Response: WebhookResponse{}},
+			{Method: http.MethodPut, Summary: "Replace a webhook", Request: WebhookRequest{}, Response: WebhookResponse{}},
+			{Method: http.MethodDelete, Summary: "Delete a webhook with its failed deliveries", Status: http.StatusNoContent},
+		}},
+		{Pattern: "/api/webhooks/dead-letters", Handler: h.handleWebhookDeadLetters, Tag: "Integrations", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Deliveries that failed every retry, newest first",
+				Query: []openapi.Param{
+					{Name: "webhook", Description: "Only the failed deliveries of this webhook"},
+					{Name: "limit", Type: "integer", Description: "At most 1000, default 100"},
+				},
+				Response: []FailedDeliveryResponse{}},
+		}},
+		{Pattern: "/api/webhooks/dead-letters/{id}", Handler: h.handleWebhookDeadLetter, Tag: "Integrations", Operations: []openapi.Operation{
+			{Method: http.MethodDelete, Summary: "Discard a failed delivery", Status: http.StatusNoContent},
+		}},
+		{Pattern: "/api/webhooks/dead-letters/{id}/redeliver", Handler: h.handleWebhookRedeliver, Tag: "Integrations", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Deliver a failed delivery again, with the same retries", Status: http.StatusAccepted},
+		}},
+
+		// GraphQL
+		{Pattern: "/api/graphql", Handler: h.handleGraphQL, Tag: "GraphQL", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Run a GraphQL query over incidents, alerts, timelines, analyses and stats",
+				Request: GraphQLRequest{}, Response: openapi.Object{"data": openapi.Object{}, "errors": []openapi.Object{}}},
+			{Method: http.MethodGet, Summary: "Run a GraphQL query given as parameters",
+				Query: []openapi.Param{
+					{Name: "query", Description: "GraphQL query"},
+					{Name: "variables", Description: "JSON object of variables"},
+					{Name: "operationName"},
+				},
+				Response: openapi.Object{"data": openapi.Object{}, "errors": []openapi.Object{}}},
+		}},
+
+		// Administration
+		{Pattern: "/api/admin/reload", Handler: h.handleAdminReload, Tag: "Administration", Operations: []openapi.Operation{
+			{Method: http.MethodPost, Summary: "Reload the configuration file", Response: config.ReloadResult{}, Auth: true},
+		}},
+		{Pattern: "/api/admin/config", Handler: h.handleAdminConfig, Tag: "Administration", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Effective configuration with secrets masked",
+				Description: "sources maps each setting (dotted YAML key) taken from the config file or an environment variable to \"file\" or \"env\"; settings left out have their default. Environment variables take precedence over the file.",
+				Response:    config.Effective{}, Auth: true},
+		}},
+		{Pattern: "/api/admin/faults", Handler: h.handleAdminFaults, Tag: "Administration", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Faults being injected into the pipeline", Response: FaultsResponse{}, Auth: true},
+			{Method: http.MethodPut, Summary: "Inject faults to test degradation behavior",
+				Description: "Adds repository_latency to alert and incident saves and the database health check, fails Netdata alarm log fetches as timed out (exercising retries and the circuit breaker) and fails AI predictions. Replaces the faults set before; they clear themselves after duration. Needs server.fault_injection.",
+				Request:     FaultsRequest{}, Response: FaultsResponse{}, Auth: true},
+			{Method: http.MethodDelete, Summary: "Stop injecting faults", Status: http.StatusNoContent, Auth: true},
+		}},
+		{Pattern: "/api/audit", Handler: h.handleAuditLog, Tag: "Administration", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Audit log of writes made through the API, newest first",
+				Description: "Every write is recorded with who made it (X-User header and bearer token fingerprint), the request payload with secrets redacted and, where the endpoint reports them, the changed fields.",
+				Query: []openapi.Param{
+					{Name: "actor"},
+					{Name: "api_key", Description: "Bearer token fingerprint"},
+					{Name: "method", Description: "e.g. PUT"},
+					{Name: "path", Description: "Path prefix, e.g. /api/playbooks"},
+					{Name: "from", Description: "RFC3339 or YYYY-MM-DD"},
+					{Name: "to", Description: "RFC3339 or YYYY-MM-DD"},
+					{Name: "limit", Type: "integer", Description: "At most 1000, default 100"},
+				},
+				Response: AuditLogResponse{}},
+		}},
+
+		// API documentation
+		{Pattern: "/api/openapi.json", Handler: h.handleOpenAPI, Tag: "System", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "This OpenAPI document", Response: openapi.Object{}},
+		}},
+		{Pattern: "/api/docs", Handler: h.handleAPIDocs},
+
+		// Web dashboard
+		{Pattern: "/{$}", Handler: h.handleDashboard},
+
+		// Public status page
+		{Pattern: "/status", Handler: h.handleStatusPage, Tag: "Status page", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Public status page", ResponseType: "text/html"},
+		}},
+		{Pattern: "/status.json", Handler: h.handleStatusPageJSON, Tag: "Status page", Operations: []openapi.Operation{
+			{Method: http.MethodGet, Summary: "Public status page as JSON", Response: statuspage.Page{}},
+		}},
+	}
+}
+
+// handleOpenAPI serves the OpenAPI document generated from the routes
+func (h *Handler) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, h.spec)
+}
+
+// handleAPIDocs serves Swagger UI for the OpenAPI document
+func (h *Handler) handleAPIDocs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(openapi.SwaggerUI(apiInfo.Title, "/api/openapi.json"))); err != nil {
+		h.logger.Error("Failed to write API docs", observability.Error(err))
+	}
+}
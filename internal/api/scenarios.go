@@ -0,0 +1,211 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/scenario"
+)
+
+// maxScenarioSpeed is the fastest a scenario can be played back
+const maxScenarioSpeed = 3600
+
+// ScenarioInfoResponse describes a built-in scenario
+type ScenarioInfoResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Steps       int    `json:"steps"`
+	Duration    string `json:"duration"`
+}
+
+// ScenarioStepRequest is one alert of a custom scenario script
+type ScenarioStepRequest struct {
+	After        string            `json:"after"` // Offset from the scenario start, e.g. "90s"
+	Host         string            `json:"host"`
+	Chart        string            `json:"chart"`
+	Family       string            `json:"family"`
+	Name         string            `json:"name"`
+	Status       string            `json:"status"` // CLEAR, WARNING or CRITICAL
+	Value        float64           `json:"value"`
+	ResourceType string            `json:"resource_type"`
+	Description  string            `json:"description"`
+	Labels       map[string]string `json:"labels"`
+}
+
+// ScenarioRequest starts a built-in scenario or a custom script of timed alerts
+type ScenarioRequest struct {
+	Scenario string                `json:"scenario"` // Built-in scenario name, or the name of the custom script
+	Steps    []ScenarioStepRequest `json:"steps"`    // Custom script, instead of a built-in scenario
+	Speed    float64               `json:"speed"`    // 0 injects every alert at once, 1 plays in real time, 60 a minute per second
+}
+
+// ScenarioResponse reports an injected or running scenario
+type ScenarioResponse struct {
+	Scenario    string     `json:"scenario"`
+	Status      string     `json:"status"` // "injected", or "running" for paced playback
+	Alerts      int        `json:"alerts"`
+	Duration    string     `json:"duration"`
+	Speed       float64    `json:"speed"`
+	IncidentIDs []string   `json:"incident_ids,omitempty"`
+	CompletesAt *time.Time `json:"completes_at,omitempty"`
+}
+
+// handleScenarios lists the built-in scenarios (GET) or injects a scenario (POST). An
+// instant scenario is backdated to end now; a paced one is played in the background,
+// stamping each alert with the time it is injected.
+func (h *Handler) handleScenarios(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		scenarios := []ScenarioInfoResponse{}
+		for _, s := range scenario.List() {
+			scenarios = append(scenarios, ScenarioInfoResponse{
+				Name:        s.Name,
+				Description: s.Description,
+				Steps:       len(s.Steps),
+				Duration:    s.Duration().String(),
+			})
+		}
+		h.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"scenarios": scenarios,
+		})
+
+	case http.MethodPost:
+		var req ScenarioRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		s, err := req.scenario()
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if req.Speed < 0 || req.Speed > maxScenarioSpeed {
+			h.writeError(w, http.StatusBadRequest, fmt.Sprintf("speed must be between 0 and %d", maxScenarioSpeed))
+			return
+		}
+
+		response := ScenarioResponse{
+			Scenario: s.Name,
+			Alerts:   len(s.Steps),
+			Duration: s.Duration().String(),
+			Speed:    req.Speed,
+		}
+
+		if req.Speed == 0 {
+			incidents, err := h.injectAlerts(r.Context(), s.Alerts(time.Now().Add(-s.Duration())), nil)
+			if err != nil {
+				h.logger.Error("Failed to inject scenario", observability.String("scenario", s.Name), observability.Error(err))
+				h.writeError(w, http.StatusInternalServerError, "Failed to inject scenario")
+				return
+			}
+			response.Status = "injected"
+			for _, incident := range incidents {
+				response.IncidentIDs = append(response.IncidentIDs, incident.ID)
+			}
+			h.writeJSON(w, http.StatusCreated, response)
+			return
+		}
+
+		completesAt := time.Now().Add(time.Duration(float64(s.Duration()) / req.Speed))
+		response.Status = "running"
+		response.CompletesAt = &completesAt
+		go h.playScenario(s, req.Speed)
+		h.writeJSON(w, http.StatusAccepted, response)
+
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// scenario returns the requested built-in scenario or custom script
+func (req ScenarioRequest) scenario() (scenario.Scenario, error) {
+	if len(req.Steps) == 0 {
+		s, ok := scenario.Get(req.Scenario)
+		if !ok {
+			names := []string{}
+			for _, s := range scenario.List() {
+				names = append(names, s.Name)
+			}
+			return s, fmt.Errorf("unknown scenario %q, expected one of %s or custom steps", req.Scenario, strings.Join(names, ", "))
+		}
+		return s, nil
+	}
+
+	s := scenario.Scenario{Name: req.Scenario}
+	if s.Name == "" {
+		s.Name = "custom"
+	}
+	for i, step := range req.Steps {
+		var after time.Duration
+		if step.After != "" {
+			var err error
+			if after, err = time.ParseDuration(step.After); err != nil {
+				return s, fmt.Errorf("step %d has invalid after %q", i+1, step.After)
+			}
+		}
+		s.Steps = append(s.Steps, scenario.Step{
+			After:        after,
+			Host:         step.Host,
+			Chart:        step.Chart,
+			Family:       step.Family,
+			Name:         step.Name,
+			Status:       domain.AlertStatus(strings.ToUpper(step.Status)),
+			Value:        step.Value,
+			ResourceType: domain.ResourceType(strings.ToUpper(step.ResourceType)),
+			Description:  step.Description,
+			Labels:       step.Labels,
+		})
+	}
+	return s, s.Validate()
+}
+
+// playScenario injects the alerts of a scenario as they become due at the given speed
+func (h *Handler) playScenario(s scenario.Scenario, speed float64) {
+	ctx := context.Background()
+	start := time.Now()
+	scripted := s.Alerts(start)
+
+	var injected []domain.Alert
+	for _, alert := range scripted {
+		due := start.Add(time.Duration(float64(alert.OccurredAt.Sub(start)) / speed))
+		time.Sleep(time.Until(due))
+
+		alert.OccurredAt = time.Now()
+		alert.ID = idgen.New(alert.OccurredAt)
+		// Rebuilding from every injected alert keeps the scenario's incident IDs stable
+		if _, err := h.injectAlerts(ctx, []domain.Alert{alert}, injected); err != nil {
+			h.logger.Error("Failed to inject scenario alert",
+				observability.String("scenario", s.Name), observability.Error(err))
+			return
+		}
+		injected = append(injected, alert)
+	}
+	h.logger.Info("Scenario completed",
+		observability.String("scenario", s.Name), observability.Int("alerts", len(injected)))
+}
+
+// injectAlerts stores synthetic alerts and the incidents built from them together with
+// the previously injected alerts of the same run
+func (h *Handler) injectAlerts(ctx context.Context, alerts, previous []domain.Alert) ([]domain.Incident, error) {
+	if err := h.repo.SaveAlerts(ctx, alerts); err != nil {
+		return nil, fmt.Errorf("failed to save alerts: %w", err)
+	}
+
+	incidents := h.builder.Build(append(append([]domain.Alert(nil), previous...), alerts...))
+	for _, incident := range incidents {
+		if err := h.repo.SaveIncident(ctx, incident); err != nil {
+			return nil, fmt.Errorf("failed to save incident: %w", err)
+		}
+		h.exportIncident(ctx, incident)
+		h.enqueueAnalysis(incident)
+	}
+	return incidents, nil
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"incident-teller/internal/observability"
+	"incident-teller/internal/services"
+	"incident-teller/internal/topology"
+)
+
+// ServiceResponse is a topology service with its incidents and availability over a period
+type ServiceResponse struct {
+	Name                string     `json:"name"`
+	Hosts               []string   `json:"hosts"`
+	DependsOn           []string   `json:"depends_on,omitempty"`
+	Health              string     `json:"health"`
+	Incidents           int        `json:"incidents"`
+	ActiveIncidents     int        `json:"active_incidents"`
+	Outages             int        `json:"outages"` // Incidents with a critical alert
+	DowntimeSeconds     float64    `json:"downtime_seconds"`
+	AvailabilityPercent float64    `json:"availability_percent"`
+	MTTRSeconds         float64    `json:"mttr_seconds"`
+	MTTR                string     `json:"mttr"`
+	LastIncidentAt      *time.Time `json:"last_incident_at,omitempty"`
+}
+
+// ServiceListResponse lists the topology services over a period
+type ServiceListResponse struct {
+	From     time.Time         `json:"from"`
+	To       time.Time         `json:"to"`
+	Services []ServiceResponse `json:"services"`
+	Total    int               `json:"total"`
+}
+
+// ServiceIncidentsResponse lists the incidents that touched a service over a period
+type ServiceIncidentsResponse struct {
+	From      time.Time                  `json:"from"`
+	To        time.Time                  `json:"to"`
+	Service   ServiceResponse            `json:"service"`
+	Incidents []IncidentListItemResponse `json:"incidents"`
+	Total     int                        `json:"total"`
+}
+
+// SetTopology enables the service endpoints, which map incidents onto the services of
+// the topology by their hosts, and matches service-wide changes to the hosts of the service
+func (h *Handler) SetTopology(topo *topology.Topology) {
+	h.topology = topo
+	h.changes.SetTopology(topo)
+}
+
+// handleServices lists the topology services with their incident counts and availability
+func (h *Handler) handleServices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if len(h.topology.Services()) == 0 {
+		h.writeError(w, http.StatusNotFound, "Service topology not configured")
+		return
+	}
+	from, to, ok := h.servicePeriod(w, r)
+	if !ok {
+		return
+	}
+
+	incidents, err := h.repo.GetIncidents(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve incidents")
+		return
+	}
+
+	response := ServiceListResponse{From: from, To: to, Services: []ServiceResponse{}}
+	for _, summary := range services.BuildServiceInventory(h.topology, incidents, from, to) {
+		response.Services = append(response.Services, serviceResponse(summary))
+	}
+	response.Total = len(response.Services)
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// handleServiceIncidents returns the incidents that touched a service, newest first
+func (h *Handler) handleServiceIncidents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if len(h.topology.Services()) == 0 {
+		h.writeError(w, http.StatusNotFound, "Service topology not configured")
+		return
+	}
+	name := r.PathValue("name")
+	if _, ok := h.topology.Service(name); !ok {
+		h.writeError(w, http.StatusNotFound, "Service not found")
+		return
+	}
+	from, to, ok := h.servicePeriod(w, r)
+	if !ok {
+		return
+	}
+
+	incidents, err := h.repo.GetIncidents(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve incidents")
+		return
+	}
+
+	summary, _ := services.SummarizeService(h.topology, name, incidents, from, to)
+	response := ServiceIncidentsResponse{
+		From:      from,
+		To:        to,
+		Service:   serviceResponse(summary),
+		Incidents: []IncidentListItemResponse{},
+	}
+	for _, incident := range services.ServiceIncidents(h.topology, name, incidents, from, to) {
+		response.Incidents = append(response.Incidents, h.convertIncidentToListItem(incident))
+	}
+	response.Total = len(response.Incidents)
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// servicePeriod reads the period of a service request: from/to (RFC3339 or YYYY-MM-DD),
+// or the window before now (default 90d)
+func (h *Handler) servicePeriod(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	params := r.URL.Query()
+	to := time.Now().UTC()
+	window := 90 * 24 * time.Hour
+	if v := params.Get("window"); v != "" {
+		parsed, err := parsePeriod(v)
+		if err != nil || parsed <= 0 {
+			h.writeError(w, http.StatusBadRequest, "Invalid window, use e.g. 90d or 720h")
+			return time.Time{}, time.Time{}, false
+		}
+		window = parsed
+	}
+	if v := params.Get("to"); v != "" {
+		parsed, dateOnly, err := parseExportTime(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid to: must be RFC3339 or YYYY-MM-DD")
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+		if dateOnly {
+			// A bare date includes the whole day
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+	}
+	from := to.Add(-window)
+	if v := params.Get("from"); v != "" {
+		parsed, _, err := parseExportTime(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid from: must be RFC3339 or YYYY-MM-DD")
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+	if !to.After(from) {
+		h.writeError(w, http.StatusBadRequest, "Invalid range: to is not after from")
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
+
+func serviceResponse(summary services.ServiceSummary) ServiceResponse {
+	return ServiceResponse{
+		Name:                summary.Service.Name,
+		Hosts:               summary.Service.Hosts,
+		DependsOn:           summary.Service.DependsOn,
+		Health:              summary.Health,
+		Incidents:           summary.Incidents,
+		ActiveIncidents:     summary.ActiveIncidents,
+		Outages:             summary.Outages,
+		DowntimeSeconds:     summary.Downtime.Seconds(),
+		AvailabilityPercent: summary.Availability,
+		MTTRSeconds:         summary.MTTR.Seconds(),
+		MTTR:                summary.MTTR.Round(time.Second).String(),
+		LastIncidentAt:      summary.LastIncidentAt,
+	}
+}
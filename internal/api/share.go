@@ -0,0 +1,229 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"incident-teller/internal/observability"
+)
+
+// DefaultShareTTL is how long a share link is valid unless the request asks otherwise
+const DefaultShareTTL = 24 * time.Hour
+
+// ShareLinkRequest asks for a share link; an empty TTL is DefaultShareTTL
+type ShareLinkRequest struct {
+	TTL string `json:"ttl,omitempty"` // e.g. 2h or 7d, at most server.share_max_ttl
+}
+
+// ShareLinkResponse is a link granting read-only access to one incident
+type ShareLinkResponse struct {
+	IncidentID  string    `json:"incident_id"`
+	Token       string    `json:"token"`
+	URL         string    `json:"url"` // Incident details
+	StoryURL    string    `json:"story_url"`
+	TimelineURL string    `json:"timeline_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// SetShareLinks enables incident share links signed with the secret, valid for at most
+// maxTTL. Without a secret a random one is used, so links stop working on restart.
+func (h *Handler) SetShareLinks(secret string, maxTTL time.Duration) error {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("failed to generate share link secret: %w", err)
+		}
+	}
+	h.shareSecret = key
+	h.shareMaxTTL = maxTTL
+	return nil
+}
+
+// handleIncidentShare creates a share link for an incident
+func (h *Handler) handleIncidentShare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.shareSecret == nil {
+		h.writeError(w, http.StatusNotFound, "Share links not enabled")
+		return
+	}
+
+	var req ShareLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	ttl := DefaultShareTTL
+	if req.TTL != "" {
+		var err error
+		if ttl, err = parsePeriod(req.TTL); err != nil || ttl <= 0 {
+			h.writeError(w, http.StatusBadRequest, "Invalid ttl: use a positive duration such as 2h or 7d")
+			return
+		}
+	}
+	if h.shareMaxTTL > 0 && ttl > h.shareMaxTTL {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid ttl: share links are valid for at most %s", h.shareMaxTTL))
+		return
+	}
+
+	incident, err := h.findIncident(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+	if incident == nil {
+		h.writeError(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second).UTC()
+	token := h.signShareToken(incident.ID, expiresAt)
+	h.logger.Info("Incident shared",
+		observability.String("incident_id", incident.ID), observability.String("expires_at", expiresAt.Format(time.RFC3339)))
+	h.writeJSON(w, http.StatusCreated, ShareLinkResponse{
+		IncidentID:  incident.ID,
+		Token:       token,
+		URL:         "/api/shared/" + token,
+		StoryURL:    "/api/shared/" + token + "/story",
+		TimelineURL: "/api/shared/" + token + "/timeline",
+		ExpiresAt:   expiresAt,
+	})
+}
+
+// handleSharedIncident returns the details of a shared incident
+func (h *Handler) handleSharedIncident(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.authorizeShare(w, r)
+	if !ok {
+		return
+	}
+	incident, err := h.findIncident(r.Context(), id)
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+	if incident == nil {
+		h.writeError(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+	h.writeJSON(w, http.StatusOK, h.incidentDetail(r.Context(), incident))
+}
+
+// handleSharedIncidentStory tells the story of a shared incident
+func (h *Handler) handleSharedIncidentStory(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.authorizeShare(w, r)
+	if !ok {
+		return
+	}
+	r.SetPathValue("id", id)
+	h.handleIncidentStory(w, r)
+}
+
+// handleSharedIncidentTimeline returns the timeline of a shared incident
+func (h *Handler) handleSharedIncidentTimeline(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.authorizeShare(w, r)
+	if !ok {
+		return
+	}
+	incident, err := h.findIncident(r.Context(), id)
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+	if incident == nil {
+		h.writeError(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+
+	events := h.incidentTimeline(r.Context(), incident)
+	h.writeJSON(w, http.StatusOK, TimelineResponse{
+		IncidentID: incident.ID,
+		Events:     events,
+		Total:      len(events),
+		Duration:   h.calculateDuration(*incident),
+	})
+}
+
+// authorizeShare checks the method and the share token of the request, returning the
+// incident it grants access to. It writes the error response if the token is invalid
+// or expired.
+func (h *Handler) authorizeShare(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return "", false
+	}
+	if h.shareSecret == nil {
+		h.writeError(w, http.StatusNotFound, "Share links not enabled")
+		return "", false
+	}
+	id, expiresAt, ok := h.verifyShareToken(r.PathValue("token"))
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "Invalid share link")
+		return "", false
+	}
+	if !time.Now().Before(expiresAt) {
+		h.writeError(w, http.StatusUnauthorized, "Share link expired")
+		return "", false
+	}
+	// Shared pages may hold internal details; keep them out of shared caches
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	return id, true
+}
+
+// signShareToken returns the token <incident ID>.<expiry>.<signature>: the base64url
+// incident ID, the expiry in Unix seconds (base 36) and the HMAC-SHA256 of both
+func (h *Handler) signShareToken(incidentID string, expiresAt time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(incidentID)) + "." +
+		strconv.FormatInt(expiresAt.Unix(), 36)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(h.shareMAC(payload))
+}
+
+// verifyShareToken checks the signature of a share token and returns the incident ID
+// and expiry it carries
+func (h *Handler) verifyShareToken(token string) (string, time.Time, bool) {
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return "", time.Time{}, false
+	}
+	payload := token[:i]
+	signature, err := base64.RawURLEncoding.DecodeString(token[i+1:])
+	if err != nil || !hmac.Equal(signature, h.shareMAC(payload)) {
+		return "", time.Time{}, false
+	}
+
+	encodedID, expiry, found := strings.Cut(payload, ".")
+	if !found {
+		return "", time.Time{}, false
+	}
+	id, err := base64.RawURLEncoding.DecodeString(encodedID)
+	if err != nil || len(id) == 0 {
+		return "", time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(expiry, 36, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return string(id), time.Unix(seconds, 0).UTC(), true
+}
+
+func (h *Handler) shareMAC(payload string) []byte {
+	mac := hmac.New(sha256.New, h.shareSecret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
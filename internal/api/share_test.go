@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"incident-teller/internal/adapters/repository"
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+)
+
+// newTestHandler returns a handler over an in-memory repository holding the incidents
+func newTestHandler(t *testing.T, incidents ...domain.Incident) *Handler {
+	t.Helper()
+	repo := repository.NewInMemoryRepository()
+	for _, incident := range incidents {
+		if err := repo.SaveIncident(context.Background(), incident); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logger := observability.NewLogger(config.ObservabilityConfig{LogLevel: "error", LogFormat: "json"})
+	return NewHandler(repo, nil, logger, nil, nil)
+}
+
+// serve sends a request through the full route and middleware stack
+func serve(routes http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func newShareHandler(t *testing.T) (*Handler, http.Handler) {
+	t.Helper()
+	now := time.Now()
+	h := newTestHandler(t,
+		domain.Incident{ID: "inc-a", StartedAt: now, Events: []domain.Alert{
+			{ID: "a1", Host: "web-01", Status: domain.StatusCritical, OccurredAt: now}}},
+		domain.Incident{ID: "inc-b", StartedAt: now, Events: []domain.Alert{
+			{ID: "b1", Host: "db-01", Status: domain.StatusWarning, OccurredAt: now}}},
+	)
+	if err := h.SetShareLinks("secret", 48*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	return h, h.SetupRoutes()
+}
+
+func createShareLink(t *testing.T, routes http.Handler, incidentID, body string) ShareLinkResponse {
+	t.Helper()
+	rec := serve(routes, http.MethodPost, "/api/incidents/"+incidentID+"/share", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("share returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var link ShareLinkResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &link); err != nil {
+		t.Fatal(err)
+	}
+	return link
+}
+
+func TestShareLink_RoundTrip(t *testing.T) {
+	_, routes := newShareHandler(t)
+	link := createShareLink(t, routes, "inc-a", `{"ttl": "2h"}`)
+
+	if until := time.Until(link.ExpiresAt); until <= time.Hour || until > 2*time.Hour {
+		t.Errorf("expected the link to expire in 2h, got %s", until)
+	}
+	for _, target := range []string{link.URL, link.StoryURL, link.TimelineURL} {
+		rec := serve(routes, http.MethodGet, target, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s returned %d: %s", target, rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("Cache-Control") != "private, no-store" {
+			t.Errorf("GET %s: expected shared pages to stay out of caches", target)
+		}
+		if !strings.Contains(rec.Body.String(), "inc-a") || strings.Contains(rec.Body.String(), "inc-b") {
+			t.Errorf("GET %s: expected only incident inc-a, got %s", target, rec.Body.String())
+		}
+	}
+	if rec := serve(routes, http.MethodPost, link.URL, ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected shared links to be read-only, got %d", rec.Code)
+	}
+}
+
+func TestShareLink_Tampered(t *testing.T) {
+	_, routes := newShareHandler(t)
+	token := createShareLink(t, routes, "inc-a", "").Token
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("unexpected token format %q", token)
+	}
+	otherSignature := strings.Split(createShareLink(t, routes, "inc-b", "").Token, ".")[2]
+	later := strconv.FormatInt(time.Now().Add(365*24*time.Hour).Unix(), 36)
+	incidentB := base64.RawURLEncoding.EncodeToString([]byte("inc-b"))
+
+	for name, tampered := range map[string]string{
+		"signature":          parts[0] + "." + parts[1] + "." + otherSignature,
+		"truncated":          parts[0] + "." + parts[1] + "." + parts[2][:len(parts[2])-2],
+		"no signature":       parts[0] + "." + parts[1],
+		"incident ID":        incidentB + "." + parts[1] + "." + parts[2],
+		"expiry":             parts[0] + "." + later + "." + parts[2],
+		"garbage":            "not-a-token",
+		"signed with other":  signWith(t, "other-secret", "inc-a", time.Now().Add(time.Hour)),
+		"undecodable expiry": parts[0] + ".!!." + parts[2],
+	} {
+		for _, suffix := range []string{"", "/story", "/timeline"} {
+			rec := serve(routes, http.MethodGet, "/api/shared/"+tampered+suffix, "")
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("%s%s: expected 401, got %d", name, suffix, rec.Code)
+			}
+		}
+	}
+}
+
+// signWith signs a share token with another secret
+func signWith(t *testing.T, secret, incidentID string, expiresAt time.Time) string {
+	t.Helper()
+	h := newTestHandler(t)
+	if err := h.SetShareLinks(secret, 0); err != nil {
+		t.Fatal(err)
+	}
+	return h.signShareToken(incidentID, expiresAt)
+}
+
+func TestShareLink_Expired(t *testing.T) {
+	h, routes := newShareHandler(t)
+	token := h.signShareToken("inc-a", time.Now().Add(-time.Second))
+
+	rec := serve(routes, http.MethodGet, "/api/shared/"+token, "")
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for an expired link, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "expired") {
+		t.Errorf("expected the error to say the link expired, got %s", rec.Body.String())
+	}
+}
+
+func TestShareLink_ScopedToIncident(t *testing.T) {
+	_, routes := newShareHandler(t)
+	link := createShareLink(t, routes, "inc-a", "")
+
+	var detail struct {
+		ID string `json:"id"`
+	}
+	rec := serve(routes, http.MethodGet, link.URL, "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &detail); err != nil || detail.ID != "inc-a" {
+		t.Fatalf("expected the details of inc-a, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	// The signature of inc-a's link doesn't carry over to inc-b's routes
+	parts := strings.Split(link.Token, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte("inc-b")) + "." + parts[1] + "." + parts[2]
+	for _, suffix := range []string{"", "/story", "/timeline"} {
+		if rec := serve(routes, http.MethodGet, "/api/shared/"+forged+suffix, ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("GET inc-b%s with inc-a's link: expected 401, got %d", suffix, rec.Code)
+		}
+	}
+}
+
+func TestShareLink_TTL(t *testing.T) {
+	_, routes := newShareHandler(t)
+
+	for body, want := range map[string]int{
+		`{"ttl": "49h"}`:  http.StatusBadRequest,
+		`{"ttl": "7d"}`:   http.StatusBadRequest,
+		`{"ttl": "-1h"}`:  http.StatusBadRequest,
+		`{"ttl": "soon"}`: http.StatusBadRequest,
+		`{"ttl": "48h"}`:  http.StatusCreated,
+		``:                http.StatusCreated,
+	} {
+		if rec := serve(routes, http.MethodPost, "/api/incidents/inc-a/share", body); rec.Code != want {
+			t.Errorf("share %q: expected %d, got %d: %s", body, want, rec.Code, rec.Body.String())
+		}
+	}
+	if rec := serve(routes, http.MethodPost, "/api/incidents/missing/share", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown incident, got %d", rec.Code)
+	}
+}
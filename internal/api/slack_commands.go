@@ -0,0 +1,225 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/report"
+	"incident-teller/internal/services"
+)
+
+// Slack rejects requests whose timestamp is further off than this to prevent replays
+const slackSignatureMaxAge = 5 * time.Minute
+
+// Slash-command payloads are small form posts
+const slackMaxCommandBody = 64 << 10
+
+const slackCommandUsage = "Usage: `/incident list`, `/incident show <id>`, `/incident ack <id>`, `/incident analyze <id>`"
+
+// SetSlackCommands enables the Slack slash-command endpoint POST /api/slack/commands.
+// Requests must be signed with the app's signing secret.
+func (h *Handler) SetSlackCommands(signingSecret string) {
+	h.slackSecret = signingSecret
+}
+
+// Acknowledgements returns the tracker holding the incidents acknowledged with
+// /incident ack, e.g. to stop escalating them
+func (h *Handler) Acknowledgements() *services.AcknowledgementTracker {
+	return h.acks
+}
+
+// handleSlackCommand handles /incident slash commands and answers with Block Kit messages
+func (h *Handler) handleSlackCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.slackSecret == "" {
+		h.writeError(w, http.StatusNotFound, "Slack commands not enabled")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, slackMaxCommandBody))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := verifySlackSignature(h.slackSecret, r.Header, body, time.Now()); err != nil {
+		h.logger.Warn("Rejected Slack command", observability.Error(err))
+		h.writeError(w, http.StatusUnauthorized, "Invalid Slack signature")
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid form payload")
+		return
+	}
+
+	user := form.Get("user_name")
+	if user == "" {
+		user = form.Get("user_id")
+	}
+	auditActor(r, user)
+	args := strings.Fields(form.Get("text"))
+
+	doc, responseType := h.runSlackCommand(r.Context(), args, user)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.WriteString(w, report.Slack{}.RenderResponse(doc, responseType)); err != nil {
+		h.logger.Error("Failed to write Slack command response", observability.Error(err))
+	}
+}
+
+// runSlackCommand executes a subcommand and returns the response document and whether
+// it is shown to the channel ("in_channel") or only to the user ("ephemeral")
+func (h *Handler) runSlackCommand(ctx context.Context, args []string, user string) (report.Document, string) {
+	if len(args) == 0 || args[0] == "help" {
+		return slackMessage(slackCommandUsage), "ephemeral"
+	}
+
+	now := time.Now()
+	if args[0] == "list" {
+		incidents, err := h.repo.GetIncidents(ctx)
+		if err != nil {
+			h.logger.Error("Failed to get incidents", observability.Error(err))
+			return slackMessage("⚠️ Failed to load incidents"), "ephemeral"
+		}
+
+		var open []domain.Incident
+		for _, incident := range incidents {
+			if incident.ResolvedAt == nil {
+				open = append(open, incident)
+			}
+		}
+		sort.Slice(open, func(i, j int) bool { return open[i].StartedAt.After(open[j].StartedAt) })
+
+		title := "Open incidents (" + strconv.Itoa(len(open)) + ")"
+		if len(open) > 10 {
+			title = fmt.Sprintf("Open incidents (10 most recent of %d)", len(open))
+			open = open[:10]
+		}
+		return services.IncidentListDocument(title, open, now), "ephemeral"
+	}
+
+	switch args[0] {
+	case "show", "ack", "analyze":
+	default:
+		return slackMessage("Unknown command `" + args[0] + "`. " + slackCommandUsage), "ephemeral"
+	}
+	if len(args) < 2 {
+		return slackMessage(slackCommandUsage), "ephemeral"
+	}
+	incident, err := h.findIncidentByPrefix(ctx, args[1])
+	if err != nil {
+		return slackMessage("⚠️ " + err.Error()), "ephemeral"
+	}
+
+	switch args[0] {
+	case "show":
+		return services.IncidentDetailDocument(incident, h.incidentAssignee(incident.ID), h.incidentAcknowledgement(incident.ID), now), "ephemeral"
+
+	case "ack":
+		if h.readOnly {
+			return slackMessage("⚠️ Server is running in read-only mode"), "ephemeral"
+		}
+		ack, created := h.acks.Acknowledge(incident.ID, user, now)
+		if !created {
+			return slackMessage(fmt.Sprintf("Incident %s was already acknowledged by %s at %s",
+				incident.ID, ack.By, ack.AcknowledgedAt.UTC().Format(services.ClockLayout))), "ephemeral"
+		}
+		if ackRepo, ok := h.repo.(AcknowledgementRepository); ok {
+			if err := ackRepo.SaveAcknowledgement(ctx, incident.ID, user, now); err != nil {
+				h.logger.Error("Failed to save acknowledgement", observability.Error(err))
+			}
+		}
+		return slackMessage(fmt.Sprintf("✋ %s acknowledged *%s* (ID: %s)", user, incident.Title, incident.ID)), "in_channel"
+
+	case "analyze":
+		if len(incident.Events) == 0 {
+			return slackMessage("Incident " + incident.ID + " has no events to analyze"), "ephemeral"
+		}
+		analyzer := services.NewComprehensiveIncidentAnalyzer()
+		analyzer.SetChangeTracker(h.changes)
+		analyzer.SetPropagationLearner(h.learner)
+		analyzer.SetPlaybooks(h.playbooks)
+		return services.TechnicalReportDocument(analyzer.AnalyzeIncident(incident)), "ephemeral"
+	}
+	return slackMessage(slackCommandUsage), "ephemeral"
+}
+
+// findIncidentByPrefix returns the incident with the given ID or unique ID prefix
+func (h *Handler) findIncidentByPrefix(ctx context.Context, id string) (domain.Incident, error) {
+	incidents, err := h.repo.GetIncidents(ctx)
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		return domain.Incident{}, fmt.Errorf("failed to load incidents")
+	}
+
+	var matches []domain.Incident
+	for _, incident := range incidents {
+		if incident.ID == id {
+			return incident, nil
+		}
+		if strings.HasPrefix(incident.ID, id) {
+			matches = append(matches, incident)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return domain.Incident{}, fmt.Errorf("incident %s not found", id)
+	case 1:
+		return matches[0], nil
+	default:
+		return domain.Incident{}, fmt.Errorf("%d incidents match %s, please give more of the ID", len(matches), id)
+	}
+}
+
+// incidentAcknowledgement returns the acknowledgement of an incident, or nil
+func (h *Handler) incidentAcknowledgement(incidentID string) *services.Acknowledgement {
+	if ack, ok := h.acks.Acknowledgement(incidentID); ok {
+		return &ack
+	}
+	return nil
+}
+
+func slackMessage(text string) report.Document {
+	return report.Document{Sections: []report.Section{{Blocks: []report.Block{report.Paragraph{Text: text}}}}}
+}
+
+// verifySlackSignature checks the X-Slack-Signature header: "v0=" followed by the hex
+// HMAC-SHA256 of "v0:<timestamp>:<body>" keyed with the signing secret
+func verifySlackSignature(secret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing or invalid request timestamp")
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > slackSignatureMaxAge || age < -slackSignatureMaxAge {
+		return fmt.Errorf("request timestamp is too old")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
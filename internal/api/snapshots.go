@@ -0,0 +1,84 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"incident-teller/internal/observability"
+	"incident-teller/internal/snapshot"
+)
+
+// handleSnapshots exports incident bundles as a snapshot (GET) or imports a snapshot (POST)
+func (h *Handler) handleSnapshots(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.exportSnapshot(w, r)
+	case http.MethodPost:
+		h.importSnapshot(w, r)
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (h *Handler) exportSnapshot(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	var ids []string
+	for _, id := range strings.Split(params.Get("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	sanitize := false
+	if v := params.Get("sanitize"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid sanitize: must be true or false")
+			return
+		}
+		sanitize = parsed
+	}
+
+	exported, err := snapshot.Export(r.Context(), h.repo, ids)
+	if err != nil {
+		if errors.Is(err, snapshot.ErrIncidentNotFound) {
+			h.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		h.logger.Error("Failed to export snapshot", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to export snapshot")
+		return
+	}
+	if sanitize {
+		exported = snapshot.Sanitize(exported)
+	}
+
+	filename := fmt.Sprintf("incident-snapshot-%s.json", exported.ExportedAt.Format("20060102-150405"))
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	h.writeJSON(w, http.StatusOK, exported)
+}
+
+func (h *Handler) importSnapshot(w http.ResponseWriter, r *http.Request) {
+	imported, err := snapshot.Read(r.Body)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid snapshot: "+err.Error())
+		return
+	}
+
+	start := time.Now()
+	result, err := snapshot.Import(r.Context(), h.repo, imported)
+	if err != nil {
+		h.logger.Error("Failed to import snapshot", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to import snapshot")
+		return
+	}
+	h.logger.Info("Imported snapshot",
+		observability.Int("incidents", result.Incidents),
+		observability.Int("alerts", result.Alerts),
+		observability.Duration("duration", time.Since(start)))
+	h.writeJSON(w, http.StatusOK, result)
+}
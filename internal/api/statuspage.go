@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"incident-teller/internal/observability"
+	"incident-teller/internal/statuspage"
+)
+
+// SetStatusPage enables the public status page at GET /status and GET /status.json
+func (h *Handler) SetStatusPage(generator *statuspage.Generator) {
+	h.statusPage = generator
+}
+
+// handleStatusPage renders the status page as HTML
+func (h *Handler) handleStatusPage(w http.ResponseWriter, r *http.Request) {
+	page, ok := h.buildStatusPage(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=30")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(statuspage.RenderHTML(page))); err != nil {
+		h.logger.Error("Failed to write status page", observability.Error(err))
+	}
+}
+
+// handleStatusPageJSON returns the status page as JSON
+func (h *Handler) handleStatusPageJSON(w http.ResponseWriter, r *http.Request) {
+	page, ok := h.buildStatusPage(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=30")
+	h.writeJSON(w, http.StatusOK, page)
+}
+
+// buildStatusPage builds the page from the incidents of the history window, writing an
+// error response if that fails
+func (h *Handler) buildStatusPage(w http.ResponseWriter, r *http.Request) (statuspage.Page, bool) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return statuspage.Page{}, false
+	}
+	if h.statusPage == nil {
+		h.writeError(w, http.StatusNotFound, "Status page not enabled")
+		return statuspage.Page{}, false
+	}
+
+	now := time.Now()
+	from := now.AddDate(0, 0, -h.statusPage.Days())
+	incidents, err := h.incidentsInRange(r.Context(), from, now)
+	if err != nil {
+		h.logger.Error("Failed to load incidents for status page", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to build status page")
+		return statuspage.Page{}, false
+	}
+
+	return h.statusPage.Build(incidents, now), true
+}
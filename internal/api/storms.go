@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"incident-teller/internal/services"
+)
+
+// AlertStormResponse represents an alert storm collapsed into a single incident
+type AlertStormResponse struct {
+	IncidentID              string    `json:"incident_id"`
+	StartedAt               time.Time `json:"started_at"`
+	LastAlertAt             time.Time `json:"last_alert_at"`
+	Alerts                  int       `json:"alerts"`
+	Hosts                   int       `json:"hosts"`
+	SuppressedNotifications int       `json:"suppressed_notifications"`
+}
+
+// SetStormDetector sets the detector whose recent storms are reported; nil reports
+// storm detection as disabled
+func (h *Handler) SetStormDetector(detector *services.StormDetector) {
+	h.storms = detector
+}
+
+// handleAlertStorms lists the recent alert storms, the latest first, with the number of
+// incidents whose notifications they suppressed
+func (h *Handler) handleAlertStorms(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	storms := []AlertStormResponse{}
+	for _, storm := range h.storms.Storms() {
+		storms = append(storms, AlertStormResponse{
+			IncidentID:              storm.IncidentID,
+			StartedAt:               storm.StartedAt,
+			LastAlertAt:             storm.LastAlertAt,
+			Alerts:                  storm.Alerts,
+			Hosts:                   storm.Hosts,
+			SuppressedNotifications: storm.Suppressed,
+		})
+	}
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"enabled": h.storms != nil,
+		"storms":  storms,
+	})
+}
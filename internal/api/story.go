@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"incident-teller/internal/observability"
+	"incident-teller/internal/services"
+)
+
+// StoryResponse is the narrative of an incident in the requested tone and language
+type StoryResponse struct {
+	IncidentID  string                  `json:"incident_id"`
+	Tone        string                  `json:"tone"`
+	Locale      string                  `json:"locale"`
+	TimeZone    string                  `json:"time_zone"` // Zone the story's times are given in
+	Summary     string                  `json:"summary"`
+	Timeline    string                  `json:"timeline"`
+	RootCause   string                  `json:"root_cause"`
+	Impact      string                  `json:"impact"`
+	Fix         RecommendationsResponse `json:"fix"`
+	GeneratedAt time.Time               `json:"generated_at"`
+}
+
+// handleIncidentStory tells the story of an incident, in the tone, locale and time zone
+// given by the query
+func (h *Handler) handleIncidentStory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	voice, err := services.ParseStoryVoice(r.URL.Query().Get("tone"), r.URL.Query().Get("locale"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	location, msg := requestTimeZone(r)
+	if msg != "" {
+		h.writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	voice.Location = location
+
+	incident, err := h.findIncident(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+	if incident == nil {
+		h.writeError(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+
+	story := h.incidentTeller().TellIncidentStoryIn(*incident, voice)
+	h.writeJSON(w, http.StatusOK, StoryResponse{
+		IncidentID: incident.ID,
+		Tone:       voice.Tone,
+		Locale:     voice.Locale,
+		TimeZone:   location.String(),
+		Summary:    story.Summary,
+		Timeline:   story.Timeline,
+		RootCause:  story.RootCause,
+		Impact:     story.Impact,
+		Fix: RecommendationsResponse{
+			Immediate:  story.Fix.ImmediateActions,
+			ShortTerm:  story.Fix.ShortTermActions,
+			LongTerm:   story.Fix.LongTermActions,
+			RunbookURL: story.Fix.RunbookURL,
+		},
+		GeneratedAt: story.GeneratedAt,
+	})
+}
+
+// incidentTeller creates a storyteller using the handler's change events, learned
+// propagation patterns and playbooks
+func (h *Handler) incidentTeller() *services.IncidentTeller {
+	teller := services.NewIncidentTeller()
+	teller.SetChangeTracker(h.changes)
+	teller.SetPropagationLearner(h.learner)
+	teller.SetPlaybooks(h.playbooks)
+	return teller
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"incident-teller/internal/domain"
+	"incident-teller/internal/templates"
+)
+
+// IncidentTemplateResponse is the known failure mode an incident matched
+type IncidentTemplateResponse struct {
+	ID          string   `json:"id"`
+	RunbookURL  string   `json:"runbook_url,omitempty"`
+	Remediation []string `json:"remediation,omitempty"`
+}
+
+// SetTemplates makes incident details and analyses show the known remediation of the
+// template an incident matched
+func (h *Handler) SetTemplates(set *templates.Set) {
+	h.templates = set
+}
+
+// incidentTemplate returns the template an incident was created from, if it is still
+// configured
+func (h *Handler) incidentTemplate(incident domain.Incident) *IncidentTemplateResponse {
+	t, ok := h.templates.Get(incident.Template)
+	if !ok {
+		return nil
+	}
+	return &IncidentTemplateResponse{ID: t.ID, RunbookURL: t.RunbookURL, Remediation: t.Remediation}
+}
+
+// applyTemplate replaces the generic immediate actions of an incident's analysis with the
+// known remediation of its template
+func (h *Handler) applyTemplate(incident domain.Incident, analysis *AIAnalysisResponse) {
+	t := h.incidentTemplate(incident)
+	if t == nil || analysis == nil {
+		return
+	}
+	if len(t.Remediation) > 0 {
+		analysis.Recommendations.Immediate = append([]string(nil), t.Remediation...)
+	}
+	if t.RunbookURL != "" {
+		analysis.Recommendations.RunbookURL = t.RunbookURL
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"incident-teller/internal/observability"
+	"incident-teller/internal/services"
+)
+
+// TicketResponse describes the tracker ticket filed for an incident
+type TicketResponse struct {
+	IncidentID string     `json:"incident_id"`
+	Tracker    string     `json:"tracker"`
+	Key        string     `json:"key"`
+	URL        string     `json:"url"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// SetTicketManager enables /api/incidents/{id}/ticket and ticket URLs on incidents
+func (h *Handler) SetTicketManager(manager *services.TicketManager) {
+	h.tickets = manager
+}
+
+// handleIncidentTicket returns (GET) or files (POST) the ticket of an incident
+func (h *Handler) handleIncidentTicket(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if h.tickets == nil {
+		h.writeError(w, http.StatusNotFound, "Ticketing not enabled")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	incident, err := h.findIncident(ctx, r.PathValue("id"))
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+	if incident == nil {
+		h.writeError(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		ticket, err := h.tickets.Ticket(ctx, incident.ID)
+		if err != nil {
+			h.logger.Error("Failed to get ticket", observability.Error(err))
+			h.writeError(w, http.StatusInternalServerError, "Failed to get ticket")
+			return
+		}
+		if ticket == nil {
+			h.writeError(w, http.StatusNotFound, "No ticket filed for this incident")
+			return
+		}
+		h.writeJSON(w, http.StatusOK, TicketResponse(*ticket))
+		return
+	}
+
+	ticket, created, err := h.tickets.CreateTicket(ctx, *incident)
+	if err != nil {
+		h.logger.Error("Failed to create ticket",
+			observability.String("incident_id", incident.ID), observability.Error(err))
+		h.writeError(w, http.StatusBadGateway, "Failed to create ticket")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+		h.logger.Info("Ticket created",
+			observability.String("incident_id", incident.ID), observability.String("url", ticket.URL))
+	}
+	h.writeJSON(w, status, TicketResponse(ticket))
+}
+
+// incidentTicketURL returns the URL of the incident's ticket, if one was filed
+func (h *Handler) incidentTicketURL(ctx context.Context, incidentID string) string {
+	if h.tickets == nil {
+		return ""
+	}
+	ticket, err := h.tickets.Ticket(ctx, incidentID)
+	if err != nil || ticket == nil {
+		return ""
+	}
+	return ticket.URL
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/ports"
+)
+
+// changeLookback is how long before an incident deployments and config changes are still
+// shown on its exported timeline
+const changeLookback = 30 * time.Minute
+
+// TimelineExportEvent is one event of an exported incident timeline
+type TimelineExportEvent struct {
+	Timestamp time.Time
+	Type      string
+	Severity  string
+	Message   string
+	Actor     string // Who changed the priority or state, or the system that reported a change event
+}
+
+var timelineExportColumns = []string{"timestamp", "type", "severity", "message", "since_start", "actor"}
+
+// handleIncidentTimelineExport downloads an incident's timeline, with notes, escalations,
+// priority and state changes and change events, as CSV or as an iCalendar file. CSV
+// timestamps are in the requested time zone; iCalendar times are always UTC.
+func (h *Handler) handleIncidentTimelineExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	location, msg := requestTimeZone(r)
+	if msg != "" {
+		h.writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "ics" {
+		h.writeError(w, http.StatusBadRequest, "Invalid format: must be csv or ics")
+		return
+	}
+
+	ctx := r.Context()
+	incident, err := h.findIncident(ctx, r.PathValue("id"))
+	if err != nil {
+		h.logger.Error("Failed to get incidents", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+	if incident == nil {
+		h.writeError(w, http.StatusNotFound, "Incident not found")
+		return
+	}
+
+	events := h.timelineExportEvents(ctx, incident)
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=incident-%s-timeline.%s", incident.ID, format))
+	if format == "ics" {
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		writeTimelineICS(w, *incident, events, time.Now().UTC())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	cw.Write(timelineExportColumns)
+	for _, event := range events {
+		cw.Write([]string{
+			event.Timestamp.In(location).Format(time.RFC3339),
+			event.Type,
+			event.Severity,
+			event.Message,
+			event.Timestamp.Sub(incident.StartedAt).String(),
+			event.Actor,
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		h.logger.Error("Failed to write timeline export", observability.Error(err))
+	}
+}
+
+// timelineExportEvents merges the incident's timeline with its priority changes and the
+// changes deployed to its hosts from changeLookback before it started until it ended
+func (h *Handler) timelineExportEvents(ctx context.Context, incident *domain.Incident) []TimelineExportEvent {
+	var events []TimelineExportEvent
+	for _, event := range h.incidentTimeline(ctx, incident) {
+		events = append(events, TimelineExportEvent{
+			Timestamp: event.Timestamp,
+			Type:      event.Type,
+			Severity:  event.Severity,
+			Message:   event.Message,
+			Actor:     event.Source,
+		})
+	}
+
+	if store, ok := h.repo.(ports.PriorityStore); ok {
+		changes, err := store.GetPriorityChanges(ctx, incident.ID)
+		if err != nil {
+			h.logger.Error("Failed to get priority changes",
+				observability.String("incident_id", incident.ID), observability.Error(err))
+		}
+		for _, change := range changes {
+			priority := string(change.Priority)
+			if priority == "" {
+				priority = "auto"
+			}
+			message := fmt.Sprintf("Priority changed to %s", priority)
+			if change.Reason != "" {
+				message += ": " + change.Reason
+			}
+			events = append(events, TimelineExportEvent{
+				Timestamp: change.ChangedAt,
+				Type:      "PRIORITY_CHANGED",
+				Severity:  "info",
+				Message:   message,
+				Actor:     change.ChangedBy,
+			})
+		}
+	}
+	if store, ok := h.repo.(ports.IncidentStateStore); ok {
+		transitions, err := store.GetStateTransitions(ctx, incident.ID)
+		if err != nil {
+			h.logger.Error("Failed to get state transitions",
+				observability.String("incident_id", incident.ID), observability.Error(err))
+		}
+		for _, transition := range transitions {
+			message := fmt.Sprintf("State changed from %s to %s", transition.From, transition.To)
+			if transition.Note != "" {
+				message += ": " + transition.Note
+			}
+			events = append(events, TimelineExportEvent{
+				Timestamp: transition.ChangedAt,
+				Type:      "STATE_CHANGED",
+				Severity:  "info",
+				Message:   message,
+				Actor:     transition.ChangedBy,
+			})
+		}
+	}
+
+	end := time.Now()
+	if incident.ResolvedAt != nil {
+		end = *incident.ResolvedAt
+	}
+	for _, change := range h.changes.ChangesBefore(incident.Events, end, end.Sub(incident.StartedAt)+changeLookback) {
+		message := string(change.Type)
+		if change.Service != "" {
+			message += " of " + change.Service
+		}
+		if change.Version != "" {
+			message += " " + change.Version
+		}
+		if change.Host != "" {
+			message += " on " + change.Host
+		}
+		if change.Description != "" {
+			message += ": " + change.Description
+		}
+		events = append(events, TimelineExportEvent{
+			Timestamp: change.OccurredAt,
+			Type:      "CHANGE",
+			Severity:  "info",
+			Message:   message,
+			Actor:     change.Source,
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+	return events
+}
+
+// writeTimelineICS writes the incident as a calendar event spanning its duration, with one
+// event per timeline entry, so the timeline can be overlaid on a calendar
+func writeTimelineICS(w io.Writer, incident domain.Incident, events []TimelineExportEvent, now time.Time) {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//IncidentTeller//Incident Timeline//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	}
+
+	end := now
+	if incident.ResolvedAt != nil {
+		end = *incident.ResolvedAt
+	}
+	lines = append(lines, icsEvent(incident.ID+"@incident-teller", now, incident.StartedAt, end,
+		"Incident: "+incident.Title, fmt.Sprintf("Incident %s, %d timeline events", incident.ID, len(events)))...)
+
+	for i, event := range events {
+		description := event.Message
+		if event.Actor != "" {
+			description += "\nBy: " + event.Actor
+		}
+		lines = append(lines, icsEvent(fmt.Sprintf("%s-%d@incident-teller", incident.ID, i+1), now,
+			event.Timestamp, event.Timestamp, fmt.Sprintf("[%s] %s", event.Type, event.Message), description)...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		io.WriteString(w, foldICSLine(line)+"\r\n")
+	}
+}
+
+func icsEvent(uid string, stamp, start, end time.Time, summary, description string) []string {
+	const layout = "20060102T150405Z"
+	return []string{
+		"BEGIN:VEVENT",
+		"UID:" + uid,
+		"DTSTAMP:" + stamp.UTC().Format(layout),
+		"DTSTART:" + start.UTC().Format(layout),
+		"DTEND:" + end.UTC().Format(layout),
+		"SUMMARY:" + escapeICSText(summary),
+		"DESCRIPTION:" + escapeICSText(description),
+		"END:VEVENT",
+	}
+}
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escapeICSText escapes a value of a TEXT property (RFC 5545, 3.3.11)
+func escapeICSText(text string) string {
+	return icsTextEscaper.Replace(text)
+}
+
+// foldICSLine splits lines longer than 75 octets, continuing them on lines that start with
+// a space, without breaking UTF-8 characters (RFC 5545, 3.1)
+func foldICSLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+
+	var sb strings.Builder
+	width := 0
+	for _, c := range line {
+		size := len(string(c))
+		if width+size > limit {
+			sb.WriteString("\r\n ")
+			width = 1
+		}
+		sb.WriteRune(c)
+		width += size
+	}
+	return sb.String()
+}
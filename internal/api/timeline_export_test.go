@@ -0,0 +1,151 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/ports"
+)
+
+func TestEscapeICSText(t *testing.T) {
+	for text, want := range map[string]string{
+		"plain":               "plain",
+		"a;b,c":               `a\;b\,c`,
+		`C:\logs`:             `C:\\logs`,
+		"first\nsecond":       `first\nsecond`,
+		"first\r\nsecond":     `first\nsecond`,
+		`\;`:                  `\\\;`,
+		"disk, 95%; db-01\n!": `disk\, 95%\; db-01\n!`,
+	} {
+		if got := escapeICSText(text); got != want {
+			t.Errorf("escapeICSText(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	for name, line := range map[string]string{
+		"short":     "SUMMARY:disk full",
+		"limit":     strings.Repeat("a", 75),
+		"ascii":     "DESCRIPTION:" + strings.Repeat("abcdefghij", 20),
+		"two bytes": "SUMMARY:" + strings.Repeat("é", 60),
+		"mixed":     "SUMMARY:" + strings.Repeat("x日本語ü🔥", 20),
+	} {
+		folded := foldICSLine(line)
+		physical := strings.Split(folded, "\r\n")
+		for i, part := range physical {
+			if len(part) > 75 {
+				t.Errorf("%s: line %d has %d octets", name, i, len(part))
+			}
+			if !utf8.ValidString(part) {
+				t.Errorf("%s: line %d splits a UTF-8 character: %q", name, i, part)
+			}
+			if i > 0 && !strings.HasPrefix(part, " ") {
+				t.Errorf("%s: continuation line %d doesn't start with a space", name, i)
+			}
+		}
+		if len(line) <= 75 && len(physical) != 1 {
+			t.Errorf("%s: expected a line of %d octets not to be folded", name, len(line))
+		}
+		if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != line {
+			t.Errorf("%s: unfolding doesn't restore the line: %q", name, unfolded)
+		}
+	}
+}
+
+func TestWriteTimelineICS(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+	resolved := start.Add(90 * time.Minute)
+	incident := domain.Incident{ID: "inc-a", Title: "disk full; db-01, replica", StartedAt: start, ResolvedAt: &resolved}
+	events := []TimelineExportEvent{{
+		Timestamp: start.Add(time.Minute),
+		Type:      "NOTE",
+		Message:   "rotated logs in C:\\logs\nthen " + strings.Repeat("ü", 50),
+		Actor:     "alice",
+	}}
+
+	var buf bytes.Buffer
+	writeTimelineICS(&buf, incident, events, start.Add(2*time.Hour))
+	out := buf.String()
+
+	if !strings.HasSuffix(out, "END:VCALENDAR\r\n") || strings.Contains(strings.ReplaceAll(out, "\r\n", ""), "\n") {
+		t.Fatalf("expected CRLF line endings only, got %q", out)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > 75 || !utf8.ValidString(line) {
+			t.Errorf("invalid content line %q", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	for _, want := range []string{
+		"DTSTART:20240301T090000Z\r\n",
+		"DTEND:20240301T103000Z\r\n",
+		`SUMMARY:Incident: disk full\; db-01\, replica` + "\r\n",
+		`SUMMARY:[NOTE] rotated logs in C:\\logs\nthen ` + strings.Repeat("ü", 50) + "\r\n",
+		`DESCRIPTION:rotated logs in C:\\logs\nthen ` + strings.Repeat("ü", 50) + `\nBy: alice` + "\r\n",
+		"UID:inc-a-1@incident-teller\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("expected %q in\n%s", want, unfolded)
+		}
+	}
+	if begins, ends := strings.Count(out, "BEGIN:VEVENT"), strings.Count(out, "END:VEVENT"); begins != 2 || ends != 2 {
+		t.Errorf("expected the incident and one timeline event, got %d/%d", begins, ends)
+	}
+}
+
+func TestTimelineExportCSV(t *testing.T) {
+	start := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	h := newTestHandler(t, domain.Incident{ID: "inc-a", StartedAt: start, Events: []domain.Alert{
+		{ID: "a1", Host: "web-01", Status: domain.StatusCritical, OccurredAt: start}}})
+	note := "rolled back \"v2\", then\nwatched p99"
+	err := h.repo.(ports.IncidentStateStore).SetIncidentState(context.Background(), domain.StateTransition{
+		IncidentID: "inc-a", From: domain.StateDetected, To: domain.StateMitigating,
+		ChangedBy: "bob, on call", Note: note, ChangedAt: start.Add(5 * time.Minute),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.changes.Record(domain.ChangeEvent{Host: "web-01", Version: "v2", Source: "ci", OccurredAt: start.Add(-10 * time.Minute)})
+	h.changes.Record(domain.ChangeEvent{Host: "web-09", Version: "v3", Source: "ci", OccurredAt: start.Add(-10 * time.Minute)})
+
+	rec := serve(h.SetupRoutes(), http.MethodGet, "/api/incidents/inc-a/timeline/export?format=csv&tz=Europe/Berlin", "")
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("expected a CSV download, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("export doesn't parse as CSV: %v", err)
+	}
+	if strings.Join(rows[0], ",") != strings.Join(timelineExportColumns, ",") {
+		t.Errorf("unexpected header %v", rows[0])
+	}
+	byType := map[string][]string{}
+	for _, row := range rows[1:] {
+		byType[row[1]] = row
+	}
+
+	state := byType["STATE_CHANGED"]
+	if state == nil || state[3] != "State changed from detected to mitigating: "+note || state[5] != "bob, on call" {
+		t.Errorf("expected the quoted note and actor to round-trip, got %q", state)
+	}
+	if state != nil && state[4] != "5m0s" {
+		t.Errorf("expected the time since start, got %q", state[4])
+	}
+	if _, err := time.Parse(time.RFC3339, rows[1][0]); err != nil || strings.HasSuffix(rows[1][0], "Z") {
+		t.Errorf("expected RFC 3339 timestamps in Europe/Berlin, got %q", rows[1][0])
+	}
+	change := byType["CHANGE"]
+	if change == nil || !strings.Contains(change[3], "v2 on web-01") || strings.Contains(rec.Body.String(), "web-09") {
+		t.Errorf("expected only the change on the incident's host, got %q", change)
+	}
+}
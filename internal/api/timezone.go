@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"incident-teller/internal/services"
+)
+
+// requestTimeZone returns the time zone a client wants stories and reports rendered in:
+// the ?tz= parameter, else the Accept-Timezone header, else UTC. Both take an IANA name
+// such as Europe/Berlin or a UTC offset such as +05:30. On invalid input it returns a
+// message suitable for a 400 response.
+func requestTimeZone(r *http.Request) (*time.Location, string) {
+	name := r.URL.Query().Get("tz")
+	if name == "" {
+		name = r.Header.Get("Accept-Timezone")
+	}
+	location, err := services.ParseTimeZone(name)
+	if err != nil {
+		return nil, "Invalid time zone: " + err.Error()
+	}
+	return location, ""
+}
@@ -0,0 +1,330 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/exporter"
+	"incident-teller/internal/idgen"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/ports"
+)
+
+// Dead letters listed when ?limit= is not given
+const defaultDeadLetterLimit = 100
+
+// WebhookRequest is the body accepted by POST /api/webhooks and PUT /api/webhooks/{id}
+type WebhookRequest struct {
+	URL     string   `json:"url"`
+	Secret  *string  `json:"secret,omitempty"`  // HMAC signing key; omitted on PUT keeps the current one, "" removes it
+	Events  []string `json:"events,omitempty"`  // Event types to deliver; empty delivers all
+	Enabled *bool    `json:"enabled,omitempty"` // Defaults to true
+}
+
+// WebhookResponse is an outgoing webhook. The secret is never returned.
+type WebhookResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Enabled   bool      `json:"enabled"`
+	Signed    bool      `json:"signed"` // Deliveries carry an HMAC-SHA256 signature
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FailedDeliveryResponse is a webhook delivery that failed every attempt
+type FailedDeliveryResponse struct {
+	ID         string    `json:"id"`
+	WebhookID  string    `json:"webhook_id"`
+	EventType  string    `json:"event_type"`
+	Payload    string    `json:"payload"`
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"status_code,omitempty"` // Of the last attempt
+	Error      string    `json:"error"`
+	FailedAt   time.Time `json:"failed_at"`
+}
+
+// SetNagiosWebhook enables POST /api/webhooks/nagios, which passes Nagios/Icinga check
+// results to the receiver acting as the alert source
+func (h *Handler) SetNagiosWebhook(receiver http.Handler) {
+	h.nagios = receiver
+}
+
+// handleNagiosWebhook forwards check results to the Nagios receiver
+func (h *Handler) handleNagiosWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.nagios == nil {
+		h.writeError(w, http.StatusNotFound, "Nagios webhook not enabled")
+		return
+	}
+	h.nagios.ServeHTTP(w, r)
+}
+
+// SetWebhooks enables /api/webhooks, managing the webhooks the publisher delivers to
+func (h *Handler) SetWebhooks(publisher *exporter.WebhookPublisher) {
+	h.webhooks = publisher
+}
+
+// webhookStore returns the store of the outgoing webhooks, or writes a 404 if webhooks
+// aren't enabled
+func (h *Handler) webhookStore(w http.ResponseWriter) (ports.WebhookStore, bool) {
+	store, ok := h.repo.(ports.WebhookStore)
+	if h.webhooks == nil || !ok {
+		h.writeError(w, http.StatusNotFound, "Webhooks not enabled")
+		return nil, false
+	}
+	return store, true
+}
+
+// handleWebhooks lists (GET) or creates (POST) outgoing webhooks
+func (h *Handler) handleWebhooks(w http.ResponseWriter, r *http.Request) {
+	store, ok := h.webhookStore(w)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		webhooks, err := store.GetWebhooks(r.Context())
+		if err != nil {
+			h.logger.Error("Failed to get webhooks", observability.Error(err))
+			h.writeError(w, http.StatusInternalServerError, "Failed to get webhooks")
+			return
+		}
+		response := make([]WebhookResponse, len(webhooks))
+		for i, webhook := range webhooks {
+			response[i] = convertWebhookToResponse(webhook)
+		}
+		h.writeJSON(w, http.StatusOK, response)
+
+	case http.MethodPost:
+		now := time.Now().UTC()
+		h.putWebhook(w, r, store, domain.Webhook{ID: idgen.New(now), CreatedAt: now}, nil)
+
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// handleWebhook returns (GET), replaces (PUT) or deletes (DELETE) an outgoing webhook
+func (h *Handler) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	store, ok := h.webhookStore(w)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	existing, err := store.GetWebhook(ctx, r.PathValue("id"))
+	if err != nil {
+		h.logger.Error("Failed to get webhook", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get webhook")
+		return
+	}
+	if existing == nil {
+		h.writeError(w, http.StatusNotFound, "Webhook not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.writeJSON(w, http.StatusOK, convertWebhookToResponse(*existing))
+
+	case http.MethodPut:
+		previous := convertWebhookToResponse(*existing)
+		h.putWebhook(w, r, store, *existing, &previous)
+
+	case http.MethodDelete:
+		if err := store.DeleteWebhook(ctx, existing.ID); err != nil {
+			h.logger.Error("Failed to delete webhook", observability.Error(err))
+			h.writeError(w, http.StatusInternalServerError, "Failed to delete webhook")
+			return
+		}
+		auditChange(r, convertWebhookToResponse(*existing), nil)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// putWebhook applies the request body to webhook, stores it and responds with it: 201 for
+// a new webhook, whose previous state is nil
+func (h *Handler) putWebhook(w http.ResponseWriter, r *http.Request, store ports.WebhookStore, webhook domain.Webhook, previous *WebhookResponse) {
+	var req WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := validateWebhookRequest(req); err != nil {
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	webhook.URL = req.URL
+	webhook.Events = req.Events
+	webhook.Enabled = req.Enabled == nil || *req.Enabled
+	if req.Secret != nil {
+		webhook.Secret = *req.Secret
+	}
+	webhook.UpdatedAt = time.Now().UTC()
+
+	if err := store.SaveWebhook(r.Context(), webhook); err != nil {
+		h.logger.Error("Failed to save webhook", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to save webhook")
+		return
+	}
+
+	updated := convertWebhookToResponse(webhook)
+	status := http.StatusOK
+	if previous == nil {
+		status = http.StatusCreated
+		auditChange(r, nil, updated)
+	} else {
+		auditChange(r, *previous, updated)
+	}
+	h.writeJSON(w, status, updated)
+}
+
+// validateWebhookRequest checks the URL and event filter of a webhook
+func validateWebhookRequest(req WebhookRequest) error {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	for _, event := range req.Events {
+		known := false
+		for _, eventType := range exporter.EventTypes {
+			known = known || event == string(eventType)
+		}
+		if !known {
+			return fmt.Errorf("unknown event type %q", event)
+		}
+	}
+	return nil
+}
+
+// handleWebhookDeadLetters lists the failed deliveries, newest first, of one webhook if
+// ?webhook= is set
+func (h *Handler) handleWebhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	store, ok := h.webhookStore(w)
+	if !ok {
+		return
+	}
+
+	limit := defaultDeadLetterLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 1000 {
+			h.writeError(w, http.StatusBadRequest, "Invalid limit: must be between 1 and 1000")
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := store.GetFailedDeliveries(r.Context(), r.URL.Query().Get("webhook"), limit)
+	if err != nil {
+		h.logger.Error("Failed to get failed deliveries", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get failed deliveries")
+		return
+	}
+	response := make([]FailedDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		response[i] = FailedDeliveryResponse{
+			ID:         d.ID,
+			WebhookID:  d.WebhookID,
+			EventType:  d.EventType,
+			Payload:    d.Payload,
+			Attempts:   d.Attempts,
+			StatusCode: d.StatusCode,
+			Error:      d.Error,
+			FailedAt:   d.FailedAt,
+		}
+	}
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+// handleWebhookDeadLetter discards (DELETE) a failed delivery
+func (h *Handler) handleWebhookDeadLetter(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	store, delivery, ok := h.findDeadLetter(w, r)
+	if !ok {
+		return
+	}
+
+	if err := store.DeleteFailedDelivery(r.Context(), delivery.ID); err != nil {
+		h.logger.Error("Failed to delete failed delivery", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to delete failed delivery")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleWebhookRedeliver removes a failed delivery and delivers it again in the background
+func (h *Handler) handleWebhookRedeliver(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	_, delivery, ok := h.findDeadLetter(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.webhooks.Redeliver(r.Context(), *delivery); err != nil {
+		if errors.Is(err, exporter.ErrWebhookNotFound) {
+			h.writeError(w, http.StatusNotFound, "Webhook not found")
+			return
+		}
+		h.logger.Error("Failed to redeliver webhook", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to redeliver")
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// findDeadLetter returns the failed delivery named by the id path value, or writes an error
+func (h *Handler) findDeadLetter(w http.ResponseWriter, r *http.Request) (ports.WebhookStore, *domain.FailedDelivery, bool) {
+	store, ok := h.webhookStore(w)
+	if !ok {
+		return nil, nil, false
+	}
+	delivery, err := store.GetFailedDelivery(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.logger.Error("Failed to get failed delivery", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to get failed delivery")
+		return nil, nil, false
+	}
+	if delivery == nil {
+		h.writeError(w, http.StatusNotFound, "Failed delivery not found")
+		return nil, nil, false
+	}
+	return store, delivery, true
+}
+
+func convertWebhookToResponse(webhook domain.Webhook) WebhookResponse {
+	events := webhook.Events
+	if events == nil {
+		events = []string{}
+	}
+	return WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    events,
+		Enabled:   webhook.Enabled,
+		Signed:    webhook.Secret != "",
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"incident-teller/internal/observability"
+	"incident-teller/internal/ports"
+	"incident-teller/internal/report"
+	"incident-teller/internal/services"
+)
+
+// WeeklyMTTRResponse is the MTTR of one week of the trend
+type WeeklyMTTRResponse struct {
+	WeekStart   time.Time `json:"week_start"`
+	Incidents   int       `json:"incidents"`
+	Resolved    int       `json:"resolved"`
+	MTTRSeconds float64   `json:"mttr_seconds"`
+}
+
+// ActionItemResponse is an open follow-up of an incident: a recorded action item, with
+// description, owner and due date, or an open tracker ticket
+type ActionItemResponse struct {
+	IncidentID  string     `json:"incident_id"`
+	Title       string     `json:"title"` // Title of the incident
+	Description string     `json:"description,omitempty"`
+	Owner       string     `json:"owner,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	Tracker     string     `json:"tracker,omitempty"`
+	Key         string     `json:"key,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	OpenedAt    time.Time  `json:"opened_at"`
+}
+
+// WeeklyReportResponse is the JSON form of the weekly reliability report
+type WeeklyReportResponse struct {
+	From          time.Time             `json:"from"`
+	To            time.Time             `json:"to"`
+	Incidents     int                   `json:"incidents"`
+	Resolved      int                   `json:"resolved"`
+	BySeverity    []DigestCountResponse `json:"by_severity"`
+	MTTRSeconds   float64               `json:"mttr_seconds"`
+	MTTRTrend     []WeeklyMTTRResponse  `json:"mttr_trend"` // Oldest week first
+	TopRootCauses []DigestCountResponse `json:"top_root_causes"`
+	NoisiestHosts []DigestCountResponse `json:"noisiest_hosts"`
+	ActionItems   []ActionItemResponse  `json:"action_items"`
+}
+
+// handleWeeklyReport returns the weekly reliability report of the week before ?to=
+// (default now) as Markdown, PDF or JSON, in the requested time zone
+func (h *Handler) handleWeeklyReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	switch format {
+	case "", "md":
+		format = string(report.FormatMarkdown)
+	case string(report.FormatMarkdown), string(report.FormatPDF), "json":
+	default:
+		h.writeError(w, http.StatusBadRequest, "Invalid format, use markdown, pdf or json")
+		return
+	}
+
+	location, msg := requestTimeZone(r)
+	if msg != "" {
+		h.writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	to := time.Now().In(location)
+	if v := r.URL.Query().Get("to"); v != "" {
+		parsed, dateOnly, err := parseExportTime(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid to: must be RFC3339 or YYYY-MM-DD")
+			return
+		}
+		to = parsed.In(location)
+		if dateOnly {
+			// A bare date includes the whole day, in the requested time zone
+			to = time.Date(parsed.Year(), parsed.Month(), parsed.Day()+1, 0, 0, 0, 0, location)
+		}
+	}
+
+	weekly, err := h.weeklyReport(r.Context(), to)
+	if err != nil {
+		h.logger.Error("Failed to build weekly report", observability.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Failed to build weekly report")
+		return
+	}
+	weekly.Location = location
+
+	if format == "json" {
+		h.writeJSON(w, http.StatusOK, convertWeeklyReportToResponse(weekly))
+		return
+	}
+
+	renderer, _ := report.NewRenderer(format)
+	if renderer.Format() == report.FormatPDF {
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf("attachment; filename=reliability-report-%s.pdf", weekly.To.Format("20060102")))
+	}
+	w.Header().Set("Content-Type", renderer.ContentType())
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(renderer.Render(services.WeeklyReportDocument(weekly)))); err != nil {
+		h.logger.Error("Failed to write weekly report", observability.Error(err))
+	}
+}
+
+// weeklyReport builds the weekly report of the week ending at to
+func (h *Handler) weeklyReport(ctx context.Context, to time.Time) (services.WeeklyReport, error) {
+	incidents, err := h.incidentsInRange(ctx, services.WeeklyReportSince(to), to)
+	if err != nil {
+		return services.WeeklyReport{}, err
+	}
+
+	var actionItems []services.ActionItem
+	tickets, _ := h.repo.(ports.TicketStore)
+	actions, _ := h.repo.(ports.ActionItemStore)
+	if tickets != nil || actions != nil {
+		all, err := h.repo.GetIncidents(ctx)
+		if err != nil {
+			return services.WeeklyReport{}, err
+		}
+		if actionItems, err = services.OpenActionItems(ctx, tickets, actions, all); err != nil {
+			return services.WeeklyReport{}, err
+		}
+	}
+
+	builder := services.NewDigestBuilder()
+	builder.SetPropagationLearner(h.learner)
+	return builder.BuildWeekly(incidents, actionItems, to), nil
+}
+
+func convertWeeklyReportToResponse(weekly services.WeeklyReport) WeeklyReportResponse {
+	counts := func(ranking []services.DigestCount) []DigestCountResponse {
+		result := make([]DigestCountResponse, len(ranking))
+		for i, c := range ranking {
+			result[i] = DigestCountResponse{Name: c.Name, Count: c.Count}
+		}
+		return result
+	}
+
+	trend := make([]WeeklyMTTRResponse, len(weekly.MTTRTrend))
+	for i, week := range weekly.MTTRTrend {
+		trend[i] = WeeklyMTTRResponse{
+			WeekStart:   week.WeekStart,
+			Incidents:   week.Incidents,
+			Resolved:    week.Resolved,
+			MTTRSeconds: week.MTTR.Seconds(),
+		}
+	}
+
+	actionItems := make([]ActionItemResponse, len(weekly.ActionItems))
+	for i, item := range weekly.ActionItems {
+		actionItems[i] = ActionItemResponse{
+			IncidentID:  item.IncidentID,
+			Title:       item.Title,
+			Description: item.Description,
+			Owner:       item.Owner,
+			DueAt:       item.DueAt,
+			Status:      string(item.Status),
+			Tracker:     item.Tracker,
+			Key:         item.Key,
+			URL:         item.URL,
+			OpenedAt:    item.OpenedAt,
+		}
+	}
+
+	return WeeklyReportResponse{
+		From:          weekly.From,
+		To:            weekly.To,
+		Incidents:     weekly.Incidents,
+		Resolved:      weekly.Resolved,
+		BySeverity:    counts(weekly.BySeverity),
+		MTTRSeconds:   weekly.MTTR.Seconds(),
+		MTTRTrend:     trend,
+		TopRootCauses: counts(weekly.TopRootCauses),
+		NoisiestHosts: counts(weekly.NoisiestHosts),
+		ActionItems:   actionItems,
+	}
+}
@@ -0,0 +1,137 @@
+// Package calendar knows when the business is open: business hours, blackout dates
+// such as public holidays, and peak traffic windows. Risk scoring and impact
+// predictions use it so that an incident at 2 PM on a Monday ranks above the same
+// incident at 3 AM on a Saturday.
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+)
+
+// dateLayout is the format of blackout dates
+const dateLayout = "2006-01-02"
+
+var riskLevels = []string{"low", "medium", "high", "critical"}
+
+// Calendar classifies points in time. A nil *Calendar knows nothing, so risk levels
+// and impact texts are left unchanged.
+type Calendar struct {
+	location *time.Location
+	business *Window
+	blackout map[string]bool
+	peaks    []peakWindow
+}
+
+type peakWindow struct {
+	name   string
+	window *Window
+}
+
+// Period describes a point in time on the calendar
+type Period struct {
+	Time          time.Time // In the calendar's time zone
+	BusinessHours bool
+	Blackout      bool   // A blackout date; never business hours
+	PeakWindow    string // Name of the peak traffic window, if any
+}
+
+// FromConfig builds the calendar from the calendar config section. It returns nil if
+// the calendar is disabled.
+func FromConfig(cfg config.CalendarConfig) (*Calendar, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	business, err := ParseWindow(cfg.BusinessHours, cfg.BusinessDays, cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("business hours: %w", err)
+	}
+	c := &Calendar{location: business.location, business: business, blackout: make(map[string]bool)}
+
+	for _, date := range cfg.BlackoutDates {
+		if _, err := time.Parse(dateLayout, date); err != nil {
+			return nil, fmt.Errorf("invalid blackout date %q, expected YYYY-MM-DD", date)
+		}
+		c.blackout[date] = true
+	}
+
+	for i, pc := range cfg.PeakWindows {
+		name := pc.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		window, err := ParseWindow(pc.Hours, pc.Days, cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("peak window %s: %w", name, err)
+		}
+		c.peaks = append(c.peaks, peakWindow{name: name, window: window})
+	}
+
+	return c, nil
+}
+
+// At classifies t
+func (c *Calendar) At(t time.Time) Period {
+	local := t.In(c.location)
+	p := Period{Time: local, Blackout: c.blackout[local.Format(dateLayout)]}
+	p.BusinessHours = !p.Blackout && c.business.Contains(t)
+	for _, peak := range c.peaks {
+		if peak.window.Contains(t) {
+			p.PeakWindow = peak.name
+			break
+		}
+	}
+	return p
+}
+
+// AdjustRisk raises a risk level one step during a peak traffic window and lowers it
+// one step outside business hours. Critical risk is never lowered.
+func (c *Calendar) AdjustRisk(level string, t time.Time) string {
+	if c == nil {
+		return level
+	}
+
+	rank := domain.RiskRank(level)
+	period := c.At(t)
+	switch {
+	case period.PeakWindow != "":
+		rank = min(rank+1, len(riskLevels)-1)
+	case !period.BusinessHours && level != "critical":
+		rank = max(rank-1, 0)
+	}
+	return riskLevels[rank]
+}
+
+// DescribeImpact appends when t falls on the calendar to a business impact text, e.g.
+// "High business impact - users experiencing issues, in business hours (Mon 14:05 CET)"
+func (c *Calendar) DescribeImpact(impact string, t time.Time) string {
+	if c == nil {
+		return impact
+	}
+	return impact + ", " + c.At(t).String()
+}
+
+// String describes the period, e.g.
+// "outside business hours, during peak traffic window checkout (Fri 19:30 CET)"
+func (p Period) String() string {
+	when := p.Time.Format("Mon 15:04 MST")
+
+	var parts []string
+	switch {
+	case p.Blackout:
+		parts = append(parts, "outside business hours (blackout date)")
+	case p.BusinessHours:
+		parts = append(parts, "in business hours")
+	default:
+		parts = append(parts, "outside business hours")
+	}
+	if p.PeakWindow != "" {
+		parts = append(parts, "during peak traffic window "+p.PeakWindow)
+	}
+	return strings.Join(parts, ", ") + " (" + when + ")"
+}
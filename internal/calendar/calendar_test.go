@@ -0,0 +1,57 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"incident-teller/internal/config"
+)
+
+func TestCalendar_AdjustRisk(t *testing.T) {
+	cal, err := FromConfig(config.CalendarConfig{
+		Enabled:       true,
+		Timezone:      "Europe/Berlin",
+		BusinessHours: "09:00-18:00",
+		BusinessDays:  []string{"mon", "tue", "wed", "thu", "fri"},
+		BlackoutDates: []string{"2026-12-25"},
+		PeakWindows:   []config.PeakWindow{{Name: "evening", Hours: "18:00-22:00"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	mondayAfternoon := time.Date(2026, 10, 12, 14, 0, 0, 0, berlin)
+	saturdayNight := time.Date(2026, 10, 17, 3, 0, 0, 0, berlin)
+	christmas := time.Date(2026, 12, 25, 11, 0, 0, 0, berlin)
+	fridayEvening := time.Date(2026, 10, 16, 19, 30, 0, 0, berlin)
+
+	tests := []struct {
+		name  string
+		level string
+		at    time.Time
+		want  string
+	}{
+		{"business hours", "high", mondayAfternoon, "high"},
+		{"outside business hours", "high", saturdayNight, "medium"},
+		{"critical is never lowered", "critical", saturdayNight, "critical"},
+		{"blackout date", "medium", christmas, "low"},
+		{"peak window", "high", fridayEvening, "critical"},
+	}
+	for _, tt := range tests {
+		if got := cal.AdjustRisk(tt.level, tt.at); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+
+	impact := cal.DescribeImpact("High business impact", fridayEvening)
+	if !strings.Contains(impact, "outside business hours, during peak traffic window evening") {
+		t.Errorf("unexpected impact text: %s", impact)
+	}
+
+	var disabled *Calendar
+	if got := disabled.AdjustRisk("high", saturdayNight); got != "high" {
+		t.Errorf("expected a nil calendar to keep the risk level, got %s", got)
+	}
+}
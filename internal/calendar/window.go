@@ -0,0 +1,94 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a daily time range on selected weekdays in a time zone
+type Window struct {
+	start, end int // Minutes since midnight; end < start wraps past midnight
+	allDay     bool
+	days       map[time.Weekday]bool
+	location   *time.Location
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseWindow parses hours like "09:00-18:00" or "22:00-06:00" (empty = all day) on
+// days like ["mon", "tue"] (empty = every day) in an IANA time zone (empty = UTC)
+func ParseWindow(hours string, days []string, timezone string) (*Window, error) {
+	w := &Window{allDay: hours == "", location: time.UTC}
+
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+		}
+		w.location = loc
+	}
+
+	if hours != "" {
+		from, to, ok := strings.Cut(hours, "-")
+		if !ok {
+			return nil, fmt.Errorf("hours must look like 09:00-18:00")
+		}
+		var err error
+		if w.start, err = parseClock(from); err != nil {
+			return nil, err
+		}
+		if w.end, err = parseClock(to); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(days) > 0 {
+		w.days = make(map[time.Weekday]bool)
+		for _, d := range days {
+			day, ok := weekdays[strings.ToLower(d)[:min(3, len(d))]]
+			if !ok {
+				return nil, fmt.Errorf("invalid day %q", d)
+			}
+			w.days[day] = true
+		}
+	}
+
+	return w, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Contains reports whether t falls inside the window
+func (w *Window) Contains(t time.Time) bool {
+	local := t.In(w.location)
+	minute := local.Hour()*60 + local.Minute()
+	day := local.Weekday()
+
+	inHours := w.allDay
+	if !w.allDay {
+		if w.start <= w.end {
+			inHours = minute >= w.start && minute < w.end
+		} else {
+			// Wraps past midnight: the early-morning part belongs to the previous day's window
+			inHours = minute >= w.start || minute < w.end
+			if minute < w.end {
+				day = (day + 6) % 7
+			}
+		}
+	}
+	if !inHours {
+		return false
+	}
+
+	return w.days == nil || w.days[day]
+}
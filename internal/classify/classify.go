@@ -0,0 +1,194 @@
+// Package classify infers the resource type of alerts from config-driven chart rules,
+// falling back to built-in rules for common database, container and application charts
+// and then to the type the alert source inferred. Charts left unclassified are counted,
+// so gaps in the rules show up in the diagnostics.
+package classify
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+)
+
+// maxUnclassified bounds the number of distinct unclassified charts remembered
+const maxUnclassified = 500
+
+// builtinRules classify charts of common services the alert sources don't know about.
+// They run after the configured rules and before the sources' own classification.
+var builtinRules = []config.ResourceRule{
+	{Type: string(domain.ResourceDatabase), ChartPrefixes: []string{
+		"mysql.", "mariadb.", "postgres.", "pgbouncer.", "redis.", "mongodb.", "memcached.",
+		"elasticsearch.", "cassandra.", "couchdb.", "clickhouse.", "proxysql.",
+	}},
+	{Type: string(domain.ResourceContainer), ChartPrefixes: []string{
+		"cgroup_", "k8s_", "k8s_state.", "docker.", "docker_engine.", "podman.",
+	}},
+	{Type: string(domain.ResourceApplication), ChartPrefixes: []string{
+		"web_log.", "nginx.", "nginxplus.", "apache.", "httpcheck.", "phpfpm.", "haproxy.",
+		"tomcat.", "traefik.", "envoy.", "go_expvar.",
+	}},
+}
+
+// rule sets the resource type of alerts on matching charts
+type rule struct {
+	resourceType domain.ResourceType
+	prefixes     []string
+	chart        *regexp.Regexp
+	family       *regexp.Regexp
+}
+
+// UnclassifiedChart is a chart whose alerts no rule or source could classify
+type UnclassifiedChart struct {
+	Chart    string    `json:"chart"`
+	Source   string    `json:"source,omitempty"`
+	Alerts   int       `json:"alerts"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Classifier sets the resource type of alerts. A nil *Classifier leaves alerts unchanged.
+type Classifier struct {
+	rules []rule
+
+	mu           sync.Mutex
+	unclassified map[string]*UnclassifiedChart // source/chart -> counts
+}
+
+// FromConfig builds a classifier from the resources config section, followed by the
+// built-in rules
+func FromConfig(cfg config.ResourcesConfig) (*Classifier, error) {
+	c := &Classifier{unclassified: make(map[string]*UnclassifiedChart)}
+	for i, rc := range cfg.Rules {
+		r, err := buildRule(rc)
+		if err != nil {
+			return nil, fmt.Errorf("resource rule #%d: %w", i+1, err)
+		}
+		c.rules = append(c.rules, r)
+	}
+	for _, rc := range builtinRules {
+		r, err := buildRule(rc)
+		if err != nil {
+			return nil, fmt.Errorf("built-in resource rule %s: %w", rc.Type, err)
+		}
+		c.rules = append(c.rules, r)
+	}
+	return c, nil
+}
+
+func buildRule(rc config.ResourceRule) (rule, error) {
+	resourceType, err := domain.ParseResourceType(rc.Type)
+	if err != nil {
+		return rule{}, err
+	}
+	r := rule{resourceType: resourceType, prefixes: rc.ChartPrefixes}
+	if len(rc.ChartPrefixes) == 0 && rc.Chart == "" && rc.Family == "" {
+		return r, fmt.Errorf("chart_prefixes, chart or family is required")
+	}
+	if rc.Chart != "" {
+		if r.chart, err = regexp.Compile(rc.Chart); err != nil {
+			return r, fmt.Errorf("invalid chart pattern %q: %w", rc.Chart, err)
+		}
+	}
+	if rc.Family != "" {
+		if r.family, err = regexp.Compile(rc.Family); err != nil {
+			return r, fmt.Errorf("invalid family pattern %q: %w", rc.Family, err)
+		}
+	}
+	return r, nil
+}
+
+func (r rule) matches(alert domain.Alert) bool {
+	if len(r.prefixes) > 0 {
+		found := false
+		for _, prefix := range r.prefixes {
+			found = found || strings.HasPrefix(alert.Chart, prefix)
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.chart != nil && !r.chart.MatchString(alert.Chart) {
+		return false
+	}
+	return r.family == nil || r.family.MatchString(alert.Family)
+}
+
+// Apply sets the resource type of the first matching rule, else keeps the alert's own.
+// Alerts still of unknown type are counted by chart.
+func (c *Classifier) Apply(alert domain.Alert) domain.Alert {
+	if c == nil {
+		return alert
+	}
+
+	for _, r := range c.rules {
+		if r.matches(alert) {
+			alert.ResourceType = r.resourceType
+			return alert
+		}
+	}
+
+	if alert.ResourceType == "" || alert.ResourceType == domain.ResourceUnknown {
+		alert.ResourceType = domain.ResourceUnknown
+		c.recordUnclassified(alert)
+	}
+	return alert
+}
+
+// ApplyAll applies the classifier to every alert
+func (c *Classifier) ApplyAll(alerts []domain.Alert) []domain.Alert {
+	if c == nil {
+		return alerts
+	}
+	result := make([]domain.Alert, len(alerts))
+	for i, alert := range alerts {
+		result[i] = c.Apply(alert)
+	}
+	return result
+}
+
+func (c *Classifier) recordUnclassified(alert domain.Alert) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := alert.Source + "/" + alert.Chart
+	chart, ok := c.unclassified[key]
+	if !ok {
+		if len(c.unclassified) >= maxUnclassified {
+			return
+		}
+		chart = &UnclassifiedChart{Chart: alert.Chart, Source: alert.Source}
+		c.unclassified[key] = chart
+	}
+	chart.Alerts++
+	if alert.OccurredAt.After(chart.LastSeen) {
+		chart.LastSeen = alert.OccurredAt
+	}
+}
+
+// Unclassified returns the charts whose alerts were left of unknown resource type since
+// startup, the most frequent first
+func (c *Classifier) Unclassified() []UnclassifiedChart {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	charts := make([]UnclassifiedChart, 0, len(c.unclassified))
+	for _, chart := range c.unclassified {
+		charts = append(charts, *chart)
+	}
+	c.mu.Unlock()
+
+	sort.Slice(charts, func(i, j int) bool {
+		if charts[i].Alerts != charts[j].Alerts {
+			return charts[i].Alerts > charts[j].Alerts
+		}
+		return charts[i].Chart < charts[j].Chart
+	})
+	return charts
+}
@@ -0,0 +1,54 @@
+package classify
+
+import (
+	"testing"
+
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+)
+
+func TestClassifier_Apply(t *testing.T) {
+	classifier, err := FromConfig(config.ResourcesConfig{Rules: []config.ResourceRule{
+		{Type: "application", ChartPrefixes: []string{"checkout."}},
+		{Type: "DATABASE", Chart: `^redis\.`, Family: "^replication$"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		alert domain.Alert
+		want  domain.ResourceType
+	}{
+		{domain.Alert{Chart: "checkout.latency"}, domain.ResourceApplication},
+		// The configured rule requires the family; the built-in one classifies the rest
+		{domain.Alert{Chart: "redis.connections", Family: "connections"}, domain.ResourceDatabase},
+		{domain.Alert{Chart: "memcached.cache", ResourceType: domain.ResourceMemory}, domain.ResourceDatabase},
+		{domain.Alert{Chart: "cgroup_api.mem_usage", ResourceType: domain.ResourceMemory}, domain.ResourceContainer},
+		{domain.Alert{Chart: "system.cpu", ResourceType: domain.ResourceCPU}, domain.ResourceCPU},
+		{domain.Alert{Chart: "custom.queue", Source: "netdata"}, domain.ResourceUnknown},
+		{domain.Alert{Chart: "custom.queue", Source: "netdata", ResourceType: domain.ResourceUnknown}, domain.ResourceUnknown},
+	}
+	for _, tt := range tests {
+		if got := classifier.Apply(tt.alert).ResourceType; got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.alert.Chart, tt.want, got)
+		}
+	}
+
+	unclassified := classifier.Unclassified()
+	if len(unclassified) != 1 || unclassified[0].Chart != "custom.queue" || unclassified[0].Alerts != 2 {
+		t.Fatalf("expected custom.queue unclassified twice, got %+v", unclassified)
+	}
+}
+
+func TestFromConfig_InvalidRules(t *testing.T) {
+	for _, rc := range []config.ResourceRule{
+		{Type: "QUEUE", ChartPrefixes: []string{"rabbitmq."}},
+		{Type: "DATABASE"},
+		{Type: "DATABASE", Chart: "("},
+	} {
+		if _, err := FromConfig(config.ResourcesConfig{Rules: []config.ResourceRule{rc}}); err == nil {
+			t.Errorf("expected %+v to be rejected", rc)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -15,10 +16,34 @@ import (
 type Config struct {
 	Server        ServerConfig        `yaml:"server" envPrefix:"SERVER_"`
 	Netdata       NetdataConfig       `yaml:"netdata" envPrefix:"NETDATA_"`
+	Zabbix        ZabbixConfig        `yaml:"zabbix" envPrefix:"ZABBIX_"`
+	Nagios        NagiosConfig        `yaml:"nagios" envPrefix:"NAGIOS_"`
+	Ingestion     IngestionConfig     `yaml:"ingestion" envPrefix:"INGESTION_"`
 	AI            AIConfig            `yaml:"ai" envPrefix:"AI_"`
 	Database      DatabaseConfig      `yaml:"database" envPrefix:"DB_"`
 	Observability ObservabilityConfig `yaml:"observability" envPrefix:"OBSERVABILITY_"`
 	Incident      IncidentConfig      `yaml:"incident" envPrefix:"INCIDENT_"`
+	Notifications NotificationsConfig `yaml:"notifications" envPrefix:"NOTIFY_"`
+	OnCall        OnCallConfig        `yaml:"oncall" envPrefix:"ONCALL_"`
+	Escalation    EscalationConfig    `yaml:"escalation" envPrefix:"ESCALATION_"`
+	Topology      TopologyConfig      `yaml:"topology"`
+	Environments  EnvironmentsConfig  `yaml:"environments" envPrefix:"ENVIRONMENTS_"`
+	Severity      SeverityConfig      `yaml:"severity"`
+	Resources     ResourcesConfig     `yaml:"resources"`
+	Anomaly       AnomalyConfig       `yaml:"anomaly" envPrefix:"ANOMALY_"`
+	Prediction    PredictionConfig    `yaml:"prediction" envPrefix:"PREDICTION_"`
+	StatusPage    StatusPageConfig    `yaml:"status_page" envPrefix:"STATUS_PAGE_"`
+	ChatOps       ChatOpsConfig       `yaml:"chatops" envPrefix:"CHATOPS_"`
+	Digest        DigestConfig        `yaml:"digest" envPrefix:"DIGEST_"`
+	Exporters     ExportersConfig     `yaml:"exporters" envPrefix:"EXPORTERS_"`
+	Ticketing     TicketingConfig     `yaml:"ticketing" envPrefix:"TICKETING_"`
+	Playbooks     PlaybooksConfig     `yaml:"playbooks" envPrefix:"PLAYBOOKS_"`
+	Enrichment    EnrichmentConfig    `yaml:"enrichment" envPrefix:"ENRICHMENT_"`
+	Calendar      CalendarConfig      `yaml:"calendar" envPrefix:"CALENDAR_"`
+	Flapping      FlappingConfig      `yaml:"flapping" envPrefix:"FLAPPING_"`
+	Suppression   SuppressionConfig   `yaml:"suppression" envPrefix:"SUPPRESSION_"`
+	Redaction     RedactionConfig     `yaml:"redaction" envPrefix:"REDACTION_"`
+	Risk          RiskConfig          `yaml:"risk"`
 }
 
 // ServerConfig holds HTTP server configuration
@@ -28,10 +53,71 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `yaml:"read_timeout" env:"READ_TIMEOUT" envDefault:"30s"`
 	WriteTimeout time.Duration `yaml:"write_timeout" env:"WRITE_TIMEOUT" envDefault:"30s"`
 	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"IDLE_TIMEOUT" envDefault:"120s"`
+
+	// Per-client token bucket (by bearer token, else by IP); 0 disables rate limiting
+	RateLimit         float64 `yaml:"rate_limit" env:"RATE_LIMIT" envDefault:"0"` // Requests per second
+	RateLimitBurst    int     `yaml:"rate_limit_burst" env:"RATE_LIMIT_BURST" envDefault:"20"`
+	TrustProxyHeaders bool    `yaml:"trust_proxy_headers" env:"TRUST_PROXY_HEADERS" envDefault:"false"` // Client IP from X-Forwarded-For, scheme from X-Forwarded-Proto
+	MaxBodyBytes      int64   `yaml:"max_body_bytes" env:"MAX_BODY_BYTES" envDefault:"1048576"`         // 0 disables the limit
+
+	// Log every API request with its route, status, latency and payload sizes; health probes
+	// log at debug level. Per-route request metrics are recorded either way.
+	AccessLog bool `yaml:"access_log" env:"ACCESS_LOG" envDefault:"true"`
+
+	// Browser origins allowed to call the API; requests from other origins are rejected
+	CORSAllowedOrigins   []string      `yaml:"cors_allowed_origins" env:"CORS_ALLOWED_ORIGINS" envDefault:"*"`
+	CORSAllowedMethods   []string      `yaml:"cors_allowed_methods" env:"CORS_ALLOWED_METHODS" envDefault:"GET,POST,PUT,DELETE,OPTIONS"`
+	CORSAllowedHeaders   []string      `yaml:"cors_allowed_headers" env:"CORS_ALLOWED_HEADERS" envDefault:"Content-Type,Authorization"`
+	CORSAllowCredentials bool          `yaml:"cors_allow_credentials" env:"CORS_ALLOW_CREDENTIALS" envDefault:"false"` // Requires explicit origins
+	CORSMaxAge           time.Duration `yaml:"cors_max_age" env:"CORS_MAX_AGE" envDefault:"24h"`
+
+	// Serve the embedded web dashboard at GET /
+	Dashboard bool `yaml:"dashboard" env:"DASHBOARD" envDefault:"true"`
+
+	// Port of the gRPC API served alongside REST; 0 disables it
+	GRPCPort int `yaml:"grpc_port" env:"GRPC_PORT" envDefault:"0"`
+	// Serve read-only GraphQL queries at /api/graphql
+	GraphQL bool `yaml:"graphql" env:"GRAPHQL" envDefault:"true"`
+
+	// Bearer token for /api/admin endpoints; empty disables them
+	AdminToken string `yaml:"admin_token" env:"ADMIN_TOKEN"`
+	// Signs the read-only incident share links of POST /api/incidents/{id}/share; random per
+	// process if empty, so links stop working on restart
+	ShareSecret string        `yaml:"share_secret" env:"SHARE_SECRET"`
+	ShareMaxTTL time.Duration `yaml:"share_max_ttl" env:"SHARE_MAX_TTL" envDefault:"168h"` // Longest a link may be valid; 0 = unlimited
+	// Allow injecting faults into the pipeline through /api/admin/faults (needs admin_token)
+	FaultInjection bool `yaml:"fault_injection" env:"FAULT_INJECTION" envDefault:"false"`
+	// How often the config file is checked for changes to reload; 0 disables watching
+	ConfigWatchInterval time.Duration `yaml:"config_watch_interval" env:"CONFIG_WATCH_INTERVAL" envDefault:"30s"`
+
+	// Reject every mutating API request while alerts are still ingested, exposing only the
+	// viewing surface; database.read_only also stops the pipeline from writing
+	ReadOnly bool `yaml:"read_only" env:"READ_ONLY" envDefault:"false"`
+	// Subsystems to turn off, e.g. {ai: false, exports: false}; unlisted ones stay enabled
+	Features map[string]bool `yaml:"features"`
+}
+
+// Subsystems that can be turned off through server.features
+const (
+	FeatureAI            = "ai"            // AI model and the analysis endpoints
+	FeatureNotifications = "notifications" // Incident notifications to the configured channels
+	FeatureSSE           = "sse"           // Server-sent events at /api/events
+	FeatureExports       = "exports"       // Bulk incident and metrics exports
+)
+
+// Features lists the subsystems server.features can turn off
+var Features = []string{FeatureAI, FeatureNotifications, FeatureSSE, FeatureExports}
+
+// FeatureEnabled reports whether a subsystem is on; features not listed in
+// server.features are
+func (c ServerConfig) FeatureEnabled(feature string) bool {
+	enabled, ok := c.Features[feature]
+	return !ok || enabled
 }
 
 // NetdataConfig holds Netdata API configuration
 type NetdataConfig struct {
+	Enabled      bool          `yaml:"enabled" env:"ENABLED" envDefault:"true"`
 	BaseURL      string        `yaml:"base_url" env:"BASE_URL" envDefault:"http://localhost:19999"`
 	Timeout      time.Duration `yaml:"timeout" env:"TIMEOUT" envDefault:"30s"`
 	RetryCount   int           `yaml:"retry_count" env:"RETRY_COUNT" envDefault:"3"`
@@ -40,6 +126,20 @@ type NetdataConfig struct {
 	Hostname     string        `yaml:"hostname" env:"HOSTNAME" envDefault:"localhost"`
 	BatchSize    int           `yaml:"batch_size" env:"BATCH_SIZE" envDefault:"100"`
 
+	// Polling pauses for CircuitCooldown after CircuitFailures failed polls in a row, then
+	// resumes after successful probe polls; 0 failures disables the circuit breaker
+	CircuitFailures int           `yaml:"circuit_failures" env:"CIRCUIT_FAILURES" envDefault:"5"`
+	CircuitCooldown time.Duration `yaml:"circuit_cooldown" env:"CIRCUIT_COOLDOWN" envDefault:"1m"`
+
+	// When an incident forms, attach the values of each alert's chart over SampleWindow
+	// before it fired, in at most SamplePoints points, from the local agent; 0 disables it
+	SamplePoints int           `yaml:"sample_points" env:"SAMPLE_POINTS" envDefault:"12"`
+	SampleWindow time.Duration `yaml:"sample_window" env:"SAMPLE_WINDOW" envDefault:"10m"`
+
+	// Ingestion mode: "poll" fetches every PollInterval, "stream" pushes alerts as they happen
+	Mode           string        `yaml:"mode" env:"MODE" envDefault:"poll"`
+	StreamInterval time.Duration `yaml:"stream_interval" env:"STREAM_INTERVAL" envDefault:"1s"`
+
 	// Cloud support configuration
 	CloudEnabled bool     `yaml:"cloud_enabled" env:"CLOUD_ENABLED" envDefault:"false"`
 	CloudToken   string   `yaml:"cloud_token" env:"CLOUD_TOKEN"`
@@ -47,18 +147,89 @@ type NetdataConfig struct {
 	CloudRooms   []string `yaml:"cloud_rooms" env:"CLOUD_ROOMS"`
 }
 
+// ZabbixConfig holds Zabbix API configuration. When enabled, Zabbix is polled alongside
+// the other enabled alert sources.
+type ZabbixConfig struct {
+	Enabled      bool          `yaml:"enabled" env:"ENABLED" envDefault:"false"`
+	URL          string        `yaml:"url" env:"URL"` // e.g. https://zabbix.example.com/api_jsonrpc.php
+	APIToken     string        `yaml:"api_token" env:"API_TOKEN"`
+	Username     string        `yaml:"username" env:"USERNAME"` // Used when no API token is set
+	Password     string        `yaml:"password" env:"PASSWORD"`
+	Timeout      time.Duration `yaml:"timeout" env:"TIMEOUT" envDefault:"30s"`
+	PollInterval time.Duration `yaml:"poll_interval" env:"POLL_INTERVAL" envDefault:"30s"`
+}
+
+// NagiosConfig holds the Nagios/Icinga check result webhook configuration. When enabled,
+// results posted to /api/webhooks/nagios are ingested alongside the other alert sources.
+type NagiosConfig struct {
+	Enabled      bool          `yaml:"enabled" env:"ENABLED" envDefault:"false"`
+	Token        string        `yaml:"token" env:"TOKEN"`                              // Required on every webhook request
+	Hostname     string        `yaml:"hostname" env:"HOSTNAME" envDefault:"localhost"` // Default hostname if a result has none
+	BufferSize   int           `yaml:"buffer_size" env:"BUFFER_SIZE" envDefault:"1000"`
+	PollInterval time.Duration `yaml:"poll_interval" env:"POLL_INTERVAL" envDefault:"10s"`
+}
+
+// IngestionConfig toggles the background ingestion of the enabled alert sources. With
+// both disabled the API only serves what is already stored.
+type IngestionConfig struct {
+	Poller   bool `yaml:"poller" env:"POLLER" envDefault:"true"`     // Poll the alert sources and persist incidents
+	Backfill bool `yaml:"backfill" env:"BACKFILL" envDefault:"true"` // Correlate stored alerts into incidents on startup
+
+	// On the first run, store the alert history of sources that keep one (the Netdata
+	// alarm log) back to HistoryWindow, in batches of HistoryChunk; 0 disables
+	HistoryWindow time.Duration `yaml:"history_window" env:"HISTORY_WINDOW" envDefault:"24h"`
+	HistoryChunk  time.Duration `yaml:"history_chunk" env:"HISTORY_CHUNK" envDefault:"1h"`
+
+	// With several replicas on one database, only the holder of a lease in the database
+	// polls the alert sources; the others serve the API and take over when it expires
+	LeaderElection bool          `yaml:"leader_election" env:"LEADER_ELECTION" envDefault:"false"`
+	LeaseTTL       time.Duration `yaml:"lease_ttl" env:"LEASE_TTL" envDefault:"15s"`
+	ReplicaID      string        `yaml:"replica_id" env:"REPLICA_ID"` // Defaults to hostname-pid
+
+	// Raise an "ingestion stalled" incident when no alert source polled successfully for
+	// this long; 0 disables the watchdog
+	StallThreshold time.Duration `yaml:"stall_threshold" env:"STALL_THRESHOLD" envDefault:"10m"`
+
+	// Stored alert batches wait in a queue of QueueSize batches for one of Workers to
+	// correlate, persist and notify them. When the queue is full, "block" holds back the
+	// pollers and "drop" skips correlating the batch; its alerts stay stored either way.
+	Workers      int    `yaml:"workers" env:"WORKERS" envDefault:"2"`
+	QueueSize    int    `yaml:"queue_size" env:"QUEUE_SIZE" envDefault:"100"`
+	Backpressure string `yaml:"backpressure" env:"BACKPRESSURE" envDefault:"block"` // block or drop
+
+	// Each alert keeps the payload its source sent, cut to this many bytes; 0 keeps none
+	RawPayloadMaxBytes int `yaml:"raw_payload_max_bytes" env:"RAW_PAYLOAD_MAX_BYTES" envDefault:"16384"`
+}
+
 // AIConfig holds AI/ML configuration
 type AIConfig struct {
-	Enabled             bool               `yaml:"enabled" env:"ENABLED" envDefault:"true"`
-	ModelType           string             `yaml:"model_type" env:"MODEL_TYPE" envDefault:"local"`
-	APIToken            string             `yaml:"api_token" env:"API_TOKEN"`
-	APIEndpoint         string             `yaml:"api_endpoint" env:"API_ENDPOINT"`
-	ConfidenceThreshold float64            `yaml:"confidence_threshold" env:"CONFIDENCE_THRESHOLD" envDefault:"0.7"`
-	MaxPredictions      int                `yaml:"max_predictions" env:"MAX_PREDICTIONS" envDefault:"5"`
-	PredictionTimeout   time.Duration      `yaml:"prediction_timeout" env:"PREDICTION_TIMEOUT" envDefault:"10s"`
-	EnableLearning      bool               `yaml:"enable_learning" env:"ENABLE_LEARNING" envDefault:"false"`
-	ModelPath           string             `yaml:"model_path" env:"MODEL_PATH" envDefault:"./models"`
-	OpenAI              OpenAIConfig       `yaml:"openai"`
+	Enabled             bool          `yaml:"enabled" env:"ENABLED" envDefault:"true"`
+	ModelType           string        `yaml:"model_type" env:"MODEL_TYPE" envDefault:"local"` // local, openai, hybrid or remote
+	APIToken            string        `yaml:"api_token" env:"API_TOKEN"`
+	APIEndpoint         string        `yaml:"api_endpoint" env:"API_ENDPOINT"` // Scoring service of the remote model type
+	RemoteRetries       int           `yaml:"remote_retries" env:"REMOTE_RETRIES" envDefault:"2"`
+	ConfidenceThreshold float64       `yaml:"confidence_threshold" env:"CONFIDENCE_THRESHOLD" envDefault:"0.7"`
+	MaxPredictions      int           `yaml:"max_predictions" env:"MAX_PREDICTIONS" envDefault:"5"`
+	PredictionTimeout   time.Duration `yaml:"prediction_timeout" env:"PREDICTION_TIMEOUT" envDefault:"10s"`
+	EnableLearning      bool          `yaml:"enable_learning" env:"ENABLE_LEARNING" envDefault:"false"`
+	ModelPath           string        `yaml:"model_path" env:"MODEL_PATH" envDefault:"./models"`    // Root cause weight versions, one YAML/JSON file each
+	ModelVersion        string        `yaml:"model_version" env:"MODEL_VERSION" envDefault:"1.0.0"` // Version answering requests; the others run in the shadow
+	OpenAI              OpenAIConfig  `yaml:"openai"`
+
+	// Propagation learning: how issues spread between resources is mined from alert history
+	LearningInterval        time.Duration `yaml:"learning_interval" env:"LEARNING_INTERVAL" envDefault:"1h"`
+	LearningWindow          time.Duration `yaml:"learning_window" env:"LEARNING_WINDOW" envDefault:"10m"`
+	LearningLookback        time.Duration `yaml:"learning_lookback" env:"LEARNING_LOOKBACK" envDefault:"720h"`
+	LearningMinObservations int           `yaml:"learning_min_observations" env:"LEARNING_MIN_OBSERVATIONS" envDefault:"5"`
+
+	// Background analysis: created and updated incidents are analyzed by a worker pool
+	AnalysisWorkers   int           `yaml:"analysis_workers" env:"ANALYSIS_WORKERS" envDefault:"2"`
+	AnalysisQueueSize int           `yaml:"analysis_queue_size" env:"ANALYSIS_QUEUE_SIZE" envDefault:"100"`
+	AnalysisTimeout   time.Duration `yaml:"analysis_timeout" env:"ANALYSIS_TIMEOUT" envDefault:"30s"`
+
+	// Calibration: root cause confidences are mapped to the accuracy observed from feedback
+	CalibrationBins       int `yaml:"calibration_bins" env:"CALIBRATION_BINS" envDefault:"10"`
+	CalibrationMinSamples int `yaml:"calibration_min_samples" env:"CALIBRATION_MIN_SAMPLES" envDefault:"5"`
 }
 
 // OpenAIConfig holds OpenAI-specific configuration
@@ -70,6 +241,7 @@ type OpenAIConfig struct {
 	MaxTokens   int           `yaml:"max_tokens" env:"OPENAI_MAX_TOKENS" envDefault:"2048"`
 	Temperature float32       `yaml:"temperature" env:"OPENAI_TEMPERATURE" envDefault:"0.7"`
 	TopP        float32       `yaml:"top_p" env:"OPENAI_TOP_P" envDefault:"1.0"`
+	Mode        string        `yaml:"mode" env:"OPENAI_MODE" envDefault:"combined"` // combined: one JSON mode call, multi: one call per section
 }
 
 // DatabaseConfig holds database configuration
@@ -85,12 +257,37 @@ type DatabaseConfig struct {
 	MaxIdleConns    int           `yaml:"max_idle_conns" env:"MAX_IDLE_CONNS" envDefault:"5"`
 	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"CONN_MAX_LIFETIME" envDefault:"1h"`
 	SQLitePath      string        `yaml:"sqlite_path" env:"SQLITE_PATH" envDefault:"./incident_teller.db"`
+	ReadOnly        bool          `yaml:"read_only" env:"READ_ONLY" envDefault:"false"`                 // Snapshot mode: no writes, no polling
+	AutoMigrate     bool          `yaml:"auto_migrate" env:"AUTO_MIGRATE" envDefault:"true"`            // Apply pending schema migrations on startup
+	BatchSize       int           `yaml:"batch_size" env:"BATCH_SIZE" envDefault:"500"`                 // Alerts per multi-row INSERT
+	CacheTTL        time.Duration `yaml:"cache_ttl" env:"CACHE_TTL" envDefault:"30s"`                   // How long incident alerts stay cached; 0 disables the cache
+	CacheMaxEntries int           `yaml:"cache_max_entries" env:"CACHE_MAX_ENTRIES" envDefault:"10000"` // Incidents cached at most; 0 disables the cache
+	RedisAddr       string        `yaml:"redis_addr" env:"REDIS_ADDR" envDefault:"localhost:6379"`
+	RedisPassword   string        `yaml:"redis_password" env:"REDIS_PASSWORD"`
+	RedisDB         int           `yaml:"redis_db" env:"REDIS_DB" envDefault:"0"`
+	RedisKeyPrefix  string        `yaml:"redis_key_prefix" env:"REDIS_KEY_PREFIX" envDefault:"incident-teller:"`
+	RedisTTL        time.Duration `yaml:"redis_ttl" env:"REDIS_TTL" envDefault:"72h"` // 0 keeps data forever
+	MongoURI        string        `yaml:"mongo_uri" env:"MONGO_URI" envDefault:"mongodb://localhost:27017"`
+
+	// Bounds of the memory database, least recently saved evicted first; incidents are
+	// bounded by incident.max_incidents. 0 is unbounded.
+	MemoryMaxAlerts int           `yaml:"memory_max_alerts" env:"MEMORY_MAX_ALERTS" envDefault:"100000"`
+	MemoryRetention time.Duration `yaml:"memory_retention" env:"MEMORY_RETENTION" envDefault:"0"` // Evict older alerts and resolved incidents
+
+	// SQLite tuning for concurrent API reads and poller writes: WAL keeps readers from
+	// blocking the writer, busy_timeout waits for locks instead of failing, and a single
+	// connection serializes writers
+	SQLiteJournalMode  string        `yaml:"sqlite_journal_mode" env:"SQLITE_JOURNAL_MODE" envDefault:"WAL"`
+	SQLiteBusyTimeout  time.Duration `yaml:"sqlite_busy_timeout" env:"SQLITE_BUSY_TIMEOUT" envDefault:"5s"`
+	SQLiteSynchronous  string        `yaml:"sqlite_synchronous" env:"SQLITE_SYNCHRONOUS" envDefault:"NORMAL"`
+	SQLiteMaxOpenConns int           `yaml:"sqlite_max_open_conns" env:"SQLITE_MAX_OPEN_CONNS" envDefault:"1"`
 }
 
 // ObservabilityConfig holds observability configuration
 type ObservabilityConfig struct {
 	LogLevel        string            `yaml:"log_level" env:"LOG_LEVEL" envDefault:"info"`
 	LogFormat       string            `yaml:"log_format" env:"LOG_FORMAT" envDefault:"json"`
+	LogBufferSize   int               `yaml:"log_buffer_size" env:"LOG_BUFFER_SIZE" envDefault:"1000"` // Recent logs kept for /api/logs
 	EnableMetrics   bool              `yaml:"enable_metrics" env:"ENABLE_METRICS" envDefault:"true"`
 	MetricsPort     int               `yaml:"metrics_port" env:"METRICS_PORT" envDefault:"9090"`
 	EnableTracing   bool              `yaml:"enable_tracing" env:"ENABLE_TRACING" envDefault:"false"`
@@ -98,6 +295,17 @@ type ObservabilityConfig struct {
 	ServiceName     string            `yaml:"service_name" env:"SERVICE_NAME" envDefault:"incident-teller"`
 	ServiceVersion  string            `yaml:"service_version" env:"SERVICE_VERSION" envDefault:"1.0.0"`
 	Tags            map[string]string `yaml:"tags" env:"TAGS"`
+
+	// OTLP export of finalized incident timelines as log records
+	EnableOTLPLogs bool              `yaml:"enable_otlp_logs" env:"ENABLE_OTLP_LOGS" envDefault:"false"`
+	OTLPEndpoint   string            `yaml:"otlp_endpoint" env:"OTLP_ENDPOINT" envDefault:"http://localhost:4318"`
+	OTLPHeaders    map[string]string `yaml:"otlp_headers" env:"OTLP_HEADERS"`
+
+	// Health checks taking longer than their timeout fail; the critical ones failing make the
+	// service unhealthy, the others (e.g. netdata) only degraded
+	HealthCheckTimeout   time.Duration            `yaml:"health_check_timeout" env:"HEALTH_CHECK_TIMEOUT" envDefault:"5s"`
+	HealthCheckTimeouts  map[string]time.Duration `yaml:"health_check_timeouts"` // Per check, e.g. netdata: 2s
+	CriticalHealthChecks []string                 `yaml:"critical_health_checks" env:"CRITICAL_HEALTH_CHECKS" envDefault:"database"`
 }
 
 // IncidentConfig holds incident processing configuration
@@ -109,12 +317,446 @@ type IncidentConfig struct {
 	ResolveThreshold  time.Duration `yaml:"resolve_threshold" env:"RESOLVE_THRESHOLD" envDefault:"30m"`
 	EnableAlertDedup  bool          `yaml:"enable_alert_dedup" env:"ENABLE_ALERT_DEDUP" envDefault:"true"`
 	DedupWindow       time.Duration `yaml:"dedup_window" env:"DEDUP_WINDOW" envDefault:"5m"`
+	IDFormat          string        `yaml:"id_format" env:"ID_FORMAT" envDefault:"ulid"` // ulid or uuidv7
+
+	// How alerts are partitioned before time-window grouping:
+	// window, host_and_window, service_and_window, workload_and_window, labels_and_window
+	// or group_by
+	CorrelationStrategy string   `yaml:"correlation_strategy" env:"CORRELATION_STRATEGY" envDefault:"window"`
+	CorrelationLabels   []string `yaml:"correlation_labels" env:"CORRELATION_LABELS"` // Label keys for labels_and_window
+
+	// Grouping key for group_by, also used to group alerts on the timeline:
+	// host, chart, name, resource_type or labels.<name>, e.g. [labels.service, host]
+	GroupBy []string `yaml:"group_by" env:"GROUP_BY"`
+
+	// Alert storms: at least StormThreshold alerts within StormWindow are collapsed into a
+	// single "alert storm" incident, and notifications of other incidents starting until
+	// StormCooldown after its last alert are suppressed; 0 disables storm detection
+	StormThreshold int           `yaml:"storm_threshold" env:"STORM_THRESHOLD" envDefault:"100"`
+	StormWindow    time.Duration `yaml:"storm_window" env:"STORM_WINDOW" envDefault:"1m"`
+	StormCooldown  time.Duration `yaml:"storm_cooldown" env:"STORM_COOLDOWN" envDefault:"10m"`
+
+	// Recurring problems: an incident whose host, resource type and alert name combinations
+	// overlap at least this much (Jaccard similarity, 0-1) with those of a resolved incident
+	// is linked to it as an occurrence of the same problem; 0 disables linking
+	RecurrenceThreshold float64 `yaml:"recurrence_threshold" env:"RECURRENCE_THRESHOLD" envDefault:"0.8"`
+
+	// Templates pre-populate incidents of known failure modes; the first template
+	// matching an alert of the incident applies
+	Templates []IncidentTemplate `yaml:"templates"`
+}
+
+// IncidentTemplate describes a known failure mode. AlertName and Chart are glob patterns
+// (e.g. "disk_space._var_log"); every set one must match the same alert.
+type IncidentTemplate struct {
+	ID          string   `yaml:"id"`
+	AlertName   string   `yaml:"alert_name"`
+	Chart       string   `yaml:"chart"`
+	Title       string   `yaml:"title"`    // e.g. "Disk full on {host}: /var/log"; {name}, {chart}, {family} and {labels.<name>} work too
+	Priority    string   `yaml:"priority"` // Default priority, P1-P4; empty follows the risk level
+	RunbookURL  string   `yaml:"runbook_url"`
+	Remediation []string `yaml:"remediation"` // Known fix steps, shown instead of generic analysis output
+
+	// Notification overrides: labels added for notification routes, or no notifications
+	NotificationLabels map[string]string `yaml:"notification_labels"`
+	SkipNotification   bool              `yaml:"skip_notification"`
+}
+
+// FlappingConfig holds flap detection: an alert stream changing between CLEAR and
+// WARNING/CRITICAL at least Threshold times within Window is flapping
+type FlappingConfig struct {
+	Enabled              bool          `yaml:"enabled" env:"ENABLED" envDefault:"true"`
+	Window               time.Duration `yaml:"window" env:"WINDOW" envDefault:"30m"`
+	Threshold            int           `yaml:"threshold" env:"THRESHOLD" envDefault:"4"`
+	ExcludeFromIncidents bool          `yaml:"exclude_from_incidents" env:"EXCLUDE_FROM_INCIDENTS" envDefault:"true"`
+}
+
+// SuppressionConfig holds dependency-based suppression: while an alert reports a host
+// unreachable, the host's other alerts are grouped under it and marked suppressed instead
+// of counting as independent cascade evidence
+type SuppressionConfig struct {
+	Enabled    bool     `yaml:"enabled" env:"ENABLED" envDefault:"true"`
+	RootAlerts []string `yaml:"root_alerts" env:"ROOT_ALERTS"` // Alert names or charts; empty uses the built-in list
+}
+
+// RedactionConfig hides hostnames, IP addresses and sensitive labels from alert context
+// sent to OpenAI and from notifications. The replacement tokens map back to the originals
+// locally, so analyses are shown with the real names.
+type RedactionConfig struct {
+	Enabled       bool     `yaml:"enabled" env:"ENABLED" envDefault:"false"`
+	HashHostnames bool     `yaml:"hash_hostnames" env:"HASH_HOSTNAMES" envDefault:"true"` // web-01 -> host-3f9a2c1e
+	MaskIPs       bool     `yaml:"mask_ips" env:"MASK_IPS" envDefault:"true"`             // 10.0.0.5 -> ip-7b1d04aa
+	LabelDenylist []string `yaml:"label_denylist" env:"LABEL_DENYLIST"`                   // Labels never sent; their values are masked in text
+	HashSecret    string   `yaml:"hash_secret" env:"HASH_SECRET"`                         // Keys the tokens; random per process if empty
+	Notifications bool     `yaml:"notifications" env:"NOTIFICATIONS" envDefault:"true"`   // Redact Slack, Teams and Discord messages
+}
+
+// AnomalyConfig holds alert-volume and novelty anomaly detection configuration
+type AnomalyConfig struct {
+	Enabled        bool          `yaml:"enabled" env:"ENABLED" envDefault:"true"`
+	BucketSize     time.Duration `yaml:"bucket_size" env:"BUCKET_SIZE" envDefault:"5m"`          // Alert rate granularity
+	BaselineWindow time.Duration `yaml:"baseline_window" env:"BASELINE_WINDOW" envDefault:"24h"` // History the baseline rate covers
+	Threshold      float64       `yaml:"threshold" env:"THRESHOLD" envDefault:"3"`               // Standard deviations above baseline
+	MinBurstSize   int           `yaml:"min_burst_size" env:"MIN_BURST_SIZE" envDefault:"5"`
+	Window         time.Duration `yaml:"window" env:"WINDOW" envDefault:"15m"` // Default lookback of GET /api/anomalies
+}
+
+// PredictionConfig holds pre-incident prediction configuration
+type PredictionConfig struct {
+	Enabled       bool          `yaml:"enabled" env:"ENABLED" envDefault:"true"`
+	Interval      time.Duration `yaml:"interval" env:"INTERVAL" envDefault:"1m"`
+	Horizon       time.Duration `yaml:"horizon" env:"HORIZON" envDefault:"30m"`               // Only incidents expected within this are predicted
+	MinConfidence float64       `yaml:"min_confidence" env:"MIN_CONFIDENCE" envDefault:"0.6"` // 0.0-1.0
+	Lookback      time.Duration `yaml:"lookback" env:"LOOKBACK" envDefault:"168h"`            // History used for escalation rates
+}
+
+// StatusPageConfig holds the public status page configuration (GET /status, /status.json)
+type StatusPageConfig struct {
+	Enabled     bool   `yaml:"enabled" env:"ENABLED" envDefault:"false"`
+	Title       string `yaml:"title" env:"TITLE" envDefault:"System Status"`
+	HistoryDays int    `yaml:"history_days" env:"HISTORY_DAYS" envDefault:"90"` // Days of uptime history per component
+}
+
+// ChatOpsConfig holds the Slack slash-command configuration (POST /api/slack/commands)
+type ChatOpsConfig struct {
+	Enabled            bool   `yaml:"enabled" env:"ENABLED" envDefault:"false"`
+	SlackSigningSecret string `yaml:"slack_signing_secret" env:"SLACK_SIGNING_SECRET"` // From the Slack app's Basic Information page
+}
+
+// DigestConfig holds the scheduled incident digest email configuration
+type DigestConfig struct {
+	Enabled    bool          `yaml:"enabled" env:"ENABLED" envDefault:"false"`
+	Period     time.Duration `yaml:"period" env:"PERIOD" envDefault:"168h"`      // Incidents covered by each digest
+	Weekday    string        `yaml:"weekday" env:"WEEKDAY" envDefault:"monday"`  // Empty sends daily
+	SendAt     string        `yaml:"send_at" env:"SEND_AT" envDefault:"09:00"`   // Time of day, HH:MM
+	TimeZone   string        `yaml:"time_zone" env:"TIME_ZONE" envDefault:"UTC"` // IANA name, e.g. Europe/Berlin
+	Recipients []string      `yaml:"recipients" env:"RECIPIENTS"`
+
+	From         string `yaml:"from" env:"FROM"`
+	SMTPHost     string `yaml:"smtp_host" env:"SMTP_HOST"`
+	SMTPPort     int    `yaml:"smtp_port" env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername string `yaml:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword string `yaml:"smtp_password" env:"SMTP_PASSWORD"`
+}
+
+// ParseSchedule returns the weekday (nil for daily), time of day and time zone digests are sent at
+func (d DigestConfig) ParseSchedule() (*time.Weekday, time.Duration, *time.Location, error) {
+	var weekday *time.Weekday
+	if d.Weekday != "" {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.EqualFold(day.String(), d.Weekday) {
+				weekday = &day
+				break
+			}
+		}
+		if weekday == nil {
+			return nil, 0, nil, fmt.Errorf("invalid digest weekday: %s", d.Weekday)
+		}
+	}
+
+	at, err := time.Parse("15:04", d.SendAt)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("invalid digest send_at %q, use HH:MM", d.SendAt)
+	}
+
+	location, err := time.LoadLocation(d.TimeZone)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("invalid digest time zone: %w", err)
+	}
+
+	return weekday, time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute, location, nil
+}
+
+// ExportersConfig holds the incident event exporters for downstream data platforms
+type ExportersConfig struct {
+	Encoding string         `yaml:"encoding" env:"ENCODING" envDefault:"json"`        // json or cloudevents
+	Source   string         `yaml:"source" env:"SOURCE" envDefault:"incident-teller"` // CloudEvents source attribute
+	Kafka    KafkaConfig    `yaml:"kafka" envPrefix:"KAFKA_"`
+	NATS     NATSConfig     `yaml:"nats" envPrefix:"NATS_"`
+	Webhooks WebhooksConfig `yaml:"webhooks" envPrefix:"WEBHOOKS_"`
+}
+
+// KafkaConfig holds the Kafka event exporter configuration
+type KafkaConfig struct {
+	Enabled bool     `yaml:"enabled" env:"ENABLED" envDefault:"false"`
+	Brokers []string `yaml:"brokers" env:"BROKERS" envDefault:"localhost:9092"`
+	Topic   string   `yaml:"topic" env:"TOPIC" envDefault:"incident-teller.events"`
+}
+
+// NATSConfig holds the NATS event exporter configuration
+type NATSConfig struct {
+	Enabled       bool   `yaml:"enabled" env:"ENABLED" envDefault:"false"`
+	URL           string `yaml:"url" env:"URL" envDefault:"nats://localhost:4222"`
+	SubjectPrefix string `yaml:"subject_prefix" env:"SUBJECT_PREFIX" envDefault:"incident-teller"` // Events go to <prefix>.<event type>
+}
+
+// WebhooksConfig holds the delivery settings of the outgoing webhooks managed through
+// /api/webhooks
+type WebhooksConfig struct {
+	Enabled        bool          `yaml:"enabled" env:"ENABLED" envDefault:"false"`
+	MaxAttempts    int           `yaml:"max_attempts" env:"MAX_ATTEMPTS" envDefault:"5"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" env:"INITIAL_BACKOFF" envDefault:"2s"` // Doubled after each failed attempt
+	MaxBackoff     time.Duration `yaml:"max_backoff" env:"MAX_BACKOFF" envDefault:"5m"`
+	Timeout        time.Duration `yaml:"timeout" env:"TIMEOUT" envDefault:"10s"` // Per attempt
+}
+
+// TicketingConfig holds the issue tracker incident tickets are filed in
+type TicketingConfig struct {
+	Tracker     string       `yaml:"tracker" env:"TRACKER"`                            // "jira" or "github"; empty disables tickets
+	AutoCreate  bool         `yaml:"auto_create" env:"AUTO_CREATE" envDefault:"false"` // File tickets as incidents are saved
+	MinSeverity string       `yaml:"min_severity" env:"MIN_SEVERITY" envDefault:"critical"`
+	Jira        JiraConfig   `yaml:"jira" envPrefix:"JIRA_"`
+	GitHub      GitHubConfig `yaml:"github" envPrefix:"GITHUB_"`
+}
+
+// JiraConfig holds the Jira project tickets are filed in
+type JiraConfig struct {
+	BaseURL    string `yaml:"base_url" env:"BASE_URL"` // e.g. https://example.atlassian.net
+	Email      string `yaml:"email" env:"EMAIL"`
+	APIToken   string `yaml:"api_token" env:"API_TOKEN"`
+	Project    string `yaml:"project" env:"PROJECT"`
+	IssueType  string `yaml:"issue_type" env:"ISSUE_TYPE" envDefault:"Bug"`
+	DoneStatus string `yaml:"done_status" env:"DONE_STATUS" envDefault:"Done"` // Transition closing resolved tickets
+}
+
+// GitHubConfig holds the GitHub repository tickets are filed in
+type GitHubConfig struct {
+	APIURL string `yaml:"api_url" env:"API_URL" envDefault:"https://api.github.com"`
+	Repo   string `yaml:"repo" env:"REPO"` // owner/name
+	Token  string `yaml:"token" env:"TOKEN"`
+}
+
+// PlaybooksConfig holds the organization's remediation playbooks
+type PlaybooksConfig struct {
+	Dir string `yaml:"dir" env:"DIR"` // YAML playbook files; empty keeps /api/playbooks changes in memory only
+}
+
+// EnrichmentConfig holds the enrichers adding labels to alerts before they are stored.
+// Static mappings run first, then regex extraction, then the CMDB lookup; a label
+// already set by the source or an earlier enricher is kept.
+type EnrichmentConfig struct {
+	MappingFile string            `yaml:"mapping_file" env:"MAPPING_FILE"` // YAML list of {match, labels}
+	Extract     []LabelExtraction `yaml:"extract"`
+	CMDB        CMDBConfig        `yaml:"cmdb" envPrefix:"CMDB_"`
+	Containers  bool              `yaml:"containers" env:"CONTAINERS" envDefault:"true"` // Container, pod and workload labels
+}
+
+// LabelExtraction turns the named groups of Pattern matched against an alert field
+// into labels
+type LabelExtraction struct {
+	Field   string `yaml:"field"` // chart (default), name, host or family
+	Pattern string `yaml:"pattern"`
+}
+
+// CMDBConfig holds the HTTP service host labels are looked up in
+type CMDBConfig struct {
+	URL      string        `yaml:"url" env:"URL"` // {host} is replaced by the alert host
+	Token    string        `yaml:"token" env:"TOKEN"`
+	CacheTTL time.Duration `yaml:"cache_ttl" env:"CACHE_TTL" envDefault:"10m"`
+	Timeout  time.Duration `yaml:"timeout" env:"TIMEOUT" envDefault:"5s"`
+}
+
+// CalendarConfig holds the business calendar used by risk scoring: incidents rank one
+// level higher during peak traffic windows and one level lower outside business hours
+type CalendarConfig struct {
+	Enabled       bool         `yaml:"enabled" env:"ENABLED" envDefault:"false"`
+	Timezone      string       `yaml:"timezone" env:"TIMEZONE" envDefault:"UTC"` // IANA name
+	BusinessHours string       `yaml:"business_hours" env:"BUSINESS_HOURS" envDefault:"09:00-18:00"`
+	BusinessDays  []string     `yaml:"business_days" env:"BUSINESS_DAYS" envDefault:"mon,tue,wed,thu,fri"`
+	BlackoutDates []string     `yaml:"blackout_dates" env:"BLACKOUT_DATES"` // YYYY-MM-DD; holidays, shutdowns
+	PeakWindows   []PeakWindow `yaml:"peak_windows"`
+}
+
+// PeakWindow is a recurring period of peak traffic
+type PeakWindow struct {
+	Name  string   `yaml:"name"`
+	Hours string   `yaml:"hours"` // e.g. "18:00-22:00"; empty = all day
+	Days  []string `yaml:"days"`  // empty = every day
+}
+
+// NotificationsConfig holds incident notification configuration
+type NotificationsConfig struct {
+	Enabled           bool   `yaml:"enabled" env:"ENABLED" envDefault:"false"`
+	SlackWebhookURL   string `yaml:"slack_webhook_url" env:"SLACK_WEBHOOK_URL"`
+	TeamsWebhookURL   string `yaml:"teams_webhook_url" env:"TEAMS_WEBHOOK_URL"`
+	DiscordWebhookURL string `yaml:"discord_webhook_url" env:"DISCORD_WEBHOOK_URL"`
+
+	// Routes send incidents with matching labels (e.g. an enriched owner) to additional channels
+	Routes []NotificationRoute `yaml:"routes"`
+
+	// Quality gates applied to analysis output before it is sent
+	MinConfidence    int `yaml:"min_confidence" env:"MIN_CONFIDENCE" envDefault:"40"`
+	MinSummaryLength int `yaml:"min_summary_length" env:"MIN_SUMMARY_LENGTH" envDefault:"20"`
+	MaxSummaryLength int `yaml:"max_summary_length" env:"MAX_SUMMARY_LENGTH" envDefault:"2000"`
+
+	// How often incident action items past their due date are checked and reported; 0 disables
+	OverdueActionItemsInterval time.Duration `yaml:"overdue_action_items_interval" env:"OVERDUE_ACTION_ITEMS_INTERVAL" envDefault:"1h"`
+}
+
+// NotificationRoute sends incidents whose labels include all of Labels and satisfy all of
+// Matchers to its channels
+type NotificationRoute struct {
+	Labels            map[string]string `yaml:"labels"`
+	Matchers          []string          `yaml:"matchers"` // e.g. service=~"checkout|payments", env!="staging"
+	SlackWebhookURL   string            `yaml:"slack_webhook_url"`
+	TeamsWebhookURL   string            `yaml:"teams_webhook_url"`
+	DiscordWebhookURL string            `yaml:"discord_webhook_url"`
+}
+
+// OnCallConfig holds the on-call rotation used to auto-assign critical incidents
+type OnCallConfig struct {
+	Enabled       bool           `yaml:"enabled" env:"ENABLED" envDefault:"false"`
+	RotationStart string         `yaml:"rotation_start" env:"ROTATION_START"` // RFC3339; first member's shift starts here
+	ShiftLength   time.Duration  `yaml:"shift_length" env:"SHIFT_LENGTH" envDefault:"168h"`
+	Members       []OnCallMember `yaml:"members"`
+}
+
+// OnCallMember is a person in the on-call rotation
+type OnCallMember struct {
+	Name    string `yaml:"name"`
+	Email   string `yaml:"email"`
+	SlackID string `yaml:"slack_id"`
+}
+
+// EscalationConfig holds the policies paging further targets while an incident stays
+// unacknowledged
+type EscalationConfig struct {
+	Enabled       bool               `yaml:"enabled" env:"ENABLED" envDefault:"false"`
+	CheckInterval time.Duration      `yaml:"check_interval" env:"CHECK_INTERVAL" envDefault:"1m"`
+	Policies      []EscalationPolicy `yaml:"policies"`
+}
+
+// EscalationPolicy escalates the unacknowledged incidents of a priority through Levels
+type EscalationPolicy struct {
+	Priority string            `yaml:"priority"` // "P1" to "P4"
+	Levels   []EscalationLevel `yaml:"levels"`
+}
+
+// EscalationLevel is a notification target paged once an incident has been
+// unacknowledged for After since it started
+type EscalationLevel struct {
+	After             time.Duration `yaml:"after"`
+	OnCallSecondary   bool          `yaml:"oncall_secondary"` // Page the member after the current one in the rotation
+	SlackWebhookURL   string        `yaml:"slack_webhook_url"`
+	TeamsWebhookURL   string        `yaml:"teams_webhook_url"`
+	DiscordWebhookURL string        `yaml:"discord_webhook_url"`
+}
+
+// TopologyConfig maps hosts to logical services and their dependencies
+type TopologyConfig struct {
+	Services []TopologyService `yaml:"services"`
+}
+
+// TopologyService is a logical service in the topology
+type TopologyService struct {
+	Name      string   `yaml:"name"`
+	Hosts     []string `yaml:"hosts"`
+	DependsOn []string `yaml:"depends_on"`
+}
+
+// EnvironmentsConfig groups hosts into environments such as prod, staging and dev. Alerts
+// of different environments never join the same incident.
+type EnvironmentsConfig struct {
+	Enabled  bool                 `yaml:"enabled" env:"ENABLED" envDefault:"false"`
+	Label    string               `yaml:"label" env:"LABEL" envDefault:"env"` // Alert label holding the environment; a value set by the source wins over the mappings
+	Default  string               `yaml:"default" env:"DEFAULT"`              // Environment of hosts no mapping matches; empty leaves them without one
+	Mappings []EnvironmentMapping `yaml:"mappings"`
+}
+
+// EnvironmentMapping assigns the hosts matching any of its glob patterns to an environment;
+// the first matching mapping wins
+type EnvironmentMapping struct {
+	Name  string   `yaml:"name"`
+	Hosts []string `yaml:"hosts"`
+}
+
+// SeverityConfig holds alert severity normalization and mapping rules, applied before storage
+type SeverityConfig struct {
+	// Aliases map source-specific status names (e.g. "warn", "crit", "ok") onto
+	// CLEAR, WARNING, CRITICAL, REMOVED or UNDEFINED; they extend the built-in aliases
+	Aliases map[string]string `yaml:"aliases"`
+	// Rules are evaluated in order; the first matching rule adjusts the alert status
+	Rules []SeverityRule `yaml:"rules"`
+}
+
+// SeverityRule changes the status of matching alerts, optionally only at certain times
+type SeverityRule struct {
+	Name      string        `yaml:"name"`
+	Match     SeverityMatch `yaml:"match"`
+	Hours     string        `yaml:"hours"`      // e.g. "09:00-18:00" or "22:00-06:00"; empty = all day
+	Days      []string      `yaml:"days"`       // e.g. ["mon", "tue"]; empty = every day
+	Timezone  string        `yaml:"timezone"`   // IANA name for Hours/Days; default UTC
+	SetStatus string        `yaml:"set_status"` // WARNING or CRITICAL
+	Adjust    string        `yaml:"adjust"`     // upgrade or downgrade by one level
+}
+
+// SeverityMatch selects alerts. Name, Chart, Family and Host are glob patterns;
+// every label must match (value may be a glob). Empty fields match everything.
+type SeverityMatch struct {
+	Name   string            `yaml:"name"`
+	Chart  string            `yaml:"chart"`
+	Family string            `yaml:"family"`
+	Host   string            `yaml:"host"`
+	Status []string          `yaml:"status"` // Only alerts currently in one of these statuses
+	Labels map[string]string `yaml:"labels"`
+}
+
+// ResourcesConfig holds the rules classifying alerts by resource type, applied before storage
+type ResourcesConfig struct {
+	// Rules are evaluated in order, before the built-in rules; the first matching rule
+	// sets the resource type. Alerts no rule matches keep the type their source inferred.
+	Rules []ResourceRule `yaml:"rules"`
+}
+
+// ResourceRule sets the resource type of alerts on matching charts. Chart and Family are
+// regular expressions; every given condition must match.
+type ResourceRule struct {
+	Type          string   `yaml:"type"`           // CPU, MEMORY, DISK, NETWORK, PROCESS, DATABASE, CONTAINER or APPLICATION
+	ChartPrefixes []string `yaml:"chart_prefixes"` // e.g. ["mysql.", "postgres."]; any of them
+	Chart         string   `yaml:"chart"`
+	Family        string   `yaml:"family"`
+}
+
+// RiskConfig holds the thresholds incidents are classified into risk levels with. An
+// incident reaches a level when it reaches any of the level's thresholds; 0 disables one.
+type RiskConfig struct {
+	Medium   RiskThresholdsConfig `yaml:"medium"`
+	High     RiskThresholdsConfig `yaml:"high"`
+	Critical RiskThresholdsConfig `yaml:"critical"`
+	// Per resource type (CPU, MEMORY, DISK, ...) weights and value cutoffs
+	Resources map[string]ResourceRiskConfig `yaml:"resources"`
+}
+
+// RiskThresholdsConfig holds the counts from which an incident reaches a risk level
+type RiskThresholdsConfig struct {
+	CriticalAlerts float64 `yaml:"critical_alerts"` // Weighted by resource type
+	Hosts          int     `yaml:"hosts"`
+	ResourceTypes  int     `yaml:"resource_types"`
+}
+
+// ResourceRiskConfig tunes how alerts of a resource type weigh in an incident's risk
+type ResourceRiskConfig struct {
+	Weight    float64 `yaml:"weight"`     // What a critical alert counts for; default 1
+	HighValue float64 `yaml:"high_value"` // Alerts valued above it count as critical; 0 = never
+}
+
+// DefaultRiskConfig returns the built-in risk thresholds
+func DefaultRiskConfig() RiskConfig {
+	return RiskConfig{
+		Medium:   RiskThresholdsConfig{CriticalAlerts: 1, Hosts: 2},
+		High:     RiskThresholdsConfig{CriticalAlerts: 2, Hosts: 2, ResourceTypes: 2},
+		Critical: RiskThresholdsConfig{CriticalAlerts: 3, Hosts: 3, ResourceTypes: 3},
+	}
 }
 
 // Load loads configuration from file and environment variables
 func Load(configPath string) (*Config, error) {
 	// Start with defaults
 	cfg := &Config{}
+	if err := env.Parse(cfg, env.Options{Environment: map[string]string{}}); err != nil {
+		return nil, fmt.Errorf("failed to apply defaults: %w", err)
+	}
+	cfg.Risk = DefaultRiskConfig()
 
 	// Load from file if provided
 	if configPath != "" {
@@ -123,10 +765,21 @@ func Load(configPath string) (*Config, error) {
 		}
 	}
 
-	// Override with environment variables
-	if err := env.Parse(cfg); err != nil {
+	// Override with environment variables. Only variables that are set override the file;
+	// parsing straight into cfg would reset every file value to its default.
+	fromEnv := &Config{}
+	if err := env.Parse(fromEnv); err != nil {
 		return nil, fmt.Errorf("failed to parse environment variables: %w", err)
 	}
+	overrideFromEnv(reflect.ValueOf(cfg).Elem(), reflect.ValueOf(fromEnv).Elem(), "")
+
+	// Secrets may come from files (<VARIABLE>_FILE) or external secret stores
+	if err := loadSecretFiles(reflect.ValueOf(cfg).Elem(), ""); err != nil {
+		return nil, fmt.Errorf("failed to load secret files: %w", err)
+	}
+	if err := resolveSecrets(cfg); err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
@@ -136,6 +789,24 @@ func Load(configPath string) (*Config, error) {
 	return cfg, nil
 }
 
+// overrideFromEnv copies the fields whose environment variable is set from src to dst,
+// following envPrefix into nested sections
+func overrideFromEnv(dst, src reflect.Value, prefix string) {
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if key, _, _ := strings.Cut(field.Tag.Get("env"), ","); key != "" {
+			if _, set := os.LookupEnv(prefix + key); set {
+				dst.Field(i).Set(src.Field(i))
+			}
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct && field.IsExported() {
+			overrideFromEnv(dst.Field(i), src.Field(i), prefix+field.Tag.Get("envPrefix"))
+		}
+	}
+}
+
 // loadFromFile loads configuration from YAML file
 func loadFromFile(cfg *Config, path string) error {
 	data, err := os.ReadFile(path)
@@ -157,8 +828,43 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("server port must be between 1 and 65535")
 	}
 
+	if c.Server.RateLimit < 0 || c.Server.RateLimitBurst < 0 || c.Server.MaxBodyBytes < 0 {
+		return fmt.Errorf("server rate limit, burst and max body bytes must not be negative")
+	}
+
+	if c.Server.ShareMaxTTL < 0 {
+		return fmt.Errorf("server share_max_ttl must not be negative")
+	}
+
+	if c.Server.FaultInjection && c.Server.AdminToken == "" {
+		return fmt.Errorf("server fault_injection needs an admin_token")
+	}
+
+	for feature := range c.Server.Features {
+		switch feature {
+		case FeatureAI, FeatureNotifications, FeatureSSE, FeatureExports:
+		default:
+			return fmt.Errorf("unsupported server feature: %s (use %s)", feature, strings.Join(Features, ", "))
+		}
+	}
+
+	if c.Server.ConfigWatchInterval < 0 {
+		return fmt.Errorf("server config watch interval must not be negative")
+	}
+
+	if c.Server.CORSAllowCredentials {
+		for _, origin := range c.Server.CORSAllowedOrigins {
+			if origin == "*" {
+				return fmt.Errorf("cors_allow_credentials requires explicit cors_allowed_origins, not *")
+			}
+		}
+	}
+
 	// Validate netdata config
-	if c.Netdata.BaseURL == "" {
+	if !c.Netdata.Enabled && !c.Zabbix.Enabled && !c.Nagios.Enabled {
+		return fmt.Errorf("at least one alert source (netdata, zabbix or nagios) must be enabled")
+	}
+	if c.Netdata.Enabled && c.Netdata.BaseURL == "" {
 		return fmt.Errorf("netdata base URL is required")
 	}
 
@@ -166,15 +872,128 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid netdata timeout format")
 	}
 
+	switch c.Netdata.Mode {
+	case "", "poll", "stream":
+	default:
+		return fmt.Errorf("netdata mode must be poll or stream")
+	}
+	if c.Netdata.RetryCount < 0 || c.Netdata.RetryDelay < 0 {
+		return fmt.Errorf("netdata retry count and delay must not be negative")
+	}
+	if c.Netdata.CircuitFailures < 0 {
+		return fmt.Errorf("netdata circuit failures must not be negative")
+	}
+	if c.Netdata.CircuitFailures > 0 && c.Netdata.CircuitCooldown <= 0 {
+		return fmt.Errorf("netdata circuit cooldown must be positive")
+	}
+	if c.Netdata.SamplePoints < 0 {
+		return fmt.Errorf("netdata sample points must not be negative")
+	}
+	if c.Netdata.SamplePoints > 0 && c.Netdata.SampleWindow <= 0 {
+		return fmt.Errorf("netdata sample window must be positive")
+	}
+
+	if c.Zabbix.Enabled {
+		if c.Zabbix.URL == "" {
+			return fmt.Errorf("zabbix URL is required")
+		}
+		if c.Zabbix.APIToken == "" && (c.Zabbix.Username == "" || c.Zabbix.Password == "") {
+			return fmt.Errorf("zabbix requires an API token or a username and password")
+		}
+		if c.Zabbix.PollInterval <= 0 {
+			return fmt.Errorf("zabbix poll interval must be positive")
+		}
+	}
+	if c.Nagios.Enabled {
+		if c.Nagios.Token == "" {
+			return fmt.Errorf("nagios webhook token is required")
+		}
+		if c.Nagios.PollInterval <= 0 {
+			return fmt.Errorf("nagios poll interval must be positive")
+		}
+	}
+
 	// Validate AI config
 	if c.AI.Enabled {
 		if c.AI.ModelType == "" {
 			return fmt.Errorf("AI model type is required when AI is enabled")
 		}
 
+		if c.AI.ModelType == "remote" {
+			if c.AI.APIEndpoint == "" {
+				return fmt.Errorf("AI api endpoint is required for the remote model type")
+			}
+			if c.AI.RemoteRetries < 0 || c.AI.PredictionTimeout <= 0 {
+				return fmt.Errorf("AI remote retries must not be negative and prediction timeout must be positive")
+			}
+		}
+
 		if c.AI.ConfidenceThreshold < 0 || c.AI.ConfidenceThreshold > 1 {
 			return fmt.Errorf("AI confidence threshold must be between 0 and 1")
 		}
+
+		if c.AI.AnalysisWorkers < 1 || c.AI.AnalysisQueueSize < 1 || c.AI.AnalysisTimeout <= 0 {
+			return fmt.Errorf("AI analysis workers, queue size and timeout must be positive")
+		}
+
+		if c.AI.ModelVersion == "" {
+			return fmt.Errorf("AI model version is required when AI is enabled")
+		}
+
+		if c.AI.CalibrationBins < 1 || c.AI.CalibrationMinSamples < 1 {
+			return fmt.Errorf("AI calibration bins and min samples must be at least 1")
+		}
+
+		if c.AI.OpenAI.Mode != "combined" && c.AI.OpenAI.Mode != "multi" {
+			return fmt.Errorf("invalid OpenAI mode %q: must be combined or multi", c.AI.OpenAI.Mode)
+		}
+	}
+
+	if c.AI.EnableLearning {
+		if c.AI.LearningInterval <= 0 || c.AI.LearningWindow <= 0 {
+			return fmt.Errorf("AI learning interval and window must be positive")
+		}
+		if c.AI.LearningMinObservations < 1 {
+			return fmt.Errorf("AI learning min observations must be at least 1")
+		}
+	}
+
+	if c.Anomaly.Enabled {
+		if c.Anomaly.BucketSize <= 0 || c.Anomaly.BaselineWindow < c.Anomaly.BucketSize {
+			return fmt.Errorf("anomaly baseline window must be at least one positive bucket size")
+		}
+		if c.Anomaly.Threshold <= 0 || c.Anomaly.MinBurstSize < 1 {
+			return fmt.Errorf("anomaly threshold and min burst size must be positive")
+		}
+	}
+
+	if c.Prediction.Enabled {
+		if c.Prediction.Interval <= 0 || c.Prediction.Horizon <= 0 || c.Prediction.Lookback <= 0 {
+			return fmt.Errorf("prediction interval, horizon and lookback must be positive")
+		}
+		if c.Prediction.MinConfidence <= 0 || c.Prediction.MinConfidence > 1 {
+			return fmt.Errorf("prediction min confidence must be between 0 and 1")
+		}
+	}
+
+	if c.StatusPage.Enabled && (c.StatusPage.HistoryDays < 1 || c.StatusPage.HistoryDays > 365) {
+		return fmt.Errorf("status page history days must be between 1 and 365")
+	}
+
+	if c.ChatOps.Enabled && c.ChatOps.SlackSigningSecret == "" {
+		return fmt.Errorf("chatops requires a Slack signing secret")
+	}
+
+	if c.Digest.Enabled {
+		if c.Digest.Period <= 0 {
+			return fmt.Errorf("digest period must be positive")
+		}
+		if len(c.Digest.Recipients) == 0 || c.Digest.From == "" || c.Digest.SMTPHost == "" {
+			return fmt.Errorf("digest requires recipients, a from address and an SMTP host")
+		}
+		if _, _, _, err := c.Digest.ParseSchedule(); err != nil {
+			return err
+		}
 	}
 
 	// Validate database config
@@ -182,6 +1001,13 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("database type is required")
 	}
 
+	if c.Database.BatchSize < 1 || c.Database.BatchSize > 2000 {
+		return fmt.Errorf("database batch size must be between 1 and 2000")
+	}
+	if c.Database.CacheTTL < 0 || c.Database.CacheMaxEntries < 0 {
+		return fmt.Errorf("database cache TTL and max entries cannot be negative")
+	}
+
 	switch c.Database.Type {
 	case "postgres", "postgresql":
 		if c.Database.Host == "" {
@@ -204,12 +1030,227 @@ func (c *Config) Validate() error {
 		if c.Database.SQLitePath == "" {
 			return fmt.Errorf("SQLite path is required")
 		}
+		switch strings.ToUpper(c.Database.SQLiteJournalMode) {
+		case "", "DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF":
+		default:
+			return fmt.Errorf("unsupported SQLite journal mode: %s", c.Database.SQLiteJournalMode)
+		}
+		switch strings.ToUpper(c.Database.SQLiteSynchronous) {
+		case "", "OFF", "NORMAL", "FULL", "EXTRA":
+		default:
+			return fmt.Errorf("unsupported SQLite synchronous setting: %s", c.Database.SQLiteSynchronous)
+		}
+		if c.Database.SQLiteBusyTimeout < 0 || c.Database.SQLiteMaxOpenConns < 1 {
+			return fmt.Errorf("SQLite busy timeout must not be negative and max open connections must be positive")
+		}
+	case "redis":
+		if c.Database.RedisAddr == "" {
+			return fmt.Errorf("redis address is required")
+		}
+		if c.Database.RedisTTL < 0 {
+			return fmt.Errorf("redis TTL must not be negative")
+		}
+	case "mongodb":
+		if c.Database.MongoURI == "" {
+			return fmt.Errorf("mongo URI is required")
+		}
+		if c.Database.Database == "" {
+			return fmt.Errorf("database name is required")
+		}
 	case "memory":
 		// No validation needed for in-memory
 	default:
 		return fmt.Errorf("unsupported database type: %s", c.Database.Type)
 	}
 
+	// Validate ingestion config
+	if c.Ingestion.HistoryWindow < 0 {
+		return fmt.Errorf("ingestion history window must not be negative")
+	}
+	if c.Ingestion.HistoryWindow > 0 && c.Ingestion.HistoryChunk <= 0 {
+		return fmt.Errorf("ingestion history chunk must be positive")
+	}
+	if c.Ingestion.LeaderElection && c.Ingestion.LeaseTTL < 3*time.Second {
+		return fmt.Errorf("ingestion lease TTL must be at least 3s")
+	}
+	if c.Ingestion.StallThreshold < 0 {
+		return fmt.Errorf("ingestion stall threshold must not be negative")
+	}
+	if c.Ingestion.Workers < 1 || c.Ingestion.QueueSize < 1 {
+		return fmt.Errorf("ingestion workers and queue size must be positive")
+	}
+	switch c.Ingestion.Backpressure {
+	case "block", "drop":
+	default:
+		return fmt.Errorf("ingestion backpressure must be block or drop")
+	}
+	if c.Ingestion.RawPayloadMaxBytes < 0 {
+		return fmt.Errorf("ingestion raw payload max bytes must not be negative")
+	}
+
+	// Validate incident config
+	switch c.Incident.IDFormat {
+	case "", "ulid", "uuidv7":
+	default:
+		return fmt.Errorf("incident ID format must be ulid or uuidv7")
+	}
+
+	switch c.Incident.CorrelationStrategy {
+	case "", "window", "host_and_window", "service_and_window", "workload_and_window":
+	case "labels_and_window":
+		if len(c.Incident.CorrelationLabels) == 0 {
+			return fmt.Errorf("correlation strategy labels_and_window needs correlation_labels")
+		}
+	case "group_by":
+		if len(c.Incident.GroupBy) == 0 {
+			return fmt.Errorf("correlation strategy group_by needs group_by fields")
+		}
+	default:
+		return fmt.Errorf("unsupported correlation strategy: %s", c.Incident.CorrelationStrategy)
+	}
+	if c.Incident.StormThreshold < 0 {
+		return fmt.Errorf("incident storm threshold must not be negative")
+	}
+	if c.Incident.StormThreshold > 0 && (c.Incident.StormWindow <= 0 || c.Incident.StormCooldown < 0) {
+		return fmt.Errorf("incident storm window must be positive and storm cooldown not negative")
+	}
+	if c.Incident.RecurrenceThreshold < 0 || c.Incident.RecurrenceThreshold > 1 {
+		return fmt.Errorf("incident recurrence threshold must be between 0 and 1")
+	}
+
+	// Validate notifications config
+	if c.Notifications.MinConfidence < 0 || c.Notifications.MinConfidence > 100 {
+		return fmt.Errorf("notification min confidence must be between 0 and 100")
+	}
+	if c.Notifications.OverdueActionItemsInterval < 0 {
+		return fmt.Errorf("notification overdue action items interval must not be negative")
+	}
+	for i, route := range c.Notifications.Routes {
+		if len(route.Labels) == 0 {
+			return fmt.Errorf("notification route #%d needs labels", i+1)
+		}
+		if route.SlackWebhookURL == "" && route.TeamsWebhookURL == "" && route.DiscordWebhookURL == "" {
+			return fmt.Errorf("notification route #%d needs a webhook URL", i+1)
+		}
+	}
+
+	// Validate enrichment config
+	for i, rule := range c.Enrichment.Extract {
+		if rule.Pattern == "" {
+			return fmt.Errorf("enrichment extract rule #%d needs a pattern", i+1)
+		}
+	}
+	if c.Enrichment.CMDB.URL != "" && c.Enrichment.CMDB.CacheTTL <= 0 {
+		return fmt.Errorf("enrichment CMDB cache TTL must be positive")
+	}
+
+	// Validate environments config
+	if c.Environments.Enabled {
+		if c.Environments.Label == "" {
+			return fmt.Errorf("environments label is required")
+		}
+		for i, mapping := range c.Environments.Mappings {
+			if mapping.Name == "" || len(mapping.Hosts) == 0 {
+				return fmt.Errorf("environment mapping #%d needs a name and hosts", i+1)
+			}
+		}
+	}
+
+	// Validate flapping config
+	if c.Flapping.Enabled && (c.Flapping.Window <= 0 || c.Flapping.Threshold < 2) {
+		return fmt.Errorf("flapping needs a positive window and a threshold of at least 2")
+	}
+
+	// Validate redaction config
+	if c.Redaction.Enabled && !c.Redaction.HashHostnames && !c.Redaction.MaskIPs && len(c.Redaction.LabelDenylist) == 0 {
+		return fmt.Errorf("redaction needs hash_hostnames, mask_ips or a label denylist")
+	}
+
+	// Validate on-call config
+	if c.OnCall.Enabled {
+		if len(c.OnCall.Members) == 0 {
+			return fmt.Errorf("on-call rotation needs at least one member")
+		}
+		if c.OnCall.ShiftLength <= 0 {
+			return fmt.Errorf("on-call shift length must be positive")
+		}
+		if c.OnCall.RotationStart != "" {
+			if _, err := time.Parse(time.RFC3339, c.OnCall.RotationStart); err != nil {
+				return fmt.Errorf("invalid on-call rotation start: %w", err)
+			}
+		}
+	}
+
+	// Validate escalation config
+	if c.Escalation.Enabled {
+		if c.Escalation.CheckInterval <= 0 {
+			return fmt.Errorf("escalation check interval must be positive")
+		}
+		priorities := make(map[string]bool)
+		for i, policy := range c.Escalation.Policies {
+			switch policy.Priority {
+			case "P1", "P2", "P3", "P4":
+			default:
+				return fmt.Errorf("escalation policy #%d: invalid priority %q", i+1, policy.Priority)
+			}
+			if priorities[policy.Priority] {
+				return fmt.Errorf("duplicate escalation policy for %s", policy.Priority)
+			}
+			priorities[policy.Priority] = true
+			if len(policy.Levels) == 0 {
+				return fmt.Errorf("escalation policy %s needs at least one level", policy.Priority)
+			}
+			for j, level := range policy.Levels {
+				if level.After <= 0 || (j > 0 && level.After <= policy.Levels[j-1].After) {
+					return fmt.Errorf("escalation policy %s: level %d must come after the previous one", policy.Priority, j+1)
+				}
+				if !level.OnCallSecondary && level.SlackWebhookURL == "" && level.TeamsWebhookURL == "" && level.DiscordWebhookURL == "" {
+					return fmt.Errorf("escalation policy %s: level %d has no target", policy.Priority, j+1)
+				}
+				if level.OnCallSecondary && !c.OnCall.Enabled {
+					return fmt.Errorf("escalation policy %s: level %d pages the on-call secondary but on-call is disabled", policy.Priority, j+1)
+				}
+			}
+		}
+	}
+
+	// Validate exporters config
+	if c.Exporters.Encoding != "json" && c.Exporters.Encoding != "cloudevents" {
+		return fmt.Errorf("invalid exporter encoding: %s", c.Exporters.Encoding)
+	}
+	if c.Exporters.Kafka.Enabled && (len(c.Exporters.Kafka.Brokers) == 0 || c.Exporters.Kafka.Topic == "") {
+		return fmt.Errorf("kafka exporter needs brokers and a topic")
+	}
+	if c.Exporters.NATS.Enabled && c.Exporters.NATS.URL == "" {
+		return fmt.Errorf("nats exporter needs a URL")
+	}
+	if w := c.Exporters.Webhooks; w.Enabled {
+		if w.MaxAttempts <= 0 || w.Timeout <= 0 {
+			return fmt.Errorf("webhook max attempts and timeout must be positive")
+		}
+		if w.InitialBackoff <= 0 || w.MaxBackoff < w.InitialBackoff {
+			return fmt.Errorf("webhook backoff must be positive and max_backoff at least initial_backoff")
+		}
+	}
+
+	// Validate ticketing config
+	switch c.Ticketing.Tracker {
+	case "":
+	case "jira":
+		if c.Ticketing.Jira.BaseURL == "" || c.Ticketing.Jira.Project == "" {
+			return fmt.Errorf("jira ticketing needs a base URL and project")
+		}
+	case "github":
+		if c.Ticketing.GitHub.Repo == "" || c.Ticketing.GitHub.Token == "" {
+			return fmt.Errorf("github ticketing needs a repo and token")
+		}
+	default:
+		return fmt.Errorf("unsupported ticket tracker: %s", c.Ticketing.Tracker)
+	}
+	if c.Ticketing.MinSeverity != "critical" && c.Ticketing.MinSeverity != "warning" {
+		return fmt.Errorf("ticketing min severity must be critical or warning")
+	}
+
 	// Validate observability config
 	validLogLevels := []string{"debug", "info", "warn", "error"}
 	found := false
@@ -222,29 +1263,96 @@ func (c *Config) Validate() error {
 	if !found {
 		return fmt.Errorf("invalid log level: %s", c.Observability.LogLevel)
 	}
+	if c.Observability.LogBufferSize <= 0 {
+		return fmt.Errorf("log buffer size must be positive")
+	}
 
 	if c.Observability.MetricsPort <= 0 || c.Observability.MetricsPort > 65535 {
 		return fmt.Errorf("metrics port must be between 1 and 65535")
 	}
 
+	if c.Observability.HealthCheckTimeout < 0 {
+		return fmt.Errorf("health check timeout must not be negative")
+	}
+	for name, timeout := range c.Observability.HealthCheckTimeouts {
+		if timeout < 0 {
+			return fmt.Errorf("health check timeout of %s must not be negative", name)
+		}
+	}
+
 	// Validate incident config
 	if c.Incident.MaxIncidents <= 0 {
 		return fmt.Errorf("max incidents must be positive")
 	}
+	if c.Database.MemoryMaxAlerts < 0 || c.Database.MemoryRetention < 0 {
+		return fmt.Errorf("memory database limits must not be negative")
+	}
+
+	if err := c.Risk.validate(); err != nil {
+		return fmt.Errorf("invalid risk config: %w", err)
+	}
 
 	return nil
 }
 
+func (c RiskConfig) validate() error {
+	levels := []struct {
+		name       string
+		thresholds RiskThresholdsConfig
+	}{{"medium", c.Medium}, {"high", c.High}, {"critical", c.Critical}}
+	for i, level := range levels {
+		t := level.thresholds
+		if t.CriticalAlerts < 0 || t.Hosts < 0 || t.ResourceTypes < 0 {
+			return fmt.Errorf("%s thresholds must not be negative", level.name)
+		}
+		if t.CriticalAlerts == 0 && t.Hosts == 0 && t.ResourceTypes == 0 {
+			return fmt.Errorf("%s needs at least one threshold", level.name)
+		}
+		if i == 0 {
+			continue
+		}
+		// A higher level must not be reached before a lower one
+		lower := levels[i-1]
+		if t.CriticalAlerts > 0 && t.CriticalAlerts < lower.thresholds.CriticalAlerts ||
+			t.Hosts > 0 && t.Hosts < lower.thresholds.Hosts ||
+			t.ResourceTypes > 0 && t.ResourceTypes < lower.thresholds.ResourceTypes {
+			return fmt.Errorf("%s thresholds must not be below the %s thresholds", level.name, lower.name)
+		}
+	}
+
+	for name, resource := range c.Resources {
+		switch name {
+		case "CPU", "MEMORY", "DISK", "NETWORK", "PROCESS", "DATABASE", "CONTAINER", "APPLICATION":
+		default:
+			return fmt.Errorf("unknown resource type %q", name)
+		}
+		if resource.Weight < 0 {
+			return fmt.Errorf("weight of %s must not be negative", name)
+		}
+		if resource.HighValue < 0 {
+			return fmt.Errorf("high value of %s must not be negative", name)
+		}
+	}
+	return nil
+}
+
 // GetDSN returns database connection string
 func (c *DatabaseConfig) GetDSN() string {
 	switch c.Type {
 	case "postgres", "postgresql":
-		return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 			c.Host, c.Port, c.Username, c.Password, c.Database, c.SSLMode)
+		if c.ReadOnly {
+			dsn += " default_transaction_read_only=on"
+		}
+		return dsn
 	case "mysql":
 		return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
 			c.Username, c.Password, c.Host, c.Port, c.Database)
 	case "sqlite":
+		if c.ReadOnly {
+			return "file:" + c.SQLitePath + "?mode=ro"
+		}
 		return c.SQLitePath
 	default:
 		return ""
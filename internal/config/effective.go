@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secrets in the effective configuration
+const redacted = "[redacted]"
+
+// Effective is the configuration in force, with secrets masked, and where each setting
+// came from
+type Effective struct {
+	Settings map[string]any    `json:"settings"` // Keyed like the YAML file
+	Sources  map[string]string `json:"sources"`  // Dotted YAML key to "file" or "env"; settings left out have their default
+}
+
+// Effective returns the redacted configuration. path is the file it was loaded from,
+// empty if it came from defaults and the environment only.
+func (c *Config) Effective(path string) (Effective, error) {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return Effective{}, fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	var settings map[string]any
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return Effective{}, fmt.Errorf("failed to unmarshal configuration: %w", err)
+	}
+	redact(settings)
+
+	var file map[string]any
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Effective{}, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return Effective{}, fmt.Errorf("failed to unmarshal YAML: %w", err)
+		}
+	}
+
+	sources := make(map[string]string)
+	settingSources(reflect.TypeOf(*c), nil, "", file, sources)
+	return Effective{Settings: settings, Sources: sources}, nil
+}
+
+// settingSources records whether each setting of section t was set by the environment,
+// which takes precedence, or the file
+func settingSources(t reflect.Type, key []string, envPrefix string, file map[string]any, sources map[string]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		fieldKey := append(append([]string(nil), key...), name)
+
+		if env, _, _ := strings.Cut(field.Tag.Get("env"), ","); env != "" {
+			_, set := os.LookupEnv(envPrefix + env)
+			if _, fromFile := os.LookupEnv(envPrefix + env + "_FILE"); fromFile && secretField(field) {
+				set = true
+			}
+			if set {
+				sources[strings.Join(fieldKey, ".")] = "env"
+				continue
+			}
+		} else if field.Type.Kind() == reflect.Struct {
+			settingSources(field.Type, fieldKey, envPrefix+field.Tag.Get("envPrefix"), file, sources)
+			continue
+		}
+		if inFile(file, fieldKey) {
+			sources[strings.Join(fieldKey, ".")] = "file"
+		}
+	}
+}
+
+// inFile reports whether the YAML file sets the key
+func inFile(file map[string]any, key []string) bool {
+	var value any = file
+	for _, part := range key {
+		section, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+		if value, ok = section[part]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// redact masks the secrets of a configuration section in place: settings named like a
+// token, password, secret, API key or webhook URL, every value of a header map (they
+// usually carry credentials), and credentials embedded in URLs
+func redact(section map[string]any) {
+	for key, value := range section {
+		switch v := value.(type) {
+		case map[string]any:
+			if headersKey(key) {
+				for name := range v {
+					v[name] = redacted
+				}
+				continue
+			}
+			redact(v)
+		case []any:
+			for _, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					redact(m)
+				}
+			}
+		case string:
+			if v == "" {
+				continue
+			}
+			if secretKey(key) {
+				section[key] = redacted
+			} else if u, err := url.Parse(v); err == nil && u.User != nil {
+				// Only the password is masked: "user:xxxxx@host"
+				section[key] = u.Redacted()
+			}
+		}
+	}
+}
+
+// secretKey reports whether a setting holds a secret
+func secretKey(key string) bool {
+	for _, name := range []string{"token", "password", "secret", "api_key", "webhook_url"} {
+		if key == name || strings.HasSuffix(key, "_"+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// headersKey reports whether a setting holds request headers, e.g. "otlp_headers"
+func headersKey(key string) bool {
+	return key == "headers" || strings.HasSuffix(key, "_headers")
+}
@@ -0,0 +1,27 @@
+package config
+
+import "testing"
+
+func TestEffective_RedactsHeaderMaps(t *testing.T) {
+	cfg := &Config{}
+	cfg.Observability.OTLPHeaders = map[string]string{"Authorization": "Bearer s3cret", "x-tenant": "acme"}
+	cfg.Observability.Tags = map[string]string{"env": "prod"}
+
+	effective, err := cfg.Effective("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	observability := effective.Settings["observability"].(map[string]any)
+	headers := observability["otlp_headers"].(map[string]any)
+	for name, value := range headers {
+		if value != redacted {
+			t.Errorf("expected header %s masked, got %v", name, value)
+		}
+	}
+	if len(headers) != 2 {
+		t.Errorf("expected the header names kept, got %v", headers)
+	}
+	if tags := observability["tags"].(map[string]any); tags["env"] != "prod" {
+		t.Errorf("expected other maps left alone, got %v", tags)
+	}
+}
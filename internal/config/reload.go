@@ -0,0 +1,138 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// ApplyFunc applies a reloaded configuration to a running component. It must only swap
+// settings, so work in progress finishes with the old ones.
+type ApplyFunc func(cfg *Config) error
+
+// ReloadResult reports which components applied a reloaded configuration
+type ReloadResult struct {
+	Applied []string          `json:"applied"`
+	Failed  map[string]string `json:"failed,omitempty"` // Component -> error
+}
+
+// Reloader re-reads the configuration on demand or when the file changes and hands it to
+// the registered components. Settings without an Apply hook need a restart.
+type Reloader struct {
+	path  string
+	hooks []reloadHook
+
+	mu      sync.Mutex
+	current *Config
+	modTime time.Time
+}
+
+type reloadHook struct {
+	name  string
+	apply ApplyFunc
+}
+
+// NewReloader creates a reloader for the configuration loaded from path ("" for
+// environment variables only)
+func NewReloader(path string, current *Config) *Reloader {
+	r := &Reloader{path: path, current: current}
+	if info, err := os.Stat(path); err == nil {
+		r.modTime = info.ModTime()
+	}
+	return r
+}
+
+// OnReload registers a component's Apply hook. Hooks run in registration order.
+func (r *Reloader) OnReload(name string, apply ApplyFunc) {
+	r.hooks = append(r.hooks, reloadHook{name: name, apply: apply})
+}
+
+// Current returns the most recently applied configuration
+func (r *Reloader) Current() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Effective returns the redacted current configuration and the source of its settings
+func (r *Reloader) Effective() (Effective, error) {
+	return r.Current().Effective(r.path)
+}
+
+// Reload loads and validates the configuration, then applies it to every component. An
+// invalid configuration is rejected as a whole and nothing is applied.
+func (r *Reloader) Reload() (ReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, err := Load(r.path)
+	if err != nil {
+		return ReloadResult{}, err
+	}
+	if info, err := os.Stat(r.path); err == nil {
+		r.modTime = info.ModTime()
+	}
+
+	result := ReloadResult{Applied: []string{}}
+	for _, hook := range r.hooks {
+		if err := hook.apply(cfg); err != nil {
+			if result.Failed == nil {
+				result.Failed = make(map[string]string)
+			}
+			result.Failed[hook.name] = err.Error()
+			continue
+		}
+		result.Applied = append(result.Applied, hook.name)
+	}
+	r.current = cfg
+
+	if len(result.Failed) > 0 {
+		return result, fmt.Errorf("%d of %d components failed to apply the configuration", len(result.Failed), len(r.hooks))
+	}
+	return result, nil
+}
+
+// Watch reloads the configuration whenever the file's modification time changes, checking
+// every interval until ctx is cancelled
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	if r.path == "" {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(r.path)
+			if err != nil {
+				continue
+			}
+			r.mu.Lock()
+			changed := !info.ModTime().Equal(r.modTime)
+			r.mu.Unlock()
+			if !changed {
+				continue
+			}
+
+			result, err := r.Reload()
+			if err != nil {
+				log.Printf("⚠️  Configuration reload failed: %v", err)
+				if len(result.Applied) == 0 && len(result.Failed) == 0 {
+					// Don't retry an invalid file until it changes again
+					r.mu.Lock()
+					r.modTime = info.ModTime()
+					r.mu.Unlock()
+				}
+				continue
+			}
+			log.Printf("🔄 Configuration reloaded from %s: %v", r.path, result.Applied)
+		}
+	}
+}
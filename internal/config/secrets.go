@@ -0,0 +1,102 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"incident-teller/internal/secrets"
+)
+
+// secretsTimeout bounds resolving every secret reference of a configuration
+const secretsTimeout = 30 * time.Second
+
+// loadSecretFiles sets the secret settings whose <VARIABLE>_FILE environment variable is
+// set, e.g. DB_PASSWORD_FILE, to the content of that file, as mounted by Docker or
+// Kubernetes secrets
+func loadSecretFiles(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+		if key == "" {
+			if field.Type.Kind() == reflect.Struct {
+				if err := loadSecretFiles(v.Field(i), prefix+field.Tag.Get("envPrefix")); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if field.Type.Kind() != reflect.String || !secretField(field) {
+			continue
+		}
+		path, set := os.LookupEnv(prefix + key + "_FILE")
+		if !set {
+			continue
+		}
+		if _, both := os.LookupEnv(prefix + key); both {
+			return fmt.Errorf("both %s and %s_FILE are set", prefix+key, prefix+key)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s_FILE: %w", prefix+key, err)
+		}
+		v.Field(i).SetString(strings.TrimRight(string(data), "\r\n"))
+	}
+	return nil
+}
+
+// resolveSecrets replaces the secret settings referencing an external secret store, e.g.
+// "vault:secret/data/incident-teller#db_password", with the secret
+func resolveSecrets(cfg *Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), secretsTimeout)
+	defer cancel()
+	return resolveSecretRefs(ctx, secrets.NewResolver(), reflect.ValueOf(cfg).Elem(), nil)
+}
+
+// resolveSecretRefs resolves the references in a section, including the sections in lists
+// such as notification routes
+func resolveSecretRefs(ctx context.Context, resolver *secrets.Resolver, v reflect.Value, key []string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		fieldKey := append(append([]string(nil), key...), name)
+
+		switch value := v.Field(i); {
+		case field.Type.Kind() == reflect.Struct:
+			if err := resolveSecretRefs(ctx, resolver, value, fieldKey); err != nil {
+				return err
+			}
+		case field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct:
+			for j := 0; j < value.Len(); j++ {
+				itemKey := append(fieldKey[:len(fieldKey):len(fieldKey)], fmt.Sprint(j))
+				if err := resolveSecretRefs(ctx, resolver, value.Index(j), itemKey); err != nil {
+					return err
+				}
+			}
+		case field.Type.Kind() == reflect.String && secretField(field) && secrets.IsReference(value.String()):
+			secret, err := resolver.Resolve(ctx, value.String())
+			if err != nil {
+				return fmt.Errorf("%s: %w", strings.Join(fieldKey, "."), err)
+			}
+			value.SetString(secret)
+		}
+	}
+	return nil
+}
+
+// secretField reports whether a setting holds a secret, going by its YAML name
+func secretField(field reflect.StructField) bool {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	return secretKey(name)
+}
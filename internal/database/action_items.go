@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// SaveActionItem stores an action item, replacing the one with the same ID
+func (r *SQLRepository) SaveActionItem(ctx context.Context, item domain.ActionItem) error {
+	query := `
+		INSERT INTO action_items
+			(id, incident_id, description, owner, due_at, status, created_at, updated_at, overdue_notified_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	` + r.dialect.OnConflictUpdate([]string{"id"},
+		[]string{"description", "owner", "due_at", "status", "updated_at", "overdue_notified_at"})
+
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(query),
+		item.ID, item.IncidentID, item.Description, item.Owner, utcOrNil(item.DueAt), string(item.Status),
+		item.CreatedAt.UTC(), item.UpdatedAt.UTC(), utcOrNil(item.OverdueNotifiedAt))
+	if err != nil {
+		return fmt.Errorf("failed to save action item: %w", err)
+	}
+	return nil
+}
+
+// DeleteActionItem removes an action item, reporting whether there was one
+func (r *SQLRepository) DeleteActionItem(ctx context.Context, id string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind("DELETE FROM action_items WHERE id = ?"), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete action item: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete action item: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// GetActionItems returns the action items of an incident, or of every incident if
+// incidentID is empty, oldest first
+func (r *SQLRepository) GetActionItems(ctx context.Context, incidentID string) ([]domain.ActionItem, error) {
+	query := `
+		SELECT id, incident_id, description, owner, due_at, status, created_at, updated_at, overdue_notified_at
+		FROM action_items
+	`
+	var args []interface{}
+	if incidentID != "" {
+		query += " WHERE incident_id = ?"
+		args = append(args, incidentID)
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query action items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []domain.ActionItem{}
+	for rows.Next() {
+		var item domain.ActionItem
+		var dueAt, notifiedAt sql.NullTime
+		if err := rows.Scan(&item.ID, &item.IncidentID, &item.Description, &item.Owner, &dueAt, &item.Status,
+			&item.CreatedAt, &item.UpdatedAt, &notifiedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan action item: %w", err)
+		}
+		if dueAt.Valid {
+			item.DueAt = &dueAt.Time
+		}
+		if notifiedAt.Valid {
+			item.OverdueNotifiedAt = &notifiedAt.Time
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// utcOrNil returns an optional time in UTC as a query argument, nil if unset
+func utcOrNil(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
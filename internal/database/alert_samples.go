@@ -0,0 +1,29 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// SaveAlertSamples replaces the chart values sampled for an alert
+func (r *SQLRepository) SaveAlertSamples(ctx context.Context, alertID string, samples []domain.MetricSample) error {
+	data, err := json.Marshal(samples)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert samples: %w", err)
+	}
+
+	query := `
+		INSERT INTO alert_samples (alert_id, samples, sampled_at)
+		VALUES (?, ?, ?)
+	` + r.dialect.OnConflictUpdate([]string{"alert_id"}, []string{"samples", "sampled_at"})
+
+	if _, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), alertID, string(data), time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to save alert samples: %w", err)
+	}
+	r.cache.invalidateAlerts(alertID)
+	return nil
+}
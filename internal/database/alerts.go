@@ -0,0 +1,170 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"incident-teller/internal/domain"
+)
+
+// alertDetailColumns are the columns scanned by scanAlertDetail
+const alertDetailColumns = `a.id, a.external_id, a.host, a.chart, a.family, a.name, a.status, a.old_status,
+	a.value, a.occurred_at, a.description, a.resource_type, a.labels, a.source, COALESCE(s.samples, ''),
+	ak.acknowledged_by, ak.note, ak.acknowledged_at`
+
+// alertDetailJoins add the samples and acknowledgement of alerts
+const alertDetailJoins = `LEFT JOIN alert_samples s ON s.alert_id = a.id
+	LEFT JOIN alert_acknowledgements ak ON ak.alert_id = a.id`
+
+// GetAlert returns an alert with its raw payload, samples and acknowledgement, or nil if
+// it doesn't exist
+func (r *SQLRepository) GetAlert(ctx context.Context, id string) (*domain.Alert, error) {
+	query := "SELECT " + alertDetailColumns + ", a.raw_payload FROM alerts a " + alertDetailJoins + " WHERE a.id = ?"
+
+	var rawPayload sql.NullString
+	alert, err := scanAlertDetail(r.db.QueryRowContext(ctx, r.dialect.Rebind(query), id), &rawPayload)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	alert.RawPayload = rawPayload.String
+	return &alert, nil
+}
+
+// QueryAlerts returns the alerts matching the query, newest first, and how many match
+func (r *SQLRepository) QueryAlerts(ctx context.Context, q domain.AlertQuery) ([]domain.Alert, int, error) {
+	var conditions []string
+	var args []interface{}
+	for column, value := range map[string]string{"a.host": q.Host, "a.source": q.Source} {
+		if value != "" {
+			conditions = append(conditions, column+" = ?")
+			args = append(args, value)
+		}
+	}
+	if len(q.Statuses) > 0 {
+		conditions = append(conditions, "a.status IN ("+strings.TrimSuffix(strings.Repeat("?, ", len(q.Statuses)), ", ")+")")
+		for _, status := range q.Statuses {
+			args = append(args, string(status))
+		}
+	}
+	if search := strings.TrimSpace(q.Search); search != "" {
+		pattern := "%" + escapeLike(strings.ToLower(search)) + "%"
+		conditions = append(conditions, `(LOWER(a.host) LIKE ? ESCAPE '!'
+			OR LOWER(a.chart) LIKE ? ESCAPE '!'
+			OR LOWER(a.name) LIKE ? ESCAPE '!')`)
+		args = append(args, pattern, pattern, pattern)
+	}
+	if !q.From.IsZero() {
+		conditions = append(conditions, "a.occurred_at >= ?")
+		args = append(args, q.From.UTC())
+	}
+	if !q.To.IsZero() {
+		conditions = append(conditions, "a.occurred_at <= ?")
+		args = append(args, q.To.UTC())
+	}
+	if q.Acknowledged != nil {
+		if *q.Acknowledged {
+			conditions = append(conditions, "ak.alert_id IS NOT NULL")
+		} else {
+			conditions = append(conditions, "ak.alert_id IS NULL")
+		}
+	}
+
+	from := " FROM alerts a " + alertDetailJoins
+	if len(conditions) > 0 {
+		from += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT COUNT(*)"+from), args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count alerts: %w", err)
+	}
+
+	query := "SELECT " + alertDetailColumns + from + " ORDER BY a.occurred_at DESC, a.id DESC"
+	if q.Limit > 0 || q.Offset > 0 {
+		limit := q.Limit
+		if limit <= 0 {
+			limit = math.MaxInt32
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, q.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := []domain.Alert{}
+	for rows.Next() {
+		alert, err := scanAlertDetail(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, total, rows.Err()
+}
+
+// AcknowledgeAlert records the acknowledgement of an alert, replacing an earlier one
+func (r *SQLRepository) AcknowledgeAlert(ctx context.Context, ack domain.AlertAck) error {
+	query := `
+		INSERT INTO alert_acknowledgements (alert_id, acknowledged_by, note, acknowledged_at)
+		VALUES (?, ?, ?, ?)
+	` + r.dialect.OnConflictUpdate([]string{"alert_id"}, []string{"acknowledged_by", "note", "acknowledged_at"})
+
+	if _, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), ack.AlertID, ack.By, ack.Note, ack.AcknowledgedAt.UTC()); err != nil {
+		return fmt.Errorf("failed to save alert acknowledgement: %w", err)
+	}
+	return nil
+}
+
+// scanAlertDetail scans the alertDetailColumns of a row, followed by extra columns
+func scanAlertDetail(row interface{ Scan(...any) error }, extra ...any) (domain.Alert, error) {
+	var alert domain.Alert
+	var description, ackBy, ackNote sql.NullString
+	var ackAt sql.NullTime
+	var labelsJSON, samplesJSON string
+
+	dest := []any{
+		&alert.ID, &alert.ExternalID, &alert.Host, &alert.Chart,
+		&alert.Family, &alert.Name, &alert.Status, &alert.OldStatus,
+		&alert.Value, &alert.OccurredAt, &description,
+		&alert.ResourceType, &labelsJSON, &alert.Source, &samplesJSON,
+		&ackBy, &ackNote, &ackAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		if err == sql.ErrNoRows {
+			return alert, err
+		}
+		return alert, fmt.Errorf("failed to scan alert: %w", err)
+	}
+
+	alert.Description = description.String
+	if labelsJSON != "" {
+		if err := json.Unmarshal([]byte(labelsJSON), &alert.Labels); err != nil {
+			return alert, fmt.Errorf("failed to unmarshal labels: %w", err)
+		}
+	}
+	if samplesJSON != "" {
+		if err := json.Unmarshal([]byte(samplesJSON), &alert.Samples); err != nil {
+			return alert, fmt.Errorf("failed to unmarshal alert samples: %w", err)
+		}
+	}
+	if ackAt.Valid {
+		alert.Ack = &domain.AlertAck{
+			AlertID:        alert.ID,
+			By:             ackBy.String,
+			Note:           ackNote.String,
+			AcknowledgedAt: ackAt.Time,
+		}
+	}
+	return alert, nil
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"incident-teller/internal/domain"
+)
+
+// SaveAuditEntry appends an entry to the audit log
+func (r *SQLRepository) SaveAuditEntry(ctx context.Context, entry domain.AuditEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit changes: %w", err)
+	}
+
+	query := `
+		INSERT INTO audit_log (id, actor, api_key, remote_addr, method, path, status, payload, changes, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = r.db.ExecContext(ctx, r.dialect.Rebind(query), entry.ID, entry.Actor, entry.APIKey, entry.RemoteAddr,
+		entry.Method, entry.Path, entry.Status, entry.Payload, string(changes), entry.At)
+	if err != nil {
+		return fmt.Errorf("failed to save audit entry: %w", err)
+	}
+	return nil
+}
+
+// GetAuditEntries returns the audit log entries matching the query, newest first
+func (r *SQLRepository) GetAuditEntries(ctx context.Context, q domain.AuditQuery) ([]domain.AuditEntry, error) {
+	var conditions []string
+	var args []any
+	for column, value := range map[string]string{"actor": q.Actor, "api_key": q.APIKey, "method": q.Method} {
+		if value != "" {
+			conditions = append(conditions, column+" = ?")
+			args = append(args, value)
+		}
+	}
+	if q.PathPrefix != "" {
+		conditions = append(conditions, "path LIKE ? ESCAPE '!'")
+		args = append(args, escapeLike(q.PathPrefix)+"%")
+	}
+	if !q.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, q.From)
+	}
+	if !q.To.IsZero() {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, q.To)
+	}
+
+	query := `SELECT id, actor, api_key, remote_addr, method, path, status, payload, changes, created_at FROM audit_log`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if q.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, q.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		var entry domain.AuditEntry
+		var changes string
+		if err := rows.Scan(&entry.ID, &entry.Actor, &entry.APIKey, &entry.RemoteAddr, &entry.Method, &entry.Path,
+			&entry.Status, &entry.Payload, &changes, &entry.At); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if err := json.Unmarshal([]byte(changes), &entry.Changes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal audit changes: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,160 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dialect captures the SQL differences between the supported databases: bind
+// parameter style, upsert syntax, identifier quoting and column types. Repository
+// queries are written with `?` placeholders and rebound per dialect.
+type Dialect string
+
+// Supported SQL dialects
+const (
+	DialectSQLite   Dialect = "sqlite"
+	DialectPostgres Dialect = "postgres"
+	DialectMySQL    Dialect = "mysql"
+)
+
+// ParseDialect maps a database type or driver name onto a dialect
+func ParseDialect(name string) (Dialect, error) {
+	switch strings.ToLower(name) {
+	case "sqlite", "sqlite3":
+		return DialectSQLite, nil
+	case "postgres", "postgresql", "pgx":
+		return DialectPostgres, nil
+	case "mysql", "mariadb":
+		return DialectMySQL, nil
+	default:
+		return "", fmt.Errorf("unsupported SQL dialect %q", name)
+	}
+}
+
+// DriverName returns the database/sql driver registered for the dialect
+func (d Dialect) DriverName() string {
+	switch d {
+	case DialectPostgres:
+		return "postgres"
+	case DialectMySQL:
+		return "mysql"
+	default:
+		return "sqlite3"
+	}
+}
+
+// detectDialect guesses the SQL dialect from the registered driver's type name,
+// falling back to sqlite
+func detectDialect(db *sql.DB) Dialect {
+	if db == nil {
+		return DialectSQLite
+	}
+
+	name := strings.ToLower(fmt.Sprintf("%T", db.Driver()))
+	switch {
+	case strings.Contains(name, "pq.") || strings.Contains(name, "pgx") || strings.Contains(name, "postgres"):
+		return DialectPostgres
+	case strings.Contains(name, "mysql"):
+		return DialectMySQL
+	default:
+		return DialectSQLite
+	}
+}
+
+// Placeholder returns the n-th (1-based) bind parameter
+func (d Dialect) Placeholder(n int) string {
+	if d == DialectPostgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// Rebind rewrites `?` placeholders into the dialect's style. Question marks inside
+// quoted strings and identifiers are left alone.
+func (d Dialect) Rebind(query string) string {
+	if d != DialectPostgres {
+		return query
+	}
+
+	var out strings.Builder
+	out.Grow(len(query) + 8)
+
+	n := 0
+	var quote rune
+	for _, c := range query {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			n++
+			out.WriteString(d.Placeholder(n))
+			continue
+		}
+		out.WriteRune(c)
+	}
+
+	return out.String()
+}
+
+// Quote quotes an identifier, e.g. reserved words used as column names
+func (d Dialect) Quote(ident string) string {
+	if d == DialectMySQL {
+		return "`" + ident + "`"
+	}
+	return `"` + ident + `"`
+}
+
+// OnConflictUpdate returns the upsert clause appended to an INSERT: on a conflict on
+// keys, the listed columns take the inserted values and the raw assignments
+// (e.g. "updated_at = CURRENT_TIMESTAMP") are applied.
+func (d Dialect) OnConflictUpdate(keys, columns []string, raw ...string) string {
+	assignments := make([]string, 0, len(columns)+len(raw))
+	for _, column := range columns {
+		if d == DialectMySQL {
+			assignments = append(assignments, fmt.Sprintf("%s = VALUES(%s)", column, column))
+		} else {
+			assignments = append(assignments, fmt.Sprintf("%s = excluded.%s", column, column))
+		}
+	}
+	assignments = append(assignments, raw...)
+
+	if d == DialectMySQL {
+		// MySQL upserts on any unique key; the key list is implied
+		return "ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", ")
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(keys, ", "), strings.Join(assignments, ", "))
+}
+
+// OnConflictIgnore returns the clause appended to an INSERT that skips rows conflicting
+// on keys, so the insert affects no rows
+func (d Dialect) OnConflictIgnore(keys []string) string {
+	if d == DialectMySQL {
+		// A no-op update leaves the affected row count at 0
+		return fmt.Sprintf("ON DUPLICATE KEY UPDATE %s = %s", keys[0], keys[0])
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO NOTHING", strings.Join(keys, ", "))
+}
+
+// TimestampType returns the column type used for timestamps
+func (d Dialect) TimestampType() string {
+	if d == DialectMySQL {
+		// Microsecond precision; plain DATETIME truncates to seconds
+		return "DATETIME(6)"
+	}
+	return "TIMESTAMP"
+}
+
+// KeyType returns the column type for indexed text such as IDs and names; MySQL
+// cannot index TEXT without a prefix length
+func (d Dialect) KeyType() string {
+	if d == DialectMySQL {
+		return "VARCHAR(255)"
+	}
+	return "TEXT"
+}
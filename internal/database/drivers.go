@@ -0,0 +1,9 @@
+package database
+
+// Register the database/sql drivers for every supported dialect. The sqlite driver
+// needs cgo; without it opening a sqlite database fails at runtime.
+import (
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
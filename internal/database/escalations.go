@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"incident-teller/internal/domain"
+)
+
+// GetEscalations returns the escalations of an incident in level order
+func (r *SQLRepository) GetEscalations(ctx context.Context, incidentID string) ([]domain.Escalation, error) {
+	query := `
+		SELECT level, priority, target, escalated_at
+		FROM incident_escalations
+		WHERE incident_id = ?
+		ORDER BY level
+	`
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query escalations: %w", err)
+	}
+	defer rows.Close()
+
+	var escalations []domain.Escalation
+	for rows.Next() {
+		escalation := domain.Escalation{IncidentID: incidentID}
+		var priority string
+		if err := rows.Scan(&escalation.Level, &priority, &escalation.Target, &escalation.EscalatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan escalation: %w", err)
+		}
+		escalation.Priority = domain.Priority(priority)
+		escalations = append(escalations, escalation)
+	}
+	return escalations, rows.Err()
+}
+
+// SaveEscalation records an escalation. Each level of an incident is kept once.
+func (r *SQLRepository) SaveEscalation(ctx context.Context, escalation domain.Escalation) error {
+	query := `
+		INSERT INTO incident_escalations (incident_id, level, priority, target, escalated_at)
+		VALUES (?, ?, ?, ?, ?)
+	` + r.dialect.OnConflictUpdate([]string{"incident_id", "level"}, nil, "level = incident_escalations.level")
+
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), escalation.IncidentID, escalation.Level,
+		string(escalation.Priority), escalation.Target, escalation.EscalatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save escalation: %w", err)
+	}
+	return nil
+}
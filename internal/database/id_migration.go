@@ -0,0 +1,323 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"incident-teller/internal/idgen"
+)
+
+// idRewrite maps a legacy row ID to its replacement
+type idRewrite struct {
+	oldID string
+	newID string
+}
+
+// idColumn is a column holding the ID of an alert or incident
+type idColumn struct {
+	table  string
+	column string
+}
+
+// Every column referencing an alert or incident ID. A table keyed by one of them must be
+// listed here, or its rows are orphaned (or cascade-deleted) when the ID is rewritten.
+var (
+	alertIDColumns = []idColumn{
+		{"incident_alerts", "alert_id"},
+		{"alert_samples", "alert_id"},
+		{"alert_acknowledgements", "alert_id"},
+		{"root_cause_predictions", "alert_id"},
+		{"root_cause_overrides", "alert_id"},
+	}
+	incidentIDColumns = []idColumn{
+		{"incident_alerts", "incident_id"},
+		{"incident_labels", "incident_id"},
+		{"incident_acknowledgements", "incident_id"},
+		{"timeline_entries", "incident_id"},
+		{"incident_tickets", "incident_id"},
+		{"root_cause_predictions", "incident_id"},
+		{"incident_priorities", "incident_id"},
+		{"incident_priority_changes", "incident_id"},
+		{"incident_escalations", "incident_id"},
+		{"incident_metadata", "incident_id"},
+		{"root_cause_overrides", "incident_id"},
+		{"incident_states", "incident_id"},
+		{"incident_state_transitions", "incident_id"},
+		{"problem_links", "incident_id"},
+		{"problem_links", "problem_id"},
+		{"action_items", "incident_id"},
+	}
+)
+
+// MigrateIDs rewrites legacy timestamp-based alert and incident IDs to the given generator's format.
+// New IDs are derived from the old ones, so alerts re-ingested after the migration map onto the
+// migrated rows. External IDs are left untouched. Rows already in the target format are skipped.
+func (r *SQLRepository) MigrateIDs(ctx context.Context, gen idgen.Generator) (alertsMigrated, incidentsMigrated int, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Alerts first: incident IDs are derived from their first alert's ID
+	alertRewrites, err := r.collectRewrites(ctx, tx, gen, "SELECT id, occurred_at FROM alerts", nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to collect alert IDs: %w", err)
+	}
+	// The fingerprint is unique, so it moves to the new row once the legacy one is gone
+	if err := r.rewriteRows(ctx, tx, "alerts", []string{"fingerprint"}, alertIDColumns, alertRewrites); err != nil {
+		return 0, 0, err
+	}
+	if err := r.rewriteTimelineAlertIDs(ctx, tx, alertRewrites); err != nil {
+		return 0, 0, err
+	}
+
+	incidentRewrites, err := r.collectRewrites(ctx, tx, gen, "SELECT id, started_at FROM incidents",
+		func(id string) (string, error) {
+			var firstAlertID string
+			err := tx.QueryRowContext(ctx, r.dialect.Rebind(`
+				SELECT alert_id FROM incident_alerts
+				WHERE incident_id = ?
+				ORDER BY sequence_order
+				LIMIT 1
+			`), id).Scan(&firstAlertID)
+			if err == sql.ErrNoRows {
+				return id, nil
+			}
+			return firstAlertID, err
+		})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to collect incident IDs: %w", err)
+	}
+	if err := r.rewriteRows(ctx, tx, "incidents", nil, incidentIDColumns, incidentRewrites); err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit ID migration: %w", err)
+	}
+	r.cache.clear()
+
+	return len(alertRewrites), len(incidentRewrites), nil
+}
+
+// rewriteRows moves each rewritten row of table to its new ID: the row is copied with all its
+// columns, every referencing column is relinked, and the legacy row is deleted. Unique columns
+// are left empty on the copy and restored after the delete.
+func (r *SQLRepository) rewriteRows(
+	ctx context.Context,
+	tx *sql.Tx,
+	table string,
+	unique []string,
+	references []idColumn,
+	rewrites []idRewrite,
+) error {
+	if len(rewrites) == 0 {
+		return nil
+	}
+
+	columns, err := tableColumns(ctx, tx, table)
+	if err != nil {
+		return fmt.Errorf("failed to read %s columns: %w", table, err)
+	}
+	isUnique := make(map[string]bool, len(unique))
+	for _, column := range unique {
+		isUnique[column] = true
+	}
+	selected := make([]string, len(columns))
+	for i, column := range columns {
+		switch {
+		case column == "id":
+			selected[i] = "?"
+		case isUnique[column]:
+			selected[i] = "NULL"
+		default:
+			selected[i] = column
+		}
+	}
+	copyQuery := r.dialect.Rebind(fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s WHERE id = ?",
+		table, strings.Join(columns, ", "), strings.Join(selected, ", "), table))
+
+	var readUnique, restoreUnique string
+	if len(unique) > 0 {
+		assignments := make([]string, len(unique))
+		for i, column := range unique {
+			assignments[i] = column + " = ?"
+		}
+		readUnique = r.dialect.Rebind(fmt.Sprintf("SELECT %s FROM %s WHERE id = ?", strings.Join(unique, ", "), table))
+		restoreUnique = r.dialect.Rebind(fmt.Sprintf("UPDATE %s SET %s WHERE id = ?", table, strings.Join(assignments, ", ")))
+	}
+
+	for _, rw := range rewrites {
+		values := make([]interface{}, len(unique))
+		if readUnique != "" {
+			dest := make([]interface{}, len(values))
+			for i := range values {
+				dest[i] = &values[i]
+			}
+			if err := tx.QueryRowContext(ctx, readUnique, rw.oldID).Scan(dest...); err != nil {
+				return fmt.Errorf("failed to read %s %s: %w", table, rw.oldID, err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, copyQuery, rw.newID, rw.oldID); err != nil {
+			return fmt.Errorf("failed to copy %s %s: %w", table, rw.oldID, err)
+		}
+		for _, ref := range references {
+			relink := r.dialect.Rebind(fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", ref.table, ref.column, ref.column))
+			if _, err := tx.ExecContext(ctx, relink, rw.newID, rw.oldID); err != nil {
+				return fmt.Errorf("failed to relink %s.%s of %s: %w", ref.table, ref.column, rw.oldID, err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, r.dialect.Rebind(fmt.Sprintf("DELETE FROM %s WHERE id = ?", table)), rw.oldID); err != nil {
+			return fmt.Errorf("failed to delete legacy %s %s: %w", table, rw.oldID, err)
+		}
+
+		if restoreUnique != "" {
+			if _, err := tx.ExecContext(ctx, restoreUnique, append(values, rw.newID)...); err != nil {
+				return fmt.Errorf("failed to restore %s %s: %w", table, rw.newID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// tableColumns returns the column names of table in declaration order
+func tableColumns(ctx context.Context, tx *sql.Tx, table string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s WHERE 1 = 0", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return rows.Columns()
+}
+
+// rewriteTimelineAlertIDs rewrites the alert IDs stored as JSON lists in timeline entries
+func (r *SQLRepository) rewriteTimelineAlertIDs(ctx context.Context, tx *sql.Tx, rewrites []idRewrite) error {
+	if len(rewrites) == 0 {
+		return nil
+	}
+	newIDs := make(map[string]string, len(rewrites))
+	for _, rw := range rewrites {
+		newIDs[rw.oldID] = rw.newID
+	}
+
+	type entryIDs struct {
+		incidentID string
+		sequence   int
+		alertIDs   string
+		causedBy   string
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT incident_id, sequence_order, alert_ids, caused_by FROM timeline_entries")
+	if err != nil {
+		return fmt.Errorf("failed to query timeline entries: %w", err)
+	}
+	var entries []entryIDs
+	for rows.Next() {
+		var entry entryIDs
+		if err := rows.Scan(&entry.incidentID, &entry.sequence, &entry.alertIDs, &entry.causedBy); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan timeline entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	update := r.dialect.Rebind("UPDATE timeline_entries SET alert_ids = ?, caused_by = ? WHERE incident_id = ? AND sequence_order = ?")
+	for _, entry := range entries {
+		alertIDs, alertsChanged, err := rewriteIDList(entry.alertIDs, newIDs)
+		if err != nil {
+			return fmt.Errorf("failed to decode timeline entry alerts: %w", err)
+		}
+		causedBy, causesChanged, err := rewriteIDList(entry.causedBy, newIDs)
+		if err != nil {
+			return fmt.Errorf("failed to decode timeline entry causes: %w", err)
+		}
+		if !alertsChanged && !causesChanged {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, update, alertIDs, causedBy, entry.incidentID, entry.sequence); err != nil {
+			return fmt.Errorf("failed to relink timeline entry of %s: %w", entry.incidentID, err)
+		}
+	}
+
+	return nil
+}
+
+// rewriteIDList maps the IDs of a JSON list through newIDs, reporting whether any changed
+func rewriteIDList(encoded string, newIDs map[string]string) (string, bool, error) {
+	var ids []string
+	if err := json.Unmarshal([]byte(encoded), &ids); err != nil {
+		return "", false, err
+	}
+	changed := false
+	for i, id := range ids {
+		if newID, ok := newIDs[id]; ok {
+			ids[i] = newID
+			changed = true
+		}
+	}
+	if !changed {
+		return encoded, false, nil
+	}
+	rewritten, err := json.Marshal(ids)
+	return string(rewritten), true, err
+}
+
+// collectRewrites reads (id, timestamp) rows and computes new IDs for those not yet in the target format.
+// seedFor optionally overrides the derivation seed; by default the legacy ID itself is used.
+func (r *SQLRepository) collectRewrites(
+	ctx context.Context,
+	tx *sql.Tx,
+	gen idgen.Generator,
+	query string,
+	seedFor func(id string) (string, error),
+) ([]idRewrite, error) {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	type legacyRow struct {
+		id string
+		at time.Time
+	}
+	var legacy []legacyRow
+	for rows.Next() {
+		var row legacyRow
+		if err := rows.Scan(&row.id, &row.at); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if !gen.Valid(row.id) {
+			legacy = append(legacy, row)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	rewrites := make([]idRewrite, 0, len(legacy))
+	for _, row := range legacy {
+		seed := row.id
+		if seedFor != nil {
+			if seed, err = seedFor(row.id); err != nil {
+				return nil, err
+			}
+		}
+		rewrites = append(rewrites, idRewrite{oldID: row.id, newID: gen.Derive(row.at, seed)})
+	}
+
+	return rewrites, nil
+}
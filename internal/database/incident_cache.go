@@ -0,0 +1,174 @@
+package database
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+)
+
+// incidentCache keeps the alerts of recently read incidents by incident ID, so listing
+// incidents doesn't join every incident's alerts again. Entries expire after the TTL and
+// the least recently used are evicted beyond the maximum; writes through the repository
+// invalidate the incidents they touch, and the TTL bounds how long writes of other
+// replicas go unseen.
+type incidentCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	metrics    observability.Metrics
+
+	entries map[string]*list.Element // incident ID -> element holding a *incidentCacheEntry
+	lru     *list.List               // Most recently used first
+	byAlert map[string]map[string]bool
+	version uint64 // Bumped on every invalidation, so reads racing a write aren't cached
+}
+
+type incidentCacheEntry struct {
+	incidentID string
+	alerts     []domain.Alert
+	expiresAt  time.Time
+}
+
+func newIncidentCache(ttl time.Duration, maxEntries int, metrics observability.Metrics) *incidentCache {
+	return &incidentCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		metrics:    metrics,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		byAlert:    make(map[string]map[string]bool),
+	}
+}
+
+// EnableCache caches the alerts of up to maxEntries incidents for ttl. Cache hits and
+// misses are counted as repository_cache_hits_total and repository_cache_misses_total.
+func (r *SQLRepository) EnableCache(ttl time.Duration, maxEntries int, metrics observability.Metrics) {
+	if ttl <= 0 || maxEntries <= 0 {
+		r.cache = nil
+		return
+	}
+	r.cache = newIncidentCache(ttl, maxEntries, metrics)
+}
+
+// get returns the cached alerts of an incident with the cache version, which put needs
+// to store what is read on a miss. A nil cache always misses.
+func (c *incidentCache) get(incidentID string) ([]domain.Alert, uint64, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[incidentID]; ok {
+		entry := elem.Value.(*incidentCacheEntry)
+		if time.Now().Before(entry.expiresAt) {
+			c.lru.MoveToFront(elem)
+			c.count("repository_cache_hits_total")
+			return append([]domain.Alert(nil), entry.alerts...), c.version, true
+		}
+		c.remove(elem)
+	}
+	c.count("repository_cache_misses_total")
+	return nil, c.version, false
+}
+
+// put caches the alerts of an incident read at the given version, unless something was
+// invalidated since
+func (c *incidentCache) put(incidentID string, alerts []domain.Alert, version uint64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if version != c.version {
+		return
+	}
+	if elem, ok := c.entries[incidentID]; ok {
+		c.remove(elem)
+	}
+	entry := &incidentCacheEntry{
+		incidentID: incidentID,
+		alerts:     append([]domain.Alert(nil), alerts...),
+		expiresAt:  time.Now().Add(c.ttl),
+	}
+	c.entries[incidentID] = c.lru.PushFront(entry)
+	for _, alert := range alerts {
+		if c.byAlert[alert.ID] == nil {
+			c.byAlert[alert.ID] = make(map[string]bool)
+		}
+		c.byAlert[alert.ID][incidentID] = true
+	}
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// invalidateIncidents drops the cached alerts of the incidents
+func (c *incidentCache) invalidateIncidents(incidentIDs ...string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	for _, id := range incidentIDs {
+		if elem, ok := c.entries[id]; ok {
+			c.remove(elem)
+		}
+	}
+}
+
+// invalidateAlerts drops the cached incidents holding any of the alerts
+func (c *incidentCache) invalidateAlerts(alertIDs ...string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	for _, alertID := range alertIDs {
+		for incidentID := range c.byAlert[alertID] {
+			if elem, ok := c.entries[incidentID]; ok {
+				c.remove(elem)
+			}
+		}
+	}
+}
+
+// clear drops every cached incident, e.g. after a bulk delete
+func (c *incidentCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.byAlert = make(map[string]map[string]bool)
+}
+
+// remove drops an element; the caller holds the lock
+func (c *incidentCache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*incidentCacheEntry)
+	delete(c.entries, entry.incidentID)
+	for _, alert := range entry.alerts {
+		delete(c.byAlert[alert.ID], entry.incidentID)
+		if len(c.byAlert[alert.ID]) == 0 {
+			delete(c.byAlert, alert.ID)
+		}
+	}
+}
+
+func (c *incidentCache) count(name string) {
+	if c.metrics != nil {
+		c.metrics.IncCounter(name, nil)
+	}
+}
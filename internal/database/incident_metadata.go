@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"incident-teller/internal/domain"
+)
+
+// SetIncidentMetadata replaces the severity, tags and custom fields of an incident, or
+// removes them if the metadata is empty
+func (r *SQLRepository) SetIncidentMetadata(ctx context.Context, metadata domain.IncidentMetadata) error {
+	if metadata.IsEmpty() {
+		_, err := r.db.ExecContext(ctx, r.dialect.Rebind("DELETE FROM incident_metadata WHERE incident_id = ?"), metadata.IncidentID)
+		if err != nil {
+			return fmt.Errorf("failed to delete incident metadata: %w", err)
+		}
+		return nil
+	}
+
+	tags, err := json.Marshal(metadata.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal incident tags: %w", err)
+	}
+	customFields, err := json.Marshal(metadata.CustomFields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal incident custom fields: %w", err)
+	}
+
+	query := `
+		INSERT INTO incident_metadata (incident_id, severity, tags, custom_fields, updated_by, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	` + r.dialect.OnConflictUpdate([]string{"incident_id"}, []string{"severity", "tags", "custom_fields", "updated_by", "updated_at"})
+
+	_, err = r.db.ExecContext(ctx, r.dialect.Rebind(query), metadata.IncidentID, metadata.Severity,
+		string(tags), string(customFields), metadata.UpdatedBy, metadata.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save incident metadata: %w", err)
+	}
+	return nil
+}
+
+// getIncidentMetadata returns the metadata of every incident that has some, by incident ID
+func (r *SQLRepository) getIncidentMetadata(ctx context.Context) (map[string]domain.IncidentMetadata, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT incident_id, severity, tags, custom_fields, updated_by, updated_at
+		FROM incident_metadata
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident metadata: %w", err)
+	}
+	defer rows.Close()
+
+	metadata := make(map[string]domain.IncidentMetadata)
+	for rows.Next() {
+		var m domain.IncidentMetadata
+		var tags, customFields string
+		if err := rows.Scan(&m.IncidentID, &m.Severity, &tags, &customFields, &m.UpdatedBy, &m.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan incident metadata: %w", err)
+		}
+		if err := json.Unmarshal([]byte(tags), &m.Tags); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal incident tags: %w", err)
+		}
+		if err := json.Unmarshal([]byte(customFields), &m.CustomFields); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal incident custom fields: %w", err)
+		}
+		metadata[m.IncidentID] = m
+	}
+	return metadata, rows.Err()
+}
+
+// withIncidentMetadata fills the severity, tags, custom fields, pinned root cause and
+// lifecycle state of the incidents
+func (r *SQLRepository) withIncidentMetadata(ctx context.Context, incidents []domain.Incident) ([]domain.Incident, error) {
+	if len(incidents) == 0 {
+		return incidents, nil
+	}
+	metadata, err := r.getIncidentMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := r.GetRootCauseOverrides(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	states, err := r.getIncidentStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pinned := make(map[string]domain.RootCauseOverride, len(overrides))
+	for _, override := range overrides {
+		pinned[override.IncidentID] = override
+	}
+	for i := range incidents {
+		if m, ok := metadata[incidents[i].ID]; ok {
+			incidents[i].SetMetadata(m)
+		}
+		if override, ok := pinned[incidents[i].ID]; ok {
+			incidents[i].RootCauseOverride = &override
+		}
+		if state, ok := states[incidents[i].ID]; ok {
+			incidents[i].State = state.To
+			incidents[i].StateChangedAt = &state.ChangedAt
+		}
+	}
+	return incidents, nil
+}
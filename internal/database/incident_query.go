@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// riskRankExpr mirrors domain.Incident.RiskLevel as a 0-3 rank over the grouped alerts
+const riskRankExpr = `CASE
+			WHEN SUM(CASE WHEN a.status = 'CRITICAL' THEN 1 ELSE 0 END) >= 3
+				OR COUNT(DISTINCT a.host) >= 3 OR COUNT(DISTINCT a.resource_type) >= 3 THEN 3
+			WHEN SUM(CASE WHEN a.status = 'CRITICAL' THEN 1 ELSE 0 END) >= 2
+				OR COUNT(DISTINCT a.host) >= 2 OR COUNT(DISTINCT a.resource_type) >= 2 THEN 2
+			WHEN SUM(CASE WHEN a.status = 'CRITICAL' THEN 1 ELSE 0 END) >= 1 THEN 1
+			ELSE 0
+		END`
+
+// priorityRankExpr mirrors domain.PriorityRank of domain.Incident.Priority: the manual
+// priority if set, else the template's default priority, else the one following from the
+// risk rank
+var priorityRankExpr = `CASE COALESCE(p.priority, i.default_priority)
+			WHEN 'P1' THEN 3 WHEN 'P2' THEN 2 WHEN 'P3' THEN 1 WHEN 'P4' THEN 0
+			ELSE ` + riskRankExpr + `
+		END`
+
+// QueryIncidents retrieves incidents matching a free-text search, ordered by the requested key.
+// Filtering and ordering happen in SQL; the search uses LIKE on sqlite and mysql and a
+// full-text match (tsvector) on postgres.
+func (r *SQLRepository) QueryIncidents(ctx context.Context, q domain.IncidentQuery) ([]domain.Incident, error) {
+	now := time.Now()
+	var args []interface{}
+
+	where := ""
+	if search := strings.TrimSpace(q.Search); search != "" {
+		clause, searchArgs := r.searchClause(search)
+		where = "WHERE " + clause
+		args = append(args, searchArgs...)
+	}
+
+	var orderExpr string
+	switch q.SortBy {
+	case domain.SortByDuration:
+		orderExpr = r.durationExpr()
+		args = append(args, now.UTC())
+	case domain.SortByRisk:
+		orderExpr = riskRankExpr
+	case domain.SortByEvents:
+		orderExpr = "COUNT(ia.alert_id)"
+	case domain.SortByPriority:
+		orderExpr = priorityRankExpr
+	case domain.SortByStartedAt, "":
+		orderExpr = "i.started_at"
+	default:
+		return nil, fmt.Errorf("unsupported incident sort %q", q.SortBy)
+	}
+
+	direction := "DESC"
+	if q.Ascending {
+		direction = "ASC"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT i.id, i.title, i.status, i.started_at, i.resolved_at, COALESCE(p.priority, ''), i.template, i.default_priority
+		FROM incidents i
+		LEFT JOIN incident_priorities p ON p.incident_id = i.id
+		LEFT JOIN incident_alerts ia ON ia.incident_id = i.id
+		LEFT JOIN alerts a ON a.id = ia.alert_id
+		%s
+		GROUP BY i.id, i.title, i.status, i.started_at, i.resolved_at, p.priority, i.template, i.default_priority
+		ORDER BY %s %s, i.started_at DESC
+	`, where, orderExpr, direction)
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incidents: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []domain.Incident
+	for rows.Next() {
+		var incident domain.Incident
+		var resolvedAt sql.NullTime
+
+		if err := rows.Scan(
+			&incident.ID, &incident.Title, &incident.Status,
+			&incident.StartedAt, &resolvedAt, &incident.PriorityOverride, &incident.Template, &incident.DefaultPriority,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan incident: %w", err)
+		}
+
+		if resolvedAt.Valid {
+			incident.ResolvedAt = &resolvedAt.Time
+		}
+		incidents = append(incidents, incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Load alerts after the result set is closed so sqlite doesn't need a second connection
+	rows.Close()
+	for i := range incidents {
+		alerts, err := r.getIncidentAlerts(ctx, incidents[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get incident alerts: %w", err)
+		}
+		incidents[i].Events = alerts
+	}
+
+	return r.withIncidentMetadata(ctx, incidents)
+}
+
+// searchClause matches the search text against the incident title and the host,
+// chart and name of any of its alerts
+func (r *SQLRepository) searchClause(search string) (string, []interface{}) {
+	if r.dialect == DialectPostgres {
+		clause := `(to_tsvector('simple', i.title) @@ plainto_tsquery('simple', ?)
+			OR EXISTS (
+				SELECT 1 FROM incident_alerts sia
+				JOIN alerts sa ON sa.id = sia.alert_id
+				WHERE sia.incident_id = i.id
+				  AND to_tsvector('simple', sa.host || ' ' || sa.chart || ' ' || sa.name) @@ plainto_tsquery('simple', ?)
+			))`
+		return clause, []interface{}{search, search}
+	}
+
+	pattern := "%" + escapeLike(strings.ToLower(search)) + "%"
+	clause := `(LOWER(i.title) LIKE ? ESCAPE '!'
+			OR EXISTS (
+				SELECT 1 FROM incident_alerts sia
+				JOIN alerts sa ON sa.id = sia.alert_id
+				WHERE sia.incident_id = i.id
+				  AND (LOWER(sa.host) LIKE ? ESCAPE '!'
+				    OR LOWER(sa.chart) LIKE ? ESCAPE '!'
+				    OR LOWER(sa.name) LIKE ? ESCAPE '!')
+			))`
+	return clause, []interface{}{pattern, pattern, pattern, pattern}
+}
+
+// durationExpr computes the incident duration in the dialect's native units; active
+// incidents are measured up to the bound "now" argument
+func (r *SQLRepository) durationExpr() string {
+	switch r.dialect {
+	case DialectPostgres:
+		return "EXTRACT(EPOCH FROM (COALESCE(i.resolved_at, ?) - i.started_at))"
+	case DialectMySQL:
+		return "TIMESTAMPDIFF(MICROSECOND, i.started_at, COALESCE(i.resolved_at, ?))"
+	default:
+		return "(julianday(COALESCE(i.resolved_at, ?)) - julianday(i.started_at))"
+	}
+}
+
+// escapeLike escapes LIKE wildcards using '!' as the escape character
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"incident-teller/internal/domain"
+)
+
+// SetIncidentState records a state transition and makes its state the incident's state,
+// provided the incident is still in the transition's From state. An incident without a
+// stored state takes the first transition recorded for it. It returns
+// domain.ErrStateConflict if another transition got there first.
+func (r *SQLRepository) SetIncidentState(ctx context.Context, transition domain.StateTransition) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, r.dialect.Rebind(`
+		UPDATE incident_states SET state = ?, changed_by = ?, changed_at = ?
+		WHERE incident_id = ? AND state = ?
+	`), string(transition.To), transition.ChangedBy, transition.ChangedAt, transition.IncidentID, string(transition.From))
+	if err != nil {
+		return fmt.Errorf("failed to set incident state: %w", err)
+	}
+	changed, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to set incident state: %w", err)
+	}
+	if changed == 0 {
+		// Either the incident has no stored state yet or it moved on
+		query := `
+			INSERT INTO incident_states (incident_id, state, changed_by, changed_at)
+			VALUES (?, ?, ?, ?)
+		` + r.dialect.OnConflictIgnore([]string{"incident_id"})
+		result, err = tx.ExecContext(ctx, r.dialect.Rebind(query),
+			transition.IncidentID, string(transition.To), transition.ChangedBy, transition.ChangedAt)
+		if err != nil {
+			return fmt.Errorf("failed to set incident state: %w", err)
+		}
+		if changed, err = result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to set incident state: %w", err)
+		}
+		if changed == 0 {
+			return domain.ErrStateConflict
+		}
+	}
+
+	// The incident's state row is held by this transaction until it commits, so no
+	// concurrent transition of the incident takes the same sequence number
+	var sequence int
+	err = tx.QueryRowContext(ctx, r.dialect.Rebind("SELECT COALESCE(MAX(sequence_order) + 1, 0) FROM incident_state_transitions WHERE incident_id = ?"),
+		transition.IncidentID).Scan(&sequence)
+	if err != nil {
+		return fmt.Errorf("failed to number state transition: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, r.dialect.Rebind(`
+		INSERT INTO incident_state_transitions
+			(incident_id, sequence_order, from_state, to_state, changed_by, note, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`), transition.IncidentID, sequence, string(transition.From), string(transition.To),
+		transition.ChangedBy, transition.Note, transition.ChangedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record state transition: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetStateTransitions returns the state transitions of an incident, oldest first
+func (r *SQLRepository) GetStateTransitions(ctx context.Context, incidentID string) ([]domain.StateTransition, error) {
+	query := `
+		SELECT from_state, to_state, changed_by, note, changed_at
+		FROM incident_state_transitions
+		WHERE incident_id = ?
+		ORDER BY sequence_order
+	`
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query state transitions: %w", err)
+	}
+	defer rows.Close()
+
+	transitions := []domain.StateTransition{}
+	for rows.Next() {
+		transition := domain.StateTransition{IncidentID: incidentID}
+		if err := rows.Scan(&transition.From, &transition.To, &transition.ChangedBy, &transition.Note, &transition.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan state transition: %w", err)
+		}
+		transitions = append(transitions, transition)
+	}
+	return transitions, rows.Err()
+}
+
+// getIncidentStates returns the current state of every incident that has one, by incident ID
+func (r *SQLRepository) getIncidentStates(ctx context.Context) (map[string]domain.StateTransition, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT incident_id, state, changed_by, changed_at FROM incident_states")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident states: %w", err)
+	}
+	defer rows.Close()
+
+	states := make(map[string]domain.StateTransition)
+	for rows.Next() {
+		var s domain.StateTransition
+		if err := rows.Scan(&s.IncidentID, &s.To, &s.ChangedBy, &s.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan incident state: %w", err)
+		}
+		states[s.IncidentID] = s
+	}
+	return states, rows.Err()
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// AcquireLease takes or renews a lease for holder until ttl from now if it is free,
+// expired or already held by holder, and returns the current lease. Expiry is judged by
+// the clock of the calling replica.
+func (r *SQLRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (domain.Lease, bool, error) {
+	now := time.Now().UTC()
+	expiresAt := now.Add(ttl)
+
+	// Renew the lease or take over an expired one. acquired_at is assigned first since
+	// MySQL sees the new holder in later assignments.
+	update := `
+		UPDATE leases
+		SET acquired_at = CASE WHEN holder = ? THEN acquired_at ELSE ? END,
+			holder = ?,
+			expires_at = ?
+		WHERE name = ? AND (holder = ? OR expires_at < ?)
+	`
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind(update), holder, now, holder, expiresAt, name, holder, now)
+	if err != nil {
+		return domain.Lease{}, false, fmt.Errorf("failed to renew lease: %w", err)
+	}
+
+	if updated, err := result.RowsAffected(); err == nil && updated == 0 {
+		// Nobody has held the lease yet; a replica inserting it first wins
+		insert := `
+			INSERT INTO leases (name, holder, acquired_at, expires_at)
+			VALUES (?, ?, ?, ?)
+		` + r.dialect.OnConflictUpdate([]string{"name"}, nil, "expires_at = leases.expires_at")
+		if _, err := r.db.ExecContext(ctx, r.dialect.Rebind(insert), name, holder, now, expiresAt); err != nil {
+			return domain.Lease{}, false, fmt.Errorf("failed to create lease: %w", err)
+		}
+	}
+
+	lease := domain.Lease{Name: name}
+	query := `SELECT holder, acquired_at, expires_at FROM leases WHERE name = ?`
+	if err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), name).
+		Scan(&lease.Holder, &lease.AcquiredAt, &lease.ExpiresAt); err != nil {
+		return domain.Lease{}, false, fmt.Errorf("failed to get lease: %w", err)
+	}
+	return lease, lease.Holder == holder, nil
+}
+
+// ReleaseLease expires a lease held by holder, so another replica can take it right away
+func (r *SQLRepository) ReleaseLease(ctx context.Context, name, holder string) error {
+	query := `UPDATE leases SET expires_at = ? WHERE name = ? AND holder = ?`
+	if _, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), time.Now().UTC(), name, holder); err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migration files live in migrations/<dialect>/NNNN_name.up.sql (and .down.sql)
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Migrator applies and rolls back the embedded migrations for one dialect, recording
+// applied versions in the schema_version table
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+// NewMigrator loads the embedded migrations for the dialect
+func NewMigrator(db *sql.DB, dialect Dialect) (*Migrator, error) {
+	migrations, err := loadMigrations(dialect)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// Migrator returns a migrator for the repository's database
+func (r *SQLRepository) Migrator() (*Migrator, error) {
+	return NewMigrator(r.db, r.dialect)
+}
+
+func loadMigrations(dialect Dialect) ([]Migration, error) {
+	dir := path.Join("migrations", string(dialect))
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for dialect %q: %w", dialect, err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		prefix, label, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", name)
+		}
+
+		content, err := fs.ReadFile(migrationFiles, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrations returns the known migrations in version order
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+func (m *Migrator) ensureVersionTable(ctx context.Context) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		name %s NOT NULL,
+		applied_at %s NOT NULL
+	)`, m.dialect.KeyType(), m.dialect.TimestampType())
+
+	if _, err := m.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_version table: %w", err)
+	}
+	return nil
+}
+
+// applied returns the applied versions and when they were applied
+func (m *Migrator) applied(ctx context.Context) (map[int]time.Time, error) {
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version, applied_at FROM schema_version")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_version: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_version: %w", err)
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+// Version returns the highest applied migration version, or 0 for an empty database
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+	return version, nil
+}
+
+// Status lists every known migration and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if at, ok := applied[migration.Version]; ok {
+			at := at
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &at
+		}
+	}
+	return statuses, nil
+}
+
+// Up applies all pending migrations in order and returns how many were applied
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		insert := m.dialect.Rebind("INSERT INTO schema_version (version, name, applied_at) VALUES (?, ?, ?)")
+		if err := m.run(ctx, migration.Up, insert, migration.Version, migration.Name, time.Now().UTC()); err != nil {
+			return count, fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Down rolls back the given number of most recently applied migrations and returns
+// how many were rolled back
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return count, fmt.Errorf("migration %04d_%s cannot be rolled back", migration.Version, migration.Name)
+		}
+
+		remove := m.dialect.Rebind("DELETE FROM schema_version WHERE version = ?")
+		if err := m.run(ctx, migration.Down, remove, migration.Version); err != nil {
+			return count, fmt.Errorf("rollback of %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// run executes a migration script and the schema_version bookkeeping in one transaction.
+// MySQL commits DDL implicitly, so there a failed script may leave partial changes.
+func (m *Migrator) run(ctx context.Context, script, bookkeeping string, args ...interface{}) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, statement := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		return fmt.Errorf("failed to update schema_version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// splitStatements splits a script on semicolons at line ends, dropping comment lines,
+// so drivers without multi-statement support can run it
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+
+	return statements
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS incident_alerts;
+DROP TABLE IF EXISTS incidents;
+DROP TABLE IF EXISTS alerts;
+DROP TABLE IF EXISTS metadata;
@@ -0,0 +1,52 @@
+-- MySQL cannot index TEXT columns without a prefix length, so keys use VARCHAR
+CREATE TABLE IF NOT EXISTS alerts (
+	id VARCHAR(64) PRIMARY KEY,
+	external_id BIGINT UNSIGNED NOT NULL,
+	host VARCHAR(255) NOT NULL,
+	chart VARCHAR(255) NOT NULL,
+	family VARCHAR(255) NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	status VARCHAR(32) NOT NULL,
+	old_status VARCHAR(32) NOT NULL,
+	value DOUBLE NOT NULL,
+	occurred_at DATETIME(6) NOT NULL,
+	description TEXT,
+	resource_type VARCHAR(32) NOT NULL,
+	labels TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	INDEX idx_alerts_external_id (external_id),
+	INDEX idx_alerts_occurred_at (occurred_at),
+	INDEX idx_alerts_host (host),
+	INDEX idx_alerts_resource_type (resource_type)
+);
+
+CREATE TABLE IF NOT EXISTS incidents (
+	id VARCHAR(64) PRIMARY KEY,
+	title VARCHAR(512) NOT NULL,
+	status VARCHAR(32) NOT NULL,
+	started_at DATETIME(6) NOT NULL,
+	resolved_at DATETIME(6) NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	INDEX idx_incidents_status (status),
+	INDEX idx_incidents_started_at (started_at),
+	INDEX idx_incidents_resolved_at (resolved_at)
+);
+
+CREATE TABLE IF NOT EXISTS incident_alerts (
+	incident_id VARCHAR(64) NOT NULL,
+	alert_id VARCHAR(64) NOT NULL,
+	sequence_order INT NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (incident_id, alert_id),
+	INDEX idx_incident_alerts_alert_id (alert_id),
+	INDEX idx_incident_alerts_sequence_order (sequence_order),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE,
+	FOREIGN KEY (alert_id) REFERENCES alerts(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS metadata (
+	`key` VARCHAR(191) PRIMARY KEY,
+	value TEXT NOT NULL,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
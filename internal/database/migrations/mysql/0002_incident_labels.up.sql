@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS incident_labels (
+	incident_id VARCHAR(64) NOT NULL,
+	label_key VARCHAR(191) NOT NULL,
+	label_value VARCHAR(255) NOT NULL,
+	started_at DATETIME(6) NOT NULL,
+	resolution_seconds BIGINT NULL,
+	PRIMARY KEY (incident_id, label_key),
+	INDEX idx_incident_labels_key_value (label_key, label_value),
+	INDEX idx_incident_labels_key_started_at (label_key, started_at),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS propagation_patterns (
+	host VARCHAR(255) NOT NULL DEFAULT '',
+	service VARCHAR(255) NOT NULL DEFAULT '',
+	from_resource VARCHAR(32) NOT NULL,
+	to_resource VARCHAR(32) NOT NULL,
+	probability DOUBLE NOT NULL,
+	window_seconds BIGINT NOT NULL,
+	observations INT NOT NULL,
+	learned_at DATETIME(6) NOT NULL,
+	PRIMARY KEY (host, service, from_resource, to_resource)
+);
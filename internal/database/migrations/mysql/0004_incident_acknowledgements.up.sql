@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS incident_acknowledgements (
+	incident_id VARCHAR(64) PRIMARY KEY,
+	acknowledged_by VARCHAR(255) NOT NULL,
+	acknowledged_at DATETIME(6) NOT NULL,
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE alerts DROP INDEX idx_alerts_source, DROP COLUMN source;
@@ -0,0 +1 @@
+ALTER TABLE alerts ADD COLUMN source VARCHAR(64) NOT NULL DEFAULT '', ADD INDEX idx_alerts_source (source);
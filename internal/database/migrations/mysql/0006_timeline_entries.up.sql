@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS timeline_entries (
+	incident_id VARCHAR(64) NOT NULL,
+	sequence_order INT NOT NULL,
+	alert_ids TEXT NOT NULL,
+	occurred_at DATETIME(6) NOT NULL,
+	entry_type VARCHAR(32) NOT NULL,
+	message TEXT NOT NULL,
+	severity VARCHAR(32) NOT NULL,
+	since_start_ms BIGINT NULL,
+	caused_by TEXT NOT NULL,
+	resource_type VARCHAR(32) NOT NULL,
+	PRIMARY KEY (incident_id, sequence_order),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS incident_tickets (
+	incident_id VARCHAR(64) PRIMARY KEY,
+	tracker VARCHAR(32) NOT NULL,
+	ticket_key VARCHAR(255) NOT NULL,
+	url TEXT NOT NULL,
+	created_at DATETIME(6) NOT NULL,
+	resolved_at DATETIME(6) NULL,
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
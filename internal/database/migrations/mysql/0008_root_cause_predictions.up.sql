@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS root_cause_predictions (
+	incident_id VARCHAR(64) NOT NULL,
+	model_version VARCHAR(64) NOT NULL,
+	alert_id VARCHAR(64) NOT NULL,
+	raw_score DOUBLE NOT NULL,
+	confidence DOUBLE NOT NULL,
+	predicted_at DATETIME(6) NOT NULL,
+	correct BOOLEAN NULL,
+	feedback_at DATETIME(6) NULL,
+	PRIMARY KEY (incident_id, model_version),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
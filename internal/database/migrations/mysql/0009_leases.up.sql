@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS leases (
+	name VARCHAR(64) PRIMARY KEY,
+	holder VARCHAR(255) NOT NULL,
+	acquired_at DATETIME(6) NOT NULL,
+	expires_at DATETIME(6) NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE alerts DROP INDEX idx_alerts_fingerprint, DROP COLUMN fingerprint;
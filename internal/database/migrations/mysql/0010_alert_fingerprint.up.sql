@@ -0,0 +1,2 @@
+-- Alerts stored before fingerprints were introduced keep a NULL fingerprint
+ALTER TABLE alerts ADD COLUMN fingerprint VARCHAR(255) NULL, ADD UNIQUE INDEX idx_alerts_fingerprint (fingerprint);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS incident_priorities (
+	incident_id VARCHAR(64) PRIMARY KEY,
+	priority VARCHAR(2) NOT NULL,
+	set_by VARCHAR(255) NOT NULL,
+	set_at DATETIME(6) NOT NULL,
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS incident_priority_changes (
+	incident_id VARCHAR(64) NOT NULL,
+	sequence_order INT NOT NULL,
+	priority VARCHAR(2) NOT NULL,
+	previous_priority VARCHAR(2) NOT NULL,
+	changed_by VARCHAR(255) NOT NULL,
+	reason TEXT NOT NULL,
+	changed_at DATETIME(6) NOT NULL,
+	PRIMARY KEY (incident_id, sequence_order),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+	id VARCHAR(64) PRIMARY KEY,
+	actor VARCHAR(255) NOT NULL,
+	api_key VARCHAR(64) NOT NULL,
+	remote_addr VARCHAR(255) NOT NULL,
+	method VARCHAR(16) NOT NULL,
+	path VARCHAR(1024) NOT NULL,
+	status INT NOT NULL,
+	payload MEDIUMTEXT NOT NULL,
+	changes MEDIUMTEXT NOT NULL,
+	created_at DATETIME(6) NOT NULL,
+	INDEX idx_audit_log_created_at (created_at),
+	INDEX idx_audit_log_actor (actor)
+);
@@ -0,0 +1 @@
+ALTER TABLE incidents DROP COLUMN default_priority, DROP COLUMN template;
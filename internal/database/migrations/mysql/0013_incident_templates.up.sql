@@ -0,0 +1,3 @@
+ALTER TABLE incidents
+	ADD COLUMN template VARCHAR(64) NOT NULL DEFAULT '',
+	ADD COLUMN default_priority VARCHAR(2) NOT NULL DEFAULT '';
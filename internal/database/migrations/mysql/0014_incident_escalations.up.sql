@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS incident_escalations (
+	incident_id VARCHAR(64) NOT NULL,
+	level INT NOT NULL,
+	priority VARCHAR(8) NOT NULL,
+	target TEXT NOT NULL,
+	escalated_at DATETIME(6) NOT NULL,
+	PRIMARY KEY (incident_id, level),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+	id VARCHAR(64) PRIMARY KEY,
+	url TEXT NOT NULL,
+	secret TEXT NOT NULL,
+	events TEXT NOT NULL,
+	enabled BOOLEAN NOT NULL,
+	created_at DATETIME(6) NOT NULL,
+	updated_at DATETIME(6) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_failed_deliveries (
+	id VARCHAR(64) PRIMARY KEY,
+	webhook_id VARCHAR(64) NOT NULL,
+	event_type VARCHAR(64) NOT NULL,
+	content_type VARCHAR(255) NOT NULL,
+	payload MEDIUMTEXT NOT NULL,
+	attempts INT NOT NULL,
+	status_code INT NOT NULL,
+	error TEXT NOT NULL,
+	failed_at DATETIME(6) NOT NULL,
+	INDEX idx_webhook_failed_deliveries_failed_at (failed_at),
+	FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS incident_metadata (
+	incident_id VARCHAR(64) PRIMARY KEY,
+	severity VARCHAR(64) NOT NULL,
+	tags TEXT NOT NULL,
+	custom_fields TEXT NOT NULL,
+	updated_by VARCHAR(255) NOT NULL,
+	updated_at DATETIME(6) NOT NULL,
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS root_cause_overrides (
+	incident_id VARCHAR(64) PRIMARY KEY,
+	alert_id VARCHAR(64) NOT NULL,
+	explanation TEXT NOT NULL,
+	set_by VARCHAR(255) NOT NULL,
+	set_at DATETIME(6) NOT NULL,
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
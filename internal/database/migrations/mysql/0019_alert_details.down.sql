@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS alert_acknowledgements;
+
+ALTER TABLE alerts DROP COLUMN raw_payload;
@@ -0,0 +1,9 @@
+ALTER TABLE alerts ADD COLUMN raw_payload MEDIUMTEXT;
+
+CREATE TABLE IF NOT EXISTS alert_acknowledgements (
+	alert_id VARCHAR(64) PRIMARY KEY,
+	acknowledged_by VARCHAR(255) NOT NULL,
+	note TEXT NOT NULL,
+	acknowledged_at DATETIME(6) NOT NULL,
+	FOREIGN KEY (alert_id) REFERENCES alerts(id) ON DELETE CASCADE
+);
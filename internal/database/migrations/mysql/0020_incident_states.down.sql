@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS incident_state_transitions;
+DROP TABLE IF EXISTS incident_states;
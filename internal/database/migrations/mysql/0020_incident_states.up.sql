@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS incident_states (
+	incident_id VARCHAR(64) PRIMARY KEY,
+	state VARCHAR(16) NOT NULL,
+	changed_by VARCHAR(255) NOT NULL,
+	changed_at DATETIME(6) NOT NULL,
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS incident_state_transitions (
+	incident_id VARCHAR(64) NOT NULL,
+	sequence_order INT NOT NULL,
+	from_state VARCHAR(16) NOT NULL,
+	to_state VARCHAR(16) NOT NULL,
+	changed_by VARCHAR(255) NOT NULL,
+	note TEXT NOT NULL,
+	changed_at DATETIME(6) NOT NULL,
+	PRIMARY KEY (incident_id, sequence_order),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
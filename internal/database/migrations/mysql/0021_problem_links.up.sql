@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS problem_links (
+	incident_id VARCHAR(64) PRIMARY KEY,
+	problem_id VARCHAR(64) NOT NULL,
+	similarity DOUBLE NOT NULL,
+	linked_at DATETIME(6) NOT NULL,
+	INDEX idx_problem_links_problem (problem_id),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS action_items (
+	id VARCHAR(64) PRIMARY KEY,
+	incident_id VARCHAR(64) NOT NULL,
+	description TEXT NOT NULL,
+	owner VARCHAR(255) NOT NULL,
+	due_at DATETIME(6) NULL,
+	status VARCHAR(16) NOT NULL,
+	created_at DATETIME(6) NOT NULL,
+	updated_at DATETIME(6) NOT NULL,
+	overdue_notified_at DATETIME(6) NULL,
+	INDEX idx_action_items_incident (incident_id),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
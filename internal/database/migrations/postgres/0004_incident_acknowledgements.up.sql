@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS incident_acknowledgements (
+	incident_id TEXT PRIMARY KEY,
+	acknowledged_by TEXT NOT NULL,
+	acknowledged_at TIMESTAMP NOT NULL,
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_alerts_fingerprint;
+
+ALTER TABLE alerts DROP COLUMN fingerprint;
@@ -0,0 +1,2 @@
+ALTER TABLE incidents DROP COLUMN default_priority;
+ALTER TABLE incidents DROP COLUMN template;
@@ -0,0 +1,2 @@
+ALTER TABLE incidents ADD COLUMN template TEXT NOT NULL DEFAULT '';
+ALTER TABLE incidents ADD COLUMN default_priority TEXT NOT NULL DEFAULT '';
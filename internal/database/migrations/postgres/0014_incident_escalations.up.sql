@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS incident_escalations (
+	incident_id TEXT NOT NULL,
+	level INTEGER NOT NULL,
+	priority TEXT NOT NULL,
+	target TEXT NOT NULL,
+	escalated_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (incident_id, level),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
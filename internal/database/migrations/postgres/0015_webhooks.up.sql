@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+	id TEXT PRIMARY KEY,
+	url TEXT NOT NULL,
+	secret TEXT NOT NULL,
+	events TEXT NOT NULL,
+	enabled BOOLEAN NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_failed_deliveries (
+	id TEXT PRIMARY KEY,
+	webhook_id TEXT NOT NULL,
+	event_type TEXT NOT NULL,
+	content_type TEXT NOT NULL,
+	payload TEXT NOT NULL,
+	attempts INTEGER NOT NULL,
+	status_code INTEGER NOT NULL,
+	error TEXT NOT NULL,
+	failed_at TIMESTAMP NOT NULL,
+	FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_failed_deliveries_failed_at ON webhook_failed_deliveries(failed_at);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS alert_samples (
+	alert_id TEXT PRIMARY KEY,
+	samples TEXT NOT NULL,
+	sampled_at TIMESTAMP NOT NULL,
+	FOREIGN KEY (alert_id) REFERENCES alerts(id) ON DELETE CASCADE
+);
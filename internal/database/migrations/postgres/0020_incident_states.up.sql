@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS incident_states (
+	incident_id TEXT PRIMARY KEY,
+	state TEXT NOT NULL,
+	changed_by TEXT NOT NULL,
+	changed_at TIMESTAMP NOT NULL,
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS incident_state_transitions (
+	incident_id TEXT NOT NULL,
+	sequence_order INTEGER NOT NULL,
+	from_state TEXT NOT NULL,
+	to_state TEXT NOT NULL,
+	changed_by TEXT NOT NULL,
+	note TEXT NOT NULL,
+	changed_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (incident_id, sequence_order),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
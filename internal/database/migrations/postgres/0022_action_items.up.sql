@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS action_items (
+	id TEXT PRIMARY KEY,
+	incident_id TEXT NOT NULL,
+	description TEXT NOT NULL,
+	owner TEXT NOT NULL,
+	due_at TIMESTAMP,
+	status TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	overdue_notified_at TIMESTAMP,
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_action_items_incident ON action_items(incident_id);
@@ -0,0 +1,54 @@
+-- IF NOT EXISTS lets databases created before versioned migrations adopt this baseline
+CREATE TABLE IF NOT EXISTS alerts (
+	id TEXT PRIMARY KEY,
+	external_id INTEGER NOT NULL,
+	host TEXT NOT NULL,
+	chart TEXT NOT NULL,
+	family TEXT NOT NULL,
+	name TEXT NOT NULL,
+	status TEXT NOT NULL,
+	old_status TEXT NOT NULL,
+	value REAL NOT NULL,
+	occurred_at TIMESTAMP NOT NULL,
+	description TEXT,
+	resource_type TEXT NOT NULL,
+	labels TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS incidents (
+	id TEXT PRIMARY KEY,
+	title TEXT NOT NULL,
+	status TEXT NOT NULL,
+	started_at TIMESTAMP NOT NULL,
+	resolved_at TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS incident_alerts (
+	incident_id TEXT NOT NULL,
+	alert_id TEXT NOT NULL,
+	sequence_order INTEGER NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (incident_id, alert_id),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE,
+	FOREIGN KEY (alert_id) REFERENCES alerts(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS metadata (
+	key TEXT PRIMARY KEY,
+	value TEXT NOT NULL,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_external_id ON alerts(external_id);
+CREATE INDEX IF NOT EXISTS idx_alerts_occurred_at ON alerts(occurred_at);
+CREATE INDEX IF NOT EXISTS idx_alerts_host ON alerts(host);
+CREATE INDEX IF NOT EXISTS idx_alerts_resource_type ON alerts(resource_type);
+CREATE INDEX IF NOT EXISTS idx_incidents_status ON incidents(status);
+CREATE INDEX IF NOT EXISTS idx_incidents_started_at ON incidents(started_at);
+CREATE INDEX IF NOT EXISTS idx_incidents_resolved_at ON incidents(resolved_at);
+CREATE INDEX IF NOT EXISTS idx_incident_alerts_incident_id ON incident_alerts(incident_id);
+CREATE INDEX IF NOT EXISTS idx_incident_alerts_alert_id ON incident_alerts(alert_id);
+CREATE INDEX IF NOT EXISTS idx_incident_alerts_sequence_order ON incident_alerts(sequence_order);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS incident_labels (
+	incident_id TEXT NOT NULL,
+	label_key TEXT NOT NULL,
+	label_value TEXT NOT NULL,
+	started_at TIMESTAMP NOT NULL,
+	resolution_seconds INTEGER,
+	PRIMARY KEY (incident_id, label_key),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_labels_key_value ON incident_labels(label_key, label_value);
+CREATE INDEX IF NOT EXISTS idx_incident_labels_key_started_at ON incident_labels(label_key, started_at);
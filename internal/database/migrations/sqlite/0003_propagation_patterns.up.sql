@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS propagation_patterns (
+	host TEXT NOT NULL DEFAULT '',
+	service TEXT NOT NULL DEFAULT '',
+	from_resource TEXT NOT NULL,
+	to_resource TEXT NOT NULL,
+	probability REAL NOT NULL,
+	window_seconds INTEGER NOT NULL,
+	observations INTEGER NOT NULL,
+	learned_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (host, service, from_resource, to_resource)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE alerts ADD COLUMN source TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_alerts_source ON alerts(source);
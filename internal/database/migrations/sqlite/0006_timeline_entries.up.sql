@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS timeline_entries (
+	incident_id TEXT NOT NULL,
+	sequence_order INTEGER NOT NULL,
+	alert_ids TEXT NOT NULL,
+	occurred_at TIMESTAMP NOT NULL,
+	entry_type TEXT NOT NULL,
+	message TEXT NOT NULL,
+	severity TEXT NOT NULL,
+	since_start_ms INTEGER,
+	caused_by TEXT NOT NULL,
+	resource_type TEXT NOT NULL,
+	PRIMARY KEY (incident_id, sequence_order),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS incident_tickets (
+	incident_id TEXT PRIMARY KEY,
+	tracker TEXT NOT NULL,
+	ticket_key TEXT NOT NULL,
+	url TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	resolved_at TIMESTAMP,
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
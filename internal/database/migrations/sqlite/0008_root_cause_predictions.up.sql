@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS root_cause_predictions (
+	incident_id TEXT NOT NULL,
+	model_version TEXT NOT NULL,
+	alert_id TEXT NOT NULL,
+	raw_score REAL NOT NULL,
+	confidence REAL NOT NULL,
+	predicted_at TIMESTAMP NOT NULL,
+	correct BOOLEAN,
+	feedback_at TIMESTAMP,
+	PRIMARY KEY (incident_id, model_version),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS leases (
+	name TEXT PRIMARY KEY,
+	holder TEXT NOT NULL,
+	acquired_at TIMESTAMP NOT NULL,
+	expires_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,4 @@
+-- Alerts stored before fingerprints were introduced keep a NULL fingerprint
+ALTER TABLE alerts ADD COLUMN fingerprint TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_fingerprint ON alerts(fingerprint);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS incident_priority_changes;
+DROP TABLE IF EXISTS incident_priorities;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS incident_priorities (
+	incident_id TEXT PRIMARY KEY,
+	priority TEXT NOT NULL,
+	set_by TEXT NOT NULL,
+	set_at TIMESTAMP NOT NULL,
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS incident_priority_changes (
+	incident_id TEXT NOT NULL,
+	sequence_order INTEGER NOT NULL,
+	priority TEXT NOT NULL,
+	previous_priority TEXT NOT NULL,
+	changed_by TEXT NOT NULL,
+	reason TEXT NOT NULL,
+	changed_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (incident_id, sequence_order),
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
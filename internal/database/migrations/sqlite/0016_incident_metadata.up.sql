@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS incident_metadata (
+	incident_id TEXT PRIMARY KEY,
+	severity TEXT NOT NULL,
+	tags TEXT NOT NULL,
+	custom_fields TEXT NOT NULL,
+	updated_by TEXT NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS root_cause_overrides;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS root_cause_overrides (
+	incident_id TEXT PRIMARY KEY,
+	alert_id TEXT NOT NULL,
+	explanation TEXT NOT NULL,
+	set_by TEXT NOT NULL,
+	set_at TIMESTAMP NOT NULL,
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+ALTER TABLE alerts ADD COLUMN raw_payload TEXT;
+
+CREATE TABLE IF NOT EXISTS alert_acknowledgements (
+	alert_id TEXT PRIMARY KEY,
+	acknowledged_by TEXT NOT NULL,
+	note TEXT NOT NULL,
+	acknowledged_at TIMESTAMP NOT NULL,
+	FOREIGN KEY (alert_id) REFERENCES alerts(id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS problem_links (
+	incident_id TEXT PRIMARY KEY,
+	problem_id TEXT NOT NULL,
+	similarity REAL NOT NULL,
+	linked_at TIMESTAMP NOT NULL,
+	FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_problem_links_problem ON problem_links(problem_id);
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"incident-teller/internal/domain"
+)
+
+// SetPriority records a priority change and sets or, for an empty priority, removes the
+// incident's manual priority
+func (r *SQLRepository) SetPriority(ctx context.Context, change domain.PriorityChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if change.Priority == "" {
+		_, err = tx.ExecContext(ctx, r.dialect.Rebind("DELETE FROM incident_priorities WHERE incident_id = ?"), change.IncidentID)
+	} else {
+		query := `
+			INSERT INTO incident_priorities (incident_id, priority, set_by, set_at)
+			VALUES (?, ?, ?, ?)
+		` + r.dialect.OnConflictUpdate([]string{"incident_id"}, []string{"priority", "set_by", "set_at"})
+		_, err = tx.ExecContext(ctx, r.dialect.Rebind(query),
+			change.IncidentID, string(change.Priority), change.ChangedBy, change.ChangedAt)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set priority: %w", err)
+	}
+
+	var sequence int
+	err = tx.QueryRowContext(ctx, r.dialect.Rebind("SELECT COUNT(*) FROM incident_priority_changes WHERE incident_id = ?"),
+		change.IncidentID).Scan(&sequence)
+	if err != nil {
+		return fmt.Errorf("failed to count priority changes: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, r.dialect.Rebind(`
+		INSERT INTO incident_priority_changes
+			(incident_id, sequence_order, priority, previous_priority, changed_by, reason, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`), change.IncidentID, sequence, string(change.Priority), string(change.Previous),
+		change.ChangedBy, change.Reason, change.ChangedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record priority change: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetPriorityChanges returns the priority changes of an incident, oldest first
+func (r *SQLRepository) GetPriorityChanges(ctx context.Context, incidentID string) ([]domain.PriorityChange, error) {
+	query := `
+		SELECT priority, previous_priority, changed_by, reason, changed_at
+		FROM incident_priority_changes
+		WHERE incident_id = ?
+		ORDER BY sequence_order
+	`
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query priority changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []domain.PriorityChange{}
+	for rows.Next() {
+		change := domain.PriorityChange{IncidentID: incidentID}
+		if err := rows.Scan(&change.Priority, &change.Previous, &change.ChangedBy, &change.Reason, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan priority change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"incident-teller/internal/domain"
+)
+
+// LinkIncident records the incident as an occurrence of a problem, replacing an earlier
+// link
+func (r *SQLRepository) LinkIncident(ctx context.Context, link domain.ProblemLink) error {
+	query := `
+		INSERT INTO problem_links (incident_id, problem_id, similarity, linked_at)
+		VALUES (?, ?, ?, ?)
+	` + r.dialect.OnConflictUpdate([]string{"incident_id"}, []string{"problem_id", "similarity", "linked_at"})
+
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), link.IncidentID, link.ProblemID, link.Similarity, link.LinkedAt)
+	if err != nil {
+		return fmt.Errorf("failed to link incident to problem: %w", err)
+	}
+	return nil
+}
+
+// GetProblemLinks returns the links of a problem, or of every problem if problemID is
+// empty, oldest first
+func (r *SQLRepository) GetProblemLinks(ctx context.Context, problemID string) ([]domain.ProblemLink, error) {
+	query := "SELECT incident_id, problem_id, similarity, linked_at FROM problem_links"
+	var args []interface{}
+	if problemID != "" {
+		query += " WHERE problem_id = ?"
+		args = append(args, problemID)
+	}
+	query += " ORDER BY linked_at, incident_id"
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query problem links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []domain.ProblemLink{}
+	for rows.Next() {
+		var link domain.ProblemLink
+		if err := rows.Scan(&link.IncidentID, &link.ProblemID, &link.Similarity, &link.LinkedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan problem link: %w", err)
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
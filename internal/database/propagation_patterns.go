@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// SavePropagationPatterns replaces the stored propagation patterns in one transaction
+func (r *SQLRepository) SavePropagationPatterns(ctx context.Context, patterns []domain.PropagationPattern) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM propagation_patterns"); err != nil {
+		return fmt.Errorf("failed to clear propagation patterns: %w", err)
+	}
+
+	query := r.dialect.Rebind(`
+		INSERT INTO propagation_patterns
+			(host, service, from_resource, to_resource, probability, window_seconds, observations, learned_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare propagation pattern insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, p := range patterns {
+		_, err := stmt.ExecContext(ctx,
+			p.Host, p.Service, string(p.From), string(p.To),
+			p.Probability, int64(p.Window/time.Second), p.Observations, p.LearnedAt.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save propagation pattern: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetPropagationPatterns returns the stored propagation patterns
+func (r *SQLRepository) GetPropagationPatterns(ctx context.Context) ([]domain.PropagationPattern, error) {
+	query := `
+		SELECT host, service, from_resource, to_resource, probability, window_seconds, observations, learned_at
+		FROM propagation_patterns
+		ORDER BY probability DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query propagation patterns: %w", err)
+	}
+	defer rows.Close()
+
+	patterns := []domain.PropagationPattern{}
+	for rows.Next() {
+		var p domain.PropagationPattern
+		var from, to string
+		var windowSeconds int64
+
+		if err := rows.Scan(&p.Host, &p.Service, &from, &to, &p.Probability, &windowSeconds, &p.Observations, &p.LearnedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan propagation pattern: %w", err)
+		}
+
+		p.From = domain.ResourceType(from)
+		p.To = domain.ResourceType(to)
+		p.Window = time.Duration(windowSeconds) * time.Second
+		patterns = append(patterns, p)
+	}
+
+	return patterns, rows.Err()
+}
@@ -0,0 +1,257 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// Frequency breakdowns list at most this many values
+const reliabilityTopN = 10
+
+// An incident title seen at least this often in a period is recurring
+const recurringMinIncidents = 2
+
+// SaveAcknowledgement records who acknowledged an incident and when. Only the first
+// acknowledgement of an incident is kept.
+func (r *SQLRepository) SaveAcknowledgement(ctx context.Context, incidentID, by string, at time.Time) error {
+	query := `
+		INSERT INTO incident_acknowledgements (incident_id, acknowledged_by, acknowledged_at)
+		VALUES (?, ?, ?)
+	` + r.dialect.OnConflictUpdate([]string{"incident_id"}, nil, "incident_id = incident_acknowledgements.incident_id")
+
+	if _, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), incidentID, by, at.UTC()); err != nil {
+		return fmt.Errorf("failed to save acknowledgement: %w", err)
+	}
+	return nil
+}
+
+// IsAcknowledged reports whether an incident has been acknowledged
+func (r *SQLRepository) IsAcknowledged(ctx context.Context, incidentID string) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(
+		"SELECT COUNT(*) FROM incident_acknowledgements WHERE incident_id = ?"), incidentID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to get acknowledgement: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ReliabilityStats aggregates MTTR, MTTA, incident frequency and recurring incidents for
+// the incidents started within [from, to) that pass the filter. All aggregation happens in SQL.
+func (r *SQLRepository) ReliabilityStats(ctx context.Context, from, to time.Time, filter domain.LabelFilter) (domain.ReliabilityStats, error) {
+	stats := domain.ReliabilityStats{From: from, To: to}
+	scope, scopeArgs := labelFilterClause("i.id", filter)
+	args := append([]interface{}{from.UTC(), to.UTC()}, scopeArgs...)
+
+	query := fmt.Sprintf(`
+		SELECT COUNT(*),
+			   COUNT(i.resolved_at),
+			   COUNT(ack.incident_id),
+			   COALESCE(AVG(%s), 0),
+			   COALESCE(AVG(%s), 0)
+		FROM incidents i
+		LEFT JOIN incident_acknowledgements ack ON ack.incident_id = i.id
+		WHERE i.started_at >= ? AND i.started_at < ?%s
+	`, r.secondsBetweenExpr("i.started_at", "i.resolved_at"), r.secondsBetweenExpr("i.started_at", "ack.acknowledged_at"), scope)
+
+	var mttrSeconds, mttaSeconds float64
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), args...).Scan(
+		&stats.Incidents, &stats.Resolved, &stats.Acknowledged, &mttrSeconds, &mttaSeconds,
+	)
+	if err != nil {
+		return stats, fmt.Errorf("failed to aggregate incidents: %w", err)
+	}
+	stats.MTTR = secondsToDuration(mttrSeconds)
+	stats.MTTA = secondsToDuration(mttaSeconds)
+
+	if stats.ByHost, err = r.incidentFrequency(ctx, "a.host", scope, args); err != nil {
+		return stats, err
+	}
+	if stats.ByResourceType, err = r.incidentFrequency(ctx, "a.resource_type", scope, args); err != nil {
+		return stats, err
+	}
+	if stats.ByState, err = r.incidentsByState(ctx, scope, args); err != nil {
+		return stats, err
+	}
+	if stats.ByWeekday, err = r.incidentsByWeekday(ctx, scope, args); err != nil {
+		return stats, err
+	}
+	if stats.Recurring, err = r.recurringIncidents(ctx, scope, args); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// incidentFrequency counts the incidents involving each value of an alert column,
+// most frequent first. args are the period bounds followed by the scope's arguments.
+func (r *SQLRepository) incidentFrequency(ctx context.Context, column, scope string, args []interface{}) ([]domain.FrequencyCount, error) {
+	query := fmt.Sprintf(`
+		SELECT %[1]s, COUNT(DISTINCT i.id)
+		FROM incidents i
+		JOIN incident_alerts ia ON ia.incident_id = i.id
+		JOIN alerts a ON a.id = ia.alert_id
+		WHERE i.started_at >= ? AND i.started_at < ?%[2]s
+		GROUP BY %[1]s
+		ORDER BY COUNT(DISTINCT i.id) DESC, %[1]s
+		LIMIT ?
+	`, column, scope)
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), append(args[:len(args):len(args)], reliabilityTopN)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count incidents by %s: %w", column, err)
+	}
+	defer rows.Close()
+
+	counts := []domain.FrequencyCount{}
+	for rows.Next() {
+		var count domain.FrequencyCount
+		if err := rows.Scan(&count.Key, &count.Incidents); err != nil {
+			return nil, fmt.Errorf("failed to scan incident frequency: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// incidentsByState counts incidents per lifecycle state. Incidents never moved are
+// detected, or resolved once their alerts cleared.
+func (r *SQLRepository) incidentsByState(ctx context.Context, scope string, args []interface{}) ([]domain.FrequencyCount, error) {
+	state := "COALESCE(st.state, CASE WHEN i.resolved_at IS NULL THEN 'detected' ELSE 'resolved' END)"
+	query := fmt.Sprintf(`
+		SELECT %[1]s, COUNT(*)
+		FROM incidents i
+		LEFT JOIN incident_states st ON st.incident_id = i.id
+		WHERE i.started_at >= ? AND i.started_at < ?%[2]s
+		GROUP BY %[1]s
+	`, state, scope)
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count incidents by state: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[domain.IncidentState]int)
+	for rows.Next() {
+		var state domain.IncidentState
+		var count int
+		if err := rows.Scan(&state, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan state count: %w", err)
+		}
+		counts[state] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return domain.StateFrequencies(counts), nil
+}
+
+// incidentsByWeekday counts incidents per UTC start weekday
+func (r *SQLRepository) incidentsByWeekday(ctx context.Context, scope string, args []interface{}) ([7]int, error) {
+	var byWeekday [7]int
+
+	weekday := r.weekdayExpr("i.started_at")
+	query := fmt.Sprintf(`
+		SELECT %[1]s, COUNT(*)
+		FROM incidents i
+		WHERE i.started_at >= ? AND i.started_at < ?%[2]s
+		GROUP BY %[1]s
+	`, weekday, scope)
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return byWeekday, fmt.Errorf("failed to count incidents by weekday: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day, count int
+		if err := rows.Scan(&day, &count); err != nil {
+			return byWeekday, fmt.Errorf("failed to scan weekday count: %w", err)
+		}
+		if day >= 0 && day < len(byWeekday) {
+			byWeekday[day] = count
+		}
+	}
+
+	return byWeekday, rows.Err()
+}
+
+// recurringIncidents returns incident titles seen repeatedly, most frequent first
+func (r *SQLRepository) recurringIncidents(ctx context.Context, scope string, args []interface{}) ([]domain.RecurringIncident, error) {
+	query := fmt.Sprintf(`
+		SELECT i.title, COUNT(*), COALESCE(AVG(%s), 0)
+		FROM incidents i
+		WHERE i.started_at >= ? AND i.started_at < ?%s
+		GROUP BY i.title
+		HAVING COUNT(*) >= ?
+		ORDER BY COUNT(*) DESC, i.title
+		LIMIT ?
+	`, r.secondsBetweenExpr("i.started_at", "i.resolved_at"), scope)
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), append(args[:len(args):len(args)], recurringMinIncidents, reliabilityTopN)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recurring incidents: %w", err)
+	}
+	defer rows.Close()
+
+	recurring := []domain.RecurringIncident{}
+	for rows.Next() {
+		var incident domain.RecurringIncident
+		var mttrSeconds float64
+		if err := rows.Scan(&incident.Title, &incident.Incidents, &mttrSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan recurring incident: %w", err)
+		}
+		incident.MTTR = secondsToDuration(mttrSeconds)
+		recurring = append(recurring, incident)
+	}
+
+	return recurring, rows.Err()
+}
+
+// labelFilterClause restricts a query to the incidents passing the filter, matching their
+// stored labels against the incident ID column. It returns no clause for the zero filter.
+func labelFilterClause(idColumn string, filter domain.LabelFilter) (string, []interface{}) {
+	if filter.IsZero() {
+		return "", nil
+	}
+	clause := fmt.Sprintf(`
+		  AND %s IN (SELECT lf.incident_id FROM incident_labels lf WHERE lf.label_key = ? AND lf.label_value = ?)`, idColumn)
+	return clause, []interface{}{filter.Key, filter.Value}
+}
+
+// secondsBetweenExpr computes the seconds from one timestamp column to another; the
+// result is NULL when either is NULL, so AVG skips open incidents
+func (r *SQLRepository) secondsBetweenExpr(from, to string) string {
+	switch r.dialect {
+	case DialectPostgres:
+		return fmt.Sprintf("EXTRACT(EPOCH FROM (%s - %s))", to, from)
+	case DialectMySQL:
+		return fmt.Sprintf("TIMESTAMPDIFF(MICROSECOND, %s, %s) / 1000000", from, to)
+	default:
+		return fmt.Sprintf("((julianday(%s) - julianday(%s)) * 86400)", to, from)
+	}
+}
+
+// weekdayExpr extracts the weekday of a timestamp column, 0 for Sunday
+func (r *SQLRepository) weekdayExpr(column string) string {
+	switch r.dialect {
+	case DialectPostgres:
+		return fmt.Sprintf("CAST(EXTRACT(DOW FROM %s) AS INTEGER)", column)
+	case DialectMySQL:
+		return fmt.Sprintf("(DAYOFWEEK(%s) - 1)", column)
+	default:
+		return fmt.Sprintf("CAST(strftime('%%w', %s) AS INTEGER)", column)
+	}
+}
+
+// secondsToDuration converts aggregated seconds, rounded to milliseconds since sqlite's
+// julianday arithmetic is only accurate to a few microseconds
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond)
+}
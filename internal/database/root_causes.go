@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// SaveRootCause stores a model version's root cause prediction for an incident. Feedback
+// on an earlier prediction is kept if the predicted alert is the same.
+func (r *SQLRepository) SaveRootCause(ctx context.Context, record domain.RootCauseRecord) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var alertID string
+	var correct sql.NullBool
+	var feedbackAt sql.NullTime
+	err = tx.QueryRowContext(ctx, r.dialect.Rebind(
+		"SELECT alert_id, correct, feedback_at FROM root_cause_predictions WHERE incident_id = ? AND model_version = ?"),
+		record.IncidentID, record.ModelVersion).Scan(&alertID, &correct, &feedbackAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return fmt.Errorf("failed to get root cause prediction: %w", err)
+	case alertID == record.AlertID && correct.Valid:
+		record.Correct = &correct.Bool
+		if feedbackAt.Valid {
+			record.FeedbackAt = &feedbackAt.Time
+		}
+	}
+
+	query := `
+		INSERT INTO root_cause_predictions
+			(incident_id, model_version, alert_id, raw_score, confidence, predicted_at, correct, feedback_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	` + r.dialect.OnConflictUpdate([]string{"incident_id", "model_version"},
+		[]string{"alert_id", "raw_score", "confidence", "predicted_at", "correct", "feedback_at"})
+
+	_, err = tx.ExecContext(ctx, r.dialect.Rebind(query),
+		record.IncidentID, record.ModelVersion, record.AlertID, record.RawScore, record.Confidence,
+		record.PredictedAt, record.Correct, record.FeedbackAt)
+	if err != nil {
+		return fmt.Errorf("failed to save root cause prediction: %w", err)
+	}
+	return tx.Commit()
+}
+
+// GetRootCauses returns the stored root cause predictions, of one incident if incidentID
+// is set, oldest first
+func (r *SQLRepository) GetRootCauses(ctx context.Context, incidentID string) ([]domain.RootCauseRecord, error) {
+	query := `
+		SELECT incident_id, model_version, alert_id, raw_score, confidence, predicted_at, correct, feedback_at
+		FROM root_cause_predictions
+	`
+	var args []interface{}
+	if incidentID != "" {
+		query += " WHERE incident_id = ?"
+		args = append(args, incidentID)
+	}
+	query += " ORDER BY predicted_at, incident_id, model_version"
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query root cause predictions: %w", err)
+	}
+	defer rows.Close()
+
+	records := []domain.RootCauseRecord{}
+	for rows.Next() {
+		var record domain.RootCauseRecord
+		var correct sql.NullBool
+		var feedbackAt sql.NullTime
+		if err := rows.Scan(&record.IncidentID, &record.ModelVersion, &record.AlertID, &record.RawScore,
+			&record.Confidence, &record.PredictedAt, &correct, &feedbackAt); err != nil {
+			return nil, fmt.Errorf("failed to scan root cause prediction: %w", err)
+		}
+		if correct.Valid {
+			record.Correct = &correct.Bool
+		}
+		if feedbackAt.Valid {
+			record.FeedbackAt = &feedbackAt.Time
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// SetRootCauseFeedback records whether a model version's prediction for an incident was
+// correct
+func (r *SQLRepository) SetRootCauseFeedback(ctx context.Context, incidentID, modelVersion string, correct bool, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(
+		"UPDATE root_cause_predictions SET correct = ?, feedback_at = ? WHERE incident_id = ? AND model_version = ?"),
+		correct, at, incidentID, modelVersion)
+	if err != nil {
+		return fmt.Errorf("failed to save root cause feedback: %w", err)
+	}
+	return nil
+}
+
+// SetRootCauseOverride pins the root cause of an incident, replacing an earlier one
+func (r *SQLRepository) SetRootCauseOverride(ctx context.Context, override domain.RootCauseOverride) error {
+	query := `
+		INSERT INTO root_cause_overrides (incident_id, alert_id, explanation, set_by, set_at)
+		VALUES (?, ?, ?, ?, ?)
+	` + r.dialect.OnConflictUpdate([]string{"incident_id"}, []string{"alert_id", "explanation", "set_by", "set_at"})
+
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(query),
+		override.IncidentID, override.AlertID, override.Explanation, override.SetBy, override.SetAt)
+	if err != nil {
+		return fmt.Errorf("failed to save root cause override: %w", err)
+	}
+	return nil
+}
+
+// DeleteRootCauseOverride unpins the root cause of an incident
+func (r *SQLRepository) DeleteRootCauseOverride(ctx context.Context, incidentID string) error {
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind("DELETE FROM root_cause_overrides WHERE incident_id = ?"), incidentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete root cause override: %w", err)
+	}
+	return nil
+}
+
+// GetRootCauseOverrides returns the pinned root causes, of one incident if incidentID is
+// set, oldest first
+func (r *SQLRepository) GetRootCauseOverrides(ctx context.Context, incidentID string) ([]domain.RootCauseOverride, error) {
+	query := "SELECT incident_id, alert_id, explanation, set_by, set_at FROM root_cause_overrides"
+	var args []interface{}
+	if incidentID != "" {
+		query += " WHERE incident_id = ?"
+		args = append(args, incidentID)
+	}
+	query += " ORDER BY set_at, incident_id"
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query root cause overrides: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := []domain.RootCauseOverride{}
+	for rows.Next() {
+		var o domain.RootCauseOverride
+		if err := rows.Scan(&o.IncidentID, &o.AlertID, &o.Explanation, &o.SetBy, &o.SetAt); err != nil {
+			return nil, fmt.Errorf("failed to scan root cause override: %w", err)
+		}
+		overrides = append(overrides, o)
+	}
+	return overrides, rows.Err()
+}
@@ -5,124 +5,211 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
 )
 
 // SQLRepository provides persistent storage using SQL databases
 type SQLRepository struct {
-	db *sql.DB
+	db        *sql.DB
+	dialect   Dialect
+	batchSize int
+	cache     *incidentCache // nil unless EnableCache was called
 }
 
-// NewSQLRepository creates a new SQL repository
+// DefaultBatchSize is the number of alerts per multi-row INSERT. With 15 columns it
+// stays well below the bind parameter limits of sqlite, postgres and mysql.
+const DefaultBatchSize = 500
+
+// NewSQLRepository creates a new SQL repository, detecting the dialect from the driver
 func NewSQLRepository(db *sql.DB) *SQLRepository {
-	return &SQLRepository{db: db}
+	return &SQLRepository{db: db, dialect: detectDialect(db)}
+}
+
+// NewSQLRepositoryWithDialect creates a new SQL repository for an explicit dialect
+func NewSQLRepositoryWithDialect(db *sql.DB, dialect Dialect) *SQLRepository {
+	return &SQLRepository{db: db, dialect: dialect}
+}
+
+// Dialect returns the SQL dialect the repository generates queries for
+func (r *SQLRepository) Dialect() Dialect {
+	return r.dialect
 }
 
-// Init initializes database tables
+// Init brings the database schema up to date by applying pending migrations
 func (r *SQLRepository) Init(ctx context.Context) error {
-	// Create tables first
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS alerts (
-			id TEXT PRIMARY KEY,
-			external_id INTEGER NOT NULL,
-			host TEXT NOT NULL,
-			chart TEXT NOT NULL,
-			family TEXT NOT NULL,
-			name TEXT NOT NULL,
-			status TEXT NOT NULL,
-			old_status TEXT NOT NULL,
-			value REAL NOT NULL,
-			occurred_at TIMESTAMP NOT NULL,
-			description TEXT,
-			resource_type TEXT NOT NULL,
-			labels TEXT,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
-		`CREATE TABLE IF NOT EXISTS incidents (
-			id TEXT PRIMARY KEY,
-			title TEXT NOT NULL,
-			status TEXT NOT NULL,
-			started_at TIMESTAMP NOT NULL,
-			resolved_at TIMESTAMP,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
-		`CREATE TABLE IF NOT EXISTS incident_alerts (
-			incident_id TEXT NOT NULL,
-			alert_id TEXT NOT NULL,
-			sequence_order INTEGER NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			PRIMARY KEY (incident_id, alert_id),
-			FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE,
-			FOREIGN KEY (alert_id) REFERENCES alerts(id) ON DELETE CASCADE
-		)`,
-		`CREATE TABLE IF NOT EXISTS metadata (
-			key TEXT PRIMARY KEY,
-			value TEXT NOT NULL,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
-
-		// Create indexes separately
-		`CREATE INDEX IF NOT EXISTS idx_alerts_external_id ON alerts(external_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_alerts_occurred_at ON alerts(occurred_at)`,
-		`CREATE INDEX IF NOT EXISTS idx_alerts_host ON alerts(host)`,
-		`CREATE INDEX IF NOT EXISTS idx_alerts_resource_type ON alerts(resource_type)`,
-		`CREATE INDEX IF NOT EXISTS idx_incidents_status ON incidents(status)`,
-		`CREATE INDEX IF NOT EXISTS idx_incidents_started_at ON incidents(started_at)`,
-		`CREATE INDEX IF NOT EXISTS idx_incidents_resolved_at ON incidents(resolved_at)`,
-		`CREATE INDEX IF NOT EXISTS idx_incident_alerts_incident_id ON incident_alerts(incident_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_incident_alerts_alert_id ON incident_alerts(alert_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_incident_alerts_sequence_order ON incident_alerts(sequence_order)`,
-	}
-
-	for _, query := range queries {
-		if _, err := r.db.ExecContext(ctx, query); err != nil {
-			return fmt.Errorf("failed to execute migration query: %w", err)
-		}
+	migrator, err := r.Migrator()
+	if err != nil {
+		return err
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
 	}
-
 	return nil
 }
 
-// SaveAlert stores an alert in the database
-func (r *SQLRepository) SaveAlert(ctx context.Context, alert domain.Alert) error {
-	labelsJSON, err := json.Marshal(alert.Labels)
-	if err != nil {
-		return fmt.Errorf("failed to marshal labels: %w", err)
+// alertColumns are the columns written for each alert
+var alertColumns = []string{
+	"id", "external_id", "host", "chart", "family", "name", "status", "old_status",
+	"value", "occurred_at", "description", "resource_type", "labels", "source", "fingerprint",
+	"raw_payload",
+}
+
+// alertUpdateColumns are overwritten when an alert is saved again; the raw payload is
+// kept as first received
+var alertUpdateColumns = []string{"status", "old_status", "value", "occurred_at", "description", "labels"}
+
+// insertAlertsQuery builds a multi-row upsert for the given number of alerts
+func (r *SQLRepository) insertAlertsQuery(rows int) string {
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(alertColumns)), ", ") + ")"
+	values := make([]string, rows)
+	for i := range values {
+		values[i] = row
 	}
 
-	query := `
-		INSERT INTO alerts (
-			id, external_id, host, chart, family, name, status, old_status,
-			value, occurred_at, description, resource_type, labels
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id) DO UPDATE SET
-			status = excluded.status,
-			old_status = excluded.old_status,
-			value = excluded.value,
-			occurred_at = excluded.occurred_at,
-			description = excluded.description,
-			labels = excluded.labels
-	`
+	query := "INSERT INTO alerts (" + strings.Join(alertColumns, ", ") + ") VALUES " +
+		strings.Join(values, ", ") + " " +
+		r.dialect.OnConflictUpdate([]string{"id"}, alertUpdateColumns)
+	return r.dialect.Rebind(query)
+}
 
-	_, err = r.db.ExecContext(ctx, query,
+func alertArgs(alert domain.Alert) ([]interface{}, error) {
+	labelsJSON, err := json.Marshal(alert.Labels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal labels: %w", err)
+	}
+	// NULL fingerprints are exempt from the unique index
+	var fingerprint interface{}
+	if fp := alert.Fingerprint(); fp != "" {
+		fingerprint = fp
+	}
+	var rawPayload interface{}
+	if alert.RawPayload != "" {
+		rawPayload = alert.RawPayload
+	}
+	return []interface{}{
 		alert.ID, alert.ExternalID, alert.Host, alert.Chart, alert.Family,
 		alert.Name, string(alert.Status), string(alert.OldStatus),
-		alert.Value, alert.OccurredAt, alert.Description,
-		string(alert.ResourceType), string(labelsJSON),
-	)
+		alert.Value, alert.OccurredAt.UTC(), alert.Description,
+		string(alert.ResourceType), string(labelsJSON), alert.Source, fingerprint,
+		rawPayload,
+	}, nil
+}
 
+// SaveAlert stores an alert in the database
+func (r *SQLRepository) SaveAlert(ctx context.Context, alert domain.Alert) error {
+	args, err := alertArgs(alert)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, r.insertAlertsQuery(1), args...)
+	r.cache.invalidateAlerts(alert.ID)
 	return err
 }
 
+// SetBatchSize sets how many alerts SaveAlerts writes per INSERT statement
+func (r *SQLRepository) SetBatchSize(size int) {
+	if size > 0 {
+		r.batchSize = size
+	}
+}
+
+// SaveAlerts stores alerts in a single transaction using multi-row inserts of up to
+// the batch size. Either all alerts are saved or none.
+func (r *SQLRepository) SaveAlerts(ctx context.Context, alerts []domain.Alert) error {
+	alerts = dedupeAlerts(alerts)
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	batchSize := r.batchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Full batches share one prepared statement; the remainder gets its own
+	var fullBatch *sql.Stmt
+	if len(alerts) >= batchSize {
+		fullBatch, err = tx.PrepareContext(ctx, r.insertAlertsQuery(batchSize))
+		if err != nil {
+			return fmt.Errorf("failed to prepare alert insert: %w", err)
+		}
+		defer fullBatch.Close()
+	}
+
+	for start := 0; start < len(alerts); start += batchSize {
+		end := start + batchSize
+		if end > len(alerts) {
+			end = len(alerts)
+		}
+
+		args := make([]interface{}, 0, (end-start)*len(alertColumns))
+		for _, alert := range alerts[start:end] {
+			alertArgs, err := alertArgs(alert)
+			if err != nil {
+				return err
+			}
+			args = append(args, alertArgs...)
+		}
+
+		if end-start == batchSize {
+			_, err = fullBatch.ExecContext(ctx, args...)
+		} else {
+			_, err = tx.ExecContext(ctx, r.insertAlertsQuery(end-start), args...)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to insert alerts: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	alertIDs := make([]string, len(alerts))
+	for i, alert := range alerts {
+		alertIDs[i] = alert.ID
+	}
+	r.cache.invalidateAlerts(alertIDs...)
+	return nil
+}
+
+// dedupeAlerts keeps the last occurrence of each alert ID or fingerprint; a single
+// upsert statement may not touch the same row twice
+func dedupeAlerts(alerts []domain.Alert) []domain.Alert {
+	index := make(map[string]int, len(alerts))
+	result := make([]domain.Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		key := "id:" + alert.ID
+		if fp := alert.Fingerprint(); fp != "" {
+			key = "fingerprint:" + fp
+		}
+		if i, ok := index[key]; ok {
+			result[i] = alert
+			continue
+		}
+		index[key] = len(result)
+		result = append(result, alert)
+	}
+	return result
+}
+
 // GetIncidents retrieves incidents from the database
 func (r *SQLRepository) GetIncidents(ctx context.Context) ([]domain.Incident, error) {
 	query := `
-		SELECT id, title, status, started_at, resolved_at
-		FROM incidents
-		ORDER BY started_at DESC
+		SELECT i.id, i.title, i.status, i.started_at, i.resolved_at, COALESCE(p.priority, ''), i.template, i.default_priority
+		FROM incidents i
+		LEFT JOIN incident_priorities p ON p.incident_id = i.id
+		ORDER BY i.started_at DESC
 	`
 
 	rows, err := r.db.QueryContext(ctx, query)
@@ -138,7 +225,7 @@ func (r *SQLRepository) GetIncidents(ctx context.Context) ([]domain.Incident, er
 
 		err := rows.Scan(
 			&incident.ID, &incident.Title, &incident.Status,
-			&incident.StartedAt, &resolvedAt,
+			&incident.StartedAt, &resolvedAt, &incident.PriorityOverride, &incident.Template, &incident.DefaultPriority,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan incident: %w", err)
@@ -147,18 +234,23 @@ func (r *SQLRepository) GetIncidents(ctx context.Context) ([]domain.Incident, er
 		if resolvedAt.Valid {
 			incident.ResolvedAt = &resolvedAt.Time
 		}
+		incidents = append(incidents, incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-		// Load associated alerts
-		alerts, err := r.getIncidentAlerts(ctx, incident.ID)
+	// Load alerts after the result set is closed so sqlite doesn't need a second connection
+	rows.Close()
+	for i := range incidents {
+		alerts, err := r.getIncidentAlerts(ctx, incidents[i].ID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get incident alerts: %w", err)
 		}
-
-		incident.Events = alerts
-		incidents = append(incidents, incident)
+		incidents[i].Events = alerts
 	}
 
-	return incidents, rows.Err()
+	return r.withIncidentMetadata(ctx, incidents)
 }
 
 // SaveIncident stores an incident in the database
@@ -170,78 +262,173 @@ func (r *SQLRepository) SaveIncident(ctx context.Context, incident domain.Incide
 	defer tx.Rollback()
 
 	query := `
-		INSERT INTO incidents (id, title, status, started_at, resolved_at)
-		VALUES (?, ?, ?, ?, ?)
-		ON CONFLICT(id) DO UPDATE SET
-			title = excluded.title,
-			status = excluded.status,
-			resolved_at = excluded.resolved_at,
-			updated_at = CURRENT_TIMESTAMP
-	`
+		INSERT INTO incidents (id, title, status, started_at, resolved_at, template, default_priority)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	` + r.dialect.OnConflictUpdate(
+		[]string{"id"},
+		[]string{"title", "status", "resolved_at", "template", "default_priority"},
+		"updated_at = CURRENT_TIMESTAMP",
+	)
 
 	var resolvedAt interface{}
 	if incident.ResolvedAt != nil {
-		resolvedAt = *incident.ResolvedAt
+		resolvedAt = incident.ResolvedAt.UTC()
 	}
 
-	_, err = tx.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, r.dialect.Rebind(query),
 		incident.ID, incident.Title, string(incident.Status),
-		incident.StartedAt, resolvedAt,
+		incident.StartedAt.UTC(), resolvedAt, incident.Template, string(incident.DefaultPriority),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to upsert incident: %w", err)
 	}
 
 	// Delete existing incident_alerts relations
-	_, err = tx.ExecContext(ctx, "DELETE FROM incident_alerts WHERE incident_id = ?", incident.ID)
+	_, err = tx.ExecContext(ctx, r.dialect.Rebind("DELETE FROM incident_alerts WHERE incident_id = ?"), incident.ID)
 	if err != nil {
 		return fmt.Errorf("failed to delete incident alerts: %w", err)
 	}
 
 	// Insert incident_alerts relations
 	for i, alert := range incident.Events {
-		_, err = tx.ExecContext(ctx, `
+		_, err = tx.ExecContext(ctx, r.dialect.Rebind(`
 			INSERT INTO incident_alerts (incident_id, alert_id, sequence_order)
 			VALUES (?, ?, ?)
-		`, incident.ID, alert.ID, i)
+		`), incident.ID, alert.ID, i)
 		if err != nil {
 			return fmt.Errorf("failed to insert incident alert: %w", err)
 		}
 	}
 
-	return tx.Commit()
+	// Refresh denormalized labels used for group-by analytics
+	_, err = tx.ExecContext(ctx, r.dialect.Rebind("DELETE FROM incident_labels WHERE incident_id = ?"), incident.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete incident labels: %w", err)
+	}
+
+	var resolutionSeconds interface{}
+	if incident.ResolvedAt != nil {
+		resolutionSeconds = int64(incident.ResolvedAt.Sub(incident.StartedAt).Seconds())
+	}
+
+	for key, value := range incident.Labels() {
+		_, err = tx.ExecContext(ctx, r.dialect.Rebind(`
+			INSERT INTO incident_labels (incident_id, label_key, label_value, started_at, resolution_seconds)
+			VALUES (?, ?, ?, ?, ?)
+		`), incident.ID, key, value, incident.StartedAt.UTC(), resolutionSeconds)
+		if err != nil {
+			return fmt.Errorf("failed to insert incident label: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	r.cache.invalidateIncidents(incident.ID)
+	return nil
+}
+
+// IncidentStatsByLabel aggregates incident counts and MTTR per value of a label key since the
+// given time, for the incidents passing the filter
+func (r *SQLRepository) IncidentStatsByLabel(ctx context.Context, key string, since time.Time, filter domain.LabelFilter) ([]domain.LabelGroupStats, error) {
+	scope, scopeArgs := labelFilterClause("incident_labels.incident_id", filter)
+	query := `
+		SELECT label_value,
+			   COUNT(*),
+			   COUNT(resolution_seconds),
+			   COALESCE(AVG(resolution_seconds), 0)
+		FROM incident_labels
+		WHERE label_key = ? AND started_at >= ?` + scope + `
+		GROUP BY label_value
+		ORDER BY COUNT(*) DESC, label_value
+	`
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), append([]interface{}{key, since.UTC()}, scopeArgs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident labels: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []domain.LabelGroupStats{}
+	for rows.Next() {
+		group := domain.LabelGroupStats{Key: key}
+		var avgSeconds float64
+
+		if err := rows.Scan(&group.Value, &group.Incidents, &group.Resolved, &avgSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan label group: %w", err)
+		}
+
+		group.MTTR = time.Duration(avgSeconds * float64(time.Second))
+		stats = append(stats, group)
+	}
+
+	return stats, rows.Err()
 }
 
 // GetLastProcessedID returns the last processed alert ID
 func (r *SQLRepository) GetLastProcessedID(ctx context.Context) (uint64, error) {
+	return r.getMetadataID(ctx, "last_processed_id")
+}
+
+// SetLastProcessedID updates the last processed alert ID
+func (r *SQLRepository) SetLastProcessedID(ctx context.Context, id uint64) error {
+	return r.setMetadataID(ctx, "last_processed_id", id)
+}
+
+// GetSourceCursor returns the last processed alert ID of an alert source
+func (r *SQLRepository) GetSourceCursor(ctx context.Context, source string) (uint64, error) {
+	return r.getMetadataID(ctx, "last_processed_id:"+source)
+}
+
+// SetSourceCursor updates the last processed alert ID of an alert source
+func (r *SQLRepository) SetSourceCursor(ctx context.Context, source string, id uint64) error {
+	return r.setMetadataID(ctx, "last_processed_id:"+source, id)
+}
+
+// GetExportCursor returns the resolution time up to which an exporter has exported
+func (r *SQLRepository) GetExportCursor(ctx context.Context, exporter string) (time.Time, error) {
+	nanos, err := r.getMetadataID(ctx, "export_cursor:"+exporter)
+	if err != nil || nanos == 0 {
+		return time.Time{}, err
+	}
+	return time.Unix(0, int64(nanos)).UTC(), nil
+}
+
+// SetExportCursor records an exporter's progress
+func (r *SQLRepository) SetExportCursor(ctx context.Context, exporter string, at time.Time) error {
+	return r.setMetadataID(ctx, "export_cursor:"+exporter, uint64(at.UnixNano()))
+}
+
+// getMetadataID reads a numeric metadata value, 0 if it is not set
+func (r *SQLRepository) getMetadataID(ctx context.Context, name string) (uint64, error) {
 	var value string
-	query := "SELECT value FROM metadata WHERE key = 'last_processed_id'"
+	// "key" is reserved in MySQL
+	query := "SELECT value FROM metadata WHERE " + r.dialect.Quote("key") + " = ?"
 
-	err := r.db.QueryRowContext(ctx, query).Scan(&value)
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), name).Scan(&value)
 	if err == sql.ErrNoRows {
 		return 0, nil
 	}
 	if err != nil {
-		return 0, fmt.Errorf("failed to get last processed ID: %w", err)
+		return 0, fmt.Errorf("failed to get %s: %w", name, err)
 	}
 
 	var id uint64
 	_, err = fmt.Sscanf(value, "%d", &id)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse last processed ID: %w", err)
+		return 0, fmt.Errorf("failed to parse %s: %w", name, err)
 	}
 
 	return id, nil
 }
 
-// SetLastProcessedID updates the last processed alert ID
-func (r *SQLRepository) SetLastProcessedID(ctx context.Context, id uint64) error {
-	query := `
-		INSERT INTO metadata (key, value) VALUES ('last_processed_id', ?)
-		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = CURRENT_TIMESTAMP
-	`
+// setMetadataID upserts a numeric metadata value
+func (r *SQLRepository) setMetadataID(ctx context.Context, name string, id uint64) error {
+	key := r.dialect.Quote("key")
+	query := "INSERT INTO metadata (" + key + ", value) VALUES (?, ?) " +
+		r.dialect.OnConflictUpdate([]string{key}, []string{"value"}, "updated_at = CURRENT_TIMESTAMP")
 
-	_, err := r.db.ExecContext(ctx, query, fmt.Sprintf("%d", id))
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), name, fmt.Sprintf("%d", id))
 	return err
 }
 
@@ -249,7 +436,7 @@ func (r *SQLRepository) SetLastProcessedID(ctx context.Context, id uint64) error
 func (r *SQLRepository) GetAlerts(ctx context.Context) ([]domain.Alert, error) {
 	query := `
 		SELECT id, external_id, host, chart, family, name, status, old_status,
-			   value, occurred_at, description, resource_type, labels
+			   value, occurred_at, description, resource_type, labels, source
 		FROM alerts
 		ORDER BY occurred_at DESC
 		LIMIT 1000
@@ -271,7 +458,7 @@ func (r *SQLRepository) GetAlerts(ctx context.Context) ([]domain.Alert, error) {
 			&alert.ID, &alert.ExternalID, &alert.Host, &alert.Chart,
 			&alert.Family, &alert.Name, &alert.Status, &alert.OldStatus,
 			&alert.Value, &alert.OccurredAt, &description,
-			&alert.ResourceType, &labelsJSON,
+			&alert.ResourceType, &labelsJSON, &alert.Source,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan alert: %w", err)
@@ -293,19 +480,25 @@ func (r *SQLRepository) GetAlerts(ctx context.Context) ([]domain.Alert, error) {
 	return alerts, rows.Err()
 }
 
-// getIncidentAlerts retrieves alerts for a specific incident
+// getIncidentAlerts retrieves alerts for a specific incident, from the cache if enabled
 func (r *SQLRepository) getIncidentAlerts(ctx context.Context, incidentID string) ([]domain.Alert, error) {
+	cached, version, ok := r.cache.get(incidentID)
+	if ok {
+		return cached, nil
+	}
+
 	query := `
 		SELECT a.id, a.external_id, a.host, a.chart, a.family, a.name, 
 			   a.status, a.old_status, a.value, a.occurred_at, a.description, 
-			   a.resource_type, a.labels
+			   a.resource_type, a.labels, a.source, COALESCE(s.samples, '')
 		FROM alerts a
 		JOIN incident_alerts ia ON a.id = ia.alert_id
+		LEFT JOIN alert_samples s ON s.alert_id = a.id
 		WHERE ia.incident_id = ?
 		ORDER BY ia.sequence_order
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, incidentID)
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), incidentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query incident alerts: %w", err)
 	}
@@ -314,14 +507,14 @@ func (r *SQLRepository) getIncidentAlerts(ctx context.Context, incidentID string
 	var alerts []domain.Alert
 	for rows.Next() {
 		var alert domain.Alert
-		var labelsJSON string
+		var labelsJSON, samplesJSON string
 		var description sql.NullString
 
 		err := rows.Scan(
 			&alert.ID, &alert.ExternalID, &alert.Host, &alert.Chart,
 			&alert.Family, &alert.Name, &alert.Status, &alert.OldStatus,
 			&alert.Value, &alert.OccurredAt, &description,
-			&alert.ResourceType, &labelsJSON,
+			&alert.ResourceType, &labelsJSON, &alert.Source, &samplesJSON,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan alert: %w", err)
@@ -336,11 +529,20 @@ func (r *SQLRepository) getIncidentAlerts(ctx context.Context, incidentID string
 				return nil, fmt.Errorf("failed to unmarshal labels: %w", err)
 			}
 		}
+		if samplesJSON != "" {
+			if err := json.Unmarshal([]byte(samplesJSON), &alert.Samples); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal alert samples: %w", err)
+			}
+		}
 
 		alerts = append(alerts, alert)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return alerts, rows.Err()
+	r.cache.put(incidentID, alerts, version)
+	return alerts, nil
 }
 
 // Close closes the database connection
@@ -390,9 +592,8 @@ func (r *SQLRepository) CreateIncidentFromAlerts(ctx context.Context, alerts []d
 		return nil, fmt.Errorf("no alerts provided")
 	}
 
-	// Generate incident ID
-	incidentID := fmt.Sprintf("incident-%s-%d",
-		alerts[0].Host, alerts[0].OccurredAt.Unix())
+	// Generate incident ID, stable for the same first alert
+	incidentID := idgen.Derive(alerts[0].OccurredAt, alerts[0].ID)
 
 	// Create incident title from first alert
 	title := fmt.Sprintf("%s on %s", alerts[0].Name, alerts[0].Host)
@@ -425,13 +626,14 @@ func (r *SQLRepository) CreateIncidentFromAlerts(ctx context.Context, alerts []d
 // GetIncidentsByTimeRange retrieves incidents within a time range
 func (r *SQLRepository) GetIncidentsByTimeRange(ctx context.Context, start, end time.Time) ([]domain.Incident, error) {
 	query := `
-		SELECT id, title, status, started_at, resolved_at
-		FROM incidents
-		WHERE started_at >= ? AND started_at <= ?
-		ORDER BY started_at DESC
+		SELECT i.id, i.title, i.status, i.started_at, i.resolved_at, COALESCE(p.priority, ''), i.template, i.default_priority
+		FROM incidents i
+		LEFT JOIN incident_priorities p ON p.incident_id = i.id
+		WHERE i.started_at >= ? AND i.started_at <= ?
+		ORDER BY i.started_at DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, start, end)
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), start.UTC(), end.UTC())
 	if err != nil {
 		return nil, fmt.Errorf("failed to query incidents by time range: %w", err)
 	}
@@ -444,7 +646,7 @@ func (r *SQLRepository) GetIncidentsByTimeRange(ctx context.Context, start, end
 
 		err := rows.Scan(
 			&incident.ID, &incident.Title, &incident.Status,
-			&incident.StartedAt, &resolvedAt,
+			&incident.StartedAt, &resolvedAt, &incident.PriorityOverride, &incident.Template, &incident.DefaultPriority,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan incident: %w", err)
@@ -453,25 +655,31 @@ func (r *SQLRepository) GetIncidentsByTimeRange(ctx context.Context, start, end
 		if resolvedAt.Valid {
 			incident.ResolvedAt = &resolvedAt.Time
 		}
+		incidents = append(incidents, incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-		// Load associated alerts
-		alerts, err := r.getIncidentAlerts(ctx, incident.ID)
+	// Load alerts after the result set is closed so sqlite doesn't need a second connection
+	rows.Close()
+	for i := range incidents {
+		alerts, err := r.getIncidentAlerts(ctx, incidents[i].ID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get incident alerts: %w", err)
 		}
-
-		incident.Events = alerts
-		incidents = append(incidents, incident)
+		incidents[i].Events = alerts
 	}
 
-	return incidents, rows.Err()
+	return r.withIncidentMetadata(ctx, incidents)
 }
 
 // DeleteOldAlerts removes alerts older than the specified duration
 func (r *SQLRepository) DeleteOldAlerts(ctx context.Context, olderThan time.Duration) error {
 	query := "DELETE FROM alerts WHERE occurred_at < ?"
 
-	_, err := r.db.ExecContext(ctx, query, time.Now().Add(-olderThan))
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), time.Now().UTC().Add(-olderThan))
+	r.cache.clear()
 	return err
 }
 
@@ -0,0 +1,1284 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
+	"incident-teller/internal/observability"
+)
+
+// Integration tests run against every dialect with a configured database:
+//
+//	INCIDENT_TELLER_TEST_POSTGRES_DSN="host=localhost user=... dbname=incident_teller_test sslmode=disable"
+//	INCIDENT_TELLER_TEST_MYSQL_DSN="user:pass@tcp(localhost:3306)/incident_teller_test?parseTime=true"
+//
+// SQLite always runs against a temporary file. The test databases are wiped.
+func integrationDatabases(t *testing.T) map[Dialect]string {
+	databases := map[Dialect]string{
+		DialectSQLite: filepath.Join(t.TempDir(), "integration.db"),
+	}
+	if dsn := os.Getenv("INCIDENT_TELLER_TEST_POSTGRES_DSN"); dsn != "" {
+		databases[DialectPostgres] = dsn
+	}
+	if dsn := os.Getenv("INCIDENT_TELLER_TEST_MYSQL_DSN"); dsn != "" {
+		databases[DialectMySQL] = dsn
+	}
+	return databases
+}
+
+func openIntegrationRepository(t *testing.T, dialect Dialect, dsn string) *SQLRepository {
+	t.Helper()
+
+	db, err := sql.Open(dialect.DriverName(), dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	if err := db.PingContext(ctx); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+
+	repo := NewSQLRepositoryWithDialect(db, dialect)
+	migrator, err := repo.Migrator()
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	if _, err := migrator.Down(ctx, len(migrator.Migrations())); err != nil {
+		t.Fatalf("reset schema: %v", err)
+	}
+	if err := repo.Init(ctx); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+
+	return repo
+}
+
+func TestSQLRepository_Dialects(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+			alert := domain.Alert{
+				ID: "alert-1", ExternalID: 1, Host: "db-01", Chart: "disk.space", Family: "disk",
+				Name: "disk_full", Status: domain.StatusWarning, OldStatus: domain.StatusClear,
+				Value: 91, OccurredAt: start, ResourceType: domain.ResourceDisk,
+				Labels: map[string]string{"env": "prod"}, Source: "zabbix",
+			}
+
+			// Saving twice exercises the upsert path
+			if err := repo.SaveAlert(ctx, alert); err != nil {
+				t.Fatalf("save alert: %v", err)
+			}
+			alert.Status = domain.StatusCritical
+			if err := repo.SaveAlert(ctx, alert); err != nil {
+				t.Fatalf("upsert alert: %v", err)
+			}
+
+			alerts, err := repo.GetAlerts(ctx)
+			if err != nil {
+				t.Fatalf("get alerts: %v", err)
+			}
+			if len(alerts) != 1 || alerts[0].Status != domain.StatusCritical || alerts[0].Source != "zabbix" {
+				t.Fatalf("expected one CRITICAL zabbix alert, got %+v", alerts)
+			}
+
+			incident := domain.Incident{
+				ID: "incident-1", Title: "Disk full on db-01", Status: domain.StatusCritical,
+				StartedAt: start, Events: []domain.Alert{alert},
+			}
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				t.Fatalf("save incident: %v", err)
+			}
+			resolved := start.Add(30 * time.Minute)
+			incident.ResolvedAt = &resolved
+			incident.Status = domain.StatusClear
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				t.Fatalf("upsert incident: %v", err)
+			}
+
+			incidents, err := repo.GetIncidents(ctx)
+			if err != nil {
+				t.Fatalf("get incidents: %v", err)
+			}
+			if len(incidents) != 1 || incidents[0].ResolvedAt == nil || len(incidents[0].Events) != 1 {
+				t.Fatalf("unexpected incidents: %+v", incidents)
+			}
+			if !incidents[0].ResolvedAt.Equal(resolved) {
+				t.Errorf("resolved_at round-trip: got %v, want %v", incidents[0].ResolvedAt, resolved)
+			}
+
+			inRange, err := repo.GetIncidentsByTimeRange(ctx, start.Add(-time.Minute), start.Add(time.Minute))
+			if err != nil || len(inRange) != 1 {
+				t.Fatalf("time range: %d incidents, err %v", len(inRange), err)
+			}
+
+			found, err := repo.QueryIncidents(ctx, domain.IncidentQuery{Search: "db-01", SortBy: domain.SortByDuration})
+			if err != nil || len(found) != 1 {
+				t.Fatalf("query: %d incidents, err %v", len(found), err)
+			}
+
+			stats, err := repo.IncidentStatsByLabel(ctx, "env", start.Add(-time.Hour), domain.LabelFilter{})
+			if err != nil || len(stats) != 1 || stats[0].Resolved != 1 {
+				t.Fatalf("label stats: %+v, err %v", stats, err)
+			}
+
+			for _, by := range []string{"alice", "bob"} {
+				if err := repo.SaveAcknowledgement(ctx, incident.ID, by, start.Add(time.Duration(len(by))*time.Minute)); err != nil {
+					t.Fatalf("save acknowledgement: %v", err)
+				}
+			}
+			reliability, err := repo.ReliabilityStats(ctx, start.Add(-time.Hour), start.Add(time.Hour), domain.LabelFilter{})
+			if err != nil {
+				t.Fatalf("reliability stats: %v", err)
+			}
+			if reliability.Incidents != 1 || reliability.Resolved != 1 || reliability.Acknowledged != 1 {
+				t.Fatalf("reliability counts: %+v", reliability)
+			}
+			if reliability.MTTR != 30*time.Minute || reliability.MTTA != 5*time.Minute {
+				t.Errorf("reliability MTTR %v / MTTA %v, want 30m / 5m (first acknowledgement)", reliability.MTTR, reliability.MTTA)
+			}
+
+			prod := domain.LabelFilter{Key: "env", Value: "prod"}
+			staging := domain.LabelFilter{Key: "env", Value: "staging"}
+			if stats, err := repo.IncidentStatsByLabel(ctx, "env", start.Add(-time.Hour), staging); err != nil || len(stats) != 0 {
+				t.Errorf("label stats for staging: %+v, err %v", stats, err)
+			}
+			for filter, want := range map[domain.LabelFilter]int{prod: 1, staging: 0} {
+				scoped, err := repo.ReliabilityStats(ctx, start.Add(-time.Hour), start.Add(time.Hour), filter)
+				if err != nil || scoped.Incidents != want || len(scoped.ByHost) != want {
+					t.Errorf("reliability stats for %s: %+v, err %v", filter.Value, scoped, err)
+				}
+			}
+			if len(reliability.ByHost) != 1 || reliability.ByHost[0].Key != "db-01" || reliability.ByWeekday[start.Weekday()] != 1 {
+				t.Errorf("reliability frequencies: %+v", reliability)
+			}
+
+			sinceStart := 2 * time.Minute
+			entries := []domain.TimelineEntry{
+				{Timestamp: start, Type: "TRIGGERED", Message: "disk full", Severity: "warning",
+					RelatedAlertIDs: []string{alert.ID}, ResourceType: domain.ResourceDisk},
+				{Timestamp: start.Add(sinceStart), Type: "CRITICAL", Message: "disk io", Severity: "critical",
+					DurationSinceStart: &sinceStart, CausedBy: []string{alert.ID}, RelatedAlertIDs: []string{"alert-2"}},
+			}
+			if err := repo.AppendTimelineEntries(ctx, incident.ID, entries[:1]); err != nil {
+				t.Fatalf("append timeline entries: %v", err)
+			}
+			if err := repo.AppendTimelineEntries(ctx, incident.ID, entries[1:]); err != nil {
+				t.Fatalf("append timeline entries: %v", err)
+			}
+			timeline, err := repo.GetTimelineEntries(ctx, incident.ID)
+			if err != nil || len(timeline) != 2 {
+				t.Fatalf("timeline entries: %+v, err %v", timeline, err)
+			}
+			if timeline[0].DurationSinceStart != nil || *timeline[1].DurationSinceStart != sinceStart ||
+				len(timeline[1].CausedBy) != 1 || timeline[1].CausedBy[0] != alert.ID || timeline[1].RelatedAlertIDs[0] != "alert-2" {
+				t.Errorf("timeline round-trip: %+v", timeline)
+			}
+			if err := repo.ReplaceTimelineEntries(ctx, incident.ID, entries[1:]); err != nil {
+				t.Fatalf("replace timeline entries: %v", err)
+			}
+			if timeline, err := repo.GetTimelineEntries(ctx, incident.ID); err != nil || len(timeline) != 1 || timeline[0].Type != "CRITICAL" {
+				t.Fatalf("replaced timeline: %+v, err %v", timeline, err)
+			}
+
+			if ticket, err := repo.GetTicket(ctx, incident.ID); err != nil || ticket != nil {
+				t.Fatalf("ticket before filing: %+v, err %v", ticket, err)
+			}
+			ticket := domain.Ticket{IncidentID: incident.ID, Tracker: "jira", Key: "OPS-1",
+				URL: "https://example.atlassian.net/browse/OPS-1", CreatedAt: start}
+			if err := repo.SaveTicket(ctx, ticket); err != nil {
+				t.Fatalf("save ticket: %v", err)
+			}
+			resolvedAt := start.Add(time.Hour)
+			ticket.ResolvedAt = &resolvedAt
+			if err := repo.SaveTicket(ctx, ticket); err != nil {
+				t.Fatalf("update ticket: %v", err)
+			}
+			if stored, err := repo.GetTicket(ctx, incident.ID); err != nil || stored == nil || stored.Key != "OPS-1" ||
+				stored.ResolvedAt == nil || !stored.ResolvedAt.Equal(resolvedAt) {
+				t.Fatalf("stored ticket: %+v, err %v", stored, err)
+			}
+
+			prediction := domain.RootCauseRecord{IncidentID: incident.ID, ModelVersion: "1.0.0", AlertID: "a1",
+				RawScore: 0.5, Confidence: 0.4, PredictedAt: start}
+			if err := repo.SaveRootCause(ctx, prediction); err != nil {
+				t.Fatalf("save root cause: %v", err)
+			}
+			if err := repo.SetRootCauseFeedback(ctx, incident.ID, "1.0.0", true, start.Add(time.Hour)); err != nil {
+				t.Fatalf("root cause feedback: %v", err)
+			}
+			// Predicting the same alert again keeps the feedback
+			prediction.Confidence = 0.9
+			if err := repo.SaveRootCause(ctx, prediction); err != nil {
+				t.Fatalf("update root cause: %v", err)
+			}
+			if records, err := repo.GetRootCauses(ctx, incident.ID); err != nil || len(records) != 1 ||
+				records[0].Confidence != 0.9 || records[0].Correct == nil || !*records[0].Correct || records[0].FeedbackAt == nil {
+				t.Fatalf("stored root causes: %+v, err %v", records, err)
+			}
+
+			// Only one holder gets a lease until it expires or is released
+			if lease, held, err := repo.AcquireLease(ctx, "ingestion", "replica-a", time.Minute); err != nil || !held || lease.Holder != "replica-a" {
+				t.Fatalf("acquire lease: %+v, %v, err %v", lease, held, err)
+			}
+			if lease, held, err := repo.AcquireLease(ctx, "ingestion", "replica-b", time.Minute); err != nil || held || lease.Holder != "replica-a" {
+				t.Fatalf("lease taken while held: %+v, %v, err %v", lease, held, err)
+			}
+			if _, held, err := repo.AcquireLease(ctx, "ingestion", "replica-a", time.Minute); err != nil || !held {
+				t.Fatalf("renew lease: %v, err %v", held, err)
+			}
+			if err := repo.ReleaseLease(ctx, "ingestion", "replica-a"); err != nil {
+				t.Fatalf("release lease: %v", err)
+			}
+			time.Sleep(10 * time.Millisecond)
+			if lease, held, err := repo.AcquireLease(ctx, "ingestion", "replica-b", time.Minute); err != nil || !held || lease.Holder != "replica-b" {
+				t.Fatalf("take over released lease: %+v, %v, err %v", lease, held, err)
+			}
+
+			patterns := []domain.PropagationPattern{{
+				Host: "db-01", From: domain.ResourceMemory, To: domain.ResourceDisk,
+				Probability: 0.92, Window: 4 * time.Minute, Observations: 25, LearnedAt: start,
+			}}
+			if err := repo.SavePropagationPatterns(ctx, patterns); err != nil {
+				t.Fatalf("save propagation patterns: %v", err)
+			}
+			loaded, err := repo.GetPropagationPatterns(ctx)
+			if err != nil || len(loaded) != 1 || loaded[0].Window != 4*time.Minute || loaded[0].Host != "db-01" {
+				t.Fatalf("propagation patterns: %+v, err %v", loaded, err)
+			}
+
+			for _, id := range []uint64{41, 42} {
+				if err := repo.SetLastProcessedID(ctx, id); err != nil {
+					t.Fatalf("set last processed ID: %v", err)
+				}
+			}
+			if id, err := repo.GetLastProcessedID(ctx); err != nil || id != 42 {
+				t.Fatalf("last processed ID: got %d, err %v", id, err)
+			}
+			if err := repo.SetSourceCursor(ctx, "zabbix", 7); err != nil {
+				t.Fatalf("set source cursor: %v", err)
+			}
+			if id, err := repo.GetSourceCursor(ctx, "zabbix"); err != nil || id != 7 {
+				t.Fatalf("zabbix cursor: got %d, err %v", id, err)
+			}
+			if id, err := repo.GetLastProcessedID(ctx); err != nil || id != 42 {
+				t.Fatalf("source cursor changed the last processed ID: got %d, err %v", id, err)
+			}
+		})
+	}
+}
+
+func TestSQLRepository_SaveAlertsBatch(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			repo.SetBatchSize(100)
+			ctx := context.Background()
+
+			// Two full batches, a partial one, and a duplicate ID within the input
+			start := time.Now().UTC().Truncate(time.Second)
+			alerts := make([]domain.Alert, 0, 251)
+			for i := 0; i < 250; i++ {
+				alerts = append(alerts, domain.Alert{
+					ID: fmt.Sprintf("batch-%03d", i), ExternalID: uint64(i + 1), Host: "web-01",
+					Chart: "system.cpu", Family: "cpu", Name: "cpu_usage", Status: domain.StatusWarning,
+					OldStatus: domain.StatusClear, OccurredAt: start.Add(time.Duration(i) * time.Second),
+					ResourceType: domain.ResourceCPU,
+				})
+			}
+			duplicate := alerts[0]
+			duplicate.Status = domain.StatusCritical
+			alerts = append(alerts, duplicate)
+
+			if err := repo.SaveAlerts(ctx, alerts); err != nil {
+				t.Fatalf("save alerts: %v", err)
+			}
+
+			stats, err := repo.Stats(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats["total_alerts"] != 250 {
+				t.Fatalf("expected 250 alerts, got %v", stats["total_alerts"])
+			}
+		})
+	}
+}
+
+func TestSQLRepository_TuneSQLite(t *testing.T) {
+	repo := openIntegrationRepository(t, DialectSQLite, filepath.Join(t.TempDir(), "tune.db"))
+	ctx := context.Background()
+
+	err := repo.TuneSQLite(ctx, SQLiteOptions{JournalMode: "WAL", BusyTimeout: 5 * time.Second, Synchronous: "NORMAL", MaxOpenConns: 1})
+	if err != nil {
+		t.Fatalf("tune: %v", err)
+	}
+
+	var mode string
+	var busyTimeout, synchronous int
+	if err := repo.db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.db.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyTimeout); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.db.QueryRowContext(ctx, "PRAGMA synchronous").Scan(&synchronous); err != nil {
+		t.Fatal(err)
+	}
+	if mode != "wal" || busyTimeout != 5000 || synchronous != 1 {
+		t.Fatalf("expected wal, 5000ms and NORMAL (1), got %s, %dms and %d", mode, busyTimeout, synchronous)
+	}
+	if open := repo.db.Stats().MaxOpenConnections; open != 1 {
+		t.Fatalf("expected a single connection, got %d", open)
+	}
+
+	// Reads must not hold the only connection while loading incident alerts
+	alert := domain.Alert{ID: "tune-1", Host: "web-01", Chart: "system.cpu", Name: "cpu_usage",
+		Status: domain.StatusCritical, OccurredAt: time.Now().UTC().Truncate(time.Second)}
+	if err := repo.SaveAlert(ctx, alert); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SaveIncident(ctx, domain.Incident{ID: "tune-inc", Title: "CPU", Status: domain.StatusCritical,
+		StartedAt: alert.OccurredAt, Events: []domain.Alert{alert}}); err != nil {
+		t.Fatal(err)
+	}
+	readCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if _, err := repo.GetIncidents(readCtx); err != nil {
+		t.Fatalf("get incidents: %v", err)
+	}
+	if _, err := repo.GetIncidentsByTimeRange(readCtx, alert.OccurredAt.Add(-time.Hour), alert.OccurredAt.Add(time.Hour)); err != nil {
+		t.Fatalf("get incidents by time range: %v", err)
+	}
+}
+
+func TestSQLRepository_DedupesByFingerprint(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			// The same Zabbix event under two adapter-specific IDs
+			at := time.Now().UTC().Truncate(time.Second)
+			first := domain.Alert{
+				ID: "zbx-a", ExternalID: 9, Host: "db-01", Chart: "zabbix.trigger.1", Name: "disk_full",
+				Status: domain.StatusWarning, OldStatus: domain.StatusClear, OccurredAt: at,
+				ResourceType: domain.ResourceDisk, Source: "zabbix",
+			}
+			second := first
+			second.ID = "zbx-b"
+			second.Status = domain.StatusCritical
+
+			if err := repo.SaveAlerts(ctx, []domain.Alert{first, second}); err != nil {
+				t.Fatalf("save alerts: %v", err)
+			}
+			alerts, err := repo.GetAlerts(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(alerts) != 1 || alerts[0].Status != domain.StatusCritical {
+				t.Fatalf("expected one CRITICAL alert, got %+v", alerts)
+			}
+
+			// Outside a batch the unique index rejects the duplicate
+			third := first
+			third.ID = "zbx-c"
+			if err := repo.SaveAlert(ctx, third); err == nil {
+				t.Fatal("expected the fingerprint's unique index to reject a second ID")
+			}
+		})
+	}
+}
+
+func TestSQLRepository_PriorityOverride(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+			for _, id := range []string{"incident-low", "incident-high"} {
+				alert := domain.Alert{
+					ID: "alert-" + id, ExternalID: 1, Host: "db-01", Chart: "disk.space", Name: "disk_full",
+					Status: domain.StatusWarning, OldStatus: domain.StatusClear, OccurredAt: start,
+					ResourceType: domain.ResourceDisk,
+				}
+				incident := domain.Incident{
+					ID: id, Title: id, Status: domain.StatusWarning, StartedAt: start, Events: []domain.Alert{alert},
+				}
+				if id == "incident-low" {
+					// A template's default priority ranks above the risk level
+					incident.Template, incident.DefaultPriority = "disk-full", domain.PriorityP2
+				}
+				if err := repo.SaveIncident(ctx, incident); err != nil {
+					t.Fatalf("save incident: %v", err)
+				}
+			}
+
+			change := domain.PriorityChange{
+				IncidentID: "incident-high", Priority: domain.PriorityP1,
+				ChangedBy: "oncall", Reason: "customer facing", ChangedAt: start,
+			}
+			if err := repo.SetPriority(ctx, change); err != nil {
+				t.Fatalf("set priority: %v", err)
+			}
+
+			found, err := repo.QueryIncidents(ctx, domain.IncidentQuery{SortBy: domain.SortByPriority})
+			if err != nil || len(found) != 2 {
+				t.Fatalf("query: %d incidents, err %v", len(found), err)
+			}
+			if found[0].ID != "incident-high" || found[0].PriorityOverride != domain.PriorityP1 {
+				t.Fatalf("expected the P1 override first, got %+v", found[0])
+			}
+			if found[1].Template != "disk-full" || found[1].Priority() != domain.PriorityP2 {
+				t.Fatalf("expected the template's P2 default, got %+v", found[1])
+			}
+
+			// Clearing the override returns the incident to its automatic priority
+			change.Priority, change.Previous = "", domain.PriorityP1
+			if err := repo.SetPriority(ctx, change); err != nil {
+				t.Fatalf("clear priority: %v", err)
+			}
+			incidents, err := repo.GetIncidents(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, incident := range incidents {
+				if incident.PriorityOverride != "" {
+					t.Fatalf("expected no overrides, got %s on %s", incident.PriorityOverride, incident.ID)
+				}
+			}
+
+			history, err := repo.GetPriorityChanges(ctx, "incident-high")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(history) != 2 || history[0].Priority != domain.PriorityP1 || history[1].Previous != domain.PriorityP1 {
+				t.Fatalf("unexpected history: %+v", history)
+			}
+		})
+	}
+}
+
+func TestSQLRepository_IncidentMetadata(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+			alert := domain.Alert{
+				ID: "alert-1", ExternalID: 1, Host: "db-01", Chart: "disk.space", Name: "disk_full",
+				Status: domain.StatusWarning, OldStatus: domain.StatusClear, OccurredAt: start,
+				ResourceType: domain.ResourceDisk,
+			}
+			incident := domain.Incident{
+				ID: "incident-1", Title: "disk full", Status: domain.StatusWarning, StartedAt: start, Events: []domain.Alert{alert},
+			}
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				t.Fatalf("save incident: %v", err)
+			}
+
+			metadata := domain.IncidentMetadata{
+				IncidentID: "incident-1", Severity: "sev2", Tags: []string{"customer-facing", "storage"},
+				CustomFields: map[string]string{"team": "payments"}, UpdatedBy: "oncall", UpdatedAt: start,
+			}
+			if err := repo.SetIncidentMetadata(ctx, metadata); err != nil {
+				t.Fatalf("set metadata: %v", err)
+			}
+			// Correlating the incident again keeps its metadata
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				t.Fatalf("save incident again: %v", err)
+			}
+
+			check := func(source string, incidents []domain.Incident, err error) {
+				t.Helper()
+				if err != nil || len(incidents) != 1 {
+					t.Fatalf("%s: %d incidents, err %v", source, len(incidents), err)
+				}
+				found := incidents[0]
+				if found.Severity != "sev2" || !found.HasTags("storage", "customer-facing") || found.CustomFields["team"] != "payments" {
+					t.Fatalf("%s: expected the metadata, got %+v", source, found)
+				}
+			}
+			all, err := repo.GetIncidents(ctx)
+			check("get incidents", all, err)
+			queried, err := repo.QueryIncidents(ctx, domain.IncidentQuery{})
+			check("query incidents", queried, err)
+			inRange, err := repo.GetIncidentsByTimeRange(ctx, start.Add(-time.Minute), start.Add(time.Minute))
+			check("incidents by time range", inRange, err)
+
+			if err := repo.SetIncidentMetadata(ctx, domain.IncidentMetadata{IncidentID: "incident-1"}); err != nil {
+				t.Fatalf("clear metadata: %v", err)
+			}
+			all, err = repo.GetIncidents(ctx)
+			if err != nil || len(all) != 1 || all[0].Severity != "" || len(all[0].Tags) != 0 || len(all[0].CustomFields) != 0 {
+				t.Fatalf("expected the metadata cleared, got %+v (err %v)", all, err)
+			}
+		})
+	}
+}
+
+func TestSQLRepository_RootCauseOverride(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+			alert := domain.Alert{
+				ID: "alert-1", ExternalID: 1, Host: "db-01", Chart: "disk.space", Name: "disk_full",
+				Status: domain.StatusWarning, OldStatus: domain.StatusClear, OccurredAt: start,
+				ResourceType: domain.ResourceDisk,
+			}
+			if err := repo.SaveAlert(ctx, alert); err != nil {
+				t.Fatalf("save alert: %v", err)
+			}
+			incident := domain.Incident{
+				ID: "incident-1", Title: "disk full", Status: domain.StatusWarning, StartedAt: start, Events: []domain.Alert{alert},
+			}
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				t.Fatalf("save incident: %v", err)
+			}
+
+			for _, explanation := range []string{"first guess", "log rotation broke"} {
+				override := domain.RootCauseOverride{
+					IncidentID: "incident-1", AlertID: "alert-1", Explanation: explanation, SetBy: "oncall", SetAt: start,
+				}
+				if err := repo.SetRootCauseOverride(ctx, override); err != nil {
+					t.Fatalf("set override: %v", err)
+				}
+			}
+			// Correlating the incident again keeps its override
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				t.Fatalf("save incident again: %v", err)
+			}
+
+			all, err := repo.GetIncidents(ctx)
+			if err != nil || len(all) != 1 {
+				t.Fatalf("get incidents: %d incidents, err %v", len(all), err)
+			}
+			if pinned, ok := all[0].PinnedRootCause(); !ok || pinned.ID != "alert-1" ||
+				all[0].RootCauseOverride.Explanation != "log rotation broke" {
+				t.Fatalf("expected the latest override, got %+v", all[0].RootCauseOverride)
+			}
+			overrides, err := repo.GetRootCauseOverrides(ctx, "")
+			if err != nil || len(overrides) != 1 {
+				t.Fatalf("expected one override, got %+v (err %v)", overrides, err)
+			}
+
+			if err := repo.DeleteRootCauseOverride(ctx, "incident-1"); err != nil {
+				t.Fatalf("delete override: %v", err)
+			}
+			all, err = repo.GetIncidents(ctx)
+			if err != nil || len(all) != 1 || all[0].RootCauseOverride != nil {
+				t.Fatalf("expected the override removed, got %+v (err %v)", all, err)
+			}
+		})
+	}
+}
+
+func TestSQLRepository_IncidentStates(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+			alert := domain.Alert{
+				ID: "alert-1", ExternalID: 1, Host: "db-01", Chart: "disk.space", Name: "disk_full",
+				Status: domain.StatusWarning, OldStatus: domain.StatusClear, OccurredAt: start,
+				ResourceType: domain.ResourceDisk,
+			}
+			if err := repo.SaveAlert(ctx, alert); err != nil {
+				t.Fatalf("save alert: %v", err)
+			}
+			incident := domain.Incident{
+				ID: "incident-1", Title: "disk full", Status: domain.StatusWarning, StartedAt: start, Events: []domain.Alert{alert},
+			}
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				t.Fatalf("save incident: %v", err)
+			}
+
+			steps := []domain.StateTransition{
+				{From: domain.StateDetected, To: domain.StateTriaged, Note: "disk on db-01"},
+				{From: domain.StateTriaged, To: domain.StateMitigating, Note: "rotating logs"},
+			}
+			for i, step := range steps {
+				step.IncidentID, step.ChangedBy, step.ChangedAt = "incident-1", "oncall", start.Add(time.Duration(i+1)*time.Minute)
+				if err := repo.SetIncidentState(ctx, step); err != nil {
+					t.Fatalf("set state: %v", err)
+				}
+			}
+			// A transition from a state the incident already left loses
+			stale := domain.StateTransition{IncidentID: "incident-1", From: domain.StateTriaged, To: domain.StateResolved,
+				ChangedBy: "late", ChangedAt: start.Add(3 * time.Minute)}
+			if err := repo.SetIncidentState(ctx, stale); !errors.Is(err, domain.ErrStateConflict) {
+				t.Fatalf("expected a conflict for a stale transition, got %v", err)
+			}
+			// Correlating the incident again keeps its state
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				t.Fatalf("save incident again: %v", err)
+			}
+
+			all, err := repo.GetIncidents(ctx)
+			if err != nil || len(all) != 1 {
+				t.Fatalf("get incidents: %d incidents, err %v", len(all), err)
+			}
+			if all[0].State != domain.StateMitigating || all[0].StateChangedAt == nil ||
+				!all[0].StateChangedAt.Equal(start.Add(2*time.Minute)) {
+				t.Fatalf("expected mitigating since the second transition, got %s at %v", all[0].State, all[0].StateChangedAt)
+			}
+
+			history, err := repo.GetStateTransitions(ctx, "incident-1")
+			if err != nil || len(history) != 2 {
+				t.Fatalf("expected two transitions, got %+v (err %v)", history, err)
+			}
+			if history[0].To != domain.StateTriaged || history[1].From != domain.StateTriaged || history[1].Note != "rotating logs" {
+				t.Fatalf("unexpected history %+v", history)
+			}
+
+			stats, err := repo.ReliabilityStats(ctx, start.Add(-time.Minute), start.Add(time.Hour), domain.LabelFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, count := range stats.ByState {
+				if want := map[string]int{"mitigating": 1}[count.Key]; count.Incidents != want {
+					t.Fatalf("expected %d %s incidents, got %d", want, count.Key, count.Incidents)
+				}
+			}
+			if len(stats.ByState) != len(domain.IncidentStates) {
+				t.Fatalf("expected every state counted, got %+v", stats.ByState)
+			}
+		})
+	}
+}
+
+func TestSQLRepository_ConcurrentIncidentStates(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			start := time.Now().UTC().Truncate(time.Second)
+			if err := repo.SaveIncident(ctx, domain.Incident{ID: "incident-1", Status: domain.StatusWarning, StartedAt: start}); err != nil {
+				t.Fatalf("save incident: %v", err)
+			}
+
+			// Responders racing to move the incident on from the same state: one wins each round
+			for round, step := range []domain.StateTransition{
+				{From: domain.StateDetected, To: domain.StateTriaged},
+				{From: domain.StateTriaged, To: domain.StateMitigating},
+			} {
+				var wg sync.WaitGroup
+				errs := make([]error, 8)
+				for i := range errs {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						transition := step
+						transition.IncidentID, transition.ChangedBy = "incident-1", fmt.Sprintf("responder-%d", i)
+						transition.ChangedAt = start.Add(time.Duration(round+1) * time.Minute)
+						errs[i] = repo.SetIncidentState(ctx, transition)
+					}(i)
+				}
+				wg.Wait()
+
+				won := 0
+				for _, err := range errs {
+					switch {
+					case err == nil:
+						won++
+					case !errors.Is(err, domain.ErrStateConflict):
+						t.Fatalf("round %d: expected a conflict, got %v", round, err)
+					}
+				}
+				if won != 1 {
+					t.Fatalf("round %d: expected one transition to win, got %d", round, won)
+				}
+			}
+
+			history, err := repo.GetStateTransitions(ctx, "incident-1")
+			if err != nil || len(history) != 2 || history[0].To != domain.StateTriaged || history[1].To != domain.StateMitigating {
+				t.Fatalf("expected triaged then mitigating, got %+v (err %v)", history, err)
+			}
+		})
+	}
+}
+
+func TestSQLRepository_ActionItems(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+			for _, id := range []string{"incident-1", "incident-2"} {
+				incident := domain.Incident{ID: id, Title: "disk full", Status: domain.StatusWarning, StartedAt: start}
+				if err := repo.SaveIncident(ctx, incident); err != nil {
+					t.Fatalf("save incident: %v", err)
+				}
+			}
+
+			due := start.Add(24 * time.Hour)
+			items := []domain.ActionItem{
+				{ID: "item-1", IncidentID: "incident-1", Description: "Add disk alerts", Owner: "alice", DueAt: &due,
+					Status: domain.ActionItemOpen, CreatedAt: start, UpdatedAt: start},
+				{ID: "item-2", IncidentID: "incident-1", Description: "Rotate logs", Status: domain.ActionItemOpen,
+					CreatedAt: start.Add(time.Minute), UpdatedAt: start.Add(time.Minute)},
+				{ID: "item-3", IncidentID: "incident-2", Description: "Resize volume", Status: domain.ActionItemOpen,
+					CreatedAt: start, UpdatedAt: start},
+			}
+			for _, item := range items {
+				if err := repo.SaveActionItem(ctx, item); err != nil {
+					t.Fatalf("save action item: %v", err)
+				}
+			}
+			// Saving again replaces the item
+			notified := start.Add(2 * time.Hour)
+			items[0].Status, items[0].OverdueNotifiedAt = domain.ActionItemInProgress, &notified
+			if err := repo.SaveActionItem(ctx, items[0]); err != nil {
+				t.Fatalf("update action item: %v", err)
+			}
+
+			stored, err := repo.GetActionItems(ctx, "incident-1")
+			if err != nil || len(stored) != 2 || stored[0].ID != "item-1" || stored[1].ID != "item-2" {
+				t.Fatalf("expected items 1 and 2, got %+v (err %v)", stored, err)
+			}
+			first := stored[0]
+			if first.Status != domain.ActionItemInProgress || first.Owner != "alice" || first.DueAt == nil || !first.DueAt.Equal(due) ||
+				first.OverdueNotifiedAt == nil || !first.OverdueNotifiedAt.Equal(notified) {
+				t.Fatalf("unexpected item %+v", first)
+			}
+			if stored[1].DueAt != nil || stored[1].OverdueNotifiedAt != nil {
+				t.Fatalf("expected no due date, got %+v", stored[1])
+			}
+			if all, err := repo.GetActionItems(ctx, ""); err != nil || len(all) != 3 {
+				t.Fatalf("expected three items, got %+v (err %v)", all, err)
+			}
+
+			if deleted, err := repo.DeleteActionItem(ctx, "item-2"); err != nil || !deleted {
+				t.Fatalf("expected item-2 deleted, got %v (err %v)", deleted, err)
+			}
+			if deleted, err := repo.DeleteActionItem(ctx, "item-2"); err != nil || deleted {
+				t.Fatalf("expected nothing to delete, got %v (err %v)", deleted, err)
+			}
+		})
+	}
+}
+
+func TestSQLRepository_ProblemLinks(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+			for _, id := range []string{"incident-1", "incident-2"} {
+				incident := domain.Incident{ID: id, Title: "disk full", Status: domain.StatusWarning, StartedAt: start}
+				if err := repo.SaveIncident(ctx, incident); err != nil {
+					t.Fatalf("save incident: %v", err)
+				}
+			}
+
+			links := []domain.ProblemLink{
+				{IncidentID: "incident-1", ProblemID: "incident-1", Similarity: 1, LinkedAt: start},
+				{IncidentID: "incident-2", ProblemID: "other", Similarity: 0.5, LinkedAt: start.Add(time.Minute)},
+				// Linking again replaces the earlier link
+				{IncidentID: "incident-2", ProblemID: "incident-1", Similarity: 0.8, LinkedAt: start.Add(time.Minute)},
+			}
+			for _, link := range links {
+				if err := repo.LinkIncident(ctx, link); err != nil {
+					t.Fatalf("link incident: %v", err)
+				}
+			}
+
+			stored, err := repo.GetProblemLinks(ctx, "incident-1")
+			if err != nil || len(stored) != 2 {
+				t.Fatalf("expected two links, got %+v (err %v)", stored, err)
+			}
+			if stored[0].IncidentID != "incident-1" || stored[1].IncidentID != "incident-2" || stored[1].Similarity != 0.8 {
+				t.Fatalf("unexpected links %+v", stored)
+			}
+			if other, err := repo.GetProblemLinks(ctx, "other"); err != nil || len(other) != 0 {
+				t.Fatalf("expected the replaced link gone, got %+v (err %v)", other, err)
+			}
+		})
+	}
+}
+
+func TestSQLRepository_IncidentCache(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			metrics := observability.NewMetrics(config.ObservabilityConfig{EnableMetrics: true}).(*observability.StandardMetrics)
+			repo.EnableCache(time.Minute, 10, metrics)
+			ctx := context.Background()
+
+			start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+			first := domain.Alert{ID: "alert-1", ExternalID: 1, Host: "db-01", Chart: "disk.space", Name: "disk_full",
+				Source: "netdata", Status: domain.StatusWarning, OccurredAt: start, ResourceType: domain.ResourceDisk}
+			if err := repo.SaveAlert(ctx, first); err != nil {
+				t.Fatalf("save alert: %v", err)
+			}
+			incident := domain.Incident{ID: "incident-1", Title: "disk full", Status: domain.StatusWarning,
+				StartedAt: start, Events: []domain.Alert{first}}
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				t.Fatalf("save incident: %v", err)
+			}
+
+			load := func() domain.Incident {
+				t.Helper()
+				incidents, err := repo.GetIncidents(ctx)
+				if err != nil || len(incidents) != 1 {
+					t.Fatalf("expected one incident, got %d (err %v)", len(incidents), err)
+				}
+				return incidents[0]
+			}
+			load()
+			load()
+			counters := metrics.GetCounters()
+			if counters["repository_cache_misses_total"] != 1 || counters["repository_cache_hits_total"] != 1 {
+				t.Fatalf("expected a miss then a hit, got %v", counters)
+			}
+
+			// Rewriting an alert drops the incidents holding it
+			first.Status = domain.StatusCritical
+			if err := repo.SaveAlert(ctx, first); err != nil {
+				t.Fatalf("save alert: %v", err)
+			}
+			if got := load(); len(got.Events) != 1 || got.Events[0].Status != domain.StatusCritical {
+				t.Fatalf("expected the updated alert, got %+v", got.Events)
+			}
+
+			// Saving the incident drops it too
+			second := domain.Alert{ID: "alert-2", ExternalID: 2, Host: "db-01", Chart: "disk.inodes", Name: "inodes_full",
+				Source: "netdata", Status: domain.StatusWarning, OccurredAt: start.Add(time.Minute), ResourceType: domain.ResourceDisk}
+			if err := repo.SaveAlert(ctx, second); err != nil {
+				t.Fatalf("save alert: %v", err)
+			}
+			incident.Events = []domain.Alert{first, second}
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				t.Fatalf("save incident: %v", err)
+			}
+			if got := load(); len(got.Events) != 2 {
+				t.Fatalf("expected two alerts, got %+v", got.Events)
+			}
+			if misses := metrics.GetCounters()["repository_cache_misses_total"]; misses != 3 {
+				t.Fatalf("expected a miss after each write, got %v", misses)
+			}
+		})
+	}
+}
+
+func TestSQLRepository_AlertDetails(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+			alerts := []domain.Alert{
+				{ID: "alert-1", ExternalID: 1, Host: "db-01", Chart: "disk.space", Name: "disk_full", Source: "netdata",
+					Status: domain.StatusWarning, OldStatus: domain.StatusClear, OccurredAt: start,
+					ResourceType: domain.ResourceDisk, RawPayload: `{"unique_id":1,"status":"WARNING"}`},
+				{ID: "alert-2", ExternalID: 2, Host: "web-01", Chart: "system.cpu", Name: "cpu_high", Source: "netdata",
+					Status: domain.StatusCritical, OldStatus: domain.StatusWarning, OccurredAt: start.Add(time.Minute),
+					ResourceType: domain.ResourceCPU},
+				{ID: "alert-3", ExternalID: 3, Host: "db-01", Chart: "disk.space", Name: "disk_full", Source: "netdata",
+					Status: domain.StatusClear, OldStatus: domain.StatusWarning, OccurredAt: start.Add(2 * time.Minute),
+					ResourceType: domain.ResourceDisk},
+			}
+			if err := repo.SaveAlerts(ctx, alerts); err != nil {
+				t.Fatalf("save alerts: %v", err)
+			}
+
+			alert, err := repo.GetAlert(ctx, "alert-1")
+			if err != nil || alert == nil || alert.RawPayload != alerts[0].RawPayload || alert.Ack != nil {
+				t.Fatalf("expected alert-1 with its payload, got %+v (err %v)", alert, err)
+			}
+			if missing, err := repo.GetAlert(ctx, "missing"); err != nil || missing != nil {
+				t.Fatalf("expected no alert, got %+v (err %v)", missing, err)
+			}
+
+			ack := domain.AlertAck{AlertID: "alert-1", By: "oncall", Note: "known flapping disk", AcknowledgedAt: start}
+			if err := repo.AcknowledgeAlert(ctx, ack); err != nil {
+				t.Fatalf("acknowledge alert: %v", err)
+			}
+
+			acknowledged := false
+			found, total, err := repo.QueryAlerts(ctx, domain.AlertQuery{Host: "db-01", Acknowledged: &acknowledged})
+			if err != nil || total != 1 || len(found) != 1 || found[0].ID != "alert-3" {
+				t.Fatalf("expected the unacknowledged db-01 alert, got %+v (total %d, err %v)", found, total, err)
+			}
+			found, total, err = repo.QueryAlerts(ctx, domain.AlertQuery{
+				Search: "DISK", Statuses: []domain.AlertStatus{domain.StatusWarning, domain.StatusCritical},
+			})
+			if err != nil || total != 1 || len(found) != 1 || found[0].Ack == nil || found[0].Ack.Note != "known flapping disk" {
+				t.Fatalf("expected acknowledged alert-1, got %+v (total %d, err %v)", found, total, err)
+			}
+			found, total, err = repo.QueryAlerts(ctx, domain.AlertQuery{Limit: 1, Offset: 1})
+			if err != nil || total != 3 || len(found) != 1 || found[0].ID != "alert-2" {
+				t.Fatalf("expected the second newest alert of 3, got %+v (total %d, err %v)", found, total, err)
+			}
+		})
+	}
+}
+
+func TestSQLRepository_AlertSamples(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+			alert := domain.Alert{
+				ID: "alert-1", ExternalID: 1, Host: "db-01", Chart: "mem.used", Name: "ram_in_use",
+				Status: domain.StatusWarning, OldStatus: domain.StatusClear, OccurredAt: start,
+				ResourceType: domain.ResourceMemory,
+			}
+			if err := repo.SaveAlert(ctx, alert); err != nil {
+				t.Fatalf("save alert: %v", err)
+			}
+			incident := domain.Incident{
+				ID: "incident-1", Title: "memory", Status: domain.StatusWarning, StartedAt: start, Events: []domain.Alert{alert},
+			}
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				t.Fatalf("save incident: %v", err)
+			}
+
+			samples := []domain.MetricSample{
+				{At: start.Add(-2 * time.Minute), Value: 76}, {At: start.Add(-time.Minute), Value: 94}, {At: start, Value: 97},
+			}
+			for _, saved := range [][]domain.MetricSample{samples[:1], samples} {
+				if err := repo.SaveAlertSamples(ctx, "alert-1", saved); err != nil {
+					t.Fatalf("save samples: %v", err)
+				}
+			}
+
+			incidents, err := repo.GetIncidents(ctx)
+			if err != nil || len(incidents) != 1 || len(incidents[0].Events) != 1 {
+				t.Fatalf("expected the incident, got %+v (err %v)", incidents, err)
+			}
+			got := incidents[0].Events[0].Samples
+			if len(got) != 3 || got[1].Value != 94 || !got[2].At.Equal(start) {
+				t.Fatalf("expected the replaced samples, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestSQLRepository_AuditLog(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			at := time.Now().UTC().Truncate(time.Second)
+			entries := []domain.AuditEntry{
+				{ID: "audit-1", Actor: "alice", Method: "PUT", Path: "/api/playbooks/disk_full", Status: 201, At: at.Add(-time.Hour)},
+				{ID: "audit-2", Actor: "bob", APIKey: "ba7816bf8f01cfea", Method: "PATCH", Path: "/api/incidents/incident-1",
+					Status: 200, Payload: `{"priority":"P1"}`, At: at,
+					Changes: map[string]domain.FieldChange{"priority": {From: "P3", To: "P1"}}},
+			}
+			for _, entry := range entries {
+				if err := repo.SaveAuditEntry(ctx, entry); err != nil {
+					t.Fatalf("save audit entry: %v", err)
+				}
+			}
+
+			all, err := repo.GetAuditEntries(ctx, domain.AuditQuery{})
+			if err != nil || len(all) != 2 || all[0].ID != "audit-2" {
+				t.Fatalf("expected both entries newest first, got %+v (err %v)", all, err)
+			}
+			if change := all[0].Changes["priority"]; change.From != "P3" || change.To != "P1" {
+				t.Errorf("changes round-trip: got %+v", all[0].Changes)
+			}
+
+			found, err := repo.GetAuditEntries(ctx, domain.AuditQuery{PathPrefix: "/api/playbooks", From: at.Add(-2 * time.Hour)})
+			if err != nil || len(found) != 1 || found[0].Actor != "alice" {
+				t.Fatalf("expected alice's playbook edit, got %+v (err %v)", found, err)
+			}
+
+			found, err = repo.GetAuditEntries(ctx, domain.AuditQuery{Actor: "alice", From: at.Add(-time.Minute)})
+			if err != nil || len(found) != 0 {
+				t.Fatalf("expected no entries, got %+v (err %v)", found, err)
+			}
+		})
+	}
+}
+
+func TestSQLRepository_Escalations(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+			incident := domain.Incident{ID: "incident-1", Title: "Database down", Status: domain.StatusCritical, StartedAt: start}
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				t.Fatalf("save incident: %v", err)
+			}
+
+			escalations := []domain.Escalation{
+				{IncidentID: "incident-1", Level: 2, Priority: domain.PriorityP1, Target: "slack", EscalatedAt: start.Add(15 * time.Minute)},
+				{IncidentID: "incident-1", Level: 1, Priority: domain.PriorityP1, Target: "Bob (secondary on-call), slack", EscalatedAt: start.Add(5 * time.Minute)},
+				// A level is kept once
+				{IncidentID: "incident-1", Level: 1, Priority: domain.PriorityP1, Target: "teams", EscalatedAt: start.Add(6 * time.Minute)},
+			}
+			for _, escalation := range escalations {
+				if err := repo.SaveEscalation(ctx, escalation); err != nil {
+					t.Fatalf("save escalation: %v", err)
+				}
+			}
+
+			found, err := repo.GetEscalations(ctx, "incident-1")
+			if err != nil || len(found) != 2 {
+				t.Fatalf("expected two escalations, got %+v (err %v)", found, err)
+			}
+			if found[0].Level != 1 || found[0].Target != "Bob (secondary on-call), slack" || !found[0].EscalatedAt.Equal(start.Add(5*time.Minute)) {
+				t.Errorf("expected the first level 1 escalation first, got %+v", found[0])
+			}
+			if found[1].Level != 2 || found[1].Priority != domain.PriorityP1 {
+				t.Errorf("unexpected level 2 escalation %+v", found[1])
+			}
+		})
+	}
+}
+
+func TestSQLRepository_Webhooks(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			at := time.Now().UTC().Truncate(time.Second)
+			webhook := domain.Webhook{ID: "webhook-1", URL: "https://example.com/hook", Secret: "s3cret",
+				Events: []string{"incident.created"}, Enabled: true, CreatedAt: at, UpdatedAt: at}
+			if err := repo.SaveWebhook(ctx, webhook); err != nil {
+				t.Fatalf("save webhook: %v", err)
+			}
+			webhook.Enabled = false
+			webhook.UpdatedAt = at.Add(time.Minute)
+			if err := repo.SaveWebhook(ctx, webhook); err != nil {
+				t.Fatalf("update webhook: %v", err)
+			}
+
+			found, err := repo.GetWebhook(ctx, "webhook-1")
+			if err != nil || found == nil || found.Enabled || found.Secret != "s3cret" || len(found.Events) != 1 {
+				t.Fatalf("expected the updated webhook, got %+v (err %v)", found, err)
+			}
+			if missing, err := repo.GetWebhook(ctx, "webhook-2"); err != nil || missing != nil {
+				t.Fatalf("expected no webhook, got %+v (err %v)", missing, err)
+			}
+
+			for i, failedAt := range []time.Time{at, at.Add(time.Second)} {
+				delivery := domain.FailedDelivery{ID: fmt.Sprintf("delivery-%d", i+1), WebhookID: "webhook-1",
+					EventType: "incident.created", ContentType: "application/json", Payload: `{"id":"1"}`,
+					Attempts: 5, StatusCode: 503, Error: "webhook responded with status 503", FailedAt: failedAt}
+				if err := repo.SaveFailedDelivery(ctx, delivery); err != nil {
+					t.Fatalf("save failed delivery: %v", err)
+				}
+			}
+			deliveries, err := repo.GetFailedDeliveries(ctx, "webhook-1", 1)
+			if err != nil || len(deliveries) != 1 || deliveries[0].ID != "delivery-2" || deliveries[0].StatusCode != 503 {
+				t.Fatalf("expected the newest failed delivery, got %+v (err %v)", deliveries, err)
+			}
+
+			if err := repo.DeleteWebhook(ctx, "webhook-1"); err != nil {
+				t.Fatalf("delete webhook: %v", err)
+			}
+			webhooks, _ := repo.GetWebhooks(ctx)
+			deliveries, _ = repo.GetFailedDeliveries(ctx, "", 0)
+			if len(webhooks) != 0 || len(deliveries) != 0 {
+				t.Fatalf("expected the webhook and its failed deliveries deleted, got %+v, %+v", webhooks, deliveries)
+			}
+		})
+	}
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			migrator, err := repo.Migrator()
+			if err != nil {
+				t.Fatal(err)
+			}
+			latest := migrator.Migrations()[len(migrator.Migrations())-1].Version
+
+			if v, err := migrator.Version(ctx); err != nil || v != latest {
+				t.Fatalf("version after init: %d (err %v), want %d", v, err, latest)
+			}
+			if n, err := migrator.Down(ctx, 1); err != nil || n != 1 {
+				t.Fatalf("down: %d, err %v", n, err)
+			}
+			if n, err := migrator.Up(ctx); err != nil || n != 1 {
+				t.Fatalf("up: %d, err %v", n, err)
+			}
+			if n, err := migrator.Up(ctx); err != nil || n != 0 {
+				t.Fatalf("second up should be a no-op: %d, err %v", n, err)
+			}
+		})
+	}
+}
+
+func TestSQLRepository_MigrateIDs(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			start := time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+			alert := domain.Alert{
+				ID: "1700000000-1", ExternalID: 1, Host: "db-01", Chart: "disk.space", Name: "disk_full", Source: "netdata",
+				Status: domain.StatusCritical, OldStatus: domain.StatusWarning, OccurredAt: start,
+				ResourceType: domain.ResourceDisk, Labels: map[string]string{"team": "storage"},
+				RawPayload: `{"unique_id":1}`,
+			}
+			if err := repo.SaveAlert(ctx, alert); err != nil {
+				t.Fatalf("save alert: %v", err)
+			}
+			incident := domain.Incident{ID: "incident-1700000000", Title: "disk", Status: domain.StatusCritical,
+				StartedAt: start, Template: "disk", Events: []domain.Alert{alert}}
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				t.Fatalf("save incident: %v", err)
+			}
+
+			// One row in every table keyed by the alert or incident
+			steps := []error{
+				repo.SaveAlertSamples(ctx, alert.ID, []domain.MetricSample{{At: start, Value: 97}}),
+				repo.AcknowledgeAlert(ctx, domain.AlertAck{AlertID: alert.ID, By: "oncall", AcknowledgedAt: start}),
+				repo.SaveAcknowledgement(ctx, incident.ID, "oncall", start),
+				repo.AppendTimelineEntries(ctx, incident.ID, []domain.TimelineEntry{{Timestamp: start, Type: "TRIGGERED",
+					Message: "disk full", Severity: "critical", RelatedAlertIDs: []string{alert.ID}, CausedBy: []string{alert.ID}}}),
+				repo.SaveTicket(ctx, domain.Ticket{IncidentID: incident.ID, Tracker: "jira", Key: "OPS-1", CreatedAt: start}),
+				repo.SaveRootCause(ctx, domain.RootCauseRecord{IncidentID: incident.ID, ModelVersion: "1.0.0", AlertID: alert.ID,
+					RawScore: 0.5, Confidence: 0.4, PredictedAt: start}),
+				repo.SetPriority(ctx, domain.PriorityChange{IncidentID: incident.ID, Priority: domain.PriorityP1,
+					ChangedBy: "oncall", ChangedAt: start}),
+				repo.SaveEscalation(ctx, domain.Escalation{IncidentID: incident.ID, Level: 1, Priority: domain.PriorityP1,
+					Target: "slack", EscalatedAt: start}),
+				repo.SetIncidentMetadata(ctx, domain.IncidentMetadata{IncidentID: incident.ID, Severity: "sev2",
+					UpdatedBy: "oncall", UpdatedAt: start}),
+				repo.SetRootCauseOverride(ctx, domain.RootCauseOverride{IncidentID: incident.ID, AlertID: alert.ID,
+					SetBy: "oncall", SetAt: start}),
+				repo.SetIncidentState(ctx, domain.StateTransition{IncidentID: incident.ID, From: domain.StateDetected,
+					To: domain.StateTriaged, ChangedBy: "oncall", ChangedAt: start}),
+				repo.LinkIncident(ctx, domain.ProblemLink{IncidentID: incident.ID, ProblemID: incident.ID, Similarity: 1, LinkedAt: start}),
+				repo.SaveActionItem(ctx, domain.ActionItem{ID: "item-1", IncidentID: incident.ID, Description: "Add disk alerts",
+					Status: domain.ActionItemOpen, CreatedAt: start, UpdatedAt: start}),
+			}
+			for i, err := range steps {
+				if err != nil {
+					t.Fatalf("populate step %d: %v", i, err)
+				}
+			}
+
+			gen := idgen.ULID{}
+			alerts, incidents, err := repo.MigrateIDs(ctx, gen)
+			if err != nil || alerts != 1 || incidents != 1 {
+				t.Fatalf("expected one alert and one incident migrated, got %d, %d (err %v)", alerts, incidents, err)
+			}
+			newAlertID := gen.Derive(start, alert.ID)
+			newIncidentID := gen.Derive(start, newAlertID)
+
+			stored, err := repo.GetAlert(ctx, newAlertID)
+			if err != nil || stored == nil || stored.RawPayload != alert.RawPayload || stored.Ack == nil || stored.Labels["team"] != "storage" {
+				t.Fatalf("expected the migrated alert with its payload and ack, got %+v (err %v)", stored, err)
+			}
+			var fingerprint sql.NullString
+			if err := repo.db.QueryRowContext(ctx, repo.dialect.Rebind("SELECT fingerprint FROM alerts WHERE id = ?"), newAlertID).
+				Scan(&fingerprint); err != nil || fingerprint.String != alert.Fingerprint() {
+				t.Errorf("expected the fingerprint to move to the new alert, got %q (err %v)", fingerprint.String, err)
+			}
+
+			all, err := repo.GetIncidents(ctx)
+			if err != nil || len(all) != 1 || all[0].ID != newIncidentID {
+				t.Fatalf("expected the migrated incident, got %+v (err %v)", all, err)
+			}
+			migrated := all[0]
+			if migrated.Template != "disk" || migrated.Severity != "sev2" || migrated.PriorityOverride != domain.PriorityP1 ||
+				migrated.State != domain.StateTriaged || migrated.RootCauseOverride == nil ||
+				migrated.RootCauseOverride.AlertID != newAlertID || len(migrated.Events) != 1 || len(migrated.Events[0].Samples) != 1 {
+				t.Errorf("expected the incident's columns and related rows to survive, got %+v", migrated)
+			}
+
+			timeline, err := repo.GetTimelineEntries(ctx, newIncidentID)
+			if err != nil || len(timeline) != 1 || timeline[0].RelatedAlertIDs[0] != newAlertID || timeline[0].CausedBy[0] != newAlertID {
+				t.Errorf("expected the timeline to reference the new alert ID, got %+v (err %v)", timeline, err)
+			}
+			links, err := repo.GetProblemLinks(ctx, newIncidentID)
+			if err != nil || len(links) != 1 || links[0].IncidentID != newIncidentID {
+				t.Errorf("expected the problem link to follow the incident, got %+v (err %v)", links, err)
+			}
+
+			for _, group := range []struct {
+				columns      []idColumn
+				legacy, next string
+			}{
+				{alertIDColumns, alert.ID, newAlertID},
+				{incidentIDColumns, incident.ID, newIncidentID},
+			} {
+				for _, ref := range group.columns {
+					query := repo.dialect.Rebind(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = ?", ref.table, ref.column))
+					var legacy, migrated int
+					if err := repo.db.QueryRowContext(ctx, query, group.legacy).Scan(&legacy); err != nil {
+						t.Fatalf("count %s.%s: %v", ref.table, ref.column, err)
+					}
+					if err := repo.db.QueryRowContext(ctx, query, group.next).Scan(&migrated); err != nil {
+						t.Fatalf("count %s.%s: %v", ref.table, ref.column, err)
+					}
+					if legacy != 0 || migrated == 0 {
+						t.Errorf("%s.%s: expected its rows relinked, got %d legacy and %d migrated", ref.table, ref.column, legacy, migrated)
+					}
+				}
+			}
+
+			// Migrating again is a no-op
+			if alerts, incidents, err := repo.MigrateIDs(ctx, gen); err != nil || alerts != 0 || incidents != 0 {
+				t.Errorf("expected nothing left to migrate, got %d, %d (err %v)", alerts, incidents, err)
+			}
+		})
+	}
+}
+
+func TestSQLRepository_ExportCursor(t *testing.T) {
+	for dialect, dsn := range integrationDatabases(t) {
+		t.Run(string(dialect), func(t *testing.T) {
+			repo := openIntegrationRepository(t, dialect, dsn)
+			ctx := context.Background()
+
+			if cursor, err := repo.GetExportCursor(ctx, "otlp_timeline"); err != nil || !cursor.IsZero() {
+				t.Fatalf("expected no cursor, got %s (err %v)", cursor, err)
+			}
+			at := time.Date(2024, 1, 2, 12, 0, 0, 123456789, time.UTC)
+			for _, cursor := range []time.Time{at.Add(-time.Hour), at} {
+				if err := repo.SetExportCursor(ctx, "otlp_timeline", cursor); err != nil {
+					t.Fatalf("set cursor: %v", err)
+				}
+			}
+			if cursor, err := repo.GetExportCursor(ctx, "otlp_timeline"); err != nil || !cursor.Equal(at) {
+				t.Fatalf("expected %s, got %s (err %v)", at, cursor, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SQLiteOptions tune SQLite for concurrent API reads and poller writes
+type SQLiteOptions struct {
+	JournalMode  string        // e.g. WAL, so readers don't block the writer; empty keeps the file's mode
+	BusyTimeout  time.Duration // How long a statement waits for a lock before failing with SQLITE_BUSY
+	Synchronous  string        // e.g. NORMAL, safe with WAL and fewer fsyncs than FULL; empty keeps the default
+	MaxOpenConns int           // Connections in the pool; 1 serializes every access through a single writer
+}
+
+// TuneSQLite applies the options to a SQLite database and does nothing for other dialects.
+// busy_timeout and synchronous are per connection, so they are set on every pooled
+// connection, which are then kept open for good.
+func (r *SQLRepository) TuneSQLite(ctx context.Context, opts SQLiteOptions) error {
+	if r.dialect != DialectSQLite {
+		return nil
+	}
+
+	conns := max(opts.MaxOpenConns, 1)
+	r.db.SetMaxOpenConns(conns)
+	r.db.SetMaxIdleConns(conns)
+	r.db.SetConnMaxLifetime(0)
+	r.db.SetConnMaxIdleTime(0)
+
+	// The journal mode is stored in the database file, so setting it once is enough
+	if opts.JournalMode != "" {
+		var mode string
+		if err := r.db.QueryRowContext(ctx, "PRAGMA journal_mode = "+opts.JournalMode).Scan(&mode); err != nil {
+			return fmt.Errorf("failed to set SQLite journal mode: %w", err)
+		}
+		if !strings.EqualFold(mode, opts.JournalMode) {
+			return fmt.Errorf("failed to set SQLite journal mode %s: database stays in %s", opts.JournalMode, mode)
+		}
+	}
+
+	pragmas := []string{fmt.Sprintf("PRAGMA busy_timeout = %d", opts.BusyTimeout.Milliseconds())}
+	if opts.Synchronous != "" {
+		pragmas = append(pragmas, "PRAGMA synchronous = "+opts.Synchronous)
+	}
+
+	// Hold every connection at once so each of them is configured
+	held := make([]*sql.Conn, 0, conns)
+	defer func() {
+		for _, conn := range held {
+			conn.Close()
+		}
+	}()
+	for i := 0; i < conns; i++ {
+		conn, err := r.db.Conn(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to open SQLite connection: %w", err)
+		}
+		held = append(held, conn)
+		for _, pragma := range pragmas {
+			if _, err := conn.ExecContext(ctx, pragma); err != nil {
+				return fmt.Errorf("failed to apply %q: %w", pragma, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"incident-teller/internal/domain"
+)
+
+// GetTicket returns the tracker ticket filed for an incident, or nil if there is none
+func (r *SQLRepository) GetTicket(ctx context.Context, incidentID string) (*domain.Ticket, error) {
+	query := `
+		SELECT tracker, ticket_key, url, created_at, resolved_at
+		FROM incident_tickets
+		WHERE incident_id = ?
+	`
+
+	ticket := domain.Ticket{IncidentID: incidentID}
+	var resolvedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), incidentID).
+		Scan(&ticket.Tracker, &ticket.Key, &ticket.URL, &ticket.CreatedAt, &resolvedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticket: %w", err)
+	}
+	if resolvedAt.Valid {
+		ticket.ResolvedAt = &resolvedAt.Time
+	}
+	return &ticket, nil
+}
+
+// SaveTicket stores the ticket of an incident, replacing any earlier one
+func (r *SQLRepository) SaveTicket(ctx context.Context, ticket domain.Ticket) error {
+	query := `
+		INSERT INTO incident_tickets (incident_id, tracker, ticket_key, url, created_at, resolved_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	` + r.dialect.OnConflictUpdate([]string{"incident_id"}, []string{"tracker", "ticket_key", "url", "created_at", "resolved_at"})
+
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(query),
+		ticket.IncidentID, ticket.Tracker, ticket.Key, ticket.URL, ticket.CreatedAt, ticket.ResolvedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save ticket: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// GetTimelineEntries returns the stored timeline entries of an incident in order
+func (r *SQLRepository) GetTimelineEntries(ctx context.Context, incidentID string) ([]domain.TimelineEntry, error) {
+	query := `
+		SELECT alert_ids, occurred_at, entry_type, message, severity, since_start_ms, caused_by, resource_type
+		FROM timeline_entries
+		WHERE incident_id = ?
+		ORDER BY sequence_order
+	`
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query timeline entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []domain.TimelineEntry{}
+	for rows.Next() {
+		var entry domain.TimelineEntry
+		var alertIDs, causedBy, resourceType string
+		var sinceStartMs sql.NullInt64
+
+		if err := rows.Scan(&alertIDs, &entry.Timestamp, &entry.Type, &entry.Message, &entry.Severity,
+			&sinceStartMs, &causedBy, &resourceType); err != nil {
+			return nil, fmt.Errorf("failed to scan timeline entry: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(alertIDs), &entry.RelatedAlertIDs); err != nil {
+			return nil, fmt.Errorf("failed to decode timeline entry alerts: %w", err)
+		}
+		if err := json.Unmarshal([]byte(causedBy), &entry.CausedBy); err != nil {
+			return nil, fmt.Errorf("failed to decode timeline entry causes: %w", err)
+		}
+		if sinceStartMs.Valid {
+			sinceStart := time.Duration(sinceStartMs.Int64) * time.Millisecond
+			entry.DurationSinceStart = &sinceStart
+		}
+		entry.ResourceType = domain.ResourceType(resourceType)
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// AppendTimelineEntries adds entries after the incident's stored timeline entries
+func (r *SQLRepository) AppendTimelineEntries(ctx context.Context, incidentID string, entries []domain.TimelineEntry) error {
+	return r.saveTimelineEntries(ctx, incidentID, entries, false)
+}
+
+// ReplaceTimelineEntries replaces the stored timeline of an incident
+func (r *SQLRepository) ReplaceTimelineEntries(ctx context.Context, incidentID string, entries []domain.TimelineEntry) error {
+	return r.saveTimelineEntries(ctx, incidentID, entries, true)
+}
+
+func (r *SQLRepository) saveTimelineEntries(ctx context.Context, incidentID string, entries []domain.TimelineEntry, replace bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	next := 0
+	if replace {
+		if _, err := tx.ExecContext(ctx, r.dialect.Rebind("DELETE FROM timeline_entries WHERE incident_id = ?"), incidentID); err != nil {
+			return fmt.Errorf("failed to delete timeline entries: %w", err)
+		}
+	} else {
+		row := tx.QueryRowContext(ctx, r.dialect.Rebind(
+			"SELECT COALESCE(MAX(sequence_order) + 1, 0) FROM timeline_entries WHERE incident_id = ?"), incidentID)
+		if err := row.Scan(&next); err != nil {
+			return fmt.Errorf("failed to get timeline length: %w", err)
+		}
+	}
+
+	stmt, err := tx.PrepareContext(ctx, r.dialect.Rebind(`
+		INSERT INTO timeline_entries
+			(incident_id, sequence_order, alert_ids, occurred_at, entry_type, message, severity, since_start_ms, caused_by, resource_type)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`))
+	if err != nil {
+		return fmt.Errorf("failed to prepare timeline entry insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, entry := range entries {
+		alertIDs, err := json.Marshal(nonNilStrings(entry.RelatedAlertIDs))
+		if err != nil {
+			return err
+		}
+		causedBy, err := json.Marshal(nonNilStrings(entry.CausedBy))
+		if err != nil {
+			return err
+		}
+		var sinceStartMs interface{}
+		if entry.DurationSinceStart != nil {
+			sinceStartMs = entry.DurationSinceStart.Milliseconds()
+		}
+
+		_, err = stmt.ExecContext(ctx,
+			incidentID, next+i, string(alertIDs), entry.Timestamp, entry.Type, entry.Message,
+			entry.Severity, sinceStartMs, string(causedBy), string(entry.ResourceType),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert timeline entry: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// nonNilStrings stores empty lists as [] rather than null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,185 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"incident-teller/internal/domain"
+)
+
+// GetWebhooks returns every outgoing webhook, oldest first
+func (r *SQLRepository) GetWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	query := `
+		SELECT id, url, secret, events, enabled, created_at, updated_at
+		FROM webhooks
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []domain.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+// GetWebhook returns a webhook, or nil if it doesn't exist
+func (r *SQLRepository) GetWebhook(ctx context.Context, id string) (*domain.Webhook, error) {
+	query := `
+		SELECT id, url, secret, events, enabled, created_at, updated_at
+		FROM webhooks
+		WHERE id = ?
+	`
+
+	webhook, err := scanWebhook(r.db.QueryRowContext(ctx, r.dialect.Rebind(query), id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// scanWebhook reads a webhook row
+func scanWebhook(row interface{ Scan(...any) error }) (domain.Webhook, error) {
+	var webhook domain.Webhook
+	var events string
+	err := row.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, &events, &webhook.Enabled,
+		&webhook.CreatedAt, &webhook.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return webhook, err
+	}
+	if err != nil {
+		return webhook, fmt.Errorf("failed to scan webhook: %w", err)
+	}
+	if err := json.Unmarshal([]byte(events), &webhook.Events); err != nil {
+		return webhook, fmt.Errorf("failed to unmarshal webhook events: %w", err)
+	}
+	return webhook, nil
+}
+
+// SaveWebhook stores or replaces a webhook
+func (r *SQLRepository) SaveWebhook(ctx context.Context, webhook domain.Webhook) error {
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook events: %w", err)
+	}
+
+	query := `
+		INSERT INTO webhooks (id, url, secret, events, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	` + r.dialect.OnConflictUpdate([]string{"id"}, []string{"url", "secret", "events", "enabled", "updated_at"})
+
+	_, err = r.db.ExecContext(ctx, r.dialect.Rebind(query), webhook.ID, webhook.URL, webhook.Secret,
+		string(events), webhook.Enabled, webhook.CreatedAt, webhook.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save webhook: %w", err)
+	}
+	return nil
+}
+
+// DeleteWebhook removes a webhook with its failed deliveries
+func (r *SQLRepository) DeleteWebhook(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// SQLite doesn't enforce the cascade unless foreign keys are enabled
+	if _, err := tx.ExecContext(ctx, r.dialect.Rebind("DELETE FROM webhook_failed_deliveries WHERE webhook_id = ?"), id); err != nil {
+		return fmt.Errorf("failed to delete failed deliveries: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, r.dialect.Rebind("DELETE FROM webhooks WHERE id = ?"), id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return tx.Commit()
+}
+
+// SaveFailedDelivery adds a delivery to the dead letters
+func (r *SQLRepository) SaveFailedDelivery(ctx context.Context, delivery domain.FailedDelivery) error {
+	query := `
+		INSERT INTO webhook_failed_deliveries
+			(id, webhook_id, event_type, content_type, payload, attempts, status_code, error, failed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), delivery.ID, delivery.WebhookID, delivery.EventType,
+		delivery.ContentType, delivery.Payload, delivery.Attempts, delivery.StatusCode, delivery.Error, delivery.FailedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save failed delivery: %w", err)
+	}
+	return nil
+}
+
+// GetFailedDelivery returns a dead letter, or nil if it doesn't exist
+func (r *SQLRepository) GetFailedDelivery(ctx context.Context, id string) (*domain.FailedDelivery, error) {
+	deliveries, err := r.queryFailedDeliveries(ctx, " WHERE id = ?", id)
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+	return &deliveries[0], nil
+}
+
+// GetFailedDeliveries returns the dead letters, of one webhook if webhookID is set, newest
+// first. limit 0 returns all.
+func (r *SQLRepository) GetFailedDeliveries(ctx context.Context, webhookID string, limit int) ([]domain.FailedDelivery, error) {
+	var where string
+	var args []any
+	if webhookID != "" {
+		where = " WHERE webhook_id = ?"
+		args = append(args, webhookID)
+	}
+	where += " ORDER BY failed_at DESC, id DESC"
+	if limit > 0 {
+		where += " LIMIT ?"
+		args = append(args, limit)
+	}
+	return r.queryFailedDeliveries(ctx, where, args...)
+}
+
+// queryFailedDeliveries returns the dead letters selected by the clauses following FROM
+func (r *SQLRepository) queryFailedDeliveries(ctx context.Context, clauses string, args ...any) ([]domain.FailedDelivery, error) {
+	query := `
+		SELECT id, webhook_id, event_type, content_type, payload, attempts, status_code, error, failed_at
+		FROM webhook_failed_deliveries
+	` + clauses
+
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query failed deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []domain.FailedDelivery{}
+	for rows.Next() {
+		var d domain.FailedDelivery
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.EventType, &d.ContentType, &d.Payload, &d.Attempts,
+			&d.StatusCode, &d.Error, &d.FailedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan failed delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// DeleteFailedDelivery removes a dead letter
+func (r *SQLRepository) DeleteFailedDelivery(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind("DELETE FROM webhook_failed_deliveries WHERE id = ?"), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete failed delivery: %w", err)
+	}
+	return nil
+}
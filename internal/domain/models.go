@@ -1,6 +1,12 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -19,14 +25,34 @@ const (
 type ResourceType string
 
 const (
-	ResourceUnknown ResourceType = "UNKNOWN"
-	ResourceCPU     ResourceType = "CPU"
-	ResourceMemory  ResourceType = "MEMORY"
-	ResourceDisk    ResourceType = "DISK"
-	ResourceNetwork ResourceType = "NETWORK"
-	ResourceProcess ResourceType = "PROCESS"
+	ResourceUnknown     ResourceType = "UNKNOWN"
+	ResourceCPU         ResourceType = "CPU"
+	ResourceMemory      ResourceType = "MEMORY"
+	ResourceDisk        ResourceType = "DISK"
+	ResourceNetwork     ResourceType = "NETWORK"
+	ResourceProcess     ResourceType = "PROCESS"
+	ResourceDatabase    ResourceType = "DATABASE"
+	ResourceContainer   ResourceType = "CONTAINER"
+	ResourceApplication ResourceType = "APPLICATION"
 )
 
+// ResourceTypes are the known resource types other than ResourceUnknown
+var ResourceTypes = []ResourceType{
+	ResourceCPU, ResourceMemory, ResourceDisk, ResourceNetwork, ResourceProcess,
+	ResourceDatabase, ResourceContainer, ResourceApplication,
+}
+
+// ParseResourceType parses the name of a known resource type, in any case
+func ParseResourceType(s string) (ResourceType, error) {
+	rt := ResourceType(strings.ToUpper(strings.TrimSpace(s)))
+	for _, known := range ResourceTypes {
+		if rt == known {
+			return rt, nil
+		}
+	}
+	return "", fmt.Errorf("invalid resource type %q: must be one of %v", s, ResourceTypes)
+}
+
 // Alert represents a normalized event ingested from an external source (Netdata)
 type Alert struct {
 	ID           string       // Unique Event ID
@@ -42,6 +68,76 @@ type Alert struct {
 	Description  string       // Raw description if available
 	ResourceType ResourceType // Classified resource type
 	Labels       map[string]string
+	Source       string         // Alert source that reported it, e.g. "netdata" or "zabbix"
+	Samples      []MetricSample // Recent values of the alert's chart, oldest first, if sampled
+	RawPayload   string         // The source's record of the alert as JSON, kept at ingest
+	Ack          *AlertAck      // Set when a responder acknowledged the alert itself
+}
+
+// AlertAck is the acknowledgement of a single alert, e.g. a noisy one, which leaves its
+// incident open
+type AlertAck struct {
+	AlertID        string
+	By             string
+	Note           string
+	AcknowledgedAt time.Time
+}
+
+// AlertQuery filters stored alerts. Zero fields match everything.
+type AlertQuery struct {
+	Search       string // Free text over host, chart and alert name
+	Host         string
+	Source       string
+	Statuses     []AlertStatus // Any of which matches
+	From         time.Time
+	To           time.Time
+	Acknowledged *bool
+	Limit        int // Newest alerts first; 0 returns all
+	Offset       int
+}
+
+// Matches reports whether an alert passes the filters
+func (q AlertQuery) Matches(alert Alert) bool {
+	switch {
+	case q.Host != "" && alert.Host != q.Host,
+		q.Source != "" && alert.Source != q.Source,
+		!q.From.IsZero() && alert.OccurredAt.Before(q.From),
+		!q.To.IsZero() && alert.OccurredAt.After(q.To),
+		q.Acknowledged != nil && *q.Acknowledged != (alert.Ack != nil):
+		return false
+	}
+	if len(q.Statuses) > 0 {
+		found := false
+		for _, status := range q.Statuses {
+			found = found || alert.Status == status
+		}
+		if !found {
+			return false
+		}
+	}
+	if search := strings.ToLower(strings.TrimSpace(q.Search)); search != "" {
+		return strings.Contains(strings.ToLower(alert.Host), search) ||
+			strings.Contains(strings.ToLower(alert.Chart), search) ||
+			strings.Contains(strings.ToLower(alert.Name), search)
+	}
+	return true
+}
+
+// MetricSample is one value of a chart at a point in time
+type MetricSample struct {
+	At    time.Time `json:"at"`
+	Value float64   `json:"value"`
+}
+
+// Fingerprint identifies an alert by the source that reported it, the source's own ID
+// for it and when it occurred, so the same event is stored once whichever adapter or
+// replica ingested it. It is empty for alerts without a source or external ID, e.g.
+// scripted test alerts.
+func (a Alert) Fingerprint() string {
+	if a.Source == "" || a.ExternalID == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d:%d", a.Source, a.ExternalID, a.OccurredAt.UnixMilli())
 }
 
 // Incident represents a grouped collection of alerts related to a specific issue
@@ -52,6 +148,490 @@ type Incident struct {
 	StartedAt  time.Time
 	ResolvedAt *time.Time // Nil if active
 	Events     []Alert    // Ordered list of events in this incident
+
+	PriorityOverride Priority // Set manually; empty follows the template or the risk level
+	Template         string   // ID of the incident template the incident matched, if any
+	DefaultPriority  Priority // From the template; empty follows the risk level
+
+	// Taxonomy of the organization, set through the API and kept when the incident is
+	// correlated again
+	Severity     string            // e.g. "SEV2"; empty if not classified
+	Tags         []string          // Lower case, sorted
+	CustomFields map[string]string // e.g. team, product_area, customer_impacting
+
+	// Root cause pinned by a responder, preferred over analyzed ones; nil if not pinned
+	RootCauseOverride *RootCauseOverride
+
+	// Lifecycle state responders last moved the incident to, and when; empty until the
+	// first transition (see CurrentState)
+	State          IncidentState
+	StateChangedAt *time.Time
+}
+
+// Labels returns the merged labels of all incident events plus the "host" of the first event.
+// When events disagree on a label value, the earliest event wins.
+func (i Incident) Labels() map[string]string {
+	labels := make(map[string]string)
+	for _, event := range i.Events {
+		for k, v := range event.Labels {
+			if _, exists := labels[k]; !exists && v != "" {
+				labels[k] = v
+			}
+		}
+	}
+	if _, exists := labels["host"]; !exists && len(i.Events) > 0 {
+		labels["host"] = i.Events[0].Host
+	}
+	return labels
+}
+
+// Duration returns how long the incident lasted, or has lasted until now if still active
+func (i Incident) Duration(now time.Time) time.Duration {
+	if i.ResolvedAt != nil {
+		return i.ResolvedAt.Sub(i.StartedAt)
+	}
+	return now.Sub(i.StartedAt)
+}
+
+// Hosts returns the distinct hosts affected by the incident, sorted
+func (i Incident) Hosts() []string {
+	seen := make(map[string]bool)
+	hosts := []string{}
+	for _, event := range i.Events {
+		if event.Host != "" && !seen[event.Host] {
+			seen[event.Host] = true
+			hosts = append(hosts, event.Host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// CriticalityLabel is the alert label (typically added by enrichment) that adjusts an
+// incident's risk level: "high" raises it one level, "low" lowers it one level
+const CriticalityLabel = "criticality"
+
+var riskLevels = []string{"low", "medium", "high", "critical"}
+
+// RiskThresholds are the counts from which an incident reaches a risk level. Reaching
+// any of them is enough; a zero threshold is never reached.
+type RiskThresholds struct {
+	CriticalAlerts float64 // Critical alerts, each counting its resource type's weight
+	Hosts          int
+	ResourceTypes  int
+}
+
+// ResourceRisk tunes how the alerts of a resource type weigh in an incident's risk
+type ResourceRisk struct {
+	Weight    float64 // What a critical alert counts for; 1 when 0
+	HighValue float64 // Alerts valued above it count as critical whatever their status; 0 never
+}
+
+// RiskPolicy holds the thresholds incidents are classified into risk levels with
+type RiskPolicy struct {
+	Medium, High, Critical RiskThresholds
+	Resources              map[ResourceType]ResourceRisk
+}
+
+// RiskAssessment is the risk level of an incident with the reasons for it, which name the
+// thresholds that were reached
+type RiskAssessment struct {
+	Level   string
+	Reasons []string
+}
+
+// DefaultRiskPolicy returns the built-in thresholds: 1 critical alert or 2 hosts is
+// medium, 2 of any count high and 3 critical
+func DefaultRiskPolicy() RiskPolicy {
+	return RiskPolicy{
+		Medium:   RiskThresholds{CriticalAlerts: 1, Hosts: 2},
+		High:     RiskThresholds{CriticalAlerts: 2, Hosts: 2, ResourceTypes: 2},
+		Critical: RiskThresholds{CriticalAlerts: 3, Hosts: 3, ResourceTypes: 3},
+	}
+}
+
+var (
+	riskPolicyMu sync.RWMutex
+	riskPolicy   = DefaultRiskPolicy()
+)
+
+// SetRiskPolicy replaces the process-wide policy incidents are classified with
+func SetRiskPolicy(p RiskPolicy) {
+	riskPolicyMu.Lock()
+	defer riskPolicyMu.Unlock()
+	riskPolicy = p
+}
+
+// CurrentRiskPolicy returns the process-wide risk policy
+func CurrentRiskPolicy() RiskPolicy {
+	riskPolicyMu.RLock()
+	defer riskPolicyMu.RUnlock()
+	return riskPolicy
+}
+
+// HighValue returns the value above which alerts of the resource type count as critical,
+// 0 if none is set
+func (p RiskPolicy) HighValue(rt ResourceType) float64 {
+	return p.Resources[rt].HighValue
+}
+
+func (p RiskPolicy) weight(rt ResourceType) float64 {
+	if w := p.Resources[rt].Weight; w > 0 {
+		return w
+	}
+	return 1
+}
+
+// Assess classifies the incident by its weighted critical alerts, affected hosts and
+// affected resource types, adjusted by the incident's criticality label
+func (p RiskPolicy) Assess(i Incident) RiskAssessment {
+	if len(i.Events) == 0 {
+		return RiskAssessment{Level: "low", Reasons: []string{"no alerts"}}
+	}
+
+	critical := 0.0
+	hosts := make(map[string]bool)
+	resourceTypes := make(map[ResourceType]bool)
+	for _, event := range i.Events {
+		highValue := p.HighValue(event.ResourceType)
+		if event.Status == StatusCritical || (highValue > 0 && event.Value > highValue) {
+			critical += p.weight(event.ResourceType)
+		}
+		hosts[event.Host] = true
+		resourceTypes[event.ResourceType] = true
+	}
+
+	assessment := RiskAssessment{Level: "low"}
+	for rank := len(riskLevels) - 1; rank > 0; rank-- {
+		level := riskLevels[rank]
+		thresholds := p.thresholds(level)
+		if thresholds.CriticalAlerts > 0 && critical >= thresholds.CriticalAlerts {
+			assessment.Reasons = append(assessment.Reasons, fmt.Sprintf("%s critical alerts reach the %s threshold of %s",
+				formatCount(critical), level, formatCount(thresholds.CriticalAlerts)))
+		}
+		if thresholds.Hosts > 0 && len(hosts) >= thresholds.Hosts {
+			assessment.Reasons = append(assessment.Reasons, fmt.Sprintf("%d hosts reach the %s threshold of %d",
+				len(hosts), level, thresholds.Hosts))
+		}
+		if thresholds.ResourceTypes > 0 && len(resourceTypes) >= thresholds.ResourceTypes {
+			assessment.Reasons = append(assessment.Reasons, fmt.Sprintf("%d resource types reach the %s threshold of %d",
+				len(resourceTypes), level, thresholds.ResourceTypes))
+		}
+		if len(assessment.Reasons) > 0 {
+			assessment.Level = level
+			break
+		}
+	}
+	if len(assessment.Reasons) == 0 {
+		assessment.Reasons = append(assessment.Reasons, fmt.Sprintf(
+			"%s critical alerts, %d hosts and %d resource types stay below the medium thresholds",
+			formatCount(critical), len(hosts), len(resourceTypes)))
+	}
+
+	rank := RiskRank(assessment.Level)
+	switch criticality := strings.ToLower(i.Labels()[CriticalityLabel]); criticality {
+	case "high":
+		rank = min(rank+1, len(riskLevels)-1)
+	case "low":
+		rank = max(rank-1, 0)
+	}
+	if adjusted := riskLevels[rank]; adjusted != assessment.Level {
+		assessment.Reasons = append(assessment.Reasons, fmt.Sprintf("the %s criticality label moves it from %s to %s",
+			strings.ToLower(i.Labels()[CriticalityLabel]), assessment.Level, adjusted))
+		assessment.Level = adjusted
+	}
+	return assessment
+}
+
+func (p RiskPolicy) thresholds(level string) RiskThresholds {
+	switch level {
+	case "critical":
+		return p.Critical
+	case "high":
+		return p.High
+	default:
+		return p.Medium
+	}
+}
+
+// formatCount formats a weighted count without trailing zeros, e.g. 2 or 2.5
+func formatCount(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// RiskLevel classifies the incident as "low", "medium", "high" or "critical" with the
+// process-wide risk policy
+func (i Incident) RiskLevel() string {
+	return i.RiskAssessment().Level
+}
+
+// RiskAssessment classifies the incident with the process-wide risk policy, with the
+// reasons for its level
+func (i Incident) RiskAssessment() RiskAssessment {
+	return CurrentRiskPolicy().Assess(i)
+}
+
+// Priority is the urgency of an incident, from P1 (most urgent) to P4
+type Priority string
+
+const (
+	PriorityP1 Priority = "P1"
+	PriorityP2 Priority = "P2"
+	PriorityP3 Priority = "P3"
+	PriorityP4 Priority = "P4"
+)
+
+// ParsePriority validates a priority name such as "P2" or "p2"
+func ParsePriority(s string) (Priority, error) {
+	switch p := Priority(strings.ToUpper(strings.TrimSpace(s))); p {
+	case PriorityP1, PriorityP2, PriorityP3, PriorityP4:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid priority %q: must be P1, P2, P3 or P4", s)
+	}
+}
+
+// PriorityForRisk maps a risk level onto a priority: "critical" is P1, "low" is P4
+func PriorityForRisk(level string) Priority {
+	return []Priority{PriorityP4, PriorityP3, PriorityP2, PriorityP1}[RiskRank(level)]
+}
+
+// PriorityRank orders priorities from P4 (0) to P1 (3), like RiskRank
+func PriorityRank(p Priority) int {
+	switch p {
+	case PriorityP1:
+		return 3
+	case PriorityP2:
+		return 2
+	case PriorityP3:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Priority returns the manually set priority, else the template's default priority, else
+// the one following from the risk level
+func (i Incident) Priority() Priority {
+	if i.PriorityOverride != "" {
+		return i.PriorityOverride
+	}
+	if i.DefaultPriority != "" {
+		return i.DefaultPriority
+	}
+	return PriorityForRisk(i.RiskLevel())
+}
+
+// RiskRank orders risk levels from "low" (0) to "critical" (3)
+func RiskRank(level string) int {
+	switch level {
+	case "critical":
+		return 3
+	case "high":
+		return 2
+	case "medium":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Matches reports whether the search text occurs (case-insensitively) in the incident
+// title or in the host, chart or name of any of its events. An empty search matches.
+func (i Incident) Matches(search string) bool {
+	search = strings.ToLower(strings.TrimSpace(search))
+	if search == "" {
+		return true
+	}
+	if strings.Contains(strings.ToLower(i.Title), search) {
+		return true
+	}
+	for _, event := range i.Events {
+		if strings.Contains(strings.ToLower(event.Host), search) ||
+			strings.Contains(strings.ToLower(event.Chart), search) ||
+			strings.Contains(strings.ToLower(event.Name), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// IncidentSort is a sort key for incident listings
+type IncidentSort string
+
+const (
+	SortByStartedAt IncidentSort = "started_at"
+	SortByDuration  IncidentSort = "duration"
+	SortByRisk      IncidentSort = "risk"
+	SortByEvents    IncidentSort = "events"
+	SortByPriority  IncidentSort = "priority"
+)
+
+// IncidentQuery filters and orders incident listings
+type IncidentQuery struct {
+	Search    string       // Free text over title, host, chart and alert name
+	SortBy    IncidentSort // Defaults to SortByStartedAt
+	Ascending bool         // Defaults to descending
+}
+
+// ApplyIncidentQuery filters and sorts incidents in memory. Ties are broken by most recent start.
+func ApplyIncidentQuery(incidents []Incident, q IncidentQuery, now time.Time) []Incident {
+	result := make([]Incident, 0, len(incidents))
+	for _, incident := range incidents {
+		if incident.Matches(q.Search) {
+			result = append(result, incident)
+		}
+	}
+
+	key := func(i Incident) float64 {
+		switch q.SortBy {
+		case SortByDuration:
+			return float64(i.Duration(now))
+		case SortByRisk:
+			return float64(RiskRank(i.RiskLevel()))
+		case SortByEvents:
+			return float64(len(i.Events))
+		case SortByPriority:
+			return float64(PriorityRank(i.Priority()))
+		default:
+			return float64(i.StartedAt.UnixNano())
+		}
+	}
+
+	sort.SliceStable(result, func(a, b int) bool {
+		ka, kb := key(result[a]), key(result[b])
+		if ka != kb {
+			if q.Ascending {
+				return ka < kb
+			}
+			return ka > kb
+		}
+		return result[a].StartedAt.After(result[b].StartedAt)
+	})
+
+	return result
+}
+
+// LabelGroupStats aggregates incident statistics for one value of a label key
+type LabelGroupStats struct {
+	Key       string
+	Value     string
+	Incidents int
+	Resolved  int
+	MTTR      time.Duration // Mean time to resolve across resolved incidents
+}
+
+// LabelFilter restricts analytics to the incidents with a label value, e.g. env=prod. The
+// zero value keeps every incident.
+type LabelFilter struct {
+	Key   string
+	Value string
+}
+
+// IsZero reports whether the filter keeps every incident
+func (f LabelFilter) IsZero() bool {
+	return f.Key == ""
+}
+
+// Matches reports whether an incident passes the filter
+func (f LabelFilter) Matches(incident Incident) bool {
+	return f.IsZero() || incident.Labels()[f.Key] == f.Value
+}
+
+// FrequencyCount is the number of incidents for one value of a dimension, e.g. a host
+type FrequencyCount struct {
+	Key       string
+	Incidents int
+}
+
+// RecurringIncident is an incident title seen several times within a period
+type RecurringIncident struct {
+	Title     string
+	Incidents int
+	MTTR      time.Duration // Mean time to resolve across resolved occurrences
+}
+
+// ReliabilityStats aggregates reliability metrics for the incidents started in a period
+type ReliabilityStats struct {
+	From, To       time.Time
+	Incidents      int
+	Resolved       int
+	Acknowledged   int
+	MTTR           time.Duration // Mean time to resolve across resolved incidents
+	MTTA           time.Duration // Mean time to acknowledge across acknowledged incidents
+	ByHost         []FrequencyCount
+	ByResourceType []FrequencyCount
+	ByState        []FrequencyCount // Incidents in each lifecycle state, in lifecycle order
+	ByWeekday      [7]int           // Incidents per UTC start weekday, indexed by time.Weekday
+	Recurring      []RecurringIncident
+}
+
+// HostInfo describes a monitored node as reported by the alert source
+type HostInfo struct {
+	Hostname      string
+	OS            string
+	Kernel        string
+	Architecture  string
+	Cores         int
+	AgentVersion  string
+	Labels        map[string]string
+	MirroredHosts []string // Child nodes streamed into this agent
+}
+
+// ChangeType represents the kind of change recorded by a CI/CD system
+type ChangeType string
+
+const (
+	ChangeDeploy      ChangeType = "deploy"
+	ChangeConfig      ChangeType = "config"
+	ChangeFeatureFlag ChangeType = "feature_flag"
+)
+
+// ChangeEvent represents a deployment, config change or feature-flag flip on a host/service
+type ChangeEvent struct {
+	ID          string
+	Type        ChangeType
+	Host        string    // Hostname the change was applied to (empty = all hosts of Service)
+	Service     string    // Logical service name, e.g. "checkout-api"
+	FleetWide   bool      // Applies to every host, e.g. a shared config or feature flag
+	Version     string    // e.g., "v2.3.1" or a commit SHA
+	Description string    // Free-form summary of the change
+	Source      string    // Reporting system, e.g. "github", "jenkins"
+	OccurredAt  time.Time // When the change was applied
+	Labels      map[string]string
+}
+
+// PropagationPattern is a learned tendency of an issue on one resource to be followed by
+// an issue on another resource of the same host. Host and Service are both empty for
+// fleet-wide patterns; at most one of them is set.
+type PropagationPattern struct {
+	Host         string
+	Service      string
+	From         ResourceType
+	To           ResourceType
+	Probability  float64       // Share of From issues followed by a To issue within Window
+	Window       time.Duration // 90th percentile delay between the two issues
+	Observations int           // Number of From issues the probability is based on
+	LearnedAt    time.Time
+}
+
+// Scope describes where the pattern applies: "db-01", "service checkout" or "fleet-wide"
+func (p PropagationPattern) Scope() string {
+	switch {
+	case p.Host != "":
+		return p.Host
+	case p.Service != "":
+		return "service " + p.Service
+	default:
+		return "fleet-wide"
+	}
+}
+
+// String describes the pattern, e.g. "on db-01, memory→disk with 92% likelihood within 4m0s"
+func (p PropagationPattern) String() string {
+	return fmt.Sprintf("on %s, %s→%s with %.0f%% likelihood within %s",
+		p.Scope(), strings.ToLower(string(p.From)), strings.ToLower(string(p.To)),
+		p.Probability*100, p.Window)
 }
 
 // TimelineEntry is a human-readable representation of an event in the timeline
@@ -66,6 +646,368 @@ type TimelineEntry struct {
 	ResourceType       ResourceType   // Resource affected
 }
 
+// Escalation records that an unacknowledged incident was escalated to a level of the
+// escalation policy of its priority
+type Escalation struct {
+	IncidentID  string
+	Level       int      // 1-based level of the policy
+	Priority    Priority // Priority whose policy was applied
+	Target      string   // Who was paged, e.g. "Bob (secondary on-call), slack"
+	EscalatedAt time.Time
+}
+
+// Ticket is an issue filed in an external tracker (Jira, GitHub) for an incident
+type Ticket struct {
+	IncidentID string
+	Tracker    string // e.g., "jira", "github"
+	Key        string // Tracker-specific issue key, e.g. "OPS-123" or "42"
+	URL        string
+	CreatedAt  time.Time
+	ResolvedAt *time.Time // Set once the ticket was closed because the incident resolved
+}
+
+// ProblemLink records an incident as an occurrence of a recurring problem. A problem is
+// identified by the ID of its first incident, which is linked to itself.
+type ProblemLink struct {
+	IncidentID string
+	ProblemID  string
+	Similarity float64 // Similarity to the resolved incident it matched, 0-1
+	LinkedAt   time.Time
+}
+
+// RootCauseRecord is a stored root cause prediction of one model version for an incident,
+// with the responder's feedback on it once given
+type RootCauseRecord struct {
+	IncidentID   string
+	ModelVersion string
+	AlertID      string  // Predicted root cause alert
+	RawScore     float64 // Model score before calibration
+	Confidence   float64 // Calibrated confidence that was reported
+	PredictedAt  time.Time
+	Correct      *bool // Nil until feedback was given
+	FeedbackAt   *time.Time
+}
+
+// PriorityChange is a manual change of an incident's priority, kept as an audit trail
+type PriorityChange struct {
+	IncidentID string
+	Priority   Priority // New manual priority; empty when reset to follow the risk level
+	Previous   Priority // Priority in effect before the change
+	ChangedBy  string
+	Reason     string
+	ChangedAt  time.Time
+}
+
+// IncidentMetadata is the severity, tags and custom fields an organization attaches to an
+// incident
+type IncidentMetadata struct {
+	IncidentID   string
+	Severity     string
+	Tags         []string
+	CustomFields map[string]string
+	UpdatedBy    string
+	UpdatedAt    time.Time
+}
+
+// IsEmpty reports whether no metadata is set
+func (m IncidentMetadata) IsEmpty() bool {
+	return m.Severity == "" && len(m.Tags) == 0 && len(m.CustomFields) == 0
+}
+
+// Metadata returns the severity, tags and custom fields of the incident
+func (i Incident) Metadata() IncidentMetadata {
+	return IncidentMetadata{IncidentID: i.ID, Severity: i.Severity, Tags: i.Tags, CustomFields: i.CustomFields}
+}
+
+// SetMetadata sets the severity, tags and custom fields of the incident
+func (i *Incident) SetMetadata(m IncidentMetadata) {
+	i.Severity = m.Severity
+	i.Tags = m.Tags
+	i.CustomFields = m.CustomFields
+}
+
+// RootCauseOverride is the root cause alert a responder pinned on an incident when the
+// analysis picked the wrong one
+type RootCauseOverride struct {
+	IncidentID  string
+	AlertID     string
+	Explanation string
+	SetBy       string
+	SetAt       time.Time
+}
+
+// PinnedRootCause returns the event a responder pinned as the root cause, if any
+func (i Incident) PinnedRootCause() (Alert, bool) {
+	if i.RootCauseOverride == nil {
+		return Alert{}, false
+	}
+	for _, event := range i.Events {
+		if event.ID == i.RootCauseOverride.AlertID {
+			return event, true
+		}
+	}
+	return Alert{}, false
+}
+
+// IncidentState is a stage of the response to an incident. Unlike Status, which follows
+// its alerts, it is moved through by responders.
+type IncidentState string
+
+const (
+	StateDetected   IncidentState = "detected"
+	StateTriaged    IncidentState = "triaged"
+	StateMitigating IncidentState = "mitigating"
+	StateMonitoring IncidentState = "monitoring"
+	StateResolved   IncidentState = "resolved"
+	StatePostmortem IncidentState = "postmortem"
+)
+
+// IncidentStates lists the lifecycle states in order
+var IncidentStates = []IncidentState{
+	StateDetected, StateTriaged, StateMitigating, StateMonitoring, StateResolved, StatePostmortem,
+}
+
+// stateTransitions lists the states each state may move to. Steps may be skipped going
+// forward; a mitigation that didn't hold, or a resolved incident that came back, goes
+// back to mitigating.
+var stateTransitions = map[IncidentState][]IncidentState{
+	StateDetected:   {StateTriaged, StateMitigating, StateMonitoring, StateResolved},
+	StateTriaged:    {StateMitigating, StateMonitoring, StateResolved},
+	StateMitigating: {StateMonitoring, StateResolved},
+	StateMonitoring: {StateMitigating, StateResolved},
+	StateResolved:   {StateMitigating, StatePostmortem},
+	StatePostmortem: {},
+}
+
+// ErrStateConflict is returned by state stores when an incident is no longer in the
+// state a transition moves it from, because another transition got there first
+var ErrStateConflict = errors.New("incident state changed concurrently")
+
+// ParseIncidentState validates a state name such as "triaged"
+func ParseIncidentState(s string) (IncidentState, error) {
+	state := IncidentState(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := stateTransitions[state]; !ok {
+		return "", fmt.Errorf("invalid state %q: must be detected, triaged, mitigating, monitoring, resolved or postmortem", s)
+	}
+	return state, nil
+}
+
+// Transitions returns the states an incident in this state may move to
+func (s IncidentState) Transitions() []IncidentState {
+	return stateTransitions[s]
+}
+
+// CanTransitionTo reports whether an incident in this state may move to the given one
+func (s IncidentState) CanTransitionTo(to IncidentState) bool {
+	for _, next := range stateTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentState returns the state responders moved the incident to. Before the first
+// transition it is detected, or resolved once the incident's alerts cleared.
+func (i Incident) CurrentState() IncidentState {
+	switch {
+	case i.State != "":
+		return i.State
+	case i.ResolvedAt != nil:
+		return StateResolved
+	default:
+		return StateDetected
+	}
+}
+
+// StateFrequencies lists the count of every lifecycle state in lifecycle order, including
+// states without incidents
+func StateFrequencies(counts map[IncidentState]int) []FrequencyCount {
+	result := make([]FrequencyCount, 0, len(IncidentStates))
+	for _, state := range IncidentStates {
+		result = append(result, FrequencyCount{Key: string(state), Incidents: counts[state]})
+	}
+	return result
+}
+
+// StateTransition records a responder moving an incident to another lifecycle state
+type StateTransition struct {
+	IncidentID string
+	From       IncidentState
+	To         IncidentState
+	ChangedBy  string
+	Note       string
+	ChangedAt  time.Time
+}
+
+// ActionItemStatus is the progress of an incident follow-up
+type ActionItemStatus string
+
+const (
+	ActionItemOpen       ActionItemStatus = "open"
+	ActionItemInProgress ActionItemStatus = "in_progress"
+	ActionItemDone       ActionItemStatus = "done"
+	ActionItemCancelled  ActionItemStatus = "cancelled"
+)
+
+// ParseActionItemStatus validates a status name such as "in_progress"
+func ParseActionItemStatus(s string) (ActionItemStatus, error) {
+	status := ActionItemStatus(strings.ToLower(strings.TrimSpace(s)))
+	switch status {
+	case ActionItemOpen, ActionItemInProgress, ActionItemDone, ActionItemCancelled:
+		return status, nil
+	}
+	return "", fmt.Errorf("invalid status %q: must be open, in_progress, done or cancelled", s)
+}
+
+// IsOpen reports whether work on the item is still outstanding
+func (s ActionItemStatus) IsOpen() bool {
+	return s == ActionItemOpen || s == ActionItemInProgress
+}
+
+// ActionItem is a follow-up of an incident, e.g. agreed in its postmortem
+type ActionItem struct {
+	ID                string
+	IncidentID        string
+	Description       string
+	Owner             string
+	DueAt             *time.Time
+	Status            ActionItemStatus
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	OverdueNotifiedAt *time.Time // Set once the item was reported overdue; cleared when the due date changes
+}
+
+// Overdue reports whether the item is still open past its due date
+func (a ActionItem) Overdue(now time.Time) bool {
+	return a.Status.IsOpen() && a.DueAt != nil && now.After(*a.DueAt)
+}
+
+// HasTags reports whether the incident carries every one of the tags, ignoring case
+func (i Incident) HasTags(tags ...string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, t := range i.Tags {
+			found = found || strings.EqualFold(t, tag)
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeTags trims tags to lower case, dropping empty and duplicate ones, and sorts them
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// AuditEntry records a write made through the API: who made it, what it changed and when
+type AuditEntry struct {
+	ID         string
+	Actor      string // User named by the X-User header or the request; empty if unknown
+	APIKey     string // Fingerprint of the bearer token, never the token itself
+	RemoteAddr string
+	Method     string
+	Path       string
+	Status     int    // HTTP status of the response
+	Payload    string // Request body with secrets redacted
+	Changes    map[string]FieldChange
+	At         time.Time
+}
+
+// FieldChange is the value of a field before and after a write; nil when the field was
+// added or removed
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// AuditQuery filters the audit log. Zero fields match everything.
+type AuditQuery struct {
+	Actor      string
+	APIKey     string
+	Method     string
+	PathPrefix string
+	From       time.Time
+	To         time.Time
+	Limit      int // Newest entries first; 0 returns all
+}
+
+// Matches reports whether an entry passes the filters
+func (q AuditQuery) Matches(entry AuditEntry) bool {
+	switch {
+	case q.Actor != "" && entry.Actor != q.Actor,
+		q.APIKey != "" && entry.APIKey != q.APIKey,
+		q.Method != "" && entry.Method != q.Method,
+		q.PathPrefix != "" && !strings.HasPrefix(entry.Path, q.PathPrefix),
+		!q.From.IsZero() && entry.At.Before(q.From),
+		!q.To.IsZero() && entry.At.After(q.To):
+		return false
+	}
+	return true
+}
+
+// Lease is a named lock held by one replica until it expires, e.g. the right to poll the
+// alert sources
+type Lease struct {
+	Name       string
+	Holder     string
+	AcquiredAt time.Time // When the current holder took over the lease
+	ExpiresAt  time.Time
+}
+
+// Webhook is a user-configured HTTP callback receiving incident events
+type Webhook struct {
+	ID        string
+	URL       string
+	Secret    string   // Key of the HMAC-SHA256 delivery signature; empty sends deliveries unsigned
+	Events    []string // Event types delivered, e.g. "incident.created"; empty delivers all
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Accepts reports whether the webhook delivers events of the given type
+func (w Webhook) Accepts(eventType string) bool {
+	if !w.Enabled {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// FailedDelivery is a webhook delivery that failed every attempt, kept as a dead letter
+// until it is redelivered or discarded
+type FailedDelivery struct {
+	ID          string
+	WebhookID   string
+	EventType   string
+	ContentType string
+	Payload     string
+	Attempts    int
+	StatusCode  int // HTTP status of the last attempt; 0 if no response was received
+	Error       string
+	FailedAt    time.Time
+}
+
 // ParsedNetdataResponse represents the raw JSON structure from Netdata (for reference in adapters)
 // Placed here for model clarity, usually lives in adapters/netdata but helpful to visualize mapping.
 type NetdataAlarmLog struct {
@@ -88,6 +1030,10 @@ type NetdataAlarmLog struct {
 	Info        string  `json:"info"`
 	ValueString string  `json:"value_string"`
 	Hostname    string  `json:"hostname"` // Optional, might be in different API versions
+
+	// Labels of the alert's chart, e.g. container_name, image and k8s_pod_name on cgroup
+	// charts; optional, sent by agents that support chart labels
+	ChartLabels map[string]string `json:"chart_labels,omitempty"`
 }
 
 // NetdataAlarmLogResponse wraps the API response
@@ -0,0 +1,144 @@
+package enrichment
+
+import (
+	"context"
+	"strings"
+
+	"incident-teller/internal/domain"
+)
+
+// Labels set on alerts of container charts
+const (
+	LabelContainer      = "container"
+	LabelContainerImage = "container_image"
+	LabelPod            = "pod"
+	LabelNamespace      = "namespace"
+	// LabelWorkload names what the container belongs to: "<namespace>/<controller>" for
+	// Kubernetes pods, the container name without its replica number otherwise
+	LabelWorkload = "workload"
+)
+
+// containerChartPrefixes mark the charts of cgroups.plugin and the Docker collector that
+// belong to a single container
+var containerChartPrefixes = []string{"cgroup_", "docker_"}
+
+// ContainerIdentity labels alerts of container charts with the container, its image and,
+// on Kubernetes, its pod, namespace and workload. The identity comes from the chart labels
+// Netdata attaches to cgroup charts (container_name, image, k8s_*), else from the chart
+// name: cgroup_<name>, cgroup_k8s_<namespace>_<pod>_<container> or the Docker shim's
+// cgroup_k8s_<container>_<pod>_<namespace>_<uid>_<attempt>.
+type ContainerIdentity struct{}
+
+// Name returns "containers"
+func (ContainerIdentity) Name() string {
+	return "containers"
+}
+
+// Enrich returns the identity labels of alerts on container charts, nothing for others
+func (ContainerIdentity) Enrich(_ context.Context, alert domain.Alert) (map[string]string, error) {
+	name, ok := containerName(alert.Chart)
+	if !ok {
+		return nil, nil
+	}
+
+	labels := map[string]string{
+		LabelContainer:      alert.Labels["k8s_container_name"],
+		LabelContainerImage: alert.Labels["image"],
+		LabelPod:            alert.Labels["k8s_pod_name"],
+		LabelNamespace:      alert.Labels["k8s_namespace"],
+	}
+	if labels[LabelContainer] == "" {
+		labels[LabelContainer] = alert.Labels["container_name"]
+	}
+
+	if parts := strings.Split(name, "_"); len(parts) >= 4 && parts[0] == "k8s" {
+		namespace, pod, container := parts[1], parts[2], parts[3]
+		if len(parts) >= 6 {
+			container, pod, namespace = parts[1], parts[2], parts[3]
+		}
+		setDefault(labels, LabelNamespace, namespace)
+		setDefault(labels, LabelPod, pod)
+		setDefault(labels, LabelContainer, container)
+	} else {
+		setDefault(labels, LabelContainer, name)
+	}
+
+	workload := alert.Labels["k8s_controller_name"]
+	switch {
+	case workload == "" && labels[LabelPod] != "":
+		workload = workloadOfPod(labels[LabelPod])
+	case workload == "":
+		workload = trimReplica(labels[LabelContainer])
+	}
+	if labels[LabelNamespace] != "" {
+		workload = labels[LabelNamespace] + "/" + workload
+	}
+	labels[LabelWorkload] = workload
+	return labels, nil
+}
+
+// containerName returns the cgroup or container name of a container chart, e.g. "api" for
+// cgroup_api.mem_usage
+func containerName(chart string) (string, bool) {
+	for _, prefix := range containerChartPrefixes {
+		if !strings.HasPrefix(chart, prefix) {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(chart, prefix), ".")
+		// docker_engine.* describes the daemon, not a container
+		if name == "" || (prefix == "docker_" && name == "engine") {
+			return "", false
+		}
+		return name, true
+	}
+	return "", false
+}
+
+func setDefault(labels map[string]string, key, value string) {
+	if labels[key] == "" {
+		labels[key] = value
+	}
+}
+
+// podSuffixChars are the characters Kubernetes uses for generated name suffixes
+const podSuffixChars = "bcdfghjklmnpqrstvwxz2456789"
+
+// workloadOfPod strips the generated suffixes from a pod name: the ReplicaSet hash and pod
+// suffix of Deployment pods ("api-7d9f8b6c5-x2k4j"), the suffix of DaemonSet and Job pods
+// ("agent-x2k4j") and the ordinal of StatefulSet pods ("db-0")
+func workloadOfPod(pod string) string {
+	parts := strings.Split(pod, "-")
+	n := len(parts)
+	switch {
+	case n < 2:
+		return pod
+	case n >= 3 && generated(parts[n-1], 5, 5) && generated(parts[n-2], 6, 10):
+		return strings.Join(parts[:n-2], "-")
+	case generated(parts[n-1], 5, 5):
+		return strings.Join(parts[:n-1], "-")
+	default:
+		return trimReplica(pod)
+	}
+}
+
+// trimReplica strips a replica number, as Compose appends ("shop-web-1", "shop_web_2")
+func trimReplica(name string) string {
+	i := strings.LastIndexAny(name, "-_")
+	if i <= 0 || i == len(name)-1 || strings.Trim(name[i+1:], "0123456789") != "" {
+		return name
+	}
+	return name[:i]
+}
+
+// generated reports whether s looks like a generated name segment of the given length
+func generated(s string, minLen, maxLen int) bool {
+	if len(s) < minLen || len(s) > maxLen {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune(podSuffixChars, r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+// Package enrichment adds labels such as environment, service owner or datacenter to
+// alerts between ingestion and storage, from static mapping files, a CMDB lookup or
+// regular expressions on alert fields, and the container, pod and workload of alerts on
+// container charts. Later stages (severity rules, risk scoring,
+// notification routing, correlation) see the enriched labels.
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+)
+
+// Enricher derives labels for an alert
+type Enricher interface {
+	// Name identifies the enricher in errors
+	Name() string
+	// Enrich returns the labels to add to the alert
+	Enrich(ctx context.Context, alert domain.Alert) (map[string]string, error)
+}
+
+// Pipeline runs enrichers in order. A label is never overwritten: values set by the
+// alert source or an earlier enricher win. A nil *Pipeline leaves alerts unchanged.
+type Pipeline struct {
+	enrichers []Enricher
+}
+
+// NewPipeline creates a pipeline running the enrichers in order
+func NewPipeline(enrichers ...Enricher) *Pipeline {
+	return &Pipeline{enrichers: enrichers}
+}
+
+// FromConfig builds the pipeline for the enrichment config section: static mappings,
+// then regex extraction, then the CMDB lookup, then container identity. It returns nil if
+// no enricher is configured.
+func FromConfig(cfg config.EnrichmentConfig) (*Pipeline, error) {
+	var enrichers []Enricher
+	if cfg.MappingFile != "" {
+		static, err := LoadStaticMappings(cfg.MappingFile)
+		if err != nil {
+			return nil, err
+		}
+		enrichers = append(enrichers, static)
+	}
+	for i, rule := range cfg.Extract {
+		extractor, err := NewRegexExtractor(rule.Field, rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("enrichment extract rule #%d: %w", i+1, err)
+		}
+		enrichers = append(enrichers, extractor)
+	}
+	if cfg.CMDB.URL != "" {
+		enrichers = append(enrichers, NewHTTPLookup(cfg.CMDB.URL, cfg.CMDB.Token, cfg.CMDB.CacheTTL, cfg.CMDB.Timeout))
+	}
+	if cfg.Containers {
+		enrichers = append(enrichers, ContainerIdentity{})
+	}
+
+	if len(enrichers) == 0 {
+		return nil, nil
+	}
+	return NewPipeline(enrichers...), nil
+}
+
+// With returns a pipeline running the given enrichers after those of p, which may be nil
+func (p *Pipeline) With(enrichers ...Enricher) *Pipeline {
+	if len(enrichers) == 0 {
+		return p
+	}
+	var existing []Enricher
+	if p != nil {
+		existing = p.enrichers
+	}
+	return NewPipeline(append(append([]Enricher{}, existing...), enrichers...)...)
+}
+
+// Apply enriches a copy of every alert. Alerts are returned even when an enricher
+// fails, with the labels the other enrichers added; the failures are returned joined.
+func (p *Pipeline) Apply(ctx context.Context, alerts []domain.Alert) ([]domain.Alert, error) {
+	if p == nil {
+		return alerts, nil
+	}
+
+	var errs []error
+	failed := make(map[string]bool) // Report each failing enricher once per batch
+	result := make([]domain.Alert, len(alerts))
+	for i, alert := range alerts {
+		labels := make(map[string]string, len(alert.Labels))
+		for k, v := range alert.Labels {
+			labels[k] = v
+		}
+
+		for _, enricher := range p.enrichers {
+			added, err := enricher.Enrich(ctx, alert)
+			if err != nil {
+				if !failed[enricher.Name()] {
+					failed[enricher.Name()] = true
+					errs = append(errs, fmt.Errorf("%s: %w", enricher.Name(), err))
+				}
+				continue
+			}
+			for k, v := range added {
+				if _, exists := labels[k]; !exists && v != "" {
+					labels[k] = v
+				}
+			}
+		}
+
+		alert.Labels = labels
+		result[i] = alert
+	}
+	return result, errors.Join(errs...)
+}
@@ -0,0 +1,134 @@
+package enrichment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+)
+
+func TestFromConfig_EnrichesInOrder(t *testing.T) {
+	mappings := `
+- match: {host: "db-*"}
+  labels: {environment: production, owner: team-db}
+- match: {}
+  labels: {environment: staging, datacenter: fra1}
+`
+	mappingFile := filepath.Join(t.TempDir(), "mappings.yaml")
+	if err := os.WriteFile(mappingFile, []byte(mappings), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	lookups := 0
+	cmdb := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		if r.URL.Path != "/hosts/db-01" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"owner": "team-cmdb", "criticality": "high"}`)
+	}))
+	defer cmdb.Close()
+
+	pipeline, err := FromConfig(config.EnrichmentConfig{
+		MappingFile: mappingFile,
+		Extract:     []config.LabelExtraction{{Pattern: `^disk_space\.(?P<mount>.+)$`}},
+		CMDB:        config.CMDBConfig{URL: cmdb.URL + "/hosts/{host}", CacheTTL: time.Minute},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alerts := []domain.Alert{
+		{Host: "db-01", Chart: "disk_space._var", Labels: map[string]string{"datacenter": "ams3"}},
+		{Host: "db-01", Chart: "system.cpu"},
+		{Host: "web-01", Chart: "system.cpu"},
+	}
+	enriched, err := pipeline.Apply(context.Background(), alerts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"environment": "production", // First matching mapping wins
+		"owner":       "team-db",    // Static mappings run before the CMDB
+		"datacenter":  "ams3",       // Source labels are kept
+		"mount":       "_var",
+		"criticality": "high",
+	}
+	for k, v := range want {
+		if enriched[0].Labels[k] != v {
+			t.Errorf("label %s: expected %q, got %q", k, v, enriched[0].Labels[k])
+		}
+	}
+	if enriched[2].Labels["environment"] != "staging" || enriched[2].Labels["criticality"] != "" {
+		t.Errorf("unexpected labels for web-01: %v", enriched[2].Labels)
+	}
+	if alerts[0].Labels["owner"] != "" {
+		t.Error("expected the input alerts to be left unchanged")
+	}
+	if lookups != 2 {
+		t.Errorf("expected one CMDB lookup per host, got %d", lookups)
+	}
+}
+
+func TestNewRegexExtractor_RequiresNamedGroups(t *testing.T) {
+	if _, err := NewRegexExtractor("chart", `^disk_space\.(.+)$`); err == nil {
+		t.Error("expected a pattern without named groups to be rejected")
+	}
+	if _, err := NewRegexExtractor("value", `(?P<x>.+)`); err == nil {
+		t.Error("expected an unknown field to be rejected")
+	}
+}
+
+func TestContainerIdentity_Enrich(t *testing.T) {
+	tests := []struct {
+		alert domain.Alert
+		want  map[string]string
+	}{
+		{
+			domain.Alert{Chart: "cgroup_k8s_shop_api-7d9f8b6c5-x2k4j_api.mem_usage"},
+			map[string]string{"namespace": "shop", "pod": "api-7d9f8b6c5-x2k4j", "container": "api", "workload": "shop/api"},
+		},
+		{
+			// Docker shim naming: container, pod, namespace, uid, attempt
+			domain.Alert{Chart: "cgroup_k8s_redis_cache-0_shop_1b2c_3.cpu"},
+			map[string]string{"namespace": "shop", "pod": "cache-0", "container": "redis", "workload": "shop/cache"},
+		},
+		{
+			// Chart labels win over the chart name
+			domain.Alert{Chart: "cgroup_b3f1.cpu_limit", Labels: map[string]string{
+				"k8s_namespace": "ops", "k8s_pod_name": "agent-x2k4j", "k8s_container_name": "agent", "image": "agent:1.4",
+			}},
+			map[string]string{"namespace": "ops", "pod": "agent-x2k4j", "container": "agent", "container_image": "agent:1.4", "workload": "ops/agent"},
+		},
+		{
+			domain.Alert{Chart: "cgroup_shop-web-1.mem_usage", Labels: map[string]string{"image": "nginx:1.27"}},
+			map[string]string{"container": "shop-web-1", "container_image": "nginx:1.27", "workload": "shop-web"},
+		},
+		{domain.Alert{Chart: "docker_engine.containers"}, nil},
+		{domain.Alert{Chart: "system.cpu"}, nil},
+	}
+
+	for _, tt := range tests {
+		got, err := ContainerIdentity{}.Enrich(context.Background(), tt.alert)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range tt.want {
+			if got[k] != v {
+				t.Errorf("%s: label %s: expected %q, got %q", tt.alert.Chart, k, v, got[k])
+			}
+		}
+		if tt.want == nil && got != nil {
+			t.Errorf("%s: expected no labels, got %v", tt.alert.Chart, got)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package enrichment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// failureBackoff is how long a failed lookup is remembered, so a CMDB outage costs one
+// request per host rather than one per alert
+const failureBackoff = time.Minute
+
+// HTTPLookup fetches the labels of an alert's host from a CMDB or inventory service.
+// The URL template's {host} is replaced by the host name; the response must be a JSON
+// object of string labels. Results are cached per host.
+type HTTPLookup struct {
+	urlTemplate string
+	token       string
+	ttl         time.Duration
+	httpClient  *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedLabels
+}
+
+type cachedLabels struct {
+	labels  map[string]string
+	expires time.Time
+}
+
+// NewHTTPLookup creates a lookup against urlTemplate, e.g.
+// https://cmdb.example.com/api/hosts/{host}/labels. token is sent as a bearer token if set.
+func NewHTTPLookup(urlTemplate, token string, ttl, timeout time.Duration) *HTTPLookup {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &HTTPLookup{
+		urlTemplate: urlTemplate,
+		token:       token,
+		ttl:         ttl,
+		httpClient:  &http.Client{Timeout: timeout},
+		cache:       make(map[string]cachedLabels),
+	}
+}
+
+// Name returns "cmdb"
+func (l *HTTPLookup) Name() string {
+	return "cmdb"
+}
+
+// Enrich returns the labels of the alert's host. Unknown hosts (404) have no labels.
+func (l *HTTPLookup) Enrich(ctx context.Context, alert domain.Alert) (map[string]string, error) {
+	if alert.Host == "" {
+		return nil, nil
+	}
+
+	l.mu.Lock()
+	cached, ok := l.cache[alert.Host]
+	l.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.labels, nil
+	}
+
+	labels, err := l.fetch(ctx, alert.Host)
+	expires := time.Now().Add(l.ttl)
+	if err != nil {
+		expires = time.Now().Add(failureBackoff)
+	}
+	l.mu.Lock()
+	l.cache[alert.Host] = cachedLabels{labels: labels, expires: expires}
+	l.mu.Unlock()
+	return labels, err
+}
+
+func (l *HTTPLookup) fetch(ctx context.Context, host string) (map[string]string, error) {
+	target := strings.ReplaceAll(l.urlTemplate, "{host}", url.PathEscape(host))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if l.token != "" {
+		req.Header.Set("Authorization", "Bearer "+l.token)
+	}
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("lookup of %s failed: %w", host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("lookup of %s: unexpected status code %d: %s", host, resp.StatusCode, string(body))
+	}
+
+	var labels map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&labels); err != nil {
+		return nil, fmt.Errorf("lookup of %s: invalid labels: %w", host, err)
+	}
+	return labels, nil
+}
@@ -0,0 +1,74 @@
+package enrichment
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"incident-teller/internal/domain"
+)
+
+// RegexExtractor turns the named groups of a regular expression matched against an
+// alert field into labels, e.g. `^disk_space\.(?P<mount>.+)$` on the chart
+type RegexExtractor struct {
+	field   string
+	pattern *regexp.Regexp
+}
+
+// NewRegexExtractor creates an extractor for field ("chart" by default, "name", "host"
+// or "family"). The pattern needs at least one named group.
+func NewRegexExtractor(field, pattern string) (*RegexExtractor, error) {
+	if field == "" {
+		field = "chart"
+	}
+	switch field {
+	case "chart", "name", "host", "family":
+	default:
+		return nil, fmt.Errorf("unknown alert field %q", field)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	named := false
+	for _, name := range re.SubexpNames() {
+		named = named || name != ""
+	}
+	if !named {
+		return nil, fmt.Errorf("pattern %q has no named groups", pattern)
+	}
+	return &RegexExtractor{field: field, pattern: re}, nil
+}
+
+// Name returns "regex"
+func (r *RegexExtractor) Name() string {
+	return "regex"
+}
+
+// Enrich returns the named groups that matched
+func (r *RegexExtractor) Enrich(_ context.Context, alert domain.Alert) (map[string]string, error) {
+	var value string
+	switch r.field {
+	case "name":
+		value = alert.Name
+	case "host":
+		value = alert.Host
+	case "family":
+		value = alert.Family
+	default:
+		value = alert.Chart
+	}
+
+	match := r.pattern.FindStringSubmatch(value)
+	if match == nil {
+		return nil, nil
+	}
+	labels := make(map[string]string)
+	for i, name := range r.pattern.SubexpNames() {
+		if name != "" && match[i] != "" {
+			labels[name] = match[i]
+		}
+	}
+	return labels, nil
+}
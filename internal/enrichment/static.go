@@ -0,0 +1,81 @@
+package enrichment
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"gopkg.in/yaml.v3"
+
+	"incident-teller/internal/domain"
+)
+
+// StaticMapping adds labels to alerts matching all of its glob patterns
+type StaticMapping struct {
+	Match struct {
+		Host   string `yaml:"host"`
+		Chart  string `yaml:"chart"`
+		Family string `yaml:"family"`
+		Name   string `yaml:"name"`
+	} `yaml:"match"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+// StaticMappings adds labels from a mapping file. Every matching mapping applies; the
+// first one to set a label wins.
+type StaticMappings struct {
+	mappings []StaticMapping
+}
+
+// LoadStaticMappings reads a YAML list of mappings, e.g.
+// [{match: {host: "db-*"}, labels: {environment: production, owner: team-db}}]
+func LoadStaticMappings(name string) (*StaticMappings, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read enrichment mapping file: %w", err)
+	}
+
+	var mappings []StaticMapping
+	if err := yaml.Unmarshal(data, &mappings); err != nil {
+		return nil, fmt.Errorf("failed to parse enrichment mapping file: %w", err)
+	}
+	for i, m := range mappings {
+		for _, pattern := range []string{m.Match.Host, m.Match.Chart, m.Match.Family, m.Match.Name} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("enrichment mapping #%d: invalid pattern %q: %w", i+1, pattern, err)
+			}
+		}
+	}
+	return &StaticMappings{mappings: mappings}, nil
+}
+
+// Name returns "static"
+func (s *StaticMappings) Name() string {
+	return "static"
+}
+
+// Enrich returns the labels of every mapping matching the alert
+func (s *StaticMappings) Enrich(_ context.Context, alert domain.Alert) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, m := range s.mappings {
+		if !globMatch(m.Match.Host, alert.Host) || !globMatch(m.Match.Chart, alert.Chart) ||
+			!globMatch(m.Match.Family, alert.Family) || !globMatch(m.Match.Name, alert.Name) {
+			continue
+		}
+		for k, v := range m.Labels {
+			if _, exists := labels[k]; !exists {
+				labels[k] = v
+			}
+		}
+	}
+	return labels, nil
+}
+
+func globMatch(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
@@ -0,0 +1,90 @@
+// Package environment assigns alerts to environments such as prod, staging and dev, from
+// the environment label set by their source or from host patterns in the config, so
+// incidents can be kept within one environment and filtered by it.
+package environment
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+)
+
+// Resolver finds the environment of alerts. A nil *Resolver assigns none.
+type Resolver struct {
+	label    string
+	fallback string
+	mappings []config.EnvironmentMapping
+}
+
+// New creates a resolver reading the environment from label, else from the first mapping
+// whose host patterns match, else fallback
+func New(label, fallback string, mappings []config.EnvironmentMapping) (*Resolver, error) {
+	for i, mapping := range mappings {
+		for _, pattern := range mapping.Hosts {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("environment mapping #%d: invalid host pattern %q: %w", i+1, pattern, err)
+			}
+		}
+	}
+	return &Resolver{label: label, fallback: fallback, mappings: mappings}, nil
+}
+
+// FromConfig builds the resolver for the environments config section. It returns nil if
+// environments are disabled.
+func FromConfig(cfg config.EnvironmentsConfig) (*Resolver, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	return New(cfg.Label, cfg.Default, cfg.Mappings)
+}
+
+// Label returns the alert label holding the environment
+func (r *Resolver) Label() string {
+	if r == nil {
+		return ""
+	}
+	return r.label
+}
+
+// Of returns the environment of an alert, empty if it has none
+func (r *Resolver) Of(alert domain.Alert) string {
+	if r == nil {
+		return ""
+	}
+	if env := alert.Labels[r.label]; env != "" {
+		return env
+	}
+	return r.OfHost(alert.Host)
+}
+
+// OfHost returns the environment the mappings assign a host to, or the default
+func (r *Resolver) OfHost(host string) string {
+	if r == nil {
+		return ""
+	}
+	for _, mapping := range r.mappings {
+		for _, pattern := range mapping.Hosts {
+			if ok, _ := path.Match(pattern, host); ok {
+				return mapping.Name
+			}
+		}
+	}
+	return r.fallback
+}
+
+// Name returns "environments"
+func (r *Resolver) Name() string {
+	return "environments"
+}
+
+// Enrich labels an alert with its environment, so it is stored and filtered by it
+func (r *Resolver) Enrich(_ context.Context, alert domain.Alert) (map[string]string, error) {
+	env := r.Of(alert)
+	if env == "" {
+		return nil, nil
+	}
+	return map[string]string{r.label: env}, nil
+}
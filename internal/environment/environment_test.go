@@ -0,0 +1,45 @@
+package environment
+
+import (
+	"testing"
+
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+)
+
+func TestResolver_Of(t *testing.T) {
+	r, err := New("env", "dev", []config.EnvironmentMapping{
+		{Name: "prod", Hosts: []string{"*.prod", "db-*"}},
+		{Name: "staging", Hosts: []string{"*.staging", "db-01"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		alert domain.Alert
+		want  string
+	}{
+		{domain.Alert{Host: "web-01.prod"}, "prod"},
+		{domain.Alert{Host: "db-01"}, "prod"}, // The first matching mapping wins
+		{domain.Alert{Host: "web-01.staging"}, "staging"},
+		{domain.Alert{Host: "web-01.prod", Labels: map[string]string{"env": "staging"}}, "staging"},
+		{domain.Alert{Host: "laptop"}, "dev"},
+	}
+	for _, tt := range tests {
+		if got := r.Of(tt.alert); got != tt.want {
+			t.Errorf("Of(%s, %v) = %q, want %q", tt.alert.Host, tt.alert.Labels, got, tt.want)
+		}
+	}
+
+	var disabled *Resolver
+	if got := disabled.Of(domain.Alert{Host: "web-01.prod"}); got != "" {
+		t.Errorf("a nil resolver should assign no environment, got %q", got)
+	}
+}
+
+func TestNew_InvalidPattern(t *testing.T) {
+	if _, err := New("env", "", []config.EnvironmentMapping{{Name: "prod", Hosts: []string{"web-["}}}); err == nil {
+		t.Fatal("expected an error for an invalid host pattern")
+	}
+}
@@ -0,0 +1,97 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Message is an encoded event ready to publish
+type Message struct {
+	Value   []byte
+	Headers map[string]string
+}
+
+// Encoder turns events into messages
+type Encoder interface {
+	Encode(event Event) (Message, error)
+}
+
+// NewEncoder returns the encoder for format, "json" or "cloudevents". source is the
+// CloudEvents source attribute.
+func NewEncoder(format, source string) (Encoder, error) {
+	switch format {
+	case "", "json":
+		return JSONEncoder{}, nil
+	case "cloudevents":
+		if source == "" {
+			source = "incident-teller"
+		}
+		return CloudEventsEncoder{Source: source}, nil
+	default:
+		return nil, fmt.Errorf("unknown exporter encoding %q", format)
+	}
+}
+
+// JSONEncoder encodes events as plain JSON
+type JSONEncoder struct{}
+
+// Encode implements Encoder
+func (JSONEncoder) Encode(event Event) (Message, error) {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Value: value,
+		Headers: map[string]string{
+			"content-type": "application/json",
+			"event-type":   string(event.Type),
+		},
+	}, nil
+}
+
+// CloudEventsEncoder encodes events as CloudEvents 1.0 in structured mode
+type CloudEventsEncoder struct {
+	Source string
+}
+
+type cloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            any       `json:"data"`
+}
+
+// Encode implements Encoder
+func (e CloudEventsEncoder) Encode(event Event) (Message, error) {
+	var data any = event.Incident
+	if event.Type == AnalysisCompleted {
+		data = event.Analysis
+	}
+
+	value, err := json.Marshal(cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              event.ID,
+		Source:          e.Source,
+		Type:            "io.incidentteller." + string(event.Type),
+		Subject:         event.IncidentID,
+		Time:            event.Time,
+		DataContentType: "application/json",
+		Data:            data,
+	})
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Value: value,
+		Headers: map[string]string{
+			"content-type": "application/cloudevents+json",
+			"event-type":   string(event.Type),
+		},
+	}, nil
+}
@@ -0,0 +1,219 @@
+// Package exporter publishes incident lifecycle and analysis events to message brokers
+// (Kafka, NATS) for downstream data platforms.
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
+)
+
+// EventType identifies what happened to an incident
+type EventType string
+
+const (
+	IncidentCreated   EventType = "incident.created"
+	IncidentUpdated   EventType = "incident.updated"
+	IncidentResolved  EventType = "incident.resolved"
+	AnalysisCompleted EventType = "analysis.completed"
+)
+
+// EventTypes lists every event type, e.g. to validate webhook event filters
+var EventTypes = []EventType{IncidentCreated, IncidentUpdated, IncidentResolved, AnalysisCompleted}
+
+// resolvedRetention is how long a resolved incident is remembered, so that saving it
+// again doesn't publish it as created
+const resolvedRetention = 24 * time.Hour
+
+// Event is an exported event. Incident is set for lifecycle events, Analysis for
+// AnalysisCompleted.
+type Event struct {
+	ID         string    `json:"id"`
+	Type       EventType `json:"type"`
+	Time       time.Time `json:"time"`
+	IncidentID string    `json:"incident_id"`
+	Incident   *Incident `json:"incident,omitempty"`
+	Analysis   any       `json:"analysis,omitempty"`
+}
+
+// Incident is the exported form of an incident
+type Incident struct {
+	ID          string            `json:"id"`
+	Title       string            `json:"title"`
+	Status      string            `json:"status"`
+	StartedAt   time.Time         `json:"started_at"`
+	ResolvedAt  *time.Time        `json:"resolved_at,omitempty"`
+	RiskLevel   string            `json:"risk_level"`
+	Hosts       []string          `json:"hosts"`
+	Labels      map[string]string `json:"labels"`
+	TotalEvents int               `json:"total_events"`
+	Alerts      []Alert           `json:"alerts"`
+}
+
+// Alert is the exported form of an alert
+type Alert struct {
+	ID           string    `json:"id"`
+	Host         string    `json:"host"`
+	Chart        string    `json:"chart"`
+	Name         string    `json:"name"`
+	Status       string    `json:"status"`
+	Value        float64   `json:"value"`
+	OccurredAt   time.Time `json:"occurred_at"`
+	ResourceType string    `json:"resource_type"`
+	Source       string    `json:"source,omitempty"`
+}
+
+// Publisher delivers encoded events to one broker
+type Publisher interface {
+	// Name identifies the broker in logs and errors
+	Name() string
+	// Publish sends a message for the event; key groups the messages of one incident
+	Publish(ctx context.Context, eventType EventType, key string, msg Message) error
+	Close() error
+}
+
+// Exporter turns saved incidents into lifecycle events and publishes them
+type Exporter struct {
+	publishers []Publisher
+	encoder    Encoder
+
+	mu   sync.Mutex
+	seen map[string]seenIncident
+}
+
+type seenIncident struct {
+	version    string
+	resolvedAt *time.Time
+}
+
+// New creates an exporter publishing events encoded by encoder to every publisher
+func New(encoder Encoder, publishers ...Publisher) *Exporter {
+	return &Exporter{
+		publishers: publishers,
+		encoder:    encoder,
+		seen:       make(map[string]seenIncident),
+	}
+}
+
+// Seed records incidents that already exist, e.g. on startup, so they are reported as
+// updated rather than created when they next change
+func (e *Exporter) Seed(incidents []domain.Incident) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, incident := range incidents {
+		e.seen[incident.ID] = seenIncident{version: version(incident), resolvedAt: incident.ResolvedAt}
+	}
+}
+
+// IncidentSaved publishes IncidentCreated the first time an incident is seen,
+// IncidentResolved when it is resolved and IncidentUpdated when it changes otherwise.
+// Saving an unchanged incident publishes nothing.
+func (e *Exporter) IncidentSaved(ctx context.Context, incident domain.Incident) error {
+	e.mu.Lock()
+	previous, known := e.seen[incident.ID]
+	current := version(incident)
+	e.seen[incident.ID] = seenIncident{version: current, resolvedAt: incident.ResolvedAt}
+	e.pruneLocked(time.Now())
+	e.mu.Unlock()
+
+	var eventType EventType
+	switch {
+	case !known:
+		eventType = IncidentCreated
+	case previous.version == current:
+		return nil
+	case incident.ResolvedAt != nil && previous.resolvedAt == nil:
+		eventType = IncidentResolved
+	default:
+		eventType = IncidentUpdated
+	}
+
+	exported := exportIncident(incident)
+	return e.publish(ctx, Event{Type: eventType, IncidentID: incident.ID, Incident: &exported})
+}
+
+// AnalysisCompleted publishes the analysis of an incident
+func (e *Exporter) AnalysisCompleted(ctx context.Context, incidentID string, analysis any) error {
+	return e.publish(ctx, Event{Type: AnalysisCompleted, IncidentID: incidentID, Analysis: analysis})
+}
+
+// Close closes every publisher
+func (e *Exporter) Close() error {
+	var errs []error
+	for _, publisher := range e.publishers {
+		if err := publisher.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", publisher.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *Exporter) publish(ctx context.Context, event Event) error {
+	event.Time = time.Now().UTC()
+	event.ID = idgen.New(event.Time)
+
+	msg, err := e.encoder.Encode(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event.Type, err)
+	}
+
+	var errs []error
+	for _, publisher := range e.publishers {
+		if err := publisher.Publish(ctx, event.Type, event.IncidentID, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", publisher.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pruneLocked forgets incidents resolved longer than resolvedRetention ago
+func (e *Exporter) pruneLocked(now time.Time) {
+	for id, incident := range e.seen {
+		if incident.resolvedAt != nil && now.Sub(*incident.resolvedAt) > resolvedRetention {
+			delete(e.seen, id)
+		}
+	}
+}
+
+// version changes whenever an incident should be exported again
+func version(incident domain.Incident) string {
+	v := fmt.Sprintf("%s/%s/%d", incident.Title, incident.Status, len(incident.Events))
+	if incident.ResolvedAt != nil {
+		v += "/" + incident.ResolvedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+func exportIncident(incident domain.Incident) Incident {
+	exported := Incident{
+		ID:          incident.ID,
+		Title:       incident.Title,
+		Status:      string(incident.Status),
+		StartedAt:   incident.StartedAt,
+		ResolvedAt:  incident.ResolvedAt,
+		RiskLevel:   incident.RiskLevel(),
+		Hosts:       incident.Hosts(),
+		Labels:      incident.Labels(),
+		TotalEvents: len(incident.Events),
+		Alerts:      make([]Alert, 0, len(incident.Events)),
+	}
+	for _, alert := range incident.Events {
+		exported.Alerts = append(exported.Alerts, Alert{
+			ID:           alert.ID,
+			Host:         alert.Host,
+			Chart:        alert.Chart,
+			Name:         alert.Name,
+			Status:       string(alert.Status),
+			Value:        alert.Value,
+			OccurredAt:   alert.OccurredAt,
+			ResourceType: string(alert.ResourceType),
+			Source:       alert.Source,
+		})
+	}
+	return exported
+}
@@ -0,0 +1,73 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+type recordingPublisher struct {
+	types    []EventType
+	messages []Message
+}
+
+func (p *recordingPublisher) Name() string { return "recording" }
+func (p *recordingPublisher) Close() error { return nil }
+
+func (p *recordingPublisher) Publish(_ context.Context, eventType EventType, _ string, msg Message) error {
+	p.types = append(p.types, eventType)
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func TestExporter_IncidentLifecycle(t *testing.T) {
+	publisher := &recordingPublisher{}
+	encoder, err := NewEncoder("cloudevents", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := New(encoder, publisher)
+	ctx := context.Background()
+
+	start := time.Now().Add(-time.Minute)
+	incident := domain.Incident{ID: "inc-1", Title: "High CPU", StartedAt: start,
+		Events: []domain.Alert{{ID: "a-1", Host: "web-01", OccurredAt: start}}}
+
+	e.IncidentSaved(ctx, incident)
+	e.IncidentSaved(ctx, incident) // unchanged, not exported
+	incident.Events = append(incident.Events, domain.Alert{ID: "a-2", Host: "web-01", OccurredAt: start.Add(time.Second)})
+	e.IncidentSaved(ctx, incident)
+	resolved := time.Now()
+	incident.ResolvedAt = &resolved
+	e.IncidentSaved(ctx, incident)
+	e.AnalysisCompleted(ctx, "inc-1", map[string]string{"summary": "CPU saturation"})
+
+	want := []EventType{IncidentCreated, IncidentUpdated, IncidentResolved, AnalysisCompleted}
+	if len(publisher.types) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, publisher.types)
+	}
+	for i := range want {
+		if publisher.types[i] != want[i] {
+			t.Errorf("event %d: expected %s, got %s", i, want[i], publisher.types[i])
+		}
+	}
+
+	var event struct {
+		SpecVersion string `json:"specversion"`
+		Type        string `json:"type"`
+		Subject     string `json:"subject"`
+		Data        struct {
+			TotalEvents int `json:"total_events"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(publisher.messages[1].Value, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.SpecVersion != "1.0" || event.Type != "io.incidentteller.incident.updated" ||
+		event.Subject != "inc-1" || event.Data.TotalEvents != 2 {
+		t.Errorf("unexpected CloudEvent: %+v", event)
+	}
+}
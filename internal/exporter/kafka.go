@@ -0,0 +1,51 @@
+package exporter
+
+import (
+	"context"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaPublisher publishes events to a Kafka topic, keyed by incident ID so that the
+// events of one incident stay ordered within a partition
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaPublisher creates a publisher writing to topic on brokers
+func NewKafkaPublisher(brokers []string, topic string) *KafkaPublisher {
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Topic:                  topic,
+			Balancer:               &kafka.Hash{},
+			BatchTimeout:           10 * time.Millisecond,
+			RequiredAcks:           kafka.RequireOne,
+			AllowAutoTopicCreation: true,
+		},
+	}
+}
+
+// Name implements Publisher
+func (p *KafkaPublisher) Name() string {
+	return "kafka"
+}
+
+// Publish implements Publisher
+func (p *KafkaPublisher) Publish(ctx context.Context, _ EventType, key string, msg Message) error {
+	headers := make([]kafka.Header, 0, len(msg.Headers))
+	for k, v := range msg.Headers {
+		headers = append(headers, kafka.Header{Key: k, Value: []byte(v)})
+	}
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(key),
+		Value:   msg.Value,
+		Headers: headers,
+	})
+}
+
+// Close flushes pending messages and closes the writer
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,56 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher publishes each event to the subject <prefix>.<event type>, e.g.
+// incident-teller.incident.created
+type NATSPublisher struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+// NewNATSPublisher connects to the NATS server at url
+func NewNATSPublisher(url, subjectPrefix string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("incident-teller"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	return &NATSPublisher{conn: conn, prefix: subjectPrefix}, nil
+}
+
+// Name implements Publisher
+func (p *NATSPublisher) Name() string {
+	return "nats"
+}
+
+// Publish implements Publisher
+func (p *NATSPublisher) Publish(ctx context.Context, eventType EventType, key string, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	natsMsg := nats.NewMsg(p.subject(eventType))
+	natsMsg.Data = msg.Value
+	for k, v := range msg.Headers {
+		natsMsg.Header.Set(k, v)
+	}
+	natsMsg.Header.Set("incident-id", key)
+	return p.conn.PublishMsg(natsMsg)
+}
+
+func (p *NATSPublisher) subject(eventType EventType) string {
+	if p.prefix == "" {
+		return string(eventType)
+	}
+	return p.prefix + "." + string(eventType)
+}
+
+// Close flushes pending messages and closes the connection
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,293 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
+	"incident-teller/internal/ports"
+)
+
+// Headers of webhook deliveries
+const (
+	WebhookEventHeader     = "X-IncidentTeller-Event"
+	WebhookDeliveryHeader  = "X-IncidentTeller-Delivery"  // Kept on redelivery, so receivers can drop duplicates
+	WebhookTimestampHeader = "X-IncidentTeller-Timestamp" // Unix seconds of the attempt, covered by the signature
+	WebhookSignatureHeader = "X-IncidentTeller-Signature-256"
+)
+
+// Receivers should reject deliveries whose timestamp is further off than this, so a
+// captured delivery can't be replayed later
+const WebhookSignatureTolerance = 5 * time.Minute
+
+// ErrWebhookNotFound is returned when redelivering to a webhook that was deleted
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// WebhookOptions configures the delivery of webhook events
+type WebhookOptions struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration // Wait before the second attempt, doubled after each failure
+	MaxBackoff     time.Duration
+	Timeout        time.Duration // Per attempt
+}
+
+// WebhookPublisher delivers events to the outgoing webhooks in a store. Deliveries run in
+// the background; failed attempts are retried with exponential backoff, and a delivery
+// failing every attempt is kept as a dead letter.
+type WebhookPublisher struct {
+	store  ports.WebhookStore
+	opts   WebhookOptions
+	client *http.Client
+
+	mu     sync.Mutex // Orders starting deliveries with Close, so none starts once it waits
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// webhookDelivery is an event on its way to one webhook
+type webhookDelivery struct {
+	id          string
+	eventType   string
+	contentType string
+	payload     []byte
+}
+
+// NewWebhookPublisher creates a publisher delivering to the webhooks in store
+func NewWebhookPublisher(store ports.WebhookStore, opts WebhookOptions) *WebhookPublisher {
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WebhookPublisher{
+		store:  store,
+		opts:   opts,
+		client: &http.Client{},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Name implements Publisher
+func (p *WebhookPublisher) Name() string {
+	return "webhooks"
+}
+
+// errPublisherClosed is returned when publishing after Close
+var errPublisherClosed = errors.New("webhook publisher is closed")
+
+// Publish starts a delivery to every enabled webhook accepting the event type, without
+// waiting for them
+func (p *WebhookPublisher) Publish(ctx context.Context, eventType EventType, _ string, msg Message) error {
+	if p.ctx.Err() != nil {
+		return errPublisherClosed
+	}
+	webhooks, err := p.store.GetWebhooks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.Accepts(string(eventType)) {
+			continue
+		}
+		if err := p.start(webhook, webhookDelivery{
+			id:          idgen.New(time.Now()),
+			eventType:   string(eventType),
+			contentType: msg.Headers["content-type"],
+			payload:     msg.Value,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Redeliver removes a dead letter and delivers it again, with the same retries. The
+// webhook's event filter and enabled flag don't apply.
+func (p *WebhookPublisher) Redeliver(ctx context.Context, delivery domain.FailedDelivery) error {
+	if p.ctx.Err() != nil {
+		return errPublisherClosed
+	}
+	webhook, err := p.store.GetWebhook(ctx, delivery.WebhookID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if webhook == nil {
+		return ErrWebhookNotFound
+	}
+	if err := p.store.DeleteFailedDelivery(ctx, delivery.ID); err != nil {
+		return err
+	}
+
+	err = p.start(*webhook, webhookDelivery{
+		id:          delivery.ID,
+		eventType:   delivery.EventType,
+		contentType: delivery.ContentType,
+		payload:     []byte(delivery.Payload),
+	})
+	if err != nil {
+		// Closed meanwhile: keep the dead letter
+		if saveErr := p.store.SaveFailedDelivery(ctx, delivery); saveErr != nil {
+			return errors.Join(err, saveErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// Close waits for the attempts in flight and aborts the retries of pending deliveries,
+// keeping them as dead letters
+func (p *WebhookPublisher) Close() error {
+	p.mu.Lock()
+	p.cancel()
+	p.mu.Unlock()
+	p.wg.Wait()
+	return nil
+}
+
+// start runs a delivery in the background, unless the publisher is closed
+func (p *WebhookPublisher) start(webhook domain.Webhook, delivery webhookDelivery) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ctx.Err() != nil {
+		return errPublisherClosed
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.deliver(webhook, delivery)
+	}()
+	return nil
+}
+
+// deliver posts a delivery until it succeeds, fails permanently or runs out of attempts,
+// then records the failure as a dead letter
+func (p *WebhookPublisher) deliver(webhook domain.Webhook, delivery webhookDelivery) {
+	var (
+		attempts int
+		status   int
+		err      error
+	)
+retry:
+	for attempts < p.opts.MaxAttempts {
+		attempts++
+		var retryable bool
+		if status, retryable, err = p.post(webhook, delivery); err == nil {
+			return
+		}
+		if !retryable || attempts == p.opts.MaxAttempts {
+			break
+		}
+		select {
+		case <-time.After(p.backoff(attempts)):
+		case <-p.ctx.Done():
+			err = fmt.Errorf("%w; retries aborted on shutdown", err)
+			break retry
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	deadLetter := domain.FailedDelivery{
+		ID:          delivery.id,
+		WebhookID:   webhook.ID,
+		EventType:   delivery.eventType,
+		ContentType: delivery.contentType,
+		Payload:     string(delivery.payload),
+		Attempts:    attempts,
+		StatusCode:  status,
+		Error:       err.Error(),
+		FailedAt:    time.Now().UTC(),
+	}
+	if err := p.store.SaveFailedDelivery(ctx, deadLetter); err != nil {
+		log.Printf("⚠️  Failed to keep failed delivery %s to webhook %s: %v", delivery.id, webhook.ID, err)
+	}
+}
+
+// post makes one delivery attempt. It returns the response status, if any, and whether a
+// failure is worth retrying: network errors, timeouts, 429 and 5xx responses are.
+func (p *WebhookPublisher) post(webhook domain.Webhook, delivery webhookDelivery) (int, bool, error) {
+	// An attempt in flight on shutdown runs to completion, bounded by the timeout
+	ctx, cancel := context.WithTimeout(context.Background(), p.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.payload))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", delivery.contentType)
+	req.Header.Set("User-Agent", "IncidentTeller-Webhooks")
+	req.Header.Set(WebhookEventHeader, delivery.eventType)
+	req.Header.Set(WebhookDeliveryHeader, delivery.id)
+	if webhook.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, Sign(webhook.Secret, timestamp, delivery.payload))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, false, nil
+	}
+	retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode == http.StatusRequestTimeout
+	return resp.StatusCode, retryable, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+}
+
+// backoff returns the wait after the given number of failed attempts
+func (p *WebhookPublisher) backoff(attempts int) time.Duration {
+	wait := p.opts.InitialBackoff
+	for i := 1; i < attempts && wait < p.opts.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if p.opts.MaxBackoff > 0 && wait > p.opts.MaxBackoff {
+		wait = p.opts.MaxBackoff
+	}
+	return wait
+}
+
+// Sign returns the signature header value of a delivery: "sha256=" followed by the hex
+// HMAC-SHA256 of "<timestamp>.<payload>" keyed with the webhook secret
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the signature and timestamp headers of a delivery received at now,
+// rejecting timestamps further off than WebhookSignatureTolerance
+func VerifySignature(secret string, header http.Header, payload []byte, now time.Time) error {
+	timestamp := header.Get(WebhookTimestampHeader)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing or invalid delivery timestamp")
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > WebhookSignatureTolerance || age < -WebhookSignatureTolerance {
+		return fmt.Errorf("delivery timestamp is outside the tolerance")
+	}
+	if !hmac.Equal([]byte(header.Get(WebhookSignatureHeader)), []byte(Sign(secret, timestamp, payload))) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
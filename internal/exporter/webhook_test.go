@@ -0,0 +1,161 @@
+package exporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"incident-teller/internal/adapters/repository"
+	"incident-teller/internal/domain"
+)
+
+func TestWebhookPublisher_RetriesAndDeadLetters(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	var signed http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/flaky":
+			signed = r.Header.Clone()
+			if requests[r.URL.Path] < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "/rejected":
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		case "/down":
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	store := repository.NewInMemoryRepository()
+	for _, webhook := range []domain.Webhook{
+		{ID: "flaky", URL: server.URL + "/flaky", Secret: "s3cret", Enabled: true},
+		{ID: "rejected", URL: server.URL + "/rejected", Enabled: true},
+		{ID: "down", URL: server.URL + "/down", Enabled: true},
+		{ID: "disabled", URL: server.URL + "/disabled"},
+		{ID: "filtered", URL: server.URL + "/filtered", Enabled: true, Events: []string{string(IncidentResolved)}},
+	} {
+		store.SaveWebhook(ctx, webhook)
+	}
+
+	publisher := NewWebhookPublisher(store, WebhookOptions{MaxAttempts: 3, InitialBackoff: time.Millisecond, Timeout: time.Second})
+	payload := []byte(`{"type":"incident.created"}`)
+	msg := Message{Value: payload, Headers: map[string]string{"content-type": "application/json"}}
+	if err := publisher.Publish(ctx, IncidentCreated, "inc-1", msg); err != nil {
+		t.Fatal(err)
+	}
+	defer publisher.Close()
+
+	// Wait for the retries to run out
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		deadLetters, _ := store.GetFailedDeliveries(ctx, "", 0)
+		mu.Lock()
+		done := len(deadLetters) == 2 && requests["/flaky"] == 3
+		mu.Unlock()
+		if done || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := map[string]int{"/flaky": 3, "/rejected": 1, "/down": 3}
+	for path, n := range want {
+		if requests[path] != n {
+			t.Errorf("expected %d requests to %s, got %d", n, path, requests[path])
+		}
+	}
+	if len(requests) != len(want) {
+		t.Errorf("unexpected deliveries %v", requests)
+	}
+	if err := VerifySignature("s3cret", signed, payload, time.Now()); err != nil {
+		t.Errorf("expected a valid signature, got %v", err)
+	}
+
+	deadLetters, _ := store.GetFailedDeliveries(ctx, "", 0)
+	if len(deadLetters) != 2 {
+		t.Fatalf("expected 2 dead letters, got %+v", deadLetters)
+	}
+	for _, d := range deadLetters {
+		switch {
+		case d.WebhookID == "rejected" && d.Attempts == 1 && d.StatusCode == http.StatusBadRequest:
+		case d.WebhookID == "down" && d.Attempts == 3 && d.StatusCode == http.StatusBadGateway:
+		default:
+			t.Errorf("unexpected dead letter %+v", d)
+		}
+		if d.Payload != string(payload) || d.EventType != string(IncidentCreated) {
+			t.Errorf("dead letter lost the event: %+v", d)
+		}
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"type":"incident.created"}`)
+	signed := func(at time.Time, body []byte) http.Header {
+		timestamp := strconv.FormatInt(at.Unix(), 10)
+		header := http.Header{}
+		header.Set(WebhookTimestampHeader, timestamp)
+		header.Set(WebhookSignatureHeader, Sign("s3cret", timestamp, body))
+		return header
+	}
+
+	if err := VerifySignature("s3cret", signed(now.Add(-time.Minute), payload), payload, now); err != nil {
+		t.Errorf("expected a recent delivery to verify, got %v", err)
+	}
+	if err := VerifySignature("s3cret", signed(now.Add(-WebhookSignatureTolerance-time.Second), payload), payload, now); err == nil {
+		t.Error("expected a replayed delivery to be rejected")
+	}
+	if err := VerifySignature("s3cret", signed(now, []byte(`{}`)), payload, now); err == nil {
+		t.Error("expected a tampered payload to be rejected")
+	}
+	header := signed(now, payload)
+	header.Set(WebhookTimestampHeader, strconv.FormatInt(now.Add(time.Second).Unix(), 10))
+	if err := VerifySignature("s3cret", header, payload, now); err == nil {
+		t.Error("expected a tampered timestamp to be rejected")
+	}
+}
+
+func TestWebhookPublisher_PublishAfterClose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	store := repository.NewInMemoryRepository()
+	store.SaveWebhook(ctx, domain.Webhook{ID: "hook", URL: server.URL, Enabled: true})
+	publisher := NewWebhookPublisher(store, WebhookOptions{MaxAttempts: 1, Timeout: time.Second})
+	msg := Message{Value: []byte(`{}`), Headers: map[string]string{"content-type": "application/json"}}
+
+	// Publishing while closing either starts a delivery Close waits for, or fails
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			publisher.Publish(ctx, IncidentCreated, "inc-1", msg)
+		}()
+	}
+	publisher.Close()
+	wg.Wait()
+
+	if err := publisher.Publish(ctx, IncidentCreated, "inc-1", msg); err == nil {
+		t.Error("expected publishing after Close to fail")
+	}
+}
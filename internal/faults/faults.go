@@ -0,0 +1,162 @@
+// Package faults injects failures into IncidentTeller's own pipeline: slow repository
+// writes, Netdata fetches timing out and failing AI predictions. Operators enable them
+// through the admin API to check that health checks, circuit breakers and queue
+// backpressure react as expected before relying on them during a real outage.
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"incident-teller/internal/ai"
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+)
+
+// ErrAIFailure is returned by AI predictions while AI failures are injected
+var ErrAIFailure = errors.New("AI prediction failed (injected fault)")
+
+// timeoutError is returned by Netdata fetches while timeouts are injected. It is a
+// net.Error, so the fetch is retried and counts towards the circuit breaker like a real
+// timeout.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout (injected fault)" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// Faults are the failures being injected
+type Faults struct {
+	RepositoryLatency time.Duration // Added to pipeline repository writes and the database health check
+	NetdataTimeout    bool          // Netdata agent fetches fail as timed out
+	AIFailure         bool          // AI predictions fail
+	ExpiresAt         time.Time     // Zero keeps the faults until cleared
+}
+
+// Active reports whether any fault is set
+func (f Faults) Active() bool {
+	return f.RepositoryLatency > 0 || f.NetdataTimeout || f.AIFailure
+}
+
+// Injector holds the faults being injected. A nil *Injector injects nothing.
+type Injector struct {
+	mu     sync.RWMutex
+	faults Faults
+	now    func() time.Time
+}
+
+// NewInjector creates an injector with no faults set
+func NewInjector() *Injector {
+	return &Injector{now: time.Now}
+}
+
+// Set replaces the injected faults. They clear themselves at ExpiresAt if set.
+func (i *Injector) Set(faults Faults) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = faults
+}
+
+// Clear stops injecting faults
+func (i *Injector) Clear() {
+	i.Set(Faults{})
+}
+
+// Current returns the faults being injected, none once they expired
+func (i *Injector) Current() Faults {
+	if i == nil {
+		return Faults{}
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if !i.faults.ExpiresAt.IsZero() && !i.now().Before(i.faults.ExpiresAt) {
+		return Faults{}
+	}
+	return i.faults
+}
+
+// Delay waits for the injected repository latency, or until ctx is done
+func (i *Injector) Delay(ctx context.Context) error {
+	latency := i.Current().RepositoryLatency
+	if latency <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NetdataError returns a timeout error while Netdata timeouts are injected
+func (i *Injector) NetdataError() error {
+	if i.Current().NetdataTimeout {
+		return timeoutError{}
+	}
+	return nil
+}
+
+// HealthCheck wraps a database health check: it takes the injected repository latency
+// longer and reports a healthy database as degraded while latency is injected
+func (i *Injector) HealthCheck(check observability.HealthCheck) observability.HealthCheck {
+	return func(ctx context.Context) observability.HealthCheckResult {
+		if err := i.Delay(ctx); err != nil {
+			return observability.HealthCheckResult{
+				Status:  "unhealthy",
+				Message: fmt.Sprintf("Database ping timed out: %v", err),
+			}
+		}
+		result := check(ctx)
+		if latency := i.Current().RepositoryLatency; latency > 0 && result.Status == "healthy" {
+			result.Status = "degraded"
+			result.Message = fmt.Sprintf("Injected repository latency of %s", latency)
+		}
+		return result
+	}
+}
+
+// AIError returns ErrAIFailure while AI failures are injected
+func (i *Injector) AIError() error {
+	if i.Current().AIFailure {
+		return ErrAIFailure
+	}
+	return nil
+}
+
+// model fails the predictions of an AI model while AI failures are injected
+type model struct {
+	ai.AIModel
+	injector *Injector
+}
+
+// WrapModel returns the AI model failing its predictions while AI failures are injected
+func WrapModel(m ai.AIModel, injector *Injector) ai.AIModel {
+	return model{AIModel: m, injector: injector}
+}
+
+func (m model) PredictRootCause(ctx context.Context, alerts []domain.Alert) (ai.RootCausePrediction, error) {
+	if err := m.injector.AIError(); err != nil {
+		return ai.RootCausePrediction{}, err
+	}
+	return m.AIModel.PredictRootCause(ctx, alerts)
+}
+
+func (m model) PredictBlastRadius(ctx context.Context, alerts []domain.Alert) (ai.BlastRadiusPrediction, error) {
+	if err := m.injector.AIError(); err != nil {
+		return ai.BlastRadiusPrediction{}, err
+	}
+	return m.AIModel.PredictBlastRadius(ctx, alerts)
+}
+
+func (m model) AnalyzePatterns(ctx context.Context, alerts []domain.Alert) (ai.PatternAnalysis, error) {
+	if err := m.injector.AIError(); err != nil {
+		return ai.PatternAnalysis{}, err
+	}
+	return m.AIModel.AnalyzePatterns(ctx, alerts)
+}
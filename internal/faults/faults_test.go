@@ -0,0 +1,62 @@
+package faults
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"incident-teller/internal/observability"
+)
+
+func TestInjector_Expires(t *testing.T) {
+	now := time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC)
+	injector := NewInjector()
+	injector.now = func() time.Time { return now }
+
+	injector.Set(Faults{NetdataTimeout: true, AIFailure: true, ExpiresAt: now.Add(time.Minute)})
+	var netErr net.Error
+	if err := injector.NetdataError(); !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("expected a timeout net.Error, got %v", err)
+	}
+	if err := injector.AIError(); !errors.Is(err, ErrAIFailure) {
+		t.Fatalf("expected ErrAIFailure, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if injector.Current().Active() || injector.NetdataError() != nil || injector.AIError() != nil {
+		t.Fatal("expected the faults to have expired")
+	}
+
+	var none *Injector
+	if none.NetdataError() != nil || none.Delay(context.Background()) != nil {
+		t.Fatal("expected a nil injector to inject nothing")
+	}
+}
+
+func TestInjector_HealthCheck(t *testing.T) {
+	injector := NewInjector()
+	check := injector.HealthCheck(func(context.Context) observability.HealthCheckResult {
+		return observability.HealthCheckResult{Status: "healthy"}
+	})
+	if result := check(context.Background()); result.Status != "healthy" {
+		t.Fatalf("expected healthy without faults, got %s", result.Status)
+	}
+
+	injector.Set(Faults{RepositoryLatency: 10 * time.Millisecond})
+	start := time.Now()
+	if result := check(context.Background()); result.Status != "degraded" {
+		t.Fatalf("expected degraded with latency injected, got %s", result.Status)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Fatalf("expected the check delayed, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	injector.Set(Faults{RepositoryLatency: time.Minute})
+	if result := check(ctx); result.Status != "unhealthy" {
+		t.Fatalf("expected unhealthy once the check is cancelled, got %s", result.Status)
+	}
+}
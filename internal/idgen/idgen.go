@@ -0,0 +1,197 @@
+// Package idgen provides pluggable, time-ordered ID generation for alerts and incidents.
+//
+// Two formats are supported: ULID (26-char Crockford base32) and UUIDv7 (RFC 9562).
+// Both sort lexicographically by creation time, so ordering by ID matches ordering by time.
+package idgen
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Generator creates time-ordered unique IDs
+type Generator interface {
+	// New returns a fresh random ID for the given time
+	New(at time.Time) string
+	// Derive returns a stable ID for the given time and seed, so that re-processing
+	// the same input (e.g. re-polling the same Netdata event) yields the same ID
+	Derive(at time.Time, seed string) string
+	// Valid reports whether id is in this generator's format
+	Valid(id string) bool
+	// Name returns the format name ("ulid" or "uuidv7")
+	Name() string
+}
+
+var (
+	mu         sync.RWMutex
+	defaultGen Generator = ULID{}
+)
+
+// NewGenerator creates a generator for the given format name
+func NewGenerator(format string) (Generator, error) {
+	switch strings.ToLower(format) {
+	case "", "ulid":
+		return ULID{}, nil
+	case "uuidv7", "uuid":
+		return UUIDv7{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported ID format: %s", format)
+	}
+}
+
+// SetDefault replaces the process-wide generator
+func SetDefault(g Generator) {
+	mu.Lock()
+	defer mu.Unlock()
+	defaultGen = g
+}
+
+// Default returns the process-wide generator
+func Default() Generator {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultGen
+}
+
+// New returns a fresh ID from the default generator
+func New(at time.Time) string {
+	return Default().New(at)
+}
+
+// Derive returns a stable ID from the default generator
+func Derive(at time.Time, seed string) string {
+	return Default().Derive(at, seed)
+}
+
+// entropy returns 10 bytes of randomness, or of seed-derived bytes when seed is non-empty
+func entropy(seed string) [10]byte {
+	var b [10]byte
+	if seed != "" {
+		sum := sha256.Sum256([]byte(seed))
+		copy(b[:], sum[:10])
+		return b
+	}
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand failing is unrecoverable for ID uniqueness
+		panic(fmt.Sprintf("idgen: failed to read random bytes: %v", err))
+	}
+	return b
+}
+
+// millis returns the 48-bit Unix millisecond timestamp of t
+func millis(t time.Time) uint64 {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return uint64(t.UnixMilli()) & 0xFFFFFFFFFFFF
+}
+
+// ULID generates Universally Unique Lexicographically Sortable Identifiers
+type ULID struct{}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// New returns a random ULID for the given time
+func (ULID) New(at time.Time) string {
+	return encodeULID(millis(at), entropy(""))
+}
+
+// Derive returns a seed-derived ULID for the given time
+func (ULID) Derive(at time.Time, seed string) string {
+	return encodeULID(millis(at), entropy(seed))
+}
+
+// Valid reports whether id is a ULID
+func (ULID) Valid(id string) bool {
+	if len(id) != 26 || id[0] > '7' {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if !strings.ContainsRune(crockford, rune(id[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// Name returns "ulid"
+func (ULID) Name() string {
+	return "ulid"
+}
+
+// encodeULID encodes a 48-bit timestamp and 80 bits of entropy as 26 base32 characters
+func encodeULID(ms uint64, rnd [10]byte) string {
+	var raw [16]byte
+	raw[0] = byte(ms >> 40)
+	raw[1] = byte(ms >> 32)
+	raw[2] = byte(ms >> 24)
+	raw[3] = byte(ms >> 16)
+	raw[4] = byte(ms >> 8)
+	raw[5] = byte(ms)
+	copy(raw[6:], rnd[:])
+
+	hi := binary.BigEndian.Uint64(raw[:8])
+	lo := binary.BigEndian.Uint64(raw[8:])
+
+	// 128 bits are encoded as 26 5-bit groups, with 2 leading zero bits
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1F]
+		lo = (lo >> 5) | (hi << 59)
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// UUIDv7 generates time-ordered UUIDs as defined in RFC 9562
+type UUIDv7 struct{}
+
+// New returns a random UUIDv7 for the given time
+func (UUIDv7) New(at time.Time) string {
+	return encodeUUIDv7(millis(at), entropy(""))
+}
+
+// Derive returns a seed-derived UUIDv7 for the given time
+func (UUIDv7) Derive(at time.Time, seed string) string {
+	return encodeUUIDv7(millis(at), entropy(seed))
+}
+
+// Valid reports whether id is a UUIDv7
+func (UUIDv7) Valid(id string) bool {
+	if len(id) != 36 || id[8] != '-' || id[13] != '-' || id[18] != '-' || id[23] != '-' {
+		return false
+	}
+	if _, err := hex.DecodeString(strings.ReplaceAll(id, "-", "")); err != nil {
+		return false
+	}
+	return id[14] == '7'
+}
+
+// Name returns "uuidv7"
+func (UUIDv7) Name() string {
+	return "uuidv7"
+}
+
+// encodeUUIDv7 lays out the timestamp, version and variant bits and formats the UUID
+func encodeUUIDv7(ms uint64, rnd [10]byte) string {
+	var raw [16]byte
+	raw[0] = byte(ms >> 40)
+	raw[1] = byte(ms >> 32)
+	raw[2] = byte(ms >> 24)
+	raw[3] = byte(ms >> 16)
+	raw[4] = byte(ms >> 8)
+	raw[5] = byte(ms)
+	copy(raw[6:], rnd[:])
+
+	raw[6] = (raw[6] & 0x0F) | 0x70 // version 7
+	raw[8] = (raw[8] & 0x3F) | 0x80 // RFC 4122 variant
+
+	h := hex.EncodeToString(raw[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
@@ -0,0 +1,60 @@
+package labels
+
+import (
+	"fmt"
+	"strings"
+
+	"incident-teller/internal/domain"
+)
+
+// GroupKey builds the key alerts are grouped by from a list of fields: host, chart, name,
+// resource_type, or labels.<name> for a label. The zero value groups every alert together.
+type GroupKey struct {
+	fields []string
+}
+
+// ParseGroupKey validates the fields of a grouping key, e.g. [labels.service, host]
+func ParseGroupKey(fields []string) (GroupKey, error) {
+	for _, field := range fields {
+		switch {
+		case field == "host", field == "chart", field == "name", field == "resource_type":
+		case strings.HasPrefix(field, "labels.") && len(field) > len("labels."):
+		default:
+			return GroupKey{}, fmt.Errorf("unsupported group_by field %q: use host, chart, name, resource_type or labels.<name>", field)
+		}
+	}
+	return GroupKey{fields: append([]string(nil), fields...)}, nil
+}
+
+// Fields returns the fields of the key
+func (k GroupKey) Fields() []string {
+	return k.fields
+}
+
+// IsZero reports whether the key has no fields
+func (k GroupKey) IsZero() bool {
+	return len(k.fields) == 0
+}
+
+// Key returns the grouping key of an alert, e.g. "labels.service=checkout,host=web-01"
+func (k GroupKey) Key(alert domain.Alert) string {
+	parts := make([]string, len(k.fields))
+	for i, field := range k.fields {
+		parts[i] = field + "=" + fieldValue(alert, field)
+	}
+	return strings.Join(parts, ",")
+}
+
+func fieldValue(alert domain.Alert, field string) string {
+	switch field {
+	case "host":
+		return alert.Host
+	case "chart":
+		return alert.Chart
+	case "name":
+		return alert.Name
+	case "resource_type":
+		return string(alert.ResourceType)
+	}
+	return alert.Labels[strings.TrimPrefix(field, "labels.")]
+}
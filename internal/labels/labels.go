@@ -0,0 +1,184 @@
+// Package labels selects alerts and incidents by their labels and builds the keys alerts
+// are grouped by.
+package labels
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MatchType is the comparison a Matcher makes
+type MatchType string
+
+const (
+	MatchEqual     MatchType = "="
+	MatchNotEqual  MatchType = "!="
+	MatchRegexp    MatchType = "=~"
+	MatchNotRegexp MatchType = "!~"
+)
+
+// Matcher compares one label with a value or, for =~ and !~, a regular expression
+// anchored at both ends. A missing label matches like an empty value.
+type Matcher struct {
+	Name  string
+	Type  MatchType
+	Value string
+	re    *regexp.Regexp
+}
+
+// NewMatcher creates a matcher, compiling the value of regexp matchers
+func NewMatcher(name string, matchType MatchType, value string) (Matcher, error) {
+	m := Matcher{Name: name, Type: matchType, Value: value}
+	if name == "" {
+		return m, fmt.Errorf("label matcher has no label name")
+	}
+	switch matchType {
+	case MatchEqual, MatchNotEqual:
+	case MatchRegexp, MatchNotRegexp:
+		re, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return m, fmt.Errorf("invalid regexp for label %s: %w", name, err)
+		}
+		m.re = re
+	default:
+		return m, fmt.Errorf("unsupported label match type: %s", matchType)
+	}
+	return m, nil
+}
+
+// Matches reports whether the labels satisfy the matcher
+func (m Matcher) Matches(labels map[string]string) bool {
+	value := labels[m.Name]
+	switch m.Type {
+	case MatchEqual:
+		return value == m.Value
+	case MatchNotEqual:
+		return value != m.Value
+	case MatchRegexp:
+		return m.re.MatchString(value)
+	case MatchNotRegexp:
+		return !m.re.MatchString(value)
+	}
+	return false
+}
+
+// String formats the matcher the way ParseMatchers reads it
+func (m Matcher) String() string {
+	return m.Name + string(m.Type) + strconv.Quote(m.Value)
+}
+
+// Matchers is a set of matchers that must all match
+type Matchers []Matcher
+
+// Matches reports whether the labels satisfy every matcher; no matchers match everything
+func (ms Matchers) Matches(labels map[string]string) bool {
+	for _, m := range ms {
+		if !m.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal returns matchers requiring exactly the given label values
+func Equal(labels map[string]string) Matchers {
+	ms := make(Matchers, 0, len(labels))
+	for name, value := range labels {
+		ms = append(ms, Matcher{Name: name, Type: MatchEqual, Value: value})
+	}
+	return ms
+}
+
+// ParseMatchers reads a comma separated list of matchers in the Prometheus style, e.g.
+// `service="checkout",env!~"dev|staging"`. Surrounding braces and unquoted values are
+// accepted.
+func ParseMatchers(s string) (Matchers, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}")
+
+	var ms Matchers
+	for _, part := range splitMatchers(s) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		m, err := parseMatcher(part)
+		if err != nil {
+			return nil, err
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+// ParseMatcherList parses several matcher lists, e.g. one per config entry, into one set
+func ParseMatcherList(list []string) (Matchers, error) {
+	var ms Matchers
+	for _, s := range list {
+		parsed, err := ParseMatchers(s)
+		if err != nil {
+			return nil, err
+		}
+		ms = append(ms, parsed...)
+	}
+	return ms, nil
+}
+
+func parseMatcher(s string) (Matcher, error) {
+	i := strings.IndexAny(s, "=!")
+	if i < 0 {
+		return Matcher{}, fmt.Errorf("invalid label matcher %q: expected =, !=, =~ or !~", s)
+	}
+	name := strings.TrimSpace(s[:i])
+	rest := s[i:]
+
+	var matchType MatchType
+	for _, t := range []MatchType{MatchRegexp, MatchNotRegexp, MatchNotEqual, MatchEqual} {
+		if strings.HasPrefix(rest, string(t)) {
+			matchType = t
+			break
+		}
+	}
+	if matchType == "" {
+		return Matcher{}, fmt.Errorf("invalid label matcher %q: expected =, !=, =~ or !~", s)
+	}
+
+	value := strings.TrimSpace(rest[len(matchType):])
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return Matcher{}, fmt.Errorf("invalid label matcher %q: bad quoting", s)
+		}
+		value = unquoted
+	}
+	m, err := NewMatcher(name, matchType, value)
+	if err != nil {
+		return Matcher{}, fmt.Errorf("invalid label matcher %q: %w", s, err)
+	}
+	return m, nil
+}
+
+// splitMatchers splits on commas outside quoted values
+func splitMatchers(s string) []string {
+	var parts []string
+	start := 0
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -0,0 +1,60 @@
+package labels
+
+import (
+	"testing"
+
+	"incident-teller/internal/domain"
+)
+
+func TestParseMatchers(t *testing.T) {
+	matchers, err := ParseMatchers(`{service="checkout", env!~"dev|staging", team=~"db.*", region!=eu, note="a,b"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matchers) != 5 || matchers[4].Value != "a,b" || matchers[3].Type != MatchNotEqual {
+		t.Fatalf("unexpected matchers: %v", matchers)
+	}
+
+	tests := []struct {
+		labels map[string]string
+		want   bool
+	}{
+		{map[string]string{"service": "checkout", "env": "prod", "team": "db-core", "note": "a,b"}, true},
+		{map[string]string{"service": "checkout", "env": "staging", "team": "db-core", "note": "a,b"}, false},
+		// Regexps are anchored
+		{map[string]string{"service": "checkout", "env": "prod", "team": "core-db", "note": "a,b"}, false},
+		{map[string]string{"service": "checkout", "env": "prod", "team": "db", "region": "eu", "note": "a,b"}, false},
+		{map[string]string{"env": "prod", "team": "db", "note": "a,b"}, false},
+	}
+	for _, tt := range tests {
+		if got := matchers.Matches(tt.labels); got != tt.want {
+			t.Errorf("Matches(%v) = %v, want %v", tt.labels, got, tt.want)
+		}
+	}
+
+	for _, invalid := range []string{`service`, `="x"`, `team=~"("`, `service="unterminated`} {
+		if _, err := ParseMatchers(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+	if ms, err := ParseMatchers(""); err != nil || !ms.Matches(nil) {
+		t.Errorf("expected no matchers to match everything, got %v, %v", ms, err)
+	}
+}
+
+func TestGroupKey(t *testing.T) {
+	key, err := ParseGroupKey([]string{"labels.service", "host"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alert := domain.Alert{Host: "web-01", Labels: map[string]string{"service": "checkout"}}
+	if got := key.Key(alert); got != "labels.service=checkout,host=web-01" {
+		t.Errorf("unexpected key %q", got)
+	}
+
+	for _, fields := range [][]string{{"service"}, {"labels."}} {
+		if _, err := ParseGroupKey(fields); err == nil {
+			t.Errorf("expected %v to be rejected", fields)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"incident-teller/internal/report"
+)
+
+// DiscordNotifier posts notifications as embeds to a Discord webhook
+type DiscordNotifier struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewDiscordNotifier creates a Discord notifier for the given webhook URL
+func NewDiscordNotifier(webhookURL string) *DiscordNotifier {
+	return &DiscordNotifier{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Name returns "discord"
+func (d *DiscordNotifier) Name() string {
+	return "discord"
+}
+
+// Send posts the notification as an embed colored by severity
+func (d *DiscordNotifier) Send(ctx context.Context, n Notification) error {
+	payload := report.Discord{Severity: n.style()}.Render(n.document())
+	return postJSON(ctx, d.httpClient, d.webhookURL, []byte(payload))
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPMailer sends HTML emails through an SMTP server. STARTTLS is used when the server
+// offers it; credentials are only sent over TLS.
+type SMTPMailer struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+// NewSMTPMailer creates a mailer sending from one address to the given recipients.
+// An empty username disables authentication.
+func NewSMTPMailer(host string, port int, username, password, from string, to []string) *SMTPMailer {
+	return &SMTPMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+		to:       to,
+	}
+}
+
+// SendHTML sends an HTML email to all recipients
+func (m *SMTPMailer) SendHTML(ctx context.Context, subject, body string) error {
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(m.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	// net/smtp has no context support; run the send so cancellation is not blocked on it
+	addr := net.JoinHostPort(m.host, strconv.Itoa(m.port))
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, m.from, m.to, []byte(msg.String()))
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,180 @@
+// Package notify delivers incident notifications to external channels (Slack, Microsoft
+// Teams, Discord)
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"incident-teller/internal/labels"
+	"incident-teller/internal/report"
+)
+
+// Notification is a channel-agnostic incident notification
+type Notification struct {
+	IncidentID  string
+	Title       string
+	Severity    string            // "critical", "warning", "info"
+	Priority    string            // Incident priority, "P1" (most urgent) to "P4"
+	Text        string            // Pre-formatted message body (Slack-flavored markdown)
+	Document    *report.Document  // Structured body for channels with rich layouts; Text is the fallback
+	ImpactScore int               // 0-100 blast-radius impact score, 0 if unknown
+	Minimal     bool              // True when analysis failed quality gates and only facts are included
+	Assignee    string            // On-call member the incident was assigned to, if any
+	Mention     string            // Chat member ID of the assignee, used to page them directly
+	Labels      map[string]string // Incident labels, including enriched ones, used for routing
+	Hosts       []string          // Affected hosts, masked wherever they appear by Redacted notifiers
+	CreatedAt   time.Time
+}
+
+// Notifier sends notifications to a single destination
+type Notifier interface {
+	// Name identifies the destination in logs and errors
+	Name() string
+	// Send delivers the notification
+	Send(ctx context.Context, n Notification) error
+}
+
+// Dispatcher fans a notification out to every registered notifier
+type Dispatcher struct {
+	mu        sync.RWMutex
+	notifiers []Notifier
+}
+
+// NewDispatcher creates a dispatcher for the given notifiers
+func NewDispatcher(notifiers ...Notifier) *Dispatcher {
+	return &Dispatcher{notifiers: notifiers}
+}
+
+// Add registers another notifier
+func (d *Dispatcher) Add(n Notifier) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.notifiers = append(d.notifiers, n)
+}
+
+// Replace swaps all registered notifiers, e.g. on configuration reload. Notifications
+// being sent finish on the old notifiers.
+func (d *Dispatcher) Replace(notifiers ...Notifier) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.notifiers = notifiers
+}
+
+// Len returns the number of registered notifiers
+func (d *Dispatcher) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.notifiers)
+}
+
+// Names returns the names of the registered notifiers
+func (d *Dispatcher) Names() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	names := make([]string, len(d.notifiers))
+	for i, notifier := range d.notifiers {
+		names[i] = notifier.Name()
+	}
+	return names
+}
+
+// Send delivers the notification to all notifiers, returning the combined errors
+func (d *Dispatcher) Send(ctx context.Context, n Notification) error {
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+
+	d.mu.RLock()
+	notifiers := d.notifiers
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, notifier := range notifiers {
+		if err := notifier.Send(ctx, n); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", notifier.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// routed delivers only notifications whose labels satisfy its matchers
+type routed struct {
+	Notifier
+	matchers labels.Matchers
+}
+
+// Routed wraps a notifier so that it only receives notifications whose labels satisfy
+// all of matchers, e.g. owner="team-db" to page a team's own channel. The priority is
+// matched as the "priority" label, e.g. priority=~"P1|P2".
+func Routed(notifier Notifier, matchers labels.Matchers) Notifier {
+	return &routed{Notifier: notifier, matchers: matchers}
+}
+
+// Send delivers the notification if its labels match
+func (r *routed) Send(ctx context.Context, n Notification) error {
+	if !r.matchers.Matches(n.routingLabels()) {
+		return nil
+	}
+	return r.Notifier.Send(ctx, n)
+}
+
+// routingLabels returns the labels routes match: the incident labels and the priority
+func (n Notification) routingLabels() map[string]string {
+	if n.Priority == "" {
+		return n.Labels
+	}
+	result := make(map[string]string, len(n.Labels)+1)
+	for k, v := range n.Labels {
+		result[k] = v
+	}
+	result["priority"] = n.Priority
+	return result
+}
+
+// style returns how the notification's severity is presented: by impact score when
+// known, otherwise by severity name
+func (n Notification) style() report.Severity {
+	if n.ImpactScore > 0 {
+		return report.SeverityForScore(n.ImpactScore)
+	}
+	switch n.Severity {
+	case "critical":
+		return report.SeverityForScore(80)
+	case "warning":
+		return report.SeverityForScore(40)
+	default:
+		return report.SeverityForScore(0)
+	}
+}
+
+// document returns the structured body, or one built from the title and text. The
+// assignee is added as a field since structured bodies do not include it.
+func (n Notification) document() report.Document {
+	if n.Document == nil {
+		return report.Document{
+			Title:    n.Title,
+			Sections: []report.Section{{Blocks: []report.Block{report.Paragraph{Text: slackToMarkdown(n.Text)}}}},
+		}
+	}
+
+	doc := *n.Document
+	if n.Assignee != "" {
+		doc.Sections = append(append([]report.Section(nil), doc.Sections...), report.Section{
+			Blocks: []report.Block{report.Fields{{Label: "Assigned to", Value: n.Assignee + " (on call)"}}},
+		})
+	}
+	return doc
+}
+
+var slackBold = regexp.MustCompile(`\*([^*\n]+)\*`)
+
+// slackToMarkdown converts Slack mrkdwn bold (*text*) into Markdown bold (**text**)
+func slackToMarkdown(text string) string {
+	return slackBold.ReplaceAllString(text, "**$1**")
+}
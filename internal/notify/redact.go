@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"context"
+
+	"incident-teller/internal/redact"
+	"incident-teller/internal/report"
+)
+
+// redacted masks hosts, IP addresses and sensitive labels before notifications leave
+type redacted struct {
+	Notifier
+	redactor *redact.Redactor
+}
+
+// Redacted wraps a notifier so that it receives notifications with hostnames, IP addresses
+// and denylisted label values replaced by the redactor's tokens. Wrap it in Routed, not the
+// other way round, so routes still match the real labels. A nil redactor returns the
+// notifier as is.
+func Redacted(notifier Notifier, redactor *redact.Redactor) Notifier {
+	if redactor == nil {
+		return notifier
+	}
+	return &redacted{Notifier: notifier, redactor: redactor}
+}
+
+// Send delivers the redacted notification
+func (r *redacted) Send(ctx context.Context, n Notification) error {
+	return r.Notifier.Send(ctx, r.redact(n))
+}
+
+func (r *redacted) redact(n Notification) Notification {
+	// Register the hosts first, so the text below masks every one of them
+	r.redactor.Host(n.Labels["host"])
+	hosts := make([]string, len(n.Hosts))
+	for i, host := range n.Hosts {
+		hosts[i] = r.redactor.Host(host)
+	}
+	n.Hosts = hosts
+	n.Labels = r.redactor.Labels(n.Labels)
+	n.Title = r.redactor.Text(n.Title)
+	n.Text = r.redactor.Text(n.Text)
+
+	if n.Document != nil {
+		doc := report.Document{
+			Title:    r.redactor.Text(n.Document.Title),
+			Footer:   r.redactor.Text(n.Document.Footer),
+			Sections: make([]report.Section, len(n.Document.Sections)),
+		}
+		for i, section := range n.Document.Sections {
+			section.Heading = r.redactor.Text(section.Heading)
+			blocks := make([]report.Block, len(section.Blocks))
+			for j, block := range section.Blocks {
+				blocks[j] = r.redactBlock(block)
+			}
+			section.Blocks = blocks
+			doc.Sections[i] = section
+		}
+		n.Document = &doc
+	}
+	return n
+}
+
+func (r *redacted) redactBlock(block report.Block) report.Block {
+	switch b := block.(type) {
+	case report.Paragraph:
+		return report.Paragraph{Text: r.redactor.Text(b.Text)}
+	case report.Fields:
+		fields := make(report.Fields, len(b))
+		for i, field := range b {
+			fields[i] = report.Field{Label: field.Label, Value: r.redactor.Text(field.Value)}
+		}
+		return fields
+	case report.List:
+		items := make([]string, len(b.Items))
+		for i, item := range b.Items {
+			items[i] = r.redactor.Text(item)
+		}
+		b.Title = r.redactor.Text(b.Title)
+		b.Items = items
+		return b
+	default:
+		return block
+	}
+}
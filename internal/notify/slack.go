@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SlackNotifier posts notifications to a Slack incoming webhook
+type SlackNotifier struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewSlackNotifier creates a Slack notifier for the given incoming webhook URL
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Name returns "slack"
+func (s *SlackNotifier) Name() string {
+	return "slack"
+}
+
+// Send posts the notification text to the webhook, mentioning the assignee if known
+func (s *SlackNotifier) Send(ctx context.Context, n Notification) error {
+	text := n.Text
+	if n.Mention != "" {
+		text = fmt.Sprintf("<@%s> %s", n.Mention, text)
+	}
+
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Slack payload: %w", err)
+	}
+
+	return postJSON(ctx, s.httpClient, s.webhookURL, payload)
+}
+
+// postJSON posts a JSON payload and treats any non-2xx status as an error
+func postJSON(ctx context.Context, client *http.Client, url string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"incident-teller/internal/report"
+)
+
+// TeamsNotifier posts notifications as Adaptive Cards to a Microsoft Teams incoming webhook
+type TeamsNotifier struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewTeamsNotifier creates a Teams notifier for the given incoming webhook URL
+func NewTeamsNotifier(webhookURL string) *TeamsNotifier {
+	return &TeamsNotifier{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Name returns "teams"
+func (t *TeamsNotifier) Name() string {
+	return "teams"
+}
+
+// Send posts the notification as an Adaptive Card colored by severity
+func (t *TeamsNotifier) Send(ctx context.Context, n Notification) error {
+	payload := report.Teams{Severity: n.style()}.Render(n.document())
+	return postJSON(ctx, t.httpClient, t.webhookURL, []byte(payload))
+}
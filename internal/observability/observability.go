@@ -7,6 +7,9 @@ import (
 	"net/http"
 	"os"
 	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"incident-teller/internal/config"
@@ -21,7 +24,25 @@ type Logger interface {
 	Fatal(msg string, fields ...Field)
 	With(fields ...Field) Logger
 	WithContext(ctx context.Context) Logger
-	GetLogs() []string
+	GetLogs(filter LogFilter) []LogEntry
+}
+
+// LogEntry is a buffered log record. Seq numbers increase by one per record, so the
+// last seen Seq is a cursor for reading newer records.
+type LogEntry struct {
+	Seq     uint64
+	Time    time.Time
+	Level   LogLevel
+	Message string
+	Fields  map[string]string
+	Line    string // As written to the output
+}
+
+// LogFilter selects buffered log records
+type LogFilter struct {
+	MinLevel LogLevel
+	Since    uint64 // Only records after this Seq, oldest first; 0 returns the newest
+	Limit    int    // At most this many records; 0 for all
 }
 
 // Field represents a key-value pair for structured logging
@@ -32,10 +53,16 @@ type Field struct {
 
 // StandardLogger provides basic structured logging
 type StandardLogger struct {
-	level   LogLevel
-	fields  []Field
-	buffer  []string
-	maxSize int
+	level  *atomic.Int32 // Shared with loggers derived by With, so SetLevel applies to all
+	fields []Field
+	buffer *logBuffer // Shared with loggers derived by With
+}
+
+// logBuffer keeps the most recent log records in a ring indexed by sequence number
+type logBuffer struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	last    uint64 // Seq of the newest record
 }
 
 // LogLevel represents logging level
@@ -51,63 +78,130 @@ const (
 
 // NewLogger creates a new logger instance
 func NewLogger(cfg config.ObservabilityConfig) Logger {
-	var level LogLevel
-	switch cfg.LogLevel {
+	level := new(atomic.Int32)
+	level.Store(int32(ParseLogLevel(cfg.LogLevel)))
+
+	size := cfg.LogBufferSize
+	if size <= 0 {
+		size = 1000
+	}
+	return &StandardLogger{
+		level:  level,
+		buffer: &logBuffer{entries: make([]LogEntry, size)},
+	}
+}
+
+// ParseLogLevel converts a configured level name, defaulting to info
+func ParseLogLevel(name string) LogLevel {
+	switch name {
 	case "debug":
-		level = DebugLevel
-	case "info":
-		level = InfoLevel
+		return DebugLevel
 	case "warn":
-		level = WarnLevel
+		return WarnLevel
 	case "error":
-		level = ErrorLevel
+		return ErrorLevel
 	default:
-		level = InfoLevel
+		return InfoLevel
 	}
+}
 
-	return &StandardLogger{
-		level:   level,
-		buffer:  make([]string, 0),
-		maxSize: 100, // Keep last 100 logs
+// String returns the level name, e.g. INFO
+func (l LogLevel) String() string {
+	switch l {
+	case DebugLevel:
+		return "DEBUG"
+	case InfoLevel:
+		return "INFO"
+	case WarnLevel:
+		return "WARN"
+	case ErrorLevel:
+		return "ERROR"
+	default:
+		return "FATAL"
 	}
 }
 
-// GetLogs returns the buffered logs
-func (l *StandardLogger) GetLogs() []string {
-	return l.buffer
+// SetLevel changes the minimum level logged, e.g. on configuration reload
+func (l *StandardLogger) SetLevel(level LogLevel) {
+	l.level.Store(int32(level))
+}
+
+func (l *StandardLogger) enabled(level LogLevel) bool {
+	return LogLevel(l.level.Load()) <= level
+}
+
+// GetLogs returns the buffered logs matching the filter, oldest first
+func (l *StandardLogger) GetLogs(filter LogFilter) []LogEntry {
+	return l.buffer.query(filter)
+}
+
+func (b *logBuffer) add(entry LogEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.last++
+	entry.Seq = b.last
+	b.entries[(b.last-1)%uint64(len(b.entries))] = entry
+}
+
+func (b *logBuffer) query(filter LogFilter) []LogEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	size := uint64(len(b.entries))
+	from := uint64(1)
+	if b.last > size {
+		from = b.last - size + 1
+	}
+	if filter.Since >= from {
+		from = filter.Since + 1
+	}
+
+	result := []LogEntry{}
+	for seq := from; seq <= b.last; seq++ {
+		if entry := b.entries[(seq-1)%size]; entry.Level >= filter.MinLevel {
+			result = append(result, entry)
+		}
+	}
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		if filter.Since > 0 {
+			return result[:filter.Limit]
+		}
+		return result[len(result)-filter.Limit:]
+	}
+	return result
 }
 
 // Debug logs debug messages
 func (l *StandardLogger) Debug(msg string, fields ...Field) {
-	if l.level <= DebugLevel {
-		l.log("DEBUG", msg, fields...)
+	if l.enabled(DebugLevel) {
+		l.log(DebugLevel, msg, fields...)
 	}
 }
 
 // Info logs info messages
 func (l *StandardLogger) Info(msg string, fields ...Field) {
-	if l.level <= InfoLevel {
-		l.log("INFO", msg, fields...)
+	if l.enabled(InfoLevel) {
+		l.log(InfoLevel, msg, fields...)
 	}
 }
 
 // Warn logs warning messages
 func (l *StandardLogger) Warn(msg string, fields ...Field) {
-	if l.level <= WarnLevel {
-		l.log("WARN", msg, fields...)
+	if l.enabled(WarnLevel) {
+		l.log(WarnLevel, msg, fields...)
 	}
 }
 
 // Error logs error messages
 func (l *StandardLogger) Error(msg string, fields ...Field) {
-	if l.level <= ErrorLevel {
-		l.log("ERROR", msg, fields...)
+	if l.enabled(ErrorLevel) {
+		l.log(ErrorLevel, msg, fields...)
 	}
 }
 
 // Fatal logs fatal messages and exits
 func (l *StandardLogger) Fatal(msg string, fields ...Field) {
-	l.log("FATAL", msg, fields...)
+	l.log(FatalLevel, msg, fields...)
 	os.Exit(1)
 }
 
@@ -120,6 +214,7 @@ func (l *StandardLogger) With(fields ...Field) Logger {
 	return &StandardLogger{
 		level:  l.level,
 		fields: newFields,
+		buffer: l.buffer,
 	}
 }
 
@@ -138,27 +233,30 @@ func (l *StandardLogger) WithContext(ctx context.Context) Logger {
 }
 
 // log performs the actual logging
-func (l *StandardLogger) log(level, msg string, fields ...Field) {
-	timestamp := time.Now().UTC().Format(time.RFC3339)
+func (l *StandardLogger) log(level LogLevel, msg string, fields ...Field) {
+	now := time.Now().UTC()
+	entryFields := make(map[string]string, len(l.fields)+len(fields))
 
 	// Build log message
-	logMsg := fmt.Sprintf("[%s] %s", timestamp, level)
+	logMsg := fmt.Sprintf("[%s] %s", now.Format(time.RFC3339), level)
 
 	// Add base fields
 	for _, field := range l.fields {
 		logMsg += fmt.Sprintf(" %s=%v", field.Key, field.Value)
+		entryFields[field.Key] = fmt.Sprint(field.Value)
 	}
 
 	// Add message fields
 	for _, field := range fields {
 		logMsg += fmt.Sprintf(" %s=%v", field.Key, field.Value)
+		entryFields[field.Key] = fmt.Sprint(field.Value)
 	}
 
 	// Add message
 	logMsg += fmt.Sprintf(" msg=\"%s\"", msg)
 
 	// Add caller information for debug logs
-	if level == "DEBUG" {
+	if level == DebugLevel {
 		_, file, line, ok := runtime.Caller(2)
 		if ok {
 			logMsg += fmt.Sprintf(" caller=\"%s:%d\"", file, line)
@@ -166,10 +264,7 @@ func (l *StandardLogger) log(level, msg string, fields ...Field) {
 	}
 
 	// Add to buffer
-	l.buffer = append(l.buffer, logMsg)
-	if len(l.buffer) > l.maxSize {
-		l.buffer = l.buffer[1:]
-	}
+	l.buffer.add(LogEntry{Time: now, Level: level, Message: msg, Fields: entryFields, Line: logMsg})
 
 	log.Println(logMsg)
 }
@@ -182,8 +277,9 @@ type Metrics interface {
 	RecordDuration(name string, duration time.Duration, labels map[string]string)
 }
 
-// StandardMetrics provides basic in-memory metrics
+// StandardMetrics provides basic in-memory metrics. It is safe for concurrent use.
 type StandardMetrics struct {
+	mu       sync.Mutex
 	counters map[string]float64
 	gauges   map[string]float64
 }
@@ -203,23 +299,29 @@ func NewMetrics(cfg config.ObservabilityConfig) Metrics {
 // IncCounter increments a counter metric
 func (m *StandardMetrics) IncCounter(name string, labels map[string]string) {
 	key := m.buildKey(name, labels)
+	m.mu.Lock()
 	m.counters[key]++
+	m.mu.Unlock()
 }
 
 // SetGauge sets a gauge metric
 func (m *StandardMetrics) SetGauge(name string, value float64, labels map[string]string) {
 	key := m.buildKey(name, labels)
+	m.mu.Lock()
 	m.gauges[key] = value
+	m.mu.Unlock()
 }
 
 // RecordHistogram records a histogram value
 func (m *StandardMetrics) RecordHistogram(name string, value float64, labels map[string]string) {
 	// For simple implementation, convert to counter
 	key := m.buildKey(name+"_sum", labels)
-	m.counters[key] += value
-
 	countKey := m.buildKey(name+"_count", labels)
+
+	m.mu.Lock()
+	m.counters[key] += value
 	m.counters[countKey]++
+	m.mu.Unlock()
 }
 
 // RecordDuration records a duration as histogram
@@ -233,9 +335,16 @@ func (m *StandardMetrics) buildKey(name string, labels map[string]string) string
 		return name
 	}
 
+	// Sorted, so the same labels always make the same key
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
 	key := name + "{"
-	for k, v := range labels {
-		key += fmt.Sprintf("%s=\"%s\",", k, v)
+	for _, k := range names {
+		key += fmt.Sprintf("%s=\"%s\",", k, labels[k])
 	}
 	key = key[:len(key)-1] + "}"
 	return key
@@ -243,6 +352,9 @@ func (m *StandardMetrics) buildKey(name string, labels map[string]string) string
 
 // GetCounters returns all counters (for testing/debugging)
 func (m *StandardMetrics) GetCounters() map[string]float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	result := make(map[string]float64)
 	for k, v := range m.counters {
 		result[k] = v
@@ -252,6 +364,9 @@ func (m *StandardMetrics) GetCounters() map[string]float64 {
 
 // GetGauges returns all gauges (for testing/debugging)
 func (m *StandardMetrics) GetGauges() map[string]float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	result := make(map[string]float64)
 	for k, v := range m.gauges {
 		result[k] = v
@@ -277,7 +392,12 @@ func (m *NoOpMetrics) RecordDuration(name string, duration time.Duration, labels
 // HealthChecker provides health check functionality
 type HealthChecker interface {
 	CheckHealth(ctx context.Context) HealthStatus
+	// CheckReadiness runs only the checks registered with RegisterReadinessCheck
+	CheckReadiness(ctx context.Context) HealthStatus
 	RegisterCheck(name string, check HealthCheck)
+	// RegisterReadinessCheck registers a check that must not fail for the service to be
+	// ready to serve traffic; it is part of the health checks too
+	RegisterReadinessCheck(name string, check HealthCheck)
 }
 
 // HealthCheck represents a health check function
@@ -287,6 +407,7 @@ type HealthCheck func(ctx context.Context) HealthCheckResult
 type HealthCheckResult struct {
 	Status    string                 `json:"status"`
 	Message   string                 `json:"message,omitempty"`
+	Critical  bool                   `json:"critical"` // Failing makes the service unhealthy, not just degraded
 	Duration  time.Duration          `json:"duration"`
 	Details   map[string]interface{} `json:"details,omitempty"`
 	Timestamp time.Time              `json:"timestamp"`
@@ -301,40 +422,94 @@ type HealthStatus struct {
 	Version   string                       `json:"version"`
 }
 
+// registeredCheck is a health check with how it counts
+type registeredCheck struct {
+	check     HealthCheck
+	readiness bool
+}
+
 // StandardHealthChecker provides health check implementation
 type StandardHealthChecker struct {
-	checks  map[string]HealthCheck
-	version string
+	mu       sync.RWMutex
+	checks   map[string]registeredCheck
+	version  string
+	timeout  time.Duration            // Default time a check may take
+	timeouts map[string]time.Duration // Per check
+	critical map[string]bool          // Checks whose failure makes the service unhealthy; nil means all
 }
 
-// NewHealthChecker creates a new health checker
+// NewHealthChecker creates a new health checker. Every check is critical and may take 5s.
 func NewHealthChecker(version string) HealthChecker {
 	return &StandardHealthChecker{
-		checks:  make(map[string]HealthCheck),
+		checks:  make(map[string]registeredCheck),
 		version: version,
+		timeout: 5 * time.Second,
+	}
+}
+
+// NewHealthCheckerFromConfig creates a health checker with the configured check timeouts
+// and critical checks; other checks failing only degrade the service
+func NewHealthCheckerFromConfig(version string, cfg config.ObservabilityConfig) HealthChecker {
+	hc := &StandardHealthChecker{
+		checks:   make(map[string]registeredCheck),
+		version:  version,
+		timeout:  cfg.HealthCheckTimeout,
+		timeouts: cfg.HealthCheckTimeouts,
+		critical: make(map[string]bool),
+	}
+	for _, name := range cfg.CriticalHealthChecks {
+		hc.critical[name] = true
 	}
+	return hc
 }
 
-// CheckHealth performs all registered health checks
+// CheckHealth performs all registered health checks concurrently
 func (hc *StandardHealthChecker) CheckHealth(ctx context.Context) HealthStatus {
+	return hc.run(ctx, false)
+}
+
+// CheckReadiness performs the readiness checks. Any of them failing makes the service
+// unhealthy, whether critical or not.
+func (hc *StandardHealthChecker) CheckReadiness(ctx context.Context) HealthStatus {
+	return hc.run(ctx, true)
+}
+
+func (hc *StandardHealthChecker) run(ctx context.Context, readiness bool) HealthStatus {
 	start := time.Now()
-	results := make(map[string]HealthCheckResult)
 
-	overallStatus := "healthy"
+	hc.mu.RLock()
+	checks := make(map[string]registeredCheck, len(hc.checks))
+	for name, registered := range hc.checks {
+		if !readiness || registered.readiness {
+			checks[name] = registered
+		}
+	}
+	hc.mu.RUnlock()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[string]HealthCheckResult, len(checks))
+	for name, registered := range checks {
+		wg.Add(1)
+		go func(name string, check HealthCheck) {
+			defer wg.Done()
+			result := hc.runCheck(ctx, name, check)
+			result.Critical = readiness || hc.isCritical(name)
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}(name, registered.check)
+	}
+	wg.Wait()
 
-	for name, check := range hc.checks {
-		checkStart := time.Now()
-		result := check(ctx)
-		result.Duration = time.Since(checkStart)
-		result.Timestamp = checkStart
-		results[name] = result
-
-		if result.Status != "healthy" && overallStatus != "unhealthy" {
-			if result.Status == "degraded" {
-				overallStatus = "degraded"
-			} else {
-				overallStatus = "unhealthy"
-			}
+	overallStatus := "healthy"
+	for _, result := range results {
+		switch {
+		case result.Status == "healthy" || overallStatus == "unhealthy":
+		case result.Status == "degraded" || !result.Critical:
+			overallStatus = "degraded"
+		default:
+			overallStatus = "unhealthy"
 		}
 	}
 
@@ -347,9 +522,56 @@ func (hc *StandardHealthChecker) CheckHealth(ctx context.Context) HealthStatus {
 	}
 }
 
+// runCheck runs a check, failing it once it takes longer than its timeout
+func (hc *StandardHealthChecker) runCheck(ctx context.Context, name string, check HealthCheck) HealthCheckResult {
+	timeout := hc.timeout
+	if t, ok := hc.timeouts[name]; ok {
+		timeout = t
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	done := make(chan HealthCheckResult, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	var result HealthCheckResult
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result = HealthCheckResult{
+			Status:  "unhealthy",
+			Message: fmt.Sprintf("Health check timed out after %s", timeout),
+		}
+	}
+	result.Duration = time.Since(start)
+	result.Timestamp = start
+	return result
+}
+
+func (hc *StandardHealthChecker) isCritical(name string) bool {
+	return hc.critical == nil || hc.critical[name]
+}
+
 // RegisterCheck registers a new health check
 func (hc *StandardHealthChecker) RegisterCheck(name string, check HealthCheck) {
-	hc.checks[name] = check
+	hc.register(name, registeredCheck{check: check})
+}
+
+// RegisterReadinessCheck registers a health check that is also a readiness check
+func (hc *StandardHealthChecker) RegisterReadinessCheck(name string, check HealthCheck) {
+	hc.register(name, registeredCheck{check: check, readiness: true})
+}
+
+func (hc *StandardHealthChecker) register(name string, registered registeredCheck) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	hc.checks[name] = registered
 }
 
 // Common health checks
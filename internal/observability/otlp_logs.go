@@ -0,0 +1,162 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OTLPLogRecord is a single log record to be exported over OTLP
+type OTLPLogRecord struct {
+	Timestamp    time.Time
+	SeverityText string // "INFO", "WARN", "ERROR"
+	Body         string
+	Attributes   map[string]string
+}
+
+// OTLPLogExporter sends log records to an OpenTelemetry collector using OTLP/HTTP with JSON encoding
+type OTLPLogExporter struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	scope       string
+	httpClient  *http.Client
+}
+
+// NewOTLPLogExporter creates an exporter posting to {endpoint}/v1/logs
+func NewOTLPLogExporter(endpoint, serviceName string, headers map[string]string) *OTLPLogExporter {
+	return &OTLPLogExporter{
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		headers:     headers,
+		serviceName: serviceName,
+		scope:       "incident-teller/timeline",
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// OTLP/JSON wire types (see opentelemetry-proto logs/v1)
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpResourceLogs struct {
+	Resource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	} `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+// Export sends the given records in a single OTLP request
+func (e *OTLPLogExporter) Export(ctx context.Context, records []OTLPLogRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	observed := strconv.FormatInt(time.Now().UnixNano(), 10)
+	scope := otlpScopeLogs{LogRecords: make([]otlpLogRecord, len(records))}
+	scope.Scope.Name = e.scope
+
+	for i, record := range records {
+		scope.LogRecords[i] = otlpLogRecord{
+			TimeUnixNano:         strconv.FormatInt(record.Timestamp.UnixNano(), 10),
+			ObservedTimeUnixNano: observed,
+			SeverityNumber:       otlpSeverityNumber(record.SeverityText),
+			SeverityText:         record.SeverityText,
+			Body:                 otlpAnyValue{StringValue: record.Body},
+			Attributes:           otlpAttributes(record.Attributes),
+		}
+	}
+
+	resource := otlpResourceLogs{ScopeLogs: []otlpScopeLogs{scope}}
+	resource.Resource.Attributes = otlpAttributes(map[string]string{"service.name": e.serviceName})
+
+	body, err := json.Marshal(otlpLogsRequest{ResourceLogs: []otlpResourceLogs{resource}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal OTLP logs: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint+"/v1/logs", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export OTLP logs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("OTLP collector returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// otlpSeverityNumber maps severity text to the OTLP SeverityNumber enum
+func otlpSeverityNumber(severity string) int {
+	switch strings.ToUpper(severity) {
+	case "DEBUG":
+		return 5
+	case "WARN", "WARNING":
+		return 13
+	case "ERROR", "CRITICAL":
+		return 17
+	default:
+		return 9 // INFO
+	}
+}
+
+// otlpAttributes converts a string map to sorted OTLP key/value attributes
+func otlpAttributes(attrs map[string]string) []otlpKeyValue {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([]otlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, otlpKeyValue{Key: k, Value: otlpAnyValue{StringValue: attrs[k]}})
+	}
+	return result
+}
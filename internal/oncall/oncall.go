@@ -0,0 +1,217 @@
+// Package oncall tracks who is on call using a simple rotation schedule and
+// records which on-call member each incident was assigned to.
+package oncall
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Member is a person in the on-call rotation
+type Member struct {
+	Name    string `json:"name"`
+	Email   string `json:"email,omitempty"`
+	SlackID string `json:"slack_id,omitempty"` // Slack member ID used for @-mentions, e.g. "U024BE7LH"
+}
+
+// Override temporarily replaces the scheduled member, e.g. for a shift swap
+type Override struct {
+	Member Member    `json:"member"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// Schedule is a fixed-length rotation through Members starting at Start
+type Schedule struct {
+	Members     []Member      `json:"members"`
+	Start       time.Time     `json:"start"`
+	ShiftLength time.Duration `json:"shift_length"`
+	Overrides   []Override    `json:"overrides,omitempty"`
+}
+
+// Shift is the on-call member for a period of time
+type Shift struct {
+	Member   Member    `json:"member"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Override bool      `json:"override"`
+}
+
+// Validate checks the schedule can produce shifts
+func (s Schedule) Validate() error {
+	if len(s.Members) == 0 {
+		return fmt.Errorf("on-call schedule needs at least one member")
+	}
+	if s.ShiftLength <= 0 {
+		return fmt.Errorf("on-call shift length must be positive")
+	}
+	for i, m := range s.Members {
+		if m.Name == "" {
+			return fmt.Errorf("on-call member %d has no name", i)
+		}
+	}
+	for i, o := range s.Overrides {
+		if o.Member.Name == "" {
+			return fmt.Errorf("on-call override %d has no member", i)
+		}
+		if !o.End.After(o.Start) {
+			return fmt.Errorf("on-call override %d must end after it starts", i)
+		}
+	}
+	return nil
+}
+
+// At returns the shift covering time t. Overrides take precedence over the rotation;
+// when several overrides overlap, the last one added wins.
+func (s Schedule) At(t time.Time) (Shift, bool) {
+	for i := len(s.Overrides) - 1; i >= 0; i-- {
+		o := s.Overrides[i]
+		if !t.Before(o.Start) && t.Before(o.End) {
+			return Shift{Member: o.Member, Start: o.Start, End: o.End, Override: true}, true
+		}
+	}
+
+	if len(s.Members) == 0 || s.ShiftLength <= 0 {
+		return Shift{}, false
+	}
+
+	// Shifts before Start continue the rotation backwards
+	elapsed := t.Sub(s.Start)
+	index := int64(elapsed / s.ShiftLength)
+	if elapsed < 0 && elapsed%s.ShiftLength != 0 {
+		index--
+	}
+
+	n := int64(len(s.Members))
+	start := s.Start.Add(time.Duration(index) * s.ShiftLength)
+	return Shift{
+		Member: s.Members[((index%n)+n)%n],
+		Start:  start,
+		End:    start.Add(s.ShiftLength),
+	}, true
+}
+
+// Secondary returns the backup for the shift covering time t: the member after the
+// scheduled one in the rotation. When an override put that member on call, the
+// scheduled member backs them up instead. A single-member rotation has no secondary.
+func (s Schedule) Secondary(t time.Time) (Member, bool) {
+	rotation := Schedule{Members: s.Members, Start: s.Start, ShiftLength: s.ShiftLength}
+	shift, ok := rotation.At(t)
+	if !ok {
+		return Member{}, false
+	}
+	next, _ := rotation.At(shift.End)
+	primary, _ := s.At(t)
+	for _, m := range []Member{next.Member, shift.Member} {
+		if m.Name != primary.Member.Name {
+			return m, true
+		}
+	}
+	return Member{}, false
+}
+
+// Assignment records which on-call member an incident was assigned to
+type Assignment struct {
+	IncidentID string    `json:"incident_id"`
+	Member     Member    `json:"member"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// Manager holds the active schedule and incident assignments. It is safe for concurrent use.
+type Manager struct {
+	mu          sync.RWMutex
+	schedule    Schedule
+	assignments map[string]Assignment
+}
+
+// NewManager creates a manager for the given schedule
+func NewManager(schedule Schedule) (*Manager, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+	return &Manager{
+		schedule:    schedule,
+		assignments: make(map[string]Assignment),
+	}, nil
+}
+
+// Schedule returns a copy of the active schedule
+func (m *Manager) Schedule() Schedule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s := m.schedule
+	s.Members = append([]Member(nil), s.Members...)
+	s.Overrides = append([]Override(nil), s.Overrides...)
+	return s
+}
+
+// SetSchedule replaces the active schedule. Existing assignments are kept.
+func (m *Manager) SetSchedule(schedule Schedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.schedule = schedule
+	return nil
+}
+
+// AddOverride adds a temporary override to the active schedule
+func (m *Manager) AddOverride(o Override) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.schedule
+	s.Overrides = append(append([]Override(nil), s.Overrides...), o)
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	m.schedule = s
+	return nil
+}
+
+// Current returns the shift covering time t
+func (m *Manager) Current(t time.Time) (Shift, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.schedule.At(t)
+}
+
+// Secondary returns the backup on-call member at time t
+func (m *Manager) Secondary(t time.Time) (Member, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.schedule.Secondary(t)
+}
+
+// Assign assigns the incident to whoever is on call at time t. An incident is only
+// assigned once; later calls return the existing assignment.
+func (m *Manager) Assign(incidentID string, t time.Time) (Assignment, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, ok := m.assignments[incidentID]; ok {
+		return existing, true
+	}
+
+	shift, ok := m.schedule.At(t)
+	if !ok {
+		return Assignment{}, false
+	}
+
+	assignment := Assignment{IncidentID: incidentID, Member: shift.Member, AssignedAt: t}
+	m.assignments[incidentID] = assignment
+	return assignment, true
+}
+
+// Assignment returns the assignment for an incident, if any
+func (m *Manager) Assignment(incidentID string) (Assignment, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	assignment, ok := m.assignments[incidentID]
+	return assignment, ok
+}
@@ -0,0 +1,282 @@
+// Package playbook holds the organization's remediation playbooks: fix steps and runbook
+// links matched to alerts by resource type, chart pattern or alert name. Playbooks are
+// loaded from YAML files and can be managed at runtime.
+package playbook
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"incident-teller/internal/domain"
+)
+
+// ErrNotFound is returned for unknown playbook IDs
+var ErrNotFound = errors.New("playbook not found")
+
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Playbook is a set of fix steps for matching alerts. Every set match field must match;
+// chart and alert name are glob patterns (e.g. "disk_space.*").
+type Playbook struct {
+	ID           string              `yaml:"id" json:"id"`
+	Name         string              `yaml:"name" json:"name"`
+	ResourceType domain.ResourceType `yaml:"resource_type,omitempty" json:"resource_type,omitempty"`
+	Chart        string              `yaml:"chart,omitempty" json:"chart,omitempty"`
+	AlertName    string              `yaml:"alert_name,omitempty" json:"alert_name,omitempty"`
+	RunbookURL   string              `yaml:"runbook_url,omitempty" json:"runbook_url,omitempty"`
+	Immediate    []string            `yaml:"immediate,omitempty" json:"immediate,omitempty"`
+	ShortTerm    []string            `yaml:"short_term,omitempty" json:"short_term,omitempty"`
+	LongTerm     []string            `yaml:"long_term,omitempty" json:"long_term,omitempty"`
+}
+
+// Validate checks the playbook can be stored and matched
+func (p Playbook) Validate() error {
+	if !validID.MatchString(p.ID) {
+		return fmt.Errorf("invalid playbook id %q: use lowercase letters, digits, - and _", p.ID)
+	}
+	if p.ResourceType == "" && p.Chart == "" && p.AlertName == "" {
+		return fmt.Errorf("playbook %s: set at least one of resource_type, chart and alert_name", p.ID)
+	}
+	for _, pattern := range []string{p.Chart, p.AlertName} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("playbook %s: invalid pattern %q: %w", p.ID, pattern, err)
+		}
+	}
+	if len(p.Immediate) == 0 && len(p.ShortTerm) == 0 && len(p.LongTerm) == 0 && p.RunbookURL == "" {
+		return fmt.Errorf("playbook %s: has no steps and no runbook URL", p.ID)
+	}
+	return nil
+}
+
+// specificity ranks how closely the playbook matches the alert, 0 if it doesn't. An
+// alert name match outranks a chart match, which outranks a resource type match.
+func (p Playbook) specificity(alert domain.Alert) int {
+	score := 0
+	if p.AlertName != "" {
+		if ok, _ := path.Match(p.AlertName, alert.Name); !ok {
+			return 0
+		}
+		score += 4
+	}
+	if p.Chart != "" {
+		if ok, _ := path.Match(p.Chart, alert.Chart); !ok {
+			return 0
+		}
+		score += 2
+	}
+	if p.ResourceType != "" {
+		if p.ResourceType != alert.ResourceType {
+			return 0
+		}
+		score++
+	}
+	return score
+}
+
+// Store holds playbooks in memory. With a directory, each playbook is kept in
+// <dir>/<id>.yaml so changes made at runtime survive restarts. It is safe for
+// concurrent use; a nil *Store has no playbooks.
+type Store struct {
+	dir string
+
+	mu        sync.RWMutex
+	playbooks map[string]Playbook
+	files     map[string]string // id -> file the playbook was loaded from or written to
+}
+
+// NewStore creates an empty store that keeps no files
+func NewStore() *Store {
+	return &Store{playbooks: make(map[string]Playbook), files: make(map[string]string)}
+}
+
+// Load creates a store from the *.yaml and *.yml files in dir, each holding one
+// playbook or a list of them. The directory is created if missing.
+func Load(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create playbook directory: %w", err)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read playbook directory: %w", err)
+	}
+
+	s := &Store{dir: dir, playbooks: make(map[string]Playbook), files: make(map[string]string)}
+	for _, file := range files {
+		ext := filepath.Ext(file.Name())
+		if file.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		playbooks, err := readFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range playbooks {
+			if _, exists := s.playbooks[p.ID]; exists {
+				return nil, fmt.Errorf("%s: duplicate playbook id %s", file.Name(), p.ID)
+			}
+			s.playbooks[p.ID] = p
+			s.files[p.ID] = filepath.Join(dir, file.Name())
+		}
+	}
+	return s, nil
+}
+
+func readFile(name string) ([]Playbook, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read playbook file: %w", err)
+	}
+
+	var playbooks []Playbook
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "-") {
+		err = yaml.Unmarshal(data, &playbooks)
+	} else {
+		var p Playbook
+		err = yaml.Unmarshal(data, &p)
+		playbooks = []Playbook{p}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(name), err)
+	}
+
+	for _, p := range playbooks {
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(name), err)
+		}
+	}
+	return playbooks, nil
+}
+
+// Match returns the most specific playbook matching the alert. Ties go to the
+// lowest ID so matching is deterministic.
+func (s *Store) Match(alert domain.Alert) (Playbook, bool) {
+	if s == nil {
+		return Playbook{}, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var best Playbook
+	bestScore := 0
+	for _, p := range s.playbooks {
+		score := p.specificity(alert)
+		if score > bestScore || (score == bestScore && score > 0 && p.ID < best.ID) {
+			best, bestScore = p, score
+		}
+	}
+	return best, bestScore > 0
+}
+
+// List returns all playbooks ordered by ID
+func (s *Store) List() []Playbook {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]Playbook, 0, len(s.playbooks))
+	for _, p := range s.playbooks {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// Get returns a playbook by ID
+func (s *Store) Get(id string) (Playbook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.playbooks[id]
+	if !ok {
+		return Playbook{}, ErrNotFound
+	}
+	return p, nil
+}
+
+// Put creates or replaces a playbook, reporting whether it was created
+func (s *Store) Put(p Playbook) (bool, error) {
+	if err := p.Validate(); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.playbooks[p.ID]
+	if s.dir != "" {
+		// A playbook loaded from a file holding several moves into its own file
+		own := filepath.Join(s.dir, p.ID+".yaml")
+		if file, ok := s.files[p.ID]; ok && file != own {
+			if err := s.removeFromFileLocked(p.ID); err != nil {
+				return false, err
+			}
+		}
+		if err := writeFile(own, p); err != nil {
+			return false, err
+		}
+		s.files[p.ID] = own
+	}
+	s.playbooks[p.ID] = p
+	return !exists, nil
+}
+
+// Delete removes a playbook
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.playbooks[id]; !ok {
+		return ErrNotFound
+	}
+	if s.dir != "" {
+		if err := s.removeFromFileLocked(id); err != nil {
+			return err
+		}
+	}
+	delete(s.playbooks, id)
+	return nil
+}
+
+// removeFromFileLocked deletes a playbook from its file, removing the file once no
+// other playbook is left in it
+func (s *Store) removeFromFileLocked(id string) error {
+	file, ok := s.files[id]
+	if !ok {
+		return nil
+	}
+	delete(s.files, id)
+
+	var rest []Playbook
+	for otherID, otherFile := range s.files {
+		if otherFile == file {
+			rest = append(rest, s.playbooks[otherID])
+		}
+	}
+	if len(rest) == 0 {
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to delete playbook file: %w", err)
+		}
+		return nil
+	}
+	sort.Slice(rest, func(i, j int) bool { return rest[i].ID < rest[j].ID })
+	return writeFile(file, rest)
+}
+
+func writeFile(name string, v any) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write playbook file: %w", err)
+	}
+	return nil
+}
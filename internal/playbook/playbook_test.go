@@ -0,0 +1,67 @@
+package playbook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"incident-teller/internal/domain"
+)
+
+func TestStore_MatchAndPersist(t *testing.T) {
+	dir := t.TempDir()
+	shared := `
+- id: disk
+  name: Disk cleanup
+  resource_type: DISK
+  runbook_url: https://runbooks.example.com/disk
+  immediate: ["Run the disk cleanup job"]
+- id: postgres-disk
+  name: Postgres WAL
+  chart: "disk_space.*"
+  alert_name: "pg_*"
+  immediate: ["Archive WAL segments"]
+`
+	if err := os.WriteFile(filepath.Join(dir, "shared.yaml"), []byte(shared), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alert := domain.Alert{Name: "pg_wal_disk", Chart: "disk_space._var", ResourceType: domain.ResourceDisk}
+	if p, ok := store.Match(alert); !ok || p.ID != "postgres-disk" {
+		t.Errorf("expected the alert name playbook to win, got %+v", p)
+	}
+	alert.Name = "disk_full"
+	if p, ok := store.Match(alert); !ok || p.ID != "disk" {
+		t.Errorf("expected the resource type playbook, got %+v", p)
+	}
+	if _, ok := store.Match(domain.Alert{ResourceType: domain.ResourceCPU}); ok {
+		t.Error("expected no playbook for CPU alerts")
+	}
+
+	// Changing a playbook from a shared file moves it into its own file
+	updated, _ := store.Get("disk")
+	updated.Immediate = []string{"Expand the volume"}
+	if created, err := store.Put(updated); err != nil || created {
+		t.Fatalf("put: created %v, err %v", created, err)
+	}
+	if err := store.Delete("postgres-disk"); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	playbooks := reloaded.List()
+	if len(playbooks) != 1 || playbooks[0].Immediate[0] != "Expand the volume" {
+		t.Errorf("unexpected playbooks after reload: %+v", playbooks)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "shared.yaml")); !os.IsNotExist(err) {
+		t.Errorf("expected the emptied shared file to be removed, got %v", err)
+	}
+}
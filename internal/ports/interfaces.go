@@ -2,6 +2,8 @@ package ports
 
 import (
 	"context"
+	"time"
+
 	"incident-teller/internal/domain"
 )
 
@@ -11,15 +13,246 @@ type AlertSource interface {
 	FetchLatest(ctx context.Context, lastID uint64) ([]domain.Alert, error)
 }
 
+// AlertStream defines sources that push alerts as they happen instead of being polled
+type AlertStream interface {
+	// Stream sends batches of alerts newer than lastID to out until ctx is cancelled
+	Stream(ctx context.Context, lastID uint64, out chan<- []domain.Alert) error
+}
+
+// HistorySource is implemented by alert sources that can return their past alerts, used
+// to fill an empty store on the first run
+type HistorySource interface {
+	// FetchHistory returns the alerts that occurred since the given time, oldest first
+	FetchHistory(ctx context.Context, since time.Time) ([]domain.Alert, error)
+}
+
+// MetricSource is implemented by alert sources that can return the recent values of the
+// chart an alert fired on
+type MetricSource interface {
+	// FetchSamples returns at most points values of the alert's chart over the window
+	// ending when the alert occurred, oldest first
+	FetchSamples(ctx context.Context, alert domain.Alert, window time.Duration, points int) ([]domain.MetricSample, error)
+}
+
 // Repository defines storage requirements for incidents and events
 type Repository interface {
 	SaveAlert(ctx context.Context, alert domain.Alert) error
+	SaveAlerts(ctx context.Context, alerts []domain.Alert) error
 	GetIncidents(ctx context.Context) ([]domain.Incident, error)
 	GetLastProcessedID(ctx context.Context) (uint64, error)
 	SetLastProcessedID(ctx context.Context, id uint64) error
 }
 
+// IncidentWriter persists alerts and the incidents built from them, e.g. incidents raised
+// by IncidentTeller itself
+type IncidentWriter interface {
+	SaveAlert(ctx context.Context, alert domain.Alert) error
+	SaveIncident(ctx context.Context, incident domain.Incident) error
+}
+
+// SourceCursorStore keeps a separate last processed ID per alert source, so several
+// sources with independent ID spaces can be polled at once
+type SourceCursorStore interface {
+	GetSourceCursor(ctx context.Context, source string) (uint64, error)
+	SetSourceCursor(ctx context.Context, source string, id uint64) error
+}
+
+// ExportCursorStore keeps the progress of exporters pushing finalized incidents elsewhere,
+// so a restart resumes where the last export stopped
+type ExportCursorStore interface {
+	// GetExportCursor returns the resolution time up to which an exporter has exported,
+	// zero if it has not exported yet
+	GetExportCursor(ctx context.Context, exporter string) (time.Time, error)
+	// SetExportCursor records an exporter's progress
+	SetExportCursor(ctx context.Context, exporter string, at time.Time) error
+}
+
 // TimelineService defines the interface for generating outputs
 type TimelineService interface {
 	Generate(incident domain.Incident) (string, error)
 }
+
+// AlertHistory provides access to all stored alerts, e.g. for learning from past incidents
+type AlertHistory interface {
+	GetAlerts(ctx context.Context) ([]domain.Alert, error)
+}
+
+// AlertStore looks up stored alerts one at a time, with the raw payload of their source,
+// or a page at a time, and keeps the acknowledgements of single alerts. Repositories
+// implementing it fill Alert.Ack for the alerts they return.
+type AlertStore interface {
+	// GetAlert returns an alert with its raw payload, or nil if it doesn't exist
+	GetAlert(ctx context.Context, id string) (*domain.Alert, error)
+	// QueryAlerts returns the alerts matching the query, newest first, and how many match
+	// without the query's limit and offset
+	QueryAlerts(ctx context.Context, q domain.AlertQuery) ([]domain.Alert, int, error)
+	// AcknowledgeAlert records the acknowledgement of an alert, replacing an earlier one
+	AcknowledgeAlert(ctx context.Context, ack domain.AlertAck) error
+}
+
+// PropagationPatternStore persists learned propagation patterns across restarts
+type PropagationPatternStore interface {
+	// SavePropagationPatterns replaces all stored patterns
+	SavePropagationPatterns(ctx context.Context, patterns []domain.PropagationPattern) error
+	GetPropagationPatterns(ctx context.Context) ([]domain.PropagationPattern, error)
+}
+
+// TimelineStore persists incident timelines with their causality links, so timelines
+// are extended as alerts arrive instead of being recomputed on every read
+type TimelineStore interface {
+	// GetTimelineEntries returns the stored entries of an incident in chronological order
+	GetTimelineEntries(ctx context.Context, incidentID string) ([]domain.TimelineEntry, error)
+	// AppendTimelineEntries adds entries after the incident's stored entries
+	AppendTimelineEntries(ctx context.Context, incidentID string, entries []domain.TimelineEntry) error
+	// ReplaceTimelineEntries replaces all stored entries of an incident
+	ReplaceTimelineEntries(ctx context.Context, incidentID string, entries []domain.TimelineEntry) error
+}
+
+// EscalationStore persists the escalations of unacknowledged incidents, so a restart
+// continues each incident's escalation policy where it left off
+type EscalationStore interface {
+	// GetEscalations returns the escalations of an incident in level order
+	GetEscalations(ctx context.Context, incidentID string) ([]domain.Escalation, error)
+	// SaveEscalation records an escalation; each level of an incident is kept once
+	SaveEscalation(ctx context.Context, escalation domain.Escalation) error
+}
+
+// AcknowledgementStore persists the first acknowledgement of each incident, so it
+// survives restarts and is seen by every replica
+type AcknowledgementStore interface {
+	// SaveAcknowledgement records an acknowledgement; only the first of an incident is kept
+	SaveAcknowledgement(ctx context.Context, incidentID, by string, at time.Time) error
+	// IsAcknowledged reports whether an incident has been acknowledged
+	IsAcknowledged(ctx context.Context, incidentID string) (bool, error)
+}
+
+// TicketStore persists the tracker tickets filed for incidents
+type TicketStore interface {
+	// GetTicket returns the ticket of an incident, or nil if none was filed
+	GetTicket(ctx context.Context, incidentID string) (*domain.Ticket, error)
+	// SaveTicket stores or updates the ticket of an incident
+	SaveTicket(ctx context.Context, ticket domain.Ticket) error
+}
+
+// ProblemStore persists which incidents are occurrences of the same recurring problem
+type ProblemStore interface {
+	// LinkIncident records the incident as an occurrence of a problem, replacing an
+	// earlier link
+	LinkIncident(ctx context.Context, link domain.ProblemLink) error
+	// GetProblemLinks returns the links of a problem, or of every problem if problemID is
+	// empty, oldest first
+	GetProblemLinks(ctx context.Context, problemID string) ([]domain.ProblemLink, error)
+}
+
+// RootCauseStore persists root cause predictions and feedback on them, for calibrating
+// model confidence and comparing model versions
+type RootCauseStore interface {
+	// SaveRootCause stores the prediction of a model version for an incident, replacing
+	// an earlier one; stored feedback is kept if the predicted alert didn't change
+	SaveRootCause(ctx context.Context, record domain.RootCauseRecord) error
+	// GetRootCauses returns the stored predictions, of one incident if incidentID is set
+	GetRootCauses(ctx context.Context, incidentID string) ([]domain.RootCauseRecord, error)
+	// SetRootCauseFeedback records whether a model version's prediction was correct
+	SetRootCauseFeedback(ctx context.Context, incidentID, modelVersion string, correct bool, at time.Time) error
+}
+
+// RootCauseOverrideStore persists the root causes responders pin on incidents apart from
+// the correlated incidents, so they survive correlation. Repositories implementing it fill
+// Incident.RootCauseOverride when loading incidents.
+type RootCauseOverrideStore interface {
+	// SetRootCauseOverride pins the root cause of an incident, replacing an earlier one
+	SetRootCauseOverride(ctx context.Context, override domain.RootCauseOverride) error
+	// DeleteRootCauseOverride unpins the root cause of an incident
+	DeleteRootCauseOverride(ctx context.Context, incidentID string) error
+	// GetRootCauseOverrides returns the pinned root causes, of one incident if incidentID is set
+	GetRootCauseOverrides(ctx context.Context, incidentID string) ([]domain.RootCauseOverride, error)
+}
+
+// PriorityStore persists manual incident priorities with the history of changes.
+// Repositories implementing it fill Incident.PriorityOverride when loading incidents.
+type PriorityStore interface {
+	// SetPriority records the change and makes its priority the incident's override;
+	// an empty priority removes the override
+	SetPriority(ctx context.Context, change domain.PriorityChange) error
+	// GetPriorityChanges returns the priority changes of an incident, oldest first
+	GetPriorityChanges(ctx context.Context, incidentID string) ([]domain.PriorityChange, error)
+}
+
+// IncidentStateStore persists the lifecycle states of incidents with every transition,
+// apart from the correlated incidents so they survive correlation. Repositories
+// implementing it fill Incident.State and StateChangedAt when loading incidents.
+type IncidentStateStore interface {
+	// SetIncidentState records the transition and makes its state the incident's state if
+	// the incident is still in the transition's From state, or returns
+	// domain.ErrStateConflict
+	SetIncidentState(ctx context.Context, transition domain.StateTransition) error
+	// GetStateTransitions returns the state transitions of an incident, oldest first
+	GetStateTransitions(ctx context.Context, incidentID string) ([]domain.StateTransition, error)
+}
+
+// ActionItemStore persists the follow-ups of incidents apart from the correlated
+// incidents, so they survive correlation
+type ActionItemStore interface {
+	// SaveActionItem stores an action item, replacing the one with the same ID
+	SaveActionItem(ctx context.Context, item domain.ActionItem) error
+	// DeleteActionItem removes an action item, reporting whether there was one
+	DeleteActionItem(ctx context.Context, id string) (bool, error)
+	// GetActionItems returns the action items of an incident, or of every incident if
+	// incidentID is empty, oldest first
+	GetActionItems(ctx context.Context, incidentID string) ([]domain.ActionItem, error)
+}
+
+// IncidentMetadataStore persists the severity, tags and custom fields of incidents apart
+// from the correlated incidents, so they survive correlation. Repositories implementing it
+// fill Incident.Severity, Tags and CustomFields when loading incidents.
+type IncidentMetadataStore interface {
+	// SetIncidentMetadata replaces the metadata of an incident; empty metadata removes it
+	SetIncidentMetadata(ctx context.Context, metadata domain.IncidentMetadata) error
+}
+
+// AlertSampleStore keeps the chart values sampled for alerts when their incident formed.
+// Repositories implementing it fill Alert.Samples of incident events when loading incidents.
+type AlertSampleStore interface {
+	// SaveAlertSamples replaces the samples of an alert
+	SaveAlertSamples(ctx context.Context, alertID string, samples []domain.MetricSample) error
+}
+
+// AuditStore keeps the audit log of writes made through the API
+type AuditStore interface {
+	// SaveAuditEntry appends an entry to the audit log
+	SaveAuditEntry(ctx context.Context, entry domain.AuditEntry) error
+	// GetAuditEntries returns the entries matching the query, newest first
+	GetAuditEntries(ctx context.Context, q domain.AuditQuery) ([]domain.AuditEntry, error)
+}
+
+// WebhookStore persists the outgoing webhooks and their failed deliveries
+type WebhookStore interface {
+	// GetWebhooks returns every webhook, oldest first
+	GetWebhooks(ctx context.Context) ([]domain.Webhook, error)
+	// GetWebhook returns a webhook, or nil if it doesn't exist
+	GetWebhook(ctx context.Context, id string) (*domain.Webhook, error)
+	// SaveWebhook stores or replaces a webhook
+	SaveWebhook(ctx context.Context, webhook domain.Webhook) error
+	// DeleteWebhook removes a webhook with its failed deliveries
+	DeleteWebhook(ctx context.Context, id string) error
+	// SaveFailedDelivery adds a delivery to the dead letters
+	SaveFailedDelivery(ctx context.Context, delivery domain.FailedDelivery) error
+	// GetFailedDelivery returns a dead letter, or nil if it doesn't exist
+	GetFailedDelivery(ctx context.Context, id string) (*domain.FailedDelivery, error)
+	// GetFailedDeliveries returns the dead letters, of one webhook if webhookID is set,
+	// newest first; limit 0 returns all
+	GetFailedDeliveries(ctx context.Context, webhookID string, limit int) ([]domain.FailedDelivery, error)
+	// DeleteFailedDelivery removes a dead letter
+	DeleteFailedDelivery(ctx context.Context, id string) error
+}
+
+// LeaseStore grants named leases to one holder at a time, used to elect the replica that
+// polls the alert sources
+type LeaseStore interface {
+	// AcquireLease takes or renews the lease for holder until ttl from now if it is free,
+	// expired or already held by holder. It returns the current lease and whether holder
+	// has it.
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (domain.Lease, bool, error)
+	// ReleaseLease gives up a lease if holder has it
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
@@ -0,0 +1,277 @@
+// Package redact replaces hostnames, IP addresses and sensitive label values with stable
+// tokens before alert context leaves the process, e.g. for OpenAI or Slack. The tokens are
+// keyed hashes, so the same value always gets the same token, and the redactor keeps the
+// mapping back to the originals to restore analyses locally.
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+)
+
+var (
+	ipv4Pattern = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	ipv6Pattern = regexp.MustCompile(`(?i)\b[0-9a-f]{0,4}(?::[0-9a-f]{0,4}){2,7}\b`)
+	// tokenPattern matches every token a redactor produces
+	tokenPattern = regexp.MustCompile(`\b[a-z][a-z0-9_]*-[0-9a-f]{8}\b`)
+)
+
+// Redactor replaces sensitive values with tokens. A nil Redactor leaves everything as is.
+type Redactor struct {
+	hashHosts bool
+	maskIPs   bool
+	denylist  map[string]bool
+	secret    []byte
+
+	mu        sync.RWMutex
+	originals map[string]string // token -> original value
+	tokens    map[string]string // original value -> token
+	replacer  *strings.Replacer // Known hosts and denylisted label values, longest first
+}
+
+// Options configure a Redactor
+type Options struct {
+	HashHostnames bool
+	MaskIPs       bool
+	LabelDenylist []string
+	Secret        string // Keys the tokens; random if empty, so tokens differ between processes
+}
+
+// New creates a redactor
+func New(opts Options) (*Redactor, error) {
+	secret := []byte(opts.Secret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate redaction secret: %w", err)
+		}
+	}
+
+	r := &Redactor{
+		hashHosts: opts.HashHostnames,
+		maskIPs:   opts.MaskIPs,
+		denylist:  make(map[string]bool, len(opts.LabelDenylist)),
+		secret:    secret,
+		originals: make(map[string]string),
+		tokens:    make(map[string]string),
+		replacer:  strings.NewReplacer(),
+	}
+	for _, label := range opts.LabelDenylist {
+		r.denylist[strings.ToLower(strings.TrimSpace(label))] = true
+	}
+	return r, nil
+}
+
+// FromConfig builds the redactor for the redaction config section. It returns nil if
+// redaction is disabled.
+func FromConfig(cfg config.RedactionConfig) (*Redactor, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	return New(Options{
+		HashHostnames: cfg.HashHostnames,
+		MaskIPs:       cfg.MaskIPs,
+		LabelDenylist: cfg.LabelDenylist,
+		Secret:        cfg.HashSecret,
+	})
+}
+
+// Alerts returns redacted copies of the alerts: hosts become tokens, denylisted labels
+// are dropped and hosts, IPs and denylisted values are masked in descriptions and labels
+func (r *Redactor) Alerts(alerts []domain.Alert) []domain.Alert {
+	if r == nil {
+		return alerts
+	}
+
+	// Learn every sensitive value first, so each alert's text masks the others' hosts too
+	for _, alert := range alerts {
+		r.Host(alert.Host)
+		r.learnLabels(alert.Labels)
+	}
+
+	redacted := make([]domain.Alert, len(alerts))
+	for i, alert := range alerts {
+		alert.Host = r.Host(alert.Host)
+		alert.Description = r.Text(alert.Description)
+		alert.Labels = r.Labels(alert.Labels)
+		redacted[i] = alert
+	}
+	return redacted
+}
+
+// Host returns the token of a hostname. If hostnames aren't hashed, only a host that is
+// an IP address is masked.
+func (r *Redactor) Host(host string) string {
+	if r == nil || host == "" {
+		return host
+	}
+	if !r.hashHosts {
+		return r.Text(host)
+	}
+	return r.token("host", host)
+}
+
+// Labels returns a copy of the labels without denylisted ones and with the other values
+// masked like text
+func (r *Redactor) Labels(labels map[string]string) map[string]string {
+	if r == nil || labels == nil {
+		return labels
+	}
+	r.learnLabels(labels)
+
+	redacted := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if r.denylist[strings.ToLower(k)] {
+			continue
+		}
+		redacted[k] = r.Text(v)
+	}
+	return redacted
+}
+
+// Text masks the known hostnames, the given ones, denylisted label values and, if
+// enabled, IP addresses in free text
+func (r *Redactor) Text(text string, hosts ...string) string {
+	if r == nil || text == "" {
+		return text
+	}
+	for _, host := range hosts {
+		r.Host(host)
+	}
+
+	r.mu.RLock()
+	replacer := r.replacer
+	r.mu.RUnlock()
+	text = replacer.Replace(text)
+
+	if r.maskIPs {
+		mask := func(candidate string) string {
+			if net.ParseIP(candidate) == nil {
+				return candidate
+			}
+			return r.token("ip", candidate)
+		}
+		text = ipv4Pattern.ReplaceAllStringFunc(text, mask)
+		text = ipv6Pattern.ReplaceAllStringFunc(text, mask)
+	}
+	return text
+}
+
+// Restore replaces the tokens in text with the values they stand for. Tokens this
+// redactor didn't produce are left as they are.
+func (r *Redactor) Restore(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return tokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		if original, ok := r.originals[token]; ok {
+			return original
+		}
+		return token
+	})
+}
+
+// Mappings returns the number of values the redactor has replaced with tokens
+func (r *Redactor) Mappings() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.originals)
+}
+
+// minMaskedValue is the length below which denylisted label values aren't masked in text,
+// since they would match inside unrelated words and numbers
+const minMaskedValue = 3
+
+// learnLabels registers the values of denylisted labels, so they are masked in text
+func (r *Redactor) learnLabels(labels map[string]string) {
+	for k, v := range labels {
+		if r.denylist[strings.ToLower(k)] && len(v) >= minMaskedValue {
+			r.token(tokenKind(k), v)
+		}
+	}
+}
+
+// token returns the stable token of a value, recording the mapping back to it
+func (r *Redactor) token(kind, value string) string {
+	r.mu.RLock()
+	token, ok := r.tokens[value]
+	r.mu.RUnlock()
+	if ok {
+		return token
+	}
+
+	mac := hmac.New(sha256.New, r.secret)
+	mac.Write([]byte(value))
+	token = kind + "-" + hex.EncodeToString(mac.Sum(nil))[:8]
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.tokens[value]; ok {
+		return existing
+	}
+	r.tokens[value] = token
+	r.originals[token] = value
+	if kind != "ip" {
+		r.rebuildReplacer()
+	}
+	return token
+}
+
+// rebuildReplacer replaces hosts and label values longest first, so "web-01.prod" gets its
+// own token rather than web-01's followed by ".prod". Callers hold the write lock.
+func (r *Redactor) rebuildReplacer() {
+	values := make([]string, 0, len(r.tokens))
+	for value, token := range r.tokens {
+		if !strings.HasPrefix(token, "ip-") {
+			values = append(values, value)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
+
+	pairs := make([]string, 0, 2*len(values))
+	for _, value := range values {
+		pairs = append(pairs, value, r.tokens[value])
+	}
+	r.replacer = strings.NewReplacer(pairs...)
+}
+
+// tokenKind turns a label name into a token prefix, e.g. "customer_email" stays as is
+// and "Customer-ID" becomes "customer_id"
+func tokenKind(label string) string {
+	var sb strings.Builder
+	for _, c := range strings.ToLower(label) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+			sb.WriteRune(c)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	kind := sb.String()
+	if kind == "" || kind[0] < 'a' || kind[0] > 'z' {
+		kind = "label_" + kind
+	}
+	return kind
+}
@@ -0,0 +1,103 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func newTestRedactor(t *testing.T) *Redactor {
+	t.Helper()
+	r, err := New(Options{HashHostnames: true, MaskIPs: true, LabelDenylist: []string{"customer"}, Secret: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRedactor_Alerts(t *testing.T) {
+	r := newTestRedactor(t)
+	alerts := []domain.Alert{
+		{ID: "a1", Host: "db-01.prod", Name: "ram_in_use", OccurredAt: time.Now(),
+			Description: "db-01.prod at 10.0.0.5 is swapping, replica web-01 is slow (fe80::1 unreachable)",
+			Labels:      map[string]string{"customer": "acme", "team": "storage", "peer": "10.0.0.6"}},
+		{ID: "a2", Host: "web-01", Name: "cpu_usage", OccurredAt: time.Now(), Description: "slow for acme"},
+	}
+
+	redacted := r.Alerts(alerts)
+
+	for _, alert := range redacted {
+		text := alert.Host + " " + alert.Description
+		for _, v := range alert.Labels {
+			text += " " + v
+		}
+		for _, secret := range []string{"db-01", "web-01", "10.0.0.5", "10.0.0.6", "fe80::1", "acme"} {
+			if strings.Contains(text, secret) {
+				t.Errorf("alert %s still contains %q: %s", alert.ID, secret, text)
+			}
+		}
+	}
+	if _, ok := redacted[0].Labels["customer"]; ok {
+		t.Error("denylisted label was not dropped")
+	}
+	if redacted[0].Labels["team"] != "storage" {
+		t.Errorf("other labels should be kept, got %v", redacted[0].Labels)
+	}
+	if alerts[0].Host != "db-01.prod" || alerts[0].Labels["customer"] != "acme" {
+		t.Error("Alerts changed the original alerts")
+	}
+
+	// Tokens are stable and restore to the originals
+	if r.Host("web-01") != redacted[1].Host || !strings.HasPrefix(redacted[1].Host, "host-") {
+		t.Errorf("unexpected token %q", redacted[1].Host)
+	}
+	if restored := r.Restore(redacted[0].Description); restored != alerts[0].Description {
+		t.Errorf("restored %q, want %q", restored, alerts[0].Description)
+	}
+	if restored := r.Restore(redacted[1].Description); restored != alerts[1].Description {
+		t.Errorf("restored %q, want %q", restored, alerts[1].Description)
+	}
+}
+
+func TestRedactor_TokensDependOnSecret(t *testing.T) {
+	a, _ := New(Options{HashHostnames: true, Secret: "one"})
+	b, _ := New(Options{HashHostnames: true, Secret: "one"})
+	c, _ := New(Options{HashHostnames: true, Secret: "two"})
+
+	if a.Host("web-01") != b.Host("web-01") {
+		t.Error("the same secret should give the same token")
+	}
+	if a.Host("web-01") == c.Host("web-01") {
+		t.Error("different secrets should give different tokens")
+	}
+	if c.Restore(a.Host("web-01")) != a.Host("web-01") {
+		t.Error("tokens of another redactor should be left as they are")
+	}
+}
+
+func TestRedactor_IPHostWithoutHashing(t *testing.T) {
+	r, _ := New(Options{MaskIPs: true, Secret: "test"})
+
+	if got := r.Host("web-01"); got != "web-01" {
+		t.Errorf("hostnames should be kept without hashing, got %q", got)
+	}
+	if got := r.Host("192.168.1.20"); !strings.HasPrefix(got, "ip-") {
+		t.Errorf("an IP address host should be masked, got %q", got)
+	}
+	if got := r.Text("at 12:30:45 on 999.1.1.1"); got != "at 12:30:45 on 999.1.1.1" {
+		t.Errorf("times and invalid addresses should be kept, got %q", got)
+	}
+}
+
+func TestRedactor_Nil(t *testing.T) {
+	var r *Redactor
+	alerts := []domain.Alert{{Host: "web-01"}}
+	if got := r.Alerts(alerts); got[0].Host != "web-01" {
+		t.Error("a nil redactor should leave alerts as they are")
+	}
+	if r.Text("web-01") != "web-01" || r.Restore("host-12345678") != "host-12345678" {
+		t.Error("a nil redactor should leave text as it is")
+	}
+}
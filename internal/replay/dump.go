@@ -0,0 +1,131 @@
+// Package replay feeds a recorded alert dump through the alert pipeline (enrichment,
+// severity rules, flap detection, correlation, AI root cause and incident prediction)
+// and reports what it produced, optionally scored against labeled ground truth. It is
+// used to validate changes to correlation and AI heuristics before deploying them.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// Record is one alert of a dump. Dumps are JSON lines, one record per line.
+type Record struct {
+	ID           string            `json:"id"`
+	ExternalID   uint64            `json:"external_id,omitempty"`
+	Host         string            `json:"host"`
+	Chart        string            `json:"chart"`
+	Family       string            `json:"family,omitempty"`
+	Name         string            `json:"name"`
+	Status       string            `json:"status"`
+	OldStatus    string            `json:"old_status,omitempty"`
+	Value        float64           `json:"value"`
+	OccurredAt   time.Time         `json:"occurred_at"`
+	Description  string            `json:"description,omitempty"`
+	ResourceType string            `json:"resource_type,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Source       string            `json:"source,omitempty"`
+}
+
+// NewRecord converts an alert to its dump record
+func NewRecord(alert domain.Alert) Record {
+	return Record{
+		ID:           alert.ID,
+		ExternalID:   alert.ExternalID,
+		Host:         alert.Host,
+		Chart:        alert.Chart,
+		Family:       alert.Family,
+		Name:         alert.Name,
+		Status:       string(alert.Status),
+		OldStatus:    string(alert.OldStatus),
+		Value:        alert.Value,
+		OccurredAt:   alert.OccurredAt,
+		Description:  alert.Description,
+		ResourceType: string(alert.ResourceType),
+		Labels:       alert.Labels,
+		Source:       alert.Source,
+	}
+}
+
+// Alert converts the record back to an alert
+func (r Record) Alert() domain.Alert {
+	return domain.Alert{
+		ID:           r.ID,
+		ExternalID:   r.ExternalID,
+		Host:         r.Host,
+		Chart:        r.Chart,
+		Family:       r.Family,
+		Name:         r.Name,
+		Status:       domain.AlertStatus(r.Status),
+		OldStatus:    domain.AlertStatus(r.OldStatus),
+		Value:        r.Value,
+		OccurredAt:   r.OccurredAt,
+		Description:  r.Description,
+		ResourceType: domain.ResourceType(r.ResourceType),
+		Labels:       r.Labels,
+		Source:       r.Source,
+	}
+}
+
+// ReadDump reads a dump and returns its alerts in time order. Blank lines are skipped.
+// Alert IDs must be unique, since ground truth refers to alerts by ID.
+func ReadDump(r io.Reader) ([]domain.Alert, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var alerts []domain.Alert
+	seen := make(map[string]bool)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if record.ID == "" || record.Host == "" || record.OccurredAt.IsZero() {
+			return nil, fmt.Errorf("line %d: alert needs an id, a host and occurred_at", line)
+		}
+		if seen[record.ID] {
+			return nil, fmt.Errorf("line %d: duplicate alert ID %q", line, record.ID)
+		}
+		seen[record.ID] = true
+		alerts = append(alerts, record.Alert())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].OccurredAt.Before(alerts[j].OccurredAt)
+	})
+	return alerts, nil
+}
+
+// WriteDump writes alerts as a dump in time order
+func WriteDump(w io.Writer, alerts []domain.Alert) error {
+	sorted := make([]domain.Alert, len(alerts))
+	copy(sorted, alerts)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].OccurredAt.Before(sorted[j].OccurredAt)
+	})
+
+	encoder := json.NewEncoder(w)
+	for _, alert := range sorted {
+		if err := encoder.Encode(NewRecord(alert)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,388 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"incident-teller/internal/adapters/repository"
+	"incident-teller/internal/ai"
+	"incident-teller/internal/calendar"
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+	"incident-teller/internal/enrichment"
+	"incident-teller/internal/environment"
+	"incident-teller/internal/services"
+	"incident-teller/internal/severity"
+	"incident-teller/internal/topology"
+)
+
+// DefaultBatchInterval is how much recorded time one replayed batch spans by default
+const DefaultBatchInterval = 10 * time.Second
+
+// Options control how a dump is replayed
+type Options struct {
+	// Speed scales the recorded time between batches: 1 replays in real time, 60 a minute
+	// per second. 0 replays as fast as possible.
+	Speed float64
+	// BatchInterval is the recorded time one batch spans, like the poll interval of a
+	// live source
+	BatchInterval time.Duration
+}
+
+// Engine runs the alert pipeline over recorded alerts in an isolated in-memory store
+type Engine struct {
+	builder         *services.IncidentBuilder
+	enrichment      *enrichment.Pipeline
+	severity        *severity.Mapper
+	flaps           *services.FlapDetector
+	excludeFlapping bool
+	suppressor      *services.DependencySuppressor
+	model           ai.AIModel
+	learner         *services.PropagationLearner
+	learnInterval   time.Duration
+	prediction      *predictionConfig
+}
+
+type predictionConfig struct {
+	horizon       time.Duration
+	minConfidence float64
+	lookback      time.Duration
+}
+
+// NewEngine creates an engine correlating alerts with builder. The other stages are
+// off until set.
+func NewEngine(builder *services.IncidentBuilder) *Engine {
+	return &Engine{builder: builder}
+}
+
+// FromConfig creates an engine with the pipeline the configuration sets up for live
+// alerts. Flap detection and learned propagation patterns start from scratch.
+func FromConfig(cfg *config.Config) (*Engine, error) {
+	serviceTopology, err := topology.FromConfig(cfg.Topology)
+	if err != nil {
+		return nil, fmt.Errorf("invalid topology: %w", err)
+	}
+	correlation, err := services.NewCorrelationStrategy(
+		cfg.Incident.CorrelationStrategy,
+		cfg.Incident.CorrelationLabels,
+		cfg.Incident.GroupBy,
+		serviceTopology,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure correlation: %w", err)
+	}
+	environments, err := environment.FromConfig(cfg.Environments)
+	if err != nil {
+		return nil, fmt.Errorf("invalid environments: %w", err)
+	}
+	if environments != nil {
+		correlation = services.ScopeByEnvironment(correlation, environments.Of)
+	}
+	builder := services.NewIncidentBuilder(cfg.Incident.CorrelationWindow)
+	builder.SetStrategy(correlation)
+	if cfg.Incident.StormThreshold > 0 {
+		builder.SetStormDetector(services.NewStormDetector(cfg.Incident.StormThreshold, cfg.Incident.StormWindow, cfg.Incident.StormCooldown))
+	}
+	engine := NewEngine(builder)
+
+	if engine.enrichment, err = enrichment.FromConfig(cfg.Enrichment); err != nil {
+		return nil, fmt.Errorf("invalid enrichment config: %w", err)
+	}
+	if environments != nil {
+		engine.enrichment = engine.enrichment.With(environments)
+	}
+	if engine.severity, err = severity.FromConfig(cfg.Severity); err != nil {
+		return nil, fmt.Errorf("invalid severity rules: %w", err)
+	}
+	if cfg.Flapping.Enabled {
+		engine.SetFlapDetector(services.NewFlapDetector(cfg.Flapping.Window, cfg.Flapping.Threshold), cfg.Flapping.ExcludeFromIncidents)
+	}
+	if cfg.Suppression.Enabled {
+		engine.SetDependencySuppressor(services.NewDependencySuppressor(cfg.Suppression.RootAlerts))
+	}
+
+	var learner *services.PropagationLearner
+	if cfg.AI.EnableLearning {
+		learner = services.NewPropagationLearner(cfg.AI.LearningWindow, cfg.AI.LearningMinObservations, cfg.AI.LearningLookback)
+		learner.SetTopology(serviceTopology)
+		engine.SetPropagationLearner(learner, cfg.AI.LearningInterval)
+	}
+	if cfg.AI.Enabled {
+		businessCalendar, err := calendar.FromConfig(cfg.Calendar)
+		if err != nil {
+			return nil, fmt.Errorf("invalid business calendar: %w", err)
+		}
+		versions, err := ai.LoadModelVersions(cfg.AI.ModelPath)
+		if err != nil {
+			return nil, err
+		}
+		weights, ok := ai.FindModelVersion(versions, cfg.AI.ModelVersion)
+		if !ok {
+			return nil, fmt.Errorf("unknown AI model version %s", cfg.AI.ModelVersion)
+		}
+		model := ai.NewLocalAIModel()
+		model.SetCalendar(businessCalendar)
+		model.SetWeights(weights)
+		if learner != nil {
+			model.SetPropagationModel(learner)
+		}
+		engine.SetAIModel(model)
+	}
+	if cfg.Prediction.Enabled {
+		engine.SetPrediction(cfg.Prediction.Horizon, cfg.Prediction.MinConfidence, cfg.Prediction.Lookback)
+	}
+	return engine, nil
+}
+
+// SetEnrichment sets the pipeline that labels alerts before they are stored
+func (e *Engine) SetEnrichment(pipeline *enrichment.Pipeline) {
+	e.enrichment = pipeline
+}
+
+// SetSeverityMapper sets the rules that remap alert statuses before they are stored
+func (e *Engine) SetSeverityMapper(mapper *severity.Mapper) {
+	e.severity = mapper
+}
+
+// SetFlapDetector labels flapping alerts; exclude keeps them out of incidents
+func (e *Engine) SetFlapDetector(detector *services.FlapDetector, exclude bool) {
+	e.flaps = detector
+	e.excludeFlapping = exclude
+}
+
+// SetDependencySuppressor labels the alerts of unreachable hosts as suppressed
+func (e *Engine) SetDependencySuppressor(suppressor *services.DependencySuppressor) {
+	e.suppressor = suppressor
+}
+
+// SetAIModel predicts the root cause of every replayed incident
+func (e *Engine) SetAIModel(model ai.AIModel) {
+	e.model = model
+}
+
+// SetPropagationLearner relearns propagation patterns from the replayed alerts every
+// interval of recorded time
+func (e *Engine) SetPropagationLearner(learner *services.PropagationLearner, interval time.Duration) {
+	e.learner = learner
+	e.learnInterval = interval
+}
+
+// SetPrediction evaluates incident predictions after every batch
+func (e *Engine) SetPrediction(horizon time.Duration, minConfidence float64, lookback time.Duration) {
+	e.prediction = &predictionConfig{horizon: horizon, minConfidence: minConfidence, lookback: lookback}
+}
+
+// Result is what a replay produced
+type Result struct {
+	Alerts      int                `json:"alerts"`
+	Batches     int                `json:"batches"`
+	Start       time.Time          `json:"start"` // Recorded time of the first alert
+	End         time.Time          `json:"end"`   // Recorded time of the last alert
+	Flapping    int                `json:"flapping"`
+	Incidents   []IncidentResult   `json:"incidents"`
+	Predictions []PredictionResult `json:"predictions"`
+}
+
+// IncidentResult is an incident the pipeline built
+type IncidentResult struct {
+	ID        string           `json:"id"`
+	Title     string           `json:"title"`
+	Status    string           `json:"status"`
+	StartedAt time.Time        `json:"started_at"`
+	RiskLevel string           `json:"risk_level"`
+	Hosts     []string         `json:"hosts"`
+	AlertIDs  []string         `json:"alert_ids"`
+	RootCause *RootCauseResult `json:"root_cause,omitempty"`
+}
+
+// RootCauseResult is the AI model's root cause of an incident
+type RootCauseResult struct {
+	AlertID     string  `json:"alert_id"`
+	Host        string  `json:"host"`
+	Chart       string  `json:"chart"`
+	Name        string  `json:"name"`
+	Confidence  float64 `json:"confidence"`
+	PatternType string  `json:"pattern_type"`
+}
+
+// PredictionResult is an incident prediction, recorded when a host is first predicted
+type PredictionResult struct {
+	Host        string    `json:"host"`
+	PredictedAt time.Time `json:"predicted_at"`
+	ExpectedAt  time.Time `json:"expected_at"`
+	Confidence  float64   `json:"confidence"`
+	Reasons     []string  `json:"reasons"`
+}
+
+// Run replays the alerts, which must be in time order, and returns what the pipeline
+// produced. It stops early with ctx's error if ctx is cancelled.
+func (e *Engine) Run(ctx context.Context, alerts []domain.Alert, opts Options) (*Result, error) {
+	if opts.BatchInterval <= 0 {
+		opts.BatchInterval = DefaultBatchInterval
+	}
+	if opts.Speed < 0 {
+		return nil, fmt.Errorf("speed must not be negative")
+	}
+
+	repo := repository.NewInMemoryRepository()
+	var predictor *services.PredictionService
+	if e.prediction != nil {
+		predictor = services.NewPredictionService(repo, e.prediction.horizon, e.prediction.minConfidence, e.prediction.lookback)
+		predictor.SetAIModel(e.model)
+		if e.learner != nil {
+			predictor.SetPropagationLearner(e.learner)
+		}
+	}
+
+	result := &Result{Alerts: len(alerts), Incidents: []IncidentResult{}, Predictions: []PredictionResult{}}
+	if len(alerts) == 0 {
+		return result, nil
+	}
+	result.Start = alerts[0].OccurredAt
+	result.End = alerts[len(alerts)-1].OccurredAt
+
+	incidents := make(map[string]IncidentResult)
+	predicted := make(map[string]bool) // Hosts predicted by the previous evaluation
+	var learnedAt time.Time
+	replayStart := time.Now()
+
+	for start := 0; start < len(alerts); {
+		// A batch holds the alerts recorded within one interval of its first alert
+		batchEnd := alerts[start].OccurredAt.Add(opts.BatchInterval)
+		end := start
+		for end < len(alerts) && alerts[end].OccurredAt.Before(batchEnd) {
+			end++
+		}
+		batch := make([]domain.Alert, end-start)
+		copy(batch, alerts[start:end])
+		start = end
+		now := batch[len(batch)-1].OccurredAt
+
+		if opts.Speed > 0 {
+			due := replayStart.Add(time.Duration(float64(now.Sub(result.Start)) / opts.Speed))
+			if err := sleepUntil(ctx, due); err != nil {
+				return result, err
+			}
+		} else if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		result.Batches++
+
+		batch, err := e.enrichment.Apply(ctx, batch)
+		if err != nil {
+			log.Printf("⚠️  Failed to enrich replayed alerts: %v", err)
+		}
+		batch = e.severity.ApplyAll(batch)
+		batch = e.flaps.Mark(batch)
+		batch = e.suppressor.Mark(batch)
+		if err := repo.SaveAlerts(ctx, batch); err != nil {
+			return result, fmt.Errorf("failed to save alerts: %w", err)
+		}
+
+		if e.flaps != nil && e.excludeFlapping {
+			correlated := services.WithoutFlapping(batch)
+			result.Flapping += len(batch) - len(correlated)
+			batch = correlated
+		}
+		for _, incident := range e.builder.Build(batch) {
+			if err := repo.SaveIncident(ctx, incident); err != nil {
+				return result, fmt.Errorf("failed to save incident: %w", err)
+			}
+			incidents[incident.ID] = e.incidentResult(ctx, incident)
+		}
+
+		if e.learner != nil && (learnedAt.IsZero() || now.Sub(learnedAt) >= e.learnInterval) {
+			history, err := repo.GetAlerts(ctx)
+			if err != nil {
+				return result, fmt.Errorf("failed to load alerts: %w", err)
+			}
+			e.learner.Learn(history, now)
+			learnedAt = now
+		}
+
+		if predictor != nil {
+			predictions, err := predictor.Evaluate(ctx, now)
+			if err != nil {
+				return result, err
+			}
+			current := make(map[string]bool, len(predictions))
+			for _, prediction := range predictions {
+				current[prediction.Host] = true
+				if predicted[prediction.Host] {
+					continue
+				}
+				result.Predictions = append(result.Predictions, PredictionResult{
+					Host:        prediction.Host,
+					PredictedAt: prediction.PredictedAt,
+					ExpectedAt:  prediction.ExpectedAt,
+					Confidence:  prediction.Confidence,
+					Reasons:     prediction.Reasons,
+				})
+			}
+			predicted = current
+		}
+	}
+
+	for _, incident := range incidents {
+		result.Incidents = append(result.Incidents, incident)
+	}
+	sort.Slice(result.Incidents, func(i, j int) bool {
+		if !result.Incidents[i].StartedAt.Equal(result.Incidents[j].StartedAt) {
+			return result.Incidents[i].StartedAt.Before(result.Incidents[j].StartedAt)
+		}
+		return result.Incidents[i].ID < result.Incidents[j].ID
+	})
+	return result, nil
+}
+
+// incidentResult records an incident and the AI model's root cause of its alerts
+func (e *Engine) incidentResult(ctx context.Context, incident domain.Incident) IncidentResult {
+	result := IncidentResult{
+		ID:        incident.ID,
+		Title:     incident.Title,
+		Status:    string(incident.Status),
+		StartedAt: incident.StartedAt,
+		RiskLevel: incident.RiskLevel(),
+		Hosts:     incident.Hosts(),
+		AlertIDs:  make([]string, 0, len(incident.Events)),
+	}
+	for _, alert := range incident.Events {
+		result.AlertIDs = append(result.AlertIDs, alert.ID)
+	}
+
+	if e.model == nil {
+		return result
+	}
+	prediction, err := e.model.PredictRootCause(ctx, incident.Events)
+	if err != nil || prediction.PrimaryCause == nil {
+		return result
+	}
+	cause := prediction.PrimaryCause
+	result.RootCause = &RootCauseResult{
+		AlertID:     cause.ID,
+		Host:        cause.Host,
+		Chart:       cause.Chart,
+		Name:        cause.Name,
+		Confidence:  prediction.Confidence,
+		PatternType: prediction.PatternType,
+	}
+	return result
+}
+
+func sleepUntil(ctx context.Context, due time.Time) error {
+	wait := time.Until(due)
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
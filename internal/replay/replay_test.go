@@ -0,0 +1,102 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"incident-teller/internal/ai"
+	"incident-teller/internal/domain"
+	"incident-teller/internal/services"
+)
+
+func replayAlerts() []domain.Alert {
+	start := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	alert := func(id string, after time.Duration, host, chart, name string, status domain.AlertStatus, resource domain.ResourceType) domain.Alert {
+		return domain.Alert{
+			ID: id, Host: host, Chart: chart, Name: name, Status: status,
+			OccurredAt: start.Add(after), ResourceType: resource,
+		}
+	}
+	return []domain.Alert{
+		alert("a1", 0, "db-01", "mem.available", "ram_in_use", domain.StatusWarning, domain.ResourceMemory),
+		alert("a2", 20*time.Second, "db-01", "mem.available", "ram_in_use", domain.StatusCritical, domain.ResourceMemory),
+		alert("a3", 40*time.Second, "db-01", "disk.io", "disk_backlog", domain.StatusCritical, domain.ResourceDisk),
+		alert("a4", 2*time.Hour, "web-01", "system.cpu", "cpu_usage", domain.StatusCritical, domain.ResourceCPU),
+		alert("a5", 2*time.Hour+30*time.Second, "web-01", "system.cpu", "cpu_usage", domain.StatusClear, domain.ResourceCPU),
+	}
+}
+
+func TestDump_RoundTrip(t *testing.T) {
+	alerts := replayAlerts()
+	alerts[0].Labels = map[string]string{"team": "storage"}
+
+	var buf bytes.Buffer
+	if err := WriteDump(&buf, alerts); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadDump(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != len(alerts) || read[0].ID != "a1" || read[0].Labels["team"] != "storage" ||
+		read[4].Status != domain.StatusClear || !read[2].OccurredAt.Equal(alerts[2].OccurredAt) {
+		t.Errorf("unexpected alerts after round trip: %+v", read)
+	}
+
+	if _, err := ReadDump(bytes.NewBufferString(`{"id":"a1","host":"h","occurred_at":"2024-03-04T10:00:00Z"}` + "\n" +
+		`{"id":"a1","host":"h","occurred_at":"2024-03-04T10:01:00Z"}`)); err == nil {
+		t.Error("expected duplicate alert IDs to be rejected")
+	}
+}
+
+func TestEngine_RunAndCompare(t *testing.T) {
+	alerts := replayAlerts()
+	engine := NewEngine(services.NewIncidentBuilder(5 * time.Minute))
+	engine.SetAIModel(ai.NewLocalAIModel())
+
+	result, err := engine.Run(context.Background(), alerts, Options{BatchInterval: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Batches != 2 || len(result.Incidents) != 2 {
+		t.Fatalf("expected 2 batches and 2 incidents, got %d and %+v", result.Batches, result.Incidents)
+	}
+	if got := result.Incidents[0].AlertIDs; len(got) != 3 || result.Incidents[0].RootCause == nil {
+		t.Errorf("expected the db-01 alerts in one incident with a root cause, got %+v", result.Incidents[0])
+	}
+
+	// The labels split db-01 into two incidents, so the replay over-groups it
+	truth := &GroundTruth{Incidents: []ExpectedIncident{
+		{Name: "db-memory", AlertIDs: []string{"a1", "a2"}, RootCause: "a1"},
+		{Name: "db-disk", AlertIDs: []string{"a3"}},
+		{Name: "web-cpu", AlertIDs: []string{"a4", "a5"}, RootCause: "a4"},
+	}}
+	report := Compare(result, alerts, truth)
+	if report.Expected != 3 || report.Replayed != 2 || report.ExactMatches != 1 {
+		t.Errorf("unexpected incident counts: %+v", report)
+	}
+	// Replayed pairs: 3 for db-01 and 1 for web-01, of which 2 are expected
+	if report.PairPrecision != 0.5 || report.PairRecall != 1 {
+		t.Errorf("expected precision 0.5 and recall 1, got %.2f and %.2f", report.PairPrecision, report.PairRecall)
+	}
+	if report.RootCauses != 2 || report.Incidents[0].ExtraAlerts != 1 || report.Incidents[2].RootCauseCorrect == nil {
+		t.Errorf("unexpected comparison: %+v", report)
+	}
+
+	var out bytes.Buffer
+	if err := WriteText(&out, result, &report); err != nil || !bytes.Contains(out.Bytes(), []byte("precision 0.50, recall 1.00")) {
+		t.Errorf("unexpected text report (%v):\n%s", err, out.String())
+	}
+}
+
+func TestEngine_RunStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	engine := NewEngine(services.NewIncidentBuilder(5 * time.Minute))
+	if _, err := engine.Run(ctx, replayAlerts(), Options{Speed: 1}); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,328 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// GroundTruth labels which recorded alerts belong to which real incident
+type GroundTruth struct {
+	Incidents []ExpectedIncident `json:"incidents"`
+}
+
+// ExpectedIncident is a labeled incident. RootCause is the ID of the alert that caused
+// it, if known.
+type ExpectedIncident struct {
+	Name      string   `json:"name"`
+	AlertIDs  []string `json:"alert_ids"`
+	RootCause string   `json:"root_cause,omitempty"`
+}
+
+// LoadGroundTruth reads ground truth from a JSON file
+func LoadGroundTruth(path string) (*GroundTruth, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var truth GroundTruth
+	if err := json.Unmarshal(data, &truth); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	labeled := make(map[string]string)
+	for i, expected := range truth.Incidents {
+		if expected.Name == "" || len(expected.AlertIDs) == 0 {
+			return nil, fmt.Errorf("incident %d needs a name and alert IDs", i+1)
+		}
+		for _, id := range expected.AlertIDs {
+			if other, ok := labeled[id]; ok {
+				return nil, fmt.Errorf("alert %q is labeled as both %q and %q", id, other, expected.Name)
+			}
+			labeled[id] = expected.Name
+		}
+	}
+	return &truth, nil
+}
+
+// Report compares a replay with ground truth.
+//
+// Grouping is scored over pairs of alerts: precision is the share of alert pairs the
+// replay put in one incident that belong to the same expected incident, recall the share
+// of pairs of an expected incident that the replay put in one incident. A root cause is
+// correct when it is the labeled alert or another alert of the same host, chart and name.
+// An expected incident was predicted when a prediction for one of its hosts came before
+// its first critical alert.
+type Report struct {
+	Expected      int     `json:"expected"`
+	Replayed      int     `json:"replayed"`
+	ExactMatches  int     `json:"exact_matches"`
+	PairPrecision float64 `json:"pair_precision"`
+	PairRecall    float64 `json:"pair_recall"`
+	PairF1        float64 `json:"pair_f1"`
+	RootCauses    int     `json:"root_causes"` // Expected incidents with a labeled root cause
+	RootCauseHits int     `json:"root_cause_hits"`
+	Critical      int     `json:"critical"` // Expected incidents that turned critical
+	Predicted     int     `json:"predicted"`
+	Predictions   int     `json:"predictions"`
+	Unconfirmed   int     `json:"unconfirmed"` // Predictions no expected incident followed
+
+	Incidents []IncidentComparison `json:"incidents"`
+}
+
+// IncidentComparison is how the replay handled one expected incident
+type IncidentComparison struct {
+	Name             string `json:"name"`
+	Alerts           int    `json:"alerts"`
+	MatchedIncident  string `json:"matched_incident,omitempty"` // Replayed incident holding most of its alerts
+	Overlap          int    `json:"overlap"`
+	SplitInto        int    `json:"split_into"`   // Replayed incidents holding its alerts
+	ExtraAlerts      int    `json:"extra_alerts"` // Other alerts in the matched incident
+	ExpectedCause    string `json:"expected_cause,omitempty"`
+	PredictedCause   string `json:"predicted_cause,omitempty"`
+	RootCauseCorrect *bool  `json:"root_cause_correct,omitempty"`
+	PredictionLead   string `json:"prediction_lead,omitempty"` // How long before it turned critical it was predicted
+}
+
+// Compare scores the replay of alerts against ground truth
+func Compare(result *Result, alerts []domain.Alert, truth *GroundTruth) Report {
+	byID := make(map[string]domain.Alert, len(alerts))
+	for _, alert := range alerts {
+		byID[alert.ID] = alert
+	}
+	replayedIn := make(map[string]string) // Alert ID -> replayed incident ID
+	replayed := make(map[string]IncidentResult, len(result.Incidents))
+	for _, incident := range result.Incidents {
+		replayed[incident.ID] = incident
+		for _, id := range incident.AlertIDs {
+			replayedIn[id] = incident.ID
+		}
+	}
+	expectedIn := make(map[string]string) // Alert ID -> expected incident name
+	for _, expected := range truth.Incidents {
+		for _, id := range expected.AlertIDs {
+			expectedIn[id] = expected.Name
+		}
+	}
+
+	report := Report{
+		Expected:    len(truth.Incidents),
+		Replayed:    len(result.Incidents),
+		Predictions: len(result.Predictions),
+		Incidents:   make([]IncidentComparison, 0, len(truth.Incidents)),
+	}
+	report.PairPrecision, report.PairRecall = pairScores(alerts, expectedIn, replayedIn)
+	if report.PairPrecision+report.PairRecall > 0 {
+		report.PairF1 = 2 * report.PairPrecision * report.PairRecall / (report.PairPrecision + report.PairRecall)
+	}
+
+	confirmed := make([]bool, len(result.Predictions))
+	for _, expected := range truth.Incidents {
+		comparison := IncidentComparison{Name: expected.Name, Alerts: len(expected.AlertIDs)}
+
+		counts := make(map[string]int)
+		for _, id := range expected.AlertIDs {
+			if incidentID, ok := replayedIn[id]; ok {
+				counts[incidentID]++
+			}
+		}
+		comparison.SplitInto = len(counts)
+		for incidentID, count := range counts {
+			if count > comparison.Overlap || (count == comparison.Overlap && incidentID < comparison.MatchedIncident) {
+				comparison.MatchedIncident, comparison.Overlap = incidentID, count
+			}
+		}
+		if matched, ok := replayed[comparison.MatchedIncident]; ok {
+			comparison.ExtraAlerts = len(matched.AlertIDs) - comparison.Overlap
+			if comparison.Overlap == len(expected.AlertIDs) && comparison.ExtraAlerts == 0 {
+				report.ExactMatches++
+			}
+		}
+
+		if expected.RootCause != "" {
+			report.RootCauses++
+			comparison.ExpectedCause = expected.RootCause
+			correct := false
+			if matched, ok := replayed[comparison.MatchedIncident]; ok && matched.RootCause != nil {
+				comparison.PredictedCause = matched.RootCause.AlertID
+				correct = sameStream(byID[expected.RootCause], byID[matched.RootCause.AlertID])
+			}
+			comparison.RootCauseCorrect = &correct
+			if correct {
+				report.RootCauseHits++
+			}
+		}
+
+		if critical, ok := firstCritical(expected.AlertIDs, byID); ok {
+			report.Critical++
+			var lead time.Duration
+			hosts := make(map[string]bool)
+			for _, id := range expected.AlertIDs {
+				hosts[byID[id].Host] = true
+			}
+			for i, prediction := range result.Predictions {
+				if !hosts[prediction.Host] || !prediction.PredictedAt.Before(critical) {
+					continue
+				}
+				confirmed[i] = true
+				lead = max(lead, critical.Sub(prediction.PredictedAt))
+			}
+			if lead > 0 {
+				comparison.PredictionLead = lead.Round(time.Second).String()
+				report.Predicted++
+			}
+		}
+
+		report.Incidents = append(report.Incidents, comparison)
+	}
+	for _, ok := range confirmed {
+		if !ok {
+			report.Unconfirmed++
+		}
+	}
+	return report
+}
+
+// pairScores returns the pair precision and recall of the replayed grouping. Alerts
+// outside any incident are groups of their own.
+func pairScores(alerts []domain.Alert, expectedIn, replayedIn map[string]string) (float64, float64) {
+	expectedSizes := make(map[string]int)
+	replayedSizes := make(map[string]int)
+	bothSizes := make(map[[2]string]int)
+	for _, alert := range alerts {
+		expected, replayed := expectedIn[alert.ID], replayedIn[alert.ID]
+		if expected != "" {
+			expectedSizes[expected]++
+		}
+		if replayed != "" {
+			replayedSizes[replayed]++
+		}
+		if expected != "" && replayed != "" {
+			bothSizes[[2]string{expected, replayed}]++
+		}
+	}
+
+	var expectedPairs, replayedPairs, bothPairs int
+	for _, n := range expectedSizes {
+		expectedPairs += pairs(n)
+	}
+	for _, n := range replayedSizes {
+		replayedPairs += pairs(n)
+	}
+	for _, n := range bothSizes {
+		bothPairs += pairs(n)
+	}
+
+	precision, recall := 1.0, 1.0
+	if replayedPairs > 0 {
+		precision = float64(bothPairs) / float64(replayedPairs)
+	}
+	if expectedPairs > 0 {
+		recall = float64(bothPairs) / float64(expectedPairs)
+	}
+	return precision, recall
+}
+
+func pairs(n int) int {
+	return n * (n - 1) / 2
+}
+
+// sameStream reports whether two alerts are the same alert of the same host
+func sameStream(a, b domain.Alert) bool {
+	return a.ID != "" && b.ID != "" && a.Host == b.Host && a.Chart == b.Chart && a.Name == b.Name
+}
+
+// firstCritical returns when the first critical alert of an expected incident occurred
+func firstCritical(ids []string, byID map[string]domain.Alert) (time.Time, bool) {
+	var first time.Time
+	for _, id := range ids {
+		alert, ok := byID[id]
+		if ok && alert.Status == domain.StatusCritical && (first.IsZero() || alert.OccurredAt.Before(first)) {
+			first = alert.OccurredAt
+		}
+	}
+	return first, !first.IsZero()
+}
+
+// WriteText writes a human-readable summary of a replay and, if report is non-nil, its
+// comparison with ground truth
+func WriteText(w io.Writer, result *Result, report *Report) error {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "Replayed %d alerts in %d batches (%s to %s)\n",
+		result.Alerts, result.Batches, result.Start.Format(time.RFC3339), result.End.Format(time.RFC3339))
+	if result.Flapping > 0 {
+		fmt.Fprintf(&out, "Kept %d flapping alerts out of incidents\n", result.Flapping)
+	}
+
+	fmt.Fprintf(&out, "\nIncidents (%d)\n", len(result.Incidents))
+	for _, incident := range result.Incidents {
+		fmt.Fprintf(&out, "  %s  %-8s %-8s %3d alerts  %s\n", incident.StartedAt.Format(time.RFC3339),
+			incident.Status, incident.RiskLevel, len(incident.AlertIDs), strings.Join(incident.Hosts, ","))
+		if rc := incident.RootCause; rc != nil {
+			fmt.Fprintf(&out, "      root cause: %s %s/%s on %s (%.0f%%, %s)\n",
+				rc.AlertID, rc.Chart, rc.Name, rc.Host, rc.Confidence*100, rc.PatternType)
+		}
+	}
+
+	fmt.Fprintf(&out, "\nPredictions (%d)\n", len(result.Predictions))
+	for _, prediction := range result.Predictions {
+		fmt.Fprintf(&out, "  %s  %s expected at %s (%.0f%%)\n", prediction.PredictedAt.Format(time.RFC3339),
+			prediction.Host, prediction.ExpectedAt.Format(time.RFC3339), prediction.Confidence*100)
+	}
+
+	if report != nil {
+		fmt.Fprintf(&out, "\nGround truth\n")
+		fmt.Fprintf(&out, "  incidents: %d expected, %d replayed, %d matched exactly\n",
+			report.Expected, report.Replayed, report.ExactMatches)
+		fmt.Fprintf(&out, "  grouping: precision %.2f, recall %.2f, F1 %.2f\n",
+			report.PairPrecision, report.PairRecall, report.PairF1)
+		if report.RootCauses > 0 {
+			fmt.Fprintf(&out, "  root cause: %d/%d correct\n", report.RootCauseHits, report.RootCauses)
+		}
+		fmt.Fprintf(&out, "  prediction: %d/%d critical incidents predicted, %d/%d predictions unconfirmed\n",
+			report.Predicted, report.Critical, report.Unconfirmed, report.Predictions)
+
+		incidents := make([]IncidentComparison, len(report.Incidents))
+		copy(incidents, report.Incidents)
+		sort.SliceStable(incidents, func(i, j int) bool {
+			return incidents[i].Name < incidents[j].Name
+		})
+		for _, c := range incidents {
+			fmt.Fprintf(&out, "  %-24s %d/%d alerts in %s", c.Name, c.Overlap, c.Alerts, orNone(c.MatchedIncident))
+			if c.SplitInto > 1 {
+				fmt.Fprintf(&out, ", split into %d", c.SplitInto)
+			}
+			if c.ExtraAlerts > 0 {
+				fmt.Fprintf(&out, ", %d extra", c.ExtraAlerts)
+			}
+			if c.RootCauseCorrect != nil {
+				verdict := "wrong"
+				if *c.RootCauseCorrect {
+					verdict = "correct"
+				}
+				fmt.Fprintf(&out, ", root cause %s", verdict)
+			}
+			if c.PredictionLead != "" {
+				fmt.Fprintf(&out, ", predicted %s ahead", c.PredictionLead)
+			}
+			out.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,113 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Discord embed limits
+const (
+	discordMaxTitle       = 256
+	discordMaxDescription = 4096
+	discordMaxFields      = 25
+	discordMaxFieldName   = 256
+	discordMaxFieldValue  = 1024
+	discordMaxFooter      = 2048
+)
+
+// Discord renders documents as a Discord webhook message with a single embed.
+// Severity colors the embed stripe.
+type Discord struct {
+	Severity Severity
+}
+
+// Format returns "discord"
+func (Discord) Format() Format {
+	return FormatDiscord
+}
+
+// ContentType returns the JSON MIME type
+func (Discord) ContentType() string {
+	return "application/json"
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color,omitempty"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Footer      *struct {
+		Text string `json:"text"`
+	} `json:"footer,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// Render renders the document as an embed. Labelled fields become inline embed fields;
+// other blocks of untitled sections go into the description and those of titled
+// sections into a field named after the heading.
+func (d Discord) Render(doc Document) string {
+	embed := discordEmbed{
+		Title:     truncate(doc.Title, discordMaxTitle),
+		Color:     d.Severity.Color,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	var description []string
+	for _, section := range doc.Sections {
+		var text strings.Builder
+		for _, block := range section.Blocks {
+			if fields, ok := block.(Fields); ok {
+				for _, f := range fields {
+					embed.Fields = append(embed.Fields, discordField{
+						Name:   truncate(f.Label, discordMaxFieldName),
+						Value:  truncate(nonEmpty(f.Value), discordMaxFieldValue),
+						Inline: true,
+					})
+				}
+				continue
+			}
+			writeMarkdownBlock(&text, block)
+		}
+
+		content := strings.TrimSpace(text.String())
+		if content == "" {
+			continue
+		}
+		if heading := section.heading(); heading != "" {
+			embed.Fields = append(embed.Fields, discordField{
+				Name:  truncate(heading, discordMaxFieldName),
+				Value: truncate(content, discordMaxFieldValue),
+			})
+		} else {
+			description = append(description, content)
+		}
+	}
+
+	embed.Description = truncate(strings.Join(description, "\n\n"), discordMaxDescription)
+	if len(embed.Fields) > discordMaxFields {
+		embed.Fields = embed.Fields[:discordMaxFields]
+	}
+	if doc.Footer != "" {
+		embed.Footer = &struct {
+			Text string `json:"text"`
+		}{Text: truncate(doc.Footer, discordMaxFooter)}
+	}
+
+	payload, _ := json.Marshal(map[string]interface{}{"embeds": []discordEmbed{embed}})
+	return string(payload)
+}
+
+// nonEmpty replaces an empty value, which Discord rejects, with a dash
+func nonEmpty(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,76 @@
+package report
+
+import (
+	"html"
+	"strings"
+)
+
+// HTML renders documents as a self-contained HTML fragment, e.g. for email and dashboards
+type HTML struct{}
+
+// Format returns "html"
+func (HTML) Format() Format {
+	return FormatHTML
+}
+
+// ContentType returns the HTML MIME type
+func (HTML) ContentType() string {
+	return "text/html; charset=utf-8"
+}
+
+// Render renders the document as an <article> element. All text is escaped.
+func (HTML) Render(doc Document) string {
+	var out strings.Builder
+
+	out.WriteString(`<article class="incident-report">` + "\n")
+	if doc.Title != "" {
+		out.WriteString("<h1>" + html.EscapeString(doc.Title) + "</h1>\n")
+	}
+
+	for _, section := range doc.Sections {
+		out.WriteString("<section>\n")
+		if heading := section.heading(); heading != "" {
+			out.WriteString("<h2>" + html.EscapeString(heading) + "</h2>\n")
+		}
+		for _, block := range section.Blocks {
+			writeHTMLBlock(&out, block)
+		}
+		out.WriteString("</section>\n")
+	}
+
+	if doc.Footer != "" {
+		out.WriteString("<footer>" + html.EscapeString(doc.Footer) + "</footer>\n")
+	}
+	out.WriteString("</article>\n")
+
+	return out.String()
+}
+
+func writeHTMLBlock(out *strings.Builder, block Block) {
+	switch b := block.(type) {
+	case Paragraph:
+		text := html.EscapeString(b.Text)
+		out.WriteString("<p>" + strings.ReplaceAll(text, "\n", "<br>\n") + "</p>\n")
+
+	case Fields:
+		out.WriteString("<dl>\n")
+		for _, f := range b {
+			out.WriteString("<dt>" + html.EscapeString(f.Label) + "</dt><dd>" + html.EscapeString(f.Value) + "</dd>\n")
+		}
+		out.WriteString("</dl>\n")
+
+	case List:
+		if b.Title != "" {
+			out.WriteString("<h3>" + html.EscapeString(b.Title) + "</h3>\n")
+		}
+		tag := "ul"
+		if b.Ordered {
+			tag = "ol"
+		}
+		out.WriteString("<" + tag + ">\n")
+		for _, item := range b.Items {
+			out.WriteString("<li>" + html.EscapeString(item) + "</li>\n")
+		}
+		out.WriteString("</" + tag + ">\n")
+	}
+}
@@ -0,0 +1,69 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown renders documents as GitHub-flavored Markdown, e.g. for postmortems and tickets
+type Markdown struct{}
+
+// Format returns "markdown"
+func (Markdown) Format() Format {
+	return FormatMarkdown
+}
+
+// ContentType returns the Markdown MIME type
+func (Markdown) ContentType() string {
+	return "text/markdown; charset=utf-8"
+}
+
+// Render renders the document as Markdown
+func (Markdown) Render(doc Document) string {
+	var out strings.Builder
+
+	if doc.Title != "" {
+		out.WriteString("# " + doc.Title + "\n\n")
+	}
+
+	for _, section := range doc.Sections {
+		if heading := section.heading(); heading != "" {
+			out.WriteString("## " + heading + "\n\n")
+		}
+		for _, block := range section.Blocks {
+			writeMarkdownBlock(&out, block)
+			out.WriteString("\n")
+		}
+	}
+
+	if doc.Footer != "" {
+		out.WriteString("---\n\n_" + doc.Footer + "_\n")
+	}
+
+	return out.String()
+}
+
+func writeMarkdownBlock(out *strings.Builder, block Block) {
+	switch b := block.(type) {
+	case Paragraph:
+		// Two trailing spaces keep single line breaks
+		out.WriteString(strings.ReplaceAll(b.Text, "\n", "  \n") + "\n")
+
+	case Fields:
+		for _, f := range b {
+			fmt.Fprintf(out, "- **%s:** %s\n", f.Label, f.Value)
+		}
+
+	case List:
+		if b.Title != "" {
+			out.WriteString("**" + b.Title + "**\n\n")
+		}
+		for i, item := range b.Items {
+			if b.Ordered {
+				fmt.Fprintf(out, "%d. %s\n", i+1, item)
+			} else {
+				out.WriteString("- " + item + "\n")
+			}
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// A4 page layout in points
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 56.0
+	pdfBodySize   = 10.0
+)
+
+// PDF renders documents as a paginated A4 PDF. It uses the standard Helvetica fonts, so
+// no fonts are embedded; characters outside the Windows-1252 set, e.g. emoji, are left out.
+type PDF struct{}
+
+// Format returns "pdf"
+func (PDF) Format() Format {
+	return FormatPDF
+}
+
+// ContentType returns the PDF MIME type
+func (PDF) ContentType() string {
+	return "application/pdf"
+}
+
+// pdfLine is a line of text laid out on a page. Text is Windows-1252 encoded.
+type pdfLine struct {
+	label  string // Bold prefix, e.g. a field label
+	text   string
+	size   float64
+	bold   bool
+	indent float64
+	space  float64 // Extra space above the line
+}
+
+// Render renders the document as PDF
+func (PDF) Render(doc Document) string {
+	var lines []pdfLine
+	if doc.Title != "" {
+		lines = append(lines, wrapPDF(pdfLine{size: 18, bold: true}, doc.Title, 0)...)
+	}
+	for _, section := range doc.Sections {
+		if heading := section.heading(); heading != "" {
+			lines = append(lines, wrapPDF(pdfLine{size: 13, bold: true, space: 14}, heading, 0)...)
+		}
+		for _, block := range section.Blocks {
+			lines = append(lines, pdfBlockLines(block)...)
+		}
+	}
+	if doc.Footer != "" {
+		lines = append(lines, wrapPDF(pdfLine{size: 8, space: 18}, doc.Footer, 0)...)
+	}
+	return writePDF(paginatePDF(lines))
+}
+
+// pdfBlockLines lays out a block as body text
+func pdfBlockLines(block Block) []pdfLine {
+	var lines []pdfLine
+	switch b := block.(type) {
+	case Paragraph:
+		for i, text := range strings.Split(b.Text, "\n") {
+			line := pdfLine{size: pdfBodySize}
+			if i == 0 {
+				line.space = 4
+			}
+			lines = append(lines, wrapPDF(line, text, 0)...)
+		}
+
+	case Fields:
+		for i, f := range b {
+			line := pdfLine{label: pdfText(f.Label) + ": ", size: pdfBodySize}
+			if i == 0 {
+				line.space = 4
+			}
+			lines = append(lines, wrapPDF(line, f.Value, 12)...)
+		}
+
+	case List:
+		if b.Title != "" {
+			lines = append(lines, wrapPDF(pdfLine{size: pdfBodySize, bold: true, space: 4}, b.Title, 0)...)
+		}
+		for i, item := range b.Items {
+			marker := "\x95 " // Bullet in Windows-1252
+			if b.Ordered {
+				marker = fmt.Sprintf("%d. ", i+1)
+			} else if b.Marker != "" {
+				marker = pdfText(b.Marker) + " "
+			}
+			line := pdfLine{label: marker, size: pdfBodySize, indent: 12}
+			if i == 0 && b.Title == "" {
+				line.space = 4
+			}
+			lines = append(lines, wrapPDF(line, item, 12)...)
+		}
+	}
+	return lines
+}
+
+// wrapPDF breaks text into lines fitting the page width. Continuation lines are indented
+// by hanging past the first line's indent.
+func wrapPDF(first pdfLine, text string, hanging float64) []pdfLine {
+	width := pdfPageWidth - 2*pdfMargin - first.indent
+	words := strings.Fields(pdfText(text))
+
+	var lines []pdfLine
+	line := first
+	used := pdfTextWidth(line.label, line.size, true)
+	for _, word := range words {
+		w := pdfTextWidth(word, line.size, line.bold)
+		if line.text != "" {
+			if used+pdfTextWidth(" ", line.size, line.bold)+w > width {
+				lines = append(lines, line)
+				line = pdfLine{size: first.size, bold: first.bold, indent: first.indent + hanging}
+				used = hanging
+			} else {
+				line.text += " "
+				used += pdfTextWidth(" ", line.size, line.bold)
+			}
+		}
+		line.text += word
+		used += w
+	}
+	return append(lines, line)
+}
+
+// paginatePDF distributes lines over pages
+func paginatePDF(lines []pdfLine) [][]pdfLine {
+	var pages [][]pdfLine
+	var page []pdfLine
+	y := pdfPageHeight - pdfMargin
+	for _, line := range lines {
+		height := line.space + line.size*1.4
+		if y-height < pdfMargin && len(page) > 0 {
+			pages = append(pages, page)
+			page = nil
+			y = pdfPageHeight - pdfMargin
+			line.space = 0
+			height = line.size * 1.4
+		}
+		page = append(page, line)
+		y -= height
+	}
+	return append(pages, page)
+}
+
+// writePDF writes the pages as a PDF file: catalog, page tree, the two fonts, then a page
+// object and a content stream per page, followed by the cross-reference table
+func writePDF(pages [][]pdfLine) string {
+	var out strings.Builder
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pages {
+		var content strings.Builder
+		y := pdfPageHeight - pdfMargin
+		for _, line := range page {
+			y -= line.space + line.size*1.4
+			font := "/F1"
+			if line.bold {
+				font = "/F2"
+			}
+			fmt.Fprintf(&content, "BT %.2f %.2f Td ", pdfMargin+line.indent, y)
+			if line.label != "" {
+				fmt.Fprintf(&content, "/F2 %.1f Tf (%s) Tj ", line.size, pdfEscape(line.label))
+			}
+			fmt.Fprintf(&content, "%s %.1f Tf (%s) Tj ET\n", font, line.size, pdfEscape(line.text))
+		}
+		if len(pages) > 1 {
+			fmt.Fprintf(&content, "BT %.2f %.2f Td /F1 8.0 Tf (Page %d of %d) Tj ET\n",
+				pdfPageWidth-pdfMargin-48, pdfMargin/2, i+1, len(pages))
+		}
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.String()
+}
+
+// winAnsi maps the characters of Windows-1252 outside Latin-1 to their byte
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// pdfText encodes s as Windows-1252, leaving out characters it lacks
+func pdfText(s string) string {
+	var out strings.Builder
+	for _, r := range s {
+		switch b, ok := winAnsi[r]; {
+		case ok:
+			out.WriteByte(b)
+		case r == '\t':
+			out.WriteByte(' ')
+		case r < 0x20 || r == 0x7f:
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			out.WriteByte(byte(r))
+		case unicode.IsSpace(r):
+			out.WriteByte(' ')
+		}
+	}
+	return strings.TrimLeft(out.String(), " ")
+}
+
+// pdfEscape escapes the delimiters of PDF string literals
+func pdfEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(s)
+}
+
+// helveticaWidths are the advance widths of the printable ASCII characters in Helvetica,
+// in thousandths of the font size
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space-/
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0-?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @-O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P-_
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // `-o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p-~
+}
+
+// pdfTextWidth approximates the width of Windows-1252 text in points. Bold text is about
+// 6% wider than regular text.
+func pdfTextWidth(s string, size float64, bold bool) float64 {
+	total := 0
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= 32 && c < 127 {
+			total += helveticaWidths[c-32]
+		} else {
+			total += 556
+		}
+	}
+	width := float64(total) * size / 1000
+	if bold {
+		width *= 1.06
+	}
+	return width
+}
@@ -0,0 +1,127 @@
+// Package report renders incident reports in several output formats.
+//
+// Report producers build a format-agnostic Document (title, sections, blocks);
+// a Renderer turns it into plain text, Markdown, HTML, PDF, Slack Block Kit, Teams
+// Adaptive Card or Discord embed JSON, so every report type is available in every format.
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Format identifies an output format
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+	FormatSlack    Format = "slack"   // Slack Block Kit JSON
+	FormatTeams    Format = "teams"   // Microsoft Teams Adaptive Card JSON
+	FormatDiscord  Format = "discord" // Discord embed JSON
+	FormatPDF      Format = "pdf"
+)
+
+// Document is a format-agnostic report
+type Document struct {
+	Title    string
+	Sections []Section
+	Footer   string
+}
+
+// Section is a titled part of a document. Heading may be empty for untitled sections.
+type Section struct {
+	Icon    string // Optional emoji shown before the heading
+	Heading string
+	Blocks  []Block
+}
+
+// Block is a piece of section content: Paragraph, Fields or List
+type Block interface {
+	block()
+}
+
+// Paragraph is free-form text; newlines are preserved
+type Paragraph struct {
+	Text string
+}
+
+// Field is a labelled value
+type Field struct {
+	Label string
+	Value string
+}
+
+// Fields is a group of labelled values
+type Fields []Field
+
+// List is an optionally titled list of items
+type List struct {
+	Title   string
+	Ordered bool
+	Marker  string // Bullet for unordered lists; defaults to "•"
+	Items   []string
+}
+
+func (Paragraph) block() {}
+func (Fields) block()    {}
+func (List) block()      {}
+
+// Renderer turns a document into a specific output format
+type Renderer interface {
+	// Format returns the output format
+	Format() Format
+	// ContentType returns the MIME type of rendered output
+	ContentType() string
+	// Render renders the document
+	Render(doc Document) string
+}
+
+// NewRenderer returns the renderer for a format name ("text", "markdown"/"md", "html",
+// "pdf", "slack", "teams", "discord")
+func NewRenderer(format string) (Renderer, error) {
+	switch Format(strings.ToLower(format)) {
+	case "", FormatText:
+		return Text{}, nil
+	case FormatMarkdown, "md":
+		return Markdown{}, nil
+	case FormatHTML:
+		return HTML{}, nil
+	case FormatPDF:
+		return PDF{}, nil
+	case FormatSlack:
+		return Slack{}, nil
+	case FormatTeams:
+		return Teams{}, nil
+	case FormatDiscord:
+		return Discord{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported report format: %s", format)
+	}
+}
+
+// Render renders the document in the named format
+func Render(format string, doc Document) (string, error) {
+	renderer, err := NewRenderer(format)
+	if err != nil {
+		return "", err
+	}
+	return renderer.Render(doc), nil
+}
+
+// heading joins the optional icon and the heading text
+func (s Section) heading() string {
+	if s.Icon == "" {
+		return s.Heading
+	}
+	return s.Icon + " " + s.Heading
+}
+
+// marker returns the bullet used for unordered list items
+func (l List) marker() string {
+	if l.Marker == "" {
+		return "•"
+	}
+	return l.Marker
+}
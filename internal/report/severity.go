@@ -0,0 +1,24 @@
+package report
+
+// Severity is how an impact score is presented in chat messages. Slack, Teams and
+// Discord output share it so an incident looks equally severe in every channel.
+type Severity struct {
+	Label  string // "CRITICAL", "HIGH", "MEDIUM" or "LOW"
+	Emoji  string
+	Color  int    // RGB accent color, e.g. the Discord embed stripe
+	Accent string // Adaptive Card text color: "attention", "warning" or "accent"
+}
+
+// SeverityForScore maps a 0-100 impact score onto its presentation
+func SeverityForScore(score int) Severity {
+	switch {
+	case score >= 80:
+		return Severity{Label: "CRITICAL", Emoji: "🚨", Color: 0xE01E5A, Accent: "attention"}
+	case score >= 60:
+		return Severity{Label: "HIGH", Emoji: "⚠️", Color: 0xF2994A, Accent: "warning"}
+	case score >= 40:
+		return Severity{Label: "MEDIUM", Emoji: "⚡", Color: 0xECB22E, Accent: "warning"}
+	default:
+		return Severity{Label: "LOW", Emoji: "ℹ️", Color: 0x36C5F0, Accent: "accent"}
+	}
+}
@@ -0,0 +1,202 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Slack Block Kit limits
+const (
+	slackMaxHeader  = 150
+	slackMaxText    = 3000
+	slackMaxFields  = 10
+	slackMaxBlocks  = 50
+	slackFieldLimit = 2000
+)
+
+// Slack renders documents as Slack Block Kit JSON ({"text": ..., "blocks": [...]}),
+// ready to be posted to chat.postMessage or an incoming webhook
+type Slack struct{}
+
+// Format returns "slack"
+func (Slack) Format() Format {
+	return FormatSlack
+}
+
+// ContentType returns the JSON MIME type
+func (Slack) ContentType() string {
+	return "application/json"
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+// Render renders the document as a Block Kit message. The plain "text" field carries
+// the mrkdwn rendering as notification fallback.
+func (s Slack) Render(doc Document) string {
+	return s.message(doc, "")
+}
+
+// RenderResponse renders the document as a slash-command response, visible only to the
+// requesting user ("ephemeral") or to the whole channel ("in_channel")
+func (s Slack) RenderResponse(doc Document, responseType string) string {
+	return s.message(doc, responseType)
+}
+
+func (s Slack) message(doc Document, responseType string) string {
+	var blocks []slackBlock
+
+	if doc.Title != "" {
+		blocks = append(blocks, slackBlock{
+			Type: "header",
+			Text: &slackText{Type: "plain_text", Text: truncate(doc.Title, slackMaxHeader)},
+		})
+	}
+
+	for i, section := range doc.Sections {
+		if i > 0 {
+			blocks = append(blocks, slackBlock{Type: "divider"})
+		}
+		if heading := section.heading(); heading != "" {
+			blocks = append(blocks, mrkdwnSection("*"+heading+"*"))
+		}
+		for _, block := range section.Blocks {
+			blocks = append(blocks, slackBlocks(block)...)
+		}
+	}
+
+	if doc.Footer != "" {
+		blocks = append(blocks, slackBlock{
+			Type:     "context",
+			Elements: []slackText{{Type: "mrkdwn", Text: truncate(doc.Footer, slackMaxText)}},
+		})
+	}
+
+	if len(blocks) > slackMaxBlocks {
+		blocks = blocks[:slackMaxBlocks]
+	}
+
+	payload, _ := json.Marshal(struct {
+		ResponseType string       `json:"response_type,omitempty"`
+		Text         string       `json:"text"`
+		Blocks       []slackBlock `json:"blocks"`
+	}{
+		ResponseType: responseType,
+		Text:         truncate(s.Mrkdwn(doc), slackMaxText),
+		Blocks:       blocks,
+	})
+	return string(payload)
+}
+
+// Mrkdwn renders the document as a single Slack mrkdwn message, for webhooks
+// that only take a "text" field
+func (Slack) Mrkdwn(doc Document) string {
+	var parts []string
+
+	if doc.Title != "" {
+		parts = append(parts, "*"+doc.Title+"*")
+	}
+
+	for _, section := range doc.Sections {
+		var lines []string
+		if heading := section.heading(); heading != "" {
+			lines = append(lines, "*"+heading+":*")
+		}
+		for _, block := range section.Blocks {
+			lines = append(lines, slackMrkdwnBlock(block))
+		}
+		parts = append(parts, strings.Join(lines, "\n"))
+	}
+
+	if doc.Footer != "" {
+		parts = append(parts, "_"+doc.Footer+"_")
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// slackBlocks converts a document block into one or more Block Kit blocks
+func slackBlocks(block Block) []slackBlock {
+	switch b := block.(type) {
+	case Fields:
+		// Section fields are limited, so long field lists span several sections
+		var result []slackBlock
+		for start := 0; start < len(b); start += slackMaxFields {
+			end := start + slackMaxFields
+			if end > len(b) {
+				end = len(b)
+			}
+			section := slackBlock{Type: "section"}
+			for _, f := range b[start:end] {
+				section.Fields = append(section.Fields, slackText{
+					Type: "mrkdwn",
+					Text: truncate(fmt.Sprintf("*%s:*\n%s", f.Label, f.Value), slackFieldLimit),
+				})
+			}
+			result = append(result, section)
+		}
+		return result
+
+	default:
+		text := slackMrkdwnBlock(block)
+		if text == "" {
+			// Slack rejects sections without text
+			return nil
+		}
+		return []slackBlock{mrkdwnSection(text)}
+	}
+}
+
+// slackMrkdwnBlock renders a block as mrkdwn text
+func slackMrkdwnBlock(block Block) string {
+	switch b := block.(type) {
+	case Paragraph:
+		return b.Text
+
+	case Fields:
+		lines := make([]string, len(b))
+		for i, f := range b {
+			lines[i] = fmt.Sprintf("*%s:* %s", f.Label, f.Value)
+		}
+		return strings.Join(lines, "\n")
+
+	case List:
+		var lines []string
+		if b.Title != "" {
+			lines = append(lines, "*"+b.Title+":*")
+		}
+		for i, item := range b.Items {
+			if b.Ordered {
+				lines = append(lines, fmt.Sprintf("%d. %s", i+1, item))
+			} else {
+				lines = append(lines, b.marker()+" "+item)
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	return ""
+}
+
+func mrkdwnSection(text string) slackBlock {
+	return slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: truncate(text, slackMaxText)}}
+}
+
+// truncate shortens s to at most max runes, marking the cut with an ellipsis
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:max-1]) + "…"
+}
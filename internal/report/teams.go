@@ -0,0 +1,122 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Teams renders documents as a Microsoft Teams message carrying an Adaptive Card,
+// ready to be posted to an incoming webhook. Severity colors the title.
+type Teams struct {
+	Severity Severity
+}
+
+// Format returns "teams"
+func (Teams) Format() Format {
+	return FormatTeams
+}
+
+// ContentType returns the JSON MIME type
+func (Teams) ContentType() string {
+	return "application/json"
+}
+
+type cardElement struct {
+	Type      string     `json:"type"`
+	Text      string     `json:"text,omitempty"`
+	Wrap      bool       `json:"wrap,omitempty"`
+	Size      string     `json:"size,omitempty"`
+	Weight    string     `json:"weight,omitempty"`
+	Color     string     `json:"color,omitempty"`
+	IsSubtle  bool       `json:"isSubtle,omitempty"`
+	Separator bool       `json:"separator,omitempty"`
+	Facts     []cardFact `json:"facts,omitempty"`
+}
+
+type cardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// Render renders the document as an Adaptive Card message. All card text is markdown.
+func (t Teams) Render(doc Document) string {
+	var body []cardElement
+
+	if doc.Title != "" {
+		body = append(body, cardElement{
+			Type: "TextBlock", Text: doc.Title, Wrap: true,
+			Size: "Large", Weight: "Bolder", Color: t.Severity.Accent,
+		})
+	}
+
+	for i, section := range doc.Sections {
+		if heading := section.heading(); heading != "" {
+			body = append(body, cardElement{
+				Type: "TextBlock", Text: heading, Wrap: true,
+				Weight: "Bolder", Separator: i > 0,
+			})
+		}
+		for _, block := range section.Blocks {
+			if element, ok := cardBlock(block); ok {
+				body = append(body, element)
+			}
+		}
+	}
+
+	if doc.Footer != "" {
+		body = append(body, cardElement{Type: "TextBlock", Text: doc.Footer, Wrap: true, Size: "Small", IsSubtle: true})
+	}
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]interface{}{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"msteams": map[string]string{"width": "Full"},
+				"body":    body,
+			},
+		}},
+	})
+	return string(payload)
+}
+
+// cardBlock converts a document block into an Adaptive Card element
+func cardBlock(block Block) (cardElement, bool) {
+	switch b := block.(type) {
+	case Paragraph:
+		if b.Text == "" {
+			return cardElement{}, false
+		}
+		// Adaptive Card markdown needs blank lines between paragraphs
+		return cardElement{Type: "TextBlock", Text: strings.ReplaceAll(b.Text, "\n", "\n\n"), Wrap: true}, true
+
+	case Fields:
+		facts := make([]cardFact, len(b))
+		for i, f := range b {
+			facts[i] = cardFact{Title: f.Label, Value: f.Value}
+		}
+		return cardElement{Type: "FactSet", Facts: facts}, true
+
+	case List:
+		var lines []string
+		if b.Title != "" {
+			lines = append(lines, "**"+b.Title+"**", "")
+		}
+		for i, item := range b.Items {
+			if b.Ordered {
+				lines = append(lines, fmt.Sprintf("%d. %s", i+1, item))
+			} else {
+				lines = append(lines, "- "+item)
+			}
+		}
+		if len(b.Items) == 0 {
+			return cardElement{}, false
+		}
+		return cardElement{Type: "TextBlock", Text: strings.Join(lines, "\n"), Wrap: true}, true
+	}
+	return cardElement{}, false
+}
@@ -0,0 +1,92 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// textWidth is the inner width of the title box and section rules
+const textWidth = 63
+
+// Text renders documents as plain text with box-drawing headers for terminals and logs
+type Text struct{}
+
+// Format returns "text"
+func (Text) Format() Format {
+	return FormatText
+}
+
+// ContentType returns the plain text MIME type
+func (Text) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+// Render renders the document as plain text
+func (Text) Render(doc Document) string {
+	var out strings.Builder
+
+	if doc.Title != "" {
+		out.WriteString("╔" + strings.Repeat("═", textWidth) + "╗\n")
+		out.WriteString("║" + centerText(doc.Title, textWidth) + "║\n")
+		out.WriteString("╚" + strings.Repeat("═", textWidth) + "╝\n\n")
+	}
+
+	for _, section := range doc.Sections {
+		if heading := section.heading(); heading != "" {
+			out.WriteString(heading + "\n")
+			out.WriteString(strings.Repeat("═", textWidth) + "\n")
+		}
+		for _, block := range section.Blocks {
+			writeTextBlock(&out, block)
+		}
+		out.WriteString("\n")
+	}
+
+	if doc.Footer != "" {
+		out.WriteString(strings.Repeat("─", textWidth) + "\n")
+		out.WriteString(doc.Footer + "\n")
+	}
+
+	return out.String()
+}
+
+func writeTextBlock(out *strings.Builder, block Block) {
+	switch b := block.(type) {
+	case Paragraph:
+		out.WriteString(b.Text + "\n")
+
+	case Fields:
+		width := 0
+		for _, f := range b {
+			if n := utf8.RuneCountInString(f.Label); n > width {
+				width = n
+			}
+		}
+		for _, f := range b {
+			fmt.Fprintf(out, "%-*s %s\n", width+1, f.Label+":", f.Value)
+		}
+
+	case List:
+		if b.Title != "" {
+			out.WriteString(b.Title + ":\n")
+		}
+		for i, item := range b.Items {
+			if b.Ordered {
+				fmt.Fprintf(out, "  %d. %s\n", i+1, item)
+			} else {
+				fmt.Fprintf(out, "  %s %s\n", b.marker(), item)
+			}
+		}
+	}
+}
+
+// centerText pads s with spaces to width, centered
+func centerText(s string, width int) string {
+	n := utf8.RuneCountInString(s)
+	if n >= width {
+		return s
+	}
+	left := (width - n) / 2
+	return strings.Repeat(" ", left) + s + strings.Repeat(" ", width-n-left)
+}
@@ -0,0 +1,56 @@
+package scenario
+
+import (
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+var builtin = []Scenario{
+	{
+		Name:        "memory-leak-cascade",
+		Description: "A PostgreSQL memory leak exhausts RAM, the host starts swapping and queries slow down until the API fails",
+		Steps: []Step{
+			{0, "db-primary-01", "apps.postgres.memory", "postgres", "postgres_memory_usage", domain.StatusWarning, 78.2, domain.ResourceMemory, "PostgreSQL memory climbing", nil},
+			{5 * time.Minute, "db-primary-01", "system.ram", "ram", "system_memory_critical", domain.StatusCritical, 94.5, domain.ResourceMemory, "System memory exhausted", nil},
+			{8 * time.Minute, "db-primary-01", "system.swap", "swap", "swap_usage_high", domain.StatusCritical, 88.7, domain.ResourceDisk, "Heavy swapping started", nil},
+			{10 * time.Minute, "db-primary-01", "system.cpu", "cpu", "cpu_iowait", domain.StatusCritical, 67.3, domain.ResourceCPU, "CPU stuck in iowait", nil},
+			{12 * time.Minute, "db-primary-01", "apps.postgres.latency", "postgres", "query_latency_high", domain.StatusWarning, 1250, domain.ResourceNetwork, "Query response time degraded", nil},
+			{14 * time.Minute, "app-server-01", "apps.api.errors", "api", "http_5xx_errors", domain.StatusCritical, 12.5, domain.ResourceProcess, "API returning 5xx errors", nil},
+		},
+	},
+	{
+		Name:        "disk-fill",
+		Description: "The root partition of a web server fills up, log writes fail and the API starts erroring",
+		Steps: []Step{
+			{0, "web-01", "disk_space._", "/", "disk_space_usage", domain.StatusWarning, 82.5, domain.ResourceDisk, "Root partition filling up", nil},
+			{3 * time.Minute, "web-01", "disk_space._", "/", "disk_space_usage", domain.StatusCritical, 95.8, domain.ResourceDisk, "Root partition nearly full", nil},
+			{4 * time.Minute, "web-01", "apps.logger", "logger", "log_write_errors", domain.StatusCritical, 100, domain.ResourceProcess, "Cannot write logs - disk full", nil},
+			{5 * time.Minute, "web-01", "apps.api.errors", "api", "api_failures", domain.StatusCritical, 45, domain.ResourceNetwork, "API error rate spiking", nil},
+		},
+	},
+	{
+		Name:        "network-partition",
+		Description: "A switch failure cuts the app tier off from the database: packet loss, connection timeouts and retry storms",
+		Steps: []Step{
+			{0, "app-server-01", "net.eth0", "eth0", "packet_loss", domain.StatusCritical, 35, domain.ResourceNetwork, "Packet loss to 10.0.2.0/24", nil},
+			{30 * time.Second, "app-server-02", "net.eth0", "eth0", "packet_loss", domain.StatusCritical, 38, domain.ResourceNetwork, "Packet loss to 10.0.2.0/24", nil},
+			{1 * time.Minute, "app-server-01", "apps.api.connections", "api", "connection_timeouts", domain.StatusCritical, 125, domain.ResourceNetwork, "Database connections timing out", nil},
+			{90 * time.Second, "app-server-02", "apps.api.connections", "api", "connection_timeouts", domain.StatusCritical, 140, domain.ResourceNetwork, "Database connections timing out", nil},
+			{2 * time.Minute, "app-server-01", "system.cpu", "cpu", "cpu_usage", domain.StatusWarning, 82, domain.ResourceCPU, "Retry storm burning CPU", nil},
+			{3 * time.Minute, "db-primary-01", "apps.postgres.connections", "postgres", "client_connections_dropped", domain.StatusWarning, 64, domain.ResourceNetwork, "Clients disconnected", nil},
+		},
+	},
+	{
+		Name:        "multi-host-outage",
+		Description: "A bad deploy exhausts memory across the web fleet; hosts go down one after another",
+		Steps: []Step{
+			{0, "web-01", "system.ram", "ram", "ram_in_use", domain.StatusWarning, 85, domain.ResourceMemory, "Memory usage rising after deploy", nil},
+			{1 * time.Minute, "web-02", "system.ram", "ram", "ram_in_use", domain.StatusWarning, 86, domain.ResourceMemory, "Memory usage rising after deploy", nil},
+			{2 * time.Minute, "web-01", "apps.nginx.processes", "nginx", "process_oom_killed", domain.StatusCritical, 1, domain.ResourceProcess, "Worker killed by the OOM killer", nil},
+			{2*time.Minute + 30*time.Second, "web-03", "system.ram", "ram", "ram_in_use", domain.StatusCritical, 97, domain.ResourceMemory, "Memory exhausted", nil},
+			{3 * time.Minute, "web-02", "apps.nginx.processes", "nginx", "process_oom_killed", domain.StatusCritical, 1, domain.ResourceProcess, "Worker killed by the OOM killer", nil},
+			{4 * time.Minute, "lb-01", "apps.haproxy.backends", "haproxy", "backends_down", domain.StatusCritical, 3, domain.ResourceNetwork, "All web backends down", nil},
+		},
+	},
+}
@@ -0,0 +1,133 @@
+// Package scenario scripts synthetic incidents as timed alerts, for testing alert
+// correlation and analysis and for demos.
+package scenario
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
+)
+
+// Limits of custom scripts
+const (
+	MaxSteps    = 500
+	MaxDuration = 24 * time.Hour
+)
+
+// Step is one alert of a scenario, After the scenario start
+type Step struct {
+	After        time.Duration
+	Host         string
+	Chart        string
+	Family       string
+	Name         string
+	Status       domain.AlertStatus
+	Value        float64
+	ResourceType domain.ResourceType
+	Description  string
+	Labels       map[string]string
+}
+
+// Scenario is a named script of timed alerts
+type Scenario struct {
+	Name        string
+	Description string
+	Steps       []Step
+}
+
+// Validate checks that the script can be injected
+func (s Scenario) Validate() error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("scenario needs at least one step")
+	}
+	if len(s.Steps) > MaxSteps {
+		return fmt.Errorf("scenario has more than %d steps", MaxSteps)
+	}
+	for i, step := range s.Steps {
+		if step.Host == "" || step.Name == "" {
+			return fmt.Errorf("step %d needs a host and an alert name", i+1)
+		}
+		if step.After < 0 || step.After > MaxDuration {
+			return fmt.Errorf("step %d must happen within %s of the start", i+1, MaxDuration)
+		}
+		switch step.Status {
+		case domain.StatusClear, domain.StatusWarning, domain.StatusCritical:
+		default:
+			return fmt.Errorf("step %d has invalid status %q", i+1, step.Status)
+		}
+	}
+	return nil
+}
+
+// Duration returns when the last step happens
+func (s Scenario) Duration() time.Duration {
+	var d time.Duration
+	for _, step := range s.Steps {
+		d = max(d, step.After)
+	}
+	return d
+}
+
+// Alerts returns the alerts of the script in time order, starting at start. OldStatus
+// is the previous status of the same alert in the script, CLEAR for its first step.
+func (s Scenario) Alerts(start time.Time) []domain.Alert {
+	steps := make([]Step, len(s.Steps))
+	copy(steps, s.Steps)
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].After < steps[j].After })
+
+	previous := make(map[string]domain.AlertStatus)
+	alerts := make([]domain.Alert, 0, len(steps))
+	for _, step := range steps {
+		key := step.Host + "|" + step.Chart + "|" + step.Name
+		oldStatus, ok := previous[key]
+		if !ok {
+			oldStatus = domain.StatusClear
+		}
+		previous[key] = step.Status
+
+		resourceType := step.ResourceType
+		if resourceType == "" {
+			resourceType = domain.ResourceUnknown
+		}
+		labels := map[string]string{"source": "test", "scenario": s.Name}
+		for k, v := range step.Labels {
+			labels[k] = v
+		}
+
+		occurredAt := start.Add(step.After)
+		alerts = append(alerts, domain.Alert{
+			ID:           idgen.New(occurredAt),
+			Host:         step.Host,
+			Chart:        step.Chart,
+			Family:       step.Family,
+			Name:         step.Name,
+			Status:       step.Status,
+			OldStatus:    oldStatus,
+			Value:        step.Value,
+			OccurredAt:   occurredAt,
+			Description:  step.Description,
+			ResourceType: resourceType,
+			Labels:       labels,
+			Source:       "scenario",
+		})
+	}
+	return alerts
+}
+
+// Get returns a built-in scenario
+func Get(name string) (Scenario, bool) {
+	for _, s := range builtin {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Scenario{}, false
+}
+
+// List returns the built-in scenarios
+func List() []Scenario {
+	return append([]Scenario(nil), builtin...)
+}
@@ -0,0 +1,48 @@
+package scenario
+
+import (
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func TestBuiltinScenarios(t *testing.T) {
+	for _, name := range []string{"memory-leak-cascade", "disk-fill", "network-partition", "multi-host-outage"} {
+		s, ok := Get(name)
+		if !ok {
+			t.Fatalf("missing built-in scenario %s", name)
+		}
+		if err := s.Validate(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestScenario_Alerts(t *testing.T) {
+	s := Scenario{Name: "custom", Steps: []Step{
+		{After: 2 * time.Minute, Host: "web-01", Name: "disk", Status: domain.StatusCritical},
+		{After: 0, Host: "web-01", Name: "disk", Status: domain.StatusWarning},
+		{After: 5 * time.Minute, Host: "web-01", Name: "disk", Status: domain.StatusClear, ResourceType: domain.ResourceDisk},
+	}}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	alerts := s.Alerts(start)
+	if len(alerts) != 3 || !alerts[0].OccurredAt.Equal(start) || !alerts[2].OccurredAt.Equal(start.Add(5*time.Minute)) {
+		t.Fatalf("expected alerts in time order, got %+v", alerts)
+	}
+	if alerts[0].OldStatus != domain.StatusClear || alerts[1].OldStatus != domain.StatusWarning || alerts[2].OldStatus != domain.StatusCritical {
+		t.Errorf("unexpected old statuses: %s, %s, %s", alerts[0].OldStatus, alerts[1].OldStatus, alerts[2].OldStatus)
+	}
+	if alerts[0].ResourceType != domain.ResourceUnknown || alerts[0].Labels["scenario"] != "custom" {
+		t.Errorf("unexpected defaults: %+v", alerts[0])
+	}
+
+	s.Steps[0].Status = "DOWN"
+	if err := s.Validate(); err == nil {
+		t.Error("expected an invalid status to be rejected")
+	}
+}
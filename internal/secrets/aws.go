@@ -0,0 +1,176 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWSCredentials signs requests to AWS
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Of temporary credentials
+}
+
+// AWSSecretsManager reads secrets from AWS Secrets Manager. Paths are secret names or
+// ARNs; a key selects a field of a secret stored as a JSON object.
+type AWSSecretsManager struct {
+	region      string
+	endpoint    string
+	credentials AWSCredentials
+	httpClient  *http.Client
+	now         func() time.Time
+}
+
+// NewAWSSecretsManager creates a Secrets Manager provider. An empty endpoint uses the
+// regional AWS endpoint.
+func NewAWSSecretsManager(region, endpoint string, credentials AWSCredentials) *AWSSecretsManager {
+	if endpoint == "" {
+		endpoint = "https://secretsmanager." + region + ".amazonaws.com"
+	}
+	return &AWSSecretsManager{
+		region:      region,
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		credentials: credentials,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		now:         time.Now,
+	}
+}
+
+// NewAWSSecretsManagerFromEnv creates a Secrets Manager provider configured like the AWS
+// CLI: AWS_REGION (or AWS_DEFAULT_REGION), AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY,
+// AWS_SESSION_TOKEN and AWS_ENDPOINT_URL_SECRETS_MANAGER (or AWS_ENDPOINT_URL)
+func NewAWSSecretsManagerFromEnv() (Provider, error) {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		return nil, errors.New("AWS_REGION is not set")
+	}
+	credentials := AWSCredentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are not set")
+	}
+	endpoint := os.Getenv("AWS_ENDPOINT_URL_SECRETS_MANAGER")
+	if endpoint == "" {
+		endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+	return NewAWSSecretsManager(region, endpoint, credentials), nil
+}
+
+// Resolve implements Provider
+func (m *AWSSecretsManager) Resolve(ctx context.Context, path, key string) (string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": path})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	m.sign(req, body)
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("secrets manager responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var secret struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if secret.SecretString == nil {
+		return "", errors.New("binary secrets are not supported")
+	}
+	if key == "" {
+		return *secret.SecretString, nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(*secret.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, so it has no field %q", key)
+	}
+	return field(fields, key)
+}
+
+// sign adds the Signature Version 4 authorization of a request
+func (m *AWSSecretsManager) sign(req *http.Request, body []byte) {
+	now := m.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if m.credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", m.credentials.SessionToken)
+	}
+
+	signed := []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date", "x-amz-target"}
+	if m.credentials.SessionToken != "" {
+		signed = append(signed, "x-amz-security-token")
+		sort.Strings(signed)
+	}
+	var headers strings.Builder
+	for _, name := range signed {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		headers.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signedHeaders := strings.Join(signed, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonical := strings.Join([]string{req.Method, path, req.URL.Query().Encode(), headers.String(), signedHeaders, payloadHash}, "\n")
+	scope := date + "/" + m.region + "/secretsmanager/aws4_request"
+	toSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonical))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+m.credentials.SecretAccessKey), date)
+	for _, part := range []string{m.region, "secretsmanager", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		m.credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,123 @@
+// Package secrets resolves references to secrets kept in external secret stores, such as
+// "vault:secret/data/incident-teller#db_password" or "awssm:prod/incident-teller#openai_key".
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Provider fetches secrets from a secret store
+type Provider interface {
+	// Resolve returns the secret at path. key selects a field of a secret holding several;
+	// an empty key is only allowed for secrets holding a single value.
+	Resolve(ctx context.Context, path, key string) (string, error)
+}
+
+var (
+	mu        sync.RWMutex
+	providers = map[string]func() (Provider, error){
+		"vault": NewVaultFromEnv,
+		"awssm": NewAWSSecretsManagerFromEnv,
+	}
+)
+
+// Register adds a provider for references starting with scheme + ":". The provider is
+// created on first use.
+func Register(scheme string, factory func() (Provider, error)) {
+	mu.Lock()
+	defer mu.Unlock()
+	providers[scheme] = factory
+}
+
+// Schemes returns the registered reference schemes, sorted
+func Schemes() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	schemes := make([]string, 0, len(providers))
+	for scheme := range providers {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// Resolver resolves secret references, creating each provider once
+type Resolver struct {
+	providers map[string]Provider
+}
+
+// NewResolver creates a resolver for the registered providers
+func NewResolver() *Resolver {
+	return &Resolver{providers: make(map[string]Provider)}
+}
+
+// IsReference reports whether value is a reference to a registered provider:
+// "<scheme>:<path>[#<key>]"
+func IsReference(value string) bool {
+	scheme, path, ok := strings.Cut(value, ":")
+	if !ok || path == "" || strings.HasPrefix(path, "//") {
+		return false
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok = providers[scheme]
+	return ok
+}
+
+// Resolve returns the secret a reference points to
+func (r *Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	scheme, rest, _ := strings.Cut(ref, ":")
+	path, key, _ := strings.Cut(rest, "#")
+
+	provider, ok := r.providers[scheme]
+	if !ok {
+		mu.RLock()
+		factory, registered := providers[scheme]
+		mu.RUnlock()
+		if !registered {
+			return "", fmt.Errorf("unknown secret provider %q", scheme)
+		}
+		var err error
+		if provider, err = factory(); err != nil {
+			return "", fmt.Errorf("failed to create %s secret provider: %w", scheme, err)
+		}
+		r.providers[scheme] = provider
+	}
+
+	secret, err := provider.Resolve(ctx, path, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s secret %s: %w", scheme, path, err)
+	}
+	return secret, nil
+}
+
+// field returns a field of a secret holding key/value pairs, or its only field if key is
+// empty
+func field(fields map[string]any, key string) (string, error) {
+	if key == "" {
+		if len(fields) != 1 {
+			return "", fmt.Errorf("secret has %d fields, select one with #<key>", len(fields))
+		}
+		for k := range fields {
+			key = k
+		}
+	}
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("secret has no field %q", key)
+	}
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", nil
+	default:
+		data, err := json.Marshal(v)
+		return string(data), err
+	}
+}
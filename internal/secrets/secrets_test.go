@@ -0,0 +1,119 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVault_Resolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/incident-teller":
+			w.Write([]byte(`{"data":{"data":{"db_password":"hunter2","openai_key":"sk-1"},"metadata":{"version":3}}}`))
+		case "/v1/kv/single":
+			w.Write([]byte(`{"data":{"value":"only"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	vault := NewVault(server.URL, "root", "")
+	tests := []struct {
+		path, key string
+		want      string
+		wantErr   bool
+	}{
+		{path: "secret/data/incident-teller", key: "db_password", want: "hunter2"},
+		{path: "kv/single", want: "only"},
+		{path: "secret/data/incident-teller", wantErr: true},
+		{path: "secret/data/incident-teller", key: "missing", wantErr: true},
+		{path: "secret/data/missing", key: "x", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := vault.Resolve(context.Background(), tt.path, tt.key)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Resolve(%q, %q) = %q, %v; want %q, error %v", tt.path, tt.key, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestAWSSecretsManager_Resolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/eu-west-1/secretsmanager/aws4_request") ||
+			r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var req struct{ SecretId string }
+		json.NewDecoder(r.Body).Decode(&req)
+		switch req.SecretId {
+		case "prod/incident-teller":
+			w.Write([]byte(`{"SecretString":"{\"smtp_password\":\"mail-pw\"}"}`))
+		case "plain":
+			w.Write([]byte(`{"SecretString":"token-1"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"ResourceNotFoundException"}`))
+		}
+	}))
+	defer server.Close()
+
+	manager := NewAWSSecretsManager("eu-west-1", server.URL, AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"})
+	ctx := context.Background()
+	if got, err := manager.Resolve(ctx, "prod/incident-teller", "smtp_password"); err != nil || got != "mail-pw" {
+		t.Errorf("expected mail-pw, got %q, %v", got, err)
+	}
+	if got, err := manager.Resolve(ctx, "plain", ""); err != nil || got != "token-1" {
+		t.Errorf("expected token-1, got %q, %v", got, err)
+	}
+	if _, err := manager.Resolve(ctx, "missing", ""); err == nil || !strings.Contains(err.Error(), "ResourceNotFoundException") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	Register("test", func() (Provider, error) { return staticProvider{"pw": "from-test"}, nil })
+
+	for value, want := range map[string]bool{
+		"test:pw":           true,
+		"vault:secret/x#y":  true,
+		"awssm:prod/x":      true,
+		"plain-password":    false,
+		"https://hooks/x":   false,
+		"unknown:something": false,
+		"test:":             false,
+	} {
+		if got := IsReference(value); got != want {
+			t.Errorf("IsReference(%q) = %v, want %v", value, got, want)
+		}
+	}
+
+	resolver := NewResolver()
+	if got, err := resolver.Resolve(context.Background(), "test:pw"); err != nil || got != "from-test" {
+		t.Errorf("expected from-test, got %q, %v", got, err)
+	}
+	if _, err := resolver.Resolve(context.Background(), "test:other"); err == nil {
+		t.Error("expected an error for a missing secret")
+	}
+}
+
+type staticProvider map[string]string
+
+func (p staticProvider) Resolve(_ context.Context, path, _ string) (string, error) {
+	secret, ok := p[path]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return secret, nil
+}
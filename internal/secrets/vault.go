@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Vault reads secrets from the key/value secrets engine of HashiCorp Vault. Paths are API
+// paths below /v1, e.g. "secret/data/incident-teller" for version 2 of the engine.
+type Vault struct {
+	addr       string
+	token      string
+	namespace  string
+	httpClient *http.Client
+}
+
+// NewVault creates a Vault provider
+func NewVault(addr, token, namespace string) *Vault {
+	return &Vault{
+		addr:       strings.TrimSuffix(addr, "/"),
+		token:      token,
+		namespace:  namespace,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NewVaultFromEnv creates a Vault provider configured like the Vault CLI: VAULT_ADDR,
+// VAULT_TOKEN (or VAULT_TOKEN_FILE) and VAULT_NAMESPACE
+func NewVaultFromEnv() (Provider, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return nil, errors.New("VAULT_ADDR is not set")
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if path := os.Getenv("VAULT_TOKEN_FILE"); token == "" && path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read VAULT_TOKEN_FILE: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token == "" {
+		return nil, errors.New("VAULT_TOKEN is not set")
+	}
+	return NewVault(addr, token, os.Getenv("VAULT_NAMESPACE")), nil
+}
+
+// Resolve implements Provider
+func (v *Vault) Resolve(ctx context.Context, path, key string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.addr+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("vault responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var secret struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	// Version 2 of the engine nests the fields with the version metadata
+	if nested, ok := secret.Data["data"].(map[string]any); ok {
+		if _, versioned := secret.Data["metadata"]; versioned {
+			secret.Data = nested
+		}
+	}
+	return field(secret.Data, key)
+}
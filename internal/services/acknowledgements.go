@@ -0,0 +1,48 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// Acknowledgement records that a responder has taken ownership of an incident
+type Acknowledgement struct {
+	IncidentID     string
+	By             string // Responder name, e.g. the Slack user name
+	AcknowledgedAt time.Time
+}
+
+// AcknowledgementTracker holds incident acknowledgements. It is safe for concurrent use.
+type AcknowledgementTracker struct {
+	mu   sync.RWMutex
+	acks map[string]Acknowledgement
+}
+
+// NewAcknowledgementTracker creates an empty tracker
+func NewAcknowledgementTracker() *AcknowledgementTracker {
+	return &AcknowledgementTracker{acks: make(map[string]Acknowledgement)}
+}
+
+// Acknowledge records the acknowledgement of an incident. An incident is only
+// acknowledged once; later calls return the existing acknowledgement and false.
+func (t *AcknowledgementTracker) Acknowledge(incidentID, by string, at time.Time) (Acknowledgement, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if existing, ok := t.acks[incidentID]; ok {
+		return existing, false
+	}
+
+	ack := Acknowledgement{IncidentID: incidentID, By: by, AcknowledgedAt: at}
+	t.acks[incidentID] = ack
+	return ack, true
+}
+
+// Acknowledgement returns the acknowledgement of an incident, if any
+func (t *AcknowledgementTracker) Acknowledgement(incidentID string) (Acknowledgement, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	ack, ok := t.acks[incidentID]
+	return ack, ok
+}
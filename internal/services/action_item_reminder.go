@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/notify"
+	"incident-teller/internal/ports"
+)
+
+// ActionItemReminder reports open action items past their due date through the
+// notification channels. Each item is reported once per due date; the report is
+// persisted, so restarts don't repeat it.
+type ActionItemReminder struct {
+	store      ports.ActionItemStore
+	dispatcher *notify.Dispatcher
+
+	mu sync.Mutex // Serializes checks so an item is reported once
+}
+
+// NewActionItemReminder creates a reminder sending to the dispatcher's channels, which
+// route the notifications by the labels of the item's incident
+func NewActionItemReminder(store ports.ActionItemStore, dispatcher *notify.Dispatcher) *ActionItemReminder {
+	return &ActionItemReminder{store: store, dispatcher: dispatcher}
+}
+
+// Check reports every overdue action item not reported yet and returns them
+func (r *ActionItemReminder) Check(ctx context.Context, incidents []domain.Incident, now time.Time) ([]domain.ActionItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items, err := r.store.GetActionItems(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get action items: %w", err)
+	}
+	byID := make(map[string]domain.Incident, len(incidents))
+	for _, incident := range incidents {
+		byID[incident.ID] = incident
+	}
+
+	var reported []domain.ActionItem
+	var errs []error
+	for _, item := range items {
+		if !item.Overdue(now) || item.OverdueNotifiedAt != nil {
+			continue
+		}
+		if err := r.dispatcher.Send(ctx, overdueNotification(item, byID[item.IncidentID], now)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to report action item %s: %w", item.ID, err))
+			continue
+		}
+		item.OverdueNotifiedAt = &now
+		if err := r.store.SaveActionItem(ctx, item); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save action item %s: %w", item.ID, err))
+			continue
+		}
+		reported = append(reported, item)
+	}
+	return reported, errors.Join(errs...)
+}
+
+// overdueNotification reports an overdue action item. It carries the labels of its
+// incident, so routes send it where the incident went, and its owner as action_owner.
+func overdueNotification(item domain.ActionItem, incident domain.Incident, now time.Time) notify.Notification {
+	title := incident.Title
+	if title == "" {
+		title = fmt.Sprintf("Incident %s", item.IncidentID)
+	}
+	owner := item.Owner
+	if owner == "" {
+		owner = "unassigned"
+	}
+
+	labels := incident.Labels()
+	if item.Owner != "" {
+		labels["action_owner"] = item.Owner
+	}
+
+	return notify.Notification{
+		IncidentID: item.IncidentID,
+		Title:      "Overdue action item: " + item.Description,
+		Severity:   "warning",
+		Text: fmt.Sprintf("⏰ *Overdue action item* of incident *%s* (ID: %s): %s\n\n*Owner:* %s\n*Due:* %s (%s ago)",
+			title, item.IncidentID, item.Description, owner,
+			item.DueAt.UTC().Format(DateTimeLayout), now.Sub(*item.DueAt).Round(time.Minute)),
+		Labels:    labels,
+		Hosts:     incident.Hosts(),
+		CreatedAt: now,
+	}
+}
+
+// Run checks the action items every interval until ctx is cancelled
+func (r *ActionItemReminder) Run(ctx context.Context, repo ports.Repository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			incidents, err := repo.GetIncidents(ctx)
+			if err != nil {
+				log.Printf("⚠️  Action item check failed to load incidents: %v", err)
+				continue
+			}
+
+			reported, err := r.Check(ctx, incidents, time.Now())
+			if err != nil {
+				log.Printf("⚠️  Action item reminder error: %v", err)
+			}
+			for _, item := range reported {
+				log.Printf("⏰ Reported overdue action item %s of incident %s", item.ID, item.IncidentID)
+			}
+		}
+	}
+}
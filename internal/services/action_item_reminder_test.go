@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"incident-teller/internal/adapters/repository"
+	"incident-teller/internal/domain"
+	"incident-teller/internal/labels"
+	"incident-teller/internal/notify"
+)
+
+func TestActionItemReminder_ReportsOverdueItemsOnce(t *testing.T) {
+	repo := repository.NewInMemoryRepository()
+	ctx := context.Background()
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	incident := domain.Incident{ID: "inc-1", Title: "Disk full on db-01", StartedAt: now.Add(-7 * 24 * time.Hour),
+		Events: []domain.Alert{{ID: "a1", Host: "db-01", Labels: map[string]string{"owner": "team-db"}}}}
+	due, later := now.Add(-24*time.Hour), now.Add(24*time.Hour)
+	items := []domain.ActionItem{
+		{ID: "overdue", IncidentID: "inc-1", Description: "Alert on inode usage", Owner: "alice", DueAt: &due, Status: domain.ActionItemOpen},
+		{ID: "done", IncidentID: "inc-1", Description: "Resize volume", DueAt: &due, Status: domain.ActionItemDone},
+		{ID: "not-due", IncidentID: "inc-1", Description: "Rotate logs", DueAt: &later, Status: domain.ActionItemInProgress},
+		{ID: "no-due-date", IncidentID: "inc-1", Description: "Write runbook", Status: domain.ActionItemOpen},
+	}
+	for _, item := range items {
+		if err := repo.SaveActionItem(ctx, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Overdue items follow the routes of their incident
+	teamDB, other := &recordingNotifier{}, &recordingNotifier{}
+	dispatcher := notify.NewDispatcher(
+		notify.Routed(teamDB, labels.Equal(map[string]string{"owner": "team-db"})),
+		notify.Routed(other, labels.Equal(map[string]string{"owner": "team-web"})),
+	)
+	reminder := NewActionItemReminder(repo, dispatcher)
+
+	reported, err := reminder.Check(ctx, []domain.Incident{incident}, now)
+	if err != nil || len(reported) != 1 || reported[0].ID != "overdue" {
+		t.Fatalf("expected the overdue item reported, got %+v (err %v)", reported, err)
+	}
+	if len(teamDB.sent) != 1 || len(other.sent) != 0 {
+		t.Fatalf("expected one notification to team-db, got %d and %d", len(teamDB.sent), len(other.sent))
+	}
+	sent := teamDB.sent[0]
+	if sent.IncidentID != "inc-1" || sent.Labels["action_owner"] != "alice" ||
+		!strings.Contains(sent.Text, "Alert on inode usage") || !strings.Contains(sent.Text, "*Owner:* alice") {
+		t.Errorf("unexpected notification %+v", sent)
+	}
+
+	// Reported once, even by a reminder started afterwards
+	if reported, err := NewActionItemReminder(repo, dispatcher).Check(ctx, []domain.Incident{incident}, now.Add(time.Hour)); err != nil || len(reported) != 0 {
+		t.Fatalf("expected no repeated report, got %+v (err %v)", reported, err)
+	}
+}
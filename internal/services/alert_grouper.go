@@ -5,11 +5,13 @@ import (
 	"time"
 
 	"incident-teller/internal/domain"
+	"incident-teller/internal/labels"
 )
 
 // AlertGrouper groups related alerts based on various criteria
 type AlertGrouper struct {
 	correlationWindow time.Duration
+	groupKey          labels.GroupKey
 }
 
 // NewAlertGrouper creates a new alert grouper
@@ -19,6 +21,12 @@ func NewAlertGrouper(correlationWindow time.Duration) *AlertGrouper {
 	}
 }
 
+// SetGroupKey makes alerts with the same key, instead of the same host, related, and keeps
+// alerts with different keys apart. The zero key restores grouping by host.
+func (ag *AlertGrouper) SetGroupKey(key labels.GroupKey) {
+	ag.groupKey = key
+}
+
 // AlertGroup represents a group of related alerts
 type AlertGroup struct {
 	ID               string
@@ -64,12 +72,16 @@ func (ag *AlertGrouper) GroupAlerts(alerts []domain.Alert) []AlertGroup {
 	return groups
 }
 
-// groupByHost groups alerts by hostname and time window
+// groupByHost groups alerts by hostname, or by the grouping key if one is set
 func (ag *AlertGrouper) groupByHost(alerts []domain.Alert) map[string][]domain.Alert {
 	hostGroups := make(map[string][]domain.Alert)
 
 	for _, alert := range alerts {
-		hostGroups[alert.Host] = append(hostGroups[alert.Host], alert)
+		key := alert.Host
+		if !ag.groupKey.IsZero() {
+			key = ag.groupKey.Key(alert)
+		}
+		hostGroups[key] = append(hostGroups[key], alert)
 	}
 
 	return hostGroups
@@ -148,6 +160,11 @@ func (ag *AlertGrouper) detectCascades(hostGroups map[string][]domain.Alert, all
 
 // isRelated checks if two alerts are related
 func (ag *AlertGrouper) isRelated(alert1, alert2 domain.Alert, allAlerts []domain.Alert) bool {
+	// A configured grouping key decides on its own
+	if !ag.groupKey.IsZero() {
+		return ag.groupKey.Key(alert1) == ag.groupKey.Key(alert2)
+	}
+
 	// Same host
 	if alert1.Host == alert2.Host {
 		return true
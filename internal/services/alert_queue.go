@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+)
+
+// ErrAlertQueueFull is returned when a batch is dropped because the alert queue is full
+var ErrAlertQueueFull = errors.New("alert queue is full")
+
+// Backpressure is what happens to a batch submitted to a full alert queue
+type Backpressure string
+
+const (
+	BackpressureBlock Backpressure = "block" // Wait for room, holding back the poller's next poll
+	BackpressureDrop  Backpressure = "drop"  // Drop the batch; its alerts stay stored
+)
+
+// ProcessAlertsFunc correlates and handles a stored batch of alerts
+type ProcessAlertsFunc func(ctx context.Context, alerts []domain.Alert)
+
+// AlertQueueStats is the state of the alert queue
+type AlertQueueStats struct {
+	Depth         int   `json:"depth"` // Batches waiting for a worker
+	Capacity      int   `json:"capacity"`
+	Workers       int   `json:"workers"`
+	Busy          int   `json:"busy"` // Workers processing a batch
+	Processed     int64 `json:"processed"`
+	Dropped       int64 `json:"dropped"`
+	DroppedAlerts int64 `json:"dropped_alerts"`
+}
+
+// AlertQueue hands the batches stored by the pollers to a pool of workers, so that a
+// burst of alerts or a slow consumer (AI predictions, notifications) neither stalls the
+// pollers indefinitely nor grows memory without bound. When the queue is full, batches
+// wait or are dropped according to the backpressure policy.
+type AlertQueue struct {
+	workers int
+	policy  Backpressure
+	queue   chan []domain.Alert
+	metrics observability.Metrics
+
+	mu    sync.Mutex
+	stats AlertQueueStats
+}
+
+// NewAlertQueue creates a queue holding up to size batches for workers
+func NewAlertQueue(workers, size int, policy Backpressure) *AlertQueue {
+	return &AlertQueue{
+		workers: workers,
+		policy:  policy,
+		queue:   make(chan []domain.Alert, size),
+		stats:   AlertQueueStats{Capacity: size, Workers: workers},
+	}
+}
+
+// SetMetrics records the queue depth, busy workers and dropped batches
+func (q *AlertQueue) SetMetrics(metrics observability.Metrics) {
+	q.metrics = metrics
+}
+
+// Submit queues a batch. With the block policy it waits for room until ctx is done; with
+// the drop policy a full queue drops the batch and returns ErrAlertQueueFull.
+func (q *AlertQueue) Submit(ctx context.Context, alerts []domain.Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	select {
+	case q.queue <- alerts:
+		q.recordDepth()
+		return nil
+	default:
+	}
+
+	if q.policy == BackpressureDrop {
+		q.mu.Lock()
+		q.stats.Dropped++
+		q.stats.DroppedAlerts += int64(len(alerts))
+		q.mu.Unlock()
+		if q.metrics != nil {
+			q.metrics.IncCounter("alert_queue_dropped_batches_total", nil)
+			q.metrics.RecordHistogram("alert_queue_dropped_batch_size", float64(len(alerts)), nil)
+		}
+		return fmt.Errorf("%w: dropped %d alerts", ErrAlertQueueFull, len(alerts))
+	}
+
+	select {
+	case q.queue <- alerts:
+		q.recordDepth()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns the current state of the queue
+func (q *AlertQueue) Stats() AlertQueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := q.stats
+	stats.Depth = len(q.queue)
+	return stats
+}
+
+// Run processes queued batches with the worker pool until ctx is cancelled
+func (q *AlertQueue) Run(ctx context.Context, process ProcessAlertsFunc) {
+	var wg sync.WaitGroup
+	for i := 0; i < q.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case alerts := <-q.queue:
+					q.recordDepth()
+					q.setBusy(1)
+					process(ctx, alerts)
+					q.setBusy(-1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (q *AlertQueue) setBusy(delta int) {
+	q.mu.Lock()
+	q.stats.Busy += delta
+	if delta < 0 {
+		q.stats.Processed++
+	}
+	busy := q.stats.Busy
+	q.mu.Unlock()
+
+	if q.metrics != nil {
+		q.metrics.SetGauge("alert_queue_busy_workers", float64(busy), nil)
+	}
+}
+
+func (q *AlertQueue) recordDepth() {
+	if q.metrics != nil {
+		q.metrics.SetGauge("alert_queue_depth", float64(len(q.queue)), nil)
+	}
+}
+
+// HealthCheck reports the queue degraded while it is full
+func (q *AlertQueue) HealthCheck() observability.HealthCheck {
+	return func(ctx context.Context) observability.HealthCheckResult {
+		stats := q.Stats()
+		details := map[string]interface{}{
+			"depth":          stats.Depth,
+			"capacity":       stats.Capacity,
+			"busy_workers":   stats.Busy,
+			"dropped":        stats.Dropped,
+			"dropped_alerts": stats.DroppedAlerts,
+		}
+		if stats.Depth >= stats.Capacity {
+			return observability.HealthCheckResult{
+				Status:  "degraded",
+				Message: fmt.Sprintf("Alert queue full (%d batches), backpressure: %s", stats.Capacity, q.policy),
+				Details: details,
+			}
+		}
+		return observability.HealthCheckResult{Status: "healthy", Message: "Alert queue OK", Details: details}
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func TestAlertQueue_Backpressure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	batch := []domain.Alert{{ID: "a1"}, {ID: "a2"}}
+
+	dropping := NewAlertQueue(2, 1, BackpressureDrop)
+	if err := dropping.Submit(ctx, batch); err != nil {
+		t.Fatal(err)
+	}
+	if err := dropping.Submit(ctx, batch); !errors.Is(err, ErrAlertQueueFull) {
+		t.Fatalf("expected the second batch to be dropped, got %v", err)
+	}
+	if stats := dropping.Stats(); stats.Depth != 1 || stats.Dropped != 1 || stats.DroppedAlerts != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if result := dropping.HealthCheck()(ctx); result.Status != "degraded" {
+		t.Errorf("expected a full queue to be degraded, got %s", result.Status)
+	}
+
+	// With the block policy a full queue holds back the submitter until a worker takes a batch
+	blocking := NewAlertQueue(2, 1, BackpressureBlock)
+	if err := blocking.Submit(ctx, batch); err != nil {
+		t.Fatal(err)
+	}
+	timeout, timeoutCancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer timeoutCancel()
+	if err := blocking.Submit(timeout, batch); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected submitting to a full queue to block, got %v", err)
+	}
+
+	processed := make(chan []domain.Alert)
+	go blocking.Run(ctx, func(ctx context.Context, alerts []domain.Alert) {
+		processed <- alerts
+	})
+	if err := blocking.Submit(ctx, batch); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case alerts := <-processed:
+			if len(alerts) != 2 {
+				t.Fatalf("unexpected batch %+v", alerts)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected both batches to be processed")
+		}
+	}
+	if stats := blocking.Stats(); stats.Dropped != 0 || stats.Depth != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/ports"
+)
+
+// AlertSampler attaches the recent values of their charts to the alerts of incidents as
+// they form, so timelines and trend detection work on the actual series rather than on
+// the trigger values alone
+type AlertSampler struct {
+	sources map[string]ports.MetricSource // Alert source name -> its metrics
+	store   ports.AlertSampleStore        // Nil keeps samples with the incident only
+	window  time.Duration
+	points  int
+}
+
+// NewAlertSampler creates a sampler fetching at most points values over the window before
+// each alert, saving them to the store if not nil
+func NewAlertSampler(store ports.AlertSampleStore, window time.Duration, points int) *AlertSampler {
+	return &AlertSampler{
+		sources: make(map[string]ports.MetricSource),
+		store:   store,
+		window:  window,
+		points:  points,
+	}
+}
+
+// AddSource samples the alerts reported by the named alert source from its metrics
+func (s *AlertSampler) AddSource(name string, source ports.MetricSource) {
+	s.sources[name] = source
+}
+
+// Sample fetches and saves the samples of the incident's alerts that have none yet and
+// returns the incident with them attached. Alerts whose samples couldn't be fetched stay
+// unsampled; the errors are returned together.
+func (s *AlertSampler) Sample(ctx context.Context, incident domain.Incident) (domain.Incident, error) {
+	events := make([]domain.Alert, len(incident.Events))
+	copy(events, incident.Events)
+
+	// Alerts of the same chart firing at the same second share their samples
+	fetched := make(map[string][]domain.MetricSample)
+	var errs []error
+	for i, alert := range events {
+		source, ok := s.sources[alert.Source]
+		if !ok || len(alert.Samples) > 0 {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s/%d", alert.Host, alert.Chart, alert.OccurredAt.Unix())
+		samples, ok := fetched[key]
+		if !ok {
+			var err error
+			samples, err = source.FetchSamples(ctx, alert, s.window, s.points)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to sample alert %s: %w", alert.ID, err))
+				continue
+			}
+			fetched[key] = samples
+		}
+		if len(samples) == 0 {
+			continue
+		}
+
+		if s.store != nil {
+			if err := s.store.SaveAlertSamples(ctx, alert.ID, samples); err != nil {
+				errs = append(errs, fmt.Errorf("failed to save samples of alert %s: %w", alert.ID, err))
+				continue
+			}
+		}
+		events[i].Samples = samples
+	}
+
+	incident.Events = events
+	return incident, errors.Join(errs...)
+}
+
+// DescribeSamples summarizes how an alert's chart moved before it fired, e.g. "memory went
+// 76→94→97% over 7 minutes", showing at most four values; it is empty for alerts with
+// fewer than two samples
+func DescribeSamples(alert domain.Alert) string {
+	values, span := sampleValues(alert)
+	if values == "" {
+		return ""
+	}
+
+	subject := alert.Chart
+	if alert.ResourceType != "" && alert.ResourceType != domain.ResourceUnknown {
+		subject = strings.ToLower(string(alert.ResourceType))
+	}
+	return fmt.Sprintf("%s went %s over %s", subject, values, describeSpan(span))
+}
+
+// sampleValues renders at most four of an alert's samples with their units, e.g.
+// "76→94→97%", and the time they span; empty for alerts with fewer than two samples
+func sampleValues(alert domain.Alert) (string, time.Duration) {
+	samples := alert.Samples
+	if len(samples) < 2 {
+		return "", 0
+	}
+
+	const shown = 4
+	picked := samples
+	if len(samples) > shown {
+		picked = make([]domain.MetricSample, shown)
+		for i := range picked {
+			picked[i] = samples[i*(len(samples)-1)/(shown-1)]
+		}
+	}
+
+	values := make([]string, len(picked))
+	for i, sample := range picked {
+		values[i] = formatSampleValue(sample.Value)
+	}
+
+	units := alert.Labels["units"]
+	if units != "" && units != "%" {
+		units = " " + units
+	}
+	return strings.Join(values, "→") + units, samples[len(samples)-1].At.Sub(samples[0].At)
+}
+
+// formatSampleValue rounds large values to integers and small ones to one decimal
+func formatSampleValue(value float64) string {
+	if math.Abs(value) >= 10 {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.1f", value)
+}
+
+// describeSpan renders the time covered by samples, e.g. "7 minutes" or "45 seconds"
+func describeSpan(span time.Duration) string {
+	switch {
+	case span < time.Minute:
+		return fmt.Sprintf("%d seconds", int(span.Seconds()))
+	case span < 2*time.Minute:
+		return "a minute"
+	case span < time.Hour:
+		return fmt.Sprintf("%d minutes", int(span.Round(time.Minute).Minutes()))
+	default:
+		return span.Round(time.Minute).String()
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+type fakeMetricSource struct {
+	fetches int
+	series  map[string][]float64 // chart -> values, one a minute
+}
+
+func (f *fakeMetricSource) FetchSamples(_ context.Context, alert domain.Alert, window time.Duration, points int) ([]domain.MetricSample, error) {
+	f.fetches++
+	values, ok := f.series[alert.Chart]
+	if !ok {
+		return nil, errors.New("chart not found")
+	}
+	samples := make([]domain.MetricSample, len(values))
+	for i, value := range values {
+		samples[i] = domain.MetricSample{At: alert.OccurredAt.Add(time.Duration(i-len(values)+1) * time.Minute), Value: value}
+	}
+	return samples, nil
+}
+
+type fakeSampleStore map[string][]domain.MetricSample
+
+func (s fakeSampleStore) SaveAlertSamples(_ context.Context, alertID string, samples []domain.MetricSample) error {
+	s[alertID] = samples
+	return nil
+}
+
+func TestAlertSampler_Sample(t *testing.T) {
+	source := &fakeMetricSource{series: map[string][]float64{"mem.used": {76, 81, 88, 94, 95, 96, 96, 97}}}
+	store := fakeSampleStore{}
+	sampler := NewAlertSampler(store, 10*time.Minute, 12)
+	sampler.AddSource("netdata", source)
+
+	at := time.Date(2026, 3, 2, 14, 7, 0, 0, time.UTC)
+	incident := domain.Incident{ID: "inc-1", Events: []domain.Alert{
+		{ID: "a-1", Source: "netdata", Host: "db-01", Chart: "mem.used", OccurredAt: at},
+		{ID: "a-2", Source: "netdata", Host: "db-01", Chart: "mem.used", OccurredAt: at}, // Shares a-1's fetch
+		{ID: "a-3", Source: "netdata", Host: "db-01", Chart: "disk.space", OccurredAt: at},
+		{ID: "a-4", Source: "zabbix", Host: "db-01", Chart: "mem.used", OccurredAt: at},
+	}}
+
+	sampled, err := sampler.Sample(context.Background(), incident)
+	if err == nil {
+		t.Fatal("expected the failed fetch of disk.space to be reported")
+	}
+	if source.fetches != 2 {
+		t.Fatalf("expected 2 fetches, got %d", source.fetches)
+	}
+	if len(sampled.Events[0].Samples) != 8 || len(sampled.Events[1].Samples) != 8 {
+		t.Fatalf("expected both mem.used alerts sampled, got %+v", sampled.Events)
+	}
+	if len(sampled.Events[2].Samples) != 0 || len(sampled.Events[3].Samples) != 0 {
+		t.Fatalf("expected the failed and unknown-source alerts unsampled, got %+v", sampled.Events)
+	}
+	if len(store) != 2 || len(store["a-1"]) != 8 {
+		t.Fatalf("expected the samples saved, got %+v", store)
+	}
+	if len(incident.Events[0].Samples) != 0 {
+		t.Fatal("expected the given incident left unchanged")
+	}
+
+	// Sampled alerts aren't fetched again
+	if _, err := sampler.Sample(context.Background(), sampled); err == nil || source.fetches != 3 {
+		t.Fatalf("expected only disk.space fetched again, got %d fetches (err %v)", source.fetches, err)
+	}
+}
+
+func TestDescribeSamples(t *testing.T) {
+	at := time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC)
+	alert := domain.Alert{Chart: "mem.used", ResourceType: domain.ResourceMemory, Labels: map[string]string{"units": "%"}}
+	if got := DescribeSamples(alert); got != "" {
+		t.Fatalf("expected no description without samples, got %q", got)
+	}
+
+	for i, value := range []float64{76.2, 81, 88, 94, 95, 96, 96.4, 97.1} {
+		alert.Samples = append(alert.Samples, domain.MetricSample{At: at.Add(time.Duration(i) * time.Minute), Value: value})
+	}
+	if got, want := DescribeSamples(alert), "memory went 76→88→95→97% over 7 minutes"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	alert.ResourceType, alert.Labels["units"] = domain.ResourceUnknown, "MiB"
+	alert.Samples = alert.Samples[:2]
+	if got, want := DescribeSamples(alert), "mem.used went 76→81 MiB over a minute"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
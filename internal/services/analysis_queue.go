@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// ErrAnalysisQueueFull is returned when an incident can't be queued for analysis
+var ErrAnalysisQueueFull = errors.New("analysis queue is full")
+
+// AnalysisStatus is the state of an incident's analysis job
+type AnalysisStatus string
+
+const (
+	AnalysisPending   AnalysisStatus = "pending"
+	AnalysisRunning   AnalysisStatus = "running"
+	AnalysisCompleted AnalysisStatus = "completed"
+	AnalysisFailed    AnalysisStatus = "failed"
+)
+
+// AnalyzeFunc analyzes an incident; the result is kept with the job
+type AnalyzeFunc func(ctx context.Context, incident domain.Incident) (any, error)
+
+// AnalysisJob is the latest analysis job of an incident. Result and Error belong to the
+// last finished run, which analyzed the incident as of AnalyzedVersion.
+type AnalysisJob struct {
+	IncidentID      string
+	Status          AnalysisStatus
+	Version         string // Incident version queued for analysis
+	AnalyzedVersion string
+	EnqueuedAt      time.Time
+	StartedAt       *time.Time
+	CompletedAt     *time.Time
+	Error           string
+	Result          any
+}
+
+// Current reports whether the job holds a completed analysis of the incident as it is
+func (j AnalysisJob) Current(incident domain.Incident) bool {
+	return j.Result != nil && j.AnalyzedVersion == AnalysisVersion(incident)
+}
+
+// AnalysisVersion changes whenever an incident should be analyzed again
+func AnalysisVersion(incident domain.Incident) string {
+	v := fmt.Sprintf("%s/%d", incident.Status, len(incident.Events))
+	if incident.ResolvedAt != nil {
+		v += "/resolved"
+	}
+	if override := incident.RootCauseOverride; override != nil {
+		v += "/pinned:" + override.AlertID
+	}
+	return v
+}
+
+// AnalysisQueue analyzes created and updated incidents with a pool of workers, so that
+// requests can use finished analyses instead of running them inline. Each incident has
+// at most one job: queuing an incident that is already queued updates the job, and one
+// that changes while it is analyzed is analyzed again afterwards.
+type AnalysisQueue struct {
+	analyze AnalyzeFunc
+	workers int
+	timeout time.Duration
+	queue   chan string // IDs of incidents with a pending job
+
+	mu          sync.Mutex
+	jobs        map[string]*analysisJob
+	subscribers map[chan AnalysisJob]struct{}
+}
+
+type analysisJob struct {
+	AnalysisJob
+	incident domain.Incident // Incident to analyze next
+	rerun    bool            // The incident changed while it was analyzed
+}
+
+// finishedRetention is how long finished jobs of incidents that aren't queued again are kept
+const finishedRetention = 24 * time.Hour
+
+// NewAnalysisQueue creates a queue holding up to size pending jobs, analyzed by workers
+// with analyze, each run limited to timeout
+func NewAnalysisQueue(analyze AnalyzeFunc, workers, size int, timeout time.Duration) *AnalysisQueue {
+	return &AnalysisQueue{
+		analyze:     analyze,
+		workers:     workers,
+		timeout:     timeout,
+		queue:       make(chan string, size),
+		jobs:        make(map[string]*analysisJob),
+		subscribers: make(map[chan AnalysisJob]struct{}),
+	}
+}
+
+// Enqueue queues an incident for analysis unless its current version is already
+// analyzed, queued or being analyzed
+func (q *AnalysisQueue) Enqueue(incident domain.Incident) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	version := AnalysisVersion(incident)
+	job, ok := q.jobs[incident.ID]
+	if !ok {
+		q.pruneLocked(now)
+		job = &analysisJob{AnalysisJob: AnalysisJob{IncidentID: incident.ID}}
+		q.jobs[incident.ID] = job
+	} else if job.Version == version && job.Status != AnalysisFailed {
+		return nil
+	}
+
+	switch job.Status {
+	case AnalysisPending:
+		// Still queued; the worker picks up the latest incident
+		job.incident, job.Version = incident, version
+		return nil
+	case AnalysisRunning:
+		job.incident, job.Version, job.rerun = incident, version, true
+		return nil
+	}
+
+	select {
+	case q.queue <- incident.ID:
+		job.incident, job.Version = incident, version
+		job.Status = AnalysisPending
+		job.EnqueuedAt = now
+		return nil
+	default:
+		if !ok {
+			delete(q.jobs, incident.ID)
+		}
+		return ErrAnalysisQueueFull
+	}
+}
+
+// Job returns the analysis job of an incident
+func (q *AnalysisQueue) Job(incidentID string) (AnalysisJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[incidentID]
+	if !ok {
+		return AnalysisJob{}, false
+	}
+	return job.AnalysisJob, true
+}
+
+// Subscribe returns a channel receiving every job that finishes, and a function that
+// ends the subscription. Jobs are dropped for subscribers that don't keep up.
+func (q *AnalysisQueue) Subscribe() (<-chan AnalysisJob, func()) {
+	ch := make(chan AnalysisJob, 16)
+
+	q.mu.Lock()
+	q.subscribers[ch] = struct{}{}
+	q.mu.Unlock()
+
+	return ch, func() {
+		q.mu.Lock()
+		delete(q.subscribers, ch)
+		q.mu.Unlock()
+	}
+}
+
+// Run analyzes queued incidents until ctx is cancelled
+func (q *AnalysisQueue) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < q.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case id := <-q.queue:
+					q.process(ctx, id)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// process runs the pending job of an incident
+func (q *AnalysisQueue) process(ctx context.Context, incidentID string) {
+	q.mu.Lock()
+	job, ok := q.jobs[incidentID]
+	if !ok || job.Status != AnalysisPending {
+		q.mu.Unlock()
+		return
+	}
+	started := time.Now()
+	job.Status = AnalysisRunning
+	job.StartedAt = &started
+	job.CompletedAt = nil
+	incident := job.incident
+	version := job.Version
+	q.mu.Unlock()
+
+	analyzeCtx, cancel := context.WithTimeout(ctx, q.timeout)
+	result, err := q.analyze(analyzeCtx, incident)
+	cancel()
+
+	q.mu.Lock()
+	completed := time.Now()
+	job.CompletedAt = &completed
+	if err != nil {
+		job.Status = AnalysisFailed
+		job.Error = err.Error()
+		log.Printf("⚠️  Analysis of incident %s failed: %v", incidentID, err)
+	} else {
+		job.Status = AnalysisCompleted
+		job.Error = ""
+		job.Result = result
+		job.AnalyzedVersion = version
+	}
+	finished := job.AnalysisJob
+	for ch := range q.subscribers {
+		select {
+		case ch <- finished:
+		default:
+		}
+	}
+
+	if job.rerun {
+		job.rerun = false
+		select {
+		case q.queue <- incidentID:
+			job.Status = AnalysisPending
+			job.EnqueuedAt = completed
+		default:
+			job.Version = version // Queued again by the next update
+			log.Printf("⚠️  Analysis queue full, incident %s keeps its previous analysis", incidentID)
+		}
+	}
+	q.mu.Unlock()
+}
+
+// pruneLocked forgets jobs that finished longer than finishedRetention ago
+func (q *AnalysisQueue) pruneLocked(now time.Time) {
+	for id, job := range q.jobs {
+		if job.CompletedAt != nil && (job.Status == AnalysisCompleted || job.Status == AnalysisFailed) &&
+			now.Sub(*job.CompletedAt) > finishedRetention {
+			delete(q.jobs, id)
+		}
+	}
+}
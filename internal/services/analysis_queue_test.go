@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func TestAnalysisQueue_AnalyzesEachVersionOnce(t *testing.T) {
+	var mu sync.Mutex
+	analyzed := map[string]int{}
+	release := make(chan struct{})
+	queue := NewAnalysisQueue(func(ctx context.Context, incident domain.Incident) (any, error) {
+		<-release
+		mu.Lock()
+		defer mu.Unlock()
+		analyzed[AnalysisVersion(incident)]++
+		if incident.ID == "broken" {
+			return nil, errors.New("model unavailable")
+		}
+		return len(incident.Events), nil
+	}, 1, 10, time.Second)
+
+	updates, unsubscribe := queue.Subscribe()
+	defer unsubscribe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx)
+
+	incident := domain.Incident{ID: "inc-1", Status: domain.StatusWarning, Events: []domain.Alert{{ID: "a1"}}}
+	if err := queue.Enqueue(incident); err != nil {
+		t.Fatal(err)
+	}
+	// Queuing the same version again is a no-op
+	if err := queue.Enqueue(incident); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the worker to pick up the job, then change the incident while it runs
+	deadline := time.Now().Add(time.Second)
+	for job, _ := queue.Job("inc-1"); job.Status != AnalysisRunning; job, _ = queue.Job("inc-1") {
+		if time.Now().After(deadline) {
+			t.Fatal("job never started")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	updated := incident
+	updated.Status = domain.StatusCritical
+	updated.Events = append(updated.Events, domain.Alert{ID: "a2"})
+	queue.Enqueue(updated)
+	queue.Enqueue(domain.Incident{ID: "broken", Events: []domain.Alert{{ID: "b1"}}})
+	close(release)
+
+	var finished []AnalysisJob
+	for len(finished) < 3 {
+		select {
+		case job := <-updates:
+			finished = append(finished, job)
+		case <-time.After(time.Second):
+			t.Fatalf("expected 3 finished jobs, got %+v", finished)
+		}
+	}
+
+	job, _ := queue.Job("inc-1")
+	if job.Status != AnalysisCompleted || !job.Current(updated) || job.Current(incident) || job.Result != 2 {
+		t.Errorf("expected the updated incident to be analyzed, got %+v", job)
+	}
+	if job, _ := queue.Job("broken"); job.Status != AnalysisFailed || job.Error != "model unavailable" {
+		t.Errorf("expected the broken incident to fail, got %+v", job)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if analyzed[AnalysisVersion(incident)] != 1 || analyzed[AnalysisVersion(updated)] != 1 {
+		t.Errorf("expected each version to be analyzed once, got %v", analyzed)
+	}
+}
+
+func TestAnalysisQueue_Full(t *testing.T) {
+	queue := NewAnalysisQueue(func(context.Context, domain.Incident) (any, error) { return nil, nil }, 1, 1, time.Second)
+
+	if err := queue.Enqueue(domain.Incident{ID: "inc-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Enqueue(domain.Incident{ID: "inc-2"}); !errors.Is(err, ErrAnalysisQueueFull) {
+		t.Errorf("expected ErrAnalysisQueueFull, got %v", err)
+	}
+	if _, ok := queue.Job("inc-2"); ok {
+		t.Error("expected no job for the incident that didn't fit")
+	}
+}
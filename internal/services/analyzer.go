@@ -59,6 +59,7 @@ var propagationRules = []PropagationRule{
 // IncidentAnalyzer provides SRE-grade incident analysis
 type IncidentAnalyzer struct {
 	propagationRules []PropagationRule
+	learner          *PropagationLearner
 }
 
 // NewIncidentAnalyzer creates a new analyzer instance
@@ -68,25 +69,52 @@ func NewIncidentAnalyzer() *IncidentAnalyzer {
 	}
 }
 
+// SetPropagationLearner makes causality detection use propagation patterns learned from
+// history where available, falling back to the standard rules
+func (a *IncidentAnalyzer) SetPropagationLearner(learner *PropagationLearner) {
+	a.learner = learner
+}
+
 // AnalyzeIncident takes a list of alerts and produces an ordered timeline with causality
 func (a *IncidentAnalyzer) AnalyzeIncident(alerts []domain.Alert) []domain.TimelineEntry {
 	if len(alerts) == 0 {
 		return []domain.TimelineEntry{}
 	}
 
+	timeline, _ := a.ExtendTimeline(nil, alerts)
+	return timeline
+}
+
+// ExtendTimeline returns the timeline entries of alerts added to an incident whose
+// earlier alerts already have entries. Causes are only detected for the added alerts,
+// so keeping a stored timeline up to date costs O(n) per batch rather than a full
+// analysis. It returns false if an added alert predates the latest earlier alert,
+// in which case the timeline must be rebuilt with AnalyzeIncident.
+func (a *IncidentAnalyzer) ExtendTimeline(earlier, added []domain.Alert) ([]domain.TimelineEntry, bool) {
+	if len(added) == 0 {
+		return []domain.TimelineEntry{}, true
+	}
+
 	// Sort alerts chronologically
-	sortedAlerts := make([]domain.Alert, len(alerts))
-	copy(sortedAlerts, alerts)
-	sort.Slice(sortedAlerts, func(i, j int) bool {
-		return sortedAlerts[i].OccurredAt.Before(sortedAlerts[j].OccurredAt)
-	})
+	sortedEarlier := sortAlertsByTime(earlier)
+	sortedAlerts := sortAlertsByTime(added)
+	if len(sortedEarlier) > 0 && sortedAlerts[0].OccurredAt.Before(sortedEarlier[len(sortedEarlier)-1].OccurredAt) {
+		return nil, false
+	}
 
-	// Track active resource issues (resource type -> alert that triggered it)
+	// Track active resource issues (resource type -> alert that triggered it),
+	// replaying the earlier alerts without detecting their causes again
 	activeIssues := make(map[domain.ResourceType]*domain.Alert)
+	for i := range sortedEarlier {
+		a.updateActiveIssues(activeIssues, &sortedEarlier[i])
+	}
 
 	// Build timeline
 	timeline := make([]domain.TimelineEntry, 0, len(sortedAlerts))
 	incidentStart := sortedAlerts[0].OccurredAt
+	if len(sortedEarlier) > 0 {
+		incidentStart = sortedEarlier[0].OccurredAt
+	}
 
 	for i := range sortedAlerts {
 		alert := &sortedAlerts[i]
@@ -97,7 +125,17 @@ func (a *IncidentAnalyzer) AnalyzeIncident(alerts []domain.Alert) []domain.Timel
 		a.updateActiveIssues(activeIssues, alert)
 	}
 
-	return timeline
+	return timeline, true
+}
+
+// sortAlertsByTime returns a chronologically sorted copy of alerts
+func sortAlertsByTime(alerts []domain.Alert) []domain.Alert {
+	sorted := make([]domain.Alert, len(alerts))
+	copy(sorted, alerts)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].OccurredAt.Before(sorted[j].OccurredAt)
+	})
+	return sorted
 }
 
 // createTimelineEntry generates a timeline entry with causality detection
@@ -109,6 +147,20 @@ func (a *IncidentAnalyzer) createTimelineEntry(
 	// Calculate duration since incident start
 	duration := alert.OccurredAt.Sub(incidentStart)
 
+	// Alerts of an unreachable host are grouped under the alert reporting it
+	if root := SuppressedBy(*alert); root != "" {
+		return domain.TimelineEntry{
+			Timestamp:          alert.OccurredAt,
+			Type:               "SUPPRESSED",
+			Message:            formatMessage(alert, nil) + "\n  ↳ Suppressed: the host was unreachable",
+			Severity:           "info",
+			DurationSinceStart: &duration,
+			CausedBy:           []string{root},
+			RelatedAlertIDs:    []string{alert.ID},
+			ResourceType:       alert.ResourceType,
+		}
+	}
+
 	// Detect potential causes
 	causes := a.detectCauses(alert, activeIssues)
 
@@ -132,36 +184,62 @@ func (a *IncidentAnalyzer) detectCauses(
 	alert *domain.Alert,
 	activeIssues map[domain.ResourceType]*domain.Alert,
 ) []*domain.Alert {
-	var causes []*domain.Alert
+	// Check active issues on other resources, oldest first
+	sources := make([]*domain.Alert, 0, len(activeIssues))
+	for resource, sourceAlert := range activeIssues {
+		if resource != alert.ResourceType {
+			sources = append(sources, sourceAlert)
+		}
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].OccurredAt.Before(sources[j].OccurredAt)
+	})
 
-	// Check each propagation rule
-	for _, rule := range a.propagationRules {
-		// Only check if this alert matches the "To" resource type
-		if rule.To != alert.ResourceType {
+	var causes []*domain.Alert
+	for _, sourceAlert := range sources {
+		// Verify the source is still in a problem state
+		if sourceAlert.Status == domain.StatusClear {
 			continue
 		}
 
-		// Check if there's an active issue of the "From" type
-		if sourceAlert, exists := activeIssues[rule.From]; exists {
-			// Verify time window
-			timeSince := alert.OccurredAt.Sub(sourceAlert.OccurredAt)
-			if timeSince >= 0 && timeSince <= rule.MaxTimeWindow {
-				// Verify the source is still in a problem state
-				if sourceAlert.Status != domain.StatusClear {
-					causes = append(causes, sourceAlert)
-				}
-			}
+		// Verify time window
+		window, ok := a.propagationWindow(sourceAlert, alert)
+		timeSince := alert.OccurredAt.Sub(sourceAlert.OccurredAt)
+		if ok && timeSince >= 0 && timeSince <= window {
+			causes = append(causes, sourceAlert)
 		}
 	}
 
 	return causes
 }
 
+// propagationWindow reports whether an issue like source can cause one like alert, and
+// within how long. Patterns learned on the alert's host take precedence over the static
+// rules; a learned pattern that is too unlikely rules the propagation out.
+func (a *IncidentAnalyzer) propagationWindow(source, alert *domain.Alert) (time.Duration, bool) {
+	if a.learner != nil && source.Host == alert.Host {
+		if pattern, ok := a.learner.Lookup(*alert, source.ResourceType, alert.ResourceType); ok {
+			return pattern.Window, pattern.Probability >= minCausalProbability
+		}
+	}
+
+	for _, rule := range a.propagationRules {
+		if rule.From == source.ResourceType && rule.To == alert.ResourceType {
+			return rule.MaxTimeWindow, true
+		}
+	}
+	return 0, false
+}
+
 // updateActiveIssues maintains the state of ongoing resource issues
 func (a *IncidentAnalyzer) updateActiveIssues(
 	activeIssues map[domain.ResourceType]*domain.Alert,
 	alert *domain.Alert,
 ) {
+	// Suppressed alerts are symptoms of their host being down, not causes of later issues
+	if SuppressedBy(*alert) != "" {
+		return
+	}
 	if alert.Status == domain.StatusClear {
 		// Remove from active issues when cleared
 		delete(activeIssues, alert.ResourceType)
@@ -198,6 +276,9 @@ func formatMessage(alert *domain.Alert, causes []*domain.Alert) string {
 		baseMsg = fmt.Sprintf("[%s@%s] %s on %s (value: %.2f)",
 			alert.ResourceType, alert.Host, alert.Name, alert.Chart, alert.Value)
 	}
+	if trend := DescribeSamples(*alert); trend != "" {
+		baseMsg += "; " + trend
+	}
 
 	if len(causes) > 0 {
 		// Add causality information
@@ -277,7 +358,7 @@ func (a *IncidentAnalyzer) GenerateIncidentSummary(timeline []domain.TimelineEnt
 	for i, entry := range timeline {
 		summary += fmt.Sprintf("%d. [%s] %s - %s\n",
 			i+1,
-			entry.Timestamp.Format("15:04:05"),
+			entry.Timestamp.UTC().Format(ClockLayout),
 			entry.Type,
 			entry.Message)
 	}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -99,3 +100,266 @@ func TestIncidentAnalyzer_CascadeDetection(t *testing.T) {
 		t.Error("Expected cascade detection but none found")
 	}
 }
+
+func TestSREAnalyzer_RecentChangeBoostsRootCause(t *testing.T) {
+	now := time.Now()
+	alerts := []domain.Alert{
+		{
+			ID:           "alert-1",
+			Name:         "cpu_high",
+			Status:       domain.StatusWarning,
+			OldStatus:    domain.StatusClear,
+			ResourceType: domain.ResourceCPU,
+			Host:         "web-01",
+			Chart:        "system.cpu",
+			OccurredAt:   now,
+		},
+		{
+			ID:           "alert-2",
+			Name:         "db_connections_high",
+			Status:       domain.StatusWarning,
+			OldStatus:    domain.StatusClear,
+			ResourceType: domain.ResourceProcess,
+			Host:         "db-primary-01",
+			Chart:        "postgres.connections",
+			OccurredAt:   now.Add(30 * time.Second),
+		},
+	}
+
+	tracker := NewChangeTracker(10)
+	tracker.Record(domain.ChangeEvent{
+		Type:       domain.ChangeDeploy,
+		Host:       "db-primary-01",
+		Version:    "v2.3.1",
+		OccurredAt: now.Add(-4 * time.Minute),
+	})
+
+	analyzer := NewSREAnalyzer()
+	analyzer.SetChangeTracker(tracker)
+	explanation := analyzer.AnalyzeIncidentForSRE(alerts)
+
+	if explanation.RootCause.Alert.Host != "db-primary-01" {
+		t.Errorf("Expected root cause on db-primary-01, got %s", explanation.RootCause.Alert.Host)
+	}
+
+	found := false
+	for _, e := range explanation.RootCause.Evidence {
+		if e == "deploy v2.3.1 to db-primary-01 4 minutes before first alert" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected deploy evidence, got %v", explanation.RootCause.Evidence)
+	}
+}
+
+func TestSREAnalyzer_PinnedRootCauseWins(t *testing.T) {
+	now := time.Now()
+	incident := domain.Incident{
+		ID: "inc-1",
+		Events: []domain.Alert{
+			{ID: "alert-1", Status: domain.StatusCritical, OldStatus: domain.StatusClear,
+				ResourceType: domain.ResourceCPU, Host: "web-01", OccurredAt: now},
+			{ID: "alert-2", Status: domain.StatusWarning, OldStatus: domain.StatusClear,
+				ResourceType: domain.ResourceDisk, Host: "db-01", OccurredAt: now.Add(time.Minute)},
+		},
+		RootCauseOverride: &domain.RootCauseOverride{
+			IncidentID: "inc-1", AlertID: "alert-2", Explanation: "log volume filled up", SetBy: "alice",
+		},
+	}
+
+	explanation := NewSREAnalyzer().ExplainIncident(incident)
+
+	root := explanation.RootCause
+	if root.Alert.ID != "alert-2" || !root.Pinned || root.ConfidenceScore != 100 {
+		t.Fatalf("Expected pinned alert-2 as root cause, got %+v", root)
+	}
+	if root.Reasoning != "Pinned as the root cause by alice: log volume filled up" {
+		t.Errorf("Unexpected reasoning %q", root.Reasoning)
+	}
+	for _, candidate := range explanation.AlternativeCauses {
+		if candidate.Alert.ID == "alert-2" {
+			t.Errorf("Expected the pinned alert not among the alternatives, got %+v", explanation.AlternativeCauses)
+		}
+	}
+}
+
+func TestIncidentBuilder_HostStrategySeparatesHosts(t *testing.T) {
+	now := time.Now()
+	alerts := []domain.Alert{
+		{ID: "a1", Host: "web-01", Status: domain.StatusWarning, OccurredAt: now},
+		{ID: "a2", Host: "db-01", Status: domain.StatusCritical, OccurredAt: now.Add(time.Minute)},
+		{ID: "a3", Host: "web-01", Status: domain.StatusCritical, OccurredAt: now.Add(2 * time.Minute)},
+	}
+
+	builder := NewIncidentBuilder(15 * time.Minute)
+	if got := len(builder.Build(alerts)); got != 1 {
+		t.Fatalf("Expected window strategy to merge all alerts into 1 incident, got %d", got)
+	}
+
+	strategy, err := NewCorrelationStrategy(CorrelationHostAndWindow, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	builder.SetStrategy(strategy)
+
+	incidents := builder.Build(alerts)
+	if len(incidents) != 2 {
+		t.Fatalf("Expected 2 incidents (one per host), got %d", len(incidents))
+	}
+	if incidents[0].Events[0].Host != "web-01" || len(incidents[0].Events) != 2 {
+		t.Errorf("Expected first incident to hold both web-01 alerts, got %+v", incidents[0].Events)
+	}
+}
+
+func TestIncidentBuilder_EnvironmentScope(t *testing.T) {
+	now := time.Now()
+	alerts := []domain.Alert{
+		{ID: "a1", Host: "db-01", Status: domain.StatusCritical, OccurredAt: now, Labels: map[string]string{"env": "prod"}},
+		{ID: "a2", Host: "db-01.staging", Status: domain.StatusCritical, OccurredAt: now.Add(time.Minute), Labels: map[string]string{"env": "staging"}},
+		{ID: "a3", Host: "web-01", Status: domain.StatusWarning, OccurredAt: now.Add(2 * time.Minute), Labels: map[string]string{"env": "prod"}},
+	}
+
+	builder := NewIncidentBuilder(15 * time.Minute)
+	builder.SetStrategy(ScopeByEnvironment(windowStrategy{}, func(alert domain.Alert) string { return alert.Labels["env"] }))
+
+	incidents := builder.Build(alerts)
+	if len(incidents) != 2 {
+		t.Fatalf("Expected 2 incidents (one per environment), got %d", len(incidents))
+	}
+	for _, incident := range incidents {
+		for _, event := range incident.Events {
+			if event.Labels["env"] != incident.Events[0].Labels["env"] {
+				t.Errorf("incident %s mixes environments: %+v", incident.ID, incident.Events)
+			}
+		}
+	}
+}
+
+func TestIncidentAnalyzer_LearnedPropagation(t *testing.T) {
+	start := time.Now().Add(-48 * time.Hour)
+	alert := func(id, host string, resource domain.ResourceType, at time.Time) domain.Alert {
+		return domain.Alert{
+			ID:           id,
+			Status:       domain.StatusWarning,
+			OldStatus:    domain.StatusClear,
+			ResourceType: resource,
+			Host:         host,
+			OccurredAt:   at,
+		}
+	}
+
+	// db-01: disk issues are followed by network issues 9 times out of 10.
+	// web-01: memory issues are followed by CPU issues only 2 times out of 10.
+	var history []domain.Alert
+	for i := 0; i < 10; i++ {
+		round := start.Add(time.Duration(i) * time.Hour)
+		history = append(history, alert("db-disk", "db-01", domain.ResourceDisk, round))
+		if i < 9 {
+			delay := time.Duration(i%4+1) * time.Minute
+			history = append(history, alert("db-net", "db-01", domain.ResourceNetwork, round.Add(delay)))
+		}
+		history = append(history, alert("web-mem", "web-01", domain.ResourceMemory, round))
+		if i < 2 {
+			history = append(history, alert("web-cpu", "web-01", domain.ResourceCPU, round.Add(time.Minute)))
+		}
+	}
+
+	learner := NewPropagationLearner(10*time.Minute, 5, 0)
+	learner.Learn(history, time.Now())
+
+	pattern, ok := learner.Lookup(domain.Alert{Host: "db-01"}, domain.ResourceDisk, domain.ResourceNetwork)
+	if !ok {
+		t.Fatal("Expected a learned disk→network pattern for db-01")
+	}
+	if want := "on db-01, disk→network with 90% likelihood within 4m0s"; pattern.String() != want {
+		t.Errorf("Expected %q, got %q", want, pattern.String())
+	}
+
+	analyzer := NewIncidentAnalyzer()
+	analyzer.SetPropagationLearner(learner)
+	now := time.Now()
+
+	// A learned pattern links resources the standard rules do not
+	timeline := analyzer.AnalyzeIncident([]domain.Alert{
+		alert("disk-1", "db-01", domain.ResourceDisk, now),
+		alert("net-1", "db-01", domain.ResourceNetwork, now.Add(2*time.Minute)),
+	})
+	if len(timeline[1].CausedBy) != 1 || timeline[1].CausedBy[0] != "disk-1" {
+		t.Errorf("Expected network issue to be caused by disk-1, got %v", timeline[1].CausedBy)
+	}
+
+	// An unlikely learned pattern overrides the standard memory→CPU rule
+	timeline = analyzer.AnalyzeIncident([]domain.Alert{
+		alert("mem-1", "web-01", domain.ResourceMemory, now),
+		alert("cpu-1", "web-01", domain.ResourceCPU, now.Add(time.Minute)),
+	})
+	if len(timeline[1].CausedBy) != 0 {
+		t.Errorf("Expected no cause for web-01 CPU issue, got %v", timeline[1].CausedBy)
+	}
+}
+
+// timelineStore is an in-memory ports.TimelineStore counting full rewrites
+type timelineStore struct {
+	entries  map[string][]domain.TimelineEntry
+	replaced int
+}
+
+func (s *timelineStore) GetTimelineEntries(ctx context.Context, incidentID string) ([]domain.TimelineEntry, error) {
+	return append([]domain.TimelineEntry{}, s.entries[incidentID]...), nil
+}
+
+func (s *timelineStore) AppendTimelineEntries(ctx context.Context, incidentID string, entries []domain.TimelineEntry) error {
+	s.entries[incidentID] = append(s.entries[incidentID], entries...)
+	return nil
+}
+
+func (s *timelineStore) ReplaceTimelineEntries(ctx context.Context, incidentID string, entries []domain.TimelineEntry) error {
+	s.entries[incidentID] = append([]domain.TimelineEntry{}, entries...)
+	s.replaced++
+	return nil
+}
+
+func TestTimelineRecorder_AppendsNewAlerts(t *testing.T) {
+	analyzer := NewIncidentAnalyzer()
+	store := &timelineStore{entries: map[string][]domain.TimelineEntry{}}
+	recorder := NewTimelineRecorder(analyzer, store)
+	ctx := context.Background()
+
+	now := time.Now()
+	memory := domain.Alert{ID: "mem", Name: "ram_usage", Host: "db-01", Status: domain.StatusWarning,
+		OldStatus: domain.StatusClear, ResourceType: domain.ResourceMemory, OccurredAt: now}
+	disk := domain.Alert{ID: "disk", Name: "disk_io", Host: "db-01", Status: domain.StatusCritical,
+		OldStatus: domain.StatusClear, ResourceType: domain.ResourceDisk, OccurredAt: now.Add(2 * time.Minute)}
+	early := domain.Alert{ID: "cpu", Name: "cpu_usage", Host: "db-01", Status: domain.StatusWarning,
+		OldStatus: domain.StatusClear, ResourceType: domain.ResourceCPU, OccurredAt: now.Add(-time.Minute)}
+
+	incident := domain.Incident{ID: "inc-1", Events: []domain.Alert{memory}}
+	if _, err := recorder.Record(ctx, incident); err != nil {
+		t.Fatal(err)
+	}
+
+	// The new alert is appended with the cause detected against the recorded one
+	incident.Events = append(incident.Events, disk)
+	timeline, err := recorder.Record(ctx, incident)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timeline) != 2 || len(store.entries["inc-1"]) != 2 || store.replaced != 0 {
+		t.Fatalf("expected an appended entry, got %d entries (%d replaced)", len(timeline), store.replaced)
+	}
+	full := analyzer.AnalyzeIncident(incident.Events)
+	if len(timeline[1].CausedBy) != 1 || timeline[1].CausedBy[0] != "mem" || timeline[1].Message != full[1].Message {
+		t.Errorf("incremental entry differs from a full analysis: %+v vs %+v", timeline[1], full[1])
+	}
+
+	// An alert older than the recorded ones forces a rebuild
+	incident.Events = append(incident.Events, early)
+	timeline, err = recorder.Record(ctx, incident)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.replaced != 1 || len(timeline) != 3 || timeline[0].RelatedAlertIDs[0] != "cpu" {
+		t.Errorf("expected a rebuilt timeline starting with the early alert, got %+v", timeline)
+	}
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// AnomalyType categorizes a detected anomaly
+type AnomalyType string
+
+const (
+	AnomalyBurst AnomalyType = "burst" // Alert rate of a host/resource far above its baseline
+	AnomalyNovel AnomalyType = "novel" // Alert name never seen before
+)
+
+// Anomaly is an unusual alert burst or a never-before-seen alert
+type Anomaly struct {
+	Type         AnomalyType
+	Host         string
+	ResourceType domain.ResourceType
+	AlertName    string
+	AlertID      string   // Alert at which the anomaly was detected
+	AlertIDs     []string // All alerts that are part of the anomaly
+	DetectedAt   time.Time
+	Count        int     // Alerts in the burst bucket
+	Baseline     float64 // Mean alerts per bucket over the baseline window
+	Score        float64 // 0.0-1.0
+	Message      string
+}
+
+// AnomalyDetector tracks baseline alert rates per host/resource in fixed-size buckets and
+// flags buckets whose count is far above the baseline mean, as well as alert names that
+// have never been seen before. Both need a full baseline window of history first.
+type AnomalyDetector struct {
+	bucket    time.Duration // Rate bucket size, e.g. 5m
+	baseline  time.Duration // History the baseline rate is computed over, e.g. 24h
+	threshold float64       // Standard deviations above the mean that make a burst
+	minBurst  int           // Minimum alerts in a bucket to count as a burst
+
+	mu        sync.RWMutex
+	counts    map[anomalyKey]map[int64][]string // host/resource -> bucket -> alert IDs
+	names     map[string]bool
+	firstSeen time.Time
+	latest    time.Time
+	anomalies []Anomaly
+	bursts    map[anomalyKey]int64 // Last bucket flagged per host/resource
+}
+
+type anomalyKey struct {
+	host     string
+	resource domain.ResourceType
+}
+
+// NewAnomalyDetector creates an anomaly detector
+func NewAnomalyDetector(bucket, baseline time.Duration, threshold float64, minBurst int) *AnomalyDetector {
+	return &AnomalyDetector{
+		bucket:    bucket,
+		baseline:  baseline,
+		threshold: threshold,
+		minBurst:  minBurst,
+		counts:    make(map[anomalyKey]map[int64][]string),
+		names:     make(map[string]bool),
+		bursts:    make(map[anomalyKey]int64),
+	}
+}
+
+// Observe feeds alerts into the baselines and returns the anomalies they caused.
+// Alerts may be historical; they are processed in time order.
+func (d *AnomalyDetector) Observe(alerts []domain.Alert) []Anomaly {
+	sorted := make([]domain.Alert, len(alerts))
+	copy(sorted, alerts)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].OccurredAt.Before(sorted[j].OccurredAt)
+	})
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var detected []Anomaly
+	for _, alert := range sorted {
+		// Only raised alerts count towards the rate; clears are part of the same issue
+		if alert.Status != domain.StatusWarning && alert.Status != domain.StatusCritical {
+			continue
+		}
+
+		if d.firstSeen.IsZero() {
+			d.firstSeen = alert.OccurredAt
+		}
+		if alert.OccurredAt.After(d.latest) {
+			d.latest = alert.OccurredAt
+		}
+		warmedUp := alert.OccurredAt.Sub(d.firstSeen) >= d.baseline
+
+		if alert.Name != "" && !d.names[alert.Name] {
+			d.names[alert.Name] = true
+			if warmedUp {
+				detected = append(detected, d.record(Anomaly{
+					Type:         AnomalyNovel,
+					Host:         alert.Host,
+					ResourceType: alert.ResourceType,
+					AlertName:    alert.Name,
+					AlertID:      alert.ID,
+					AlertIDs:     []string{alert.ID},
+					DetectedAt:   alert.OccurredAt,
+					Count:        1,
+					Score:        0.6,
+					Message:      fmt.Sprintf("Never-before-seen alert %s on %s", alert.Name, alert.Host),
+				}))
+			}
+		}
+
+		if anomaly, ok := d.observeRate(alert, warmedUp); ok {
+			detected = append(detected, anomaly)
+		}
+	}
+
+	d.prune()
+	return detected
+}
+
+// observeRate counts the alert in its bucket and reports a burst the first time the
+// bucket count exceeds the baseline. Later alerts in a flagged bucket join that anomaly.
+func (d *AnomalyDetector) observeRate(alert domain.Alert, warmedUp bool) (Anomaly, bool) {
+	key := anomalyKey{host: alert.Host, resource: alert.ResourceType}
+	index := alert.OccurredAt.UnixNano() / int64(d.bucket)
+
+	buckets, ok := d.counts[key]
+	if !ok {
+		buckets = make(map[int64][]string)
+		d.counts[key] = buckets
+	}
+	buckets[index] = append(buckets[index], alert.ID)
+	count := len(buckets[index])
+
+	if last, flagged := d.bursts[key]; flagged && last == index {
+		for i := len(d.anomalies) - 1; i >= 0; i-- {
+			a := &d.anomalies[i]
+			if a.Type == AnomalyBurst && a.Host == key.host && a.ResourceType == key.resource {
+				a.Count = count
+				a.AlertIDs = append(a.AlertIDs, alert.ID)
+				break
+			}
+		}
+		return Anomaly{}, false
+	}
+
+	if !warmedUp || count < d.minBurst {
+		return Anomaly{}, false
+	}
+
+	mean, stddev := d.baselineRate(buckets, index)
+	z := (float64(count) - mean) / math.Max(stddev, 1)
+	if z < d.threshold {
+		return Anomaly{}, false
+	}
+
+	d.bursts[key] = index
+	ids := make([]string, count)
+	copy(ids, buckets[index])
+	return d.record(Anomaly{
+		Type:         AnomalyBurst,
+		Host:         alert.Host,
+		ResourceType: alert.ResourceType,
+		AlertName:    alert.Name,
+		AlertID:      alert.ID,
+		AlertIDs:     ids,
+		DetectedAt:   alert.OccurredAt,
+		Count:        count,
+		Baseline:     mean,
+		Score:        math.Min(0.5+(z-d.threshold)/(2*d.threshold), 1.0),
+		Message: fmt.Sprintf("Alert burst on %s: %d %s alerts in %s (baseline %.1f)",
+			alert.Host, count, alert.ResourceType, d.bucket, mean),
+	}), true
+}
+
+// baselineRate returns the mean and standard deviation of the bucket counts over the
+// baseline window before the given bucket; empty buckets count as zero
+func (d *AnomalyDetector) baselineRate(buckets map[int64][]string, index int64) (float64, float64) {
+	n := int64(d.baseline / d.bucket)
+	if n < 1 {
+		n = 1
+	}
+
+	sum, sumSquares := 0.0, 0.0
+	for i := index - n; i < index; i++ {
+		c := float64(len(buckets[i]))
+		sum += c
+		sumSquares += c * c
+	}
+
+	mean := sum / float64(n)
+	variance := sumSquares/float64(n) - mean*mean
+	return mean, math.Sqrt(math.Max(variance, 0))
+}
+
+func (d *AnomalyDetector) record(anomaly Anomaly) Anomaly {
+	d.anomalies = append(d.anomalies, anomaly)
+	return anomaly
+}
+
+// prune drops buckets and anomalies that have fallen out of the baseline window
+func (d *AnomalyDetector) prune() {
+	cutoff := d.latest.Add(-d.baseline - d.bucket)
+	oldest := cutoff.UnixNano() / int64(d.bucket)
+
+	for key, buckets := range d.counts {
+		for index := range buckets {
+			if index < oldest {
+				delete(buckets, index)
+			}
+		}
+		if len(buckets) == 0 {
+			delete(d.counts, key)
+		}
+	}
+
+	kept := d.anomalies[:0]
+	for _, anomaly := range d.anomalies {
+		if anomaly.DetectedAt.After(cutoff) {
+			kept = append(kept, anomaly)
+		}
+	}
+	d.anomalies = kept
+}
+
+// Recent returns the anomalies detected since the given time, newest first
+func (d *AnomalyDetector) Recent(since time.Time) []Anomaly {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	anomalies := []Anomaly{}
+	for i := len(d.anomalies) - 1; i >= 0; i-- {
+		if !d.anomalies[i].DetectedAt.Before(since) {
+			anomalies = append(anomalies, d.copyAnomaly(i))
+		}
+	}
+	return anomalies
+}
+
+// ForAlerts returns the anomalies any of the given alerts are part of, oldest first
+func (d *AnomalyDetector) ForAlerts(alerts []domain.Alert) []Anomaly {
+	ids := make(map[string]bool, len(alerts))
+	for _, alert := range alerts {
+		ids[alert.ID] = true
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var anomalies []Anomaly
+	for i, anomaly := range d.anomalies {
+		for _, id := range anomaly.AlertIDs {
+			if ids[id] {
+				anomalies = append(anomalies, d.copyAnomaly(i))
+				break
+			}
+		}
+	}
+	return anomalies
+}
+
+// AnomalyScore returns the highest score of the anomalies among the alerts, or 0
+func (d *AnomalyDetector) AnomalyScore(alerts []domain.Alert) float64 {
+	score := 0.0
+	for _, anomaly := range d.ForAlerts(alerts) {
+		score = math.Max(score, anomaly.Score)
+	}
+	return score
+}
+
+func (d *AnomalyDetector) copyAnomaly(i int) Anomaly {
+	anomaly := d.anomalies[i]
+	anomaly.AlertIDs = append([]string(nil), anomaly.AlertIDs...)
+	return anomaly
+}
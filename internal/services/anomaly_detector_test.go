@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func TestAnomalyDetector_BurstAndNovelty(t *testing.T) {
+	detector := NewAnomalyDetector(5*time.Minute, time.Hour, 3, 5)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	alert := func(id, name string, at time.Time) domain.Alert {
+		return domain.Alert{
+			ID:           id,
+			Name:         name,
+			Host:         "db-01",
+			Status:       domain.StatusWarning,
+			ResourceType: domain.ResourceDisk,
+			OccurredAt:   at,
+		}
+	}
+
+	// Baseline: one disk alert every 10 minutes for two hours
+	var history []domain.Alert
+	for i := 0; i < 12; i++ {
+		history = append(history, alert(fmt.Sprintf("base-%d", i), "disk_util", start.Add(time.Duration(i)*10*time.Minute)))
+	}
+	if anomalies := detector.Observe(history); len(anomalies) != 0 {
+		t.Fatalf("Expected no anomalies in the baseline, got %+v", anomalies)
+	}
+
+	// Burst: eight alerts within one bucket, one of them never seen before
+	burstStart := start.Add(2*time.Hour + time.Minute)
+	var burst []domain.Alert
+	for i := 0; i < 7; i++ {
+		burst = append(burst, alert(fmt.Sprintf("burst-%d", i), "disk_util", burstStart.Add(time.Duration(i)*10*time.Second)))
+	}
+	burst = append(burst, alert("novel-1", "disk_inode_exhaustion", burstStart.Add(2*time.Minute)))
+
+	anomalies := detector.Observe(burst)
+	if len(anomalies) != 2 {
+		t.Fatalf("Expected a burst and a novel anomaly, got %+v", anomalies)
+	}
+	if anomalies[0].Type != AnomalyBurst || anomalies[0].AlertID != "burst-4" {
+		t.Errorf("Expected burst detected at the fifth alert, got %+v", anomalies[0])
+	}
+	if anomalies[1].Type != AnomalyNovel || anomalies[1].AlertName != "disk_inode_exhaustion" {
+		t.Errorf("Expected novel alert anomaly, got %+v", anomalies[1])
+	}
+
+	// Later alerts in the bucket join the burst instead of raising a new anomaly
+	recent := detector.Recent(burstStart)
+	if len(recent) != 2 || recent[1].Count != 8 {
+		t.Errorf("Expected the burst to grow to 8 alerts, got %+v", recent)
+	}
+	if score := detector.AnomalyScore([]domain.Alert{burst[6]}); score < 0.5 {
+		t.Errorf("Expected anomaly score of at least 0.5 for a burst alert, got %.2f", score)
+	}
+
+	// The timeline gets an anomaly event after the alert the burst was detected at
+	builder := NewEnhancedTimelineBuilder(NewAlertGrouper(15 * time.Minute))
+	builder.SetAnomalyDetector(detector)
+	timeline := builder.BuildTimeline(burst, nil)
+	if len(timeline.Events) != len(burst)+2 || timeline.Events[5].Type != "anomaly" {
+		t.Errorf("Expected anomaly event after burst-4, got %d events", len(timeline.Events))
+	}
+}
@@ -58,6 +58,8 @@ type ActionableFix struct {
 	RootCauseType   domain.ResourceType
 	FixComplexity   string // "Simple", "Moderate", "Complex"
 	EstimatedTimeToResolve string
+	Playbook        string // ID of the organization playbook the fixes came from, if any
+	RunbookURL      string // Runbook linked by that playbook
 }
 
 // BlastRadiusAnalyzer provides enhanced impact analysis
@@ -112,12 +114,12 @@ func (b *BlastRadiusAnalyzer) AnalyzeBlastRadius(
 	}
 
 	// Estimate cascade depth
-	if len(resources) == 1 {
+	if cascading := len(cascadeResources(alerts)); cascading <= 1 {
 		maxDepth = 0
-	} else if len(resources) == 2 {
+	} else if cascading == 2 {
 		maxDepth = 1
 	} else {
-		maxDepth = len(resources) - 1
+		maxDepth = cascading - 1
 	}
 
 	duration := time.Duration(0)
@@ -272,7 +274,11 @@ func (b *BlastRadiusAnalyzer) identifyIndirectlyAffected(
 				isIndirect = true
 				evidence = append(evidence, 
 					fmt.Sprintf("Occurred %.0fs after root cause", timeDiff.Seconds()))
-				evidence = append(evidence, "Different resource type - likely cascade effect")
+				if SuppressedBy(*alert) != "" {
+					evidence = append(evidence, "Suppressed - the host was unreachable")
+				} else {
+					evidence = append(evidence, "Different resource type - likely cascade effect")
+				}
 			}
 		}
 
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/topology"
+)
+
+// BlastRadiusNode is a topology service, host or resource of a blast radius graph
+type BlastRadiusNode struct {
+	ID     string // "service:<name>", "host:<host>" or "resource:<host>:<type>"
+	Name   string
+	Type   string // "service", "host" or "resource"
+	Impact ComponentImpact
+	Alerts int // Alerts of the incident on the node
+}
+
+// BlastRadiusEdge connects two nodes: a service depending on another ("depends_on"), a
+// service running on a host ("runs_on") or a host with a resource ("has")
+type BlastRadiusEdge struct {
+	From string
+	To   string
+	Type string
+}
+
+// BlastRadiusGraph is the part of the topology an incident touches, with the impact of
+// each node, for rendering an impact map
+type BlastRadiusGraph struct {
+	Nodes []BlastRadiusNode // Services, then hosts, then resources, each sorted by ID
+	Edges []BlastRadiusEdge
+}
+
+// BuildBlastRadiusGraph builds the graph of the hosts and resources the alerts of an
+// incident are on, colored by the blast radius analysis. With a topology, the services
+// of those hosts are added with the services they depend on and those depending on them,
+// each with all its hosts; nodes without alerts are unaffected.
+func BuildBlastRadiusGraph(alerts []domain.Alert, analysis EnhancedBlastRadiusAnalysis, topo *topology.Topology) BlastRadiusGraph {
+	impacts := make(map[string]ComponentImpact)
+	for _, component := range analysis.IndirectlyAffected {
+		impacts[component.Type+":"+component.Name] = ImpactIndirect
+	}
+	for _, component := range analysis.DirectlyAffected {
+		impacts[component.Type+":"+component.Name] = ImpactDirect
+	}
+
+	nodes := make(map[string]*BlastRadiusNode)
+	var edges []BlastRadiusEdge
+	addNode := func(id, name, kind string) *BlastRadiusNode {
+		node, ok := nodes[id]
+		if !ok {
+			node = &BlastRadiusNode{ID: id, Name: name, Type: kind, Impact: ImpactNone}
+			nodes[id] = node
+		}
+		return node
+	}
+	edgeSeen := make(map[BlastRadiusEdge]bool)
+	addEdge := func(edge BlastRadiusEdge) {
+		if !edgeSeen[edge] {
+			edgeSeen[edge] = true
+			edges = append(edges, edge)
+		}
+	}
+
+	for _, alert := range alerts {
+		host := addNode("host:"+alert.Host, alert.Host, "host")
+		host.Alerts++
+		host.Impact = worstImpact(host.Impact, impacts["host:"+alert.Host])
+
+		name := fmt.Sprintf("%s on %s", alert.ResourceType, alert.Host)
+		resource := addNode(fmt.Sprintf("resource:%s:%s", alert.Host, alert.ResourceType), name, "resource")
+		resource.Alerts++
+		resource.Impact = worstImpact(resource.Impact, impacts["resource:"+name])
+		host.Impact = worstImpact(host.Impact, resource.Impact)
+		addEdge(BlastRadiusEdge{From: host.ID, To: resource.ID, Type: "has"})
+	}
+
+	// The services of the incident's hosts and their neighbours in the dependency graph
+	involved := make(map[string]bool)
+	for _, alert := range alerts {
+		if name, ok := topo.ServiceForHost(alert.Host); ok {
+			involved[name] = true
+		}
+	}
+	services := make(map[string]bool)
+	for name := range involved {
+		services[name] = true
+		svc, _ := topo.Service(name)
+		for _, dep := range svc.DependsOn {
+			services[dep] = true
+		}
+		for _, dependent := range topo.Dependents(name) {
+			services[dependent] = true
+		}
+	}
+	for name := range services {
+		svc, _ := topo.Service(name)
+		node := addNode("service:"+name, name, "service")
+		for _, host := range svc.Hosts {
+			hostNode := addNode("host:"+host, host, "host")
+			node.Alerts += hostNode.Alerts
+			node.Impact = worstImpact(node.Impact, hostNode.Impact)
+			addEdge(BlastRadiusEdge{From: node.ID, To: hostNode.ID, Type: "runs_on"})
+		}
+		for _, dep := range svc.DependsOn {
+			if services[dep] {
+				addEdge(BlastRadiusEdge{From: node.ID, To: "service:" + dep, Type: "depends_on"})
+			}
+		}
+	}
+
+	graph := BlastRadiusGraph{Nodes: make([]BlastRadiusNode, 0, len(nodes)), Edges: edges}
+	for _, node := range nodes {
+		graph.Nodes = append(graph.Nodes, *node)
+	}
+	order := map[string]int{"service": 0, "host": 1, "resource": 2}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		a, b := graph.Nodes[i], graph.Nodes[j]
+		if order[a.Type] != order[b.Type] {
+			return order[a.Type] < order[b.Type]
+		}
+		return a.ID < b.ID
+	})
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	return graph
+}
+
+// worstImpact returns the more severe of two impacts
+func worstImpact(a, b ComponentImpact) ComponentImpact {
+	rank := map[ComponentImpact]int{ImpactNone: 0, ImpactIndirect: 1, ImpactDirect: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/topology"
+)
+
+func TestBuildBlastRadiusGraph(t *testing.T) {
+	topo, err := topology.New([]topology.Service{
+		{Name: "web", Hosts: []string{"web-01", "web-02"}, DependsOn: []string{"db"}},
+		{Name: "db", Hosts: []string{"db-01"}},
+		{Name: "billing", Hosts: []string{"billing-01"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	alerts := []domain.Alert{
+		{ID: "a1", Host: "db-01", Chart: "disk.io", ResourceType: domain.ResourceDisk, Status: domain.StatusCritical, OccurredAt: start},
+		{ID: "a2", Host: "web-01", Chart: "system.cpu", ResourceType: domain.ResourceCPU, Status: domain.StatusWarning, OccurredAt: start.Add(time.Minute)},
+	}
+	analysis := NewBlastRadiusAnalyzer().AnalyzeBlastRadius(alerts, RootCauseCandidate{Alert: &alerts[0]})
+	graph := BuildBlastRadiusGraph(alerts, analysis, topo)
+
+	impacts := map[string]ComponentImpact{}
+	for _, node := range graph.Nodes {
+		impacts[node.ID] = node.Impact
+	}
+	want := map[string]ComponentImpact{
+		"service:db":          ImpactDirect,
+		"service:web":         ImpactIndirect,
+		"host:db-01":          ImpactDirect,
+		"host:web-01":         ImpactIndirect,
+		"host:web-02":         ImpactNone,
+		"resource:db-01:DISK": ImpactDirect,
+		"resource:web-01:CPU": ImpactIndirect,
+	}
+	if len(impacts) != len(want) {
+		t.Errorf("nodes: got %v, want %v", impacts, want)
+	}
+	for id, impact := range want {
+		if impacts[id] != impact {
+			t.Errorf("%s: got impact %q, want %q", id, impacts[id], impact)
+		}
+	}
+	if graph.Nodes[0].Type != "service" || graph.Nodes[len(graph.Nodes)-1].Type != "resource" {
+		t.Errorf("nodes not ordered services, hosts, resources: %+v", graph.Nodes)
+	}
+
+	edges := map[BlastRadiusEdge]bool{}
+	for _, edge := range graph.Edges {
+		edges[edge] = true
+	}
+	for _, edge := range []BlastRadiusEdge{
+		{From: "service:web", To: "service:db", Type: "depends_on"},
+		{From: "service:web", To: "host:web-02", Type: "runs_on"},
+		{From: "host:db-01", To: "resource:db-01:DISK", Type: "has"},
+	} {
+		if !edges[edge] {
+			t.Errorf("missing edge %+v in %+v", edge, graph.Edges)
+		}
+	}
+}
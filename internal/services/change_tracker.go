@@ -0,0 +1,116 @@
+package services
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
+	"incident-teller/internal/topology"
+)
+
+// ChangeTracker keeps a bounded, in-memory history of change events
+// (deployments, config changes, feature-flag flips) reported by CI/CD systems
+type ChangeTracker struct {
+	mu       sync.RWMutex
+	changes  []domain.ChangeEvent
+	maxSize  int
+	topology *topology.Topology
+}
+
+// NewChangeTracker creates a change tracker holding at most maxSize events
+func NewChangeTracker(maxSize int) *ChangeTracker {
+	if maxSize <= 0 {
+		maxSize = 1000
+	}
+	return &ChangeTracker{
+		changes: []domain.ChangeEvent{},
+		maxSize: maxSize,
+	}
+}
+
+// SetTopology maps hosts onto services for changes recorded without a host. Without a
+// topology the alert's "service" label is used.
+func (ct *ChangeTracker) SetTopology(topo *topology.Topology) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	ct.topology = topo
+}
+
+// Record stores a change event, evicting the oldest events when at capacity
+func (ct *ChangeTracker) Record(change domain.ChangeEvent) domain.ChangeEvent {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	if change.OccurredAt.IsZero() {
+		change.OccurredAt = time.Now()
+	}
+	if change.Type == "" {
+		change.Type = domain.ChangeDeploy
+	}
+	if change.ID == "" {
+		change.ID = idgen.New(change.OccurredAt)
+	}
+
+	ct.changes = append(ct.changes, change)
+	sort.SliceStable(ct.changes, func(i, j int) bool {
+		return ct.changes[i].OccurredAt.Before(ct.changes[j].OccurredAt)
+	})
+
+	if len(ct.changes) > ct.maxSize {
+		ct.changes = ct.changes[len(ct.changes)-ct.maxSize:]
+	}
+
+	return change
+}
+
+// Recent returns up to limit of the most recent change events, newest first
+func (ct *ChangeTracker) Recent(limit int) []domain.ChangeEvent {
+	ct.mu.RLock()
+	defer ct.mu.RUnlock()
+
+	if limit <= 0 || limit > len(ct.changes) {
+		limit = len(ct.changes)
+	}
+
+	result := make([]domain.ChangeEvent, 0, limit)
+	for i := len(ct.changes) - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, ct.changes[i])
+	}
+	return result
+}
+
+// ChangesBefore returns the changes on the hosts of the alerts that happened within
+// lookback before t. Changes without a host apply to the hosts of their service, taken
+// from the topology or the alert's "service" label; only changes marked fleet-wide
+// apply to every host.
+func (ct *ChangeTracker) ChangesBefore(alerts []domain.Alert, t time.Time, lookback time.Duration) []domain.ChangeEvent {
+	ct.mu.RLock()
+	defer ct.mu.RUnlock()
+
+	hostSet := make(map[string]bool)
+	serviceSet := make(map[string]bool)
+	for _, alert := range alerts {
+		hostSet[alert.Host] = true
+		if service, ok := ct.topology.ServiceForHost(alert.Host); ok {
+			serviceSet[service] = true
+		} else if service := alert.Labels["service"]; service != "" {
+			serviceSet[service] = true
+		}
+	}
+
+	result := []domain.ChangeEvent{}
+	for _, change := range ct.changes {
+		if change.OccurredAt.After(t) || t.Sub(change.OccurredAt) > lookback {
+			continue
+		}
+		switch {
+		case change.FleetWide,
+			change.Host != "" && hostSet[change.Host],
+			change.Host == "" && change.Service != "" && serviceSet[change.Service]:
+			result = append(result, change)
+		}
+	}
+	return result
+}
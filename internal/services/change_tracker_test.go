@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/topology"
+)
+
+func TestChangeTracker_ChangesBefore(t *testing.T) {
+	now := time.Now()
+	tracker := NewChangeTracker(10)
+	for _, change := range []domain.ChangeEvent{
+		{ID: "host", Host: "web-01", OccurredAt: now.Add(-time.Minute)},
+		{ID: "other-host", Host: "web-09", OccurredAt: now.Add(-time.Minute)},
+		{ID: "service", Service: "checkout", OccurredAt: now.Add(-time.Minute)},
+		{ID: "labeled", Service: "search", OccurredAt: now.Add(-time.Minute)},
+		{ID: "other-service", Service: "billing", OccurredAt: now.Add(-time.Minute)},
+		{ID: "fleet", Type: domain.ChangeFeatureFlag, FleetWide: true, OccurredAt: now.Add(-time.Minute)},
+		{ID: "too-old", Host: "web-01", OccurredAt: now.Add(-time.Hour)},
+		{ID: "after", Host: "web-01", OccurredAt: now.Add(time.Minute)},
+	} {
+		tracker.Record(change)
+	}
+
+	topo, err := topology.New([]topology.Service{{Name: "checkout", Hosts: []string{"web-01"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker.SetTopology(topo)
+
+	alerts := []domain.Alert{
+		{Host: "web-01"},
+		{Host: "search-01", Labels: map[string]string{"service": "search"}},
+	}
+	got := map[string]bool{}
+	for _, change := range tracker.ChangesBefore(alerts, now, 30*time.Minute) {
+		got[change.ID] = true
+	}
+	for _, id := range []string{"host", "service", "labeled", "fleet"} {
+		if !got[id] {
+			t.Errorf("expected change %s to match", id)
+		}
+	}
+	if len(got) != 4 {
+		t.Errorf("expected 4 matching changes, got %v", got)
+	}
+}
+
+func TestChangeTracker_ServiceChangeSkipsUnrelatedHosts(t *testing.T) {
+	now := time.Now()
+	tracker := NewChangeTracker(10)
+	tracker.Record(domain.ChangeEvent{Service: "checkout", Version: "v2", OccurredAt: now.Add(-time.Minute)})
+
+	if changes := tracker.ChangesBefore([]domain.Alert{{Host: "db-01"}}, now, 30*time.Minute); len(changes) != 0 {
+		t.Errorf("expected a service change not to match a host outside the service, got %v", changes)
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/enrichment"
+	"incident-teller/internal/labels"
+	"incident-teller/internal/topology"
+)
+
+// Correlation strategy names accepted in IncidentConfig.CorrelationStrategy
+const (
+	CorrelationWindow            = "window"
+	CorrelationHostAndWindow     = "host_and_window"
+	CorrelationServiceAndWindow  = "service_and_window"
+	CorrelationWorkloadAndWindow = "workload_and_window"
+	CorrelationLabelsAndWindow   = "labels_and_window"
+	CorrelationGroupBy           = "group_by"
+)
+
+// CorrelationStrategy partitions alerts before the IncidentBuilder applies its time window.
+// Alerts with different keys never end up in the same incident.
+type CorrelationStrategy interface {
+	// Name returns the strategy name
+	Name() string
+	// Key returns the partition key of an alert
+	Key(alert domain.Alert) string
+}
+
+// NewCorrelationStrategy creates a strategy by name. labelNames is used by labels_and_window,
+// groupBy by group_by, topo by service_and_window (may be nil; the alert's "service" label
+// or host is used instead).
+func NewCorrelationStrategy(name string, labelNames, groupBy []string, topo *topology.Topology) (CorrelationStrategy, error) {
+	switch name {
+	case "", CorrelationWindow:
+		return windowStrategy{}, nil
+	case CorrelationHostAndWindow:
+		return hostStrategy{}, nil
+	case CorrelationServiceAndWindow:
+		return serviceStrategy{topology: topo}, nil
+	case CorrelationWorkloadAndWindow:
+		return workloadStrategy{}, nil
+	case CorrelationLabelsAndWindow:
+		if len(labelNames) == 0 {
+			return nil, fmt.Errorf("correlation strategy %s needs at least one label", name)
+		}
+		return labelStrategy{labels: labelNames}, nil
+	case CorrelationGroupBy:
+		if len(groupBy) == 0 {
+			return nil, fmt.Errorf("correlation strategy %s needs at least one group_by field", name)
+		}
+		key, err := labels.ParseGroupKey(groupBy)
+		if err != nil {
+			return nil, err
+		}
+		return groupByStrategy{key: key}, nil
+	default:
+		return nil, fmt.Errorf("unsupported correlation strategy: %s", name)
+	}
+}
+
+// windowStrategy groups every alert purely by time
+type windowStrategy struct{}
+
+func (windowStrategy) Name() string                  { return CorrelationWindow }
+func (windowStrategy) Key(alert domain.Alert) string { return "" }
+
+// hostStrategy keeps alerts from different hosts in separate incidents
+type hostStrategy struct{}
+
+func (hostStrategy) Name() string                  { return CorrelationHostAndWindow }
+func (hostStrategy) Key(alert domain.Alert) string { return alert.Host }
+
+// serviceStrategy groups alerts by the service their host belongs to
+type serviceStrategy struct {
+	topology *topology.Topology
+}
+
+func (s serviceStrategy) Name() string { return CorrelationServiceAndWindow }
+
+func (s serviceStrategy) Key(alert domain.Alert) string {
+	if service, ok := s.topology.ServiceForHost(alert.Host); ok {
+		return "service=" + service
+	}
+	if service := alert.Labels["service"]; service != "" {
+		return "service=" + service
+	}
+	// Hosts outside the topology are treated as their own service
+	return "host=" + alert.Host
+}
+
+// workloadStrategy groups alerts of containers by the workload they belong to, across
+// hosts, so the pods of one Deployment failing on several nodes form one incident.
+// Alerts without a workload label are grouped by host.
+type workloadStrategy struct{}
+
+func (workloadStrategy) Name() string { return CorrelationWorkloadAndWindow }
+
+func (workloadStrategy) Key(alert domain.Alert) string {
+	if workload := alert.Labels[enrichment.LabelWorkload]; workload != "" {
+		return "workload=" + workload
+	}
+	return "host=" + alert.Host
+}
+
+// labelStrategy groups alerts sharing the same values for the selected labels
+type labelStrategy struct {
+	labels []string
+}
+
+func (s labelStrategy) Name() string { return CorrelationLabelsAndWindow }
+
+func (s labelStrategy) Key(alert domain.Alert) string {
+	parts := make([]string, len(s.labels))
+	for i, label := range s.labels {
+		value := alert.Labels[label]
+		if value == "" && label == "host" {
+			value = alert.Host
+		}
+		parts[i] = label + "=" + value
+	}
+	return strings.Join(parts, ",")
+}
+
+// groupByStrategy groups alerts by a configured key of alert fields and labels
+type groupByStrategy struct {
+	key labels.GroupKey
+}
+
+func (s groupByStrategy) Name() string                  { return CorrelationGroupBy }
+func (s groupByStrategy) Key(alert domain.Alert) string { return s.key.Key(alert) }
+
+// environmentStrategy keeps alerts of different environments apart on top of another strategy
+type environmentStrategy struct {
+	strategy      CorrelationStrategy
+	environmentOf func(domain.Alert) string
+}
+
+// ScopeByEnvironment keeps alerts of different environments, e.g. a staging and a prod
+// disk alert, in separate incidents; within an environment the strategy applies as is
+func ScopeByEnvironment(strategy CorrelationStrategy, environmentOf func(domain.Alert) string) CorrelationStrategy {
+	return environmentStrategy{strategy: strategy, environmentOf: environmentOf}
+}
+
+func (s environmentStrategy) Name() string { return s.strategy.Name() }
+
+func (s environmentStrategy) Key(alert domain.Alert) string {
+	return "env=" + s.environmentOf(alert) + "|" + s.strategy.Key(alert)
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"incident-teller/internal/domain"
+)
+
+// SuppressedByLabel marks alerts raised while their host was unreachable with the ID of
+// the alert reporting the outage: they are symptoms of the host going down rather than
+// independent evidence of a cascade
+const SuppressedByLabel = "suppressed_by"
+
+// DefaultRootAlerts are the alert names and charts reporting a host as unreachable:
+// Nagios host checks, Netdata's ping collector and common Prometheus rules
+var DefaultRootAlerts = []string{"host_check", "nagios.host", "ping_host_reachable", "host_unreachable", "host_down", "node_down", "instancedown"}
+
+// DependencySuppressor groups the alerts of an unreachable host under the alert reporting
+// it: while a root alert is active on a host, the host's other alerts are suppressed
+// until it clears.
+type DependencySuppressor struct {
+	roots map[string]bool // Lowercase alert names and charts
+
+	mu     sync.Mutex
+	active map[string]domain.Alert // host -> active root alert
+}
+
+// NewDependencySuppressor creates a suppressor treating alerts with any of the names or
+// charts as root alerts; none uses DefaultRootAlerts
+func NewDependencySuppressor(rootAlerts []string) *DependencySuppressor {
+	if len(rootAlerts) == 0 {
+		rootAlerts = DefaultRootAlerts
+	}
+	roots := make(map[string]bool, len(rootAlerts))
+	for _, name := range rootAlerts {
+		roots[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	return &DependencySuppressor{roots: roots, active: make(map[string]domain.Alert)}
+}
+
+// IsRoot reports whether the alert reports its host as unreachable
+func (s *DependencySuppressor) IsRoot(alert domain.Alert) bool {
+	return s.roots[strings.ToLower(alert.Name)] || s.roots[strings.ToLower(alert.Chart)]
+}
+
+// Mark records the root alerts of a batch and returns a copy in which alerts of hosts with
+// an active root alert carry the SuppressedByLabel. A nil suppressor returns the batch
+// unchanged.
+func (s *DependencySuppressor) Mark(alerts []domain.Alert) []domain.Alert {
+	if s == nil {
+		return alerts
+	}
+
+	s.mu.Lock()
+	suppressedBy := s.observe(s.active, alerts)
+	s.mu.Unlock()
+
+	marked := make([]domain.Alert, len(alerts))
+	for i, alert := range alerts {
+		if suppressedBy[i] != "" {
+			labels := make(map[string]string, len(alert.Labels)+1)
+			for k, v := range alert.Labels {
+				labels[k] = v
+			}
+			labels[SuppressedByLabel] = suppressedBy[i]
+			alert.Labels = labels
+		}
+		marked[i] = alert
+	}
+	return marked
+}
+
+// observe feeds alerts into the active root alerts in time order and returns, in input
+// order, the ID of the root alert suppressing each alert, if any
+func (s *DependencySuppressor) observe(active map[string]domain.Alert, alerts []domain.Alert) []string {
+	order := make([]int, len(alerts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return alerts[order[i]].OccurredAt.Before(alerts[order[j]].OccurredAt)
+	})
+
+	suppressedBy := make([]string, len(alerts))
+	for _, idx := range order {
+		alert := alerts[idx]
+		if s.IsRoot(alert) {
+			if isActiveStatus(alert.Status) {
+				if _, ok := active[alert.Host]; !ok {
+					active[alert.Host] = alert
+				}
+			} else {
+				delete(active, alert.Host)
+			}
+			continue
+		}
+		if root, ok := active[alert.Host]; ok && alert.Host != "" {
+			suppressedBy[idx] = root.ID
+		}
+	}
+	return suppressedBy
+}
+
+// SuppressedBy returns the ID of the root alert suppressing an alert, or "" if the
+// alert is independent
+func SuppressedBy(alert domain.Alert) string {
+	return alert.Labels[SuppressedByLabel]
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func TestDependencySuppressor_GroupsAlertsOfUnreachableHost(t *testing.T) {
+	suppressor := NewDependencySuppressor(nil)
+	start := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	// The host goes down in one batch, its charts fire in the next
+	marked := suppressor.Mark([]domain.Alert{
+		{ID: "down", Host: "web-01", Chart: "nagios.host", Name: "host_check", ResourceType: domain.ResourceNetwork,
+			Status: domain.StatusCritical, OldStatus: domain.StatusClear, OccurredAt: at(0)},
+	})
+	if SuppressedBy(marked[0]) != "" {
+		t.Error("expected the root alert not to be suppressed")
+	}
+	alerts := append(marked, suppressor.Mark([]domain.Alert{
+		{ID: "mem", Host: "web-01", Chart: "system.ram", Name: "ram_usage", ResourceType: domain.ResourceMemory,
+			Status: domain.StatusCritical, OldStatus: domain.StatusClear, OccurredAt: at(1)},
+		{ID: "disk", Host: "web-01", Chart: "disk.sda", Name: "disk_util", ResourceType: domain.ResourceDisk,
+			Status: domain.StatusCritical, OldStatus: domain.StatusClear, OccurredAt: at(2)},
+		{ID: "cpu", Host: "web-01", Chart: "system.cpu", Name: "cpu_usage", ResourceType: domain.ResourceCPU,
+			Status: domain.StatusCritical, OldStatus: domain.StatusClear, OccurredAt: at(3)},
+		{ID: "other", Host: "db-01", Chart: "system.cpu", Name: "cpu_usage", ResourceType: domain.ResourceCPU,
+			Status: domain.StatusWarning, OldStatus: domain.StatusClear, OccurredAt: at(3)},
+	})...)
+	for _, alert := range alerts[1:] {
+		want := ""
+		if alert.Host == "web-01" {
+			want = "down"
+		}
+		if got := SuppressedBy(alert); got != want {
+			t.Errorf("alert %s: expected suppressed_by %q, got %q", alert.ID, want, got)
+		}
+	}
+
+	// Suppressed alerts are grouped under the root alert, not caused by one another
+	timeline := NewIncidentAnalyzer().AnalyzeIncident(alerts[:4])
+	for _, entry := range timeline[1:] {
+		if entry.Type != "SUPPRESSED" || len(entry.CausedBy) != 1 || entry.CausedBy[0] != "down" {
+			t.Errorf("expected %v to be suppressed by the root alert, got %s caused by %v",
+				entry.RelatedAlertIDs, entry.Type, entry.CausedBy)
+		}
+	}
+
+	// The outage is the root cause, without a cascade across memory, disk and CPU
+	explanation := NewSREAnalyzer().AnalyzeIncidentForSRE(alerts[:4])
+	if explanation.RootCause.Alert == nil || explanation.RootCause.Alert.ID != "down" {
+		t.Fatalf("expected the root alert to be the root cause, got %+v", explanation.RootCause)
+	}
+	if len(explanation.AlternativeCauses) != 0 || explanation.RootCause.HasCascade || explanation.BlastRadius.CascadeDepth != 0 {
+		t.Errorf("expected suppressed alerts not to count as cascade evidence, got %d alternatives, cascade %v, depth %d",
+			len(explanation.AlternativeCauses), explanation.RootCause.HasCascade, explanation.BlastRadius.CascadeDepth)
+	}
+	if !strings.Contains(explanation.WhatHappened, "3 alerts suppressed") {
+		t.Errorf("expected the suppressed alerts to be counted, got %q", explanation.WhatHappened)
+	}
+
+	// Once the host is reachable again its alerts are independent
+	marked = suppressor.Mark([]domain.Alert{
+		{ID: "up", Host: "web-01", Chart: "nagios.host", Name: "host_check", Status: domain.StatusClear, OccurredAt: at(10)},
+		{ID: "later", Host: "web-01", Chart: "system.cpu", Name: "cpu_usage", Status: domain.StatusWarning, OccurredAt: at(11)},
+	})
+	if SuppressedBy(marked[1]) != "" {
+		t.Error("expected alerts after the host recovered not to be suppressed")
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/report"
+)
+
+// Digests list at most this many root causes and hosts
+const digestTopN = 5
+
+// DigestCount is a named count in a digest ranking
+type DigestCount struct {
+	Name  string
+	Count int
+}
+
+// Digest summarizes the incidents that started in a period
+type Digest struct {
+	From, To      time.Time
+	Incidents     int
+	Resolved      int
+	Active        int
+	Critical      int // Incidents with at least one critical alert
+	MTTR          time.Duration
+	TopRootCauses []DigestCount // Most frequent root-cause alerts first
+	NoisiestHosts []DigestCount // Hosts with the most alerts first
+	Longest       []domain.Incident
+	Location      *time.Location // Time zone the digest is rendered in; nil is UTC
+}
+
+// DigestBuilder compiles incident digests, identifying each incident's root cause with
+// the SRE analyzer
+type DigestBuilder struct {
+	analyzer *SREAnalyzer
+}
+
+// NewDigestBuilder creates a digest builder
+func NewDigestBuilder() *DigestBuilder {
+	return &DigestBuilder{analyzer: NewSREAnalyzer()}
+}
+
+// SetPropagationLearner enables learned propagation patterns in root cause analysis
+func (b *DigestBuilder) SetPropagationLearner(learner *PropagationLearner) {
+	b.analyzer.SetPropagationLearner(learner)
+}
+
+// Build compiles the digest of the incidents started within [from, to)
+func (b *DigestBuilder) Build(incidents []domain.Incident, from, to time.Time) Digest {
+	digest := Digest{From: from, To: to}
+
+	rootCauses := make(map[string]int)
+	hosts := make(map[string]int)
+	var resolvedTime time.Duration
+	var inPeriod []domain.Incident
+
+	for _, incident := range incidents {
+		if incident.StartedAt.Before(from) || !incident.StartedAt.Before(to) {
+			continue
+		}
+		inPeriod = append(inPeriod, incident)
+		digest.Incidents++
+
+		if incident.ResolvedAt != nil {
+			digest.Resolved++
+			resolvedTime += incident.ResolvedAt.Sub(incident.StartedAt)
+		} else {
+			digest.Active++
+		}
+
+		for _, event := range incident.Events {
+			if event.Status == domain.StatusCritical {
+				digest.Critical++
+				break
+			}
+		}
+		for _, event := range incident.Events {
+			if event.Host != "" {
+				hosts[event.Host]++
+			}
+		}
+
+		if len(incident.Events) > 0 {
+			rootCause := b.analyzer.ExplainIncident(incident).RootCause.Alert
+			rootCauses[fmt.Sprintf("%s (%s)", rootCause.Name, strings.ToLower(string(rootCause.ResourceType)))]++
+		}
+	}
+
+	if digest.Resolved > 0 {
+		digest.MTTR = resolvedTime / time.Duration(digest.Resolved)
+	}
+	digest.TopRootCauses = topCounts(rootCauses, digestTopN)
+	digest.NoisiestHosts = topCounts(hosts, digestTopN)
+
+	sort.SliceStable(inPeriod, func(i, j int) bool {
+		return inPeriod[i].Duration(to) > inPeriod[j].Duration(to)
+	})
+	digest.Longest = firstN(inPeriod, 3)
+
+	return digest
+}
+
+// topCounts returns the n largest counts, ties broken by name
+func topCounts(counts map[string]int, n int) []DigestCount {
+	result := make([]DigestCount, 0, len(counts))
+	for name, count := range counts {
+		result = append(result, DigestCount{Name: name, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	return firstN(result, n)
+}
+
+// DigestSender delivers a rendered digest, e.g. by email
+type DigestSender interface {
+	SendHTML(ctx context.Context, subject, body string) error
+}
+
+// DigestSchedule sends a digest covering the preceding period every day, or every week
+// on a given weekday, at a fixed time of day
+type DigestSchedule struct {
+	Period   time.Duration
+	Weekday  *time.Weekday // Nil sends daily
+	At       time.Duration // Time of day, e.g. 9h
+	Location *time.Location
+}
+
+// Next returns the first send time after t
+func (s DigestSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.Location)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.Location)
+
+	for i := 0; ; i++ {
+		candidate := day.AddDate(0, 0, i).Add(s.At)
+		if !candidate.After(t) {
+			continue
+		}
+		if s.Weekday == nil || candidate.Weekday() == *s.Weekday {
+			return candidate
+		}
+	}
+}
+
+// RunDigest builds and sends a digest on the schedule until ctx is cancelled.
+// load returns the incidents started within [from, to].
+func (b *DigestBuilder) RunDigest(
+	ctx context.Context,
+	schedule DigestSchedule,
+	load func(ctx context.Context, from, to time.Time) ([]domain.Incident, error),
+	sender DigestSender,
+) {
+	for {
+		next := schedule.Next(time.Now())
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		from := next.Add(-schedule.Period)
+		incidents, err := load(ctx, from, next)
+		if err != nil {
+			log.Printf("⚠️  Failed to load incidents for digest: %v", err)
+			continue
+		}
+
+		digest := b.Build(incidents, from, next)
+		digest.Location = schedule.Location
+		doc := DigestDocument(digest)
+		if err := sender.SendHTML(ctx, doc.Title, DigestHTML(doc)); err != nil {
+			log.Printf("⚠️  Failed to send incident digest: %v", err)
+			continue
+		}
+		log.Printf("📬 Sent incident digest covering %d incidents", digest.Incidents)
+	}
+}
+
+// DigestHTML renders a digest document as a complete HTML email body
+func DigestHTML(doc report.Document) string {
+	return "<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"></head>\n" +
+		"<body style=\"font-family:Helvetica,Arial,sans-serif;color:#222;max-width:720px\">\n" +
+		report.HTML{}.Render(doc) +
+		"</body>\n</html>\n"
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func TestDigestBuilder_Build(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(7 * 24 * time.Hour)
+
+	incident := func(id, host string, start time.Time, duration time.Duration, status domain.AlertStatus) domain.Incident {
+		inc := domain.Incident{
+			ID:        id,
+			StartedAt: start,
+			Events: []domain.Alert{{
+				ID:           id + "-1",
+				Name:         "disk_util",
+				Host:         host,
+				Status:       status,
+				ResourceType: domain.ResourceDisk,
+				OccurredAt:   start,
+			}},
+		}
+		if duration > 0 {
+			resolved := start.Add(duration)
+			inc.ResolvedAt = &resolved
+		}
+		return inc
+	}
+
+	incidents := []domain.Incident{
+		incident("a", "db-01", from.Add(time.Hour), 10*time.Minute, domain.StatusCritical),
+		incident("b", "db-01", from.Add(2*time.Hour), 30*time.Minute, domain.StatusWarning),
+		incident("c", "web-01", from.Add(3*time.Hour), 0, domain.StatusCritical),
+		incident("old", "web-01", from.Add(-time.Hour), 5*time.Minute, domain.StatusCritical),
+	}
+
+	digest := NewDigestBuilder().Build(incidents, from, to)
+
+	if digest.Incidents != 3 || digest.Resolved != 2 || digest.Active != 1 || digest.Critical != 2 {
+		t.Fatalf("Unexpected counts: %+v", digest)
+	}
+	if digest.MTTR != 20*time.Minute {
+		t.Errorf("Expected MTTR of 20m, got %s", digest.MTTR)
+	}
+	if len(digest.NoisiestHosts) != 2 || digest.NoisiestHosts[0] != (DigestCount{Name: "db-01", Count: 2}) {
+		t.Errorf("Expected db-01 to be the noisiest host, got %+v", digest.NoisiestHosts)
+	}
+	if len(digest.TopRootCauses) != 1 || digest.TopRootCauses[0].Count != 3 {
+		t.Errorf("Expected a single root cause for all incidents, got %+v", digest.TopRootCauses)
+	}
+	if len(digest.Longest) == 0 || digest.Longest[0].ID != "c" {
+		t.Errorf("Expected the still active incident to be the longest, got %+v", digest.Longest)
+	}
+}
+
+func TestDigestBuilder_BuildWeekly(t *testing.T) {
+	to := time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC)
+
+	incident := func(id, host string, status domain.AlertStatus, start time.Time, duration time.Duration) domain.Incident {
+		inc := domain.Incident{ID: id, Status: status, StartedAt: start, Events: []domain.Alert{{
+			ID: id + "-1", Name: "disk_util", Host: host, Status: status, ResourceType: domain.ResourceDisk, OccurredAt: start,
+		}}}
+		if duration > 0 {
+			resolved := start.Add(duration)
+			inc.ResolvedAt = &resolved
+		}
+		return inc
+	}
+	incidents := []domain.Incident{
+		incident("a", "db-01", domain.StatusCritical, to.Add(-2*24*time.Hour), 10*time.Minute),
+		incident("b", "db-01", domain.StatusWarning, to.Add(-3*24*time.Hour), 30*time.Minute),
+		incident("c", "web-01", domain.StatusCritical, to.Add(-time.Hour), 0),
+		incident("last-week", "web-01", domain.StatusCritical, to.Add(-10*24*time.Hour), time.Hour),
+	}
+	actionItems := []ActionItem{{IncidentID: "c", Tracker: "jira", Key: "OPS-1"}}
+
+	weekly := NewDigestBuilder().BuildWeekly(incidents, actionItems, to)
+
+	if weekly.Incidents != 3 || weekly.Resolved != 2 || weekly.MTTR != 20*time.Minute {
+		t.Fatalf("Unexpected week: %+v", weekly)
+	}
+	wantSeverities := []DigestCount{{Name: "critical", Count: 2}, {Name: "warning", Count: 1}, {Name: "info", Count: 0}}
+	for i, want := range wantSeverities {
+		if weekly.BySeverity[i] != want {
+			t.Errorf("Expected %+v, got %+v", want, weekly.BySeverity[i])
+		}
+	}
+	if len(weekly.MTTRTrend) != 4 || weekly.MTTRTrend[2].MTTR != time.Hour || weekly.MTTRTrend[3].Incidents != 3 {
+		t.Errorf("Unexpected MTTR trend %+v", weekly.MTTRTrend)
+	}
+	if len(weekly.TopRootCauses) != 1 || weekly.TopRootCauses[0] != (DigestCount{Name: string(domain.ResourceDisk), Count: 3}) {
+		t.Errorf("Expected DISK as the only root-cause resource type, got %+v", weekly.TopRootCauses)
+	}
+	if weekly.NoisiestHosts[0] != (DigestCount{Name: "db-01", Count: 2}) || len(weekly.ActionItems) != 1 {
+		t.Errorf("Unexpected hosts %+v or action items %+v", weekly.NoisiestHosts, weekly.ActionItems)
+	}
+}
+
+func TestDigestSchedule_Next(t *testing.T) {
+	monday := time.Monday
+	schedule := DigestSchedule{Period: 7 * 24 * time.Hour, Weekday: &monday, At: 9 * time.Hour, Location: time.UTC}
+
+	// Wednesday 2024-01-03 -> Monday 2024-01-08 09:00
+	next := schedule.Next(time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC))
+	if want := time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("Expected next weekly digest at %s, got %s", want, next)
+	}
+
+	// Exactly at send time -> the following week
+	next = schedule.Next(time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC))
+	if want := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("Expected next weekly digest at %s, got %s", want, next)
+	}
+
+	schedule.Weekday = nil
+	next = schedule.Next(time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC))
+	if want := time.Date(2024, 1, 4, 9, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("Expected next daily digest at %s, got %s", want, next)
+	}
+}
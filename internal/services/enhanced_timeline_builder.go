@@ -6,11 +6,13 @@ import (
 	"time"
 
 	"incident-teller/internal/domain"
+	"incident-teller/internal/report"
 )
 
 // EnhancedTimelineBuilder creates detailed incident timelines with AI insights
 type EnhancedTimelineBuilder struct {
-	grouper *AlertGrouper
+	grouper   *AlertGrouper
+	anomalies *AnomalyDetector
 }
 
 // NewEnhancedTimelineBuilder creates a new timeline builder
@@ -20,10 +22,15 @@ func NewEnhancedTimelineBuilder(grouper *AlertGrouper) *EnhancedTimelineBuilder
 	}
 }
 
+// SetAnomalyDetector adds an "anomaly" event after each alert at which an anomaly was detected
+func (etb *EnhancedTimelineBuilder) SetAnomalyDetector(detector *AnomalyDetector) {
+	etb.anomalies = detector
+}
+
 // TimelineEvent represents an event in the incident timeline
 type TimelineEvent struct {
 	Timestamp            time.Time
-	Type                 string // "trigger", "escalation", "propagation", "resolution", "state_change"
+	Type                 string // "trigger", "escalation", "propagation", "resolution", "state_change", "anomaly"
 	Severity             string // "info", "warning", "critical"
 	Message              string
 	SourceAlert          *domain.Alert
@@ -126,8 +133,33 @@ func (etb *EnhancedTimelineBuilder) buildEvents(alerts []domain.Alert, groups []
 		}
 
 		events = append(events, event)
+		events = append(events, etb.anomalyEvents(alert, firstTime)...)
+	}
+
+	return events
+}
+
+// anomalyEvents returns an "anomaly" event for each anomaly detected at the alert
+func (etb *EnhancedTimelineBuilder) anomalyEvents(alert domain.Alert, firstTime time.Time) []TimelineEvent {
+	if etb.anomalies == nil {
+		return nil
 	}
 
+	var events []TimelineEvent
+	for _, anomaly := range etb.anomalies.ForAlerts([]domain.Alert{alert}) {
+		if anomaly.AlertID != alert.ID {
+			continue
+		}
+		events = append(events, TimelineEvent{
+			Timestamp:             alert.OccurredAt,
+			Type:                  "anomaly",
+			Severity:              "warning",
+			Message:               "Anomaly detected: " + anomaly.Message,
+			SourceAlert:           &alert,
+			ResourcesAffected:     []string{alert.Host},
+			TimeFromIncidentStart: alert.OccurredAt.Sub(firstTime),
+		})
+	}
 	return events
 }
 
@@ -139,6 +171,11 @@ func (etb *EnhancedTimelineBuilder) isCascadePoint(alert domain.Alert, allAlerts
 
 	nextAlert := allAlerts[index+1]
 
+	// Alerts of an unreachable host are grouped under the alert reporting it
+	if SuppressedBy(alert) != "" || SuppressedBy(nextAlert) != "" {
+		return false
+	}
+
 	// Check if next alert is on same host and within 5 seconds
 	if alert.Host != nextAlert.Host {
 		return false
@@ -281,29 +318,5 @@ func (etb *EnhancedTimelineBuilder) generateEventMessage(alert domain.Alert, eve
 
 // FormatTimeline creates a human-readable timeline string
 func (etb *EnhancedTimelineBuilder) FormatTimeline(timeline TimelineWithInsights) string {
-	output := fmt.Sprintf("Incident Timeline (Duration: %v)\n", timeline.Duration)
-	output += fmt.Sprintf("Start: %s\n", timeline.StartTime.Format("15:04:05"))
-	output += fmt.Sprintf("End: %s\n\n", timeline.EndTime.Format("15:04:05"))
-
-	output += "Events:\n"
-	for i, event := range timeline.Events {
-		relativeTime := event.Timestamp.Sub(timeline.StartTime)
-		output += fmt.Sprintf("%d. [%s] (+%v) %s - %s\n",
-			i+1,
-			event.Type,
-			relativeTime,
-			event.Message,
-			event.Severity,
-		)
-
-		if event.IsCascadePoint {
-			output += "   └─ CASCADE POINT: Multiple downstream alerts triggered\n"
-		}
-
-		if timeline.RootCauseEventIndex != nil && *timeline.RootCauseEventIndex == i {
-			output += "   └─ ROOT CAUSE: This is likely where the incident started\n"
-		}
-	}
-
-	return output
+	return report.Text{}.Render(TimelineDocument(timeline))
 }
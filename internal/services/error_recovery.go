@@ -17,6 +17,10 @@ const (
 	StateHalfOpen CircuitBreakerState = "half-open"
 )
 
+// ErrCircuitOpen is returned by CircuitBreaker.Execute without running the function while
+// the breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
 // CircuitBreaker implements the circuit breaker pattern for fault tolerance
 type CircuitBreaker struct {
 	name          string
@@ -52,7 +56,7 @@ func (cb *CircuitBreaker) Execute(fn func() error) error {
 			cb.successCount = 0
 			cb.failures = 0
 		} else {
-			return fmt.Errorf("circuit breaker %s is open", cb.name)
+			return fmt.Errorf("%w: %s", ErrCircuitOpen, cb.name)
 		}
 	}
 
@@ -63,7 +67,8 @@ func (cb *CircuitBreaker) Execute(fn func() error) error {
 		cb.failures++
 		cb.lastFailTime = time.Now()
 
-		if cb.failures >= cb.maxFailures {
+		// A failed half-open probe opens the circuit again right away
+		if cb.failures >= cb.maxFailures || cb.state == StateHalfOpen {
 			cb.state = StateOpen
 		}
 
@@ -91,6 +96,17 @@ func (cb *CircuitBreaker) GetState() CircuitBreakerState {
 	return cb.state
 }
 
+// RetryAt returns when an open breaker lets the next probe through, or the zero time if
+// the breaker is not open
+func (cb *CircuitBreaker) RetryAt() time.Time {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	if cb.state != StateOpen {
+		return time.Time{}
+	}
+	return cb.lastFailTime.Add(cb.resetTimeout)
+}
+
 // Retry implements the retry pattern with exponential backoff
 type Retry struct {
 	maxAttempts int
@@ -276,7 +292,7 @@ func (erm *ErrorRecoveryManager) Execute(ctx context.Context, name string, fn fu
 	// Check circuit breaker
 	if cb != nil {
 		if cb.GetState() == StateOpen {
-			return errors.New("circuit breaker is open")
+			return fmt.Errorf("%w: %s", ErrCircuitOpen, name)
 		}
 	}
 
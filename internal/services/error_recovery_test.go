@@ -195,3 +195,12 @@ func BenchmarkCircuitBreaker(b *testing.B) {
 		})
 	}
 }
+
+// testError is a simple error used to simulate failures
+type testError struct {
+	msg string
+}
+
+func (e *testError) Error() string {
+	return e.msg
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/notify"
+	"incident-teller/internal/oncall"
+	"incident-teller/internal/ports"
+)
+
+// EscalationLevel is a notification target paged once an incident has been
+// unacknowledged for After since it started
+type EscalationLevel struct {
+	After           time.Duration
+	OnCallSecondary bool               // Mention the backup on-call member
+	Channels        *notify.Dispatcher // Channels of the level; nil uses the default channels
+}
+
+// EscalationPolicy escalates the unacknowledged incidents of a priority through its
+// levels, in order
+type EscalationPolicy struct {
+	Priority domain.Priority
+	Levels   []EscalationLevel
+}
+
+// Escalator pages the next level of an incident's escalation policy while nobody has
+// acknowledged it or moved it past detected. Escalations are persisted, so every level is
+// paged once even across restarts, and show up in the incident timeline.
+type Escalator struct {
+	policies   map[domain.Priority][]EscalationLevel
+	store      ports.EscalationStore
+	acks       *AcknowledgementTracker
+	ackStore   ports.AcknowledgementStore // Acknowledgements made before a restart or on other replicas
+	dispatcher *notify.Dispatcher
+	onCall     *oncall.Manager
+
+	mu sync.Mutex // Serializes checks so a level is paged once
+}
+
+// NewEscalator creates an escalator. dispatcher holds the default channels, used by
+// levels without channels of their own.
+func NewEscalator(policies []EscalationPolicy, store ports.EscalationStore, acks *AcknowledgementTracker, dispatcher *notify.Dispatcher) *Escalator {
+	e := &Escalator{
+		policies:   make(map[domain.Priority][]EscalationLevel, len(policies)),
+		store:      store,
+		acks:       acks,
+		dispatcher: dispatcher,
+	}
+	for _, policy := range policies {
+		e.policies[policy.Priority] = policy.Levels
+	}
+	return e
+}
+
+// SetOnCall enables levels paging the backup on-call member
+func (e *Escalator) SetOnCall(manager *oncall.Manager) {
+	e.onCall = manager
+}
+
+// SetAcknowledgementStore makes acknowledgements persisted by any replica stop escalation
+func (e *Escalator) SetAcknowledgementStore(store ports.AcknowledgementStore) {
+	e.ackStore = store
+}
+
+// Check escalates every open, unacknowledged incident whose next level is due and
+// returns the escalations made. An incident moves up at most one level per check.
+func (e *Escalator) Check(ctx context.Context, incidents []domain.Incident, now time.Time) ([]domain.Escalation, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var escalated []domain.Escalation
+	var errs []error
+	for _, incident := range incidents {
+		if incident.ResolvedAt != nil {
+			continue
+		}
+		priority := incident.Priority()
+		levels := e.policies[priority]
+		if len(levels) == 0 {
+			continue
+		}
+		acknowledged, err := e.acknowledged(ctx, incident)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get acknowledgement of incident %s: %w", incident.ID, err))
+			continue
+		}
+		if acknowledged {
+			continue
+		}
+
+		previous, err := e.store.GetEscalations(ctx, incident.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get escalations of incident %s: %w", incident.ID, err))
+			continue
+		}
+		next := 0
+		for _, escalation := range previous {
+			next = max(next, escalation.Level)
+		}
+		if next >= len(levels) || now.Sub(incident.StartedAt) < levels[next].After {
+			continue
+		}
+
+		escalation, err := e.escalate(ctx, incident, priority, next+1, levels[next], now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to escalate incident %s: %w", incident.ID, err))
+			continue
+		}
+		escalated = append(escalated, escalation)
+	}
+	return escalated, errors.Join(errs...)
+}
+
+// acknowledged reports whether a responder has taken ownership of the incident: it was
+// acknowledged, here or in the store, or moved on from the detected state
+func (e *Escalator) acknowledged(ctx context.Context, incident domain.Incident) (bool, error) {
+	if incident.CurrentState() != domain.StateDetected {
+		return true, nil
+	}
+	if _, ok := e.acks.Acknowledgement(incident.ID); ok {
+		return true, nil
+	}
+	if e.ackStore == nil {
+		return false, nil
+	}
+	return e.ackStore.IsAcknowledged(ctx, incident.ID)
+}
+
+// escalate pages a level for the incident and records the escalation
+func (e *Escalator) escalate(ctx context.Context, incident domain.Incident, priority domain.Priority, number int, level EscalationLevel, now time.Time) (domain.Escalation, error) {
+	title := incident.Title
+	if title == "" {
+		title = fmt.Sprintf("Incident %s", incident.ID)
+	}
+	notification := notify.Notification{
+		IncidentID: incident.ID,
+		Title:      "Escalation: " + title,
+		Severity:   incidentSeverity(incident),
+		Priority:   string(priority),
+		Text: fmt.Sprintf("🔺 *Escalation level %d*: %s incident *%s* (ID: %s) has not been acknowledged for %s",
+			number, priority, title, incident.ID, now.Sub(incident.StartedAt).Round(time.Minute)),
+		Labels:    incident.Labels(),
+		Hosts:     incident.Hosts(),
+		CreatedAt: now,
+	}
+
+	var targets []string
+	if level.OnCallSecondary && e.onCall != nil {
+		if member, ok := e.onCall.Secondary(now); ok {
+			notification.Assignee = member.Name
+			notification.Mention = member.SlackID
+			notification.Text += fmt.Sprintf("\n\n*Escalated to:* %s (secondary on call)", member.Name)
+			targets = append(targets, member.Name+" (secondary on-call)")
+		}
+	}
+
+	channels := level.Channels
+	if channels == nil {
+		channels = e.dispatcher
+	}
+	if channels == nil || channels.Len() == 0 {
+		return domain.Escalation{}, fmt.Errorf("level %d has no notification channels", number)
+	}
+	if err := channels.Send(ctx, notification); err != nil {
+		return domain.Escalation{}, err
+	}
+	targets = append(targets, channels.Names()...)
+
+	escalation := domain.Escalation{
+		IncidentID:  incident.ID,
+		Level:       number,
+		Priority:    priority,
+		Target:      strings.Join(targets, ", "),
+		EscalatedAt: now,
+	}
+	if err := e.store.SaveEscalation(ctx, escalation); err != nil {
+		return domain.Escalation{}, err
+	}
+	return escalation, nil
+}
+
+// Run checks the incidents of repo every interval until ctx is cancelled
+func (e *Escalator) Run(ctx context.Context, repo ports.Repository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			incidents, err := repo.GetIncidents(ctx)
+			if err != nil {
+				log.Printf("⚠️  Escalation check failed to load incidents: %v", err)
+				continue
+			}
+
+			escalations, err := e.Check(ctx, incidents, time.Now())
+			if err != nil {
+				log.Printf("⚠️  Escalation error: %v", err)
+			}
+			for _, escalation := range escalations {
+				log.Printf("🔺 Escalated incident %s to level %d: %s", escalation.IncidentID, escalation.Level, escalation.Target)
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/notify"
+	"incident-teller/internal/oncall"
+)
+
+type fakeEscalationStore map[string][]domain.Escalation
+
+func (s fakeEscalationStore) GetEscalations(_ context.Context, incidentID string) ([]domain.Escalation, error) {
+	return s[incidentID], nil
+}
+
+func (s fakeEscalationStore) SaveEscalation(_ context.Context, escalation domain.Escalation) error {
+	s[escalation.IncidentID] = append(s[escalation.IncidentID], escalation)
+	return nil
+}
+
+func TestEscalator_EscalatesUntilAcknowledged(t *testing.T) {
+	start := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	onCall, err := oncall.NewManager(oncall.Schedule{
+		Members:     []oncall.Member{{Name: "Alice"}, {Name: "Bob", SlackID: "U2"}},
+		Start:       start.Add(-time.Hour),
+		ShiftLength: 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	primary, manager := &recordingNotifier{}, &recordingNotifier{}
+	store := fakeEscalationStore{}
+	acks := NewAcknowledgementTracker()
+	escalator := NewEscalator([]EscalationPolicy{{
+		Priority: domain.PriorityP1,
+		Levels: []EscalationLevel{
+			{After: 5 * time.Minute, OnCallSecondary: true},
+			{After: 15 * time.Minute, Channels: notify.NewDispatcher(manager)},
+		},
+	}}, store, acks, notify.NewDispatcher(primary))
+	escalator.SetOnCall(onCall)
+	ctx := context.Background()
+
+	critical := domain.Incident{ID: "inc-1", Title: "Database down", Status: domain.StatusCritical, StartedAt: start,
+		PriorityOverride: domain.PriorityP1}
+	minor := domain.Incident{ID: "inc-2", Status: domain.StatusWarning, StartedAt: start, PriorityOverride: domain.PriorityP3}
+	incidents := []domain.Incident{critical, minor}
+
+	check := func(at time.Duration) []domain.Escalation {
+		t.Helper()
+		escalations, err := escalator.Check(ctx, incidents, start.Add(at))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return escalations
+	}
+
+	if escalations := check(4 * time.Minute); len(escalations) != 0 {
+		t.Fatalf("expected no escalation before the first level, got %+v", escalations)
+	}
+
+	escalations := check(6 * time.Minute)
+	if len(escalations) != 1 || escalations[0].Level != 1 || escalations[0].IncidentID != "inc-1" {
+		t.Fatalf("expected inc-1 at level 1, got %+v", escalations)
+	}
+	if len(primary.sent) != 1 || primary.sent[0].Assignee != "Bob" || primary.sent[0].Mention != "U2" {
+		t.Fatalf("expected the secondary on-call to be paged on the default channels, got %+v", primary.sent)
+	}
+	if !strings.Contains(escalations[0].Target, "Bob (secondary on-call)") {
+		t.Errorf("unexpected target %q", escalations[0].Target)
+	}
+
+	// A level is paged once
+	if escalations := check(7 * time.Minute); len(escalations) != 0 {
+		t.Fatalf("expected level 1 not to be paged again, got %+v", escalations)
+	}
+
+	if escalations := check(16 * time.Minute); len(escalations) != 1 || escalations[0].Level != 2 || len(manager.sent) != 1 {
+		t.Fatalf("expected level 2 on the manager channel, got %+v and %d notifications", escalations, len(manager.sent))
+	}
+	if len(store["inc-1"]) != 2 {
+		t.Fatalf("expected two stored escalations, got %+v", store["inc-1"])
+	}
+
+	// Acknowledged incidents aren't escalated any further
+	store["inc-1"] = store["inc-1"][:1]
+	acks.Acknowledge("inc-1", "alice", start.Add(20*time.Minute))
+	if escalations := check(30 * time.Minute); len(escalations) != 0 {
+		t.Errorf("expected no escalation after the acknowledgement, got %+v", escalations)
+	}
+}
+
+type fakeAcknowledgementStore map[string]bool
+
+func (s fakeAcknowledgementStore) SaveAcknowledgement(_ context.Context, incidentID, _ string, _ time.Time) error {
+	s[incidentID] = true
+	return nil
+}
+
+func (s fakeAcknowledgementStore) IsAcknowledged(_ context.Context, incidentID string) (bool, error) {
+	return s[incidentID], nil
+}
+
+func TestEscalator_SkipsIncidentsTakenElsewhere(t *testing.T) {
+	start := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	primary := &recordingNotifier{}
+	escalator := NewEscalator([]EscalationPolicy{{
+		Priority: domain.PriorityP1,
+		Levels:   []EscalationLevel{{After: 5 * time.Minute}},
+	}}, fakeEscalationStore{}, NewAcknowledgementTracker(), notify.NewDispatcher(primary))
+	// Acknowledged before a restart or on another replica
+	escalator.SetAcknowledgementStore(fakeAcknowledgementStore{"inc-stored": true})
+
+	incidents := []domain.Incident{
+		{ID: "inc-stored", StartedAt: start, PriorityOverride: domain.PriorityP1},
+		{ID: "inc-triaged", StartedAt: start, PriorityOverride: domain.PriorityP1, State: domain.StateTriaged},
+		{ID: "inc-mitigating", StartedAt: start, PriorityOverride: domain.PriorityP1, State: domain.StateMitigating},
+		{ID: "inc-open", StartedAt: start, PriorityOverride: domain.PriorityP1},
+	}
+	escalations, err := escalator.Check(context.Background(), incidents, start.Add(10*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(escalations) != 1 || escalations[0].IncidentID != "inc-open" {
+		t.Fatalf("expected only the untouched incident escalated, got %+v", escalations)
+	}
+}
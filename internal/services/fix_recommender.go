@@ -5,6 +5,9 @@ import (
 	"strings"
 
 	"incident-teller/internal/domain"
+	"incident-teller/internal/enrichment"
+	"incident-teller/internal/playbook"
+	"incident-teller/internal/report"
 )
 
 // FixRecommender provides structured, actionable remediation guidance
@@ -13,6 +16,9 @@ type FixRecommender struct {
 	immediateActions  map[domain.ResourceType][]string
 	shortTermActions  map[domain.ResourceType][]string
 	longTermActions   map[domain.ResourceType][]string
+
+	// Organization playbooks, consulted before the built-in ones
+	playbooks *playbook.Store
 }
 
 // NewFixRecommender creates a new fix recommender with built-in playbooks
@@ -27,6 +33,12 @@ func NewFixRecommender() *FixRecommender {
 	return fr
 }
 
+// SetPlaybooks makes the organization's playbooks take precedence over the built-in
+// ones. A matching playbook's steps replace the built-in steps of each tier it sets.
+func (fr *FixRecommender) SetPlaybooks(store *playbook.Store) {
+	fr.playbooks = store
+}
+
 // loadPlaybooks initializes the fix playbook database
 func (fr *FixRecommender) loadPlaybooks() {
 	// MEMORY playbooks
@@ -138,6 +150,68 @@ func (fr *FixRecommender) loadPlaybooks() {
 		"Implement circuit breakers for dependency failures",
 		"Document service dependencies and startup order",
 	}
+
+	// DATABASE playbooks
+	fr.immediateActions[domain.ResourceDatabase] = []string{
+		"List running queries and kill runaway ones: `SHOW PROCESSLIST` / `SELECT * FROM pg_stat_activity`",
+		"Check connection counts against the configured maximum",
+		"Look for lock waits and long transactions blocking others",
+		"Verify replication status and lag on replicas",
+	}
+	fr.shortTermActions[domain.ResourceDatabase] = []string{
+		"Review the slow query log for new or regressed queries",
+		"Add missing indexes for the hottest queries: `EXPLAIN <query>`",
+		"Tune connection pooling (pgbouncer, ProxySQL) in front of the database",
+		"Check free disk space and growth of data and WAL/binlog files",
+		"Review schema migrations deployed recently",
+	}
+	fr.longTermActions[domain.ResourceDatabase] = []string{
+		"Set up query performance monitoring and slow query alerts",
+		"Plan read replicas or sharding for growing load",
+		"Automate vacuuming, statistics updates and backups verification",
+		"Load-test schema changes before deploying them",
+		"Document connection limits per client service",
+	}
+
+	// CONTAINER playbooks
+	fr.immediateActions[domain.ResourceContainer] = []string{
+		"Find restarting or OOM-killed containers: `kubectl get pods -A | grep -v Running` or `docker ps -a`",
+		"Inspect the last termination reason: `kubectl describe pod <pod>`",
+		"Check container resource usage against limits: `kubectl top pods` or `docker stats`",
+		"View logs of the previous container instance: `kubectl logs <pod> --previous`",
+	}
+	fr.shortTermActions[domain.ResourceContainer] = []string{
+		"Adjust CPU and memory requests/limits to observed usage",
+		"Check node pressure conditions: `kubectl describe node <node>`",
+		"Roll back the image if the failures started with a deployment",
+		"Review liveness and readiness probe thresholds",
+	}
+	fr.longTermActions[domain.ResourceContainer] = []string{
+		"Enable horizontal pod autoscaling for the workload",
+		"Set up alerts on restarts and throttling per workload",
+		"Right-size resource limits from historical usage",
+		"Spread replicas across nodes with anti-affinity rules",
+	}
+
+	// APPLICATION playbooks
+	fr.immediateActions[domain.ResourceApplication] = []string{
+		"Check error rates and recent error responses in the access logs",
+		"Verify the health endpoints of the application and its dependencies",
+		"Roll back the latest deployment if errors started with it",
+		"Check upstream/backend status in the web server or load balancer",
+	}
+	fr.shortTermActions[domain.ResourceApplication] = []string{
+		"Trace slow or failing requests to the dependency causing them",
+		"Review worker pool sizes (php-fpm, Tomcat threads) against load",
+		"Check timeouts and retries between the application and its backends",
+		"Review recent configuration and feature-flag changes",
+	}
+	fr.longTermActions[domain.ResourceApplication] = []string{
+		"Define SLOs with latency and error-rate alerts",
+		"Add circuit breakers and graceful degradation for dependencies",
+		"Introduce canary deployments with automatic rollback",
+		"Instrument request tracing across services",
+	}
 }
 
 // RecommendFixes generates actionable fixes based on root cause and blast radius
@@ -153,6 +227,30 @@ func (fr *FixRecommender) RecommendFixes(
 	shortTerm := fr.shortTermActions[resourceType]
 	longTerm := fr.longTermActions[resourceType]
 
+	// Commands for the affected container replace the generic container steps
+	if actions := containerActions(*rootCause.Alert); len(actions) > 0 {
+		if resourceType == domain.ResourceContainer {
+			immediate = actions
+		} else {
+			immediate = append(actions, immediate...)
+		}
+	}
+
+	// Prefer the organization's playbook for this alert. Its steps are copied since the
+	// fix gets appended to.
+	custom, hasCustom := fr.playbooks.Match(*rootCause.Alert)
+	if hasCustom {
+		if len(custom.Immediate) > 0 {
+			immediate = append([]string(nil), custom.Immediate...)
+		}
+		if len(custom.ShortTerm) > 0 {
+			shortTerm = append([]string(nil), custom.ShortTerm...)
+		}
+		if len(custom.LongTerm) > 0 {
+			longTerm = append([]string(nil), custom.LongTerm...)
+		}
+	}
+
 	// Enhance based on blast radius
 	immediate, shortTerm = fr.enhanceForCascade(
 		immediate, shortTerm, blastRadius, resourceType,
@@ -167,7 +265,7 @@ func (fr *FixRecommender) RecommendFixes(
 	// Estimate time to resolve
 	estimatedTime := fr.estimateResolutionTime(blastRadius, complexity)
 
-	return ActionableFix{
+	fix := ActionableFix{
 		ImmediateFix:           immediate,
 		ShortTermFix:           shortTerm,
 		LongTermFix:            longTerm,
@@ -175,6 +273,11 @@ func (fr *FixRecommender) RecommendFixes(
 		FixComplexity:          complexity,
 		EstimatedTimeToResolve: estimatedTime,
 	}
+	if hasCustom {
+		fix.Playbook = custom.ID
+		fix.RunbookURL = custom.RunbookURL
+	}
+	return fix
 }
 
 // enhanceForCascade adds cascade-specific mitigation steps
@@ -247,6 +350,40 @@ func (fr *FixRecommender) addContextualActions(
 	return actions
 }
 
+// containerActions returns the steps to restart the container an alert's chart belongs
+// to, check its limits and inspect its OOM kills, from the labels set by container
+// enrichment; nil for alerts of other charts
+func containerActions(alert domain.Alert) []string {
+	container := alert.Labels[enrichment.LabelContainer]
+	pod, namespace := alert.Labels[enrichment.LabelPod], alert.Labels[enrichment.LabelNamespace]
+	workload := alert.Labels[enrichment.LabelWorkload]
+
+	switch {
+	case pod != "":
+		if namespace == "" {
+			namespace = "default"
+		}
+		target := fmt.Sprintf("-n %s %s", namespace, pod)
+		return []string{
+			fmt.Sprintf("🎯 Target workload: %s (pod %s, container %s)", workload, pod, container),
+			fmt.Sprintf("Inspect OOM kills and restarts: `kubectl get events -n %s --field-selector involvedObject.name=%s`", namespace, pod),
+			fmt.Sprintf("Check the last termination reason: `kubectl get pod %s -o jsonpath='{.status.containerStatuses[*].lastState.terminated.reason}'`", target),
+			fmt.Sprintf("Compare usage with limits: `kubectl top pod %s --containers` and `kubectl get pod %s -o jsonpath='{.spec.containers[*].resources}'`", target, target),
+			fmt.Sprintf("If the pod stays unhealthy, restart it: `kubectl delete pod %s` (its controller recreates it)", target),
+		}
+	case container != "":
+		return []string{
+			fmt.Sprintf("🎯 Target container: %s", container),
+			fmt.Sprintf("Check for OOM kills and restarts: `docker inspect -f '{{.State.OOMKilled}} {{.RestartCount}}' %s`", container),
+			fmt.Sprintf("Inspect recent OOM events: `docker events --since 1h --filter container=%s --filter event=oom`", container),
+			fmt.Sprintf("Compare usage with limits: `docker stats --no-stream %s` and `docker inspect -f '{{.HostConfig.Memory}} {{.HostConfig.NanoCpus}}' %s`", container, container),
+			fmt.Sprintf("If the container stays unhealthy, restart it: `docker restart %s`", container),
+		}
+	default:
+		return nil
+	}
+}
+
 // determineComplexity assesses fix complexity
 func (fr *FixRecommender) determineComplexity(blastRadius EnhancedBlastRadiusAnalysis) string {
 	score := 0
@@ -297,36 +434,5 @@ func (fr *FixRecommender) estimateResolutionTime(
 
 // FormatActionableFix creates formatted output for SREs
 func FormatActionableFix(fix ActionableFix) string {
-	var output strings.Builder
-
-	output.WriteString("╔═══════════════════════════════════════════════════════════════╗\n")
-	output.WriteString("║                 ACTIONABLE FIX PLAYBOOK                       ║\n")
-	output.WriteString("╚═══════════════════════════════════════════════════════════════╝\n\n")
-
-	output.WriteString(fmt.Sprintf("Root Cause: %s\n", fix.RootCauseType))
-	output.WriteString(fmt.Sprintf("Complexity: %s\n", fix.FixComplexity))
-	output.WriteString(fmt.Sprintf("Est. Time to Resolve: %s\n\n", fix.EstimatedTimeToResolve))
-
-	output.WriteString("🔴 IMMEDIATE FIX (NOW - within 5 minutes)\n")
-	output.WriteString("═══════════════════════════════════════════════════════════════\n")
-	for i, action := range fix.ImmediateFix {
-		output.WriteString(fmt.Sprintf("%d. %s\n", i+1, action))
-	}
-	output.WriteString("\n")
-
-	output.WriteString("🟡 SHORT-TERM FIX (TODAY - within 8 hours)\n")
-	output.WriteString("═══════════════════════════════════════════════════════════════\n")
-	for i, action := range fix.ShortTermFix {
-		output.WriteString(fmt.Sprintf("%d. %s\n", i+1, action))
-	}
-	output.WriteString("\n")
-
-	output.WriteString("🟢 LONG-TERM PREVENTION (ONGOING)\n")
-	output.WriteString("═══════════════════════════════════════════════════════════════\n")
-	for i, action := range fix.LongTermFix {
-		output.WriteString(fmt.Sprintf("%d. %s\n", i+1, action))
-	}
-	output.WriteString("\n")
-
-	return output.String()
+	return report.Text{}.Render(ActionableFixDocument(fix))
 }
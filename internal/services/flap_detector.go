@@ -0,0 +1,153 @@
+package services
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// FlappingLabel marks alerts of a stream that is flapping
+const FlappingLabel = "flapping"
+
+// FlapDetector flags alert streams (an alert rule on a host) that oscillate between CLEAR
+// and WARNING/CRITICAL: a stream with at least threshold such transitions within the
+// window is flapping until its transitions thin out again.
+type FlapDetector struct {
+	window    time.Duration
+	threshold int
+
+	mu      sync.Mutex
+	streams map[string]*flapState
+}
+
+// flapState is the recent history of one alert stream
+type flapState struct {
+	known       bool
+	active      bool
+	transitions []time.Time
+}
+
+// NewFlapDetector creates a flap detector
+func NewFlapDetector(window time.Duration, threshold int) *FlapDetector {
+	if window <= 0 {
+		window = 30 * time.Minute
+	}
+	if threshold <= 0 {
+		threshold = 4
+	}
+	return &FlapDetector{
+		window:    window,
+		threshold: threshold,
+		streams:   make(map[string]*flapState),
+	}
+}
+
+// Mark records the state changes of a batch and returns a copy in which alerts of
+// flapping streams carry the FlappingLabel. A nil detector returns the batch unchanged.
+func (d *FlapDetector) Mark(alerts []domain.Alert) []domain.Alert {
+	if d == nil {
+		return alerts
+	}
+
+	d.mu.Lock()
+	flapping := d.observe(d.streams, alerts)
+	d.mu.Unlock()
+
+	marked := make([]domain.Alert, len(alerts))
+	for i, alert := range alerts {
+		if flapping[i] {
+			labels := make(map[string]string, len(alert.Labels)+1)
+			for k, v := range alert.Labels {
+				labels[k] = v
+			}
+			labels[FlappingLabel] = "true"
+			alert.Labels = labels
+		}
+		marked[i] = alert
+	}
+	return marked
+}
+
+// Replay reports which alerts of a history were flapping, without touching the state of
+// the live streams
+func (d *FlapDetector) Replay(alerts []domain.Alert) []bool {
+	return d.observe(make(map[string]*flapState), alerts)
+}
+
+// Flapping reports whether the stream of the alert is currently flapping
+func (d *FlapDetector) Flapping(alert domain.Alert) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	state, ok := d.streams[alertNoiseKey(alert)]
+	return ok && len(state.transitions) >= d.threshold
+}
+
+// observe feeds alerts into the streams in time order and returns, in input order,
+// whether each alert's stream was flapping at that moment
+func (d *FlapDetector) observe(streams map[string]*flapState, alerts []domain.Alert) []bool {
+	order := make([]int, len(alerts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return alerts[order[i]].OccurredAt.Before(alerts[order[j]].OccurredAt)
+	})
+
+	flapping := make([]bool, len(alerts))
+	for _, idx := range order {
+		alert := alerts[idx]
+		if alert.Status == domain.StatusRemoved || alert.Status == domain.StatusUndefined {
+			continue
+		}
+
+		key := alertNoiseKey(alert)
+		state, ok := streams[key]
+		if !ok {
+			state = &flapState{}
+			streams[key] = state
+		}
+
+		active := alert.Status != domain.StatusClear
+		switch {
+		case state.known && state.active != active:
+			state.transitions = append(state.transitions, alert.OccurredAt)
+		case !state.known && isStreamStatus(alert.OldStatus) && isActiveStatus(alert.OldStatus) != active:
+			// The source reported the previous state of a stream we have not seen yet
+			state.transitions = append(state.transitions, alert.OccurredAt)
+		}
+		state.known, state.active = true, active
+
+		cutoff := alert.OccurredAt.Add(-d.window)
+		kept := state.transitions[:0]
+		for _, t := range state.transitions {
+			if t.After(cutoff) {
+				kept = append(kept, t)
+			}
+		}
+		state.transitions = kept
+
+		flapping[idx] = len(state.transitions) >= d.threshold
+	}
+	return flapping
+}
+
+func isActiveStatus(status domain.AlertStatus) bool {
+	return status == domain.StatusWarning || status == domain.StatusCritical
+}
+
+func isStreamStatus(status domain.AlertStatus) bool {
+	return status == domain.StatusClear || isActiveStatus(status)
+}
+
+// WithoutFlapping returns the alerts that are not marked as flapping
+func WithoutFlapping(alerts []domain.Alert) []domain.Alert {
+	result := make([]domain.Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		if alert.Labels[FlappingLabel] != "true" {
+			result = append(result, alert)
+		}
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func TestFlapDetector_MarksOscillatingStreams(t *testing.T) {
+	detector := NewFlapDetector(10*time.Minute, 4)
+	start := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+
+	var alerts []domain.Alert
+	statuses := []domain.AlertStatus{
+		domain.StatusWarning, domain.StatusClear, domain.StatusCritical, domain.StatusClear, domain.StatusWarning,
+	}
+	for i, status := range statuses {
+		alerts = append(alerts, domain.Alert{
+			ID: string(rune('a' + i)), Host: "web-01", Chart: "system.cpu", Name: "cpu_usage",
+			Status: status, OccurredAt: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	// A steady stream on another host is never flapping
+	alerts = append(alerts, domain.Alert{
+		ID: "steady", Host: "db-01", Chart: "system.cpu", Name: "cpu_usage",
+		Status: domain.StatusCritical, OccurredAt: start.Add(4 * time.Minute),
+	})
+
+	marked := detector.Mark(alerts)
+	for i, alert := range marked {
+		want := i == 4 // The fourth transition makes web-01 flap
+		if (alert.Labels[FlappingLabel] == "true") != want {
+			t.Errorf("alert %s: expected flapping=%v, got labels %v", alert.ID, want, alert.Labels)
+		}
+	}
+	if alerts[4].Labels != nil {
+		t.Error("expected the input alerts to be left unchanged")
+	}
+	if got := WithoutFlapping(marked); len(got) != 5 {
+		t.Errorf("expected the flapping alert to be excluded, got %d alerts", len(got))
+	}
+	if !detector.Flapping(alerts[0]) || detector.Flapping(alerts[5]) {
+		t.Error("expected only web-01 to be flapping")
+	}
+
+	// Once the transitions leave the window the stream calms down
+	calm := detector.Mark([]domain.Alert{{
+		Host: "web-01", Chart: "system.cpu", Name: "cpu_usage",
+		Status: domain.StatusWarning, OccurredAt: start.Add(time.Hour),
+	}})
+	if calm[0].Labels[FlappingLabel] == "true" {
+		t.Error("expected the stream to stop flapping after the window")
+	}
+
+	generators := NewNoiseAnalyzer(5*time.Minute).RankGenerators(alerts, detector.Replay(alerts), 1)
+	if len(generators) != 1 || generators[0].Host != "web-01" || generators[0].Transitions != 4 || generators[0].FlappingAlerts != 1 {
+		t.Errorf("expected web-01 to be the top noise generator, got %+v", generators)
+	}
+}
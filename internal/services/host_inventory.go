@@ -0,0 +1,111 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// HostSummary describes one machine in the discovered fleet inventory
+type HostSummary struct {
+	Host            string
+	Info            *domain.HostInfo // Nil when the host was only seen in alerts
+	Health          string           // "healthy", "warning", "critical"
+	TotalIncidents  int
+	ActiveIncidents int
+	ActiveAlerts    int
+	LastAlertAt     *time.Time
+}
+
+// BuildHostInventory merges agent-reported hosts with hosts observed in alerts and incidents
+func BuildHostInventory(infos []domain.HostInfo, alerts []domain.Alert, incidents []domain.Incident) []HostSummary {
+	summaries := make(map[string]*HostSummary)
+	get := func(host string) *HostSummary {
+		if s, ok := summaries[host]; ok {
+			return s
+		}
+		s := &HostSummary{Host: host, Health: "healthy"}
+		summaries[host] = s
+		return s
+	}
+
+	for i := range infos {
+		if infos[i].Hostname == "" {
+			continue
+		}
+		get(infos[i].Hostname).Info = &infos[i]
+	}
+
+	// Latest state per alert stream decides current health
+	type streamState struct {
+		status domain.AlertStatus
+		at     time.Time
+	}
+	latest := make(map[string]map[string]streamState)
+
+	for _, alert := range alerts {
+		summary := get(alert.Host)
+		if summary.LastAlertAt == nil || alert.OccurredAt.After(*summary.LastAlertAt) {
+			at := alert.OccurredAt
+			summary.LastAlertAt = &at
+		}
+
+		if latest[alert.Host] == nil {
+			latest[alert.Host] = make(map[string]streamState)
+		}
+		key := alert.Chart + "|" + alert.Name
+		if prev, ok := latest[alert.Host][key]; !ok || alert.OccurredAt.After(prev.at) {
+			latest[alert.Host][key] = streamState{status: alert.Status, at: alert.OccurredAt}
+		}
+	}
+
+	for host, streams := range latest {
+		summary := get(host)
+		for _, state := range streams {
+			switch state.status {
+			case domain.StatusCritical:
+				summary.ActiveAlerts++
+				summary.Health = "critical"
+			case domain.StatusWarning:
+				summary.ActiveAlerts++
+				if summary.Health != "critical" {
+					summary.Health = "warning"
+				}
+			}
+		}
+	}
+
+	for _, incident := range incidents {
+		for _, host := range IncidentHosts(incident) {
+			summary := get(host)
+			summary.TotalIncidents++
+			if incident.ResolvedAt == nil {
+				summary.ActiveIncidents++
+			}
+		}
+	}
+
+	result := make([]HostSummary, 0, len(summaries))
+	for _, s := range summaries {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Host < result[j].Host
+	})
+
+	return result
+}
+
+// IncidentHosts returns the distinct hosts involved in an incident
+func IncidentHosts(incident domain.Incident) []string {
+	seen := make(map[string]bool)
+	hosts := []string{}
+	for _, event := range incident.Events {
+		if event.Host != "" && !seen[event.Host] {
+			seen[event.Host] = true
+			hosts = append(hosts, event.Host)
+		}
+	}
+	return hosts
+}
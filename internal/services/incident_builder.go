@@ -1,47 +1,123 @@
 package services
 
 import (
-	"fmt"
 	"sort"
+	"sync"
 	"time"
 
 	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
+	"incident-teller/internal/templates"
 )
 
 type IncidentBuilder struct {
-	window time.Duration
+	mu        sync.RWMutex
+	window    time.Duration
+	strategy  CorrelationStrategy
+	templates *templates.Set
+	storms    *StormDetector
 }
 
 func NewIncidentBuilder(window time.Duration) *IncidentBuilder {
-	return &IncidentBuilder{window: window}
+	return &IncidentBuilder{window: window, strategy: windowStrategy{}}
 }
 
+// SetStrategy changes how alerts are partitioned before time-window grouping
+func (b *IncidentBuilder) SetStrategy(strategy CorrelationStrategy) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.strategy = strategy
+}
+
+// SetWindow changes the correlation window; builds already running keep the old one
+func (b *IncidentBuilder) SetWindow(window time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.window = window
+}
+
+// SetTemplates pre-populates incidents matching a template with its title and default
+// priority; nil disables templates
+func (b *IncidentBuilder) SetTemplates(set *templates.Set) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.templates = set
+}
+
+// SetStormDetector collapses alert storms into a single incident; nil disables it
+func (b *IncidentBuilder) SetStormDetector(detector *StormDetector) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.storms = detector
+}
 
 func (b *IncidentBuilder) Build(alerts []domain.Alert) []domain.Incident {
 	if len(alerts) == 0 {
 		return nil
 	}
 
+	b.mu.RLock()
+	window, strategy, set, storms := b.window, b.strategy, b.templates, b.storms
+	b.mu.RUnlock()
+
 	sort.Slice(alerts, func(i, j int) bool {
 		return alerts[i].OccurredAt.Before(alerts[j].OccurredAt)
 	})
 
+	// The alerts of a storm form one incident instead of being correlated
+	storm, alerts := storms.Collapse(alerts)
+
+	// Partition by strategy key, keeping time order within each partition
+	var keys []string
+	partitions := make(map[string][]domain.Alert)
+	for _, alert := range alerts {
+		key := strategy.Key(alert)
+		if _, exists := partitions[key]; !exists {
+			keys = append(keys, key)
+		}
+		partitions[key] = append(partitions[key], alert)
+	}
+
 	var incidents []domain.Incident
-	if len(alerts) == 0 {
-		return nil
+	for _, key := range keys {
+		incidents = append(incidents, buildWindowed(partitions[key], window)...)
+	}
+	if storm != nil {
+		incidents = append(incidents, *storm)
+	}
+
+	sort.SliceStable(incidents, func(i, j int) bool {
+		return incidents[i].StartedAt.Before(incidents[j].StartedAt)
+	})
+
+	// An incident whose last event cleared is resolved at that moment
+	for i := range incidents {
+		events := incidents[i].Events
+		if len(events) > 0 && incidents[i].Status == domain.StatusClear {
+			resolvedAt := events[len(events)-1].OccurredAt
+			incidents[i].ResolvedAt = &resolvedAt
+		}
+		incidents[i] = set.Apply(incidents[i])
 	}
-	
+
+	return incidents
+}
+
+// buildWindowed groups time-ordered alerts into incidents spanning at most the window
+func buildWindowed(alerts []domain.Alert, window time.Duration) []domain.Incident {
+	var incidents []domain.Incident
+
 	current := domain.Incident{
-		ID:        fmt.Sprintf("incident-%s-%d", alerts[0].Host, alerts[0].OccurredAt.Unix()),
+		ID:        idgen.Derive(alerts[0].OccurredAt, alerts[0].ID),
 		StartedAt: alerts[0].OccurredAt,
 		Status:    alerts[0].Status,
 	}
 
 	for _, alert := range alerts {
-		if alert.OccurredAt.Sub(current.StartedAt) > b.window {
+		if alert.OccurredAt.Sub(current.StartedAt) > window {
 			incidents = append(incidents, current)
 			current = domain.Incident{
-				ID:        fmt.Sprintf("incident-%s-%d", alert.Host, alert.OccurredAt.Unix()),
+				ID:        idgen.Derive(alert.OccurredAt, alert.ID),
 				StartedAt: alert.OccurredAt,
 				Status:    alert.Status,
 			}
@@ -50,6 +126,5 @@ func (b *IncidentBuilder) Build(alerts []domain.Alert) []domain.Incident {
 		current.Status = alert.Status
 	}
 
-	incidents = append(incidents, current)
-	return incidents
+	return append(incidents, current)
 }
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func TestIncidentBuilder_ResolvesClearedIncidents(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	alerts := []domain.Alert{
+		{ID: "a1", Host: "web-01", Status: domain.StatusCritical, OccurredAt: start},
+		{ID: "a2", Host: "web-01", Status: domain.StatusClear, OccurredAt: start.Add(2 * time.Minute)},
+		// Outside the window: a second incident that is still firing
+		{ID: "a3", Host: "db-01", Status: domain.StatusWarning, OccurredAt: start.Add(time.Hour)},
+	}
+
+	incidents := NewIncidentBuilder(5 * time.Minute).Build(alerts)
+	if len(incidents) != 2 {
+		t.Fatalf("expected 2 incidents, got %d", len(incidents))
+	}
+	if resolved := incidents[0].ResolvedAt; resolved == nil || !resolved.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("expected the cleared incident to be resolved at its last event, got %v", resolved)
+	}
+	if incidents[1].ResolvedAt != nil {
+		t.Errorf("expected the firing incident to stay open, got %v", incidents[1].ResolvedAt)
+	}
+}
+
+func TestIncidentBuilder_ReopenedIncidentStaysOpen(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	alerts := []domain.Alert{
+		{ID: "a1", Host: "web-01", Status: domain.StatusCritical, OccurredAt: start},
+		{ID: "a2", Host: "web-01", Status: domain.StatusClear, OccurredAt: start.Add(time.Minute)},
+		{ID: "a3", Host: "web-01", Status: domain.StatusWarning, OccurredAt: start.Add(2 * time.Minute)},
+	}
+
+	incidents := NewIncidentBuilder(5 * time.Minute).Build(alerts)
+	if len(incidents) != 1 || incidents[0].ResolvedAt != nil {
+		t.Errorf("expected one open incident after the alert fired again, got %+v", incidents)
+	}
+}
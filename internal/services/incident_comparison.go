@@ -0,0 +1,171 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// Overlap splits the values seen in two incidents into shared and one-sided ones
+type Overlap struct {
+	Common     []string
+	OnlyA      []string
+	OnlyB      []string
+	Similarity float64 // Jaccard index: shared values over all values, 0-1
+}
+
+// AlignedSignal is an alert signal (chart and alert name) seen in both incidents, with
+// when it first fired relative to the start of each
+type AlignedSignal struct {
+	Signal       string
+	ResourceType domain.ResourceType
+	OffsetA      time.Duration
+	OffsetB      time.Duration
+}
+
+// Shift is how much later the signal fired in B than in A, relative to the incidents' starts
+func (s AlignedSignal) Shift() time.Duration {
+	return s.OffsetB - s.OffsetA
+}
+
+// TimelineAlignment lines up the signals of two incidents by their offset from each
+// incident's start, ignoring hosts, so a repeat on other machines still aligns
+type TimelineAlignment struct {
+	Aligned   []AlignedSignal // Ordered by the offset in A
+	OnlyA     []string
+	OnlyB     []string
+	SameOrder bool          // The shared signals first fired in the same order
+	MeanShift time.Duration // Mean absolute shift of the shared signals
+}
+
+// IncidentComparison is the alert-level diff of two incidents
+type IncidentComparison struct {
+	Hosts     Overlap
+	Resources Overlap // Resource types
+	Charts    Overlap
+	Timeline  TimelineAlignment
+}
+
+// CompareIncidents diffs the hosts, resources and timelines of incidents a and b
+func CompareIncidents(a, b domain.Incident) IncidentComparison {
+	resourceType := func(e domain.Alert) string { return string(e.ResourceType) }
+	chart := func(e domain.Alert) string { return e.Chart }
+	return IncidentComparison{
+		Hosts:     NewOverlap(a.Hosts(), b.Hosts()),
+		Resources: NewOverlap(incidentValues(a, resourceType), incidentValues(b, resourceType)),
+		Charts:    NewOverlap(incidentValues(a, chart), incidentValues(b, chart)),
+		Timeline:  alignTimelines(a, b),
+	}
+}
+
+// incidentValues returns the distinct non-empty values of the incident's events
+func incidentValues(incident domain.Incident, value func(domain.Alert) string) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, event := range incident.Events {
+		if v := value(event); v != "" && !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// NewOverlap splits two value sets into sorted shared and one-sided values
+func NewOverlap(a, b []string) Overlap {
+	inB := make(map[string]bool, len(b))
+	for _, v := range b {
+		inB[v] = true
+	}
+
+	result := Overlap{Common: []string{}, OnlyA: []string{}, OnlyB: []string{}}
+	inA := make(map[string]bool, len(a))
+	for _, v := range a {
+		inA[v] = true
+		if inB[v] {
+			result.Common = append(result.Common, v)
+		} else {
+			result.OnlyA = append(result.OnlyA, v)
+		}
+	}
+	for _, v := range b {
+		if !inA[v] {
+			result.OnlyB = append(result.OnlyB, v)
+		}
+	}
+	sort.Strings(result.Common)
+	sort.Strings(result.OnlyA)
+	sort.Strings(result.OnlyB)
+
+	if total := len(result.Common) + len(result.OnlyA) + len(result.OnlyB); total > 0 {
+		result.Similarity = float64(len(result.Common)) / float64(total)
+	}
+	return result
+}
+
+// signalOffsets returns when each signal of the incident first fired, relative to its start
+func signalOffsets(incident domain.Incident) (map[string]time.Duration, map[string]domain.ResourceType) {
+	offsets := make(map[string]time.Duration)
+	resources := make(map[string]domain.ResourceType)
+	for _, event := range incident.Events {
+		signal := event.Chart + "/" + event.Name
+		offset := event.OccurredAt.Sub(incident.StartedAt)
+		if first, ok := offsets[signal]; !ok || offset < first {
+			offsets[signal] = offset
+			resources[signal] = event.ResourceType
+		}
+	}
+	return offsets, resources
+}
+
+// alignTimelines lines up the signals of a and b
+func alignTimelines(a, b domain.Incident) TimelineAlignment {
+	offsetsA, resources := signalOffsets(a)
+	offsetsB, _ := signalOffsets(b)
+
+	alignment := TimelineAlignment{Aligned: []AlignedSignal{}, OnlyA: []string{}, OnlyB: []string{}, SameOrder: true}
+	for signal, offset := range offsetsA {
+		if offsetB, ok := offsetsB[signal]; ok {
+			alignment.Aligned = append(alignment.Aligned, AlignedSignal{
+				Signal:       signal,
+				ResourceType: resources[signal],
+				OffsetA:      offset,
+				OffsetB:      offsetB,
+			})
+		} else {
+			alignment.OnlyA = append(alignment.OnlyA, signal)
+		}
+	}
+	for signal := range offsetsB {
+		if _, ok := offsetsA[signal]; !ok {
+			alignment.OnlyB = append(alignment.OnlyB, signal)
+		}
+	}
+	sort.Strings(alignment.OnlyA)
+	sort.Strings(alignment.OnlyB)
+	sort.Slice(alignment.Aligned, func(i, j int) bool {
+		x, y := alignment.Aligned[i], alignment.Aligned[j]
+		if x.OffsetA != y.OffsetA {
+			return x.OffsetA < y.OffsetA
+		}
+		return x.Signal < y.Signal
+	})
+
+	var total time.Duration
+	for i, signal := range alignment.Aligned {
+		shift := signal.Shift()
+		if shift < 0 {
+			shift = -shift
+		}
+		total += shift
+		// Signals firing at the same offset in A may fire in either order in B
+		if i > 0 && signal.OffsetA > alignment.Aligned[i-1].OffsetA && signal.OffsetB < alignment.Aligned[i-1].OffsetB {
+			alignment.SameOrder = false
+		}
+	}
+	if len(alignment.Aligned) > 0 {
+		alignment.MeanShift = total / time.Duration(len(alignment.Aligned))
+	}
+	return alignment
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func TestCompareIncidents(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	alert := func(host, chart, name string, resource domain.ResourceType, at time.Duration) domain.Alert {
+		return domain.Alert{Host: host, Chart: chart, Name: name, ResourceType: resource, OccurredAt: start.Add(at)}
+	}
+	a := domain.Incident{ID: "a", StartedAt: start, Events: []domain.Alert{
+		alert("db-1", "disk.space", "disk_full", domain.ResourceDisk, 0),
+		alert("db-1", "mysql.queries", "slow_queries", domain.ResourceProcess, 2*time.Minute),
+		alert("web-1", "system.cpu", "cpu_high", domain.ResourceCPU, 5*time.Minute),
+	}}
+	// The repeat a week later on another replica: same cascade, faster, without the CPU alert
+	later := start.Add(7 * 24 * time.Hour)
+	b := domain.Incident{ID: "b", StartedAt: later, Events: []domain.Alert{
+		alert("db-2", "disk.space", "disk_full", domain.ResourceDisk, 7*24*time.Hour),
+		alert("db-2", "mysql.queries", "slow_queries", domain.ResourceProcess, 7*24*time.Hour+time.Minute),
+		alert("db-1", "disk.space", "disk_full", domain.ResourceDisk, 7*24*time.Hour+3*time.Minute),
+	}}
+
+	comparison := CompareIncidents(a, b)
+
+	if want := (Overlap{Common: []string{"db-1"}, OnlyA: []string{"web-1"}, OnlyB: []string{"db-2"}, Similarity: 1.0 / 3}); !reflect.DeepEqual(comparison.Hosts, want) {
+		t.Errorf("unexpected host overlap %+v", comparison.Hosts)
+	}
+	if got := comparison.Resources; len(got.Common) != 2 || !reflect.DeepEqual(got.OnlyA, []string{string(domain.ResourceCPU)}) {
+		t.Errorf("unexpected resource overlap %+v", got)
+	}
+
+	timeline := comparison.Timeline
+	if len(timeline.Aligned) != 2 || timeline.Aligned[0].Signal != "disk.space/disk_full" || timeline.Aligned[1].Signal != "mysql.queries/slow_queries" {
+		t.Fatalf("unexpected alignment %+v", timeline.Aligned)
+	}
+	// The first disk alert of b counts, not the later one on db-1
+	if timeline.Aligned[0].Shift() != 0 || timeline.Aligned[1].Shift() != -time.Minute {
+		t.Errorf("unexpected shifts %+v", timeline.Aligned)
+	}
+	if !timeline.SameOrder || timeline.MeanShift != 30*time.Second {
+		t.Errorf("expected the same order with a mean shift of 30s, got %+v", timeline)
+	}
+	if !reflect.DeepEqual(timeline.OnlyA, []string{"system.cpu/cpu_high"}) || len(timeline.OnlyB) != 0 {
+		t.Errorf("unexpected one-sided signals %v and %v", timeline.OnlyA, timeline.OnlyB)
+	}
+
+	// Reversing the cascade changes the order
+	b.Events[1].OccurredAt = later.Add(-time.Minute)
+	b.StartedAt = later.Add(-time.Minute)
+	if CompareIncidents(a, b).Timeline.SameOrder {
+		t.Error("expected a reversed cascade not to be in the same order")
+	}
+}
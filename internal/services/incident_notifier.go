@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/notify"
+	"incident-teller/internal/oncall"
+	"incident-teller/internal/playbook"
+	"incident-teller/internal/templates"
+)
+
+// IncidentNotifier analyzes incidents and sends notifications once per incident,
+// gating the analysis through a QualityGate first
+type IncidentNotifier struct {
+	analyzer   *ComprehensiveIncidentAnalyzer
+	gate       *QualityGate
+	dispatcher *notify.Dispatcher
+	onCall     *oncall.Manager
+	storms     *StormDetector
+
+	mu         sync.Mutex
+	templates  *templates.Set
+	notified   map[string]bool     // Incidents that received a full notification
+	reanalysis map[string][]string // Incidents flagged for re-analysis -> gate failures
+}
+
+// NewIncidentNotifier creates an incident notifier
+func NewIncidentNotifier(gate *QualityGate, dispatcher *notify.Dispatcher) *IncidentNotifier {
+	return &IncidentNotifier{
+		analyzer:   NewComprehensiveIncidentAnalyzer(),
+		gate:       gate,
+		dispatcher: dispatcher,
+		notified:   make(map[string]bool),
+		reanalysis: make(map[string][]string),
+	}
+}
+
+// SetChangeTracker enables change-event correlation in notification analysis
+func (n *IncidentNotifier) SetChangeTracker(tracker *ChangeTracker) {
+	n.analyzer.SetChangeTracker(tracker)
+}
+
+// SetPropagationLearner enables learned propagation patterns in notification analysis
+func (n *IncidentNotifier) SetPropagationLearner(learner *PropagationLearner) {
+	n.analyzer.SetPropagationLearner(learner)
+}
+
+// SetQualityGate replaces the quality gate; notifications being analyzed keep the old one
+func (n *IncidentNotifier) SetQualityGate(gate *QualityGate) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.gate = gate
+}
+
+// SetOnCall enables auto-assignment of critical incidents to the current on-call member
+func (n *IncidentNotifier) SetOnCall(manager *oncall.Manager) {
+	n.onCall = manager
+}
+
+// SetStormDetector suppresses the notifications of incidents starting during an alert storm
+func (n *IncidentNotifier) SetStormDetector(detector *StormDetector) {
+	n.storms = detector
+}
+
+// SetPlaybooks makes notification fixes use the organization's playbooks
+func (n *IncidentNotifier) SetPlaybooks(store *playbook.Store) {
+	n.analyzer.SetPlaybooks(store)
+}
+
+// SetTemplates applies the notification overrides and known remediation of the template
+// an incident was created from
+func (n *IncidentNotifier) SetTemplates(set *templates.Set) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.templates = set
+}
+
+// Notify analyzes the incident and sends a notification if one is due.
+// Incidents that already got a full notification are skipped; incidents flagged for
+// re-analysis are analyzed again and get the full notification once they pass the gate.
+// During an alert storm only the storm incident is notified.
+func (n *IncidentNotifier) Notify(ctx context.Context, incident domain.Incident) error {
+	if len(incident.Events) == 0 {
+		return nil
+	}
+
+	n.mu.Lock()
+	_, flagged := n.reanalysis[incident.ID]
+	if n.notified[incident.ID] {
+		n.mu.Unlock()
+		return nil
+	}
+	gate := n.gate
+	template, hasTemplate := n.templates.Get(incident.Template)
+	n.mu.Unlock()
+
+	if hasTemplate && template.SkipNotification {
+		return nil
+	}
+	if n.storms.Throttle(incident) {
+		return nil
+	}
+
+	intelligence := n.analyzer.AnalyzeIncident(incident)
+	if hasTemplate {
+		applyTemplateFixes(&intelligence.ActionableFixes, template)
+	}
+	result := gate.Check(intelligence)
+
+	if !result.Passed {
+		n.mu.Lock()
+		n.reanalysis[incident.ID] = result.Failures
+		n.mu.Unlock()
+
+		// Only one minimal notification per incident
+		if flagged {
+			return nil
+		}
+		notification := n.minimalNotification(incident, result)
+		notification.Labels = templateLabels(notification.Labels, template)
+		n.assign(incident, &notification)
+		return n.dispatcher.Send(ctx, notification)
+	}
+
+	document := IncidentAlertDocument(intelligence)
+	notification := notify.Notification{
+		IncidentID:  incident.ID,
+		Title:       incident.Title,
+		Severity:    incidentSeverity(incident),
+		Priority:    string(incident.Priority()),
+		Text:        n.analyzer.GenerateSlackMessage(intelligence),
+		Document:    &document,
+		ImpactScore: intelligence.BlastRadius.ImpactScore,
+		Labels:      templateLabels(incident.Labels(), template),
+		Hosts:       incident.Hosts(),
+	}
+	n.assign(incident, &notification)
+	if err := n.dispatcher.Send(ctx, notification); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	n.notified[incident.ID] = true
+	delete(n.reanalysis, incident.ID)
+	n.mu.Unlock()
+
+	return nil
+}
+
+// FlaggedForReanalysis returns incident IDs whose analysis failed the quality gate, with the reasons
+func (n *IncidentNotifier) FlaggedForReanalysis() map[string][]string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	result := make(map[string][]string, len(n.reanalysis))
+	for id, failures := range n.reanalysis {
+		result[id] = append([]string(nil), failures...)
+	}
+	return result
+}
+
+// assign auto-assigns critical incidents to the current on-call member and routes the
+// notification to them
+func (n *IncidentNotifier) assign(incident domain.Incident, notification *notify.Notification) {
+	if n.onCall == nil || notification.Severity != "critical" {
+		return
+	}
+
+	assignment, ok := n.onCall.Assign(incident.ID, time.Now())
+	if !ok {
+		return
+	}
+
+	notification.Assignee = assignment.Member.Name
+	notification.Mention = assignment.Member.SlackID
+	notification.Text += fmt.Sprintf("\n\n*Assigned to:* %s (on call)", assignment.Member.Name)
+}
+
+// minimalNotification builds a factual notification without any analysis claims
+func (n *IncidentNotifier) minimalNotification(incident domain.Incident, result QualityResult) notify.Notification {
+	hosts := make(map[string]bool)
+	names := make(map[string]bool)
+	for _, event := range incident.Events {
+		hosts[event.Host] = true
+		names[event.Name] = true
+	}
+
+	title := incident.Title
+	if title == "" {
+		title = fmt.Sprintf("Incident %s", incident.ID)
+	}
+
+	text := fmt.Sprintf(`🔔 *INCIDENT* %s
+
+*Status:* %s
+*Started:* %s
+*Hosts:* %s
+*Alerts:* %d (%s)
+
+_Automated analysis is pending; this incident has been queued for re-analysis._`,
+		title,
+		incident.Status,
+		incident.StartedAt.Format(time.RFC3339),
+		strings.Join(sortedKeys(hosts), ", "),
+		len(incident.Events),
+		strings.Join(sortedKeys(names), ", "),
+	)
+
+	return notify.Notification{
+		IncidentID: incident.ID,
+		Title:      title,
+		Severity:   incidentSeverity(incident),
+		Priority:   string(incident.Priority()),
+		Text:       text,
+		Minimal:    true,
+		Labels:     incident.Labels(),
+		Hosts:      incident.Hosts(),
+	}
+}
+
+// applyTemplateFixes replaces the generic immediate fixes with the template's known
+// remediation and runbook
+func applyTemplateFixes(fix *ActionableFix, t templates.Template) {
+	if len(t.Remediation) > 0 {
+		fix.ImmediateFix = append([]string(nil), t.Remediation...)
+	}
+	if t.RunbookURL != "" {
+		fix.RunbookURL = t.RunbookURL
+	}
+}
+
+// templateLabels adds the template's notification labels, which take precedence, to the
+// incident labels notification routes match
+func templateLabels(incidentLabels map[string]string, t templates.Template) map[string]string {
+	if len(t.NotificationLabels) == 0 {
+		return incidentLabels
+	}
+	result := make(map[string]string, len(incidentLabels)+len(t.NotificationLabels))
+	for k, v := range incidentLabels {
+		result[k] = v
+	}
+	for k, v := range t.NotificationLabels {
+		result[k] = v
+	}
+	return result
+}
+
+// incidentSeverity maps the incident status to a notification severity
+func incidentSeverity(incident domain.Incident) string {
+	for _, event := range incident.Events {
+		if event.Status == domain.StatusCritical {
+			return "critical"
+		}
+	}
+	if incident.Status == domain.StatusWarning {
+		return "warning"
+	}
+	return "info"
+}
+
+func sortedKeys(m map[string]bool) []string {
+	result := keys(m)
+	sort.Strings(result)
+	return result
+}
@@ -1,12 +1,13 @@
 package services
 
 import (
-	"fmt"
 	"sort"
 	"strings"
 	"time"
 
 	"incident-teller/internal/domain"
+	"incident-teller/internal/playbook"
+	"incident-teller/internal/report"
 )
 
 // IncidentStory represents a narrative-style incident report
@@ -24,6 +25,7 @@ type IncidentFix struct {
 	ImmediateActions  []string // Right now (< 5 min)
 	ShortTermActions  []string // Today (< 8 hours)
 	LongTermActions   []string // Prevention (ongoing)
+	RunbookURL        string   // Organization runbook for the root cause, if any
 }
 
 // IncidentTeller converts technical incident data into human-readable stories
@@ -38,12 +40,39 @@ func NewIncidentTeller() *IncidentTeller {
 	}
 }
 
+// SetChangeTracker enables change-event correlation in the generated stories
+func (it *IncidentTeller) SetChangeTracker(tracker *ChangeTracker) {
+	it.comprehensiveAnalyzer.SetChangeTracker(tracker)
+}
+
+// SetPropagationLearner enables learned propagation patterns in the generated stories
+func (it *IncidentTeller) SetPropagationLearner(learner *PropagationLearner) {
+	it.comprehensiveAnalyzer.SetPropagationLearner(learner)
+}
+
+// SetPlaybooks makes the fixes in the generated stories use the organization's playbooks
+func (it *IncidentTeller) SetPlaybooks(store *playbook.Store) {
+	it.comprehensiveAnalyzer.SetPlaybooks(store)
+}
+
 // TellStory converts incident alerts into a narrative story
 func (it *IncidentTeller) TellStory(alerts []domain.Alert) IncidentStory {
+	return it.TellStoryIn(alerts, StoryVoice{})
+}
+
+// TellStoryIn tells the story of incident alerts in the given tone and language
+func (it *IncidentTeller) TellStoryIn(alerts []domain.Alert, voice StoryVoice) IncidentStory {
+	return it.TellIncidentStoryIn(domain.Incident{Events: alerts}, voice)
+}
+
+// TellIncidentStoryIn tells the story of an incident in the given tone and language, around
+// the root cause a responder pinned, if any
+func (it *IncidentTeller) TellIncidentStoryIn(incident domain.Incident, voice StoryVoice) IncidentStory {
+	alerts := incident.Events
 	if len(alerts) == 0 {
 		return IncidentStory{
-			Summary:     "No incident detected",
-			GeneratedAt: time.Now(),
+			Summary:     voice.phrase("story.none"),
+			GeneratedAt: inZone(time.Now(), voice.Location),
 		}
 	}
 
@@ -55,14 +84,17 @@ func (it *IncidentTeller) TellStory(alerts []domain.Alert) IncidentStory {
 	})
 
 	// Perform comprehensive analysis
-	intelligence := it.comprehensiveAnalyzer.Analyze(sortedAlerts)
+	intelligence := it.comprehensiveAnalyzer.AnalyzeIncident(domain.Incident{
+		Events:            sortedAlerts,
+		RootCauseOverride: incident.RootCauseOverride,
+	})
 
 	// Generate narrative sections
-	timeline := it.narrateTimeline(sortedAlerts, intelligence)
-	rootCause := it.narrateRootCause(intelligence)
-	impact := it.narrateImpact(intelligence)
+	timeline := it.narrateTimeline(voice, sortedAlerts, intelligence)
+	rootCause := it.narrateRootCause(voice, intelligence)
+	impact := it.narrateImpact(voice, intelligence)
 	fix := it.narrateFixes(intelligence)
-	summary := it.generateSummary(sortedAlerts, intelligence)
+	summary := it.generateSummary(voice, sortedAlerts, intelligence)
 
 	return IncidentStory{
 		Timeline:    timeline,
@@ -70,61 +102,62 @@ func (it *IncidentTeller) TellStory(alerts []domain.Alert) IncidentStory {
 		Impact:      impact,
 		Fix:         fix,
 		Summary:     summary,
-		GeneratedAt: time.Now(),
+		GeneratedAt: inZone(time.Now(), voice.Location),
 	}
 }
 
 // narrateTimeline creates a cause → effect timeline narrative
 func (it *IncidentTeller) narrateTimeline(
+	v StoryVoice,
 	alerts []domain.Alert,
 	intelligence IncidentIntelligence,
 ) string {
 	var narrative strings.Builder
 
-	narrative.WriteString("Here's what happened:\n\n")
+	narrative.WriteString(v.phrase("timeline.intro"))
 
 	// Group alerts by time proximity (within 2 minutes = same event cluster)
 	clusters := it.clusterEvents(alerts)
 
 	for i, cluster := range clusters {
 		firstAlert := &cluster[0]
-		timestamp := firstAlert.OccurredAt.Format("15:04:05")
+		timestamp := v.clock(firstAlert.OccurredAt)
 
 		if i == 0 {
 			// First event - the trigger
-			narrative.WriteString(fmt.Sprintf("At %s, we first noticed %s on %s ",
-				timestamp,
-				it.describeAlert(firstAlert),
-				firstAlert.Host))
-
-			if firstAlert.Value > 90 {
-				narrative.WriteString(fmt.Sprintf("hitting %.1f%%. ", firstAlert.Value))
-			} else {
-				narrative.WriteString(fmt.Sprintf("at %.1f%%. ", firstAlert.Value))
+			key := "timeline.first"
+			if firstAlert.Value > peakValue(firstAlert.ResourceType) {
+				key = "timeline.first.peak"
 			}
+			narrative.WriteString(v.phrase(key,
+				timestamp,
+				it.describeAlert(v, firstAlert),
+				firstAlert.Host,
+				firstAlert.Value))
+			narrative.WriteString(it.narrateTrend(v, firstAlert))
 
 			if len(cluster) > 1 {
-				narrative.WriteString(fmt.Sprintf("Around the same time, %s also showed issues. ",
-					it.listOtherAlerts(cluster[1:])))
+				narrative.WriteString(v.phrase("timeline.also.first",
+					it.listOtherAlerts(v, cluster[1:])))
 			}
 		} else {
 			// Subsequent events - cascading effects
 			timeSinceLast := firstAlert.OccurredAt.Sub(clusters[i-1][0].OccurredAt)
 
-			narrative.WriteString(fmt.Sprintf("\n%s later (%s), this caused %s ",
-				it.formatDuration(timeSinceLast),
-				timestamp,
-				it.describeAlert(firstAlert)))
-
+			key := "timeline.next"
 			if firstAlert.ResourceType != alerts[0].ResourceType {
-				narrative.WriteString("to degrade ")
+				key = "timeline.next.degrade"
 			}
-
-			narrative.WriteString(fmt.Sprintf("(%.1f%%). ", firstAlert.Value))
+			narrative.WriteString(v.phrase(key,
+				it.formatDuration(v, timeSinceLast),
+				timestamp,
+				it.describeAlert(v, firstAlert),
+				firstAlert.Value))
+			narrative.WriteString(it.narrateTrend(v, firstAlert))
 
 			if len(cluster) > 1 {
-				narrative.WriteString(fmt.Sprintf("We also saw %s failing. ",
-					it.listOtherAlerts(cluster[1:])))
+				narrative.WriteString(v.phrase("timeline.also.next",
+					it.listOtherAlerts(v, cluster[1:])))
 			}
 		}
 	}
@@ -133,48 +166,56 @@ func (it *IncidentTeller) narrateTimeline(
 	lastAlert := alerts[len(alerts)-1]
 	totalDuration := lastAlert.OccurredAt.Sub(alerts[0].OccurredAt)
 
-	narrative.WriteString(fmt.Sprintf("\n\nThe situation fully developed over %s.",
-		it.formatDuration(totalDuration)))
+	narrative.WriteString(v.phrase("timeline.end",
+		it.formatDuration(v, totalDuration)))
 
 	return narrative.String()
 }
 
+// narrateTrend describes the samples leading up to an alert, if it has any
+func (it *IncidentTeller) narrateTrend(v StoryVoice, alert *domain.Alert) string {
+	values, span := sampleValues(*alert)
+	if values == "" {
+		return ""
+	}
+	return v.phrase("timeline.trend", DescribeSamples(*alert), values, it.formatDuration(v, span))
+}
+
 // narrateRootCause explains the root cause in plain English
-func (it *IncidentTeller) narrateRootCause(intelligence IncidentIntelligence) string {
+func (it *IncidentTeller) narrateRootCause(v StoryVoice, intelligence IncidentIntelligence) string {
 	var narrative strings.Builder
 
 	rc := intelligence.RootCause
 
-	narrative.WriteString("Looking at the timeline and correlation patterns, ")
+	narrative.WriteString(v.phrase("rootcause.intro"))
 
 	// Confidence-based language
 	if rc.ConfidenceScore >= 90 {
-		narrative.WriteString("I'm highly confident that ")
+		narrative.WriteString(v.phrase("rootcause.confidence.high"))
 	} else if rc.ConfidenceScore >= 75 {
-		narrative.WriteString("the evidence strongly suggests ")
+		narrative.WriteString(v.phrase("rootcause.confidence.strong"))
 	} else if rc.ConfidenceScore >= 60 {
-		narrative.WriteString("it appears ")
+		narrative.WriteString(v.phrase("rootcause.confidence.likely"))
 	} else {
-		narrative.WriteString("it's possible that ")
+		narrative.WriteString(v.phrase("rootcause.confidence.possible"))
 	}
 
-	narrative.WriteString(fmt.Sprintf("the root cause was %s ",
-		it.describeAlert(rc.Alert)))
-
-	narrative.WriteString(fmt.Sprintf("on %s. ", rc.Alert.Host))
+	narrative.WriteString(v.phrase("rootcause.cause",
+		it.describeAlert(v, rc.Alert),
+		rc.Alert.Host,
+		rc.ConfidenceScore))
 
 	// Add reasoning
 	if rc.IsEarliest {
-		narrative.WriteString("This was the first thing to fail. ")
+		narrative.WriteString(v.phrase("rootcause.earliest"))
 	}
 
 	if rc.HasCascade {
-		narrative.WriteString("After it hit critical levels, we saw a cascade effect ")
-		narrative.WriteString("where other resources started degrading. ")
+		narrative.WriteString(v.phrase("rootcause.cascade"))
 	}
 
 	if rc.HasLogErrors {
-		narrative.WriteString("The error logs around this time corroborate this. ")
+		narrative.WriteString(v.phrase("rootcause.logs"))
 	}
 
 	// Alternative causes
@@ -187,9 +228,8 @@ func (it *IncidentTeller) narrateRootCause(intelligence IncidentIntelligence) st
 		}
 
 		if closeCalls > 0 {
-			narrative.WriteString(fmt.Sprintf("\n\nThere's also a chance that %s ",
-				it.describeAlert(intelligence.AlternativeCauses[0].Alert)))
-			narrative.WriteString("contributed, but the timing makes the primary cause more likely.")
+			narrative.WriteString(v.phrase("rootcause.alternative",
+				it.describeAlert(v, intelligence.AlternativeCauses[0].Alert)))
 		}
 	}
 
@@ -197,39 +237,38 @@ func (it *IncidentTeller) narrateRootCause(intelligence IncidentIntelligence) st
 }
 
 // narrateImpact describes the blast radius in human terms
-func (it *IncidentTeller) narrateImpact(intelligence IncidentIntelligence) string {
+func (it *IncidentTeller) narrateImpact(v StoryVoice, intelligence IncidentIntelligence) string {
 	var narrative strings.Builder
 
 	br := intelligence.BlastRadius
 
 	// Start with simple summary
-	narrative.WriteString(br.SimpleSummary)
-	narrative.WriteString("\n\n")
+	narrative.WriteString(v.phrase("impact.summary", br.SimpleSummary))
 
 	// Severity assessment
 	if br.ImpactScore >= 80 {
-		narrative.WriteString("This is a significant incident. ")
+		narrative.WriteString(v.phrase("impact.severity.high"))
 	} else if br.ImpactScore >= 60 {
-		narrative.WriteString("This is a moderate incident. ")
+		narrative.WriteString(v.phrase("impact.severity.moderate"))
 	} else {
-		narrative.WriteString("This is a relatively contained incident. ")
+		narrative.WriteString(v.phrase("impact.severity.contained"))
 	}
 
 	// Affected components
 	if len(br.AffectedHosts) == 1 {
-		narrative.WriteString(fmt.Sprintf("Only %s was directly affected, ",
+		narrative.WriteString(v.phrase("impact.hosts.one",
 			br.AffectedHosts[0]))
 	} else {
-		narrative.WriteString(fmt.Sprintf("%d hosts were affected, ",
+		narrative.WriteString(v.phrase("impact.hosts.many",
 			len(br.AffectedHosts)))
 	}
 
-	narrative.WriteString(fmt.Sprintf("with %d resource types experiencing issues. ",
+	narrative.WriteString(v.phrase("impact.resources",
 		len(br.AffectedResources)))
 
 	// Cascade info
 	if br.CascadeDepth > 0 {
-		narrative.WriteString(fmt.Sprintf("\n\nThe cascade went %d levels deep: ",
+		narrative.WriteString(v.phrase("impact.cascade",
 			br.CascadeDepth))
 
 		// Describe the cascade chain
@@ -254,24 +293,23 @@ func (it *IncidentTeller) narrateImpact(intelligence IncidentIntelligence) strin
 		}
 
 		if len(directTypes) > 0 {
-			narrative.WriteString(fmt.Sprintf("%v failed first, ",
+			narrative.WriteString(v.phrase("impact.cascade.first",
 				it.resourceTypesList(directTypes)))
 		}
 
 		if len(indirectTypes) > 0 {
-			narrative.WriteString(fmt.Sprintf("then %v degraded as a result.",
+			narrative.WriteString(v.phrase("impact.cascade.then",
 				it.resourceTypesList(indirectTypes)))
 		}
 	}
 
 	// User impact
-	narrative.WriteString("\n\n")
 	if br.CriticalAlerts >= 3 {
-		narrative.WriteString("User-facing services were likely impacted during this time.")
+		narrative.WriteString(v.phrase("impact.users.likely"))
 	} else if br.CriticalAlerts >= 1 {
-		narrative.WriteString("Some user impact is possible, though services remained partially available.")
+		narrative.WriteString(v.phrase("impact.users.possible"))
 	} else {
-		narrative.WriteString("User impact was minimal - we caught this before it became user-facing.")
+		narrative.WriteString(v.phrase("impact.users.minimal"))
 	}
 
 	return narrative.String()
@@ -308,21 +346,23 @@ func (it *IncidentTeller) narrateFixes(intelligence IncidentIntelligence) Incide
 		ImmediateActions: immediate,
 		ShortTermActions: shortTerm,
 		LongTermActions:  longTerm,
+		RunbookURL:       fixes.RunbookURL,
 	}
 }
 
 // generateSummary creates a one-line incident summary
 func (it *IncidentTeller) generateSummary(
+	v StoryVoice,
 	alerts []domain.Alert,
 	intelligence IncidentIntelligence,
 ) string {
 	duration := intelligence.IncidentDuration
 
-	return fmt.Sprintf("%s on %s caused %s incident lasting %s",
+	return v.phrase("summary",
 		intelligence.RootCause.Alert.Name,
 		intelligence.RootCause.Alert.Host,
-		strings.ToLower(getSeverityLabel(intelligence.BlastRadius.ImpactScore)),
-		it.formatDuration(duration))
+		v.phrase("severity."+getSeverityLabel(intelligence.BlastRadius.ImpactScore)),
+		it.formatDuration(v, duration))
 }
 
 // Helper methods
@@ -350,56 +390,58 @@ func (it *IncidentTeller) clusterEvents(alerts []domain.Alert) [][]domain.Alert
 	return clusters
 }
 
-func (it *IncidentTeller) describeAlert(alert *domain.Alert) string {
+// peakValue returns the value above which an alert of the resource type reads as a peak:
+// the risk policy's high value for the type, else 90
+func peakValue(rt domain.ResourceType) float64 {
+	if v := domain.CurrentRiskPolicy().HighValue(rt); v > 0 {
+		return v
+	}
+	return 90
+}
+
+func (it *IncidentTeller) describeAlert(v StoryVoice, alert *domain.Alert) string {
 	switch alert.ResourceType {
-	case domain.ResourceMemory:
-		return "memory pressure"
-	case domain.ResourceDisk:
-		return "disk space/I/O issues"
-	case domain.ResourceCPU:
-		return "CPU load"
-	case domain.ResourceNetwork:
-		return "network/latency problems"
-	case domain.ResourceProcess:
-		return "process failures"
+	case domain.ResourceMemory, domain.ResourceDisk, domain.ResourceCPU, domain.ResourceNetwork,
+		domain.ResourceProcess, domain.ResourceDatabase, domain.ResourceContainer, domain.ResourceApplication:
+		return v.phrase("resource." + string(alert.ResourceType))
 	default:
-		return strings.ToLower(string(alert.ResourceType)) + " issues"
+		return v.phrase("resource.other", strings.ToLower(string(alert.ResourceType)))
 	}
 }
 
-func (it *IncidentTeller) listOtherAlerts(alerts []domain.Alert) string {
+func (it *IncidentTeller) listOtherAlerts(v StoryVoice, alerts []domain.Alert) string {
 	if len(alerts) == 0 {
 		return ""
 	}
 
 	if len(alerts) == 1 {
-		return it.describeAlert(&alerts[0])
+		return it.describeAlert(v, &alerts[0])
 	}
 
 	parts := make([]string, len(alerts))
 	for i, alert := range alerts {
-		parts[i] = it.describeAlert(&alert)
+		parts[i] = it.describeAlert(v, &alert)
 	}
 
-	return strings.Join(parts, " and ")
+	return strings.Join(parts, v.phrase("list.and"))
 }
 
-func (it *IncidentTeller) formatDuration(d time.Duration) string {
+func (it *IncidentTeller) formatDuration(v StoryVoice, d time.Duration) string {
 	if d < time.Minute {
-		return fmt.Sprintf("%d seconds", int(d.Seconds()))
+		return v.phrase("duration.seconds", int(d.Seconds()))
 	} else if d < time.Hour {
 		mins := int(d.Minutes())
 		if mins == 1 {
-			return "about a minute"
+			return v.phrase("duration.minute")
 		}
-		return fmt.Sprintf("%d minutes", mins)
+		return v.phrase("duration.minutes", mins)
 	} else {
 		hours := int(d.Hours())
 		mins := int(d.Minutes()) - (hours * 60)
 		if mins == 0 {
-			return fmt.Sprintf("%d hours", hours)
+			return v.phrase("duration.hours", hours)
 		}
-		return fmt.Sprintf("%d hours and %d minutes", hours, mins)
+		return v.phrase("duration.hours_minutes", hours, mins)
 	}
 }
 
@@ -413,52 +455,5 @@ func (it *IncidentTeller) resourceTypesList(types map[domain.ResourceType]bool)
 
 // FormatIncidentStory creates a formatted output for the incident story
 func FormatIncidentStory(story IncidentStory) string {
-	var output strings.Builder
-
-	output.WriteString("╔════════════════════════════════════════════════════════════════╗\n")
-	output.WriteString("║                    INCIDENT STORY                              ║\n")
-	output.WriteString("╚════════════════════════════════════════════════════════════════╝\n\n")
-
-	output.WriteString("📝 SUMMARY\n")
-	output.WriteString("────────────────────────────────────────────────────────────────\n")
-	output.WriteString(story.Summary)
-	output.WriteString("\n\n")
-
-	output.WriteString("⏱️  TIMELINE\n")
-	output.WriteString("────────────────────────────────────────────────────────────────\n")
-	output.WriteString(story.Timeline)
-	output.WriteString("\n\n")
-
-	output.WriteString("🎯 ROOT CAUSE\n")
-	output.WriteString("────────────────────────────────────────────────────────────────\n")
-	output.WriteString(story.RootCause)
-	output.WriteString("\n\n")
-
-	output.WriteString("💥 IMPACT\n")
-	output.WriteString("────────────────────────────────────────────────────────────────\n")
-	output.WriteString(story.Impact)
-	output.WriteString("\n\n")
-
-	output.WriteString("🔧 FIX\n")
-	output.WriteString("────────────────────────────────────────────────────────────────\n\n")
-
-	output.WriteString("IMMEDIATE (do this now):\n")
-	for i, action := range story.Fix.ImmediateActions {
-		output.WriteString(fmt.Sprintf("  %d. %s\n", i+1, action))
-	}
-
-	output.WriteString("\nSHORT-TERM (today):\n")
-	for i, action := range story.Fix.ShortTermActions {
-		output.WriteString(fmt.Sprintf("  %d. %s\n", i+1, action))
-	}
-
-	output.WriteString("\nLONG-TERM (prevention):\n")
-	for i, action := range story.Fix.LongTermActions {
-		output.WriteString(fmt.Sprintf("  %d. %s\n", i+1, action))
-	}
-
-	output.WriteString("\n────────────────────────────────────────────────────────────────\n")
-	output.WriteString(fmt.Sprintf("Generated: %s\n", story.GeneratedAt.Format("2006-01-02 15:04:05 MST")))
-
-	return output.String()
+	return report.Text{}.Render(StoryDocument(story))
 }
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
+	"incident-teller/internal/notify"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/ports"
+)
+
+// InternalSourceName is the alert source of alerts raised by IncidentTeller itself
+const InternalSourceName = "incident-teller"
+
+// IngestionStatus is the heartbeat of alert ingestion
+type IngestionStatus struct {
+	LastSuccess *time.Time    // Latest successful poll of any source, nil if none yet
+	Silence     time.Duration // Time since the latest successful poll, or since watching started
+	Stalled     bool
+	StalledAt   *time.Time // When the watchdog raised the stall, while stalled
+}
+
+// IngestionWatchdog is a dead man's switch for the alert sources: when no source has
+// polled successfully for the threshold, e.g. because the alert source stopped answering
+// or a poller died, it raises an internal "ingestion stalled" incident and notification,
+// and resolves them once polls succeed again.
+type IngestionWatchdog struct {
+	sources    *SourceManager
+	threshold  time.Duration
+	store      ports.IncidentWriter
+	dispatcher *notify.Dispatcher
+	metrics    observability.Metrics
+	host       string
+
+	mu       sync.Mutex
+	watching time.Time        // Start of the current Run; silence before the first poll counts from here
+	incident *domain.Incident // Open "ingestion stalled" incident
+}
+
+// NewIngestionWatchdog creates a watchdog raising a stall after threshold without a
+// successful poll of any of the sources
+func NewIngestionWatchdog(sources *SourceManager, threshold time.Duration) *IngestionWatchdog {
+	host, _ := os.Hostname()
+	return &IngestionWatchdog{sources: sources, threshold: threshold, host: host}
+}
+
+// SetIncidentStore records stalls as incidents
+func (w *IngestionWatchdog) SetIncidentStore(store ports.IncidentWriter) {
+	w.store = store
+}
+
+// SetDispatcher sends stall and recovery notifications
+func (w *IngestionWatchdog) SetDispatcher(dispatcher *notify.Dispatcher) {
+	w.dispatcher = dispatcher
+}
+
+// SetMetrics records the time of the latest successful poll and whether ingestion is
+// stalled as gauges
+func (w *IngestionWatchdog) SetMetrics(metrics observability.Metrics) {
+	w.metrics = metrics
+}
+
+// Threshold returns the silence after which ingestion is stalled
+func (w *IngestionWatchdog) Threshold() time.Duration {
+	return w.threshold
+}
+
+// Status returns the ingestion heartbeat at time now
+func (w *IngestionWatchdog) Status(now time.Time) IngestionStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status(now)
+}
+
+func (w *IngestionWatchdog) status(now time.Time) IngestionStatus {
+	var status IngestionStatus
+	for _, source := range w.sources.Statuses() {
+		if source.LastSuccess != nil && (status.LastSuccess == nil || source.LastSuccess.After(*status.LastSuccess)) {
+			status.LastSuccess = source.LastSuccess
+		}
+	}
+	switch {
+	case status.LastSuccess != nil && status.LastSuccess.After(w.watching):
+		status.Silence = now.Sub(*status.LastSuccess)
+	case !w.watching.IsZero():
+		status.Silence = now.Sub(w.watching)
+	}
+	if w.incident != nil {
+		status.Stalled = true
+		status.StalledAt = &w.incident.StartedAt
+	}
+	return status
+}
+
+// Check raises the stall once the silence exceeds the threshold and resolves it after the
+// next successful poll
+func (w *IngestionWatchdog) Check(ctx context.Context, now time.Time) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	status := w.status(now)
+	if w.metrics != nil {
+		if status.LastSuccess != nil {
+			w.metrics.SetGauge("ingestion_last_success_timestamp", float64(status.LastSuccess.Unix()), nil)
+		}
+		stalled := 0.0
+		if status.Silence >= w.threshold {
+			stalled = 1
+		}
+		w.metrics.SetGauge("ingestion_stalled", stalled, nil)
+	}
+
+	switch {
+	case w.incident == nil && status.Silence >= w.threshold:
+		return w.raise(ctx, status, now)
+	case w.incident != nil && status.Silence < w.threshold:
+		return w.resolve(ctx, now)
+	}
+	return nil
+}
+
+// raise opens the "ingestion stalled" incident
+func (w *IngestionWatchdog) raise(ctx context.Context, status IngestionStatus, now time.Time) error {
+	since := "watching started"
+	if status.LastSuccess != nil && status.LastSuccess.After(w.watching) {
+		since = "the last successful poll at " + status.LastSuccess.Format(time.RFC3339)
+	}
+	alert := w.alert(domain.StatusCritical, domain.StatusClear, now)
+	incident := domain.Incident{
+		ID:        idgen.Derive(alert.OccurredAt, alert.ID),
+		Title:     "Ingestion stalled: no alerts received",
+		Status:    domain.StatusCritical,
+		StartedAt: now,
+		Events:    []domain.Alert{alert},
+	}
+	w.incident = &incident
+	log.Printf("🚨 Ingestion stalled: no alert source polled successfully for %s", status.Silence.Round(time.Second))
+
+	text := fmt.Sprintf("No alert source has been polled successfully for %s, since %s. New alerts are not being recorded.\n\n%s",
+		status.Silence.Round(time.Second), since, w.sourceSummary())
+	return w.publish(ctx, incident, "critical", text)
+}
+
+// resolve closes the "ingestion stalled" incident
+func (w *IngestionWatchdog) resolve(ctx context.Context, now time.Time) error {
+	incident := *w.incident
+	incident.Events = append(append([]domain.Alert(nil), incident.Events...), w.alert(domain.StatusClear, domain.StatusCritical, now))
+	incident.Status = domain.StatusClear
+	incident.ResolvedAt = &now
+	w.incident = nil
+	log.Printf("✅ Ingestion recovered after %s", now.Sub(incident.StartedAt).Round(time.Second))
+
+	text := fmt.Sprintf("Alert sources are polled successfully again; ingestion was stalled for %s.",
+		now.Sub(incident.StartedAt).Round(time.Second))
+	return w.publish(ctx, incident, "info", text)
+}
+
+// alert builds an internal alert of the ingestion stall
+func (w *IngestionWatchdog) alert(status, oldStatus domain.AlertStatus, at time.Time) domain.Alert {
+	alert := domain.Alert{
+		Host:         w.host,
+		Chart:        "incident_teller.ingestion",
+		Family:       "ingestion",
+		Name:         "ingestion_stalled",
+		Status:       status,
+		OldStatus:    oldStatus,
+		OccurredAt:   at,
+		Description:  fmt.Sprintf("No alert source polled successfully for %s", w.threshold),
+		ResourceType: domain.ResourceProcess,
+		Source:       InternalSourceName,
+	}
+	alert.ID = idgen.Derive(at, InternalSourceName+":ingestion_stalled:"+string(status))
+	return alert
+}
+
+// publish stores the incident with its latest alert and notifies about it. Both are
+// attempted; the errors are combined.
+func (w *IngestionWatchdog) publish(ctx context.Context, incident domain.Incident, severity, text string) error {
+	var errs []error
+	if w.store != nil {
+		if err := w.store.SaveAlert(ctx, incident.Events[len(incident.Events)-1]); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save ingestion alert: %w", err))
+		} else if err := w.store.SaveIncident(ctx, incident); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save ingestion incident: %w", err))
+		}
+	}
+	if w.dispatcher != nil {
+		notification := notify.Notification{
+			IncidentID: incident.ID,
+			Title:      incident.Title,
+			Severity:   severity,
+			Priority:   string(domain.PriorityP1),
+			Text:       text,
+			Labels:     map[string]string{"host": w.host, "source": InternalSourceName},
+		}
+		if incident.ResolvedAt != nil {
+			notification.Title = "Ingestion recovered"
+		}
+		if err := w.dispatcher.Send(ctx, notification); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send ingestion notification: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sourceSummary lists the last successful poll and error of every source
+func (w *IngestionWatchdog) sourceSummary() string {
+	var lines []string
+	for _, source := range w.sources.Statuses() {
+		line := fmt.Sprintf("• *%s*: never polled successfully", source.Name)
+		if source.LastSuccess != nil {
+			line = fmt.Sprintf("• *%s*: last success %s", source.Name, source.LastSuccess.Format(time.RFC3339))
+		}
+		if source.LastError != "" {
+			line += " (" + source.LastError + ")"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HealthCheck reports ingestion unhealthy while it is stalled
+func (w *IngestionWatchdog) HealthCheck() observability.HealthCheck {
+	return func(ctx context.Context) observability.HealthCheckResult {
+		status := w.Status(time.Now())
+		details := map[string]interface{}{
+			"silence_seconds": status.Silence.Seconds(),
+			"stall_threshold": w.threshold.String(),
+		}
+		if status.LastSuccess != nil {
+			details["last_success"] = status.LastSuccess
+		}
+		if status.Stalled {
+			return observability.HealthCheckResult{
+				Status:  "unhealthy",
+				Message: fmt.Sprintf("Ingestion stalled since %s", status.StalledAt.Format(time.RFC3339)),
+				Details: details,
+			}
+		}
+		return observability.HealthCheckResult{Status: "healthy", Message: "Ingestion OK", Details: details}
+	}
+}
+
+// Run checks the heartbeat until ctx is cancelled. Silence counts from the start of Run,
+// so a replica only watches while it polls.
+func (w *IngestionWatchdog) Run(ctx context.Context) {
+	w.mu.Lock()
+	w.watching = time.Now()
+	w.mu.Unlock()
+
+	interval := min(w.threshold/4, time.Minute)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Check(ctx, time.Now()); err != nil {
+				log.Printf("⚠️  Ingestion watchdog error: %v", err)
+			}
+		}
+	}
+}
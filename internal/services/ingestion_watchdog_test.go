@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"incident-teller/internal/adapters/repository"
+	"incident-teller/internal/notify"
+)
+
+func TestIngestionWatchdog_RaisesAndResolvesStall(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryRepository()
+	manager := NewSourceManager(repo, NewIncidentAnalyzer())
+	source := &fakeSource{err: errors.New("connection refused")}
+	poller := manager.Add("netdata", source, time.Minute)
+
+	notifier := &recordingNotifier{}
+	watchdog := NewIngestionWatchdog(manager, 10*time.Minute)
+	watchdog.SetIncidentStore(repo)
+	watchdog.SetDispatcher(notify.NewDispatcher(notifier))
+	start := time.Now()
+	watchdog.watching = start
+
+	if _, err := poller.PollOnce(ctx); err == nil {
+		t.Fatal("expected the poll to fail")
+	}
+	if err := watchdog.Check(ctx, start.Add(5*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if status := watchdog.Status(start.Add(5 * time.Minute)); status.Stalled || status.Silence != 5*time.Minute {
+		t.Fatalf("expected no stall within the threshold, got %+v", status)
+	}
+
+	if err := watchdog.Check(ctx, start.Add(11*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if status := watchdog.Status(start.Add(11 * time.Minute)); !status.Stalled || status.LastSuccess != nil {
+		t.Fatalf("expected a stall without any successful poll, got %+v", status)
+	}
+	incidents, _ := repo.GetIncidents(ctx)
+	if len(incidents) != 1 || incidents[0].ResolvedAt != nil || incidents[0].Events[0].Source != InternalSourceName {
+		t.Fatalf("expected an open ingestion incident, got %+v", incidents)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0].Severity != "critical" {
+		t.Fatalf("expected a critical notification, got %+v", notifier.sent)
+	}
+
+	// A stall is raised once
+	if err := watchdog.Check(ctx, start.Add(12*time.Minute)); err != nil || len(notifier.sent) != 1 {
+		t.Fatalf("expected no second notification, got %d (err %v)", len(notifier.sent), err)
+	}
+
+	source.err = nil
+	if _, err := poller.PollOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := watchdog.Check(ctx, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if status := watchdog.Status(time.Now()); status.Stalled || status.LastSuccess == nil {
+		t.Fatalf("expected ingestion to recover, got %+v", status)
+	}
+	incidents, _ = repo.GetIncidents(ctx)
+	if len(incidents) != 1 || incidents[0].ResolvedAt == nil || len(incidents[0].Events) != 2 {
+		t.Fatalf("expected the ingestion incident to be resolved, got %+v", incidents)
+	}
+	if len(notifier.sent) != 2 || notifier.sent[1].Title != "Ingestion recovered" {
+		t.Errorf("expected a recovery notification, got %+v", notifier.sent)
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/ports"
+)
+
+// IngestionLease is the lease held by the replica that polls the alert sources
+const IngestionLease = "ingestion"
+
+// LeaderElector elects one of the replicas sharing a database through a lease, which
+// the leader renews every third of its TTL. When the leader stops renewing, another
+// replica takes over once the lease has expired.
+type LeaderElector struct {
+	store  ports.LeaseStore
+	name   string
+	holder string
+	ttl    time.Duration
+
+	mu     sync.RWMutex
+	lease  domain.Lease // Last seen, also when held by another replica
+	leader bool
+}
+
+// LeaderStatus describes the current leader as seen by this replica
+type LeaderStatus struct {
+	Lease     string     `json:"lease"`
+	Replica   string     `json:"replica"`          // This replica
+	Leader    string     `json:"leader,omitempty"` // Replica holding the lease
+	IsLeader  bool       `json:"is_leader"`
+	Since     *time.Time `json:"since,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// NewLeaderElector creates an elector campaigning for the named lease as holder
+func NewLeaderElector(store ports.LeaseStore, name, holder string, ttl time.Duration) *LeaderElector {
+	return &LeaderElector{store: store, name: name, holder: holder, ttl: ttl}
+}
+
+// Run campaigns for the lease until ctx is cancelled. Each time this replica becomes the
+// leader, lead runs with a context that is cancelled when the lease is lost; the lease
+// is only campaigned for again after lead has returned. The lease is released on return.
+func (e *LeaderElector) Run(ctx context.Context, lead func(ctx context.Context)) {
+	interval := e.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var stop func() // Cancels lead and waits for it to return; nil while not leading
+	stepDown := func() {
+		if stop != nil {
+			stop()
+			stop = nil
+		}
+		e.mu.Lock()
+		e.leader = false
+		e.mu.Unlock()
+	}
+
+	for {
+		lease, held, err := e.store.AcquireLease(ctx, e.name, e.holder, e.ttl)
+		switch {
+		case ctx.Err() != nil:
+		case err != nil:
+			log.Printf("⚠️  Failed to renew the %s lease: %v", e.name, err)
+			// Stop leading before the lease may expire and another replica takes over
+			e.mu.RLock()
+			expiring := e.leader && !time.Now().Add(interval).Before(e.lease.ExpiresAt)
+			e.mu.RUnlock()
+			if expiring {
+				log.Printf("⏸️  %s lost the %s lease", e.holder, e.name)
+				stepDown()
+			}
+		default:
+			e.mu.Lock()
+			e.lease = lease
+			wasLeader := e.leader
+			e.leader = held
+			e.mu.Unlock()
+
+			switch {
+			case held && !wasLeader:
+				log.Printf("👑 %s is now the %s leader", e.holder, e.name)
+				leadCtx, cancel := context.WithCancel(ctx)
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					lead(leadCtx)
+				}()
+				stop = func() {
+					cancel()
+					<-done
+				}
+			case !held && wasLeader:
+				log.Printf("⏸️  %s lost the %s lease to %s", e.holder, e.name, lease.Holder)
+				stepDown()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			stepDown()
+			releaseCtx, cancelRelease := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := e.store.ReleaseLease(releaseCtx, e.name, e.holder); err != nil {
+				log.Printf("⚠️  Failed to release the %s lease: %v", e.name, err)
+			}
+			cancelRelease()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// IsLeader reports whether this replica currently holds the lease
+func (e *LeaderElector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader
+}
+
+// Status returns the current leader as last seen by this replica
+func (e *LeaderElector) Status() LeaderStatus {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	status := LeaderStatus{Lease: e.name, Replica: e.holder, IsLeader: e.leader}
+	if e.lease.Holder != "" && e.lease.ExpiresAt.After(time.Now()) {
+		since, expiresAt := e.lease.AcquiredAt, e.lease.ExpiresAt
+		status.Leader = e.lease.Holder
+		status.Since = &since
+		status.ExpiresAt = &expiresAt
+	}
+	return status
+}
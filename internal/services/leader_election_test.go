@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"incident-teller/internal/adapters/repository"
+)
+
+func TestLeaderElector_Failover(t *testing.T) {
+	repo := repository.NewInMemoryRepository()
+	ttl := 60 * time.Millisecond
+
+	var leading [2]atomic.Int32
+	campaign := func(ctx context.Context, e *LeaderElector, i int) chan struct{} {
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			e.Run(ctx, func(ctx context.Context) {
+				leading[i].Add(1)
+				<-ctx.Done()
+				leading[i].Add(-1)
+			})
+		}()
+		return stopped
+	}
+
+	first := NewLeaderElector(repo, IngestionLease, "replica-a", ttl)
+	second := NewLeaderElector(repo, IngestionLease, "replica-b", ttl)
+	ctxA, stopA := context.WithCancel(context.Background())
+	stoppedA := campaign(ctxA, first, 0)
+	time.Sleep(10 * time.Millisecond)
+	ctxB, stopB := context.WithCancel(context.Background())
+	defer stopB()
+	campaign(ctxB, second, 1)
+
+	time.Sleep(3 * ttl)
+	if !first.IsLeader() || second.IsLeader() || leading[0].Load() != 1 || leading[1].Load() != 0 {
+		t.Fatalf("expected only replica-a to lead, got a=%v b=%v", first.Status(), second.Status())
+	}
+	if status := second.Status(); status.Leader != "replica-a" || status.Replica != "replica-b" || status.ExpiresAt == nil {
+		t.Errorf("expected replica-b to see replica-a as leader, got %+v", status)
+	}
+
+	// Stopping the leader releases the lease and the other replica takes over
+	stopA()
+	<-stoppedA
+	if leading[0].Load() != 0 {
+		t.Error("expected replica-a to stop leading")
+	}
+	time.Sleep(ttl)
+	if !second.IsLeader() || leading[1].Load() != 1 {
+		t.Errorf("expected replica-b to take over, got %+v", second.Status())
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"incident-teller/internal/ai"
+	"incident-teller/internal/domain"
+	"incident-teller/internal/ports"
+)
+
+// ErrNoRootCausePredictions is returned for feedback on an incident without stored predictions
+var ErrNoRootCausePredictions = errors.New("no root cause predictions stored for this incident")
+
+// RootCauseFeedback tells whether an incident's root cause was predicted correctly: either
+// the actual root cause alert, or whether the active model version was right
+type RootCauseFeedback struct {
+	RootCauseAlertID string
+	Correct          *bool
+}
+
+// ModelReport is how well a model version predicted root causes that got feedback
+type ModelReport struct {
+	Version     string
+	Active      bool
+	Weights     ai.ModelWeights
+	Predictions int
+	Feedback    int
+	Correct     int
+	Overrides   int // Predictions of incidents whose root cause a responder pinned to another alert
+	Accuracy    float64
+	Calibration *ai.Calibration
+}
+
+// ModelEvaluator stores the root cause predicted by each model version for incidents,
+// scores them against operator feedback and calibrates the models' confidences from the
+// outcomes. The active model answers requests; the other versions run in the shadow so
+// that they can be compared before switching.
+type ModelEvaluator struct {
+	store      ports.RootCauseStore
+	active     *ai.LocalAIModel
+	models     []*ai.LocalAIModel
+	bins       int
+	minSamples int
+}
+
+// NewModelEvaluator creates an evaluator of models, one per version, of which active
+// answers requests. Calibration curves have bins bins and calibrate bins of at least
+// minSamples predictions.
+func NewModelEvaluator(store ports.RootCauseStore, active *ai.LocalAIModel, models []*ai.LocalAIModel, bins, minSamples int) *ModelEvaluator {
+	return &ModelEvaluator{
+		store:      store,
+		active:     active,
+		models:     models,
+		bins:       bins,
+		minSamples: minSamples,
+	}
+}
+
+// ActiveVersion returns the version of the model that answers requests
+func (e *ModelEvaluator) ActiveVersion() string {
+	return e.active.Version()
+}
+
+// Record predicts the root cause of an incident with every model version and stores the
+// predictions
+func (e *ModelEvaluator) Record(ctx context.Context, incident domain.Incident) error {
+	now := time.Now()
+	for _, model := range e.models {
+		prediction, err := model.PredictRootCause(ctx, incident.Events)
+		if err != nil || prediction.PrimaryCause == nil {
+			continue
+		}
+		err = e.store.SaveRootCause(ctx, domain.RootCauseRecord{
+			IncidentID:   incident.ID,
+			ModelVersion: prediction.ModelVersion,
+			AlertID:      prediction.PrimaryCause.ID,
+			RawScore:     prediction.RawConfidence,
+			Confidence:   prediction.Confidence,
+			PredictedAt:  now,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save root cause of model %s: %w", prediction.ModelVersion, err)
+		}
+	}
+	return nil
+}
+
+// Predictions returns the stored root cause predictions of an incident
+func (e *ModelEvaluator) Predictions(ctx context.Context, incidentID string) ([]domain.RootCauseRecord, error) {
+	return e.store.GetRootCauses(ctx, incidentID)
+}
+
+// Feedback scores the stored predictions of an incident and recalibrates the models. With
+// the actual root cause every version is scored; otherwise the active version's prediction
+// is confirmed or rejected, along with versions that predicted the same alert.
+func (e *ModelEvaluator) Feedback(ctx context.Context, incidentID string, feedback RootCauseFeedback) ([]domain.RootCauseRecord, error) {
+	records, err := e.store.GetRootCauses(ctx, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrNoRootCausePredictions
+	}
+
+	actual := feedback.RootCauseAlertID
+	var rejected string
+	if actual == "" {
+		if feedback.Correct == nil {
+			return nil, fmt.Errorf("feedback needs the root cause alert or whether the prediction was correct")
+		}
+		var active *domain.RootCauseRecord
+		for i := range records {
+			if records[i].ModelVersion == e.ActiveVersion() {
+				active = &records[i]
+			}
+		}
+		if active == nil {
+			return nil, fmt.Errorf("model %s predicted no root cause for this incident", e.ActiveVersion())
+		}
+		if *feedback.Correct {
+			actual = active.AlertID
+		} else {
+			rejected = active.AlertID
+		}
+	}
+
+	now := time.Now()
+	for _, record := range records {
+		if actual == "" && record.AlertID != rejected {
+			continue // Can't tell whether another alert was the root cause
+		}
+		if err := e.store.SetRootCauseFeedback(ctx, incidentID, record.ModelVersion, record.AlertID == actual, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := e.Calibrate(ctx); err != nil {
+		return nil, err
+	}
+	return e.store.GetRootCauses(ctx, incidentID)
+}
+
+// Calibrate refits the calibration of every model version from the stored feedback
+func (e *ModelEvaluator) Calibrate(ctx context.Context) error {
+	records, err := e.store.GetRootCauses(ctx, "")
+	if err != nil {
+		return err
+	}
+	samples := calibrationSamples(records)
+	for _, model := range e.models {
+		model.SetCalibration(ai.FitCalibration(samples[model.Version()], e.bins, e.minSamples))
+	}
+	return nil
+}
+
+// Report returns the accuracy and calibration curve of every model version
+func (e *ModelEvaluator) Report(ctx context.Context) ([]ModelReport, error) {
+	records, err := e.store.GetRootCauses(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	predictions := map[string]int{}
+	for _, record := range records {
+		predictions[record.ModelVersion]++
+	}
+	samples := calibrationSamples(records)
+	overridden, err := e.overriddenPredictions(ctx, records)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]ModelReport, 0, len(e.models))
+	for _, model := range e.models {
+		version := model.Version()
+		report := ModelReport{
+			Version:     version,
+			Active:      model == e.active,
+			Weights:     model.Weights(),
+			Predictions: predictions[version],
+			Feedback:    len(samples[version]),
+			Overrides:   overridden[version],
+			Calibration: ai.FitCalibration(samples[version], e.bins, e.minSamples),
+		}
+		for _, sample := range samples[version] {
+			if sample.Correct {
+				report.Correct++
+			}
+		}
+		if report.Feedback > 0 {
+			report.Accuracy = float64(report.Correct) / float64(report.Feedback)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// overriddenPredictions counts, per model version, the predictions of incidents whose root
+// cause a responder pinned to another alert. The store may not keep overrides.
+func (e *ModelEvaluator) overriddenPredictions(ctx context.Context, records []domain.RootCauseRecord) (map[string]int, error) {
+	counts := map[string]int{}
+	store, ok := e.store.(ports.RootCauseOverrideStore)
+	if !ok {
+		return counts, nil
+	}
+	overrides, err := store.GetRootCauseOverrides(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	pinned := make(map[string]string, len(overrides))
+	for _, override := range overrides {
+		pinned[override.IncidentID] = override.AlertID
+	}
+	for _, record := range records {
+		if alertID, ok := pinned[record.IncidentID]; ok && alertID != record.AlertID {
+			counts[record.ModelVersion]++
+		}
+	}
+	return counts, nil
+}
+
+// calibrationSamples groups the predictions that got feedback by model version
+func calibrationSamples(records []domain.RootCauseRecord) map[string][]ai.CalibrationSample {
+	samples := map[string][]ai.CalibrationSample{}
+	for _, record := range records {
+		if record.Correct != nil {
+			samples[record.ModelVersion] = append(samples[record.ModelVersion],
+				ai.CalibrationSample{Score: record.RawScore, Correct: *record.Correct})
+		}
+	}
+	return samples
+}
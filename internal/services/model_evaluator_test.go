@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"incident-teller/internal/adapters/repository"
+	"incident-teller/internal/ai"
+	"incident-teller/internal/domain"
+)
+
+func TestModelEvaluator_FeedbackCalibratesVersions(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryRepository()
+
+	active := ai.NewLocalAIModel()
+	shadow := ai.NewLocalAIModel()
+	shadow.SetWeights(ai.ModelWeights{
+		Version:        "2.0.0",
+		ResourceScores: map[domain.ResourceType]float64{domain.ResourceDisk: 0.5},
+		CriticalScore:  0.2,
+	})
+	evaluator := NewModelEvaluator(repo, active, []*ai.LocalAIModel{active, shadow}, 10, 2)
+
+	// The active version blames memory, the shadow version the disk, which is right
+	start := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		incident := domain.Incident{ID: fmt.Sprintf("inc-%d", i), Events: []domain.Alert{
+			{ID: fmt.Sprintf("mem-%d", i), ResourceType: domain.ResourceMemory, Status: domain.StatusCritical, OccurredAt: start},
+			{ID: fmt.Sprintf("disk-%d", i), ResourceType: domain.ResourceDisk, Status: domain.StatusCritical, OccurredAt: start},
+		}}
+		if err := repo.SaveIncident(ctx, incident); err != nil {
+			t.Fatal(err)
+		}
+		if err := evaluator.Record(ctx, incident); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wrong := false
+	records, err := evaluator.Feedback(ctx, "inc-0", RootCauseFeedback{Correct: &wrong})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		if record.ModelVersion == "1.0.0" && (record.Correct == nil || *record.Correct) {
+			t.Errorf("expected the active prediction to be marked wrong, got %+v", record)
+		}
+		if record.ModelVersion == "2.0.0" && record.Correct != nil {
+			t.Errorf("expected the shadow prediction to stay unscored, got %+v", record)
+		}
+	}
+	for _, id := range []string{"inc-1", "inc-2"} {
+		if _, err := evaluator.Feedback(ctx, id, RootCauseFeedback{RootCauseAlertID: "disk-" + id[4:]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := evaluator.Feedback(ctx, "unknown", RootCauseFeedback{RootCauseAlertID: "x"}); err != ErrNoRootCausePredictions {
+		t.Errorf("expected ErrNoRootCausePredictions, got %v", err)
+	}
+
+	reports, err := evaluator.Report(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 || !reports[0].Active || reports[0].Feedback != 3 || reports[0].Accuracy != 0 ||
+		reports[1].Feedback != 2 || reports[1].Accuracy != 1 {
+		t.Fatalf("unexpected reports: %+v", reports)
+	}
+
+	// The active version's 0.5 scores were always wrong, so it is no longer confident
+	prediction, err := active.PredictRootCause(ctx, []domain.Alert{
+		{ID: "mem", ResourceType: domain.ResourceMemory, Status: domain.StatusCritical},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prediction.ModelVersion != "1.0.0" || prediction.RawConfidence != 0.5 || prediction.Confidence != 0 {
+		t.Errorf("expected a calibrated confidence of 0, got %+v", prediction)
+	}
+}
+
+func TestModelEvaluator_ReportCountsOverrides(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryRepository()
+	model := ai.NewLocalAIModel()
+	evaluator := NewModelEvaluator(repo, model, []*ai.LocalAIModel{model}, 10, 2)
+
+	start := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		incident := domain.Incident{ID: fmt.Sprintf("inc-%d", i), Events: []domain.Alert{
+			{ID: fmt.Sprintf("mem-%d", i), ResourceType: domain.ResourceMemory, Status: domain.StatusCritical, OccurredAt: start},
+			{ID: fmt.Sprintf("net-%d", i), ResourceType: domain.ResourceNetwork, Status: domain.StatusWarning, OccurredAt: start},
+		}}
+		if err := repo.SaveIncident(ctx, incident); err != nil {
+			t.Fatal(err)
+		}
+		if err := evaluator.Record(ctx, incident); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// One responder pins the predicted alert, another a different one
+	for id, alertID := range map[string]string{"inc-0": "mem-0", "inc-1": "net-1"} {
+		err := repo.SetRootCauseOverride(ctx, domain.RootCauseOverride{IncidentID: id, AlertID: alertID, SetBy: "alice", SetAt: start})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reports, err := evaluator.Report(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].Predictions != 2 || reports[0].Overrides != 1 {
+		t.Fatalf("unexpected reports: %+v", reports)
+	}
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+// AlertNoiseClass classifies an alert by how much signal it carried
+type AlertNoiseClass string
+
+const (
+	NoiseInformative AlertNoiseClass = "informative"
+	NoiseDuplicate   AlertNoiseClass = "duplicate" // Same state repeated within the dedup window
+	NoiseFlapping    AlertNoiseClass = "flapping"  // Oscillation back into a state the alert already held
+	NoiseChurn       AlertNoiseClass = "churn"     // CLEAR immediately followed by a re-trigger
+)
+
+// IncidentNoiseReport summarizes redundant versus informative alerts for one incident
+type IncidentNoiseReport struct {
+	IncidentID  string
+	Title       string
+	TotalAlerts int
+	Informative int
+	Duplicate   int
+	Flapping    int
+	Churn       int
+	Efficiency  float64 // Informative / Total (0-1)
+}
+
+// SourceNoiseReport aggregates noise per alert source (alert rule) across incidents
+type SourceNoiseReport struct {
+	Source      string
+	Incidents   int
+	TotalAlerts int
+	Informative int
+	Duplicate   int
+	Flapping    int
+	Churn       int
+	Efficiency  float64
+}
+
+// NoiseReport is the full alerting-noise cost report
+type NoiseReport struct {
+	Incidents         []IncidentNoiseReport
+	Sources           []SourceNoiseReport // Noisiest sources first
+	TotalAlerts       int
+	RedundantAlerts   int
+	OverallEfficiency float64
+}
+
+// NoiseGenerator is an alert stream (an alert rule on a host) ranked by its noise
+type NoiseGenerator struct {
+	Host           string
+	Chart          string
+	Name           string
+	Alerts         int
+	NoiseAlerts    int // Alerts that were duplicates, flaps, churn or sent while flapping
+	FlappingAlerts int // Alerts sent while the stream was flapping
+	Transitions    int // Changes between CLEAR and WARNING/CRITICAL
+	NoiseRatio     float64
+}
+
+// NoiseAnalyzer measures the cost of alerting noise for resolved incidents
+type NoiseAnalyzer struct {
+	dedupWindow time.Duration
+	churnWindow time.Duration
+}
+
+// NewNoiseAnalyzer creates a noise analyzer using the given dedup window
+func NewNoiseAnalyzer(dedupWindow time.Duration) *NoiseAnalyzer {
+	if dedupWindow <= 0 {
+		dedupWindow = 5 * time.Minute
+	}
+	return &NoiseAnalyzer{
+		dedupWindow: dedupWindow,
+		churnWindow: 5 * time.Minute,
+	}
+}
+
+// ClassifyAlerts labels every alert of an incident with its noise class
+func (na *NoiseAnalyzer) ClassifyAlerts(alerts []domain.Alert) []AlertNoiseClass {
+	order := make([]int, len(alerts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return alerts[order[i]].OccurredAt.Before(alerts[order[j]].OccurredAt)
+	})
+
+	classes := make([]AlertNoiseClass, len(alerts))
+	lastIdx := make(map[string]int)
+	seen := make(map[string]map[domain.AlertStatus]bool)
+
+	for _, idx := range order {
+		alert := alerts[idx]
+		key := alertNoiseKey(alert)
+		classes[idx] = NoiseInformative
+
+		prevIdx, hasPrev := lastIdx[key]
+		if seen[key] == nil {
+			seen[key] = make(map[domain.AlertStatus]bool)
+		}
+
+		if hasPrev {
+			prev := alerts[prevIdx]
+			gap := alert.OccurredAt.Sub(prev.OccurredAt)
+
+			switch {
+			case alert.Status == prev.Status && gap <= na.dedupWindow:
+				classes[idx] = NoiseDuplicate
+			case prev.Status == domain.StatusClear && alert.Status != domain.StatusClear && gap <= na.churnWindow:
+				// The resolution did not stick: both sides of the bounce are noise
+				classes[idx] = NoiseChurn
+				classes[prevIdx] = NoiseChurn
+			case alert.Status != domain.StatusClear && seen[key][alert.Status]:
+				classes[idx] = NoiseFlapping
+			}
+		}
+
+		seen[key][alert.Status] = true
+		lastIdx[key] = idx
+	}
+
+	return classes
+}
+
+// AnalyzeIncident computes the noise report for a single incident
+func (na *NoiseAnalyzer) AnalyzeIncident(incident domain.Incident) IncidentNoiseReport {
+	report := IncidentNoiseReport{
+		IncidentID:  incident.ID,
+		Title:       incident.Title,
+		TotalAlerts: len(incident.Events),
+	}
+
+	for _, class := range na.ClassifyAlerts(incident.Events) {
+		switch class {
+		case NoiseDuplicate:
+			report.Duplicate++
+		case NoiseFlapping:
+			report.Flapping++
+		case NoiseChurn:
+			report.Churn++
+		default:
+			report.Informative++
+		}
+	}
+
+	report.Efficiency = efficiency(report.Informative, report.TotalAlerts)
+	return report
+}
+
+// BuildReport analyzes all resolved incidents and aggregates noise per source
+func (na *NoiseAnalyzer) BuildReport(incidents []domain.Incident) NoiseReport {
+	report := NoiseReport{
+		Incidents: []IncidentNoiseReport{},
+		Sources:   []SourceNoiseReport{},
+	}
+	sources := make(map[string]*SourceNoiseReport)
+	informative := 0
+
+	for _, incident := range incidents {
+		if incident.ResolvedAt == nil {
+			continue
+		}
+
+		incidentReport := na.AnalyzeIncident(incident)
+		report.Incidents = append(report.Incidents, incidentReport)
+		report.TotalAlerts += incidentReport.TotalAlerts
+		informative += incidentReport.Informative
+
+		counted := make(map[string]bool)
+		for i, class := range na.ClassifyAlerts(incident.Events) {
+			source := alertNoiseSource(incident.Events[i])
+			agg, ok := sources[source]
+			if !ok {
+				agg = &SourceNoiseReport{Source: source}
+				sources[source] = agg
+			}
+			if !counted[source] {
+				agg.Incidents++
+				counted[source] = true
+			}
+
+			agg.TotalAlerts++
+			switch class {
+			case NoiseDuplicate:
+				agg.Duplicate++
+			case NoiseFlapping:
+				agg.Flapping++
+			case NoiseChurn:
+				agg.Churn++
+			default:
+				agg.Informative++
+			}
+		}
+	}
+
+	for _, agg := range sources {
+		agg.Efficiency = efficiency(agg.Informative, agg.TotalAlerts)
+		report.Sources = append(report.Sources, *agg)
+	}
+	sort.Slice(report.Sources, func(i, j int) bool {
+		redundantI := report.Sources[i].TotalAlerts - report.Sources[i].Informative
+		redundantJ := report.Sources[j].TotalAlerts - report.Sources[j].Informative
+		if redundantI != redundantJ {
+			return redundantI > redundantJ
+		}
+		return report.Sources[i].Source < report.Sources[j].Source
+	})
+
+	report.RedundantAlerts = report.TotalAlerts - informative
+	report.OverallEfficiency = efficiency(informative, report.TotalAlerts)
+	return report
+}
+
+// RankGenerators ranks the alert streams of alerts by their noise, noisiest first.
+// flapping flags the alerts sent while their stream was flapping (see FlapDetector.Replay);
+// limit caps the result if positive.
+func (na *NoiseAnalyzer) RankGenerators(alerts []domain.Alert, flapping []bool, limit int) []NoiseGenerator {
+	streams := make(map[string][]int)
+	for i, alert := range alerts {
+		key := alertNoiseKey(alert)
+		streams[key] = append(streams[key], i)
+	}
+
+	generators := make([]NoiseGenerator, 0, len(streams))
+	for _, indexes := range streams {
+		first := alerts[indexes[0]]
+		generator := NoiseGenerator{Host: first.Host, Chart: first.Chart, Name: first.Name, Alerts: len(indexes)}
+
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return alerts[indexes[i]].OccurredAt.Before(alerts[indexes[j]].OccurredAt)
+		})
+		stream := make([]domain.Alert, len(indexes))
+		for i, idx := range indexes {
+			stream[i] = alerts[idx]
+		}
+		classes := na.ClassifyAlerts(stream)
+
+		for i, idx := range indexes {
+			if i > 0 && isActiveStatus(stream[i].Status) != isActiveStatus(stream[i-1].Status) {
+				generator.Transitions++
+			}
+			if flapping[idx] {
+				generator.FlappingAlerts++
+			}
+			if flapping[idx] || classes[i] != NoiseInformative {
+				generator.NoiseAlerts++
+			}
+		}
+		generator.NoiseRatio = float64(generator.NoiseAlerts) / float64(generator.Alerts)
+		generators = append(generators, generator)
+	}
+
+	sort.Slice(generators, func(i, j int) bool {
+		a, b := generators[i], generators[j]
+		if a.NoiseAlerts != b.NoiseAlerts {
+			return a.NoiseAlerts > b.NoiseAlerts
+		}
+		if a.Alerts != b.Alerts {
+			return a.Alerts > b.Alerts
+		}
+		return a.Host+a.Chart+a.Name < b.Host+b.Chart+b.Name
+	})
+	if limit > 0 && len(generators) > limit {
+		generators = generators[:limit]
+	}
+	return generators
+}
+
+// alertNoiseKey identifies a single alert stream (rule instance on a host)
+func alertNoiseKey(alert domain.Alert) string {
+	return alert.Host + "|" + alert.Chart + "|" + alert.Name
+}
+
+// alertNoiseSource identifies the alert rule responsible for an alert
+func alertNoiseSource(alert domain.Alert) string {
+	if alert.Chart != "" {
+		return alert.Name + " (" + alert.Chart + ")"
+	}
+	return alert.Name
+}
+
+func efficiency(informative, total int) float64 {
+	if total == 0 {
+		return 1.0
+	}
+	return float64(informative) / float64(total)
+}
@@ -2,21 +2,66 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
+	"unicode/utf8"
 
+	"incident-teller/internal/classify"
 	"incident-teller/internal/domain"
+	"incident-teller/internal/enrichment"
+	"incident-teller/internal/faults"
+	"incident-teller/internal/idgen"
+	"incident-teller/internal/observability"
 	"incident-teller/internal/ports"
+	"incident-teller/internal/severity"
 )
 
-// RealTimePoller continuously polls Netdata for new alerts
+// DefaultSourceName is the alert source whose cursor is the repository's last processed ID
+const DefaultSourceName = "netdata"
+
+// DefaultRawPayloadLimit is how many bytes of the payload its source sent an alert keeps,
+// unless SetRawPayloadLimit changes it
+const DefaultRawPayloadLimit = 16 << 10
+
+// RealTimePoller continuously polls an alert source for new alerts
 type RealTimePoller struct {
+	name         string
 	source       ports.AlertSource
 	repository   ports.Repository
 	analyzer     *IncidentAnalyzer
 	pollInterval time.Duration
 	eventChan    chan []domain.Alert
+	stream       ports.AlertStream
+	enrichment   *enrichment.Pipeline
+	classifier   *classify.Classifier
+	severity     *severity.Mapper
+	flaps        *FlapDetector
+	suppressor   *DependencySuppressor
+	anomalies    *AnomalyDetector
+	metrics      observability.Metrics
+	breaker      *CircuitBreaker
+	queue        *AlertQueue
+	faults       *faults.Injector
+	rawLimit     int
+
+	mu       sync.Mutex
+	status   SourceStatus
+	interval chan time.Duration // Poll interval changes for the running loop
+}
+
+// SourceStatus is the polling health of an alert source
+type SourceStatus struct {
+	Name                string     `json:"name"`
+	LastPoll            *time.Time `json:"last_poll,omitempty"`
+	LastSuccess         *time.Time `json:"last_success,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Alerts              int        `json:"alerts"`                 // Alerts received since start
+	Circuit             string     `json:"circuit,omitempty"`      // closed, open or half-open
+	PausedUntil         *time.Time `json:"paused_until,omitempty"` // Next probe of an open circuit
 }
 
 // NewRealTimePoller creates a new real-time alert poller
@@ -27,19 +72,136 @@ func NewRealTimePoller(
 	pollInterval time.Duration,
 ) *RealTimePoller {
 	return &RealTimePoller{
+		name:         DefaultSourceName,
 		source:       source,
 		repository:   repo,
 		analyzer:     analyzer,
 		pollInterval: pollInterval,
 		eventChan:    make(chan []domain.Alert, 100),
+		status:       SourceStatus{Name: DefaultSourceName},
+		interval:     make(chan time.Duration, 1),
+		rawLimit:     DefaultRawPayloadLimit,
+	}
+}
+
+// SetSourceName names the alert source. Alerts without a source are attributed to it, and
+// sources other than the default keep their own cursor when the repository supports it.
+func (p *RealTimePoller) SetSourceName(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.name = name
+	p.status.Name = name
+}
+
+// SetPollInterval changes the poll interval, also while polling; the next poll happens
+// one new interval from now
+func (p *RealTimePoller) SetPollInterval(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pollInterval = interval
+
+	// Replace a change the loop hasn't picked up yet
+	select {
+	case <-p.interval:
+	default:
 	}
+	p.interval <- interval
+}
+
+// PollInterval returns the current poll interval
+func (p *RealTimePoller) PollInterval() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pollInterval
+}
+
+// SetMetrics records poll results and batch sizes per source
+func (p *RealTimePoller) SetMetrics(metrics observability.Metrics) {
+	p.metrics = metrics
+}
+
+// Status returns the polling health of the source
+func (p *RealTimePoller) Status() SourceStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.status
+}
+
+// SetCircuitBreaker pauses polling while the breaker is open: after its maximum of
+// failed polls in a row, polls are skipped until its reset timeout has passed, then probe
+// polls decide whether polling resumes or pauses again. Only state changes are logged
+// while paused. Streams are not guarded.
+func (p *RealTimePoller) SetCircuitBreaker(breaker *CircuitBreaker) {
+	p.breaker = breaker
+	p.mu.Lock()
+	p.status.Circuit = string(breaker.GetState())
+	p.mu.Unlock()
+}
+
+// SetStream switches the poller to streaming mode, consuming alerts pushed by the stream
+func (p *RealTimePoller) SetStream(stream ports.AlertStream) {
+	p.stream = stream
+}
+
+// SetEnrichment adds labels to alerts before severity mapping and storage
+func (p *RealTimePoller) SetEnrichment(pipeline *enrichment.Pipeline) {
+	p.enrichment = pipeline
+}
+
+// SetClassifier sets the resource type of alerts before severity mapping and storage
+func (p *RealTimePoller) SetClassifier(classifier *classify.Classifier) {
+	p.classifier = classifier
+}
+
+// SetFaults delays saving alerts while repository latency is injected
+func (p *RealTimePoller) SetFaults(injector *faults.Injector) {
+	p.faults = injector
+}
+
+// SetRawPayloadLimit caps the source payload stored with each alert at limit bytes; longer
+// payloads are cut short, and 0 stores none
+func (p *RealTimePoller) SetRawPayloadLimit(limit int) {
+	p.rawLimit = limit
+}
+
+// SetSeverityMapper normalizes and remaps alert severities before they are stored
+func (p *RealTimePoller) SetSeverityMapper(mapper *severity.Mapper) {
+	p.severity = mapper
+}
+
+// SetFlapDetector labels alerts of flapping streams before they are stored
+func (p *RealTimePoller) SetFlapDetector(detector *FlapDetector) {
+	p.flaps = detector
+}
+
+// SetDependencySuppressor labels alerts of unreachable hosts before they are stored
+func (p *RealTimePoller) SetDependencySuppressor(suppressor *DependencySuppressor) {
+	p.suppressor = suppressor
+}
+
+// SetAnomalyDetector feeds every stored batch into the anomaly detector
+func (p *RealTimePoller) SetAnomalyDetector(detector *AnomalyDetector) {
+	p.anomalies = detector
+}
+
+// SetAlertQueue submits stored batches to the queue instead of the Events channel, so
+// that the queue's backpressure policy applies when consumers fall behind
+func (p *RealTimePoller) SetAlertQueue(queue *AlertQueue) {
+	p.queue = queue
 }
 
 // Start begins the polling loop
 func (p *RealTimePoller) Start(ctx context.Context) error {
-	log.Println("🚀 Starting real-time alert poller...")
+	if p.stream != nil {
+		return p.startStream(ctx)
+	}
 
-	ticker := time.NewTicker(p.pollInterval)
+	log.Printf("🚀 Starting real-time alert poller for %s...", p.name)
+
+	ticker := time.NewTicker(p.PollInterval())
 	defer ticker.Stop()
 
 	for {
@@ -47,19 +209,104 @@ func (p *RealTimePoller) Start(ctx context.Context) error {
 		case <-ctx.Done():
 			log.Println("⏹️  Poller stopped")
 			return ctx.Err()
+		case interval := <-p.interval:
+			ticker.Reset(interval)
+			log.Printf("🔄 %s poll interval changed to %s", p.name, interval)
 		case <-ticker.C:
-			if err := p.poll(ctx); err != nil {
-				log.Printf("⚠️  Poll error: %v", err)
-				// Continue polling even on error
-			}
+			p.guardedPoll(ctx)
+		}
+	}
+}
+
+// startStream consumes alert batches pushed by the configured stream
+func (p *RealTimePoller) startStream(ctx context.Context) error {
+	log.Printf("🚀 Starting real-time alert stream for %s...", p.name)
+
+	lastID, err := SourceCursor(ctx, p.repository, p.name)
+	if err != nil {
+		log.Printf("Failed to get last processed ID (using 0): %v", err)
+		lastID = 0
+	}
+
+	batches := make(chan []domain.Alert, 10)
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- p.stream.Stream(ctx, lastID, batches)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("⏹️  Stream stopped")
+			return ctx.Err()
+		case err := <-streamErr:
+			p.recordPoll(err)
+			return err
+		case alerts := <-batches:
+			p.recordPoll(nil)
+			p.process(ctx, alerts)
 		}
 	}
 }
 
+// guardedPoll polls through the circuit breaker, if any. Skipped polls are not recorded.
+func (p *RealTimePoller) guardedPoll(ctx context.Context) {
+	if p.breaker == nil {
+		err := p.poll(ctx)
+		p.recordPoll(err)
+		if err != nil {
+			log.Printf("⚠️  %s poll error: %v", p.name, err)
+			// Continue polling even on error
+		}
+		return
+	}
+
+	before := p.breaker.GetState()
+	err := p.breaker.Execute(func() error { return p.poll(ctx) })
+	if errors.Is(err, ErrCircuitOpen) {
+		return
+	}
+	p.recordPoll(err)
+
+	after := p.breaker.GetState()
+	if after != before || after == StateOpen {
+		p.recordCircuit(after)
+	}
+	switch {
+	case after == StateOpen && before != StateOpen:
+		log.Printf("⛔ %s paused after %d failed polls, probing again at %s: %v",
+			p.name, p.Status().ConsecutiveFailures, p.breaker.RetryAt().Format(time.RFC3339), err)
+	case after == StateOpen:
+		// Failed probe; the breaker opened again
+		log.Printf("⛔ %s probe failed, probing again at %s: %v", p.name, p.breaker.RetryAt().Format(time.RFC3339), err)
+	case after == StateClosed && before != StateClosed:
+		log.Printf("✅ %s polls resumed", p.name)
+	case err != nil:
+		log.Printf("⚠️  %s poll error: %v", p.name, err)
+	}
+}
+
+// recordCircuit updates the status and metrics after the circuit breaker changed state
+func (p *RealTimePoller) recordCircuit(state CircuitBreakerState) {
+	p.mu.Lock()
+	p.status.Circuit = string(state)
+	p.status.PausedUntil = nil
+	if retryAt := p.breaker.RetryAt(); !retryAt.IsZero() {
+		p.status.PausedUntil = &retryAt
+	}
+	name := p.name
+	p.mu.Unlock()
+
+	if p.metrics != nil {
+		value := map[CircuitBreakerState]float64{StateClosed: 0, StateHalfOpen: 1, StateOpen: 2}[state]
+		p.metrics.SetGauge("alert_source_circuit_state", value, map[string]string{"source": name})
+	}
+}
+
 // poll fetches and processes new alerts
 func (p *RealTimePoller) poll(ctx context.Context) error {
 	// Get last processed ID
-	lastID, err := p.repository.GetLastProcessedID(ctx)
+	lastID, err := SourceCursor(ctx, p.repository, p.name)
 	if err != nil {
 		log.Printf("Failed to get last processed ID (using 0): %v", err)
 		lastID = 0
@@ -75,33 +322,38 @@ func (p *RealTimePoller) poll(ctx context.Context) error {
 		return nil // No new alerts
 	}
 
-	log.Printf("📥 Received %d new alerts", len(alerts))
+	p.process(ctx, alerts)
+	return nil
+}
 
-	// Save alerts
-	var maxID uint64
-	for _, alert := range alerts {
-		if err := p.repository.SaveAlert(ctx, alert); err != nil {
-			log.Printf("⚠️  Failed to save alert %s: %v", alert.ID, err)
-			continue
-		}
+// process saves, publishes and analyzes a batch of new alerts
+func (p *RealTimePoller) process(ctx context.Context, alerts []domain.Alert) {
+	log.Printf("📥 Received %d new alerts from %s", len(alerts), p.name)
 
-		if alert.ExternalID > maxID {
-			maxID = alert.ExternalID
-		}
+	// On failure the batch is fetched again on the next poll
+	alerts, err := p.store(ctx, alerts)
+	if err != nil {
+		log.Printf("⚠️  %v", err)
+		return
 	}
 
-	// Update last processed ID
-	if maxID > 0 {
-		if err := p.repository.SetLastProcessedID(ctx, maxID); err != nil {
-			log.Printf("⚠️  Failed to update last processed ID: %v", err)
+	if p.anomalies != nil {
+		for _, anomaly := range p.anomalies.Observe(alerts) {
+			log.Printf("🔍 Anomaly detected: %s", anomaly.Message)
 		}
 	}
 
-	// Send to event channel for consumers
-	select {
-	case p.eventChan <- alerts:
-	default:
-		log.Println("⚠️  Event channel full, dropping alerts")
+	// Hand the batch to the consumers. Alerts that aren't consumed stay stored.
+	if p.queue != nil {
+		if err := p.queue.Submit(ctx, alerts); err != nil {
+			log.Printf("⚠️  Alerts from %s not queued: %v", p.name, err)
+		}
+	} else {
+		select {
+		case p.eventChan <- alerts:
+		default:
+			log.Println("⚠️  Event channel full, dropping alerts")
+		}
 	}
 
 	// Analyze and log
@@ -115,8 +367,205 @@ func (p *RealTimePoller) poll(ctx context.Context) error {
 				entry.Message)
 		}
 	}
+}
 
-	return nil
+// store labels and saves a batch, then advances the source cursor past it
+func (p *RealTimePoller) store(ctx context.Context, alerts []domain.Alert) ([]domain.Alert, error) {
+	p.attribute(alerts)
+	p.limitRawPayloads(alerts)
+	alerts = p.enrich(ctx, alerts)
+	alerts = p.classifier.ApplyAll(alerts)
+	alerts = p.severity.ApplyAll(alerts)
+	alerts = p.flaps.Mark(alerts)
+	alerts = p.suppressor.Mark(alerts)
+
+	if err := p.faults.Delay(ctx); err != nil {
+		return nil, fmt.Errorf("failed to save %d alerts: %w", len(alerts), err)
+	}
+	if err := p.repository.SaveAlerts(ctx, alerts); err != nil {
+		return nil, fmt.Errorf("failed to save %d alerts: %w", len(alerts), err)
+	}
+
+	// Update last processed ID
+	if maxID := maxExternalID(alerts); maxID > 0 {
+		if err := SetSourceCursor(ctx, p.repository, p.name, maxID); err != nil {
+			log.Printf("⚠️  Failed to update last processed ID: %v", err)
+		}
+	}
+	return alerts, nil
+}
+
+// BackfillHistory stores the past alerts of a source that keeps a history, on a cold
+// start only: while the source has no cursor yet. The alerts since the given time are
+// stored oldest first in batches covering chunk each, so an interrupted backfill resumes
+// with regular polling after the last stored batch. Backfilled alerts are not published
+// on the event channel; the stored alerts are returned for correlation.
+func (p *RealTimePoller) BackfillHistory(ctx context.Context, since time.Time, chunk time.Duration) ([]domain.Alert, error) {
+	history, ok := p.source.(ports.HistorySource)
+	if !ok {
+		return nil, nil
+	}
+	lastID, err := SourceCursor(ctx, p.repository, p.name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last processed ID: %w", err)
+	}
+	if lastID > 0 {
+		return nil, nil
+	}
+
+	alerts, err := history.FetchHistory(ctx, since)
+	p.recordPoll(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch alert history: %w", err)
+	}
+
+	var stored []domain.Alert
+	for _, batch := range chunkByTime(alerts, since, chunk) {
+		if ctx.Err() != nil {
+			return stored, ctx.Err()
+		}
+		saved, err := p.store(ctx, batch)
+		if err != nil {
+			return stored, err
+		}
+		stored = append(stored, saved...)
+	}
+	if p.anomalies != nil {
+		p.anomalies.Observe(stored)
+	}
+	return stored, nil
+}
+
+// chunkByTime splits time-ordered alerts into batches covering consecutive periods of
+// chunk starting at since; periods without alerts are skipped
+func chunkByTime(alerts []domain.Alert, since time.Time, chunk time.Duration) [][]domain.Alert {
+	if chunk <= 0 {
+		return [][]domain.Alert{alerts}
+	}
+	var batches [][]domain.Alert
+	var end time.Time
+	for _, alert := range alerts {
+		if len(batches) == 0 || !alert.OccurredAt.Before(end) {
+			// Start the batch at the period the alert falls in
+			periods := alert.OccurredAt.Sub(since) / chunk
+			end = since.Add((periods + 1) * chunk)
+			batches = append(batches, nil)
+		}
+		batches[len(batches)-1] = append(batches[len(batches)-1], alert)
+	}
+	return batches
+}
+
+// attribute stamps the source name on alerts that don't carry one, derives their IDs
+// from the alert fingerprint and counts them. Adapters derive IDs differently, so the
+// fingerprint makes re-ingesting an event yield the stored alert's ID.
+func (p *RealTimePoller) attribute(alerts []domain.Alert) {
+	p.mu.Lock()
+	name := p.name
+	p.status.Alerts += len(alerts)
+	p.mu.Unlock()
+
+	for i := range alerts {
+		if alerts[i].Source == "" {
+			alerts[i].Source = name
+		}
+		if fingerprint := alerts[i].Fingerprint(); fingerprint != "" {
+			alerts[i].ID = idgen.Derive(alerts[i].OccurredAt, fingerprint)
+		}
+	}
+	if p.metrics != nil {
+		p.metrics.RecordHistogram("alert_source_batch_size", float64(len(alerts)), map[string]string{"source": name})
+	}
+}
+
+// limitRawPayloads cuts the source payloads of a batch to the raw payload limit, at a
+// UTF-8 boundary. A cut payload is no longer valid JSON.
+func (p *RealTimePoller) limitRawPayloads(alerts []domain.Alert) {
+	for i := range alerts {
+		payload := alerts[i].RawPayload
+		if len(payload) <= p.rawLimit {
+			continue
+		}
+		if p.rawLimit <= 0 {
+			alerts[i].RawPayload = ""
+			continue
+		}
+		end := p.rawLimit
+		for end > 0 && !utf8.RuneStart(payload[end]) {
+			end--
+		}
+		alerts[i].RawPayload = payload[:end]
+		if p.metrics != nil {
+			p.metrics.IncCounter("alert_raw_payloads_truncated_total", map[string]string{"source": alerts[i].Source})
+		}
+	}
+}
+
+// recordPoll updates the source status after a poll or stream batch
+func (p *RealTimePoller) recordPoll(err error) {
+	now := time.Now()
+
+	p.mu.Lock()
+	p.status.LastPoll = &now
+	if err != nil {
+		p.status.LastError = err.Error()
+		p.status.ConsecutiveFailures++
+	} else {
+		p.status.LastSuccess = &now
+		p.status.LastError = ""
+		p.status.ConsecutiveFailures = 0
+	}
+	name := p.name
+	p.mu.Unlock()
+
+	if p.metrics == nil {
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	p.metrics.IncCounter("alert_source_polls_total", map[string]string{"source": name, "result": result})
+	if err == nil {
+		p.metrics.SetGauge("alert_source_last_success_timestamp", float64(now.Unix()), map[string]string{"source": name})
+	}
+}
+
+// SourceCursor returns the last processed alert ID of a source. The default source uses
+// the repository's last processed ID, so existing deployments keep their position.
+func SourceCursor(ctx context.Context, repo ports.Repository, source string) (uint64, error) {
+	if store, ok := repo.(ports.SourceCursorStore); ok && source != DefaultSourceName {
+		return store.GetSourceCursor(ctx, source)
+	}
+	return repo.GetLastProcessedID(ctx)
+}
+
+// SetSourceCursor updates the last processed alert ID of a source
+func SetSourceCursor(ctx context.Context, repo ports.Repository, source string, id uint64) error {
+	if store, ok := repo.(ports.SourceCursorStore); ok && source != DefaultSourceName {
+		return store.SetSourceCursor(ctx, source, id)
+	}
+	return repo.SetLastProcessedID(ctx, id)
+}
+
+// maxExternalID returns the highest source ID in the batch
+func maxExternalID(alerts []domain.Alert) uint64 {
+	var maxID uint64
+	for _, alert := range alerts {
+		if alert.ExternalID > maxID {
+			maxID = alert.ExternalID
+		}
+	}
+	return maxID
+}
+
+// enrich labels a batch; alerts are stored even if an enricher fails
+func (p *RealTimePoller) enrich(ctx context.Context, alerts []domain.Alert) []domain.Alert {
+	alerts, err := p.enrichment.Apply(ctx, alerts)
+	if err != nil {
+		log.Printf("⚠️  Failed to enrich alerts from %s: %v", p.name, err)
+	}
+	return alerts
 }
 
 // Events returns the channel for consuming alert events
@@ -126,29 +575,27 @@ func (p *RealTimePoller) Events() <-chan []domain.Alert {
 
 // PollOnce performs a single poll (useful for testing or manual triggers)
 func (p *RealTimePoller) PollOnce(ctx context.Context) ([]domain.Alert, error) {
-	lastID, err := p.repository.GetLastProcessedID(ctx)
+	lastID, err := SourceCursor(ctx, p.repository, p.name)
 	if err != nil {
 		lastID = 0
 	}
 
 	alerts, err := p.source.FetchLatest(ctx, lastID)
+	p.recordPoll(err)
 	if err != nil {
 		return nil, err
 	}
+	p.attribute(alerts)
+	p.limitRawPayloads(alerts)
+	alerts = p.enrich(ctx, alerts)
 
 	// Save and update
-	var maxID uint64
-	for _, alert := range alerts {
-		if err := p.repository.SaveAlert(ctx, alert); err != nil {
-			continue
-		}
-		if alert.ExternalID > maxID {
-			maxID = alert.ExternalID
-		}
+	if err := p.repository.SaveAlerts(ctx, alerts); err != nil {
+		return nil, fmt.Errorf("failed to save alerts: %w", err)
 	}
 
-	if maxID > 0 {
-		p.repository.SetLastProcessedID(ctx, maxID)
+	if maxID := maxExternalID(alerts); maxID > 0 {
+		SetSourceCursor(ctx, p.repository, p.name, maxID)
 	}
 
 	return alerts, nil
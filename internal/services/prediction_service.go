@@ -0,0 +1,392 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"incident-teller/internal/ai"
+	"incident-teller/internal/domain"
+	"incident-teller/internal/notify"
+	"incident-teller/internal/ports"
+)
+
+// Warnings need this many resolved past occurrences before their escalation rate is used
+const minEscalationSamples = 3
+
+// IncidentPrediction forecasts that the open warnings on a host are about to become an incident
+type IncidentPrediction struct {
+	Host        string
+	Warnings    []domain.Alert // Open warning-level alerts the prediction is based on
+	Confidence  float64        // 0.0-1.0
+	ExpectedAt  time.Time
+	Reasons     []string
+	PredictedAt time.Time
+}
+
+// MinutesUntil returns in how many whole minutes (rounded up) the incident is expected
+func (p IncidentPrediction) MinutesUntil(now time.Time) int {
+	if p.ExpectedAt.Before(now) {
+		return 0
+	}
+	return int(math.Ceil(p.ExpectedAt.Sub(now).Minutes()))
+}
+
+// PredictionService periodically evaluates open warning-level alerts against patterns
+// learned from history (how often the same warning escalated to critical, and how
+// issues propagate between resources) and the AI model's pattern analysis, and emits
+// "incident likely within N minutes" notifications for likely incidents
+type PredictionService struct {
+	history       ports.AlertHistory
+	horizon       time.Duration // Only incidents expected within this are predicted
+	minConfidence float64
+	lookback      time.Duration // History used for escalation statistics
+
+	learner    *PropagationLearner
+	model      ai.AIModel
+	dispatcher *notify.Dispatcher
+
+	mu          sync.RWMutex
+	predictions []IncidentPrediction
+	notified    map[string]time.Time // Host -> when a pre-incident notification was sent
+}
+
+// NewPredictionService creates a prediction service over the alert history
+func NewPredictionService(history ports.AlertHistory, horizon time.Duration, minConfidence float64, lookback time.Duration) *PredictionService {
+	return &PredictionService{
+		history:       history,
+		horizon:       horizon,
+		minConfidence: minConfidence,
+		lookback:      lookback,
+		predictions:   []IncidentPrediction{},
+		notified:      make(map[string]time.Time),
+	}
+}
+
+// SetPropagationLearner adds learned propagation patterns as a prediction signal
+func (s *PredictionService) SetPropagationLearner(learner *PropagationLearner) {
+	s.learner = learner
+}
+
+// SetAIModel adds the model's pattern analysis and next-occurrence estimate as a signal
+func (s *PredictionService) SetAIModel(model ai.AIModel) {
+	s.model = model
+}
+
+// SetDispatcher enables pre-incident notifications
+func (s *PredictionService) SetDispatcher(dispatcher *notify.Dispatcher) {
+	s.dispatcher = dispatcher
+}
+
+// Predictions returns the predictions of the last evaluation, most confident first
+func (s *PredictionService) Predictions() []IncidentPrediction {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	predictions := make([]IncidentPrediction, len(s.predictions))
+	copy(predictions, s.predictions)
+	return predictions
+}
+
+// Run evaluates predictions every interval until ctx is cancelled
+func (s *PredictionService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			predictions, err := s.Evaluate(ctx, time.Now())
+			if err != nil {
+				log.Printf("⚠️  Incident prediction failed: %v", err)
+				continue
+			}
+			if err := s.notify(ctx, predictions, time.Now()); err != nil {
+				log.Printf("⚠️  Failed to send pre-incident notification: %v", err)
+			}
+		}
+	}
+}
+
+// escalationStats counts how often a warning escalated to critical before clearing
+type escalationStats struct {
+	resolved  int             // Warnings that either escalated or cleared
+	escalated []time.Duration // Delays from warning to critical
+}
+
+func (e *escalationStats) probability() float64 {
+	return float64(len(e.escalated)) / float64(e.resolved)
+}
+
+// Evaluate computes the current predictions and stores them
+func (s *PredictionService) Evaluate(ctx context.Context, now time.Time) ([]IncidentPrediction, error) {
+	alerts, err := s.history.GetAlerts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load alerts: %w", err)
+	}
+
+	recent := make([]domain.Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		if !alert.OccurredAt.Before(now.Add(-s.lookback)) && !alert.OccurredAt.After(now) {
+			recent = append(recent, alert)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].OccurredAt.Before(recent[j].OccurredAt)
+	})
+
+	byHostName, byName, open := escalationHistory(recent)
+
+	// Group open warnings by host; hosts with an open critical already have an incident
+	warnings := make(map[string][]domain.Alert)
+	critical := make(map[string]bool)
+	for _, alert := range open {
+		switch alert.Status {
+		case domain.StatusWarning:
+			warnings[alert.Host] = append(warnings[alert.Host], alert)
+		case domain.StatusCritical:
+			critical[alert.Host] = true
+		}
+	}
+
+	predictions := []IncidentPrediction{}
+	for host, hostWarnings := range warnings {
+		if critical[host] {
+			continue
+		}
+		sort.Slice(hostWarnings, func(i, j int) bool {
+			return hostWarnings[i].OccurredAt.Before(hostWarnings[j].OccurredAt)
+		})
+
+		prediction, ok := s.predictHost(ctx, host, hostWarnings, byHostName, byName, now)
+		if ok && prediction.Confidence >= s.minConfidence && !prediction.ExpectedAt.After(now.Add(s.horizon)) {
+			predictions = append(predictions, prediction)
+		}
+	}
+
+	sort.Slice(predictions, func(i, j int) bool {
+		if predictions[i].Confidence != predictions[j].Confidence {
+			return predictions[i].Confidence > predictions[j].Confidence
+		}
+		return predictions[i].Host < predictions[j].Host
+	})
+
+	s.mu.Lock()
+	s.predictions = predictions
+	s.mu.Unlock()
+
+	return predictions, nil
+}
+
+// escalationHistory replays the alerts (in time order) per host and alert name. It returns
+// escalation statistics per host+name and per name, and the latest alert of every
+// host+name whose issue is still open.
+func escalationHistory(alerts []domain.Alert) (map[string]*escalationStats, map[string]*escalationStats, []domain.Alert) {
+	byHostName := make(map[string]*escalationStats)
+	byName := make(map[string]*escalationStats)
+	warningSince := make(map[string]time.Time)
+	latest := make(map[string]domain.Alert)
+
+	record := func(host, name string, escalated bool, delay time.Duration) {
+		for key, stats := range map[string]map[string]*escalationStats{host + "/" + name: byHostName, name: byName} {
+			e, ok := stats[key]
+			if !ok {
+				e = &escalationStats{}
+				stats[key] = e
+			}
+			e.resolved++
+			if escalated {
+				e.escalated = append(e.escalated, delay)
+			}
+		}
+	}
+
+	for _, alert := range alerts {
+		key := alert.Host + "/" + alert.Name
+		latest[key] = alert
+
+		since, warning := warningSince[key]
+		switch alert.Status {
+		case domain.StatusWarning:
+			if !warning {
+				warningSince[key] = alert.OccurredAt
+			}
+		case domain.StatusCritical:
+			if warning {
+				record(alert.Host, alert.Name, true, alert.OccurredAt.Sub(since))
+				delete(warningSince, key)
+			}
+		default:
+			if warning {
+				record(alert.Host, alert.Name, false, 0)
+				delete(warningSince, key)
+			}
+		}
+	}
+
+	open := make([]domain.Alert, 0, len(latest))
+	for _, alert := range latest {
+		if alert.Status == domain.StatusWarning || alert.Status == domain.StatusCritical {
+			open = append(open, alert)
+		}
+	}
+
+	// Warnings still open are not resolved yet, so they did not contribute to the stats;
+	// report when they started rather than their latest update
+	for i, alert := range open {
+		if since, ok := warningSince[alert.Host+"/"+alert.Name]; ok && alert.Status == domain.StatusWarning {
+			open[i].OccurredAt = since
+		}
+	}
+
+	return byHostName, byName, open
+}
+
+// predictHost combines the signals for one host's open warnings. Each signal is an
+// independent chance of the incident forming; the expected time is that of the most
+// likely signal.
+func (s *PredictionService) predictHost(
+	ctx context.Context,
+	host string,
+	warnings []domain.Alert,
+	byHostName, byName map[string]*escalationStats,
+	now time.Time,
+) (IncidentPrediction, bool) {
+	prediction := IncidentPrediction{Host: host, Warnings: warnings, PredictedAt: now}
+
+	noIncident := 1.0
+	best := 0.0
+	signal := func(probability float64, expectedAt time.Time, reason string) {
+		noIncident *= 1 - probability
+		prediction.Reasons = append(prediction.Reasons, reason)
+		if probability > best {
+			best = probability
+			prediction.ExpectedAt = expectedAt
+		}
+	}
+
+	openResources := make(map[domain.ResourceType]bool)
+	for _, w := range warnings {
+		openResources[w.ResourceType] = true
+	}
+
+	for _, w := range warnings {
+		// How often this warning escalated to critical before, on this host or fleet-wide
+		stats, scope := byHostName[host+"/"+w.Name], "on "+host
+		if stats == nil || stats.resolved < minEscalationSamples {
+			stats, scope = byName[w.Name], "fleet-wide"
+		}
+		if stats != nil && stats.resolved >= minEscalationSamples && len(stats.escalated) > 0 {
+			delay := medianDuration(stats.escalated)
+			signal(stats.probability(), w.OccurredAt.Add(delay), fmt.Sprintf(
+				"%s WARNING escalated to CRITICAL in %d of %d past cases %s (median %s)",
+				w.Name, len(stats.escalated), stats.resolved, scope, delay.Round(time.Second)))
+		}
+
+		// How often an issue on this resource spread to another one
+		if s.learner != nil {
+			for _, pattern := range s.learner.PatternsFrom(w, w.ResourceType) {
+				if openResources[pattern.To] {
+					continue
+				}
+				signal(pattern.Probability, w.OccurredAt.Add(pattern.Window), "learned pattern "+pattern.String())
+			}
+		}
+	}
+
+	// The model's pattern analysis: an increasing trend with a next occurrence in the horizon
+	if s.model != nil {
+		analysis, err := s.model.AnalyzePatterns(ctx, warnings)
+		if err == nil && analysis.Trend == "increasing" && analysis.PredictedNext.Before(now.Add(s.horizon)) {
+			signal(analysis.Confidence*0.5, analysis.PredictedNext, fmt.Sprintf(
+				"%s alert pattern is increasing; next occurrence expected at %s",
+				analysis.PatternType, analysis.PredictedNext.UTC().Format("15:04 -07:00")))
+		}
+	}
+
+	if len(prediction.Reasons) == 0 {
+		return prediction, false
+	}
+
+	prediction.Confidence = math.Round((1-noIncident)*100) / 100
+	if prediction.ExpectedAt.Before(now) {
+		prediction.ExpectedAt = now
+	}
+	return prediction, true
+}
+
+// medianDuration returns the median of the durations
+func medianDuration(durations []time.Duration) time.Duration {
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[len(sorted)/2]
+}
+
+// notify sends a pre-incident notification for every host newly predicted. A host is
+// notified again only after its prediction lapsed or the horizon passed.
+func (s *PredictionService) notify(ctx context.Context, predictions []IncidentPrediction, now time.Time) error {
+	if s.dispatcher == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	predicted := make(map[string]bool, len(predictions))
+	var due []IncidentPrediction
+	for _, p := range predictions {
+		predicted[p.Host] = true
+		if sent, ok := s.notified[p.Host]; !ok || now.Sub(sent) >= s.horizon {
+			s.notified[p.Host] = now
+			due = append(due, p)
+		}
+	}
+	for host := range s.notified {
+		if !predicted[host] {
+			delete(s.notified, host)
+		}
+	}
+	s.mu.Unlock()
+
+	var errs []error
+	for _, p := range due {
+		if err := s.dispatcher.Send(ctx, PredictionNotification(p, now)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Host, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PredictionNotification formats a pre-incident notification
+func PredictionNotification(p IncidentPrediction, now time.Time) notify.Notification {
+	title := fmt.Sprintf("Incident likely within %d minutes on %s", p.MinutesUntil(now), p.Host)
+
+	names := make([]string, len(p.Warnings))
+	for i, w := range p.Warnings {
+		names[i] = w.Name
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "🔮 *PRE-INCIDENT WARNING* %s\n\n", title)
+	fmt.Fprintf(&text, "*Confidence:* %.0f%%\n", p.Confidence*100)
+	fmt.Fprintf(&text, "*Expected:* %s\n", p.ExpectedAt.Format(time.RFC3339))
+	fmt.Fprintf(&text, "*Open warnings:* %s\n\n", strings.Join(names, ", "))
+	text.WriteString("*Why:*\n")
+	for _, reason := range p.Reasons {
+		fmt.Fprintf(&text, "• %s\n", reason)
+	}
+
+	return notify.Notification{
+		Title:    title,
+		Severity: "warning",
+		Text:     text.String(),
+		Hosts:    []string{p.Host},
+	}
+}
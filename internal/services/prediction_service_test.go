@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/notify"
+)
+
+type staticHistory []domain.Alert
+
+func (h staticHistory) GetAlerts(ctx context.Context) ([]domain.Alert, error) {
+	return h, nil
+}
+
+type recordingNotifier struct {
+	sent []notify.Notification
+}
+
+func (n *recordingNotifier) Name() string { return "recording" }
+
+func (n *recordingNotifier) Send(ctx context.Context, notification notify.Notification) error {
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func TestPredictionService_EscalationHistory(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	var history staticHistory
+	alert := func(host, name string, status domain.AlertStatus, at time.Time) {
+		history = append(history, domain.Alert{
+			ID:           host + "-" + name + "-" + at.Format(time.RFC3339),
+			Host:         host,
+			Name:         name,
+			Status:       status,
+			ResourceType: domain.ResourceDisk,
+			OccurredAt:   at,
+		})
+	}
+
+	// db-01: disk_util warnings escalated to critical after 10 minutes in 4 of 5 cases
+	for i := 0; i < 5; i++ {
+		at := now.Add(-time.Duration(24-i*2) * time.Hour)
+		alert("db-01", "disk_util", domain.StatusWarning, at)
+		if i < 4 {
+			alert("db-01", "disk_util", domain.StatusCritical, at.Add(10*time.Minute))
+		}
+		alert("db-01", "disk_util", domain.StatusClear, at.Add(30*time.Minute))
+	}
+	alert("db-01", "disk_util", domain.StatusWarning, now.Add(-2*time.Minute))
+
+	// cache-01: the same kind of warning always cleared on its own
+	for i := 0; i < 4; i++ {
+		at := now.Add(-time.Duration(20-i*2) * time.Hour)
+		alert("cache-01", "disk_fill", domain.StatusWarning, at)
+		alert("cache-01", "disk_fill", domain.StatusClear, at.Add(5*time.Minute))
+	}
+	alert("cache-01", "disk_fill", domain.StatusWarning, now.Add(-time.Minute))
+
+	recorder := &recordingNotifier{}
+	predictor := NewPredictionService(history, 30*time.Minute, 0.6, 7*24*time.Hour)
+	predictor.SetDispatcher(notify.NewDispatcher(recorder))
+
+	predictions, err := predictor.Evaluate(context.Background(), now)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if len(predictions) != 1 || predictions[0].Host != "db-01" {
+		t.Fatalf("Expected a single prediction for db-01, got %+v", predictions)
+	}
+	p := predictions[0]
+	if p.Confidence != 0.8 {
+		t.Errorf("Expected confidence 0.8, got %v", p.Confidence)
+	}
+	if !p.ExpectedAt.Equal(now.Add(8 * time.Minute)) {
+		t.Errorf("Expected incident 10 minutes after the warning, got %v", p.ExpectedAt)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := predictor.notify(context.Background(), predictions, now); err != nil {
+			t.Fatalf("notify failed: %v", err)
+		}
+	}
+	if len(recorder.sent) != 1 {
+		t.Fatalf("Expected one notification per prediction, got %d", len(recorder.sent))
+	}
+	if !strings.Contains(recorder.sent[0].Title, "Incident likely within 8 minutes on db-01") {
+		t.Errorf("Unexpected notification title %q", recorder.sent[0].Title)
+	}
+
+	// Once the warning escalates the incident has formed and is no longer predicted
+	alert("db-01", "disk_util", domain.StatusCritical, now.Add(time.Minute))
+	predictor.history = history
+	if predictions, _ := predictor.Evaluate(context.Background(), now.Add(2*time.Minute)); len(predictions) != 0 {
+		t.Errorf("Expected no predictions once critical, got %+v", predictions)
+	}
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/ports"
+)
+
+// DefaultRecurrenceThreshold is the signature similarity from which an incident is an
+// occurrence of the problem of a resolved incident
+const DefaultRecurrenceThreshold = 0.8
+
+// Problem is an underlying problem that caused several incidents
+type Problem struct {
+	ID          string // ID of the first incident
+	Title       string // Title of the first incident
+	Occurrences int
+	IncidentIDs []string // Oldest first
+	Hosts       []string
+	FirstSeen   time.Time // Start of the first incident
+	LastSeen    time.Time // Start of the latest incident
+	Active      bool      // The latest incident is not resolved
+}
+
+// ProblemLinker links new incidents to the resolved incidents they repeat, as
+// occurrences of the same problem. Incidents repeat each other when their signatures, the
+// host, resource type and alert name of their alerts, are similar enough.
+type ProblemLinker struct {
+	repo      ports.Repository
+	store     ports.ProblemStore
+	threshold float64
+}
+
+// NewProblemLinker creates a linker matching incidents whose signatures have at least the
+// threshold Jaccard similarity (0-1)
+func NewProblemLinker(repo ports.Repository, store ports.ProblemStore, threshold float64) *ProblemLinker {
+	return &ProblemLinker{repo: repo, store: store, threshold: threshold}
+}
+
+// IncidentSignature returns the distinct host, resource type and alert name combinations
+// of an incident's alerts
+func IncidentSignature(incident domain.Incident) []string {
+	return incidentValues(incident, func(e domain.Alert) string {
+		return e.Host + "/" + string(e.ResourceType) + "/" + e.Name
+	})
+}
+
+// Link links the incident to the problem of the most similar incident resolved before it
+// started, if any is similar enough, and returns the link. An incident is linked once;
+// later calls return nil.
+func (l *ProblemLinker) Link(ctx context.Context, incident domain.Incident) (*domain.ProblemLink, error) {
+	if len(incident.Events) == 0 {
+		return nil, nil
+	}
+	links, err := l.store.GetProblemLinks(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	problemOf := make(map[string]string, len(links))
+	for _, link := range links {
+		problemOf[link.IncidentID] = link.ProblemID
+	}
+	if _, ok := problemOf[incident.ID]; ok {
+		return nil, nil
+	}
+
+	incidents, err := l.repo.GetIncidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	signature := IncidentSignature(incident)
+	var match *domain.Incident
+	best := 0.0
+	for i := range incidents {
+		candidate := &incidents[i]
+		if candidate.ID == incident.ID || candidate.ResolvedAt == nil || candidate.ResolvedAt.After(incident.StartedAt) {
+			continue
+		}
+		similarity := NewOverlap(signature, IncidentSignature(*candidate)).Similarity
+		if similarity < l.threshold {
+			continue
+		}
+		// Ties go to the latest occurrence
+		if match == nil || similarity > best || (similarity == best && candidate.StartedAt.After(match.StartedAt)) {
+			match, best = candidate, similarity
+		}
+	}
+	if match == nil {
+		return nil, nil
+	}
+
+	now := time.Now().UTC()
+	problemID, ok := problemOf[match.ID]
+	if !ok {
+		problemID = match.ID
+		first := domain.ProblemLink{IncidentID: match.ID, ProblemID: problemID, Similarity: 1, LinkedAt: now}
+		if err := l.store.LinkIncident(ctx, first); err != nil {
+			return nil, err
+		}
+	}
+	link := domain.ProblemLink{IncidentID: incident.ID, ProblemID: problemID, Similarity: best, LinkedAt: now}
+	if err := l.store.LinkIncident(ctx, link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// GroupProblems groups linked incidents into problems, most recently seen first. Links to
+// incidents no longer stored are skipped, and problems left with a single occurrence are
+// not recurring.
+func GroupProblems(links []domain.ProblemLink, incidents []domain.Incident) []Problem {
+	byID := make(map[string]*domain.Incident, len(incidents))
+	for i := range incidents {
+		byID[incidents[i].ID] = &incidents[i]
+	}
+	occurrences := make(map[string][]*domain.Incident)
+	for _, link := range links {
+		if incident, ok := byID[link.IncidentID]; ok {
+			occurrences[link.ProblemID] = append(occurrences[link.ProblemID], incident)
+		}
+	}
+
+	problems := []Problem{}
+	for id, linked := range occurrences {
+		if len(linked) < 2 {
+			continue
+		}
+		sort.Slice(linked, func(i, j int) bool { return linked[i].StartedAt.Before(linked[j].StartedAt) })
+		first, latest := linked[0], linked[len(linked)-1]
+		problem := Problem{
+			ID:          id,
+			Title:       first.Title,
+			Occurrences: len(linked),
+			FirstSeen:   first.StartedAt,
+			LastSeen:    latest.StartedAt,
+			Active:      latest.ResolvedAt == nil,
+		}
+		if incident, ok := byID[id]; ok {
+			problem.Title = incident.Title
+		}
+		hosts := make(map[string]bool)
+		for _, incident := range linked {
+			problem.IncidentIDs = append(problem.IncidentIDs, incident.ID)
+			for _, host := range incident.Hosts() {
+				if !hosts[host] {
+					hosts[host] = true
+					problem.Hosts = append(problem.Hosts, host)
+				}
+			}
+		}
+		sort.Strings(problem.Hosts)
+		problems = append(problems, problem)
+	}
+	sort.Slice(problems, func(i, j int) bool {
+		if !problems[i].LastSeen.Equal(problems[j].LastSeen) {
+			return problems[i].LastSeen.After(problems[j].LastSeen)
+		}
+		return problems[i].ID < problems[j].ID
+	})
+	return problems
+}
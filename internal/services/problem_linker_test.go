@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"incident-teller/internal/adapters/repository"
+	"incident-teller/internal/domain"
+)
+
+func TestProblemLinker_LinksRepeatsOfResolvedIncidents(t *testing.T) {
+	repo := repository.NewInMemoryRepository()
+	linker := NewProblemLinker(repo, repo, DefaultRecurrenceThreshold)
+	ctx := context.Background()
+
+	start := time.Now().UTC().Add(-72 * time.Hour)
+	newIncident := func(id string, at time.Time, resolved bool, names ...string) domain.Incident {
+		incident := domain.Incident{ID: id, Title: "Disk full on db-01", Status: domain.StatusWarning, StartedAt: at}
+		for i, name := range names {
+			incident.Events = append(incident.Events, domain.Alert{ID: id + "-" + name, Host: "db-01", Name: name,
+				ResourceType: domain.ResourceDisk, Status: domain.StatusWarning, OccurredAt: at.Add(time.Duration(i) * time.Minute)})
+		}
+		if resolved {
+			end := at.Add(time.Hour)
+			incident.ResolvedAt = &end
+		}
+		if err := repo.SaveIncident(ctx, incident); err != nil {
+			t.Fatal(err)
+		}
+		return incident
+	}
+
+	first := newIncident("inc-1", start, true, "disk_space", "disk_inodes")
+	newIncident("inc-other", start.Add(time.Hour), true, "cpu_usage")
+	second := newIncident("inc-2", start.Add(24*time.Hour), true, "disk_space", "disk_inodes")
+	// Still ongoing when the third occurrence starts, so it's not a previous occurrence
+	newIncident("inc-ongoing", start.Add(47*time.Hour), false, "disk_space", "disk_inodes")
+	third := newIncident("inc-3", start.Add(48*time.Hour), false, "disk_space", "disk_inodes")
+
+	if link, err := linker.Link(ctx, first); err != nil || link != nil {
+		t.Fatalf("expected the first occurrence unlinked, got %+v (err %v)", link, err)
+	}
+	link, err := linker.Link(ctx, second)
+	if err != nil || link == nil || link.ProblemID != "inc-1" || link.Similarity != 1 {
+		t.Fatalf("expected inc-2 linked to inc-1, got %+v (err %v)", link, err)
+	}
+	// The latest matching occurrence is already linked, so its problem is reused
+	link, err = linker.Link(ctx, third)
+	if err != nil || link == nil || link.ProblemID != "inc-1" {
+		t.Fatalf("expected inc-3 linked to inc-1, got %+v (err %v)", link, err)
+	}
+	if again, err := linker.Link(ctx, third); err != nil || again != nil {
+		t.Fatalf("expected an incident linked once, got %+v (err %v)", again, err)
+	}
+
+	links, _ := repo.GetProblemLinks(ctx, "")
+	incidents, _ := repo.GetIncidents(ctx)
+	problems := GroupProblems(links, incidents)
+	if len(problems) != 1 {
+		t.Fatalf("expected one problem, got %+v", problems)
+	}
+	problem := problems[0]
+	if problem.ID != "inc-1" || problem.Occurrences != 3 || !problem.Active ||
+		!problem.FirstSeen.Equal(first.StartedAt) || !problem.LastSeen.Equal(third.StartedAt) {
+		t.Fatalf("unexpected problem %+v", problem)
+	}
+	if len(problem.Hosts) != 1 || problem.Hosts[0] != "db-01" {
+		t.Fatalf("expected db-01, got %v", problem.Hosts)
+	}
+}
+
+func TestProblemLinker_BelowThreshold(t *testing.T) {
+	repo := repository.NewInMemoryRepository()
+	linker := NewProblemLinker(repo, repo, DefaultRecurrenceThreshold)
+	ctx := context.Background()
+
+	start := time.Now().UTC().Add(-48 * time.Hour)
+	end := start.Add(time.Hour)
+	alert := func(name string, at time.Time) domain.Alert {
+		return domain.Alert{ID: name + at.String(), Host: "db-01", Name: name, ResourceType: domain.ResourceDisk, OccurredAt: at}
+	}
+	earlier := domain.Incident{ID: "inc-1", StartedAt: start, ResolvedAt: &end,
+		Events: []domain.Alert{alert("disk_space", start), alert("disk_inodes", start)}}
+	later := domain.Incident{ID: "inc-2", StartedAt: start.Add(24 * time.Hour),
+		Events: []domain.Alert{alert("disk_space", start.Add(24*time.Hour)), alert("disk_io", start.Add(24*time.Hour))}}
+	for _, incident := range []domain.Incident{earlier, later} {
+		if err := repo.SaveIncident(ctx, incident); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// One shared combination out of three is not the same problem
+	if link, err := linker.Link(ctx, later); err != nil || link != nil {
+		t.Fatalf("expected no link, got %+v (err %v)", link, err)
+	}
+}
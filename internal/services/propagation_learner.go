@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/ports"
+	"incident-teller/internal/topology"
+)
+
+// Learned patterns with a lower probability are not treated as causal; they also
+// override the static rule for the same resource pair
+const minCausalProbability = 0.5
+
+// PropagationLearner mines historical alert sequences for how issues spread between
+// resources on the same host. For each issue onset it records which other resources
+// started failing on that host within the learning window, and derives per-host,
+// per-service and fleet-wide propagation probabilities from those counts.
+type PropagationLearner struct {
+	window          time.Duration // Max delay between two issues to count as propagation
+	minObservations int           // Onsets needed before a pattern is trusted
+	lookback        time.Duration // Only alerts this recent are mined; 0 mines everything
+	topology        *topology.Topology
+
+	mu       sync.RWMutex
+	patterns map[propagationKey]domain.PropagationPattern
+}
+
+type propagationKey struct {
+	host, service string
+	from, to      domain.ResourceType
+}
+
+// NewPropagationLearner creates a learner. Issues on a resource that follow an issue on
+// another resource of the same host within window count as propagation.
+func NewPropagationLearner(window time.Duration, minObservations int, lookback time.Duration) *PropagationLearner {
+	if minObservations < 1 {
+		minObservations = 1
+	}
+	return &PropagationLearner{
+		window:          window,
+		minObservations: minObservations,
+		lookback:        lookback,
+		patterns:        make(map[propagationKey]domain.PropagationPattern),
+	}
+}
+
+// SetTopology maps hosts onto services for service-level patterns. Without a topology
+// the alert's "service" label is used.
+func (l *PropagationLearner) SetTopology(topo *topology.Topology) {
+	l.topology = topo
+}
+
+func (l *PropagationLearner) serviceOf(alert domain.Alert) string {
+	if service, ok := l.topology.ServiceForHost(alert.Host); ok {
+		return service
+	}
+	return alert.Labels["service"]
+}
+
+// propagationStats accumulates onsets of one resource and the delays of the issues that followed
+type propagationStats struct {
+	observations int
+	delays       map[domain.ResourceType][]time.Duration
+}
+
+// Learn derives propagation patterns from the alert history, replaces the current
+// patterns with them and returns them
+func (l *PropagationLearner) Learn(alerts []domain.Alert, now time.Time) []domain.PropagationPattern {
+	onsets := l.onsetsByHost(alerts, now)
+
+	// Per host/service/fleet scope -> from resource -> stats
+	stats := make(map[propagationKey]*propagationStats)
+	record := func(key propagationKey, followers map[domain.ResourceType]time.Duration) {
+		s, ok := stats[key]
+		if !ok {
+			s = &propagationStats{delays: make(map[domain.ResourceType][]time.Duration)}
+			stats[key] = s
+		}
+		s.observations++
+		for to, delay := range followers {
+			s.delays[to] = append(s.delays[to], delay)
+		}
+	}
+
+	for host, hostOnsets := range onsets {
+		for i, onset := range hostOnsets {
+			// First onset of each other resource within the window
+			followers := make(map[domain.ResourceType]time.Duration)
+			for _, next := range hostOnsets[i+1:] {
+				delay := next.OccurredAt.Sub(onset.OccurredAt)
+				if delay > l.window {
+					break
+				}
+				if next.ResourceType == onset.ResourceType {
+					continue
+				}
+				if _, seen := followers[next.ResourceType]; !seen {
+					followers[next.ResourceType] = delay
+				}
+			}
+
+			record(propagationKey{host: host, from: onset.ResourceType}, followers)
+			if service := l.serviceOf(onset); service != "" {
+				record(propagationKey{service: service, from: onset.ResourceType}, followers)
+			}
+			record(propagationKey{from: onset.ResourceType}, followers)
+		}
+	}
+
+	learned := make(map[propagationKey]domain.PropagationPattern)
+	for key, s := range stats {
+		if s.observations < l.minObservations {
+			continue
+		}
+		for to, delays := range s.delays {
+			pattern := domain.PropagationPattern{
+				Host:         key.host,
+				Service:      key.service,
+				From:         key.from,
+				To:           to,
+				Probability:  float64(len(delays)) / float64(s.observations),
+				Window:       percentileDelay(delays, 0.9),
+				Observations: s.observations,
+				LearnedAt:    now,
+			}
+			learned[propagationKey{host: key.host, service: key.service, from: key.from, to: to}] = pattern
+		}
+	}
+
+	l.mu.Lock()
+	l.patterns = learned
+	l.mu.Unlock()
+
+	return l.Patterns()
+}
+
+// onsetsByHost returns, per host and in time order, the alerts that start an issue on a
+// resource: non-clear alerts with no other issue on that resource in the preceding window
+func (l *PropagationLearner) onsetsByHost(alerts []domain.Alert, now time.Time) map[string][]domain.Alert {
+	sorted := make([]domain.Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		if l.lookback > 0 && alert.OccurredAt.Before(now.Add(-l.lookback)) {
+			continue
+		}
+		if alert.ResourceType == "" || alert.ResourceType == domain.ResourceUnknown {
+			continue
+		}
+		if alert.Status != domain.StatusWarning && alert.Status != domain.StatusCritical {
+			continue
+		}
+		sorted = append(sorted, alert)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].OccurredAt.Before(sorted[j].OccurredAt)
+	})
+
+	onsets := make(map[string][]domain.Alert)
+	lastSeen := make(map[string]map[domain.ResourceType]time.Time)
+	for _, alert := range sorted {
+		seen, ok := lastSeen[alert.Host]
+		if !ok {
+			seen = make(map[domain.ResourceType]time.Time)
+			lastSeen[alert.Host] = seen
+		}
+		if last, active := seen[alert.ResourceType]; !active || alert.OccurredAt.Sub(last) > l.window {
+			onsets[alert.Host] = append(onsets[alert.Host], alert)
+		}
+		seen[alert.ResourceType] = alert.OccurredAt
+	}
+
+	return onsets
+}
+
+// percentileDelay returns the p-th percentile of the delays, rounded up to a second
+func percentileDelay(delays []time.Duration, p float64) time.Duration {
+	sorted := make([]time.Duration, len(delays))
+	copy(sorted, delays)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	index := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	window := sorted[index].Truncate(time.Second)
+	if window < sorted[index] || window == 0 {
+		window += time.Second
+	}
+	return window
+}
+
+// Load replaces the current patterns, e.g. with patterns persisted by an earlier run
+func (l *PropagationLearner) Load(patterns []domain.PropagationPattern) {
+	loaded := make(map[propagationKey]domain.PropagationPattern, len(patterns))
+	for _, p := range patterns {
+		loaded[propagationKey{host: p.Host, service: p.Service, from: p.From, to: p.To}] = p
+	}
+
+	l.mu.Lock()
+	l.patterns = loaded
+	l.mu.Unlock()
+}
+
+// Patterns returns all learned patterns, most likely first
+func (l *PropagationLearner) Patterns() []domain.PropagationPattern {
+	l.mu.RLock()
+	patterns := make([]domain.PropagationPattern, 0, len(l.patterns))
+	for _, p := range l.patterns {
+		patterns = append(patterns, p)
+	}
+	l.mu.RUnlock()
+
+	sort.Slice(patterns, func(i, j int) bool {
+		a, b := patterns[i], patterns[j]
+		if a.Probability != b.Probability {
+			return a.Probability > b.Probability
+		}
+		if a.Scope() != b.Scope() {
+			return a.Scope() < b.Scope()
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	return patterns
+}
+
+// Lookup returns the most specific learned pattern for an issue on from being followed by
+// an issue on to for the alert's host: host-specific, then service, then fleet-wide
+func (l *PropagationLearner) Lookup(alert domain.Alert, from, to domain.ResourceType) (domain.PropagationPattern, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, key := range l.scopes(alert) {
+		key.from, key.to = from, to
+		if p, ok := l.patterns[key]; ok {
+			return p, true
+		}
+	}
+	return domain.PropagationPattern{}, false
+}
+
+// PatternsFrom returns the most specific learned pattern for every resource an issue on
+// from has been seen spreading to on the alert's host
+func (l *PropagationLearner) PatternsFrom(alert domain.Alert, from domain.ResourceType) []domain.PropagationPattern {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	seen := make(map[domain.ResourceType]bool)
+	var patterns []domain.PropagationPattern
+	for _, scope := range l.scopes(alert) {
+		for key, p := range l.patterns {
+			if key.host != scope.host || key.service != scope.service || key.from != from || seen[key.to] {
+				continue
+			}
+			seen[key.to] = true
+			patterns = append(patterns, p)
+		}
+	}
+
+	sort.Slice(patterns, func(i, j int) bool { return patterns[i].To < patterns[j].To })
+	return patterns
+}
+
+// scopes lists the pattern scopes that apply to the alert, most specific first
+func (l *PropagationLearner) scopes(alert domain.Alert) []propagationKey {
+	scopes := []propagationKey{{host: alert.Host}}
+	if service := l.serviceOf(alert); service != "" {
+		scopes = append(scopes, propagationKey{service: service})
+	}
+	return append(scopes, propagationKey{})
+}
+
+// LoadFrom restores persisted patterns from the store
+func (l *PropagationLearner) LoadFrom(ctx context.Context, store ports.PropagationPatternStore) error {
+	patterns, err := store.GetPropagationPatterns(ctx)
+	if err != nil {
+		return err
+	}
+	l.Load(patterns)
+	return nil
+}
+
+// Run relearns the patterns from the alert history every interval until ctx is cancelled,
+// persisting them to store if it is non-nil
+func (l *PropagationLearner) Run(ctx context.Context, history ports.AlertHistory, store ports.PropagationPatternStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		l.learnOnce(ctx, history, store)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (l *PropagationLearner) learnOnce(ctx context.Context, history ports.AlertHistory, store ports.PropagationPatternStore) {
+	alerts, err := history.GetAlerts(ctx)
+	if err != nil {
+		log.Printf("⚠️  Propagation learning failed to load alerts: %v", err)
+		return
+	}
+	if len(alerts) == 0 {
+		// Keep previously learned (or persisted) patterns until there is history to learn from
+		return
+	}
+
+	patterns := l.Learn(alerts, time.Now())
+	log.Printf("🧠 Learned %d propagation patterns from %d alerts", len(patterns), len(alerts))
+
+	if store != nil {
+		if err := store.SavePropagationPatterns(ctx, patterns); err != nil {
+			log.Printf("⚠️  Failed to persist propagation patterns: %v", err)
+		}
+	}
+}
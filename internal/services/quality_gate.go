@@ -0,0 +1,57 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QualityGate validates analysis output before it is sent to humans
+type QualityGate struct {
+	MinConfidence    int // Minimum root cause confidence (0-100)
+	MinSummaryLength int // Minimum length of the "what happened" narrative
+	MaxSummaryLength int // Maximum length of the "what happened" narrative
+}
+
+// QualityResult reports whether analysis output passed the gate
+type QualityResult struct {
+	Passed   bool
+	Failures []string
+}
+
+// NewQualityGate creates a quality gate with the given thresholds
+func NewQualityGate(minConfidence, minSummaryLength, maxSummaryLength int) *QualityGate {
+	return &QualityGate{
+		MinConfidence:    minConfidence,
+		MinSummaryLength: minSummaryLength,
+		MaxSummaryLength: maxSummaryLength,
+	}
+}
+
+// Check validates an incident intelligence package
+func (g *QualityGate) Check(intelligence IncidentIntelligence) QualityResult {
+	failures := []string{}
+
+	if intelligence.RootCause.Alert == nil || strings.TrimSpace(intelligence.RootCause.Alert.Name) == "" {
+		failures = append(failures, "root cause is empty")
+	} else if intelligence.RootCause.ConfidenceScore < g.MinConfidence {
+		failures = append(failures, fmt.Sprintf("root cause confidence %d%% is below floor of %d%%",
+			intelligence.RootCause.ConfidenceScore, g.MinConfidence))
+	}
+
+	summary := strings.TrimSpace(intelligence.WhatHappened)
+	if len(summary) < g.MinSummaryLength {
+		failures = append(failures, fmt.Sprintf("summary is too short (%d chars)", len(summary)))
+	}
+	if g.MaxSummaryLength > 0 && len(summary) > g.MaxSummaryLength {
+		failures = append(failures, fmt.Sprintf("summary is too long (%d chars)", len(summary)))
+	}
+
+	if strings.TrimSpace(intelligence.BlastRadius.SimpleSummary) == "" {
+		failures = append(failures, "impact summary is empty")
+	}
+
+	return QualityResult{
+		Passed:   len(failures) == 0,
+		Failures: failures,
+	}
+}
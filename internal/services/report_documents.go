@@ -0,0 +1,507 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/report"
+)
+
+// This file turns analysis results into format-agnostic report documents.
+// Render them with any report.Renderer (text, markdown, html, slack).
+
+// StoryDocument builds the incident story report
+func StoryDocument(story IncidentStory) report.Document {
+	return report.Document{
+		Title: "INCIDENT STORY",
+		Sections: []report.Section{
+			{Icon: "📝", Heading: "SUMMARY", Blocks: []report.Block{report.Paragraph{Text: story.Summary}}},
+			{Icon: "⏱️", Heading: "TIMELINE", Blocks: []report.Block{report.Paragraph{Text: story.Timeline}}},
+			{Icon: "🎯", Heading: "ROOT CAUSE", Blocks: []report.Block{report.Paragraph{Text: story.RootCause}}},
+			{Icon: "💥", Heading: "IMPACT", Blocks: []report.Block{report.Paragraph{Text: story.Impact}}},
+			{Icon: "🔧", Heading: "FIX", Blocks: []report.Block{
+				report.List{Title: "IMMEDIATE (do this now)", Ordered: true, Items: story.Fix.ImmediateActions},
+				report.List{Title: "SHORT-TERM (today)", Ordered: true, Items: story.Fix.ShortTermActions},
+				report.List{Title: "LONG-TERM (prevention)", Ordered: true, Items: story.Fix.LongTermActions},
+			}},
+		},
+		Footer: fmt.Sprintf("Generated: %s", story.GeneratedAt.Format(DateTimeLayout)),
+	}
+}
+
+// ExplanationDocument builds the SRE incident analysis report
+func ExplanationDocument(exp IncidentExplanation) report.Document {
+	rootCause := []report.Block{
+		report.Paragraph{Text: fmt.Sprintf("Confidence: %s (%d/100)", exp.ConfidenceLevel, exp.RootCause.ConfidenceScore)},
+		report.List{Title: "Primary Root Cause", Items: []string{
+			fmt.Sprintf("Alert: %s", exp.RootCause.Alert.Name),
+			fmt.Sprintf("Resource: %s", exp.RootCause.Alert.ResourceType),
+			fmt.Sprintf("Host: %s", exp.RootCause.Alert.Host),
+			fmt.Sprintf("Value: %.2f", exp.RootCause.Alert.Value),
+		}},
+	}
+	if len(exp.RootCause.Evidence) > 0 {
+		rootCause = append(rootCause, report.List{Title: "Evidence", Marker: "✓", Items: exp.RootCause.Evidence})
+	}
+	if alternatives := alternativeCauseItems(exp.AlternativeCauses, nil); len(alternatives) > 0 {
+		rootCause = append(rootCause, report.List{Title: "Alternative Causes", Ordered: true, Items: alternatives})
+	}
+
+	return report.Document{
+		Title: "SRE INCIDENT ANALYSIS REPORT",
+		Sections: []report.Section{
+			{Icon: "📋", Heading: "WHAT HAPPENED", Blocks: []report.Block{report.Paragraph{Text: exp.WhatHappened}}},
+			{Icon: "🔍", Heading: "WHY IT HAPPENED", Blocks: []report.Block{report.Paragraph{Text: exp.WhyItHappened}}},
+			{Icon: "🔴", Heading: "WHAT BROKE FIRST", Blocks: []report.Block{report.Paragraph{Text: exp.WhatBrokeFirst}}},
+			{Icon: "💥", Heading: "BLAST RADIUS", Blocks: []report.Block{report.Fields{
+				{Label: "Impact", Value: exp.BlastRadius.ImpactDescription},
+				{Label: "Affected Hosts", Value: fmt.Sprintf("%d (%v)", len(exp.BlastRadius.AffectedHosts), exp.BlastRadius.AffectedHosts)},
+				{Label: "Affected Resources", Value: fmt.Sprintf("%v", exp.BlastRadius.AffectedResources)},
+				{Label: "Total Alerts", Value: fmt.Sprintf("%d (Critical: %d)", exp.BlastRadius.TotalAlerts, exp.BlastRadius.CriticalAlerts)},
+				{Label: "Cascade Depth", Value: fmt.Sprintf("%d levels", exp.BlastRadius.CascadeDepth)},
+				{Label: "Duration", Value: exp.BlastRadius.Duration.Round(time.Second).String()},
+			}}},
+			{Icon: "🔧", Heading: "SUGGESTED FIX", Blocks: []report.Block{report.Paragraph{Text: exp.SuggestedFix}}},
+			{Icon: "🎯", Heading: "ROOT CAUSE ANALYSIS", Blocks: rootCause},
+		},
+	}
+}
+
+// ActionableFixDocument builds the fix playbook report
+func ActionableFixDocument(fix ActionableFix) report.Document {
+	return report.Document{
+		Title:    "ACTIONABLE FIX PLAYBOOK",
+		Sections: fixSections(fix),
+	}
+}
+
+// ExecutiveSummaryDocument builds the concise summary for leadership
+func ExecutiveSummaryDocument(intelligence IncidentIntelligence) report.Document {
+	return report.Document{
+		Title: "EXECUTIVE INCIDENT SUMMARY",
+		Sections: []report.Section{
+			{Icon: "📊", Heading: "INCIDENT OVERVIEW", Blocks: []report.Block{report.Fields{
+				{Label: "Duration", Value: intelligence.IncidentDuration.Round(time.Second).String()},
+				{Label: "Total Alerts", Value: fmt.Sprintf("%d (%d critical)", intelligence.TotalAlerts, intelligence.BlastRadius.CriticalAlerts)},
+				{Label: "Impact Score", Value: fmt.Sprintf("%d/100 (%s)", intelligence.BlastRadius.ImpactScore, getSeverityLabel(intelligence.BlastRadius.ImpactScore))},
+				{Label: "Recovery Time", Value: intelligence.BlastRadius.RecoveryEstimate},
+			}}},
+			{Icon: "🎯", Heading: fmt.Sprintf("ROOT CAUSE (Confidence: %s)", intelligence.ConfidenceLevel), Blocks: []report.Block{
+				report.Paragraph{Text: fmt.Sprintf("%s on %s", intelligence.RootCause.Alert.Name, intelligence.RootCause.Alert.Host)},
+				report.Fields{
+					{Label: "Value", Value: fmt.Sprintf("%.2f", intelligence.RootCause.Alert.Value)},
+					{Label: "Time", Value: intelligence.RootCause.Alert.OccurredAt.UTC().Format(ClockLayout)},
+				},
+			}},
+			{Icon: "💥", Heading: "BUSINESS IMPACT", Blocks: []report.Block{
+				report.Paragraph{Text: intelligence.BlastRadius.SimpleSummary},
+				report.List{Title: "Affected", Items: []string{
+					fmt.Sprintf("%d hosts", len(intelligence.BlastRadius.AffectedHosts)),
+					fmt.Sprintf("%d resource types", len(intelligence.BlastRadius.AffectedResources)),
+					fmt.Sprintf("Cascade depth: %d levels", intelligence.BlastRadius.CascadeDepth),
+				}},
+			}},
+			{Icon: "🔧", Heading: "STATUS & NEXT STEPS", Blocks: []report.Block{
+				report.Fields{
+					{Label: "Fix Complexity", Value: intelligence.ActionableFixes.FixComplexity},
+					{Label: "Est. Resolution", Value: intelligence.ActionableFixes.EstimatedTimeToResolve},
+				},
+				report.List{Title: "Immediate Actions Required", Ordered: true, Items: firstN(intelligence.ActionableFixes.ImmediateFix, 3)},
+			}},
+		},
+	}
+}
+
+// TechnicalReportDocument builds the detailed report for on-call engineers
+func TechnicalReportDocument(intelligence IncidentIntelligence) report.Document {
+	rootCause := []report.Block{report.Fields{
+		{Label: "Primary", Value: intelligence.RootCause.Alert.Name},
+		{Label: "Confidence", Value: fmt.Sprintf("%d/100 (%s)", intelligence.RootCause.ConfidenceScore, intelligence.ConfidenceLevel)},
+		{Label: "Reasoning", Value: intelligence.RootCause.Reasoning},
+	}}
+	if len(intelligence.RootCause.Evidence) > 0 {
+		rootCause = append(rootCause, report.List{Title: "Evidence", Marker: "✓", Items: intelligence.RootCause.Evidence})
+	}
+	if alternatives := alternativeCauseItems(intelligence.AlternativeCauses, &intelligence.RootCause); len(alternatives) > 0 {
+		rootCause = append(rootCause, report.List{Title: "Alternative Causes Evaluated", Ordered: true, Items: alternatives})
+	}
+
+	sections := []report.Section{
+		{Icon: "📅", Heading: "INCIDENT TIMELINE", Blocks: []report.Block{report.Fields{
+			{Label: "Start", Value: intelligence.RootCause.Alert.OccurredAt.Format(time.RFC3339)},
+			{Label: "Duration", Value: intelligence.IncidentDuration.Round(time.Second).String()},
+			{Label: "Analyzed", Value: intelligence.AnalyzedAt.Format(time.RFC3339)},
+		}}},
+		{Icon: "📋", Heading: "WHAT HAPPENED", Blocks: []report.Block{report.Paragraph{Text: intelligence.WhatHappened}}},
+		{Icon: "🎯", Heading: "ROOT CAUSE ANALYSIS", Blocks: rootCause},
+		{Icon: "💥", Heading: "BLAST RADIUS", Blocks: []report.Block{
+			report.Fields{
+				{Label: "Impact Score", Value: fmt.Sprintf("%d/100", intelligence.BlastRadius.ImpactScore)},
+				{Label: "Summary", Value: intelligence.BlastRadius.SimpleSummary},
+			},
+			report.List{Title: "Direct Impact", Items: []string{
+				fmt.Sprintf("%d components", len(intelligence.BlastRadius.DirectlyAffected)),
+			}},
+			report.List{Title: "Indirect Impact (Cascade)", Items: []string{
+				fmt.Sprintf("%d components (%d cascade levels)", len(intelligence.BlastRadius.IndirectlyAffected), intelligence.BlastRadius.CascadeDepth),
+			}},
+		}},
+	}
+
+	return report.Document{
+		Title:    "TECHNICAL INCIDENT ANALYSIS REPORT",
+		Sections: append(sections, fixSections(intelligence.ActionableFixes)...),
+	}
+}
+
+// IncidentAlertDocument builds the short incident notification used for chat channels
+func IncidentAlertDocument(intelligence IncidentIntelligence) report.Document {
+	return report.Document{
+		Title: getSeverityEmoji(intelligence.BlastRadius.ImpactScore) + " INCIDENT ALERT",
+		Sections: []report.Section{
+			{Blocks: []report.Block{report.Fields{
+				{Label: "Root Cause", Value: fmt.Sprintf("%s (Confidence: %d%%)", intelligence.RootCause.Alert.Name, intelligence.RootCause.ConfidenceScore)},
+				{Label: "Host", Value: intelligence.RootCause.Alert.Host},
+				{Label: "Impact", Value: fmt.Sprintf("%s (%d/100)", getSeverityLabel(intelligence.BlastRadius.ImpactScore), intelligence.BlastRadius.ImpactScore)},
+				{Label: "Duration", Value: intelligence.IncidentDuration.Round(time.Second).String()},
+			}}},
+			{Heading: "What Happened", Blocks: []report.Block{report.Paragraph{Text: intelligence.BlastRadius.SimpleSummary}}},
+			{Heading: "Immediate Actions", Blocks: []report.Block{
+				report.List{Ordered: true, Items: firstN(intelligence.ActionableFixes.ImmediateFix, 3)},
+			}},
+			{Blocks: []report.Block{report.Fields{
+				{Label: "Est. Time to Resolve", Value: intelligence.ActionableFixes.EstimatedTimeToResolve},
+			}}},
+		},
+	}
+}
+
+// TimelineDocument builds the enhanced timeline report
+func TimelineDocument(timeline TimelineWithInsights) report.Document {
+	items := make([]string, len(timeline.Events))
+	for i, event := range timeline.Events {
+		item := fmt.Sprintf("[%s] (+%v) %s - %s",
+			event.Type,
+			event.Timestamp.Sub(timeline.StartTime),
+			event.Message,
+			event.Severity,
+		)
+		if event.IsCascadePoint {
+			item += " (CASCADE POINT: multiple downstream alerts triggered)"
+		}
+		if timeline.RootCauseEventIndex != nil && *timeline.RootCauseEventIndex == i {
+			item += " (ROOT CAUSE: this is likely where the incident started)"
+		}
+		items[i] = item
+	}
+
+	return report.Document{
+		Title: "INCIDENT TIMELINE",
+		Sections: []report.Section{
+			{Blocks: []report.Block{report.Fields{
+				{Label: "Duration", Value: timeline.Duration.String()},
+				{Label: "Start", Value: timeline.StartTime.UTC().Format(ClockLayout)},
+				{Label: "End", Value: timeline.EndTime.UTC().Format(ClockLayout)},
+			}}},
+			{Heading: "Events", Blocks: []report.Block{report.List{Ordered: true, Items: items}}},
+		},
+	}
+}
+
+// IncidentListDocument builds a short listing of incidents, one line each
+func IncidentListDocument(title string, incidents []domain.Incident, now time.Time) report.Document {
+	if len(incidents) == 0 {
+		return report.Document{
+			Title:    title,
+			Sections: []report.Section{{Blocks: []report.Block{report.Paragraph{Text: "✅ No open incidents"}}}},
+		}
+	}
+
+	items := make([]string, len(incidents))
+	for i, incident := range incidents {
+		items[i] = fmt.Sprintf("%s %s — %s, %s risk, %s (ID: %s)",
+			incidentStatusEmoji(incident.Status), incident.Title, incident.Status, incident.RiskLevel(),
+			incidentAge(incident, now), incident.ID)
+	}
+
+	return report.Document{
+		Title:    title,
+		Sections: []report.Section{{Blocks: []report.Block{report.List{Items: items}}}},
+	}
+}
+
+// actionItemLine describes a recorded action item, e.g. "Add disk alerts — alice, due
+// Oct 20, overdue (incident 01J..., opened Oct 13)"
+func actionItemLine(item ActionItem, now time.Time, location *time.Location) string {
+	owner := item.Owner
+	if owner == "" {
+		owner = "unassigned"
+	}
+	line := fmt.Sprintf("%s — %s", item.Description, owner)
+	if item.Status == domain.ActionItemInProgress {
+		line += ", in progress"
+	}
+	if item.DueAt != nil {
+		line += ", due " + inZone(*item.DueAt, location).Format("Jan 2")
+		if now.After(*item.DueAt) {
+			line += ", ⚠️ overdue"
+		}
+	}
+	return fmt.Sprintf("%s (incident %s, opened %s)", line, item.IncidentID, inZone(item.OpenedAt, location).Format("Jan 2"))
+}
+
+// IncidentDetailDocument builds the overview of one incident with its latest events.
+// assignee and ack are optional.
+func IncidentDetailDocument(incident domain.Incident, assignee string, ack *Acknowledgement, now time.Time) report.Document {
+	risk := incident.RiskAssessment()
+	fields := report.Fields{
+		{Label: "ID", Value: incident.ID},
+		{Label: "Status", Value: string(incident.Status)},
+		{Label: "Risk", Value: fmt.Sprintf("%s (%s)", risk.Level, strings.Join(risk.Reasons, "; "))},
+		{Label: "Started", Value: incident.StartedAt.Format(time.RFC3339)},
+		{Label: "Duration", Value: incidentAge(incident, now)},
+		{Label: "Hosts", Value: strings.Join(incident.Hosts(), ", ")},
+		{Label: "Events", Value: fmt.Sprintf("%d", len(incident.Events))},
+	}
+	if assignee != "" {
+		fields = append(fields, report.Field{Label: "Assignee", Value: assignee})
+	}
+	if ack != nil {
+		fields = append(fields, report.Field{Label: "Acknowledged", Value: fmt.Sprintf("by %s at %s",
+			ack.By, ack.AcknowledgedAt.UTC().Format(ClockLayout))})
+	}
+
+	events := incident.Events
+	if len(events) > 5 {
+		events = events[len(events)-5:]
+	}
+	items := make([]string, len(events))
+	for i, event := range events {
+		items[i] = fmt.Sprintf("[%s] %s %s on %s (%s)",
+			event.OccurredAt.UTC().Format(ClockLayout), event.Name, event.Status, event.Host, event.ResourceType)
+	}
+
+	return report.Document{
+		Title: incidentStatusEmoji(incident.Status) + " " + incident.Title,
+		Sections: []report.Section{
+			{Blocks: []report.Block{fields}},
+			{Heading: "Latest Events", Blocks: []report.Block{report.List{Items: items}}},
+		},
+	}
+}
+
+func incidentStatusEmoji(status domain.AlertStatus) string {
+	switch status {
+	case domain.StatusCritical:
+		return "🔴"
+	case domain.StatusWarning:
+		return "🟡"
+	default:
+		return "🟢"
+	}
+}
+
+// incidentAge describes how long the incident has lasted, e.g. "12m ongoing" or "1h5m"
+func incidentAge(incident domain.Incident, now time.Time) string {
+	duration := incident.Duration(now).Round(time.Second).String()
+	if incident.Duration(now) >= time.Minute {
+		duration = strings.TrimSuffix(incident.Duration(now).Round(time.Minute).String(), "0s")
+	}
+	if incident.ResolvedAt == nil {
+		return duration + " ongoing"
+	}
+	return duration
+}
+
+// DigestDocument builds the periodic incident digest, with dates in the digest's time zone
+func DigestDocument(digest Digest) report.Document {
+	period := fmt.Sprintf("%s – %s",
+		inZone(digest.From, digest.Location).Format("Jan 2"), inZone(digest.To, digest.Location).Format("Jan 2, 2006"))
+
+	mttr := "n/a"
+	if digest.Resolved > 0 {
+		mttr = digest.MTTR.Round(time.Minute).String()
+	}
+
+	rankings := func(counts []DigestCount, unit string) []string {
+		items := make([]string, len(counts))
+		for i, c := range counts {
+			items[i] = fmt.Sprintf("%s — %d %s", c.Name, c.Count, unit)
+		}
+		return items
+	}
+
+	longest := make([]string, len(digest.Longest))
+	for i, incident := range digest.Longest {
+		longest[i] = fmt.Sprintf("%s — %s (ID: %s)", incident.Title, incidentAge(incident, digest.To), incident.ID)
+	}
+
+	sections := []report.Section{
+		{Icon: "📊", Heading: "Overview", Blocks: []report.Block{report.Fields{
+			{Label: "Incidents", Value: fmt.Sprintf("%d (%d critical)", digest.Incidents, digest.Critical)},
+			{Label: "Resolved", Value: fmt.Sprintf("%d", digest.Resolved)},
+			{Label: "Still Active", Value: fmt.Sprintf("%d", digest.Active)},
+			{Label: "MTTR", Value: mttr},
+		}}},
+	}
+	if digest.Incidents == 0 {
+		sections = append(sections, report.Section{Blocks: []report.Block{
+			report.Paragraph{Text: "✅ No incidents in this period."},
+		}})
+	} else {
+		sections = append(sections,
+			report.Section{Icon: "🎯", Heading: "Top Root Causes", Blocks: []report.Block{
+				report.List{Ordered: true, Items: rankings(digest.TopRootCauses, "incidents")},
+			}},
+			report.Section{Icon: "📢", Heading: "Noisiest Hosts", Blocks: []report.Block{
+				report.List{Ordered: true, Items: rankings(digest.NoisiestHosts, "alerts")},
+			}},
+			report.Section{Icon: "⏱️", Heading: "Longest Incidents", Blocks: []report.Block{
+				report.List{Ordered: true, Items: longest},
+			}},
+		)
+	}
+
+	return report.Document{
+		Title:    "Incident Digest: " + period,
+		Sections: sections,
+		Footer:   "Generated by IncidentTeller at " + inZone(time.Now(), digest.Location).Format(time.RFC1123Z),
+	}
+}
+
+// WeeklyReportDocument renders the weekly operations report, with dates in the report's
+// time zone
+func WeeklyReportDocument(weekly WeeklyReport) report.Document {
+	period := fmt.Sprintf("%s – %s",
+		inZone(weekly.From, weekly.Location).Format("Jan 2"), inZone(weekly.To, weekly.Location).Format("Jan 2, 2006"))
+
+	mttr := "n/a"
+	if weekly.Resolved > 0 {
+		mttr = weekly.MTTR.Round(time.Minute).String()
+		if len(weekly.MTTRTrend) > 1 {
+			if previous := weekly.MTTRTrend[len(weekly.MTTRTrend)-2]; previous.Resolved > 0 {
+				change := (weekly.MTTR - previous.MTTR).Round(time.Minute)
+				switch {
+				case change > 0:
+					mttr += fmt.Sprintf(" (%s slower than the previous week)", change)
+				case change < 0:
+					mttr += fmt.Sprintf(" (%s faster than the previous week)", -change)
+				}
+			}
+		}
+	}
+
+	severities := make(report.Fields, len(weekly.BySeverity))
+	for i, c := range weekly.BySeverity {
+		severities[i] = report.Field{Label: strings.ToUpper(c.Name[:1]) + c.Name[1:], Value: fmt.Sprintf("%d", c.Count)}
+	}
+
+	trend := make([]string, len(weekly.MTTRTrend))
+	for i, week := range weekly.MTTRTrend {
+		value := "n/a"
+		if week.Resolved > 0 {
+			value = week.MTTR.Round(time.Minute).String()
+		}
+		trend[i] = fmt.Sprintf("Week of %s: %s (%d of %d incidents resolved)",
+			inZone(week.WeekStart, weekly.Location).Format("Jan 2"), value, week.Resolved, week.Incidents)
+	}
+
+	rankings := func(counts []DigestCount, unit string) []report.Block {
+		if len(counts) == 0 {
+			return []report.Block{report.Paragraph{Text: "None this week."}}
+		}
+		items := make([]string, len(counts))
+		for i, c := range counts {
+			items[i] = fmt.Sprintf("%s — %d %s", c.Name, c.Count, unit)
+		}
+		return []report.Block{report.List{Ordered: true, Items: items}}
+	}
+
+	actionItems := []report.Block{report.Paragraph{Text: "✅ No open action items."}}
+	if len(weekly.ActionItems) > 0 {
+		items := make([]string, len(weekly.ActionItems))
+		for i, item := range weekly.ActionItems {
+			if item.Tracker == "" {
+				items[i] = actionItemLine(item, weekly.To, weekly.Location)
+				continue
+			}
+			items[i] = fmt.Sprintf("%s %s — %s (incident %s, opened %s)",
+				item.Tracker, item.Key, item.Title, item.IncidentID, inZone(item.OpenedAt, weekly.Location).Format("Jan 2"))
+			if item.URL != "" {
+				items[i] += " " + item.URL
+			}
+		}
+		actionItems = []report.Block{report.List{Items: items}}
+	}
+
+	return report.Document{
+		Title: "Weekly Reliability Report: " + period,
+		Sections: []report.Section{
+			{Icon: "📊", Heading: "Overview", Blocks: []report.Block{report.Fields{
+				{Label: "Incidents", Value: fmt.Sprintf("%d", weekly.Incidents)},
+				{Label: "Resolved", Value: fmt.Sprintf("%d", weekly.Resolved)},
+				{Label: "MTTR", Value: mttr},
+			}}},
+			{Icon: "🚨", Heading: "Incidents by Severity", Blocks: []report.Block{severities}},
+			{Icon: "📈", Heading: "MTTR Trend", Blocks: []report.Block{report.List{Items: trend}}},
+			{Icon: "🎯", Heading: "Top Root-Cause Resource Types", Blocks: rankings(weekly.TopRootCauses, "incidents")},
+			{Icon: "📢", Heading: "Noisiest Hosts", Blocks: rankings(weekly.NoisiestHosts, "alerts")},
+			{Icon: "📝", Heading: "Open Action Items", Blocks: actionItems},
+		},
+		Footer: "Generated by IncidentTeller at " + inZone(time.Now(), weekly.Location).Format(time.RFC1123Z),
+	}
+}
+
+// fixSections renders the three remediation horizons of a fix playbook
+func fixSections(fix ActionableFix) []report.Section {
+	fields := report.Fields{
+		{Label: "Root Cause", Value: string(fix.RootCauseType)},
+		{Label: "Complexity", Value: fix.FixComplexity},
+		{Label: "Est. Time to Resolve", Value: fix.EstimatedTimeToResolve},
+	}
+	if fix.Playbook != "" {
+		fields = append(fields, report.Field{Label: "Playbook", Value: fix.Playbook})
+	}
+	if fix.RunbookURL != "" {
+		fields = append(fields, report.Field{Label: "Runbook", Value: fix.RunbookURL})
+	}
+
+	return []report.Section{
+		{Blocks: []report.Block{fields}},
+		{Icon: "🔴", Heading: "IMMEDIATE FIX (NOW - within 5 minutes)", Blocks: []report.Block{
+			report.List{Ordered: true, Items: fix.ImmediateFix},
+		}},
+		{Icon: "🟡", Heading: "SHORT-TERM FIX (TODAY - within 8 hours)", Blocks: []report.Block{
+			report.List{Ordered: true, Items: fix.ShortTermFix},
+		}},
+		{Icon: "🟢", Heading: "LONG-TERM PREVENTION (ONGOING)", Blocks: []report.Block{
+			report.List{Ordered: true, Items: fix.LongTermFix},
+		}},
+	}
+}
+
+// alternativeCauseItems describes the top 3 alternative causes. When primary is given,
+// the confidence gap to the primary root cause is included.
+func alternativeCauseItems(alternatives []RootCauseCandidate, primary *RootCauseCandidate) []string {
+	var items []string
+	for _, alt := range firstN(alternatives, 3) {
+		if primary != nil {
+			items = append(items, fmt.Sprintf("%s (Confidence: %d, Gap: -%d)",
+				alt.Alert.Name, alt.ConfidenceScore, primary.ConfidenceScore-alt.ConfidenceScore))
+		} else {
+			items = append(items, fmt.Sprintf("%s (%s) - Confidence: %d%%",
+				alt.Alert.Name, alt.Alert.ResourceType, alt.ConfidenceScore))
+		}
+	}
+	return items
+}
+
+// firstN returns at most n leading elements of s
+func firstN[T any](s []T, n int) []T {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
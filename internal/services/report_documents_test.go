@@ -0,0 +1,64 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/report"
+)
+
+func TestIncidentDetailDocument_RiskReasons(t *testing.T) {
+	t.Cleanup(func() { domain.SetRiskPolicy(domain.DefaultRiskPolicy()) })
+
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	incident := domain.Incident{ID: "inc-1", Status: domain.StatusWarning, StartedAt: now, Events: []domain.Alert{
+		{ID: "a1", Host: "db-1", ResourceType: domain.ResourceDatabase, Status: domain.StatusWarning, Value: 97, OccurredAt: now},
+		{ID: "a2", Host: "db-1", ResourceType: domain.ResourceDisk, Status: domain.StatusWarning, Value: 60, OccurredAt: now.Add(time.Minute)},
+	}}
+
+	if got := riskField(t, IncidentDetailDocument(incident, "", nil, now)); got !=
+		"high (2 resource types reach the high threshold of 2)" {
+		t.Errorf("unexpected default risk %q", got)
+	}
+
+	// Database alerts above 95 count as critical, and twice as much as others
+	policy := domain.DefaultRiskPolicy()
+	policy.Resources = map[domain.ResourceType]domain.ResourceRisk{domain.ResourceDatabase: {Weight: 2, HighValue: 95}}
+	domain.SetRiskPolicy(policy)
+	if got := riskField(t, IncidentDetailDocument(incident, "", nil, now)); got !=
+		"high (2 critical alerts reach the high threshold of 2; 2 resource types reach the high threshold of 2)" {
+		t.Errorf("unexpected risk with the database policy %q", got)
+	}
+
+	policy.Critical.CriticalAlerts = 2
+	domain.SetRiskPolicy(policy)
+	if got := riskField(t, IncidentDetailDocument(incident, "", nil, now)); got !=
+		"critical (2 critical alerts reach the critical threshold of 2)" {
+		t.Errorf("unexpected risk with a lower critical threshold %q", got)
+	}
+
+	// The story calls the first alert a peak by the configured value, not the default 90
+	incident.Events[0].Value = 92
+	if story := NewIncidentTeller().TellStory(incident.Events); strings.Contains(story.Timeline, "hitting 92.0%") {
+		t.Errorf("expected 92 below the database high value not to read as a peak, got %q", story.Timeline)
+	}
+}
+
+func riskField(t *testing.T, doc report.Document) string {
+	t.Helper()
+	for _, section := range doc.Sections {
+		for _, block := range section.Blocks {
+			if fields, ok := block.(report.Fields); ok {
+				for _, field := range fields {
+					if field.Label == "Risk" {
+						return field.Value
+					}
+				}
+			}
+		}
+	}
+	t.Fatal("no risk field")
+	return ""
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/topology"
+)
+
+// ServiceSummary describes the incidents of a topology service within a period
+type ServiceSummary struct {
+	Service         topology.Service
+	Health          string // "healthy", "warning", "critical" from its open incidents
+	Incidents       int    // Incidents open at some point of the period
+	ActiveIncidents int
+	Outages         int           // Incidents with a critical alert on the service's hosts
+	Downtime        time.Duration // Time within the period with an outage open
+	Availability    float64       // Percent of the period without an outage
+	MTTR            time.Duration // Of the incidents resolved within the period
+	LastIncidentAt  *time.Time
+}
+
+// BuildServiceInventory summarizes the incidents of every service of the topology within
+// [from, to]. Like on the status page, only incidents with a critical alert count as
+// downtime, and only if the alert is on one of the service's hosts.
+func BuildServiceInventory(topo *topology.Topology, incidents []domain.Incident, from, to time.Time) []ServiceSummary {
+	byService := make(map[string][]domain.Incident)
+	for _, incident := range incidents {
+		if !incidentOverlaps(incident, from, to) {
+			continue
+		}
+		for _, name := range IncidentServices(topo, incident) {
+			byService[name] = append(byService[name], incident)
+		}
+	}
+
+	summaries := []ServiceSummary{}
+	for _, svc := range topo.Services() {
+		summaries = append(summaries, summarizeService(svc, byService[svc.Name], from, to))
+	}
+	return summaries
+}
+
+// ServiceIncidents returns the incidents that touched a service within [from, to],
+// newest first
+func ServiceIncidents(topo *topology.Topology, name string, incidents []domain.Incident, from, to time.Time) []domain.Incident {
+	matched := []domain.Incident{}
+	for _, incident := range incidents {
+		if !incidentOverlaps(incident, from, to) {
+			continue
+		}
+		for _, svc := range IncidentServices(topo, incident) {
+			if svc == name {
+				matched = append(matched, incident)
+				break
+			}
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].StartedAt.After(matched[j].StartedAt)
+	})
+	return matched
+}
+
+// IncidentServices returns the distinct topology services whose hosts are involved in an
+// incident
+func IncidentServices(topo *topology.Topology, incident domain.Incident) []string {
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, host := range IncidentHosts(incident) {
+		if name, ok := topo.ServiceForHost(host); ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// SummarizeService summarizes the incidents of one service within [from, to]
+func SummarizeService(topo *topology.Topology, name string, incidents []domain.Incident, from, to time.Time) (ServiceSummary, bool) {
+	svc, ok := topo.Service(name)
+	if !ok {
+		return ServiceSummary{}, false
+	}
+	return summarizeService(svc, ServiceIncidents(topo, name, incidents, from, to), from, to), true
+}
+
+func summarizeService(svc topology.Service, incidents []domain.Incident, from, to time.Time) ServiceSummary {
+	summary := ServiceSummary{Service: svc, Health: "healthy", Incidents: len(incidents), Availability: 100}
+	hosts := make(map[string]bool, len(svc.Hosts))
+	for _, host := range svc.Hosts {
+		hosts[host] = true
+	}
+
+	type span struct{ start, end time.Time }
+	var outages []span
+	var repair time.Duration
+	resolved := 0
+	for _, incident := range incidents {
+		if summary.LastIncidentAt == nil || incident.StartedAt.After(*summary.LastIncidentAt) {
+			started := incident.StartedAt
+			summary.LastIncidentAt = &started
+		}
+
+		end := to
+		if incident.ResolvedAt != nil {
+			if !incident.ResolvedAt.After(to) {
+				end = *incident.ResolvedAt
+				repair += incident.ResolvedAt.Sub(incident.StartedAt)
+				resolved++
+			}
+		} else {
+			summary.ActiveIncidents++
+			switch incident.Status {
+			case domain.StatusCritical:
+				summary.Health = "critical"
+			case domain.StatusWarning:
+				if summary.Health != "critical" {
+					summary.Health = "warning"
+				}
+			}
+		}
+
+		for _, event := range incident.Events {
+			if event.Status == domain.StatusCritical && hosts[event.Host] {
+				summary.Outages++
+				start := incident.StartedAt
+				if start.Before(from) {
+					start = from
+				}
+				outages = append(outages, span{start, end})
+				break
+			}
+		}
+	}
+	if resolved > 0 {
+		summary.MTTR = repair / time.Duration(resolved)
+	}
+
+	// Overlapping outages count once
+	sort.Slice(outages, func(i, j int) bool { return outages[i].start.Before(outages[j].start) })
+	var covered time.Time
+	for _, outage := range outages {
+		if outage.start.Before(covered) {
+			outage.start = covered
+		}
+		if outage.end.After(outage.start) {
+			summary.Downtime += outage.end.Sub(outage.start)
+			covered = outage.end
+		}
+	}
+	if period := to.Sub(from); period > 0 {
+		summary.Availability = 100 * (1 - float64(summary.Downtime)/float64(period))
+	}
+	return summary
+}
+
+// incidentOverlaps reports whether an incident was open at some point of [from, to]
+func incidentOverlaps(incident domain.Incident, from, to time.Time) bool {
+	if incident.StartedAt.After(to) {
+		return false
+	}
+	return incident.ResolvedAt == nil || !incident.ResolvedAt.Before(from)
+}
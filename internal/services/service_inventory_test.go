@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/topology"
+)
+
+func TestBuildServiceInventory(t *testing.T) {
+	topo, err := topology.New([]topology.Service{
+		{Name: "checkout", Hosts: []string{"web-01", "web-02"}, DependsOn: []string{"postgres"}},
+		{Name: "postgres", Hosts: []string{"db-01"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(100 * time.Hour)
+	at := func(hours float64) time.Time { return from.Add(time.Duration(hours * float64(time.Hour))) }
+	resolved := func(hours float64) *time.Time { t := at(hours); return &t }
+	incidents := []domain.Incident{
+		// Started before the period: only the hour within it is downtime
+		{ID: "outage-1", StartedAt: at(-1), ResolvedAt: resolved(1), Events: []domain.Alert{
+			{Host: "web-01", Status: domain.StatusCritical}, {Host: "db-01", Status: domain.StatusWarning},
+		}},
+		// Overlaps the next outage, which counts once
+		{ID: "outage-2", StartedAt: at(10), ResolvedAt: resolved(12), Events: []domain.Alert{{Host: "web-02", Status: domain.StatusCritical}}},
+		{ID: "outage-3", StartedAt: at(11), ResolvedAt: resolved(13), Events: []domain.Alert{{Host: "web-01", Status: domain.StatusCritical}}},
+		{ID: "open", Status: domain.StatusWarning, StartedAt: at(99), Events: []domain.Alert{{Host: "web-01", Status: domain.StatusWarning}}},
+		{ID: "before", StartedAt: at(-10), ResolvedAt: resolved(-9), Events: []domain.Alert{{Host: "web-01", Status: domain.StatusCritical}}},
+		{ID: "elsewhere", StartedAt: at(5), ResolvedAt: resolved(6), Events: []domain.Alert{{Host: "cache-01", Status: domain.StatusCritical}}},
+	}
+
+	summaries := BuildServiceInventory(topo, incidents, from, to)
+	if len(summaries) != 2 || summaries[0].Service.Name != "checkout" || summaries[1].Service.Name != "postgres" {
+		t.Fatalf("expected checkout and postgres, got %+v", summaries)
+	}
+
+	checkout := summaries[0]
+	if checkout.Incidents != 4 || checkout.ActiveIncidents != 1 || checkout.Outages != 3 || checkout.Health != "warning" {
+		t.Errorf("unexpected checkout counts: %+v", checkout)
+	}
+	if checkout.Downtime != 4*time.Hour || checkout.Availability != 96 || checkout.MTTR != 2*time.Hour {
+		t.Errorf("expected 4h downtime, 96%% availability and 2h MTTR, got %v, %.2f and %v",
+			checkout.Downtime, checkout.Availability, checkout.MTTR)
+	}
+
+	// A warning on the database is an incident but not downtime
+	postgres := summaries[1]
+	if postgres.Incidents != 1 || postgres.Outages != 0 || postgres.Availability != 100 || postgres.Health != "healthy" {
+		t.Errorf("unexpected postgres summary: %+v", postgres)
+	}
+
+	matched := ServiceIncidents(topo, "checkout", incidents, from, to)
+	if len(matched) != 4 || matched[0].ID != "open" || matched[3].ID != "outage-1" {
+		t.Errorf("expected checkout incidents newest first, got %+v", matched)
+	}
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"incident-teller/internal/classify"
+	"incident-teller/internal/domain"
+	"incident-teller/internal/enrichment"
+	"incident-teller/internal/faults"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/ports"
+	"incident-teller/internal/severity"
+)
+
+// A source failing this many polls in a row is reported unhealthy
+const sourceUnhealthyFailures = 3
+
+// A polled source without a successful poll for this many intervals is reported degraded
+const sourceStaleIntervals = 5
+
+// SourceManager runs one poller per alert source concurrently and merges their alerts into
+// a single event channel. Each source keeps its own cursor, so sources with overlapping
+// external IDs don't skip each other's alerts.
+type SourceManager struct {
+	repository ports.Repository
+	analyzer   *IncidentAnalyzer
+	pollers    map[string]*RealTimePoller
+	names      []string // Registration order
+	eventChan  chan []domain.Alert
+	polling    atomic.Int32 // Sources being polled by Start
+}
+
+// NewSourceManager creates a manager without sources
+func NewSourceManager(repo ports.Repository, analyzer *IncidentAnalyzer) *SourceManager {
+	return &SourceManager{
+		repository: repo,
+		analyzer:   analyzer,
+		pollers:    make(map[string]*RealTimePoller),
+		eventChan:  make(chan []domain.Alert, 100),
+	}
+}
+
+// Add registers an alert source polled every interval. The returned poller can be switched
+// to streaming with SetStream before Start.
+func (m *SourceManager) Add(name string, source ports.AlertSource, interval time.Duration) *RealTimePoller {
+	poller := NewRealTimePoller(source, m.repository, m.analyzer, interval)
+	poller.SetSourceName(name)
+
+	if _, exists := m.pollers[name]; !exists {
+		m.names = append(m.names, name)
+	}
+	m.pollers[name] = poller
+	return poller
+}
+
+// SetPollInterval changes the poll interval of a running source
+func (m *SourceManager) SetPollInterval(name string, interval time.Duration) error {
+	poller, ok := m.pollers[name]
+	if !ok {
+		return fmt.Errorf("alert source %s is not configured", name)
+	}
+	poller.SetPollInterval(interval)
+	return nil
+}
+
+// SetEnrichment adds labels to the alerts of every source
+func (m *SourceManager) SetEnrichment(pipeline *enrichment.Pipeline) {
+	for _, poller := range m.pollers {
+		poller.SetEnrichment(pipeline)
+	}
+}
+
+// SetClassifier sets the resource type of the alerts of every source
+func (m *SourceManager) SetClassifier(classifier *classify.Classifier) {
+	for _, poller := range m.pollers {
+		poller.SetClassifier(classifier)
+	}
+}
+
+// SetFaults delays saving the alerts of every source while repository latency is injected
+func (m *SourceManager) SetFaults(injector *faults.Injector) {
+	for _, poller := range m.pollers {
+		poller.SetFaults(injector)
+	}
+}
+
+// SetRawPayloadLimit caps the source payload stored with the alerts of every source
+func (m *SourceManager) SetRawPayloadLimit(limit int) {
+	for _, poller := range m.pollers {
+		poller.SetRawPayloadLimit(limit)
+	}
+}
+
+// SetSeverityMapper normalizes alert severities of every source
+func (m *SourceManager) SetSeverityMapper(mapper *severity.Mapper) {
+	for _, poller := range m.pollers {
+		poller.SetSeverityMapper(mapper)
+	}
+}
+
+// SetFlapDetector labels flapping alerts of every source; all sources share its state
+func (m *SourceManager) SetFlapDetector(detector *FlapDetector) {
+	for _, poller := range m.pollers {
+		poller.SetFlapDetector(detector)
+	}
+}
+
+// SetDependencySuppressor suppresses alerts of unreachable hosts of every source; all
+// sources share its state, so a host check from one source suppresses alerts from another
+func (m *SourceManager) SetDependencySuppressor(suppressor *DependencySuppressor) {
+	for _, poller := range m.pollers {
+		poller.SetDependencySuppressor(suppressor)
+	}
+}
+
+// SetAnomalyDetector feeds the alerts of every source into the anomaly detector
+func (m *SourceManager) SetAnomalyDetector(detector *AnomalyDetector) {
+	for _, poller := range m.pollers {
+		poller.SetAnomalyDetector(detector)
+	}
+}
+
+// SetMetrics records poll results and batch sizes per source
+func (m *SourceManager) SetMetrics(metrics observability.Metrics) {
+	for _, poller := range m.pollers {
+		poller.SetMetrics(metrics)
+	}
+}
+
+// SetAlertQueue submits the stored batches of every source to the queue instead of the
+// merged event channel
+func (m *SourceManager) SetAlertQueue(queue *AlertQueue) {
+	for _, poller := range m.pollers {
+		poller.SetAlertQueue(queue)
+	}
+}
+
+// Sources returns the registered source names in registration order
+func (m *SourceManager) Sources() []string {
+	return append([]string(nil), m.names...)
+}
+
+// Start runs every source until ctx is cancelled. A source that stops with an error is
+// logged and doesn't stop the others.
+func (m *SourceManager) Start(ctx context.Context) error {
+	if len(m.pollers) == 0 {
+		return fmt.Errorf("no alert sources configured")
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range m.names {
+		poller := m.pollers[name]
+
+		wg.Add(2)
+		go func(name string) {
+			defer wg.Done()
+			m.polling.Add(1)
+			defer m.polling.Add(-1)
+			if err := poller.Start(ctx); err != nil && err != context.Canceled {
+				log.Printf("⚠️  Alert source %s stopped: %v", name, err)
+			}
+		}(name)
+		go func() {
+			defer wg.Done()
+			m.forward(ctx, poller)
+		}()
+	}
+
+	wg.Wait()
+	return ctx.Err()
+}
+
+// BackfillHistory stores the history since the given time of every source that keeps one
+// and has no cursor yet, and returns the stored alerts. Sources failing to backfill are
+// logged and left to regular polling.
+func (m *SourceManager) BackfillHistory(ctx context.Context, since time.Time, chunk time.Duration) []domain.Alert {
+	var stored []domain.Alert
+	for _, name := range m.names {
+		alerts, err := m.pollers[name].BackfillHistory(ctx, since, chunk)
+		if err != nil {
+			log.Printf("⚠️  Failed to backfill %s history: %v", name, err)
+		}
+		if len(alerts) > 0 {
+			log.Printf("📜 Backfilled %d alerts of %s history", len(alerts), name)
+		}
+		stored = append(stored, alerts...)
+	}
+	return stored
+}
+
+// forward copies a poller's alerts to the merged event channel
+func (m *SourceManager) forward(ctx context.Context, poller *RealTimePoller) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case alerts := <-poller.Events():
+			select {
+			case m.eventChan <- alerts:
+			default:
+				log.Println("⚠️  Event channel full, dropping alerts")
+			}
+		}
+	}
+}
+
+// Events returns the merged alerts of all sources
+func (m *SourceManager) Events() <-chan []domain.Alert {
+	return m.eventChan
+}
+
+// Statuses returns the polling health of every source, sorted by name
+func (m *SourceManager) Statuses() []SourceStatus {
+	statuses := make([]SourceStatus, 0, len(m.pollers))
+	for _, poller := range m.pollers {
+		statuses = append(statuses, poller.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// PollingCheck reports whether the alert sources are being polled, unhealthy until Start
+// runs them and once every source stopped
+func (m *SourceManager) PollingCheck() observability.HealthCheck {
+	return func(ctx context.Context) observability.HealthCheckResult {
+		polling := int(m.polling.Load())
+		details := map[string]interface{}{"polling": polling, "sources": len(m.names)}
+		if polling == 0 {
+			return observability.HealthCheckResult{
+				Status:  "unhealthy",
+				Message: "No alert source is being polled",
+				Details: details,
+			}
+		}
+		return observability.HealthCheckResult{
+			Status:  "healthy",
+			Message: fmt.Sprintf("Polling %d of %d alert sources", polling, len(m.names)),
+			Details: details,
+		}
+	}
+}
+
+// HealthCheck reports a source unhealthy while its polls are paused or after repeated poll
+// failures, and degraded after any failure or when a polled source has not succeeded for
+// several intervals
+func (m *SourceManager) HealthCheck(name string) observability.HealthCheck {
+	return func(ctx context.Context) observability.HealthCheckResult {
+		poller, ok := m.pollers[name]
+		if !ok {
+			return observability.HealthCheckResult{
+				Status:  "unhealthy",
+				Message: fmt.Sprintf("Alert source %s is not configured", name),
+			}
+		}
+
+		status := poller.Status()
+		details := map[string]interface{}{
+			"alerts":               status.Alerts,
+			"consecutive_failures": status.ConsecutiveFailures,
+		}
+		if status.LastSuccess != nil {
+			details["last_success"] = status.LastSuccess
+		}
+		if status.LastError != "" {
+			details["last_error"] = status.LastError
+		}
+		if status.Circuit != "" {
+			details["circuit"] = status.Circuit
+		}
+
+		result := observability.HealthCheckResult{
+			Status:  "healthy",
+			Message: fmt.Sprintf("Alert source %s OK", name),
+			Details: details,
+		}
+		switch {
+		case status.Circuit == string(StateOpen) && status.PausedUntil != nil:
+			result.Status = "unhealthy"
+			result.Message = fmt.Sprintf("Alert source %s paused after %d failed polls until %s: %s",
+				name, status.ConsecutiveFailures, status.PausedUntil.Format(time.RFC3339), status.LastError)
+		case status.ConsecutiveFailures >= sourceUnhealthyFailures:
+			result.Status = "unhealthy"
+			result.Message = fmt.Sprintf("Alert source %s failed %d polls in a row: %s", name, status.ConsecutiveFailures, status.LastError)
+		case status.ConsecutiveFailures > 0:
+			result.Status = "degraded"
+			result.Message = fmt.Sprintf("Alert source %s poll failed: %s", name, status.LastError)
+		case poller.stream == nil && status.LastSuccess != nil &&
+			time.Since(*status.LastSuccess) > sourceStaleIntervals*poller.PollInterval():
+			result.Status = "degraded"
+			result.Message = fmt.Sprintf("Alert source %s has not been polled since %s", name, status.LastSuccess.Format(time.RFC3339))
+		}
+		return result
+	}
+}
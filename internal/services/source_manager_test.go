@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"incident-teller/internal/adapters/repository"
+	"incident-teller/internal/domain"
+)
+
+// fakeSource returns its alerts newer than lastID, or err
+type fakeSource struct {
+	alerts []domain.Alert
+	err    error
+}
+
+func (s *fakeSource) FetchLatest(ctx context.Context, lastID uint64) ([]domain.Alert, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var alerts []domain.Alert
+	for _, alert := range s.alerts {
+		if alert.ExternalID > lastID {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
+
+func TestSourceManager_SeparateCursors(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryRepository()
+	manager := NewSourceManager(repo, NewIncidentAnalyzer())
+
+	now := time.Now()
+	netdata := &fakeSource{alerts: []domain.Alert{
+		{ID: "nd-1", ExternalID: 100, Host: "web-01", OccurredAt: now},
+	}}
+	// Zabbix event IDs overlap with Netdata's and must not be skipped
+	zabbix := &fakeSource{alerts: []domain.Alert{
+		{ID: "zbx-1", ExternalID: 5, Host: "db-01", OccurredAt: now},
+	}}
+	netdataPoller := manager.Add("netdata", netdata, time.Minute)
+	zabbixPoller := manager.Add("zabbix", zabbix, time.Minute)
+
+	if _, err := netdataPoller.PollOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	alerts, err := zabbixPoller.PollOnce(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 || alerts[0].Source != "zabbix" {
+		t.Fatalf("expected one zabbix alert, got %+v", alerts)
+	}
+
+	if id, _ := repo.GetLastProcessedID(ctx); id != 100 {
+		t.Errorf("netdata cursor: got %d, want 100", id)
+	}
+	if id, _ := repo.GetSourceCursor(ctx, "zabbix"); id != 5 {
+		t.Errorf("zabbix cursor: got %d, want 5", id)
+	}
+
+	stored, _ := repo.GetAlerts(ctx)
+	sources := map[uint64]string{}
+	for _, alert := range stored {
+		sources[alert.ExternalID] = alert.Source
+	}
+	if sources[100] != "netdata" || sources[5] != "zabbix" {
+		t.Errorf("unexpected source attribution: %v", sources)
+	}
+}
+
+func TestSourceManager_DedupesByFingerprint(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryRepository()
+	manager := NewSourceManager(repo, NewIncidentAnalyzer())
+
+	// The same Netdata event as derived by two adapters, e.g. before and after a restart
+	now := time.Now()
+	first := &fakeSource{alerts: []domain.Alert{
+		{ID: "local-1", ExternalID: 7, Host: "web-01", OccurredAt: now, Status: domain.StatusWarning},
+	}}
+	second := &fakeSource{alerts: []domain.Alert{
+		{ID: "cloud-1", ExternalID: 7, Host: "web-01", OccurredAt: now, Status: domain.StatusCritical},
+	}}
+
+	a, err := manager.Add("netdata", first, time.Minute).PollOnce(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A lost cursor makes the replacement adapter fetch the event again
+	repo.SetLastProcessedID(ctx, 0)
+	b, err := manager.Add("netdata", second, time.Minute).PollOnce(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a[0].ID != b[0].ID {
+		t.Errorf("expected the same canonical ID, got %s and %s", a[0].ID, b[0].ID)
+	}
+
+	stored, _ := repo.GetAlerts(ctx)
+	if len(stored) != 1 || stored[0].Status != domain.StatusCritical {
+		t.Fatalf("expected one updated alert, got %+v", stored)
+	}
+}
+
+func TestSourceManager_HealthCheck(t *testing.T) {
+	ctx := context.Background()
+	source := &fakeSource{err: errors.New("connection refused")}
+	manager := NewSourceManager(repository.NewInMemoryRepository(), NewIncidentAnalyzer())
+	poller := manager.Add("zabbix", source, time.Minute)
+	check := manager.HealthCheck("zabbix")
+
+	if got := check(ctx).Status; got != "healthy" {
+		t.Errorf("before polling: got %s, want healthy", got)
+	}
+
+	want := []string{"degraded", "degraded", "unhealthy"}
+	for i, status := range want {
+		poller.PollOnce(ctx)
+		if got := check(ctx).Status; got != status {
+			t.Errorf("after %d failures: got %s, want %s", i+1, got, status)
+		}
+	}
+
+	source.err = nil
+	poller.PollOnce(ctx)
+	if got := check(ctx).Status; got != "healthy" {
+		t.Errorf("after recovery: got %s, want healthy", got)
+	}
+
+	if got := manager.HealthCheck("nagios")(ctx).Status; got != "unhealthy" {
+		t.Errorf("unknown source: got %s, want unhealthy", got)
+	}
+}
+
+// historySource also returns its alerts since a time, like the Netdata alarm log
+type historySource struct {
+	fakeSource
+}
+
+func (s *historySource) FetchHistory(ctx context.Context, since time.Time) ([]domain.Alert, error) {
+	var alerts []domain.Alert
+	for _, alert := range s.alerts {
+		if !alert.OccurredAt.Before(since) {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
+
+// batchCountingRepository counts the batches of saved alerts
+type batchCountingRepository struct {
+	*repository.InMemoryRepository
+	batches int
+}
+
+func (r *batchCountingRepository) SaveAlerts(ctx context.Context, alerts []domain.Alert) error {
+	r.batches++
+	return r.InMemoryRepository.SaveAlerts(ctx, alerts)
+}
+
+func TestSourceManager_BackfillHistory(t *testing.T) {
+	ctx := context.Background()
+	repo := &batchCountingRepository{InMemoryRepository: repository.NewInMemoryRepository()}
+	manager := NewSourceManager(repo, NewIncidentAnalyzer())
+
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	netdata := &historySource{fakeSource{alerts: []domain.Alert{
+		{ID: "old", ExternalID: 1, Host: "web-01", OccurredAt: since.Add(-time.Hour)},
+		{ID: "a", ExternalID: 2, Host: "web-01", OccurredAt: since.Add(10 * time.Minute)},
+		{ID: "b", ExternalID: 3, Host: "web-01", OccurredAt: since.Add(50 * time.Minute)},
+		{ID: "c", ExternalID: 4, Host: "web-01", OccurredAt: since.Add(3*time.Hour + time.Minute)},
+	}}}
+	manager.Add("netdata", netdata, time.Minute)
+	// Sources without a history are left to polling
+	manager.Add("zabbix", &fakeSource{alerts: []domain.Alert{{ID: "zbx-1", ExternalID: 5}}}, time.Minute)
+
+	stored := manager.BackfillHistory(ctx, since, time.Hour)
+	if len(stored) != 3 || stored[0].ExternalID != 2 || stored[0].Source != "netdata" {
+		t.Fatalf("expected the three alerts since the start, got %+v", stored)
+	}
+	if repo.batches != 2 {
+		t.Errorf("expected one batch per hour with alerts, got %d", repo.batches)
+	}
+	if id, _ := repo.GetLastProcessedID(ctx); id != 4 {
+		t.Errorf("netdata cursor: got %d, want 4", id)
+	}
+
+	// Once a cursor exists it is no longer a cold start
+	if again := manager.BackfillHistory(ctx, since, time.Hour); len(again) != 0 {
+		t.Errorf("expected no backfill after the first run, got %d alerts", len(again))
+	}
+}
+
+// countingSource counts fetches and fails while err is set
+type countingSource struct {
+	fetches int
+	err     error
+}
+
+func (s *countingSource) FetchLatest(ctx context.Context, lastID uint64) ([]domain.Alert, error) {
+	s.fetches++
+	return nil, s.err
+}
+
+func TestRealTimePoller_CircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	source := &countingSource{err: errors.New("connection refused")}
+	manager := NewSourceManager(repository.NewInMemoryRepository(), NewIncidentAnalyzer())
+	poller := manager.Add("netdata", source, time.Minute)
+	poller.SetCircuitBreaker(NewCircuitBreaker("netdata", 2, 20*time.Millisecond))
+
+	for i := 0; i < 4; i++ {
+		poller.guardedPoll(ctx)
+	}
+	status := poller.Status()
+	if source.fetches != 2 || status.Circuit != "open" || status.PausedUntil == nil {
+		t.Fatalf("expected polls to pause after 2 failures, got %d fetches and %+v", source.fetches, status)
+	}
+	if result := manager.HealthCheck("netdata")(ctx); result.Status != "unhealthy" {
+		t.Errorf("expected a paused source to be unhealthy, got %+v", result)
+	}
+
+	// A failed probe pauses again
+	time.Sleep(30 * time.Millisecond)
+	poller.guardedPoll(ctx)
+	poller.guardedPoll(ctx)
+	if source.fetches != 3 || poller.Status().Circuit != "open" {
+		t.Fatalf("expected one failed probe, got %d fetches and %+v", source.fetches, poller.Status())
+	}
+
+	// Successful probes resume polling
+	source.err = nil
+	time.Sleep(30 * time.Millisecond)
+	poller.guardedPoll(ctx)
+	poller.guardedPoll(ctx)
+	if status := poller.Status(); status.Circuit != "closed" || status.PausedUntil != nil || status.ConsecutiveFailures != 0 {
+		t.Errorf("expected polling to resume, got %+v", status)
+	}
+}
+
+func TestSourceManager_PollingCheck(t *testing.T) {
+	manager := NewSourceManager(repository.NewInMemoryRepository(), NewIncidentAnalyzer())
+	manager.Add("netdata", &fakeSource{}, time.Hour)
+	check := manager.PollingCheck()
+
+	if result := check(context.Background()); result.Status != "unhealthy" {
+		t.Fatalf("expected unhealthy before Start, got %s", result.Status)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		manager.Start(ctx)
+		close(done)
+	}()
+	deadline := time.Now().Add(time.Second)
+	for check(ctx).Status != "healthy" {
+		if time.Now().After(deadline) {
+			t.Fatal("expected healthy while polling")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	<-done
+	if result := check(context.Background()); result.Status != "unhealthy" {
+		t.Errorf("expected unhealthy once stopped, got %s", result.Status)
+	}
+}
+
+func TestSourceManager_RawPayloadLimit(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewInMemoryRepository()
+	manager := NewSourceManager(repo, NewIncidentAnalyzer())
+
+	now := time.Now()
+	source := &fakeSource{alerts: []domain.Alert{
+		{ID: "short", ExternalID: 1, Host: "web-01", OccurredAt: now, RawPayload: `{"a":1}`},
+		{ID: "long", ExternalID: 2, Host: "web-01", OccurredAt: now, RawPayload: `{"info":"héllo"}`},
+	}}
+	manager.Add("netdata", source, time.Minute)
+	// Cutting at 11 bytes would split the é
+	manager.SetRawPayloadLimit(11)
+
+	alerts, err := manager.pollers["netdata"].PollOnce(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alerts[0].RawPayload != `{"a":1}` {
+		t.Errorf("short payload: got %q", alerts[0].RawPayload)
+	}
+	if alerts[1].RawPayload != `{"info":"h` {
+		t.Errorf("long payload: got %q, want it cut before the é", alerts[1].RawPayload)
+	}
+
+	manager.SetRawPayloadLimit(0)
+	source.alerts[0].ExternalID = 3
+	alerts, err = manager.pollers["netdata"].PollOnce(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 || alerts[0].RawPayload != "" {
+		t.Errorf("limit 0 must keep no payload, got %+v", alerts)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"incident-teller/internal/domain"
+	"incident-teller/internal/report"
 )
 
 // RootCauseCandidate represents a potential root cause with confidence score
@@ -19,6 +20,8 @@ type RootCauseCandidate struct {
 	IsEarliest      bool
 	HasCascade      bool
 	HasLogErrors    bool
+	RelatedChanges  []domain.ChangeEvent // Changes on the same host shortly before the incident
+	Pinned          bool                 // Set by a responder rather than analyzed
 }
 
 // BlastRadiusAnalysis represents the impact scope of an incident
@@ -48,6 +51,7 @@ type IncidentExplanation struct {
 // SREAnalyzer provides on-call SRE-grade incident analysis
 type SREAnalyzer struct {
 	analyzer *IncidentAnalyzer
+	changes  *ChangeTracker
 }
 
 // NewSREAnalyzer creates a new SRE analyzer
@@ -57,8 +61,28 @@ func NewSREAnalyzer() *SREAnalyzer {
 	}
 }
 
+// SetChangeTracker enables correlation of deployments and config changes into root cause scoring
+func (s *SREAnalyzer) SetChangeTracker(tracker *ChangeTracker) {
+	s.changes = tracker
+}
+
+// SetPropagationLearner enables learned propagation patterns in causality detection
+func (s *SREAnalyzer) SetPropagationLearner(learner *PropagationLearner) {
+	s.analyzer.SetPropagationLearner(learner)
+}
+
 // AnalyzeIncidentForSRE performs comprehensive root cause analysis with confidence scoring
 func (s *SREAnalyzer) AnalyzeIncidentForSRE(alerts []domain.Alert) IncidentExplanation {
+	return s.explain(alerts, nil)
+}
+
+// ExplainIncident analyzes the events of an incident like AnalyzeIncidentForSRE, keeping
+// the root cause a responder pinned on it
+func (s *SREAnalyzer) ExplainIncident(incident domain.Incident) IncidentExplanation {
+	return s.explain(incident.Events, incident.RootCauseOverride)
+}
+
+func (s *SREAnalyzer) explain(alerts []domain.Alert, override *domain.RootCauseOverride) IncidentExplanation {
 	if len(alerts) == 0 {
 		return IncidentExplanation{
 			WhatHappened: "No incident data available",
@@ -86,6 +110,7 @@ func (s *SREAnalyzer) AnalyzeIncidentForSRE(alerts []domain.Alert) IncidentExpla
 	sort.Slice(scoredCandidates, func(i, j int) bool {
 		return scoredCandidates[i].ConfidenceScore > scoredCandidates[j].ConfidenceScore
 	})
+	scoredCandidates = pinRootCause(scoredCandidates, sortedAlerts, override)
 
 	// Analyze blast radius
 	blastRadius := s.analyzeBlastRadius(sortedAlerts)
@@ -125,6 +150,11 @@ func (s *SREAnalyzer) identifyRootCauseCandidates(
 		}
 	}
 
+	ids := make(map[string]bool, len(alerts))
+	for i := range alerts {
+		ids[alerts[i].ID] = true
+	}
+
 	for i := range alerts {
 		alert := &alerts[i]
 
@@ -132,6 +162,10 @@ func (s *SREAnalyzer) identifyRootCauseCandidates(
 		if alert.Status == domain.StatusClear {
 			continue
 		}
+		// Alerts of an unreachable host are symptoms of the alert reporting it
+		if root := SuppressedBy(*alert); root != "" && ids[root] {
+			continue
+		}
 
 		// Get timeline entry for causality info
 		var timelineEntry *domain.TimelineEntry
@@ -217,6 +251,13 @@ func (s *SREAnalyzer) scoreRootCauses(
 			evidence = append(evidence, fmt.Sprintf("%s is a high-impact resource", alert.ResourceType))
 		}
 
+		// Rule 6: Recent change on the same host (max 25 points)
+		if changeScore, changeEvidence := s.scoreRecentChanges(&candidates[i], allAlerts); changeScore > 0 {
+			score += changeScore
+			evidence = append(evidence, changeEvidence...)
+			reasoning += "; preceded by a recent change on the same host"
+		}
+
 		// Normalize to 0-100
 		if score > 100 {
 			score = 100
@@ -230,6 +271,65 @@ func (s *SREAnalyzer) scoreRootCauses(
 	return candidates
 }
 
+// scoreRecentChanges weights change events that landed shortly before the first alert
+func (s *SREAnalyzer) scoreRecentChanges(candidate *RootCauseCandidate, allAlerts []domain.Alert) (int, []string) {
+	if s.changes == nil || len(allAlerts) == 0 {
+		return 0, nil
+	}
+
+	firstAlertAt := allAlerts[0].OccurredAt
+	changes := s.changes.ChangesBefore([]domain.Alert{*candidate.Alert}, firstAlertAt, 30*time.Minute)
+	if len(changes) == 0 {
+		return 0, nil
+	}
+	candidate.RelatedChanges = changes
+
+	best := 0
+	evidence := []string{}
+	for _, change := range changes {
+		gap := firstAlertAt.Sub(change.OccurredAt)
+
+		points := 10
+		switch {
+		case gap <= 5*time.Minute:
+			points = 25
+		case gap <= 15*time.Minute:
+			points = 15
+		}
+		if points > best {
+			best = points
+		}
+
+		evidence = append(evidence, describeChange(change, candidate.Alert.Host, gap))
+	}
+
+	return best, evidence
+}
+
+// describeChange renders a change event as root cause evidence
+func describeChange(change domain.ChangeEvent, host string, gap time.Duration) string {
+	what := string(change.Type)
+	if change.Version != "" {
+		what += " " + change.Version
+	} else if change.Description != "" {
+		what += " (" + change.Description + ")"
+	}
+
+	target := change.Host
+	if target == "" {
+		target = host
+	}
+
+	minutes := int(gap.Round(time.Minute) / time.Minute)
+	if minutes < 1 {
+		return fmt.Sprintf("%s to %s less than a minute before first alert", what, target)
+	}
+	if minutes == 1 {
+		return fmt.Sprintf("%s to %s 1 minute before first alert", what, target)
+	}
+	return fmt.Sprintf("%s to %s %d minutes before first alert", what, target, minutes)
+}
+
 // getResourceImpactScore assigns weight based on resource criticality
 func (s *SREAnalyzer) getResourceImpactScore(rt domain.ResourceType) int {
 	switch rt {
@@ -243,6 +343,12 @@ func (s *SREAnalyzer) getResourceImpactScore(rt domain.ResourceType) int {
 		return 7  // Network issues affect availability
 	case domain.ResourceProcess:
 		return 9  // Process issues often root causes
+	case domain.ResourceDatabase:
+		return 9  // Databases back most services
+	case domain.ResourceContainer:
+		return 7  // Container failures take workloads down
+	case domain.ResourceApplication:
+		return 5  // Application symptoms usually follow infrastructure causes
 	default:
 		return 0
 	}
@@ -255,8 +361,8 @@ func (s *SREAnalyzer) hasCascadingEffects(alert *domain.Alert, allAlerts []domai
 
 	for i := range allAlerts {
 		other := &allAlerts[i]
-		// Skip same resource type or earlier alerts
-		if other.ResourceType == alert.ResourceType || !other.OccurredAt.After(alert.OccurredAt) {
+		// Skip same resource type, earlier and suppressed alerts
+		if other.ResourceType == alert.ResourceType || !other.OccurredAt.After(alert.OccurredAt) || SuppressedBy(*other) != "" {
 			continue
 		}
 
@@ -270,6 +376,18 @@ func (s *SREAnalyzer) hasCascadingEffects(alert *domain.Alert, allAlerts []domai
 	return len(laterResources) >= 2
 }
 
+// cascadeResources returns the resource types with alerts that may be part of a cascade,
+// leaving out those suppressed while their host was unreachable
+func cascadeResources(alerts []domain.Alert) map[domain.ResourceType]bool {
+	resources := make(map[domain.ResourceType]bool)
+	for i := range alerts {
+		if SuppressedBy(alerts[i]) == "" {
+			resources[alerts[i].ResourceType] = true
+		}
+	}
+	return resources
+}
+
 // hasRelatedLogErrors simulates log correlation (in real system, query Loki/Elasticsearch)
 func (s *SREAnalyzer) hasRelatedLogErrors(alert *domain.Alert) bool {
 	// In production: Query log aggregator for error logs around alert.OccurredAt
@@ -296,13 +414,13 @@ func (s *SREAnalyzer) analyzeBlastRadius(alerts []domain.Alert) BlastRadiusAnaly
 		}
 	}
 
-	// Estimate cascade depth
-	if len(resources) == 1 {
+	// Estimate cascade depth; suppressed alerts went down with their host, not in a cascade
+	if cascading := len(cascadeResources(alerts)); cascading <= 1 {
 		maxDepth = 0 // Single resource, no cascade
-	} else if len(resources) == 2 {
+	} else if cascading == 2 {
 		maxDepth = 1 // Direct cascade
 	} else {
-		maxDepth = len(resources) - 1 // Multi-level cascade
+		maxDepth = cascading - 1 // Multi-level cascade
 	}
 
 	duration := time.Duration(0)
@@ -352,6 +470,7 @@ func (s *SREAnalyzer) explainWhatHappened(alerts []domain.Alert, timeline []doma
 	triggered := 0
 	escalated := 0
 	resolved := 0
+	suppressed := 0
 	for _, entry := range timeline {
 		switch entry.Type {
 		case "TRIGGERED":
@@ -360,6 +479,8 @@ func (s *SREAnalyzer) explainWhatHappened(alerts []domain.Alert, timeline []doma
 			escalated++
 		case "RESOLVED":
 			resolved++
+		case "SUPPRESSED":
+			suppressed++
 		}
 	}
 
@@ -375,6 +496,9 @@ func (s *SREAnalyzer) explainWhatHappened(alerts []domain.Alert, timeline []doma
 	if resolved > 0 {
 		summary += fmt.Sprintf("%d alerts resolved. ", resolved)
 	}
+	if suppressed > 0 {
+		summary += fmt.Sprintf("%d alerts suppressed while their host was unreachable. ", suppressed)
+	}
 
 	// Add resource context
 	resources := make(map[domain.ResourceType]bool)
@@ -388,6 +512,49 @@ func (s *SREAnalyzer) explainWhatHappened(alerts []domain.Alert, timeline []doma
 	return summary
 }
 
+// pinRootCause puts the candidate of the alert a responder pinned first with full
+// confidence, keeping the analyzed candidates as alternatives
+func pinRootCause(candidates []RootCauseCandidate, alerts []domain.Alert, override *domain.RootCauseOverride) []RootCauseCandidate {
+	if override == nil {
+		return candidates
+	}
+
+	var pinned *RootCauseCandidate
+	rest := make([]RootCauseCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if pinned == nil && candidate.Alert.ID == override.AlertID {
+			candidate := candidate
+			pinned = &candidate
+			continue
+		}
+		rest = append(rest, candidate)
+	}
+	if pinned == nil {
+		// A CLEAR alert is no analyzed candidate but can still be pinned
+		for i := range alerts {
+			if alerts[i].ID == override.AlertID {
+				pinned = &RootCauseCandidate{Alert: &alerts[i], TimelinePosition: i, IsEarliest: i == 0, Evidence: []string{}}
+				break
+			}
+		}
+	}
+	if pinned == nil {
+		return candidates // The alert is no longer part of the incident
+	}
+
+	by := override.SetBy
+	if by == "" {
+		by = "a responder"
+	}
+	pinned.Pinned = true
+	pinned.ConfidenceScore = 100
+	pinned.Reasoning = "Pinned as the root cause by " + by
+	if override.Explanation != "" {
+		pinned.Reasoning += ": " + override.Explanation
+	}
+	return append([]RootCauseCandidate{*pinned}, rest...)
+}
+
 // explainWhyItHappened provides root cause reasoning
 func (s *SREAnalyzer) explainWhyItHappened(candidates []RootCauseCandidate, timeline []domain.TimelineEntry) string {
 	if len(candidates) == 0 {
@@ -408,6 +575,12 @@ func (s *SREAnalyzer) explainWhyItHappened(candidates []RootCauseCandidate, time
 		explanation += "This caused a cascade effect, impacting other system resources. "
 	}
 
+	if len(rootCause.RelatedChanges) > 0 {
+		latest := rootCause.RelatedChanges[len(rootCause.RelatedChanges)-1]
+		explanation += fmt.Sprintf("A %s was applied to %s shortly before the first alert and is the most likely trigger. ",
+			latest.Type, alert.Host)
+	}
+
 	return explanation
 }
 
@@ -425,7 +598,7 @@ func (s *SREAnalyzer) explainWhatBrokeFirst(candidates []RootCauseCandidate) str
 		alert.Chart,
 		alert.Host,
 		alert.Value,
-		alert.OccurredAt.Format("15:04:05"))
+		alert.OccurredAt.UTC().Format(ClockLayout))
 }
 
 // suggestFix provides remediation guidance
@@ -470,6 +643,24 @@ func (s *SREAnalyzer) suggestFix(candidates []RootCauseCandidate, blast BlastRad
 		fixes = append(fixes, "3. Verify process limits: `ulimit -a`")
 		fixes = append(fixes, "4. Review recent deployments or config changes")
 
+	case domain.ResourceDatabase:
+		fixes = append(fixes, "1. List running queries and kill runaway ones")
+		fixes = append(fixes, "2. Check connection counts and lock waits")
+		fixes = append(fixes, "3. Review the slow query log")
+		fixes = append(fixes, "4. Verify replication lag and free disk space")
+
+	case domain.ResourceContainer:
+		fixes = append(fixes, "1. Find restarting or OOM-killed containers: `kubectl get pods -A`")
+		fixes = append(fixes, "2. Inspect the last termination reason: `kubectl describe pod <pod>`")
+		fixes = append(fixes, "3. Compare usage with resource limits: `kubectl top pods`")
+		fixes = append(fixes, "4. Roll back the image if failures started with a deployment")
+
+	case domain.ResourceApplication:
+		fixes = append(fixes, "1. Check error rates in the access logs")
+		fixes = append(fixes, "2. Verify health endpoints of the application and its dependencies")
+		fixes = append(fixes, "3. Check upstream status in the web server or load balancer")
+		fixes = append(fixes, "4. Roll back the latest deployment if errors started with it")
+
 	default:
 		fixes = append(fixes, "1. Review system logs: `journalctl -xe`")
 		fixes = append(fixes, "2. Check resource utilization: `vmstat 1 5`")
@@ -525,72 +716,5 @@ func resourceKeys(m map[domain.ResourceType]bool) []domain.ResourceType {
 
 // FormatIncidentExplanation creates a formatted report for SREs
 func FormatIncidentExplanation(exp IncidentExplanation) string {
-	var report strings.Builder
-
-	report.WriteString("╔═══════════════════════════════════════════════════════════════╗\n")
-	report.WriteString("║           SRE INCIDENT ANALYSIS REPORT                        ║\n")
-	report.WriteString("╚═══════════════════════════════════════════════════════════════╝\n\n")
-
-	report.WriteString("📋 WHAT HAPPENED\n")
-	report.WriteString("═══════════════════════════════════════════════════════════════\n")
-	report.WriteString(exp.WhatHappened)
-	report.WriteString("\n\n")
-
-	report.WriteString("🔍 WHY IT HAPPENED\n")
-	report.WriteString("═══════════════════════════════════════════════════════════════\n")
-	report.WriteString(exp.WhyItHappened)
-	report.WriteString("\n\n")
-
-	report.WriteString("🔴 WHAT BROKE FIRST\n")
-	report.WriteString("═══════════════════════════════════════════════════════════════\n")
-	report.WriteString(exp.WhatBrokeFirst)
-	report.WriteString("\n\n")
-
-	report.WriteString("💥 BLAST RADIUS\n")
-	report.WriteString("═══════════════════════════════════════════════════════════════\n")
-	report.WriteString(fmt.Sprintf("Impact: %s\n", exp.BlastRadius.ImpactDescription))
-	report.WriteString(fmt.Sprintf("Affected Hosts: %d (%v)\n", len(exp.BlastRadius.AffectedHosts), exp.BlastRadius.AffectedHosts))
-	report.WriteString(fmt.Sprintf("Affected Resources: %v\n", exp.BlastRadius.AffectedResources))
-	report.WriteString(fmt.Sprintf("Total Alerts: %d (Critical: %d)\n", exp.BlastRadius.TotalAlerts, exp.BlastRadius.CriticalAlerts))
-	report.WriteString(fmt.Sprintf("Cascade Depth: %d levels\n", exp.BlastRadius.CascadeDepth))
-	report.WriteString(fmt.Sprintf("Duration: %s\n", exp.BlastRadius.Duration.Round(time.Second)))
-	report.WriteString("\n")
-
-	report.WriteString("🔧 SUGGESTED FIX\n")
-	report.WriteString("═══════════════════════════════════════════════════════════════\n")
-	report.WriteString(exp.SuggestedFix)
-	report.WriteString("\n\n")
-
-	report.WriteString("🎯 ROOT CAUSE ANALYSIS\n")
-	report.WriteString("═══════════════════════════════════════════════════════════════\n")
-	report.WriteString(fmt.Sprintf("Confidence: %s (%d/100)\n\n", exp.ConfidenceLevel, exp.RootCause.ConfidenceScore))
-	
-	report.WriteString("Primary Root Cause:\n")
-	report.WriteString(fmt.Sprintf("  • Alert: %s\n", exp.RootCause.Alert.Name))
-	report.WriteString(fmt.Sprintf("  • Resource: %s\n", exp.RootCause.Alert.ResourceType))
-	report.WriteString(fmt.Sprintf("  • Host: %s\n", exp.RootCause.Alert.Host))
-	report.WriteString(fmt.Sprintf("  • Value: %.2f\n", exp.RootCause.Alert.Value))
-	
-	if len(exp.RootCause.Evidence) > 0 {
-		report.WriteString("\nEvidence:\n")
-		for _, evidence := range exp.RootCause.Evidence {
-			report.WriteString(fmt.Sprintf("  ✓ %s\n", evidence))
-		}
-	}
-
-	if len(exp.AlternativeCauses) > 0 {
-		report.WriteString("\nAlternative Causes:\n")
-		for i, alt := range exp.AlternativeCauses {
-			if i >= 3 {
-				break // Show top 3 alternatives
-			}
-			report.WriteString(fmt.Sprintf("  %d. %s (%s) - Confidence: %d%%\n",
-				i+1, alt.Alert.Name, alt.Alert.ResourceType, alt.ConfidenceScore))
-		}
-	}
-
-	report.WriteString("\n")
-	report.WriteString("═══════════════════════════════════════════════════════════════\n")
-
-	return report.String()
+	return report.Text{}.Render(ExplanationDocument(exp))
 }
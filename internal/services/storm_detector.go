@@ -0,0 +1,217 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/idgen"
+)
+
+// maxRecentStorms is how many storms StormDetector.Storms reports
+const maxRecentStorms = 20
+
+// AlertStorm summarizes one alert storm
+type AlertStorm struct {
+	IncidentID  string
+	StartedAt   time.Time
+	LastAlertAt time.Time
+	Alerts      int
+	Hosts       int
+	Suppressed  int // Incidents whose notifications were suppressed during the storm
+}
+
+// StormDetector collapses alert storms, when a monitoring meltdown sends at least
+// threshold alerts within the window, into a single "alert storm" incident, and throttles
+// the notifications of other incidents starting until cooldown after its last alert.
+// A storm ends once its alerts thin out for longer than the window.
+type StormDetector struct {
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+
+	mu      sync.Mutex
+	current *stormState
+	storms  []*stormState // Oldest first, at most maxRecentStorms including current
+}
+
+// stormState is one storm: its events and the incidents that were not notified
+type stormState struct {
+	id         string
+	events     []domain.Alert
+	seen       map[string]bool
+	suppressed map[string]bool
+}
+
+// NewStormDetector creates a storm detector
+func NewStormDetector(threshold int, window, cooldown time.Duration) *StormDetector {
+	if threshold <= 0 {
+		threshold = 100
+	}
+	if window <= 0 {
+		window = time.Minute
+	}
+	return &StormDetector{threshold: threshold, window: window, cooldown: cooldown}
+}
+
+// Collapse takes the alerts of a storm out of a time-ordered batch and returns them as the
+// storm incident, together with the remaining alerts. Alerts continuing the current storm
+// join its incident, which keeps its ID across batches. A nil detector returns the batch
+// unchanged.
+func (d *StormDetector) Collapse(alerts []domain.Alert) (*domain.Incident, []domain.Alert) {
+	if d == nil || len(alerts) == 0 {
+		return nil, alerts
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// The alerts of the current storm just before the batch count towards its density
+	var times []time.Time
+	if d.current != nil {
+		from := alerts[0].OccurredAt.Add(-d.window)
+		for _, event := range d.current.events {
+			if !event.OccurredAt.Before(from) && event.OccurredAt.Before(alerts[0].OccurredAt) {
+				times = append(times, event.OccurredAt)
+			}
+		}
+	}
+	offset := len(times)
+	for _, alert := range alerts {
+		times = append(times, alert.OccurredAt)
+	}
+
+	inStorm := d.dense(times, offset)
+	var storm, rest []domain.Alert
+	for i, alert := range alerts {
+		if inStorm[i] || (d.current != nil && d.current.seen[alert.ID]) {
+			storm = append(storm, alert)
+		} else {
+			rest = append(rest, alert)
+		}
+	}
+	if len(storm) == 0 {
+		return nil, alerts
+	}
+
+	if d.current == nil || storm[0].OccurredAt.Sub(d.current.lastAlertAt()) > d.window {
+		d.current = &stormState{
+			id:         idgen.Derive(storm[0].OccurredAt, storm[0].ID),
+			seen:       make(map[string]bool),
+			suppressed: make(map[string]bool),
+		}
+		d.storms = append(d.storms, d.current)
+		if len(d.storms) > maxRecentStorms {
+			d.storms = d.storms[len(d.storms)-maxRecentStorms:]
+		}
+	}
+	for _, alert := range storm {
+		if !d.current.seen[alert.ID] {
+			d.current.seen[alert.ID] = true
+			d.current.events = append(d.current.events, alert)
+		}
+	}
+	sort.SliceStable(d.current.events, func(i, j int) bool {
+		return d.current.events[i].OccurredAt.Before(d.current.events[j].OccurredAt)
+	})
+
+	incident := d.current.incident()
+	return &incident, rest
+}
+
+// dense marks, for the batch part of the time-ordered times starting at offset, the ones
+// lying in a window holding at least threshold alerts
+func (d *StormDetector) dense(times []time.Time, offset int) []bool {
+	marked := make([]bool, len(times))
+	left, markedTo := 0, -1
+	for right := range times {
+		for times[right].Sub(times[left]) > d.window {
+			left++
+		}
+		if right-left+1 < d.threshold {
+			continue
+		}
+		from := left
+		if markedTo+1 > from {
+			from = markedTo + 1
+		}
+		for i := from; i <= right; i++ {
+			marked[i] = true
+		}
+		markedTo = right
+	}
+	return marked[offset:]
+}
+
+// Throttle reports whether the notification of an incident is suppressed because it
+// started during a storm, or until cooldown after it, and records the suppression. The
+// storm incident itself is never throttled. A nil detector throttles nothing.
+func (d *StormDetector) Throttle(incident domain.Incident) bool {
+	if d == nil {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := len(d.storms) - 1; i >= 0; i-- {
+		storm := d.storms[i]
+		if storm.id == incident.ID {
+			return false
+		}
+		if !incident.StartedAt.Before(storm.events[0].OccurredAt) &&
+			!incident.StartedAt.After(storm.lastAlertAt().Add(d.cooldown)) {
+			storm.suppressed[incident.ID] = true
+			return true
+		}
+	}
+	return false
+}
+
+// Storms returns the recent storms, the latest first
+func (d *StormDetector) Storms() []AlertStorm {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	storms := make([]AlertStorm, 0, len(d.storms))
+	for i := len(d.storms) - 1; i >= 0; i-- {
+		storm := d.storms[i]
+		storms = append(storms, AlertStorm{
+			IncidentID:  storm.id,
+			StartedAt:   storm.events[0].OccurredAt,
+			LastAlertAt: storm.lastAlertAt(),
+			Alerts:      len(storm.events),
+			Hosts:       len(storm.hosts()),
+			Suppressed:  len(storm.suppressed),
+		})
+	}
+	return storms
+}
+
+func (s *stormState) lastAlertAt() time.Time {
+	return s.events[len(s.events)-1].OccurredAt
+}
+
+func (s *stormState) hosts() map[string]bool {
+	hosts := make(map[string]bool)
+	for _, event := range s.events {
+		hosts[event.Host] = true
+	}
+	return hosts
+}
+
+// incident returns the storm as an incident holding a copy of its events
+func (s *stormState) incident() domain.Incident {
+	events := append([]domain.Alert(nil), s.events...)
+	return domain.Incident{
+		ID:        s.id,
+		Title:     fmt.Sprintf("Alert storm: %d alerts on %d hosts", len(events), len(s.hosts())),
+		Status:    events[len(events)-1].Status,
+		StartedAt: events[0].OccurredAt,
+		Events:    events,
+	}
+}
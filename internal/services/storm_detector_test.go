@@ -0,0 +1,87 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func stormAlerts(prefix string, start time.Time, count int, every time.Duration) []domain.Alert {
+	alerts := make([]domain.Alert, count)
+	for i := range alerts {
+		alerts[i] = domain.Alert{
+			ID: fmt.Sprintf("%s-%d", prefix, i), Host: fmt.Sprintf("web-%02d", i%5), Name: "cpu_usage",
+			Status: domain.StatusCritical, OccurredAt: start.Add(time.Duration(i) * every),
+		}
+	}
+	return alerts
+}
+
+func TestIncidentBuilder_CollapsesAlertStorm(t *testing.T) {
+	detector := NewStormDetector(10, time.Minute, 10*time.Minute)
+	builder := NewIncidentBuilder(15 * time.Minute)
+	builder.SetStrategy(hostStrategy{})
+	builder.SetStormDetector(detector)
+	start := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+
+	// A lone alert well before the storm stays its own incident
+	alerts := append([]domain.Alert{{
+		ID: "early", Host: "db-01", Name: "disk_space", Status: domain.StatusWarning, OccurredAt: start.Add(-time.Hour),
+	}}, stormAlerts("first", start, 12, time.Second)...)
+
+	incidents := builder.Build(alerts)
+	if len(incidents) != 2 {
+		t.Fatalf("expected the lone incident and the storm, got %d incidents", len(incidents))
+	}
+	storm := incidents[1]
+	if len(storm.Events) != 12 || storm.Title != "Alert storm: 12 alerts on 5 hosts" {
+		t.Errorf("unexpected storm incident %q with %d events", storm.Title, len(storm.Events))
+	}
+
+	// The next batch continues the storm under the same incident
+	next := builder.Build(stormAlerts("second", start.Add(20*time.Second), 5, time.Second))
+	if len(next) != 1 || next[0].ID != storm.ID || len(next[0].Events) != 17 {
+		t.Fatalf("expected the storm incident to grow to 17 events, got %+v", next)
+	}
+
+	// Once the alerts thin out, they are correlated as usual again
+	calm := builder.Build(stormAlerts("calm", start.Add(time.Hour), 3, time.Minute))
+	if len(calm) != 3 {
+		t.Errorf("expected one incident per host after the storm, got %d", len(calm))
+	}
+
+	if storms := detector.Storms(); len(storms) != 1 || storms[0].Alerts != 17 || storms[0].Hosts != 5 {
+		t.Errorf("unexpected storms %+v", storms)
+	}
+}
+
+func TestStormDetector_ThrottlesNotifications(t *testing.T) {
+	detector := NewStormDetector(10, time.Minute, 10*time.Minute)
+	start := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	storm, rest := detector.Collapse(stormAlerts("storm", start, 10, time.Second))
+	if storm == nil || len(rest) != 0 {
+		t.Fatalf("expected the whole batch to be a storm, got %v and %d alerts", storm, len(rest))
+	}
+
+	during := domain.Incident{ID: "during", StartedAt: start.Add(5 * time.Minute)}
+	after := domain.Incident{ID: "after", StartedAt: start.Add(time.Hour)}
+	if detector.Throttle(*storm) {
+		t.Error("expected the storm incident to be notified")
+	}
+	if !detector.Throttle(during) || !detector.Throttle(during) {
+		t.Error("expected an incident starting during the cooldown to be throttled")
+	}
+	if detector.Throttle(after) {
+		t.Error("expected an incident after the cooldown to be notified")
+	}
+	if storms := detector.Storms(); len(storms) != 1 || storms[0].Suppressed != 1 {
+		t.Errorf("expected one suppressed incident, got %+v", storms)
+	}
+
+	var disabled *StormDetector
+	if storm, rest := disabled.Collapse(rest); storm != nil || disabled.Throttle(during) {
+		t.Errorf("expected a nil detector to do nothing, got %v and %d alerts", storm, len(rest))
+	}
+}
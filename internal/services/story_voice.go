@@ -0,0 +1,553 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Tones incident stories can be told in
+const (
+	ToneCalmEngineer = "calm-engineer" // Explains what happened and why, step by step
+	ToneExecutive    = "executive"     // Business impact without the technical detail
+	ToneTerse        = "terse"         // Facts only, one short line each
+)
+
+// StoryTones lists the accepted tones, the default first
+var StoryTones = []string{ToneCalmEngineer, ToneExecutive, ToneTerse}
+
+// StoryLocales lists the languages stories are told in, the default first
+var StoryLocales = []string{"en", "es", "de", "hi"}
+
+// StoryVoice selects the tone, language and time zone of an incident story. The zero
+// value tells it as a calm engineer in English, with times in UTC. Fix steps come from
+// the playbooks and aren't translated.
+type StoryVoice struct {
+	Tone     string
+	Locale   string
+	Location *time.Location // Time zone the story's times are given in; nil is UTC
+}
+
+// ParseStoryVoice validates a tone and locale, defaulting to a calm engineer in English.
+// Regional locales such as es-MX or de_AT use their language.
+func ParseStoryVoice(tone, locale string) (StoryVoice, error) {
+	voice := StoryVoice{Tone: ToneCalmEngineer, Locale: "en"}
+	if tone != "" {
+		tone = strings.ToLower(tone)
+		if !contains(StoryTones, tone) {
+			return voice, fmt.Errorf("unknown tone %q, use one of %s", tone, strings.Join(StoryTones, ", "))
+		}
+		voice.Tone = tone
+	}
+	if locale != "" {
+		language, _, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(locale, "_", "-")), "-")
+		if !contains(StoryLocales, language) {
+			return voice, fmt.Errorf("unsupported locale %q, use one of %s", locale, strings.Join(StoryLocales, ", "))
+		}
+		voice.Locale = language
+	}
+	return voice, nil
+}
+
+// clock formats the time of day of t in the voice's time zone, with its UTC offset
+func (v StoryVoice) clock(t time.Time) string {
+	return inZone(t, v.Location).Format(ClockLayout)
+}
+
+// phrase formats the catalog phrase for key, preferring the voice's tone and locale and
+// falling back to the locale's default tone, then to English. Phrases use indexed verbs
+// (%[2]s), so a translation may reorder or leave out arguments.
+func (v StoryVoice) phrase(key string, args ...interface{}) string {
+	text := v.lookup(key)
+	if len(args) == 0 || !strings.Contains(text, "%") {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+func (v StoryVoice) lookup(key string) string {
+	locale := v.Locale
+	if locale == "" {
+		locale = "en"
+	}
+	for _, phrases := range []map[string]string{storyPhrases[locale], storyPhrases["en"]} {
+		if v.Tone != "" {
+			if text, ok := phrases[key+"@"+v.Tone]; ok {
+				return text
+			}
+		}
+		if text, ok := phrases[key]; ok {
+			return text
+		}
+	}
+	return key
+}
+
+// storyPhrases holds the story templates per locale. A key with an "@tone" suffix
+// overrides the phrase for that tone; an empty phrase leaves the sentence out.
+var storyPhrases = map[string]map[string]string{
+	"en": {
+		"story.none": "No incident detected",
+
+		"timeline.intro":                  "Here's what happened:\n\n",
+		"timeline.intro@executive":        "What happened:\n\n",
+		"timeline.intro@terse":            "",
+		"timeline.first":                  "At %[1]s, we first noticed %[2]s on %[3]s at %.1[4]f%%. ",
+		"timeline.first.peak":             "At %[1]s, we first noticed %[2]s on %[3]s hitting %.1[4]f%%. ",
+		"timeline.first@executive":        "At %[1]s, %[3]s began experiencing %[2]s. ",
+		"timeline.first.peak@executive":   "At %[1]s, %[3]s began experiencing %[2]s. ",
+		"timeline.first@terse":            "%[1]s %[2]s on %[3]s (%.1[4]f%%).",
+		"timeline.first.peak@terse":       "%[1]s %[2]s on %[3]s (%.1[4]f%%).",
+		"timeline.trend":                  "Leading up to it, %[1]s. ",
+		"timeline.trend@executive":        "",
+		"timeline.trend@terse":            " Trend: %[2]s over %[3]s.",
+		"timeline.also.first":             "Around the same time, %[1]s also showed issues. ",
+		"timeline.also.first@terse":       " Also: %[1]s.",
+		"timeline.next":                   "\n%[1]s later (%[2]s), this caused %[3]s (%.1[4]f%%). ",
+		"timeline.next.degrade":           "\n%[1]s later (%[2]s), this caused %[3]s to degrade (%.1[4]f%%). ",
+		"timeline.next@executive":         "\n%[1]s later, this led to %[3]s. ",
+		"timeline.next.degrade@executive": "\n%[1]s later, this led to %[3]s. ",
+		"timeline.next@terse":             "\n%[2]s %[3]s (%.1[4]f%%, +%[1]s).",
+		"timeline.next.degrade@terse":     "\n%[2]s %[3]s (%.1[4]f%%, +%[1]s).",
+		"timeline.also.next":              "We also saw %[1]s failing. ",
+		"timeline.also.next@executive":    "",
+		"timeline.also.next@terse":        " Also: %[1]s.",
+		"timeline.end":                    "\n\nThe situation fully developed over %[1]s.",
+		"timeline.end@executive":          "\n\nThe incident developed over %[1]s.",
+		"timeline.end@terse":              "\nDuration: %[1]s.",
+		"list.and":                        " and ",
+
+		"rootcause.intro":                     "Looking at the timeline and correlation patterns, ",
+		"rootcause.intro@executive":           "Based on our analysis, ",
+		"rootcause.intro@terse":               "Root cause: ",
+		"rootcause.confidence.high":           "I'm highly confident that ",
+		"rootcause.confidence.high@executive": "we are highly confident that ",
+		"rootcause.confidence.strong":         "the evidence strongly suggests ",
+		"rootcause.confidence.likely":         "it appears ",
+		"rootcause.confidence.possible":       "it's possible that ",
+		"rootcause.confidence.high@terse":     "",
+		"rootcause.confidence.strong@terse":   "",
+		"rootcause.confidence.likely@terse":   "",
+		"rootcause.confidence.possible@terse": "",
+		"rootcause.cause":                     "the root cause was %[1]s on %[2]s. ",
+		"rootcause.cause@executive":           "the incident was triggered by %[1]s on %[2]s. ",
+		"rootcause.cause@terse":               "%[1]s on %[2]s (%[3]d%% confidence). ",
+		"rootcause.earliest":                  "This was the first thing to fail. ",
+		"rootcause.earliest@executive":        "",
+		"rootcause.earliest@terse":            "First to fail. ",
+		"rootcause.cascade":                   "After it hit critical levels, we saw a cascade effect where other resources started degrading. ",
+		"rootcause.cascade@executive":         "From there, the problem spread to other parts of the system. ",
+		"rootcause.cascade@terse":             "Caused a cascade. ",
+		"rootcause.logs":                      "The error logs around this time corroborate this. ",
+		"rootcause.logs@executive":            "",
+		"rootcause.logs@terse":                "Confirmed by error logs. ",
+		"rootcause.alternative":               "\n\nThere's also a chance that %[1]s contributed, but the timing makes the primary cause more likely.",
+		"rootcause.alternative@executive":     "",
+		"rootcause.alternative@terse":         "\nAlternative: %[1]s.",
+
+		"impact.summary":                  "%[1]s\n\n",
+		"impact.summary@terse":            "",
+		"impact.severity.high":            "This is a significant incident. ",
+		"impact.severity.high@executive":  "This is a significant incident with likely business impact. ",
+		"impact.severity.high@terse":      "Severity: significant. ",
+		"impact.severity.moderate":        "This is a moderate incident. ",
+		"impact.severity.moderate@terse":  "Severity: moderate. ",
+		"impact.severity.contained":       "This is a relatively contained incident. ",
+		"impact.severity.contained@terse": "Severity: contained. ",
+		"impact.hosts.one":                "Only %[1]s was directly affected, ",
+		"impact.hosts.one@terse":          "Hosts: %[1]s. ",
+		"impact.hosts.many":               "%[1]d hosts were affected, ",
+		"impact.hosts.many@terse":         "Hosts: %[1]d. ",
+		"impact.resources":                "with %[1]d resource types experiencing issues. ",
+		"impact.resources@terse":          "Resource types: %[1]d. ",
+		"impact.cascade":                  "\n\nThe cascade went %[1]d levels deep: ",
+		"impact.cascade@executive":        "",
+		"impact.cascade@terse":            "\nCascade depth: %[1]d. ",
+		"impact.cascade.first":            "%[1]s failed first, ",
+		"impact.cascade.first@executive":  "",
+		"impact.cascade.first@terse":      "%[1]s → ",
+		"impact.cascade.then":             "then %[1]s degraded as a result.",
+		"impact.cascade.then@executive":   "",
+		"impact.cascade.then@terse":       "%[1]s.",
+		"impact.users.likely":             "\n\nUser-facing services were likely impacted during this time.",
+		"impact.users.likely@executive":   "\n\nCustomers were likely affected during this time.",
+		"impact.users.likely@terse":       "\nUsers: likely impacted.",
+		"impact.users.possible":           "\n\nSome user impact is possible, though services remained partially available.",
+		"impact.users.possible@terse":     "\nUsers: possibly impacted.",
+		"impact.users.minimal":            "\n\nUser impact was minimal - we caught this before it became user-facing.",
+		"impact.users.minimal@executive":  "\n\nCustomer impact was minimal; the issue was caught before it became visible.",
+		"impact.users.minimal@terse":      "\nUsers: minimal impact.",
+
+		"summary":           "%[1]s on %[2]s caused %[3]s incident lasting %[4]s",
+		"summary@executive": "A %[3]s incident lasting %[4]s, triggered by %[1]s on %[2]s",
+		"summary@terse":     "%[1]s on %[2]s: %[3]s, %[4]s",
+		"severity.CRITICAL": "critical",
+		"severity.HIGH":     "high",
+		"severity.MEDIUM":   "medium",
+		"severity.LOW":      "low",
+
+		"duration.seconds":       "%[1]d seconds",
+		"duration.minute":        "about a minute",
+		"duration.minutes":       "%[1]d minutes",
+		"duration.hours":         "%[1]d hours",
+		"duration.hours_minutes": "%[1]d hours and %[2]d minutes",
+
+		"resource.MEMORY":      "memory pressure",
+		"resource.DISK":        "disk space/I/O issues",
+		"resource.CPU":         "CPU load",
+		"resource.NETWORK":     "network/latency problems",
+		"resource.PROCESS":     "process failures",
+		"resource.DATABASE":    "database problems",
+		"resource.CONTAINER":   "container failures",
+		"resource.APPLICATION": "application errors",
+		"resource.other":       "%[1]s issues",
+	},
+
+	"es": {
+		"story.none": "No se detectó ningún incidente",
+
+		"timeline.intro":                  "Esto es lo que ocurrió:\n\n",
+		"timeline.intro@executive":        "Qué ocurrió:\n\n",
+		"timeline.intro@terse":            "",
+		"timeline.first":                  "A las %[1]s detectamos por primera vez %[2]s en %[3]s, en %.1[4]f%%. ",
+		"timeline.first.peak":             "A las %[1]s detectamos por primera vez %[2]s en %[3]s, alcanzando %.1[4]f%%. ",
+		"timeline.first@executive":        "A las %[1]s, %[3]s empezó a sufrir %[2]s. ",
+		"timeline.first.peak@executive":   "A las %[1]s, %[3]s empezó a sufrir %[2]s. ",
+		"timeline.first@terse":            "%[1]s %[2]s en %[3]s (%.1[4]f%%).",
+		"timeline.first.peak@terse":       "%[1]s %[2]s en %[3]s (%.1[4]f%%).",
+		"timeline.trend":                  "Antes de eso, el valor pasó por %[2]s en %[3]s. ",
+		"timeline.trend@executive":        "",
+		"timeline.trend@terse":            " Tendencia: %[2]s en %[3]s.",
+		"timeline.also.first":             "Casi al mismo tiempo, también aparecieron %[1]s. ",
+		"timeline.also.first@terse":       " También: %[1]s.",
+		"timeline.next":                   "\n%[1]s después (%[2]s), esto provocó %[3]s (%.1[4]f%%). ",
+		"timeline.next.degrade":           "\n%[1]s después (%[2]s), esto provocó %[3]s y degradó el servicio (%.1[4]f%%). ",
+		"timeline.next@executive":         "\n%[1]s después, esto derivó en %[3]s. ",
+		"timeline.next.degrade@executive": "\n%[1]s después, esto derivó en %[3]s. ",
+		"timeline.next@terse":             "\n%[2]s %[3]s (%.1[4]f%%, +%[1]s).",
+		"timeline.next.degrade@terse":     "\n%[2]s %[3]s (%.1[4]f%%, +%[1]s).",
+		"timeline.also.next":              "También vimos %[1]s. ",
+		"timeline.also.next@executive":    "",
+		"timeline.also.next@terse":        " También: %[1]s.",
+		"timeline.end":                    "\n\nLa situación se desarrolló por completo en %[1]s.",
+		"timeline.end@executive":          "\n\nEl incidente se desarrolló en %[1]s.",
+		"timeline.end@terse":              "\nDuración: %[1]s.",
+		"list.and":                        " y ",
+
+		"rootcause.intro":                     "Observando la cronología y los patrones de correlación, ",
+		"rootcause.intro@executive":           "Según nuestro análisis, ",
+		"rootcause.intro@terse":               "Causa raíz: ",
+		"rootcause.confidence.high":           "estoy muy seguro de que ",
+		"rootcause.confidence.high@executive": "tenemos mucha confianza en que ",
+		"rootcause.confidence.strong":         "la evidencia indica claramente que ",
+		"rootcause.confidence.likely":         "parece que ",
+		"rootcause.confidence.possible":       "es posible que ",
+		"rootcause.confidence.high@terse":     "",
+		"rootcause.confidence.strong@terse":   "",
+		"rootcause.confidence.likely@terse":   "",
+		"rootcause.confidence.possible@terse": "",
+		"rootcause.cause":                     "la causa raíz fue %[1]s en %[2]s. ",
+		"rootcause.cause@executive":           "el incidente lo desencadenó %[1]s en %[2]s. ",
+		"rootcause.cause@terse":               "%[1]s en %[2]s (confianza del %[3]d%%). ",
+		"rootcause.earliest":                  "Fue lo primero en fallar. ",
+		"rootcause.earliest@executive":        "",
+		"rootcause.earliest@terse":            "Primero en fallar. ",
+		"rootcause.cascade":                   "Tras alcanzar niveles críticos, vimos un efecto cascada en el que otros recursos empezaron a degradarse. ",
+		"rootcause.cascade@executive":         "A partir de ahí, el problema se extendió a otras partes del sistema. ",
+		"rootcause.cascade@terse":             "Provocó una cascada. ",
+		"rootcause.logs":                      "Los registros de errores de ese momento lo corroboran. ",
+		"rootcause.logs@executive":            "",
+		"rootcause.logs@terse":                "Confirmado por los registros de errores. ",
+		"rootcause.alternative":               "\n\nTambién es posible que %[1]s contribuyeran, pero la secuencia temporal hace más probable la causa principal.",
+		"rootcause.alternative@executive":     "",
+		"rootcause.alternative@terse":         "\nAlternativa: %[1]s.",
+
+		"impact.summary":                  "",
+		"impact.severity.high":            "Es un incidente importante. ",
+		"impact.severity.high@executive":  "Es un incidente importante con probable impacto en el negocio. ",
+		"impact.severity.high@terse":      "Gravedad: importante. ",
+		"impact.severity.moderate":        "Es un incidente moderado. ",
+		"impact.severity.moderate@terse":  "Gravedad: moderada. ",
+		"impact.severity.contained":       "Es un incidente relativamente acotado. ",
+		"impact.severity.contained@terse": "Gravedad: acotada. ",
+		"impact.hosts.one":                "Solo %[1]s se vio afectado directamente, ",
+		"impact.hosts.one@terse":          "Hosts: %[1]s. ",
+		"impact.hosts.many":               "Se vieron afectados %[1]d hosts, ",
+		"impact.hosts.many@terse":         "Hosts: %[1]d. ",
+		"impact.resources":                "con problemas en %[1]d tipos de recursos. ",
+		"impact.resources@terse":          "Tipos de recursos: %[1]d. ",
+		"impact.cascade":                  "\n\nLa cascada alcanzó %[1]d niveles: ",
+		"impact.cascade@executive":        "",
+		"impact.cascade@terse":            "\nProfundidad de la cascada: %[1]d. ",
+		"impact.cascade.first":            "primero falló %[1]s, ",
+		"impact.cascade.first@executive":  "",
+		"impact.cascade.first@terse":      "%[1]s → ",
+		"impact.cascade.then":             "y como consecuencia se degradó %[1]s.",
+		"impact.cascade.then@executive":   "",
+		"impact.cascade.then@terse":       "%[1]s.",
+		"impact.users.likely":             "\n\nEs probable que los servicios de cara al usuario se vieran afectados durante este tiempo.",
+		"impact.users.likely@executive":   "\n\nEs probable que los clientes se vieran afectados durante este tiempo.",
+		"impact.users.likely@terse":       "\nUsuarios: probablemente afectados.",
+		"impact.users.possible":           "\n\nEs posible cierto impacto en los usuarios, aunque los servicios siguieron parcialmente disponibles.",
+		"impact.users.possible@terse":     "\nUsuarios: posiblemente afectados.",
+		"impact.users.minimal":            "\n\nEl impacto en los usuarios fue mínimo: lo detectamos antes de que llegara a ellos.",
+		"impact.users.minimal@executive":  "\n\nEl impacto en los clientes fue mínimo; el problema se detectó antes de que fuera visible.",
+		"impact.users.minimal@terse":      "\nUsuarios: impacto mínimo.",
+
+		"summary":           "%[1]s en %[2]s causó un incidente %[3]s de %[4]s",
+		"summary@executive": "Incidente %[3]s de %[4]s, desencadenado por %[1]s en %[2]s",
+		"summary@terse":     "%[1]s en %[2]s: %[3]s, %[4]s",
+		"severity.CRITICAL": "crítico",
+		"severity.HIGH":     "grave",
+		"severity.MEDIUM":   "moderado",
+		"severity.LOW":      "leve",
+
+		"duration.seconds":       "%[1]d segundos",
+		"duration.minute":        "aproximadamente un minuto",
+		"duration.minutes":       "%[1]d minutos",
+		"duration.hours":         "%[1]d horas",
+		"duration.hours_minutes": "%[1]d horas y %[2]d minutos",
+
+		"resource.MEMORY":      "presión de memoria",
+		"resource.DISK":        "problemas de espacio o E/S de disco",
+		"resource.CPU":         "carga de CPU",
+		"resource.NETWORK":     "problemas de red o latencia",
+		"resource.PROCESS":     "fallos de procesos",
+		"resource.DATABASE":    "problemas de base de datos",
+		"resource.CONTAINER":   "fallos de contenedores",
+		"resource.APPLICATION": "errores de aplicación",
+		"resource.other":       "problemas de %[1]s",
+	},
+
+	"de": {
+		"story.none": "Kein Vorfall erkannt",
+
+		"timeline.intro":                  "Das ist passiert:\n\n",
+		"timeline.intro@executive":        "Was passiert ist:\n\n",
+		"timeline.intro@terse":            "",
+		"timeline.first":                  "Um %[1]s bemerkten wir zuerst %[2]s auf %[3]s bei %.1[4]f%%. ",
+		"timeline.first.peak":             "Um %[1]s bemerkten wir zuerst %[2]s auf %[3]s mit %.1[4]f%%. ",
+		"timeline.first@executive":        "Um %[1]s traten auf %[3]s erste Probleme auf: %[2]s. ",
+		"timeline.first.peak@executive":   "Um %[1]s traten auf %[3]s erste Probleme auf: %[2]s. ",
+		"timeline.first@terse":            "%[1]s %[2]s auf %[3]s (%.1[4]f%%).",
+		"timeline.first.peak@terse":       "%[1]s %[2]s auf %[3]s (%.1[4]f%%).",
+		"timeline.trend":                  "Davor entwickelte sich der Wert über %[3]s so: %[2]s. ",
+		"timeline.trend@executive":        "",
+		"timeline.trend@terse":            " Verlauf: %[2]s in %[3]s.",
+		"timeline.also.first":             "Etwa zur gleichen Zeit zeigten sich auch %[1]s. ",
+		"timeline.also.first@terse":       " Außerdem: %[1]s.",
+		"timeline.next":                   "\n%[1]s später (%[2]s) führte das zu %[3]s (%.1[4]f%%). ",
+		"timeline.next.degrade":           "\n%[1]s später (%[2]s) führte das zu %[3]s und einer Verschlechterung (%.1[4]f%%). ",
+		"timeline.next@executive":         "\n%[1]s später führte das zu %[3]s. ",
+		"timeline.next.degrade@executive": "\n%[1]s später führte das zu %[3]s. ",
+		"timeline.next@terse":             "\n%[2]s %[3]s (%.1[4]f%%, +%[1]s).",
+		"timeline.next.degrade@terse":     "\n%[2]s %[3]s (%.1[4]f%%, +%[1]s).",
+		"timeline.also.next":              "Außerdem sahen wir %[1]s. ",
+		"timeline.also.next@executive":    "",
+		"timeline.also.next@terse":        " Außerdem: %[1]s.",
+		"timeline.end":                    "\n\nDie Lage entwickelte sich vollständig über %[1]s.",
+		"timeline.end@executive":          "\n\nDer Vorfall entwickelte sich über %[1]s.",
+		"timeline.end@terse":              "\nDauer: %[1]s.",
+		"list.and":                        " und ",
+
+		"rootcause.intro":                     "Nach Zeitverlauf und Korrelationsmustern ",
+		"rootcause.intro@executive":           "Nach unserer Analyse ",
+		"rootcause.intro@terse":               "Ursache: ",
+		"rootcause.confidence.high":           "bin ich mir sehr sicher, dass ",
+		"rootcause.confidence.high@executive": "sind wir sehr sicher, dass ",
+		"rootcause.confidence.strong":         "deuten die Hinweise stark darauf hin, dass ",
+		"rootcause.confidence.likely":         "scheint es, dass ",
+		"rootcause.confidence.possible":       "ist es möglich, dass ",
+		"rootcause.confidence.high@terse":     "",
+		"rootcause.confidence.strong@terse":   "",
+		"rootcause.confidence.likely@terse":   "",
+		"rootcause.confidence.possible@terse": "",
+		"rootcause.cause":                     "die Ursache %[1]s auf %[2]s war. ",
+		"rootcause.cause@executive":           "der Vorfall durch %[1]s auf %[2]s ausgelöst wurde. ",
+		"rootcause.cause@terse":               "%[1]s auf %[2]s (Konfidenz %[3]d%%). ",
+		"rootcause.earliest":                  "Das fiel als Erstes aus. ",
+		"rootcause.earliest@executive":        "",
+		"rootcause.earliest@terse":            "Fiel zuerst aus. ",
+		"rootcause.cascade":                   "Nachdem kritische Werte erreicht waren, setzte ein Kaskadeneffekt ein, bei dem sich weitere Ressourcen verschlechterten. ",
+		"rootcause.cascade@executive":         "Von dort breitete sich das Problem auf andere Teile des Systems aus. ",
+		"rootcause.cascade@terse":             "Löste eine Kaskade aus. ",
+		"rootcause.logs":                      "Die Fehlerprotokolle aus dieser Zeit bestätigen das. ",
+		"rootcause.logs@executive":            "",
+		"rootcause.logs@terse":                "Durch Fehlerprotokolle bestätigt. ",
+		"rootcause.alternative":               "\n\nMöglicherweise haben auch %[1]s beigetragen, der zeitliche Ablauf spricht aber für die Hauptursache.",
+		"rootcause.alternative@executive":     "",
+		"rootcause.alternative@terse":         "\nAlternative: %[1]s.",
+
+		"impact.summary":                  "",
+		"impact.severity.high":            "Das ist ein schwerwiegender Vorfall. ",
+		"impact.severity.high@executive":  "Das ist ein schwerwiegender Vorfall mit wahrscheinlichen Auswirkungen auf das Geschäft. ",
+		"impact.severity.high@terse":      "Schwere: hoch. ",
+		"impact.severity.moderate":        "Das ist ein mittelschwerer Vorfall. ",
+		"impact.severity.moderate@terse":  "Schwere: mittel. ",
+		"impact.severity.contained":       "Das ist ein relativ begrenzter Vorfall. ",
+		"impact.severity.contained@terse": "Schwere: begrenzt. ",
+		"impact.hosts.one":                "Nur %[1]s war direkt betroffen, ",
+		"impact.hosts.one@terse":          "Hosts: %[1]s. ",
+		"impact.hosts.many":               "%[1]d Hosts waren betroffen, ",
+		"impact.hosts.many@terse":         "Hosts: %[1]d. ",
+		"impact.resources":                "mit Problemen bei %[1]d Ressourcentypen. ",
+		"impact.resources@terse":          "Ressourcentypen: %[1]d. ",
+		"impact.cascade":                  "\n\nDie Kaskade reichte %[1]d Ebenen tief: ",
+		"impact.cascade@executive":        "",
+		"impact.cascade@terse":            "\nKaskadentiefe: %[1]d. ",
+		"impact.cascade.first":            "zuerst fiel %[1]s aus, ",
+		"impact.cascade.first@executive":  "",
+		"impact.cascade.first@terse":      "%[1]s → ",
+		"impact.cascade.then":             "dann verschlechterte sich dadurch %[1]s.",
+		"impact.cascade.then@executive":   "",
+		"impact.cascade.then@terse":       "%[1]s.",
+		"impact.users.likely":             "\n\nNutzerseitige Dienste waren in dieser Zeit wahrscheinlich beeinträchtigt.",
+		"impact.users.likely@executive":   "\n\nKunden waren in dieser Zeit wahrscheinlich betroffen.",
+		"impact.users.likely@terse":       "\nNutzer: wahrscheinlich betroffen.",
+		"impact.users.possible":           "\n\nAuswirkungen auf Nutzer sind möglich, die Dienste blieben aber teilweise verfügbar.",
+		"impact.users.possible@terse":     "\nNutzer: möglicherweise betroffen.",
+		"impact.users.minimal":            "\n\nDie Auswirkungen auf Nutzer waren minimal – wir haben das Problem erkannt, bevor es sichtbar wurde.",
+		"impact.users.minimal@executive":  "\n\nDie Auswirkungen auf Kunden waren minimal; das Problem wurde erkannt, bevor es sichtbar wurde.",
+		"impact.users.minimal@terse":      "\nNutzer: minimale Auswirkungen.",
+
+		"summary":           "%[1]s auf %[2]s verursachte einen Vorfall (Schwere: %[3]s) von %[4]s",
+		"summary@executive": "Vorfall der Schwere „%[3]s“ über %[4]s, ausgelöst durch %[1]s auf %[2]s",
+		"summary@terse":     "%[1]s auf %[2]s: %[3]s, %[4]s",
+		"severity.CRITICAL": "kritisch",
+		"severity.HIGH":     "hoch",
+		"severity.MEDIUM":   "mittel",
+		"severity.LOW":      "gering",
+
+		"duration.seconds":       "%[1]d Sekunden",
+		"duration.minute":        "etwa eine Minute",
+		"duration.minutes":       "%[1]d Minuten",
+		"duration.hours":         "%[1]d Stunden",
+		"duration.hours_minutes": "%[1]d Stunden und %[2]d Minuten",
+
+		"resource.MEMORY":      "Speicherdruck",
+		"resource.DISK":        "Festplattenplatz- oder E/A-Probleme",
+		"resource.CPU":         "CPU-Last",
+		"resource.NETWORK":     "Netzwerk- oder Latenzprobleme",
+		"resource.PROCESS":     "Prozessausfälle",
+		"resource.DATABASE":    "Datenbankprobleme",
+		"resource.CONTAINER":   "Containerausfälle",
+		"resource.APPLICATION": "Anwendungsfehler",
+		"resource.other":       "%[1]s-Probleme",
+	},
+
+	"hi": {
+		"story.none": "कोई घटना नहीं मिली",
+
+		"timeline.intro":                  "यह हुआ:\n\n",
+		"timeline.intro@executive":        "क्या हुआ:\n\n",
+		"timeline.intro@terse":            "",
+		"timeline.first":                  "%[1]s पर हमने पहली बार %[3]s पर %[2]s देखा, %.1[4]f%% पर। ",
+		"timeline.first.peak":             "%[1]s पर हमने पहली बार %[3]s पर %[2]s देखा, जो %.1[4]f%% तक पहुँच गया। ",
+		"timeline.first@executive":        "%[1]s पर %[3]s पर %[2]s शुरू हुआ। ",
+		"timeline.first.peak@executive":   "%[1]s पर %[3]s पर %[2]s शुरू हुआ। ",
+		"timeline.first@terse":            "%[1]s %[3]s पर %[2]s (%.1[4]f%%)।",
+		"timeline.first.peak@terse":       "%[1]s %[3]s पर %[2]s (%.1[4]f%%)।",
+		"timeline.trend":                  "उससे पहले %[3]s में मान %[2]s रहा। ",
+		"timeline.trend@executive":        "",
+		"timeline.trend@terse":            " रुझान: %[3]s में %[2]s।",
+		"timeline.also.first":             "लगभग उसी समय %[1]s भी दिखाई दिए। ",
+		"timeline.also.first@terse":       " साथ ही: %[1]s।",
+		"timeline.next":                   "\n%[1]s बाद (%[2]s), इसके कारण %[3]s हुआ (%.1[4]f%%)। ",
+		"timeline.next.degrade":           "\n%[1]s बाद (%[2]s), इसके कारण %[3]s हुआ और सेवा बिगड़ गई (%.1[4]f%%)। ",
+		"timeline.next@executive":         "\n%[1]s बाद इसके कारण %[3]s हुआ। ",
+		"timeline.next.degrade@executive": "\n%[1]s बाद इसके कारण %[3]s हुआ। ",
+		"timeline.next@terse":             "\n%[2]s %[3]s (%.1[4]f%%, +%[1]s)।",
+		"timeline.next.degrade@terse":     "\n%[2]s %[3]s (%.1[4]f%%, +%[1]s)।",
+		"timeline.also.next":              "हमने %[1]s भी देखा। ",
+		"timeline.also.next@executive":    "",
+		"timeline.also.next@terse":        " साथ ही: %[1]s।",
+		"timeline.end":                    "\n\nपूरी स्थिति %[1]s में विकसित हुई।",
+		"timeline.end@executive":          "\n\nयह घटना %[1]s में विकसित हुई।",
+		"timeline.end@terse":              "\nअवधि: %[1]s।",
+		"list.and":                        " और ",
+
+		"rootcause.intro":                     "समयरेखा और सहसंबंध पैटर्न को देखते हुए, ",
+		"rootcause.intro@executive":           "हमारे विश्लेषण के अनुसार, ",
+		"rootcause.intro@terse":               "मूल कारण: ",
+		"rootcause.confidence.high":           "मुझे पूरा भरोसा है कि ",
+		"rootcause.confidence.high@executive": "हमें पूरा भरोसा है कि ",
+		"rootcause.confidence.strong":         "सबूत साफ़ संकेत देते हैं कि ",
+		"rootcause.confidence.likely":         "ऐसा लगता है कि ",
+		"rootcause.confidence.possible":       "संभव है कि ",
+		"rootcause.confidence.high@terse":     "",
+		"rootcause.confidence.strong@terse":   "",
+		"rootcause.confidence.likely@terse":   "",
+		"rootcause.confidence.possible@terse": "",
+		"rootcause.cause":                     "मूल कारण %[2]s पर %[1]s था। ",
+		"rootcause.cause@executive":           "यह घटना %[2]s पर %[1]s से शुरू हुई। ",
+		"rootcause.cause@terse":               "%[2]s पर %[1]s (%[3]d%% भरोसा)। ",
+		"rootcause.earliest":                  "यही सबसे पहले विफल हुआ। ",
+		"rootcause.earliest@executive":        "",
+		"rootcause.earliest@terse":            "सबसे पहले विफल। ",
+		"rootcause.cascade":                   "गंभीर स्तर पर पहुँचने के बाद एक कैस्केड प्रभाव दिखा, जिसमें अन्य संसाधन भी बिगड़ने लगे। ",
+		"rootcause.cascade@executive":         "वहाँ से समस्या सिस्टम के दूसरे हिस्सों में फैल गई। ",
+		"rootcause.cascade@terse":             "कैस्केड शुरू हुआ। ",
+		"rootcause.logs":                      "उस समय के एरर लॉग इसकी पुष्टि करते हैं। ",
+		"rootcause.logs@executive":            "",
+		"rootcause.logs@terse":                "एरर लॉग से पुष्टि। ",
+		"rootcause.alternative":               "\n\nयह भी संभव है कि %[1]s ने योगदान दिया हो, लेकिन समय को देखते हुए मुख्य कारण अधिक संभावित है।",
+		"rootcause.alternative@executive":     "",
+		"rootcause.alternative@terse":         "\nविकल्प: %[1]s।",
+
+		"impact.summary":                  "",
+		"impact.severity.high":            "यह एक गंभीर घटना है। ",
+		"impact.severity.high@executive":  "यह एक गंभीर घटना है, जिसका व्यवसाय पर असर होने की संभावना है। ",
+		"impact.severity.high@terse":      "गंभीरता: अधिक। ",
+		"impact.severity.moderate":        "यह एक मध्यम घटना है। ",
+		"impact.severity.moderate@terse":  "गंभीरता: मध्यम। ",
+		"impact.severity.contained":       "यह एक अपेक्षाकृत सीमित घटना है। ",
+		"impact.severity.contained@terse": "गंभीरता: सीमित। ",
+		"impact.hosts.one":                "केवल %[1]s सीधे प्रभावित हुआ, ",
+		"impact.hosts.one@terse":          "होस्ट: %[1]s। ",
+		"impact.hosts.many":               "%[1]d होस्ट प्रभावित हुए, ",
+		"impact.hosts.many@terse":         "होस्ट: %[1]d। ",
+		"impact.resources":                "और %[1]d प्रकार के संसाधनों में समस्याएँ आईं। ",
+		"impact.resources@terse":          "संसाधन प्रकार: %[1]d। ",
+		"impact.cascade":                  "\n\nकैस्केड %[1]d स्तर गहरा था: ",
+		"impact.cascade@executive":        "",
+		"impact.cascade@terse":            "\nकैस्केड गहराई: %[1]d। ",
+		"impact.cascade.first":            "पहले %[1]s विफल हुआ, ",
+		"impact.cascade.first@executive":  "",
+		"impact.cascade.first@terse":      "%[1]s → ",
+		"impact.cascade.then":             "फिर उसके कारण %[1]s बिगड़ा।",
+		"impact.cascade.then@executive":   "",
+		"impact.cascade.then@terse":       "%[1]s।",
+		"impact.users.likely":             "\n\nइस दौरान उपयोगकर्ताओं से जुड़ी सेवाएँ संभवतः प्रभावित हुईं।",
+		"impact.users.likely@executive":   "\n\nइस दौरान ग्राहक संभवतः प्रभावित हुए।",
+		"impact.users.likely@terse":       "\nउपयोगकर्ता: संभवतः प्रभावित।",
+		"impact.users.possible":           "\n\nउपयोगकर्ताओं पर कुछ असर संभव है, हालाँकि सेवाएँ आंशिक रूप से उपलब्ध रहीं।",
+		"impact.users.possible@terse":     "\nउपयोगकर्ता: आंशिक असर संभव।",
+		"impact.users.minimal":            "\n\nउपयोगकर्ताओं पर असर न्यूनतम रहा - हमने इसे उपयोगकर्ताओं तक पहुँचने से पहले पकड़ लिया।",
+		"impact.users.minimal@executive":  "\n\nग्राहकों पर असर न्यूनतम रहा; समस्या दिखने से पहले पकड़ ली गई।",
+		"impact.users.minimal@terse":      "\nउपयोगकर्ता: न्यूनतम असर।",
+
+		"summary":           "%[2]s पर %[1]s के कारण %[4]s चली एक %[3]s घटना हुई",
+		"summary@executive": "%[4]s चली एक %[3]s घटना, जो %[2]s पर %[1]s से शुरू हुई",
+		"summary@terse":     "%[2]s पर %[1]s: %[3]s, %[4]s",
+		"severity.CRITICAL": "अति गंभीर",
+		"severity.HIGH":     "गंभीर",
+		"severity.MEDIUM":   "मध्यम",
+		"severity.LOW":      "मामूली",
+
+		"duration.seconds":       "%[1]d सेकंड",
+		"duration.minute":        "लगभग एक मिनट",
+		"duration.minutes":       "%[1]d मिनट",
+		"duration.hours":         "%[1]d घंटे",
+		"duration.hours_minutes": "%[1]d घंटे %[2]d मिनट",
+
+		"resource.MEMORY":      "मेमोरी दबाव",
+		"resource.DISK":        "डिस्क स्पेस/I/O समस्याएँ",
+		"resource.CPU":         "CPU लोड",
+		"resource.NETWORK":     "नेटवर्क/लेटेंसी समस्याएँ",
+		"resource.PROCESS":     "प्रोसेस विफलताएँ",
+		"resource.DATABASE":    "डेटाबेस समस्याएँ",
+		"resource.CONTAINER":   "कंटेनर विफलताएँ",
+		"resource.APPLICATION": "एप्लिकेशन त्रुटियाँ",
+		"resource.other":       "%[1]s समस्याएँ",
+	},
+}
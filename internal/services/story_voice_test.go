@@ -0,0 +1,64 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+)
+
+func TestParseStoryVoice(t *testing.T) {
+	voice, err := ParseStoryVoice("", "")
+	if err != nil || voice != (StoryVoice{Tone: ToneCalmEngineer, Locale: "en"}) {
+		t.Fatalf("expected the calm engineer in English by default, got %+v, %v", voice, err)
+	}
+	voice, err = ParseStoryVoice("Terse", "es-MX")
+	if err != nil || voice != (StoryVoice{Tone: ToneTerse, Locale: "es"}) {
+		t.Fatalf("expected terse Spanish, got %+v, %v", voice, err)
+	}
+	if _, err := ParseStoryVoice("pirate", ""); err == nil {
+		t.Error("expected an unknown tone to be rejected")
+	}
+	if _, err := ParseStoryVoice("", "fr"); err == nil {
+		t.Error("expected an unsupported locale to be rejected")
+	}
+}
+
+func TestIncidentTeller_TellStoryIn(t *testing.T) {
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	alerts := []domain.Alert{
+		{ID: "a1", Name: "ram_in_use", Host: "web-1", Chart: "system.ram", ResourceType: domain.ResourceMemory, Status: domain.StatusCritical, Value: 95, OccurredAt: base},
+		{ID: "a2", Name: "disk_util", Host: "web-1", Chart: "disk.io", ResourceType: domain.ResourceDisk, Status: domain.StatusCritical, Value: 85, OccurredAt: base.Add(5 * time.Minute)},
+	}
+	teller := NewIncidentTeller()
+
+	story := teller.TellStory(alerts)
+	if !strings.HasPrefix(story.Timeline, "Here's what happened:\n\nAt 10:00:00 +00:00, we first noticed memory pressure on web-1 hitting 95.0%.") {
+		t.Errorf("unexpected default timeline: %q", story.Timeline)
+	}
+
+	berlin := teller.TellStoryIn(alerts, StoryVoice{Location: time.FixedZone("CET", 3600)})
+	if !strings.Contains(berlin.Timeline, "At 11:00:00 +01:00,") || !strings.Contains(berlin.Timeline, "(11:05:00 +01:00)") {
+		t.Errorf("expected times in the voice's time zone, got %q", berlin.Timeline)
+	}
+	if _, offset := berlin.GeneratedAt.Zone(); offset != 3600 {
+		t.Errorf("expected the generation time in the voice's time zone, got %v", berlin.GeneratedAt)
+	}
+	if !strings.HasPrefix(story.Summary, "ram_in_use on web-1 caused ") || !strings.HasSuffix(story.Summary, " incident lasting 5 minutes") {
+		t.Errorf("unexpected default summary: %q", story.Summary)
+	}
+
+	spanish := teller.TellStoryIn(alerts, StoryVoice{Tone: ToneCalmEngineer, Locale: "es"})
+	if !strings.Contains(spanish.Timeline, "presión de memoria en web-1") || !strings.Contains(spanish.Timeline, "5 minutos") {
+		t.Errorf("expected a Spanish timeline, got %q", spanish.Timeline)
+	}
+
+	terse := teller.TellStoryIn(alerts, StoryVoice{Tone: ToneTerse, Locale: "en"})
+	if !strings.HasPrefix(terse.RootCause, "Root cause: memory pressure on web-1 (") {
+		t.Errorf("expected a terse root cause, got %q", terse.RootCause)
+	}
+	if len(terse.Fix.ImmediateActions) != len(story.Fix.ImmediateActions) {
+		t.Error("expected the fixes not to depend on the voice")
+	}
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/playbook"
+	"incident-teller/internal/ports"
+	"incident-teller/internal/report"
+	"incident-teller/internal/ticketing"
+)
+
+// TicketManager files tracker tickets for incidents, with the executive summary,
+// technical report and fix playbook, and closes them when the incidents resolve
+type TicketManager struct {
+	analyzer    *ComprehensiveIncidentAnalyzer
+	tracker     ticketing.Tracker
+	store       ports.TicketStore
+	autoCreate  bool
+	minSeverity string // "critical" or "warning"
+
+	mu sync.Mutex // Serializes filing so an incident gets one ticket
+}
+
+// NewTicketManager creates a ticket manager filing tickets in tracker
+func NewTicketManager(tracker ticketing.Tracker, store ports.TicketStore) *TicketManager {
+	return &TicketManager{
+		analyzer: NewComprehensiveIncidentAnalyzer(),
+		tracker:  tracker,
+		store:    store,
+	}
+}
+
+// SetAutoCreate files tickets for active incidents of at least minSeverity
+// ("critical" or "warning") as they are saved
+func (m *TicketManager) SetAutoCreate(minSeverity string) {
+	m.autoCreate = true
+	m.minSeverity = minSeverity
+}
+
+// SetPropagationLearner enables learned propagation patterns in ticket analysis
+func (m *TicketManager) SetPropagationLearner(learner *PropagationLearner) {
+	m.analyzer.SetPropagationLearner(learner)
+}
+
+// SetPlaybooks makes the ticket's fix playbook use the organization's playbooks
+func (m *TicketManager) SetPlaybooks(store *playbook.Store) {
+	m.analyzer.SetPlaybooks(store)
+}
+
+// Ticket returns the ticket of an incident, or nil if none was filed
+func (m *TicketManager) Ticket(ctx context.Context, incidentID string) (*domain.Ticket, error) {
+	return m.store.GetTicket(ctx, incidentID)
+}
+
+// CreateTicket files a ticket for the incident. If one was filed already it is returned
+// with created false.
+func (m *TicketManager) CreateTicket(ctx context.Context, incident domain.Incident) (ticket domain.Ticket, created bool, err error) {
+	if len(incident.Events) == 0 {
+		return domain.Ticket{}, false, fmt.Errorf("incident %s has no alerts", incident.ID)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, err := m.store.GetTicket(ctx, incident.ID)
+	if err != nil {
+		return domain.Ticket{}, false, err
+	}
+	if existing != nil {
+		return *existing, false, nil
+	}
+
+	intelligence := m.analyzer.AnalyzeIncident(incident)
+	title := incident.Title
+	if title == "" {
+		title = fmt.Sprintf("Incident %s", incident.ID)
+	}
+	ref, err := m.tracker.Create(ctx, ticketing.Issue{
+		Title: fmt.Sprintf("[%s] %s", incidentSeverity(incident), title),
+		Documents: []report.Document{
+			ExecutiveSummaryDocument(intelligence),
+			TechnicalReportDocument(intelligence),
+			ActionableFixDocument(intelligence.ActionableFixes),
+		},
+		Labels: []string{"incident", incidentSeverity(incident)},
+	})
+	if err != nil {
+		return domain.Ticket{}, false, err
+	}
+
+	ticket = domain.Ticket{
+		IncidentID: incident.ID,
+		Tracker:    m.tracker.Name(),
+		Key:        ref.Key,
+		URL:        ref.URL,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := m.store.SaveTicket(ctx, ticket); err != nil {
+		return domain.Ticket{}, false, fmt.Errorf("ticket %s was filed but not stored: %w", ref.URL, err)
+	}
+	return ticket, true, nil
+}
+
+// Sync files a ticket for a saved incident when auto-creation applies to it, and closes
+// the incident's ticket once the incident is resolved
+func (m *TicketManager) Sync(ctx context.Context, incident domain.Incident) error {
+	if incident.ResolvedAt == nil {
+		if m.autoCreate && m.severityReached(incident) {
+			_, _, err := m.CreateTicket(ctx, incident)
+			return err
+		}
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ticket, err := m.store.GetTicket(ctx, incident.ID)
+	if err != nil || ticket == nil || ticket.ResolvedAt != nil {
+		return err
+	}
+
+	comment := fmt.Sprintf("Incident resolved at %s after %s.",
+		incident.ResolvedAt.UTC().Format(time.RFC3339), incident.Duration(time.Now()).Round(time.Second))
+	if err := m.tracker.Resolve(ctx, ticket.Key, comment); err != nil {
+		return err
+	}
+
+	resolvedAt := *incident.ResolvedAt
+	ticket.ResolvedAt = &resolvedAt
+	return m.store.SaveTicket(ctx, *ticket)
+}
+
+func (m *TicketManager) severityReached(incident domain.Incident) bool {
+	switch incidentSeverity(incident) {
+	case "critical":
+		return true
+	case "warning":
+		return m.minSeverity == "warning"
+	default:
+		return false
+	}
+}
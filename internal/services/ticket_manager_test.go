@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/report"
+	"incident-teller/internal/ticketing"
+)
+
+type fakeTracker struct {
+	created  []ticketing.Issue
+	resolved []string
+}
+
+func (f *fakeTracker) Name() string { return "fake" }
+
+func (f *fakeTracker) Create(_ context.Context, issue ticketing.Issue) (ticketing.Reference, error) {
+	f.created = append(f.created, issue)
+	return ticketing.Reference{Key: "OPS-1", URL: "https://tracker.example.com/OPS-1"}, nil
+}
+
+func (f *fakeTracker) Resolve(_ context.Context, key, _ string) error {
+	f.resolved = append(f.resolved, key)
+	return nil
+}
+
+type fakeTicketStore map[string]domain.Ticket
+
+func (s fakeTicketStore) GetTicket(_ context.Context, incidentID string) (*domain.Ticket, error) {
+	if ticket, ok := s[incidentID]; ok {
+		return &ticket, nil
+	}
+	return nil, nil
+}
+
+func (s fakeTicketStore) SaveTicket(_ context.Context, ticket domain.Ticket) error {
+	s[ticket.IncidentID] = ticket
+	return nil
+}
+
+func TestTicketManager_AutoCreateAndResolve(t *testing.T) {
+	tracker := &fakeTracker{}
+	store := fakeTicketStore{}
+	manager := NewTicketManager(tracker, store)
+	manager.SetAutoCreate("critical")
+	ctx := context.Background()
+
+	start := time.Now().Add(-10 * time.Minute)
+	incident := domain.Incident{ID: "inc-1", Title: "Disk full on db-01", Status: domain.StatusCritical, StartedAt: start,
+		Events: []domain.Alert{{ID: "a-1", Name: "disk_space", Host: "db-01", Status: domain.StatusCritical,
+			ResourceType: domain.ResourceDisk, OccurredAt: start}}}
+
+	// Saving an active incident again doesn't file a second ticket
+	for i := 0; i < 2; i++ {
+		if err := manager.Sync(ctx, incident); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(tracker.created) != 1 || store["inc-1"].URL != "https://tracker.example.com/OPS-1" {
+		t.Fatalf("expected one stored ticket, created %d: %+v", len(tracker.created), store)
+	}
+	body := report.Markdown{}.Render(tracker.created[0].Documents[0])
+	if !strings.Contains(tracker.created[0].Title, "Disk full on db-01") || !strings.Contains(body, "EXECUTIVE") ||
+		len(tracker.created[0].Documents) != 3 {
+		t.Errorf("unexpected issue %q with body %q", tracker.created[0].Title, body)
+	}
+
+	resolved := time.Now()
+	incident.ResolvedAt = &resolved
+	for i := 0; i < 2; i++ {
+		if err := manager.Sync(ctx, incident); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(tracker.resolved) != 1 || store["inc-1"].ResolvedAt == nil {
+		t.Errorf("expected the ticket to be closed once, closed %v: %+v", tracker.resolved, store["inc-1"])
+	}
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+	"incident-teller/internal/ports"
+)
+
+// timelineExportCursor names the timeline exporter's cursor in the ExportCursorStore
+const timelineExportCursor = "otlp_timeline"
+
+// Incidents are exported this long after they were resolved, so an incident whose
+// resolution is stored a little late still sorts after the cursor
+const timelineExportSettle = 5 * time.Minute
+
+// TimelineLogExporter emits the timeline of finalized (resolved) incidents as OTLP log records,
+// one record per timeline event. Incidents are exported in resolution order, and the
+// resolution time of the last one is persisted, so each is exported once across restarts.
+type TimelineLogExporter struct {
+	exporter *observability.OTLPLogExporter
+	builder  *EnhancedTimelineBuilder
+	grouper  *AlertGrouper
+	cursors  ports.ExportCursorStore
+
+	mu sync.Mutex // Serializes exports so the cursor only moves forward
+}
+
+// NewTimelineLogExporter creates a timeline exporter keeping its progress in cursors. On the
+// first run, incidents resolved before then are considered already exported (avoids
+// sending the history).
+func NewTimelineLogExporter(exporter *observability.OTLPLogExporter, cursors ports.ExportCursorStore) *TimelineLogExporter {
+	grouper := NewAlertGrouper(15 * time.Minute)
+	return &TimelineLogExporter{
+		exporter: exporter,
+		builder:  NewEnhancedTimelineBuilder(grouper),
+		grouper:  grouper,
+		cursors:  cursors,
+	}
+}
+
+// ExportFinalized exports the incidents resolved after the cursor and settled by now, oldest
+// first. The cursor moves past a resolution time once all its incidents are exported, so a
+// failed export is retried by the next call. A reopened incident is exported again when it
+// is resolved again.
+func (e *TimelineLogExporter) ExportFinalized(ctx context.Context, incidents []domain.Incident, now time.Time) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	cursor, err := e.cursors.GetExportCursor(ctx, timelineExportCursor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get timeline export cursor: %w", err)
+	}
+	if cursor.IsZero() {
+		cursor = now
+		if err := e.cursors.SetExportCursor(ctx, timelineExportCursor, cursor); err != nil {
+			return 0, fmt.Errorf("failed to save timeline export cursor: %w", err)
+		}
+	}
+
+	type finalized struct {
+		incident   domain.Incident
+		resolvedAt time.Time
+	}
+	var due []finalized
+	settled := now.Add(-timelineExportSettle)
+	for _, incident := range incidents {
+		resolvedAt, ok := finalizedAt(incident)
+		if ok && resolvedAt.After(cursor) && !resolvedAt.After(settled) {
+			due = append(due, finalized{incident: incident, resolvedAt: resolvedAt})
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].resolvedAt.Before(due[j].resolvedAt) })
+
+	count := 0
+	for i, item := range due {
+		if err := e.exporter.Export(ctx, e.buildRecords(item.incident)); err != nil {
+			return count, fmt.Errorf("failed to export timeline for incident %s: %w", item.incident.ID, err)
+		}
+		count++
+
+		if i+1 < len(due) && !due[i+1].resolvedAt.After(item.resolvedAt) {
+			continue
+		}
+		if err := e.cursors.SetExportCursor(ctx, timelineExportCursor, item.resolvedAt); err != nil {
+			return count, fmt.Errorf("failed to save timeline export cursor: %w", err)
+		}
+	}
+
+	return count, nil
+}
+
+// finalizedAt returns when an incident was resolved: its resolution time, or the time of
+// its last event once that cleared
+func finalizedAt(incident domain.Incident) (time.Time, bool) {
+	if incident.ResolvedAt != nil {
+		return *incident.ResolvedAt, true
+	}
+	if n := len(incident.Events); n > 0 && incident.Status == domain.StatusClear {
+		return incident.Events[n-1].OccurredAt, true
+	}
+	return time.Time{}, false
+}
+
+// Run periodically exports newly finalized incidents until ctx is cancelled
+func (e *TimelineLogExporter) Run(ctx context.Context, repo ports.Repository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			incidents, err := repo.GetIncidents(ctx)
+			if err != nil {
+				log.Printf("⚠️  Timeline export failed to load incidents: %v", err)
+				continue
+			}
+
+			count, err := e.ExportFinalized(ctx, incidents, time.Now())
+			if err != nil {
+				log.Printf("⚠️  Timeline export error: %v", err)
+			}
+			if count > 0 {
+				log.Printf("📤 Exported %d incident timelines to OTLP", count)
+			}
+		}
+	}
+}
+
+// buildRecords converts an incident timeline into OTLP log records
+func (e *TimelineLogExporter) buildRecords(incident domain.Incident) []observability.OTLPLogRecord {
+	groups := e.grouper.GroupAlerts(incident.Events)
+	timeline := e.builder.BuildTimeline(incident.Events, groups)
+
+	records := make([]observability.OTLPLogRecord, 0, len(timeline.Events))
+	for i, event := range timeline.Events {
+		attrs := map[string]string{
+			"incident.id":            incident.ID,
+			"incident.title":         incident.Title,
+			"incident.status":        string(incident.Status),
+			"timeline.index":         fmt.Sprintf("%d", i),
+			"timeline.event_type":    event.Type,
+			"timeline.offset":        event.TimeFromIncidentStart.String(),
+			"timeline.is_root_cause": fmt.Sprintf("%t", timeline.RootCauseEventIndex != nil && *timeline.RootCauseEventIndex == i),
+			"timeline.is_cascade":    fmt.Sprintf("%t", event.IsCascadePoint),
+		}
+		if event.SourceAlert != nil {
+			attrs["alert.id"] = event.SourceAlert.ID
+			attrs["alert.name"] = event.SourceAlert.Name
+			attrs["alert.chart"] = event.SourceAlert.Chart
+			attrs["alert.resource_type"] = string(event.SourceAlert.ResourceType)
+			attrs["host.name"] = event.SourceAlert.Host
+			if event.SourceAlert.Source != "" {
+				attrs["alert.source"] = event.SourceAlert.Source
+			}
+		}
+
+		records = append(records, observability.OTLPLogRecord{
+			Timestamp:    event.Timestamp,
+			SeverityText: timelineSeverityText(event.Severity),
+			Body:         event.Message,
+			Attributes:   attrs,
+		})
+	}
+
+	return records
+}
+
+// timelineSeverityText maps timeline severities onto OTLP severity text
+func timelineSeverityText(severity string) string {
+	switch severity {
+	case "critical", string(domain.StatusCritical):
+		return "ERROR"
+	case "warning", string(domain.StatusWarning):
+		return "WARN"
+	default:
+		return "INFO"
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/observability"
+)
+
+type fakeExportCursorStore map[string]time.Time
+
+func (s fakeExportCursorStore) GetExportCursor(_ context.Context, exporter string) (time.Time, error) {
+	return s[exporter], nil
+}
+
+func (s fakeExportCursorStore) SetExportCursor(_ context.Context, exporter string, at time.Time) error {
+	s[exporter] = at
+	return nil
+}
+
+func TestTimelineLogExporter_ResumesFromCursor(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+	}))
+	defer server.Close()
+	exported := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), bodies...)
+	}
+
+	start := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	resolved := func(id string, at time.Duration) domain.Incident {
+		resolvedAt := start.Add(at)
+		return domain.Incident{ID: id, Status: domain.StatusClear, StartedAt: start.Add(-time.Hour), ResolvedAt: &resolvedAt,
+			Events: []domain.Alert{{ID: id + "-alert", Host: "db-01", Status: domain.StatusCritical, OccurredAt: start.Add(-time.Hour)}}}
+	}
+	incidents := []domain.Incident{resolved("inc-history", -time.Hour), resolved("inc-b", 2*time.Minute), resolved("inc-a", time.Minute)}
+
+	store := fakeExportCursorStore{}
+	newExporter := func() *TimelineLogExporter {
+		return NewTimelineLogExporter(observability.NewOTLPLogExporter(server.URL, "incident-teller", nil), store)
+	}
+	exporter := newExporter()
+	ctx := context.Background()
+
+	// The first run starts from now instead of sending the history
+	if count, err := exporter.ExportFinalized(ctx, incidents, start); err != nil || count != 0 {
+		t.Fatalf("expected nothing exported on the first run, got %d (err %v)", count, err)
+	}
+	// Incidents are exported once they have settled, oldest first
+	if count, err := exporter.ExportFinalized(ctx, incidents, start.Add(5*time.Minute)); err != nil || count != 0 {
+		t.Fatalf("expected nothing settled yet, got %d (err %v)", count, err)
+	}
+	if count, err := exporter.ExportFinalized(ctx, incidents, start.Add(8*time.Minute)); err != nil || count != 2 {
+		t.Fatalf("expected two incidents exported, got %d (err %v)", count, err)
+	}
+	if bodies := exported(); len(bodies) != 2 || !strings.Contains(bodies[0], "inc-a") || !strings.Contains(bodies[1], "inc-b") {
+		t.Fatalf("expected inc-a then inc-b, got %d exports", len(bodies))
+	}
+
+	// A restart resumes after the last exported incident
+	exporter = newExporter()
+	if count, err := exporter.ExportFinalized(ctx, incidents, start.Add(9*time.Minute)); err != nil || count != 0 {
+		t.Fatalf("expected nothing exported again after a restart, got %d (err %v)", count, err)
+	}
+
+	// A failed export is retried
+	incidents = append(incidents, resolved("inc-c", 3*time.Minute))
+	failing.Store(true)
+	if _, err := exporter.ExportFinalized(ctx, incidents, start.Add(10*time.Minute)); err == nil {
+		t.Fatal("expected the export to fail")
+	}
+	failing.Store(false)
+	if count, err := exporter.ExportFinalized(ctx, incidents, start.Add(11*time.Minute)); err != nil || count != 1 {
+		t.Fatalf("expected inc-c exported on retry, got %d (err %v)", count, err)
+	}
+	if bodies := exported(); len(bodies) != 3 || !strings.Contains(bodies[2], "inc-c") {
+		t.Errorf("expected inc-c exported last, got %d exports", len(bodies))
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/ports"
+)
+
+// TimelineRecorder keeps the stored timelines of incidents up to date as alerts are
+// attached to them. Causes are detected once, when an alert is recorded, and kept with
+// the entry; later changes to the propagation patterns don't rewrite recorded entries.
+type TimelineRecorder struct {
+	analyzer *IncidentAnalyzer
+	store    ports.TimelineStore
+}
+
+// NewTimelineRecorder creates a recorder storing timelines built by the analyzer
+func NewTimelineRecorder(analyzer *IncidentAnalyzer, store ports.TimelineStore) *TimelineRecorder {
+	return &TimelineRecorder{analyzer: analyzer, store: store}
+}
+
+// Record appends entries for the incident's alerts that have none yet and returns its
+// full timeline. The timeline is rebuilt if an alert arrived out of order or the stored
+// entries no longer match the incident's alerts.
+func (r *TimelineRecorder) Record(ctx context.Context, incident domain.Incident) ([]domain.TimelineEntry, error) {
+	stored, err := r.store.GetTimelineEntries(ctx, incident.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := make(map[string]bool, len(stored))
+	for _, entry := range stored {
+		for _, id := range entry.RelatedAlertIDs {
+			recorded[id] = true
+		}
+	}
+
+	var earlier, added []domain.Alert
+	for _, alert := range incident.Events {
+		if recorded[alert.ID] {
+			earlier = append(earlier, alert)
+		} else {
+			added = append(added, alert)
+		}
+	}
+	if len(added) == 0 && len(earlier) == len(stored) {
+		return stored, nil
+	}
+
+	if len(earlier) == len(stored) {
+		if entries, ok := r.analyzer.ExtendTimeline(earlier, added); ok {
+			if err := r.store.AppendTimelineEntries(ctx, incident.ID, entries); err != nil {
+				return nil, err
+			}
+			return append(stored, entries...), nil
+		}
+	}
+
+	timeline := r.analyzer.AnalyzeIncident(incident.Events)
+	if err := r.store.ReplaceTimelineEntries(ctx, incident.ID, timeline); err != nil {
+		return nil, err
+	}
+	return timeline, nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts of the timestamps in stories and reports. They always carry the UTC offset, so
+// a report forwarded to someone in another time zone still reads right.
+const (
+	ClockLayout    = "15:04:05 -07:00"
+	DateTimeLayout = "2006-01-02 15:04:05 -07:00"
+)
+
+// ParseTimeZone resolves an IANA time zone such as Europe/Berlin, or a UTC offset such as
+// +05:30, -0800 or UTC+2. An empty name is UTC.
+func ParseTimeZone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	switch strings.ToUpper(name) {
+	case "", "UTC", "Z", "GMT":
+		return time.UTC, nil
+	}
+
+	offset := strings.TrimPrefix(strings.TrimPrefix(strings.ToUpper(name), "UTC"), "GMT")
+	if offset != "" && (offset[0] == '+' || offset[0] == '-') {
+		seconds, ok := parseOffset(offset[1:])
+		if !ok {
+			return nil, fmt.Errorf("invalid UTC offset %q, use e.g. +05:30 or -0800", name)
+		}
+		if offset[0] == '-' {
+			seconds = -seconds
+		}
+		return time.FixedZone("UTC"+offset, seconds), nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q, use an IANA name such as Europe/Berlin or an offset such as +05:30", name)
+	}
+	return location, nil
+}
+
+// parseOffset parses the HH, HHMM or HH:MM of a UTC offset into seconds
+func parseOffset(value string) (int, bool) {
+	if value == "" || strings.Trim(value, "0123456789:") != "" {
+		return 0, false
+	}
+	hours, minutes, found := strings.Cut(value, ":")
+	if !found && len(value) == 4 {
+		hours, minutes = value[:2], value[2:]
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil || len(hours) > 2 || h > 14 {
+		return 0, false
+	}
+	m := 0
+	if minutes != "" || found {
+		if m, err = strconv.Atoi(minutes); err != nil || len(minutes) != 2 || m > 59 {
+			return 0, false
+		}
+	}
+	return h*3600 + m*60, true
+}
+
+// inZone returns t in the location, or in UTC without one
+func inZone(t time.Time, location *time.Location) time.Time {
+	if location == nil {
+		return t.UTC()
+	}
+	return t.In(location)
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeZone(t *testing.T) {
+	at := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	offsets := map[string]int{
+		"":       0,
+		"utc":    0,
+		"+05:30": 5*3600 + 30*60,
+		"-0800":  -8 * 3600,
+		"UTC+2":  2 * 3600,
+		"GMT-03": -3 * 3600,
+	}
+	for name, want := range offsets {
+		location, err := ParseTimeZone(name)
+		if err != nil {
+			t.Errorf("%q: %v", name, err)
+			continue
+		}
+		if _, offset := at.In(location).Zone(); offset != want {
+			t.Errorf("%q: expected offset %d, got %d", name, want, offset)
+		}
+	}
+
+	for _, name := range []string{"Mars/Olympus", "+25:00", "+05:7", "++5", "UTC+"} {
+		if _, err := ParseTimeZone(name); err == nil {
+			t.Errorf("expected %q to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/ports"
+)
+
+// Weekly reports show the MTTR trend over this many weeks, ending with the reported one
+const weeklyReportTrendWeeks = 4
+
+// WeeklyMTTR is the mean time to resolve of the incidents started in one week
+type WeeklyMTTR struct {
+	WeekStart time.Time
+	Incidents int
+	Resolved  int
+	MTTR      time.Duration // Zero without resolved incidents
+}
+
+// ActionItem is an open follow-up of an incident: an action item recorded for it, with
+// a description, owner and due date, or the tracker ticket filed for it while the incident
+// is open, with a tracker, key and URL
+type ActionItem struct {
+	IncidentID  string
+	Title       string // Title of the incident
+	Description string
+	Owner       string
+	DueAt       *time.Time
+	Status      domain.ActionItemStatus
+	Tracker     string
+	Key         string
+	URL         string
+	OpenedAt    time.Time
+}
+
+// WeeklyReport is the weekly operations report: the incidents started in the week before
+// To, with the MTTR trend of the preceding weeks
+type WeeklyReport struct {
+	From, To      time.Time
+	Incidents     int
+	Resolved      int
+	BySeverity    []DigestCount // critical, warning and info, in that order
+	MTTR          time.Duration
+	MTTRTrend     []WeeklyMTTR   // Oldest week first, ending with the reported week
+	TopRootCauses []DigestCount  // Root-cause resource types, most frequent first
+	NoisiestHosts []DigestCount  // Hosts with the most alerts first
+	ActionItems   []ActionItem   // Oldest first
+	Location      *time.Location // Time zone the report is rendered in; nil is UTC
+}
+
+// WeeklyReportSince returns the start of the incidents a weekly report ending at to needs
+func WeeklyReportSince(to time.Time) time.Time {
+	return to.AddDate(0, 0, -7*weeklyReportTrendWeeks)
+}
+
+// BuildWeekly compiles the report of the week ending at to. incidents must include those
+// started since WeeklyReportSince(to) for the MTTR trend.
+func (b *DigestBuilder) BuildWeekly(incidents []domain.Incident, actionItems []ActionItem, to time.Time) WeeklyReport {
+	result := WeeklyReport{From: to.AddDate(0, 0, -7), To: to, ActionItems: actionItems}
+
+	for week := weeklyReportTrendWeeks - 1; week >= 0; week-- {
+		end := to.AddDate(0, 0, -7*week)
+		result.MTTRTrend = append(result.MTTRTrend, weeklyMTTR(incidents, end.AddDate(0, 0, -7), end))
+	}
+	current := result.MTTRTrend[len(result.MTTRTrend)-1]
+	result.Incidents = current.Incidents
+	result.Resolved = current.Resolved
+	result.MTTR = current.MTTR
+
+	severities := map[string]int{"critical": 0, "warning": 0, "info": 0}
+	rootCauses := make(map[string]int)
+	hosts := make(map[string]int)
+	for _, incident := range incidents {
+		if incident.StartedAt.Before(result.From) || !incident.StartedAt.Before(to) {
+			continue
+		}
+		severities[incidentSeverity(incident)]++
+		for _, event := range incident.Events {
+			if event.Host != "" {
+				hosts[event.Host]++
+			}
+		}
+		if len(incident.Events) > 0 {
+			rootCause := b.analyzer.ExplainIncident(incident).RootCause.Alert
+			rootCauses[string(rootCause.ResourceType)]++
+		}
+	}
+
+	for _, severity := range []string{"critical", "warning", "info"} {
+		result.BySeverity = append(result.BySeverity, DigestCount{Name: severity, Count: severities[severity]})
+	}
+	result.TopRootCauses = topCounts(rootCauses, digestTopN)
+	result.NoisiestHosts = topCounts(hosts, digestTopN)
+	return result
+}
+
+// weeklyMTTR computes the MTTR of the incidents started within [from, to)
+func weeklyMTTR(incidents []domain.Incident, from, to time.Time) WeeklyMTTR {
+	week := WeeklyMTTR{WeekStart: from}
+	var resolvedTime time.Duration
+	for _, incident := range incidents {
+		if incident.StartedAt.Before(from) || !incident.StartedAt.Before(to) {
+			continue
+		}
+		week.Incidents++
+		if incident.ResolvedAt != nil {
+			week.Resolved++
+			resolvedTime += incident.ResolvedAt.Sub(incident.StartedAt)
+		}
+	}
+	if week.Resolved > 0 {
+		week.MTTR = resolvedTime / time.Duration(week.Resolved)
+	}
+	return week
+}
+
+// OpenActionItems returns the open action items recorded for incidents and the open
+// tickets of the incidents that are still open, oldest first. Either store may be nil.
+func OpenActionItems(ctx context.Context, tickets ports.TicketStore, actions ports.ActionItemStore, incidents []domain.Incident) ([]ActionItem, error) {
+	var items []ActionItem
+	titles := make(map[string]string, len(incidents))
+	for _, incident := range incidents {
+		titles[incident.ID] = incident.Title
+		if tickets == nil || incident.ResolvedAt != nil {
+			continue
+		}
+		ticket, err := tickets.GetTicket(ctx, incident.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ticket of incident %s: %w", incident.ID, err)
+		}
+		if ticket == nil || ticket.ResolvedAt != nil {
+			continue
+		}
+		items = append(items, ActionItem{
+			IncidentID: incident.ID,
+			Title:      incident.Title,
+			Tracker:    ticket.Tracker,
+			Key:        ticket.Key,
+			URL:        ticket.URL,
+			OpenedAt:   ticket.CreatedAt,
+		})
+	}
+
+	if actions != nil {
+		recorded, err := actions.GetActionItems(ctx, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get action items: %w", err)
+		}
+		for _, item := range recorded {
+			if !item.Status.IsOpen() {
+				continue
+			}
+			items = append(items, ActionItem{
+				IncidentID:  item.IncidentID,
+				Title:       titles[item.IncidentID],
+				Description: item.Description,
+				Owner:       item.Owner,
+				DueAt:       item.DueAt,
+				Status:      item.Status,
+				OpenedAt:    item.CreatedAt,
+			})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].OpenedAt.Before(items[j].OpenedAt)
+	})
+	return items, nil
+}
@@ -0,0 +1,245 @@
+// Package severity normalizes alert statuses coming from different sources and
+// applies config-driven rules that upgrade or downgrade severity, e.g. depending
+// on the time of day.
+package severity
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"incident-teller/internal/calendar"
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+)
+
+// defaultAliases maps common status spellings onto domain statuses
+var defaultAliases = map[string]domain.AlertStatus{
+	"clear":     domain.StatusClear,
+	"ok":        domain.StatusClear,
+	"resolved":  domain.StatusClear,
+	"recovered": domain.StatusClear,
+	"info":      domain.StatusClear,
+	"warning":   domain.StatusWarning,
+	"warn":      domain.StatusWarning,
+	"minor":     domain.StatusWarning,
+	"critical":  domain.StatusCritical,
+	"crit":      domain.StatusCritical,
+	"error":     domain.StatusCritical,
+	"major":     domain.StatusCritical,
+	"fatal":     domain.StatusCritical,
+	"removed":   domain.StatusRemoved,
+	"undefined": domain.StatusUndefined,
+	"unknown":   domain.StatusUndefined,
+}
+
+// rank orders the active statuses for upgrade/downgrade
+var rank = []domain.AlertStatus{domain.StatusClear, domain.StatusWarning, domain.StatusCritical}
+
+// rule changes the status of matching alerts
+type rule struct {
+	Name      string
+	Match     config.SeverityMatch
+	statuses  map[domain.AlertStatus]bool
+	window    *calendar.Window
+	setStatus domain.AlertStatus
+	adjust    int // +1 upgrade, -1 downgrade
+}
+
+// Mapper normalizes and remaps alert statuses. A nil *Mapper leaves alerts unchanged.
+type Mapper struct {
+	aliases map[string]domain.AlertStatus
+	rules   []rule
+}
+
+// FromConfig builds a mapper from the severity config section
+func FromConfig(cfg config.SeverityConfig) (*Mapper, error) {
+	m := &Mapper{aliases: make(map[string]domain.AlertStatus, len(defaultAliases)+len(cfg.Aliases))}
+	for k, v := range defaultAliases {
+		m.aliases[k] = v
+	}
+	for raw, target := range cfg.Aliases {
+		status, ok := defaultAliases[strings.ToLower(target)]
+		if !ok {
+			return nil, fmt.Errorf("severity alias %q maps to unknown status %q", raw, target)
+		}
+		m.aliases[strings.ToLower(raw)] = status
+	}
+
+	for i, rc := range cfg.Rules {
+		r, err := m.buildRule(rc)
+		if err != nil {
+			name := rc.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("severity rule %s: %w", name, err)
+		}
+		m.rules = append(m.rules, r)
+	}
+
+	return m, nil
+}
+
+func (m *Mapper) buildRule(rc config.SeverityRule) (rule, error) {
+	r := rule{Name: rc.Name, Match: rc.Match}
+
+	for _, pattern := range []string{rc.Match.Name, rc.Match.Chart, rc.Match.Family, rc.Match.Host} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return r, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	if len(rc.Match.Status) > 0 {
+		r.statuses = make(map[domain.AlertStatus]bool)
+		for _, s := range rc.Match.Status {
+			r.statuses[m.Normalize(s)] = true
+		}
+	}
+
+	switch {
+	case rc.SetStatus != "" && rc.Adjust != "":
+		return r, fmt.Errorf("set_status and adjust are mutually exclusive")
+	case rc.SetStatus != "":
+		r.setStatus = m.Normalize(rc.SetStatus)
+		if r.setStatus != domain.StatusWarning && r.setStatus != domain.StatusCritical {
+			return r, fmt.Errorf("set_status must be WARNING or CRITICAL")
+		}
+	case strings.EqualFold(rc.Adjust, "upgrade"):
+		r.adjust = 1
+	case strings.EqualFold(rc.Adjust, "downgrade"):
+		r.adjust = -1
+	default:
+		return r, fmt.Errorf("either set_status or adjust (upgrade|downgrade) is required")
+	}
+
+	if rc.Hours != "" || len(rc.Days) > 0 || rc.Timezone != "" {
+		window, err := calendar.ParseWindow(rc.Hours, rc.Days, rc.Timezone)
+		if err != nil {
+			return r, err
+		}
+		r.window = window
+	}
+
+	return r, nil
+}
+
+// Normalize maps a raw status name onto a domain status. Unknown names become UNDEFINED.
+func (m *Mapper) Normalize(raw string) domain.AlertStatus {
+	key := strings.ToLower(strings.TrimSpace(raw))
+	if m != nil {
+		if status, ok := m.aliases[key]; ok {
+			return status
+		}
+	} else if status, ok := defaultAliases[key]; ok {
+		return status
+	}
+	return domain.StatusUndefined
+}
+
+// Apply normalizes the alert's statuses and applies the first matching rule
+func (m *Mapper) Apply(alert domain.Alert) domain.Alert {
+	if m == nil {
+		return alert
+	}
+
+	alert.Status = m.Normalize(string(alert.Status))
+	alert.OldStatus = m.Normalize(string(alert.OldStatus))
+
+	// Only active alerts are remapped; clear/removed transitions keep their meaning
+	if alert.Status != domain.StatusWarning && alert.Status != domain.StatusCritical {
+		return alert
+	}
+
+	for _, r := range m.rules {
+		if !r.matches(alert) {
+			continue
+		}
+		original := alert.Status
+		alert.Status = r.apply(alert.Status)
+		if alert.Status != original {
+			if alert.Labels == nil {
+				alert.Labels = make(map[string]string)
+			} else {
+				alert.Labels = copyLabels(alert.Labels)
+			}
+			alert.Labels["severity_rule"] = r.Name
+			alert.Labels["original_status"] = string(original)
+		}
+		break
+	}
+
+	return alert
+}
+
+// ApplyAll applies the mapper to every alert
+func (m *Mapper) ApplyAll(alerts []domain.Alert) []domain.Alert {
+	if m == nil {
+		return alerts
+	}
+	result := make([]domain.Alert, len(alerts))
+	for i, alert := range alerts {
+		result[i] = m.Apply(alert)
+	}
+	return result
+}
+
+func (r rule) matches(alert domain.Alert) bool {
+	if !globMatch(r.Match.Name, alert.Name) ||
+		!globMatch(r.Match.Chart, alert.Chart) ||
+		!globMatch(r.Match.Family, alert.Family) ||
+		!globMatch(r.Match.Host, alert.Host) {
+		return false
+	}
+	if r.statuses != nil && !r.statuses[alert.Status] {
+		return false
+	}
+	for key, pattern := range r.Match.Labels {
+		if !globMatch(pattern, alert.Labels[key]) {
+			return false
+		}
+	}
+	if r.window != nil && !r.window.Contains(alert.OccurredAt) {
+		return false
+	}
+	return true
+}
+
+func (r rule) apply(status domain.AlertStatus) domain.AlertStatus {
+	if r.setStatus != "" {
+		return r.setStatus
+	}
+
+	for i, s := range rank {
+		if s != status {
+			continue
+		}
+		// Never downgrade an active alert to CLEAR
+		next := i + r.adjust
+		if next < 1 {
+			next = 1
+		}
+		if next >= len(rank) {
+			next = len(rank) - 1
+		}
+		return rank[next]
+	}
+	return status
+}
+
+// globMatch reports whether value matches the glob pattern; an empty pattern matches everything
+func globMatch(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels)+2)
+	for k, v := range labels {
+		result[k] = v
+	}
+	return result
+}
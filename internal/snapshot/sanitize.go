@@ -0,0 +1,83 @@
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sanitize returns a copy of the snapshot fit for sharing outside the organization:
+// hostnames become host-1, host-2, ... in order of first appearance, also inside titles,
+// descriptions, timeline messages and label values, and the people who changed
+// priorities are removed. Alert IDs, charts and values are kept, so the incidents stay
+// usable as training data.
+func Sanitize(s Snapshot) Snapshot {
+	hosts := make(map[string]string)
+	for _, bundle := range s.Incidents {
+		for _, alert := range bundle.Alerts {
+			if _, ok := hosts[alert.Host]; !ok && alert.Host != "" {
+				hosts[alert.Host] = fmt.Sprintf("host-%d", len(hosts)+1)
+			}
+		}
+	}
+
+	// Longer names first, so "web-01.prod" is not rewritten as "host-1.prod"
+	names := make([]string, 0, len(hosts))
+	for name := range hosts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	pairs := make([]string, 0, 2*len(names))
+	for _, name := range names {
+		pairs = append(pairs, name, hosts[name])
+	}
+	replace := strings.NewReplacer(pairs...).Replace
+
+	sanitized := Snapshot{Version: s.Version, ExportedAt: s.ExportedAt, Sanitized: true, Incidents: make([]Bundle, len(s.Incidents))}
+	for i, bundle := range s.Incidents {
+		incident := bundle.Incident
+		incident.Title = replace(incident.Title)
+		incident.CustomFields = replaceValues(incident.CustomFields, replace)
+
+		alerts := make([]AlertRecord, len(bundle.Alerts))
+		for j, alert := range bundle.Alerts {
+			alert.Host = hosts[alert.Host]
+			alert.Description = replace(alert.Description)
+			alert.Labels = replaceValues(alert.Labels, replace)
+			alerts[j] = alert
+		}
+
+		timeline := make([]TimelineRecord, len(bundle.Timeline))
+		for j, entry := range bundle.Timeline {
+			entry.Message = replace(entry.Message)
+			timeline[j] = entry
+		}
+
+		changes := make([]PriorityChangeRecord, len(bundle.PriorityChanges))
+		for j, change := range bundle.PriorityChanges {
+			change.ChangedBy = ""
+			change.Reason = replace(change.Reason)
+			changes[j] = change
+		}
+
+		sanitized.Incidents[i] = Bundle{
+			Incident:        incident,
+			Alerts:          alerts,
+			RootCauses:      bundle.RootCauses,
+			Timeline:        timeline,
+			PriorityChanges: changes,
+		}
+	}
+	return sanitized
+}
+
+func replaceValues(values map[string]string, replace func(string) string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	replaced := make(map[string]string, len(values))
+	for k, v := range values {
+		replaced[k] = replace(v)
+	}
+	return replaced
+}
@@ -0,0 +1,472 @@
+// Package snapshot exports complete incidents - the incident, its alerts, root cause
+// analyses and timeline notes - as a versioned JSON bundle, and imports such bundles into
+// a repository. It is used to move incidents between database backends and to share
+// sanitized incidents, e.g. as training data.
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/ports"
+)
+
+// Version is the snapshot format written by this build. Import accepts snapshots up to it.
+const Version = 1
+
+// ErrIncidentNotFound is returned by Export for requested incidents that don't exist
+var ErrIncidentNotFound = errors.New("incident not found")
+
+// Snapshot is a set of incident bundles
+type Snapshot struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Sanitized  bool      `json:"sanitized,omitempty"`
+	Incidents  []Bundle  `json:"incidents"`
+}
+
+// Bundle is one incident with everything stored about it
+type Bundle struct {
+	Incident        IncidentRecord         `json:"incident"`
+	Alerts          []AlertRecord          `json:"alerts"`
+	RootCauses      []RootCauseRecord      `json:"root_causes,omitempty"`
+	Timeline        []TimelineRecord       `json:"timeline,omitempty"` // Includes NOTE entries
+	PriorityChanges []PriorityChangeRecord `json:"priority_changes,omitempty"`
+}
+
+// IncidentRecord is the incident of a bundle; its events are the bundle's alerts
+type IncidentRecord struct {
+	ID               string            `json:"id"`
+	Title            string            `json:"title"`
+	Status           string            `json:"status"`
+	StartedAt        time.Time         `json:"started_at"`
+	ResolvedAt       *time.Time        `json:"resolved_at,omitempty"`
+	PriorityOverride string            `json:"priority_override,omitempty"`
+	Template         string            `json:"template,omitempty"`
+	DefaultPriority  string            `json:"default_priority,omitempty"`
+	Severity         string            `json:"severity,omitempty"`
+	Tags             []string          `json:"tags,omitempty"`
+	CustomFields     map[string]string `json:"custom_fields,omitempty"`
+}
+
+// AlertRecord is one alert of an incident with its sampled chart values
+type AlertRecord struct {
+	ID           string                `json:"id"`
+	ExternalID   uint64                `json:"external_id,omitempty"`
+	Host         string                `json:"host"`
+	Chart        string                `json:"chart"`
+	Family       string                `json:"family,omitempty"`
+	Name         string                `json:"name"`
+	Status       string                `json:"status"`
+	OldStatus    string                `json:"old_status,omitempty"`
+	Value        float64               `json:"value"`
+	OccurredAt   time.Time             `json:"occurred_at"`
+	Description  string                `json:"description,omitempty"`
+	ResourceType string                `json:"resource_type,omitempty"`
+	Labels       map[string]string     `json:"labels,omitempty"`
+	Source       string                `json:"source,omitempty"`
+	Samples      []domain.MetricSample `json:"samples,omitempty"`
+}
+
+// RootCauseRecord is a root cause prediction of one model version with its feedback
+type RootCauseRecord struct {
+	ModelVersion string     `json:"model_version"`
+	AlertID      string     `json:"alert_id"`
+	RawScore     float64    `json:"raw_score"`
+	Confidence   float64    `json:"confidence"`
+	PredictedAt  time.Time  `json:"predicted_at"`
+	Correct      *bool      `json:"correct,omitempty"`
+	FeedbackAt   *time.Time `json:"feedback_at,omitempty"`
+}
+
+// TimelineRecord is one stored timeline entry
+type TimelineRecord struct {
+	Timestamp       time.Time `json:"timestamp"`
+	Type            string    `json:"type"`
+	Message         string    `json:"message"`
+	Severity        string    `json:"severity,omitempty"`
+	SinceStart      string    `json:"since_start,omitempty"` // Go duration, e.g. "4m30s"
+	CausedBy        []string  `json:"caused_by,omitempty"`
+	RelatedAlertIDs []string  `json:"related_alert_ids,omitempty"`
+	ResourceType    string    `json:"resource_type,omitempty"`
+}
+
+// PriorityChangeRecord is one manual priority change
+type PriorityChangeRecord struct {
+	Priority  string    `json:"priority"`
+	Previous  string    `json:"previous,omitempty"`
+	ChangedBy string    `json:"changed_by,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// Source is what an export reads; the stores it also implements add their data to the bundles
+type Source interface {
+	GetIncidents(ctx context.Context) ([]domain.Incident, error)
+}
+
+// Target is what an import writes; the stores it also implements receive the rest of the bundles
+type Target interface {
+	SaveAlerts(ctx context.Context, alerts []domain.Alert) error
+	SaveIncident(ctx context.Context, incident domain.Incident) error
+}
+
+// Export bundles the incidents with the given IDs, or all incidents if there are none
+func Export(ctx context.Context, source Source, ids []string) (Snapshot, error) {
+	incidents, err := source.GetIncidents(ctx)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to get incidents: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	snapshot := Snapshot{Version: Version, ExportedAt: time.Now().UTC(), Incidents: []Bundle{}}
+	for _, incident := range incidents {
+		if len(wanted) > 0 && !wanted[incident.ID] {
+			continue
+		}
+		delete(wanted, incident.ID)
+
+		bundle, err := exportBundle(ctx, source, incident)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		snapshot.Incidents = append(snapshot.Incidents, bundle)
+	}
+	if len(wanted) > 0 {
+		missing := make([]string, 0, len(wanted))
+		for id := range wanted {
+			missing = append(missing, id)
+		}
+		sort.Strings(missing)
+		return Snapshot{}, fmt.Errorf("%w: %s", ErrIncidentNotFound, strings.Join(missing, ", "))
+	}
+
+	sort.SliceStable(snapshot.Incidents, func(i, j int) bool {
+		return snapshot.Incidents[i].Incident.StartedAt.Before(snapshot.Incidents[j].Incident.StartedAt)
+	})
+	return snapshot, nil
+}
+
+func exportBundle(ctx context.Context, source Source, incident domain.Incident) (Bundle, error) {
+	bundle := Bundle{
+		Incident: IncidentRecord{
+			ID:               incident.ID,
+			Title:            incident.Title,
+			Status:           string(incident.Status),
+			StartedAt:        incident.StartedAt,
+			ResolvedAt:       incident.ResolvedAt,
+			PriorityOverride: string(incident.PriorityOverride),
+			Template:         incident.Template,
+			DefaultPriority:  string(incident.DefaultPriority),
+			Severity:         incident.Severity,
+			Tags:             incident.Tags,
+			CustomFields:     incident.CustomFields,
+		},
+		Alerts: make([]AlertRecord, len(incident.Events)),
+	}
+	for i, alert := range incident.Events {
+		bundle.Alerts[i] = newAlertRecord(alert)
+	}
+
+	if store, ok := source.(ports.RootCauseStore); ok {
+		records, err := store.GetRootCauses(ctx, incident.ID)
+		if err != nil {
+			return Bundle{}, fmt.Errorf("failed to get root causes of incident %s: %w", incident.ID, err)
+		}
+		for _, record := range records {
+			bundle.RootCauses = append(bundle.RootCauses, RootCauseRecord{
+				ModelVersion: record.ModelVersion,
+				AlertID:      record.AlertID,
+				RawScore:     record.RawScore,
+				Confidence:   record.Confidence,
+				PredictedAt:  record.PredictedAt,
+				Correct:      record.Correct,
+				FeedbackAt:   record.FeedbackAt,
+			})
+		}
+	}
+
+	if store, ok := source.(ports.TimelineStore); ok {
+		entries, err := store.GetTimelineEntries(ctx, incident.ID)
+		if err != nil {
+			return Bundle{}, fmt.Errorf("failed to get timeline of incident %s: %w", incident.ID, err)
+		}
+		for _, entry := range entries {
+			record := TimelineRecord{
+				Timestamp:       entry.Timestamp,
+				Type:            entry.Type,
+				Message:         entry.Message,
+				Severity:        entry.Severity,
+				CausedBy:        entry.CausedBy,
+				RelatedAlertIDs: entry.RelatedAlertIDs,
+				ResourceType:    string(entry.ResourceType),
+			}
+			if entry.DurationSinceStart != nil {
+				record.SinceStart = entry.DurationSinceStart.String()
+			}
+			bundle.Timeline = append(bundle.Timeline, record)
+		}
+	}
+
+	if store, ok := source.(ports.PriorityStore); ok {
+		changes, err := store.GetPriorityChanges(ctx, incident.ID)
+		if err != nil {
+			return Bundle{}, fmt.Errorf("failed to get priority changes of incident %s: %w", incident.ID, err)
+		}
+		for _, change := range changes {
+			bundle.PriorityChanges = append(bundle.PriorityChanges, PriorityChangeRecord{
+				Priority:  string(change.Priority),
+				Previous:  string(change.Previous),
+				ChangedBy: change.ChangedBy,
+				Reason:    change.Reason,
+				ChangedAt: change.ChangedAt,
+			})
+		}
+	}
+
+	return bundle, nil
+}
+
+func newAlertRecord(alert domain.Alert) AlertRecord {
+	return AlertRecord{
+		ID:           alert.ID,
+		ExternalID:   alert.ExternalID,
+		Host:         alert.Host,
+		Chart:        alert.Chart,
+		Family:       alert.Family,
+		Name:         alert.Name,
+		Status:       string(alert.Status),
+		OldStatus:    string(alert.OldStatus),
+		Value:        alert.Value,
+		OccurredAt:   alert.OccurredAt,
+		Description:  alert.Description,
+		ResourceType: string(alert.ResourceType),
+		Labels:       alert.Labels,
+		Source:       alert.Source,
+		Samples:      alert.Samples,
+	}
+}
+
+// Alert converts the record back to an alert
+func (r AlertRecord) Alert() domain.Alert {
+	return domain.Alert{
+		ID:           r.ID,
+		ExternalID:   r.ExternalID,
+		Host:         r.Host,
+		Chart:        r.Chart,
+		Family:       r.Family,
+		Name:         r.Name,
+		Status:       domain.AlertStatus(r.Status),
+		OldStatus:    domain.AlertStatus(r.OldStatus),
+		Value:        r.Value,
+		OccurredAt:   r.OccurredAt,
+		Description:  r.Description,
+		ResourceType: domain.ResourceType(r.ResourceType),
+		Labels:       r.Labels,
+		Source:       r.Source,
+		Samples:      r.Samples,
+	}
+}
+
+// ImportResult counts what an import stored
+type ImportResult struct {
+	Incidents       int `json:"incidents"`
+	Alerts          int `json:"alerts"`
+	RootCauses      int `json:"root_causes"`
+	TimelineEntries int `json:"timeline_entries"`
+	PriorityChanges int `json:"priority_changes"`
+}
+
+// Import stores the bundles of a snapshot. Incidents, alerts, root causes and timelines are
+// replaced, so importing a snapshot again changes nothing; priority changes are only added
+// to incidents without a priority history. Data the target has no store for is skipped.
+func Import(ctx context.Context, target Target, snapshot Snapshot) (ImportResult, error) {
+	var result ImportResult
+	if err := snapshot.Validate(); err != nil {
+		return result, err
+	}
+
+	for _, bundle := range snapshot.Incidents {
+		if err := importBundle(ctx, target, bundle, &result); err != nil {
+			return result, fmt.Errorf("failed to import incident %s: %w", bundle.Incident.ID, err)
+		}
+	}
+	return result, nil
+}
+
+func importBundle(ctx context.Context, target Target, bundle Bundle, result *ImportResult) error {
+	record := bundle.Incident
+	incident := domain.Incident{
+		ID:               record.ID,
+		Title:            record.Title,
+		Status:           domain.AlertStatus(record.Status),
+		StartedAt:        record.StartedAt,
+		ResolvedAt:       record.ResolvedAt,
+		PriorityOverride: domain.Priority(record.PriorityOverride),
+		Template:         record.Template,
+		DefaultPriority:  domain.Priority(record.DefaultPriority),
+		Severity:         record.Severity,
+		Tags:             record.Tags,
+		CustomFields:     record.CustomFields,
+		Events:           make([]domain.Alert, len(bundle.Alerts)),
+	}
+	for i, alert := range bundle.Alerts {
+		incident.Events[i] = alert.Alert()
+	}
+
+	if err := target.SaveAlerts(ctx, incident.Events); err != nil {
+		return fmt.Errorf("failed to save alerts: %w", err)
+	}
+	if store, ok := target.(ports.AlertSampleStore); ok {
+		for _, alert := range incident.Events {
+			if len(alert.Samples) == 0 {
+				continue
+			}
+			if err := store.SaveAlertSamples(ctx, alert.ID, alert.Samples); err != nil {
+				return err
+			}
+		}
+	}
+	if err := target.SaveIncident(ctx, incident); err != nil {
+		return fmt.Errorf("failed to save incident: %w", err)
+	}
+	result.Incidents++
+	result.Alerts += len(incident.Events)
+
+	if store, ok := target.(ports.IncidentMetadataStore); ok {
+		metadata := domain.IncidentMetadata{
+			IncidentID:   incident.ID,
+			Severity:     incident.Severity,
+			Tags:         incident.Tags,
+			CustomFields: incident.CustomFields,
+			UpdatedBy:    "snapshot",
+			UpdatedAt:    time.Now().UTC(),
+		}
+		if err := store.SetIncidentMetadata(ctx, metadata); err != nil {
+			return err
+		}
+	}
+
+	if store, ok := target.(ports.RootCauseStore); ok {
+		for _, rc := range bundle.RootCauses {
+			err := store.SaveRootCause(ctx, domain.RootCauseRecord{
+				IncidentID:   incident.ID,
+				ModelVersion: rc.ModelVersion,
+				AlertID:      rc.AlertID,
+				RawScore:     rc.RawScore,
+				Confidence:   rc.Confidence,
+				PredictedAt:  rc.PredictedAt,
+				Correct:      rc.Correct,
+				FeedbackAt:   rc.FeedbackAt,
+			})
+			if err != nil {
+				return err
+			}
+			result.RootCauses++
+		}
+	}
+
+	if store, ok := target.(ports.TimelineStore); ok && len(bundle.Timeline) > 0 {
+		entries := make([]domain.TimelineEntry, len(bundle.Timeline))
+		for i, tr := range bundle.Timeline {
+			entries[i] = domain.TimelineEntry{
+				Timestamp:       tr.Timestamp,
+				Type:            tr.Type,
+				Message:         tr.Message,
+				Severity:        tr.Severity,
+				CausedBy:        tr.CausedBy,
+				RelatedAlertIDs: tr.RelatedAlertIDs,
+				ResourceType:    domain.ResourceType(tr.ResourceType),
+			}
+			if tr.SinceStart != "" {
+				since, err := time.ParseDuration(tr.SinceStart)
+				if err != nil {
+					return fmt.Errorf("invalid since_start %q: %w", tr.SinceStart, err)
+				}
+				entries[i].DurationSinceStart = &since
+			}
+		}
+		if err := store.ReplaceTimelineEntries(ctx, incident.ID, entries); err != nil {
+			return err
+		}
+		result.TimelineEntries += len(entries)
+	}
+
+	if store, ok := target.(ports.PriorityStore); ok && len(bundle.PriorityChanges) > 0 {
+		existing, err := store.GetPriorityChanges(ctx, incident.ID)
+		if err != nil {
+			return err
+		}
+		if len(existing) == 0 {
+			for _, pc := range bundle.PriorityChanges {
+				err := store.SetPriority(ctx, domain.PriorityChange{
+					IncidentID: incident.ID,
+					Priority:   domain.Priority(pc.Priority),
+					Previous:   domain.Priority(pc.Previous),
+					ChangedBy:  pc.ChangedBy,
+					Reason:     pc.Reason,
+					ChangedAt:  pc.ChangedAt,
+				})
+				if err != nil {
+					return err
+				}
+				result.PriorityChanges++
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validate checks that the snapshot can be imported
+func (s Snapshot) Validate() error {
+	if s.Version < 1 || s.Version > Version {
+		return fmt.Errorf("unsupported snapshot version %d (this build reads 1-%d)", s.Version, Version)
+	}
+	seen := make(map[string]bool, len(s.Incidents))
+	for i, bundle := range s.Incidents {
+		if bundle.Incident.ID == "" || bundle.Incident.StartedAt.IsZero() {
+			return fmt.Errorf("incident %d: needs an id and started_at", i+1)
+		}
+		if seen[bundle.Incident.ID] {
+			return fmt.Errorf("duplicate incident ID %q", bundle.Incident.ID)
+		}
+		seen[bundle.Incident.ID] = true
+		for _, alert := range bundle.Alerts {
+			if alert.ID == "" || alert.Host == "" || alert.OccurredAt.IsZero() {
+				return fmt.Errorf("incident %s: alert needs an id, a host and occurred_at", bundle.Incident.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// Read decodes and validates a snapshot
+func Read(r io.Reader) (Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if err := snapshot.Validate(); err != nil {
+		return Snapshot{}, err
+	}
+	return snapshot, nil
+}
+
+// Write encodes a snapshot as indented JSON
+func Write(w io.Writer, snapshot Snapshot) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snapshot)
+}
@@ -0,0 +1,151 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"incident-teller/internal/adapters/repository"
+	"incident-teller/internal/domain"
+)
+
+func seedRepository(t *testing.T) *repository.InMemoryRepository {
+	t.Helper()
+	ctx := context.Background()
+	repo := repository.NewInMemoryRepository()
+
+	start := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	alerts := []domain.Alert{
+		{ID: "a1", Host: "db-01.prod", Chart: "mem.available", Name: "ram_in_use", Status: domain.StatusCritical,
+			OccurredAt: start, ResourceType: domain.ResourceMemory, Description: "db-01.prod is out of memory",
+			Labels:  map[string]string{"instance": "db-01.prod", "team": "storage"},
+			Samples: []domain.MetricSample{{At: start.Add(-time.Minute), Value: 91}, {At: start, Value: 97}}},
+		{ID: "a2", Host: "web-01", Chart: "system.cpu", Name: "cpu_usage", Status: domain.StatusWarning,
+			OccurredAt: start.Add(time.Minute), ResourceType: domain.ResourceCPU},
+	}
+	incident := domain.Incident{
+		ID: "inc-1", Title: "Memory pressure on db-01.prod", Status: domain.StatusCritical,
+		StartedAt: start, Events: alerts,
+	}
+	if err := repo.SaveAlerts(ctx, alerts); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SaveIncident(ctx, incident); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.SetIncidentMetadata(ctx, domain.IncidentMetadata{IncidentID: "inc-1", Severity: "SEV2",
+		Tags: []string{"database"}, UpdatedBy: "alice", UpdatedAt: start}); err != nil {
+		t.Fatal(err)
+	}
+
+	correct := true
+	since := 30 * time.Second
+	if err := repo.SaveRootCause(ctx, domain.RootCauseRecord{IncidentID: "inc-1", ModelVersion: "v1", AlertID: "a1",
+		Confidence: 0.8, PredictedAt: start, Correct: &correct, FeedbackAt: &start}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.ReplaceTimelineEntries(ctx, "inc-1", []domain.TimelineEntry{
+		{Timestamp: start.Add(since), Type: "NOTE", Message: "Restarted db-01.prod", DurationSinceStart: &since},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SetPriority(ctx, domain.PriorityChange{IncidentID: "inc-1", Priority: domain.PriorityP1,
+		ChangedBy: "alice", Reason: "checkout down", ChangedAt: start}); err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	exported, err := Export(ctx, seedRepository(t), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exported.Incidents) != 1 || exported.Version != Version {
+		t.Fatalf("expected one incident in a version %d snapshot, got %+v", Version, exported)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, exported); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := repository.NewInMemoryRepository()
+	for i := 0; i < 2; i++ {
+		result, err := Import(ctx, target, read)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Incidents != 1 || result.Alerts != 2 || result.RootCauses != 1 || result.TimelineEntries != 1 {
+			t.Fatalf("unexpected import result %+v", result)
+		}
+	}
+
+	incidents, _ := target.GetIncidents(ctx)
+	if len(incidents) != 1 || len(incidents[0].Events) != 2 || incidents[0].PriorityOverride != domain.PriorityP1 ||
+		incidents[0].Severity != "SEV2" {
+		t.Fatalf("incident not restored: %+v", incidents)
+	}
+	changes, _ := target.GetPriorityChanges(ctx, "inc-1")
+	if len(changes) != 1 {
+		t.Fatalf("expected the priority history once after importing twice, got %d changes", len(changes))
+	}
+	rootCauses, _ := target.GetRootCauses(ctx, "inc-1")
+	if len(rootCauses) != 1 || rootCauses[0].Correct == nil || !*rootCauses[0].Correct {
+		t.Fatalf("root cause feedback not restored: %+v", rootCauses)
+	}
+	timeline, _ := target.GetTimelineEntries(ctx, "inc-1")
+	if len(timeline) != 1 || timeline[0].DurationSinceStart == nil || *timeline[0].DurationSinceStart != 30*time.Second {
+		t.Fatalf("timeline not restored: %+v", timeline)
+	}
+}
+
+func TestExport_UnknownIncident(t *testing.T) {
+	if _, err := Export(context.Background(), seedRepository(t), []string{"inc-1", "nope"}); err == nil {
+		t.Fatal("expected an error for an unknown incident")
+	}
+}
+
+func TestRead_RejectsNewerVersion(t *testing.T) {
+	if _, err := Read(strings.NewReader(`{"version": 99, "incidents": []}`)); err == nil {
+		t.Fatal("expected an error for a newer snapshot version")
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	exported, err := Export(context.Background(), seedRepository(t), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sanitized := Sanitize(exported)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, sanitized); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, secret := range []string{"db-01", "web-01", "alice"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("sanitized snapshot still contains %q", secret)
+		}
+	}
+
+	bundle := sanitized.Incidents[0]
+	if bundle.Alerts[0].Host != "host-1" || bundle.Alerts[1].Host != "host-2" {
+		t.Errorf("expected hosts in order of appearance, got %s and %s", bundle.Alerts[0].Host, bundle.Alerts[1].Host)
+	}
+	if bundle.Incident.Title != "Memory pressure on host-1" || bundle.Alerts[0].Labels["instance"] != "host-1" {
+		t.Errorf("hostnames not replaced consistently: %q, %v", bundle.Incident.Title, bundle.Alerts[0].Labels)
+	}
+	if exported.Incidents[0].Alerts[0].Host != "db-01.prod" {
+		t.Error("Sanitize changed the original snapshot")
+	}
+}
@@ -0,0 +1,73 @@
+package statuspage
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+var statusColors = map[Status]string{
+	StatusOperational: "#2fcc66",
+	StatusDegraded:    "#f1c40f",
+	StatusOutage:      "#e74c3c",
+}
+
+// RenderHTML renders the page as a self-contained HTML document. All text is escaped.
+func RenderHTML(page Page) string {
+	var out strings.Builder
+	title := html.EscapeString(page.Title)
+
+	out.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	out.WriteString(`<meta name="viewport" content="width=device-width, initial-scale=1">` + "\n")
+	out.WriteString("<title>" + title + "</title>\n")
+	out.WriteString(`<style>
+body{font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;max-width:860px;margin:2rem auto;padding:0 1rem;color:#222}
+.banner{padding:1rem;border-radius:6px;color:#fff;font-weight:600;margin-bottom:2rem}
+.component{border:1px solid #ddd;border-radius:6px;padding:1rem;margin-bottom:1rem}
+.component h2{font-size:1.1rem;margin:0;display:flex;justify-content:space-between}
+.message{color:#666;font-size:.9rem;margin:.25rem 0 0}
+.bars{display:flex;gap:2px;margin:.75rem 0 .25rem}
+.bars span{flex:1;height:28px;border-radius:2px}
+.legend{display:flex;justify-content:space-between;color:#888;font-size:.8rem}
+footer{color:#888;font-size:.8rem;margin-top:2rem}
+</style>
+</head>
+<body>
+`)
+	out.WriteString("<h1>" + title + "</h1>\n")
+
+	banner := "All systems operational"
+	if page.Status != StatusOperational {
+		banner = "Some systems are experiencing issues"
+	}
+	fmt.Fprintf(&out, "<div class=\"banner\" style=\"background:%s\">%s</div>\n", statusColors[page.Status], banner)
+
+	for _, c := range page.Components {
+		out.WriteString("<section class=\"component\">\n")
+		fmt.Fprintf(&out, "<h2><span>%s</span><span style=\"color:%s\">%s</span></h2>\n",
+			html.EscapeString(c.Name), statusColors[c.Status], c.Status.Label())
+		if c.Message != "" {
+			out.WriteString("<p class=\"message\">" + html.EscapeString(c.Message) + "</p>\n")
+		}
+
+		out.WriteString("<div class=\"bars\">")
+		for _, day := range c.History {
+			fmt.Fprintf(&out, "<span style=\"background:%s\" title=\"%s: %.2f%% uptime\"></span>",
+				statusColors[day.Status], day.Date.Format("Jan 2, 2006"), day.Uptime)
+		}
+		out.WriteString("</div>\n")
+		fmt.Fprintf(&out, "<div class=\"legend\"><span>%d days ago</span><span>%.2f%% uptime</span><span>Today</span></div>\n",
+			len(c.History), c.Uptime)
+		out.WriteString("</section>\n")
+	}
+
+	if len(page.Components) == 0 {
+		out.WriteString("<p>No components are being monitored.</p>\n")
+	}
+
+	fmt.Fprintf(&out, "<footer>Last updated %s</footer>\n", page.UpdatedAt.Format(time.RFC1123))
+	out.WriteString("</body>\n</html>\n")
+
+	return out.String()
+}
@@ -0,0 +1,293 @@
+// Package statuspage builds a minimal public status page: the current health of every
+// service, derived from open incidents mapped onto services via the topology, and its
+// daily uptime history.
+//
+// The page deliberately exposes nothing but service names, statuses and uptime, so it
+// can be shown to customers.
+package statuspage
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"incident-teller/internal/domain"
+	"incident-teller/internal/topology"
+)
+
+// Status is the health of a component
+type Status string
+
+const (
+	StatusOperational Status = "operational"
+	StatusDegraded    Status = "degraded"     // Open warning incident, or a dependency is down
+	StatusOutage      Status = "major_outage" // Open critical incident
+)
+
+// rank orders statuses from operational (0) to outage (2)
+func (s Status) rank() int {
+	switch s {
+	case StatusOutage:
+		return 2
+	case StatusDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Label is the human-readable status, e.g. "Major outage"
+func (s Status) Label() string {
+	switch s {
+	case StatusOutage:
+		return "Major outage"
+	case StatusDegraded:
+		return "Degraded performance"
+	default:
+		return "Operational"
+	}
+}
+
+func worst(a, b Status) Status {
+	if b.rank() > a.rank() {
+		return b
+	}
+	return a
+}
+
+// Day is one day of a component's uptime history
+type Day struct {
+	Date   time.Time `json:"date"`
+	Uptime float64   `json:"uptime"` // Percent of the day without an outage
+	Status Status    `json:"status"` // Worst status during the day
+}
+
+// Component is a service shown on the status page
+type Component struct {
+	Name    string  `json:"name"`
+	Status  Status  `json:"status"`
+	Message string  `json:"message,omitempty"` // e.g. "Affected by an outage of postgres"
+	Uptime  float64 `json:"uptime"`            // Percent over the whole history
+	History []Day   `json:"history"`           // Oldest first
+}
+
+// Page is the rendered status of all components
+type Page struct {
+	Title      string      `json:"title"`
+	Status     Status      `json:"status"` // Worst component status
+	Components []Component `json:"components"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+// Generator builds status pages. Components are the topology's services; without a
+// topology every host that had an incident in the history window is a component.
+type Generator struct {
+	title    string
+	topology *topology.Topology
+	days     int
+}
+
+// NewGenerator creates a status page generator covering days of uptime history
+func NewGenerator(title string, topo *topology.Topology, days int) *Generator {
+	if days < 1 {
+		days = 1
+	}
+	return &Generator{title: title, topology: topo, days: days}
+}
+
+// Days returns how many days of history the page covers
+func (g *Generator) Days() int {
+	return g.days
+}
+
+// interval is a period a component was in a non-operational status
+type interval struct {
+	start, end time.Time
+	status     Status
+}
+
+// Build derives the page from the incidents of the history window
+func (g *Generator) Build(incidents []domain.Incident, now time.Time) Page {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := today.AddDate(0, 0, -(g.days - 1))
+
+	intervals := make(map[string][]interval)
+	current := make(map[string]Status)
+	for _, name := range g.componentNames() {
+		intervals[name] = nil
+		current[name] = StatusOperational
+	}
+
+	for _, incident := range incidents {
+		status := StatusDegraded
+		for _, event := range incident.Events {
+			if event.Status == domain.StatusCritical {
+				status = StatusOutage
+				break
+			}
+		}
+		end := now
+		if incident.ResolvedAt != nil {
+			end = *incident.ResolvedAt
+		}
+		if end.Before(from) {
+			continue
+		}
+
+		for _, name := range g.componentsOf(incident) {
+			intervals[name] = append(intervals[name], interval{start: incident.StartedAt, end: end, status: status})
+			if incident.ResolvedAt == nil {
+				current[name] = worst(current[name], currentStatus(incident))
+			}
+		}
+	}
+
+	page := Page{Title: g.title, Status: StatusOperational, Components: []Component{}, UpdatedAt: now}
+	for name := range intervals {
+		component := Component{Name: name, Status: current[name]}
+		if component.Status == StatusOperational {
+			if dep, ok := g.impairedDependency(name, current); ok {
+				component.Status = StatusDegraded
+				component.Message = "Affected by an issue with " + dep
+			}
+		}
+		component.History, component.Uptime = history(intervals[name], from, g.days, now)
+
+		page.Status = worst(page.Status, component.Status)
+		page.Components = append(page.Components, component)
+	}
+
+	sort.Slice(page.Components, func(i, j int) bool {
+		return page.Components[i].Name < page.Components[j].Name
+	})
+	return page
+}
+
+// currentStatus maps an open incident's aggregate status onto a component status
+func currentStatus(incident domain.Incident) Status {
+	switch incident.Status {
+	case domain.StatusCritical:
+		return StatusOutage
+	case domain.StatusWarning:
+		return StatusDegraded
+	default:
+		return StatusOperational
+	}
+}
+
+func (g *Generator) componentNames() []string {
+	var names []string
+	for _, svc := range g.topology.Services() {
+		names = append(names, svc.Name)
+	}
+	return names
+}
+
+// componentsOf returns the components an incident affects
+func (g *Generator) componentsOf(incident domain.Incident) []string {
+	if len(g.topology.Services()) == 0 {
+		return incident.Hosts()
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, host := range incident.Hosts() {
+		if name, ok := g.topology.ServiceForHost(host); ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// impairedDependency returns a (transitive) dependency of the service that is not operational
+func (g *Generator) impairedDependency(name string, current map[string]Status) (string, bool) {
+	visited := map[string]bool{name: true}
+	queue := []string{name}
+	for len(queue) > 0 {
+		svc, _ := g.topology.Service(queue[0])
+		queue = queue[1:]
+		for _, dep := range svc.DependsOn {
+			if visited[dep] {
+				continue
+			}
+			visited[dep] = true
+			if current[dep] != StatusOperational {
+				return dep, true
+			}
+			queue = append(queue, dep)
+		}
+	}
+	return "", false
+}
+
+// history splits the intervals into days starting at from. Only outages count as downtime;
+// today only counts up to now.
+func history(intervals []interval, from time.Time, days int, now time.Time) ([]Day, float64) {
+	result := make([]Day, 0, days)
+	var total, down time.Duration
+
+	for i := 0; i < days; i++ {
+		start := from.AddDate(0, 0, i)
+		end := start.AddDate(0, 0, 1)
+		if end.After(now) {
+			end = now
+		}
+
+		day := Day{Date: start, Status: StatusOperational}
+		var spans []interval
+		for _, iv := range intervals {
+			if iv.end.After(start) && iv.start.Before(end) {
+				day.Status = worst(day.Status, iv.status)
+				if iv.status == StatusOutage {
+					spans = append(spans, iv)
+				}
+			}
+		}
+
+		length := end.Sub(start)
+		downtime := overlap(spans, start, end)
+		day.Uptime = 100
+		if length > 0 {
+			day.Uptime = percent(length-downtime, length)
+		}
+
+		total += length
+		down += downtime
+		result = append(result, day)
+	}
+
+	uptime := 100.0
+	if total > 0 {
+		uptime = percent(total-down, total)
+	}
+	return result, uptime
+}
+
+// overlap returns how much of [start, end) the union of the intervals covers
+func overlap(intervals []interval, start, end time.Time) time.Duration {
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start.Before(intervals[j].start) })
+
+	var covered time.Duration
+	cursor := start
+	for _, iv := range intervals {
+		s, e := iv.start, iv.end
+		if s.Before(cursor) {
+			s = cursor
+		}
+		if e.After(end) {
+			e = end
+		}
+		if e.After(s) {
+			covered += e.Sub(s)
+			cursor = e
+		}
+	}
+	return covered
+}
+
+// percent returns part/whole as a percentage rounded to two decimals
+func percent(part, whole time.Duration) float64 {
+	return math.Round(float64(part)/float64(whole)*10000) / 100
+}
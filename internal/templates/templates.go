@@ -0,0 +1,174 @@
+// Package templates pre-populates incidents of known failure modes. A template matched by
+// alert name and chart patterns gives the incident its title, a default priority, a
+// runbook link with the known remediation, and notification overrides.
+package templates
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+)
+
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// placeholder matches {host}, {labels.service} etc. in a title
+var placeholder = regexp.MustCompile(`\{([a-z_]+(?:\.[^{}]+)?)\}`)
+
+// Template is a known failure mode
+type Template struct {
+	ID                 string
+	AlertName          string
+	Chart              string
+	Title              string
+	Priority           domain.Priority
+	RunbookURL         string
+	Remediation        []string
+	NotificationLabels map[string]string
+	SkipNotification   bool
+}
+
+// Matches reports whether the alert has the template's alert name and chart
+func (t Template) Matches(alert domain.Alert) bool {
+	return globMatch(t.AlertName, alert.Name) && globMatch(t.Chart, alert.Chart)
+}
+
+// RenderTitle fills the title placeholders from the alert; unknown placeholders are kept
+func (t Template) RenderTitle(alert domain.Alert) string {
+	return placeholder.ReplaceAllStringFunc(t.Title, func(match string) string {
+		field := match[1 : len(match)-1]
+		switch field {
+		case "host":
+			return alert.Host
+		case "name":
+			return alert.Name
+		case "chart":
+			return alert.Chart
+		case "family":
+			return alert.Family
+		}
+		if label, ok := strings.CutPrefix(field, "labels."); ok {
+			return alert.Labels[label]
+		}
+		return match
+	})
+}
+
+// Set holds templates in match order. A nil *Set matches nothing.
+type Set struct {
+	templates []Template
+	byID      map[string]Template
+}
+
+// FromConfig builds a set from the incident templates config
+func FromConfig(cfgs []config.IncidentTemplate) (*Set, error) {
+	s := &Set{byID: make(map[string]Template, len(cfgs))}
+	for i, cfg := range cfgs {
+		t, err := build(cfg)
+		if err != nil {
+			name := cfg.ID
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("incident template %s: %w", name, err)
+		}
+		if _, exists := s.byID[t.ID]; exists {
+			return nil, fmt.Errorf("duplicate incident template id %s", t.ID)
+		}
+		s.templates = append(s.templates, t)
+		s.byID[t.ID] = t
+	}
+	return s, nil
+}
+
+func build(cfg config.IncidentTemplate) (Template, error) {
+	t := Template{
+		ID:                 cfg.ID,
+		AlertName:          cfg.AlertName,
+		Chart:              cfg.Chart,
+		Title:              cfg.Title,
+		RunbookURL:         cfg.RunbookURL,
+		Remediation:        cfg.Remediation,
+		NotificationLabels: cfg.NotificationLabels,
+		SkipNotification:   cfg.SkipNotification,
+	}
+
+	if !validID.MatchString(t.ID) {
+		return t, fmt.Errorf("invalid id %q: use lowercase letters, digits, - and _", t.ID)
+	}
+	if t.AlertName == "" && t.Chart == "" {
+		return t, fmt.Errorf("set at least one of alert_name and chart")
+	}
+	for _, pattern := range []string{t.AlertName, t.Chart} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return t, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	if cfg.Priority != "" {
+		priority, err := domain.ParsePriority(cfg.Priority)
+		if err != nil {
+			return t, err
+		}
+		t.Priority = priority
+	}
+	return t, nil
+}
+
+// Len returns the number of templates
+func (s *Set) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.templates)
+}
+
+// Get returns a template by ID
+func (s *Set) Get(id string) (Template, bool) {
+	if s == nil || id == "" {
+		return Template{}, false
+	}
+	t, ok := s.byID[id]
+	return t, ok
+}
+
+// Match returns the first template matching an alert of the incident, with the earliest
+// alert it matches
+func (s *Set) Match(incident domain.Incident) (Template, domain.Alert, bool) {
+	if s == nil {
+		return Template{}, domain.Alert{}, false
+	}
+	for _, t := range s.templates {
+		for _, event := range incident.Events {
+			if t.Matches(event) {
+				return t, event, true
+			}
+		}
+	}
+	return Template{}, domain.Alert{}, false
+}
+
+// Apply sets the template, default priority and, unless the incident has one, the title
+// of the template the incident matches
+func (s *Set) Apply(incident domain.Incident) domain.Incident {
+	t, alert, ok := s.Match(incident)
+	if !ok {
+		return incident
+	}
+	incident.Template = t.ID
+	incident.DefaultPriority = t.Priority
+	if incident.Title == "" && t.Title != "" {
+		incident.Title = t.RenderTitle(alert)
+	}
+	return incident
+}
+
+func globMatch(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
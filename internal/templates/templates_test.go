@@ -0,0 +1,72 @@
+package templates
+
+import (
+	"testing"
+	"time"
+
+	"incident-teller/internal/config"
+	"incident-teller/internal/domain"
+)
+
+func TestSet_Apply(t *testing.T) {
+	set, err := FromConfig([]config.IncidentTemplate{
+		{ID: "cpu", AlertName: "cpu_*", Priority: "p3"},
+		{
+			ID: "var-log-full", AlertName: "disk_space_usage", Chart: "disk_space._var_log",
+			Title: "Disk full on {host}: /var/log ({labels.env}, {unknown})", Priority: "P2",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	incident := domain.Incident{ID: "incident-1", Events: []domain.Alert{
+		{Name: "load_average", Chart: "system.load", Host: "web-01", OccurredAt: now},
+		{Name: "disk_space_usage", Chart: "disk_space._var_log", Host: "web-01",
+			Labels: map[string]string{"env": "prod"}, OccurredAt: now.Add(time.Minute)},
+	}}
+
+	applied := set.Apply(incident)
+	if applied.Template != "var-log-full" || applied.DefaultPriority != domain.PriorityP2 {
+		t.Fatalf("expected the var-log-full template with P2, got %q with %q", applied.Template, applied.DefaultPriority)
+	}
+	if want := "Disk full on web-01: /var/log (prod, {unknown})"; applied.Title != want {
+		t.Errorf("title: got %q, want %q", applied.Title, want)
+	}
+	if applied.Priority() != domain.PriorityP2 {
+		t.Errorf("expected the default priority to apply, got %s", applied.Priority())
+	}
+
+	// A manual priority and an existing title are kept
+	incident.Title = "Investigating web-01"
+	incident.PriorityOverride = domain.PriorityP1
+	applied = set.Apply(incident)
+	if applied.Title != "Investigating web-01" || applied.Priority() != domain.PriorityP1 {
+		t.Errorf("expected the title and manual priority to be kept, got %q and %s", applied.Title, applied.Priority())
+	}
+
+	// The chart must match the same alert as the alert name
+	incident.Events[1].Chart = "disk_space._"
+	if applied = set.Apply(incident); applied.Template != "" {
+		t.Errorf("expected no template, got %q", applied.Template)
+	}
+}
+
+func TestFromConfig_Invalid(t *testing.T) {
+	for name, cfg := range map[string]config.IncidentTemplate{
+		"no id":            {AlertName: "cpu_*"},
+		"no pattern":       {ID: "cpu"},
+		"invalid pattern":  {ID: "cpu", Chart: "system.[cpu"},
+		"invalid priority": {ID: "cpu", AlertName: "cpu_*", Priority: "urgent"},
+	} {
+		if _, err := FromConfig([]config.IncidentTemplate{cfg}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	duplicate := config.IncidentTemplate{ID: "cpu", AlertName: "cpu_*"}
+	if _, err := FromConfig([]config.IncidentTemplate{duplicate, duplicate}); err == nil {
+		t.Error("expected an error for duplicate IDs")
+	}
+}
@@ -0,0 +1,85 @@
+package ticketing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"incident-teller/internal/report"
+)
+
+// GitHubTracker files issues in a GitHub repository
+type GitHubTracker struct {
+	apiURL     string
+	repo       string
+	token      string
+	httpClient *http.Client
+}
+
+// NewGitHubTracker creates a tracker for repo ("owner/name"). apiURL defaults to
+// https://api.github.com; set it for GitHub Enterprise.
+func NewGitHubTracker(apiURL, repo, token string) *GitHubTracker {
+	if apiURL == "" {
+		apiURL = "https://api.github.com"
+	}
+	return &GitHubTracker{
+		apiURL:     strings.TrimRight(apiURL, "/"),
+		repo:       repo,
+		token:      token,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Name returns "github"
+func (g *GitHubTracker) Name() string {
+	return "github"
+}
+
+// Create files the issue with the documents rendered as Markdown
+func (g *GitHubTracker) Create(ctx context.Context, issue Issue) (Reference, error) {
+	bodies := make([]string, 0, len(issue.Documents))
+	for _, doc := range issue.Documents {
+		bodies = append(bodies, report.Markdown{}.Render(doc))
+	}
+
+	request := map[string]any{
+		"title":  issue.Title,
+		"body":   strings.Join(bodies, "\n---\n\n"),
+		"labels": issue.Labels,
+	}
+	var created struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := doJSON(ctx, g.httpClient, http.MethodPost, g.issuesURL(), g.auth, request, &created); err != nil {
+		return Reference{}, fmt.Errorf("failed to create GitHub issue: %w", err)
+	}
+	return Reference{Key: strconv.Itoa(created.Number), URL: created.HTMLURL}, nil
+}
+
+// Resolve comments on the issue and closes it as completed
+func (g *GitHubTracker) Resolve(ctx context.Context, key, comment string) error {
+	issueURL := g.issuesURL() + "/" + key
+
+	if err := doJSON(ctx, g.httpClient, http.MethodPost, issueURL+"/comments", g.auth,
+		map[string]string{"body": comment}, nil); err != nil {
+		return fmt.Errorf("failed to comment on GitHub issue: %w", err)
+	}
+	if err := doJSON(ctx, g.httpClient, http.MethodPatch, issueURL, g.auth,
+		map[string]string{"state": "closed", "state_reason": "completed"}, nil); err != nil {
+		return fmt.Errorf("failed to close GitHub issue: %w", err)
+	}
+	return nil
+}
+
+func (g *GitHubTracker) issuesURL() string {
+	return g.apiURL + "/repos/" + g.repo + "/issues"
+}
+
+func (g *GitHubTracker) auth(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+g.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+}
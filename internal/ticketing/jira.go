@@ -0,0 +1,127 @@
+package ticketing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"incident-teller/internal/report"
+)
+
+// JiraTracker files issues through the Jira REST API v2
+type JiraTracker struct {
+	baseURL    string
+	email      string
+	apiToken   string
+	project    string
+	issueType  string
+	doneStatus string
+	httpClient *http.Client
+}
+
+// JiraOptions configures a JiraTracker
+type JiraOptions struct {
+	BaseURL    string // e.g. https://example.atlassian.net
+	Email      string // Account the API token belongs to
+	APIToken   string
+	Project    string // Project key, e.g. OPS
+	IssueType  string // Defaults to "Bug"
+	DoneStatus string // Transition or status closing an issue, defaults to "Done"
+}
+
+// NewJiraTracker creates a Jira tracker
+func NewJiraTracker(opts JiraOptions) *JiraTracker {
+	if opts.IssueType == "" {
+		opts.IssueType = "Bug"
+	}
+	if opts.DoneStatus == "" {
+		opts.DoneStatus = "Done"
+	}
+	return &JiraTracker{
+		baseURL:    strings.TrimRight(opts.BaseURL, "/"),
+		email:      opts.Email,
+		apiToken:   opts.APIToken,
+		project:    opts.Project,
+		issueType:  opts.IssueType,
+		doneStatus: opts.DoneStatus,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Name returns "jira"
+func (j *JiraTracker) Name() string {
+	return "jira"
+}
+
+// Create files the issue. Jira descriptions use wiki markup, so the documents are
+// rendered as plain text in a {noformat} block to keep their layout.
+func (j *JiraTracker) Create(ctx context.Context, issue Issue) (Reference, error) {
+	var description strings.Builder
+	for _, doc := range issue.Documents {
+		description.WriteString("{noformat}\n" + report.Text{}.Render(doc) + "{noformat}\n")
+	}
+
+	// Jira labels can't contain spaces
+	labels := make([]string, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		labels = append(labels, strings.ReplaceAll(label, " ", "-"))
+	}
+
+	request := map[string]any{
+		"fields": map[string]any{
+			"project":     map[string]string{"key": j.project},
+			"issuetype":   map[string]string{"name": j.issueType},
+			"summary":     issue.Title,
+			"description": description.String(),
+			"labels":      labels,
+		},
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := doJSON(ctx, j.httpClient, http.MethodPost, j.baseURL+"/rest/api/2/issue", j.auth, request, &created); err != nil {
+		return Reference{}, fmt.Errorf("failed to create Jira issue: %w", err)
+	}
+	return Reference{Key: created.Key, URL: j.baseURL + "/browse/" + created.Key}, nil
+}
+
+// Resolve comments on the issue and moves it through the transition named, or leading
+// to the status named, DoneStatus
+func (j *JiraTracker) Resolve(ctx context.Context, key, comment string) error {
+	issueURL := j.baseURL + "/rest/api/2/issue/" + url.PathEscape(key)
+
+	if err := doJSON(ctx, j.httpClient, http.MethodPost, issueURL+"/comment", j.auth,
+		map[string]string{"body": comment}, nil); err != nil {
+		return fmt.Errorf("failed to comment on Jira issue: %w", err)
+	}
+
+	var transitions struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := doJSON(ctx, j.httpClient, http.MethodGet, issueURL+"/transitions", j.auth, nil, &transitions); err != nil {
+		return fmt.Errorf("failed to get Jira transitions: %w", err)
+	}
+	for _, t := range transitions.Transitions {
+		if strings.EqualFold(t.Name, j.doneStatus) || strings.EqualFold(t.To.Name, j.doneStatus) {
+			if err := doJSON(ctx, j.httpClient, http.MethodPost, issueURL+"/transitions", j.auth,
+				map[string]any{"transition": map[string]string{"id": t.ID}}, nil); err != nil {
+				return fmt.Errorf("failed to transition Jira issue: %w", err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no Jira transition to %q available for %s", j.doneStatus, key)
+}
+
+func (j *JiraTracker) auth(req *http.Request) {
+	req.SetBasicAuth(j.email, j.apiToken)
+}
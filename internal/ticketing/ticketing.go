@@ -0,0 +1,78 @@
+// Package ticketing files incident tickets in issue trackers (Jira, GitHub Issues) and
+// closes them when incidents resolve
+package ticketing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"incident-teller/internal/report"
+)
+
+// Issue is a tracker-agnostic ticket to file
+type Issue struct {
+	Title     string
+	Documents []report.Document // Rendered into the ticket body in the tracker's format
+	Labels    []string
+}
+
+// Reference identifies a filed ticket
+type Reference struct {
+	Key string // Tracker-specific key, e.g. "OPS-123" or "42"
+	URL string // Link for humans
+}
+
+// Tracker files and closes tickets in one issue tracker
+type Tracker interface {
+	// Name identifies the tracker in logs, errors and stored tickets
+	Name() string
+	// Create files the issue
+	Create(ctx context.Context, issue Issue) (Reference, error)
+	// Resolve adds the comment to the ticket and closes it
+	Resolve(ctx context.Context, key, comment string) error
+}
+
+// doJSON sends body as JSON and decodes a JSON response into out, if not nil. Any
+// non-2xx status is an error.
+func doJSON(ctx context.Context, client *http.Client, method, url string, auth func(*http.Request), body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	auth(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
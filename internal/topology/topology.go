@@ -0,0 +1,120 @@
+// Package topology describes which hosts make up which logical services and how
+// services depend on each other.
+package topology
+
+import (
+	"fmt"
+	"sort"
+
+	"incident-teller/internal/config"
+)
+
+// Service is a logical service running on one or more hosts
+type Service struct {
+	Name      string   `json:"name"`
+	Hosts     []string `json:"hosts"`
+	DependsOn []string `json:"depends_on,omitempty"` // Names of services this one calls
+}
+
+// Topology is an immutable service map. The zero value and a nil *Topology are empty.
+type Topology struct {
+	services map[string]Service
+	byHost   map[string]string
+}
+
+// New builds a topology and validates that service names are unique, every host
+// belongs to at most one service, and every dependency refers to a known service
+func New(services []Service) (*Topology, error) {
+	t := &Topology{
+		services: make(map[string]Service, len(services)),
+		byHost:   make(map[string]string),
+	}
+
+	for _, svc := range services {
+		if svc.Name == "" {
+			return nil, fmt.Errorf("topology service has no name")
+		}
+		if _, exists := t.services[svc.Name]; exists {
+			return nil, fmt.Errorf("duplicate topology service: %s", svc.Name)
+		}
+		for _, host := range svc.Hosts {
+			if owner, exists := t.byHost[host]; exists {
+				return nil, fmt.Errorf("host %s belongs to both %s and %s", host, owner, svc.Name)
+			}
+			t.byHost[host] = svc.Name
+		}
+		t.services[svc.Name] = svc
+	}
+
+	for _, svc := range services {
+		for _, dep := range svc.DependsOn {
+			if _, exists := t.services[dep]; !exists {
+				return nil, fmt.Errorf("service %s depends on unknown service %s", svc.Name, dep)
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// FromConfig builds a topology from the topology config section
+func FromConfig(cfg config.TopologyConfig) (*Topology, error) {
+	services := make([]Service, len(cfg.Services))
+	for i, svc := range cfg.Services {
+		services[i] = Service{Name: svc.Name, Hosts: svc.Hosts, DependsOn: svc.DependsOn}
+	}
+	return New(services)
+}
+
+// ServiceForHost returns the name of the service the host belongs to
+func (t *Topology) ServiceForHost(host string) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	name, ok := t.byHost[host]
+	return name, ok
+}
+
+// Service returns a service by name
+func (t *Topology) Service(name string) (Service, bool) {
+	if t == nil {
+		return Service{}, false
+	}
+	svc, ok := t.services[name]
+	return svc, ok
+}
+
+// Services returns all services sorted by name
+func (t *Topology) Services() []Service {
+	if t == nil {
+		return nil
+	}
+
+	result := make([]Service, 0, len(t.services))
+	for _, svc := range t.services {
+		result = append(result, svc)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Dependents returns the names of services that depend directly on the given service
+func (t *Topology) Dependents(name string) []string {
+	if t == nil {
+		return nil
+	}
+
+	var result []string
+	for _, svc := range t.services {
+		for _, dep := range svc.DependsOn {
+			if dep == name {
+				result = append(result, svc.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,220 @@
+// Package client is a Go client for the IncidentTeller HTTP API.
+//
+//	c, err := client.New("http://incident-teller:8080", client.Options{})
+//	incidents, err := c.ListIncidents(ctx, client.ListOptions{Query: "db-01"})
+//
+// Requests that are safe to repeat are retried on network errors, 429 and 5xx responses
+// with exponential backoff. Failed requests return an *APIError, which matches ErrNotFound,
+// ErrUnauthorized and ErrRateLimited with errors.Is.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout      = 30 * time.Second
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = 500 * time.Millisecond
+	maxRetryBackoff     = 30 * time.Second
+)
+
+// Options configures a Client. The zero value is usable.
+type Options struct {
+	Token        string        // Sent as a Bearer Authorization header
+	HTTPClient   *http.Client  // Defaults to a client with a 30s timeout
+	MaxRetries   int           // Retries after the first attempt; 0 means 3, negative disables retries
+	RetryBackoff time.Duration // Delay before the first retry, doubled for each retry; defaults to 500ms
+	UserAgent    string
+}
+
+// Client calls the IncidentTeller API. It is safe for concurrent use.
+type Client struct {
+	baseURL      *url.URL
+	httpClient   *http.Client
+	token        string
+	maxRetries   int
+	retryBackoff time.Duration
+	userAgent    string
+}
+
+// New creates a client for the API served at baseURL, e.g. http://localhost:8080
+func New(baseURL string, opts Options) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:      u,
+		httpClient:   opts.HTTPClient,
+		token:        opts.Token,
+		maxRetries:   opts.MaxRetries,
+		retryBackoff: opts.RetryBackoff,
+		userAgent:    opts.UserAgent,
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	switch {
+	case c.maxRetries == 0:
+		c.maxRetries = defaultMaxRetries
+	case c.maxRetries < 0:
+		c.maxRetries = 0
+	}
+	if c.retryBackoff <= 0 {
+		c.retryBackoff = defaultRetryBackoff
+	}
+	if c.userAgent == "" {
+		c.userAgent = "incident-teller-go-client/1.0"
+	}
+	return c, nil
+}
+
+// ListIncidents returns a page of incidents
+func (c *Client) ListIncidents(ctx context.Context, opts ListOptions) (*IncidentList, error) {
+	var list IncidentList
+	if err := c.do(ctx, http.MethodGet, "/api/incidents", opts.values(), true, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetIncident returns an incident with its timeline, root cause and blast radius
+func (c *Client) GetIncident(ctx context.Context, id string) (*IncidentDetail, error) {
+	var incident IncidentDetail
+	if err := c.do(ctx, http.MethodGet, "/api/incidents/"+url.PathEscape(id), nil, true, &incident); err != nil {
+		return nil, err
+	}
+	return &incident, nil
+}
+
+// GetAnalysis analyzes an incident and returns its story: summary, root cause, impact
+// and recommended actions. An empty incidentID analyzes all stored alerts.
+func (c *Client) GetAnalysis(ctx context.Context, incidentID string) (*Analysis, error) {
+	var query url.Values
+	if incidentID != "" {
+		query = url.Values{"incident_id": {incidentID}}
+	}
+
+	// Analysis only computes a result, so it is safe to retry although it is a POST
+	var analysis Analysis
+	if err := c.do(ctx, http.MethodPost, "/api/analyze", query, true, &analysis); err != nil {
+		return nil, err
+	}
+	return &analysis, nil
+}
+
+// do sends a request and decodes the JSON response into out, retrying idempotent
+// requests on transient failures
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, idempotent bool, out any) error {
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.attempt(ctx, method, path, query, out)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if !idempotent || attempt >= c.maxRetries || !retryable(err) {
+			return lastErr
+		}
+
+		wait := c.backoff(attempt)
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return lastErr
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends one request, returning the server's Retry-After delay on failure
+func (c *Client) attempt(ctx context.Context, method, path string, query url.Values, out any) (time.Duration, error) {
+	req, err := c.newRequest(ctx, method, path, query, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return parseRetryAfter(resp.Header.Get("Retry-After")), newAPIError(resp)
+	}
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return 0, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return 0, fmt.Errorf("failed to decode %s %s response: %w", method, path, err)
+	}
+	return 0, nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body any) (*http.Request, error) {
+	u := *c.baseURL
+	u.Path = c.baseURL.Path + path
+	u.RawQuery = query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	return req, nil
+}
+
+// backoff returns the delay before retry attempt+1
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.retryBackoff << attempt
+	if wait <= 0 || wait > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return wait
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds
+func parseRetryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return min(time.Duration(seconds)*time.Second, maxRetryBackoff)
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_RetriesAndTypedErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/incidents":
+			attempts++
+			if attempts < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprintf(w, `{"incidents":[{"id":"inc-1","title":"High CPU","status":"CRITICAL"}],"total":1,"page":%s,"page_size":20}`, r.URL.Query().Get("page"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"Not Found","message":"Incident not found","code":404}`)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, Options{Token: "secret", RetryBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	list, err := c.ListIncidents(ctx, ListOptions{Page: 2})
+	if err != nil {
+		t.Fatalf("expected the third attempt to succeed: %v", err)
+	}
+	if attempts != 3 || list.Page != 2 || len(list.Incidents) != 1 || list.Incidents[0].ID != "inc-1" {
+		t.Errorf("unexpected result after %d attempts: %+v", attempts, list)
+	}
+
+	_, err = c.GetIncident(ctx, "missing")
+	var apiErr *APIError
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &apiErr) || apiErr.Message != "Incident not found" {
+		t.Errorf("expected a not found APIError, got %v", err)
+	}
+
+	unauthorized, _ := New(server.URL, Options{MaxRetries: -1})
+	if _, err := unauthorized.ListIncidents(ctx, ListOptions{}); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestClient_StreamEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"ID\":\"inc-1\",\"Title\":\"High CPU\",\"Events\":[{\"ID\":\"a-1\",\"Host\":\"web-01\"}]}\n\n")
+		fmt.Fprint(w, "event: analysis\ndata: {\"incident_id\":\"inc-1\",\"status\":\"completed\"}\n\n")
+		fmt.Fprint(w, "data: {\"ID\":\"inc-2\",\"Title\":\"Disk full\"}\n\n")
+	}))
+	defer server.Close()
+
+	c, _ := New(server.URL, Options{RetryBackoff: time.Millisecond})
+	stop := errors.New("stop")
+	var ids []string
+	err := c.StreamEvents(context.Background(), func(incident StreamedIncident) error {
+		ids = append(ids, incident.ID)
+		if len(ids) == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected the handler error, got %v", err)
+	}
+	// The stream is reopened after the server closes it
+	if len(ids) != 3 || ids[0] != "inc-1" || ids[1] != "inc-2" || ids[2] != "inc-1" {
+		t.Errorf("unexpected events: %v", ids)
+	}
+}
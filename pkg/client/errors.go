@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+var (
+	// ErrNotFound matches errors for missing resources and disabled features
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized matches errors for missing or invalid tokens
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden matches errors for requests the server refuses, e.g. from a disallowed origin
+	ErrForbidden = errors.New("forbidden")
+	// ErrRateLimited matches errors for requests rejected by the rate limiter
+	ErrRateLimited = errors.New("rate limited")
+	// ErrReadOnly matches errors for writes to a server in read-only mode
+	ErrReadOnly = errors.New("server is read-only")
+)
+
+// errStreamClosed is returned when the server ends the event stream; it is reopened
+var errStreamClosed = errors.New("event stream closed by server")
+
+// APIError is an error response from the API
+type APIError struct {
+	StatusCode int    // HTTP status code
+	Message    string // Error message from the server
+	Body       string // Raw body if it was not an API error response
+	ReadOnly   bool   // Rejected because the server is in read-only mode
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("incident-teller: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	}
+	return fmt.Sprintf("incident-teller: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Is matches the sentinel error of the status code
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden && !e.ReadOnly
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrReadOnly:
+		return e.ReadOnly
+	}
+	return false
+}
+
+// Temporary reports whether the request may succeed when retried
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// newAPIError reads an error response
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		ReadOnly:   resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-Read-Only") == "true",
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var errResp struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &errResp) == nil && (errResp.Message != "" || errResp.Error != "") {
+		apiErr.Message = errResp.Message
+		if apiErr.Message == "" {
+			apiErr.Message = errResp.Error
+		}
+	} else {
+		apiErr.Body = string(body)
+	}
+	return apiErr
+}
+
+// retryable reports whether a failed request should be retried
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, errStreamClosed) {
+		return true
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Temporary()
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
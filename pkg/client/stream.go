@@ -0,0 +1,108 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StreamEvents calls handle for every incident update pushed on the /api/events stream
+// until ctx is cancelled or handle returns an error. Dropped connections are reopened
+// with backoff; the error is returned once the retries are used up.
+func (c *Client) StreamEvents(ctx context.Context, handle func(StreamedIncident) error) error {
+	failures := 0
+	for {
+		received, err := c.stream(ctx, handle)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if herr, ok := err.(handlerError); ok {
+			return herr.err
+		}
+		if received {
+			failures = 0
+		}
+		if !retryable(err) || failures >= c.maxRetries {
+			return err
+		}
+
+		timer := time.NewTimer(c.backoff(failures))
+		failures++
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// handlerError is an error returned by the StreamEvents callback
+type handlerError struct{ err error }
+
+func (e handlerError) Error() string { return e.err.Error() }
+
+// stream reads one connection until it ends, reporting whether any event was received
+func (c *Client) stream(ctx context.Context, handle func(StreamedIncident) error) (bool, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/events", nil, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream outlives the client's request timeout
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, newAPIError(resp)
+	}
+
+	received := false
+	var event string
+	var data strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// A blank line ends the event; only incident (unnamed) events are handled
+			if data.Len() == 0 || (event != "" && event != "message") {
+				event = ""
+				data.Reset()
+				continue
+			}
+			var incident StreamedIncident
+			if err := json.Unmarshal([]byte(data.String()), &incident); err != nil {
+				return received, fmt.Errorf("failed to decode event: %w", err)
+			}
+			data.Reset()
+			event = ""
+			received = true
+			if err := handle(incident); err != nil {
+				return received, handlerError{err}
+			}
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return received, err
+	}
+	return received, errStreamClosed
+}
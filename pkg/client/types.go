@@ -0,0 +1,179 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ListOptions filters and pages ListIncidents
+type ListOptions struct {
+	Page     int    // 1-based; defaults to 1
+	PageSize int    // At most 100; defaults to 20
+	Query    string // Searches title, host, chart and alert name
+	Sort     string // started_at, duration, risk, events or priority
+	Order    string // asc or desc
+}
+
+func (o ListOptions) values() url.Values {
+	values := url.Values{}
+	if o.Page > 0 {
+		values.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PageSize > 0 {
+		values.Set("page_size", strconv.Itoa(o.PageSize))
+	}
+	if o.Query != "" {
+		values.Set("q", o.Query)
+	}
+	if o.Sort != "" {
+		values.Set("sort", o.Sort)
+	}
+	if o.Order != "" {
+		values.Set("order", o.Order)
+	}
+	return values
+}
+
+// IncidentList is a page of incidents
+type IncidentList struct {
+	Incidents []Incident `json:"incidents"`
+	Total     int        `json:"total"`
+	Page      int        `json:"page"`
+	PageSize  int        `json:"page_size"`
+}
+
+// Incident is an incident in a list
+type Incident struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Status      string     `json:"status"`
+	StartedAt   time.Time  `json:"started_at"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+	Duration    string     `json:"duration"`
+	RootCause   string     `json:"root_cause"`
+	TotalEvents int        `json:"total_events"`
+	RiskLevel   string     `json:"risk_level"`
+	Priority    string     `json:"priority"` // P1 (most urgent) to P4
+	Assignee    string     `json:"assignee,omitempty"`
+}
+
+// Active reports whether the incident is unresolved
+func (i Incident) Active() bool {
+	return i.ResolvedAt == nil
+}
+
+// IncidentDetail is an incident with its timeline and AI analysis
+type IncidentDetail struct {
+	ID             string          `json:"id"`
+	Title          string          `json:"title"`
+	Status         string          `json:"status"`
+	StartedAt      time.Time       `json:"started_at"`
+	ResolvedAt     *time.Time      `json:"resolved_at,omitempty"`
+	Duration       string          `json:"duration"`
+	RootCause      *RootCause      `json:"root_cause,omitempty"`
+	BlastRadius    *BlastRadius    `json:"blast_radius,omitempty"`
+	RiskLevel      string          `json:"risk_level"`
+	Priority       string          `json:"priority"`
+	PriorityManual bool            `json:"priority_manual"`
+	TotalEvents    int             `json:"total_events"`
+	Timeline       []TimelineEvent `json:"event_timeline"`
+	Assignee       string          `json:"assignee,omitempty"`
+	AcknowledgedBy string          `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt *time.Time      `json:"acknowledged_at,omitempty"`
+	Template       *Template       `json:"template,omitempty"`
+}
+
+// Template is the known failure mode an incident matched, with its remediation
+type Template struct {
+	ID          string   `json:"id"`
+	RunbookURL  string   `json:"runbook_url,omitempty"`
+	Remediation []string `json:"remediation,omitempty"`
+}
+
+// RootCause is the predicted root cause of an incident
+type RootCause struct {
+	AlertID           string             `json:"alert_id"`
+	ResourceType      string             `json:"resource_type"`
+	Chart             string             `json:"chart"`
+	Host              string             `json:"host"`
+	Confidence        float64            `json:"confidence"`
+	PatternType       string             `json:"pattern_type"`
+	Reasoning         string             `json:"reasoning"`
+	AlternativeCauses []AlternativeCause `json:"alternative_causes"`
+}
+
+// AlternativeCause is a less likely root cause
+type AlternativeCause struct {
+	AlertID      string  `json:"alert_id"`
+	ResourceType string  `json:"resource_type"`
+	Chart        string  `json:"chart"`
+	Host         string  `json:"host"`
+	Confidence   float64 `json:"confidence"`
+}
+
+// BlastRadius is the predicted impact of an incident
+type BlastRadius struct {
+	ImpactScore        float64  `json:"impact_score"`
+	AffectedServices   []string `json:"affected_services"`
+	CascadeProbability float64  `json:"cascade_probability"`
+	DurationPredicted  string   `json:"duration_predicted"`
+	BusinessImpact     string   `json:"business_impact"`
+	RiskLevel          string   `json:"risk_level"`
+}
+
+// TimelineEvent is an event in an incident timeline
+type TimelineEvent struct {
+	Timestamp          time.Time `json:"timestamp"`
+	Type               string    `json:"type"`
+	Message            string    `json:"message"`
+	Severity           string    `json:"severity"`
+	DurationSinceStart *string   `json:"duration_since_start,omitempty"`
+	ResourceType       string    `json:"resource_type"`
+	Source             string    `json:"source,omitempty"`
+}
+
+// Analysis is the story of an incident
+type Analysis struct {
+	Summary          string          `json:"summary"`
+	Narrative        string          `json:"narrative,omitempty"`
+	RootCause        string          `json:"root_cause_text"`
+	ImpactAssessment string          `json:"impact_assessment"`
+	Recommendations  Recommendations `json:"recommendations"`
+	GeneratedAt      time.Time       `json:"generated_at"`
+	AlertCount       int             `json:"alert_count"`
+}
+
+// Recommendations are remediation steps by urgency
+type Recommendations struct {
+	Immediate []string `json:"immediate"`
+	ShortTerm []string `json:"short_term"`
+	LongTerm  []string `json:"long_term"`
+}
+
+// Alert is an alert of a streamed incident
+type Alert struct {
+	ID           string
+	Host         string
+	Chart        string
+	Family       string
+	Name         string
+	Status       string
+	OldStatus    string
+	Value        float64
+	OccurredAt   time.Time
+	Description  string
+	ResourceType string
+	Labels       map[string]string
+	Source       string
+}
+
+// StreamedIncident is an incident pushed by StreamEvents, with all of its alerts
+type StreamedIncident struct {
+	ID         string
+	Title      string
+	Status     string
+	StartedAt  time.Time
+	ResolvedAt *time.Time
+	Events     []Alert
+}